@@ -0,0 +1,291 @@
+// client/client.go
+// Package client 브릿지 관리 HTTP API(HEALTH_ADDR)의 Go 클라이언트
+//
+// 다른 Go 서비스가 요청/응답 구조체를 다시 정의하지 않고 브릿지와 연동할 수 있도록
+// 서버가 쓰는 타입을 그대로(types.go의 별칭) 주고받습니다.
+// 모든 메서드는 context를 받으며, 연결 실패와 502/503/504 응답은 지수 백오프로 재시도합니다.
+// 변경 요청(POST, PUT, PATCH, DELETE)에는 Idempotency-Key를 붙여 재시도해도 서버에서 한 번만 처리됩니다.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/idgen"
+	"mqtt-bridge/internal/health"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// 재시도 기본값
+const (
+	defaultMaxRetries   = 3
+	defaultRetryBackoff = 200 * time.Millisecond
+	maxRetryBackoff     = 5 * time.Second
+	defaultTimeout      = 30 * time.Second
+)
+
+// Client 브릿지 관리 API 클라이언트 (여러 고루틴에서 함께 써도 안전)
+type Client struct {
+	baseURL      string
+	httpClient   *http.Client
+	timeout      time.Duration // 요청 하나(재시도 포함)의 제한 시간 (롱 폴링/스트림 제외)
+	maxRetries   int
+	retryBackoff time.Duration
+}
+
+// New baseURL(예: http://bridge:8081, 스킴이 없으면 http)로 클라이언트 생성
+func New(baseURL string) *Client {
+	if !strings.Contains(baseURL, "://") {
+		if strings.HasPrefix(baseURL, ":") {
+			baseURL = "localhost" + baseURL
+		}
+		baseURL = "http://" + baseURL
+	}
+	return &Client{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		httpClient:   &http.Client{},
+		timeout:      defaultTimeout,
+		maxRetries:   defaultMaxRetries,
+		retryBackoff: defaultRetryBackoff,
+	}
+}
+
+// SetHTTPClient 요청에 쓸 http.Client 교체 (인증 프록시, TLS 설정 등)
+// 오더 대기(WaitOrder)와 로그 스트림이 오래 걸리므로 http.Client.Timeout은 비워 두고 SetTimeout을 쓰세요.
+func (c *Client) SetHTTPClient(httpClient *http.Client) {
+	c.httpClient = httpClient
+}
+
+// SetTimeout 요청 하나(재시도 포함)의 제한 시간 (기본 30초, 0이면 context만 따름)
+// 오더 대기는 대기 시간만큼 늘려 적용하고, 로그 스트림에는 적용하지 않습니다.
+func (c *Client) SetTimeout(timeout time.Duration) {
+	c.timeout = timeout
+}
+
+// SetRetries 재시도 횟수와 첫 대기 시간 설정 (대기 시간은 재시도마다 두 배, 최대 5초, maxRetries가 0이면 재시도 안 함)
+func (c *Client) SetRetries(maxRetries int, backoff time.Duration) {
+	c.maxRetries = maxRetries
+	c.retryBackoff = backoff
+}
+
+// idempotencyKeyContext context에 지정한 Idempotency-Key
+type idempotencyKeyContext struct{}
+
+// WithIdempotencyKey 변경 요청에 쓸 Idempotency-Key 지정
+// 지정하지 않으면 호출마다 새 키를 만들어 그 호출의 재시도에만 씁니다.
+// 프로세스 재시작 후에도 같은 작업을 한 번만 처리하려면 작업 ID에서 만든 키를 지정하세요.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContext{}, key)
+}
+
+// Error 브릿지가 반환한 오류 응답 (apperr.CodeOf로 코드 확인 가능)
+type Error struct {
+	StatusCode    int         `json:"-"`
+	Code          apperr.Code `json:"code"`
+	Message       string      `json:"message"`
+	Field         string      `json:"field,omitempty"`
+	CorrelationID string      `json:"cid,omitempty"`
+}
+
+func (e *Error) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("bridge API %d: [%s] %s", e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("bridge API %d: %s", e.StatusCode, e.Message)
+}
+
+// ErrorCode apperr.Coder 구현 (코드가 없는 응답은 HTTP 상태로 추정)
+func (e *Error) ErrorCode() apperr.Code {
+	if e.Code != "" {
+		return e.Code
+	}
+	switch e.StatusCode {
+	case http.StatusNotFound:
+		return apperr.CodeNotFound
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return apperr.CodeValidationFailed
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return apperr.CodeTransportUnavailable
+	}
+	return apperr.CodeInternal
+}
+
+// ErrorField apperr.Fielder 구현
+func (e *Error) ErrorField() string {
+	return e.Field
+}
+
+// IsNotFound 대상이 없거나 그 기능이 브릿지에서 꺼져 있는 오류인지
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsReadOnly 브릿지가 읽기 전용이라 거부된 변경 요청인지
+func IsReadOnly(err error) bool {
+	return apperr.CodeOf(err) == apperr.CodeReadOnly
+}
+
+// request 보낼 요청 하나
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	body        interface{} // JSON으로 보낼 값 (raw가 있으면 무시)
+	raw         []byte
+	contentType string
+	accept      []int // 오류로 보지 않고 out으로 해석할 상태 코드 (2xx 외)
+	noRetry     bool
+	wait        time.Duration // 서버가 응답 전에 기다리는 시간 (제한 시간에 더함)
+	text        bool          // 응답이 JSON이 아닌 텍스트 (out은 *string)
+	readOnly    bool          // POST여도 변경이 없는 요청 (Idempotency-Key를 붙이지 않음)
+}
+
+// do 요청을 보내고(필요하면 재시도) 응답 JSON을 out에 해석합니다 (out이 nil이면 본문 무시).
+func (c *Client) do(ctx context.Context, req request, out interface{}) (int, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout+req.wait)
+		defer cancel()
+	}
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	accepted := resp.StatusCode >= 200 && resp.StatusCode < 300
+	for _, status := range req.accept {
+		accepted = accepted || resp.StatusCode == status
+	}
+	if !accepted {
+		return resp.StatusCode, decodeError(resp.StatusCode, body)
+	}
+	if text, ok := out.(*string); ok && req.text {
+		*text = string(body)
+		return resp.StatusCode, nil
+	}
+	if out != nil && len(bytes.TrimSpace(body)) > 0 {
+		if err := json.Unmarshal(body, out); err != nil {
+			return resp.StatusCode, fmt.Errorf("failed to decode %s %s response: %w", req.method, req.path, err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// send 요청을 보내고 재시도할 만한 실패는 재시도합니다. 응답 본문은 호출자가 닫아야 합니다.
+func (c *Client) send(ctx context.Context, req request) (*http.Response, error) {
+	target := c.baseURL + req.path
+	if len(req.query) > 0 {
+		target += "?" + req.query.Encode()
+	}
+	payload := req.raw
+	contentType := req.contentType
+	if payload == nil && req.body != nil {
+		data, err := json.Marshal(req.body)
+		if err != nil {
+			return nil, err
+		}
+		payload = data
+		contentType = "application/json"
+	}
+	idempotencyKey := ""
+	if req.method != http.MethodGet && !req.readOnly {
+		idempotencyKey, _ = ctx.Value(idempotencyKeyContext{}).(string)
+		if idempotencyKey == "" {
+			idempotencyKey = idgen.UniqueID()
+		}
+	}
+
+	backoff := c.retryBackoff
+	for attempt := 0; ; attempt++ {
+		httpReq, err := http.NewRequestWithContext(ctx, req.method, target, bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		if contentType != "" {
+			httpReq.Header.Set("Content-Type", contentType)
+		}
+		if idempotencyKey != "" {
+			httpReq.Header.Set(health.IdempotencyKeyHeader, idempotencyKey)
+		}
+
+		resp, err := c.httpClient.Do(httpReq)
+		retryable := err != nil && ctx.Err() == nil
+		if err == nil {
+			switch resp.StatusCode {
+			case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+				retryable = true
+			}
+		}
+		if !retryable || req.noRetry || attempt >= c.maxRetries {
+			if err != nil {
+				return nil, apperr.Wrap(apperr.CodeTransportUnavailable, err, "failed to reach bridge API")
+			}
+			return resp, nil
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+	}
+}
+
+// decodeError 오류 응답 본문 해석 ({code, message} 또는 {"error": ...})
+func decodeError(status int, body []byte) error {
+	apiErr := &Error{StatusCode: status}
+	if json.Unmarshal(body, apiErr) == nil && apiErr.Message != "" {
+		return apiErr
+	}
+	var wrapped struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if json.Unmarshal(body, &wrapped) == nil && len(wrapped.Error) > 0 {
+		var message string
+		if json.Unmarshal(wrapped.Error, &message) == nil {
+			apiErr.Message = message
+			return apiErr
+		}
+		if json.Unmarshal(wrapped.Error, apiErr) == nil && apiErr.Message != "" {
+			return apiErr
+		}
+	}
+	apiErr.Message = strings.TrimSpace(string(body))
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(status)
+	}
+	return apiErr
+}
+
+// get GET 요청
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	_, err := c.do(ctx, request{method: http.MethodGet, path: path, query: query}, out)
+	return err
+}
+
+// mutate JSON 본문 변경 요청
+func (c *Client) mutate(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	_, err := c.do(ctx, request{method: method, path: path, query: query, body: body}, out)
+	return err
+}
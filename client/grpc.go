@@ -0,0 +1,140 @@
+// client/grpc.go
+package client
+
+import (
+	"context"
+	"mqtt-bridge/internal/grpcapi"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// GRPCClient 브릿지 gRPC API 클라이언트 (GRPC_ADDR로 켠 서버, 메시지는 JSON 코덱)
+type GRPCClient struct {
+	conn grpc.ClientConnInterface
+}
+
+// DialGRPC target(예: bridge:9090)에 연결 (opts가 없으면 평문 연결)
+func DialGRPC(target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	if len(opts) == 0 {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+	return grpc.NewClient(target, opts...)
+}
+
+// NewGRPC 연결로 gRPC 클라이언트 생성
+func NewGRPC(conn grpc.ClientConnInterface) *GRPCClient {
+	return &GRPCClient{conn: conn}
+}
+
+// invoke 단항 RPC 호출
+func (c *GRPCClient) invoke(ctx context.Context, method string, in, out interface{}) error {
+	return c.conn.Invoke(ctx, grpcapi.FullMethod(method), in, out, grpc.CallContentSubtype(grpcapi.CodecName))
+}
+
+// ListRobots 로봇 목록 (selector는 메타데이터 셀렉터, 비우면 모두)
+func (c *GRPCClient) ListRobots(ctx context.Context, selector string) ([]RobotStatus, error) {
+	var out grpcapi.RobotList
+	if err := c.invoke(ctx, "ListRobots", &grpcapi.ListRobotsRequest{Selector: selector}, &out); err != nil {
+		return nil, err
+	}
+	return out.Robots, nil
+}
+
+// GetRobot 로봇 하나의 연결/상태 요약
+func (c *GRPCClient) GetRobot(ctx context.Context, serialNumber string) (*RobotStatus, error) {
+	var out RobotStatus
+	if err := c.invoke(ctx, "GetRobot", &grpcapi.GetRobotRequest{SerialNumber: serialNumber}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListOrders 오더 실행 이력 (최신순)
+func (c *GRPCClient) ListOrders(ctx context.Context, req GRPCListOrdersRequest) ([]OrderExecution, error) {
+	var out grpcapi.OrderList
+	if err := c.invoke(ctx, "ListOrders", &req, &out); err != nil {
+		return nil, err
+	}
+	return out.Orders, nil
+}
+
+// GetOrder 오더 실행 하나
+func (c *GRPCClient) GetOrder(ctx context.Context, orderID string) (*OrderExecution, error) {
+	var out OrderExecution
+	if err := c.invoke(ctx, "GetOrder", &grpcapi.GetOrderRequest{OrderID: orderID}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CancelOrder 오더 하나 취소
+func (c *GRPCClient) CancelOrder(ctx context.Context, orderID, reason string) error {
+	return c.invoke(ctx, "CancelOrder", &grpcapi.CancelOrderRequest{OrderID: orderID, Reason: reason}, &grpcapi.Empty{})
+}
+
+// SubmitJob 작업 묶음 생성
+func (c *GRPCClient) SubmitJob(ctx context.Context, req JobRequest) (*Job, error) {
+	var out Job
+	if err := c.invoke(ctx, "SubmitJob", &req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetJob 작업 묶음 하나와 모아진 상태
+func (c *GRPCClient) GetJob(ctx context.Context, jobID string) (*Job, error) {
+	var out Job
+	if err := c.invoke(ctx, "GetJob", &grpcapi.GetJobRequest{JobID: jobID}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// EmergencyStop 로봇 비상 정지 (정지 처리한 오더 수 반환)
+func (c *GRPCClient) EmergencyStop(ctx context.Context, serialNumber, reason string) (int, error) {
+	var out grpcapi.EmergencyStopResponse
+	if err := c.invoke(ctx, "EmergencyStop", &grpcapi.EmergencyStopRequest{SerialNumber: serialNumber, Reason: reason}, &out); err != nil {
+		return 0, err
+	}
+	return out.StoppedOrders, nil
+}
+
+// SendInstantActions 원시 VDA 5050 instantActions 전송 (headerId는 브릿지가 채움)
+func (c *GRPCClient) SendInstantActions(ctx context.Context, serialNumber string, message []byte) (*InstantActionsMessage, error) {
+	var out InstantActionsMessage
+	if err := c.invoke(ctx, "SendInstantActions", &grpcapi.InstantActionsRequest{SerialNumber: serialNumber, Message: message}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// WatchStates 로봇 state 스트림 구독 (serialNumbers가 없으면 모든 로봇, ctx가 끝나면 스트림 종료)
+func (c *GRPCClient) WatchStates(ctx context.Context, serialNumbers ...string) (*StateStream, error) {
+	stream, err := c.conn.NewStream(ctx, &grpcapi.ServiceDesc.Streams[0], grpcapi.FullMethod("WatchStates"),
+		grpc.CallContentSubtype(grpcapi.CodecName))
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.SendMsg(&grpcapi.WatchStatesRequest{SerialNumbers: serialNumbers}); err != nil {
+		return nil, err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return &StateStream{stream: stream}, nil
+}
+
+// StateStream WatchStates 스트림
+type StateStream struct {
+	stream grpc.ClientStream
+}
+
+// Recv 다음 state 메시지 (스트림이 끝나면 io.EOF)
+func (s *StateStream) Recv() (*RobotStateMessage, error) {
+	var state RobotStateMessage
+	if err := s.stream.RecvMsg(&state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
@@ -0,0 +1,290 @@
+// client/orders.go
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WaitOrder 오더 상태가 knownStatus(비어 있으면 현재 상태)에서 바뀔 때까지 최대 timeout 동안 기다립니다 (서버 최대 5분).
+// 시간이 지나면 오류 없이 TimedOut=true인 현재 상태를 반환합니다.
+func (c *Client) WaitOrder(ctx context.Context, orderID, knownStatus string, timeout time.Duration) (*OrderWaitResult, error) {
+	query := url.Values{}
+	if timeout > 0 {
+		query.Set("timeout", timeout.String())
+	}
+	if knownStatus != "" {
+		query.Set("status", knownStatus)
+	}
+	var result OrderWaitResult
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: orderPath(orderID, "wait"), query: query, wait: timeout}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// OrderTimeline 오더 생성, 전송, 단계 시작/종료, 액션 상태 전이 타임라인 (시간순)
+func (c *Client) OrderTimeline(ctx context.Context, orderID string) (*OrderTimeline, error) {
+	var timeline OrderTimeline
+	if err := c.get(ctx, orderPath(orderID, "timeline"), nil, &timeline); err != nil {
+		return nil, err
+	}
+	return &timeline, nil
+}
+
+// OrderArtifacts 오더의 결과물 목록
+func (c *Client) OrderArtifacts(ctx context.Context, orderID string) ([]OrderArtifact, error) {
+	var list []OrderArtifact
+	if err := c.get(ctx, orderPath(orderID, "artifacts"), nil, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// UploadArtifact 오더에 결과물 첨부 (같은 이름이면 교체, contentType이 비어 있으면 서버가 내용으로 추정)
+func (c *Client) UploadArtifact(ctx context.Context, orderID, name, kind, contentType string, data []byte) (*OrderArtifact, error) {
+	var artifact OrderArtifact
+	query := url.Values{"kind": {kind}}
+	if _, err := c.do(ctx, request{method: http.MethodPut, path: artifactPath(orderID, name), query: query, raw: data, contentType: contentType}, &artifact); err != nil {
+		return nil, err
+	}
+	return &artifact, nil
+}
+
+// DownloadArtifact 결과물 본문 (호출자가 닫아야 함)
+func (c *Client) DownloadArtifact(ctx context.Context, orderID, name string) (io.ReadCloser, error) {
+	resp, err := c.send(ctx, request{method: http.MethodGet, path: artifactPath(orderID, name)})
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, decodeError(resp.StatusCode, body)
+	}
+	return resp.Body, nil
+}
+
+// ArtifactURL 결과물 pre-signed 다운로드 URL (저장소가 지원하지 않으면 501 오류)
+func (c *Client) ArtifactURL(ctx context.Context, orderID, name string) (string, error) {
+	var body struct {
+		URL string `json:"url"`
+	}
+	if err := c.get(ctx, artifactPath(orderID, name), url.Values{"url": {"true"}}, &body); err != nil {
+		return "", err
+	}
+	return body.URL, nil
+}
+
+// DeleteArtifact 결과물 삭제
+func (c *Client) DeleteArtifact(ctx context.Context, orderID, name string) error {
+	return c.mutate(ctx, http.MethodDelete, artifactPath(orderID, name), nil, nil, nil)
+}
+
+// CancelOrder 오더 하나 취소 (로봇에 cancelOrder 전송, 이미 종료된 오더는 검증 오류)
+func (c *Client) CancelOrder(ctx context.Context, orderID, reason string) error {
+	return c.mutate(ctx, http.MethodPost, orderPath(orderID, "cancel"), nil, map[string]string{"reason": reason}, nil)
+}
+
+// PauseOrder 실행 중인 오더 일시정지 (로봇에 startPause 전송, 상태가 RUNNING이 아니면 오류)
+func (c *Client) PauseOrder(ctx context.Context, orderID, reason string) (*OrderExecution, error) {
+	var execution OrderExecution
+	if err := c.mutate(ctx, http.MethodPost, orderPath(orderID, "pause"), nil, map[string]string{"reason": reason}, &execution); err != nil {
+		return nil, err
+	}
+	return &execution, nil
+}
+
+// ResumeOrder 일시정지한 오더 재개 (로봇에 stopPause 전송)
+func (c *Client) ResumeOrder(ctx context.Context, orderID string) (*OrderExecution, error) {
+	var execution OrderExecution
+	if err := c.mutate(ctx, http.MethodPost, orderPath(orderID, "resume"), nil, nil, &execution); err != nil {
+		return nil, err
+	}
+	return &execution, nil
+}
+
+// OrderAnnotations 오더와 그 오더를 만든 명령의 주석 (오래된 순)
+func (c *Client) OrderAnnotations(ctx context.Context, orderID string) ([]Annotation, error) {
+	var annotations []Annotation
+	if err := c.get(ctx, orderPath(orderID, "annotations"), nil, &annotations); err != nil {
+		return nil, err
+	}
+	return annotations, nil
+}
+
+// AnnotateOrder 오더에 라벨/메모 추가
+func (c *Client) AnnotateOrder(ctx context.Context, orderID string, input AnnotationInput) (*Annotation, error) {
+	var annotation Annotation
+	if err := c.mutate(ctx, http.MethodPost, orderPath(orderID, "annotations"), nil, input, &annotation); err != nil {
+		return nil, err
+	}
+	return &annotation, nil
+}
+
+// CommandAnnotations PLC 명령(상관 ID)의 주석
+func (c *Client) CommandAnnotations(ctx context.Context, correlationID string) ([]Annotation, error) {
+	var annotations []Annotation
+	if err := c.get(ctx, commandPath(correlationID, "annotations"), nil, &annotations); err != nil {
+		return nil, err
+	}
+	return annotations, nil
+}
+
+// AnnotateCommand PLC 명령(상관 ID)에 라벨/메모 추가
+func (c *Client) AnnotateCommand(ctx context.Context, correlationID string, input AnnotationInput) (*Annotation, error) {
+	var annotation Annotation
+	if err := c.mutate(ctx, http.MethodPost, commandPath(correlationID, "annotations"), nil, input, &annotation); err != nil {
+		return nil, err
+	}
+	return &annotation, nil
+}
+
+// DryRunCommand 명령의 오더 체인 시뮬레이션 (params는 템플릿 자리표시자 값, 로봇에는 아무것도 보내지 않음)
+func (c *Client) DryRunCommand(ctx context.Context, commandType string, params map[string]string) (*DryRunReport, error) {
+	var report DryRunReport
+	_, err := c.do(ctx, request{method: http.MethodPost, path: commandPath(commandType, "dry-run"),
+		body: map[string]interface{}{"params": params}, readOnly: true}, &report)
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// DirectActions 등록된 PLC 직접 액션 정의 목록 (인자 포함)
+func (c *Client) DirectActions(ctx context.Context) ([]DirectAction, error) {
+	var defs []DirectAction
+	if err := c.get(ctx, "/admin/direct-actions", nil, &defs); err != nil {
+		return nil, err
+	}
+	return defs, nil
+}
+
+// DirectAction 접미사로 직접 액션 정의 조회 (없으면 IsNotFound 오류)
+func (c *Client) DirectAction(ctx context.Context, suffix string) (*DirectAction, error) {
+	var def DirectAction
+	if err := c.get(ctx, directActionPath(suffix), nil, &def); err != nil {
+		return nil, err
+	}
+	return &def, nil
+}
+
+// SaveDirectAction 직접 액션 정의 저장 (같은 접미사는 인자까지 교체), 저장된 정의 반환
+func (c *Client) SaveDirectAction(ctx context.Context, def DirectAction) (*DirectAction, error) {
+	var saved DirectAction
+	if err := c.mutate(ctx, http.MethodPut, directActionPath(def.Suffix), nil, def, &saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// DeleteDirectAction 직접 액션 정의 삭제
+func (c *Client) DeleteDirectAction(ctx context.Context, suffix string) error {
+	return c.mutate(ctx, http.MethodDelete, directActionPath(suffix), nil, nil, nil)
+}
+
+// CommandHistory 조건에 맞는 PLC 명령과 실행, 오더 체인, PLC 응답 기록 (최근 순)
+func (c *Client) CommandHistory(ctx context.Context, filter CommandHistoryFilter) ([]CommandHistory, error) {
+	query := url.Values{}
+	if filter.Type != "" {
+		query.Set("type", filter.Type)
+	}
+	if filter.Status != "" {
+		query.Set("status", filter.Status)
+	}
+	if !filter.From.IsZero() {
+		query.Set("from", filter.From.Format(time.RFC3339))
+	}
+	if !filter.To.IsZero() {
+		query.Set("to", filter.To.Format(time.RFC3339))
+	}
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	var history []CommandHistory
+	if err := c.get(ctx, "/admin/commands/history", query, &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// CommandHistoryByID 명령 하나의 전체 실행 트리 (단계 포함)
+func (c *Client) CommandHistoryByID(ctx context.Context, commandID uint) (*CommandHistory, error) {
+	var history CommandHistory
+	if err := c.get(ctx, "/admin/commands/history/"+strconv.FormatUint(uint64(commandID), 10), nil, &history); err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+// Jobs 조건에 맞는 작업 묶음과 작업 항목 (최근 순)
+func (c *Client) Jobs(ctx context.Context, filter JobFilter) ([]Job, error) {
+	query := url.Values{}
+	if filter.Status != "" {
+		query.Set("status", filter.Status)
+	}
+	if filter.Robot != "" {
+		query.Set("robot", filter.Robot)
+	}
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	var jobs []Job
+	if err := c.get(ctx, "/admin/jobs", query, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// Job 작업 묶음 하나와 모아진 상태
+func (c *Client) Job(ctx context.Context, jobID string) (*Job, error) {
+	var job Job
+	if err := c.get(ctx, "/admin/jobs/"+url.PathEscape(jobID), nil, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// CreateJob 여러 로봇/명령에 걸친 작업 묶음 생성 (작업 항목 하나라도 문제가 있으면 모두 거부)
+func (c *Client) CreateJob(ctx context.Context, request JobRequest) (*Job, error) {
+	var job Job
+	if err := c.mutate(ctx, http.MethodPost, "/admin/jobs", nil, request, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// CancelJob 작업 묶음 전체 취소
+func (c *Client) CancelJob(ctx context.Context, jobID, reason string) (*Job, error) {
+	var job Job
+	path := "/admin/jobs/" + url.PathEscape(jobID) + "/cancel"
+	if err := c.mutate(ctx, http.MethodPost, path, nil, map[string]string{"reason": reason}, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// orderPath /admin/orders/<orderId>/<route>
+func orderPath(orderID, route string) string {
+	return "/admin/orders/" + url.PathEscape(orderID) + "/" + route
+}
+
+// artifactPath /admin/orders/<orderId>/artifacts/<name>
+func artifactPath(orderID, name string) string {
+	return orderPath(orderID, "artifacts") + "/" + url.PathEscape(name)
+}
+
+// directActionPath /admin/direct-actions/<suffix> (앞의 ":"는 제거)
+func directActionPath(suffix string) string {
+	return "/admin/direct-actions/" + url.PathEscape(strings.TrimPrefix(suffix, ":"))
+}
+
+// commandPath /admin/commands/<type|correlationId>/<route>
+func commandPath(key, route string) string {
+	return "/admin/commands/" + url.PathEscape(key) + "/" + route
+}
@@ -0,0 +1,400 @@
+// client/robots.go
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// InitPositionRequests 로봇의 최근 위치 초기화 요청과 진행 상태 (최신순)
+func (c *Client) InitPositionRequests(ctx context.Context, serialNumber string) ([]InitPositionRequest, error) {
+	var requests []InitPositionRequest
+	if err := c.get(ctx, robotPath(serialNumber, "init-position"), nil, &requests); err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// InitPosition 지정한 위치로 initPosition 전송 (결과는 이후 state 메시지로 갱신되며 InitPositionRequests로 확인)
+func (c *Client) InitPosition(ctx context.Context, serialNumber string, pose Pose) (*InitPositionRequest, error) {
+	return c.initPosition(ctx, serialNumber, struct {
+		Pose
+		UseHome bool `json:"useHome"`
+	}{Pose: pose})
+}
+
+// InitPositionFromHome 로봇 기본(홈) 위치로 initPosition 전송
+func (c *Client) InitPositionFromHome(ctx context.Context, serialNumber string) (*InitPositionRequest, error) {
+	return c.initPosition(ctx, serialNumber, map[string]bool{"useHome": true})
+}
+
+func (c *Client) initPosition(ctx context.Context, serialNumber string, body interface{}) (*InitPositionRequest, error) {
+	var request InitPositionRequest
+	if err := c.mutate(ctx, http.MethodPost, robotPath(serialNumber, "init-position"), nil, body, &request); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// EmergencyStop 로봇 비상 정지 (안전 액션 전송에 실패해도 오더 정리 결과와 함께 오류로 반환)
+func (c *Client) EmergencyStop(ctx context.Context, serialNumber, reason string) (*EmergencyStopResult, error) {
+	var result EmergencyStopResult
+	status, err := c.do(ctx, request{method: http.MethodPost, path: robotPath(serialNumber, "estop"), body: map[string]string{"reason": reason},
+		accept: []int{http.StatusBadGateway}, noRetry: true}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusBadGateway {
+		return &result, fmt.Errorf("emergency stop was not delivered to %s (%d order(s) stopped): %s", serialNumber, result.StoppedOrders, result.PublishError)
+	}
+	return &result, nil
+}
+
+// SendInstantActions 원시 VDA 5050 instantActions 메시지 전송 (스키마/허용 목록 검사 실패는 검증 오류, headerId는 서버가 다시 매김)
+func (c *Client) SendInstantActions(ctx context.Context, serialNumber string, payload []byte) (*InstantActionsMessage, error) {
+	var message InstantActionsMessage
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: robotPath(serialNumber, "instant-actions"), raw: payload, contentType: "application/json"}, &message); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// DualArmTrajectory 왼팔/오른팔 궤적을 오더 하나로 전송 (궤적 이름이 팩트시트에 없으면 검증 오류)
+func (c *Client) DualArmTrajectory(ctx context.Context, serialNumber string, req DualArmTrajectory) (*DualArmTrajectoryResult, error) {
+	var result DualArmTrajectoryResult
+	if err := c.mutate(ctx, http.MethodPost, robotPath(serialNumber, "dual-arm-trajectory"), nil, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ActionSchemas 로봇 팩트시트의 모든 액션과 파라미터 스키마
+func (c *Client) ActionSchemas(ctx context.Context, serialNumber string) ([]ActionSchema, error) {
+	var schemas []ActionSchema
+	if err := c.get(ctx, robotPath(serialNumber, "actions"), nil, &schemas); err != nil {
+		return nil, err
+	}
+	return schemas, nil
+}
+
+// ActionSchema 액션 하나의 파라미터 스키마
+func (c *Client) ActionSchema(ctx context.Context, serialNumber, actionType string) (*ActionSchema, error) {
+	var schema ActionSchema
+	if err := c.get(ctx, robotPath(serialNumber, "actions")+"/"+url.PathEscape(actionType)+"/schema", nil, &schema); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// Charging 로봇에 적용 중인 충전 정책과 자동 충전 상태
+func (c *Client) Charging(ctx context.Context, serialNumber string) (*ChargingStatus, error) {
+	var status ChargingStatus
+	if err := c.get(ctx, robotPath(serialNumber, "charging"), nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// RobotHealth 로봇 state 메시지 지연(시계 오차, 네트워크 지연) 통계와 기준 초과 여부
+func (c *Client) RobotHealth(ctx context.Context, serialNumber string) (*RobotLatencyHealth, error) {
+	var health RobotLatencyHealth
+	if err := c.get(ctx, robotPath(serialNumber, "health"), nil, &health); err != nil {
+		return nil, err
+	}
+	return &health, nil
+}
+
+// RobotStats 로봇의 최근 window 동안 SLA 지표 (window가 0이면 서버 기본값 24시간)
+func (c *Client) RobotStats(ctx context.Context, serialNumber string, window time.Duration) (*RobotStats, error) {
+	query := url.Values{"format": {"json"}}
+	if window > 0 {
+		query.Set("window", window.String())
+	}
+	var stats RobotStats
+	if err := c.get(ctx, "/metrics/robots/"+url.PathEscape(serialNumber), query, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// RobotMetadata 로봇 메타데이터 (위치, 담당 팀 등)
+func (c *Client) RobotMetadata(ctx context.Context, serialNumber string) (map[string]string, error) {
+	var metadata map[string]string
+	if err := c.get(ctx, robotPath(serialNumber, "metadata"), nil, &metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// PatchRobotMetadata 로봇 메타데이터 수정 (값이 nil인 키는 삭제), 수정 후 전체 메타데이터 반환
+func (c *Client) PatchRobotMetadata(ctx context.Context, serialNumber string, patch map[string]*string) (map[string]string, error) {
+	var metadata map[string]string
+	if err := c.mutate(ctx, http.MethodPatch, robotPath(serialNumber, "metadata"), nil, patch, &metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// RobotMaintenance 로봇 유지보수 모드 상태
+func (c *Client) RobotMaintenance(ctx context.Context, serialNumber string) (*RobotMaintenanceStatus, error) {
+	var status RobotMaintenanceStatus
+	if err := c.get(ctx, robotPath(serialNumber, "maintenance"), nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// SetRobotMaintenance 로봇을 유지보수 모드로 설정 (duration이 0이면 수동 해제까지 유지)
+func (c *Client) SetRobotMaintenance(ctx context.Context, serialNumber, reason string, duration time.Duration) (*RobotMaintenanceStatus, error) {
+	body := map[string]interface{}{"enabled": true, "reason": reason}
+	if duration > 0 {
+		body["for"] = duration.String()
+	}
+	return c.putRobotMaintenance(ctx, serialNumber, body)
+}
+
+// ClearRobotMaintenance 로봇의 유지보수 모드 해제
+func (c *Client) ClearRobotMaintenance(ctx context.Context, serialNumber string) (*RobotMaintenanceStatus, error) {
+	return c.putRobotMaintenance(ctx, serialNumber, map[string]bool{"enabled": false})
+}
+
+func (c *Client) putRobotMaintenance(ctx context.Context, serialNumber string, body interface{}) (*RobotMaintenanceStatus, error) {
+	var status RobotMaintenanceStatus
+	if err := c.mutate(ctx, http.MethodPut, robotPath(serialNumber, "maintenance"), nil, body, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// RobotDefaults 로봇 기본 위치 (없으면 IsNotFound 오류)
+func (c *Client) RobotDefaults(ctx context.Context, serialNumber string) (*RobotDefaults, error) {
+	var defaults RobotDefaults
+	if err := c.get(ctx, robotPath(serialNumber, "defaults"), nil, &defaults); err != nil {
+		return nil, err
+	}
+	return &defaults, nil
+}
+
+// SaveRobotDefaults 로봇 기본 위치 저장 (기존 값 교체), 저장된 값 반환
+func (c *Client) SaveRobotDefaults(ctx context.Context, serialNumber string, defaults RobotDefaults) (*RobotDefaults, error) {
+	var saved RobotDefaults
+	if err := c.mutate(ctx, http.MethodPut, robotPath(serialNumber, "defaults"), nil, defaults, &saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// DeleteRobotDefaults 로봇 기본 위치 삭제 (없으면 IsNotFound 오류)
+func (c *Client) DeleteRobotDefaults(ctx context.Context, serialNumber string) error {
+	return c.mutate(ctx, http.MethodDelete, robotPath(serialNumber, "defaults"), nil, nil, nil)
+}
+
+// SetDisabledPreflightChecks 로봇에서 끌 사전 점검 교체 (빈 목록이면 모두 켬)
+func (c *Client) SetDisabledPreflightChecks(ctx context.Context, serialNumber string, checks []string, reason string) error {
+	body := map[string]interface{}{"disabled": checks, "reason": reason}
+	return c.mutate(ctx, http.MethodPut, robotPath(serialNumber, "preflight"), nil, body, nil)
+}
+
+// Maps 사이트의 맵과 지오펜스 구역
+func (c *Client) Maps(ctx context.Context) ([]RobotMap, error) {
+	var maps []RobotMap
+	if err := c.get(ctx, "/admin/maps", nil, &maps); err != nil {
+		return nil, err
+	}
+	return maps, nil
+}
+
+// Map 맵 하나 (없으면 IsNotFound 오류)
+func (c *Client) Map(ctx context.Context, mapID string) (*RobotMap, error) {
+	var robotMap RobotMap
+	if err := c.get(ctx, "/admin/maps/"+url.PathEscape(mapID), nil, &robotMap); err != nil {
+		return nil, err
+	}
+	return &robotMap, nil
+}
+
+// SaveMap 맵 경계와 구역 저장 (같은 맵 ID는 구역까지 교체), 저장된 맵 반환
+func (c *Client) SaveMap(ctx context.Context, robotMap RobotMap) (*RobotMap, error) {
+	var saved RobotMap
+	if err := c.mutate(ctx, http.MethodPut, "/admin/maps/"+url.PathEscape(robotMap.MapID), nil, robotMap, &saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// DeleteMap 맵과 구역 삭제
+func (c *Client) DeleteMap(ctx context.Context, mapID string) error {
+	return c.mutate(ctx, http.MethodDelete, "/admin/maps/"+url.PathEscape(mapID), nil, nil, nil)
+}
+
+// Zones 사이트의 구역 점유 예약, 대기 로봇, 교착 순환
+func (c *Client) Zones(ctx context.Context) (*ZoneSnapshot, error) {
+	var snapshot ZoneSnapshot
+	if err := c.get(ctx, "/admin/zones", nil, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// ReleaseZone 구역 예약을 강제로 해제하고 해제한 예약 반환 (예약이 없으면 IsNotFound 오류)
+func (c *Client) ReleaseZone(ctx context.Context, zone string) (*ZoneReservation, error) {
+	var reservation ZoneReservation
+	if err := c.mutate(ctx, http.MethodDelete, "/admin/zones/"+url.PathEscape(zone), nil, nil, &reservation); err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// ExecutionWindows 사이트의 오더 실행 제한 시간대
+func (c *Client) ExecutionWindows(ctx context.Context) ([]ExecutionWindow, error) {
+	var windows []ExecutionWindow
+	if err := c.get(ctx, "/admin/windows", nil, &windows); err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+// SaveExecutionWindow 실행 제한 시간대 저장 (같은 이름은 교체, 관리자 일시 해제는 유지), 저장된 시간대 반환
+func (c *Client) SaveExecutionWindow(ctx context.Context, window ExecutionWindow) (*ExecutionWindow, error) {
+	var saved ExecutionWindow
+	if err := c.mutate(ctx, http.MethodPut, "/admin/windows/"+url.PathEscape(window.Name), nil, window, &saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// DeleteExecutionWindow 실행 제한 시간대 삭제
+func (c *Client) DeleteExecutionWindow(ctx context.Context, name string) error {
+	return c.mutate(ctx, http.MethodDelete, "/admin/windows/"+url.PathEscape(name), nil, nil, nil)
+}
+
+// OverrideExecutionWindow duration 동안 시간대 제한 해제 (대기 중인 명령은 1분 안에 배차)
+func (c *Client) OverrideExecutionWindow(ctx context.Context, name string, duration time.Duration) error {
+	return c.mutate(ctx, http.MethodPut, "/admin/windows/"+url.PathEscape(name)+"/override", nil,
+		map[string]string{"for": duration.String()}, nil)
+}
+
+// ClearExecutionWindowOverride 시간대 제한 해제 취소
+func (c *Client) ClearExecutionWindowOverride(ctx context.Context, name string) error {
+	return c.mutate(ctx, http.MethodDelete, "/admin/windows/"+url.PathEscape(name)+"/override", nil, nil, nil)
+}
+
+// ChargingPolicies 사이트의 자동 충전 정책
+func (c *Client) ChargingPolicies(ctx context.Context) ([]ChargingPolicy, error) {
+	var policies []ChargingPolicy
+	if err := c.get(ctx, "/admin/charging/policies", nil, &policies); err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// SaveChargingPolicy 충전 정책 저장 (같은 이름은 교체), 저장된 정책 반환
+func (c *Client) SaveChargingPolicy(ctx context.Context, policy ChargingPolicy) (*ChargingPolicy, error) {
+	var saved ChargingPolicy
+	if err := c.mutate(ctx, http.MethodPut, "/admin/charging/policies/"+url.PathEscape(policy.Name), nil, policy, &saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// DeleteChargingPolicy 충전 정책 삭제
+func (c *Client) DeleteChargingPolicy(ctx context.Context, name string) error {
+	return c.mutate(ctx, http.MethodDelete, "/admin/charging/policies/"+url.PathEscape(name), nil, nil, nil)
+}
+
+// Discovery 탐색/일괄 등록으로 등록된 로봇 목록 (status가 비어 있으면 전체)
+func (c *Client) Discovery(ctx context.Context, status string) (*Discovery, error) {
+	query := url.Values{}
+	if status != "" {
+		query.Set("status", status)
+	}
+	var discovery Discovery
+	if err := c.get(ctx, "/admin/discovery", query, &discovery); err != nil {
+		return nil, err
+	}
+	return &discovery, nil
+}
+
+// ApproveRobot 등록된 로봇을 승인하여 오더 배차 허용
+func (c *Client) ApproveRobot(ctx context.Context, serialNumber, note string) (*RobotRegistration, error) {
+	return c.decideRobot(ctx, serialNumber, "approve", note)
+}
+
+// RejectRobot 등록된 로봇 거부
+func (c *Client) RejectRobot(ctx context.Context, serialNumber, note string) (*RobotRegistration, error) {
+	return c.decideRobot(ctx, serialNumber, "reject", note)
+}
+
+func (c *Client) decideRobot(ctx context.Context, serialNumber, decision, note string) (*RobotRegistration, error) {
+	var registration RobotRegistration
+	path := "/admin/discovery/" + url.PathEscape(serialNumber) + "/" + decision
+	if err := c.mutate(ctx, http.MethodPost, path, nil, map[string]string{"note": note}, &registration); err != nil {
+		return nil, err
+	}
+	return &registration, nil
+}
+
+// RobotTokens 로봇 HTTP 콜백 세션 토큰 목록 (all이 false면 활성 토큰만, 원문은 포함하지 않음)
+func (c *Client) RobotTokens(ctx context.Context, serialNumber string, all bool) ([]RobotToken, error) {
+	query := url.Values{}
+	if all {
+		query.Set("all", "true")
+	}
+	var response struct {
+		Tokens []RobotToken `json:"tokens"`
+	}
+	if err := c.get(ctx, robotPath(serialNumber, "tokens"), query, &response); err != nil {
+		return nil, err
+	}
+	return response.Tokens, nil
+}
+
+// IssueRobotToken 토큰 발급 (원문은 이 응답에서만 받을 수 있음, ttl이 0이면 서버 기본값)
+func (c *Client) IssueRobotToken(ctx context.Context, serialNumber, label string, ttl time.Duration) (*IssuedRobotToken, error) {
+	return c.issueRobotToken(ctx, robotPath(serialNumber, "tokens"), label, ttl)
+}
+
+// RotateRobotToken 새 토큰 발급, 기존 토큰은 서버의 grace 시간 뒤 만료
+func (c *Client) RotateRobotToken(ctx context.Context, serialNumber, label string, ttl time.Duration) (*IssuedRobotToken, error) {
+	return c.issueRobotToken(ctx, robotPath(serialNumber, "tokens")+"/rotate", label, ttl)
+}
+
+func (c *Client) issueRobotToken(ctx context.Context, path, label string, ttl time.Duration) (*IssuedRobotToken, error) {
+	request := map[string]string{"label": label}
+	if ttl > 0 {
+		request["ttl"] = ttl.String()
+	}
+	var issued IssuedRobotToken
+	if err := c.mutate(ctx, http.MethodPost, path, nil, request, &issued); err != nil {
+		return nil, err
+	}
+	return &issued, nil
+}
+
+// RevokeRobotToken 토큰 하나 폐기
+func (c *Client) RevokeRobotToken(ctx context.Context, serialNumber, tokenID string) (*RobotToken, error) {
+	var token RobotToken
+	if err := c.mutate(ctx, http.MethodDelete, robotPath(serialNumber, "tokens")+"/"+url.PathEscape(tokenID), nil, nil, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RevokeRobotTokens 로봇의 모든 토큰 폐기, 폐기한 개수 반환
+func (c *Client) RevokeRobotTokens(ctx context.Context, serialNumber string) (int64, error) {
+	var response struct {
+		Revoked int64 `json:"revoked"`
+	}
+	if err := c.mutate(ctx, http.MethodDelete, robotPath(serialNumber, "tokens"), nil, nil, &response); err != nil {
+		return 0, err
+	}
+	return response.Revoked, nil
+}
+
+// robotPath /admin/robots/<serial>/<route>
+func robotPath(serialNumber, route string) string {
+	return "/admin/robots/" + url.PathEscape(serialNumber) + "/" + route
+}
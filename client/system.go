@@ -0,0 +1,385 @@
+// client/system.go
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Live 생존 프로브 (/healthz)
+func (c *Client) Live(ctx context.Context) error {
+	return c.get(ctx, "/healthz", nil, nil)
+}
+
+// Ready 준비 프로브 (/readyz) 보고서 (중요 의존성이 DOWN이면 Ready()가 false, 503도 오류가 아님)
+func (c *Client) Ready(ctx context.Context) (*HealthReport, error) {
+	var report HealthReport
+	_, err := c.do(ctx, request{method: http.MethodGet, path: "/readyz", accept: []int{http.StatusServiceUnavailable}, noRetry: true}, &report)
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// SelfTest 브릿지 자체 점검 보고서 (/admin/selftest, 점검이 실패하면 Passed()가 false, 503도 오류가 아님)
+func (c *Client) SelfTest(ctx context.Context) (*SelfTestReport, error) {
+	var report SelfTestReport
+	_, err := c.do(ctx, request{method: http.MethodGet, path: "/admin/selftest", accept: []int{http.StatusServiceUnavailable}, noRetry: true}, &report)
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Metrics Prometheus 텍스트 형식 지표 (/metrics)
+func (c *Client) Metrics(ctx context.Context) (string, error) {
+	var text string
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/metrics", text: true}, &text); err != nil {
+		return "", err
+	}
+	return text, nil
+}
+
+// SchemaReport 페이로드 스키마 검증 지표 (검증이 꺼져 있으면 IsNotFound 오류)
+func (c *Client) SchemaReport(ctx context.Context) (*SchemaReport, error) {
+	var report SchemaReport
+	if err := c.get(ctx, "/admin/schema", nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// FreshnessReport 수신 메시지 timestamp 검사 지표 (검사가 꺼져 있으면 IsNotFound 오류)
+func (c *Client) FreshnessReport(ctx context.Context) (*FreshnessReport, error) {
+	var report FreshnessReport
+	if err := c.get(ctx, "/admin/freshness", nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// ClockReport 로봇, PLC, DB와 브릿지 사이 시계 오차 추정 (감사가 꺼져 있으면 IsNotFound 오류)
+func (c *Client) ClockReport(ctx context.Context) (*ClockReport, error) {
+	var report ClockReport
+	if err := c.get(ctx, "/admin/diagnostics/clock", nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// AccessPolicy 적용 중인 CORS/변경 요청 IP 제한 정책
+func (c *Client) AccessPolicy(ctx context.Context) (*AccessPolicy, error) {
+	var policy AccessPolicy
+	if err := c.get(ctx, "/admin/access", nil, &policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// StatsOverview 로봇별 실행 현황, 대기열 깊이, 최근 1시간 처리량 요약
+func (c *Client) StatsOverview(ctx context.Context) (*StatsOverview, error) {
+	var overview StatsOverview
+	if err := c.get(ctx, "/admin/stats/overview", nil, &overview); err != nil {
+		return nil, err
+	}
+	return &overview, nil
+}
+
+// Retention 실행 이력 보존 정책과 최근 정리 실행 limit건
+func (c *Client) Retention(ctx context.Context, limit int) (*RetentionStatus, error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	var status RetentionStatus
+	if err := c.get(ctx, "/admin/retention", query, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// PurgeRetention 실행 이력 정리 시작 (dryRun이면 삭제하지 않고 건수만 계산)
+func (c *Client) PurgeRetention(ctx context.Context, dryRun bool) (*PurgeRun, error) {
+	var run PurgeRun
+	if err := c.mutate(ctx, http.MethodPost, "/admin/retention/purge", url.Values{"dry_run": {strconv.FormatBool(dryRun)}}, nil, &run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// TransportDebug 전송 경로의 최근 요청/응답 기록 limit건 (0이면 전부, 기록이 꺼진 경로는 IsNotFound 오류)
+func (c *Client) TransportDebug(ctx context.Context, transport string, limit int) (*TransportDebug, error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	var debug TransportDebug
+	if err := c.get(ctx, "/admin/transports/"+url.PathEscape(transport)+"/debug", query, &debug); err != nil {
+		return nil, err
+	}
+	return &debug, nil
+}
+
+// PLCStatusMap 적용 중인 PLC 응답 상태 매핑
+func (c *Client) PLCStatusMap(ctx context.Context) (*PLCStatusMap, error) {
+	var statusMap PLCStatusMap
+	if err := c.get(ctx, "/admin/plc/status-map", nil, &statusMap); err != nil {
+		return nil, err
+	}
+	return &statusMap, nil
+}
+
+// ReplacePLCStatusMap PLC 응답 상태 매핑 전체 교체 (빠진 상태는 기본 문자)
+func (c *Client) ReplacePLCStatusMap(ctx context.Context, statusMap map[string]string) (*PLCStatusMap, error) {
+	var result PLCStatusMap
+	if err := c.mutate(ctx, http.MethodPut, "/admin/plc/status-map", nil, statusMap, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Subscriptions MQTT 구독별 일시 중지 여부와 수신 지표
+func (c *Client) Subscriptions(ctx context.Context) ([]SubscriptionStats, error) {
+	var stats []SubscriptionStats
+	if err := c.get(ctx, "/admin/subscriptions", nil, &stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// PauseSubscription MQTT 구독 일시 중지 (topic은 구독 필터 그대로)
+func (c *Client) PauseSubscription(ctx context.Context, topic string) (*SubscriptionStats, error) {
+	return c.toggleSubscription(ctx, "pause", topic)
+}
+
+// ResumeSubscription MQTT 구독 재개
+func (c *Client) ResumeSubscription(ctx context.Context, topic string) (*SubscriptionStats, error) {
+	return c.toggleSubscription(ctx, "resume", topic)
+}
+
+func (c *Client) toggleSubscription(ctx context.Context, action, topic string) (*SubscriptionStats, error) {
+	var stats SubscriptionStats
+	if err := c.mutate(ctx, http.MethodPost, "/admin/subscriptions/"+action, nil, map[string]string{"topic": topic}, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// ReadOnly 읽기 전용 상태
+func (c *Client) ReadOnly(ctx context.Context) (*ReadOnlyStatus, error) {
+	var status ReadOnlyStatus
+	if err := c.get(ctx, "/admin/read-only", nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// SetReadOnly 읽기 전용 켜기/끄기 (켤 때는 reason 필수)
+func (c *Client) SetReadOnly(ctx context.Context, enabled bool, reason string) (*ReadOnlyStatus, error) {
+	var status ReadOnlyStatus
+	body := map[string]interface{}{"enabled": enabled, "reason": reason}
+	if err := c.mutate(ctx, http.MethodPut, "/admin/read-only", nil, body, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Faults 장애 주입 대상별 규칙과 지표 (FAULT_INJECTION이 꺼져 있으면 IsNotFound 오류)
+func (c *Client) Faults(ctx context.Context) ([]FaultTargetState, error) {
+	var states []FaultTargetState
+	if err := c.get(ctx, "/admin/faults", nil, &states); err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+// SetFault 대상(transport:mqtt, transport:http, postgres, redis)의 장애 주입 규칙 설정
+func (c *Client) SetFault(ctx context.Context, target string, rule FaultRule) ([]FaultTargetState, error) {
+	var states []FaultTargetState
+	if err := c.mutate(ctx, http.MethodPut, "/admin/faults/"+target, nil, rule, &states); err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+// ClearFaults 대상의 장애 주입 규칙 제거 (target이 비어 있으면 모두)
+func (c *Client) ClearFaults(ctx context.Context, target string) ([]FaultTargetState, error) {
+	path := "/admin/faults"
+	if target != "" {
+		path += "/" + target
+	}
+	var states []FaultTargetState
+	if err := c.mutate(ctx, http.MethodDelete, path, nil, nil, &states); err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+// Alerts 알림 이력 (filter.Rule, filter.Status, filter.Limit)과 알림 설정
+func (c *Client) Alerts(ctx context.Context, filter AlertEventFilter) (*Alerts, error) {
+	query := url.Values{}
+	if filter.Rule != "" {
+		query.Set("rule", filter.Rule)
+	}
+	if filter.Status != "" {
+		query.Set("status", filter.Status)
+	}
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	var alerts Alerts
+	if err := c.get(ctx, "/admin/alerts", query, &alerts); err != nil {
+		return nil, err
+	}
+	return &alerts, nil
+}
+
+// SendTestAlert 모든 알림 채널로 시험 알림 전송 (전송 실패도 기록된 이벤트와 함께 오류로 반환)
+func (c *Client) SendTestAlert(ctx context.Context, message string) (*AlertEvent, error) {
+	var event AlertEvent
+	status, err := c.do(ctx, request{method: http.MethodPost, path: "/admin/alerts/test", body: map[string]string{"message": message},
+		accept: []int{http.StatusBadGateway}, noRetry: true}, &event)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusBadGateway {
+		return &event, fmt.Errorf("test alert was not delivered (%s): %s", event.Status, event.Error)
+	}
+	return &event, nil
+}
+
+// AlertSuppressions 현재와 앞으로의 알림 억제 시간대
+func (c *Client) AlertSuppressions(ctx context.Context) ([]AlertSuppression, error) {
+	var suppressions []AlertSuppression
+	if err := c.get(ctx, "/admin/alerts/suppressions", nil, &suppressions); err != nil {
+		return nil, err
+	}
+	return suppressions, nil
+}
+
+// CreateAlertSuppression 알림 억제 시간대 추가
+func (c *Client) CreateAlertSuppression(ctx context.Context, suppression AlertSuppression) (*AlertSuppression, error) {
+	var created AlertSuppression
+	if err := c.mutate(ctx, http.MethodPost, "/admin/alerts/suppressions", nil, suppression, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// DeleteAlertSuppression 알림 억제 시간대 삭제
+func (c *Client) DeleteAlertSuppression(ctx context.Context, id uint) error {
+	return c.mutate(ctx, http.MethodDelete, "/admin/alerts/suppressions/"+strconv.FormatUint(uint64(id), 10), nil, nil, nil)
+}
+
+// Import CSV 일괄 등록 (kind: robots, nodes, actions)
+// 행 오류가 있으면 아무것도 저장되지 않으며 오류 없이 report.Valid()가 false인 결과를 반환합니다.
+func (c *Client) Import(ctx context.Context, kind string, csv io.Reader, dryRun bool) (*ImportReport, error) {
+	data, err := io.ReadAll(csv)
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{"kind": {kind}, "dry_run": {strconv.FormatBool(dryRun)}}
+	var report ImportReport
+	_, err = c.do(ctx, request{method: http.MethodPost, path: "/admin/import", query: query, raw: data, contentType: "text/csv",
+		accept: []int{http.StatusUnprocessableEntity}}, &report)
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Logs 브릿지가 메모리에 보관한 최근 로그 limit건 (LOG_BUFFER_SIZE=0이면 IsNotFound 오류)
+func (c *Client) Logs(ctx context.Context, filter LogFilter, limit int) (*Logs, error) {
+	query := logQuery(filter)
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	var logs Logs
+	if err := c.get(ctx, "/admin/logs", query, &logs); err != nil {
+		return nil, err
+	}
+	return &logs, nil
+}
+
+// StreamLogs 최근 로그 backlog건과 이후 새 로그를 ctx가 끝날 때까지 fn으로 전달합니다.
+// ctx가 끝나면 nil, 브릿지가 연결을 끊으면(느린 클라이언트, 서버 종료) 오류를 반환하며 재연결은 호출자가 합니다.
+func (c *Client) StreamLogs(ctx context.Context, filter LogFilter, backlog int, fn func(LogEntry)) error {
+	query := logQuery(filter)
+	query.Set("backlog", strconv.Itoa(backlog))
+	resp, err := c.send(ctx, request{method: http.MethodGet, path: "/admin/logs/stream", query: query, noRetry: true})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return decodeError(resp.StatusCode, body)
+	}
+
+	event := ""
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			if event == "dropped" {
+				return fmt.Errorf("log stream dropped by the bridge (client too slow)")
+			}
+			var entry LogEntry
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &entry); err == nil {
+				fn(entry)
+			}
+		case line == "":
+			event = ""
+		}
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return io.ErrUnexpectedEOF
+}
+
+func logQuery(filter LogFilter) url.Values {
+	query := url.Values{}
+	if filter.Level != "" {
+		query.Set("level", filter.Level)
+	}
+	if len(filter.Modules) > 0 {
+		query.Set("module", strings.Join(filter.Modules, ","))
+	}
+	return query
+}
+
+// GraphQL 대시보드 GraphQL 조회 결과의 data를 out에 해석합니다.
+// 필드 오류가 있어도 나머지 data는 채워지며 오류 목록을 함께 반환합니다.
+func (c *Client) GraphQL(ctx context.Context, req GraphQLRequest, out interface{}) ([]GraphQLError, error) {
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []GraphQLError  `json:"errors"`
+	}
+	// 조회이므로 POST여도 Idempotency-Key를 붙이지 않습니다 (서버가 응답을 보관하지 않도록).
+	_, err := c.do(ctx, request{method: http.MethodPost, path: "/graphql", body: req, accept: []int{http.StatusBadRequest}, readOnly: true}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if out != nil && len(resp.Data) > 0 && !bytes.Equal(resp.Data, []byte("null")) {
+		if err := json.Unmarshal(resp.Data, out); err != nil {
+			return resp.Errors, err
+		}
+	}
+	return resp.Errors, nil
+}
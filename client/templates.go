@@ -0,0 +1,226 @@
+// client/templates.go
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// TemplateRollouts 템플릿 카나리 롤아웃 목록
+func (c *Client) TemplateRollouts(ctx context.Context) ([]TemplateRollout, error) {
+	var rollouts []TemplateRollout
+	if err := c.get(ctx, "/admin/templates/rollouts", nil, &rollouts); err != nil {
+		return nil, err
+	}
+	return rollouts, nil
+}
+
+// StartTemplateRollout 후보 템플릿을 canaryRobots에만 적용하는 카나리 롤아웃 시작
+func (c *Client) StartTemplateRollout(ctx context.Context, baseTemplateID, candidateTemplateID uint, canaryRobots []string) (*TemplateRollout, error) {
+	body := map[string]interface{}{
+		"base_template_id":      baseTemplateID,
+		"candidate_template_id": candidateTemplateID,
+		"canary_robots":         canaryRobots,
+	}
+	var rollout TemplateRollout
+	if err := c.mutate(ctx, http.MethodPost, "/admin/templates/rollouts", nil, body, &rollout); err != nil {
+		return nil, err
+	}
+	return &rollout, nil
+}
+
+// TemplateRolloutReport 롤아웃의 기존/후보 버전 성공률 비교
+func (c *Client) TemplateRolloutReport(ctx context.Context, rolloutID uint) (*TemplateRolloutReport, error) {
+	var report TemplateRolloutReport
+	if err := c.get(ctx, rolloutPath(rolloutID, ""), nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// PromoteTemplateRollout 후보 버전 승격 (force면 성공률 조건 무시)
+func (c *Client) PromoteTemplateRollout(ctx context.Context, rolloutID uint, force bool) (*TemplateRolloutReport, error) {
+	var report TemplateRolloutReport
+	query := url.Values{"force": {strconv.FormatBool(force)}}
+	if err := c.mutate(ctx, http.MethodPost, rolloutPath(rolloutID, "/promote"), query, nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// RollbackTemplateRollout 카나리 중단
+func (c *Client) RollbackTemplateRollout(ctx context.Context, rolloutID uint) (*TemplateRolloutReport, error) {
+	var report TemplateRolloutReport
+	if err := c.mutate(ctx, http.MethodPost, rolloutPath(rolloutID, "/rollback"), nil, nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Templates 사이트의 오더 템플릿 목록 (includeDeleted면 보관된 템플릿 포함)
+func (c *Client) Templates(ctx context.Context, includeDeleted bool) ([]OrderTemplate, error) {
+	var templates []OrderTemplate
+	query := url.Values{"include_deleted": {strconv.FormatBool(includeDeleted)}}
+	if err := c.get(ctx, "/admin/templates", query, &templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// RestoreTemplate 보관된 템플릿과 보관 시 함께 삭제된 스텝, 엣지 복구
+func (c *Client) RestoreTemplate(ctx context.Context, templateID uint) error {
+	return c.mutate(ctx, http.MethodPost, templatePath(templateID, "restore"), nil, nil, nil)
+}
+
+// PurgeTemplate 보관된 템플릿을 영구 삭제 (보관되지 않은 템플릿은 검증 오류)
+func (c *Client) PurgeTemplate(ctx context.Context, templateID uint) error {
+	return c.mutate(ctx, http.MethodPost, templatePath(templateID, "purge"), nil, nil, nil)
+}
+
+// CloneTemplate 템플릿을 새 이름으로 복제 (draft면 DRAFT 상태로 생성), 복제본 반환
+func (c *Client) CloneTemplate(ctx context.Context, templateID uint, name string, draft bool) (*OrderTemplate, error) {
+	var clone OrderTemplate
+	body := map[string]interface{}{"name": name, "draft": draft}
+	if err := c.mutate(ctx, http.MethodPost, templatePath(templateID, "clone"), nil, body, &clone); err != nil {
+		return nil, err
+	}
+	return &clone, nil
+}
+
+// SetTemplateStatus 템플릿 상태 변경 (DRAFT, ACTIVE, DEPRECATED, 카나리 롤아웃 중이면 검증 오류), 변경된 템플릿 반환
+func (c *Client) SetTemplateStatus(ctx context.Context, templateID uint, status string) (*OrderTemplate, error) {
+	return c.putTemplateSetting(ctx, templateID, "status", map[string]string{"status": status})
+}
+
+// SetTemplateLimit 사이트 전체에서 템플릿 오더를 동시에 실행할 최대 로봇 수 (0이면 제한 없음)
+func (c *Client) SetTemplateLimit(ctx context.Context, templateID uint, maxConcurrent int) (*OrderTemplate, error) {
+	return c.putTemplateSetting(ctx, templateID, "limit", map[string]int{"max_concurrent_executions": maxConcurrent})
+}
+
+// SetTemplateDeadline 템플릿 오더 실행 제한 시간 (0이면 서버의 ORDER_DEADLINE_SECONDS)
+func (c *Client) SetTemplateDeadline(ctx context.Context, templateID uint, deadline time.Duration) (*OrderTemplate, error) {
+	return c.putTemplateSetting(ctx, templateID, "deadline", map[string]string{"deadline": deadline.String()})
+}
+
+// SetTemplateNotifications 템플릿 오더 종료 알림 설정
+func (c *Client) SetTemplateNotifications(ctx context.Context, templateID uint, settings TemplateNotifications) (*OrderTemplate, error) {
+	return c.putTemplateSetting(ctx, templateID, "notifications", settings)
+}
+
+func (c *Client) putTemplateSetting(ctx context.Context, templateID uint, route string, body interface{}) (*OrderTemplate, error) {
+	var template OrderTemplate
+	if err := c.mutate(ctx, http.MethodPut, templatePath(templateID, route), nil, body, &template); err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// ImportTemplates 내보내기 형식의 템플릿을 한 트랜잭션으로 가져오기 (하나라도 실패하면 아무것도 만들지 않음)
+func (c *Client) ImportTemplates(ctx context.Context, exports []TemplateExport) ([]OrderTemplate, error) {
+	var templates []OrderTemplate
+	if err := c.mutate(ctx, http.MethodPost, "/admin/templates/import", nil, exports, &templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// SyncDiff 원본 브릿지(TEMPLATE_SYNC_SOURCES 중 하나, 하나만 있으면 생략 가능)와 템플릿/PLC 매핑 비교
+func (c *Client) SyncDiff(ctx context.Context, source string) (*SyncDiff, error) {
+	query := url.Values{}
+	if source != "" {
+		query.Set("source", source)
+	}
+	var diff SyncDiff
+	if err := c.get(ctx, "/admin/sync/diff", query, &diff); err != nil {
+		return nil, err
+	}
+	return &diff, nil
+}
+
+// ApplySync 원본 브릿지의 고른 변경(template:<이름>, command:<명령 유형>)을 한 트랜잭션으로 적용 (dryRun이면 적용해 보고 되돌림)
+func (c *Client) ApplySync(ctx context.Context, source string, changes []string, dryRun bool) (*SyncApplyResult, error) {
+	query := url.Values{}
+	if dryRun {
+		query.Set("dry_run", "true")
+	}
+	body := map[string]interface{}{"source": source, "changes": changes}
+	var result SyncApplyResult
+	if err := c.mutate(ctx, http.MethodPost, "/admin/sync/apply", query, body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// EstimateTemplate 템플릿 오더의 예상 소요 시간 (serialNumber가 있으면 그 로봇의 실행 기록 우선)
+func (c *Client) EstimateTemplate(ctx context.Context, templateID uint, serialNumber string) (*DurationEstimate, error) {
+	query := url.Values{}
+	if serialNumber != "" {
+		query.Set("robot", serialNumber)
+	}
+	var estimate DurationEstimate
+	if err := c.get(ctx, templatePath(templateID, "estimate"), query, &estimate); err != nil {
+		return nil, err
+	}
+	return &estimate, nil
+}
+
+// LintTemplate 템플릿에 모범 사례 규칙을 적용한 보고서 (문제가 있어도 오류가 아니며 report.Errors/Warnings로 판단)
+func (c *Client) LintTemplate(ctx context.Context, templateID uint) (*LintReport, error) {
+	var report LintReport
+	_, err := c.do(ctx, request{method: http.MethodPost, path: templatePath(templateID, "lint"), readOnly: true}, &report)
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// TemplateShadow 템플릿의 섀도 실행 설정 (없으면 IsNotFound 오류)
+func (c *Client) TemplateShadow(ctx context.Context, templateID uint) (*TemplateShadow, error) {
+	var shadow TemplateShadow
+	if err := c.get(ctx, templatePath(templateID, "shadow"), nil, &shadow); err != nil {
+		return nil, err
+	}
+	return &shadow, nil
+}
+
+// SetTemplateShadow 템플릿 실행마다 shadowTemplateID의 오더 메시지도 만들어 비교 (기존 설정 교체)
+func (c *Client) SetTemplateShadow(ctx context.Context, templateID, shadowTemplateID uint) (*TemplateShadow, error) {
+	var shadow TemplateShadow
+	if err := c.mutate(ctx, http.MethodPut, templatePath(templateID, "shadow"), nil,
+		map[string]uint{"shadow_template_id": shadowTemplateID}, &shadow); err != nil {
+		return nil, err
+	}
+	return &shadow, nil
+}
+
+// RemoveTemplateShadow 섀도 실행 중지
+func (c *Client) RemoveTemplateShadow(ctx context.Context, templateID uint) error {
+	return c.mutate(ctx, http.MethodDelete, templatePath(templateID, "shadow"), nil, nil, nil)
+}
+
+// CompareTemplateShadow 실행별 오더 메시지 비교 (최근 limit건, 0이면 서버 기본값)
+func (c *Client) CompareTemplateShadow(ctx context.Context, templateID, shadowTemplateID uint, limit int) (*ShadowCompareReport, error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	var report ShadowCompareReport
+	path := templatePath(templateID, "shadow-compare") + "/" + strconv.FormatUint(uint64(shadowTemplateID), 10)
+	if err := c.get(ctx, path, query, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// templatePath /admin/templates/<id>/<route>
+func templatePath(templateID uint, route string) string {
+	return "/admin/templates/" + strconv.FormatUint(uint64(templateID), 10) + "/" + route
+}
+
+// rolloutPath /admin/templates/rollouts/<id>[<action>]
+func rolloutPath(rolloutID uint, action string) string {
+	return "/admin/templates/rollouts/" + strconv.FormatUint(uint64(rolloutID), 10) + action
+}
@@ -0,0 +1,143 @@
+// client/types.go
+package client
+
+import (
+	"mqtt-bridge/internal/common/readonly"
+	"mqtt-bridge/internal/diagnostics"
+	"mqtt-bridge/internal/faults"
+	"mqtt-bridge/internal/graphql"
+	"mqtt-bridge/internal/grpcapi"
+	"mqtt-bridge/internal/health"
+	"mqtt-bridge/internal/messaging"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/provisioning"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/retention"
+	"mqtt-bridge/internal/robot"
+	"mqtt-bridge/internal/selftest"
+	"mqtt-bridge/internal/utils"
+	"mqtt-bridge/internal/workflow"
+	"mqtt-bridge/internal/zones"
+)
+
+// 서버가 쓰는 요청/응답 타입 별칭 (이 모듈 밖에서도 이름으로 쓸 수 있도록)
+type (
+	HealthReport            = health.Report
+	SelfTestReport          = selftest.Report
+	StatsOverview           = health.StatsOverviewResponse
+	AccessPolicy            = health.AccessPolicy
+	SchemaReport            = messaging.SchemaReport
+	FreshnessReport         = messaging.FreshnessReport
+	ClockReport             = diagnostics.ClockReport
+	SubscriptionStats       = messaging.SubscriptionStats
+	RetentionPolicy         = retention.Policy
+	PurgeRun                = models.PurgeRun
+	TransportCapture        = workflow.TransportCapture
+	DryRunReport            = workflow.DryRunReport
+	OrderWaitResult         = workflow.OrderWaitResult
+	OrderTimeline           = repository.OrderTimeline
+	OrderArtifact           = models.OrderArtifact
+	TimelineEvent           = repository.TimelineEvent
+	ChargingStatus          = workflow.ChargingStatus
+	RobotLatencyHealth      = health.RobotLatencyHealth
+	RobotMaintenanceStatus  = health.RobotMaintenanceStatus
+	RobotStats              = repository.RobotStats
+	StepFailureCount        = repository.StepFailureCount
+	OrderTemplate           = models.OrderTemplate
+	TemplateRollout         = models.TemplateRollout
+	TemplateRolloutReport   = repository.TemplateRolloutReport
+	TemplateShadow          = models.TemplateShadow
+	ShadowCompareReport     = repository.ShadowCompareReport
+	DurationEstimate        = repository.DurationEstimate
+	LintReport              = repository.LintReport
+	LintFinding             = repository.LintFinding
+	FaultRule               = faults.Rule
+	FaultTargetState        = faults.TargetState
+	AlertEvent              = models.AlertEvent
+	AlertEventFilter        = repository.AlertEventFilter
+	AlertSuppression        = models.AlertSuppression
+	Pose                    = models.PoseValue
+	InitPositionRequest     = robot.InitPositionRequest
+	InstantActionsMessage   = robot.InstantActionsMessage
+	ActionSchema            = robot.ActionSchema
+	RobotRegistration       = models.RobotRegistration
+	RobotToken              = models.RobotToken
+	IssuedRobotToken        = repository.IssuedRobotToken
+	Annotation              = models.ExecutionAnnotation
+	AnnotationInput         = repository.AnnotationInput
+	CommandHistory          = repository.CommandHistory
+	CommandHistoryFilter    = repository.CommandHistoryFilter
+	DirectAction            = models.DirectActionDefinition
+	DirectActionArgument    = models.DirectActionArgument
+	Job                     = models.Job
+	JobTask                 = models.JobTask
+	JobRequest              = repository.JobRequest
+	JobTaskRequest          = repository.JobTaskRequest
+	JobFilter               = repository.JobFilter
+	ImportReport            = provisioning.Report
+	LogEntry                = utils.LogEntry
+	LogFilter               = utils.LogFilter
+	GraphQLRequest          = graphql.Request
+	GraphQLError            = graphql.ResponseError
+	EmergencyStopResult     = health.EmergencyStopResult
+	DualArmTrajectory       = workflow.DualArmTrajectory
+	DualArmTrajectoryResult = workflow.DualArmTrajectoryResult
+	OrderExecution          = models.OrderExecution
+	RobotMap                = models.RobotMap
+	MapZone                 = models.MapZone
+	ZoneSnapshot            = zones.Snapshot
+	ZoneReservation         = zones.Reservation
+	ZoneWaiter              = zones.Waiter
+	ReadOnlyStatus          = readonly.Status
+	RobotStatus             = models.RobotStatus
+	RobotStateMessage       = models.RobotStateMessage
+	GRPCListOrdersRequest   = grpcapi.ListOrdersRequest
+	RobotDefaults           = models.RobotDefaults
+	ExecutionWindow         = models.ExecutionWindow
+	ChargingPolicy          = models.ChargingPolicy
+	TemplateNotifications   = repository.TemplateNotifications
+	TemplateExport          = repository.TemplateExport
+	SyncDiff                = repository.SyncDiff
+	SyncChange              = repository.SyncChange
+	SyncApplyResult         = repository.SyncApplyResult
+)
+
+// RetentionStatus 실행 이력 보존 정책과 최근 정리 실행
+type RetentionStatus struct {
+	Policy RetentionPolicy `json:"policy"`
+	Runs   []PurgeRun      `json:"runs"`
+}
+
+// TransportDebug 전송 경로의 요청/응답 디버그 기록
+type TransportDebug struct {
+	Transport string             `json:"transport"`
+	Total     int                `json:"total"`
+	Captures  []TransportCapture `json:"captures"`
+}
+
+// PLCStatusMap 적용 중인 PLC 응답 상태 매핑
+type PLCStatusMap struct {
+	Codec     string            `json:"codec"`
+	File      string            `json:"file"`
+	StatusMap map[string]string `json:"status_map"`
+}
+
+// Alerts 알림 이력과 알림 설정 (Enabled가 false면 이 브릿지에 알림 채널이 없음)
+type Alerts struct {
+	Enabled  bool         `json:"enabled"`
+	Events   []AlertEvent `json:"events"`
+	Rules    string       `json:"rules,omitempty"`
+	Channels []string     `json:"channels,omitempty"`
+}
+
+// Discovery 탐색으로 등록된 로봇 목록 (Enabled가 false면 이 브릿지에서 탐색이 꺼져 있음)
+type Discovery struct {
+	Enabled bool                `json:"enabled"`
+	Robots  []RobotRegistration `json:"robots"`
+}
+
+// Logs 브릿지가 메모리에 보관한 최근 로그
+type Logs struct {
+	BufferSize int        `json:"buffer_size"`
+	Entries    []LogEntry `json:"entries"`
+}
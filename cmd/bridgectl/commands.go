@@ -0,0 +1,579 @@
+// cmd/bridgectl/commands.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/client"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/common/idgen"
+	"mqtt-bridge/internal/messaging"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/workflow"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/spf13/cobra"
+)
+
+// newPLCCmd PLC 연동 설정 명령
+func newPLCCmd() *cobra.Command {
+	plcCmd := &cobra.Command{Use: "plc", Short: "PLC 연동 설정"}
+
+	var set map[string]string
+	var reset bool
+	statusMapCmd := &cobra.Command{
+		Use:   "status-map",
+		Short: "실행 중인 브릿지의 PLC 응답 상태 매핑 조회/교체 (HEALTH_ADDR의 /admin/plc/status-map)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.HealthAddr == "" {
+				return apperr.New(apperr.CodeTransportUnavailable, "HEALTH_ADDR is not configured")
+			}
+			addr := cfg.HealthAddr
+			if strings.HasPrefix(addr, ":") {
+				addr = "localhost" + addr
+			}
+			url := "http://" + addr + "/admin/plc/status-map"
+
+			req, err := http.NewRequest(http.MethodGet, url, nil)
+			if err != nil {
+				return err
+			}
+			if len(set) > 0 || reset {
+				overrides := make(map[string]string, len(set))
+				for name, value := range set {
+					overrides[strings.ToUpper(name)] = value
+				}
+				body, err := json.Marshal(overrides)
+				if err != nil {
+					return err
+				}
+				req, err = http.NewRequest(http.MethodPut, url, bytes.NewReader(body))
+				if err != nil {
+					return err
+				}
+				req.Header.Set("Content-Type", "application/json")
+			}
+
+			httpClient := &http.Client{Timeout: 5 * time.Second}
+			resp, err := httpClient.Do(req)
+			if err != nil {
+				return apperr.Wrap(apperr.CodeTransportUnavailable, err, "failed to reach bridge health server")
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				var errResp apperr.Response
+				if json.NewDecoder(resp.Body).Decode(&errResp) == nil && errResp.Code != "" {
+					return apperr.New(errResp.Code, "%s", errResp.Message)
+				}
+				return fmt.Errorf("unexpected status from health server: %s", resp.Status)
+			}
+
+			var result struct {
+				Codec     string            `json:"codec"`
+				File      string            `json:"file"`
+				StatusMap map[string]string `json:"status_map"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				return err
+			}
+			fmt.Printf("Codec: %s\n", result.Codec)
+			if result.File != "" {
+				fmt.Printf("File: %s\n", result.File)
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "STATUS\tVALUE")
+			for _, name := range messaging.PLCStatusNames() {
+				fmt.Fprintf(w, "%s\t%s\n", name, result.StatusMap[name])
+			}
+			return w.Flush()
+		},
+	}
+	statusMapCmd.Flags().StringToStringVar(&set, "set", nil, "매핑 전체 교체 (예: --set SUCCESS=OK,FAILURE=NG, 빠진 상태는 기본 문자)")
+	statusMapCmd.Flags().BoolVar(&reset, "reset", false, "모든 상태를 기본 문자로 되돌림")
+	plcCmd.AddCommand(statusMapCmd)
+
+	return plcCmd
+}
+
+// newCommandCmd PLC 명령 전송 명령
+func newCommandCmd() *cobra.Command {
+	commandCmd := &cobra.Command{Use: "command", Short: "PLC 명령"}
+
+	var params map[string]string
+	sendCmd := &cobra.Command{
+		Use:   "send <command>",
+		Short: "브릿지에 PLC 명령을 전송하고 응답 대기 (예: CR, GR:I, OC)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sendPLCCommandWithParams(args[0], params)
+		},
+	}
+	sendCmd.Flags().StringToStringVarP(&params, "param", "p", nil, "템플릿 자리표시자 값 (예: -p pallet_id=P1)")
+	commandCmd.AddCommand(sendCmd)
+	commandCmd.AddCommand(newAnnotateCmd("annotate <correlationId>", "명령에 라벨/메모 추가 (그 명령이 만든 오더의 검색에도 포함)",
+		(*client.Client).AnnotateCommand))
+	commandCmd.AddCommand(&cobra.Command{
+		Use:   "annotations <correlationId>",
+		Short: "명령에 남긴 라벨/메모",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+			annotations, err := repository.ListCommandAnnotations(db, cfg.SiteID, args[0])
+			if err != nil {
+				return err
+			}
+			printAnnotations(annotations)
+			return nil
+		},
+	})
+
+	var dryRunParams map[string]string
+	var dryRunJSON bool
+	dryRunCmd := &cobra.Command{
+		Use:   "dry-run <commandType>",
+		Short: "명령의 오더 매핑 체인을 따라 생성될 오더 메시지 시뮬레이션 (로봇 전송/실행 기록 없음)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+			report, err := workflow.DryRunCommand(db, cfg, args[0], dryRunParams)
+			if err != nil {
+				return err
+			}
+
+			if dryRunJSON {
+				data, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			fmt.Printf("Command: %s\n", report.CommandType)
+			if report.ParameterError != "" {
+				fmt.Printf("Parameters: %s (a real run would be rejected)\n", report.ParameterError)
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ORDER\tTEMPLATE\tSTEPS\tNODES\tACTIONS\tON SUCCESS\tON FAILURE\tNOTES")
+			for _, order := range report.Orders {
+				nodes, actions := 0, 0
+				var notes []string
+				if order.Error != "" {
+					notes = append(notes, "error: "+order.Error)
+				}
+				for _, step := range order.Steps {
+					nodes += len(step.Message.Nodes)
+					for _, node := range step.Message.Nodes {
+						actions += len(node.Actions)
+					}
+					if step.Conditional {
+						notes = append(notes, fmt.Sprintf("step %v conditional", step.StepOrders))
+					}
+					if step.FailureBranch {
+						notes = append(notes, fmt.Sprintf("step %v on failure only", step.StepOrders))
+					}
+					if step.GeofenceError != "" {
+						notes = append(notes, fmt.Sprintf("step %v geofence: %s", step.StepOrders, step.GeofenceError))
+					}
+				}
+				fmt.Fprintf(w, "%d\t%s (%d)\t%d\t%d\t%d\t%d\t%d\t%s\n",
+					order.ExecutionOrder, order.TemplateName, order.TemplateID, len(order.Steps), nodes, actions,
+					order.NextOnSuccess, order.NextOnFailure, strings.Join(notes, "; "))
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+
+			fmt.Println("\nPaths:")
+			for _, path := range report.Paths {
+				fmt.Printf("  %s -> %s\n", strings.Join(path.Outcomes, " -> "), path.Result)
+			}
+			if report.PathsTruncated {
+				fmt.Println("  ... (more paths omitted)")
+			}
+			if len(report.MissingMappings) > 0 {
+				fmt.Printf("Missing mappings: %v\n", report.MissingMappings)
+			}
+			return nil
+		},
+	}
+	dryRunCmd.Flags().StringToStringVarP(&dryRunParams, "param", "p", nil, "템플릿 자리표시자 값 (예: -p pallet_id=P1)")
+	dryRunCmd.Flags().BoolVar(&dryRunJSON, "json", false, "생성될 오더 메시지 전체를 JSON으로 출력")
+	commandCmd.AddCommand(dryRunCmd)
+	commandCmd.AddCommand(newCommandHistoryCmd())
+
+	return commandCmd
+}
+
+// newCommandHistoryCmd PLC 명령 이력 조회 (인자가 있으면 명령 하나의 전체 실행 트리)
+func newCommandHistoryCmd() *cobra.Command {
+	var filter repository.CommandHistoryFilter
+	var since time.Duration
+	var asJSON bool
+	historyCmd := &cobra.Command{
+		Use:   "history [commandId]",
+		Short: "PLC 명령 이력 (실행, 오더 체인, PLC 응답 기록)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+			if len(args) == 1 {
+				id, err := strconv.ParseUint(args[0], 10, 64)
+				if err != nil {
+					return apperr.Validation("commandId", "invalid command id: %s", args[0])
+				}
+				history, err := repository.GetCommandHistory(db, cfg.SiteID, uint(id))
+				if err != nil {
+					return err
+				}
+				if asJSON {
+					data, err := json.MarshalIndent(history, "", "  ")
+					if err != nil {
+						return err
+					}
+					fmt.Println(string(data))
+					return nil
+				}
+				printCommandHistory(*history)
+				return nil
+			}
+
+			if since > 0 {
+				filter.From = time.Now().Add(-since)
+			}
+			histories, err := repository.ListCommandHistory(db, cfg.SiteID, filter)
+			if err != nil {
+				return err
+			}
+			if asJSON {
+				data, err := json.MarshalIndent(histories, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tREQUESTED\tCOMMAND\tSTATUS\tCID\tORDERS\tRESPONSES\tERROR")
+			for _, h := range histories {
+				orders, responses := "-", "-"
+				var chain []string
+				for _, execution := range h.Executions {
+					for _, order := range execution.Orders {
+						chain = append(chain, fmt.Sprintf("%d:%s", order.ExecutionOrder, order.Status))
+					}
+				}
+				if len(chain) > 0 {
+					orders = strings.Join(chain, " -> ")
+				}
+				if len(h.Responses) > 0 {
+					var statuses []string
+					for _, response := range h.Responses {
+						statuses = append(statuses, response.Status)
+					}
+					responses = strings.Join(statuses, ",")
+				}
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", h.ID, h.RequestTime.Format(time.RFC3339), h.CommandType,
+					h.Status, h.CorrelationID, orders, responses, h.ErrorMessage)
+			}
+			return w.Flush()
+		},
+	}
+	historyCmd.Flags().StringVar(&filter.Type, "type", "", "명령 종류 (예: CR)")
+	historyCmd.Flags().StringVar(&filter.Status, "status", "", "명령 상태 (PENDING, SUCCESS, FAILURE 등)")
+	historyCmd.Flags().DurationVar(&since, "since", 24*time.Hour, "조회 기간 (0이면 제한 없음)")
+	historyCmd.Flags().IntVar(&filter.Limit, "limit", 50, "최대 건수")
+	historyCmd.Flags().BoolVar(&asJSON, "json", false, "JSON으로 출력")
+	return historyCmd
+}
+
+// printCommandHistory 명령 하나의 실행 트리 출력
+func printCommandHistory(h repository.CommandHistory) {
+	fmt.Printf("Command %d: %s (cid=%s)\n", h.ID, h.CommandType, h.CorrelationID)
+	fmt.Printf("Status:    %s\n", h.Status)
+	fmt.Printf("Requested: %s\n", h.RequestTime.Format(time.RFC3339))
+	if h.ResponseTime != nil {
+		fmt.Printf("Responded: %s\n", h.ResponseTime.Format(time.RFC3339))
+	}
+	if len(h.Parameters) > 0 {
+		fmt.Printf("Params:    %v\n", h.Parameters)
+	}
+	if h.ErrorMessage != "" {
+		fmt.Printf("Error:     %s\n", h.ErrorMessage)
+	}
+	for _, execution := range h.Executions {
+		fmt.Printf("\nExecution %d on %s: %s\n", execution.ID, execution.SerialNumber, execution.Status)
+		for _, order := range execution.Orders {
+			fmt.Printf("  [%d] %s template=%d %s", order.ExecutionOrder, order.OrderID, order.TemplateID, order.Status)
+			if order.ErrorMessage != "" {
+				fmt.Printf(" (%s)", order.ErrorMessage)
+			}
+			fmt.Println()
+			for _, step := range order.Steps {
+				fmt.Printf("      step %d %s %s %s\n", step.StepOrder, step.Status, step.Result, step.ErrorMessage)
+			}
+		}
+	}
+	fmt.Println("\nPLC responses:")
+	if len(h.Responses) == 0 {
+		fmt.Println("  (none recorded)")
+	}
+	for _, response := range h.Responses {
+		line := fmt.Sprintf("  %s %s:%s", response.SentAt.Format(time.RFC3339Nano), response.Command, response.SentStatus)
+		if response.Code != "" {
+			line += " code=" + response.Code
+		}
+		if response.ErrorMessage != "" {
+			line += " " + response.ErrorMessage
+		}
+		if response.PublishError != "" {
+			line += " (publish failed: " + response.PublishError + ")"
+		}
+		fmt.Println(line)
+	}
+}
+
+// sendPLCCommand 명령을 bridge/command로 발행하고 PLC 응답 토픽에서 결과 대기
+func sendPLCCommand(command string) error {
+	return sendPLCCommandWithParams(command, nil)
+}
+
+// sendPLCCommandWithParams 템플릿 파라미터를 포함하여 PLC 명령 전송
+func sendPLCCommandWithParams(command string, params map[string]string) error {
+	mqttClient, err := connectMQTT()
+	if err != nil {
+		return err
+	}
+	defer mqttClient.Disconnect(250)
+
+	codec, err := messaging.NewPLCCodec(cfg.PlcCodec)
+	if err != nil {
+		return err
+	}
+	statusMap, err := messaging.PLCStatusMapFromConfig(codec.Name(), cfg.PlcStatusMap, cfg.PlcStatusMapFile)
+	if err != nil {
+		return err
+	}
+	request := messaging.PLCRequest{Command: command, CorrelationID: idgen.CorrelationID(), Params: params}
+	payload, err := codec.EncodeCommand(request)
+	if err != nil {
+		return err
+	}
+
+	baseCommand := strings.SplitN(command, ":", 2)[0]
+	responses := make(chan messaging.PLCResponse, 1)
+	err = mqttClient.Subscribe(cfg.PlcResponseTopic, 1, func(_ mqtt.Client, msg mqtt.Message) {
+		response, err := codec.DecodeResponse(msg.Payload())
+		if err != nil || response.Command != baseCommand {
+			return
+		}
+		// 추적 ID를 전달하는 코덱이면 이 요청의 응답만 수신
+		if response.CorrelationID != "" && response.CorrelationID != request.CorrelationID {
+			return
+		}
+		select {
+		case responses <- response:
+		default:
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := mqttClient.Publish(constants.TopicBridgeCommand, 1, false, payload); err != nil {
+		return err
+	}
+
+	select {
+	case response := <-responses:
+		// 매핑된 상태 값이면 내부 상태 문자도 함께 표시 (예: CR:OK=S)
+		if internal := statusMap.Decode(response.Status); internal != response.Status {
+			response.Status += "=" + internal
+		}
+		if response.Code != "" {
+			fmt.Printf("%s:%s [%s] (%s)\n", response.Command, response.Status, response.Code, response.Error)
+		} else if response.Error != "" {
+			fmt.Printf("%s:%s (%s)\n", response.Command, response.Status, response.Error)
+		} else {
+			fmt.Printf("%s:%s\n", response.Command, response.Status)
+		}
+		return nil
+	case <-time.After(cfg.Timeout):
+		return apperr.New(apperr.CodeTransportUnavailable, "no response for %s within %v", command, cfg.Timeout).WithCorrelationID(request.CorrelationID)
+	}
+}
+
+// newMappingsCmd 명령-오더 매핑 조회 명령
+func newMappingsCmd() *cobra.Command {
+	mappingsCmd := &cobra.Command{Use: "mappings", Short: "명령-오더 매핑"}
+
+	mappingsCmd.AddCommand(&cobra.Command{
+		Use:   "list [commandType]",
+		Short: "명령별 오더 실행 체인 조회",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+
+			query := db.Preload("CommandDefinition").Preload("Template").
+				Joins("JOIN command_definitions ON command_definitions.id = command_order_mappings.command_definition_id").
+				Order("command_definitions.command_type ASC, command_order_mappings.execution_order ASC")
+			if len(args) == 1 {
+				query = query.Where("command_definitions.command_type = ?", args[0])
+			}
+
+			var mappings []models.CommandOrderMapping
+			if err := query.Find(&mappings).Error; err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "COMMAND\tORDER\tTEMPLATE\tON SUCCESS\tON FAILURE\tACTIVE")
+			for _, m := range mappings {
+				if m.Template.SiteID != "" && m.Template.SiteID != cfg.SiteID {
+					continue
+				}
+				fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%t\n",
+					m.CommandDefinition.CommandType, m.ExecutionOrder, m.Template.Name,
+					formatNextOrder(m.NextExecutionOrder), formatNextOrder(m.FailureOrder), m.IsActive)
+			}
+			return w.Flush()
+		},
+	})
+
+	return mappingsCmd
+}
+
+// newDirectActionsCmd PLC 직접 액션 레지스트리 관리 명령
+func newDirectActionsCmd() *cobra.Command {
+	directCmd := &cobra.Command{Use: "direct-actions", Short: "PLC 직접 액션(<명령>:<접미사>) 레지스트리 관리"}
+
+	directCmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "등록된 직접 액션 목록",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+
+			defs, err := repository.ListDirectActions(db)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "SUFFIX\tACTION TYPE\tPARAMETER\tARGUMENTS\tBLOCKING\tACTIVE")
+			for _, def := range defs {
+				arguments := make([]string, 0, len(def.Arguments))
+				for _, arg := range def.Arguments {
+					arguments = append(arguments, formatDirectActionArgument(arg))
+				}
+				argText := "-"
+				if len(arguments) > 0 {
+					argText = strings.Join(arguments, " ")
+				}
+				fmt.Fprintf(w, ":%s\t%s\t%s\t%s\t%s\t%t\n",
+					def.Suffix, def.ActionType, def.ParameterKey, argText, def.BlockingType, def.IsActive)
+			}
+			return w.Flush()
+		},
+	})
+
+	directCmd.AddCommand(&cobra.Command{
+		Use:   "apply <file>",
+		Short: "JSON 파일의 직접 액션 정의를 저장 (같은 접미사는 인자까지 교체)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			var items []json.RawMessage
+			if err := json.Unmarshal(data, &items); err != nil {
+				return fmt.Errorf("invalid direct action file: %w", err)
+			}
+
+			defs := make([]models.DirectActionDefinition, len(items))
+			for i, item := range items {
+				// is_active를 생략하면 활성으로 저장
+				defs[i].IsActive = true
+				if err := json.Unmarshal(item, &defs[i]); err != nil {
+					return fmt.Errorf("invalid direct action #%d: %w", i+1, err)
+				}
+			}
+
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+
+			for _, def := range defs {
+				saved, err := api.SaveDirectAction(cmd.Context(), def)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Saved direct action :%s -> %s (arguments: %d)\n", saved.Suffix, saved.ActionType, len(saved.Arguments))
+			}
+			return nil
+		},
+	})
+
+	directCmd.AddCommand(&cobra.Command{
+		Use:   "delete <suffix>",
+		Short: "직접 액션 정의 삭제",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			return api.DeleteDirectAction(cmd.Context(), strings.TrimPrefix(args[0], ":"))
+		},
+	})
+
+	return directCmd
+}
+
+// formatDirectActionArgument 직접 액션 인자 표시 (예: "1:arm=right[R=right,L=left]")
+func formatDirectActionArgument(arg models.DirectActionArgument) string {
+	text := fmt.Sprintf("%d:%s", arg.Position, arg.Key)
+	if arg.Required {
+		text += "*"
+	} else if arg.DefaultValue != "" {
+		text += "=" + arg.DefaultValue
+	}
+	if arg.ValueMap != "" {
+		text += "[" + arg.ValueMap + "]"
+	}
+	return text
+}
+
+// formatNextOrder 다음 오더 순번 표시 (0은 종료)
+func formatNextOrder(order int) string {
+	if order == 0 {
+		return "end"
+	}
+	return fmt.Sprintf("%d", order)
+}
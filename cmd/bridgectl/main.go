@@ -0,0 +1,130 @@
+// cmd/bridgectl/main.go
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mqtt-bridge/client"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/idgen"
+	"mqtt-bridge/internal/config"
+	"mqtt-bridge/internal/database"
+	"mqtt-bridge/internal/messaging"
+	"mqtt-bridge/internal/utils"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+var cfg *config.Config
+
+// errorFormat 오류 출력 형식 (text, json)
+var errorFormat string
+
+func main() {
+	root := &cobra.Command{
+		Use:           "bridgectl",
+		Short:         "MQTT Bridge 관리 도구",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			cfg, err = config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			// 템플릿 전개/재생 등에서 브릿지와 같은 형식으로 ID 생성
+			if err := idgen.Configure(cfg.IDStrategy, cfg.IDPrefixes); err != nil {
+				return err
+			}
+			// 관리 도구 출력이 로그에 묻히지 않도록 경고 이상만 출력
+			utils.SetupLogger("warn")
+			return nil
+		},
+	}
+
+	root.PersistentFlags().StringVar(&errorFormat, "error-format", "text", "오류 출력 형식 (text, json: {code, message, field, cid})")
+
+	root.AddCommand(
+		newRobotsCmd(),
+		newStateCmd(),
+		newPayloadsCmd(),
+		newCommandCmd(),
+		newOrdersCmd(),
+		newJobsCmd(),
+		newTemplatesCmd(),
+		newMappingsCmd(),
+		newMapsCmd(),
+		newWindowsCmd(),
+		newChargingCmd(),
+		newZonesCmd(),
+		newAlertsCmd(),
+		newDirectActionsCmd(),
+		newRetentionCmd(),
+		newPLCCmd(),
+		newSubscriptionsCmd(),
+		newFaultsCmd(),
+		newReadOnlyCmd(),
+		newGraphQLCmd(),
+		newTransportsCmd(),
+		newReplayCmd(),
+		newLogsCmd(),
+		newImportCmd(),
+		newSelfTestCmd(),
+		newDiagnosticsCmd(),
+	)
+
+	if err := root.Execute(); err != nil {
+		printError(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// printError 오류를 코드와 함께 출력 (--error-format json이면 apperr.Response JSON)
+func printError(w io.Writer, err error) {
+	if errorFormat == "json" {
+		data, _ := json.Marshal(apperr.ToResponse(err, ""))
+		fmt.Fprintln(w, string(data))
+		return
+	}
+	// apperr.Error와 관리 API 오류는 메시지에 이미 코드를 포함
+	var appErr *apperr.Error
+	var apiErr *client.Error
+	if errors.As(err, &appErr) || errors.As(err, &apiErr) {
+		fmt.Fprintf(w, "Error: %v\n", err)
+		return
+	}
+	fmt.Fprintf(w, "Error: [%s] %v\n", apperr.CodeOf(err), err)
+}
+
+// adminClient 브릿지 관리 API 클라이언트
+// 변경 작업은 DB를 직접 고치지 않고 이 API를 거쳐 읽기 전용 모드, Idempotency-Key, 관리자 IP 허용 목록을 적용받습니다.
+func adminClient() (*client.Client, error) {
+	if cfg.HealthAddr == "" {
+		return nil, apperr.New(apperr.CodeTransportUnavailable, "HEALTH_ADDR is not configured")
+	}
+	return client.New(cfg.HealthAddr), nil
+}
+
+// openDB 마이그레이션 없이 데이터베이스 연결
+func openDB() (*gorm.DB, error) {
+	return database.Open(cfg)
+}
+
+// openReadDB 목록/이력 조회용 연결 (DB_READ_DSN이 있으면 읽기 복제본 사용)
+func openReadDB() (*gorm.DB, error) {
+	if cfg.DBReadDSN == "" {
+		return openDB()
+	}
+	return database.OpenReader(cfg, nil)
+}
+
+// connectMQTT 브릿지 세션과 충돌하지 않도록 별도 클라이언트 ID로 MQTT 연결
+func connectMQTT() (*messaging.MQTTClient, error) {
+	ctlCfg := *cfg
+	ctlCfg.MQTTClientID = cfg.MQTTClientID + "_ctl_" + idgen.NewGenerator().ShortID()
+	return messaging.NewMQTTClient(&ctlCfg)
+}
@@ -0,0 +1,616 @@
+// cmd/bridgectl/orders.go
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mqtt-bridge/client"
+	"mqtt-bridge/internal/artifacts"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/workflow"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+// newAnnotateCmd 오더/명령에 운영자 라벨과 메모를 남기는 명령
+func newAnnotateCmd(use, short string,
+	annotate func(*client.Client, context.Context, string, client.AnnotationInput) (*client.Annotation, error)) *cobra.Command {
+	var input client.AnnotationInput
+	annotateCmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			annotation, err := annotate(api, cmd.Context(), args[0], input)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Annotation %d added to %s %s\n", annotation.ID, strings.ToLower(annotation.TargetType), args[0])
+			return nil
+		},
+	}
+	annotateCmd.Flags().StringSliceVarP(&input.Labels, "label", "l", nil, "라벨 (여러 번 또는 쉼표로 구분, 예: -l review -l operator-cancel)")
+	annotateCmd.Flags().StringVar(&input.Note, "note", "", "메모")
+	annotateCmd.Flags().StringVar(&input.Author, "author", os.Getenv("USER"), "작성자")
+	return annotateCmd
+}
+
+// printAnnotations 주석 목록 출력
+func printAnnotations(annotations []models.ExecutionAnnotation) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ANNOTATED\tON\tAUTHOR\tLABELS\tNOTE")
+	for _, a := range annotations {
+		author, labels := a.Author, a.Labels
+		if author == "" {
+			author = "-"
+		}
+		if labels == "" {
+			labels = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", a.CreatedAt.Format(time.RFC3339), strings.ToLower(a.TargetType), author, labels, a.Note)
+	}
+	w.Flush()
+}
+
+// newJobsCmd 여러 로봇/명령에 걸친 작업 묶음 명령
+func newJobsCmd() *cobra.Command {
+	jobsCmd := &cobra.Command{
+		Use:   "jobs",
+		Short: "작업 묶음 (여러 로봇/명령을 한 번에 승인하고 함께 추적, 묶음 단위 취소)",
+	}
+
+	var filter repository.JobFilter
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "작업 묶음 목록 (최근 순)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+			jobs, err := repository.ListJobs(db, cfg.SiteID, filter)
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "JOB\tNAME\tSTATUS\tTASKS\tCREATED\tCID")
+			for _, job := range jobs {
+				done := 0
+				for _, task := range job.Tasks {
+					if task.Status != constants.JobStatusPending && task.Status != constants.JobStatusRunning {
+						done++
+					}
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%d/%d\t%s\t%s\n", job.JobID, job.Name, job.Status, done, len(job.Tasks),
+					job.CreatedAt.Format(time.RFC3339), job.CorrelationID)
+			}
+			return w.Flush()
+		},
+	}
+	listCmd.Flags().StringVar(&filter.Status, "status", "", "작업 묶음 상태 (PENDING, RUNNING, COMPLETED, FAILED, CANCELLED)")
+	listCmd.Flags().StringVar(&filter.Robot, "robot", "", "이 로봇의 작업 항목이 있는 묶음만")
+	listCmd.Flags().IntVar(&filter.Limit, "limit", 50, "최대 건수")
+
+	showCmd := &cobra.Command{
+		Use:   "show <jobId>",
+		Short: "작업 묶음과 작업 항목 상태",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+			job, err := repository.GetJob(db, cfg.SiteID, args[0])
+			if err != nil {
+				return err
+			}
+			printJob(job)
+			return nil
+		},
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create <file.json>",
+		Short: "작업 묶음 생성 ({\"name\", \"tasks\": [{\"serial_number\", \"command_type\", \"parameters\"}]}, 하나라도 승인되지 않으면 모두 거부)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			var request repository.JobRequest
+			if err := json.Unmarshal(data, &request); err != nil {
+				return apperr.Validation("file", "invalid job file: %v", err)
+			}
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			job, err := api.CreateJob(cmd.Context(), request)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Created job %s with %d task(s) (cid=%s)\n", job.JobID, len(job.Tasks), job.CorrelationID)
+			return nil
+		},
+	}
+
+	var cancelReason string
+	cancelCmd := &cobra.Command{
+		Use:   "cancel <jobId>",
+		Short: "작업 묶음 전체 취소 (실행 중인 작업 항목은 로봇을 관리하는 브릿지가 오더를 취소)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			job, err := api.CancelJob(cmd.Context(), args[0], cancelReason)
+			if err != nil {
+				return err
+			}
+			printJob(job)
+			return nil
+		},
+	}
+	cancelCmd.Flags().StringVar(&cancelReason, "reason", "", "취소 사유")
+
+	jobsCmd.AddCommand(listCmd, showCmd, createCmd, cancelCmd)
+	return jobsCmd
+}
+
+// printJob 작업 묶음과 작업 항목 출력
+func printJob(job *models.Job) {
+	fmt.Printf("Job %s %s (cid=%s)\n", job.JobID, job.Name, job.CorrelationID)
+	fmt.Printf("Status:  %s\n", job.Status)
+	fmt.Printf("Created: %s\n", job.CreatedAt.Format(time.RFC3339))
+	if job.CancelReason != "" {
+		fmt.Printf("Reason:  %s\n", job.CancelReason)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\nSEQ\tROBOT\tCOMMAND\tSTATUS\tCOMMAND ID\tERROR")
+	for _, task := range job.Tasks {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%s\n", task.Sequence, task.SerialNumber, task.CommandType, task.Status,
+			task.CommandID, task.ErrorMessage)
+	}
+	w.Flush()
+}
+
+// newOrdersCmd 오더 제어 명령
+func newOrdersCmd() *cobra.Command {
+	ordersCmd := &cobra.Command{Use: "orders", Short: "오더 제어"}
+
+	var cancelReason string
+	cancelCmd := &cobra.Command{
+		Use:   "cancel [orderId]",
+		Short: "실행 중인 오더 취소 (OC 명령 전송, orderId를 지정하면 HEALTH_ADDR의 /admin/orders/<orderId>/cancel로 해당 오더만 취소)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return sendPLCCommand(constants.CommandOrderCancel)
+			}
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			if err := api.CancelOrder(cmd.Context(), args[0], cancelReason); err != nil {
+				return err
+			}
+			fmt.Printf("Order %s cancelled\n", args[0])
+			return nil
+		},
+	}
+	cancelCmd.Flags().StringVar(&cancelReason, "reason", "", "취소 사유 (orderId를 지정한 경우)")
+	ordersCmd.AddCommand(cancelCmd)
+
+	var filter repository.OrderExecutionFilter
+	var since time.Duration
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "오더 실행 이력 조회 (최신순)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+
+			if since > 0 {
+				filter.Since = time.Now().Add(-since)
+			}
+			executions, err := repository.ListOrderExecutions(db, cfg.SiteID, filter)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ORDER ID\tROBOT\tSTATUS\tTEMPLATE\tTRANSPORT\tSTARTED\tDURATION\tCID")
+			for _, e := range executions {
+				duration := "-"
+				if e.CompletedAt != nil {
+					duration = e.CompletedAt.Sub(e.StartedAt).Round(time.Millisecond).String()
+				}
+				transport := e.Transport
+				if transport == "" {
+					transport = "-"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\n",
+					e.OrderID, e.SerialNumber, e.Status, e.TemplateID, transport,
+					e.StartedAt.Format(time.RFC3339), duration, e.CorrelationID)
+			}
+			return w.Flush()
+		},
+	}
+	listCmd.Flags().StringVar(&filter.SerialNumber, "robot", "", "로봇 시리얼 번호")
+	listCmd.Flags().StringVar(&filter.Status, "status", "", "실행 상태 (RUNNING, COMPLETED, FAILED)")
+	listCmd.Flags().DurationVar(&since, "since", 24*time.Hour, "조회 기간 (0이면 전체)")
+	listCmd.Flags().IntVar(&filter.Limit, "limit", 50, "최대 건수")
+	listCmd.Flags().StringVar(&filter.Label, "label", "", "이 라벨의 주석이 있는 오더만 (명령 주석 포함)")
+	listCmd.Flags().StringVar(&filter.Note, "note", "", "메모에 이 문자열이 들어 있는 주석이 있는 오더만")
+	ordersCmd.AddCommand(listCmd)
+	ordersCmd.AddCommand(newAnnotateCmd("annotate <orderId>", "오더 실행에 라벨/메모 추가 (취소 사유, 검토 대상 표시 등)",
+		(*client.Client).AnnotateOrder))
+
+	ordersCmd.AddCommand(&cobra.Command{
+		Use:   "show <orderId>",
+		Short: "오더 실행 상세 (노드 진행 상황과 단계)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+			var execution models.OrderExecution
+			err = db.Scopes(repository.SiteScope(cfg.SiteID)).
+				Preload("Steps", func(db *gorm.DB) *gorm.DB { return db.Order("step_order ASC, id ASC") }).
+				Where("order_id = ?", args[0]).
+				First(&execution).Error
+			if err == gorm.ErrRecordNotFound {
+				return apperr.New(apperr.CodeNotFound, "order %s not found", args[0]).WithField("orderId")
+			}
+			if err != nil {
+				return err
+			}
+
+			currentNode := execution.LastNodeID
+			if currentNode == "" {
+				currentNode = "-"
+			}
+			remaining := repository.RemainingNodeIDs(&execution)
+			fmt.Printf("Order:        %s\n", execution.OrderID)
+			fmt.Printf("Robot:        %s\n", execution.SerialNumber)
+			fmt.Printf("Status:       %s\n", execution.Status)
+			if execution.ErrorMessage != "" {
+				fmt.Printf("Error:        %s\n", execution.ErrorMessage)
+			}
+			if execution.Status == constants.OrderExecutionStatusWaiting && execution.QueuePosition > 0 {
+				fmt.Printf("Queue:        position %d (template concurrency limit)\n", execution.QueuePosition)
+			}
+			fmt.Printf("Template:     %d\n", execution.TemplateID)
+			if execution.EstimatedCompletionAt != nil && execution.Status == constants.OrderExecutionStatusRunning {
+				fmt.Printf("ETA:          %s (in %s)\n", execution.EstimatedCompletionAt.Format(time.RFC3339),
+					time.Until(*execution.EstimatedCompletionAt).Round(time.Second))
+			}
+			fmt.Printf("Current step: %d\n", execution.CurrentStep)
+			fmt.Printf("Current node: %s (seq %d)\n", currentNode, execution.LastNodeSequenceID)
+			fmt.Printf("Remaining:    %d %v\n", len(remaining), remaining)
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "\nSTEP\tSTATUS\tRESULT\tGROUP\tSTARTED\tERROR")
+			for _, step := range execution.Steps {
+				group := step.ParallelGroup
+				if group == "" {
+					group = "-"
+				}
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n",
+					step.StepOrder, step.Status, step.Result, group, step.StartedAt.Format(time.RFC3339), step.ErrorMessage)
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+
+			annotations, err := repository.ListOrderAnnotations(db, cfg.SiteID, execution.OrderID)
+			if err != nil {
+				return err
+			}
+			if len(annotations) > 0 {
+				fmt.Println()
+				printAnnotations(annotations)
+			}
+			return nil
+		},
+	})
+	var waitTimeout time.Duration
+	var waitStatus string
+	var untilFinal bool
+	waitCmd := &cobra.Command{
+		Use:   "wait <orderId>",
+		Short: "오더 상태가 바뀔 때까지 대기 (HEALTH_ADDR의 /admin/orders/<orderId>/wait 롱 폴링)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.HealthAddr == "" {
+				return apperr.New(apperr.CodeTransportUnavailable, "HEALTH_ADDR is not configured")
+			}
+			addr := cfg.HealthAddr
+			if strings.HasPrefix(addr, ":") {
+				addr = "localhost" + addr
+			}
+			// 서버가 timeout까지 응답을 잡고 있으므로 클라이언트 제한 시간은 조금 더 길게
+			httpClient := &http.Client{Timeout: waitTimeout + 5*time.Second}
+
+			status := waitStatus
+			for {
+				query := url.Values{"timeout": {waitTimeout.String()}}
+				if status != "" {
+					query.Set("status", status)
+				}
+				resp, err := httpClient.Get(fmt.Sprintf("http://%s/admin/orders/%s/wait?%s", addr, args[0], query.Encode()))
+				if err != nil {
+					return apperr.Wrap(apperr.CodeTransportUnavailable, err, "failed to reach bridge health server")
+				}
+				var result workflow.OrderWaitResult
+				if resp.StatusCode != http.StatusOK {
+					var failure apperr.Response
+					err := json.NewDecoder(resp.Body).Decode(&failure)
+					resp.Body.Close()
+					if err != nil || failure.Message == "" {
+						return fmt.Errorf("unexpected status from health server: %s", resp.Status)
+					}
+					return apperr.New(failure.Code, "%s", failure.Message)
+				}
+				err = json.NewDecoder(resp.Body).Decode(&result)
+				resp.Body.Close()
+				if err != nil {
+					return err
+				}
+
+				switch {
+				case result.Changed:
+					fmt.Printf("%s %s -> %s (step %d)\n", time.Now().Format(time.RFC3339), result.PreviousStatus, result.Status, result.CurrentStep)
+				case result.TimedOut:
+					fmt.Printf("%s still %s after %s\n", time.Now().Format(time.RFC3339), result.Status, waitTimeout)
+				default:
+					fmt.Printf("%s %s\n", time.Now().Format(time.RFC3339), result.Status)
+				}
+				if result.Final {
+					if result.Status != constants.OrderExecutionStatusCompleted {
+						return fmt.Errorf("order %s finished as %s", args[0], result.Status)
+					}
+					return nil
+				}
+				if !untilFinal {
+					if result.TimedOut {
+						return fmt.Errorf("order %s did not change within %s", args[0], waitTimeout)
+					}
+					return nil
+				}
+				status = result.Status
+			}
+		},
+	}
+	waitCmd.Flags().DurationVar(&waitTimeout, "timeout", 30*time.Second, "요청마다 서버에서 기다리는 최대 시간 (최대 5m)")
+	waitCmd.Flags().StringVar(&waitStatus, "status", "", "이 상태와 달라질 때까지 대기 (기본: 현재 상태)")
+	waitCmd.Flags().BoolVar(&untilFinal, "until-final", false, "종료 상태(COMPLETED, FAILED, E_STOPPED, CANCELLED, REJECTED, EXPIRED)가 될 때까지 반복 대기")
+	ordersCmd.AddCommand(waitCmd)
+	ordersCmd.AddCommand(newOrderPauseCmd())
+	ordersCmd.AddCommand(&cobra.Command{
+		Use:   "resume <orderId>",
+		Short: "일시정지한 오더 재개 (stopPause 전송, HEALTH_ADDR의 /admin/orders/<orderId>/resume)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return postOrderPause(args[0], "resume", nil)
+		},
+	})
+	ordersCmd.AddCommand(newArtifactsCmd())
+
+	return ordersCmd
+}
+
+// newOrderPauseCmd 실행 중인 오더 일시정지 명령
+func newOrderPauseCmd() *cobra.Command {
+	var reason string
+	pauseCmd := &cobra.Command{
+		Use:   "pause <orderId>",
+		Short: "실행 중인 오더 일시정지 (startPause 전송, HEALTH_ADDR의 /admin/orders/<orderId>/pause)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return postOrderPause(args[0], "pause", map[string]string{"reason": reason})
+		},
+	}
+	pauseCmd.Flags().StringVar(&reason, "reason", "", "일시정지 사유 (PLC 응답과 로그에 기록)")
+	return pauseCmd
+}
+
+// postOrderPause 헬스 서버의 오더 일시정지/재개 엔드포인트 호출
+func postOrderPause(orderID, route string, body interface{}) error {
+	if cfg.HealthAddr == "" {
+		return apperr.New(apperr.CodeTransportUnavailable, "HEALTH_ADDR is not configured")
+	}
+	addr := cfg.HealthAddr
+	if strings.HasPrefix(addr, ":") {
+		addr = "localhost" + addr
+	}
+	payload, _ := json.Marshal(body)
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+	resp, err := httpClient.Post(fmt.Sprintf("http://%s/admin/orders/%s/%s", addr, url.PathEscape(orderID), route),
+		"application/json", bytes.NewReader(payload))
+	if err != nil {
+		return apperr.Wrap(apperr.CodeTransportUnavailable, err, "failed to reach bridge health server")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var failure apperr.Response
+		if err := json.NewDecoder(resp.Body).Decode(&failure); err != nil || failure.Message == "" {
+			return fmt.Errorf("unexpected status from health server: %s", resp.Status)
+		}
+		return apperr.New(failure.Code, "%s", failure.Message)
+	}
+	var execution models.OrderExecution
+	if err := json.NewDecoder(resp.Body).Decode(&execution); err != nil {
+		return err
+	}
+	fmt.Printf("Order %s is now %s (step %d)\n", execution.OrderID, execution.Status, execution.CurrentStep)
+	return nil
+}
+
+// newArtifactsCmd 오더 결과물(추론 출력, 이미지, 로그) 관리 명령
+func newArtifactsCmd() *cobra.Command {
+	artifactsCmd := &cobra.Command{Use: "artifacts", Short: "오더 결과물 조회/업로드/다운로드"}
+
+	artifactsCmd.AddCommand(&cobra.Command{
+		Use:   "list <orderId>",
+		Short: "오더의 결과물 목록",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			service, err := openArtifactService(openReadDB)
+			if err != nil {
+				return err
+			}
+			list, err := service.List(args[0])
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tKIND\tCONTENT TYPE\tSIZE\tSHA256\tCREATED")
+			for _, a := range list {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%.12s\t%s\n",
+					a.Name, a.Kind, a.ContentType, a.Size, a.SHA256, a.CreatedAt.Format(time.RFC3339))
+			}
+			return w.Flush()
+		},
+	})
+
+	var kind, name, contentType string
+	uploadCmd := &cobra.Command{
+		Use:   "upload <orderId> <file|->",
+		Short: "오더에 결과물 첨부 (같은 이름이면 교체)",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var data []byte
+			var err error
+			if args[1] == "-" {
+				data, err = io.ReadAll(os.Stdin)
+			} else {
+				data, err = os.ReadFile(args[1])
+			}
+			if err != nil {
+				return err
+			}
+			if name == "" {
+				if args[1] == "-" {
+					return fmt.Errorf("--name is required when reading from stdin")
+				}
+				name = filepath.Base(args[1])
+			}
+
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			artifact, err := api.UploadArtifact(cmd.Context(), args[0], name, strings.ToUpper(kind), contentType, data)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Attached %s to order %s (%d bytes, sha256 %s)\n", artifact.Name, artifact.OrderID, artifact.Size, artifact.SHA256)
+			return nil
+		},
+	}
+	uploadCmd.Flags().StringVar(&kind, "kind", constants.ArtifactKindOther, "결과물 종류 (INFERENCE_OUTPUT, IMAGE, LOG_REPORT, OTHER)")
+	uploadCmd.Flags().StringVar(&name, "name", "", "결과물 이름 (기본값: 파일 이름)")
+	uploadCmd.Flags().StringVar(&contentType, "content-type", "", "Content-Type (기본값: 내용으로 추정)")
+	artifactsCmd.AddCommand(uploadCmd)
+
+	var output string
+	var urlOnly bool
+	downloadCmd := &cobra.Command{
+		Use:   "download <orderId> <name>",
+		Short: "결과물 내려받기 (--url이면 pre-signed URL 출력)",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			service, err := openArtifactService(openReadDB)
+			if err != nil {
+				return err
+			}
+
+			if urlOnly {
+				url, err := service.URL(args[0], args[1])
+				if err != nil {
+					return err
+				}
+				if url == "" {
+					return fmt.Errorf("artifact store %s does not support pre-signed URLs", cfg.ArtifactStore)
+				}
+				fmt.Println(url)
+				return nil
+			}
+
+			_, body, err := service.Open(cmd.Context(), args[0], args[1])
+			if err != nil {
+				return err
+			}
+			defer body.Close()
+
+			out := os.Stdout
+			if output != "" && output != "-" {
+				file, err := os.Create(output)
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+				out = file
+			}
+			_, err = io.Copy(out, body)
+			return err
+		},
+	}
+	downloadCmd.Flags().StringVarP(&output, "output", "o", "", "저장할 파일 (기본값: stdout)")
+	downloadCmd.Flags().BoolVar(&urlOnly, "url", false, "내려받지 않고 pre-signed URL만 출력")
+	artifactsCmd.AddCommand(downloadCmd)
+
+	artifactsCmd.AddCommand(&cobra.Command{
+		Use:   "delete <orderId> <name>",
+		Short: "결과물 삭제",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			return api.DeleteArtifact(cmd.Context(), args[0], args[1])
+		},
+	})
+
+	return artifactsCmd
+}
+
+// openArtifactService 결과물 저장소와 DB로 결과물 서비스 생성
+func openArtifactService(open func() (*gorm.DB, error)) (*artifacts.Service, error) {
+	db, err := open()
+	if err != nil {
+		return nil, err
+	}
+	store, err := artifacts.NewStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return artifacts.NewService(db, store, cfg.SiteID, cfg.ArtifactURLExpiry), nil
+}
@@ -0,0 +1,1192 @@
+// cmd/bridgectl/robots.go
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mqtt-bridge/client"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/health"
+	"mqtt-bridge/internal/messaging"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/redis"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/robot"
+	"mqtt-bridge/internal/workflow"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/spf13/cobra"
+)
+
+// newRobotsCmd 로봇 상태 조회 명령
+func newRobotsCmd() *cobra.Command {
+	robotsCmd := &cobra.Command{Use: "robots", Short: "로봇 관리"}
+
+	var listSelector string
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "사이트의 로봇 연결 상태 목록",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			selector, err := repository.ParseMetadataSelector(listSelector)
+			if err != nil {
+				return err
+			}
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+
+			serials, err := repository.SelectRobots(db, cfg.SiteID, selector)
+			if err != nil {
+				return err
+			}
+			var robots []models.RobotStatus
+			if err := db.Scopes(repository.SiteScope(cfg.SiteID)).
+				Where("serial_number IN ?", serials).
+				Order("serial_number ASC").
+				Find(&robots).Error; err != nil {
+				return err
+			}
+			metadata, err := repository.ListRobotMetadata(db, cfg.SiteID)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "SERIAL\tMANUFACTURER\tSTATE\tVERSION\tLAST SEEN\tMAINTENANCE\tMETADATA")
+			for _, r := range robots {
+				version := r.Version
+				if version == "" {
+					version = "-"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					r.SerialNumber, r.Manufacturer, r.ConnectionState, version, r.LastTimestamp.Format(time.RFC3339),
+					formatMaintenance(r), formatMetadata(metadata[r.SerialNumber]))
+			}
+			return w.Flush()
+		},
+	}
+	listCmd.Flags().StringVar(&listSelector, "selector", "", "메타데이터 셀렉터 (예: location=line-1,team!=qa,!retired)")
+	robotsCmd.AddCommand(listCmd)
+
+	var historySince time.Duration
+	var historyLimit int
+	historyCmd := &cobra.Command{
+		Use:   "history <serialNumber>",
+		Short: "로봇 연결 상태 변경 이력 (최신순)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+
+			transitions, err := repository.ListConnectionHistory(db, cfg.SiteID, args[0], time.Now().Add(-historySince), historyLimit)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "OBSERVED\tFROM\tTO")
+			for _, t := range transitions {
+				from := t.FromState
+				if from == "" {
+					from = "-"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", t.ObservedAt.Format(time.RFC3339), from, t.ToState)
+			}
+			return w.Flush()
+		},
+	}
+	historyCmd.Flags().DurationVar(&historySince, "since", 24*time.Hour, "조회 기간")
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 100, "최대 건수")
+	robotsCmd.AddCommand(historyCmd)
+
+	var statsWindow time.Duration
+	var statsFormat string
+	statsCmd := &cobra.Command{
+		Use:   "stats <serialNumber>",
+		Short: "로봇 SLA 지표 (오더 성공률, 평균 수행 시간, MTBF, 가동률, 액션별 단계 실패)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+
+			to := time.Now()
+			stats, err := repository.ComputeRobotStats(db, cfg.SiteID, args[0], to.Add(-statsWindow), to)
+			if err != nil {
+				return err
+			}
+
+			switch statsFormat {
+			case "json":
+				data, err := json.MarshalIndent(stats, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(data))
+				return nil
+			case "prometheus":
+				stats.WritePrometheus(os.Stdout, cfg.SiteID)
+				return nil
+			default:
+				return fmt.Errorf("unsupported format: %s (json, prometheus)", statsFormat)
+			}
+		},
+	}
+	statsCmd.Flags().DurationVar(&statsWindow, "window", 24*time.Hour, "집계 기간")
+	statsCmd.Flags().StringVar(&statsFormat, "format", "json", "출력 형식 (json, prometheus)")
+	robotsCmd.AddCommand(statsCmd)
+
+	var maintenanceReason string
+	var maintenanceFor time.Duration
+	var maintenanceSelector string
+	maintenanceCmd := &cobra.Command{
+		Use:   "maintenance [serialNumber] <on|off>",
+		Short: "로봇 유지보수 모드 설정/해제 (설정 중에는 새 명령/오더 배차 거부, 상태 조회는 유지)",
+		Long: "로봇 유지보수 모드를 설정/해제합니다.\n" +
+			"시리얼 번호 대신 --selector를 주면 메타데이터가 일치하는 모든 로봇에 적용합니다.",
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (maintenanceSelector == "") != (len(args) == 2) {
+				return apperr.Validation("selector", "give either a serial number or --selector")
+			}
+			mode := args[len(args)-1]
+			if mode != "on" && mode != "off" {
+				return apperr.Validation("mode", "unsupported maintenance mode: %s (on, off)", mode)
+			}
+			if mode == "on" && maintenanceReason == "" {
+				return apperr.Validation("reason", "--reason is required")
+			}
+			selector, err := repository.ParseMetadataSelector(maintenanceSelector)
+			if err != nil {
+				return err
+			}
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+
+			serials := args[:1]
+			if !selector.Empty() {
+				db, err := openReadDB()
+				if err != nil {
+					return err
+				}
+				if serials, err = repository.SelectRobots(db, cfg.SiteID, selector); err != nil {
+					return err
+				}
+				if len(serials) == 0 {
+					return apperr.New(apperr.CodeNotFound, "no robot matches selector %q", selector).WithField("selector")
+				}
+			}
+			var failed int
+			for _, serial := range serials {
+				var status *client.RobotMaintenanceStatus
+				if mode == "off" {
+					status, err = api.ClearRobotMaintenance(cmd.Context(), serial)
+				} else {
+					status, err = api.SetRobotMaintenance(cmd.Context(), serial, maintenanceReason, maintenanceFor)
+				}
+				switch {
+				case err != nil && len(serials) == 1:
+					return err
+				case err != nil:
+					failed++
+					fmt.Printf("Robot %s: %v\n", serial, err)
+				case mode == "off":
+					fmt.Printf("Robot %s maintenance cleared\n", serial)
+				case status.Until != nil:
+					fmt.Printf("Robot %s in maintenance until %s\n", serial, status.Until.Format(time.RFC3339))
+				default:
+					fmt.Printf("Robot %s in maintenance until cleared\n", serial)
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d robot(s) failed", failed, len(serials))
+			}
+			return nil
+		},
+	}
+	maintenanceCmd.Flags().StringVar(&maintenanceReason, "reason", "", "유지보수 사유 (on일 때 필수)")
+	maintenanceCmd.Flags().DurationVar(&maintenanceFor, "for", 0, "자동 해제까지의 시간 (0이면 수동 해제까지 유지)")
+	maintenanceCmd.Flags().StringVar(&maintenanceSelector, "selector", "", "메타데이터 셀렉터와 일치하는 로봇에 일괄 적용 (예: location=line-1)")
+	robotsCmd.AddCommand(maintenanceCmd)
+	robotsCmd.AddCommand(newRobotMetadataCmd())
+	robotsCmd.AddCommand(newRobotDiscoveryCmds()...)
+	robotsCmd.AddCommand(newRobotDefaultsCmd())
+	robotsCmd.AddCommand(newInitPositionCmd())
+	robotsCmd.AddCommand(newDualArmTrajectoryCmd())
+	robotsCmd.AddCommand(newRobotHealthCmd())
+	robotsCmd.AddCommand(newRobotPreflightCmd())
+	robotsCmd.AddCommand(newRobotTokensCmd())
+
+	robotsCmd.AddCommand(&cobra.Command{
+		Use:   "actions <serialNumber> [actionType]",
+		Short: "팩트시트가 보고한 액션 목록, actionType을 주면 파라미터 스키마 (JSON)",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+			factsheets := robot.NewFactsheetManager(db)
+
+			if len(args) == 2 {
+				schema, err := factsheets.ActionSchema(args[0], args[1])
+				if err != nil {
+					return err
+				}
+				data, err := json.MarshalIndent(schema, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			schemas, err := factsheets.ActionSchemas(args[0])
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ACTION TYPE\tSCOPES\tPARAMETERS")
+			for _, schema := range schemas {
+				params := make([]string, 0, len(schema.Parameters))
+				for _, p := range schema.Parameters {
+					param := p.Key + ":" + p.Type
+					if !p.Required {
+						param += "?"
+					}
+					params = append(params, param)
+				}
+				paramText := "-"
+				if len(params) > 0 {
+					paramText = strings.Join(params, " ")
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", schema.ActionType, strings.Join(schema.Scopes, ","), paramText)
+			}
+			return w.Flush()
+		},
+	})
+
+	robotsCmd.AddCommand(&cobra.Command{
+		Use:   "compatibility <serialNumber>",
+		Short: "로봇이 보고한 VDA 버전 기준 기능별 지원 여부 (VDA_FEATURE_VERSIONS)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+			matrix, err := robot.ParseCompatibilityMatrix(cfg.VdaFeatureVersions)
+			if err != nil {
+				return err
+			}
+
+			var status models.RobotStatus
+			if err := db.Scopes(repository.SiteScope(cfg.SiteID)).
+				Where("serial_number = ?", args[0]).
+				First(&status).Error; err != nil {
+				return apperr.Wrap(apperr.CodeNotFound, err, "robot %s not found", args[0]).WithField("serialNumber")
+			}
+
+			version := status.Version
+			if version == "" {
+				version = "unknown"
+			}
+			fmt.Printf("Robot %s reports VDA version %s (mode: %s)\n", status.SerialNumber, version, cfg.VdaCompatibilityMode)
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "FEATURE\tSUPPORTED VERSIONS\tSUPPORTED")
+			for _, feature := range matrix.Features() {
+				versionRange := matrix[feature]
+				supported := "yes"
+				if status.Version == "" {
+					supported = "unknown"
+				} else if !versionRange.Contains(status.Version) {
+					supported = "no"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", feature, versionRange, supported)
+			}
+			return w.Flush()
+		},
+	})
+
+	var estopReason string
+	estopCmd := &cobra.Command{
+		Use:   "estop <serialNumber>",
+		Short: "로봇 비상 정지 (HEALTH_ADDR의 /admin/robots/<serial>/estop: 안전 액션 전송 후 실행 중인 오더를 E_STOPPED 처리)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			result, err := api.EmergencyStop(cmd.Context(), args[0], estopReason)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Robot %s emergency stopped (%d order(s) stopped)\n", result.SerialNumber, result.StoppedOrders)
+			return nil
+		},
+	}
+	estopCmd.Flags().StringVar(&estopReason, "reason", "", "비상 정지 사유 (기본: 서버 기본 사유)")
+	robotsCmd.AddCommand(estopCmd)
+
+	robotsCmd.AddCommand(&cobra.Command{
+		Use:   "instant-action <serialNumber> <file|->",
+		Short: "원시 VDA 5050 instantActions 메시지를 검증 후 로봇에 전송",
+		Long: "VDA 5050 instantActions 스키마와 INSTANT_ACTION_ALLOWLIST에 허용된 actionType만 전송합니다.\n" +
+			"파일 대신 -를 지정하면 stdin에서 읽습니다.",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			serialNumber := args[0]
+
+			var payload []byte
+			var err error
+			if args[1] == "-" {
+				payload, err = io.ReadAll(os.Stdin)
+			} else {
+				payload, err = os.ReadFile(args[1])
+			}
+			if err != nil {
+				return err
+			}
+
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			message, err := api.SendInstantActions(cmd.Context(), serialNumber, payload)
+			if err != nil {
+				return err
+			}
+			topic := constants.GetMeiliInstantActionsTopic(message.Manufacturer, serialNumber)
+			fmt.Printf("Sent %d instant action(s) to %s\n", len(message.Actions), topic)
+			return nil
+		},
+	})
+
+	return robotsCmd
+}
+
+// newRobotDiscoveryCmds 로봇 탐색으로 등록된 로봇의 승인 대기 목록과 승인/거부 명령
+func newRobotDiscoveryCmds() []*cobra.Command {
+	var all bool
+	pendingCmd := &cobra.Command{
+		Use:   "pending",
+		Short: "탐색으로 발견되어 승인을 기다리는 로봇 목록 (--all이면 승인/거부된 로봇 포함)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+			status := constants.RobotRegistrationPending
+			if all {
+				status = ""
+			}
+			registrations, err := repository.ListRobotRegistrations(db, cfg.SiteID, status)
+			if err != nil {
+				return err
+			}
+			if !cfg.RobotDiscovery {
+				fmt.Fprintln(os.Stderr, "Note: ROBOT_DISCOVERY is off for this configuration, approval is not enforced")
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "SERIAL\tMANUFACTURER\tGROUP\tSTATUS\tFIRST SEEN\tLAST SEEN\tLAST TOPIC\tNOTE")
+			for _, r := range registrations {
+				note := r.Note
+				if note == "" {
+					note = "-"
+				}
+				group := r.RobotGroup
+				if group == "" {
+					group = "-"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.SerialNumber, r.Manufacturer, group, r.Status,
+					r.FirstSeenAt.Format(time.RFC3339), r.LastSeenAt.Format(time.RFC3339), r.LastTopic, note)
+			}
+			return w.Flush()
+		},
+	}
+	pendingCmd.Flags().BoolVar(&all, "all", false, "승인/거부된 로봇도 표시")
+
+	var note string
+	decide := func(use, short string, fn func(*client.Client, context.Context, string, string) (*client.RobotRegistration, error)) *cobra.Command {
+		return &cobra.Command{
+			Use:   use + " <serialNumber>",
+			Short: short,
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				api, err := adminClient()
+				if err != nil {
+					return err
+				}
+				registration, err := fn(api, cmd.Context(), args[0], note)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Robot %s is now %s\n", registration.SerialNumber, registration.Status)
+				return nil
+			},
+		}
+	}
+	approveCmd := decide("approve", "발견된 로봇을 승인하여 오더를 받을 수 있게 함", (*client.Client).ApproveRobot)
+	rejectCmd := decide("reject", "발견된 로봇을 거부 (승인한 로봇의 배차를 다시 막을 때도 사용)", (*client.Client).RejectRobot)
+	approveCmd.Flags().StringVar(&note, "note", "", "승인 사유")
+	rejectCmd.Flags().StringVar(&note, "note", "", "거부 사유")
+	return []*cobra.Command{pendingCmd, approveCmd, rejectCmd}
+}
+
+// formatMetadata 로봇 목록의 메타데이터 표시 (키 순 key=value)
+func formatMetadata(metadata map[string]string) string {
+	if len(metadata) == 0 {
+		return "-"
+	}
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = key + "=" + metadata[key]
+	}
+	return strings.Join(pairs, ",")
+}
+
+// newRobotMetadataCmd 로봇 메타데이터 조회/수정 명령
+func newRobotMetadataCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "metadata <serialNumber> [key=value | key-]...",
+		Short: "로봇 메타데이터 조회, key=value로 추가/갱신하고 key-로 삭제",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			patch := make(map[string]*string, len(args)-1)
+			for _, arg := range args[1:] {
+				if key, value, ok := strings.Cut(arg, "="); ok {
+					patch[key] = &value
+				} else if key, ok := strings.CutSuffix(arg, "-"); ok {
+					patch[key] = nil
+				} else {
+					return apperr.Validation("metadata", "invalid argument %q (key=value or key-)", arg)
+				}
+			}
+
+			var metadata map[string]string
+			if len(patch) == 0 {
+				db, err := openReadDB()
+				if err != nil {
+					return err
+				}
+				if metadata, err = repository.GetRobotMetadata(db, cfg.SiteID, args[0]); err != nil {
+					return err
+				}
+			} else {
+				api, err := adminClient()
+				if err != nil {
+					return err
+				}
+				if metadata, err = api.PatchRobotMetadata(cmd.Context(), args[0], patch); err != nil {
+					return err
+				}
+			}
+
+			keys := make([]string, 0, len(metadata))
+			for key := range metadata {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "KEY\tVALUE")
+			for _, key := range keys {
+				fmt.Fprintf(w, "%s\t%s\n", key, metadata[key])
+			}
+			return w.Flush()
+		},
+	}
+}
+
+// formatMaintenance 로봇 목록의 유지보수 표시 (만료된 유지보수는 표시하지 않음)
+func formatMaintenance(r models.RobotStatus) string {
+	if !r.Maintenance || (r.MaintenanceUntil != nil && time.Now().After(*r.MaintenanceUntil)) {
+		return "-"
+	}
+	if r.MaintenanceUntil != nil {
+		return fmt.Sprintf("%s (until %s)", r.MaintenanceReason, r.MaintenanceUntil.Format(time.RFC3339))
+	}
+	return r.MaintenanceReason
+}
+
+// newRobotDefaultsCmd 로봇별 기본 노드 위치 관리 명령
+func newRobotDefaultsCmd() *cobra.Command {
+	defaultsCmd := &cobra.Command{Use: "defaults", Short: "로봇별 기본 노드 위치 (홈 위치, 기본 맵, 허용 편차)"}
+
+	defaultsCmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "로봇별 기본 위치 목록",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+			list, err := repository.ListRobotDefaults(db, cfg.SiteID)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "SERIAL\tMAP\tHOME (X, Y, THETA)\tDEVIATION (XY, THETA)\tUPDATED")
+			for _, d := range list {
+				mapID := d.MapID
+				if mapID == "" {
+					mapID = "-"
+				}
+				fmt.Fprintf(w, "%s\t%s\t(%.2f, %.2f, %.2f)\t(%.2f, %.2f)\t%s\n",
+					d.SerialNumber, mapID, d.X, d.Y, d.Theta, d.AllowedDeviationXY, d.AllowedDeviationTheta,
+					d.UpdatedAt.Format(time.RFC3339))
+			}
+			return w.Flush()
+		},
+	})
+
+	var defaults models.RobotDefaults
+	setCmd := &cobra.Command{
+		Use:   "set <serialNumber>",
+		Short: "로봇 기본 위치 저장 (이미 있으면 전체 교체)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			if _, err := api.SaveRobotDefaults(cmd.Context(), args[0], defaults); err != nil {
+				return err
+			}
+			fmt.Printf("Saved defaults for robot %s\n", args[0])
+			return nil
+		},
+	}
+	setCmd.Flags().Float64Var(&defaults.X, "x", 0, "홈 위치 X")
+	setCmd.Flags().Float64Var(&defaults.Y, "y", 0, "홈 위치 Y")
+	setCmd.Flags().Float64Var(&defaults.Theta, "theta", 0, "홈 방향 (rad)")
+	setCmd.Flags().StringVar(&defaults.MapID, "map", "", "기본 맵 ID")
+	setCmd.Flags().Float64Var(&defaults.AllowedDeviationXY, "deviation-xy", 0, "허용 위치 편차 (m)")
+	setCmd.Flags().Float64Var(&defaults.AllowedDeviationTheta, "deviation-theta", 0, "허용 방향 편차 (rad)")
+	defaultsCmd.AddCommand(setCmd)
+
+	defaultsCmd.AddCommand(&cobra.Command{
+		Use:   "delete <serialNumber>",
+		Short: "로봇 기본 위치 삭제 (이후 노드 위치는 0)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			if err := api.DeleteRobotDefaults(cmd.Context(), args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("Deleted defaults for robot %s\n", args[0])
+			return nil
+		},
+	})
+
+	return defaultsCmd
+}
+
+// newInitPositionCmd 실행 중인 브릿지를 통해 로봇 위치 초기화(initPosition) 요청
+func newInitPositionCmd() *cobra.Command {
+	var pose models.PoseValue
+	var useHome bool
+	var wait time.Duration
+	initCmd := &cobra.Command{
+		Use:   "init-position <serialNumber>",
+		Short: "로봇 위치 초기화 (initPosition instantAction, HEALTH_ADDR의 /admin/robots/<serial>/init-position)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.HealthAddr == "" {
+				return apperr.New(apperr.CodeTransportUnavailable, "HEALTH_ADDR is not configured")
+			}
+			if !useHome && pose.MapID == "" {
+				return apperr.Validation("map", "--map is required unless --home is set")
+			}
+			addr := cfg.HealthAddr
+			if strings.HasPrefix(addr, ":") {
+				addr = "localhost" + addr
+			}
+			url := fmt.Sprintf("http://%s/admin/robots/%s/init-position", addr, args[0])
+
+			body, _ := json.Marshal(struct {
+				models.PoseValue
+				UseHome bool `json:"useHome"`
+			}{pose, useHome})
+			httpClient := &http.Client{Timeout: 5 * time.Second}
+			resp, err := httpClient.Post(url, "application/json", bytes.NewReader(body))
+			if err != nil {
+				return apperr.Wrap(apperr.CodeTransportUnavailable, err, "failed to reach bridge health server")
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusAccepted {
+				var failure apperr.Response
+				if err := json.NewDecoder(resp.Body).Decode(&failure); err != nil || failure.Message == "" {
+					return fmt.Errorf("unexpected status from health server: %s", resp.Status)
+				}
+				return apperr.New(failure.Code, "%s", failure.Message)
+			}
+			var request robot.InitPositionRequest
+			if err := json.NewDecoder(resp.Body).Decode(&request); err != nil {
+				return err
+			}
+			fmt.Printf("Sent initPosition %s to %s (%s pose: map %s, x=%.3f y=%.3f theta=%.3f)\n",
+				request.ActionID, args[0], request.Source, request.Pose.MapID, request.Pose.X, request.Pose.Y, request.Pose.Theta)
+			if wait <= 0 {
+				return nil
+			}
+
+			// 로봇이 actionStates로 결과를 보고할 때까지 진행 상태 조회
+			deadline := time.Now().Add(wait)
+			for time.Now().Before(deadline) {
+				time.Sleep(time.Second)
+				resp, err := httpClient.Get(url)
+				if err != nil {
+					return apperr.Wrap(apperr.CodeTransportUnavailable, err, "failed to reach bridge health server")
+				}
+				var requests []robot.InitPositionRequest
+				err = json.NewDecoder(resp.Body).Decode(&requests)
+				resp.Body.Close()
+				if err != nil {
+					return err
+				}
+				for _, r := range requests {
+					if r.ActionID != request.ActionID || r.Status == request.Status {
+						continue
+					}
+					request = r
+					fmt.Printf("%s %s\n", time.Now().Format(time.RFC3339), r.Status)
+				}
+				switch request.Status {
+				case constants.ActionStatusFinished:
+					fmt.Printf("Position initialized: %t\n", request.PositionInit)
+					return nil
+				case constants.ActionStatusFailed:
+					return fmt.Errorf("initPosition failed on %s: %s", args[0], request.ResultDescription)
+				}
+			}
+			return fmt.Errorf("no initPosition result from %s within %s (status %s)", args[0], wait, request.Status)
+		},
+	}
+	initCmd.Flags().StringVar(&pose.MapID, "map", "", "맵 ID (--home이 아니면 필수)")
+	initCmd.Flags().Float64Var(&pose.X, "x", 0, "X 좌표")
+	initCmd.Flags().Float64Var(&pose.Y, "y", 0, "Y 좌표")
+	initCmd.Flags().Float64Var(&pose.Theta, "theta", 0, "방향 (rad)")
+	initCmd.Flags().StringVar(&pose.LastNodeID, "last-node", "", "lastNodeId")
+	initCmd.Flags().BoolVar(&useHome, "home", false, "저장된 홈 위치 사용 (robots defaults)")
+	initCmd.Flags().DurationVar(&wait, "wait", 0, "로봇이 결과를 보고할 때까지 기다리는 시간 (0이면 전송만)")
+	return initCmd
+}
+
+// newDualArmTrajectoryCmd 실행 중인 브릿지를 통해 양팔 궤적을 오더 하나로 전송
+func newDualArmTrajectoryCmd() *cobra.Command {
+	var req workflow.DualArmTrajectory
+	dualCmd := &cobra.Command{
+		Use:   "dual-arm <serialNumber>",
+		Short: "왼팔/오른팔 궤적을 오더 하나로 전송 (HEALTH_ADDR의 /admin/robots/<serial>/dual-arm-trajectory)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.HealthAddr == "" {
+				return apperr.New(apperr.CodeTransportUnavailable, "HEALTH_ADDR is not configured")
+			}
+			addr := cfg.HealthAddr
+			if strings.HasPrefix(addr, ":") {
+				addr = "localhost" + addr
+			}
+			url := fmt.Sprintf("http://%s/admin/robots/%s/dual-arm-trajectory", addr, args[0])
+
+			body, _ := json.Marshal(req)
+			httpClient := &http.Client{Timeout: 5 * time.Second}
+			resp, err := httpClient.Post(url, "application/json", bytes.NewReader(body))
+			if err != nil {
+				return apperr.Wrap(apperr.CodeTransportUnavailable, err, "failed to reach bridge health server")
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusAccepted {
+				var failure apperr.Response
+				if err := json.NewDecoder(resp.Body).Decode(&failure); err != nil || failure.Message == "" {
+					return fmt.Errorf("unexpected status from health server: %s", resp.Status)
+				}
+				return apperr.New(failure.Code, "%s", failure.Message)
+			}
+			var result workflow.DualArmTrajectoryResult
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				return err
+			}
+			fmt.Printf("Sent dual-arm order %s to %s\n", result.OrderID, result.SerialNumber)
+			fmt.Printf("  left:  %s (action %s)\n", req.Left, result.LeftActionID)
+			fmt.Printf("  right: %s (action %s)\n", req.Right, result.RightActionID)
+			if result.Barrier {
+				fmt.Printf("  barrier: sync_group=%s\n", result.SyncGroup)
+			}
+			return nil
+		},
+	}
+	dualCmd.Flags().StringVar(&req.Left, "left", "", "왼팔 trajectory_name (필수)")
+	dualCmd.Flags().StringVar(&req.Right, "right", "", "오른팔 trajectory_name (필수)")
+	dualCmd.Flags().BoolVar(&req.Barrier, "barrier", false, "두 팔을 동시에 시작하고 둘 다 끝나야 완료")
+	_ = dualCmd.MarkFlagRequired("left")
+	_ = dualCmd.MarkFlagRequired("right")
+	return dualCmd
+}
+
+// newStateCmd 로봇 상태 메시지 구독 명령
+func newStateCmd() *cobra.Command {
+	stateCmd := &cobra.Command{Use: "state", Short: "로봇 상태 메시지"}
+
+	stateCmd.AddCommand(&cobra.Command{
+		Use:   "tail [serialNumber]",
+		Short: "로봇 상태 메시지를 실시간으로 출력 (기본: 전체 로봇)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			serial := "+"
+			if len(args) == 1 {
+				serial = args[0]
+			}
+			topic := "meili/v2/+/" + serial + "/state"
+
+			mqttClient, err := connectMQTT()
+			if err != nil {
+				return err
+			}
+			defer mqttClient.Disconnect(250)
+
+			err = mqttClient.Subscribe(topic, 0, func(_ mqtt.Client, msg mqtt.Message) {
+				fmt.Printf("%s %s %s\n", time.Now().Format(time.RFC3339), msg.Topic(), string(msg.Payload()))
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Tailing %s (Ctrl+C to stop)\n", topic)
+
+			sigChan := make(chan os.Signal, 1)
+			signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+			<-sigChan
+			return nil
+		},
+	})
+
+	stateCmd.AddCommand(&cobra.Command{
+		Use:   "get <serialNumber>",
+		Short: "Redis에 캐시된 로봇의 마지막 state 필드 출력",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			redisConn, err := redis.NewRedisClient(cfg)
+			if err != nil {
+				return apperr.Wrap(apperr.CodeTransportUnavailable, err, "failed to connect to Redis")
+			}
+			defer redisConn.Close()
+
+			fields, err := robot.ReadCachedState(cmd.Context(), redisConn, args[0])
+			if err != nil {
+				return err
+			}
+			if len(fields) == 0 {
+				return apperr.New(apperr.CodeNotFound, "no cached state for robot %s", args[0]).WithField("serialNumber")
+			}
+
+			keys := make([]string, 0, len(fields))
+			for key := range fields {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			for _, key := range keys {
+				fmt.Fprintf(w, "%s\t%s\n", key, fields[key])
+			}
+			return w.Flush()
+		},
+	})
+
+	return stateCmd
+}
+
+// newPayloadsCmd 수신 페이로드 스키마 검증 명령
+func newPayloadsCmd() *cobra.Command {
+	payloadsCmd := &cobra.Command{Use: "payloads", Short: "수신 페이로드 스키마 검증"}
+
+	payloadsCmd.AddCommand(&cobra.Command{
+		Use:   "validate <state|connection|factsheet> <file|->",
+		Short: "VDA 5050 페이로드를 브릿지와 같은 스키마로 검증 (파일 대신 -면 stdin)",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var payload []byte
+			var err error
+			if args[1] == "-" {
+				payload, err = io.ReadAll(os.Stdin)
+			} else {
+				payload, err = os.ReadFile(args[1])
+			}
+			if err != nil {
+				return err
+			}
+
+			violations, err := messaging.ValidatePayload(args[0], payload)
+			if err != nil {
+				return apperr.Validation("kind", "%v", err)
+			}
+			if len(violations) == 0 {
+				fmt.Printf("%s payload is valid\n", args[0])
+				return nil
+			}
+			for _, violation := range violations {
+				fmt.Println(violation.String())
+			}
+			return apperr.New(apperr.CodeValidationFailed, "%s payload has %d schema violation(s)", args[0], len(violations))
+		},
+	})
+
+	payloadsCmd.AddCommand(&cobra.Command{
+		Use:   "stats",
+		Short: "실행 중인 브릿지의 페이로드 검증 지표 조회 (HEALTH_ADDR의 /admin/schema)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.HealthAddr == "" {
+				return apperr.New(apperr.CodeTransportUnavailable, "HEALTH_ADDR is not configured")
+			}
+			addr := cfg.HealthAddr
+			if strings.HasPrefix(addr, ":") {
+				addr = "localhost" + addr
+			}
+
+			httpClient := &http.Client{Timeout: 5 * time.Second}
+			resp, err := httpClient.Get("http://" + addr + "/admin/schema")
+			if err != nil {
+				return apperr.Wrap(apperr.CodeTransportUnavailable, err, "failed to reach bridge health server")
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode == http.StatusNotFound {
+				return apperr.New(apperr.CodeNotFound, "payload schema validation is disabled on the bridge")
+			}
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("unexpected status from health server: %s", resp.Status)
+			}
+
+			var report messaging.SchemaReport
+			if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+				return err
+			}
+
+			fmt.Printf("Mode: %s\n", report.Mode)
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "KIND\tVALIDATED\tINVALID\tREJECTED\tLAST VIOLATION")
+			for _, k := range report.Kinds {
+				last := "-"
+				if k.LastViolation != "" {
+					last = fmt.Sprintf("%s (%s)", k.LastViolation, k.LastTopic)
+				}
+				fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\n", k.Kind, k.Validated, k.Invalid, k.Rejected, last)
+			}
+			return w.Flush()
+		},
+	})
+
+	payloadsCmd.AddCommand(&cobra.Command{
+		Use:   "freshness",
+		Short: "실행 중인 브릿지의 timestamp 오차/오래된 state 메시지 지표 조회 (HEALTH_ADDR의 /admin/freshness)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.HealthAddr == "" {
+				return apperr.New(apperr.CodeTransportUnavailable, "HEALTH_ADDR is not configured")
+			}
+			addr := cfg.HealthAddr
+			if strings.HasPrefix(addr, ":") {
+				addr = "localhost" + addr
+			}
+
+			httpClient := &http.Client{Timeout: 5 * time.Second}
+			resp, err := httpClient.Get("http://" + addr + "/admin/freshness")
+			if err != nil {
+				return apperr.Wrap(apperr.CodeTransportUnavailable, err, "failed to reach bridge health server")
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode == http.StatusNotFound {
+				return apperr.New(apperr.CodeNotFound, "message timestamp checks are disabled on the bridge")
+			}
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("unexpected status from health server: %s", resp.Status)
+			}
+
+			var report messaging.FreshnessReport
+			if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+				return err
+			}
+
+			fmt.Printf("Max skew: %gs, drop stale state: %t\n", report.MaxSkewSeconds, report.DropStale)
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "KIND\tCHECKED\tUNPARSABLE\tSKEWED\tSTALE DROPPED\tLAST DROP")
+			for _, k := range report.Kinds {
+				last := "-"
+				if k.LastDrop != "" {
+					last = fmt.Sprintf("%s (%s)", k.LastDrop, k.LastTopic)
+				}
+				fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%s\n", k.Kind, k.Checked, k.Unparsable, k.Skewed, k.StaleDropped, last)
+			}
+			return w.Flush()
+		},
+	})
+
+	return payloadsCmd
+}
+
+// newRobotHealthCmd 실행 중인 브릿지의 로봇 state 메시지 지연 조회 (HEALTH_ADDR의 /admin/robots/<serial>/health)
+func newRobotHealthCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "health <serialNumber>",
+		Short: "로봇 state 메시지 지연 통계 (헤더 timestamp와 수신 시각 차이, 추정 시계 오차/네트워크 지연)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.HealthAddr == "" {
+				return apperr.New(apperr.CodeTransportUnavailable, "HEALTH_ADDR is not configured")
+			}
+			addr := cfg.HealthAddr
+			if strings.HasPrefix(addr, ":") {
+				addr = "localhost" + addr
+			}
+			httpClient := &http.Client{Timeout: 5 * time.Second}
+			resp, err := httpClient.Get("http://" + addr + "/admin/robots/" + url.PathEscape(args[0]) + "/health")
+			if err != nil {
+				return apperr.Wrap(apperr.CodeTransportUnavailable, err, "failed to reach bridge health server")
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				var failure apperr.Response
+				if err := json.NewDecoder(resp.Body).Decode(&failure); err != nil || failure.Message == "" {
+					return fmt.Errorf("unexpected status from health server: %s", resp.Status)
+				}
+				return apperr.New(failure.Code, "%s", failure.Message)
+			}
+			var report health.RobotLatencyHealth
+			if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "Status:\t%s\n", report.Status)
+			fmt.Fprintf(w, "Samples:\t%d (last received %s)\n", report.Samples, report.LastReceivedAt.Local().Format(time.RFC3339))
+			fmt.Fprintf(w, "Delay (ms):\tlast %.0f  min %.0f  p50 %.0f  p95 %.0f  max %.0f\n",
+				report.LastMs, report.MinMs, report.P50Ms, report.P95Ms, report.MaxMs)
+			fmt.Fprintf(w, "Clock offset:\t%.0fms (negative = robot clock ahead)\n", report.ClockOffsetMs)
+			fmt.Fprintf(w, "Network p95:\t%.0fms\n", report.NetworkP95Ms)
+			for _, warning := range report.Warnings {
+				fmt.Fprintf(w, "Warning:\t%s\n", warning)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+// newRobotPreflightCmd 로봇별 사전 점검 설정 조회와 점검 끄기/켜기 (PREFLIGHT_CHECKS)
+func newRobotPreflightCmd() *cobra.Command {
+	var disable, reason string
+	var enableAll bool
+	preflightCmd := &cobra.Command{
+		Use:   "preflight <serialNumber>",
+		Short: "오더 전송 전 사전 점검 설정 (--disable로 로봇에서 점검 끄기, --enable-all로 모두 켜기)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			serialNumber := args[0]
+			changing := cmd.Flags().Changed("disable") || enableAll
+			if cmd.Flags().Changed("disable") && enableAll {
+				return apperr.Validation("disable", "give either --disable or --enable-all")
+			}
+			if changing {
+				var checks []string
+				if !enableAll {
+					if reason == "" {
+						return apperr.Validation("reason", "--reason is required")
+					}
+					checks = strings.Split(disable, ",")
+				}
+				api, err := adminClient()
+				if err != nil {
+					return err
+				}
+				if err := api.SetDisabledPreflightChecks(cmd.Context(), serialNumber, checks, reason); err != nil {
+					return err
+				}
+			}
+
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+
+			enabled, err := repository.ParsePreflightChecks(cfg.PreflightChecks)
+			if err != nil {
+				return err
+			}
+			disabled, err := repository.DisabledPreflightChecks(db, cfg.SiteID, serialNumber)
+			if err != nil {
+				return err
+			}
+			override, err := repository.GetPreflightOverride(db, cfg.SiteID, serialNumber)
+			if err != nil {
+				return err
+			}
+			configured := make(map[string]bool)
+			for _, check := range enabled {
+				configured[check] = true
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "CHECK\tPREFLIGHT_CHECKS\tROBOT")
+			for _, check := range repository.PreflightChecks {
+				state, robotState := "off", "on"
+				if configured[check] {
+					state = "on"
+				}
+				if disabled[check] {
+					robotState = "disabled"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", check, state, robotState)
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			if override != nil {
+				fmt.Printf("Disabled since %s: %s\n", override.UpdatedAt.Local().Format(time.RFC3339), override.Reason)
+			}
+			return nil
+		},
+	}
+	preflightCmd.Flags().StringVar(&disable, "disable", "", "로봇에서 끌 점검 (쉼표 구분, 기존 설정 교체: "+strings.Join(repository.PreflightChecks, ", ")+")")
+	preflightCmd.Flags().StringVar(&reason, "reason", "", "점검을 끄는 사유 (--disable일 때 필수)")
+	preflightCmd.Flags().BoolVar(&enableAll, "enable-all", false, "로봇에서 끈 점검을 모두 다시 켬")
+	return preflightCmd
+}
+
+// newRobotTokensCmd 로봇 HTTP 콜백 세션 토큰 조회, 발급, 교체, 폐기
+func newRobotTokensCmd() *cobra.Command {
+	tokensCmd := &cobra.Command{Use: "tokens", Short: "로봇이 HTTP로 결과/state를 보낼 때 쓰는 세션 토큰"}
+
+	var all bool
+	listCmd := &cobra.Command{
+		Use:   "list <serialNumber>",
+		Short: "토큰 목록 (기본은 활성 토큰만)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+			tokens, err := repository.ListRobotTokens(db, cfg.SiteID, args[0], all)
+			if err != nil {
+				return err
+			}
+			now := time.Now()
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "TOKEN_ID\tLABEL\tSTATUS\tCREATED\tEXPIRES\tLAST_USED")
+			for _, token := range tokens {
+				status := "active"
+				if token.RevokedAt != nil {
+					status = "revoked"
+				} else if !token.Active(now) {
+					status = "expired"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", token.TokenID, token.Label, status,
+					token.CreatedAt.Local().Format(time.RFC3339), formatOptionalTime(token.ExpiresAt), formatOptionalTime(token.LastUsedAt))
+			}
+			return w.Flush()
+		},
+	}
+	listCmd.Flags().BoolVar(&all, "all", false, "폐기/만료된 토큰도 표시")
+	tokensCmd.AddCommand(listCmd)
+
+	for _, rotate := range []bool{false, true} {
+		var label string
+		var ttl time.Duration
+		issueCmd := &cobra.Command{
+			Use:   "issue <serialNumber>",
+			Short: "토큰 발급 (원문은 지금 한 번만 표시)",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				api, err := adminClient()
+				if err != nil {
+					return err
+				}
+				var issued *client.IssuedRobotToken
+				if rotate {
+					issued, err = api.RotateRobotToken(cmd.Context(), args[0], label, ttl)
+				} else {
+					issued, err = api.IssueRobotToken(cmd.Context(), args[0], label, ttl)
+				}
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Issued token %s for %s (expires %s)\n", issued.TokenID, args[0], formatOptionalTime(issued.ExpiresAt))
+				if rotate {
+					fmt.Printf("Previous tokens stop working after %s\n", cfg.RobotTokenRotationGrace)
+				}
+				fmt.Println(issued.Token)
+				return nil
+			},
+		}
+		if rotate {
+			issueCmd.Use = "rotate <serialNumber>"
+			issueCmd.Short = "새 토큰 발급, 기존 토큰은 ROBOT_TOKEN_ROTATION_GRACE_SECONDS 뒤 만료"
+		}
+		issueCmd.Flags().StringVar(&label, "label", "", "토큰 설명")
+		issueCmd.Flags().DurationVar(&ttl, "ttl", 0, "유효 기간 (기본: 브릿지의 ROBOT_TOKEN_TTL_HOURS)")
+		tokensCmd.AddCommand(issueCmd)
+	}
+
+	tokensCmd.AddCommand(&cobra.Command{
+		Use:   "revoke <serialNumber> [tokenId]",
+		Short: "토큰 폐기 (tokenId가 없으면 로봇의 모든 토큰)",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			if len(args) == 2 {
+				if _, err := api.RevokeRobotToken(cmd.Context(), args[0], args[1]); err != nil {
+					return err
+				}
+				fmt.Printf("Revoked token %s of %s\n", args[1], args[0])
+				return nil
+			}
+			revoked, err := api.RevokeRobotTokens(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Revoked %d token(s) of %s\n", revoked, args[0])
+			return nil
+		},
+	})
+
+	return tokensCmd
+}
+
+// formatOptionalTime 시각 표시 (nil이면 "-")
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Local().Format(time.RFC3339)
+}
@@ -0,0 +1,285 @@
+// cmd/bridgectl/schedule.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/workflow"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// newWindowsCmd 오더 실행 제한 시간대 관리 명령
+func newWindowsCmd() *cobra.Command {
+	windowsCmd := &cobra.Command{Use: "windows", Short: "오더 실행 제한 시간대 (조용한 시간)"}
+
+	windowsCmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "실행 제한 시간대 목록",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+			windows, err := repository.ListExecutionWindows(db, cfg.SiteID)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tTIME\tTEMPLATE\tROBOTS\tPOLICY\tACTIVE\tOVERRIDE UNTIL")
+			for _, window := range windows {
+				template, robots, override := "*", "*", "-"
+				if window.TemplateID != nil {
+					template = strconv.FormatUint(uint64(*window.TemplateID), 10)
+				}
+				if window.Robots != "" {
+					robots = window.Robots
+				}
+				if window.OverrideUntil != nil && time.Now().Before(*window.OverrideUntil) {
+					override = window.OverrideUntil.Format(time.RFC3339)
+				}
+				timeRange := window.StartTime + "-" + window.EndTime
+				if window.Timezone != "" {
+					timeRange += " " + window.Timezone
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%t\t%s\n",
+					window.Name, timeRange, template, robots, window.Policy, window.IsActive, override)
+			}
+			return w.Flush()
+		},
+	})
+
+	var window models.ExecutionWindow
+	var templateID uint
+	var robots []string
+	var inactive bool
+	setCmd := &cobra.Command{
+		Use:   "set <name>",
+		Short: "실행 제한 시간대 저장 (이미 있으면 전체 교체)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			window.Name = args[0]
+			window.Robots = strings.Join(robots, ",")
+			window.IsActive = !inactive
+			if templateID != 0 {
+				window.TemplateID = &templateID
+			}
+			saved, err := api.SaveExecutionWindow(cmd.Context(), window)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Saved execution window %s (%s-%s, %s)\n", saved.Name, saved.StartTime, saved.EndTime, saved.Policy)
+			return nil
+		},
+	}
+	setCmd.Flags().StringVar(&window.StartTime, "start", "", "제한 시작 시각 (HH:MM)")
+	setCmd.Flags().StringVar(&window.EndTime, "end", "", "제한 종료 시각 (HH:MM, 시작보다 이르면 다음 날)")
+	setCmd.Flags().StringVar(&window.Timezone, "timezone", "", "IANA 시간대 (기본: 브릿지 로컬 시간)")
+	setCmd.Flags().StringVar(&window.Policy, "policy", constants.ExecutionWindowPolicyQueue, "제한 시간대의 오더 처리 (queue: 끝날 때까지 대기, reject: 거부)")
+	setCmd.Flags().UintVar(&templateID, "template", 0, "적용할 템플릿 ID (기본: 모든 템플릿)")
+	setCmd.Flags().StringSliceVar(&robots, "robots", nil, "적용할 로봇 시리얼 번호 (쉼표로 구분, 기본: 모든 로봇)")
+	setCmd.Flags().BoolVar(&inactive, "inactive", false, "비활성 상태로 저장")
+	_ = setCmd.MarkFlagRequired("start")
+	_ = setCmd.MarkFlagRequired("end")
+	windowsCmd.AddCommand(setCmd)
+
+	windowsCmd.AddCommand(&cobra.Command{
+		Use:   "delete <name>",
+		Short: "실행 제한 시간대 삭제",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			if err := api.DeleteExecutionWindow(cmd.Context(), args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("Deleted execution window %s\n", args[0])
+			return nil
+		},
+	})
+
+	var overrideFor time.Duration
+	var clearOverride bool
+	overrideCmd := &cobra.Command{
+		Use:   "override <name>",
+		Short: "관리자 권한으로 시간대 제한을 일시 해제 (대기 중인 명령은 1분 안에 배차)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !clearOverride && overrideFor <= 0 {
+				return apperr.Validation("for", "--for or --clear is required")
+			}
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			if clearOverride {
+				if err := api.ClearExecutionWindowOverride(cmd.Context(), args[0]); err != nil {
+					return err
+				}
+				fmt.Printf("Cleared override for execution window %s\n", args[0])
+				return nil
+			}
+			if err := api.OverrideExecutionWindow(cmd.Context(), args[0], overrideFor); err != nil {
+				return err
+			}
+			fmt.Printf("Execution window %s overridden until %s\n", args[0], time.Now().Add(overrideFor).Format(time.RFC3339))
+			return nil
+		},
+	}
+	overrideCmd.Flags().DurationVar(&overrideFor, "for", 0, "해제 기간 (예: 30m, 2h)")
+	overrideCmd.Flags().BoolVar(&clearOverride, "clear", false, "해제 취소")
+	windowsCmd.AddCommand(overrideCmd)
+
+	return windowsCmd
+}
+
+// newChargingCmd 로봇 그룹별 자동 충전 정책과 충전 상태
+func newChargingCmd() *cobra.Command {
+	chargingCmd := &cobra.Command{Use: "charging", Short: "배터리 부족 시 자동 충전 정책 (로봇 그룹별)"}
+
+	chargingCmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "충전 정책 목록",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+			policies, err := repository.ListChargingPolicies(db, cfg.SiteID)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tROBOTS\tLOW\tRESUME\tCOMMAND\tACTIVE")
+			for _, policy := range policies {
+				robots := "*"
+				if policy.Robots != "" {
+					robots = policy.Robots
+				}
+				fmt.Fprintf(w, "%s\t%s\t%.1f%%\t%.1f%%\t%s\t%t\n",
+					policy.Name, robots, policy.LowBattery, policy.ResumeBattery, policy.CommandType, policy.IsActive)
+			}
+			return w.Flush()
+		},
+	})
+
+	var policy models.ChargingPolicy
+	var robots []string
+	var inactive bool
+	setCmd := &cobra.Command{
+		Use:   "set <name>",
+		Short: "충전 정책 저장 (이미 있으면 전체 교체)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			policy.Name = args[0]
+			policy.Robots = strings.Join(robots, ",")
+			policy.IsActive = !inactive
+			saved, err := api.SaveChargingPolicy(cmd.Context(), policy)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Saved charging policy %s (below %.1f%% send %s, available again at %.1f%%)\n",
+				saved.Name, saved.LowBattery, saved.CommandType, saved.ResumeBattery)
+			return nil
+		},
+	}
+	setCmd.Flags().Float64Var(&policy.LowBattery, "low", 0, "이 잔량(%) 미만이면 충전 명령 배차")
+	setCmd.Flags().Float64Var(&policy.ResumeBattery, "resume", 0, "이 잔량(%) 이상이면 다시 배차 가능")
+	setCmd.Flags().StringVar(&policy.CommandType, "command", "", "충전소 이동 명령 (충전 템플릿에 매핑된 명령 유형)")
+	setCmd.Flags().StringSliceVar(&robots, "robots", nil, "적용할 로봇 시리얼 번호 (쉼표로 구분, 기본: 다른 정책에 없는 모든 로봇)")
+	setCmd.Flags().BoolVar(&inactive, "inactive", false, "비활성 상태로 저장")
+	_ = setCmd.MarkFlagRequired("low")
+	_ = setCmd.MarkFlagRequired("resume")
+	_ = setCmd.MarkFlagRequired("command")
+	chargingCmd.AddCommand(setCmd)
+
+	chargingCmd.AddCommand(&cobra.Command{
+		Use:   "delete <name>",
+		Short: "충전 정책 삭제 (충전 중인 로봇은 다음 평가에서 배차 가능으로 복귀)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			if err := api.DeleteChargingPolicy(cmd.Context(), args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("Deleted charging policy %s\n", args[0])
+			return nil
+		},
+	})
+
+	chargingCmd.AddCommand(&cobra.Command{
+		Use:   "status <serialNumber>",
+		Short: "로봇의 자동 충전 상태 (HEALTH_ADDR의 /admin/robots/<serial>/charging)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.HealthAddr == "" {
+				return apperr.New(apperr.CodeTransportUnavailable, "HEALTH_ADDR is not configured")
+			}
+			addr := cfg.HealthAddr
+			if strings.HasPrefix(addr, ":") {
+				addr = "localhost" + addr
+			}
+			httpClient := &http.Client{Timeout: 5 * time.Second}
+			resp, err := httpClient.Get(fmt.Sprintf("http://%s/admin/robots/%s/charging", addr, args[0]))
+			if err != nil {
+				return apperr.Wrap(apperr.CodeTransportUnavailable, err, "failed to reach bridge health server")
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				var failure apperr.Response
+				if err := json.NewDecoder(resp.Body).Decode(&failure); err != nil || failure.Message == "" {
+					return fmt.Errorf("unexpected status from health server: %s", resp.Status)
+				}
+				return apperr.New(failure.Code, "%s", failure.Message)
+			}
+			var status workflow.ChargingStatus
+			if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+				return err
+			}
+
+			fmt.Printf("Robot:    %s\n", status.SerialNumber)
+			fmt.Printf("State:    %s\n", status.State)
+			fmt.Printf("Battery:  %.1f%% (charging=%t)\n", status.BatteryCharge, status.Charging)
+			if status.Policy != "" {
+				fmt.Printf("Policy:   %s (below %.1f%% send %s, available again at %.1f%%)\n",
+					status.Policy, status.LowBattery, status.CommandType, status.ResumeBattery)
+			}
+			if status.LastDispatchAt != nil {
+				fmt.Printf("Dispatch: %s at %s\n", status.LastDispatch, status.LastDispatchAt.Format(time.RFC3339))
+			}
+			if status.LastError != "" {
+				fmt.Printf("Error:    %s\n", status.LastError)
+			}
+			return nil
+		},
+	})
+
+	return chargingCmd
+}
@@ -0,0 +1,1157 @@
+// cmd/bridgectl/system.go
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mqtt-bridge/internal/bridge"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/common/readonly"
+	"mqtt-bridge/internal/database"
+	"mqtt-bridge/internal/diagnostics"
+	"mqtt-bridge/internal/faults"
+	"mqtt-bridge/internal/graphql"
+	"mqtt-bridge/internal/messaging"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/redis"
+	"mqtt-bridge/internal/replay"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/retention"
+	"mqtt-bridge/internal/selftest"
+	"mqtt-bridge/internal/utils"
+	"mqtt-bridge/internal/workflow"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// newReplayCmd 기록된 MQTT 트래픽을 테스트 DB에 대해 핸들러 체인으로 재생
+func newReplayCmd() *cobra.Command {
+	var opts replay.Options
+	var testDBName, publishLog string
+	var redisDB int
+	var settle time.Duration
+
+	replayCmd := &cobra.Command{
+		Use:   "replay <recordFile>",
+		Short: "RECORD_FILE로 기록한 MQTT 메시지를 테스트 DB에 대해 재생",
+		Long: "브로커 없이 브릿지의 라우터/명령/워크플로우 핸들러에 기록된 메시지를 순서대로 전달합니다.\n" +
+			"테스트 DB는 마이그레이션과 기본 데이터가 적용되며, 운영 DB(DB_NAME)로는 실행할 수 없습니다.\n" +
+			"브릿지가 발행하는 오더/instantActions/PLC 응답은 --publish-log 파일에 기록됩니다.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if testDBName == "" || testDBName == cfg.DBName {
+				return apperr.Validation("test-db", "--test-db must name a database other than %q", cfg.DBName)
+			}
+
+			file, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			entries, err := replay.ReadEntries(file)
+			file.Close()
+			if err != nil {
+				return err
+			}
+
+			replayCfg := *cfg
+			replayCfg.DBName = testDBName
+			replayCfg.DBReadDSN = ""
+			replayCfg.RedisDB = redisDB
+			replayCfg.RecordFile = ""
+
+			db, err := database.NewPostgresDB(&replayCfg)
+			if err != nil {
+				return fmt.Errorf("failed to prepare test database %s: %w", testDBName, err)
+			}
+			redisConn, err := redis.NewRedisClient(&replayCfg)
+			if err != nil {
+				return err
+			}
+			defer redisConn.Close()
+
+			var out io.Writer
+			if publishLog != "" {
+				logFile, err := os.Create(publishLog)
+				if err != nil {
+					return err
+				}
+				defer logFile.Close()
+				out = logFile
+			}
+			client := replay.NewCaptureClient(out)
+
+			chain, err := bridge.NewHandlerChain(db, redisConn, &replayCfg, client)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+			chain.Executor.Start(ctx)
+			if chain.StateCache != nil {
+				chain.StateCache.Start(ctx)
+				defer chain.StateCache.Stop()
+			}
+			if chain.StateWriter != nil {
+				chain.StateWriter.Start(ctx)
+				defer chain.StateWriter.Stop()
+			}
+
+			stats, err := replay.Play(ctx, entries, chain.Router, client, opts)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				return err
+			}
+
+			// 비동기 워크플로우(아웃박스 전송 등)가 마무리될 시간
+			if settle > 0 && ctx.Err() == nil {
+				time.Sleep(settle)
+			}
+
+			fmt.Printf("Replayed %d message(s) (%d skipped) in %s, bridge published %d message(s)\n",
+				stats.Routed, stats.Skipped, stats.Duration.Round(time.Millisecond), client.Published())
+			return nil
+		},
+	}
+	replayCmd.Flags().StringVar(&testDBName, "test-db", "", "재생에 사용할 테스트 데이터베이스 이름 (필수)")
+	replayCmd.Flags().IntVar(&redisDB, "redis-db", 15, "재생에 사용할 Redis DB 번호")
+	replayCmd.Flags().Float64Var(&opts.Speed, "speed", 1, "기록 간격 배속 (0이면 간격 무시)")
+	replayCmd.Flags().Float64Var(&opts.MaxRate, "rate", 0, "초당 최대 메시지 수 (0이면 제한 없음)")
+	replayCmd.Flags().StringSliceVar(&opts.TopicFilters, "topic", nil, "재생할 토픽 부분 문자열 (반복 가능, 예: /state)")
+	replayCmd.Flags().StringVar(&publishLog, "publish-log", "", "브릿지가 발행한 메시지를 기록할 JSON Lines 파일")
+	replayCmd.Flags().DurationVar(&settle, "settle", 2*time.Second, "마지막 메시지 후 비동기 처리를 기다릴 시간")
+	return replayCmd
+}
+
+// newAlertsCmd 알림 이력과 억제 시간대 명령
+func newAlertsCmd() *cobra.Command {
+	alertsCmd := &cobra.Command{Use: "alerts", Short: "알림 (Slack/이메일) 이력과 억제 시간대"}
+
+	var filter repository.AlertEventFilter
+	var since time.Duration
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "알림 이력 (최신순)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+			if since > 0 {
+				filter.Since = time.Now().Add(-since)
+			}
+			filter.Status = strings.ToUpper(filter.Status)
+			events, err := repository.ListAlertEvents(db, cfg.SiteID, filter)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "TIME	RULE	SUBJECT	SEVERITY	STATUS	CHANNELS	MESSAGE")
+			for _, event := range events {
+				channels := event.Channels
+				if channels == "" {
+					channels = "-"
+				}
+				message := event.Message
+				if event.Error != "" {
+					message += " (" + event.Error + ")"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					event.CreatedAt.Format(time.RFC3339), event.Rule, event.Subject, event.Severity,
+					event.Status, channels, message)
+			}
+			return w.Flush()
+		},
+	}
+	listCmd.Flags().StringVar(&filter.Rule, "rule", "", "알림 규칙 (robot_offline, command_failed, battery_low, order_stuck, test)")
+	listCmd.Flags().StringVar(&filter.Status, "status", "", "전송 상태 (SENT, FAILED, SUPPRESSED)")
+	listCmd.Flags().DurationVar(&since, "since", 24*time.Hour, "조회 기간 (0이면 전체)")
+	listCmd.Flags().IntVar(&filter.Limit, "limit", 50, "최대 건수")
+	alertsCmd.AddCommand(listCmd)
+
+	var suppression models.AlertSuppression
+	var suppressFor time.Duration
+	suppressCmd := &cobra.Command{
+		Use:   "suppress",
+		Short: "지금부터 일정 시간 알림 억제 (점검 작업 등, 억제된 알림은 이력에만 기록)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if suppressFor <= 0 {
+				return apperr.Validation("for", "--for must be positive")
+			}
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			suppression.StartsAt = time.Now()
+			suppression.EndsAt = suppression.StartsAt.Add(suppressFor)
+			created, err := api.CreateAlertSuppression(cmd.Context(), suppression)
+			if err != nil {
+				return err
+			}
+			rule := created.Rule
+			if rule == "" {
+				rule = "all rules"
+			}
+			fmt.Printf("Suppressed %s until %s (id: %d)\n", rule, created.EndsAt.Format(time.RFC3339), created.ID)
+			return nil
+		},
+	}
+	suppressCmd.Flags().StringVar(&suppression.Rule, "rule", "", "억제할 규칙 (기본: 모든 규칙)")
+	suppressCmd.Flags().StringVar(&suppression.Reason, "reason", "", "억제 사유")
+	suppressCmd.Flags().DurationVar(&suppressFor, "for", 0, "억제 기간 (예: 30m, 2h)")
+	_ = suppressCmd.MarkFlagRequired("for")
+	alertsCmd.AddCommand(suppressCmd)
+
+	alertsCmd.AddCommand(&cobra.Command{
+		Use:   "suppressions",
+		Short: "끝나지 않은 알림 억제 시간대 목록",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+			suppressions, err := repository.ListAlertSuppressions(db, cfg.SiteID, time.Now())
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tRULE\tSTARTS\tENDS\tREASON")
+			for _, s := range suppressions {
+				rule := s.Rule
+				if rule == "" {
+					rule = "*"
+				}
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n",
+					s.ID, rule, s.StartsAt.Format(time.RFC3339), s.EndsAt.Format(time.RFC3339), s.Reason)
+			}
+			return w.Flush()
+		},
+	})
+
+	alertsCmd.AddCommand(&cobra.Command{
+		Use:   "unsuppress <id>",
+		Short: "알림 억제 시간대 삭제",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return apperr.Validation("id", "invalid suppression id: %s", args[0])
+			}
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			if err := api.DeleteAlertSuppression(cmd.Context(), uint(id)); err != nil {
+				return err
+			}
+			fmt.Printf("Deleted alert suppression %d\n", id)
+			return nil
+		},
+	})
+
+	alertsCmd.AddCommand(&cobra.Command{
+		Use:   "test [message]",
+		Short: "실행 중인 브릿지의 알림 채널로 시험 알림 전송 (HEALTH_ADDR의 /admin/alerts/test)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.HealthAddr == "" {
+				return apperr.New(apperr.CodeTransportUnavailable, "HEALTH_ADDR is not configured")
+			}
+			addr := cfg.HealthAddr
+			if strings.HasPrefix(addr, ":") {
+				addr = "localhost" + addr
+			}
+			message := ""
+			if len(args) == 1 {
+				message = args[0]
+			}
+			body, _ := json.Marshal(map[string]string{"message": message})
+
+			httpClient := &http.Client{Timeout: 30 * time.Second}
+			resp, err := httpClient.Post(fmt.Sprintf("http://%s/admin/alerts/test", addr), "application/json", bytes.NewReader(body))
+			if err != nil {
+				return apperr.Wrap(apperr.CodeTransportUnavailable, err, "failed to reach bridge health server")
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode == http.StatusServiceUnavailable {
+				return apperr.New(apperr.CodeTransportUnavailable, "the bridge has no alert channels configured")
+			}
+
+			var event models.AlertEvent
+			if err := json.NewDecoder(resp.Body).Decode(&event); err != nil {
+				return fmt.Errorf("unexpected response from health server: %s", resp.Status)
+			}
+			if event.Status != constants.AlertStatusSent {
+				return apperr.New(apperr.CodeTransportUnavailable, "test alert not delivered: %s", event.Error)
+			}
+			fmt.Printf("Test alert sent via %s\n", event.Channels)
+			if event.Error != "" {
+				fmt.Printf("Some channels failed: %s\n", event.Error)
+			}
+			return nil
+		},
+	})
+
+	return alertsCmd
+}
+
+// newImportCmd 로봇/노드 템플릿/액션 템플릿 CSV 일괄 등록 (사이트 초기 구성용)
+func newImportCmd() *cobra.Command {
+	var dryRun, asJSON bool
+	importCmd := &cobra.Command{
+		Use:   "import <robots|nodes|actions> <file.csv|->",
+		Short: "CSV 일괄 등록 (행 오류가 하나라도 있으면 아무것도 저장하지 않음, --dry-run이면 검증만)",
+		Long: `스프레드시트에서 내보낸 CSV(쉼표, 세미콜론, 탭 구분)를 등록합니다. 첫 행은 헤더입니다.
+
+  robots:  serial_number, manufacturer, group, map_id, x, y, theta, allowed_deviation_xy, allowed_deviation_theta
+           (새 로봇은 승인 상태로 등록, 홈 위치 컬럼에 값이 있으면 로봇 기본 위치 저장)
+  nodes:   name, description, map_id, x, y, theta, allowed_deviation_xy, allowed_deviation_theta
+           (같은 이름의 노드 템플릿은 갱신)
+  actions: action_type, description, blocking_type, parameters
+           (parameters는 "key=value|key:NUMBER=value", 단계에 연결되지 않은 같은 액션은 갱신)`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var data []byte
+			var err error
+			if args[1] == "-" {
+				data, err = io.ReadAll(os.Stdin)
+			} else {
+				data, err = os.ReadFile(args[1])
+			}
+			if err != nil {
+				return err
+			}
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			report, err := api.Import(cmd.Context(), args[0], bytes.NewReader(data), dryRun)
+			if err != nil {
+				return err
+			}
+
+			if asJSON {
+				out, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(out))
+			} else {
+				switch {
+				case report.Committed:
+					fmt.Printf("Imported %d %s: %d created, %d updated\n", report.Rows, report.Kind, report.Created, report.Updated)
+				case report.Valid():
+					fmt.Printf("Dry run: %d %s valid (%d would be created, %d updated)\n", report.Rows, report.Kind, report.Created, report.Updated)
+				default:
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+					fmt.Fprintln(w, "ROW\tCOLUMN\tERROR")
+					for _, rowErr := range report.Errors {
+						column := rowErr.Column
+						if column == "" {
+							column = "-"
+						}
+						fmt.Fprintf(w, "%d\t%s\t%s\n", rowErr.Row, column, rowErr.Message)
+					}
+					if err := w.Flush(); err != nil {
+						return err
+					}
+				}
+			}
+			if !report.Valid() {
+				return apperr.New(apperr.CodeValidationFailed, "%s import has %d error(s), nothing was saved", report.Kind, len(report.Errors))
+			}
+			return nil
+		},
+	}
+	importCmd.Flags().BoolVar(&dryRun, "dry-run", false, "검증만 하고 저장하지 않음")
+	importCmd.Flags().BoolVar(&asJSON, "json", false, "결과를 JSON으로 출력")
+	return importCmd
+}
+
+// newSelfTestCmd 브릿지 의존성 자체 점검 (기본은 실행 중인 브릿지의 /admin/selftest, --local이면 이 도구가 직접 연결)
+func newSelfTestCmd() *cobra.Command {
+	var local, asJSON bool
+	cmd := &cobra.Command{
+		Use:   "selftest",
+		Short: "브로커 루프백, DB 스키마, Redis 지연, 전송 경로 자체 점검 (실패하면 종료 코드 1)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var report *selftest.Report
+			if local {
+				report = selftest.RunStandalone(cmd.Context(), cfg)
+			} else {
+				if cfg.HealthAddr == "" {
+					return apperr.New(apperr.CodeTransportUnavailable, "HEALTH_ADDR is not configured (use --local to run without a bridge)")
+				}
+				addr := cfg.HealthAddr
+				if strings.HasPrefix(addr, ":") {
+					addr = "localhost" + addr
+				}
+				httpClient := &http.Client{Timeout: 60 * time.Second}
+				resp, err := httpClient.Get(fmt.Sprintf("http://%s/admin/selftest", addr))
+				if err != nil {
+					return apperr.Wrap(apperr.CodeTransportUnavailable, err, "failed to reach bridge health server")
+				}
+				defer resp.Body.Close()
+				if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+					var failure apperr.Response
+					if err := json.NewDecoder(resp.Body).Decode(&failure); err != nil || failure.Message == "" {
+						return fmt.Errorf("unexpected status from health server: %s", resp.Status)
+					}
+					return apperr.New(failure.Code, "%s", failure.Message)
+				}
+				report = &selftest.Report{}
+				if err := json.NewDecoder(resp.Body).Decode(report); err != nil {
+					return err
+				}
+			}
+
+			if asJSON {
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				if err := encoder.Encode(report); err != nil {
+					return err
+				}
+			} else {
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "CHECK\tSTATUS\tLATENCY\tDETAIL")
+				for _, check := range report.Checks {
+					detail := check.Detail
+					if check.Error != "" {
+						detail = check.Error
+					}
+					fmt.Fprintf(w, "%s\t%s\t%dms\t%s\n", check.Name, check.Status, check.LatencyMs, detail)
+				}
+				if err := w.Flush(); err != nil {
+					return err
+				}
+			}
+			if !report.Passed() {
+				return apperr.New(apperr.CodeTransportUnavailable, "self-test failed")
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&local, "local", false, "실행 중인 브릿지 대신 이 도구가 설정대로 직접 연결하여 점검")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "보고서를 JSON으로 출력")
+	return cmd
+}
+
+// newLogsCmd 실행 중인 브릿지의 최근 로그 조회와 실시간 추적 (HEALTH_ADDR의 /admin/logs)
+func newLogsCmd() *cobra.Command {
+	var level, module string
+	var limit int
+	var follow, asJSON bool
+	logsCmd := &cobra.Command{
+		Use:   "logs",
+		Short: "실행 중인 브릿지의 최근 로그 조회 (--follow면 /admin/logs/stream으로 계속 출력)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.HealthAddr == "" {
+				return apperr.New(apperr.CodeTransportUnavailable, "HEALTH_ADDR is not configured")
+			}
+			addr := cfg.HealthAddr
+			if strings.HasPrefix(addr, ":") {
+				addr = "localhost" + addr
+			}
+			query := url.Values{}
+			if level != "" {
+				query.Set("level", level)
+			}
+			if module != "" {
+				query.Set("module", module)
+			}
+			printEntry := func(entry utils.LogEntry) {
+				if asJSON {
+					data, _ := json.Marshal(entry)
+					fmt.Println(string(data))
+					return
+				}
+				fmt.Println(entry.String())
+			}
+
+			if !follow {
+				query.Set("limit", strconv.Itoa(limit))
+				httpClient := &http.Client{Timeout: 5 * time.Second}
+				resp, err := httpClient.Get("http://" + addr + "/admin/logs?" + query.Encode())
+				if err != nil {
+					return apperr.Wrap(apperr.CodeTransportUnavailable, err, "failed to reach bridge health server")
+				}
+				defer resp.Body.Close()
+				if resp.StatusCode == http.StatusNotFound {
+					return apperr.New(apperr.CodeNotFound, "log buffer is disabled on the bridge (LOG_BUFFER_SIZE=0)")
+				}
+				if resp.StatusCode != http.StatusOK {
+					return fmt.Errorf("unexpected status from health server: %s", resp.Status)
+				}
+				var result struct {
+					Entries []utils.LogEntry `json:"entries"`
+				}
+				if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+					return err
+				}
+				for _, entry := range result.Entries {
+					printEntry(entry)
+				}
+				return nil
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			query.Set("backlog", strconv.Itoa(limit))
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+addr+"/admin/logs/stream?"+query.Encode(), nil)
+			if err != nil {
+				return err
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return apperr.Wrap(apperr.CodeTransportUnavailable, err, "failed to reach bridge health server")
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode == http.StatusNotFound {
+				return apperr.New(apperr.CodeNotFound, "log buffer is disabled on the bridge (LOG_BUFFER_SIZE=0)")
+			}
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("unexpected status from health server: %s", resp.Status)
+			}
+
+			event := ""
+			scanner := bufio.NewScanner(resp.Body)
+			scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+			for scanner.Scan() {
+				line := scanner.Text()
+				switch {
+				case strings.HasPrefix(line, "event: "):
+					event = strings.TrimPrefix(line, "event: ")
+				case strings.HasPrefix(line, "data: "):
+					if event == "dropped" {
+						return fmt.Errorf("log stream dropped by the bridge (client too slow)")
+					}
+					var entry utils.LogEntry
+					if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &entry); err == nil {
+						printEntry(entry)
+					}
+				case line == "":
+					event = ""
+				}
+			}
+			if ctx.Err() != nil {
+				return nil
+			}
+			return scanner.Err()
+		},
+	}
+	logsCmd.Flags().StringVar(&level, "level", "", "이 수준 이상만 (debug, info, warn, error)")
+	logsCmd.Flags().StringVar(&module, "module", "", "로그를 남긴 패키지 (쉼표 구분, 예: workflow,messaging)")
+	logsCmd.Flags().IntVar(&limit, "limit", 100, "출력할 최근 로그 수 (--follow면 먼저 보여줄 수)")
+	logsCmd.Flags().BoolVarP(&follow, "follow", "f", false, "새 로그를 계속 출력 (Ctrl+C로 종료)")
+	logsCmd.Flags().BoolVar(&asJSON, "json", false, "로그마다 JSON 한 줄로 출력")
+	return logsCmd
+}
+
+// newSubscriptionsCmd 실행 중인 브릿지의 MQTT 구독 조회/일시 중지/재개 명령 (HEALTH_ADDR의 /admin/subscriptions)
+func newSubscriptionsCmd() *cobra.Command {
+	subscriptionsCmd := &cobra.Command{Use: "subscriptions", Short: "실행 중인 브릿지의 MQTT 구독 관리"}
+
+	// call 관리 엔드포인트 호출 후 응답을 out에 디코딩
+	call := func(method, path string, body interface{}, out interface{}) error {
+		if cfg.HealthAddr == "" {
+			return apperr.New(apperr.CodeTransportUnavailable, "HEALTH_ADDR is not configured")
+		}
+		addr := cfg.HealthAddr
+		if strings.HasPrefix(addr, ":") {
+			addr = "localhost" + addr
+		}
+		var reader io.Reader
+		if body != nil {
+			data, err := json.Marshal(body)
+			if err != nil {
+				return err
+			}
+			reader = bytes.NewReader(data)
+		}
+		req, err := http.NewRequest(method, "http://"+addr+path, reader)
+		if err != nil {
+			return err
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		httpClient := &http.Client{Timeout: 10 * time.Second}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return apperr.Wrap(apperr.CodeTransportUnavailable, err, "failed to reach bridge health server")
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			var failure apperr.Response
+			if err := json.NewDecoder(resp.Body).Decode(&failure); err != nil || failure.Message == "" {
+				return fmt.Errorf("unexpected status from health server: %s", resp.Status)
+			}
+			return apperr.New(failure.Code, "%s", failure.Message)
+		}
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+
+	printSubscriptions := func(subscriptions []messaging.SubscriptionStats) error {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TOPIC\tQOS\tSTATE\tRECEIVED\tDROPPED\tLAST MESSAGE")
+		for _, s := range subscriptions {
+			state := "active"
+			if s.Paused {
+				state = "paused"
+				if s.PausedAt != nil {
+					state += " since " + s.PausedAt.Format(time.RFC3339)
+				}
+			}
+			last := "-"
+			if s.LastMessageAt != nil {
+				last = s.LastMessageAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%s\t%d\t%s\t%d\t%d\t%s\n", s.Topic, s.QoS, state, s.Received, s.Dropped, last)
+		}
+		return w.Flush()
+	}
+
+	subscriptionsCmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "구독별 상태와 수신 메시지 수",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var subscriptions []messaging.SubscriptionStats
+			if err := call(http.MethodGet, "/admin/subscriptions", nil, &subscriptions); err != nil {
+				return err
+			}
+			return printSubscriptions(subscriptions)
+		},
+	})
+
+	for _, action := range []struct{ name, short string }{
+		{"pause", "구독 일시 중지 (브로커 구독 해제, 재연결/재시작 후에도 유지)"},
+		{"resume", "일시 중지한 구독 재개"},
+	} {
+		subscriptionsCmd.AddCommand(&cobra.Command{
+			Use:   action.name + " <topic>",
+			Short: action.short,
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				var stats messaging.SubscriptionStats
+				body := map[string]string{"topic": args[0]}
+				if err := call(http.MethodPost, "/admin/subscriptions/"+action.name, body, &stats); err != nil {
+					return err
+				}
+				return printSubscriptions([]messaging.SubscriptionStats{stats})
+			},
+		})
+	}
+
+	return subscriptionsCmd
+}
+
+// newDiagnosticsCmd 실행 중인 브릿지의 진단 명령 (HEALTH_ADDR의 /admin/diagnostics)
+func newDiagnosticsCmd() *cobra.Command {
+	diagnosticsCmd := &cobra.Command{Use: "diagnostics", Short: "실행 중인 브릿지 진단"}
+
+	diagnosticsCmd.AddCommand(&cobra.Command{
+		Use:   "clock",
+		Short: "로봇 메시지, PLC 하트비트, DB 서버 시각과 브릿지 시계 사이 오차 (CLOCK_AUDIT_WINDOW, CLOCK_DRIFT_THRESHOLD_MS)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.HealthAddr == "" {
+				return apperr.New(apperr.CodeTransportUnavailable, "HEALTH_ADDR is not configured")
+			}
+			addr := cfg.HealthAddr
+			if strings.HasPrefix(addr, ":") {
+				addr = "localhost" + addr
+			}
+			httpClient := &http.Client{Timeout: 5 * time.Second}
+			resp, err := httpClient.Get("http://" + addr + "/admin/diagnostics/clock")
+			if err != nil {
+				return apperr.Wrap(apperr.CodeTransportUnavailable, err, "failed to reach bridge health server")
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode == http.StatusNotFound {
+				return apperr.New(apperr.CodeNotFound, "clock audit is disabled on the bridge (CLOCK_AUDIT_WINDOW=0)")
+			}
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("unexpected status from health server: %s", resp.Status)
+			}
+			var report diagnostics.ClockReport
+			if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+				return err
+			}
+
+			fmt.Printf("Bridge time: %s (threshold %.0fms, %d flagged)\n",
+				report.BridgeTime.Format(time.RFC3339Nano), report.ThresholdMs, report.Flagged)
+			if report.ProbeError != "" {
+				fmt.Printf("Database time unavailable: %s\n", report.ProbeError)
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "KIND\tDEVICE\tDRIFT(ms)\tLAST(ms)\tMAX(ms)\tSAMPLES\tLAST SEEN\tFLAG")
+			for _, device := range report.Devices {
+				flag := ""
+				if device.Flagged {
+					flag = "DRIFT"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%.0f\t%.0f\t%.0f\t%d\t%s\t%s\n", device.Kind, device.ID,
+					device.DriftMs, device.LastOffsetMs, device.MaxOffsetMs, device.Samples,
+					device.LastSeenAt.Local().Format(time.RFC3339), flag)
+			}
+			return w.Flush()
+		},
+	})
+	return diagnosticsCmd
+}
+
+// newReadOnlyCmd 실행 중인 브릿지의 읽기 전용 스위치 명령 (HEALTH_ADDR의 /admin/read-only)
+func newReadOnlyCmd() *cobra.Command {
+	readOnlyCmd := &cobra.Command{
+		Use:   "read-only",
+		Short: "읽기 전용 조회/켜기/끄기 (DB 페일오버, 브로커 점검 중 PLC 명령과 오더 전송, 관리 변경 요청 거부)",
+	}
+
+	// call 읽기 전용 엔드포인트 호출 후 상태 출력
+	call := func(method string, body interface{}) error {
+		if cfg.HealthAddr == "" {
+			return apperr.New(apperr.CodeTransportUnavailable, "HEALTH_ADDR is not configured")
+		}
+		addr := cfg.HealthAddr
+		if strings.HasPrefix(addr, ":") {
+			addr = "localhost" + addr
+		}
+		var reader io.Reader
+		if body != nil {
+			data, err := json.Marshal(body)
+			if err != nil {
+				return err
+			}
+			reader = bytes.NewReader(data)
+		}
+		req, err := http.NewRequest(method, "http://"+addr+"/admin/read-only", reader)
+		if err != nil {
+			return err
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		httpClient := &http.Client{Timeout: 5 * time.Second}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return apperr.Wrap(apperr.CodeTransportUnavailable, err, "failed to reach bridge health server")
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			var failure apperr.Response
+			if err := json.NewDecoder(resp.Body).Decode(&failure); err != nil || failure.Message == "" {
+				return fmt.Errorf("unexpected status from health server: %s", resp.Status)
+			}
+			return apperr.New(failure.Code, "%s", failure.Message)
+		}
+		var status readonly.Status
+		if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+			return err
+		}
+		if !status.Enabled {
+			fmt.Println("Read-only: off")
+			return nil
+		}
+		fmt.Printf("Read-only: on since %s (%s)\n", status.Since.Format(time.RFC3339), status.Reason)
+		return nil
+	}
+
+	readOnlyCmd.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "읽기 전용 상태",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return call(http.MethodGet, nil)
+		},
+	})
+	readOnlyCmd.AddCommand(&cobra.Command{
+		Use:   "on <reason>",
+		Short: "읽기 전용 켜기 (비상 정지와 오더 취소, 조회는 계속 허용)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return call(http.MethodPut, map[string]interface{}{"enabled": true, "reason": args[0]})
+		},
+	})
+	readOnlyCmd.AddCommand(&cobra.Command{
+		Use:   "off",
+		Short: "읽기 전용 끄기 (보류한 오더 단계와 아웃박스 메시지를 이어서 전송)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return call(http.MethodPut, map[string]interface{}{"enabled": false})
+		},
+	})
+
+	return readOnlyCmd
+}
+
+// newFaultsCmd 실행 중인 브릿지의 장애 주입 규칙 명령 (FAULT_INJECTION=true, HEALTH_ADDR의 /admin/faults)
+func newFaultsCmd() *cobra.Command {
+	faultsCmd := &cobra.Command{
+		Use:   "faults",
+		Short: "장애 주입 규칙 조회/설정 (스테이징 전용, 브릿지가 FAULT_INJECTION=true로 실행 중이어야 함)",
+	}
+
+	// call 장애 주입 엔드포인트 호출 후 대상별 상태 출력
+	call := func(method, target string, rule *faults.Rule) error {
+		if cfg.HealthAddr == "" {
+			return apperr.New(apperr.CodeTransportUnavailable, "HEALTH_ADDR is not configured")
+		}
+		addr := cfg.HealthAddr
+		if strings.HasPrefix(addr, ":") {
+			addr = "localhost" + addr
+		}
+		url := "http://" + addr + "/admin/faults"
+		if target != "" {
+			url += "/" + target
+		}
+		var body io.Reader
+		if rule != nil {
+			data, err := json.Marshal(rule)
+			if err != nil {
+				return err
+			}
+			body = bytes.NewReader(data)
+		}
+		req, err := http.NewRequest(method, url, body)
+		if err != nil {
+			return err
+		}
+		if rule != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		httpClient := &http.Client{Timeout: 5 * time.Second}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return apperr.Wrap(apperr.CodeTransportUnavailable, err, "failed to reach bridge health server")
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			var failure apperr.Response
+			if err := json.NewDecoder(resp.Body).Decode(&failure); err == nil && failure.Message != "" {
+				return apperr.New(failure.Code, "%s", failure.Message)
+			}
+			if resp.StatusCode == http.StatusNotFound {
+				return apperr.New(apperr.CodeNotFound, "fault injection is disabled on the bridge (FAULT_INJECTION=false)")
+			}
+			return fmt.Errorf("unexpected status from health server: %s", resp.Status)
+		}
+
+		var states []faults.TargetState
+		if err := json.NewDecoder(resp.Body).Decode(&states); err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TARGET\tFAILURE RATE\tLATENCY\tCALLS\tFAILED\tDELAYED")
+		for _, s := range states {
+			if s.Rule == nil {
+				fmt.Fprintf(w, "%s\t-\t-\t%d\t%d\t%d\n", s.Target, s.Calls, s.Failures, s.Delayed)
+				continue
+			}
+			latency := fmt.Sprintf("%dms", s.Rule.LatencyMs)
+			if s.Rule.JitterMs > 0 {
+				latency += fmt.Sprintf(" +%dms", s.Rule.JitterMs)
+			}
+			fmt.Fprintf(w, "%s\t%.2f\t%s\t%d\t%d\t%d\n", s.Target, s.Rule.FailureRate, latency, s.Calls, s.Failures, s.Delayed)
+		}
+		return w.Flush()
+	}
+
+	faultsCmd.AddCommand(&cobra.Command{
+		Use:   "show",
+		Short: "대상별 규칙과 주입 지표",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return call(http.MethodGet, "", nil)
+		},
+	})
+
+	var rule faults.Rule
+	var latency, jitter time.Duration
+	setCmd := &cobra.Command{
+		Use:   "set <target>",
+		Short: "대상에 지연/실패 주입 (" + strings.Join(faults.Targets(), ", ") + ")",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rule.LatencyMs = int(latency.Milliseconds())
+			rule.JitterMs = int(jitter.Milliseconds())
+			return call(http.MethodPut, args[0], &rule)
+		},
+	}
+	setCmd.Flags().Float64Var(&rule.FailureRate, "failure-rate", 0, "호출을 실패시킬 확률 (0~1)")
+	setCmd.Flags().DurationVar(&latency, "latency", 0, "호출마다 더할 지연 (예: 300ms)")
+	setCmd.Flags().DurationVar(&jitter, "jitter", 0, "지연에 더할 무작위 시간의 상한")
+	faultsCmd.AddCommand(setCmd)
+
+	faultsCmd.AddCommand(&cobra.Command{
+		Use:   "clear [target]",
+		Short: "규칙 제거 (대상을 생략하면 모두)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target := ""
+			if len(args) == 1 {
+				target = args[0]
+			}
+			return call(http.MethodDelete, target, nil)
+		},
+	})
+
+	return faultsCmd
+}
+
+// newRetentionCmd 실행 이력 보존/정리 명령
+func newRetentionCmd() *cobra.Command {
+	retentionCmd := &cobra.Command{Use: "retention", Short: "실행 이력 보존 정책과 정리"}
+
+	retentionCmd.AddCommand(&cobra.Command{
+		Use:   "policy",
+		Short: "설정된 보존 정책 출력 (RETENTION_*)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := json.MarshalIndent(retention.PolicyFromConfig(cfg), "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		},
+	})
+
+	var runsLimit int
+	runsCmd := &cobra.Command{
+		Use:   "runs",
+		Short: "최근 정리 실행 기록 (최신순)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+			runs, err := retention.ListRuns(db, cfg.SiteID, runsLimit)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tSTARTED\tTRIGGER\tDRY RUN\tSTATUS\tCOUNTS\tARCHIVE\tERROR")
+			for _, run := range runs {
+				archive := run.ArchivePath
+				if archive == "" {
+					archive = "-"
+				}
+				fmt.Fprintf(w, "%d\t%s\t%s\t%t\t%s\t%s\t%s\t%s\n",
+					run.ID, run.StartedAt.Format(time.RFC3339), run.Trigger, run.DryRun, run.Status, run.Counts, archive, run.Error)
+			}
+			return w.Flush()
+		},
+	}
+	runsCmd.Flags().IntVar(&runsLimit, "limit", 20, "최대 건수")
+	retentionCmd.AddCommand(runsCmd)
+
+	var dryRun bool
+	purgeCmd := &cobra.Command{
+		Use:   "purge",
+		Short: "보존 기간이 지난 실행 이력을 보관 후 삭제 (--dry-run이면 대상 건수만 집계)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			run, err := api.PurgeRetention(cmd.Context(), dryRun)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Purge run %d %s: %s\n", run.ID, run.Status, run.Counts)
+			if run.ArchivePath != "" {
+				fmt.Printf("Archived to %s\n", run.ArchivePath)
+			}
+			return nil
+		},
+	}
+	purgeCmd.Flags().BoolVar(&dryRun, "dry-run", false, "삭제하지 않고 대상 건수만 집계")
+	retentionCmd.AddCommand(purgeCmd)
+
+	return retentionCmd
+}
+
+// newTransportsCmd 오더 전송 경로 명령
+func newTransportsCmd() *cobra.Command {
+	transportsCmd := &cobra.Command{Use: "transports", Short: "오더 전송 경로 (mqtt, http)"}
+
+	var limit int
+	var raw bool
+	debugCmd := &cobra.Command{
+		Use:   "debug <mqtt|http>",
+		Short: "실행 중인 브릿지의 전송 요청/응답 기록 조회 (TRANSPORT_DEBUG로 켠 경로, HEALTH_ADDR의 /admin/transports)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.HealthAddr == "" {
+				return apperr.New(apperr.CodeTransportUnavailable, "HEALTH_ADDR is not configured")
+			}
+			addr := cfg.HealthAddr
+			if strings.HasPrefix(addr, ":") {
+				addr = "localhost" + addr
+			}
+
+			httpClient := &http.Client{Timeout: 5 * time.Second}
+			resp, err := httpClient.Get(fmt.Sprintf("http://%s/admin/transports/%s/debug?limit=%d", addr, args[0], limit))
+			if err != nil {
+				return apperr.Wrap(apperr.CodeTransportUnavailable, err, "failed to reach bridge health server")
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode == http.StatusNotFound {
+				return apperr.New(apperr.CodeNotFound, "debug capture is not enabled for transport %s", args[0]).WithField("transport")
+			}
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("unexpected status from health server: %s", resp.Status)
+			}
+
+			var report struct {
+				Total    int                         `json:"total"`
+				Captures []workflow.TransportCapture `json:"captures"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+				return err
+			}
+
+			fmt.Printf("Transport: %s (%d captured, showing %d)\n", args[0], report.Total, len(report.Captures))
+			for _, c := range report.Captures {
+				status := "OK"
+				if c.Error != "" {
+					status = "ERROR " + c.Error
+				}
+				fmt.Printf("\n%s %s %dms %s\n", c.Time.Format(time.RFC3339Nano), c.Topic, c.DurationMs, status)
+				if raw {
+					fmt.Printf("  request:  %s\n", c.Request)
+				} else {
+					fmt.Printf("  request:  %.200s\n", c.Request)
+				}
+				if c.Response != "" {
+					fmt.Printf("  response: %s\n", c.Response)
+				}
+			}
+			return nil
+		},
+	}
+	debugCmd.Flags().IntVar(&limit, "limit", 20, "최근 기록 건수")
+	debugCmd.Flags().BoolVar(&raw, "raw", false, "요청 페이로드를 자르지 않고 출력")
+	transportsCmd.AddCommand(debugCmd)
+
+	var transport, version string
+	transformCmd := &cobra.Command{
+		Use:   "transform <topic> <payload.json|->",
+		Short: "페이로드 변환 규칙(PAYLOAD_TRANSFORM_FILE) 적용 결과 미리 보기 (DB 없이 실행)",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.PayloadTransformFile == "" {
+				return apperr.Validation("PAYLOAD_TRANSFORM_FILE", "PAYLOAD_TRANSFORM_FILE is not configured")
+			}
+			transforms, err := workflow.LoadPayloadTransforms(cfg.PayloadTransformFile, cfg.SiteID)
+			if err != nil {
+				return err
+			}
+			var payload []byte
+			if args[1] == "-" {
+				payload, err = io.ReadAll(os.Stdin)
+			} else {
+				payload, err = os.ReadFile(args[1])
+			}
+			if err != nil {
+				return err
+			}
+
+			target := workflow.TransformTargetFromTopic(transport, args[0])
+			target.Version = version
+			transformed, applied, err := transforms.Apply(target, payload)
+			if err != nil {
+				return apperr.Wrap(apperr.CodeValidationFailed, err, "payload transform failed")
+			}
+			if len(applied) == 0 {
+				fmt.Fprintln(os.Stderr, "No rule matched; payload is published unchanged")
+			} else {
+				fmt.Fprintf(os.Stderr, "Applied rules: %s\n", strings.Join(applied, ", "))
+			}
+			var out bytes.Buffer
+			if err := json.Indent(&out, transformed, "", "  "); err != nil {
+				return err
+			}
+			fmt.Println(out.String())
+			return nil
+		},
+	}
+	transformCmd.Flags().StringVar(&transport, "transport", workflow.TransportMQTT, "전송 경로 (mqtt, http)")
+	transformCmd.Flags().StringVar(&version, "version", "", "로봇이 보고한 VDA 버전 (버전 조건 규칙 확인용)")
+	transportsCmd.AddCommand(transformCmd)
+
+	return transportsCmd
+}
+
+// newGraphQLCmd 대시보드 GraphQL 쿼리를 로컬에서 실행 (브릿지의 /graphql과 같은 스키마)
+func newGraphQLCmd() *cobra.Command {
+	var variables string
+	graphqlCmd := &cobra.Command{
+		Use:   "graphql <query|->",
+		Short: "대시보드 GraphQL 쿼리 실행 (-이면 표준 입력에서 읽음)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := args[0]
+			if query == "-" {
+				data, err := io.ReadAll(os.Stdin)
+				if err != nil {
+					return err
+				}
+				query = string(data)
+			}
+			req := graphql.Request{Query: query}
+			if variables != "" {
+				if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+					return apperr.Validation("variables", "variables must be a JSON object: %v", err)
+				}
+			}
+
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+			// Redis 없이도 DB 필드는 조회 가능 (Robot.state만 null)
+			redisConn, err := redis.NewRedisClient(cfg)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Redis unavailable, robot state will be null: %v\n", err)
+				redisConn = nil
+			} else {
+				defer redisConn.Close()
+			}
+
+			resp := graphql.NewDashboardSchema(db, redisConn, cfg.SiteID).Execute(cmd.Context(), req)
+			data, err := json.MarshalIndent(resp, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			if len(resp.Errors) > 0 {
+				return apperr.New(apperr.CodeValidationFailed, "query returned %d error(s)", len(resp.Errors))
+			}
+			return nil
+		},
+	}
+	graphqlCmd.Flags().StringVar(&variables, "variables", "", "쿼리 변수 (JSON 객체)")
+	return graphqlCmd
+}
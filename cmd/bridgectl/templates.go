@@ -0,0 +1,920 @@
+// cmd/bridgectl/templates.go
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/workflow"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// formatSyncValue 동기화 미리보기 값 (없는 값은 "-", 긴 문자열은 잘라서 표시)
+func formatSyncValue(v interface{}) string {
+	if v == nil {
+		return "-"
+	}
+	text := []rune(fmt.Sprintf("%v", v))
+	if len(text) > 60 {
+		return string(text[:57]) + "..."
+	}
+	return string(text)
+}
+
+// newTemplatesCmd 오더 템플릿 내보내기/가져오기 명령
+func newTemplatesCmd() *cobra.Command {
+	templatesCmd := &cobra.Command{Use: "templates", Short: "오더 템플릿 관리"}
+
+	var outputFile string
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "사이트의 활성 오더 템플릿을 JSON으로 내보내기",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+
+			exports, err := repository.ExportOrderTemplates(db, cfg.SiteID)
+			if err != nil {
+				return err
+			}
+			data, err := json.MarshalIndent(exports, "", "  ")
+			if err != nil {
+				return err
+			}
+
+			if outputFile == "" {
+				fmt.Println(string(data))
+				return nil
+			}
+			if err := os.WriteFile(outputFile, data, 0644); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Exported %d templates to %s\n", len(exports), outputFile)
+			return nil
+		},
+	}
+	exportCmd.Flags().StringVarP(&outputFile, "file", "f", "", "출력 파일 (기본: stdout)")
+
+	importCmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "JSON 파일의 오더 템플릿을 사이트에 가져오기",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			var exports []repository.TemplateExport
+			if err := json.Unmarshal(data, &exports); err != nil {
+				return fmt.Errorf("invalid template file: %w", err)
+			}
+
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+
+			// 하나라도 실패하면 아무것도 가져오지 않음
+			templates, err := api.ImportTemplates(cmd.Context(), exports)
+			if err != nil {
+				return err
+			}
+			for i, template := range templates {
+				fmt.Printf("Imported template %q (id: %d, steps: %d)\n", template.Name, template.ID, len(exports[i].Steps))
+			}
+			return nil
+		},
+	}
+
+	validateCmd := &cobra.Command{
+		Use:   "validate [templateId]",
+		Short: "오더 템플릿의 노드/엣지 그래프 검증 (기본: 사이트의 모든 템플릿)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+
+			var templateIDs []uint
+			if len(args) == 1 {
+				id, err := strconv.ParseUint(args[0], 10, 64)
+				if err != nil {
+					return apperr.Validation("templateID", "invalid template id: %s", args[0])
+				}
+				templateIDs = append(templateIDs, uint(id))
+			} else {
+				templates, err := repository.ListOrderTemplates(db, cfg.SiteID, false)
+				if err != nil {
+					return err
+				}
+				for _, template := range templates {
+					templateIDs = append(templateIDs, template.ID)
+				}
+			}
+
+			invalid := 0
+			for _, templateID := range templateIDs {
+				template, err := repository.LoadTemplateDetail(db, cfg.SiteID, templateID)
+				if err != nil {
+					return err
+				}
+
+				var graphErr *repository.GraphValidationError
+				if err := repository.ValidateTemplateGraph(template); errors.As(err, &graphErr) {
+					invalid++
+					fmt.Printf("✗ %s (id: %d)\n", template.Name, template.ID)
+					for _, problem := range graphErr.Problems {
+						fmt.Printf("    %s: %s\n      %s\n", problem.Code, problem.Message, strings.Join(problem.IDs, ", "))
+					}
+					continue
+				}
+				if err := repository.ValidateStepConditions(template); err != nil {
+					invalid++
+					fmt.Printf("✗ %s (id: %d)\n    %v\n", template.Name, template.ID, err)
+					continue
+				}
+				expanded, err := repository.ExpandTemplate(db, cfg.SiteID, template)
+				if err != nil {
+					invalid++
+					fmt.Printf("✗ %s (id: %d)\n    %v\n", template.Name, template.ID, err)
+					continue
+				}
+				if expanded != template {
+					fmt.Printf("✓ %s (id: %d, %d step(s) after expanding sub templates)\n", template.Name, template.ID, len(expanded.OrderSteps))
+					continue
+				}
+				fmt.Printf("✓ %s (id: %d)\n", template.Name, template.ID)
+			}
+
+			if invalid > 0 {
+				return fmt.Errorf("%d of %d templates are invalid", invalid, len(templateIDs))
+			}
+			return nil
+		},
+	}
+
+	var cloneDraft bool
+	cloneCmd := &cobra.Command{
+		Use:   "clone <templateId> <newName>",
+		Short: "오더 템플릿을 단계/액션/엣지까지 새 이름으로 복제",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return apperr.Validation("templateID", "invalid template id: %s", args[0])
+			}
+
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+
+			clone, err := api.CloneTemplate(cmd.Context(), uint(id), args[1], cloneDraft)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Cloned template %d as %q (id: %d, status: %s)\n", id, clone.Name, clone.ID, clone.Status)
+			return nil
+		},
+	}
+	cloneCmd.Flags().BoolVar(&cloneDraft, "draft", false, "복제본을 DRAFT 상태로 생성 (카나리 롤아웃의 새 버전용)")
+
+	statusCmd := &cobra.Command{
+		Use:   "status <templateId> <DRAFT|ACTIVE|DEPRECATED>",
+		Short: "오더 템플릿 활성화 상태 변경 (카나리 롤아웃 중인 템플릿은 제외)",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return apperr.Validation("templateID", "invalid template id: %s", args[0])
+			}
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			template, err := api.SetTemplateStatus(cmd.Context(), uint(id), strings.ToUpper(args[1]))
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Template %d is now %s\n", id, template.Status)
+			return nil
+		},
+	}
+
+	var includeDeleted bool
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "사이트의 오더 템플릿 목록 (--include-deleted면 보관된 템플릿 포함)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+			templates, err := repository.ListOrderTemplates(db, cfg.SiteID, includeDeleted)
+			if err != nil {
+				return err
+			}
+			for _, template := range templates {
+				archived := ""
+				if template.DeletedAt.Valid {
+					archived = " archived=" + template.DeletedAt.Time.Format(time.RFC3339)
+				}
+				fmt.Printf("%d\t%s\t%s%s\n", template.ID, template.Name, template.Status, archived)
+			}
+			return nil
+		},
+	}
+	listCmd.Flags().BoolVar(&includeDeleted, "include-deleted", false, "보관(soft delete)된 템플릿 포함")
+
+	restoreCmd := &cobra.Command{
+		Use:   "restore <templateId>",
+		Short: "보관된 오더 템플릿과 함께 보관된 스텝, 엣지 복구",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return apperr.Validation("templateID", "invalid template id: %s", args[0])
+			}
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			if err := api.RestoreTemplate(cmd.Context(), uint(id)); err != nil {
+				return err
+			}
+			fmt.Printf("Template %d restored\n", id)
+			return nil
+		},
+	}
+
+	purgeCmd := &cobra.Command{
+		Use:   "purge <templateId>",
+		Short: "보관된 오더 템플릿을 스텝, 엣지, 명령 매핑까지 영구 삭제",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return apperr.Validation("templateID", "invalid template id: %s", args[0])
+			}
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			if err := api.PurgeTemplate(cmd.Context(), uint(id)); err != nil {
+				return err
+			}
+			fmt.Printf("Template %d purged\n", id)
+			return nil
+		},
+	}
+
+	limitCmd := &cobra.Command{
+		Use:   "limit <templateId> <maxConcurrent>",
+		Short: "사이트 전체에서 템플릿 오더를 동시에 실행할 최대 로봇 수 설정 (0이면 제한 없음)",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return apperr.Validation("templateID", "invalid template id: %s", args[0])
+			}
+			limit, err := strconv.Atoi(args[1])
+			if err != nil {
+				return apperr.Validation("maxConcurrentExecutions", "invalid limit: %s", args[1])
+			}
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			if _, err := api.SetTemplateLimit(cmd.Context(), uint(id), limit); err != nil {
+				return err
+			}
+			if limit == 0 {
+				fmt.Printf("Template %d has no concurrency limit\n", id)
+			} else {
+				fmt.Printf("Template %d runs on at most %d robot(s) at once\n", id, limit)
+			}
+			return nil
+		},
+	}
+
+	deadlineCmd := &cobra.Command{
+		Use:   "deadline <templateId> <duration>",
+		Short: "템플릿 오더 실행 제한 시간 설정 (예: 15m, 지나면 로봇에서 취소하고 EXPIRED, 0이면 ORDER_DEADLINE_SECONDS)",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return apperr.Validation("templateID", "invalid template id: %s", args[0])
+			}
+			deadline, err := time.ParseDuration(args[1])
+			if err != nil {
+				return apperr.Validation("deadlineSeconds", "invalid deadline: %s", args[1])
+			}
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			if _, err := api.SetTemplateDeadline(cmd.Context(), uint(id), deadline); err != nil {
+				return err
+			}
+			if deadline < time.Second {
+				fmt.Printf("Template %d uses the site default deadline (ORDER_DEADLINE_SECONDS)\n", id)
+			} else {
+				fmt.Printf("Template %d orders expire %s after they start\n", id, deadline.Truncate(time.Second))
+			}
+			return nil
+		},
+	}
+
+	var syncFrom string
+	var syncApply []string
+	var syncApplyAll, syncDryRun bool
+	syncCmd := &cobra.Command{
+		Use:   "sync [--from <bridge-url>]",
+		Short: "다른 브릿지(예: 스테이징)와 템플릿/PLC 매핑 비교, --apply로 고른 변경 적용 (없으면 미리보기만)",
+		Long: `브릿지가 다른 브릿지의 /admin/sync/snapshot을 받아 이 사이트의 오더 템플릿(노드/엣지/액션 포함)과
+PLC 명령 매핑을 비교합니다. 변경은 template:<이름>, command:<명령 유형> 키로 표시됩니다.
+PLC 명령 정의는 모든 사이트가 함께 쓰므로 이 사이트 템플릿으로 가는 매핑만 바꾸고, 정의는 없을 때만 만듭니다.
+
+  +  원본에만 있음 (생성)
+  ~  내용이 다름 (원본 내용으로 교체, 실행 중인 오더나 롤아웃이 있으면 거부)
+  -  이쪽에만 있음 (템플릿은 보관, 명령은 이 사이트 매핑만 삭제)
+
+--apply template:pick,command:PICK 또는 --apply-all로 한 트랜잭션에 적용하며 --dry-run이면 적용해 보고 되돌립니다.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			diff, err := api.SyncDiff(cmd.Context(), syncFrom)
+			if err != nil {
+				return err
+			}
+
+			symbols := map[string]string{repository.SyncActionAdd: "+", repository.SyncActionUpdate: "~", repository.SyncActionRemove: "-"}
+			for _, change := range diff.Changes {
+				fmt.Printf("%s %s\n", symbols[change.Action], change.Key)
+				for _, field := range change.Fields {
+					fmt.Printf("    %s: %v -> %v\n", field.Path, formatSyncValue(field.From), formatSyncValue(field.To))
+				}
+			}
+			fmt.Printf("%d change(s), %d unchanged (source %s)\n", len(diff.Changes), diff.Unchanged, diff.Source)
+
+			keys := syncApply
+			if syncApplyAll {
+				keys = nil
+				for _, change := range diff.Changes {
+					keys = append(keys, change.Key)
+				}
+			}
+			if len(keys) == 0 {
+				return nil
+			}
+			result, err := api.ApplySync(cmd.Context(), diff.Source, keys, syncDryRun)
+			if err != nil {
+				return err
+			}
+			if result.DryRun {
+				fmt.Printf("Dry run: %d change(s) would be applied\n", len(result.Applied))
+			} else {
+				fmt.Printf("Applied %d change(s)\n", len(result.Applied))
+			}
+			return nil
+		},
+	}
+	syncCmd.Flags().StringVar(&syncFrom, "from", "", "원본 브릿지 HTTP 주소 (브릿지의 TEMPLATE_SYNC_SOURCES 중 하나, 하나만 있으면 생략 가능)")
+	syncCmd.Flags().StringSliceVar(&syncApply, "apply", nil, "적용할 변경 키 (쉼표 구분)")
+	syncCmd.Flags().BoolVar(&syncApplyAll, "apply-all", false, "모든 변경 적용")
+	syncCmd.Flags().BoolVar(&syncDryRun, "dry-run", false, "적용해 보고 되돌리기")
+
+	var notify repository.TemplateNotifications
+	notifyCmd := &cobra.Command{
+		Use:   "notify <templateId>",
+		Short: "템플릿 오더 종료 알림 설정 (order_finished 규칙: 알림 대상, 채널, 연속 실패 에스컬레이션)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return apperr.Validation("templateID", "invalid template id: %s", args[0])
+			}
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			template, err := api.SetTemplateNotifications(cmd.Context(), uint(id), notify)
+			if err != nil {
+				return err
+			}
+			channel := template.NotifyChannel
+			if channel == "" {
+				channel = "all channels"
+			}
+			fmt.Printf("Template %d notifies on %s via %s", id, template.NotifyOn, channel)
+			if template.NotifyEscalateAfter > 0 {
+				fmt.Printf(", escalating to CRITICAL on all channels after %d consecutive failures", template.NotifyEscalateAfter)
+			}
+			fmt.Println()
+			return nil
+		},
+	}
+	notifyCmd.Flags().StringVar(&notify.NotifyOn, "on", constants.TemplateNotifyFailure, "알림 대상 (FAILURE: 실패만, ALL: 완료 포함, NONE: 끔)")
+	notifyCmd.Flags().StringVar(&notify.Channel, "channel", "", "알림 채널 (slack, email, 비어 있으면 모든 채널)")
+	notifyCmd.Flags().IntVar(&notify.EscalateAfter, "escalate-after", 0, "연속 N번째 실패부터 CRITICAL로 모든 채널에 알림 (0이면 끔)")
+
+	var estimateRobot string
+	estimateCmd := &cobra.Command{
+		Use:   "estimate <templateId>",
+		Short: "과거 실행 이력으로 템플릿 오더의 예상 소요 시간과 90% 신뢰 구간 계산",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return apperr.Validation("templateID", "invalid template id: %s", args[0])
+			}
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+			estimate, err := repository.EstimateOrderDuration(db, cfg.SiteID, uint(id), estimateRobot)
+			if err != nil {
+				return err
+			}
+			if estimate.SampleSize == 0 {
+				fmt.Printf("Template %d has no completed executions to estimate from\n", id)
+				return nil
+			}
+			ms := func(v int64) time.Duration { return (time.Duration(v) * time.Millisecond).Round(time.Second) }
+			fmt.Printf("Template:  %d\n", estimate.TemplateID)
+			fmt.Printf("Basis:     %s (%d completed order(s))\n", estimate.Basis, estimate.SampleSize)
+			fmt.Printf("Expected:  %s\n", ms(estimate.ExpectedMs))
+			fmt.Printf("Interval:  %s - %s (%.0f%%)\n", ms(estimate.LowerMs), ms(estimate.UpperMs), estimate.Confidence*100)
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "\nSTEP\tSAMPLES\tEXPECTED")
+			for _, step := range estimate.Steps {
+				fmt.Fprintf(w, "%d\t%d\t%s\n", step.StepOrder, step.SampleSize, ms(step.ExpectedMs))
+			}
+			return w.Flush()
+		},
+	}
+	estimateCmd.Flags().StringVar(&estimateRobot, "robot", "", "로봇 시리얼 번호 (이력이 부족하면 템플릿 전체 이력 사용)")
+
+	var lintStrict bool
+	lintCmd := &cobra.Command{
+		Use:   "lint <templateId>",
+		Short: "오더 템플릿에 모범 사례 규칙 적용 (제한 시간 0, HARD 뒤 NONE 액션, 없는 FailureOrder 순번 등)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return apperr.Validation("templateID", "invalid template id: %s", args[0])
+			}
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+			report, err := repository.LintOrderTemplate(db, cfg.SiteID, uint(id))
+			if err != nil {
+				return err
+			}
+			if report.Clean() {
+				fmt.Printf("✓ %s (id: %d): no findings\n", report.TemplateName, report.TemplateID)
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "SEVERITY\tRULE\tSTEP\tMESSAGE")
+			for _, finding := range report.Findings {
+				step := "-"
+				if finding.StepOrder > 0 {
+					step = strconv.Itoa(finding.StepOrder)
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", finding.Severity, finding.Rule, step, finding.Message)
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			if report.Errors > 0 || (lintStrict && report.Warnings > 0) {
+				return fmt.Errorf("template %q has %d error(s) and %d warning(s)", report.TemplateName, report.Errors, report.Warnings)
+			}
+			return nil
+		},
+	}
+	lintCmd.Flags().BoolVar(&lintStrict, "strict", false, "경고가 있어도 실패로 종료 (CI용)")
+
+	templatesCmd.AddCommand(exportCmd, importCmd, validateCmd, cloneCmd, statusCmd, listCmd, restoreCmd, purgeCmd, limitCmd, deadlineCmd, syncCmd, notifyCmd, estimateCmd, lintCmd, newTemplateCanaryCmd(), newTemplateShadowCmd(), newTemplateGoldenCmd())
+	return templatesCmd
+}
+
+// newTemplateCanaryCmd 템플릿 카나리 롤아웃 명령
+func newTemplateCanaryCmd() *cobra.Command {
+	canaryCmd := &cobra.Command{Use: "canary", Short: "템플릿 새 버전을 일부 로봇에 먼저 적용하는 카나리 롤아웃"}
+
+	var robots []string
+	startCmd := &cobra.Command{
+		Use:   "start <baseTemplateId> <candidateTemplateId>",
+		Short: "ACTIVE 템플릿 대신 DRAFT 후보 버전을 --robots 로봇에만 적용",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			baseID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return apperr.Validation("baseTemplateID", "invalid template id: %s", args[0])
+			}
+			candidateID, err := strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				return apperr.Validation("candidateTemplateID", "invalid template id: %s", args[1])
+			}
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			rollout, err := api.StartTemplateRollout(cmd.Context(), uint(baseID), uint(candidateID), robots)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Started canary rollout %d: template %d -> %d on %s\n",
+				rollout.ID, rollout.BaseTemplateID, rollout.CandidateTemplateID, rollout.CanaryRobots)
+			return nil
+		},
+	}
+	startCmd.Flags().StringSliceVar(&robots, "robots", nil, "카나리 로봇 시리얼 번호 (쉼표로 구분)")
+	_ = startCmd.MarkFlagRequired("robots")
+
+	canaryCmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "롤아웃 목록",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+			rollouts, err := repository.ListTemplateRollouts(db, cfg.SiteID)
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tBASE\tCANDIDATE\tROBOTS\tSTATUS\tSTARTED")
+			for _, r := range rollouts {
+				fmt.Fprintf(w, "%d\t%d\t%d\t%s\t%s\t%s\n", r.ID, r.BaseTemplateID, r.CandidateTemplateID,
+					r.CanaryRobots, r.Status, r.StartedAt.Format(time.RFC3339))
+			}
+			return w.Flush()
+		},
+	})
+
+	canaryCmd.AddCommand(&cobra.Command{
+		Use:   "show <rolloutId>",
+		Short: "기존/후보 버전의 오더 성공률 비교",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseRolloutID(args[0])
+			if err != nil {
+				return err
+			}
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+			rollout, err := repository.FindTemplateRollout(db, cfg.SiteID, id)
+			if err != nil {
+				return err
+			}
+			report, err := repository.ComputeTemplateRolloutReport(db, cfg.SiteID, rollout)
+			if err != nil {
+				return err
+			}
+			return printRolloutReport(report)
+		},
+	})
+
+	var force bool
+	promoteCmd := &cobra.Command{
+		Use:   "promote <rolloutId>",
+		Short: "후보 버전을 ACTIVE로 승격하고 기존 버전의 매핑을 옮김 (카나리 성공률이 기존 이상일 때)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseRolloutID(args[0])
+			if err != nil {
+				return err
+			}
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			report, err := api.PromoteTemplateRollout(cmd.Context(), id, force)
+			if report != nil {
+				if printErr := printRolloutReport(report); printErr != nil {
+					return printErr
+				}
+			}
+			return err
+		},
+	}
+	promoteCmd.Flags().BoolVar(&force, "force", false, "성공률과 관계없이 승격")
+
+	canaryCmd.AddCommand(startCmd, promoteCmd, &cobra.Command{
+		Use:   "rollback <rolloutId>",
+		Short: "카나리를 중단하고 모든 로봇이 기존 버전을 사용하게 함",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseRolloutID(args[0])
+			if err != nil {
+				return err
+			}
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			report, err := api.RollbackTemplateRollout(cmd.Context(), id)
+			if err != nil {
+				return err
+			}
+			return printRolloutReport(report)
+		},
+	})
+	return canaryCmd
+}
+
+func parseRolloutID(arg string) (uint, error) {
+	id, err := strconv.ParseUint(arg, 10, 64)
+	if err != nil {
+		return 0, apperr.Validation("rolloutID", "invalid rollout id: %s", arg)
+	}
+	return uint(id), nil
+}
+
+// printRolloutReport 롤아웃 상태와 버전별 성공률 출력
+func printRolloutReport(report *repository.TemplateRolloutReport) error {
+	fmt.Printf("Rollout %d: %s (robots: %s)\n", report.Rollout.ID, report.Rollout.Status, report.Rollout.CanaryRobots)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tTEMPLATE\tSTATUS\tORDERS\tSUCCEEDED\tFAILED\tSUCCESS RATE")
+	for _, row := range []struct {
+		label string
+		stats repository.TemplateRunStats
+	}{{"base", report.Base}, {"candidate", report.Candidate}} {
+		fmt.Fprintf(w, "%s\t%s (%d)\t%s\t%d\t%d\t%d\t%.1f%%\n", row.label, row.stats.Name, row.stats.TemplateID,
+			row.stats.Status, row.stats.Total, row.stats.Succeeded, row.stats.Failed, row.stats.SuccessRate*100)
+	}
+	return w.Flush()
+}
+
+// newTemplateGoldenCmd 템플릿→오더 메시지 변환의 골든 픽스처 기록/비교 명령
+func newTemplateGoldenCmd() *cobra.Command {
+	goldenCmd := &cobra.Command{Use: "golden", Short: "골든 오더 메시지 픽스처 (OrderBuilder 회귀 검사)"}
+
+	var fixtureName string
+	var goldenCtx workflow.GoldenContext
+	recordCmd := &cobra.Command{
+		Use:   "record <templateId> <file>",
+		Short: "템플릿(하위 템플릿을 펼친 것)과 실행 조건으로 현재 오더 메시지를 픽스처 파일에 기록",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return apperr.Validation("templateID", "invalid template id: %s", args[0])
+			}
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+			template, err := repository.LoadExpandedTemplate(db, cfg.SiteID, uint(id))
+			if err != nil {
+				return err
+			}
+			if goldenCtx.Manufacturer == "" {
+				goldenCtx.Manufacturer = cfg.RobotManufacturer
+			}
+			if goldenCtx.SerialNumber == "" {
+				goldenCtx.SerialNumber = cfg.RobotSerialNumber
+			}
+			if fixtureName == "" {
+				fixtureName = template.Name
+			}
+			fixture, err := workflow.NewGoldenFixture(fixtureName, template, goldenCtx)
+			if err != nil {
+				return err
+			}
+			if err := workflow.SaveGoldenFixture(args[1], fixture); err != nil {
+				return err
+			}
+			fmt.Printf("Recorded %d golden message(s) for template %d to %s\n", len(fixture.Messages), id, args[1])
+			return nil
+		},
+	}
+	recordCmd.Flags().StringVar(&fixtureName, "name", "", "픽스처 이름 (기본: 템플릿 이름)")
+	recordCmd.Flags().StringVar(&goldenCtx.OrderID, "order-id", "golden-order", "메시지에 넣을 orderId")
+	recordCmd.Flags().StringVar(&goldenCtx.Manufacturer, "manufacturer", "", "manufacturer (기본: ROBOT_MANUFACTURER)")
+	recordCmd.Flags().StringVar(&goldenCtx.SerialNumber, "serial", "", "serialNumber (기본: ROBOT_SERIAL_NUMBER)")
+	recordCmd.Flags().StringToStringVarP(&goldenCtx.Parameters, "param", "p", nil, "템플릿 자리표시자 값 (예: -p pallet_id=P1)")
+	goldenCmd.AddCommand(recordCmd)
+
+	var update, asJSON bool
+	runCmd := &cobra.Command{
+		Use:   "run <file|dir>...",
+		Short: "픽스처를 DB 없이 렌더링하여 골든 메시지와 비교 (차이가 있으면 실패)",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			results, err := workflow.RunGoldenFixtures(args, update)
+			if err != nil {
+				return err
+			}
+			failed := 0
+			for _, result := range results {
+				if !result.Passed {
+					failed++
+				}
+			}
+			if asJSON {
+				data, _ := json.MarshalIndent(results, "", "  ")
+				fmt.Println(string(data))
+			} else {
+				for _, result := range results {
+					switch {
+					case result.Error != "":
+						fmt.Printf("ERROR  %s: %s\n", result.File, result.Error)
+					case result.Updated:
+						fmt.Printf("UPDATE %s (%d difference(s))\n", result.File, len(result.Diffs))
+					case result.Passed:
+						fmt.Printf("PASS   %s\n", result.File)
+					default:
+						fmt.Printf("FAIL   %s (%d difference(s))\n", result.File, len(result.Diffs))
+						for _, diff := range result.Diffs {
+							expected, _ := json.Marshal(diff.Expected)
+							actual, _ := json.Marshal(diff.Actual)
+							path := diff.Path
+							if path == "" {
+								path = "(message)"
+							}
+							fmt.Printf("       step %d %s\n         expected: %s\n         actual:   %s\n", diff.StepOrder, path, expected, actual)
+						}
+					}
+				}
+			}
+			if failed > 0 {
+				return apperr.New(apperr.CodeValidationFailed, "%d of %d golden fixture(s) failed", failed, len(results))
+			}
+			return nil
+		},
+	}
+	runCmd.Flags().BoolVar(&update, "update", false, "차이가 있으면 골든 메시지를 현재 출력으로 갱신")
+	runCmd.Flags().BoolVar(&asJSON, "json", false, "결과를 JSON으로 출력 (step_order, path, expected, actual)")
+	goldenCmd.AddCommand(runCmd)
+
+	return goldenCmd
+}
+
+// newTemplateShadowCmd 템플릿 섀도 실행(새 템플릿을 드라이런으로 나란히 실행해 메시지 비교) 명령
+func newTemplateShadowCmd() *cobra.Command {
+	shadowCmd := &cobra.Command{Use: "shadow", Short: "템플릿 오더마다 다른 템플릿을 드라이런하여 오더 메시지 비교 (워크플로 이전 검증)"}
+
+	parseIDs := func(args []string) ([]uint, error) {
+		ids := make([]uint, 0, len(args))
+		for _, arg := range args {
+			id, err := strconv.ParseUint(arg, 10, 64)
+			if err != nil {
+				return nil, apperr.Validation("templateID", "invalid template id: %s", arg)
+			}
+			ids = append(ids, uint(id))
+		}
+		return ids, nil
+	}
+
+	shadowCmd.AddCommand(&cobra.Command{
+		Use:   "set <templateId> <shadowTemplateId>",
+		Short: "templateId 오더가 끝날 때마다 shadowTemplateId를 드라이런하여 비교 (로봇에는 전송하지 않음)",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids, err := parseIDs(args)
+			if err != nil {
+				return err
+			}
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			if _, err := api.SetTemplateShadow(cmd.Context(), ids[0], ids[1]); err != nil {
+				return err
+			}
+			fmt.Printf("Template %d is now shadowed by template %d\n", ids[0], ids[1])
+			return nil
+		},
+	})
+
+	shadowCmd.AddCommand(&cobra.Command{
+		Use:   "off <templateId>",
+		Short: "섀도 실행 중지 (비교 기록은 유지)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids, err := parseIDs(args)
+			if err != nil {
+				return err
+			}
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			if err := api.RemoveTemplateShadow(cmd.Context(), ids[0]); err != nil {
+				return err
+			}
+			fmt.Printf("Shadow execution of template %d stopped\n", ids[0])
+			return nil
+		},
+	})
+
+	shadowCmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "섀도 설정 목록",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+			shadows, err := repository.ListTemplateShadows(db, cfg.SiteID)
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "TEMPLATE\tSHADOW\tSINCE")
+			for _, s := range shadows {
+				fmt.Fprintf(w, "%d\t%d\t%s\n", s.TemplateID, s.ShadowTemplateID, s.UpdatedAt.Format(time.RFC3339))
+			}
+			return w.Flush()
+		},
+	})
+
+	var compareLimit int
+	var showDiffs bool
+	compareCmd := &cobra.Command{
+		Use:   "compare <templateId> <otherTemplateId>",
+		Short: "실행별 실제 오더 메시지와 섀도 템플릿 메시지의 차이 (최신순)",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids, err := parseIDs(args)
+			if err != nil {
+				return err
+			}
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+			report, err := repository.CompareTemplateShadow(db, cfg.SiteID, ids[0], ids[1], compareLimit)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Template %d vs shadow %d (active: %t): %d execution(s), %d identical, %d divergent\n",
+				report.TemplateID, report.ShadowTemplateID, report.Active, report.Executions, report.Identical, report.Divergent)
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "\nORDER ID\tROBOT\tSTATUS\tDIFFS\tCOMPARED\tERROR")
+			for _, c := range report.Comparisons {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n", c.OrderID, c.SerialNumber, c.ExecutionStatus,
+					c.DiffCount, c.CreatedAt.Format(time.RFC3339), c.ShadowError)
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			if !showDiffs {
+				return nil
+			}
+			for _, c := range report.Comparisons {
+				if c.DiffCount == 0 {
+					continue
+				}
+				var diffs []workflow.ShadowDiff
+				if err := json.Unmarshal([]byte(c.Differences), &diffs); err != nil {
+					return err
+				}
+				fmt.Printf("\n%s:\n", c.OrderID)
+				for _, d := range diffs {
+					path := d.Path
+					if path == "" {
+						path = "(message)"
+					}
+					fmt.Printf("  step %d %s: %v -> %v\n", d.StepOrder, path, d.Primary, d.Shadow)
+				}
+			}
+			return nil
+		},
+	}
+	compareCmd.Flags().IntVar(&compareLimit, "limit", 20, "최대 실행 수")
+	compareCmd.Flags().BoolVar(&showDiffs, "diffs", false, "실행별 차이 경로 출력")
+	shadowCmd.AddCommand(compareCmd)
+	return shadowCmd
+}
@@ -0,0 +1,176 @@
+// cmd/bridgectl/zones.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// newZonesCmd 구역 점유 예약 조회와 강제 해제 명령 (HEALTH_ADDR의 /admin/zones)
+func newZonesCmd() *cobra.Command {
+	zonesCmd := &cobra.Command{Use: "zones", Short: "구역 점유 예약, 대기 로봇, 교착 조회와 예약 해제"}
+
+	var jsonOutput bool
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "사이트의 구역 예약과 대기 로봇 (교착 순환이 있으면 함께 출력)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			snapshot, err := api.Zones(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				data, err := json.MarshalIndent(snapshot, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ZONE\tROBOT\tORDER\tSTEP\tACQUIRED")
+			for _, r := range snapshot.Reservations {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", r.Zone, r.SerialNumber, r.OrderID, r.StepOrder, r.AcquiredAt.Format(time.RFC3339))
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			if len(snapshot.Waiters) > 0 {
+				fmt.Println()
+				w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "WAITING\tORDER\tSTEP\tZONES\tBLOCKED BY\tSINCE")
+				for _, waiter := range snapshot.Waiters {
+					blockers := make([]string, 0, len(waiter.BlockedBy))
+					for zone, serial := range waiter.BlockedBy {
+						blockers = append(blockers, zone+"="+serial)
+					}
+					sort.Strings(blockers)
+					fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\n", waiter.SerialNumber, waiter.OrderID, waiter.StepOrder,
+						strings.Join(waiter.Zones, ","), strings.Join(blockers, ","), waiter.Since.Format(time.RFC3339))
+				}
+				if err := w.Flush(); err != nil {
+					return err
+				}
+			}
+			for _, cycle := range snapshot.Deadlocks {
+				fmt.Printf("\nDEADLOCK: %s -> %s (release one of their zones with 'zones release <zone>')\n",
+					strings.Join(cycle, " -> "), cycle[0])
+			}
+			return nil
+		},
+	}
+	listCmd.Flags().BoolVar(&jsonOutput, "json", false, "JSON으로 출력")
+	zonesCmd.AddCommand(listCmd)
+
+	zonesCmd.AddCommand(&cobra.Command{
+		Use:   "release <zone>",
+		Short: "구역 예약 강제 해제 (교착을 풀거나 멈춘 브릿지가 남긴 예약 정리)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			reservation, err := api.ReleaseZone(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Zone %s released (was held by %s for order %s step %d)\n",
+				reservation.Zone, reservation.SerialNumber, reservation.OrderID, reservation.StepOrder)
+			return nil
+		},
+	})
+
+	return zonesCmd
+}
+
+// newMapsCmd 맵 메타데이터와 구역 관리 명령
+func newMapsCmd() *cobra.Command {
+	mapsCmd := &cobra.Command{Use: "maps", Short: "맵 및 지오펜스 구역 관리"}
+
+	mapsCmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "사이트의 맵과 구역 목록",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openReadDB()
+			if err != nil {
+				return err
+			}
+
+			maps, err := repository.ListRobotMaps(db, cfg.SiteID)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "MAP\tZONE\tTYPE\tMIN (X, Y)\tMAX (X, Y)")
+			for _, m := range maps {
+				fmt.Fprintf(w, "%s\t-\tBOUNDS\t(%.2f, %.2f)\t(%.2f, %.2f)\n", m.MapID, m.MinX, m.MinY, m.MaxX, m.MaxY)
+				for _, z := range m.Zones {
+					fmt.Fprintf(w, "%s\t%s\t%s\t(%.2f, %.2f)\t(%.2f, %.2f)\n", m.MapID, z.Name, z.ZoneType, z.MinX, z.MinY, z.MaxX, z.MaxY)
+				}
+			}
+			return w.Flush()
+		},
+	})
+
+	mapsCmd.AddCommand(&cobra.Command{
+		Use:   "apply <file>",
+		Short: "JSON 파일의 맵 정의를 저장 (같은 맵 ID는 구역까지 교체)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			var maps []models.RobotMap
+			if err := json.Unmarshal(data, &maps); err != nil {
+				return fmt.Errorf("invalid map file: %w", err)
+			}
+
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+
+			for _, m := range maps {
+				saved, err := api.SaveMap(cmd.Context(), m)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Saved map %s (zones: %d)\n", saved.MapID, len(saved.Zones))
+			}
+			return nil
+		},
+	})
+
+	mapsCmd.AddCommand(&cobra.Command{
+		Use:   "delete <mapId>",
+		Short: "맵과 구역 삭제",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := adminClient()
+			if err != nil {
+				return err
+			}
+			return api.DeleteMap(cmd.Context(), args[0])
+		},
+	})
+
+	return mapsCmd
+}
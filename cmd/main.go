@@ -3,10 +3,14 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"flag"
 	"mqtt-bridge/internal/bridge"
 	"mqtt-bridge/internal/config"
 	"mqtt-bridge/internal/database"
 	"mqtt-bridge/internal/redis"
+	"mqtt-bridge/internal/selftest"
+	"mqtt-bridge/internal/telemetry"
 	"mqtt-bridge/internal/utils"
 	"os"
 	"os/signal"
@@ -14,6 +18,9 @@ import (
 )
 
 func main() {
+	selfTest := flag.Bool("selftest", false, "의존성 자체 점검 후 보고서(JSON)를 출력하고 종료 (실패하면 종료 코드 1)")
+	flag.Parse()
+
 	// 설정 로드
 	cfg, err := config.Load()
 	if err != nil {
@@ -22,8 +29,18 @@ func main() {
 
 	// 로거 설정
 	utils.SetupLogger(cfg.LogLevel)
+
+	if *selfTest {
+		os.Exit(runSelfTest(cfg))
+	}
 	utils.Logger.Infof("🚀 Starting MQTT Bridge with streamlined architecture")
 
+	// 트레이싱 설정 (OTLP 엔드포인트가 없으면 비활성화)
+	shutdownTracing, err := telemetry.Setup(context.Background(), cfg)
+	if err != nil {
+		utils.Logger.Fatalf("Failed to set up tracing: %v", err)
+	}
+
 	// 데이터베이스 연결
 	db, err := database.NewPostgresDB(cfg)
 	if err != nil {
@@ -66,5 +83,28 @@ func main() {
 	// 브릿지 서비스 정리
 	bridgeService.Stop()
 
+	// 남은 스팬 전송
+	if err := shutdownTracing(context.Background()); err != nil {
+		utils.Logger.Errorf("Failed to flush traces: %v", err)
+	}
+
 	utils.Logger.Info("✅ Shutdown complete")
 }
+
+// runSelfTest 자체 점검 보고서를 표준 출력에 JSON으로 쓰고 종료 코드 반환 (로그는 표준 오류로 나감)
+func runSelfTest(cfg *config.Config) int {
+	utils.Logger.Infof("🩺 Running self-test")
+	report := selftest.RunStandalone(context.Background(), cfg)
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		utils.Logger.Errorf("Failed to write self-test report: %v", err)
+		return 1
+	}
+	if !report.Passed() {
+		utils.Logger.Errorf("❌ Self-test failed")
+		return 1
+	}
+	utils.Logger.Infof("✅ Self-test passed")
+	return 0
+}
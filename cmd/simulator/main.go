@@ -0,0 +1,78 @@
+// cmd/simulator/main.go
+package main
+
+import (
+	"context"
+	"fmt"
+	"mqtt-bridge/internal/common/idgen"
+	"mqtt-bridge/internal/config"
+	"mqtt-bridge/internal/simulator"
+	"mqtt-bridge/internal/utils"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	var opts simulator.Options
+	var failActions []string
+	var logLevel string
+
+	root := &cobra.Command{
+		Use:   "simulator",
+		Short: "VDA 5050 가상 로봇 (브릿지 End-to-End 테스트용)",
+		Long: "order/instantActions 토픽을 구독하여 액션을 RUNNING→FINISHED로 진행시키고\n" +
+			"state, connection(LWT 포함), factsheet 메시지를 발행합니다.\n" +
+			"브로커/로봇 식별자 기본값은 브릿지와 같은 환경 변수(MQTT_BROKER, ROBOT_SERIAL_NUMBER 등)를 사용합니다.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			utils.SetupLogger(logLevel)
+
+			opts.FailActionTypes = make(map[string]bool)
+			for _, actionType := range failActions {
+				if actionType = strings.TrimSpace(actionType); actionType != "" {
+					opts.FailActionTypes[actionType] = true
+				}
+			}
+
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+
+			sim := simulator.New(opts)
+			if err := sim.Start(ctx); err != nil {
+				return err
+			}
+			<-ctx.Done()
+			sim.Stop()
+			return nil
+		},
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	flags := root.Flags()
+	flags.StringVar(&opts.Broker, "broker", cfg.MQTTBroker, "MQTT 브로커 주소")
+	flags.StringVar(&opts.ClientID, "client-id", cfg.RobotSerialNumber+"_sim_"+idgen.NewGenerator().ShortID(), "MQTT 클라이언트 ID")
+	flags.StringVar(&opts.Username, "username", "", "MQTT 사용자 이름")
+	flags.StringVar(&opts.Password, "password", "", "MQTT 비밀번호")
+	flags.StringVar(&opts.Manufacturer, "manufacturer", cfg.RobotManufacturer, "로봇 제조사")
+	flags.StringVar(&opts.SerialNumber, "serial", cfg.RobotSerialNumber, "로봇 시리얼 번호")
+	flags.DurationVar(&opts.ActionDuration, "action-duration", time.Second, "액션 하나의 수행 시간")
+	flags.DurationVar(&opts.StateInterval, "state-interval", 5*time.Second, "주기적 state 발행 간격 (0이면 변화 시에만)")
+	flags.StringSliceVar(&failActions, "fail-action", nil, "FAILED로 끝낼 actionType (반복 가능)")
+	flags.StringVar(&opts.InferenceResult, "inference-result", `{"result":"ok"}`, "추론 액션 완료 시 resultDescription")
+	flags.StringVar(&logLevel, "log-level", "info", "로그 레벨")
+
+	if err := root.Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
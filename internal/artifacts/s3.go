@@ -0,0 +1,206 @@
+// internal/artifacts/s3.go
+package artifacts
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxPresignExpiry SigV4 pre-signed URL 최대 유효 시간
+const maxPresignExpiry = 7 * 24 * time.Hour
+
+// S3Store S3/MinIO 호환 저장소
+// SDK 없이 SigV4 query 서명(pre-signed URL)으로 업로드/다운로드하며, 경로 방식(endpoint/bucket/key) 주소를 사용합니다.
+type S3Store struct {
+	endpoint   *url.URL
+	bucket     string
+	region     string
+	accessKey  string
+	secretKey  string
+	httpClient *http.Client
+}
+
+// NewS3Store S3 저장소 생성
+func NewS3Store(endpoint, bucket, region, accessKey, secretKey string) (*S3Store, error) {
+	if endpoint == "" || bucket == "" {
+		return nil, fmt.Errorf("ARTIFACT_S3_ENDPOINT and ARTIFACT_S3_BUCKET are required for the s3 artifact store")
+	}
+	if accessKey == "" || secretKey == "" {
+		return nil, fmt.Errorf("ARTIFACT_S3_ACCESS_KEY and ARTIFACT_S3_SECRET_KEY are required for the s3 artifact store")
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", endpoint)
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &S3Store{
+		endpoint:   u,
+		bucket:     bucket,
+		region:     region,
+		accessKey:  accessKey,
+		secretKey:  secretKey,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+func (s *S3Store) Name() string { return "s3" }
+
+func (s *S3Store) Put(ctx context.Context, key, contentType string, data []byte) error {
+	signed, err := s.presign(http.MethodPut, key, 15*time.Minute, time.Now())
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, signed, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(len(data))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	_, err = s.do(req)
+	return err
+}
+
+func (s *S3Store) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	signed, err := s.presign(http.MethodGet, key, 15*time.Minute, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, signed, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.do(req)
+}
+
+func (s *S3Store) PresignedURL(key string, expiry time.Duration) (string, error) {
+	return s.presign(http.MethodGet, key, expiry, time.Now())
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	signed, err := s.presign(http.MethodDelete, key, 15*time.Minute, time.Now())
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, signed, nil)
+	if err != nil {
+		return err
+	}
+	body, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	return body.Close()
+}
+
+// do 요청을 보내고 2xx가 아니면 응답 본문을 포함한 오류 반환
+func (s *S3Store) do(req *http.Request) (io.ReadCloser, error) {
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 %s failed: %w", req.Method, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("s3 %s returned %s: %s", req.Method, resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return resp.Body, nil
+}
+
+// presign AWS SigV4 query 서명 URL 생성 (UNSIGNED-PAYLOAD, host 헤더만 서명)
+func (s *S3Store) presign(method, key string, expiry time.Duration, now time.Time) (string, error) {
+	if key == "" {
+		return "", fmt.Errorf("artifact key is required")
+	}
+	if expiry <= 0 || expiry > maxPresignExpiry {
+		return "", fmt.Errorf("presigned URL expiry must be between 1s and %s", maxPresignExpiry)
+	}
+
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	scope := date + "/" + s.region + "/s3/aws4_request"
+
+	canonicalURI := strings.TrimSuffix(s.endpoint.EscapedPath(), "/") + "/" + uriEncode(s.bucket, false) + "/" + uriEncode(key, true)
+
+	query := map[string]string{
+		"X-Amz-Algorithm":     "AWS4-HMAC-SHA256",
+		"X-Amz-Credential":    s.accessKey + "/" + scope,
+		"X-Amz-Date":          amzDate,
+		"X-Amz-Expires":       strconv.Itoa(int(expiry.Seconds())),
+		"X-Amz-SignedHeaders": "host",
+	}
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, uriEncode(name, false)+"="+uriEncode(query[name], false))
+	}
+	canonicalQuery := strings.Join(pairs, "&")
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		canonicalURI,
+		canonicalQuery,
+		"host:" + s.endpoint.Host + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	signingKey = hmacSHA256(signingKey, s.region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	return fmt.Sprintf("%s://%s%s?%s&X-Amz-Signature=%s",
+		s.endpoint.Scheme, s.endpoint.Host, canonicalURI, canonicalQuery, signature), nil
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// uriEncode SigV4 규칙의 URI 인코딩 (비예약 문자 외에는 %XX, keepSlash면 '/' 유지)
+func uriEncode(s string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && keepSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
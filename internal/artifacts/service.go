@@ -0,0 +1,193 @@
+// internal/artifacts/service.go
+package artifacts
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/utils"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MaxArtifactSize 결과물 하나의 최대 크기
+const MaxArtifactSize = 64 << 20
+
+// Service 오더 결과물 첨부/조회 서비스 (메타데이터는 DB, 본문은 Store)
+type Service struct {
+	db        *gorm.DB
+	store     Store
+	siteID    string
+	urlExpiry time.Duration
+}
+
+// NewService 결과물 서비스 생성
+func NewService(db *gorm.DB, store Store, siteID string, urlExpiry time.Duration) *Service {
+	return &Service{db: db, store: store, siteID: siteID, urlExpiry: urlExpiry}
+}
+
+// Attach 오더에 결과물 첨부 (같은 이름이 있으면 교체)
+func (s *Service) Attach(ctx context.Context, orderID, name, kind, contentType string, data []byte) (*models.OrderArtifact, error) {
+	artifact := &models.OrderArtifact{
+		OrderID:     orderID,
+		Name:        name,
+		Kind:        kind,
+		ContentType: contentType,
+	}
+	if err := s.attach(ctx, artifact, data); err != nil {
+		return nil, err
+	}
+	return artifact, nil
+}
+
+// attach 본문을 저장소에 올리고 메타데이터 저장
+func (s *Service) attach(ctx context.Context, artifact *models.OrderArtifact, data []byte) error {
+	if artifact.OrderID == "" {
+		return apperr.Validation("orderId", "order id is required")
+	}
+	if err := validateName(artifact.Name); err != nil {
+		return err
+	}
+	switch artifact.Kind {
+	case constants.ArtifactKindInferenceOutput, constants.ArtifactKindImage, constants.ArtifactKindLogReport, constants.ArtifactKindOther:
+	default:
+		return apperr.Validation("kind", "invalid artifact kind %q", artifact.Kind)
+	}
+	if len(data) > MaxArtifactSize {
+		return apperr.Validation("body", "artifact %s is too large (%d bytes, max %d)", artifact.Name, len(data), MaxArtifactSize)
+	}
+	if artifact.ContentType == "" {
+		artifact.ContentType = http.DetectContentType(data)
+	}
+
+	sum := sha256.Sum256(data)
+	artifact.SiteID = s.siteID
+	artifact.Size = int64(len(data))
+	artifact.SHA256 = hex.EncodeToString(sum[:])
+	artifact.StorageKey = s.storageKey(artifact.OrderID, artifact.Name)
+
+	if err := s.store.Put(ctx, artifact.StorageKey, artifact.ContentType, data); err != nil {
+		return fmt.Errorf("failed to store artifact %s for order %s: %w", artifact.Name, artifact.OrderID, err)
+	}
+	if err := repository.SaveOrderArtifact(s.db, artifact); err != nil {
+		return fmt.Errorf("failed to save artifact %s for order %s: %w", artifact.Name, artifact.OrderID, err)
+	}
+
+	utils.Logger.Infof("📎 Artifact %s attached to order %s (%s, %d bytes)",
+		artifact.Name, artifact.OrderID, artifact.Kind, artifact.Size)
+	return nil
+}
+
+// CaptureActionResults 완료된 추론 액션의 resultDescription을 INFERENCE_OUTPUT 결과물로 저장
+// 상태 메시지마다 호출되므로 이미 저장된 결과물은 건너뜁니다.
+func (s *Service) CaptureActionResults(ctx context.Context, orderID string, actionStates []models.ActionState) {
+	for _, action := range actionStates {
+		if action.ActionType != constants.ActionTypeInference ||
+			action.ActionStatus != constants.ActionStatusFinished ||
+			action.ResultDescription == "" {
+			continue
+		}
+
+		name := "inference-" + action.ActionID + ".json"
+		contentType := "application/json"
+		if !json.Valid([]byte(action.ResultDescription)) {
+			name = "inference-" + action.ActionID + ".txt"
+			contentType = "text/plain; charset=utf-8"
+		}
+
+		existing, err := repository.FindOrderArtifact(s.db, s.siteID, orderID, name)
+		if err != nil {
+			utils.Logger.Errorf("❌ Failed to check artifact %s for order %s: %v", name, orderID, err)
+			continue
+		}
+		if existing != nil {
+			continue
+		}
+
+		artifact := &models.OrderArtifact{
+			OrderID:     orderID,
+			Name:        name,
+			Kind:        constants.ArtifactKindInferenceOutput,
+			ContentType: contentType,
+			ActionID:    action.ActionID,
+		}
+		if err := s.attach(ctx, artifact, []byte(action.ResultDescription)); err != nil {
+			utils.Logger.Errorf("❌ Failed to capture inference output of action %s: %v", action.ActionID, err)
+		}
+	}
+}
+
+// List 오더의 결과물 목록
+func (s *Service) List(orderID string) ([]models.OrderArtifact, error) {
+	return repository.ListOrderArtifacts(s.db, s.siteID, orderID)
+}
+
+// Open 결과물 본문 열기
+func (s *Service) Open(ctx context.Context, orderID, name string) (*models.OrderArtifact, io.ReadCloser, error) {
+	artifact, err := s.find(orderID, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	body, err := s.store.Open(ctx, artifact.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open artifact %s for order %s: %w", name, orderID, err)
+	}
+	return artifact, body, nil
+}
+
+// URL 결과물 pre-signed 다운로드 URL (저장소가 지원하지 않으면 빈 문자열)
+func (s *Service) URL(orderID, name string) (string, error) {
+	artifact, err := s.find(orderID, name)
+	if err != nil {
+		return "", err
+	}
+	return s.store.PresignedURL(artifact.StorageKey, s.urlExpiry)
+}
+
+// Delete 결과물 메타데이터와 본문 삭제
+func (s *Service) Delete(ctx context.Context, orderID, name string) error {
+	artifact, err := s.find(orderID, name)
+	if err != nil {
+		return err
+	}
+	if err := s.store.Delete(ctx, artifact.StorageKey); err != nil {
+		return fmt.Errorf("failed to delete artifact %s for order %s: %w", name, orderID, err)
+	}
+	// 같은 이름으로 다시 첨부할 수 있도록 영구 삭제
+	return s.db.Unscoped().Delete(artifact).Error
+}
+
+func (s *Service) find(orderID, name string) (*models.OrderArtifact, error) {
+	artifact, err := repository.FindOrderArtifact(s.db, s.siteID, orderID, name)
+	if err != nil {
+		return nil, err
+	}
+	if artifact == nil {
+		return nil, apperr.New(apperr.CodeNotFound, "artifact %s not found for order %s", name, orderID).WithField("name")
+	}
+	return artifact, nil
+}
+
+// storageKey 저장소 키 (sites/<site>/orders/<orderId>/<name>)
+func (s *Service) storageKey(orderID, name string) string {
+	return path.Join("sites", s.siteID, "orders", orderID, name)
+}
+
+// validateName 결과물 이름 검증 (경로 구분자 불가)
+func validateName(name string) error {
+	if name == "" || name == "." || name == ".." || len(name) > 255 || strings.ContainsAny(name, "/\\") {
+		return apperr.Validation("name", "invalid artifact name %q", name)
+	}
+	return nil
+}
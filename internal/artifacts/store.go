@@ -0,0 +1,100 @@
+// internal/artifacts/store.go
+package artifacts
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mqtt-bridge/internal/config"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Store 결과물 본문 저장소
+type Store interface {
+	// Put 키에 본문 저장 (같은 키는 덮어씀)
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	// Open 키의 본문 열기
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// PresignedURL 인증 없이 내려받을 수 있는 URL (지원하지 않으면 빈 문자열)
+	PresignedURL(key string, expiry time.Duration) (string, error)
+	// Delete 키의 본문 삭제
+	Delete(ctx context.Context, key string) error
+	Name() string
+}
+
+// NewStore 설정에 맞는 결과물 저장소 생성
+func NewStore(cfg *config.Config) (Store, error) {
+	switch strings.ToLower(cfg.ArtifactStore) {
+	case "", "fs":
+		return NewFileStore(cfg.ArtifactDir)
+	case "s3", "minio":
+		return NewS3Store(cfg.ArtifactS3Endpoint, cfg.ArtifactS3Bucket, cfg.ArtifactS3Region,
+			cfg.ArtifactS3AccessKey, cfg.ArtifactS3SecretKey)
+	default:
+		return nil, fmt.Errorf("unknown artifact store %q (expected fs or s3)", cfg.ArtifactStore)
+	}
+}
+
+// FileStore 로컬 디렉터리 저장소 (단일 노드/개발용)
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore 디렉터리를 만들고 파일 저장소 생성
+func NewFileStore(dir string) (*FileStore, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("artifact directory is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create artifact directory %s: %w", dir, err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) Name() string { return "fs" }
+
+func (s *FileStore) Put(ctx context.Context, key, contentType string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func (s *FileStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+func (s *FileStore) PresignedURL(key string, expiry time.Duration) (string, error) {
+	return "", nil
+}
+
+func (s *FileStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// path 키를 저장 디렉터리 안의 경로로 변환 (디렉터리 밖으로 벗어나는 키는 거부)
+func (s *FileStore) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" {
+		return "", fmt.Errorf("invalid artifact key %q", key)
+	}
+	return filepath.Join(s.dir, clean), nil
+}
@@ -0,0 +1,176 @@
+// internal/breaker/breaker.go
+package breaker
+
+import (
+	"errors"
+	"fmt"
+	"mqtt-bridge/internal/utils"
+	"sync"
+	"time"
+)
+
+// 회로 차단기 상태
+const (
+	StateClosed   = "CLOSED"    // 정상 (호출 허용)
+	StateOpen     = "OPEN"      // 연속 실패로 차단 (즉시 실패)
+	StateHalfOpen = "HALF_OPEN" // 차단 시간이 지나 시험 호출 하나만 허용
+)
+
+// ErrOpen 차단기가 열려 호출을 거부했을 때의 오류 (errors.Is로 확인)
+var ErrOpen = errors.New("circuit breaker is open")
+
+// OpenError 차단된 의존성과 재시도 가능 시점
+type OpenError struct {
+	Name    string
+	RetryIn time.Duration
+}
+
+func (e *OpenError) Error() string {
+	return fmt.Sprintf("%s circuit breaker is open (retry in %s)", e.Name, e.RetryIn.Round(time.Second))
+}
+
+// Is errors.Is(err, ErrOpen) 지원
+func (e *OpenError) Is(target error) bool {
+	return target == ErrOpen
+}
+
+// Stats 차단기 상태와 누적 지표
+type Stats struct {
+	Name                string    `json:"name"`
+	State               string    `json:"state"`
+	StateSince          time.Time `json:"state_since"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	Failures            int64     `json:"failures"`
+	Rejected            int64     `json:"rejected"`
+	Opens               int64     `json:"opens"`
+	LastError           string    `json:"last_error,omitempty"`
+}
+
+// Breaker 연속 실패 횟수 기반 회로 차단기
+// threshold번 연속 실패하면 openTimeout 동안 호출을 즉시 거부하고, 이후 시험 호출 하나가
+// 성공하면 닫히고 실패하면 다시 열립니다.
+type Breaker struct {
+	name        string
+	threshold   int
+	openTimeout time.Duration
+
+	mu          sync.Mutex
+	state       string
+	stateSince  time.Time
+	consecutive int
+	probing     bool
+	failures    int64
+	rejected    int64
+	opens       int64
+	lastError   string
+}
+
+// New 새 회로 차단기 생성
+func New(name string, threshold int, openTimeout time.Duration) *Breaker {
+	if threshold <= 0 {
+		threshold = 5
+	}
+	if openTimeout <= 0 {
+		openTimeout = 30 * time.Second
+	}
+	return &Breaker{
+		name:        name,
+		threshold:   threshold,
+		openTimeout: openTimeout,
+		state:       StateClosed,
+		stateSince:  time.Now(),
+	}
+}
+
+// Name 차단기 이름
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// Allow 호출 허용 여부 확인 (허용되면 결과를 Success/Failure로 반드시 보고)
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if wait := b.openTimeout - time.Since(b.stateSince); wait > 0 {
+			b.rejected++
+			return &OpenError{Name: b.name, RetryIn: wait}
+		}
+		b.setState(StateHalfOpen)
+		b.probing = true
+		return nil
+	case StateHalfOpen:
+		if b.probing {
+			b.rejected++
+			return &OpenError{Name: b.name}
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Success 허용된 호출의 성공 보고
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.consecutive = 0
+	if b.state == StateHalfOpen {
+		b.probing = false
+		b.setState(StateClosed)
+		utils.Logger.Infof("🔌 %s circuit breaker closed", b.name)
+	}
+}
+
+// Failure 허용된 호출의 인프라 실패 보고
+func (b *Breaker) Failure(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.consecutive++
+	if err != nil {
+		b.lastError = err.Error()
+	}
+
+	switch {
+	case b.state == StateHalfOpen:
+		b.probing = false
+		b.open()
+	case b.state == StateClosed && b.consecutive >= b.threshold:
+		b.open()
+	}
+}
+
+// Stats 현재 상태와 지표
+func (b *Breaker) Stats() Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return Stats{
+		Name:                b.name,
+		State:               b.state,
+		StateSince:          b.stateSince,
+		ConsecutiveFailures: b.consecutive,
+		Failures:            b.failures,
+		Rejected:            b.rejected,
+		Opens:               b.opens,
+		LastError:           b.lastError,
+	}
+}
+
+// open 차단 상태로 전환 (mu 보유 상태에서 호출)
+func (b *Breaker) open() {
+	b.opens++
+	b.setState(StateOpen)
+	utils.Logger.Warnf("🔌 %s circuit breaker opened after %d consecutive failures (last: %s); failing fast for %s",
+		b.name, b.consecutive, b.lastError, b.openTimeout)
+}
+
+func (b *Breaker) setState(state string) {
+	b.state = state
+	b.stateSince = time.Now()
+}
@@ -0,0 +1,164 @@
+// internal/breaker/hooks.go
+package breaker
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+const gormAllowedKey = "breaker:allowed"
+
+// GormPlugin 모든 gorm 연산을 차단기로 감싸는 플러그인
+// 차단기가 열려 있으면 트랜잭션/커넥션을 잡기 전에 ErrOpen으로 즉시 실패합니다.
+type GormPlugin struct {
+	Breaker *Breaker
+}
+
+// Name 플러그인 이름
+func (GormPlugin) Name() string {
+	return "breaker"
+}
+
+// Initialize 연산별 첫/마지막 콜백 등록
+func (p GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	registrations := []error{
+		cb.Create().Before("*").Register("breaker:before_create", p.before),
+		cb.Create().After("*").Register("breaker:after_create", p.after),
+		cb.Query().Before("*").Register("breaker:before_query", p.before),
+		cb.Query().After("*").Register("breaker:after_query", p.after),
+		cb.Update().Before("*").Register("breaker:before_update", p.before),
+		cb.Update().After("*").Register("breaker:after_update", p.after),
+		cb.Delete().Before("*").Register("breaker:before_delete", p.before),
+		cb.Delete().After("*").Register("breaker:after_delete", p.after),
+		cb.Row().Before("*").Register("breaker:before_row", p.before),
+		cb.Row().After("*").Register("breaker:after_row", p.after),
+		cb.Raw().Before("*").Register("breaker:before_raw", p.before),
+		cb.Raw().After("*").Register("breaker:after_raw", p.after),
+	}
+	for _, err := range registrations {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p GormPlugin) before(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	if err := p.Breaker.Allow(); err != nil {
+		db.AddError(err)
+		return
+	}
+	db.InstanceSet(gormAllowedKey, true)
+}
+
+func (p GormPlugin) after(db *gorm.DB) {
+	if _, ok := db.InstanceGet(gormAllowedKey); !ok {
+		return
+	}
+	if isPostgresFailure(db.Error) {
+		p.Breaker.Failure(db.Error)
+		return
+	}
+	p.Breaker.Success()
+}
+
+// isPostgresFailure 연결/서버 가용성 오류인지 판단 (없는 행, 제약 조건 위반 등은 정상 응답으로 취급)
+func isPostgresFailure(err error) bool {
+	if err == nil || errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, ErrOpen) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// 08: 연결 예외, 53: 자원 부족, 57P: 서버 종료/재시작
+		return strings.HasPrefix(pgErr.Code, "08") || strings.HasPrefix(pgErr.Code, "53") || strings.HasPrefix(pgErr.Code, "57P")
+	}
+	return isConnectionError(err)
+}
+
+// RedisHook 모든 Redis 명령을 차단기로 감싸는 go-redis 훅
+type RedisHook struct {
+	Breaker *Breaker
+}
+
+var _ redis.Hook = RedisHook{}
+
+type redisAllowedKey struct{}
+
+// BeforeProcess 차단기가 열려 있으면 명령을 보내지 않고 실패
+func (h RedisHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	if err := h.Breaker.Allow(); err != nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, redisAllowedKey{}, true), nil
+}
+
+// AfterProcess 명령 결과 보고
+func (h RedisHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	h.report(ctx, cmd.Err())
+	return nil
+}
+
+// BeforeProcessPipeline 파이프라인도 명령 하나로 취급
+func (h RedisHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	return h.BeforeProcess(ctx, nil)
+}
+
+// AfterProcessPipeline 파이프라인 중 인프라 실패가 하나라도 있으면 실패로 보고
+func (h RedisHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	var err error
+	for _, cmd := range cmds {
+		if isRedisFailure(cmd.Err()) {
+			err = cmd.Err()
+			break
+		}
+	}
+	h.report(ctx, err)
+	return nil
+}
+
+func (h RedisHook) report(ctx context.Context, err error) {
+	if ctx.Value(redisAllowedKey{}) == nil {
+		return
+	}
+	if isRedisFailure(err) {
+		h.Breaker.Failure(err)
+		return
+	}
+	h.Breaker.Success()
+}
+
+// isRedisFailure 연결 오류인지 판단 (redis.Nil과 서버 오류 응답은 정상 응답으로 취급)
+func isRedisFailure(err error) bool {
+	if err == nil || err == redis.Nil || errors.Is(err, ErrOpen) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var replyErr redis.Error
+	if errors.As(err, &replyErr) {
+		// 서버가 보낸 오류 응답 (WRONGTYPE 등)은 가용성 문제가 아님
+		return strings.HasPrefix(err.Error(), "LOADING") || strings.HasPrefix(err.Error(), "MASTERDOWN")
+	}
+	return isConnectionError(err) || strings.HasPrefix(err.Error(), "redis: connection pool timeout")
+}
+
+// isConnectionError 네트워크/연결 수준 오류
+func isConnectionError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, net.ErrClosed)
+}
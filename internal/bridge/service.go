@@ -3,13 +3,33 @@ package bridge
 
 import (
 	"context"
+	"mqtt-bridge/internal/artifacts"
+	"mqtt-bridge/internal/breaker"
 	"mqtt-bridge/internal/command"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/common/idgen"
+	"mqtt-bridge/internal/common/readonly"
 	"mqtt-bridge/internal/config"
+	"mqtt-bridge/internal/diagnostics"
+	"mqtt-bridge/internal/faults"
+	"mqtt-bridge/internal/graphql"
+	"mqtt-bridge/internal/grpcapi"
+	"mqtt-bridge/internal/health"
+	"mqtt-bridge/internal/janitor"
 	"mqtt-bridge/internal/messaging"
+	"mqtt-bridge/internal/notifier"
+	"mqtt-bridge/internal/replay"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/retention"
 	"mqtt-bridge/internal/robot"
+	"mqtt-bridge/internal/selftest"
+	"mqtt-bridge/internal/sequence"
+	"mqtt-bridge/internal/sink"
 	"mqtt-bridge/internal/utils"
 	"mqtt-bridge/internal/workflow"
+	"mqtt-bridge/internal/zones"
 
+	mqtt "github.com/eclipse/paho.mqtt.golang"
 	redisClient "github.com/go-redis/redis/v8"
 	"gorm.io/gorm"
 )
@@ -24,39 +44,450 @@ type Service struct {
 	router         *messaging.Router
 	commandHandler command.CommandHandler
 	robotHandler   *robot.Handler
+	executor       *workflow.Executor
+	stateCache     *robot.StateCache
+	stateWriter    *robot.StateWriter // INGEST_STATE_FLUSH_MS가 0이면 nil
+	ingestPool     *messaging.IngestPool
+	stateSink      sink.StateSink
+	recorder       *replay.Recorder
+	healthServer   *health.Server
+	grpcServer     *grpcapi.Server    // GRPC_ADDR가 비어 있으면 nil
+	purger         *retention.Purger  // 보존 기간이 설정되지 않으면 nil
+	notifier       *notifier.Notifier // 알림 채널이 설정되지 않으면 nil
+	discovery      *robot.Discovery   // ROBOT_DISCOVERY가 꺼져 있으면 nil
+	janitor        *janitor.Janitor   // 주기가 0이면 시작하지 않음
+	jobRunner      *workflow.JobRunner
+	deadlines      *workflow.DeadlineMonitor
+	clockAudit     *diagnostics.ClockAudit // CLOCK_AUDIT_WINDOW가 0이면 nil
 }
 
-// NewService 새 브릿지 서비스 생성
-func NewService(db *gorm.DB, redisClient *redisClient.Client, cfg *config.Config) (*Service, error) {
-	utils.Logger.Infof("🏗️ CREATING Bridge Service")
+// HandlerChain MQTT 메시지를 처리하는 라우터와 도메인 핸들러 묶음
+type HandlerChain struct {
+	Router         *messaging.Router
+	Executor       *workflow.Executor
+	CommandHandler command.CommandHandler
+	RobotHandler   *robot.Handler
+	StateCache     *robot.StateCache  // STATE_CACHE_FLUSH_MS가 0이면 nil
+	StateWriter    *robot.StateWriter // INGEST_STATE_FLUSH_MS가 0이면 nil
+	Transports     []workflow.Transport
+	StatusMap      *messaging.PLCStatusMap
+	CommandDedup   *command.Deduplicator     // PLC_DEDUP_WINDOW_MS가 0이면 nil
+	Zones          *zones.Coordinator        // 구역 예약 조회/해제 (ZONE_RESERVATIONS가 꺼져 있어도 조회 가능)
+	Artifacts      *artifacts.Service        // 오더 결과물 첨부/조회
+	ReadOnly       *readonly.Switch          // 읽기 전용 스위치 (READ_ONLY로 켠 상태로 시작 가능)
+	Latency        *messaging.LatencyTracker // STATE_LATENCY_WINDOW가 0이면 nil
+	Preflight      *workflow.Preflight       // PREFLIGHT_CHECKS가 비어 있으면 nil
+	ClockAudit     *diagnostics.ClockAudit   // CLOCK_AUDIT_WINDOW가 0이면 nil
+}
 
-	mqttClient, err := messaging.NewMQTTClient(cfg)
+// NewHandlerChain 주어진 MQTT 클라이언트로 라우터와 도메인 핸들러를 구성합니다.
+// 브릿지 서비스와 리플레이(브로커 없는 CaptureClient)에서 같은 구성을 사용합니다.
+func NewHandlerChain(db *gorm.DB, redisClient *redisClient.Client, cfg *config.Config, mqttClient mqtt.Client) (*HandlerChain, error) {
+	plcCodec, err := messaging.NewPLCCodec(cfg.PlcCodec)
+	if err != nil {
+		return nil, err
+	}
+	statusMap, err := messaging.PLCStatusMapFromConfig(plcCodec.Name(), cfg.PlcStatusMap, cfg.PlcStatusMapFile)
 	if err != nil {
 		return nil, err
 	}
-	plcSender := messaging.NewPLCResponseSender(mqttClient.GetNativeClient(), cfg.PlcResponseTopic)
+	plcSender := messaging.NewPLCResponseSender(mqttClient, cfg.PlcResponseTopic)
+	plcSender.SetCodec(plcCodec)
+	plcSender.SetStatusMap(statusMap)
+	plcSender.SetRecorder(repository.NewPLCResponseLog(db, cfg.SiteID))
 
 	// --- Domain Dependencies ---
-	robotStatusManager := robot.NewStatusManager(db)
+	robotStatusManager := robot.NewStatusManager(db, cfg.SiteID)
 	robotFactsheetManager := robot.NewFactsheetManager(db)
 
 	workflowExecutor := workflow.NewExecutor(
-		db, redisClient, mqttClient.GetNativeClient(), cfg, plcSender,
+		db, redisClient, mqttClient, cfg, plcSender,
 	)
 
 	commandHandler := command.NewHandler(
 		db, cfg, plcSender, workflowExecutor, robotStatusManager,
 	)
 
+	transports, err := workflow.NewTransports(cfg, mqttClient)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.PayloadTransformFile != "" {
+		transforms, err := workflow.LoadPayloadTransforms(cfg.PayloadTransformFile, cfg.SiteID)
+		if err != nil {
+			return nil, err
+		}
+		transports = workflow.WithPayloadTransforms(transports, transforms, db, cfg.SiteID)
+		utils.Logger.Infof("✅ Payload transforms loaded from %s (%d rules)", cfg.PayloadTransformFile, len(transforms.Rules()))
+	}
+	payloadPolicy, err := workflow.NewOrderPayloadPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+	transports = workflow.WithOrderPayloadPolicy(transports, payloadPolicy)
+	workflowExecutor.SetTransports(transports)
+	if payloadPolicy != nil {
+		workflowExecutor.SetOrderPayloadPolicy(payloadPolicy)
+	}
+
+	commandHandler.SetPLCCodec(plcCodec)
+	readOnly := readonly.New(cfg.ReadOnly, cfg.ReadOnlyReason)
+	commandHandler.SetReadOnly(readOnly)
+	workflowExecutor.SetReadOnly(readOnly)
+	var commandDedup *command.Deduplicator
+	if cfg.PlcDedupWindow > 0 {
+		commandDedup = command.NewDeduplicator(cfg.PlcDedupWindow, cfg.PlcDedupByPayload)
+		commandHandler.SetDeduplicator(commandDedup)
+	}
 	workflowExecutor.SetCommandHandler(commandHandler)
 
+	artifactStore, err := artifacts.NewStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+	artifactService := artifacts.NewService(db, artifactStore, cfg.SiteID, cfg.ArtifactURLExpiry)
+	workflowExecutor.SetArtifactService(artifactService)
+
+	zoneCoordinator := zones.NewCoordinator(redisClient, cfg.SiteID, cfg.ZoneReservationTTL)
+	if cfg.ZoneReservations {
+		workflowExecutor.SetZoneCoordinator(zoneCoordinator)
+	}
+
+	preflight, err := workflow.NewPreflight(db, cfg.SiteID, cfg.PreflightChecks)
+	if err != nil {
+		return nil, err
+	}
+	if preflight != nil {
+		workflowExecutor.SetPreflight(preflight)
+	}
+
 	robotHandler := robot.NewHandler(
-		robotStatusManager, robotFactsheetManager, commandHandler, mqttClient.GetNativeClient(),
+		robotStatusManager, robotFactsheetManager, commandHandler, mqttClient,
 	)
 
-	// --- Messaging ---
+	robotHandler.SetInitPositionFromHome(cfg.InitPositionFromHome)
+	workflowExecutor.SetFactsheetManager(robotFactsheetManager)
+	robotHandler.SetInstantActionsPublish(cfg.InstantActionsQoS, cfg.InstantActionsRetained)
+	robotHandler.SetInstantActionValidator(robot.NewInstantActionValidator(cfg.InstantActionAllowlist))
+	factsheetPolicy := cfg.SendPolicy(workflow.TransportMQTT, constants.ActionTypeFactsheetRequest)
+	robotHandler.SetFactsheetSendPolicy(factsheetPolicy.Timeout, factsheetPolicy.Retries, cfg.TransportRetryBackoff)
+
+	if cfg.VdaCompatibilityMode != robot.CompatibilityModeOff {
+		compatibility, err := robot.NewCompatibilityGate(db, cfg.SiteID, cfg.VdaCompatibilityMode, cfg.VdaFeatureVersions)
+		if err != nil {
+			return nil, err
+		}
+		workflowExecutor.SetCompatibilityGate(compatibility)
+		robotHandler.SetCompatibilityGate(compatibility)
+	}
+
+	var stateCache *robot.StateCache
+	if cfg.StateCacheFlushInterval > 0 {
+		stateCache = robot.NewStateCache(redisClient, cfg.StateCacheFlushInterval)
+		robotHandler.SetStateCache(stateCache)
+	}
+
+	var stateWriter *robot.StateWriter
+	if cfg.IngestStateFlushInterval > 0 {
+		stateWriter = robot.NewStateWriter(db, cfg.SiteID, cfg.IngestStateWorkers, cfg.IngestStateFlushInterval)
+		robotHandler.SetStateWriter(stateWriter)
+	}
+
 	router := messaging.NewRouter(commandHandler, robotHandler, workflowExecutor)
+	if cfg.PayloadSchemaMode != messaging.SchemaModeOff {
+		validator, err := messaging.NewSchemaValidator(cfg.PayloadSchemaMode)
+		if err != nil {
+			return nil, err
+		}
+		router.SetSchemaValidator(validator)
+	}
+	if cfg.MessageMaxSkew > 0 || cfg.DropStaleStates {
+		router.SetFreshnessGuard(messaging.NewFreshnessGuard(cfg.MessageMaxSkew, cfg.DropStaleStates))
+	}
+	var latency *messaging.LatencyTracker
+	if cfg.StateLatencyWindow > 0 {
+		latency = messaging.NewLatencyTracker(cfg.StateLatencyWindow)
+		router.SetLatencyTracker(latency)
+	}
+	var clockAudit *diagnostics.ClockAudit
+	if cfg.ClockAuditWindow > 0 {
+		clockAudit = diagnostics.NewClockAudit(cfg.ClockAuditWindow, cfg.ClockDriftThreshold)
+		clockAudit.SetDatabase(db)
+		router.SetClockAudit(clockAudit)
+	}
+
+	return &HandlerChain{
+		Router:         router,
+		Executor:       workflowExecutor,
+		CommandHandler: commandHandler,
+		RobotHandler:   robotHandler,
+		StateCache:     stateCache,
+		StateWriter:    stateWriter,
+		Zones:          zoneCoordinator,
+		Artifacts:      artifactService,
+		ReadOnly:       readOnly,
+		Latency:        latency,
+		Preflight:      preflight,
+		ClockAudit:     clockAudit,
+		Transports:     transports,
+		StatusMap:      statusMap,
+		CommandDedup:   commandDedup,
+	}, nil
+}
+
+// installBreakers Postgres/Redis 접근을 회로 차단기로 감쌈 (BREAKER_FAILURE_THRESHOLD가 0이면 설치하지 않음)
+// 연속 실패 후에는 호출이 커넥션 대기 없이 즉시 실패하므로 워크플로우가 타임아웃을 기다리며 쌓이지 않습니다.
+func installBreakers(db *gorm.DB, redisClient *redisClient.Client, cfg *config.Config) ([]*breaker.Breaker, error) {
+	if cfg.BreakerFailureThreshold <= 0 {
+		return nil, nil
+	}
+	dbBreaker := breaker.New("postgres", cfg.BreakerFailureThreshold, cfg.BreakerOpenTimeout)
+	if err := db.Use(breaker.GormPlugin{Breaker: dbBreaker}); err != nil {
+		return nil, err
+	}
+	redisBreaker := breaker.New("redis", cfg.BreakerFailureThreshold, cfg.BreakerOpenTimeout)
+	redisClient.AddHook(breaker.RedisHook{Breaker: redisBreaker})
+
+	utils.Logger.Infof("🔌 Circuit breakers enabled (threshold=%d, open=%s)", cfg.BreakerFailureThreshold, cfg.BreakerOpenTimeout)
+	return []*breaker.Breaker{dbBreaker, redisBreaker}, nil
+}
+
+// installFaults FAULT_INJECTION이 켜져 있으면 Postgres/Redis 장애 주입기 설치 (꺼져 있으면 nil)
+// 회로 차단기 다음에 등록하여 주입한 실패도 차단기에 집계되게 합니다.
+func installFaults(db *gorm.DB, redisClient *redisClient.Client, cfg *config.Config) (*faults.Injector, error) {
+	if !cfg.FaultInjection {
+		return nil, nil
+	}
+	injector := faults.NewInjector()
+	if err := db.Use(faults.GormPlugin{Injector: injector}); err != nil {
+		return nil, err
+	}
+	redisClient.AddHook(faults.RedisHook{Injector: injector})
+	return injector, nil
+}
+
+// NewService 새 브릿지 서비스 생성
+func NewService(db *gorm.DB, redisClient *redisClient.Client, cfg *config.Config) (*Service, error) {
+	utils.Logger.Infof("🏗️ CREATING Bridge Service")
+
+	// 오더/노드/엣지/액션 ID 생성 방식 (관리 도구도 같은 설정으로 적용)
+	if err := idgen.Configure(cfg.IDStrategy, cfg.IDPrefixes); err != nil {
+		return nil, err
+	}
+
+	for _, warning := range cfg.PublishWarnings() {
+		utils.Logger.Warnf("⚠️ %s", warning)
+	}
+
+	breakers, err := installBreakers(db, redisClient, cfg)
+	if err != nil {
+		return nil, err
+	}
+	injector, err := installFaults(db, redisClient, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// VDA 5050 headerId를 로봇/토픽별로 Redis에 이어서 발급 (재시작 후에도 증가)
+	utils.SetHeaderSequencer(sequence.NewHeaderSequence(redisClient))
+
+	mqttClient, err := messaging.NewMQTTClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := NewHandlerChain(db, redisClient, cfg, mqttClient.GetNativeClient())
+	if err != nil {
+		return nil, err
+	}
+	if injector != nil {
+		chain.Transports = workflow.WithFaults(chain.Transports, injector)
+		chain.Executor.SetTransports(chain.Transports)
+	}
+
+	// --- Messaging ---
+	router := chain.Router
 	subscriber := messaging.NewSubscriber(mqttClient, router)
+	subscriber.SetSubscriptionFilter(cfg.SubscriptionFilter)
+	if cfg.MQTTSharedGroup != "" {
+		utils.Logger.Infof("🤝 Shared subscriptions enabled (group %s): %v", cfg.MQTTSharedGroup, cfg.MQTTSharedSubscriptions)
+	}
+
+	var ingestPool *messaging.IngestPool
+	if cfg.IngestPool {
+		ingestPool = messaging.NewIngestPool(router, messaging.IngestOptions{
+			CommandQueueSize:    cfg.IngestCommandQueueSize,
+			ConnectionQueueSize: cfg.IngestConnectionQueueSize,
+			StateQueueSize:      cfg.IngestStateQueueSize,
+			OtherQueueSize:      cfg.IngestOtherQueueSize,
+			StateWorkers:        cfg.IngestStateWorkers,
+		})
+		subscriber.SetIngestPool(ingestPool)
+	}
+
+	stateSink, err := sink.NewFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if stateSink != nil {
+		router.SetStateSink(stateSink)
+	}
+
+	var recorder *replay.Recorder
+	if cfg.RecordFile != "" {
+		recorder, err = replay.NewRecorder(cfg.RecordFile)
+		if err != nil {
+			return nil, err
+		}
+		router.SetRecorder(recorder)
+	}
+
+	var purger *retention.Purger
+	if policy := retention.PolicyFromConfig(cfg); policy.Enabled() {
+		purger, err = retention.NewPurger(db, cfg.SiteID, policy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	alertNotifier, err := notifier.NewFromConfig(db, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if alertNotifier != nil {
+		chain.RobotHandler.AddStateObserver(alertNotifier)
+		if chain.Latency != nil {
+			alertNotifier.SetLatencySource(chain.Latency)
+		}
+	}
+	var discovery *robot.Discovery
+	if cfg.RobotDiscovery {
+		discovery = robot.NewDiscovery(db, cfg.SiteID, cfg.RobotManufacturer)
+	}
+	chargingMonitor := workflow.NewChargingMonitor(chain.Executor)
+	jobRunner := workflow.NewJobRunner(chain.Executor)
+	deadlines := workflow.NewDeadlineMonitor(chain.Executor)
+	redisJanitor := janitor.NewJanitor(db, redisClient, cfg.RedisJanitorInterval, cfg.RedisStepActionsTTL)
+	chain.RobotHandler.AddStateObserver(chargingMonitor)
+
+	var healthServer *health.Server
+	var access *health.AccessControl
+	if cfg.HealthAddr != "" {
+		checker := health.NewChecker(
+			db, redisClient, mqttClient, subscriber, router, cfg.SiteID, cfg.HealthStateStaleness,
+		)
+		if ingestPool != nil {
+			checker.SetQueueSource(ingestPool)
+		}
+		checker.SetSchemaSource(router)
+		checker.SetFreshnessSource(router)
+		checker.SetBreakers(breakers)
+		if chain.CommandDedup != nil {
+			checker.SetDedupSource(chain.CommandDedup)
+		}
+		if buffer := mqttClient.OutboundBuffer(); buffer != nil {
+			checker.SetOutboundBuffer(buffer)
+		}
+		checker.SetProtocolSource(mqttClient)
+		if cfg.RedisJanitorInterval > 0 {
+			checker.SetRedisJanitor(redisJanitor)
+		}
+		if chain.StateWriter != nil {
+			checker.SetStateWriter(chain.StateWriter)
+		}
+		healthServer = health.NewServer(cfg.HealthAddr, checker)
+		access, err = health.NewAccessControl(health.AccessPolicyFromConfig(cfg), cfg.AccessPolicyFile)
+		if err != nil {
+			return nil, err
+		}
+		healthServer.SetAccessControl(access)
+		selfTest := selftest.NewRunner(cfg)
+		selfTest.SetDB(db, nil)
+		selfTest.SetRedis(redisClient, nil)
+		selfTest.SetMQTT(mqttClient.GetNativeClient(), nil)
+		healthServer.SetSelfTest(selfTest)
+		healthServer.SetZones(chain.Zones)
+		healthServer.SetMaps(db, cfg.SiteID)
+		healthServer.SetReadOnly(chain.ReadOnly)
+		if chain.Latency != nil {
+			// 알림 채널이 없어도 같은 기준으로 상태를 판정 (규칙 파싱 오류는 알림 설정에서 이미 보고됨)
+			rules, _ := notifier.ParseRules(cfg.AlertRules)
+			healthServer.SetRobotLatency(chain.Latency, rules.StateLatency, rules.ClockDrift)
+		}
+		if purger != nil {
+			healthServer.SetRetention(purger)
+		}
+		healthServer.SetTransportDebug(workflow.DebugCaptures(chain.Transports))
+		healthServer.SetCommandDryRun(func(commandType string, params map[string]string) (*workflow.DryRunReport, error) {
+			return workflow.DryRunCommand(db, cfg, commandType, params)
+		})
+		healthServer.SetTemplateRollouts(db, cfg.SiteID)
+		healthServer.SetTemplateArchive(db, cfg.SiteID)
+		healthServer.SetTemplateClone(db, cfg.SiteID)
+		healthServer.SetTemplateSettings(db, cfg.SiteID)
+		healthServer.SetTemplateImport(db, cfg.SiteID)
+		healthServer.SetTemplateEstimates(db, cfg.SiteID)
+		healthServer.SetTemplateLint(db, cfg.SiteID)
+		healthServer.SetTemplateShadows(db, cfg.SiteID)
+		healthServer.SetPLCStatusMap(chain.StatusMap, cfg.PlcStatusMapFile)
+		healthServer.SetSubscriptions(mqttClient)
+		if injector != nil {
+			healthServer.SetFaults(injector)
+		}
+		healthServer.SetAlerts(db, cfg.SiteID, alertNotifier)
+		healthServer.SetInitPosition(chain.RobotHandler)
+		healthServer.SetInstantActions(chain.RobotHandler)
+		healthServer.SetEmergencyStop(chain.CommandHandler)
+		healthServer.SetActionSchemas(chain.RobotHandler.GetFactsheetManager())
+		healthServer.SetDualArmTrajectory(chain.Executor)
+		healthServer.SetCharging(chargingMonitor)
+		healthServer.SetRobotMetadata(db, cfg.SiteID)
+		healthServer.SetRobotMaintenance(db, cfg.SiteID)
+		healthServer.SetRobotDefaults(db, cfg.SiteID)
+		healthServer.SetExecutionWindows(db, cfg.SiteID)
+		healthServer.SetChargingPolicies(db, cfg.SiteID)
+		healthServer.SetRobotDiscovery(db, cfg.SiteID, cfg.RobotDiscovery)
+		healthServer.SetStatsOverview(db, redisClient, cfg.SiteID)
+		healthServer.SetRobotStats(db, cfg.SiteID)
+		healthServer.SetAnnotations(db, cfg.SiteID)
+		healthServer.SetCommandHistory(db, cfg.SiteID)
+		healthServer.SetDirectActions(db)
+		healthServer.SetJobs(db, cfg.SiteID)
+		healthServer.SetPreflight(db, cfg.SiteID, chain.Preflight)
+		healthServer.SetRobotTokens(db, cfg.SiteID, cfg.RobotTokenTTL, cfg.RobotTokenRotationGrace)
+		healthServer.SetHTTPIngest(db, cfg.SiteID, messaging.NewHTTPIngest(router, ingestPool, mqttClient.GetNativeClient()))
+		healthServer.SetTemplateSync(db, cfg.SiteID, cfg.TemplateSyncSources)
+		if chain.ClockAudit != nil {
+			healthServer.SetClockAudit(chain.ClockAudit)
+		}
+		healthServer.SetImport(db, cfg.SiteID)
+		if logBuffer := utils.EnableLogBuffer(cfg.LogBufferSize); logBuffer != nil {
+			healthServer.SetLogStream(logBuffer)
+		}
+		orderStatusHub := workflow.NewOrderStatusHub(db, cfg.SiteID)
+		repository.SetOrderStatusListener(orderStatusHub.Publish)
+		healthServer.SetOrderWait(orderStatusHub)
+		healthServer.SetOrderTimeline(db, cfg.SiteID)
+		healthServer.SetArtifacts(chain.Artifacts)
+		healthServer.SetOrderPause(chain.Executor)
+		healthServer.SetOrderCancel(chain.CommandHandler)
+		healthServer.SetGraphQL(graphql.NewHandler(graphql.NewDashboardSchema(db, redisClient, cfg.SiteID)))
+	}
+
+	var grpcServer *grpcapi.Server
+	if cfg.GRPCAddr != "" {
+		grpcService := grpcapi.NewService(db, cfg.SiteID, chain.CommandHandler, chain.CommandHandler, chain.RobotHandler)
+		chain.RobotHandler.AddStateObserver(grpcService)
+		grpcServer = grpcapi.NewServer(cfg.GRPCAddr, grpcService)
+		if access == nil {
+			// 헬스 서버가 꺼져 있어도 변경 작업에는 같은 IP 허용 목록을 적용
+			if access, err = health.NewAccessControl(health.AccessPolicyFromConfig(cfg), cfg.AccessPolicyFile); err != nil {
+				return nil, err
+			}
+		}
+		grpcServer.SetMutationGuard(access)
+		grpcServer.SetReadOnly(chain.ReadOnly)
+	}
 
 	service := &Service{
 		db:             db,
@@ -65,8 +496,23 @@ func NewService(db *gorm.DB, redisClient *redisClient.Client, cfg *config.Config
 		mqttClient:     mqttClient,
 		subscriber:     subscriber,
 		router:         router,
-		commandHandler: commandHandler,
-		robotHandler:   robotHandler,
+		commandHandler: chain.CommandHandler,
+		robotHandler:   chain.RobotHandler,
+		executor:       chain.Executor,
+		stateCache:     chain.StateCache,
+		stateWriter:    chain.StateWriter,
+		ingestPool:     ingestPool,
+		stateSink:      stateSink,
+		recorder:       recorder,
+		healthServer:   healthServer,
+		grpcServer:     grpcServer,
+		purger:         purger,
+		notifier:       alertNotifier,
+		discovery:      discovery,
+		janitor:        redisJanitor,
+		jobRunner:      jobRunner,
+		deadlines:      deadlines,
+		clockAudit:     chain.ClockAudit,
 	}
 
 	utils.Logger.Infof("✅ Bridge Service CREATED")
@@ -76,9 +522,46 @@ func NewService(db *gorm.DB, redisClient *redisClient.Client, cfg *config.Config
 // Start 브릿지 서비스 시작
 func (s *Service) Start(ctx context.Context) error {
 	utils.Logger.Infof("🚀 STARTING Bridge Service")
+	if s.ingestPool != nil {
+		s.ingestPool.Start()
+	}
+	// 재시작 전에 실행 중이던 단계의 액션 맵을 상태 메시지 수신 전에 복구
+	s.executor.RebuildStepActions(ctx)
 	if err := s.subscriber.SubscribeAll(); err != nil {
 		return err
 	}
+	if s.discovery != nil {
+		if err := s.subscriber.Subscribe(s.discovery.Topic(), 0, s.discovery.HandleMessage); err != nil {
+			return err
+		}
+	}
+	s.executor.Start(ctx)
+	s.jobRunner.Start(ctx)
+	s.deadlines.Start(ctx)
+	if s.clockAudit != nil {
+		s.clockAudit.Start(ctx)
+	}
+	if s.stateCache != nil {
+		s.stateCache.Start(ctx)
+	}
+	if s.stateWriter != nil {
+		s.stateWriter.Start(ctx)
+	}
+	if s.purger != nil {
+		s.purger.Start(ctx)
+	}
+	if s.notifier != nil {
+		s.notifier.Start(ctx)
+	}
+	s.janitor.Start(ctx)
+	if s.healthServer != nil {
+		s.healthServer.Start()
+	}
+	if s.grpcServer != nil {
+		if err := s.grpcServer.Start(); err != nil {
+			return err
+		}
+	}
 	go func() {
 		<-ctx.Done()
 		utils.Logger.Info("Context cancelled, stopping bridge service")
@@ -89,7 +572,45 @@ func (s *Service) Start(ctx context.Context) error {
 // Stop 브릿지 서비스 중지
 func (s *Service) Stop() {
 	utils.Logger.Info("🛑 STOPPING Bridge Service")
+	if s.healthServer != nil {
+		s.healthServer.Stop()
+	}
+	if s.grpcServer != nil {
+		s.grpcServer.Stop()
+	}
+	if s.purger != nil {
+		s.purger.Stop()
+	}
+	if s.notifier != nil {
+		s.notifier.Stop()
+	}
+	s.janitor.Stop()
+	s.jobRunner.Stop()
+	s.deadlines.Stop()
+	if s.clockAudit != nil {
+		s.clockAudit.Stop()
+	}
 	s.mqttClient.Disconnect(250)
+	if s.ingestPool != nil {
+		s.ingestPool.Stop()
+	}
+	if s.stateCache != nil {
+		s.stateCache.Stop()
+	}
+	// 수집 워커가 멈춘 뒤 남은 로봇 상태를 기록
+	if s.stateWriter != nil {
+		s.stateWriter.Stop()
+	}
+	if s.stateSink != nil {
+		if err := s.stateSink.Close(); err != nil {
+			utils.Logger.Errorf("Failed to close state sink: %v", err)
+		}
+	}
+	if s.recorder != nil {
+		if err := s.recorder.Close(); err != nil {
+			utils.Logger.Errorf("Failed to close traffic recorder: %v", err)
+		}
+	}
 	s.redis.Close()
 	utils.Logger.Info("✅ Bridge Service STOPPED")
 }
@@ -0,0 +1,131 @@
+// internal/bridge/simulator_integration_test.go
+package bridge_test
+
+import (
+	"context"
+	"fmt"
+	"mqtt-bridge/internal/bridge"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/config"
+	"mqtt-bridge/internal/database"
+	"mqtt-bridge/internal/redis"
+	"mqtt-bridge/internal/robot"
+	"mqtt-bridge/internal/simulator"
+	"os"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// TestCommandRunsOnSimulator PLC 명령이 Executor/StepManager를 거쳐 시뮬레이터 로봇에서 끝까지 실행되는지 확인
+// 프로세스 내 브로커를 쓰지만 PostgreSQL/Redis가 필요하므로 INTEGRATION_TESTS=true일 때만 실행합니다.
+// (DB_*, REDIS_* 환경 변수로 접속 정보를 지정)
+func TestCommandRunsOnSimulator(t *testing.T) {
+	if os.Getenv("INTEGRATION_TESTS") != "true" {
+		t.Skip("set INTEGRATION_TESTS=true with PostgreSQL and Redis available to run")
+	}
+
+	broker, err := simulator.NewBroker("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("NewBroker: %v", err)
+	}
+	defer broker.Close()
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("config.Load: %v", err)
+	}
+	// 실행마다 새 사이트를 써서 샘플 CR 워크플로우가 생성되고 다른 데이터와 섞이지 않게 함
+	suffix := fmt.Sprintf("%d", time.Now().UnixNano())
+	cfg.SiteID = "it-" + suffix
+	cfg.RobotSerialNumber = "SIM-" + suffix
+	cfg.MQTTBroker = broker.URL()
+	cfg.MQTTClientID = "bridge-" + suffix
+	cfg.MQTTClientVersion = config.MQTTVersion311
+	cfg.MQTTBrokerVersion = config.MQTTVersion311
+	cfg.MQTTSharedSubscriptions = nil
+	cfg.RobotDiscovery = false
+	cfg.HealthAddr = ""
+
+	db, err := database.NewPostgresDB(cfg)
+	if err != nil {
+		t.Fatalf("NewPostgresDB: %v", err)
+	}
+	redisClient, err := redis.NewRedisClient(cfg)
+	if err != nil {
+		t.Fatalf("NewRedisClient: %v", err)
+	}
+	defer redisClient.Close()
+
+	service, err := bridge.NewService(db, redisClient, cfg)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := service.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer service.Stop()
+
+	sim := simulator.New(simulator.Options{
+		Broker:         broker.URL(),
+		ClientID:       "simulator-" + suffix,
+		Manufacturer:   cfg.RobotManufacturer,
+		SerialNumber:   cfg.RobotSerialNumber,
+		ActionDuration: 50 * time.Millisecond,
+	})
+	simCtx, simCancel := context.WithCancel(context.Background())
+	if err := sim.Start(simCtx); err != nil {
+		simCancel()
+		t.Fatalf("simulator Start: %v", err)
+	}
+	defer func() {
+		simCancel()
+		sim.Stop()
+	}()
+
+	// 브릿지가 시뮬레이터의 ONLINE 연결 메시지를 기록할 때까지 대기
+	status := robot.NewStatusManager(db, cfg.SiteID)
+	deadline := time.Now().Add(10 * time.Second)
+	for !status.IsOnline(cfg.RobotSerialNumber) {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the simulator to be reported online")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	plc := mqtt.NewClient(mqtt.NewClientOptions().AddBroker(broker.URL()).SetClientID("plc-" + suffix))
+	if token := plc.Connect(); token.Wait() && token.Error() != nil {
+		t.Fatalf("plc connect: %v", token.Error())
+	}
+	defer plc.Disconnect(100)
+
+	responses := make(chan string, 16)
+	if token := plc.Subscribe(cfg.PlcResponseTopic, 1, func(_ mqtt.Client, msg mqtt.Message) {
+		responses <- string(msg.Payload())
+	}); token.Wait() && token.Error() != nil {
+		t.Fatalf("subscribe %s: %v", cfg.PlcResponseTopic, token.Error())
+	}
+	if token := plc.Publish(constants.TopicBridgeCommand, 1, false, "CR"); token.Wait() && token.Error() != nil {
+		t.Fatalf("publish command: %v", token.Error())
+	}
+
+	// 샘플 CR 워크플로우의 모든 단계가 시뮬레이터에서 끝나면 성공 응답이 와야 함
+	want := "CR:" + constants.StatusSuccess
+	timeout := time.After(60 * time.Second)
+	for {
+		select {
+		case response := <-responses:
+			if response == want {
+				return
+			}
+			if response == "CR:"+constants.StatusFailure {
+				t.Fatalf("command failed on the simulator: %q", response)
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %q on %s", want, cfg.PlcResponseTopic)
+		}
+	}
+}
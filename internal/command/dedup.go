@@ -0,0 +1,80 @@
+// internal/command/dedup.go
+package command
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Deduplicator PLC가 같은 명령을 짧은 간격으로 재전송할 때 첫 명령만 처리하도록 걸러냅니다.
+// 첫 명령을 처리하는 동안과 수신 후 window 동안 같은 키의 명령은 무시합니다.
+type Deduplicator struct {
+	window    time.Duration
+	byPayload bool
+
+	mu         sync.Mutex
+	entries    map[string]*dedupEntry
+	suppressed uint64
+}
+
+// dedupEntry 키별 첫 명령 정보
+type dedupEntry struct {
+	correlationID string
+	receivedAt    time.Time
+	processing    bool
+}
+
+// NewDeduplicator 중복 명령 억제기 생성 (byPayload면 명령 문자열과 페이로드 해시를 함께 키로 사용)
+func NewDeduplicator(window time.Duration, byPayload bool) *Deduplicator {
+	return &Deduplicator{
+		window:    window,
+		byPayload: byPayload,
+		entries:   make(map[string]*dedupEntry),
+	}
+}
+
+// Key 중복 판단 키
+func (d *Deduplicator) Key(commandStr string, payload []byte) string {
+	if !d.byPayload {
+		return commandStr
+	}
+	sum := sha256.Sum256(payload)
+	return commandStr + "#" + hex.EncodeToString(sum[:8])
+}
+
+// Begin 명령 처리를 시작합니다. 같은 키의 첫 명령이 처리 중이거나 window 안에 받은 것이면
+// false와 첫 명령의 상관 ID를 반환하며, 이 경우 Done을 호출하지 않습니다.
+func (d *Deduplicator) Begin(key, correlationID string, now time.Time) (bool, string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for k, entry := range d.entries {
+		if !entry.processing && now.Sub(entry.receivedAt) >= d.window {
+			delete(d.entries, k)
+		}
+	}
+	if first, exists := d.entries[key]; exists {
+		d.suppressed++
+		return false, first.correlationID
+	}
+	d.entries[key] = &dedupEntry{correlationID: correlationID, receivedAt: now, processing: true}
+	return true, ""
+}
+
+// Done 명령 처리가 끝났음을 기록 (window가 남아 있으면 그동안은 계속 억제)
+func (d *Deduplicator) Done(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if entry, exists := d.entries[key]; exists {
+		entry.processing = false
+	}
+}
+
+// SuppressedDuplicates 지금까지 무시한 중복 명령 수
+func (d *Deduplicator) SuppressedDuplicates() uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.suppressed
+}
@@ -4,10 +4,14 @@ package command
 import (
 	"context"
 	"fmt"
+	"mqtt-bridge/internal/common/apperr"
 	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/common/idgen"
+	"mqtt-bridge/internal/common/readonly"
 	"mqtt-bridge/internal/config"
 	"mqtt-bridge/internal/messaging"
 	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
 	"mqtt-bridge/internal/utils"
 	"strings"
 	"sync"
@@ -24,9 +28,15 @@ type Handler struct {
 	plcSender        *messaging.PLCResponseSender
 	workflowExecutor WorkflowExecutor
 	robotChecker     RobotStatusChecker
+	codec            messaging.PLCCodec
+	dedup            *Deduplicator    // PLC_DEDUP_WINDOW_MS가 0이면 nil
+	readOnly         *readonly.Switch // 켜져 있으면 취소 외의 명령을 거부 (nil이면 항상 꺼짐)
 
 	activeFSMs map[string]*CommandStateMachine
 	mu         sync.Mutex
+
+	// 로봇별 디스패치 잠금: 같은 로봇의 오더 전송은 직렬화하고 다른 로봇은 병렬 처리
+	dispatchLocks map[string]*sync.Mutex
 }
 
 // NewHandler는 새 명령 핸들러를 생성합니다.
@@ -44,33 +54,163 @@ func NewHandler(
 		plcSender:        plcSender,
 		workflowExecutor: executor,
 		robotChecker:     robotChecker,
+		codec:            messaging.TextCodec{},
 		activeFSMs:       make(map[string]*CommandStateMachine),
+		dispatchLocks:    make(map[string]*sync.Mutex),
 	}
 }
 
+// SetPLCCodec은 PLC 명령 페이로드 코덱을 설정합니다.
+func (h *Handler) SetPLCCodec(codec messaging.PLCCodec) {
+	h.codec = codec
+	utils.Logger.Infof("✅ Command Handler: %s PLC codec set", codec.Name())
+}
+
+// SetDeduplicator는 PLC 명령 재전송 억제기를 설정합니다.
+func (h *Handler) SetDeduplicator(dedup *Deduplicator) {
+	h.dedup = dedup
+	utils.Logger.Infof("✅ Command Handler: duplicate suppression enabled (window=%s, byPayload=%t)", dedup.window, dedup.byPayload)
+}
+
+// SetReadOnly는 읽기 전용 스위치를 설정합니다.
+func (h *Handler) SetReadOnly(sw *readonly.Switch) {
+	h.readOnly = sw
+}
+
 // HandlePLCCommand는 PLC 명령을 받아 표준 또는 직접 액션 FSM을 생성합니다.
 func (h *Handler) HandlePLCCommand(client mqtt.Client, msg mqtt.Message) {
-	commandStr := strings.TrimSpace(string(msg.Payload()))
-	utils.Logger.Infof("🎯 PLC Command received: '%s'", commandStr)
+	request, err := h.codec.DecodeCommand(msg.Payload())
+	if err != nil {
+		utils.Logger.Errorf("❌ Failed to decode PLC command: %v", err)
+		return
+	}
+	commandStr := request.Command
+
+	// 요청에 추적 ID가 없으면 생성하여 DB 기록, 오더, 응답까지 전달
+	correlationID := request.CorrelationID
+	if correlationID == "" {
+		correlationID = idgen.CorrelationID()
+	}
+	utils.Logger.Infof("🎯 PLC Command received: '%s' (cid=%s)", commandStr, correlationID)
+
+	// 비상 정지는 최우선: 연결 상태 확인과 디스패치 잠금을 거치지 않음
+	if commandStr == constants.CommandEmergencyStop {
+		h.handleEmergencyStop(commandStr, correlationID)
+		return
+	}
+
+	// PLC 재전송: 첫 명령이 처리 중이거나 억제 시간 안이면 응답 없이 무시 (첫 명령이 응답함)
+	if h.dedup != nil {
+		key := h.dedup.Key(commandStr, msg.Payload())
+		if ok, firstID := h.dedup.Begin(key, correlationID, time.Now()); !ok {
+			utils.Logger.Warnf("🔁 Duplicate PLC command '%s' suppressed (cid=%s, first cid=%s)", commandStr, correlationID, firstID)
+			return
+		}
+		defer h.dedup.Done(key)
+	}
+
+	// 읽기 전용이면 취소 외의 명령은 실행하지 않고 바쁨(거부)으로 응답
+	if commandStr != constants.CommandOrderCancel && !strings.HasPrefix(commandStr, constants.CommandOrderCancel+":") {
+		if err := h.readOnly.Check("command " + commandStr); err != nil {
+			utils.Logger.Warnf("🔒 Bridge is read-only. Rejecting command: %s (cid=%s)", commandStr, correlationID)
+			h.plcSender.SendError(correlationID, commandStr, constants.StatusRejected, err)
+			return
+		}
+	}
 
 	if !h.robotChecker.IsOnline(h.config.RobotSerialNumber) {
-		utils.Logger.Errorf("❌ Robot is offline. Rejecting command: %s", commandStr)
-		h.plcSender.SendFailure(commandStr, "Robot is not online")
+		utils.Logger.Errorf("❌ Robot is offline. Rejecting command: %s (cid=%s)", commandStr, correlationID)
+		h.plcSender.SendError(correlationID, commandStr, constants.StatusFailure,
+			apperr.New(apperr.CodeRobotOffline, "Robot %s is not online", h.config.RobotSerialNumber))
 		return
 	}
 
-	if IsDirectActionCommand(commandStr) {
-		h.handleDirectAction(commandStr)
+	// 특정 오더 취소 (OC:<orderId>)는 디스패치 잠금 없이 처리
+	if orderID, ok := strings.CutPrefix(commandStr, constants.CommandOrderCancel+":"); ok {
+		h.handleOrderCancel(commandStr, correlationID, orderID)
+		return
+	}
+
+	// 취소 명령은 실행 중인 오더를 멈추기 위한 것이므로 항상 허용
+	if commandStr != constants.CommandOrderCancel {
+		maintenance, err := h.robotChecker.Maintenance(h.config.RobotSerialNumber)
+		if err != nil {
+			utils.Logger.Errorf("❌ Failed to check maintenance mode: %v (cid=%s)", err, correlationID)
+			h.plcSender.SendError(correlationID, commandStr, constants.StatusFailure, err)
+			return
+		}
+		if maintenance != nil {
+			utils.Logger.Warnf("🔧 Robot %s is in maintenance (%s). Rejecting command: %s (cid=%s)",
+				maintenance.SerialNumber, maintenance.Reason, commandStr, correlationID)
+			h.plcSender.SendError(correlationID, commandStr, constants.StatusRejected, repository.MaintenanceError(maintenance))
+			return
+		}
+
+		serialNumber := h.config.RobotSerialNumber
+		lock := h.dispatchLock(serialNumber)
+		lock.Lock()
+		defer lock.Unlock()
+
+		if active := h.activeCommandFor(serialNumber); active != nil {
+			utils.Logger.Warnf("🚦 Robot %s is busy with '%s' (cid=%s). Rejecting command: %s (cid=%s)",
+				serialNumber, active.GetFullCommand(), active.GetCorrelationID(), commandStr, correlationID)
+			h.plcSender.SendError(correlationID, commandStr, constants.StatusRejected,
+				apperr.New(apperr.CodeRobotBusy, "Robot busy with command %s", active.GetFullCommand()))
+			return
+		}
+	}
+
+	// 직접 액션 레지스트리에 등록된 접미사면 단일 액션 오더로 처리
+	call, err := repository.ResolveDirectActionCommand(h.db, commandStr)
+	if err != nil {
+		utils.Logger.Errorf("❌ Invalid direct action command '%s': %v (cid=%s)", commandStr, err, correlationID)
+		h.plcSender.SendError(correlationID, commandStr, constants.StatusFailure, err)
+		return
+	}
+	if call != nil {
+		h.handleDirectAction(commandStr, correlationID, call)
 	} else {
-		h.handleStandardCommand(commandStr)
+		h.handleStandardCommand(commandStr, correlationID, request.Params)
+	}
+}
+
+// dispatchLock은 로봇별 디스패치 잠금을 반환합니다.
+func (h *Handler) dispatchLock(serialNumber string) *sync.Mutex {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	lock, exists := h.dispatchLocks[serialNumber]
+	if !exists {
+		lock = &sync.Mutex{}
+		h.dispatchLocks[serialNumber] = lock
+	}
+	return lock
+}
+
+// activeCommandFor는 로봇에서 아직 종료되지 않은 명령의 상태 머신을 반환합니다.
+func (h *Handler) activeCommandFor(serialNumber string) *CommandStateMachine {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, csm := range h.activeFSMs {
+		if csm.SerialNumber == serialNumber && !csm.IsFinished() {
+			return csm
+		}
 	}
+	return nil
 }
 
-func (h *Handler) handleStandardCommand(commandStr string) {
+func (h *Handler) handleStandardCommand(commandStr, correlationID string, params map[string]string) {
 	var cmdDef models.CommandDefinition
 	if err := h.db.Where("command_type = ? AND is_active = true", commandStr).First(&cmdDef).Error; err != nil {
-		utils.Logger.Errorf("❌ Command definition not found: %s", commandStr)
-		h.plcSender.SendFailure(commandStr, "Command not defined or inactive")
+		utils.Logger.Errorf("❌ Command definition not found: %s (cid=%s)", commandStr, correlationID)
+		h.plcSender.SendError(correlationID, commandStr, constants.StatusFailure,
+			apperr.New(apperr.CodeCommandNotFound, "Command not defined or inactive"))
+		return
+	}
+
+	// 템플릿 자리표시자와 전달된 파라미터가 맞지 않으면 워크플로우를 시작하지 않음
+	if err := repository.ValidateCommandParameters(h.db, cmdDef.ID, params); err != nil {
+		utils.Logger.Errorf("❌ %v (command=%s, cid=%s)", err, commandStr, correlationID)
+		h.plcSender.SendError(correlationID, commandStr, constants.StatusFailure, err)
 		return
 	}
 
@@ -78,15 +218,19 @@ func (h *Handler) handleStandardCommand(commandStr string) {
 		CommandDefinitionID: cmdDef.ID,
 		Status:              constants.CommandStatusPending,
 		RequestTime:         time.Now(),
+		CorrelationID:       correlationID,
+		ParameterOverrides:  repository.EncodeParameters(params),
 	}
 	if err := h.db.Create(command).Error; err != nil {
-		utils.Logger.Errorf("❌ Failed to create command record: %v", err)
-		h.plcSender.SendFailure(commandStr, "Failed to record command")
+		utils.Logger.Errorf("❌ Failed to create command record: %v (cid=%s)", err, correlationID)
+		h.plcSender.SendError(correlationID, commandStr, constants.StatusFailure,
+			apperr.Wrap(apperr.CodeInternal, err, "Failed to record command"))
 		return
 	}
 	h.db.Preload("CommandDefinition").First(&command, command.ID)
 
 	csm := NewCommandStateMachine(h.db, h.plcSender, h.workflowExecutor).ForStandardCommand(command)
+	csm.SerialNumber = h.config.RobotSerialNumber
 	h.addStateMachine(fmt.Sprintf("std-%d", command.ID), csm)
 
 	if err := csm.StartWorkflow(); err != nil {
@@ -95,22 +239,96 @@ func (h *Handler) handleStandardCommand(commandStr string) {
 	}
 }
 
-func (h *Handler) handleDirectAction(commandStr string) {
-	parts := strings.Split(commandStr, ":")
-	baseCommand, cmdType, armParam := parts[0], rune(parts[1][0]), ""
-	if len(parts) >= 3 {
-		armParam = parts[2]
+func (h *Handler) handleDirectAction(commandStr, correlationID string, call *repository.DirectActionCall) {
+	orderID, err := h.workflowExecutor.SendDirectActionOrder(call)
+	if err != nil {
+		utils.Logger.Errorf("❌ Failed to send direct action order: %v (cid=%s)", err, correlationID)
+		h.plcSender.SendError(correlationID, commandStr, constants.StatusFailure, err)
+		return
+	}
+	utils.Logger.Infof("📤 Direct action order %s sent (cid=%s)", orderID, correlationID)
+
+	csm := NewCommandStateMachine(h.db, h.plcSender, h.workflowExecutor).ForDirectAction(commandStr, orderID, correlationID)
+	csm.SerialNumber = h.config.RobotSerialNumber
+	h.addStateMachine(orderID, csm)
+}
+
+// handleOrderCancel은 지정한 오더 하나만 취소합니다.
+func (h *Handler) handleOrderCancel(commandStr, correlationID, orderID string) {
+	if orderID == "" {
+		h.plcSender.SendError(correlationID, commandStr, constants.StatusFailure,
+			apperr.Validation("orderId", "order id is required"))
+		return
+	}
+	reason := fmt.Sprintf("Order %s cancelled by PLC", orderID)
+
+	if err := h.CancelOrder(orderID, reason); err != nil {
+		utils.Logger.Errorf("❌ Failed to cancel order %s: %v (cid=%s)", orderID, err, correlationID)
+		h.plcSender.SendError(correlationID, commandStr, constants.StatusFailure, err)
+		return
+	}
+	utils.Logger.Infof("🚫 Order %s cancelled (cid=%s)", orderID, correlationID)
+	h.plcSender.SendCorrelatedResponse(correlationID, commandStr, constants.StatusSuccess, reason)
+}
+
+// CancelOrder는 오더 하나를 취소합니다. PLC OC:<orderId> 명령과 관리 API가 같은 경로를 사용합니다.
+// 직접 액션 오더는 해당 상태 머신을 실패 처리하고, 표준 명령의 오더는 Executor가 FailureOrder로 분기합니다.
+func (h *Handler) CancelOrder(orderID, reason string) error {
+	h.mu.Lock()
+	csm, isDirect := h.activeFSMs[orderID]
+	if isDirect {
+		delete(h.activeFSMs, orderID)
+	}
+	h.mu.Unlock()
+
+	if isDirect {
+		if !csm.IsFinished() {
+			csm.Fail(reason)
+		}
+		return h.workflowExecutor.SendCancelOrder(orderID)
 	}
+	return h.workflowExecutor.CancelOrder(orderID, reason)
+}
 
-	orderID, err := h.workflowExecutor.SendDirectActionOrder(baseCommand, cmdType, armParam)
+// handleEmergencyStop은 로봇을 비상 정지하고 해당 로봇의 활성 명령을 모두 실패 처리합니다.
+func (h *Handler) handleEmergencyStop(commandStr, correlationID string) {
+	serialNumber := h.config.RobotSerialNumber
+	stopped, err := h.EmergencyStop(serialNumber, "Emergency stop requested by PLC")
 	if err != nil {
-		utils.Logger.Errorf("❌ Failed to send direct action order: %v", err)
-		h.plcSender.SendFailure(commandStr, "Failed to send order to robot")
+		utils.Logger.Errorf("❌ Emergency stop for robot %s failed: %v (cid=%s)", serialNumber, err, correlationID)
+		h.plcSender.SendError(correlationID, commandStr, constants.StatusFailure, err)
 		return
 	}
+	utils.Logger.Warnf("🛑 Emergency stop for robot %s completed: %d order(s) stopped (cid=%s)", serialNumber, stopped, correlationID)
+	h.plcSender.SendCorrelatedResponse(correlationID, commandStr, constants.StatusSuccess,
+		fmt.Sprintf("Emergency stop sent, %d order(s) stopped", stopped))
+}
 
-	csm := NewCommandStateMachine(h.db, h.plcSender, h.workflowExecutor).ForDirectAction(commandStr, orderID)
-	h.addStateMachine(orderID, csm)
+// EmergencyStop은 로봇을 비상 정지하고 진행 중인 직접 액션 FSM까지 실패 처리합니다.
+// PLC ES 명령과 관리 API가 같은 경로를 사용하며, 중지한 오더 수와 전송 오류를 반환합니다.
+func (h *Handler) EmergencyStop(serialNumber, reason string) (int, error) {
+	stopped, err := h.workflowExecutor.EmergencyStop(serialNumber, reason)
+	if apperr.CodeOf(err) == apperr.CodeNotFound {
+		return 0, err
+	}
+
+	// 직접 액션은 Executor가 추적하지 않으므로 여기서 실패 처리
+	h.mu.Lock()
+	keysToRemove := []string{}
+	for key, csm := range h.activeFSMs {
+		if csm.SerialNumber == serialNumber {
+			if !csm.IsFinished() {
+				csm.Fail(reason)
+			}
+			keysToRemove = append(keysToRemove, key)
+		}
+	}
+	for _, key := range keysToRemove {
+		delete(h.activeFSMs, key)
+	}
+	h.mu.Unlock()
+
+	return stopped, err
 }
 
 // HandleRobotStateUpdate는 state 메시지를 적절한 FSM에 전달합니다.
@@ -139,7 +357,7 @@ func (h *Handler) HandleRobotStateUpdate(stateMsg *models.RobotStateMessage) {
 
 	if targetFsm != nil {
 		targetFsm.HandleRobotStateUpdate(stateMsg)
-		if targetFsm.IsDirectAction && (targetFsm.FSM.Is("Completed") || targetFsm.FSM.Is("Failed")) {
+		if targetFsm.IsDirectAction && targetFsm.IsFinished() {
 			delete(h.activeFSMs, targetKey)
 			utils.Logger.Infof("Direct action FSM for order %s has been finalized and removed.", targetKey)
 		}
@@ -190,7 +408,3 @@ func (h *Handler) removeStateMachine(key string) {
 	defer h.mu.Unlock()
 	delete(h.activeFSMs, key)
 }
-
-func IsDirectActionCommand(commandStr string) bool {
-	return strings.HasSuffix(commandStr, ":I") || strings.Contains(commandStr, ":T")
-}
@@ -3,6 +3,7 @@ package command
 
 import (
 	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 )
@@ -13,17 +14,23 @@ type CommandHandler interface {
 	HandleRobotStateUpdate(stateMsg *models.RobotStateMessage)
 	FailAllProcessingCommands(reason string)
 	FinishCommand(commandID uint, success bool)
+	CancelOrder(orderID, reason string) error
+	EmergencyStop(serialNumber, reason string) (int, error)
 }
 
 // WorkflowExecutor는 워크플로우 실행을 담당하는 인터페이스
 type WorkflowExecutor interface {
 	// 인자를 *models.CommandExecution에서 다시 *models.Command로 변경
 	ExecuteCommandOrder(command *models.Command) error
-	SendDirectActionOrder(baseCommand string, commandType rune, armParam string) (string, error)
+	SendDirectActionOrder(call *repository.DirectActionCall) (string, error)
 	CancelAllRunningOrders() error
+	CancelOrder(orderID, reason string) error
+	SendCancelOrder(orderID string) error
+	EmergencyStop(serialNumber, reason string) (int, error)
 }
 
-// RobotStatusChecker는 로봇의 온라인 상태를 확인하는 인터페이스
+// RobotStatusChecker는 로봇의 온라인 상태와 유지보수 모드를 확인하는 인터페이스
 type RobotStatusChecker interface {
 	IsOnline(serialNumber string) bool
+	Maintenance(serialNumber string) (*repository.RobotMaintenance, error)
 }
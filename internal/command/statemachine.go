@@ -26,6 +26,8 @@ type CommandStateMachine struct {
 	Command          *models.Command // 표준 명령용. 직접 액션의 경우 nil
 	CommandExecution *models.CommandExecution
 	OrderID          string // 직접 액션용
+	CorrelationID    string // 직접 액션용 요청 추적 ID (표준 명령은 Command.CorrelationID 사용)
+	SerialNumber     string // 오더를 수행하는 로봇 (로봇별 디스패치 직렬화용)
 }
 
 // NewCommandStateMachine은 새 상태 머신 인스턴스를 생성합니다.
@@ -52,10 +54,11 @@ func (csm *CommandStateMachine) ForStandardCommand(cmd *models.Command) *Command
 }
 
 // ForDirectAction은 직접 액션용으로 상태 머신을 설정합니다.
-func (csm *CommandStateMachine) ForDirectAction(fullCommand, orderID string) *CommandStateMachine {
+func (csm *CommandStateMachine) ForDirectAction(fullCommand, orderID, correlationID string) *CommandStateMachine {
 	csm.IsDirectAction = true
 	csm.FullCommand = fullCommand
 	csm.OrderID = orderID
+	csm.CorrelationID = correlationID
 	// 생성과 동시에 order_sent 이벤트를 발생시켜 Acknowledged 상태로 전환
 	csm.FSM.Event(context.Background(), "order_sent")
 	return csm
@@ -92,12 +95,12 @@ func (csm *CommandStateMachine) onEnterState(ctx context.Context, e *fsm.Event)
 }
 
 func (csm *CommandStateMachine) onEnterAcknowledged(ctx context.Context, e *fsm.Event) {
-	csm.plcSender.SendResponse(csm.FullCommand, constants.StatusAcknowledged, "Order acknowledged by robot")
+	csm.plcSender.SendCorrelatedResponse(csm.GetCorrelationID(), csm.FullCommand, constants.StatusAcknowledged, "Order acknowledged by robot")
 }
 
 func (csm *CommandStateMachine) onEnterCompleted(ctx context.Context, e *fsm.Event) {
 	if csm.IsDirectAction {
-		csm.plcSender.SendResponse(csm.FullCommand, constants.StatusSuccess, "Direct action completed successfully")
+		csm.plcSender.SendCorrelatedResponse(csm.GetCorrelationID(), csm.FullCommand, constants.StatusSuccess, "Direct action completed successfully")
 	} else {
 		utils.Logger.Infof("COMMAND '%s' workflow finished. Final status will be determined by Executor.", csm.FullCommand)
 	}
@@ -113,7 +116,7 @@ func (csm *CommandStateMachine) onEnterFailed(ctx context.Context, e *fsm.Event)
 		}
 	}
 	if csm.IsDirectAction {
-		csm.plcSender.SendResponse(csm.FullCommand, constants.StatusFailure, errMsg)
+		csm.plcSender.SendCorrelatedResponse(csm.GetCorrelationID(), csm.FullCommand, constants.StatusFailure, errMsg)
 	} else {
 		// 표준 명령 실패 시 최종 응답은 Executor가 담당하므로 로그만 남김
 		utils.Logger.Warnf("COMMAND '%s' workflow failed. Final status will be determined by Executor.", csm.FullCommand)
@@ -162,7 +165,7 @@ func (csm *CommandStateMachine) HandleRobotStateUpdate(stateMsg *models.RobotSta
 	}
 
 	if hasRunning && csm.FSM.Is("Running") {
-		csm.plcSender.SendResponse(csm.GetFullCommand(), constants.StatusRunning, "Order is running")
+		csm.plcSender.SendCorrelatedResponse(csm.GetCorrelationID(), csm.GetFullCommand(), constants.StatusRunning, "Order is running")
 	}
 
 	if hasFailed {
@@ -204,6 +207,19 @@ func (csm *CommandStateMachine) Fail(reason string) {
 	csm.FSM.Event(context.Background(), "robot_failed", reason)
 }
 
+// IsFinished는 명령이 완료 또는 실패 상태인지 확인합니다.
+func (csm *CommandStateMachine) IsFinished() bool {
+	return csm.FSM.Is("Completed") || csm.FSM.Is("Failed")
+}
+
 func (csm *CommandStateMachine) GetFullCommand() string {
 	return csm.FullCommand
 }
+
+// GetCorrelationID는 명령의 요청 추적 ID를 반환합니다.
+func (csm *CommandStateMachine) GetCorrelationID() string {
+	if csm.Command != nil {
+		return csm.Command.CorrelationID
+	}
+	return csm.CorrelationID
+}
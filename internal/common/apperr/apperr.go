@@ -0,0 +1,158 @@
+// internal/common/apperr/apperr.go
+package apperr
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Code 기계가 판별할 수 있는 안정적인 오류 코드
+type Code string
+
+// 오류 코드 (PLC 응답과 관리 도구 출력에 그대로 노출되므로 값을 바꾸지 않음)
+const (
+	CodeRobotOffline         Code = "ROBOT_OFFLINE"
+	CodeRobotBusy            Code = "ROBOT_BUSY"
+	CodeRobotMaintenance     Code = "ROBOT_MAINTENANCE"
+	CodeRobotNotApproved     Code = "ROBOT_NOT_APPROVED"
+	CodeExecutionWindow      Code = "EXECUTION_WINDOW"
+	CodePreflightFailed      Code = "PREFLIGHT_FAILED"
+	CodeUnsupportedFeature   Code = "UNSUPPORTED_FEATURE"
+	CodeTemplateNotFound     Code = "TEMPLATE_NOT_FOUND"
+	CodeCommandNotFound      Code = "COMMAND_NOT_FOUND"
+	CodeNotFound             Code = "NOT_FOUND"
+	CodeTransportUnavailable Code = "TRANSPORT_UNAVAILABLE"
+	CodePayloadTooLarge      Code = "PAYLOAD_TOO_LARGE"
+	CodeUnauthorized         Code = "UNAUTHORIZED"
+	CodeReadOnly             Code = "READ_ONLY"
+	CodeValidationFailed     Code = "VALIDATION_FAILED"
+	CodeInternal             Code = "INTERNAL"
+)
+
+// Error 코드, 문제가 된 필드, 요청 추적 ID를 담은 구조화된 오류
+type Error struct {
+	Code          Code
+	Message       string
+	Field         string
+	CorrelationID string
+	Err           error
+}
+
+// Error "[CODE] message" 형식 (원인 오류가 있으면 덧붙임)
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("[%s] %s", e.Code, e.Message)
+	if e.Field != "" {
+		msg += " (field: " + e.Field + ")"
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap 원인 오류 반환
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WithField 문제가 된 필드 설정
+func (e *Error) WithField(field string) *Error {
+	e.Field = field
+	return e
+}
+
+// WithCorrelationID 요청 추적 ID 설정
+func (e *Error) WithCorrelationID(correlationID string) *Error {
+	e.CorrelationID = correlationID
+	return e
+}
+
+// New 코드와 메시지로 오류 생성
+func New(code Code, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Wrap 원인 오류를 코드와 메시지로 감싸기
+func Wrap(code Code, err error, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+// Validation 필드 검증 실패 오류 생성
+func Validation(field, format string, args ...interface{}) *Error {
+	return &Error{Code: CodeValidationFailed, Message: fmt.Sprintf(format, args...), Field: field}
+}
+
+// Coder 자체 오류 코드를 가진 오류 타입 (예: 검증 오류)
+type Coder interface {
+	ErrorCode() Code
+}
+
+// Fielder 문제가 된 필드를 알려주는 오류 타입
+type Fielder interface {
+	ErrorField() string
+}
+
+// From 임의의 오류를 구조화된 오류로 변환합니다.
+//   - *Error가 포함되어 있으면 그대로 사용
+//   - Coder/Fielder를 구현한 오류는 해당 코드와 필드 사용
+//   - gorm.ErrRecordNotFound는 NOT_FOUND
+//   - 그 외는 INTERNAL
+func From(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	result := &Error{Code: CodeInternal, Message: err.Error(), Err: err}
+	var coder Coder
+	if errors.As(err, &coder) {
+		result.Code = coder.ErrorCode()
+	} else if errors.Is(err, gorm.ErrRecordNotFound) {
+		result.Code = CodeNotFound
+	}
+	var fielder Fielder
+	if errors.As(err, &fielder) {
+		result.Field = fielder.ErrorField()
+	}
+	return result
+}
+
+// CodeOf 오류의 코드 (nil이면 빈 값)
+func CodeOf(err error) Code {
+	if e := From(err); e != nil {
+		return e.Code
+	}
+	return ""
+}
+
+// Response 외부(PLC 응답, 관리 도구 JSON 출력)에 노출하는 오류 표현
+type Response struct {
+	Code          Code   `json:"code"`
+	Message       string `json:"message"`
+	Field         string `json:"field,omitempty"`
+	CorrelationID string `json:"cid,omitempty"`
+}
+
+// ToResponse 오류를 외부 응답 형식으로 변환 (추적 ID가 없으면 correlationID 사용)
+func ToResponse(err error, correlationID string) Response {
+	e := From(err)
+	if e == nil {
+		return Response{}
+	}
+	response := Response{
+		Code:          e.Code,
+		Message:       e.Message,
+		Field:         e.Field,
+		CorrelationID: e.CorrelationID,
+	}
+	if response.CorrelationID == "" {
+		response.CorrelationID = correlationID
+	}
+	return response
+}
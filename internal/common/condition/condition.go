@@ -0,0 +1,417 @@
+// internal/common/condition/condition.go
+package condition
+
+import (
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/models"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// 값 타입
+const (
+	TypeNumber = "number"
+	TypeString = "string"
+	TypeBool   = "bool"
+)
+
+// Env 조건 평가 환경 (최신 로봇 상태와 직전 단계 결과)
+type Env struct {
+	State          *models.RobotStateMessage
+	PreviousResult string // SUCCESS, FAILURE (첫 단계는 빈 값)
+}
+
+// variable 조건식에서 사용할 수 있는 변수
+type variable struct {
+	typ      string
+	needs    bool // 로봇 상태가 필요한지 여부
+	describe string
+	get      func(env Env) interface{}
+}
+
+// variables 변수 이름 → 정의
+var variables = map[string]variable{
+	"battery":             {TypeNumber, true, "batteryState.batteryCharge (%)", func(e Env) interface{} { return e.State.BatteryState.BatteryCharge }},
+	"batteryVoltage":      {TypeNumber, true, "batteryState.batteryVoltage", func(e Env) interface{} { return e.State.BatteryState.BatteryVoltage }},
+	"charging":            {TypeBool, true, "batteryState.charging", func(e Env) interface{} { return e.State.BatteryState.Charging }},
+	"operatingMode":       {TypeString, true, "operatingMode", func(e Env) interface{} { return e.State.OperatingMode }},
+	"driving":             {TypeBool, true, "driving", func(e Env) interface{} { return e.State.Driving }},
+	"paused":              {TypeBool, true, "paused", func(e Env) interface{} { return e.State.Paused }},
+	"lastNodeId":          {TypeString, true, "lastNodeId", func(e Env) interface{} { return e.State.LastNodeID }},
+	"mapId":               {TypeString, true, "agvPosition.mapId", func(e Env) interface{} { return e.State.AgvPosition.MapID }},
+	"positionInitialized": {TypeBool, true, "agvPosition.positionInitialized", func(e Env) interface{} { return e.State.AgvPosition.PositionInitialized }},
+	"x":                   {TypeNumber, true, "agvPosition.x", func(e Env) interface{} { return e.State.AgvPosition.X }},
+	"y":                   {TypeNumber, true, "agvPosition.y", func(e Env) interface{} { return e.State.AgvPosition.Y }},
+	"theta":               {TypeNumber, true, "agvPosition.theta", func(e Env) interface{} { return e.State.AgvPosition.Theta }},
+	"eStop":               {TypeString, true, "safetyState.eStop", func(e Env) interface{} { return e.State.SafetyState.EStop }},
+	"errors":              {TypeNumber, true, "number of errors", func(e Env) interface{} { return float64(len(e.State.Errors)) }},
+	"fatalErrors": {TypeNumber, true, "number of FATAL errors", func(e Env) interface{} {
+		count := 0
+		for _, err := range e.State.Errors {
+			if err.ErrorLevel == "FATAL" {
+				count++
+			}
+		}
+		return float64(count)
+	}},
+	"previousResult": {TypeString, false, "previous step result (SUCCESS, FAILURE)", func(e Env) interface{} { return e.PreviousResult }},
+}
+
+// Variables 사용 가능한 변수 이름과 설명 (이름순)
+func Variables() []string {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		v := variables[name]
+		result = append(result, fmt.Sprintf("%s (%s): %s", name, v.typ, v.describe))
+	}
+	return result
+}
+
+// Expr 검증된 조건식
+// 문법: 비교(==, !=, >, >=, <, <=)를 &&, ||, !, 괄호로 조합합니다.
+// 피연산자는 변수, 숫자, 'text'/"text" 문자열, true/false, 대문자 상수(AUTOMATIC 등)입니다.
+type Expr struct {
+	source     string
+	root       node
+	needsState bool
+}
+
+// String 원본 조건식
+func (e *Expr) String() string {
+	return e.source
+}
+
+// NeedsState 로봇 상태 변수를 참조하는지 여부
+func (e *Expr) NeedsState() bool {
+	return e.needsState
+}
+
+// Parse 조건식을 구문 분석하고 변수와 타입을 검증
+func Parse(source string) (*Expr, error) {
+	p := &parser{source: source}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, p.errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	if root.typ() != TypeBool {
+		return nil, p.errorf("expression must be a comparison or boolean, got %s", root.typ())
+	}
+	return &Expr{source: source, root: root, needsState: p.needsState}, nil
+}
+
+// Validate 조건식이 올바른지 검사 (빈 조건식은 항상 참)
+func Validate(source string) error {
+	if strings.TrimSpace(source) == "" {
+		return nil
+	}
+	_, err := Parse(source)
+	return err
+}
+
+// Eval 조건식 평가 (상태 변수를 쓰는데 로봇 상태가 없으면 오류)
+func (e *Expr) Eval(env Env) (bool, error) {
+	if e.needsState && env.State == nil {
+		return false, fmt.Errorf("condition %q needs robot state but none has been received", e.source)
+	}
+	return e.root.eval(env).(bool), nil
+}
+
+// node 구문 트리 노드 (타입은 구문 분석 시 확정)
+type node interface {
+	typ() string
+	eval(env Env) interface{}
+}
+
+type literalNode struct {
+	value interface{}
+	t     string
+}
+
+func (n literalNode) typ() string          { return n.t }
+func (n literalNode) eval(Env) interface{} { return n.value }
+
+type variableNode struct {
+	name string
+}
+
+func (n variableNode) typ() string              { return variables[n.name].typ }
+func (n variableNode) eval(env Env) interface{} { return variables[n.name].get(env) }
+
+type notNode struct {
+	operand node
+}
+
+func (n notNode) typ() string              { return TypeBool }
+func (n notNode) eval(env Env) interface{} { return !n.operand.eval(env).(bool) }
+
+type logicalNode struct {
+	op          string // &&, ||
+	left, right node
+}
+
+func (n logicalNode) typ() string { return TypeBool }
+func (n logicalNode) eval(env Env) interface{} {
+	left := n.left.eval(env).(bool)
+	if n.op == "&&" {
+		return left && n.right.eval(env).(bool)
+	}
+	return left || n.right.eval(env).(bool)
+}
+
+type compareNode struct {
+	op          string
+	left, right node
+}
+
+func (n compareNode) typ() string { return TypeBool }
+func (n compareNode) eval(env Env) interface{} {
+	left, right := n.left.eval(env), n.right.eval(env)
+	switch n.op {
+	case "==":
+		return left == right
+	case "!=":
+		return left != right
+	}
+	l, r := left.(float64), right.(float64)
+	switch n.op {
+	case ">":
+		return l > r
+	case ">=":
+		return l >= r
+	case "<":
+		return l < r
+	default:
+		return l <= r
+	}
+}
+
+// token 어휘 단위
+type token struct {
+	kind string // ident, number, string, op, lparen, rparen
+	text string
+	pos  int
+}
+
+type parser struct {
+	source     string
+	tokens     []token
+	pos        int
+	needsState bool
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return apperr.Validation("condition", "invalid condition %q: %s", p.source, fmt.Sprintf(format, args...))
+}
+
+// tokenize 조건식을 토큰으로 분리
+func (p *parser) tokenize() error {
+	src := p.source
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(' || c == ')':
+			kind := "lparen"
+			if c == ')' {
+				kind = "rparen"
+			}
+			p.tokens = append(p.tokens, token{kind, string(c), i})
+			i++
+		case strings.ContainsRune("=!<>&|", c):
+			op := string(c)
+			if i+1 < len(src) {
+				two := src[i : i+2]
+				switch two {
+				case "==", "!=", ">=", "<=", "&&", "||":
+					op = two
+				}
+			}
+			if op == "=" || op == "&" || op == "|" {
+				return p.errorf("unknown operator %q at position %d", op, i)
+			}
+			p.tokens = append(p.tokens, token{"op", op, i})
+			i += len(op)
+		case c == '\'' || c == '"':
+			end := strings.IndexRune(src[i+1:], c)
+			if end < 0 {
+				return p.errorf("unterminated string at position %d", i)
+			}
+			p.tokens = append(p.tokens, token{"string", src[i+1 : i+1+end], i})
+			i += end + 2
+		case unicode.IsDigit(c) || c == '-' || c == '.':
+			start := i
+			i++
+			for i < len(src) && (unicode.IsDigit(rune(src[i])) || src[i] == '.') {
+				i++
+			}
+			p.tokens = append(p.tokens, token{"number", src[start:i], start})
+		case unicode.IsLetter(c) || c == '_':
+			start := i
+			for i < len(src) && (unicode.IsLetter(rune(src[i])) || unicode.IsDigit(rune(src[i])) || src[i] == '_') {
+				i++
+			}
+			p.tokens = append(p.tokens, token{"ident", src[start:i], start})
+		default:
+			return p.errorf("unexpected character %q at position %d", c, i)
+		}
+	}
+	if len(p.tokens) == 0 {
+		return p.errorf("empty expression")
+	}
+	return nil
+}
+
+func (p *parser) peek() *token {
+	if p.pos < len(p.tokens) {
+		return &p.tokens[p.pos]
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t != nil && t.text == "||"; t = p.peek() {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.requireBool("||", left, right); err != nil {
+			return nil, err
+		}
+		left = logicalNode{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t != nil && t.text == "&&"; t = p.peek() {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.requireBool("&&", left, right); err != nil {
+			return nil, err
+		}
+		left = logicalNode{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if t := p.peek(); t != nil && t.kind == "op" && t.text == "!" {
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if operand.typ() != TypeBool {
+			return nil, p.errorf("'!' needs a boolean, got %s", operand.typ())
+		}
+		return notNode{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	if t == nil || t.kind != "op" || t.text == "&&" || t.text == "||" || t.text == "!" {
+		return left, nil
+	}
+	p.pos++
+	right, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if left.typ() != right.typ() {
+		return nil, p.errorf("cannot compare %s with %s", left.typ(), right.typ())
+	}
+	if t.text != "==" && t.text != "!=" && left.typ() != TypeNumber {
+		return nil, p.errorf("operator %s needs numbers, got %s", t.text, left.typ())
+	}
+	return compareNode{op: t.text, left: left, right: right}, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, p.errorf("unexpected end of expression")
+	}
+	p.pos++
+
+	switch t.kind {
+	case "lparen":
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if next := p.peek(); next == nil || next.kind != "rparen" {
+			return nil, p.errorf("missing ')' for '(' at position %d", t.pos)
+		}
+		p.pos++
+		return inner, nil
+	case "number":
+		value, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", t.text)
+		}
+		return literalNode{value: value, t: TypeNumber}, nil
+	case "string":
+		return literalNode{value: t.text, t: TypeString}, nil
+	case "ident":
+		switch t.text {
+		case "true":
+			return literalNode{value: true, t: TypeBool}, nil
+		case "false":
+			return literalNode{value: false, t: TypeBool}, nil
+		}
+		if v, ok := variables[t.text]; ok {
+			if v.needs {
+				p.needsState = true
+			}
+			return variableNode{name: t.text}, nil
+		}
+		// AUTOMATIC, SUCCESS 같은 대문자 상수는 문자열로 취급
+		if strings.ToUpper(t.text) == t.text {
+			return literalNode{value: t.text, t: TypeString}, nil
+		}
+		return nil, p.errorf("unknown variable %q", t.text)
+	default:
+		return nil, p.errorf("unexpected %q at position %d", t.text, t.pos)
+	}
+}
+
+// requireBool 논리 연산자의 양쪽이 불리언인지 확인
+func (p *parser) requireBool(op string, left, right node) error {
+	if left.typ() != TypeBool || right.typ() != TypeBool {
+		return p.errorf("'%s' needs booleans, got %s and %s", op, left.typ(), right.typ())
+	}
+	return nil
+}
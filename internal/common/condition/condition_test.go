@@ -0,0 +1,179 @@
+// internal/common/condition/condition_test.go
+package condition
+
+import (
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/models"
+	"strings"
+	"testing"
+)
+
+// testEnv 배터리 42.5%, 충전 중 아님, AUTOMATIC, FATAL 오류 1건, 직전 단계 SUCCESS
+func testEnv() Env {
+	return Env{
+		State: &models.RobotStateMessage{
+			BatteryState:  models.BatteryState{BatteryCharge: 42.5, BatteryVoltage: 24.1},
+			OperatingMode: "AUTOMATIC",
+			LastNodeID:    "dock-1",
+			AgvPosition:   models.AgvPosition{MapID: "floor-1", X: -1.5, Y: 3, PositionInitialized: true},
+			SafetyState:   models.SafetyState{EStop: "NONE"},
+			Errors: []models.ErrorInfo{
+				{ErrorType: "laser", ErrorLevel: "WARNING"},
+				{ErrorType: "drive", ErrorLevel: "FATAL"},
+			},
+		},
+		PreviousResult: "SUCCESS",
+	}
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		// 비교
+		{"battery > 40", true},
+		{"battery >= 42.5", true},
+		{"battery < 42.5", false},
+		{"battery <= .5", false},
+		{"x < -1", true},
+		{"x == -1.5", true},
+		{"batteryVoltage != 24.1", false},
+		{"errors == 2", true},
+		{"fatalErrors > 0", true},
+
+		// 문자열과 대문자 상수
+		{"operatingMode == AUTOMATIC", true},
+		{"operatingMode == 'AUTOMATIC'", true},
+		{`operatingMode == "automatic"`, false},
+		{"lastNodeId == 'dock-1'", true},
+		{"mapId != 'floor-2'", true},
+		{"previousResult == SUCCESS", true},
+		{"eStop == NONE", true},
+
+		// 불리언 변수와 리터럴
+		{"charging", false},
+		{"!charging", true},
+		{"!!charging", false},
+		{"positionInitialized == true", true},
+		{"true", true},
+
+		// 우선순위: !는 비교보다, &&는 ||보다 먼저 묶임
+		{"charging || battery > 40 && operatingMode == AUTOMATIC", true},
+		{"battery > 90 && charging || previousResult == SUCCESS", true},
+		{"battery > 90 && (charging || previousResult == SUCCESS)", false},
+		{"!(battery > 40) || charging", false},
+		{"!charging && !paused && !driving", true},
+		{"((battery > 40))", true},
+	}
+	env := testEnv()
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.expr, err)
+			}
+			got, err := expr.Eval(env)
+			if err != nil {
+				t.Fatalf("Eval(%q): %v", tt.expr, err)
+			}
+			if got != tt.want {
+				t.Fatalf("Eval(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string // 오류 메시지에 들어 있어야 하는 내용
+	}{
+		// 알 수 없는 변수 (소문자 이름은 상수로 취급하지 않음)
+		{"batery > 20", `unknown variable "batery"`},
+		{"operatingMode == automatic", `unknown variable "automatic"`},
+
+		// 타입 오류
+		{"battery == 'full'", "cannot compare number with string"},
+		{"operatingMode > 'A'", "operator > needs numbers, got string"},
+		{"charging == 1", "cannot compare bool with number"},
+		{"charging < true", "operator < needs numbers, got bool"},
+		{"!battery", "'!' needs a boolean, got number"},
+		{"battery && charging", "'&&' needs booleans, got number and bool"},
+		{"operatingMode || charging", "'||' needs booleans, got string and bool"},
+		{"battery", "must be a comparison or boolean, got number"},
+		{"'AUTOMATIC'", "must be a comparison or boolean, got string"},
+
+		// 지원하지 않는 연산 (산술 없음)
+		{"battery / 2 > 10", `unexpected character '/'`},
+		{"battery * 2 > 10", `unexpected character '*'`},
+		{"battery + 1 > 10", `unexpected character '+'`},
+		{"battery = 20", `unknown operator "="`},
+		{"charging & paused", `unknown operator "&"`},
+		{"charging | paused", `unknown operator "|"`},
+
+		// 잘못된 형식
+		{"", "empty expression"},
+		{"   ", "empty expression"},
+		{"battery >", "unexpected end of expression"},
+		{"(battery > 20", "missing ')'"},
+		{"battery > 20)", `unexpected ")"`},
+		{"battery > 20 battery", `unexpected "battery"`},
+		{"operatingMode == 'AUTO", "unterminated string"},
+		{"battery > 1.2.3", `invalid number "1.2.3"`},
+		{"battery > -", `invalid number "-"`},
+		{"battery > 20 > 10", `unexpected ">"`},
+		{"&& charging", `unexpected "&&"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Parse(tt.expr)
+			if err == nil {
+				t.Fatalf("Parse(%q): expected an error", tt.expr)
+			}
+			if apperr.CodeOf(err) != apperr.CodeValidationFailed {
+				t.Fatalf("Parse(%q): expected a validation error, got %v", tt.expr, err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Parse(%q): error %q does not contain %q", tt.expr, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalWithoutState(t *testing.T) {
+	expr, err := Parse("previousResult == FAILURE")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if expr.NeedsState() {
+		t.Fatal("previousResult should not need robot state")
+	}
+	got, err := expr.Eval(Env{PreviousResult: "FAILURE"})
+	if err != nil || !got {
+		t.Fatalf("Eval = %v, %v; want true", got, err)
+	}
+
+	expr, err = Parse("previousResult == SUCCESS && battery > 20")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !expr.NeedsState() {
+		t.Fatal("battery should need robot state")
+	}
+	if _, err := expr.Eval(Env{PreviousResult: "SUCCESS"}); err == nil || !strings.Contains(err.Error(), "needs robot state") {
+		t.Fatalf("expected a missing state error, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate("  "); err != nil {
+		t.Fatalf("blank condition should be valid (always true): %v", err)
+	}
+	if err := Validate("battery > 20"); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if err := Validate("battery >"); err == nil {
+		t.Fatal("expected an error for an incomplete condition")
+	}
+}
@@ -10,6 +10,7 @@ const (
 	StatusAbnormal     = "A" // 비정상 상태
 	StatusNormal       = "N" // 정상 상태
 	StatusAcknowledged = "K" // 새로 추가: Acknowledged (요청 인지됨)
+	StatusPaused       = "P" // 운영자가 오더를 일시정지함 (재개하면 다시 R)
 )
 
 // Command Status DB 저장용 상태 상수
@@ -30,12 +31,18 @@ const (
 	CommandExecutionStatusCompleted = "COMPLETED"
 	CommandExecutionStatusFailed    = "FAILED"
 	CommandExecutionStatusCancelled = "CANCELLED"
+	CommandExecutionStatusEStopped  = "E_STOPPED"
 
 	OrderExecutionStatusPending   = "PENDING"
 	OrderExecutionStatusRunning   = "RUNNING"
 	OrderExecutionStatusWaiting   = "WAITING"
+	OrderExecutionStatusPaused    = "PAUSED" // 운영자가 startPause로 일시정지 (stopPause로 재개하면 RUNNING)
 	OrderExecutionStatusCompleted = "COMPLETED"
 	OrderExecutionStatusFailed    = "FAILED"
+	OrderExecutionStatusEStopped  = "E_STOPPED"
+	OrderExecutionStatusCancelled = "CANCELLED"
+	OrderExecutionStatusRejected  = "REJECTED" // 로봇이 오더를 받아들이지 않음 (state.errors에서 orderId 참조)
+	OrderExecutionStatusExpired   = "EXPIRED"  // 제한 시간(DeadlineAt)이 지나 브릿지가 로봇에서 취소
 
 	StepExecutionStatusPending  = "PENDING"
 	StepExecutionStatusRunning  = "RUNNING"
@@ -43,6 +50,99 @@ const (
 	StepExecutionStatusFailed   = "FAILED"
 	StepExecutionStatusSkipped  = "SKIPPED"
 	StepExecutionStatusTimeout  = "TIMEOUT"
+	// 오더를 보냈지만 제한 시간 안에 그 오더를 참조하는 상태 메시지가 오지 않음 (로봇이 오더를 받지 못함)
+	StepExecutionStatusNotAcknowledged = "NOT_ACKNOWLEDGED"
+)
+
+// Outbox Status 아웃박스 메시지 상태 상수
+const (
+	OutboxStatusPending = "PENDING"
+	OutboxStatusSent    = "SENT"
+	OutboxStatusFailed  = "FAILED"
+)
+
+// Purge Run 실행 이력 정리 실행 상태/트리거 상수
+const (
+	PurgeRunStatusRunning   = "RUNNING"
+	PurgeRunStatusCompleted = "COMPLETED"
+	PurgeRunStatusFailed    = "FAILED"
+
+	PurgeTriggerScheduled = "SCHEDULED"
+	PurgeTriggerManual    = "MANUAL"
+)
+
+// Template Status 오더 템플릿 활성화 상태 상수
+const (
+	TemplateStatusDraft      = "DRAFT"      // 작성 중 (카나리 롤아웃 대상 로봇만 실행)
+	TemplateStatusActive     = "ACTIVE"     // 운영 버전
+	TemplateStatusDeprecated = "DEPRECATED" // 새 버전으로 대체됨
+
+	TemplateRolloutStatusCanary     = "CANARY"
+	TemplateRolloutStatusPromoted   = "PROMOTED"
+	TemplateRolloutStatusRolledBack = "ROLLED_BACK"
+
+	TemplateNotifyFailure = "FAILURE" // 실패한 오더만 알림 (기본값)
+	TemplateNotifyAll     = "ALL"     // 완료된 오더도 알림
+	TemplateNotifyNone    = "NONE"    // 알림 없음
+)
+
+// Job Status 여러 로봇/명령에 걸친 작업 묶음과 작업 항목 상태 상수
+const (
+	JobStatusPending   = "PENDING"   // 아직 시작한 작업 항목 없음
+	JobStatusRunning   = "RUNNING"   // 실행 중인 작업 항목이 있음
+	JobStatusCompleted = "COMPLETED" // 모든 작업 항목 완료
+	JobStatusFailed    = "FAILED"    // 모든 작업 항목이 끝났고 하나 이상 실패
+	JobStatusCancelled = "CANCELLED" // 운영자가 작업 묶음 전체를 취소
+)
+
+// Execution Window Policy 실행 제한 시간대의 오더 처리 방식 상수
+const (
+	ExecutionWindowPolicyQueue  = "QUEUE"  // 시간대가 끝날 때까지 대기 후 실행
+	ExecutionWindowPolicyReject = "REJECT" // 즉시 거부
+)
+
+// Robot Registration 로봇 탐색으로 등록된 로봇의 승인 상태 상수
+const (
+	RobotRegistrationPending  = "PENDING"  // 새로 발견되어 운영자 승인 대기 (오더 배차 불가)
+	RobotRegistrationApproved = "APPROVED" // 오더 배차 가능
+	RobotRegistrationRejected = "REJECTED" // 거부됨 (계속 메시지를 보내도 승인 대기로 돌아가지 않음)
+)
+
+// Preflight Check 오더 전송 전 로봇 사전 점검 상수
+const (
+	PreflightCheckNoErrors            = "no_errors"            // state.errors가 비어 있음
+	PreflightCheckPositionInitialized = "position_initialized" // agvPosition.positionInitialized
+	PreflightCheckAutomaticMode       = "automatic_mode"       // operatingMode가 AUTOMATIC
+	PreflightCheckEStopReleased       = "estop_released"       // safetyState.eStop이 NONE
+)
+
+// Annotation Target 운영자 주석 대상 상수
+const (
+	AnnotationTargetOrder   = "ORDER"
+	AnnotationTargetCommand = "COMMAND"
+)
+
+// Alert 알림 규칙/심각도/전송 상태 상수
+const (
+	AlertRuleRobotOffline  = "robot_offline"  // 로봇이 일정 시간 이상 오프라인
+	AlertRuleCommandFailed = "command_failed" // 명령 실행 실패
+	AlertRuleBatteryLow    = "battery_low"    // 배터리 잔량이 기준 미만
+	AlertRuleOrderStuck    = "order_stuck"    // 오더가 일정 시간 이상 RUNNING
+	AlertRuleStateLatency  = "state_latency"  // state 메시지 네트워크 지연 p95가 기준 초과
+	AlertRuleClockDrift    = "clock_drift"    // 로봇 시계 오차 추정값이 기준 초과
+	AlertRuleOrderFinished = "order_finished" // 오더 종료 (템플릿 알림 설정에 따라 실패/완료)
+	AlertRuleTest          = "test"           // 채널 설정 확인용 시험 알림
+
+	AlertSeverityInfo     = "INFO"
+	AlertSeverityWarning  = "WARNING"
+	AlertSeverityCritical = "CRITICAL"
+
+	AlertChannelSlack = "slack"
+	AlertChannelEmail = "email"
+
+	AlertStatusSent       = "SENT"
+	AlertStatusFailed     = "FAILED"
+	AlertStatusSuppressed = "SUPPRESSED"
 )
 
 // Robot Connection State 로봇 연결 상태 상수
@@ -101,11 +201,26 @@ const (
 	EStopManualAck = "MANUALACK"
 )
 
+// Artifact Kind 오더 결과물 종류 상수
+const (
+	ArtifactKindInferenceOutput = "INFERENCE_OUTPUT"
+	ArtifactKindImage           = "IMAGE"
+	ArtifactKindLogReport       = "LOG_REPORT"
+	ArtifactKindOther           = "OTHER"
+)
+
+// Map Zone Type 맵 구역 타입 상수
+const (
+	ZoneTypeAllowed    = "ALLOWED"
+	ZoneTypeRestricted = "RESTRICTED"
+)
+
 // Command Type 명령 타입 상수
 const (
 	CommandTypeInference  = 'I'
 	CommandTypeTrajectory = 'T'
 	CommandOrderCancel    = "OC"
+	CommandEmergencyStop  = "ES"
 )
 
 // Arm Type 팔 타입 상수
@@ -121,6 +236,9 @@ const (
 	ActionTypeInitPosition     = "initPosition"
 	ActionTypeFactsheetRequest = "factsheetRequest"
 	ActionTypeCancelOrder      = "cancelOrder"
+	ActionTypeStartPause       = "startPause"
+	ActionTypeStopPause        = "stopPause"
+	ActionTypeStateRequest     = "stateRequest"
 	ActionTypeInference        = "Roboligent Robin - Inference"
 	ActionTypeTrajectory       = "Roboligent Robin - Follow Trajectory"
 )
@@ -129,6 +247,8 @@ const (
 const (
 	TopicBridgeCommand   = "bridge/command"
 	TopicBridgeResponse  = "bridge/response"
+	TopicBridgeSelfTest  = "bridge/selftest"  // 자체 점검 루프백 (뒤에 /<nonce>)
+	TopicBridgeHeartbeat = "bridge/heartbeat" // PLC 하트비트 (시계 동기화 감사용 timestamp)
 	TopicMeiliConnection = "meili/v2/+/+/connection"
 	TopicMeiliState      = "meili/v2/+/+/state"
 	TopicMeiliFactsheet  = "meili/v2/+/+/factsheet"
@@ -143,6 +263,18 @@ func GetMeiliInstantActionsTopic(manufacturer, serialNumber string) string {
 	return "meili/v2/" + manufacturer + "/" + serialNumber + "/instantActions"
 }
 
+func GetMeiliStateTopic(manufacturer, serialNumber string) string {
+	return "meili/v2/" + manufacturer + "/" + serialNumber + "/state"
+}
+
+func GetMeiliConnectionTopic(manufacturer, serialNumber string) string {
+	return "meili/v2/" + manufacturer + "/" + serialNumber + "/connection"
+}
+
+func GetMeiliFactsheetTopic(manufacturer, serialNumber string) string {
+	return "meili/v2/" + manufacturer + "/" + serialNumber + "/factsheet"
+}
+
 // IsValidConnectionState 유효한 연결 상태인지 확인
 func IsValidConnectionState(state string) bool {
 	validStates := []string{
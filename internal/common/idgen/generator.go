@@ -5,7 +5,10 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // Generator ID 생성기
@@ -24,24 +27,24 @@ func NewGenerator(prefix ...string) *Generator {
 	}
 }
 
-// OrderID 오더 ID 생성 (32자리 hex)
+// OrderID 오더 ID 생성 (ID_STRATEGY에 따라 32자리 hex, ULID, UUIDv7)
 func (g *Generator) OrderID() string {
-	return g.generateHex(16)
+	return g.typedID(PrefixOrder)
 }
 
-// NodeID 노드 ID 생성 (32자리 hex)
+// NodeID 노드 ID 생성 (ID_STRATEGY에 따라 32자리 hex, ULID, UUIDv7)
 func (g *Generator) NodeID() string {
-	return g.generateHex(16)
+	return g.typedID(PrefixNode)
 }
 
-// ActionID 액션 ID 생성 (32자리 hex)
+// ActionID 액션 ID 생성 (ID_STRATEGY에 따라 32자리 hex, ULID, UUIDv7)
 func (g *Generator) ActionID() string {
-	return g.generateHex(16)
+	return g.typedID(PrefixAction)
 }
 
-// EdgeID 엣지 ID 생성 (32자리 hex)
+// EdgeID 엣지 ID 생성 (ID_STRATEGY에 따라 32자리 hex, ULID, UUIDv7)
 func (g *Generator) EdgeID() string {
-	return g.generateHex(16)
+	return g.typedID(PrefixEdge)
 }
 
 // UniqueID 고유 ID 생성 (16자리 hex + 타임스탬프)
@@ -81,11 +84,15 @@ func (g *Generator) generateHex(byteCount int) string {
 		return fmt.Sprintf("fallback_%d", time.Now().UnixNano())
 	}
 
-	hexStr := hex.EncodeToString(randomBytes)
+	return g.withPrefix(hex.EncodeToString(randomBytes))
+}
+
+// withPrefix 생성기 접두사가 있으면 붙임
+func (g *Generator) withPrefix(id string) string {
 	if g.prefix != "" {
-		return fmt.Sprintf("%s_%s", g.prefix, hexStr)
+		return fmt.Sprintf("%s_%s", g.prefix, id)
 	}
-	return hexStr
+	return id
 }
 
 // 전역 ID 생성기 인스턴스들
@@ -104,6 +111,15 @@ var (
 
 	// Session 세션 관련 생성기
 	Session = NewGenerator("session")
+
+	// Correlation 요청 추적용 생성기
+	Correlation = NewGenerator("cid")
+
+	// Job 작업 묶음 생성기
+	Job = NewGenerator("job")
+
+	// RobotToken 로봇 세션 토큰 식별자 생성기
+	RobotToken = NewGenerator("rtk")
 )
 
 // 편의 함수들 (전역 생성기 사용)
@@ -148,6 +164,21 @@ func SessionID() string {
 	return Session.SessionID()
 }
 
+// CorrelationID 요청 추적 ID 생성 (cid_ + 16자리 hex)
+func CorrelationID() string {
+	return Correlation.generateHex(8)
+}
+
+// RobotTokenID 로봇 세션 토큰 식별자 생성 (rtk_ + 16자리 hex)
+func RobotTokenID() string {
+	return RobotToken.generateHex(8)
+}
+
+// JobID 작업 묶음 ID 생성 (job_ + 16자리 hex)
+func JobID() string {
+	return Job.generateHex(8)
+}
+
 // IDValidator ID 유효성 검사기
 type IDValidator struct{}
 
@@ -166,14 +197,26 @@ func (v *IDValidator) IsValidHex(id string) bool {
 	return err == nil
 }
 
-// IsValidOrderID 오더 ID 유효성 검사
+// IsValidOrderID 오더 ID 유효성 검사 (ord_ 접두사 허용)
 func (v *IDValidator) IsValidOrderID(id string) bool {
-	return len(id) == 32 && v.IsValidHex(id)
+	return v.isValidGenerated(strings.TrimPrefix(id, PrefixOrder))
 }
 
-// IsValidActionID 액션 ID 유효성 검사
+// IsValidActionID 액션 ID 유효성 검사 (act_ 접두사 허용)
 func (v *IDValidator) IsValidActionID(id string) bool {
-	return len(id) == 32 && v.IsValidHex(id)
+	return v.isValidGenerated(strings.TrimPrefix(id, PrefixAction))
+}
+
+// isValidGenerated 지원하는 생성 방식 중 하나의 형식인지 확인 (32자리 hex, ULID, UUID)
+func (v *IDValidator) isValidGenerated(id string) bool {
+	if len(id) == 32 && v.IsValidHex(id) {
+		return true
+	}
+	if isULID(id) {
+		return true
+	}
+	_, err := uuid.Parse(id)
+	return err == nil && len(id) == 36
 }
 
 // 전역 검사기
@@ -0,0 +1,170 @@
+// internal/common/idgen/idgen_test.go
+package idgen
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// configure 테스트 동안 ID 생성 방식을 바꾸고 끝나면 기본값(hex, 접두사 없음)으로 되돌림
+func configure(t *testing.T, strategy string, prefixed bool) {
+	t.Helper()
+	if err := Configure(strategy, prefixed); err != nil {
+		t.Fatalf("Configure(%q, %v): %v", strategy, prefixed, err)
+	}
+	t.Cleanup(func() { _ = Configure(StrategyHex, false) })
+}
+
+func TestConcurrentIDsAreUnique(t *testing.T) {
+	const workers, perWorker = 16, 2000
+	for _, strategy := range Strategies {
+		for _, prefixed := range []bool{false, true} {
+			t.Run(strategy+map[bool]string{false: "", true: "/prefixed"}[prefixed], func(t *testing.T) {
+				configure(t, strategy, prefixed)
+
+				var mu sync.Mutex
+				seen := make(map[string]struct{}, workers*perWorker)
+				var wg sync.WaitGroup
+				for w := 0; w < workers; w++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						ids := make([]string, 0, perWorker)
+						for i := 0; i < perWorker; i++ {
+							ids = append(ids, OrderID())
+						}
+						mu.Lock()
+						defer mu.Unlock()
+						for _, id := range ids {
+							if _, dup := seen[id]; dup {
+								t.Errorf("duplicate %s id %q", strategy, id)
+							}
+							seen[id] = struct{}{}
+						}
+					}()
+				}
+				wg.Wait()
+				if len(seen) != workers*perWorker {
+					t.Fatalf("got %d unique ids, want %d", len(seen), workers*perWorker)
+				}
+			})
+		}
+	}
+}
+
+func TestULIDMonotonicWithinMillisecond(t *testing.T) {
+	configure(t, StrategyULID, false)
+
+	// 연속 생성한 ULID는 같은 밀리초 안에서도 문자열 순서로 증가해야 함
+	prev := OrderID()
+	sameMillisecond := 0
+	for i := 0; i < 10000; i++ {
+		id := OrderID()
+		if id <= prev {
+			t.Fatalf("ULID %q is not greater than previous %q", id, prev)
+		}
+		if id[:10] == prev[:10] {
+			sameMillisecond++
+		}
+		prev = id
+	}
+	if sameMillisecond == 0 {
+		t.Fatal("no ULIDs were generated within the same millisecond")
+	}
+
+	// 시계가 뒤로 가도 직전 ULID보다 커야 함 (마지막 타임스탬프를 미래로 설정)
+	ulidState.Lock()
+	ulidState.lastMs = time.Now().Add(time.Hour).UnixMilli()
+	ulidState.Unlock()
+	t.Cleanup(func() {
+		ulidState.Lock()
+		ulidState.lastMs = 0
+		ulidState.Unlock()
+	})
+	before := OrderID()
+	after := OrderID()
+	if after <= before {
+		t.Fatalf("ULID %q after clock step back is not greater than %q", after, before)
+	}
+	if after[:10] != before[:10] {
+		t.Fatalf("ULIDs %q and %q should share the held timestamp", before, after)
+	}
+}
+
+func TestIncrementEntropyOverflow(t *testing.T) {
+	var id [16]byte
+	for i := 6; i < 16; i++ {
+		id[i] = 0xff
+	}
+	if incrementEntropy(&id) {
+		t.Fatal("incrementEntropy should report overflow when the random part is all ones")
+	}
+}
+
+func TestValidatorsAcceptEveryStrategy(t *testing.T) {
+	for _, strategy := range Strategies {
+		for _, prefixed := range []bool{false, true} {
+			t.Run(strategy+map[bool]string{false: "", true: "/prefixed"}[prefixed], func(t *testing.T) {
+				configure(t, strategy, prefixed)
+
+				orderID, actionID := OrderID(), ActionID()
+				if prefixed != strings.HasPrefix(orderID, PrefixOrder) {
+					t.Errorf("order id %q: prefix present = %v, want %v", orderID, !prefixed, prefixed)
+				}
+				if prefixed != strings.HasPrefix(actionID, PrefixAction) {
+					t.Errorf("action id %q: prefix present = %v, want %v", actionID, !prefixed, prefixed)
+				}
+				if !IsValidOrderID(orderID) {
+					t.Errorf("IsValidOrderID(%q) = false", orderID)
+				}
+				if !IsValidActionID(actionID) {
+					t.Errorf("IsValidActionID(%q) = false", actionID)
+				}
+				if prefixed {
+					// 다른 종류의 접두사는 허용하지 않음
+					if IsValidOrderID(actionID) {
+						t.Errorf("IsValidOrderID(%q) = true for an action id", actionID)
+					}
+					if IsValidActionID(orderID) {
+						t.Errorf("IsValidActionID(%q) = true for an order id", orderID)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestValidatorsRejectMalformedIDs(t *testing.T) {
+	for _, id := range []string{
+		"",
+		"ord_",
+		"not-an-id",
+		strings.Repeat("g", 32),                  // hex가 아님
+		strings.Repeat("a", 30),                  // 32자리가 아닌 hex
+		"8ZZZZZZZZZZZZZZZZZZZZZZZZZ",             // 첫 글자가 7보다 큰 ULID
+		"01ARZ3NDEKTSV4RRFFQ69G5FAI",             // Crockford base32에 없는 I
+		"0190b1c2-7d4e-7abc-8def-0123456789a",    // 짧은 UUID
+		"ord_0190b1c2-7d4e-7abc-8def-0123456789", // 접두사 뒤 짧은 UUID
+	} {
+		if IsValidOrderID(id) {
+			t.Errorf("IsValidOrderID(%q) = true", id)
+		}
+		if IsValidActionID(id) {
+			t.Errorf("IsValidActionID(%q) = true", id)
+		}
+	}
+}
+
+func TestParseStrategy(t *testing.T) {
+	for input, want := range map[string]string{"": StrategyHex, " ULID ": StrategyULID, "uuidv7": StrategyUUIDv7, "hex": StrategyHex} {
+		got, err := ParseStrategy(input)
+		if err != nil || got != want {
+			t.Errorf("ParseStrategy(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseStrategy("snowflake"); err == nil {
+		t.Error("ParseStrategy(\"snowflake\") should fail")
+	}
+}
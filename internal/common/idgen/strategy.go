@@ -0,0 +1,180 @@
+// internal/common/idgen/strategy.go
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ID 생성 방식 (ID_STRATEGY)
+const (
+	StrategyHex    = "hex"    // 32자리 무작위 hex (기존 형식, 시간순 정렬 안 됨)
+	StrategyULID   = "ulid"   // 26자 Crockford base32 ULID (밀리초 단위 시간순 정렬, 같은 밀리초 안에서도 단조 증가)
+	StrategyUUIDv7 = "uuidv7" // RFC 9562 UUID 버전 7 (시간순 정렬)
+)
+
+// Strategies 지원하는 ID 생성 방식
+var Strategies = []string{StrategyHex, StrategyULID, StrategyUUIDv7}
+
+// ID 종류 접두사 (ID_PREFIXES를 켜면 오더/노드/엣지/액션 ID 앞에 붙임)
+const (
+	PrefixOrder  = "ord_"
+	PrefixNode   = "nod_"
+	PrefixEdge   = "edg_"
+	PrefixAction = "act_"
+)
+
+// options 프로세스 전체 ID 생성 설정
+type options struct {
+	strategy string
+	prefixed bool
+}
+
+var current atomic.Pointer[options]
+
+// ParseStrategy ID 생성 방식 이름 정규화 (빈 값이면 hex, 지원하지 않으면 오류)
+func ParseStrategy(strategy string) (string, error) {
+	strategy = strings.ToLower(strings.TrimSpace(strategy))
+	if strategy == "" {
+		return StrategyHex, nil
+	}
+	switch strategy {
+	case StrategyHex, StrategyULID, StrategyUUIDv7:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("unsupported ID_STRATEGY %q (%s)", strategy, strings.Join(Strategies, ", "))
+}
+
+// Configure 오더/노드/엣지/액션 ID 생성 방식과 종류 접두사 사용 여부 설정 (프로세스 시작 시 한 번 호출)
+func Configure(strategy string, prefixed bool) error {
+	strategy, err := ParseStrategy(strategy)
+	if err != nil {
+		return err
+	}
+	current.Store(&options{strategy: strategy, prefixed: prefixed})
+	return nil
+}
+
+// Strategy 현재 ID 생성 방식
+func Strategy() string {
+	return currentOptions().strategy
+}
+
+func currentOptions() *options {
+	if opts := current.Load(); opts != nil {
+		return opts
+	}
+	return &options{strategy: StrategyHex}
+}
+
+// typedID 설정된 방식으로 종류별 ID 생성 (접두사를 켰으면 typePrefix를 붙임)
+func (g *Generator) typedID(typePrefix string) string {
+	opts := currentOptions()
+	var id string
+	switch opts.strategy {
+	case StrategyULID:
+		id = g.withPrefix(newULID())
+	case StrategyUUIDv7:
+		id = g.withPrefix(newUUIDv7())
+	default:
+		id = g.generateHex(16)
+	}
+	if opts.prefixed {
+		return typePrefix + id
+	}
+	return id
+}
+
+// newUUIDv7 UUID 버전 7 생성 (실패하면 ULID)
+func newUUIDv7() string {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return newULID()
+	}
+	return id.String()
+}
+
+// crockford ULID 인코딩 문자 (I, L, O, U 제외)
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidState 같은 밀리초 안에서 단조 증가시키기 위한 마지막 ULID
+var ulidState struct {
+	sync.Mutex
+	lastMs int64
+	last   [16]byte
+}
+
+// newULID ULID 생성 (48비트 밀리초 타임스탬프 + 80비트 무작위)
+// 같은 밀리초에 다시 생성하거나 시계가 뒤로 가면 직전 ULID의 무작위 부분을 1 증가시켜 순서를 유지합니다.
+func newULID() string {
+	ulidState.Lock()
+	defer ulidState.Unlock()
+
+	ms := time.Now().UnixMilli()
+	var id [16]byte
+	if ms <= ulidState.lastMs && incrementEntropy(&ulidState.last) {
+		id = ulidState.last
+	} else {
+		var stamp [8]byte
+		binary.BigEndian.PutUint64(stamp[:], uint64(ms))
+		copy(id[:6], stamp[2:])
+		if _, err := rand.Read(id[6:]); err != nil {
+			binary.BigEndian.PutUint64(id[8:], uint64(time.Now().UnixNano()))
+		}
+		ulidState.lastMs = ms
+	}
+	ulidState.last = id
+	return encodeULID(id)
+}
+
+// incrementEntropy ULID의 무작위 80비트를 1 증가 (넘치면 false)
+func incrementEntropy(id *[16]byte) bool {
+	for i := 15; i >= 6; i-- {
+		id[i]++
+		if id[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeULID 128비트 값을 26자 Crockford base32로 인코딩 (첫 글자는 상위 3비트)
+func encodeULID(id [16]byte) string {
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+	var out [26]byte
+	for i := range out {
+		shift := uint(5 * (25 - i))
+		var v uint64
+		switch {
+		case shift >= 64:
+			v = hi >> (shift - 64)
+		case shift == 0:
+			v = lo
+		default:
+			v = lo>>shift | hi<<(64-shift)
+		}
+		out[i] = crockford[v&31]
+	}
+	return string(out[:])
+}
+
+// isULID 26자 Crockford base32이고 첫 글자가 7 이하인지 확인
+func isULID(id string) bool {
+	if len(id) != 26 || id[0] > '7' {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if !strings.ContainsRune(crockford, rune(id[i])) {
+			return false
+		}
+	}
+	return true
+}
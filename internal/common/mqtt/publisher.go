@@ -47,10 +47,11 @@ func (p *Publisher) PublishInstantAction(action interface{}) error {
 
 // PublishInitPosition 위치 초기화 요청 발행
 func (p *Publisher) PublishInitPosition(manufacturer, serialNumber string, pose map[string]interface{}) error {
-	actionID := idgen.UniqueID()
+	actionID := idgen.ActionID()
+	topic := constants.GetMeiliInstantActionsTopic(manufacturer, serialNumber)
 
 	request := map[string]interface{}{
-		"headerId":     utils.GetNextHeaderID(),
+		"headerId":     utils.NextHeaderID(topic),
 		"timestamp":    time.Now().Format(time.RFC3339Nano),
 		"version":      "2.0.0",
 		"manufacturer": manufacturer,
@@ -70,7 +71,6 @@ func (p *Publisher) PublishInitPosition(manufacturer, serialNumber string, pose
 		},
 	}
 
-	topic := constants.GetMeiliInstantActionsTopic(manufacturer, serialNumber)
 	utils.Logger.Infof("Sending initPosition request to %s (ActionID: %s)", topic, actionID)
 
 	return p.publishJSON(topic, request, "initPosition")
@@ -78,10 +78,11 @@ func (p *Publisher) PublishInitPosition(manufacturer, serialNumber string, pose
 
 // PublishFactsheetRequest 팩트시트 요청 발행
 func (p *Publisher) PublishFactsheetRequest(manufacturer, serialNumber string) error {
-	actionID := idgen.UniqueID()
+	actionID := idgen.ActionID()
+	topic := constants.GetMeiliInstantActionsTopic(manufacturer, serialNumber)
 
 	request := map[string]interface{}{
-		"headerId":     utils.GetNextHeaderID(),
+		"headerId":     utils.NextHeaderID(topic),
 		"timestamp":    time.Now().Format(time.RFC3339Nano),
 		"version":      "2.0.0",
 		"manufacturer": manufacturer,
@@ -96,7 +97,6 @@ func (p *Publisher) PublishFactsheetRequest(manufacturer, serialNumber string) e
 		},
 	}
 
-	topic := constants.GetMeiliInstantActionsTopic(manufacturer, serialNumber)
 	utils.Logger.Infof("Sending factsheet request to %s (ActionID: %s)", topic, actionID)
 
 	return p.publishJSON(topic, request, "factsheet request")
@@ -104,10 +104,11 @@ func (p *Publisher) PublishFactsheetRequest(manufacturer, serialNumber string) e
 
 // PublishCancelOrder 오더 취소 요청 발행
 func (p *Publisher) PublishCancelOrder() error {
-	actionID := idgen.UniqueID()
+	actionID := idgen.ActionID()
+	topic := constants.GetMeiliInstantActionsTopic(p.config.RobotManufacturer, p.config.RobotSerialNumber)
 
 	request := map[string]interface{}{
-		"headerId":     utils.GetNextHeaderID(),
+		"headerId":     utils.NextHeaderID(topic),
 		"timestamp":    time.Now().Format(time.RFC3339Nano),
 		"version":      "2.0.0",
 		"manufacturer": p.config.RobotManufacturer,
@@ -122,7 +123,6 @@ func (p *Publisher) PublishCancelOrder() error {
 		},
 	}
 
-	topic := constants.GetMeiliInstantActionsTopic(p.config.RobotManufacturer, p.config.RobotSerialNumber)
 	utils.Logger.Infof("Sending cancel order request to %s (ActionID: %s)", topic, actionID)
 
 	return p.publishJSON(topic, request, "cancel order")
@@ -167,7 +167,7 @@ func (p *Publisher) PublishDirectAction(baseCommand string, commandType rune, ar
 	actionID := idgen.ActionID()
 
 	directOrder := map[string]interface{}{
-		"headerId":      utils.GetNextHeaderID(),
+		"headerId":      utils.NextHeaderID(constants.GetMeiliOrderTopic(p.config.RobotManufacturer, p.config.RobotSerialNumber)),
 		"timestamp":     time.Now().Format(time.RFC3339Nano),
 		"version":       "2.0.0",
 		"manufacturer":  p.config.RobotManufacturer,
@@ -222,7 +222,8 @@ func (p *Publisher) publishJSON(topic string, payload interface{}, messageType s
 
 	utils.Logger.Infof("📤 SENDING %s: %s", messageType, string(msgData))
 
-	token := p.client.Publish(topic, 0, false, msgData)
+	options := p.config.RobotPublishOptions(topic)
+	token := p.client.Publish(topic, options.QoS, options.Retained, msgData)
 	if token.Wait() && token.Error() != nil {
 		return fmt.Errorf("MQTT publish failed for %s: %v", messageType, token.Error())
 	}
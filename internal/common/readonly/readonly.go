@@ -0,0 +1,87 @@
+// internal/common/readonly/readonly.go
+// Package readonly 재해 상황(DB 페일오버, 브로커 점검)용 읽기 전용 스위치
+// 켜져 있는 동안 PLC 명령, 오더 전송, 관리 API의 변경 요청을 거부하고 조회와 모니터링만 허용합니다.
+// 비상 정지와 오더 취소는 안전을 위해 계속 허용합니다.
+package readonly
+
+import (
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/utils"
+	"sync"
+	"time"
+)
+
+// Status 읽기 전용 상태
+type Status struct {
+	Enabled bool       `json:"enabled"`
+	Reason  string     `json:"reason,omitempty"`
+	Since   *time.Time `json:"since,omitempty"`
+}
+
+// Switch 프로세스 전체의 읽기 전용 스위치 (nil이면 항상 꺼짐)
+type Switch struct {
+	mu     sync.RWMutex
+	status Status
+}
+
+// New 읽기 전용 스위치 생성 (READ_ONLY=true면 켜진 상태로 시작)
+func New(enabled bool, reason string) *Switch {
+	s := &Switch{}
+	if enabled {
+		s.Set(true, reason)
+	}
+	return s
+}
+
+// Enabled 읽기 전용인지
+func (s *Switch) Enabled() bool {
+	if s == nil {
+		return false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.status.Enabled
+}
+
+// Status 현재 상태
+func (s *Switch) Status() Status {
+	if s == nil {
+		return Status{}
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.status
+}
+
+// Set 읽기 전용을 켜거나 끔 (이미 같은 상태면 사유만 갱신하고 시작 시각은 유지)
+func (s *Switch) Set(enabled bool, reason string) Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !enabled {
+		if s.status.Enabled {
+			utils.Logger.Warnf("✏️ Read-only mode disabled (was on since %s: %s)", s.status.Since.Format(time.RFC3339), s.status.Reason)
+		}
+		s.status = Status{}
+		return s.status
+	}
+	if !s.status.Enabled {
+		now := time.Now()
+		s.status.Since = &now
+		utils.Logger.Warnf("🔒 Read-only mode enabled: %s (PLC commands, order sends and admin changes are rejected)", reason)
+	}
+	s.status.Enabled = true
+	s.status.Reason = reason
+	return s.status
+}
+
+// Check 읽기 전용이면 operation을 거부하는 오류 (꺼져 있으면 nil)
+func (s *Switch) Check(operation string) error {
+	status := s.Status()
+	if !status.Enabled {
+		return nil
+	}
+	if status.Reason == "" {
+		return apperr.New(apperr.CodeReadOnly, "Bridge is in read-only mode: %s is not allowed", operation)
+	}
+	return apperr.New(apperr.CodeReadOnly, "Bridge is in read-only mode (%s): %s is not allowed", status.Reason, operation)
+}
@@ -19,6 +19,13 @@ const (
 
 	// Session 관련 (필요시 확장)
 	SessionPattern = "session:%s"
+
+	// VDA 5050 headerId 순번 (로봇/토픽별)
+	HeaderSequencePattern = "header_seq:%s"
+
+	// 구역 점유 예약/대기 (사이트별 해시, 필드는 구역 ID/시리얼 번호)
+	ZoneReservationsPattern = "zone_reservations:%s"
+	ZoneWaitersPattern      = "zone_waiters:%s"
 )
 
 // KeyGenerator Redis 키 생성기
@@ -54,6 +61,21 @@ func (k *KeyGenerator) Session(sessionID string) string {
 	return fmt.Sprintf(SessionPattern, sessionID)
 }
 
+// HeaderSequence 토픽(로봇/메시지 종류)별 headerId 순번 키 생성
+func (k *KeyGenerator) HeaderSequence(topic string) string {
+	return fmt.Sprintf(HeaderSequencePattern, topic)
+}
+
+// ZoneReservations 사이트의 구역 예약 해시 키 생성
+func (k *KeyGenerator) ZoneReservations(siteID string) string {
+	return fmt.Sprintf(ZoneReservationsPattern, siteID)
+}
+
+// ZoneWaiters 사이트의 구역 대기 해시 키 생성
+func (k *KeyGenerator) ZoneWaiters(siteID string) string {
+	return fmt.Sprintf(ZoneWaitersPattern, siteID)
+}
+
 // 전역 키 생성기 인스턴스
 var Keys = NewKeyGenerator()
 
@@ -84,6 +106,21 @@ func Session(sessionID string) string {
 	return Keys.Session(sessionID)
 }
 
+// HeaderSequence 토픽별 headerId 순번 키 생성
+func HeaderSequence(topic string) string {
+	return Keys.HeaderSequence(topic)
+}
+
+// ZoneReservations 사이트의 구역 예약 해시 키 생성
+func ZoneReservations(siteID string) string {
+	return Keys.ZoneReservations(siteID)
+}
+
+// ZoneWaiters 사이트의 구역 대기 해시 키 생성
+func ZoneWaiters(siteID string) string {
+	return Keys.ZoneWaiters(siteID)
+}
+
 // Pattern Matching 패턴 매칭용 함수들
 
 // AllPendingDirectCommands 모든 대기 중인 직접 명령 키 패턴
@@ -0,0 +1,204 @@
+// internal/common/validate/validate.go
+package validate
+
+import (
+	"fmt"
+	"math"
+	"mqtt-bridge/internal/common/apperr"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Pi theta 같은 각도 필드의 범위 (±π)
+const Pi = math.Pi
+
+// Problem 필드 하나의 검증 실패
+type Problem struct {
+	Field   string `json:"field"` // JSON 경로 (예: steps[0].node.theta)
+	Message string `json:"message"`
+}
+
+// Errors 구조체 검증 실패 목록 (apperr.Coder, apperr.Fielder)
+type Errors struct {
+	Problems []Problem
+}
+
+// Error 모든 문제 나열
+func (e *Errors) Error() string {
+	parts := make([]string, 0, len(e.Problems))
+	for _, p := range e.Problems {
+		parts = append(parts, p.Field+": "+p.Message)
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// ErrorCode 오류 코드 (apperr.Coder)
+func (e *Errors) ErrorCode() apperr.Code {
+	return apperr.CodeValidationFailed
+}
+
+// ErrorField 첫 번째로 실패한 필드 (apperr.Fielder)
+func (e *Errors) ErrorField() string {
+	if len(e.Problems) == 0 {
+		return ""
+	}
+	return e.Problems[0].Field
+}
+
+// Struct `validate` 태그로 구조체를 검증합니다 (문제가 없으면 nil, 있으면 *Errors).
+// 중첩 구조체, 구조체 포인터, 구조체 슬라이스는 자동으로 따라가며 필드 경로는 JSON 이름을 사용합니다.
+//
+//	required     문자열은 비어 있지 않아야 하고, 포인터/슬라이스는 nil/비어 있지 않아야 함
+//	min=N,max=N  숫자는 값의 범위, 문자열은 길이 (음수와 소수 허용, "pi"/"-pi"는 ±π)
+//	oneof=A B C  문자열 열거값 (빈 값은 required가 없으면 허용)
+func Struct(v interface{}) error {
+	var problems []Problem
+	walk(reflect.ValueOf(v), "", &problems)
+	if len(problems) > 0 {
+		return &Errors{Problems: problems}
+	}
+	return nil
+}
+
+// walk 값의 필드를 재귀적으로 검사
+func walk(v reflect.Value, path string, problems *[]Problem) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			value := v.Field(i)
+			fieldPath := joinPath(path, field)
+			if field.Anonymous {
+				fieldPath = path // 임베드된 구조체의 필드는 같은 단계에 나타남
+			}
+			if tag := field.Tag.Get("validate"); tag != "" && tag != "-" {
+				checkField(value, fieldPath, tag, problems)
+			}
+			walk(value, fieldPath, problems)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walk(v.Index(i), fmt.Sprintf("%s[%d]", path, i), problems)
+		}
+	}
+}
+
+// joinPath JSON 이름으로 필드 경로 생성
+func joinPath(path string, field reflect.StructField) string {
+	name := field.Name
+	if tag := field.Tag.Get("json"); tag != "" {
+		if jsonName, _, _ := strings.Cut(tag, ","); jsonName != "" && jsonName != "-" {
+			name = jsonName
+		}
+	}
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// checkField 필드 하나에 태그 규칙 적용
+func checkField(v reflect.Value, path, tag string, problems *[]Problem) {
+	add := func(format string, args ...interface{}) {
+		*problems = append(*problems, Problem{Field: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch name {
+		case "required":
+			if isEmpty(v) {
+				add("is required")
+				return // 비어 있으면 나머지 규칙은 의미 없음
+			}
+		case "min", "max":
+			limit, err := parseLimit(arg)
+			if err != nil {
+				add("invalid %s rule %q", name, arg)
+				continue
+			}
+			value, isLength, ok := measure(v)
+			if !ok {
+				continue
+			}
+			if name == "min" && value < limit {
+				if isLength {
+					add("must be at least %s characters", arg)
+				} else {
+					add("must be >= %s", arg)
+				}
+			}
+			if name == "max" && value > limit {
+				if isLength {
+					add("must be at most %s characters", arg)
+				} else {
+					add("must be <= %s", arg)
+				}
+			}
+		case "oneof":
+			if v.Kind() != reflect.String || v.String() == "" {
+				continue
+			}
+			allowed := strings.Fields(arg)
+			found := false
+			for _, a := range allowed {
+				if v.String() == a {
+					found = true
+					break
+				}
+			}
+			if !found {
+				add("must be one of %s", strings.Join(allowed, ", "))
+			}
+		}
+	}
+}
+
+// parseLimit min/max 인자 파싱 ("pi", "-pi" 허용)
+func parseLimit(arg string) (float64, error) {
+	switch arg {
+	case "pi":
+		return Pi, nil
+	case "-pi":
+		return -Pi, nil
+	}
+	return strconv.ParseFloat(arg, 64)
+}
+
+// measure 숫자는 값, 문자열/슬라이스는 길이 (비교할 수 없는 종류면 ok=false)
+func measure(v reflect.Value) (value float64, isLength bool, ok bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), false, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), false, true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), false, true
+	case reflect.String:
+		return float64(len([]rune(v.String()))), true, true
+	}
+	return 0, false, false
+}
+
+// isEmpty required 검사용 빈 값 여부
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(v.String()) == ""
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return v.IsNil() || (v.Kind() != reflect.Ptr && v.Kind() != reflect.Interface && v.Len() == 0)
+	}
+	return false
+}
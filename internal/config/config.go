@@ -2,8 +2,10 @@
 package config
 
 import (
+	"mqtt-bridge/internal/common/idgen"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -17,6 +19,20 @@ type Config struct {
 	DBPassword string
 	DBName     string
 
+	// Database 읽기 복제본 및 커넥션 풀 (0이면 database/sql 기본값)
+	DBReadDSN         string // 목록/이력 조회용 읽기 전용 DSN (빈 값이면 primary 사용)
+	DBMaxOpenConns    int
+	DBMaxIdleConns    int
+	DBConnMaxLifetime time.Duration
+	DBConnMaxIdleTime time.Duration
+
+	// Postgres/Redis 회로 차단기 (연속 실패 횟수가 0이면 비활성화)
+	BreakerFailureThreshold int
+	BreakerOpenTimeout      time.Duration
+
+	// 장애 주입 (스테이징 전용, true일 때만 /admin/faults로 전송 경로/DB/Redis에 지연과 실패를 주입할 수 있음)
+	FaultInjection bool
+
 	// Redis
 	RedisHost     string
 	RedisPort     string
@@ -30,15 +46,232 @@ type Config struct {
 	MQTTUsername     string
 	MQTTPassword     string
 	PlcResponseTopic string // Added PLC response topic
+	PlcCodec         string // text, json, binary
+	PlcStatusMap     string // 응답 상태 매핑 (예: SUCCESS=OK,FAILURE=NG), 매핑 파일보다 우선
+	PlcStatusMapFile string // 코덱별 응답 상태 매핑 JSON 파일 ({"default": {...}, "binary": {...}})
+
+	// PLC 명령 중복 억제 (PLC가 같은 명령을 짧은 간격으로 재전송하는 경우)
+	PlcDedupWindow    time.Duration // 첫 명령 수신 후 같은 명령을 무시하는 시간 (0이면 비활성화)
+	PlcDedupByPayload bool          // 명령 문자열뿐 아니라 페이로드 전체가 같을 때만 중복으로 판단
+
+	// MQTT 브로커 프로파일 (generic, aws-iot, azure-iot-hub)
+	MQTTBrokerProfile string
+	MQTTCAFile        string // 브로커 CA 인증서 (PEM)
+	MQTTCertFile      string // 클라이언트 인증서 (PEM)
+	MQTTKeyFile       string // 클라이언트 개인키 (PEM)
+	MQTTKeepAlive     time.Duration
+	AWSIoTIngestRule  string // 설정 시 $aws/rules/{rule}/ Basic Ingest로 발행
+	AzureIoTDeviceID  string // 빈 값이면 MQTTClientID 사용
+	AzureIoTHubHost   string // 빈 값이면 MQTTBroker 호스트 사용
+
+	// 브로커 연결이 끊긴 동안 발행 메시지를 보관할 디렉터리 (빈 값이면 비활성화, 재연결 시 순서대로 전송)
+	MQTTBufferDir      string
+	MQTTBufferMaxAge   time.Duration // 이보다 오래 보관된 메시지는 전송하지 않고 버림
+	MQTTBufferMaxBytes int64         // 보관 페이로드 총 크기 상한 (넘으면 새 메시지 발행 실패)
+
+	// 일시 중지한 구독 목록을 저장하는 JSON 파일 (빈 값이면 재연결 동안만 유지하고 재시작하면 모두 구독)
+	MQTTSubscriptionStateFile string
+
+	// 브릿지 구독 QoS와 공유 구독 (여러 브릿지 인스턴스가 같은 그룹으로 state 수신을 나눠 처리)
+	MQTTBrokerVersion       string          // 브로커가 지원하는 MQTT 버전 (3.1.1, 5), 3.1.1이면 공유 구독 불가
+	MQTTSubscriptionQoS     map[string]byte // 구독 종류(command, connection, state, factsheet, order)별 QoS, 없으면 0
+	MQTTSharedGroup         string          // 빈 값이면 공유 구독 안 함
+	MQTTSharedSubscriptions []string        // $share/<그룹>/으로 구독할 구독 종류
+
+	// MQTT 5 클라이언트 (MQTT_CLIENT_VERSION=5): 오더 메시지 만료, 팩트시트 요청의 response topic/correlation data,
+	// 발행 실패 사유 코드 보고. 3.1.1 클라이언트에서는 아래 설정을 무시합니다.
+	MQTTClientVersion  string
+	OrderMessageExpiry time.Duration // 브로커가 이 시간 안에 전달하지 못한 오더는 버림 (0이면 만료 없음)
+
+	// 로봇으로 보내는 메시지 종류별 MQTT QoS/retained (브로커 프로필이 허용하지 않으면 retained는 무시)
+	OrderQoS               byte
+	OrderRetained          bool // 늦게 연결한 로봇도 마지막 오더를 받음
+	InstantActionsQoS      byte // 비상 정지는 이 값과 관계없이 최소 QoS 1
+	InstantActionsRetained bool // 재연결할 때마다 마지막 즉시 액션이 다시 전달되므로 권장하지 않음
 
 	// Robot Configuration
 	RobotSerialNumber string
 	RobotManufacturer string
+	RobotDiscovery    bool // 제조사 와일드카드 구독으로 새 로봇을 승인 대기로 등록하고, 승인된 로봇에만 오더 배차
+
+	// Site (멀티 테넌시: 로봇, 템플릿, 오더 실행의 소속 사이트)
+	SiteID string
 
 	// Application
 	LogLevel       string
 	TimeoutSeconds int
 	Timeout        time.Duration
+
+	// 최근 로그 메모리 보관 건수 (/admin/logs, /admin/logs/stream으로 조회, 0이면 비활성화)
+	LogBufferSize int
+
+	// State Sink (Kafka / NATS)
+	StateSinkType  string
+	StateSinkURL   string
+	StateSinkTopic string
+
+	// 라우팅되는 MQTT 메시지를 기록할 JSON Lines 파일 (빈 값이면 비활성화, bridgectl replay로 재생)
+	RecordFile string
+
+	// Outbox
+	OutboxPollInterval time.Duration
+	OutboxMaxAttempts  int
+
+	// 재시작 복구: 실행 중이던 단계를 확인하기 위해 로봇 상태를 기다리는 시간 (0이면 확인하지 않음)
+	ReconcileStateTimeout time.Duration
+
+	// 오더 수신 확인: 오더 발행 후 그 오더를 참조하는 상태 메시지를 기다리는 시간 (0이면 확인하지 않음)
+	// 시간 안에 오지 않으면 OrderAckRetries번까지 다시 보내고, 그래도 오지 않으면 단계를 NOT_ACKNOWLEDGED로 실패 처리
+	OrderAckTimeout time.Duration
+	OrderAckRetries int
+
+	// 오더 실행 제한 시간 기본값 (템플릿 deadline_seconds가 0일 때 사용, 0이면 제한 없음)
+	// 시작 후 이 시간이 지나도 RUNNING이면 로봇에 cancelOrder를 보내고 EXPIRED로 끝내 FailureOrder로 분기
+	OrderDeadline time.Duration
+
+	// 구역 점유 예약: 노드/엣지 템플릿이 선언한 구역을 노드를 보내기 전에 예약 (다른 로봇이 점유 중이면 대기)
+	ZoneReservations   bool
+	ZoneReservationTTL time.Duration // 예약 만료 (0이면 오더가 끝나거나 운영자가 해제할 때까지 유지)
+
+	// 오더 페이로드 압축 (off, gzip)과 최대 전송 크기
+	// 압축하면 MQTT 3.1.1은 gzip 매직 바이트(1f 8b)로, MQTT 5는 content-encoding 사용자 속성으로, HTTP는 Content-Encoding 헤더로 알림
+	OrderCompression      string
+	OrderCompressMinBytes int    // 이 크기 이상인 오더만 압축
+	OrderMaxPayloadBytes  int    // 압축 후 전송 크기 상한 (0이면 제한 없음)
+	OrderOversizePolicy   string // 상한을 넘으면 reject(거부) 또는 split(오더 업데이트로 나눠 전송)
+
+	// 오더/노드/엣지/액션 ID 생성 방식 (hex, ulid, uuidv7)과 종류 접두사 (ord_, nod_, edg_, act_) 사용 여부
+	IDStrategy string
+	IDPrefixes bool
+
+	// 오더 전송 전 로봇 사전 점검 (쉼표 구분, 예: "no_errors,position_initialized,automatic_mode,estop_released", 비어 있으면 끔)
+	// 점검에 실패하면 오더를 보내지 않고 단계를 실패 처리하며, 로봇별로 개별 점검을 끌 수 있음
+	PreflightChecks string
+
+	// 읽기 전용으로 시작 (DB 페일오버/브로커 점검 중 재시작할 때, /admin/read-only로 해제)
+	ReadOnly       bool
+	ReadOnlyReason string
+
+	// 위치 미초기화 로봇에 자동으로 보내는 initPosition에 현재 위치 대신 저장된 홈 위치 사용
+	InitPositionFromHome bool
+
+	// 오더 전송 경로 (쉼표 구분 우선순위, 예: "mqtt,http"면 MQTT 실패 시 로봇 HTTP로 재시도)
+	TransportFailover string
+	RobotHTTPURL      string
+	RobotHTTPTimeout  time.Duration
+
+	// 로봇 HTTP 콜백 세션 토큰 (/admin/robots/<serial>/tokens로 발급/교체/폐기)
+	RobotTokenTTL           time.Duration // 발급한 토큰 유효 기간 (0이면 만료 없음)
+	RobotTokenRotationGrace time.Duration // 교체 뒤 기존 토큰을 계속 받아 주는 시간 (0이면 즉시 폐기)
+
+	// 전송 경로/메시지 종류별 발행 제한 시간과 재시도 (TRANSPORT_POLICIES, 예: "mqtt.order=5s,http.order=30s/2")
+	// 재시도 사이에는 TransportRetryBackoff부터 두 배씩 늘리며 무작위 지터를 더해 기다립니다.
+	TransportPolicies     map[string]SendPolicy
+	TransportRetryBackoff time.Duration
+
+	// 전송 디버그 기록 (요청/응답을 링 버퍼에 보관, /admin/transports/<name>/debug로 조회)
+	TransportDebug       []string // 기록할 전송 경로 (빈 값이면 비활성화)
+	TransportDebugBuffer int      // 경로별 최대 기록 건수
+	TransportDebugRedact []string // 값을 가릴 JSON 필드/액션 파라미터 키
+
+	// 전송 전 페이로드 변환 규칙 JSON 파일 (제조사/로봇/전송 경로별 필드 이름 변경, 단위 변환 등, 빈 값이면 변환 없음)
+	PayloadTransformFile string
+
+	// Tracing (OTLP 엔드포인트가 비어 있으면 비활성화)
+	OTLPEndpoint     string
+	OTLPInsecure     bool
+	OTelServiceName  string
+	TraceSampleRatio float64
+
+	// 원시 instantActions 전송 시 허용할 actionType 목록 ("*"는 전체 허용)
+	InstantActionAllowlist []string
+
+	// 비상 정지 시 전송할 안전 instantAction 목록 (순서대로 한 메시지로 전송)
+	// EStopOrderTemplate이 설정되면 instantAction 대신 해당 템플릿(단일 단계)으로 비상 오더 전송
+	EStopActions       []string
+	EStopOrderTemplate string
+
+	// 오더 결과물 저장소 (fs: 로컬 디렉터리, s3: S3/MinIO 호환 스토리지)
+	ArtifactStore       string
+	ArtifactDir         string
+	ArtifactS3Endpoint  string // 예: https://s3.ap-northeast-2.amazonaws.com, http://minio:9000
+	ArtifactS3Bucket    string
+	ArtifactS3Region    string
+	ArtifactS3AccessKey string
+	ArtifactS3SecretKey string
+	ArtifactURLExpiry   time.Duration // pre-signed URL 유효 시간
+
+	// MQTT Ingest Pool (INGEST_POOL=false면 paho 콜백에서 직접 처리)
+	IngestPool                bool
+	IngestStateWorkers        int
+	IngestStateQueueSize      int
+	IngestCommandQueueSize    int
+	IngestConnectionQueueSize int
+	IngestOtherQueueSize      int
+	IngestStateFlushInterval  time.Duration // 0보다 크면 로봇 상태 컬럼을 상태 워커 샤드별로 모아 이 주기마다 로봇당 UPDATE 한 번으로 기록
+
+	// 수신 페이로드 스키마 검증 (off, lenient, strict)
+	PayloadSchemaMode string
+
+	// 수신 메시지 timestamp 검사 (오차는 기록만 하고, 마지막으로 처리한 것보다 오래된 state 메시지는 버림)
+	MessageMaxSkew  time.Duration // 0이면 오차 검사 안 함
+	DropStaleStates bool
+
+	// 로봇별 state 메시지 지연(헤더 timestamp와 수신 시각 차이)을 보관할 최근 메시지 수 (0이면 측정 안 함)
+	StateLatencyWindow int
+
+	// 시계 동기화 감사: 로봇 메시지, PLC 하트비트(bridge/heartbeat), DB 서버 시각을 브릿지 시계와 비교
+	ClockAuditWindow    int           // 장치별로 보관할 최근 표본 수 (0이면 감사하지 않고 하트비트도 구독하지 않음)
+	ClockDriftThreshold time.Duration // 추정 시계 오차(절댓값)가 이 값을 넘는 장치를 표시
+
+	// 로봇 VDA 버전 호환성 검사 (off, lenient, strict)와 기능별 지원 버전 (feature=min..max, 쉼표 구분)
+	VdaCompatibilityMode string
+	VdaFeatureVersions   string
+
+	// Robot State Cache (0이면 Redis state 캐시 비활성화)
+	StateCacheFlushInterval time.Duration
+
+	// Execution History Retention (보존 기간이 모두 0이면 정리하지 않음)
+	RetentionCommandTTL        time.Duration
+	RetentionOrderExecutionTTL time.Duration
+	RetentionStepExecutionTTL  time.Duration
+	RetentionInterval          time.Duration // 0이면 백그라운드 정리 비활성화 (수동 실행만 가능)
+	RetentionBatchSize         int
+	RetentionArchive           string // none, file, table
+	RetentionArchiveDir        string
+
+	// Redis 키 정리 (주기가 0이면 비활성화)
+	RedisJanitorInterval time.Duration // 고아 step_actions/pending_direct_command 키 정리 주기
+	RedisStepActionsTTL  time.Duration // 단계 액션 상태 키 만료 (생성/갱신 시 적용, 0이면 만료 없음)
+
+	// 알림 (Slack 웹훅이나 SMTP가 하나도 설정되지 않으면 비활성화)
+	AlertRules           string        // 규칙=기준 쉼표 구분 (예: "robot_offline=5m,command_failed,battery_low=15,order_stuck=30m,state_latency=2s,clock_drift=5s,order_finished")
+	AlertInterval        time.Duration // 규칙 평가 주기
+	AlertCooldown        time.Duration // 같은 대상의 같은 규칙 알림을 다시 보내기까지의 최소 간격
+	AlertSlackWebhookURL string
+	AlertSMTPAddr        string // host:port
+	AlertSMTPUsername    string
+	AlertSMTPPassword    string
+	AlertSMTPFrom        string
+	AlertSMTPTo          string // 받는 사람 (쉼표 구분)
+	AlertRobotSelector   string // 로봇 메타데이터 셀렉터 (예: "team=ops,!retired", 일치하는 로봇만 알림, 비우면 모두)
+
+	// Health (빈 값이면 헬스 체크 HTTP 서버 비활성화)
+	HealthAddr           string
+	HealthStateStaleness time.Duration
+
+	// gRPC API (빈 값이면 비활성화, 관리 API와 같은 조회/제어 작업과 로봇 state 스트림)
+	GRPCAddr string
+
+	// 헬스/관리 HTTP 서버 접근 제어
+	CORSAllowedOrigins string // 쉼표 구분 ("*"는 모든 출처, 빈 값이면 CORS 헤더를 보내지 않음)
+	CORSAllowedMethods string
+	CORSAllowedHeaders string
+	AdminIPAllowlist   string // /admin/ 변경 요청(POST/PUT/PATCH/DELETE)을 보낼 수 있는 IP/CIDR (쉼표 구분, 빈 값이면 제한 없음, bridgectl은 127.0.0.1)
+	AccessPolicyFile   string // 위 설정을 덮어쓰는 JSON 파일 (바뀌면 다시 읽음)
+
+	// 템플릿/PLC 매핑을 받아 올 수 있는 다른 브릿지 주소 (쉼표 구분, 예: 스테이징 브릿지, 빈 값이면 /admin/sync/diff, apply 비활성화)
+	TemplateSyncSources []string
 }
 
 func Load() (*Config, error) {
@@ -48,29 +281,261 @@ func Load() (*Config, error) {
 	}
 
 	redisDB, _ := strconv.Atoi(getEnv("REDIS_DB", "0"))
+	dbMaxOpenConns, _ := strconv.Atoi(getEnv("DB_MAX_OPEN_CONNS", "0"))
+	dbMaxIdleConns, _ := strconv.Atoi(getEnv("DB_MAX_IDLE_CONNS", "0"))
+	dbConnMaxLifetimeSeconds, _ := strconv.Atoi(getEnv("DB_CONN_MAX_LIFETIME_SECONDS", "0"))
+	dbConnMaxIdleSeconds, _ := strconv.Atoi(getEnv("DB_CONN_MAX_IDLE_SECONDS", "0"))
+	breakerFailureThreshold, _ := strconv.Atoi(getEnv("BREAKER_FAILURE_THRESHOLD", "5"))
+	breakerOpenSeconds, _ := strconv.Atoi(getEnv("BREAKER_OPEN_SECONDS", "30"))
+	faultInjection, _ := strconv.ParseBool(getEnv("FAULT_INJECTION", "false"))
+	robotDiscovery, _ := strconv.ParseBool(getEnv("ROBOT_DISCOVERY", "false"))
+	orderQoS, err := parseQoS("ORDER_QOS", getEnv("ORDER_QOS", "0"))
+	if err != nil {
+		return nil, err
+	}
+	orderRetained, _ := strconv.ParseBool(getEnv("ORDER_RETAINED", "false"))
+	instantActionsQoS, err := parseQoS("INSTANT_ACTIONS_QOS", getEnv("INSTANT_ACTIONS_QOS", "0"))
+	if err != nil {
+		return nil, err
+	}
+	instantActionsRetained, _ := strconv.ParseBool(getEnv("INSTANT_ACTIONS_RETAINED", "false"))
+	plcDedupWindowMillis, _ := strconv.Atoi(getEnv("PLC_DEDUP_WINDOW_MS", "0"))
+	plcDedupByPayload, _ := strconv.ParseBool(getEnv("PLC_DEDUP_BY_PAYLOAD", "false"))
 	timeoutSeconds, _ := strconv.Atoi(getEnv("TIMEOUT_SECONDS", "30"))
+	outboxPollMillis, _ := strconv.Atoi(getEnv("OUTBOX_POLL_INTERVAL_MS", "1000"))
+	outboxMaxAttempts, _ := strconv.Atoi(getEnv("OUTBOX_MAX_ATTEMPTS", "5"))
+	reconcileStateSeconds, _ := strconv.Atoi(getEnv("RECONCILE_STATE_TIMEOUT_SECONDS", "30"))
+	orderAckTimeoutSeconds, _ := strconv.Atoi(getEnv("ORDER_ACK_TIMEOUT_SECONDS", "0"))
+	orderDeadlineSeconds, _ := strconv.Atoi(getEnv("ORDER_DEADLINE_SECONDS", "0"))
+	orderAckRetries, _ := strconv.Atoi(getEnv("ORDER_ACK_RETRIES", "1"))
+	zoneReservations, _ := strconv.ParseBool(getEnv("ZONE_RESERVATIONS", "false"))
+	zoneReservationTTLSeconds, _ := strconv.Atoi(getEnv("ZONE_RESERVATION_TTL_SECONDS", "0"))
+	initPositionFromHome, _ := strconv.ParseBool(getEnv("INIT_POSITION_FROM_HOME", "false"))
+	readOnly, _ := strconv.ParseBool(getEnv("READ_ONLY", "false"))
+	robotHTTPTimeoutSeconds, _ := strconv.Atoi(getEnv("ROBOT_HTTP_TIMEOUT_SECONDS", "5"))
+	transportDebugBuffer, _ := strconv.Atoi(getEnv("TRANSPORT_DEBUG_BUFFER", "100"))
+	transportPolicies, err := parseTransportPolicies(getEnv("TRANSPORT_POLICIES", ""))
+	if err != nil {
+		return nil, err
+	}
+	mqttSubscriptionQoS, err := parseSubscriptionQoS(getEnv("MQTT_SUBSCRIPTION_QOS", ""))
+	if err != nil {
+		return nil, err
+	}
+	mqttBrokerVersion := getEnv("MQTT_BROKER_VERSION", MQTTVersion311)
+	mqttSharedGroup := getEnv("MQTT_SHARED_GROUP", "")
+	mqttSharedSubscriptions, err := parseSharedSubscriptions(mqttBrokerVersion, mqttSharedGroup,
+		getEnv("MQTT_SHARED_SUBSCRIPTIONS", SubscriptionState))
+	if err != nil {
+		return nil, err
+	}
+	mqttClientVersion := getEnv("MQTT_CLIENT_VERSION", MQTTVersion311)
+	if err := parseClientVersion(mqttClientVersion, mqttBrokerVersion); err != nil {
+		return nil, err
+	}
+	orderMessageExpirySeconds, _ := strconv.Atoi(getEnv("ORDER_MESSAGE_EXPIRY_SECONDS", "0"))
+	transportRetryBackoffMillis, _ := strconv.Atoi(getEnv("TRANSPORT_RETRY_BACKOFF_MS", "200"))
+	logBufferSize, _ := strconv.Atoi(getEnv("LOG_BUFFER_SIZE", "1000"))
+	mqttKeepAliveSeconds, _ := strconv.Atoi(getEnv("MQTT_KEEPALIVE_SECONDS", "0"))
+	mqttBufferMaxAgeSeconds, _ := strconv.Atoi(getEnv("MQTT_BUFFER_MAX_AGE_SECONDS", "300"))
+	mqttBufferMaxBytes, _ := strconv.ParseInt(getEnv("MQTT_BUFFER_MAX_BYTES", "10485760"), 10, 64)
+	artifactURLExpirySeconds, _ := strconv.Atoi(getEnv("ARTIFACT_URL_EXPIRY_SECONDS", "900"))
+	ingestPool, _ := strconv.ParseBool(getEnv("INGEST_POOL", "true"))
+	ingestStateWorkers, _ := strconv.Atoi(getEnv("INGEST_STATE_WORKERS", "4"))
+	ingestStateQueueSize, _ := strconv.Atoi(getEnv("INGEST_STATE_QUEUE_SIZE", "50"))
+	ingestCommandQueueSize, _ := strconv.Atoi(getEnv("INGEST_COMMAND_QUEUE_SIZE", "100"))
+	ingestConnectionQueueSize, _ := strconv.Atoi(getEnv("INGEST_CONNECTION_QUEUE_SIZE", "100"))
+	ingestOtherQueueSize, _ := strconv.Atoi(getEnv("INGEST_OTHER_QUEUE_SIZE", "100"))
+	ingestStateFlushMs, _ := strconv.Atoi(getEnv("INGEST_STATE_FLUSH_MS", "0"))
+	messageMaxSkewSeconds, _ := strconv.Atoi(getEnv("MESSAGE_MAX_SKEW_SECONDS", "30"))
+	dropStaleStates, _ := strconv.ParseBool(getEnv("DROP_STALE_STATES", "true"))
+	stateLatencyWindow, _ := strconv.Atoi(getEnv("STATE_LATENCY_WINDOW", "100"))
+	robotTokenTTLHours, _ := strconv.Atoi(getEnv("ROBOT_TOKEN_TTL_HOURS", "720"))
+	robotTokenRotationGraceSeconds, _ := strconv.Atoi(getEnv("ROBOT_TOKEN_ROTATION_GRACE_SECONDS", "300"))
+	clockAuditWindow, _ := strconv.Atoi(getEnv("CLOCK_AUDIT_WINDOW", "50"))
+	clockDriftThresholdMs, _ := strconv.Atoi(getEnv("CLOCK_DRIFT_THRESHOLD_MS", "2000"))
+	stateCacheFlushMillis, _ := strconv.Atoi(getEnv("STATE_CACHE_FLUSH_MS", "250"))
+	retentionCommandDays, _ := strconv.Atoi(getEnv("RETENTION_COMMAND_DAYS", "0"))
+	retentionOrderExecutionDays, _ := strconv.Atoi(getEnv("RETENTION_ORDER_EXECUTION_DAYS", "0"))
+	retentionStepExecutionDays, _ := strconv.Atoi(getEnv("RETENTION_STEP_EXECUTION_DAYS", "0"))
+	retentionIntervalMinutes, _ := strconv.Atoi(getEnv("RETENTION_INTERVAL_MINUTES", "60"))
+	retentionBatchSize, _ := strconv.Atoi(getEnv("RETENTION_BATCH_SIZE", "500"))
+	redisJanitorIntervalMinutes, _ := strconv.Atoi(getEnv("REDIS_JANITOR_INTERVAL_MINUTES", "10"))
+	redisStepActionsTTLHours, _ := strconv.Atoi(getEnv("REDIS_STEP_ACTIONS_TTL_HOURS", "24"))
+	var templateSyncSources []string
+	for _, source := range strings.Split(getEnv("TEMPLATE_SYNC_SOURCES", ""), ",") {
+		if source = strings.TrimSpace(source); source != "" {
+			templateSyncSources = append(templateSyncSources, source)
+		}
+	}
+	alertIntervalSeconds, _ := strconv.Atoi(getEnv("ALERT_INTERVAL_SECONDS", "30"))
+	alertCooldownMinutes, _ := strconv.Atoi(getEnv("ALERT_COOLDOWN_MINUTES", "15"))
+	healthStalenessSeconds, _ := strconv.Atoi(getEnv("HEALTH_STATE_STALENESS_SECONDS", "60"))
+	otlpInsecure, _ := strconv.ParseBool(getEnv("OTEL_EXPORTER_OTLP_INSECURE", "false"))
+	traceSampleRatio, err := strconv.ParseFloat(getEnv("TRACE_SAMPLE_RATIO", "1.0"), 64)
+	if err != nil {
+		traceSampleRatio = 1.0
+	}
+	orderCompressMinBytes, _ := strconv.Atoi(getEnv("ORDER_COMPRESS_MIN_BYTES", "1024"))
+	orderMaxPayloadBytes, _ := strconv.Atoi(getEnv("ORDER_MAX_PAYLOAD_BYTES", "0"))
+	idStrategy, err := idgen.ParseStrategy(getEnv("ID_STRATEGY", idgen.StrategyHex))
+	if err != nil {
+		return nil, err
+	}
+	idPrefixes, _ := strconv.ParseBool(getEnv("ID_PREFIXES", "false"))
 
 	return &Config{
-		DBHost:            getEnv("DB_HOST", "localhost"),
-		DBPort:            getEnv("DB_PORT", "5432"),
-		DBUser:            getEnv("DB_USER", "postgres"),
-		DBPassword:        getEnv("DB_PASSWORD", "password"),
-		DBName:            getEnv("DB_NAME", "mqtt_bridge"),
-		RedisHost:         getEnv("REDIS_HOST", "localhost"),
-		RedisPort:         getEnv("REDIS_PORT", "6379"),
-		RedisPassword:     getEnv("REDIS_PASSWORD", ""),
-		RedisDB:           redisDB,
-		MQTTBroker:        getEnv("MQTT_BROKER", "tcp://localhost:1883"),
-		MQTTPort:          getEnv("MQTT_PORT", "1883"),
-		MQTTClientID:      getEnv("MQTT_CLIENT_ID", "DEX0002_PLC_BRIDGE"),
-		MQTTUsername:      getEnv("MQTT_USERNAME", "DEX0002_PLC_BRIDGE"),
-		MQTTPassword:      getEnv("MQTT_PASSWORD", "DEX0002_PLC_BRIDGE"),
-		PlcResponseTopic:  getEnv("PLC_RESPONSE_TOPIC", "bridge/response"),
-		RobotSerialNumber: getEnv("ROBOT_SERIAL_NUMBER", "DEX0002"),
-		RobotManufacturer: getEnv("ROBOT_MANUFACTURER", "Roboligent"),
-		LogLevel:          getEnv("LOG_LEVEL", "info"),
-		TimeoutSeconds:    timeoutSeconds,
-		Timeout:           time.Duration(timeoutSeconds) * time.Second,
+		DBHost:                  getEnv("DB_HOST", "localhost"),
+		DBPort:                  getEnv("DB_PORT", "5432"),
+		DBUser:                  getEnv("DB_USER", "postgres"),
+		DBPassword:              getEnv("DB_PASSWORD", "password"),
+		DBName:                  getEnv("DB_NAME", "mqtt_bridge"),
+		DBReadDSN:               getEnv("DB_READ_DSN", ""),
+		DBMaxOpenConns:          dbMaxOpenConns,
+		DBMaxIdleConns:          dbMaxIdleConns,
+		DBConnMaxLifetime:       time.Duration(dbConnMaxLifetimeSeconds) * time.Second,
+		DBConnMaxIdleTime:       time.Duration(dbConnMaxIdleSeconds) * time.Second,
+		BreakerFailureThreshold: breakerFailureThreshold,
+		BreakerOpenTimeout:      time.Duration(breakerOpenSeconds) * time.Second,
+		FaultInjection:          faultInjection,
+		RedisHost:               getEnv("REDIS_HOST", "localhost"),
+		RedisPort:               getEnv("REDIS_PORT", "6379"),
+		RedisPassword:           getEnv("REDIS_PASSWORD", ""),
+		RedisDB:                 redisDB,
+		MQTTBroker:              getEnv("MQTT_BROKER", "tcp://localhost:1883"),
+		MQTTPort:                getEnv("MQTT_PORT", "1883"),
+		MQTTClientID:            getEnv("MQTT_CLIENT_ID", "DEX0002_PLC_BRIDGE"),
+		MQTTUsername:            getEnv("MQTT_USERNAME", "DEX0002_PLC_BRIDGE"),
+		MQTTPassword:            getEnv("MQTT_PASSWORD", "DEX0002_PLC_BRIDGE"),
+		PlcResponseTopic:        getEnv("PLC_RESPONSE_TOPIC", "bridge/response"),
+		PlcCodec:                getEnv("PLC_CODEC", "text"),
+		PlcStatusMap:            getEnv("PLC_STATUS_MAP", ""),
+		PlcStatusMapFile:        getEnv("PLC_STATUS_MAP_FILE", ""),
+		PlcDedupWindow:          time.Duration(plcDedupWindowMillis) * time.Millisecond,
+		PlcDedupByPayload:       plcDedupByPayload,
+		MQTTBrokerProfile:       getEnv("MQTT_BROKER_PROFILE", "generic"),
+		MQTTCAFile:              getEnv("MQTT_CA_FILE", ""),
+		MQTTCertFile:            getEnv("MQTT_CERT_FILE", ""),
+		MQTTKeyFile:             getEnv("MQTT_KEY_FILE", ""),
+		MQTTKeepAlive:           time.Duration(mqttKeepAliveSeconds) * time.Second,
+		AWSIoTIngestRule:        getEnv("AWS_IOT_INGEST_RULE", ""),
+		AzureIoTDeviceID:        getEnv("AZURE_IOT_DEVICE_ID", ""),
+		AzureIoTHubHost:         getEnv("AZURE_IOT_HUB_HOST", ""),
+		MQTTBufferDir:           getEnv("MQTT_BUFFER_DIR", ""),
+		MQTTBufferMaxAge:        time.Duration(mqttBufferMaxAgeSeconds) * time.Second,
+		MQTTBufferMaxBytes:      mqttBufferMaxBytes,
+		RobotSerialNumber:       getEnv("ROBOT_SERIAL_NUMBER", "DEX0002"),
+		RobotManufacturer:       getEnv("ROBOT_MANUFACTURER", "Roboligent"),
+		SiteID:                  getEnv("SITE_ID", "default"),
+		LogLevel:                getEnv("LOG_LEVEL", "info"),
+		TimeoutSeconds:          timeoutSeconds,
+		Timeout:                 time.Duration(timeoutSeconds) * time.Second,
+		StateSinkType:           getEnv("STATE_SINK_TYPE", ""),
+		StateSinkURL:            getEnv("STATE_SINK_URL", ""),
+		StateSinkTopic:          getEnv("STATE_SINK_TOPIC", "mqtt-bridge.robot"),
+		RecordFile:              getEnv("RECORD_FILE", ""),
+		OutboxPollInterval:      time.Duration(outboxPollMillis) * time.Millisecond,
+		OutboxMaxAttempts:       outboxMaxAttempts,
+		ReconcileStateTimeout:   time.Duration(reconcileStateSeconds) * time.Second,
+		InitPositionFromHome:    initPositionFromHome,
+		ReadOnly:                readOnly,
+		ReadOnlyReason:          getEnv("READ_ONLY_REASON", "READ_ONLY=true"),
+		TransportFailover:       getEnv("TRANSPORT_FAILOVER", "mqtt"),
+		RobotHTTPURL:            getEnv("ROBOT_HTTP_URL", ""),
+		RobotHTTPTimeout:        time.Duration(robotHTTPTimeoutSeconds) * time.Second,
+		TransportPolicies:       transportPolicies,
+		TransportRetryBackoff:   time.Duration(transportRetryBackoffMillis) * time.Millisecond,
+		TransportDebug:          strings.Split(getEnv("TRANSPORT_DEBUG", ""), ","),
+		TransportDebugBuffer:    transportDebugBuffer,
+		TransportDebugRedact:    strings.Split(getEnv("TRANSPORT_DEBUG_REDACT", "password,token,secret,apiKey,authorization"), ","),
+		OTLPEndpoint:            getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+		OTLPInsecure:            otlpInsecure,
+		OTelServiceName:         getEnv("OTEL_SERVICE_NAME", "mqtt-bridge"),
+		TraceSampleRatio:        traceSampleRatio,
+		InstantActionAllowlist: strings.Split(getEnv("INSTANT_ACTION_ALLOWLIST",
+			"cancelOrder,startPause,stopPause,stateRequest,factsheetRequest"), ","),
+		EStopActions:               strings.Split(getEnv("ESTOP_ACTIONS", "startPause,cancelOrder"), ","),
+		EStopOrderTemplate:         getEnv("ESTOP_ORDER_TEMPLATE", ""),
+		ArtifactStore:              getEnv("ARTIFACT_STORE", "fs"),
+		ArtifactDir:                getEnv("ARTIFACT_DIR", "./artifacts"),
+		ArtifactS3Endpoint:         getEnv("ARTIFACT_S3_ENDPOINT", ""),
+		ArtifactS3Bucket:           getEnv("ARTIFACT_S3_BUCKET", ""),
+		ArtifactS3Region:           getEnv("ARTIFACT_S3_REGION", "us-east-1"),
+		ArtifactS3AccessKey:        getEnv("ARTIFACT_S3_ACCESS_KEY", ""),
+		ArtifactS3SecretKey:        getEnv("ARTIFACT_S3_SECRET_KEY", ""),
+		ArtifactURLExpiry:          time.Duration(artifactURLExpirySeconds) * time.Second,
+		IngestPool:                 ingestPool,
+		IngestStateWorkers:         ingestStateWorkers,
+		IngestStateQueueSize:       ingestStateQueueSize,
+		IngestCommandQueueSize:     ingestCommandQueueSize,
+		IngestConnectionQueueSize:  ingestConnectionQueueSize,
+		IngestOtherQueueSize:       ingestOtherQueueSize,
+		IngestStateFlushInterval:   time.Duration(ingestStateFlushMs) * time.Millisecond,
+		PayloadSchemaMode:          getEnv("PAYLOAD_SCHEMA_MODE", "lenient"),
+		MessageMaxSkew:             time.Duration(messageMaxSkewSeconds) * time.Second,
+		DropStaleStates:            dropStaleStates,
+		StateLatencyWindow:         stateLatencyWindow,
+		ClockAuditWindow:           clockAuditWindow,
+		RobotTokenTTL:              time.Duration(robotTokenTTLHours) * time.Hour,
+		RobotTokenRotationGrace:    time.Duration(robotTokenRotationGraceSeconds) * time.Second,
+		OrderCompression:           getEnv("ORDER_COMPRESSION", "off"),
+		OrderCompressMinBytes:      orderCompressMinBytes,
+		OrderMaxPayloadBytes:       orderMaxPayloadBytes,
+		OrderOversizePolicy:        getEnv("ORDER_OVERSIZE_POLICY", "reject"),
+		IDStrategy:                 idStrategy,
+		IDPrefixes:                 idPrefixes,
+		ClockDriftThreshold:        time.Duration(clockDriftThresholdMs) * time.Millisecond,
+		RobotDiscovery:             robotDiscovery,
+		VdaCompatibilityMode:       getEnv("VDA_COMPATIBILITY_MODE", "lenient"),
+		VdaFeatureVersions:         getEnv("VDA_FEATURE_VERSIONS", ""),
+		StateCacheFlushInterval:    time.Duration(stateCacheFlushMillis) * time.Millisecond,
+		RetentionCommandTTL:        time.Duration(retentionCommandDays) * 24 * time.Hour,
+		RetentionOrderExecutionTTL: time.Duration(retentionOrderExecutionDays) * 24 * time.Hour,
+		RetentionStepExecutionTTL:  time.Duration(retentionStepExecutionDays) * 24 * time.Hour,
+		RetentionInterval:          time.Duration(retentionIntervalMinutes) * time.Minute,
+		RetentionBatchSize:         retentionBatchSize,
+		RetentionArchive:           getEnv("RETENTION_ARCHIVE", "file"),
+		RetentionArchiveDir:        getEnv("RETENTION_ARCHIVE_DIR", "./archive"),
+		AlertRules:                 getEnv("ALERT_RULES", "robot_offline=5m,command_failed,battery_low=15,order_stuck=30m"),
+		AlertInterval:              time.Duration(alertIntervalSeconds) * time.Second,
+		AlertCooldown:              time.Duration(alertCooldownMinutes) * time.Minute,
+		AlertSlackWebhookURL:       getEnv("ALERT_SLACK_WEBHOOK_URL", ""),
+		AlertSMTPAddr:              getEnv("ALERT_SMTP_ADDR", ""),
+		AlertSMTPUsername:          getEnv("ALERT_SMTP_USERNAME", ""),
+		AlertSMTPPassword:          getEnv("ALERT_SMTP_PASSWORD", ""),
+		AlertSMTPFrom:              getEnv("ALERT_SMTP_FROM", ""),
+		AlertSMTPTo:                getEnv("ALERT_SMTP_TO", ""),
+		AlertRobotSelector:         getEnv("ALERT_ROBOT_SELECTOR", ""),
+		HealthAddr:                 getEnv("HEALTH_ADDR", ""),
+		HealthStateStaleness:       time.Duration(healthStalenessSeconds) * time.Second,
+		GRPCAddr:                   getEnv("GRPC_ADDR", ""),
+		CORSAllowedOrigins:         getEnv("CORS_ALLOWED_ORIGINS", ""),
+		CORSAllowedMethods:         getEnv("CORS_ALLOWED_METHODS", "GET,POST,PUT,DELETE"),
+		CORSAllowedHeaders:         getEnv("CORS_ALLOWED_HEADERS", "Content-Type"),
+		AdminIPAllowlist:           getEnv("ADMIN_IP_ALLOWLIST", ""),
+		AccessPolicyFile:           getEnv("ACCESS_POLICY_FILE", ""),
+		MQTTSubscriptionStateFile:  getEnv("MQTT_SUBSCRIPTION_STATE_FILE", ""),
+		MQTTBrokerVersion:          mqttBrokerVersion,
+		MQTTSubscriptionQoS:        mqttSubscriptionQoS,
+		MQTTSharedGroup:            mqttSharedGroup,
+		MQTTSharedSubscriptions:    mqttSharedSubscriptions,
+		MQTTClientVersion:          mqttClientVersion,
+		OrderMessageExpiry:         time.Duration(orderMessageExpirySeconds) * time.Second,
+		OrderQoS:                   orderQoS,
+		OrderRetained:              orderRetained,
+		InstantActionsQoS:          instantActionsQoS,
+		InstantActionsRetained:     instantActionsRetained,
+		PayloadTransformFile:       getEnv("PAYLOAD_TRANSFORM_FILE", ""),
+		LogBufferSize:              logBufferSize,
+		OrderAckTimeout:            time.Duration(orderAckTimeoutSeconds) * time.Second,
+		OrderAckRetries:            orderAckRetries,
+		OrderDeadline:              time.Duration(orderDeadlineSeconds) * time.Second,
+		ZoneReservations:           zoneReservations,
+		ZoneReservationTTL:         time.Duration(zoneReservationTTLSeconds) * time.Second,
+		PreflightChecks:            getEnv("PREFLIGHT_CHECKS", ""),
+		RedisJanitorInterval:       time.Duration(redisJanitorIntervalMinutes) * time.Minute,
+		RedisStepActionsTTL:        time.Duration(redisStepActionsTTLHours) * time.Hour,
+		TemplateSyncSources:        templateSyncSources,
 	}, nil
 }
 
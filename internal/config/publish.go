@@ -0,0 +1,52 @@
+// internal/config/publish.go
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PublishOptions 로봇으로 보내는 MQTT 메시지의 QoS와 retained 플래그
+type PublishOptions struct {
+	QoS      byte
+	Retained bool
+}
+
+// RobotPublishOptions 토픽의 메시지 종류(마지막 세그먼트)에 맞는 발행 옵션
+// order, instantActions 외의 토픽은 QoS 0, retained 없이 발행합니다.
+func (c *Config) RobotPublishOptions(topic string) PublishOptions {
+	switch topic[strings.LastIndex(topic, "/")+1:] {
+	case "order":
+		return PublishOptions{QoS: c.OrderQoS, Retained: c.OrderRetained}
+	case "instantActions":
+		return PublishOptions{QoS: c.InstantActionsQoS, Retained: c.InstantActionsRetained}
+	}
+	return PublishOptions{}
+}
+
+// PublishWarnings 발행 옵션 설정에서 운영상 주의가 필요한 항목 (시작할 때 경고로 기록)
+func (c *Config) PublishWarnings() []string {
+	var warnings []string
+	if c.InstantActionsRetained {
+		warnings = append(warnings, "INSTANT_ACTIONS_RETAINED=true: the broker replays the last instantActions "+
+			"(e.g. cancelOrder, startPause) to a robot every time it reconnects")
+	}
+	if c.OrderRetained {
+		warnings = append(warnings, "ORDER_RETAINED=true: a robot that reconnects receives the last order again; "+
+			"clear the retained message after cancelling an order")
+	}
+	return warnings
+}
+
+// parseQoS MQTT QoS 값 (0, 1, 2)
+func parseQoS(key, value string) (byte, error) {
+	switch strings.TrimSpace(value) {
+	case "0":
+		return 0, nil
+	case "1":
+		return 1, nil
+	case "2":
+		return 2, nil
+	}
+	return 0, fmt.Errorf("%s must be 0, 1 or 2, got %q", key, value)
+}
@@ -0,0 +1,127 @@
+// internal/config/subscriptions.go
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 브릿지가 구독하는 메시지 종류 (MQTT_SUBSCRIPTION_QOS, MQTT_SHARED_SUBSCRIPTIONS의 키)
+const (
+	SubscriptionCommand    = "command"    // bridge/command (PLC 명령)
+	SubscriptionConnection = "connection" // meili/v2/+/+/connection
+	SubscriptionState      = "state"      // meili/v2/+/+/state
+	SubscriptionFactsheet  = "factsheet"  // meili/v2/+/+/factsheet
+	SubscriptionOrder      = "order"      // meili/v2/+/+/order
+	SubscriptionHeartbeat  = "heartbeat"  // bridge/heartbeat (PLC 하트비트, CLOCK_AUDIT_WINDOW가 0이면 구독 안 함)
+)
+
+// subscriptionKinds 알려진 구독 종류
+var subscriptionKinds = []string{
+	SubscriptionCommand, SubscriptionConnection, SubscriptionState, SubscriptionFactsheet, SubscriptionOrder,
+	SubscriptionHeartbeat,
+}
+
+// 브로커가 지원하는 MQTT 버전 (MQTT_BROKER_VERSION)
+const (
+	MQTTVersion311 = "3.1.1"
+	MQTTVersion5   = "5"
+)
+
+// SubscriptionFilter 구독 종류의 브로커 구독 필터와 QoS
+// 공유 구독 그룹이 설정되어 있고 종류가 MQTT_SHARED_SUBSCRIPTIONS에 있으면 $share/<그룹>/<토픽>으로 구독하여
+// 같은 그룹의 브릿지 인스턴스들이 메시지를 나눠 받습니다.
+func (c *Config) SubscriptionFilter(kind, topic string) (string, byte) {
+	qos := c.MQTTSubscriptionQoS[kind]
+	if c.MQTTSharedGroup == "" {
+		return topic, qos
+	}
+	for _, shared := range c.MQTTSharedSubscriptions {
+		if shared == kind {
+			return "$share/" + c.MQTTSharedGroup + "/" + topic, qos
+		}
+	}
+	return topic, qos
+}
+
+// parseSubscriptionQoS MQTT_SUBSCRIPTION_QOS 해석 (예: "command=1,connection=1,state=0", 없는 종류는 QoS 0)
+func parseSubscriptionQoS(value string) (map[string]byte, error) {
+	result := make(map[string]byte)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kind, qosSpec, ok := strings.Cut(entry, "=")
+		kind = strings.TrimSpace(kind)
+		if !ok || kind == "" {
+			return nil, fmt.Errorf("MQTT_SUBSCRIPTION_QOS entry %q must be <subscription>=<qos>", entry)
+		}
+		if !isSubscriptionKind(kind) {
+			return nil, fmt.Errorf("MQTT_SUBSCRIPTION_QOS entry %q: unknown subscription %s (%s)",
+				entry, kind, strings.Join(subscriptionKinds, ", "))
+		}
+		qos, err := parseQoS("MQTT_SUBSCRIPTION_QOS "+kind, qosSpec)
+		if err != nil {
+			return nil, err
+		}
+		result[kind] = qos
+	}
+	return result, nil
+}
+
+// parseClientVersion MQTT_CLIENT_VERSION 검사 (MQTT 5 클라이언트는 MQTT 5 브로커에서만 연결 가능)
+func parseClientVersion(clientVersion, brokerVersion string) error {
+	if clientVersion != MQTTVersion311 && clientVersion != MQTTVersion5 {
+		return fmt.Errorf("MQTT_CLIENT_VERSION must be %s or %s, got %q", MQTTVersion311, MQTTVersion5, clientVersion)
+	}
+	if clientVersion == MQTTVersion5 && brokerVersion != MQTTVersion5 {
+		return fmt.Errorf("MQTT_CLIENT_VERSION=%s requires MQTT_BROKER_VERSION=%s (got %s)", MQTTVersion5, MQTTVersion5, brokerVersion)
+	}
+	return nil
+}
+
+// parseSharedSubscriptions MQTT_BROKER_VERSION, MQTT_SHARED_GROUP, MQTT_SHARED_SUBSCRIPTIONS 검사
+// 공유 구독은 MQTT 5 기능이므로 브로커가 3.1.1만 지원한다고 설정되어 있으면 그룹을 지정할 수 없습니다.
+func parseSharedSubscriptions(version, group, kinds string) ([]string, error) {
+	if version != MQTTVersion311 && version != MQTTVersion5 {
+		return nil, fmt.Errorf("MQTT_BROKER_VERSION must be %s or %s, got %q", MQTTVersion311, MQTTVersion5, version)
+	}
+	if group == "" {
+		return nil, nil
+	}
+	if version != MQTTVersion5 {
+		return nil, fmt.Errorf("MQTT_SHARED_GROUP requires shared subscriptions, which MQTT %s brokers do not support "+
+			"(set MQTT_BROKER_VERSION=%s if the broker accepts $share/ filters)", version, MQTTVersion5)
+	}
+	if strings.ContainsAny(group, "/+#") {
+		return nil, fmt.Errorf("MQTT_SHARED_GROUP %q must not contain '/', '+' or '#'", group)
+	}
+
+	var shared []string
+	for _, kind := range strings.Split(kinds, ",") {
+		kind = strings.TrimSpace(kind)
+		if kind == "" {
+			continue
+		}
+		if !isSubscriptionKind(kind) {
+			return nil, fmt.Errorf("MQTT_SHARED_SUBSCRIPTIONS: unknown subscription %s (%s)",
+				kind, strings.Join(subscriptionKinds, ", "))
+		}
+		shared = append(shared, kind)
+	}
+	if len(shared) == 0 {
+		return nil, fmt.Errorf("MQTT_SHARED_SUBSCRIPTIONS must list at least one subscription when MQTT_SHARED_GROUP is set")
+	}
+	return shared, nil
+}
+
+// isSubscriptionKind 알려진 구독 종류인지 확인
+func isSubscriptionKind(kind string) bool {
+	for _, k := range subscriptionKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+// internal/config/transport_policy.go
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SendPolicy 전송 경로/메시지 종류별 발행 제한 시간과 재시도 횟수
+type SendPolicy struct {
+	Timeout time.Duration // 시도 한 번의 제한 시간
+	Retries int           // 실패 후 같은 경로로 다시 시도하는 횟수 (0이면 한 번만 시도)
+}
+
+// SendPolicy 전송 경로와 메시지 종류(order, instantActions, factsheetRequest)의 발행 정책
+// TRANSPORT_POLICIES에서 "<경로>.<종류>", "<경로>" 순으로 찾고, 없으면 MQTT는 TIMEOUT_SECONDS,
+// HTTP는 ROBOT_HTTP_TIMEOUT_SECONDS를 재시도 없이 사용합니다.
+func (c *Config) SendPolicy(transport, messageType string) SendPolicy {
+	if policy, ok := c.TransportPolicies[transport+"."+messageType]; ok {
+		return policy
+	}
+	if policy, ok := c.TransportPolicies[transport]; ok {
+		return policy
+	}
+	if transport == "http" && c.RobotHTTPTimeout > 0 {
+		return SendPolicy{Timeout: c.RobotHTTPTimeout}
+	}
+	return SendPolicy{Timeout: c.Timeout}
+}
+
+// parseTransportPolicies TRANSPORT_POLICIES 해석
+// 형식: "<경로>[.<종류>]=<제한 시간>[/<재시도>]" 쉼표 구분, 예: "mqtt.order=5s,http=30s/2,http.factsheetRequest=10s/1"
+func parseTransportPolicies(value string) (map[string]SendPolicy, error) {
+	policies := make(map[string]SendPolicy)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, spec, ok := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("TRANSPORT_POLICIES entry %q must be <transport>[.<messageType>]=<timeout>[/<retries>]", entry)
+		}
+		transport, _, _ := strings.Cut(key, ".")
+		if transport != "mqtt" && transport != "http" {
+			return nil, fmt.Errorf("TRANSPORT_POLICIES entry %q: unsupported transport %s (mqtt, http)", entry, transport)
+		}
+
+		timeoutSpec, retriesSpec, hasRetries := strings.Cut(strings.TrimSpace(spec), "/")
+		timeout, err := time.ParseDuration(strings.TrimSpace(timeoutSpec))
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("TRANSPORT_POLICIES entry %q: timeout must be a positive duration like 5s", entry)
+		}
+		policy := SendPolicy{Timeout: timeout}
+		if hasRetries {
+			retries, err := strconv.Atoi(strings.TrimSpace(retriesSpec))
+			if err != nil || retries < 0 {
+				return nil, fmt.Errorf("TRANSPORT_POLICIES entry %q: retries must be a non-negative integer", entry)
+			}
+			policy.Retries = retries
+		}
+		if _, duplicate := policies[key]; duplicate {
+			return nil, fmt.Errorf("TRANSPORT_POLICIES lists %s more than once", key)
+		}
+		policies[key] = policy
+	}
+	return policies, nil
+}
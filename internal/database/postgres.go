@@ -6,6 +6,7 @@ import (
 	"mqtt-bridge/internal/common/constants"
 	"mqtt-bridge/internal/config"
 	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/telemetry"
 	"mqtt-bridge/internal/utils"
 
 	"gorm.io/driver/postgres"
@@ -13,10 +14,102 @@ import (
 	"gorm.io/gorm/logger"
 )
 
+// migratedModels 시작 시 마이그레이션하는 모델 (CheckSchema가 같은 목록으로 스키마를 확인)
+var migratedModels = []interface{}{
+	&models.CommandDefinition{},
+	&models.Command{},
+	&models.CommandOrderMapping{},
+	&models.RobotStatus{},
+	&models.ConnectionStateTransition{},
+	&models.RobotFactsheet{},
+	&models.OrderTemplate{},
+	&models.TemplateRollout{},
+	&models.TemplateShadow{},
+	&models.ShadowComparison{},
+	&models.OrderStep{},
+	&models.NodeTemplate{},
+	&models.ActionTemplate{},
+	&models.ActionParameter{},
+	&models.StepActionMapping{},
+	&models.EdgeTemplate{},
+	&models.CommandExecution{},
+	&models.OrderExecution{},
+	&models.StepExecution{},
+	&models.OutboxMessage{},
+	&models.ActionStatusTransition{},
+	&models.RobotMap{},
+	&models.MapZone{},
+	&models.RobotDefaults{},
+	&models.ExecutionWindow{},
+	&models.ChargingPolicy{},
+	&models.RobotRegistration{},
+	&models.RobotMetadata{},
+	&models.ExecutionAnnotation{},
+	&models.PLCResponse{},
+	&models.AlertEvent{},
+	&models.AlertSuppression{},
+	&models.DirectActionDefinition{},
+	&models.DirectActionArgument{},
+	&models.OrderArtifact{},
+	&models.PurgeRun{},
+	&models.ArchivedRecord{},
+	&models.Job{},
+	&models.JobTask{},
+	&models.PreflightOverride{},
+	&models.RobotToken{},
+}
+
+// NewPostgresDB 데이터베이스 연결, 마이그레이션 및 기본 데이터 생성
 func NewPostgresDB(cfg *config.Config) (*gorm.DB, error) {
+	db, err := Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// 템플릿 이름 유니크 인덱스를 사이트 단위로 변경 (기존 전역 인덱스 제거)
+	if db.Migrator().HasTable(&models.OrderTemplate{}) &&
+		db.Migrator().HasIndex(&models.OrderTemplate{}, "idx_order_templates_name") {
+		if err := db.Migrator().DropIndex(&models.OrderTemplate{}, "idx_order_templates_name"); err != nil {
+			return nil, err
+		}
+	}
+
+	// 테이블 마이그레이션
+	if err := db.AutoMigrate(migratedModels...); err != nil {
+		return nil, err
+	}
+
+	// 샘플 데이터 생성
+	if err := createSampleData(db, cfg.SiteID); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// Open 마이그레이션 없이 데이터베이스 연결만 생성 (관리 도구용)
+func Open(cfg *config.Config) (*gorm.DB, error) {
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
 		cfg.DBHost, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.DBPort)
+	return openDSN(cfg, dsn)
+}
 
+// OpenReader 목록/이력 조회용 연결 생성 (DB_READ_DSN이 없으면 primary 사용)
+// 복제 지연이 있을 수 있으므로 쓰기 직후 읽어야 하는 워크플로우 조회에는 사용하지 않습니다.
+func OpenReader(cfg *config.Config, primary *gorm.DB) (*gorm.DB, error) {
+	if cfg.DBReadDSN == "" {
+		return primary, nil
+	}
+	db, err := openDSN(cfg, cfg.DBReadDSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to read replica: %w", err)
+	}
+	utils.Logger.Infof("📖 Read replica connected for list/history queries")
+	return db, nil
+}
+
+// openDSN DSN으로 연결하고 커넥션 풀과 트레이싱 설정
+func openDSN(cfg *config.Config, dsn string) (*gorm.DB, error) {
 	// 로그 레벨을 환경에 따라 조정
 	logLevel := logger.Silent // 기본값은 Silent
 	if cfg.LogLevel == "debug" {
@@ -30,37 +123,40 @@ func NewPostgresDB(cfg *config.Config) (*gorm.DB, error) {
 		return nil, err
 	}
 
-	// 테이블 마이그레이션
-	if err := db.AutoMigrate(
-		&models.CommandDefinition{},
-		&models.Command{},
-		&models.CommandOrderMapping{},
-		&models.RobotStatus{},
-		&models.RobotFactsheet{},
-		&models.OrderTemplate{},
-		&models.OrderStep{},
-		&models.NodeTemplate{},
-		&models.ActionTemplate{},
-		&models.ActionParameter{},
-		&models.StepActionMapping{},
-		&models.EdgeTemplate{},
-		&models.CommandExecution{},
-		&models.OrderExecution{},
-		&models.StepExecution{},
-	); err != nil {
+	if err := configurePool(db, cfg); err != nil {
 		return nil, err
 	}
 
-	// 샘플 데이터 생성
-	if err := createSampleData(db); err != nil {
+	// 부모 스팬이 있는 쿼리(db.WithContext)를 트레이싱
+	if err := db.Use(telemetry.GormPlugin{}); err != nil {
 		return nil, err
 	}
-
 	return db, nil
 }
 
+// configurePool 커넥션 풀 설정 (0이면 database/sql 기본값 유지)
+func configurePool(db *gorm.DB, cfg *config.Config) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	if cfg.DBMaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	}
+	if cfg.DBMaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	}
+	if cfg.DBConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(cfg.DBConnMaxLifetime)
+	}
+	if cfg.DBConnMaxIdleTime > 0 {
+		sqlDB.SetConnMaxIdleTime(cfg.DBConnMaxIdleTime)
+	}
+	return nil
+}
+
 // createSampleData 샘플 데이터 생성
-func createSampleData(db *gorm.DB) error {
+func createSampleData(db *gorm.DB, siteID string) error {
 	utils.Logger.Info("🔧 Setting up minimal database data...")
 
 	// 1. 모든 기본 명령 정의 생성
@@ -86,7 +182,47 @@ func createSampleData(db *gorm.DB) error {
 		}
 	}
 
-	// 2. 기본 노드 템플릿 생성
+	// 2. 기본 직접 액션 정의 생성 (기존 :I / :T 동작)
+	directActions := []models.DirectActionDefinition{
+		{
+			Suffix:       string(constants.CommandTypeInference),
+			ActionType:   constants.ActionTypeInference,
+			ParameterKey: "inference_name",
+			BlockingType: constants.BlockingTypeNone,
+			Description:  "추론 실행",
+			IsActive:     true,
+		},
+		{
+			Suffix:       string(constants.CommandTypeTrajectory),
+			ActionType:   constants.ActionTypeTrajectory,
+			ParameterKey: "trajectory_name",
+			BlockingType: constants.BlockingTypeNone,
+			Description:  "궤적 실행",
+			IsActive:     true,
+			Arguments: []models.DirectActionArgument{
+				{
+					Position:     1,
+					Key:          "arm",
+					DefaultValue: constants.ArmRight,
+					ValueMap: fmt.Sprintf("%s=%s,%s=%s",
+						constants.ArmParamRight, constants.ArmRight, constants.ArmParamLeft, constants.ArmLeft),
+				},
+			},
+		},
+	}
+
+	for _, def := range directActions {
+		var existing models.DirectActionDefinition
+		result := db.Where("suffix = ?", def.Suffix).First(&existing)
+		if result.Error != nil {
+			if err := db.Create(&def).Error; err != nil {
+				return fmt.Errorf("failed to create direct action %s: %w", def.Suffix, err)
+			}
+			utils.Logger.Infof("✅ Direct action created: :%s", def.Suffix)
+		}
+	}
+
+	// 3. 기본 노드 템플릿 생성
 	var defaultNode models.NodeTemplate
 	result := db.Where("name = ?", "Default Origin").First(&defaultNode)
 	if result.Error != nil {
@@ -106,9 +242,9 @@ func createSampleData(db *gorm.DB) error {
 		utils.Logger.Info("✅ Default node template created")
 	}
 
-	// 3. CR 명령용 최소 샘플 데이터 (선택적 - 개발 편의를 위해)
-	if shouldCreateSampleWorkflow(db) {
-		if err := createCRWorkflowSample(db); err != nil {
+	// 4. CR 명령용 최소 샘플 데이터 (선택적 - 개발 편의를 위해)
+	if shouldCreateSampleWorkflow(db, siteID) {
+		if err := createCRWorkflowSample(db, siteID); err != nil {
 			utils.Logger.Warnf("Failed to create CR workflow sample: %v", err)
 			// 샘플 데이터 생성 실패는 치명적이지 않음
 		}
@@ -119,14 +255,14 @@ func createSampleData(db *gorm.DB) error {
 }
 
 // shouldCreateSampleWorkflow 샘플 워크플로우를 생성할지 결정
-func shouldCreateSampleWorkflow(db *gorm.DB) bool {
+func shouldCreateSampleWorkflow(db *gorm.DB, siteID string) bool {
 	var count int64
-	db.Model(&models.OrderTemplate{}).Count(&count)
+	db.Model(&models.OrderTemplate{}).Where("site_id = ?", siteID).Count(&count)
 	return count == 0 // OrderTemplate이 없으면 샘플 생성
 }
 
 // createCRWorkflowSample CR 명령용 최소 샘플 워크플로우 생성 (2단계: 직장파지 → 직장근막절개)
-func createCRWorkflowSample(db *gorm.DB) error {
+func createCRWorkflowSample(db *gorm.DB, siteID string) error {
 	utils.Logger.Info("🔧 Creating CR workflow sample (직장파지 → 직장근막절개)...")
 
 	// 1. 액션 템플릿 생성
@@ -178,8 +314,8 @@ func createCRWorkflowSample(db *gorm.DB) error {
 
 	// 2. 오더 템플릿 생성
 	var phacoOrderTpl, iolOrderTpl models.OrderTemplate
-	db.FirstOrCreate(&phacoOrderTpl, models.OrderTemplate{Name: "직장 파지"})
-	db.FirstOrCreate(&iolOrderTpl, models.OrderTemplate{Name: "직장 근막 절개"})
+	db.FirstOrCreate(&phacoOrderTpl, models.OrderTemplate{SiteID: siteID, Name: "직장 파지"})
+	db.FirstOrCreate(&iolOrderTpl, models.OrderTemplate{SiteID: siteID, Name: "직장 근막 절개"})
 
 	// 3. 각 오더 템플릿의 스텝 생성
 	var phacoStep, iolStep models.OrderStep
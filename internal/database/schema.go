@@ -0,0 +1,53 @@
+// internal/database/schema.go
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// SchemaReport DB 스키마와 이 바이너리가 마이그레이션하는 모델의 비교 결과
+type SchemaReport struct {
+	Tables         int      `json:"tables"`                    // 확인한 테이블 수
+	MissingTables  []string `json:"missing_tables,omitempty"`  // DB에 없는 테이블
+	MissingColumns []string `json:"missing_columns,omitempty"` // DB에 없는 컬럼 (table.column)
+}
+
+// Current DB 스키마가 이 바이너리의 모델을 모두 담고 있는지
+func (r *SchemaReport) Current() bool {
+	return len(r.MissingTables) == 0 && len(r.MissingColumns) == 0
+}
+
+// CheckSchema 마이그레이션 대상 테이블과 컬럼이 DB에 모두 있는지 확인 (마이그레이션은 하지 않음)
+// 새 버전을 배포하기 전에 아직 마이그레이션되지 않은 DB를 찾는 데 씁니다.
+func CheckSchema(db *gorm.DB) (*SchemaReport, error) {
+	report := &SchemaReport{}
+	migrator := db.Migrator()
+	for _, model := range migratedModels {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, fmt.Errorf("failed to parse model %T: %w", model, err)
+		}
+		table := stmt.Schema.Table
+		report.Tables++
+		if !migrator.HasTable(model) {
+			report.MissingTables = append(report.MissingTables, table)
+			continue
+		}
+		columnTypes, err := migrator.ColumnTypes(model)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+		}
+		existing := make(map[string]bool, len(columnTypes))
+		for _, column := range columnTypes {
+			existing[column.Name()] = true
+		}
+		for _, column := range stmt.Schema.DBNames {
+			if !existing[column] {
+				report.MissingColumns = append(report.MissingColumns, table+"."+column)
+			}
+		}
+	}
+	return report, nil
+}
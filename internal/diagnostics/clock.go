@@ -0,0 +1,297 @@
+// internal/diagnostics/clock.go
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"mqtt-bridge/internal/utils"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 시계 비교 대상 장치 종류
+const (
+	DeviceRobot    = "robot"    // VDA 5050 state/connection 헤더 timestamp
+	DevicePLC      = "plc"      // bridge/heartbeat 하트비트 timestamp
+	DeviceDatabase = "database" // DB 서버 now() (이력 조회의 기준 시각)
+)
+
+// clockProbeInterval DB 서버 시각을 확인하는 간격
+const clockProbeInterval = 30 * time.Second
+
+// minClockSamples 메시지 기반 장치의 오차를 판정하기 위한 최소 표본 수 (DB는 확인 한 번으로 판정)
+const minClockSamples = 5
+
+// ClockDevice 장치 하나의 시계 오차 추정 (밀리초)
+// 오프셋은 브릿지 수신 시각 - 장치 timestamp입니다. 메시지에는 네트워크 지연이 더해지므로 가장 작은 오프셋을,
+// DB는 왕복 시간의 절반을 보정한 오프셋의 중앙값을 시계 오차(DriftMs, 음수면 장치 시계가 앞섬)로 봅니다.
+type ClockDevice struct {
+	Kind         string    `json:"kind"`
+	ID           string    `json:"id"`
+	Samples      int       `json:"samples"`
+	DriftMs      float64   `json:"drift_ms"`
+	LastOffsetMs float64   `json:"last_offset_ms"`
+	MaxOffsetMs  float64   `json:"max_offset_ms"`
+	LastSeenAt   time.Time `json:"last_seen_at"`
+	Flagged      bool      `json:"flagged"` // 표본이 충분하고 |DriftMs|가 기준 초과
+}
+
+// ClockReport 브릿지 시계와 장치별 시계 오차
+type ClockReport struct {
+	BridgeTime  time.Time     `json:"bridge_time"`
+	ThresholdMs float64       `json:"threshold_ms"`
+	Flagged     int           `json:"flagged"`
+	Devices     []ClockDevice `json:"devices"`
+	ProbeError  string        `json:"probe_error,omitempty"` // DB 시각 확인 실패 사유
+}
+
+// clockWindow 장치 하나의 최근 오프셋 (링 버퍼)
+type clockWindow struct {
+	offsets    []time.Duration
+	next       int
+	full       bool
+	lastSeenAt time.Time
+	flagged    bool
+}
+
+// ClockAudit 로봇 메시지, PLC 하트비트, DB 서버 시각을 브릿지 시계와 비교해 장치별 시계 오차를 추정합니다.
+// 오차가 기준을 넘거나 다시 기준 안으로 들어오면 한 번씩 로그를 남깁니다.
+type ClockAudit struct {
+	window    int
+	threshold time.Duration
+	db        *gorm.DB
+
+	mu      sync.Mutex
+	devices map[string]*clockWindow
+
+	cancel context.CancelFunc
+	doneCh chan struct{}
+}
+
+// NewClockAudit 장치별 최근 window개의 표본으로 시계 오차를 추정하는 감사기 생성
+func NewClockAudit(window int, threshold time.Duration) *ClockAudit {
+	if window <= 0 {
+		window = 50
+	}
+	return &ClockAudit{window: window, threshold: threshold, devices: make(map[string]*clockWindow)}
+}
+
+// SetDatabase DB 서버 시각 비교 대상 설정 (Start 전에 호출)
+func (a *ClockAudit) SetDatabase(db *gorm.DB) {
+	a.db = db
+}
+
+// Threshold 장치를 표시하는 시계 오차 기준
+func (a *ClockAudit) Threshold() time.Duration {
+	return a.threshold
+}
+
+// Start 주기적 DB 시각 확인 시작 (DB가 없으면 아무것도 하지 않음)
+func (a *ClockAudit) Start(ctx context.Context) {
+	if a.db == nil {
+		return
+	}
+	ctx, a.cancel = context.WithCancel(ctx)
+	a.doneCh = make(chan struct{})
+	go a.run(ctx)
+}
+
+// Stop 주기적 DB 시각 확인 중지
+func (a *ClockAudit) Stop() {
+	if a.cancel == nil {
+		return
+	}
+	a.cancel()
+	<-a.doneCh
+	a.cancel = nil
+}
+
+func (a *ClockAudit) run(ctx context.Context) {
+	defer close(a.doneCh)
+	ticker := time.NewTicker(clockProbeInterval)
+	defer ticker.Stop()
+
+	if err := a.ProbeDatabase(ctx); err != nil {
+		utils.Logger.Warnf("⚠️ Clock audit: failed to read database time: %v", err)
+	}
+	for {
+		select {
+		case <-ticker.C:
+			if err := a.ProbeDatabase(ctx); err != nil {
+				utils.Logger.Warnf("⚠️ Clock audit: failed to read database time: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Observe 장치가 보낸 시각과 브릿지 수신 시각 기록
+func (a *ClockAudit) Observe(kind, id string, sentAt, receivedAt time.Time) {
+	if id == "" || sentAt.IsZero() {
+		return
+	}
+	a.record(kind, id, receivedAt.Sub(sentAt), receivedAt)
+}
+
+// ObserveTimestamp 헤더 timestamp(RFC3339)와 수신 시각 기록 (timestamp를 해석할 수 없으면 무시)
+func (a *ClockAudit) ObserveTimestamp(kind, id, timestamp string, receivedAt time.Time) {
+	sentAt, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return
+	}
+	a.Observe(kind, id, sentAt, receivedAt)
+}
+
+// ObserveHeartbeat PLC 하트비트 기록
+// 페이로드는 RFC3339 시각, Unix 밀리초, 또는 {"timestamp": ..., "source": "plc-1"} 형식입니다 (source가 없으면 "plc").
+func (a *ClockAudit) ObserveHeartbeat(payload []byte, receivedAt time.Time) error {
+	source, sentAt, err := parseHeartbeat(payload)
+	if err != nil {
+		return err
+	}
+	a.Observe(DevicePLC, source, sentAt, receivedAt)
+	return nil
+}
+
+// ProbeDatabase DB 서버 now()와 브릿지 시계 비교 (왕복 시간의 절반 보정)
+func (a *ClockAudit) ProbeDatabase(ctx context.Context) error {
+	if a.db == nil {
+		return nil
+	}
+	var dbNow time.Time
+	start := time.Now()
+	if err := a.db.WithContext(ctx).Raw("SELECT now()").Row().Scan(&dbNow); err != nil {
+		return err
+	}
+	end := time.Now()
+	midpoint := start.Add(end.Sub(start) / 2)
+	a.record(DeviceDatabase, a.db.Dialector.Name(), midpoint.Sub(dbNow), end)
+	return nil
+}
+
+// Report 장치별 시계 오차 (DB 시각을 먼저 확인, 종류와 ID 순)
+func (a *ClockAudit) Report(ctx context.Context) *ClockReport {
+	report := &ClockReport{ThresholdMs: milliseconds(a.threshold)}
+	if err := a.ProbeDatabase(ctx); err != nil {
+		report.ProbeError = err.Error()
+	}
+	report.BridgeTime = time.Now()
+
+	a.mu.Lock()
+	for key, w := range a.devices {
+		kind, id, _ := strings.Cut(key, "/")
+		device := w.summary(kind, id)
+		device.Flagged = w.flagged
+		if device.Flagged {
+			report.Flagged++
+		}
+		report.Devices = append(report.Devices, device)
+	}
+	a.mu.Unlock()
+
+	sort.Slice(report.Devices, func(i, j int) bool {
+		if report.Devices[i].Kind != report.Devices[j].Kind {
+			return report.Devices[i].Kind < report.Devices[j].Kind
+		}
+		return report.Devices[i].ID < report.Devices[j].ID
+	})
+	return report
+}
+
+// record 오프셋 하나를 보관하고 기준 초과 여부가 바뀌면 로그
+func (a *ClockAudit) record(kind, id string, offset time.Duration, seenAt time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	key := kind + "/" + id
+	w, ok := a.devices[key]
+	if !ok {
+		w = &clockWindow{offsets: make([]time.Duration, a.window)}
+		a.devices[key] = w
+	}
+	w.offsets[w.next] = offset
+	w.next = (w.next + 1) % len(w.offsets)
+	if w.next == 0 {
+		w.full = true
+	}
+	w.lastSeenAt = seenAt
+
+	device := w.summary(kind, id)
+	minSamples := minClockSamples
+	if kind == DeviceDatabase {
+		minSamples = 1
+	}
+	drift := time.Duration(device.DriftMs * float64(time.Millisecond))
+	flagged := a.threshold > 0 && device.Samples >= minSamples && (drift > a.threshold || drift < -a.threshold)
+	if flagged != w.flagged {
+		w.flagged = flagged
+		if flagged {
+			utils.Logger.Warnf("🕒 Clock drift of %s %s is %s (threshold %s, negative = device clock ahead)",
+				kind, id, drift.Round(time.Millisecond), a.threshold)
+		} else {
+			utils.Logger.Infof("🕒 Clock drift of %s %s back within threshold (%s)", kind, id, drift.Round(time.Millisecond))
+		}
+	}
+}
+
+// summary 링 버퍼의 오프셋 요약
+func (w *clockWindow) summary(kind, id string) ClockDevice {
+	count := w.next
+	if w.full {
+		count = len(w.offsets)
+	}
+	sorted := append([]time.Duration(nil), w.offsets[:count]...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	drift := sorted[0]
+	if kind == DeviceDatabase {
+		drift = sorted[count/2]
+	}
+	return ClockDevice{
+		Kind:         kind,
+		ID:           id,
+		Samples:      count,
+		DriftMs:      milliseconds(drift),
+		LastOffsetMs: milliseconds(w.offsets[(w.next+len(w.offsets)-1)%len(w.offsets)]),
+		MaxOffsetMs:  milliseconds(sorted[count-1]),
+		LastSeenAt:   w.lastSeenAt,
+	}
+}
+
+// parseHeartbeat 하트비트 페이로드에서 보낸 장치와 시각 추출
+func parseHeartbeat(payload []byte) (string, time.Time, error) {
+	text := strings.TrimSpace(string(payload))
+	source := DevicePLC
+	if strings.HasPrefix(text, "{") {
+		var heartbeat struct {
+			Source    string          `json:"source"`
+			Timestamp json.RawMessage `json:"timestamp"`
+		}
+		if err := json.Unmarshal([]byte(text), &heartbeat); err != nil {
+			return "", time.Time{}, fmt.Errorf("invalid heartbeat payload: %v", err)
+		}
+		if heartbeat.Source != "" {
+			source = heartbeat.Source
+		}
+		text = strings.Trim(string(heartbeat.Timestamp), `"`)
+	}
+	if millis, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return source, time.UnixMilli(millis), nil
+	}
+	sentAt, err := time.Parse(time.RFC3339Nano, text)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("heartbeat timestamp %q is neither RFC3339 nor Unix milliseconds", text)
+	}
+	return source, sentAt, nil
+}
+
+func milliseconds(d time.Duration) float64 {
+	return math.Round(float64(d)/float64(time.Microsecond)) / 1000
+}
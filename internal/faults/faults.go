@@ -0,0 +1,179 @@
+// internal/faults/faults.go
+package faults
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"mqtt-bridge/internal/utils"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 장애를 주입할 수 있는 대상
+const (
+	TargetMQTT     = "transport:mqtt" // MQTT 오더/instantActions 발행
+	TargetHTTP     = "transport:http" // 로봇 HTTP 직접 전송
+	TargetPostgres = "postgres"       // 모든 gorm 연산
+	TargetRedis    = "redis"          // 모든 Redis 명령
+)
+
+// Targets 장애를 주입할 수 있는 대상 목록
+func Targets() []string {
+	return []string{TargetMQTT, TargetHTTP, TargetPostgres, TargetRedis}
+}
+
+// InjectedError 주입한 장애 오류
+// 회로 차단기와 재시도 로직이 실제 연결 장애와 같이 다루도록 net.Error를 구현합니다.
+type InjectedError struct {
+	Target string
+}
+
+func (e *InjectedError) Error() string {
+	return fmt.Sprintf("injected %s failure", e.Target)
+}
+
+// Timeout net.Error 구현
+func (e *InjectedError) Timeout() bool { return false }
+
+// Temporary net.Error 구현
+func (e *InjectedError) Temporary() bool { return true }
+
+// Rule 대상 하나의 장애 주입 규칙
+type Rule struct {
+	FailureRate float64 `json:"failure_rate"` // 호출을 실패시킬 확률 (0~1)
+	LatencyMs   int     `json:"latency_ms"`   // 호출마다 더하는 지연
+	JitterMs    int     `json:"jitter_ms"`    // 지연에 더하는 0~JitterMs 사이의 무작위 시간
+}
+
+// TargetState 대상별 규칙과 누적 지표
+type TargetState struct {
+	Target   string     `json:"target"`
+	Rule     *Rule      `json:"rule,omitempty"`  // 없으면 주입하지 않음
+	Calls    uint64     `json:"calls"`           // 규칙이 있는 동안 거친 호출 수
+	Failures uint64     `json:"failures"`        // 실패시킨 호출 수
+	Delayed  uint64     `json:"delayed"`         // 지연시킨 호출 수
+	Since    *time.Time `json:"since,omitempty"` // 규칙을 설정한 시각
+}
+
+// Injector 전송 경로와 저장소 호출에 지연과 실패를 주입합니다 (FAULT_INJECTION=true일 때만 생성).
+// 스테이징에서 워크플로 엔진의 재시도/대체 경로/회로 차단기 동작을 검증하기 위한 것으로,
+// 규칙은 관리 엔드포인트로만 바꾸며 재시작하면 모두 사라집니다.
+type Injector struct {
+	mu     sync.Mutex
+	states map[string]*TargetState
+	rand   *rand.Rand
+}
+
+// NewInjector 규칙이 없는 장애 주입기 생성
+func NewInjector() *Injector {
+	i := &Injector{
+		states: make(map[string]*TargetState),
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	for _, target := range Targets() {
+		i.states[target] = &TargetState{Target: target}
+	}
+	utils.Logger.Warnf("🧪 Fault injection ENABLED (targets: %s)", strings.Join(Targets(), ", "))
+	return i
+}
+
+// Set 대상의 규칙 설정 (기존 규칙과 지표는 교체)
+func (i *Injector) Set(target string, rule Rule) error {
+	if rule.FailureRate < 0 || rule.FailureRate > 1 {
+		return fmt.Errorf("failure_rate must be between 0 and 1, got %g", rule.FailureRate)
+	}
+	if rule.LatencyMs < 0 || rule.JitterMs < 0 {
+		return fmt.Errorf("latency_ms and jitter_ms must not be negative")
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if _, ok := i.states[target]; !ok {
+		return fmt.Errorf("unknown fault target: %s (%s)", target, strings.Join(Targets(), ", "))
+	}
+	now := time.Now()
+	i.states[target] = &TargetState{Target: target, Rule: &rule, Since: &now}
+	utils.Logger.Warnf("🧪 Fault injection on %s: failure rate %.2f, latency %dms (+%dms jitter)",
+		target, rule.FailureRate, rule.LatencyMs, rule.JitterMs)
+	return nil
+}
+
+// Clear 대상의 규칙 제거 (빈 값이면 모든 대상)
+func (i *Injector) Clear(target string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if target == "" {
+		for _, t := range Targets() {
+			i.states[t] = &TargetState{Target: t}
+		}
+		utils.Logger.Infof("🧪 Fault injection cleared on all targets")
+		return nil
+	}
+	if _, ok := i.states[target]; !ok {
+		return fmt.Errorf("unknown fault target: %s (%s)", target, strings.Join(Targets(), ", "))
+	}
+	i.states[target] = &TargetState{Target: target}
+	utils.Logger.Infof("🧪 Fault injection cleared on %s", target)
+	return nil
+}
+
+// Report 대상별 규칙과 지표 (대상 이름 순)
+func (i *Injector) Report() []TargetState {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	report := make([]TargetState, 0, len(i.states))
+	for _, state := range i.states {
+		copied := *state
+		if state.Rule != nil {
+			rule := *state.Rule
+			copied.Rule = &rule
+		}
+		report = append(report, copied)
+	}
+	sort.Slice(report, func(a, b int) bool { return report[a].Target < report[b].Target })
+	return report
+}
+
+// Inject 대상 규칙에 따라 지연 후 확률적으로 InjectedError 반환 (규칙이 없으면 즉시 nil)
+func (i *Injector) Inject(ctx context.Context, target string) error {
+	i.mu.Lock()
+	state := i.states[target]
+	if state == nil || state.Rule == nil {
+		i.mu.Unlock()
+		return nil
+	}
+	rule := *state.Rule
+	state.Calls++
+	delay := time.Duration(rule.LatencyMs) * time.Millisecond
+	if rule.JitterMs > 0 {
+		delay += time.Duration(i.rand.Intn(rule.JitterMs+1)) * time.Millisecond
+	}
+	if delay > 0 {
+		state.Delayed++
+	}
+	fail := rule.FailureRate > 0 && i.rand.Float64() < rule.FailureRate
+	if fail {
+		state.Failures++
+	}
+	i.mu.Unlock()
+
+	if delay > 0 {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+	if fail {
+		return &InjectedError{Target: target}
+	}
+	return nil
+}
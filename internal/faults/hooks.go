@@ -0,0 +1,76 @@
+// internal/faults/hooks.go
+package faults
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+// GormPlugin 모든 gorm 연산 전에 postgres 규칙의 지연/실패를 주입하는 플러그인
+// 회로 차단기 플러그인 다음에 등록하면 주입한 실패도 차단기에 실패로 집계됩니다.
+type GormPlugin struct {
+	Injector *Injector
+}
+
+// Name 플러그인 이름
+func (GormPlugin) Name() string {
+	return "faults"
+}
+
+// Initialize 연산별 첫 콜백 등록
+func (p GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	registrations := []error{
+		cb.Create().Before("*").Register("faults:before_create", p.before),
+		cb.Query().Before("*").Register("faults:before_query", p.before),
+		cb.Update().Before("*").Register("faults:before_update", p.before),
+		cb.Delete().Before("*").Register("faults:before_delete", p.before),
+		cb.Row().Before("*").Register("faults:before_row", p.before),
+		cb.Raw().Before("*").Register("faults:before_raw", p.before),
+	}
+	for _, err := range registrations {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p GormPlugin) before(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	if err := p.Injector.Inject(db.Statement.Context, TargetPostgres); err != nil {
+		db.AddError(err)
+	}
+}
+
+// RedisHook 모든 Redis 명령 전에 redis 규칙의 지연/실패를 주입하는 go-redis 훅
+// 회로 차단기 훅 다음에 추가해야 차단기가 주입한 실패를 봅니다.
+type RedisHook struct {
+	Injector *Injector
+}
+
+var _ redis.Hook = RedisHook{}
+
+// BeforeProcess 명령 전 장애 주입
+func (h RedisHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return ctx, h.Injector.Inject(ctx, TargetRedis)
+}
+
+// AfterProcess 아무것도 하지 않음
+func (h RedisHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	return nil
+}
+
+// BeforeProcessPipeline 파이프라인도 명령 하나로 취급
+func (h RedisHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	return ctx, h.Injector.Inject(ctx, TargetRedis)
+}
+
+// AfterProcessPipeline 아무것도 하지 않음
+func (h RedisHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	return nil
+}
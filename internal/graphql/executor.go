@@ -0,0 +1,301 @@
+// internal/graphql/executor.go
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// MaxDepth 허용하는 최대 선택 깊이
+const MaxDepth = 10
+
+// MaxResolvedFields 요청 하나에서 해석하는 최대 필드 수 (목록 항목마다 센 값, 별칭으로 같은 필드를 반복해도 각각 셈)
+// 넘으면 나머지 필드는 null로 두고 오류 하나를 반환합니다.
+const MaxResolvedFields = 10000
+
+// ResolveParams 필드 리졸버 입력
+type ResolveParams struct {
+	Context context.Context
+	Source  interface{}            // 상위 객체 값 (Query는 nil)
+	Args    map[string]interface{} // 변수가 치환된 인자
+}
+
+// ResolveFunc 필드 값 계산
+type ResolveFunc func(p ResolveParams) (interface{}, error)
+
+// Field 객체 타입의 필드 정의
+type Field struct {
+	Type    string // 객체 타입 이름 (빈 값이면 스칼라)
+	List    bool   // 객체 목록 여부
+	Args    []string
+	Resolve ResolveFunc
+}
+
+// Object 필드 이름 → 정의
+type Object map[string]*Field
+
+// Schema 루트 Query 타입과 객체 타입들
+type Schema struct {
+	Query string
+	Types map[string]Object
+}
+
+// Request /graphql 요청 본문
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// ResponseError 응답의 errors 항목
+type ResponseError struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+	Line    int           `json:"line,omitempty"`
+}
+
+// Response 실행 결과 (필드 오류가 있어도 나머지 데이터는 반환)
+type Response struct {
+	Data   *OrderedMap     `json:"data"`
+	Errors []ResponseError `json:"errors,omitempty"`
+}
+
+// Execute 쿼리를 파싱하고 루트부터 선택된 필드만 해석합니다.
+func (s *Schema) Execute(ctx context.Context, req Request) *Response {
+	doc, err := Parse(req.Query)
+	if err != nil {
+		resp := &Response{Errors: []ResponseError{{Message: err.Error()}}}
+		if syntaxErr, ok := err.(*SyntaxError); ok {
+			resp.Errors[0].Line = syntaxErr.Line
+		}
+		return resp
+	}
+	if req.OperationName != "" && doc.OperationName != "" && req.OperationName != doc.OperationName {
+		return &Response{Errors: []ResponseError{{Message: fmt.Sprintf("unknown operation %q", req.OperationName)}}}
+	}
+
+	e := &execution{ctx: ctx, schema: s, variables: req.Variables}
+	data := e.selectObject(s.Query, nil, doc.Selection, nil, 1)
+	return &Response{Data: data, Errors: e.errors}
+}
+
+// execution 한 요청의 실행 상태
+type execution struct {
+	ctx       context.Context
+	schema    *Schema
+	variables map[string]interface{}
+	errors    []ResponseError
+	resolved  int  // 해석한 필드 수 (MaxResolvedFields까지)
+	exhausted bool // 필드 수 한도를 넘어 이후 필드를 해석하지 않음
+}
+
+func (e *execution) fail(path []interface{}, line int, format string, args ...interface{}) {
+	e.errors = append(e.errors, ResponseError{
+		Message: fmt.Sprintf(format, args...),
+		Path:    append([]interface{}(nil), path...),
+		Line:    line,
+	})
+}
+
+// selectObject 객체 값에서 선택된 필드를 순서대로 해석 (오류가 난 필드는 null)
+func (e *execution) selectObject(typeName string, source interface{}, selections []*Selection, path []interface{}, depth int) *OrderedMap {
+	object := e.schema.Types[typeName]
+	result := newOrderedMap(len(selections))
+	for _, sel := range selections {
+		fieldPath := append(append([]interface{}(nil), path...), sel.Key())
+
+		if sel.Name == "__typename" {
+			result.Set(sel.Key(), typeName)
+			continue
+		}
+		field, ok := object[sel.Name]
+		if !ok {
+			e.fail(fieldPath, sel.Line, "cannot query field %q on type %q (available: %s)", sel.Name, typeName, object.fieldNames())
+			result.Set(sel.Key(), nil)
+			continue
+		}
+		if field.Type == "" && len(sel.Selection) > 0 {
+			e.fail(fieldPath, sel.Line, "field %q of type %q must not have a selection", sel.Name, typeName)
+			result.Set(sel.Key(), nil)
+			continue
+		}
+		if field.Type != "" && len(sel.Selection) == 0 {
+			e.fail(fieldPath, sel.Line, "field %q of type %q must have a selection of subfields", sel.Name, typeName)
+			result.Set(sel.Key(), nil)
+			continue
+		}
+		if field.Type != "" && depth >= MaxDepth {
+			e.fail(fieldPath, sel.Line, "query exceeds the maximum depth of %d", MaxDepth)
+			result.Set(sel.Key(), nil)
+			continue
+		}
+
+		if e.resolved >= MaxResolvedFields {
+			if !e.exhausted {
+				e.exhausted = true
+				e.fail(fieldPath, sel.Line, "query exceeds the maximum of %d resolved fields", MaxResolvedFields)
+			}
+			result.Set(sel.Key(), nil)
+			continue
+		}
+		e.resolved++
+
+		args, err := e.arguments(field, sel)
+		if err != nil {
+			e.fail(fieldPath, sel.Line, "%v", err)
+			result.Set(sel.Key(), nil)
+			continue
+		}
+		value, err := field.Resolve(ResolveParams{Context: e.ctx, Source: source, Args: args})
+		if err != nil {
+			e.fail(fieldPath, sel.Line, "%v", err)
+			result.Set(sel.Key(), nil)
+			continue
+		}
+		result.Set(sel.Key(), e.complete(field, value, sel, fieldPath, depth))
+	}
+	return result
+}
+
+// complete 스칼라는 그대로, 객체/목록은 하위 선택으로 해석
+func (e *execution) complete(field *Field, value interface{}, sel *Selection, path []interface{}, depth int) interface{} {
+	if field.Type == "" || isNil(value) {
+		return value
+	}
+	if !field.List {
+		return e.selectObject(field.Type, value, sel.Selection, path, depth+1)
+	}
+
+	items, ok := value.([]interface{})
+	if !ok {
+		e.fail(path, sel.Line, "internal error: field %q did not resolve to a list", sel.Name)
+		return nil
+	}
+	list := make([]interface{}, len(items))
+	for i, item := range items {
+		list[i] = e.selectObject(field.Type, item, sel.Selection, append(path, i), depth+1)
+	}
+	return list
+}
+
+// arguments 선언된 인자만 허용하고 변수 치환
+func (e *execution) arguments(field *Field, sel *Selection) (map[string]interface{}, error) {
+	args := make(map[string]interface{}, len(sel.Arguments))
+	for name, value := range sel.Arguments {
+		if !contains(field.Args, name) {
+			return nil, fmt.Errorf("unknown argument %q on field %q", name, sel.Name)
+		}
+		resolved, err := e.resolveValue(value)
+		if err != nil {
+			return nil, err
+		}
+		args[name] = resolved
+	}
+	return args, nil
+}
+
+func (e *execution) resolveValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case Variable:
+		resolved, ok := e.variables[string(v)]
+		if !ok {
+			return nil, fmt.Errorf("variable $%s is not provided", v)
+		}
+		return resolved, nil
+	case EnumValue:
+		return string(v), nil
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			resolved, err := e.resolveValue(item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = resolved
+		}
+		return list, nil
+	default:
+		return v, nil
+	}
+}
+
+// fieldNames 오류 메시지용 정렬된 필드 이름
+func (o Object) fieldNames() string {
+	names := make([]string, 0, len(o))
+	for name := range o {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	data, _ := json.Marshal(names)
+	return string(data)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// isNil 인터페이스에 담긴 nil 포인터/슬라이스/맵도 nil로 판단
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// OrderedMap 선택 순서를 유지하는 응답 객체 (GraphQL 응답은 쿼리의 필드 순서를 따름)
+type OrderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newOrderedMap(size int) *OrderedMap {
+	return &OrderedMap{keys: make([]string, 0, size), values: make(map[string]interface{}, size)}
+}
+
+// Set 값 설정 (같은 키가 다시 선택되면 처음 위치 유지)
+func (m *OrderedMap) Set(key string, value interface{}) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Get 값 조회
+func (m *OrderedMap) Get(key string) interface{} {
+	return m.values[key]
+}
+
+// MarshalJSON 키 순서대로 직렬화
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		buf.Write(k)
+		buf.WriteByte(':')
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
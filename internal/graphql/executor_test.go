@@ -0,0 +1,169 @@
+// internal/graphql/executor_test.go
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// testNode 테스트 스키마의 객체 값
+type testNode struct {
+	id int
+}
+
+// newTestSchema node(id), nodes(count)와 Node.child, Node.children(count)로 임의 깊이/너비를 만드는 스키마
+func newTestSchema() *Schema {
+	children := &Field{Type: "Node", List: true, Args: []string{"count"}, Resolve: func(p ResolveParams) (interface{}, error) {
+		count, err := intArg(p.Args, "count", 1)
+		if err != nil {
+			return nil, err
+		}
+		list := make([]interface{}, count)
+		for i := range list {
+			list[i] = &testNode{id: i}
+		}
+		return list, nil
+	}}
+	return &Schema{
+		Query: "Query",
+		Types: map[string]Object{
+			"Query": {
+				"node": {Type: "Node", Args: []string{"id"}, Resolve: func(p ResolveParams) (interface{}, error) {
+					id, err := intArg(p.Args, "id", 0)
+					if err != nil {
+						return nil, err
+					}
+					return &testNode{id: id}, nil
+				}},
+				"nodes": children,
+			},
+			"Node": {
+				"id": scalar(func(n *testNode) interface{} { return n.id }),
+				"child": {Type: "Node", Resolve: func(p ResolveParams) (interface{}, error) {
+					return &testNode{id: p.Source.(*testNode).id + 1}, nil
+				}},
+				"children": children,
+			},
+		},
+	}
+}
+
+func execute(t *testing.T, query string, variables map[string]interface{}) (string, []ResponseError) {
+	t.Helper()
+	resp := newTestSchema().Execute(context.Background(), Request{Query: query, Variables: variables})
+	data, err := json.Marshal(resp.Data)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return string(data), resp.Errors
+}
+
+func TestExecuteAliasesKeepQueryOrder(t *testing.T) {
+	data, errs := execute(t, `{ b: node(id: 2) { id } a: node(id: 1) { id kind: __typename } }`, nil)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %+v", errs)
+	}
+	want := `{"b":{"id":2},"a":{"id":1,"kind":"Node"}}`
+	if data != want {
+		t.Fatalf("data = %s, want %s", data, want)
+	}
+}
+
+func TestExecuteVariables(t *testing.T) {
+	// JSON 변수의 숫자는 float64로 들어옴
+	data, errs := execute(t, `query One($id: Int!) { node(id: $id) { id } }`, map[string]interface{}{"id": float64(7)})
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %+v", errs)
+	}
+	if data != `{"node":{"id":7}}` {
+		t.Fatalf("data = %s", data)
+	}
+
+	data, errs = execute(t, `query One($id: Int!) { node(id: $id) { id } }`, nil)
+	if data != `{"node":null}` || len(errs) != 1 || !strings.Contains(errs[0].Message, "$id is not provided") {
+		t.Fatalf("expected missing variable error, got %s %+v", data, errs)
+	}
+
+	_, errs = execute(t, `{ node(id: $id) { id } }`, map[string]interface{}{"id": 1.5})
+	if len(errs) != 1 || !strings.Contains(errs[0].Message, "must be an integer") {
+		t.Fatalf("expected integer error, got %+v", errs)
+	}
+}
+
+func TestExecuteFieldErrors(t *testing.T) {
+	data, errs := execute(t, `{ node(id: 1) { id missing } other: node(id: 1, extra: true) { id } }`, nil)
+	if data != `{"node":{"id":1,"missing":null},"other":null}` {
+		t.Fatalf("data = %s", data)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %+v", errs)
+	}
+	if !strings.Contains(errs[0].Message, `cannot query field "missing"`) || len(errs[0].Path) != 2 {
+		t.Fatalf("unexpected first error: %+v", errs[0])
+	}
+	if !strings.Contains(errs[1].Message, `unknown argument "extra"`) {
+		t.Fatalf("unexpected second error: %+v", errs[1])
+	}
+}
+
+func TestExecuteDepthLimit(t *testing.T) {
+	query := "{ node(id: 0) " + strings.Repeat("{ child ", MaxDepth) + "{ id }" + strings.Repeat(" }", MaxDepth) + " }"
+	data, errs := execute(t, query, nil)
+	if len(errs) != 1 || !strings.Contains(errs[0].Message, "maximum depth") {
+		t.Fatalf("expected a depth error, got %+v", errs)
+	}
+	if len(errs[0].Path) != MaxDepth {
+		t.Fatalf("expected the error at depth %d, got path %v", MaxDepth, errs[0].Path)
+	}
+	if !strings.HasPrefix(data, `{"node":{"child":{`) {
+		t.Fatalf("expected partial data above the limit, got %s", data)
+	}
+}
+
+func TestExecuteResolvedFieldLimit(t *testing.T) {
+	// 100 + 100*100 + 100*100 = 20,100 필드 (별칭으로 너비를 늘려도 각각 셈)
+	query := `{ nodes(count: 100) { a: children(count: 100) { id } } }`
+	_, errs := execute(t, query, nil)
+	if len(errs) != 1 || !strings.Contains(errs[0].Message, "maximum of") {
+		t.Fatalf("expected one resolved-field limit error, got %d error(s): %+v", len(errs), errs)
+	}
+
+	_, errs = execute(t, `{ nodes(count: 50) { children(count: 50) { id } } }`, nil)
+	if len(errs) != 0 {
+		t.Fatalf("query under the limit should succeed: %+v", errs)
+	}
+}
+
+func TestExecutionFilterLimit(t *testing.T) {
+	tests := []struct {
+		args    map[string]interface{}
+		want    int
+		wantErr bool
+	}{
+		{map[string]interface{}{}, defaultListLimit, false},
+		{map[string]interface{}{"limit": 10}, 10, false},
+		{map[string]interface{}{"limit": float64(100000)}, maxListLimit, false},
+		{map[string]interface{}{"limit": 0}, 0, true},
+		{map[string]interface{}{"limit": -5}, 0, true},
+		{map[string]interface{}{"limit": 2.5}, 0, true},
+		{map[string]interface{}{"limit": "10"}, 0, true},
+	}
+	for _, tt := range tests {
+		filter, err := executionFilter(tt.args)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("executionFilter(%v): expected an error", tt.args)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("executionFilter(%v): %v", tt.args, err)
+			continue
+		}
+		if filter.Limit != tt.want {
+			t.Errorf("executionFilter(%v).Limit = %d, want %d", tt.args, filter.Limit, tt.want)
+		}
+	}
+}
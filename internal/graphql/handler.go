@@ -0,0 +1,57 @@
+// internal/graphql/handler.go
+package graphql
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// maxRequestBytes 요청 본문 최대 크기
+const maxRequestBytes = 1 << 20
+
+// NewHandler /graphql HTTP 핸들러 생성
+// POST는 {"query", "variables", "operationName"} JSON 본문, GET은 query/variables 쿼리 파라미터를 사용합니다.
+// 문법/필드 오류가 있어도 GraphQL 관례대로 200과 errors 배열로 응답합니다.
+func NewHandler(schema *Schema) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+		switch r.Method {
+		case http.MethodGet:
+			req.Query = r.URL.Query().Get("query")
+			req.OperationName = r.URL.Query().Get("operationName")
+			if variables := r.URL.Query().Get("variables"); variables != "" {
+				if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+					writeResponse(w, http.StatusBadRequest, &Response{Errors: []ResponseError{{Message: "variables must be a JSON object"}}})
+					return
+				}
+			}
+		case http.MethodPost:
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBytes))
+			if err != nil {
+				writeResponse(w, http.StatusBadRequest, &Response{Errors: []ResponseError{{Message: err.Error()}}})
+				return
+			}
+			if err := json.Unmarshal(body, &req); err != nil {
+				writeResponse(w, http.StatusBadRequest, &Response{Errors: []ResponseError{{Message: "request body must be JSON with a query field"}}})
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			writeResponse(w, http.StatusMethodNotAllowed, &Response{Errors: []ResponseError{{Message: "use GET or POST"}}})
+			return
+		}
+
+		if req.Query == "" {
+			writeResponse(w, http.StatusBadRequest, &Response{Errors: []ResponseError{{Message: "query is required"}}})
+			return
+		}
+		writeResponse(w, http.StatusOK, schema.Execute(r.Context(), req))
+	})
+}
+
+func writeResponse(w http.ResponseWriter, code int, resp *Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,445 @@
+// internal/graphql/parser.go
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// 지원하는 GraphQL 문법 (대시보드 조회용 부분 집합)
+//   - query 연산 하나 (키워드/연산 이름/변수 정의는 선택)
+//   - 필드, 별칭, 인자, 중첩 선택
+//   - 인자 값: 문자열, 정수, 실수, true/false, null, 열거형(이름), 변수($name), 목록
+//
+// fragment, directive, mutation, subscription은 지원하지 않습니다.
+
+// Selection 선택된 필드
+type Selection struct {
+	Alias     string
+	Name      string
+	Arguments map[string]interface{} // 변수는 Variable 값으로 남고 실행 시 치환
+	Selection []*Selection
+	Line      int
+}
+
+// Key 응답에서 사용할 이름 (별칭 우선)
+func (s *Selection) Key() string {
+	if s.Alias != "" {
+		return s.Alias
+	}
+	return s.Name
+}
+
+// Variable 실행 시 요청 변수로 치환되는 인자 값
+type Variable string
+
+// EnumValue 따옴표 없는 이름 값 (예: status: RUNNING)
+type EnumValue string
+
+// Document 파싱된 쿼리
+type Document struct {
+	OperationName string
+	Selection     []*Selection
+}
+
+// SyntaxError 쿼리 문법 오류
+type SyntaxError struct {
+	Line    int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at line %d: %s", e.Line, e.Message)
+}
+
+// Parse 쿼리 문자열 파싱
+func Parse(query string) (*Document, error) {
+	p := &parser{lexer: newLexer(query)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &Document{}
+	if p.token.kind == tokenName {
+		switch p.token.value {
+		case "query":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if p.token.kind == tokenName {
+				doc.OperationName = p.token.value
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+			}
+			if p.token.is("(") {
+				if err := p.skipVariableDefinitions(); err != nil {
+					return nil, err
+				}
+			}
+		case "mutation", "subscription":
+			return nil, p.errorf("%s operations are not supported", p.token.value)
+		case "fragment":
+			return nil, p.errorf("fragments are not supported")
+		default:
+			return nil, p.errorf("unexpected %q", p.token.value)
+		}
+	}
+
+	selection, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	if p.token.kind != tokenEOF {
+		return nil, p.errorf("only a single operation is supported")
+	}
+	doc.Selection = selection
+	return doc, nil
+}
+
+// maxParseDepth 파서가 허용하는 선택 중첩 깊이 (실행 깊이 제한 MaxDepth와 별개로 재귀 깊이를 막음)
+const maxParseDepth = 64
+
+// parser 재귀 하강 파서
+type parser struct {
+	lexer *lexer
+	token token
+	depth int // 현재 선택 중첩 깊이
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.token = tok
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &SyntaxError{Line: p.token.line, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) expect(punct string) error {
+	if !p.token.is(punct) {
+		return p.errorf("expected %q, found %s", punct, p.token)
+	}
+	return p.advance()
+}
+
+// skipVariableDefinitions ($name: Type = default, ...) 정의는 타입 검사 없이 건너뜀
+func (p *parser) skipVariableDefinitions() error {
+	depth := 0
+	for {
+		switch {
+		case p.token.kind == tokenEOF:
+			return p.errorf("unterminated variable definitions")
+		case p.token.is("("):
+			depth++
+		case p.token.is(")"):
+			depth--
+			if depth == 0 {
+				return p.advance()
+			}
+		}
+		if err := p.advance(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *parser) parseSelectionSet() ([]*Selection, error) {
+	if p.depth >= maxParseDepth {
+		return nil, p.errorf("selections are nested deeper than %d levels", maxParseDepth)
+	}
+	p.depth++
+	defer func() { p.depth-- }()
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var selections []*Selection
+	for !p.token.is("}") {
+		if p.token.is("...") {
+			return nil, p.errorf("fragments are not supported")
+		}
+		selection, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, selection)
+	}
+	if len(selections) == 0 {
+		return nil, p.errorf("selection set must not be empty")
+	}
+	return selections, p.advance()
+}
+
+func (p *parser) parseField() (*Selection, error) {
+	if p.token.kind != tokenName {
+		return nil, p.errorf("expected field name, found %s", p.token)
+	}
+	selection := &Selection{Name: p.token.value, Line: p.token.line}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	if p.token.is(":") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.token.kind != tokenName {
+			return nil, p.errorf("expected field name after alias %q", selection.Name)
+		}
+		selection.Alias = selection.Name
+		selection.Name = p.token.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.token.is("(") {
+		args, err := p.parseArguments()
+		if err != nil {
+			return nil, err
+		}
+		selection.Arguments = args
+	}
+	if p.token.is("@") {
+		return nil, p.errorf("directives are not supported")
+	}
+
+	if p.token.is("{") {
+		children, err := p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+		selection.Selection = children
+	}
+	return selection, nil
+}
+
+func (p *parser) parseArguments() (map[string]interface{}, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	args := make(map[string]interface{})
+	for !p.token.is(")") {
+		if p.token.kind != tokenName {
+			return nil, p.errorf("expected argument name, found %s", p.token)
+		}
+		name := p.token.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := args[name]; dup {
+			return nil, p.errorf("argument %q is given more than once", name)
+		}
+		args[name] = value
+	}
+	return args, p.advance()
+}
+
+func (p *parser) parseValue() (interface{}, error) {
+	tok := p.token
+	switch {
+	case tok.is("$"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.token.kind != tokenName {
+			return nil, p.errorf("expected variable name")
+		}
+		name := p.token.value
+		return Variable(name), p.advance()
+	case tok.is("["):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		list := []interface{}{}
+		for !p.token.is("]") {
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		return list, p.advance()
+	case tok.kind == tokenString:
+		return tok.value, p.advance()
+	case tok.kind == tokenNumber:
+		if i, err := strconv.ParseInt(tok.value, 10, 64); err == nil {
+			return int(i), p.advance()
+		}
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", tok.value)
+		}
+		return f, p.advance()
+	case tok.kind == tokenName:
+		var value interface{}
+		switch tok.value {
+		case "true":
+			value = true
+		case "false":
+			value = false
+		case "null":
+			value = nil
+		default:
+			value = EnumValue(tok.value)
+		}
+		return value, p.advance()
+	default:
+		return nil, p.errorf("unexpected %s in argument value", tok)
+	}
+}
+
+// token 종류
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenName
+	tokenString
+	tokenNumber
+	tokenPunct
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	line  int
+}
+
+func (t token) is(punct string) bool {
+	return t.kind == tokenPunct && t.value == punct
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of query"
+	case tokenString:
+		return strconv.Quote(t.value)
+	default:
+		return fmt.Sprintf("%q", t.value)
+	}
+}
+
+// lexer 쿼리 토큰 분리 (쉼표와 # 주석은 공백으로 취급)
+type lexer struct {
+	src  []rune
+	pos  int
+	line int
+}
+
+func newLexer(src string) *lexer {
+	return &lexer{src: []rune(src), line: 1}
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+	if l.pos >= len(l.src) {
+		return token{kind: tokenEOF, line: l.line}, nil
+	}
+
+	r := l.src[l.pos]
+	switch {
+	case r == '_' || unicode.IsLetter(r):
+		start := l.pos
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || unicode.IsLetter(l.src[l.pos]) || unicode.IsDigit(l.src[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokenName, value: string(l.src[start:l.pos]), line: l.line}, nil
+	case r == '-' || unicode.IsDigit(r):
+		start := l.pos
+		l.pos++
+		for l.pos < len(l.src) && strings.ContainsRune("0123456789.eE+-", l.src[l.pos]) {
+			l.pos++
+		}
+		return token{kind: tokenNumber, value: string(l.src[start:l.pos]), line: l.line}, nil
+	case r == '"':
+		return l.readString()
+	case r == '.':
+		if l.pos+2 < len(l.src) && l.src[l.pos+1] == '.' && l.src[l.pos+2] == '.' {
+			l.pos += 3
+			return token{kind: tokenPunct, value: "...", line: l.line}, nil
+		}
+	case strings.ContainsRune("{}():[]$!=@", r):
+		l.pos++
+		return token{kind: tokenPunct, value: string(r), line: l.line}, nil
+	}
+	return token{}, &SyntaxError{Line: l.line, Message: fmt.Sprintf("unexpected character %q", r)}
+}
+
+func (l *lexer) skipIgnored() {
+	for l.pos < len(l.src) {
+		r := l.src[l.pos]
+		switch {
+		case r == '\n':
+			l.line++
+			l.pos++
+		case r == ',' || unicode.IsSpace(r) || r == '\uFEFF':
+			l.pos++
+		case r == '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (l *lexer) readString() (token, error) {
+	line := l.line
+	l.pos++ // 여는 따옴표
+	var sb strings.Builder
+	for l.pos < len(l.src) {
+		r := l.src[l.pos]
+		switch r {
+		case '"':
+			l.pos++
+			return token{kind: tokenString, value: sb.String(), line: line}, nil
+		case '\n':
+			return token{}, &SyntaxError{Line: line, Message: "unterminated string"}
+		case '\\':
+			if l.pos+1 >= len(l.src) {
+				return token{}, &SyntaxError{Line: line, Message: "unterminated string"}
+			}
+			l.pos++
+			switch esc := l.src[l.pos]; esc {
+			case 'n':
+				sb.WriteRune('\n')
+			case 't':
+				sb.WriteRune('\t')
+			case 'r':
+				sb.WriteRune('\r')
+			case 'u':
+				if l.pos+4 >= len(l.src) {
+					return token{}, &SyntaxError{Line: line, Message: "invalid unicode escape"}
+				}
+				code, err := strconv.ParseUint(string(l.src[l.pos+1:l.pos+5]), 16, 32)
+				if err != nil {
+					return token{}, &SyntaxError{Line: line, Message: "invalid unicode escape"}
+				}
+				sb.WriteRune(rune(code))
+				l.pos += 4
+			default:
+				sb.WriteRune(esc) // \" \\ \/
+			}
+		default:
+			sb.WriteRune(r)
+		}
+		l.pos++
+	}
+	return token{}, &SyntaxError{Line: line, Message: "unterminated string"}
+}
@@ -0,0 +1,78 @@
+// internal/graphql/parser_test.go
+package graphql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseAliasesAndArguments(t *testing.T) {
+	doc, err := Parse(`query Dashboard($serial: String) {
+		first: robot(serialNumber: $serial) { serialNumber }
+		busy: executions(status: RUNNING, limit: 5, robots: ["a", "b"]) { orderId }
+	}`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if doc.OperationName != "Dashboard" {
+		t.Fatalf("operation name = %q", doc.OperationName)
+	}
+	if len(doc.Selection) != 2 {
+		t.Fatalf("expected 2 root selections, got %d", len(doc.Selection))
+	}
+
+	first := doc.Selection[0]
+	if first.Alias != "first" || first.Name != "robot" || first.Key() != "first" {
+		t.Fatalf("unexpected alias parse: %+v", first)
+	}
+	if v, ok := first.Arguments["serialNumber"].(Variable); !ok || v != "serial" {
+		t.Fatalf("expected variable argument, got %#v", first.Arguments["serialNumber"])
+	}
+
+	busy := doc.Selection[1]
+	if v, ok := busy.Arguments["status"].(EnumValue); !ok || v != "RUNNING" {
+		t.Fatalf("expected enum argument, got %#v", busy.Arguments["status"])
+	}
+	if v, ok := busy.Arguments["limit"].(int); !ok || v != 5 {
+		t.Fatalf("expected int argument, got %#v", busy.Arguments["limit"])
+	}
+	if list, ok := busy.Arguments["robots"].([]interface{}); !ok || len(list) != 2 || list[1] != "b" {
+		t.Fatalf("expected list argument, got %#v", busy.Arguments["robots"])
+	}
+	if busy.Line != 3 {
+		t.Fatalf("expected line 3, got %d", busy.Line)
+	}
+}
+
+func TestParseRejectsUnsupportedSyntax(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"fragment spread", `{ robots { ...RobotFields } }`, "fragments are not supported"},
+		{"inline fragment", `{ robots { ... on Robot { serialNumber } } }`, "fragments are not supported"},
+		{"fragment definition", `fragment RobotFields on Robot { serialNumber }`, "fragments are not supported"},
+		{"mutation", `mutation { cancel }`, "mutation operations are not supported"},
+		{"subscription", `subscription { states }`, "subscription operations are not supported"},
+		{"directive", `{ robots @include(if: true) { serialNumber } }`, "directives are not supported"},
+		{"multiple operations", `{ robots { serialNumber } } { templates { name } }`, "only a single operation"},
+		{"empty selection", `{ }`, "must not be empty"},
+		{"unterminated string", `{ robot(serialNumber: "abc) { serialNumber } }`, "unterminated string"},
+		{"too deep", strings.Repeat("{ a ", maxParseDepth+1) + strings.Repeat("}", maxParseDepth+1), "nested deeper"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			if err == nil {
+				t.Fatalf("expected an error for %q", tt.query)
+			}
+			if _, ok := err.(*SyntaxError); !ok {
+				t.Fatalf("expected *SyntaxError, got %T: %v", err, err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error %q does not mention %q", err, tt.want)
+			}
+		})
+	}
+}
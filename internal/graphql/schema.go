@@ -0,0 +1,528 @@
+// internal/graphql/schema.go
+package graphql
+
+import (
+	"fmt"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/robot"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+// 대시보드 스키마 (읽기 전용)
+//
+//	Query
+//	  robots(selector): [Robot]           robot(serialNumber): Robot
+//	  executions(status, robot, limit): [OrderExecution]
+//	  execution(orderId): OrderExecution
+//	  templates(active): [Template]       template(id, name): Template
+//	Robot → metadata, state: RobotState, currentOrder: OrderExecution, executions(status, limit)
+//	OrderExecution → template: Template, steps: [StepExecution]
+//	StepExecution → actionStates: [ActionState]
+//	Template → steps: [TemplateStep]
+
+// defaultListLimit 목록 필드의 기본 최대 건수
+const defaultListLimit = 50
+
+// maxListLimit 목록 필드 limit 인자의 상한 (더 큰 값은 이 값으로 줄임)
+const maxListLimit = 200
+
+// cachedState Redis에 캐시된 로봇 state 필드 (평탄화된 경로 → 값)
+type cachedState map[string]string
+
+// stateField RobotState.fields 항목
+type stateField struct {
+	path  string
+	value string
+}
+
+// actionState 단계의 액션별 최신 상태 (상태 전이 기록 기준)
+type actionState struct {
+	transition models.ActionStatusTransition
+}
+
+// dashboard 리졸버가 사용하는 의존성
+type dashboard struct {
+	db          *gorm.DB
+	redisClient *redis.Client
+	siteID      string
+}
+
+// NewDashboardSchema 로봇, 상태, 실행 이력, 템플릿을 조회하는 스키마 생성
+// redisClient가 nil이면 Robot.state는 항상 null입니다.
+func NewDashboardSchema(db *gorm.DB, redisClient *redis.Client, siteID string) *Schema {
+	d := &dashboard{db: db, redisClient: redisClient, siteID: siteID}
+	return &Schema{
+		Query: "Query",
+		Types: map[string]Object{
+			"Query":          d.queryType(),
+			"Robot":          d.robotType(),
+			"RobotState":     robotStateType(),
+			"StateField":     stateFieldType(),
+			"OrderExecution": d.executionType(),
+			"StepExecution":  d.stepType(),
+			"ActionState":    actionStateType(),
+			"Template":       templateType(),
+			"TemplateStep":   templateStepType(),
+		},
+	}
+}
+
+// scalar 상위 객체에서 값을 꺼내는 스칼라 필드
+func scalar[T any](get func(T) interface{}) *Field {
+	return &Field{Resolve: func(p ResolveParams) (interface{}, error) {
+		return get(p.Source.(T)), nil
+	}}
+}
+
+func (d *dashboard) queryType() Object {
+	return Object{
+		"robots": {Type: "Robot", List: true, Args: []string{"selector"}, Resolve: func(p ResolveParams) (interface{}, error) {
+			raw, err := stringArg(p.Args, "selector", false)
+			if err != nil {
+				return nil, err
+			}
+			selector, err := repository.ParseMetadataSelector(raw)
+			if err != nil {
+				return nil, err
+			}
+			serials, err := repository.SelectRobots(d.db, d.siteID, selector)
+			if err != nil {
+				return nil, err
+			}
+			var robots []models.RobotStatus
+			if err := d.db.Scopes(repository.SiteScope(d.siteID)).Where("serial_number IN ?", serials).
+				Order("serial_number ASC").Find(&robots).Error; err != nil {
+				return nil, err
+			}
+			return toList(robots), nil
+		}},
+		"robot": {Type: "Robot", Args: []string{"serialNumber"}, Resolve: func(p ResolveParams) (interface{}, error) {
+			serial, err := stringArg(p.Args, "serialNumber", true)
+			if err != nil {
+				return nil, err
+			}
+			var status models.RobotStatus
+			err = d.db.Scopes(repository.SiteScope(d.siteID)).Where("serial_number = ?", serial).First(&status).Error
+			if err == gorm.ErrRecordNotFound {
+				return nil, nil
+			}
+			return &status, err
+		}},
+		"executions": {Type: "OrderExecution", List: true, Args: []string{"status", "robot", "label", "limit"}, Resolve: func(p ResolveParams) (interface{}, error) {
+			filter, err := executionFilter(p.Args)
+			if err != nil {
+				return nil, err
+			}
+			if filter.SerialNumber, err = stringArg(p.Args, "robot", false); err != nil {
+				return nil, err
+			}
+			executions, err := repository.ListOrderExecutions(d.db, d.siteID, filter)
+			if err != nil {
+				return nil, err
+			}
+			return toList(executions), nil
+		}},
+		"execution": {Type: "OrderExecution", Args: []string{"orderId"}, Resolve: func(p ResolveParams) (interface{}, error) {
+			orderID, err := stringArg(p.Args, "orderId", true)
+			if err != nil {
+				return nil, err
+			}
+			var execution models.OrderExecution
+			err = d.db.Scopes(repository.SiteScope(d.siteID)).Where("order_id = ?", orderID).First(&execution).Error
+			if err == gorm.ErrRecordNotFound {
+				return nil, nil
+			}
+			return &execution, err
+		}},
+		"templates": {Type: "Template", List: true, Args: []string{"active"}, Resolve: func(p ResolveParams) (interface{}, error) {
+			query := d.db.Scopes(repository.SiteScope(d.siteID))
+			if active, ok := p.Args["active"].(bool); ok {
+				query = query.Where("is_active = ?", active)
+			}
+			var templates []models.OrderTemplate
+			if err := query.Order("name ASC").Find(&templates).Error; err != nil {
+				return nil, err
+			}
+			list := make([]interface{}, 0, len(templates))
+			for _, t := range templates {
+				detail, err := repository.LoadTemplateDetail(d.db, d.siteID, t.ID)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, detail)
+			}
+			return list, nil
+		}},
+		"template": {Type: "Template", Args: []string{"id", "name"}, Resolve: func(p ResolveParams) (interface{}, error) {
+			id, err := intArg(p.Args, "id", 0)
+			if err != nil {
+				return nil, err
+			}
+			name, err := stringArg(p.Args, "name", false)
+			if err != nil {
+				return nil, err
+			}
+			if id == 0 && name == "" {
+				return nil, fmt.Errorf("template requires id or name")
+			}
+			if id == 0 {
+				var t models.OrderTemplate
+				err := d.db.Scopes(repository.SiteScope(d.siteID)).Where("name = ?", name).First(&t).Error
+				if err == gorm.ErrRecordNotFound {
+					return nil, nil
+				}
+				if err != nil {
+					return nil, err
+				}
+				id = int(t.ID)
+			}
+			detail, err := repository.LoadTemplateDetail(d.db, d.siteID, uint(id))
+			if err != nil {
+				return nil, nil // 없는 템플릿은 null
+			}
+			return detail, nil
+		}},
+	}
+}
+
+func (d *dashboard) robotType() Object {
+	return Object{
+		"serialNumber":      scalar(func(r *models.RobotStatus) interface{} { return r.SerialNumber }),
+		"manufacturer":      scalar(func(r *models.RobotStatus) interface{} { return r.Manufacturer }),
+		"connectionState":   scalar(func(r *models.RobotStatus) interface{} { return r.ConnectionState }),
+		"version":           scalar(func(r *models.RobotStatus) interface{} { return r.Version }),
+		"lastSeen":          scalar(func(r *models.RobotStatus) interface{} { return formatTime(&r.LastTimestamp) }),
+		"currentMapId":      scalar(func(r *models.RobotStatus) interface{} { return r.CurrentMapID }),
+		"maintenance":       scalar(func(r *models.RobotStatus) interface{} { return r.Maintenance }),
+		"maintenanceReason": scalar(func(r *models.RobotStatus) interface{} { return r.MaintenanceReason }),
+		"maintenanceUntil":  scalar(func(r *models.RobotStatus) interface{} { return formatTime(r.MaintenanceUntil) }),
+		"metadata": {Resolve: func(p ResolveParams) (interface{}, error) {
+			return repository.GetRobotMetadata(d.db, d.siteID, p.Source.(*models.RobotStatus).SerialNumber)
+		}},
+		"state": {Type: "RobotState", Resolve: func(p ResolveParams) (interface{}, error) {
+			if d.redisClient == nil {
+				return nil, nil
+			}
+			fields, err := robot.ReadCachedState(p.Context, d.redisClient, p.Source.(*models.RobotStatus).SerialNumber)
+			if err != nil {
+				return nil, err
+			}
+			if len(fields) == 0 {
+				return nil, nil
+			}
+			return cachedState(fields), nil
+		}},
+		"currentOrder": {Type: "OrderExecution", Resolve: func(p ResolveParams) (interface{}, error) {
+			var execution models.OrderExecution
+			err := d.db.Scopes(repository.SiteScope(d.siteID)).
+				Where("serial_number = ? AND status = ?", p.Source.(*models.RobotStatus).SerialNumber, constants.OrderExecutionStatusRunning).
+				Order("started_at DESC").
+				First(&execution).Error
+			if err == gorm.ErrRecordNotFound {
+				return nil, nil
+			}
+			return &execution, err
+		}},
+		"executions": {Type: "OrderExecution", List: true, Args: []string{"status", "label", "limit"}, Resolve: func(p ResolveParams) (interface{}, error) {
+			filter, err := executionFilter(p.Args)
+			if err != nil {
+				return nil, err
+			}
+			filter.SerialNumber = p.Source.(*models.RobotStatus).SerialNumber
+			executions, err := repository.ListOrderExecutions(d.db, d.siteID, filter)
+			if err != nil {
+				return nil, err
+			}
+			return toList(executions), nil
+		}},
+	}
+}
+
+func robotStateType() Object {
+	text := func(path string) *Field {
+		return scalar(func(s cachedState) interface{} { return s[path] })
+	}
+	number := func(path string) *Field {
+		return scalar(func(s cachedState) interface{} {
+			if v, err := strconv.ParseFloat(s[path], 64); err == nil {
+				return v
+			}
+			return nil
+		})
+	}
+	flag := func(path string) *Field {
+		return scalar(func(s cachedState) interface{} {
+			if v, err := strconv.ParseBool(s[path]); err == nil {
+				return v
+			}
+			return nil
+		})
+	}
+	return Object{
+		"batteryCharge":  number("batteryState.batteryCharge"),
+		"batteryVoltage": number("batteryState.batteryVoltage"),
+		"charging":       flag("batteryState.charging"),
+		"operatingMode":  text("operatingMode"),
+		"driving":        flag("driving"),
+		"paused":         flag("paused"),
+		"orderId":        text("orderId"),
+		"lastNodeId":     text("lastNodeId"),
+		"mapId":          text("agvPosition.mapId"),
+		"x":              number("agvPosition.x"),
+		"y":              number("agvPosition.y"),
+		"theta":          number("agvPosition.theta"),
+		"errors":         text("errors"), // JSON 배열 문자열
+		"field": {Args: []string{"path"}, Resolve: func(p ResolveParams) (interface{}, error) {
+			path, err := stringArg(p.Args, "path", true)
+			if err != nil {
+				return nil, err
+			}
+			value, ok := p.Source.(cachedState)[path]
+			if !ok {
+				return nil, nil
+			}
+			return value, nil
+		}},
+		"fields": {Type: "StateField", List: true, Resolve: func(p ResolveParams) (interface{}, error) {
+			state := p.Source.(cachedState)
+			paths := make([]string, 0, len(state))
+			for path := range state {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+			list := make([]interface{}, 0, len(paths))
+			for _, path := range paths {
+				list = append(list, stateField{path: path, value: state[path]})
+			}
+			return list, nil
+		}},
+	}
+}
+
+func stateFieldType() Object {
+	return Object{
+		"path":  scalar(func(f stateField) interface{} { return f.path }),
+		"value": scalar(func(f stateField) interface{} { return f.value }),
+	}
+}
+
+func (d *dashboard) executionType() Object {
+	return Object{
+		"id":            scalar(func(e *models.OrderExecution) interface{} { return e.ID }),
+		"orderId":       scalar(func(e *models.OrderExecution) interface{} { return e.OrderID }),
+		"status":        scalar(func(e *models.OrderExecution) interface{} { return e.Status }),
+		"serialNumber":  scalar(func(e *models.OrderExecution) interface{} { return e.SerialNumber }),
+		"templateId":    scalar(func(e *models.OrderExecution) interface{} { return e.TemplateID }),
+		"correlationId": scalar(func(e *models.OrderExecution) interface{} { return e.CorrelationID }),
+		"transport":     scalar(func(e *models.OrderExecution) interface{} { return e.Transport }),
+		"currentStep":   scalar(func(e *models.OrderExecution) interface{} { return e.CurrentStep }),
+		"queuePosition": scalar(func(e *models.OrderExecution) interface{} { return e.QueuePosition }),
+		"currentNode": scalar(func(e *models.OrderExecution) interface{} {
+			if e.LastNodeID == "" {
+				return nil
+			}
+			return e.LastNodeID
+		}),
+		"lastNodeSequenceId": scalar(func(e *models.OrderExecution) interface{} { return e.LastNodeSequenceID }),
+		"remainingNodes":     scalar(func(e *models.OrderExecution) interface{} { return repository.RemainingNodeIDs(e) }),
+		"startedAt":          scalar(func(e *models.OrderExecution) interface{} { return formatTime(&e.StartedAt) }),
+		"completedAt":        scalar(func(e *models.OrderExecution) interface{} { return formatTime(e.CompletedAt) }),
+		"eta":                scalar(func(e *models.OrderExecution) interface{} { return formatTime(e.EstimatedCompletionAt) }),
+		"durationMs": scalar(func(e *models.OrderExecution) interface{} {
+			if e.CompletedAt == nil {
+				return nil
+			}
+			return e.CompletedAt.Sub(e.StartedAt).Milliseconds()
+		}),
+		"template": {Type: "Template", Resolve: func(p ResolveParams) (interface{}, error) {
+			detail, err := repository.LoadTemplateDetail(d.db, d.siteID, p.Source.(*models.OrderExecution).TemplateID)
+			if err != nil {
+				return nil, nil // 삭제된 템플릿은 null
+			}
+			return detail, nil
+		}},
+		"steps": {Type: "StepExecution", List: true, Resolve: func(p ResolveParams) (interface{}, error) {
+			var steps []models.StepExecution
+			err := d.db.Where("execution_id = ?", p.Source.(*models.OrderExecution).ID).
+				Order("step_order ASC, id ASC").
+				Find(&steps).Error
+			if err != nil {
+				return nil, err
+			}
+			return toList(steps), nil
+		}},
+	}
+}
+
+func (d *dashboard) stepType() Object {
+	return Object{
+		"id":            scalar(func(s *models.StepExecution) interface{} { return s.ID }),
+		"stepOrder":     scalar(func(s *models.StepExecution) interface{} { return s.StepOrder }),
+		"status":        scalar(func(s *models.StepExecution) interface{} { return s.Status }),
+		"result":        scalar(func(s *models.StepExecution) interface{} { return s.Result }),
+		"parallelGroup": scalar(func(s *models.StepExecution) interface{} { return s.ParallelGroup }),
+		"sentToRobot":   scalar(func(s *models.StepExecution) interface{} { return s.SentToRobot }),
+		"errorMessage":  scalar(func(s *models.StepExecution) interface{} { return s.ErrorMessage }),
+		"startedAt":     scalar(func(s *models.StepExecution) interface{} { return formatTime(&s.StartedAt) }),
+		"completedAt":   scalar(func(s *models.StepExecution) interface{} { return formatTime(s.CompletedAt) }),
+		"actionStates": {Type: "ActionState", List: true, Resolve: func(p ResolveParams) (interface{}, error) {
+			var transitions []models.ActionStatusTransition
+			err := d.db.Where("step_execution_id = ?", p.Source.(*models.StepExecution).ID).
+				Order("observed_at ASC, id ASC").
+				Find(&transitions).Error
+			if err != nil {
+				return nil, err
+			}
+
+			// 액션별 마지막 전이가 현재 상태 (처음 보고된 순서 유지)
+			latest := make(map[string]int)
+			var list []interface{}
+			for _, t := range transitions {
+				if i, ok := latest[t.ActionID]; ok {
+					list[i] = &actionState{transition: t}
+					continue
+				}
+				latest[t.ActionID] = len(list)
+				list = append(list, &actionState{transition: t})
+			}
+			return list, nil
+		}},
+	}
+}
+
+func actionStateType() Object {
+	return Object{
+		"actionId":          scalar(func(a *actionState) interface{} { return a.transition.ActionID }),
+		"actionType":        scalar(func(a *actionState) interface{} { return a.transition.ActionType }),
+		"status":            scalar(func(a *actionState) interface{} { return a.transition.ToStatus }),
+		"resultDescription": scalar(func(a *actionState) interface{} { return a.transition.ResultDescription }),
+		"updatedAt":         scalar(func(a *actionState) interface{} { return formatTime(&a.transition.ObservedAt) }),
+	}
+}
+
+func templateType() Object {
+	return Object{
+		"id":          scalar(func(t *models.OrderTemplate) interface{} { return t.ID }),
+		"name":        scalar(func(t *models.OrderTemplate) interface{} { return t.Name }),
+		"description": scalar(func(t *models.OrderTemplate) interface{} { return t.Description }),
+		"isActive":    scalar(func(t *models.OrderTemplate) interface{} { return t.IsActive }),
+		"status":      scalar(func(t *models.OrderTemplate) interface{} { return t.Status }),
+		"steps": {Type: "TemplateStep", List: true, Resolve: func(p ResolveParams) (interface{}, error) {
+			steps := p.Source.(*models.OrderTemplate).OrderSteps
+			list := make([]interface{}, 0, len(steps))
+			for i := range steps {
+				list = append(list, &steps[i])
+			}
+			return list, nil
+		}},
+	}
+}
+
+func templateStepType() Object {
+	return Object{
+		"stepOrder":          scalar(func(s *models.OrderStep) interface{} { return s.StepOrder }),
+		"previousStepResult": scalar(func(s *models.OrderStep) interface{} { return s.PreviousStepResult }),
+		"condition":          scalar(func(s *models.OrderStep) interface{} { return s.Condition }),
+		"parallelGroup":      scalar(func(s *models.OrderStep) interface{} { return s.ParallelGroup }),
+		"waitForCompletion":  scalar(func(s *models.OrderStep) interface{} { return s.WaitForCompletion }),
+		"timeoutSeconds":     scalar(func(s *models.OrderStep) interface{} { return s.TimeoutSeconds }),
+		"node": scalar(func(s *models.OrderStep) interface{} {
+			if s.NodeTemplate == nil {
+				return nil
+			}
+			return s.NodeTemplate.Name
+		}),
+		"subTemplate": scalar(func(s *models.OrderStep) interface{} {
+			if s.SubTemplate == nil {
+				return nil
+			}
+			return s.SubTemplate.Name
+		}),
+		"actions": scalar(func(s *models.OrderStep) interface{} {
+			actions := make([]string, 0, len(s.StepActionMappings))
+			for _, mapping := range s.StepActionMappings {
+				actions = append(actions, mapping.ActionTemplate.ActionType)
+			}
+			return actions
+		}),
+	}
+}
+
+// toList 모델 슬라이스를 요소 포인터 목록으로 변환
+func toList[T any](items []T) []interface{} {
+	list := make([]interface{}, len(items))
+	for i := range items {
+		list[i] = &items[i]
+	}
+	return list
+}
+
+// executionFilter status, limit 인자로 실행 이력 조회 조건 생성 (limit은 1부터 maxListLimit까지)
+func executionFilter(args map[string]interface{}) (repository.OrderExecutionFilter, error) {
+	var filter repository.OrderExecutionFilter
+	var err error
+	if filter.Status, err = stringArg(args, "status", false); err != nil {
+		return filter, err
+	}
+	if filter.Label, err = stringArg(args, "label", false); err != nil {
+		return filter, err
+	}
+	if filter.Limit, err = intArg(args, "limit", defaultListLimit); err != nil {
+		return filter, err
+	}
+	if filter.Limit < 1 {
+		return filter, fmt.Errorf("argument \"limit\" must be at least 1")
+	}
+	if filter.Limit > maxListLimit {
+		filter.Limit = maxListLimit
+	}
+	return filter, nil
+}
+
+// stringArg 문자열 인자
+func stringArg(args map[string]interface{}, name string, required bool) (string, error) {
+	value, ok := args[name]
+	if !ok || value == nil {
+		if required {
+			return "", fmt.Errorf("argument %q is required", name)
+		}
+		return "", nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("argument %q must be a string", name)
+	}
+	return s, nil
+}
+
+// intArg 정수 인자 (JSON 변수는 float64로 전달됨)
+func intArg(args map[string]interface{}, name string, fallback int) (int, error) {
+	switch v := args[name].(type) {
+	case nil:
+		return fallback, nil
+	case int:
+		return v, nil
+	case float64:
+		if v != float64(int(v)) {
+			return 0, fmt.Errorf("argument %q must be an integer", name)
+		}
+		return int(v), nil
+	default:
+		return 0, fmt.Errorf("argument %q must be an integer", name)
+	}
+}
+
+// formatTime RFC3339 문자열 (nil이면 null)
+func formatTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.Format(time.RFC3339Nano)
+}
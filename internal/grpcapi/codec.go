@@ -0,0 +1,35 @@
+// internal/grpcapi/codec.go
+package grpcapi
+
+import (
+	"encoding/json"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// CodecName gRPC content-subtype (application/grpc+json)
+const CodecName = "json"
+
+// Codec gRPC 메시지를 JSON으로 인코딩
+// 빌드에 protoc 코드 생성 단계가 없어 메시지는 관리 API와 같은 JSON 구조를 그대로 씁니다.
+// 클라이언트는 grpc.CallContentSubtype(CodecName)으로 호출해야 합니다.
+type Codec struct{}
+
+func init() {
+	encoding.RegisterCodec(Codec{})
+}
+
+// Marshal 메시지를 JSON으로 인코딩
+func (Codec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal JSON을 메시지로 디코딩
+func (Codec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// Name 코덱 이름 (content-subtype)
+func (Codec) Name() string {
+	return CodecName
+}
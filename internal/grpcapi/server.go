@@ -0,0 +1,223 @@
+// internal/grpcapi/server.go
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/readonly"
+	"mqtt-bridge/internal/utils"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// ServiceName gRPC 서비스 이름
+const ServiceName = "mqttbridge.v1.Bridge"
+
+// 변경 작업 (IP 허용 목록 적용, 읽기 전용이면 safetyMethods 외에는 거부)
+var mutationMethods = map[string]bool{
+	"CancelOrder":        true,
+	"SubmitJob":          true,
+	"EmergencyStop":      true,
+	"SendInstantActions": true,
+}
+
+// 읽기 전용에서도 허용하는 안전 작업 (관리 API, PLC 명령과 같음)
+var safetyMethods = map[string]bool{
+	"CancelOrder":   true,
+	"EmergencyStop": true,
+}
+
+// ServiceDesc Bridge 서비스 정의
+//
+//	rpc ListRobots(ListRobotsRequest) returns (RobotList)
+//	rpc GetRobot(GetRobotRequest) returns (RobotStatus)
+//	rpc ListOrders(ListOrdersRequest) returns (OrderList)
+//	rpc GetOrder(GetOrderRequest) returns (OrderExecution)
+//	rpc CancelOrder(CancelOrderRequest) returns (Empty)
+//	rpc SubmitJob(SubmitJobRequest) returns (Job)
+//	rpc GetJob(GetJobRequest) returns (Job)
+//	rpc EmergencyStop(EmergencyStopRequest) returns (EmergencyStopResponse)
+//	rpc SendInstantActions(InstantActionsRequest) returns (InstantActionsMessage)
+//	rpc WatchStates(WatchStatesRequest) returns (stream RobotStateMessage)
+var ServiceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*serviceHandler)(nil),
+	Methods: []grpc.MethodDesc{
+		unary("ListRobots", (*Service).ListRobots),
+		unary("GetRobot", (*Service).GetRobot),
+		unary("ListOrders", (*Service).ListOrders),
+		unary("GetOrder", (*Service).GetOrder),
+		unary("CancelOrder", (*Service).CancelOrder),
+		unary("SubmitJob", (*Service).SubmitJob),
+		unary("GetJob", (*Service).GetJob),
+		unary("EmergencyStop", (*Service).EmergencyStop),
+		unary("SendInstantActions", (*Service).SendInstantActions),
+	},
+	Streams: []grpc.StreamDesc{
+		{StreamName: "WatchStates", Handler: watchStatesHandler, ServerStreams: true},
+	},
+}
+
+// serviceHandler ServiceDesc.HandlerType (등록할 수 있는 구현은 *Service뿐)
+type serviceHandler interface {
+	subscribe(serials []string) (*stateSubscriber, func())
+}
+
+// FullMethod 메서드의 전체 이름 (/mqttbridge.v1.Bridge/<method>)
+func FullMethod(method string) string {
+	return "/" + ServiceName + "/" + method
+}
+
+// unary 단항 RPC 정의 (요청 디코딩 후 인터셉터를 거쳐 서비스 메서드 호출)
+func unary[Req, Resp any](method string, call func(*Service, context.Context, *Req) (*Resp, error)) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: method,
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			in := new(Req)
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return call(srv.(*Service), ctx, req.(*Req))
+			}
+			if interceptor == nil {
+				return handler(ctx, in)
+			}
+			return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: FullMethod(method)}, handler)
+		},
+	}
+}
+
+// watchStatesHandler 구독 요청을 받아 스트림이 끝날 때까지 state 메시지 전송
+func watchStatesHandler(srv interface{}, stream grpc.ServerStream) error {
+	var req WatchStatesRequest
+	if err := stream.RecvMsg(&req); err != nil {
+		return err
+	}
+	sub, unsubscribe := srv.(*Service).subscribe(req.SerialNumbers)
+	defer unsubscribe()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sub.closed:
+			return nil
+		case state := <-sub.states:
+			if err := stream.SendMsg(&state); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// MutationGuard 변경 요청을 보낸 주소 허용 여부 (health.AccessControl)
+type MutationGuard interface {
+	AllowsMutation(ip net.IP) bool
+}
+
+// Server gRPC API 서버
+type Server struct {
+	addr     string
+	server   *grpc.Server
+	service  *Service
+	guard    MutationGuard    // 없으면 주소 제한 없음
+	readOnly *readonly.Switch // 켜져 있으면 안전 작업 외의 변경 작업 거부 (없으면 제한 없음)
+}
+
+// NewServer addr에서 service를 제공하는 서버 생성
+func NewServer(addr string, service *Service) *Server {
+	s := &Server{addr: addr, service: service}
+	s.server = grpc.NewServer(grpc.UnaryInterceptor(s.intercept))
+	s.server.RegisterService(&ServiceDesc, service)
+	return s
+}
+
+// SetMutationGuard 변경 작업에 관리 API와 같은 IP 허용 목록 적용 (Start 전에 호출)
+func (s *Server) SetMutationGuard(guard MutationGuard) {
+	s.guard = guard
+}
+
+// SetReadOnly 읽기 전용 스위치 설정 (Start 전에 호출)
+func (s *Server) SetReadOnly(sw *readonly.Switch) {
+	s.readOnly = sw
+}
+
+// Start 리스너를 열고 백그라운드에서 요청 처리
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	go func() {
+		utils.Logger.Infof("🛰️ gRPC server listening on %s", listener.Addr())
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			utils.Logger.Errorf("❌ gRPC server failed: %v", err)
+		}
+	}()
+	return nil
+}
+
+// Stop state 스트림을 끝내고 진행 중인 단항 요청을 마친 뒤 서버 종료
+func (s *Server) Stop() {
+	s.service.closeSubscribers()
+	s.server.GracefulStop()
+}
+
+// intercept 변경 작업의 주소/읽기 전용 검사 후 호출하고 오류를 gRPC 상태 코드로 변환
+func (s *Server) intercept(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	method := info.FullMethod[strings.LastIndex(info.FullMethod, "/")+1:]
+	if mutationMethods[method] {
+		if s.guard != nil {
+			var ip net.IP
+			if p, ok := peer.FromContext(ctx); ok {
+				if addr, ok := p.Addr.(*net.TCPAddr); ok {
+					ip = addr.IP
+				}
+			}
+			if !s.guard.AllowsMutation(ip) {
+				utils.Logger.Warnf("🔐 Rejected gRPC %s from %v (not in admin IP allowlist)", method, ip)
+				return nil, status.Errorf(codes.PermissionDenied, "%s is not allowed from %v", method, ip)
+			}
+		}
+		if !safetyMethods[method] {
+			if err := s.readOnly.Check(method); err != nil {
+				return nil, toStatus(err)
+			}
+		}
+	}
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return resp, nil
+}
+
+// toStatus apperr 오류 코드를 gRPC 상태 코드로 변환 (메시지는 그대로)
+func toStatus(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	code := codes.Internal
+	switch apperr.CodeOf(err) {
+	case apperr.CodeNotFound, apperr.CodeTemplateNotFound, apperr.CodeCommandNotFound:
+		code = codes.NotFound
+	case apperr.CodeValidationFailed, apperr.CodePayloadTooLarge:
+		code = codes.InvalidArgument
+	case apperr.CodeRobotOffline, apperr.CodeRobotBusy, apperr.CodeRobotMaintenance, apperr.CodeRobotNotApproved,
+		apperr.CodeExecutionWindow, apperr.CodePreflightFailed, apperr.CodeUnsupportedFeature:
+		code = codes.FailedPrecondition
+	case apperr.CodeTransportUnavailable, apperr.CodeReadOnly:
+		code = codes.Unavailable
+	case apperr.CodeUnauthorized:
+		code = codes.PermissionDenied
+	}
+	return status.Error(code, err.Error())
+}
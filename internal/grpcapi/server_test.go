@@ -0,0 +1,218 @@
+// internal/grpcapi/server_test.go
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/readonly"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/robot"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeControl 비상 정지, 오더 취소, instantActions 전송 대역
+type fakeControl struct {
+	stopErr   error
+	stopped   []string
+	cancelled []string
+	sent      []string
+}
+
+func (f *fakeControl) EmergencyStop(serialNumber, reason string) (int, error) {
+	f.stopped = append(f.stopped, serialNumber)
+	return 2, f.stopErr
+}
+
+func (f *fakeControl) CancelOrder(orderID, reason string) error {
+	f.cancelled = append(f.cancelled, orderID)
+	return nil
+}
+
+func (f *fakeControl) SendInstantActions(serialNumber string, payload []byte) (*robot.InstantActionsMessage, error) {
+	f.sent = append(f.sent, serialNumber)
+	return &robot.InstantActionsMessage{}, nil
+}
+
+// denyAll 모든 주소의 변경 요청 거부
+type denyAll struct{}
+
+func (denyAll) AllowsMutation(ip net.IP) bool { return false }
+
+// startServer 메모리 리스너로 서버를 띄우고 연결 반환
+func startServer(t *testing.T, configure func(*Server)) (*Service, *fakeControl, *grpc.ClientConn) {
+	t.Helper()
+	control := &fakeControl{}
+	service := NewService(nil, "site-a", control, control, control)
+	server := NewServer("", service)
+	if configure != nil {
+		configure(server)
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	go server.server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return service, control, conn
+}
+
+func invoke(conn *grpc.ClientConn, method string, in, out interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return conn.Invoke(ctx, FullMethod(method), in, out, grpc.CallContentSubtype(CodecName))
+}
+
+func TestEmergencyStop(t *testing.T) {
+	_, control, conn := startServer(t, nil)
+
+	var resp EmergencyStopResponse
+	if err := invoke(conn, "EmergencyStop", &EmergencyStopRequest{SerialNumber: "AGV-1"}, &resp); err != nil {
+		t.Fatalf("EmergencyStop: %v", err)
+	}
+	if resp.SerialNumber != "AGV-1" || resp.StoppedOrders != 2 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	control.stopErr = apperr.New(apperr.CodeTransportUnavailable, "broker down")
+	err := invoke(conn, "EmergencyStop", &EmergencyStopRequest{SerialNumber: "AGV-1"}, &resp)
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable when the safety action is not published, got %v", err)
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	_, control, conn := startServer(t, nil)
+
+	err := invoke(conn, "CancelOrder", &CancelOrderRequest{}, &Empty{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for missing orderId, got %v", err)
+	}
+	if len(control.cancelled) != 0 {
+		t.Fatalf("cancel should not be called: %v", control.cancelled)
+	}
+
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{apperr.New(apperr.CodeNotFound, "missing"), codes.NotFound},
+		{apperr.Validation("field", "bad"), codes.InvalidArgument},
+		{apperr.New(apperr.CodeRobotOffline, "offline"), codes.FailedPrecondition},
+		{apperr.New(apperr.CodeReadOnly, "read-only"), codes.Unavailable},
+		{errors.New("boom"), codes.Internal},
+		{status.Error(codes.Canceled, "client gone"), codes.Canceled},
+	}
+	for _, tt := range tests {
+		if got := status.Code(toStatus(tt.err)); got != tt.code {
+			t.Errorf("toStatus(%v) = %v, want %v", tt.err, got, tt.code)
+		}
+	}
+}
+
+func TestReadOnlyAllowsSafetyMethods(t *testing.T) {
+	_, control, conn := startServer(t, func(s *Server) {
+		s.SetReadOnly(readonly.New(true, "failover"))
+	})
+
+	err := invoke(conn, "SendInstantActions", &InstantActionsRequest{SerialNumber: "AGV-1", Message: []byte(`{}`)}, &robot.InstantActionsMessage{})
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable in read-only mode, got %v", err)
+	}
+	if len(control.sent) != 0 {
+		t.Fatalf("instantActions should not be sent in read-only mode")
+	}
+
+	if err := invoke(conn, "EmergencyStop", &EmergencyStopRequest{SerialNumber: "AGV-1"}, &EmergencyStopResponse{}); err != nil {
+		t.Fatalf("EmergencyStop should be allowed in read-only mode: %v", err)
+	}
+	if err := invoke(conn, "CancelOrder", &CancelOrderRequest{OrderID: "order-1"}, &Empty{}); err != nil {
+		t.Fatalf("CancelOrder should be allowed in read-only mode: %v", err)
+	}
+}
+
+func TestMutationGuard(t *testing.T) {
+	_, control, conn := startServer(t, func(s *Server) {
+		s.SetMutationGuard(denyAll{})
+	})
+
+	err := invoke(conn, "EmergencyStop", &EmergencyStopRequest{SerialNumber: "AGV-1"}, &EmergencyStopResponse{})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied, got %v", err)
+	}
+	if len(control.stopped) != 0 {
+		t.Fatalf("EmergencyStop should not be called: %v", control.stopped)
+	}
+}
+
+func TestWatchStates(t *testing.T) {
+	service, _, conn := startServer(t, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := conn.NewStream(ctx, &ServiceDesc.Streams[0], FullMethod("WatchStates"), grpc.CallContentSubtype(CodecName))
+	if err != nil {
+		t.Fatalf("NewStream: %v", err)
+	}
+	if err := stream.SendMsg(&WatchStatesRequest{SerialNumbers: []string{"AGV-2"}}); err != nil {
+		t.Fatalf("SendMsg: %v", err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("CloseSend: %v", err)
+	}
+
+	// 구독이 등록될 때까지 대기
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		service.mu.Lock()
+		n := len(service.subscribers)
+		service.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("subscriber was not registered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	service.ObserveState(&models.RobotStateMessage{SerialNumber: "AGV-1", HeaderID: 1})
+	service.ObserveState(&models.RobotStateMessage{SerialNumber: "AGV-2", HeaderID: 2})
+
+	var state models.RobotStateMessage
+	if err := stream.RecvMsg(&state); err != nil {
+		t.Fatalf("RecvMsg: %v", err)
+	}
+	if state.SerialNumber != "AGV-2" || state.HeaderID != 2 {
+		t.Fatalf("expected only AGV-2 state, got %+v", state)
+	}
+
+	cancel()
+	deadline = time.Now().Add(5 * time.Second)
+	for {
+		service.mu.Lock()
+		n := len(service.subscribers)
+		service.mu.Unlock()
+		if n == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("subscriber was not removed after the stream ended")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
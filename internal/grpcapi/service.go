@@ -0,0 +1,320 @@
+// internal/grpcapi/service.go
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/robot"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// maxListLimit 목록 조회 한 번의 최대 건수
+const maxListLimit = 500
+
+// stateBufferSize 구독자별 state 메시지 버퍼 (가득 차면 느린 구독자의 메시지를 건너뜀)
+const stateBufferSize = 64
+
+// GetRobotRequest 로봇 하나 조회
+type GetRobotRequest struct {
+	SerialNumber string `json:"serialNumber"`
+}
+
+// ListRobotsRequest 로봇 목록 조회 (selector는 메타데이터 셀렉터, 예: "team=ops,!retired")
+type ListRobotsRequest struct {
+	Selector string `json:"selector,omitempty"`
+}
+
+// RobotList 로봇 목록
+type RobotList struct {
+	Robots []models.RobotStatus `json:"robots"`
+}
+
+// GetOrderRequest 오더 실행 하나 조회
+type GetOrderRequest struct {
+	OrderID string `json:"orderId"`
+}
+
+// ListOrdersRequest 오더 실행 이력 조회 (최신순, limit 기본 50, 최대 500)
+type ListOrdersRequest struct {
+	SerialNumber string `json:"serialNumber,omitempty"`
+	Status       string `json:"status,omitempty"`
+	Limit        int    `json:"limit,omitempty"`
+}
+
+// OrderList 오더 실행 목록
+type OrderList struct {
+	Orders []models.OrderExecution `json:"orders"`
+}
+
+// CancelOrderRequest 오더 취소
+type CancelOrderRequest struct {
+	OrderID string `json:"orderId"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// SubmitJobRequest 작업 묶음 생성 (POST /admin/jobs와 같은 본문)
+type SubmitJobRequest = repository.JobRequest
+
+// GetJobRequest 작업 묶음 조회
+type GetJobRequest struct {
+	JobID string `json:"jobId"`
+}
+
+// EmergencyStopRequest 로봇 비상 정지
+type EmergencyStopRequest struct {
+	SerialNumber string `json:"serialNumber"`
+	Reason       string `json:"reason,omitempty"`
+}
+
+// EmergencyStopResponse 비상 정지 결과
+type EmergencyStopResponse struct {
+	SerialNumber  string `json:"serialNumber"`
+	StoppedOrders int    `json:"stoppedOrders"`
+}
+
+// InstantActionsRequest 원시 VDA 5050 instantActions 전송 (message는 POST /admin/robots/<serial>/instant-actions 본문)
+type InstantActionsRequest struct {
+	SerialNumber string          `json:"serialNumber"`
+	Message      json.RawMessage `json:"message"`
+}
+
+// WatchStatesRequest 로봇 state 스트림 구독 (serialNumbers가 비어 있으면 모든 로봇)
+type WatchStatesRequest struct {
+	SerialNumbers []string `json:"serialNumbers,omitempty"`
+}
+
+// Empty 빈 응답
+type Empty struct{}
+
+// EmergencyStopper 비상 정지 인터페이스
+type EmergencyStopper interface {
+	EmergencyStop(serialNumber, reason string) (int, error)
+}
+
+// OrderCanceller 오더 취소 인터페이스
+type OrderCanceller interface {
+	CancelOrder(orderID, reason string) error
+}
+
+// InstantActionSender 원시 instantActions 검증 후 전송 인터페이스
+type InstantActionSender interface {
+	SendInstantActions(serialNumber string, payload []byte) (*robot.InstantActionsMessage, error)
+}
+
+// Service 관리 API의 조회/제어 작업을 gRPC로 제공하는 서비스
+// 로봇 핸들러의 StateObserver로 등록하면 받은 state 메시지를 WatchStates 구독자에게 전달합니다.
+type Service struct {
+	db        *gorm.DB
+	siteID    string
+	stopper   EmergencyStopper
+	canceller OrderCanceller
+	sender    InstantActionSender
+
+	mu          sync.Mutex
+	subscribers map[*stateSubscriber]struct{}
+	stopped     bool // 서버 종료 후 들어온 구독은 바로 끝냄
+}
+
+// stateSubscriber WatchStates 구독 하나
+type stateSubscriber struct {
+	serials map[string]bool // 비어 있으면 모든 로봇
+	states  chan models.RobotStateMessage
+	closed  chan struct{} // 서버 종료 시 닫힘
+}
+
+// NewService gRPC 서비스 생성
+func NewService(db *gorm.DB, siteID string, stopper EmergencyStopper, canceller OrderCanceller, sender InstantActionSender) *Service {
+	return &Service{
+		db:          db,
+		siteID:      siteID,
+		stopper:     stopper,
+		canceller:   canceller,
+		sender:      sender,
+		subscribers: make(map[*stateSubscriber]struct{}),
+	}
+}
+
+// ListRobots 로봇 목록 (셀렉터에 맞는 로봇만)
+func (s *Service) ListRobots(ctx context.Context, req *ListRobotsRequest) (*RobotList, error) {
+	selector, err := repository.ParseMetadataSelector(req.Selector)
+	if err != nil {
+		return nil, err
+	}
+	serials, err := repository.SelectRobots(s.db, s.siteID, selector)
+	if err != nil {
+		return nil, err
+	}
+	list := &RobotList{Robots: []models.RobotStatus{}}
+	if err := s.db.WithContext(ctx).Scopes(repository.SiteScope(s.siteID)).Where("serial_number IN ?", serials).
+		Order("serial_number ASC").Find(&list.Robots).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetRobot 로봇 하나의 연결/상태 요약
+func (s *Service) GetRobot(ctx context.Context, req *GetRobotRequest) (*models.RobotStatus, error) {
+	if req.SerialNumber == "" {
+		return nil, apperr.Validation("serialNumber", "serialNumber is required")
+	}
+	var status models.RobotStatus
+	err := s.db.WithContext(ctx).Scopes(repository.SiteScope(s.siteID)).Where("serial_number = ?", req.SerialNumber).First(&status).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, apperr.New(apperr.CodeNotFound, "robot %s not found", req.SerialNumber).WithField("serialNumber")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// ListOrders 오더 실행 이력 (최신순)
+func (s *Service) ListOrders(ctx context.Context, req *ListOrdersRequest) (*OrderList, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+	executions, err := repository.ListOrderExecutions(s.db.WithContext(ctx), s.siteID, repository.OrderExecutionFilter{
+		SerialNumber: req.SerialNumber,
+		Status:       strings.ToUpper(req.Status),
+		Limit:        limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if executions == nil {
+		executions = []models.OrderExecution{}
+	}
+	return &OrderList{Orders: executions}, nil
+}
+
+// GetOrder 오더 실행 하나
+func (s *Service) GetOrder(ctx context.Context, req *GetOrderRequest) (*models.OrderExecution, error) {
+	if req.OrderID == "" {
+		return nil, apperr.Validation("orderId", "orderId is required")
+	}
+	var execution models.OrderExecution
+	err := s.db.WithContext(ctx).Scopes(repository.SiteScope(s.siteID)).Where("order_id = ?", req.OrderID).First(&execution).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, apperr.New(apperr.CodeNotFound, "order %s not found", req.OrderID).WithField("orderId")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &execution, nil
+}
+
+// CancelOrder 오더 하나 취소 (PLC OC:<orderId>와 같은 경로)
+func (s *Service) CancelOrder(ctx context.Context, req *CancelOrderRequest) (*Empty, error) {
+	if req.OrderID == "" {
+		return nil, apperr.Validation("orderId", "orderId is required")
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		reason = "Order cancelled via gRPC API"
+	}
+	if err := s.canceller.CancelOrder(req.OrderID, reason); err != nil {
+		return nil, err
+	}
+	return &Empty{}, nil
+}
+
+// SubmitJob 여러 로봇/명령에 걸친 작업 묶음 생성 (모든 작업 항목을 승인하거나 모두 거부)
+func (s *Service) SubmitJob(ctx context.Context, req *SubmitJobRequest) (*models.Job, error) {
+	return repository.CreateJob(s.db.WithContext(ctx), s.siteID, *req)
+}
+
+// GetJob 작업 묶음 하나와 모아진 상태
+func (s *Service) GetJob(ctx context.Context, req *GetJobRequest) (*models.Job, error) {
+	return repository.GetJob(s.db.WithContext(ctx), s.siteID, req.JobID)
+}
+
+// EmergencyStop 안전 액션 전송 후 실행 중인 오더를 E_STOPPED 처리 (읽기 전용에서도 허용)
+func (s *Service) EmergencyStop(ctx context.Context, req *EmergencyStopRequest) (*EmergencyStopResponse, error) {
+	if req.SerialNumber == "" {
+		return nil, apperr.Validation("serialNumber", "serialNumber is required")
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		reason = "Emergency stop requested via gRPC API"
+	}
+	stopped, err := s.stopper.EmergencyStop(req.SerialNumber, reason)
+	if err != nil {
+		if apperr.CodeOf(err) == apperr.CodeTransportUnavailable {
+			// 오더는 이미 정지 처리되었으므로 몇 건인지 함께 알림
+			return nil, apperr.Wrap(apperr.CodeTransportUnavailable, err, "%d order(s) stopped but the safety action was not published", stopped)
+		}
+		return nil, err
+	}
+	return &EmergencyStopResponse{SerialNumber: req.SerialNumber, StoppedOrders: stopped}, nil
+}
+
+// SendInstantActions 스키마와 허용 목록 검사를 통과한 원시 instantActions 전송
+func (s *Service) SendInstantActions(ctx context.Context, req *InstantActionsRequest) (*robot.InstantActionsMessage, error) {
+	if req.SerialNumber == "" {
+		return nil, apperr.Validation("serialNumber", "serialNumber is required")
+	}
+	if len(req.Message) == 0 {
+		return nil, apperr.Validation("message", "message is required")
+	}
+	return s.sender.SendInstantActions(req.SerialNumber, req.Message)
+}
+
+// ObserveState 받은 state 메시지를 WatchStates 구독자에게 전달 (robot.StateObserver)
+func (s *Service) ObserveState(stateMsg *models.RobotStateMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subscribers {
+		if len(sub.serials) > 0 && !sub.serials[stateMsg.SerialNumber] {
+			continue
+		}
+		select {
+		case sub.states <- *stateMsg:
+		default:
+		}
+	}
+}
+
+// subscribe state 구독 등록 (반환한 함수로 해제)
+func (s *Service) subscribe(serials []string) (*stateSubscriber, func()) {
+	sub := &stateSubscriber{
+		serials: make(map[string]bool, len(serials)),
+		states:  make(chan models.RobotStateMessage, stateBufferSize),
+		closed:  make(chan struct{}),
+	}
+	for _, serial := range serials {
+		sub.serials[serial] = true
+	}
+	s.mu.Lock()
+	if s.stopped {
+		close(sub.closed)
+	} else {
+		s.subscribers[sub] = struct{}{}
+	}
+	s.mu.Unlock()
+	return sub, func() {
+		s.mu.Lock()
+		delete(s.subscribers, sub)
+		s.mu.Unlock()
+	}
+}
+
+// closeSubscribers 모든 state 구독을 끝냄 (서버 종료용)
+func (s *Service) closeSubscribers() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped = true
+	for sub := range s.subscribers {
+		close(sub.closed)
+		delete(s.subscribers, sub)
+	}
+}
@@ -0,0 +1,350 @@
+// internal/health/access.go
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/config"
+	"mqtt-bridge/internal/utils"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// accessPolicyPollInterval 접근 정책 파일 변경 확인 주기
+const accessPolicyPollInterval = 5 * time.Second
+
+// AccessPolicy 헬스/관리 서버의 CORS와 변경 요청 IP 제한
+type AccessPolicy struct {
+	Origins           []OriginRule `json:"origins"`            // 허용 출처 (비어 있으면 CORS 헤더를 보내지 않음)
+	Methods           []string     `json:"methods"`            // 출처에 메서드 지정이 없을 때 허용 메서드
+	Headers           []string     `json:"headers"`            // 허용 요청 헤더
+	MaxAgeSeconds     int          `json:"max_age_seconds"`    // preflight 결과 캐시 시간
+	MutationAllowlist []string     `json:"mutation_allowlist"` // 변경 요청을 보낼 수 있는 IP/CIDR (비어 있으면 제한 없음)
+}
+
+// OriginRule 출처 하나와 그 출처에 허용할 메서드
+type OriginRule struct {
+	Origin  string   `json:"origin"`            // 예: https://dashboard.example.com, "*"는 모든 출처
+	Methods []string `json:"methods,omitempty"` // 비어 있으면 AccessPolicy.Methods
+}
+
+// AccessPolicyFromConfig CORS_*, ADMIN_IP_ALLOWLIST 설정으로 정책 생성
+func AccessPolicyFromConfig(cfg *config.Config) AccessPolicy {
+	policy := AccessPolicy{
+		Methods:           splitList(cfg.CORSAllowedMethods),
+		Headers:           splitList(cfg.CORSAllowedHeaders),
+		MaxAgeSeconds:     600,
+		MutationAllowlist: splitList(cfg.AdminIPAllowlist),
+	}
+	for _, origin := range splitList(cfg.CORSAllowedOrigins) {
+		policy.Origins = append(policy.Origins, OriginRule{Origin: origin})
+	}
+	return policy
+}
+
+// AccessControl 접근 정책을 적용하는 HTTP 미들웨어 (정책 파일이 있으면 바뀔 때 다시 읽음)
+type AccessControl struct {
+	base AccessPolicy // 설정 값 (정책 파일이 덮어쓰지 않은 항목에 사용)
+	file string
+
+	mu       sync.RWMutex
+	policy   AccessPolicy
+	networks []*net.IPNet
+	modTime  time.Time
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewAccessControl 설정 정책과 정책 파일(빈 값이면 사용 안 함)로 접근 제어 생성
+func NewAccessControl(base AccessPolicy, file string) (*AccessControl, error) {
+	a := &AccessControl{base: base, file: file, stopCh: make(chan struct{})}
+	if file == "" {
+		if err := a.apply(base); err != nil {
+			return nil, fmt.Errorf("invalid access policy: %w", err)
+		}
+		return a, nil
+	}
+	if _, err := a.reload(); err != nil {
+		return nil, fmt.Errorf("failed to load access policy %s: %w", file, err)
+	}
+	return a, nil
+}
+
+// Policy 현재 적용 중인 정책
+func (a *AccessControl) Policy() AccessPolicy {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.policy
+}
+
+// Start 정책 파일 변경 감시 시작 (파일이 없으면 아무것도 하지 않음)
+func (a *AccessControl) Start() {
+	if a.file == "" {
+		return
+	}
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		ticker := time.NewTicker(accessPolicyPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-a.stopCh:
+				return
+			case <-ticker.C:
+				changed, err := a.reload()
+				if err != nil {
+					utils.Logger.Errorf("❌ Failed to reload access policy %s (keeping previous policy): %v", a.file, err)
+				} else if changed {
+					utils.Logger.Infof("🔐 Access policy reloaded from %s", a.file)
+				}
+			}
+		}
+	}()
+}
+
+// Stop 정책 파일 감시 중지
+func (a *AccessControl) Stop() {
+	if a.file == "" {
+		return
+	}
+	close(a.stopCh)
+	a.wg.Wait()
+}
+
+// reload 정책 파일이 바뀌었으면 다시 읽어 적용 (파일에 없는 항목은 설정 값 사용)
+func (a *AccessControl) reload() (bool, error) {
+	info, err := os.Stat(a.file)
+	if err != nil {
+		return false, err
+	}
+	a.mu.RLock()
+	unchanged := info.ModTime().Equal(a.modTime)
+	a.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(a.file)
+	if err != nil {
+		return false, err
+	}
+	var override AccessPolicy
+	if err := json.Unmarshal(data, &override); err != nil {
+		return false, fmt.Errorf("invalid access policy file: %w", err)
+	}
+	policy := a.base
+	if override.Origins != nil {
+		policy.Origins = override.Origins
+	}
+	if override.Methods != nil {
+		policy.Methods = override.Methods
+	}
+	if override.Headers != nil {
+		policy.Headers = override.Headers
+	}
+	if override.MaxAgeSeconds > 0 {
+		policy.MaxAgeSeconds = override.MaxAgeSeconds
+	}
+	if override.MutationAllowlist != nil {
+		policy.MutationAllowlist = override.MutationAllowlist
+	}
+	if err := a.apply(policy); err != nil {
+		return false, err
+	}
+	a.mu.Lock()
+	a.modTime = info.ModTime()
+	a.mu.Unlock()
+	return true, nil
+}
+
+// apply 정책 검증 후 적용
+func (a *AccessControl) apply(policy AccessPolicy) error {
+	networks := make([]*net.IPNet, 0, len(policy.MutationAllowlist))
+	for _, entry := range policy.MutationAllowlist {
+		network, err := parseNetwork(entry)
+		if err != nil {
+			return err
+		}
+		networks = append(networks, network)
+	}
+	for i := range policy.Origins {
+		policy.Origins[i].Origin = strings.TrimRight(strings.TrimSpace(policy.Origins[i].Origin), "/")
+		if policy.Origins[i].Origin == "" {
+			return fmt.Errorf("access policy origin %d is empty", i)
+		}
+	}
+
+	a.mu.Lock()
+	a.policy = policy
+	a.networks = networks
+	a.mu.Unlock()
+	return nil
+}
+
+// Wrap CORS 헤더를 붙이고 허용되지 않은 출처/IP의 요청을 거부하는 핸들러
+// 허용 목록에 없는 출처의 변경 요청과 허용 IP가 아닌 곳의 /admin/ 변경 요청은 403으로 거부합니다.
+// 로봇 토큰으로 인증하는 /api/v1/ingest/는 /admin/ 밖에 있어 IP 허용 목록을 적용하지 않습니다.
+func (a *AccessControl) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.mu.RLock()
+		policy := a.policy
+		networks := a.networks
+		a.mu.RUnlock()
+
+		origin := r.Header.Get("Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		method := r.Method
+		if preflight {
+			method = r.Header.Get("Access-Control-Request-Method")
+		}
+
+		if origin != "" && len(policy.Origins) > 0 {
+			w.Header().Add("Vary", "Origin")
+			rule, ok := matchOrigin(policy.Origins, origin)
+			methods := policy.Methods
+			if ok && len(rule.Methods) > 0 {
+				methods = rule.Methods
+			}
+			if !ok || (!containsFold(methods, method) && method != http.MethodGet && method != http.MethodHead) {
+				if preflight || isMutation(method) {
+					writeJSON(w, http.StatusForbidden, map[string]string{
+						"error": fmt.Sprintf("%s requests from origin %s are not allowed", method, origin),
+					})
+					return
+				}
+			} else {
+				allowOrigin := origin
+				if rule.Origin == "*" {
+					allowOrigin = "*"
+				}
+				w.Header().Set("Access-Control-Allow-Origin", allowOrigin)
+				if preflight {
+					w.Header().Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+					w.Header().Set("Access-Control-Allow-Headers", strings.Join(policy.Headers, ", "))
+					w.Header().Set("Access-Control-Max-Age", strconv.Itoa(policy.MaxAgeSeconds))
+				}
+			}
+		}
+		if preflight {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if isMutation(r.Method) && strings.HasPrefix(r.URL.Path, "/admin/") && len(networks) > 0 {
+			ip := clientIP(r)
+			if !containsIP(networks, ip) {
+				utils.Logger.Warnf("🔐 Rejected %s %s from %s (not in admin IP allowlist)", r.Method, r.URL.Path, r.RemoteAddr)
+				writeJSON(w, http.StatusForbidden, map[string]string{
+					"error": fmt.Sprintf("%s %s is not allowed from %s", r.Method, r.URL.Path, ip),
+				})
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// AllowsMutation 주소가 변경 요청 IP 허용 목록에 드는지 (목록이 비어 있으면 항상 허용, HTTP 밖의 관리 경로용)
+func (a *AccessControl) AllowsMutation(ip net.IP) bool {
+	a.mu.RLock()
+	networks := a.networks
+	a.mu.RUnlock()
+	return len(networks) == 0 || containsIP(networks, ip)
+}
+
+// matchOrigin 요청 출처에 맞는 규칙 (정확히 일치하는 규칙이 "*"보다 우선)
+func matchOrigin(rules []OriginRule, origin string) (OriginRule, bool) {
+	var wildcard *OriginRule
+	for i := range rules {
+		if strings.EqualFold(rules[i].Origin, origin) {
+			return rules[i], true
+		}
+		if rules[i].Origin == "*" {
+			wildcard = &rules[i]
+		}
+	}
+	if wildcard != nil {
+		return *wildcard, true
+	}
+	return OriginRule{}, false
+}
+
+// isMutation 상태를 바꾸는 HTTP 메서드인지
+func isMutation(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// clientIP 요청을 보낸 주소 (프록시 헤더는 신뢰하지 않음)
+func clientIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return net.ParseIP(host)
+}
+
+// containsIP 주소가 허용 네트워크 중 하나에 속하는지
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseNetwork "10.0.0.0/8" 또는 단일 IP를 네트워크로 변환
+func parseNetwork(entry string) (*net.IPNet, error) {
+	entry = strings.TrimSpace(entry)
+	if strings.Contains(entry, "/") {
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowlist entry %q: %w", entry, err)
+		}
+		return network, nil
+	}
+	ip := net.ParseIP(entry)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid allowlist entry %q: not an IP or CIDR", entry)
+	}
+	bits := 128
+	if v4 := ip.To4(); v4 != nil {
+		ip, bits = v4, 32
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
+
+// containsFold 대소문자 구분 없이 목록에 값이 있는지
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitList 쉼표 구분 목록 (빈 항목 제외)
+func splitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,657 @@
+// internal/health/admin.go
+package health
+
+import (
+	"encoding/json"
+	"io"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/diagnostics"
+	"mqtt-bridge/internal/faults"
+	"mqtt-bridge/internal/messaging"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/notifier"
+	"mqtt-bridge/internal/provisioning"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/retention"
+	"mqtt-bridge/internal/selftest"
+	"mqtt-bridge/internal/utils"
+	"mqtt-bridge/internal/workflow"
+	"mqtt-bridge/internal/zones"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RetentionAdmin 실행 이력 정리 조회/실행 인터페이스
+type RetentionAdmin interface {
+	Policy() retention.Policy
+	ListRuns(limit int) ([]models.PurgeRun, error)
+	Trigger(dryRun bool) (*models.PurgeRun, error)
+}
+
+// SetRetention 실행 이력 정리 관리 엔드포인트 등록 (Start 전에 호출)
+func (s *Server) SetRetention(admin RetentionAdmin) {
+	s.mux.HandleFunc("/admin/retention", func(w http.ResponseWriter, r *http.Request) {
+		limit := 20
+		if v, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && v > 0 {
+			limit = v
+		}
+		runs, err := admin.ListRuns(limit)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, apperr.ToResponse(err, ""))
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"policy": admin.Policy(), "runs": runs})
+	})
+	s.mux.HandleFunc("/admin/retention/purge", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+			return
+		}
+		dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+		run, err := admin.Trigger(dryRun)
+		if err != nil {
+			writeJSON(w, http.StatusConflict, apperr.ToResponse(err, ""))
+			return
+		}
+		writeJSON(w, http.StatusAccepted, run)
+	})
+}
+
+// SetTransportDebug 전송 디버그 기록 조회 엔드포인트 등록 (Start 전에 호출)
+// 기록이 켜지지 않은 경로는 404를 반환합니다. ?limit=N이면 최근 N건만 반환합니다.
+func (s *Server) SetTransportDebug(captures map[string]*workflow.DebugCapture) {
+	s.mux.HandleFunc("/admin/transports/", func(w http.ResponseWriter, r *http.Request) {
+		name, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/admin/transports/"), "/debug")
+		if !ok || name == "" || strings.Contains(name, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		capture, ok := captures[name]
+		if !ok {
+			writeJSON(w, http.StatusNotFound, apperr.ToResponse(
+				apperr.New(apperr.CodeNotFound, "debug capture is not enabled for transport %s (TRANSPORT_DEBUG)", name), ""))
+			return
+		}
+
+		entries := capture.Entries()
+		if limit, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && limit > 0 && limit < len(entries) {
+			entries = entries[len(entries)-limit:]
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"transport": name,
+			"total":     capture.Total(),
+			"captures":  entries,
+		})
+	})
+}
+
+// SetPLCStatusMap PLC 응답 상태 매핑 엔드포인트 등록 (Start 전에 호출)
+// PUT 본문 {"SUCCESS": "OK", ...}은 매핑 전체를 교체하며(빠진 상태는 기본 문자), 매핑 파일이
+// 설정되어 있으면 현재 코덱 섹션에 저장하여 재시작 후에도 유지합니다.
+func (s *Server) SetPLCStatusMap(statusMap *messaging.PLCStatusMap, file string) {
+	s.mux.HandleFunc("/admin/plc/status-map", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var overrides map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&overrides); err != nil {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+				return
+			}
+			if err := statusMap.Update(overrides); err != nil {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("statusMap", "%v", err), ""))
+				return
+			}
+			utils.Logger.Infof("🔤 PLC status map updated: %v", statusMap.Table())
+			if file != "" {
+				if err := messaging.SavePLCStatusMapFile(file, statusMap.Codec(), statusMap.Table()); err != nil {
+					writeJSON(w, http.StatusInternalServerError, apperr.ToResponse(
+						apperr.Wrap(apperr.CodeInternal, err, "status map applied but not saved to %s", file), ""))
+					return
+				}
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET or PUT"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"codec":      statusMap.Codec(),
+			"file":       file,
+			"status_map": statusMap.Table(),
+		})
+	})
+}
+
+// SubscriptionAdmin MQTT 구독 조회/일시 중지/재개 인터페이스
+type SubscriptionAdmin interface {
+	Subscriptions() []messaging.SubscriptionStats
+	PauseSubscription(topic string) (*messaging.SubscriptionStats, error)
+	ResumeSubscription(topic string) (*messaging.SubscriptionStats, error)
+}
+
+// SetSubscriptions MQTT 구독 관리 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET  /admin/subscriptions          구독별 일시 중지 여부와 수신 지표
+//	POST /admin/subscriptions/pause    {"topic": "meili/v2/+/+/state"} 구독 일시 중지 (재연결/재시작 후에도 유지)
+//	POST /admin/subscriptions/resume   {"topic": "meili/v2/+/+/state"} 구독 재개
+func (s *Server) SetSubscriptions(admin SubscriptionAdmin) {
+	s.mux.HandleFunc("/admin/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+		writeJSON(w, http.StatusOK, admin.Subscriptions())
+	})
+
+	toggle := func(change func(topic string) (*messaging.SubscriptionStats, error)) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				w.Header().Set("Allow", http.MethodPost)
+				writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+				return
+			}
+			var body struct {
+				Topic string `json:"topic"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Topic == "" {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("topic", "request body must be {\"topic\": \"<filter>\"}"), ""))
+				return
+			}
+			stats, err := change(body.Topic)
+			if err != nil {
+				status := http.StatusInternalServerError
+				switch apperr.CodeOf(err) {
+				case apperr.CodeNotFound:
+					status = http.StatusNotFound
+				case apperr.CodeTransportUnavailable:
+					status = http.StatusBadGateway
+				}
+				writeJSON(w, status, apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusOK, stats)
+		}
+	}
+	s.mux.HandleFunc("/admin/subscriptions/pause", toggle(admin.PauseSubscription))
+	s.mux.HandleFunc("/admin/subscriptions/resume", toggle(admin.ResumeSubscription))
+}
+
+// SetFaults 장애 주입 엔드포인트 등록 (FAULT_INJECTION=true일 때만, Start 전에 호출)
+//
+//	GET    /admin/faults                대상별 규칙과 주입 지표
+//	PUT    /admin/faults/<target>       {"failure_rate": 0.2, "latency_ms": 300, "jitter_ms": 100} 규칙 설정
+//	DELETE /admin/faults[/<target>]     규칙 제거 (대상이 없으면 모두)
+//
+// 대상: transport:mqtt, transport:http, postgres, redis
+func (s *Server) SetFaults(injector *faults.Injector) {
+	handle := func(w http.ResponseWriter, r *http.Request) {
+		target := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/admin/faults"), "/")
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var rule faults.Rule
+			if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+				return
+			}
+			if err := injector.Set(target, rule); err != nil {
+				writeJSON(w, http.StatusUnprocessableEntity, apperr.ToResponse(apperr.Validation("target", "%v", err), ""))
+				return
+			}
+		case http.MethodDelete:
+			if err := injector.Clear(target); err != nil {
+				writeJSON(w, http.StatusNotFound, apperr.ToResponse(apperr.New(apperr.CodeNotFound, "%v", err).WithField("target"), ""))
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT, DELETE")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET, PUT or DELETE"})
+			return
+		}
+		writeJSON(w, http.StatusOK, injector.Report())
+	}
+	s.mux.HandleFunc("/admin/faults", handle)
+	s.mux.HandleFunc("/admin/faults/", handle)
+}
+
+// SetZones 구역 점유 예약 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET    /admin/zones          사이트의 예약, 대기 로봇, 교착 순환
+//	DELETE /admin/zones/<zone>   구역 예약 강제 해제 (교착을 풀거나 멈춘 브릿지가 남긴 예약 정리)
+func (s *Server) SetZones(coordinator *zones.Coordinator) {
+	handle := func(w http.ResponseWriter, r *http.Request) {
+		zone := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/admin/zones"), "/")
+		switch {
+		case zone == "" && r.Method == http.MethodGet:
+			snapshot, err := coordinator.Snapshot(r.Context())
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusOK, snapshot)
+		case zone != "" && r.Method == http.MethodDelete:
+			reservation, err := coordinator.ReleaseZone(r.Context(), zone)
+			if err != nil {
+				code := http.StatusInternalServerError
+				if apperr.CodeOf(err) == apperr.CodeNotFound {
+					code = http.StatusNotFound
+				}
+				writeJSON(w, code, apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusOK, reservation)
+		case zone == "":
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+		default:
+			w.Header().Set("Allow", http.MethodDelete)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use DELETE"})
+		}
+	}
+	s.mux.HandleFunc("/admin/zones", handle)
+	s.mux.HandleFunc("/admin/zones/", handle)
+}
+
+// SetSelfTest 자체 점검 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET /admin/selftest   점검 보고서 (모두 통과하면 200, 하나라도 실패하면 503)
+func (s *Server) SetSelfTest(runner *selftest.Runner) {
+	s.mux.HandleFunc("/admin/selftest", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+		report := runner.Run(r.Context())
+		code := http.StatusOK
+		if !report.Passed() {
+			code = http.StatusServiceUnavailable
+		}
+		writeJSON(w, code, report)
+	})
+}
+
+// SetAlerts 알림 이력/억제 시간대/시험 전송 엔드포인트 등록 (Start 전에 호출)
+// 이력과 억제 시간대는 사이트 DB 기준이므로 이 브릿지에 알림 채널이 없어도(n == nil) 관리할 수 있습니다.
+func (s *Server) SetAlerts(db *gorm.DB, siteID string, n *notifier.Notifier) {
+	s.mux.HandleFunc("/admin/alerts", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+		query := r.URL.Query()
+		filter := repository.AlertEventFilter{Rule: query.Get("rule"), Status: strings.ToUpper(query.Get("status"))}
+		filter.Limit, _ = strconv.Atoi(query.Get("limit"))
+		events, err := repository.ListAlertEvents(db, siteID, filter)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, apperr.ToResponse(err, ""))
+			return
+		}
+		body := map[string]interface{}{"enabled": n != nil, "events": events}
+		if n != nil {
+			body["rules"] = n.Rules().String()
+			body["channels"] = n.ChannelNames()
+		}
+		writeJSON(w, http.StatusOK, body)
+	})
+	s.mux.HandleFunc("/admin/alerts/test", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+			return
+		}
+		if n == nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "no alert channels configured"})
+			return
+		}
+		var body struct {
+			Message string `json:"message"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+				return
+			}
+		}
+		event := n.SendTest(r.Context(), body.Message)
+		code := http.StatusOK
+		if event.Status != constants.AlertStatusSent {
+			code = http.StatusBadGateway
+		}
+		writeJSON(w, code, event)
+	})
+	s.mux.HandleFunc("/admin/alerts/suppressions", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			suppressions, err := repository.ListAlertSuppressions(db, siteID, time.Now())
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusOK, suppressions)
+		case http.MethodPost:
+			var suppression models.AlertSuppression
+			if err := json.NewDecoder(r.Body).Decode(&suppression); err != nil {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+				return
+			}
+			if err := repository.CreateAlertSuppression(db, siteID, &suppression); err != nil {
+				writeJSON(w, validationErrorStatus(err), apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusCreated, suppression)
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET or POST"})
+		}
+	})
+	s.mux.HandleFunc("/admin/alerts/suppressions/", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(strings.TrimPrefix(r.URL.Path, "/admin/alerts/suppressions/"), 10, 64)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodDelete {
+			w.Header().Set("Allow", http.MethodDelete)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use DELETE"})
+			return
+		}
+		if err := repository.DeleteAlertSuppression(db, siteID, uint(id)); err != nil {
+			writeJSON(w, rolloutErrorStatus(err), apperr.ToResponse(err, ""))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// maxImportSize 일괄 등록 CSV 최대 크기
+const maxImportSize = 10 << 20
+
+// SetImport 로봇/노드 템플릿/액션 템플릿 CSV 일괄 등록 엔드포인트 등록 (Start 전에 호출)
+//
+//	POST /admin/import?kind=robots|nodes|actions[&dry_run=true]
+//
+// 본문은 CSV 그대로(text/csv) 또는 multipart의 file 필드입니다.
+// 행 오류가 있으면 아무것도 저장하지 않고 422와 행별 오류를 반환하며, dry_run이면 검증 결과만 반환합니다.
+func (s *Server) SetImport(db *gorm.DB, siteID string) {
+	s.mux.HandleFunc("/admin/import", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+		var data io.Reader = r.Body
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+			file, _, err := r.FormFile("file")
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("file", "multipart upload requires a file field: %v", err), ""))
+				return
+			}
+			defer file.Close()
+			data = file
+		}
+		dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+		report, err := provisioning.Import(db, siteID, r.URL.Query().Get("kind"), data, dryRun)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if apperr.CodeOf(err) == apperr.CodeValidationFailed {
+				status = http.StatusBadRequest
+			}
+			writeJSON(w, status, apperr.ToResponse(err, ""))
+			return
+		}
+		if !report.Valid() {
+			writeJSON(w, http.StatusUnprocessableEntity, report)
+			return
+		}
+		writeJSON(w, http.StatusOK, report)
+	})
+}
+
+// SetClockAudit 시계 동기화 감사 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET /admin/diagnostics/clock   브릿지 시각과 로봇/PLC/DB별 추정 시계 오차 (DB 시각은 요청마다 다시 확인)
+func (s *Server) SetClockAudit(audit *diagnostics.ClockAudit) {
+	s.mux.HandleFunc("/admin/diagnostics/clock", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+		writeJSON(w, http.StatusOK, audit.Report(r.Context()))
+	})
+}
+
+// SetMaps 맵과 지오펜스 구역 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET    /admin/maps           사이트의 맵 목록 (구역 포함)
+//	GET    /admin/maps/<mapId>   맵 하나
+//	PUT    /admin/maps/<mapId>   {"description", "min_x", "min_y", "max_x", "max_y", "zones": [...]} 저장 (구역까지 교체)
+//	DELETE /admin/maps/<mapId>   맵과 구역 삭제
+//
+// 저장한 경계와 구역은 이후 전송하는 오더의 노드 위치 검사에 바로 적용됩니다.
+func (s *Server) SetMaps(db *gorm.DB, siteID string) {
+	writeError := func(w http.ResponseWriter, err error) {
+		status := http.StatusInternalServerError
+		switch apperr.CodeOf(err) {
+		case apperr.CodeNotFound:
+			status = http.StatusNotFound
+		case apperr.CodeValidationFailed:
+			status = http.StatusUnprocessableEntity
+		}
+		writeJSON(w, status, apperr.ToResponse(err, ""))
+	}
+
+	s.mux.HandleFunc("/admin/maps", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+		maps, err := repository.ListRobotMaps(db, siteID)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, maps)
+	})
+
+	s.mux.HandleFunc("/admin/maps/", func(w http.ResponseWriter, r *http.Request) {
+		mapID := strings.TrimPrefix(r.URL.Path, "/admin/maps/")
+		if mapID == "" || strings.Contains(mapID, "/") {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			robotMap, err := repository.FindRobotMap(db, siteID, mapID)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			if robotMap == nil {
+				writeError(w, apperr.New(apperr.CodeNotFound, "map %s not found", mapID).WithField("mapId"))
+				return
+			}
+			writeJSON(w, http.StatusOK, robotMap)
+		case http.MethodPut:
+			var robotMap models.RobotMap
+			if err := json.NewDecoder(r.Body).Decode(&robotMap); err != nil {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+				return
+			}
+			if robotMap.MapID != "" && robotMap.MapID != mapID {
+				writeError(w, apperr.Validation("map_id", "body map_id %q does not match path map id %q", robotMap.MapID, mapID))
+				return
+			}
+			robotMap.ID = 0
+			robotMap.MapID = mapID
+			if err := repository.SaveRobotMap(db, siteID, &robotMap); err != nil {
+				writeError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, robotMap)
+		case http.MethodDelete:
+			if err := repository.DeleteRobotMap(db, siteID, mapID); err != nil {
+				writeError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Allow", "GET, PUT, DELETE")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET, PUT or DELETE"})
+		}
+	})
+}
+
+// SetExecutionWindows 오더 실행 제한 시간대 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET    /admin/windows                    실행 제한 시간대 목록
+//	PUT    /admin/windows/<name>             {"start_time": "22:00", "end_time": "06:00", "timezone", "policy", "template_id", "robots", "is_active"} 저장 (전체 교체)
+//	DELETE /admin/windows/<name>             삭제
+//	PUT    /admin/windows/<name>/override    {"for": "2h"} 관리자 권한으로 제한 일시 해제 (대기 중인 명령은 1분 안에 배차)
+//	DELETE /admin/windows/<name>/override    일시 해제 취소
+func (s *Server) SetExecutionWindows(db *gorm.DB, siteID string) {
+	s.mux.HandleFunc("/admin/windows", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+		windows, err := repository.ListExecutionWindows(db, siteID)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, apperr.ToResponse(err, ""))
+			return
+		}
+		writeJSON(w, http.StatusOK, windows)
+	})
+
+	s.mux.HandleFunc("/admin/windows/", func(w http.ResponseWriter, r *http.Request) {
+		name, route, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/admin/windows/"), "/")
+		if name == "" || (route != "" && route != "override") {
+			http.NotFound(w, r)
+			return
+		}
+
+		if route == "override" {
+			var until *time.Time
+			switch r.Method {
+			case http.MethodPut:
+				var body struct {
+					For string `json:"for"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+					return
+				}
+				d, err := time.ParseDuration(body.For)
+				if err != nil || d <= 0 {
+					writeJSON(w, http.StatusUnprocessableEntity, apperr.ToResponse(apperr.Validation("for", "invalid override duration %q", body.For), ""))
+					return
+				}
+				t := time.Now().Add(d)
+				until = &t
+			case http.MethodDelete:
+			default:
+				w.Header().Set("Allow", "PUT, DELETE")
+				writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use PUT or DELETE"})
+				return
+			}
+			if err := repository.SetExecutionWindowOverride(db, siteID, name, until); err != nil {
+				writeJSON(w, settingsErrorStatus(err), apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{"name": name, "override_until": until})
+			return
+		}
+
+		switch r.Method {
+		case http.MethodPut:
+			var window models.ExecutionWindow
+			if err := json.NewDecoder(r.Body).Decode(&window); err != nil {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+				return
+			}
+			window.ID = 0
+			window.Name = name
+			if err := repository.SaveExecutionWindow(db, siteID, &window); err != nil {
+				writeJSON(w, settingsErrorStatus(err), apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusOK, window)
+		case http.MethodDelete:
+			if err := repository.DeleteExecutionWindow(db, siteID, name); err != nil {
+				writeJSON(w, settingsErrorStatus(err), apperr.ToResponse(err, ""))
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Allow", "PUT, DELETE")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use PUT or DELETE"})
+		}
+	})
+}
+
+// SetChargingPolicies 로봇 그룹별 자동 충전 정책 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET    /admin/charging/policies          충전 정책 목록
+//	PUT    /admin/charging/policies/<name>   {"low_battery": 20, "resume_battery": 80, "command_type": "CHG", "robots", "is_active"} 저장 (전체 교체)
+//	DELETE /admin/charging/policies/<name>   삭제 (충전 중인 로봇은 다음 평가에서 배차 가능으로 복귀)
+func (s *Server) SetChargingPolicies(db *gorm.DB, siteID string) {
+	s.mux.HandleFunc("/admin/charging/policies", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+		policies, err := repository.ListChargingPolicies(db, siteID)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, apperr.ToResponse(err, ""))
+			return
+		}
+		writeJSON(w, http.StatusOK, policies)
+	})
+
+	s.mux.HandleFunc("/admin/charging/policies/", func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/admin/charging/policies/")
+		if name == "" || strings.Contains(name, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			var policy models.ChargingPolicy
+			if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+				return
+			}
+			policy.ID = 0
+			policy.Name = name
+			if err := repository.SaveChargingPolicy(db, siteID, &policy); err != nil {
+				writeJSON(w, settingsErrorStatus(err), apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusOK, policy)
+		case http.MethodDelete:
+			if err := repository.DeleteChargingPolicy(db, siteID, name); err != nil {
+				writeJSON(w, settingsErrorStatus(err), apperr.ToResponse(err, ""))
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Allow", "PUT, DELETE")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use PUT or DELETE"})
+		}
+	})
+}
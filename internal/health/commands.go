@@ -0,0 +1,189 @@
+// internal/health/commands.go
+package health
+
+import (
+	"encoding/json"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/workflow"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CommandDryRunner 명령 드라이런 실행 함수
+type CommandDryRunner func(commandType string, params map[string]string) (*workflow.DryRunReport, error)
+
+// SetCommandDryRun 명령 드라이런 엔드포인트 등록 (Start 전에 호출)
+// 본문은 선택이며 {"params": {...}}로 템플릿 자리표시자 값을 전달합니다.
+func (s *Server) SetCommandDryRun(run CommandDryRunner) {
+	s.handleCommand("dry-run", func(w http.ResponseWriter, r *http.Request, commandType string) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+			return
+		}
+
+		var body struct {
+			Params map[string]string `json:"params"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("params", "invalid request body: %v", err), ""))
+				return
+			}
+		}
+		report, err := run(commandType, body.Params)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if apperr.CodeOf(err) == apperr.CodeCommandNotFound {
+				status = http.StatusNotFound
+			}
+			writeJSON(w, status, apperr.ToResponse(err, ""))
+			return
+		}
+		writeJSON(w, http.StatusOK, report)
+	})
+}
+
+// SetCommandHistory PLC 명령 이력 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET /admin/commands/history?type=CR&status=FAILURE&from=<RFC3339>&to=<RFC3339>&limit=50
+//	    명령과 실행, 오더 체인, PLC 응답 기록 (최근 순)
+//	GET /admin/commands/history/<id>   명령 하나의 전체 실행 트리 (단계 포함)
+func (s *Server) SetCommandHistory(db *gorm.DB, siteID string) {
+	s.mux.HandleFunc("/admin/commands/history", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", "GET")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+		query := r.URL.Query()
+		filter := repository.CommandHistoryFilter{Type: query.Get("type"), Status: query.Get("status")}
+		filter.Limit, _ = strconv.Atoi(query.Get("limit"))
+		for field, target := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+			raw := query.Get(field)
+			if raw == "" {
+				continue
+			}
+			parsed, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation(field, "invalid %s: use RFC3339", field), ""))
+				return
+			}
+			*target = parsed
+		}
+		history, err := repository.ListCommandHistory(db, siteID, filter)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, apperr.ToResponse(err, ""))
+			return
+		}
+		writeJSON(w, http.StatusOK, history)
+	})
+	s.mux.HandleFunc("/admin/commands/history/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", "GET")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+		id, err := strconv.ParseUint(strings.TrimPrefix(r.URL.Path, "/admin/commands/history/"), 10, 64)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		history, err := repository.GetCommandHistory(db, siteID, uint(id))
+		if err != nil {
+			writeJSON(w, rolloutErrorStatus(err), apperr.ToResponse(err, ""))
+			return
+		}
+		writeJSON(w, http.StatusOK, history)
+	})
+}
+
+// SetDirectActions PLC 직접 액션 레지스트리 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET    /admin/direct-actions            등록된 직접 액션 목록 (인자 포함)
+//	GET    /admin/direct-actions/<suffix>   정의 하나
+//	PUT    /admin/direct-actions/<suffix>   {"action_type", "parameter_key", "blocking_type", "arguments": [...]} 저장 (인자까지 교체)
+//	DELETE /admin/direct-actions/<suffix>   정의 삭제
+//
+// PUT 본문에 is_active가 없으면 활성으로 저장하며, 저장한 정의는 다음 PLC 명령부터 적용됩니다.
+func (s *Server) SetDirectActions(db *gorm.DB) {
+	writeError := func(w http.ResponseWriter, err error) {
+		status := http.StatusInternalServerError
+		switch apperr.CodeOf(err) {
+		case apperr.CodeNotFound:
+			status = http.StatusNotFound
+		case apperr.CodeValidationFailed:
+			status = http.StatusUnprocessableEntity
+		}
+		writeJSON(w, status, apperr.ToResponse(err, ""))
+	}
+
+	s.mux.HandleFunc("/admin/direct-actions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+		defs, err := repository.ListDirectActions(db)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, defs)
+	})
+
+	s.mux.HandleFunc("/admin/direct-actions/", func(w http.ResponseWriter, r *http.Request) {
+		suffix := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/admin/direct-actions/"), ":")
+		if suffix == "" || strings.Contains(suffix, "/") {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			def, err := repository.FindDirectAction(db, suffix)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			if def == nil {
+				writeError(w, apperr.New(apperr.CodeNotFound, "direct action %s not found", suffix).WithField("suffix"))
+				return
+			}
+			writeJSON(w, http.StatusOK, def)
+		case http.MethodPut:
+			// is_active를 생략하면 활성으로 저장
+			def := models.DirectActionDefinition{IsActive: true}
+			if err := json.NewDecoder(r.Body).Decode(&def); err != nil {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+				return
+			}
+			if def.Suffix != "" && def.Suffix != suffix {
+				writeError(w, apperr.Validation("suffix", "body suffix %q does not match path suffix %q", def.Suffix, suffix))
+				return
+			}
+			def.ID = 0
+			def.Suffix = suffix
+			if err := repository.SaveDirectAction(db, &def); err != nil {
+				writeError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, def)
+		case http.MethodDelete:
+			if err := repository.DeleteDirectAction(db, suffix); err != nil {
+				writeError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Allow", "GET, PUT, DELETE")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET, PUT or DELETE"})
+		}
+	})
+}
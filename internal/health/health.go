@@ -0,0 +1,394 @@
+// internal/health/health.go
+package health
+
+import (
+	"context"
+	"mqtt-bridge/internal/breaker"
+	"mqtt-bridge/internal/janitor"
+	"mqtt-bridge/internal/messaging"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/robot"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+// Status 헬스 상태 값
+const (
+	StatusUp       = "UP"
+	StatusDown     = "DOWN"
+	StatusDegraded = "DEGRADED"
+)
+
+// MQTTStatus MQTT 연결 상태 조회 인터페이스
+type MQTTStatus interface {
+	IsConnected() bool
+}
+
+// SubscriptionSource 구독 토픽 조회 인터페이스
+type SubscriptionSource interface {
+	Subscriptions() []string
+}
+
+// StateSource 로봇별 마지막 상태 메시지 수신 시각 조회 인터페이스
+type StateSource interface {
+	LastStateTimes() map[string]time.Time
+}
+
+// QueueSource 수집 큐 지표 조회 인터페이스
+type QueueSource interface {
+	QueueStats() []messaging.QueueStats
+}
+
+// SchemaSource 페이로드 스키마 검증 지표 조회 인터페이스
+type SchemaSource interface {
+	SchemaReport() *messaging.SchemaReport
+}
+
+// FreshnessSource 수신 메시지 타임스탬프 검사 지표 조회 인터페이스
+type FreshnessSource interface {
+	FreshnessReport() *messaging.FreshnessReport
+}
+
+// DedupSource PLC 중복 명령 억제 지표 조회 인터페이스
+type DedupSource interface {
+	SuppressedDuplicates() uint64
+}
+
+// ProtocolSource MQTT 5 클라이언트 지표 조회 인터페이스 (3.1.1 클라이언트면 nil 반환)
+type ProtocolSource interface {
+	ProtocolStats() *messaging.ProtocolStats
+}
+
+// DependencyStatus 개별 의존성 상태
+type DependencyStatus struct {
+	Status    string `json:"status"`
+	Critical  bool   `json:"critical"`
+	LatencyMs int64  `json:"latency_ms,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// RobotHealth 로봇별 상태 메시지 수신 현황
+type RobotHealth struct {
+	SerialNumber    string     `json:"serial_number"`
+	ConnectionState string     `json:"connection_state"`
+	LastStateAt     *time.Time `json:"last_state_at,omitempty"`
+	Stale           bool       `json:"stale"`
+}
+
+// Report 헬스 체크 결과
+type Report struct {
+	Status        string                      `json:"status"`
+	CheckedAt     time.Time                   `json:"checked_at"`
+	Dependencies  map[string]DependencyStatus `json:"dependencies"`
+	Subscriptions []string                    `json:"subscriptions"`
+	Robots        []RobotHealth               `json:"robots"`
+	Queues        []messaging.QueueStats      `json:"queues,omitempty"`
+	Breakers      []breaker.Stats             `json:"breakers,omitempty"`
+	Buffer        *messaging.BufferStats      `json:"outbound_buffer,omitempty"`
+	MQTT5         *messaging.ProtocolStats    `json:"mqtt5,omitempty"`
+}
+
+// Ready 중요 의존성이 모두 정상인지 여부
+func (r *Report) Ready() bool {
+	return r.Status != StatusDown
+}
+
+// Checker 브릿지 의존성 상태 집계기
+type Checker struct {
+	db            *gorm.DB
+	redis         *redis.Client
+	mqtt          MQTTStatus
+	subscriptions SubscriptionSource
+	states        StateSource
+	queues        QueueSource
+	schemas       SchemaSource
+	freshness     FreshnessSource
+	dedup         DedupSource
+	breakers      []*breaker.Breaker
+	buffer        *messaging.OutboundBuffer
+	protocol      ProtocolSource
+	janitor       *janitor.Janitor
+	stateWriter   *robot.StateWriter
+	siteID        string
+	staleAfter    time.Duration
+	timeout       time.Duration
+}
+
+// NewChecker 새 헬스 체커 생성
+func NewChecker(db *gorm.DB, redisClient *redis.Client, mqtt MQTTStatus, subscriptions SubscriptionSource, states StateSource, siteID string, staleAfter time.Duration) *Checker {
+	return &Checker{
+		db:            db,
+		redis:         redisClient,
+		mqtt:          mqtt,
+		subscriptions: subscriptions,
+		states:        states,
+		siteID:        siteID,
+		staleAfter:    staleAfter,
+		timeout:       2 * time.Second,
+	}
+}
+
+// SetQueueSource 수집 큐 지표 조회 대상 설정 (큐가 가득 차면 DEGRADED로 보고)
+func (c *Checker) SetQueueSource(queues QueueSource) {
+	c.queues = queues
+}
+
+// QueueStats 수집 큐 지표 (조회 대상이 없으면 nil)
+func (c *Checker) QueueStats() []messaging.QueueStats {
+	if c.queues == nil {
+		return nil
+	}
+	return c.queues.QueueStats()
+}
+
+// SetSchemaSource 페이로드 스키마 검증 지표 조회 대상 설정
+func (c *Checker) SetSchemaSource(schemas SchemaSource) {
+	c.schemas = schemas
+}
+
+// SchemaReport 페이로드 스키마 검증 지표 (조회 대상이 없으면 nil)
+func (c *Checker) SchemaReport() *messaging.SchemaReport {
+	if c.schemas == nil {
+		return nil
+	}
+	return c.schemas.SchemaReport()
+}
+
+// SetFreshnessSource 수신 메시지 타임스탬프 검사 지표 조회 대상 설정
+func (c *Checker) SetFreshnessSource(freshness FreshnessSource) {
+	c.freshness = freshness
+}
+
+// FreshnessReport 수신 메시지 타임스탬프 검사 지표 (조회 대상이 없으면 nil)
+func (c *Checker) FreshnessReport() *messaging.FreshnessReport {
+	if c.freshness == nil {
+		return nil
+	}
+	return c.freshness.FreshnessReport()
+}
+
+// SetDedupSource PLC 중복 명령 억제 지표 조회 대상 설정
+func (c *Checker) SetDedupSource(dedup DedupSource) {
+	c.dedup = dedup
+}
+
+// SuppressedDuplicates 무시한 PLC 중복 명령 수 (억제가 꺼져 있으면 false)
+func (c *Checker) SuppressedDuplicates() (uint64, bool) {
+	if c.dedup == nil {
+		return 0, false
+	}
+	return c.dedup.SuppressedDuplicates(), true
+}
+
+// SetBreakers Postgres/Redis 회로 차단기 설정 (열려 있으면 DEGRADED로 보고)
+func (c *Checker) SetBreakers(breakers []*breaker.Breaker) {
+	c.breakers = breakers
+}
+
+// BreakerStats 회로 차단기 상태 (설정되지 않았으면 nil)
+func (c *Checker) BreakerStats() []breaker.Stats {
+	var stats []breaker.Stats
+	for _, b := range c.breakers {
+		stats = append(stats, b.Stats())
+	}
+	return stats
+}
+
+// SetOutboundBuffer MQTT 발행 버퍼 설정 (보관 중인 메시지가 있으면 DEGRADED로 보고)
+func (c *Checker) SetOutboundBuffer(buffer *messaging.OutboundBuffer) {
+	c.buffer = buffer
+}
+
+// BufferStats MQTT 발행 버퍼 지표 (설정되지 않았으면 nil)
+func (c *Checker) BufferStats() *messaging.BufferStats {
+	if c.buffer == nil {
+		return nil
+	}
+	stats := c.buffer.Stats()
+	return &stats
+}
+
+// SetProtocolSource MQTT 5 클라이언트 지표 조회 대상 설정 (발행 거부 사유 코드를 /metrics에 노출)
+func (c *Checker) SetProtocolSource(protocol ProtocolSource) {
+	c.protocol = protocol
+}
+
+// ProtocolStats MQTT 5 클라이언트 지표 (설정되지 않았거나 3.1.1 클라이언트면 nil)
+func (c *Checker) ProtocolStats() *messaging.ProtocolStats {
+	if c.protocol == nil {
+		return nil
+	}
+	return c.protocol.ProtocolStats()
+}
+
+// SetRedisJanitor Redis 키 정리기 설정 (정리 지표를 /metrics에 노출)
+func (c *Checker) SetRedisJanitor(j *janitor.Janitor) {
+	c.janitor = j
+}
+
+// JanitorStats Redis 키 정리 지표 (설정되지 않았으면 nil)
+func (c *Checker) JanitorStats() *janitor.Stats {
+	if c.janitor == nil {
+		return nil
+	}
+	stats := c.janitor.Stats()
+	return &stats
+}
+
+// SetStateWriter 로봇 상태 기록기 설정 (기록 지표를 /metrics에 노출)
+func (c *Checker) SetStateWriter(w *robot.StateWriter) {
+	c.stateWriter = w
+}
+
+// StateWriterStats 로봇 상태 기록 지표 (설정되지 않았으면 nil)
+func (c *Checker) StateWriterStats() *robot.StateWriterStats {
+	if c.stateWriter == nil {
+		return nil
+	}
+	stats := c.stateWriter.Stats()
+	return &stats
+}
+
+// Check 모든 의존성을 확인하여 보고서 생성
+func (c *Checker) Check(ctx context.Context) *Report {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	report := &Report{
+		Status:       StatusUp,
+		CheckedAt:    time.Now(),
+		Dependencies: make(map[string]DependencyStatus),
+	}
+
+	report.Dependencies["mqtt"] = c.checkMQTT()
+	report.Dependencies["postgres"] = c.checkPostgres(ctx)
+	report.Dependencies["redis"] = c.checkRedis(ctx)
+
+	for _, dep := range report.Dependencies {
+		if dep.Status == StatusDown && dep.Critical {
+			report.Status = StatusDown
+			break
+		}
+	}
+
+	if c.subscriptions != nil {
+		report.Subscriptions = c.subscriptions.Subscriptions()
+	}
+	if len(report.Subscriptions) == 0 && report.Status == StatusUp {
+		report.Status = StatusDegraded
+	}
+
+	report.Robots = c.robotHealth(ctx, report.Dependencies["postgres"].Status == StatusUp)
+	for _, robot := range report.Robots {
+		if robot.Stale && report.Status == StatusUp {
+			report.Status = StatusDegraded
+		}
+	}
+
+	report.Queues = c.QueueStats()
+	for _, queue := range report.Queues {
+		if queue.Depth >= queue.Capacity && report.Status == StatusUp {
+			report.Status = StatusDegraded
+		}
+	}
+
+	report.Breakers = c.BreakerStats()
+	for _, b := range report.Breakers {
+		if b.State != breaker.StateClosed && report.Status == StatusUp {
+			report.Status = StatusDegraded
+		}
+	}
+
+	report.Buffer = c.BufferStats()
+	if report.Buffer != nil && report.Buffer.Messages > 0 && report.Status == StatusUp {
+		report.Status = StatusDegraded
+	}
+	report.MQTT5 = c.ProtocolStats()
+
+	return report
+}
+
+// checkMQTT MQTT 브로커 연결 확인
+func (c *Checker) checkMQTT() DependencyStatus {
+	if c.mqtt != nil && c.mqtt.IsConnected() {
+		return DependencyStatus{Status: StatusUp, Critical: true}
+	}
+	return DependencyStatus{Status: StatusDown, Critical: true, Error: "MQTT client is not connected"}
+}
+
+// checkPostgres 데이터베이스 ping
+func (c *Checker) checkPostgres(ctx context.Context) DependencyStatus {
+	start := time.Now()
+	sqlDB, err := c.db.DB()
+	if err == nil {
+		err = sqlDB.PingContext(ctx)
+	}
+	return dependencyResult(start, err)
+}
+
+// checkRedis Redis ping
+func (c *Checker) checkRedis(ctx context.Context) DependencyStatus {
+	start := time.Now()
+	return dependencyResult(start, c.redis.Ping(ctx).Err())
+}
+
+// dependencyResult ping 결과를 중요 의존성 상태로 변환
+func dependencyResult(start time.Time, err error) DependencyStatus {
+	status := DependencyStatus{
+		Status:    StatusUp,
+		Critical:  true,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		status.Status = StatusDown
+		status.Error = err.Error()
+	}
+	return status
+}
+
+// robotHealth 사이트 로봇의 마지막 상태 메시지 수신 현황
+func (c *Checker) robotHealth(ctx context.Context, dbUp bool) []RobotHealth {
+	var lastStates map[string]time.Time
+	if c.states != nil {
+		lastStates = c.states.LastStateTimes()
+	}
+
+	robots := make([]RobotHealth, 0)
+	seen := make(map[string]bool)
+
+	if dbUp {
+		var statuses []models.RobotStatus
+		c.db.WithContext(ctx).Scopes(repository.SiteScope(c.siteID)).
+			Order("serial_number ASC").
+			Find(&statuses)
+		for _, status := range statuses {
+			robots = append(robots, c.newRobotHealth(status.SerialNumber, status.ConnectionState, lastStates))
+			seen[status.SerialNumber] = true
+		}
+	}
+
+	// DB에 아직 기록되지 않았지만 상태 메시지를 수신한 로봇
+	for serial := range lastStates {
+		if !seen[serial] {
+			robots = append(robots, c.newRobotHealth(serial, "", lastStates))
+		}
+	}
+
+	return robots
+}
+
+// newRobotHealth 로봇 상태 항목 생성
+func (c *Checker) newRobotHealth(serial, connectionState string, lastStates map[string]time.Time) RobotHealth {
+	robot := RobotHealth{
+		SerialNumber:    serial,
+		ConnectionState: connectionState,
+	}
+	if t, ok := lastStates[serial]; ok {
+		robot.LastStateAt = &t
+		robot.Stale = c.staleAfter > 0 && time.Since(t) > c.staleAfter
+	}
+	return robot
+}
@@ -0,0 +1,124 @@
+// internal/health/idempotency.go
+package health
+
+import (
+	"bytes"
+	"mqtt-bridge/internal/common/apperr"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// IdempotencyKeyHeader 변경 요청(POST, PUT, PATCH, DELETE)을 재시도해도 한 번만 처리되게 하는 요청 헤더
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayHeader 저장된 응답을 다시 보낼 때 붙이는 응답 헤더
+const IdempotentReplayHeader = "Idempotent-Replayed"
+
+// idempotencyTTL 같은 키의 응답을 다시 보내는 기간
+const idempotencyTTL = 10 * time.Minute
+
+// idempotencyCache 키별로 처리한 변경 요청의 응답을 보관 (프로세스 메모리, 재시작하면 비워짐)
+type idempotencyCache struct {
+	mu      sync.Mutex
+	entries map[string]*idempotentResponse
+}
+
+// idempotentResponse 키로 처리한 요청과 그 응답 (done이 닫히기 전에는 처리 중)
+type idempotentResponse struct {
+	method  string
+	path    string
+	done    chan struct{}
+	status  int
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+func newIdempotencyCache() *idempotencyCache {
+	return &idempotencyCache{entries: make(map[string]*idempotentResponse)}
+}
+
+// Wrap Idempotency-Key가 있는 변경 요청은 처음 한 번만 처리하고, 이후에는 저장한 응답을 그대로 보냅니다.
+// 같은 키를 다른 요청에 쓰면 422, 첫 요청이 아직 처리 중이면 409를 반환합니다.
+func (c *idempotencyCache) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" || (r.Method != http.MethodPost && r.Method != http.MethodPut && r.Method != http.MethodPatch && r.Method != http.MethodDelete) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		now := time.Now()
+		c.mu.Lock()
+		entry, exists := c.entries[key]
+		if exists && now.After(entry.expires) {
+			exists = false
+		}
+		if !exists {
+			for k, e := range c.entries {
+				if now.After(e.expires) {
+					delete(c.entries, k)
+				}
+			}
+			entry = &idempotentResponse{method: r.Method, path: r.URL.RequestURI(), done: make(chan struct{}), expires: now.Add(idempotencyTTL)}
+			c.entries[key] = entry
+		}
+		c.mu.Unlock()
+
+		if exists {
+			if entry.method != r.Method || entry.path != r.URL.RequestURI() {
+				writeJSON(w, http.StatusUnprocessableEntity, apperr.ToResponse(apperr.Validation(IdempotencyKeyHeader,
+					"idempotency key was already used for %s %s", entry.method, entry.path), ""))
+				return
+			}
+			select {
+			case <-entry.done:
+			default:
+				writeJSON(w, http.StatusConflict, apperr.ToResponse(apperr.New(apperr.CodeValidationFailed,
+					"a request with this idempotency key is still in progress").WithField(IdempotencyKeyHeader), ""))
+				return
+			}
+			for name, values := range entry.header {
+				w.Header()[name] = values
+			}
+			w.Header().Set(IdempotentReplayHeader, "true")
+			w.WriteHeader(entry.status)
+			w.Write(entry.body)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			c.mu.Lock()
+			if recorder.status >= http.StatusInternalServerError {
+				// 서버 오류는 재시도로 다시 처리될 수 있도록 저장하지 않습니다.
+				delete(c.entries, key)
+			} else {
+				entry.status = recorder.status
+				entry.header = w.Header().Clone()
+				entry.body = recorder.body.Bytes()
+			}
+			close(entry.done)
+			c.mu.Unlock()
+		}()
+		next.ServeHTTP(recorder, r)
+	})
+}
+
+// responseRecorder 응답을 보내면서 상태 코드와 본문을 기록
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
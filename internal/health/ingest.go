@@ -0,0 +1,81 @@
+// internal/health/ingest.go
+package health
+
+import (
+	"encoding/json"
+	"io"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/messaging"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"net/http"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// maxIngestSize HTTP로 받는 state/connection 메시지 최대 크기
+const maxIngestSize = 1 << 20
+
+// SetHTTPIngest HTTP state/connection 수신 엔드포인트 등록 (Start 전에 호출)
+//
+//	POST /api/v1/ingest/state        VDA 5050 state 메시지 (202, MQTT state 토픽과 같은 처리)
+//	POST /api/v1/ingest/connection   VDA 5050 connection 메시지 (202, MQTT connection 토픽과 같은 처리)
+//
+// Authorization: Bearer <로봇 토큰>으로 인증하며, 토큰이 없거나 유효하지 않으면 401,
+// 토큰의 로봇과 메시지의 serialNumber가 다르면 403을 반환합니다.
+func (s *Server) SetHTTPIngest(db *gorm.DB, siteID string, ingest *messaging.HTTPIngest) {
+	for _, kind := range []string{messaging.HTTPIngestState, messaging.HTTPIngestConnection} {
+		s.mux.HandleFunc("/api/v1/ingest/"+kind, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				w.Header().Set("Allow", http.MethodPost)
+				writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+				return
+			}
+			token, err := authenticateRobot(db, siteID, r)
+			if err != nil {
+				status := http.StatusInternalServerError
+				if apperr.CodeOf(err) == apperr.CodeUnauthorized {
+					w.Header().Set("WWW-Authenticate", `Bearer realm="robot"`)
+					status = http.StatusUnauthorized
+				}
+				writeJSON(w, status, apperr.ToResponse(err, ""))
+				return
+			}
+
+			payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIngestSize))
+			if err != nil {
+				writeJSON(w, http.StatusRequestEntityTooLarge, apperr.ToResponse(apperr.New(apperr.CodePayloadTooLarge, "failed to read %s payload: %v", kind, err), ""))
+				return
+			}
+			var header struct {
+				SerialNumber string `json:"serialNumber"`
+			}
+			if err := json.Unmarshal(payload, &header); err != nil {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid %s payload: %v", kind, err), ""))
+				return
+			}
+			if header.SerialNumber != token.SerialNumber {
+				writeJSON(w, http.StatusForbidden, apperr.ToResponse(apperr.New(apperr.CodeUnauthorized,
+					"token %s belongs to %s, not %q", token.TokenID, token.SerialNumber, header.SerialNumber), ""))
+				return
+			}
+
+			ingested, err := ingest.Submit(kind, payload)
+			if err != nil {
+				writeJSON(w, http.StatusUnprocessableEntity, apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusAccepted, ingested)
+		})
+	}
+}
+
+// authenticateRobot Authorization: Bearer 헤더의 로봇 토큰 확인
+func authenticateRobot(db *gorm.DB, siteID string, r *http.Request) (*models.RobotToken, error) {
+	scheme, raw, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(raw) == "" {
+		return nil, apperr.New(apperr.CodeUnauthorized, "robot token required (Authorization: Bearer <token>)")
+	}
+	return repository.VerifyRobotToken(db, siteID, raw)
+}
@@ -0,0 +1,128 @@
+// internal/health/logs.go
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// logStreamKeepAlive 로그 스트림 연결 유지용 주석을 보내는 주기 (프록시 유휴 시간 초과 방지)
+const logStreamKeepAlive = 15 * time.Second
+
+// SetLogStream 최근 로그 조회와 실시간 로그 스트림 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET /admin/logs?level=warn&module=workflow,messaging&limit=200   버퍼의 최근 로그 (JSON, 오래된 순)
+//	GET /admin/logs/stream?level=&module=&backlog=50                 최근 로그 backlog개 뒤 새 로그 (text/event-stream)
+//
+// 스트림의 각 이벤트는 data: 한 줄의 LogEntry JSON이며 id는 로그 순번입니다.
+// 따라오지 못하는 클라이언트는 로깅을 막지 않도록 서버가 연결을 끊습니다.
+func (s *Server) SetLogStream(buffer *utils.LogBuffer) {
+	shutdown, cancelStreams := context.WithCancel(context.Background())
+	s.server.RegisterOnShutdown(cancelStreams)
+
+	s.mux.HandleFunc("/admin/logs", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+		filter, err := logFilterFromQuery(r)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, apperr.ToResponse(err, ""))
+			return
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"buffer_size": buffer.Size(),
+			"entries":     buffer.Recent(filter, limit),
+		})
+	})
+	s.mux.HandleFunc("/admin/logs/stream", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "streaming not supported"})
+			return
+		}
+		filter, err := logFilterFromQuery(r)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, apperr.ToResponse(err, ""))
+			return
+		}
+		backlog := 50
+		if v, err := strconv.Atoi(r.URL.Query().Get("backlog")); err == nil && v >= 0 {
+			backlog = v
+		}
+
+		recent, entries, cancel := buffer.Subscribe(filter, backlog)
+		defer cancel()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		for _, entry := range recent {
+			writeLogEvent(w, entry)
+		}
+		flusher.Flush()
+
+		keepAlive := time.NewTicker(logStreamKeepAlive)
+		defer keepAlive.Stop()
+		for {
+			select {
+			case entry, open := <-entries:
+				if !open {
+					fmt.Fprint(w, "event: dropped\ndata: {\"error\":\"client too slow, reconnect\"}\n\n")
+					flusher.Flush()
+					return
+				}
+				writeLogEvent(w, entry)
+				flusher.Flush()
+			case <-keepAlive.C:
+				fmt.Fprint(w, ": keepalive\n\n")
+				flusher.Flush()
+			case <-r.Context().Done():
+				return
+			case <-shutdown.Done():
+				return
+			}
+		}
+	})
+}
+
+// logFilterFromQuery level, module(쉼표 구분) 쿼리로 로그 조건 생성
+func logFilterFromQuery(r *http.Request) (utils.LogFilter, error) {
+	query := r.URL.Query()
+	filter := utils.LogFilter{Level: strings.ToLower(query.Get("level"))}
+	if filter.Level != "" {
+		if _, err := logrus.ParseLevel(filter.Level); err != nil {
+			return filter, apperr.Validation("level", "invalid level %q (debug, info, warn, error)", filter.Level)
+		}
+	}
+	if raw := query.Get("module"); raw != "" {
+		filter.Modules = strings.Split(raw, ",")
+	}
+	return filter, nil
+}
+
+// writeLogEvent 로그 하나를 SSE 이벤트로 기록
+func writeLogEvent(w http.ResponseWriter, entry utils.LogEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %d\ndata: %s\n\n", entry.Seq, data)
+}
@@ -0,0 +1,300 @@
+// internal/health/metrics.go
+package health
+
+import (
+	"context"
+	"fmt"
+	"mqtt-bridge/internal/breaker"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/messaging"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/robot"
+	"mqtt-bridge/internal/utils"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+// handleMetrics 수집 큐 깊이/처리/버림 지표
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	stats := s.checker.QueueStats()
+
+	fmt.Fprintln(w, "# HELP mqtt_bridge_ingest_queue_depth Messages waiting in the ingest queue")
+	fmt.Fprintln(w, "# TYPE mqtt_bridge_ingest_queue_depth gauge")
+	for _, q := range stats {
+		fmt.Fprintf(w, "mqtt_bridge_ingest_queue_depth{queue=%q} %d\n", q.Name, q.Depth)
+	}
+	fmt.Fprintln(w, "# HELP mqtt_bridge_ingest_queue_capacity Ingest queue capacity")
+	fmt.Fprintln(w, "# TYPE mqtt_bridge_ingest_queue_capacity gauge")
+	for _, q := range stats {
+		fmt.Fprintf(w, "mqtt_bridge_ingest_queue_capacity{queue=%q} %d\n", q.Name, q.Capacity)
+	}
+	fmt.Fprintln(w, "# HELP mqtt_bridge_ingest_messages_total Messages by ingest queue and outcome")
+	fmt.Fprintln(w, "# TYPE mqtt_bridge_ingest_messages_total counter")
+	for _, q := range stats {
+		fmt.Fprintf(w, "mqtt_bridge_ingest_messages_total{queue=%q,outcome=\"enqueued\"} %d\n", q.Name, q.Enqueued)
+		fmt.Fprintf(w, "mqtt_bridge_ingest_messages_total{queue=%q,outcome=\"processed\"} %d\n", q.Name, q.Processed)
+		fmt.Fprintf(w, "mqtt_bridge_ingest_messages_total{queue=%q,outcome=\"dropped\"} %d\n", q.Name, q.Dropped)
+	}
+
+	if s.readOnly != nil {
+		readOnly := 0
+		if s.readOnly.Enabled() {
+			readOnly = 1
+		}
+		fmt.Fprintln(w, "# HELP mqtt_bridge_read_only Whether the bridge rejects mutating operations (1=read-only)")
+		fmt.Fprintln(w, "# TYPE mqtt_bridge_read_only gauge")
+		fmt.Fprintf(w, "mqtt_bridge_read_only %d\n", readOnly)
+	}
+
+	if s.latency != nil {
+		latencies := s.latency.Latencies()
+		fmt.Fprintln(w, "# HELP mqtt_bridge_robot_state_latency_ms Robot state header timestamp to receipt delay over the recent window")
+		fmt.Fprintln(w, "# TYPE mqtt_bridge_robot_state_latency_ms gauge")
+		for _, l := range latencies {
+			fmt.Fprintf(w, "mqtt_bridge_robot_state_latency_ms{serial=%q,stat=\"p50\"} %.1f\n", l.SerialNumber, l.P50Ms)
+			fmt.Fprintf(w, "mqtt_bridge_robot_state_latency_ms{serial=%q,stat=\"p95\"} %.1f\n", l.SerialNumber, l.P95Ms)
+			fmt.Fprintf(w, "mqtt_bridge_robot_state_latency_ms{serial=%q,stat=\"max\"} %.1f\n", l.SerialNumber, l.MaxMs)
+		}
+		fmt.Fprintln(w, "# HELP mqtt_bridge_robot_clock_offset_ms Estimated robot clock offset from the bridge clock (negative=robot ahead)")
+		fmt.Fprintln(w, "# TYPE mqtt_bridge_robot_clock_offset_ms gauge")
+		for _, l := range latencies {
+			fmt.Fprintf(w, "mqtt_bridge_robot_clock_offset_ms{serial=%q} %.1f\n", l.SerialNumber, l.ClockOffsetMs)
+		}
+	}
+
+	if suppressed, ok := s.checker.SuppressedDuplicates(); ok {
+		fmt.Fprintln(w, "# HELP mqtt_bridge_plc_duplicate_commands_suppressed_total PLC command retransmissions ignored within the dedup window")
+		fmt.Fprintln(w, "# TYPE mqtt_bridge_plc_duplicate_commands_suppressed_total counter")
+		fmt.Fprintf(w, "mqtt_bridge_plc_duplicate_commands_suppressed_total %d\n", suppressed)
+	}
+
+	if breakers := s.checker.BreakerStats(); len(breakers) > 0 {
+		fmt.Fprintln(w, "# HELP mqtt_bridge_circuit_breaker_state Circuit breaker state (0=closed, 1=half-open, 2=open)")
+		fmt.Fprintln(w, "# TYPE mqtt_bridge_circuit_breaker_state gauge")
+		for _, b := range breakers {
+			state := 0
+			switch b.State {
+			case breaker.StateHalfOpen:
+				state = 1
+			case breaker.StateOpen:
+				state = 2
+			}
+			fmt.Fprintf(w, "mqtt_bridge_circuit_breaker_state{name=%q} %d\n", b.Name, state)
+		}
+		fmt.Fprintln(w, "# HELP mqtt_bridge_circuit_breaker_events_total Circuit breaker failures, rejected calls and openings")
+		fmt.Fprintln(w, "# TYPE mqtt_bridge_circuit_breaker_events_total counter")
+		for _, b := range breakers {
+			fmt.Fprintf(w, "mqtt_bridge_circuit_breaker_events_total{name=%q,event=\"failure\"} %d\n", b.Name, b.Failures)
+			fmt.Fprintf(w, "mqtt_bridge_circuit_breaker_events_total{name=%q,event=\"rejected\"} %d\n", b.Name, b.Rejected)
+			fmt.Fprintf(w, "mqtt_bridge_circuit_breaker_events_total{name=%q,event=\"opened\"} %d\n", b.Name, b.Opens)
+		}
+	}
+
+	if buffer := s.checker.BufferStats(); buffer != nil {
+		fmt.Fprintln(w, "# HELP mqtt_bridge_outbound_buffer_messages Outbound MQTT messages held while the broker is unreachable")
+		fmt.Fprintln(w, "# TYPE mqtt_bridge_outbound_buffer_messages gauge")
+		fmt.Fprintf(w, "mqtt_bridge_outbound_buffer_messages %d\n", buffer.Messages)
+		fmt.Fprintln(w, "# HELP mqtt_bridge_outbound_buffer_bytes Payload bytes held in the outbound MQTT buffer")
+		fmt.Fprintln(w, "# TYPE mqtt_bridge_outbound_buffer_bytes gauge")
+		fmt.Fprintf(w, "mqtt_bridge_outbound_buffer_bytes %d\n", buffer.Bytes)
+		fmt.Fprintln(w, "# HELP mqtt_bridge_outbound_buffer_total Outbound MQTT buffer messages by outcome")
+		fmt.Fprintln(w, "# TYPE mqtt_bridge_outbound_buffer_total counter")
+		fmt.Fprintf(w, "mqtt_bridge_outbound_buffer_total{outcome=\"buffered\"} %d\n", buffer.Buffered)
+		fmt.Fprintf(w, "mqtt_bridge_outbound_buffer_total{outcome=\"flushed\"} %d\n", buffer.Flushed)
+		fmt.Fprintf(w, "mqtt_bridge_outbound_buffer_total{outcome=\"expired\"} %d\n", buffer.Expired)
+		fmt.Fprintf(w, "mqtt_bridge_outbound_buffer_total{outcome=\"rejected\"} %d\n", buffer.Rejected)
+	}
+
+	if protocol := s.checker.ProtocolStats(); protocol != nil {
+		codes := make([]string, 0, len(protocol.PublishFailures))
+		for code := range protocol.PublishFailures {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		fmt.Fprintln(w, "# HELP mqtt_bridge_mqtt_publish_failures_total MQTT 5 publishes rejected or failed, by broker reason code")
+		fmt.Fprintln(w, "# TYPE mqtt_bridge_mqtt_publish_failures_total counter")
+		for _, code := range codes {
+			fmt.Fprintf(w, "mqtt_bridge_mqtt_publish_failures_total{reason_code=%q} %d\n", code, protocol.PublishFailures[code])
+		}
+		fmt.Fprintln(w, "# HELP mqtt_bridge_mqtt_factsheet_requests_total Factsheet requests sent with response topic/correlation data and responses matched to them")
+		fmt.Fprintln(w, "# TYPE mqtt_bridge_mqtt_factsheet_requests_total counter")
+		fmt.Fprintf(w, "mqtt_bridge_mqtt_factsheet_requests_total{outcome=\"sent\"} %d\n", protocol.FactsheetRequests)
+		fmt.Fprintf(w, "mqtt_bridge_mqtt_factsheet_requests_total{outcome=\"answered\"} %d\n", protocol.FactsheetResponses)
+	}
+
+	if report := s.checker.SchemaReport(); report != nil {
+		fmt.Fprintln(w, "# HELP mqtt_bridge_payload_validation_total Incoming payloads by schema validation outcome")
+		fmt.Fprintln(w, "# TYPE mqtt_bridge_payload_validation_total counter")
+		for _, k := range report.Kinds {
+			fmt.Fprintf(w, "mqtt_bridge_payload_validation_total{kind=%q,outcome=\"validated\"} %d\n", k.Kind, k.Validated)
+			fmt.Fprintf(w, "mqtt_bridge_payload_validation_total{kind=%q,outcome=\"invalid\"} %d\n", k.Kind, k.Invalid)
+			fmt.Fprintf(w, "mqtt_bridge_payload_validation_total{kind=%q,outcome=\"rejected\"} %d\n", k.Kind, k.Rejected)
+		}
+	}
+
+	if report := s.checker.FreshnessReport(); report != nil {
+		fmt.Fprintln(w, "# HELP mqtt_bridge_message_timestamp_total Incoming messages by header timestamp check outcome")
+		fmt.Fprintln(w, "# TYPE mqtt_bridge_message_timestamp_total counter")
+		for _, k := range report.Kinds {
+			fmt.Fprintf(w, "mqtt_bridge_message_timestamp_total{kind=%q,outcome=\"checked\"} %d\n", k.Kind, k.Checked)
+			fmt.Fprintf(w, "mqtt_bridge_message_timestamp_total{kind=%q,outcome=\"unparsable\"} %d\n", k.Kind, k.Unparsable)
+			fmt.Fprintf(w, "mqtt_bridge_message_timestamp_total{kind=%q,outcome=\"skewed\"} %d\n", k.Kind, k.Skewed)
+			fmt.Fprintf(w, "mqtt_bridge_message_timestamp_total{kind=%q,outcome=\"stale_dropped\"} %d\n", k.Kind, k.StaleDropped)
+		}
+	}
+
+	if stats := s.checker.JanitorStats(); stats != nil {
+		fmt.Fprintln(w, "# HELP mqtt_bridge_redis_janitor_runs_total Redis key janitor runs by outcome")
+		fmt.Fprintln(w, "# TYPE mqtt_bridge_redis_janitor_runs_total counter")
+		fmt.Fprintf(w, "mqtt_bridge_redis_janitor_runs_total{outcome=\"success\"} %d\n", stats.Runs-stats.Failures)
+		fmt.Fprintf(w, "mqtt_bridge_redis_janitor_runs_total{outcome=\"failure\"} %d\n", stats.Failures)
+		fmt.Fprintln(w, "# HELP mqtt_bridge_redis_janitor_keys_total Bridge-owned Redis keys by janitor outcome")
+		fmt.Fprintln(w, "# TYPE mqtt_bridge_redis_janitor_keys_total counter")
+		for _, k := range stats.Keys {
+			fmt.Fprintf(w, "mqtt_bridge_redis_janitor_keys_total{pattern=%q,outcome=\"scanned\"} %d\n", k.Pattern, k.Scanned)
+			fmt.Fprintf(w, "mqtt_bridge_redis_janitor_keys_total{pattern=%q,outcome=\"deleted\"} %d\n", k.Pattern, k.Deleted)
+			fmt.Fprintf(w, "mqtt_bridge_redis_janitor_keys_total{pattern=%q,outcome=\"ttl_set\"} %d\n", k.Pattern, k.TTLSet)
+		}
+		if stats.LastRunAt != nil {
+			fmt.Fprintln(w, "# HELP mqtt_bridge_redis_janitor_last_run_timestamp_seconds Time of the last Redis key janitor run")
+			fmt.Fprintln(w, "# TYPE mqtt_bridge_redis_janitor_last_run_timestamp_seconds gauge")
+			fmt.Fprintf(w, "mqtt_bridge_redis_janitor_last_run_timestamp_seconds %d\n", stats.LastRunAt.Unix())
+		}
+	}
+
+	if stats := s.checker.StateWriterStats(); stats != nil {
+		fmt.Fprintln(w, "# HELP mqtt_bridge_state_writer_shards Robot state writer shards")
+		fmt.Fprintln(w, "# TYPE mqtt_bridge_state_writer_shards gauge")
+		fmt.Fprintf(w, "mqtt_bridge_state_writer_shards %d\n", stats.Shards)
+		fmt.Fprintln(w, "# HELP mqtt_bridge_state_writer_pending Robots with state waiting for the next write")
+		fmt.Fprintln(w, "# TYPE mqtt_bridge_state_writer_pending gauge")
+		fmt.Fprintf(w, "mqtt_bridge_state_writer_pending %d\n", stats.Pending)
+		fmt.Fprintln(w, "# HELP mqtt_bridge_state_writer_observed_total State messages collected by the robot state writer")
+		fmt.Fprintln(w, "# TYPE mqtt_bridge_state_writer_observed_total counter")
+		fmt.Fprintf(w, "mqtt_bridge_state_writer_observed_total %d\n", stats.Observed)
+		fmt.Fprintln(w, "# HELP mqtt_bridge_state_writer_updates_total Robot state UPDATE statements by outcome")
+		fmt.Fprintln(w, "# TYPE mqtt_bridge_state_writer_updates_total counter")
+		fmt.Fprintf(w, "mqtt_bridge_state_writer_updates_total{outcome=\"success\"} %d\n", stats.Updates-stats.Failures)
+		fmt.Fprintf(w, "mqtt_bridge_state_writer_updates_total{outcome=\"failure\"} %d\n", stats.Failures)
+	}
+}
+
+// 로봇 SLA 지표 집계 기간
+const (
+	defaultRobotStatsWindow = 24 * time.Hour
+	maxRobotStatsWindow     = 90 * 24 * time.Hour
+)
+
+// SetRobotStats 로봇별 SLA 지표 엔드포인트를 /metrics 옆에 등록 (Start 전에 호출)
+//
+//	GET /metrics/robots/<serial>?window=24h[&format=json]
+//
+// 기본은 /metrics와 같은 Prometheus 텍스트 형식이며, bridgectl robots stats와 같은 값을 반환합니다.
+func (s *Server) SetRobotStats(db *gorm.DB, siteID string) {
+	s.mux.HandleFunc("/metrics/robots/", func(w http.ResponseWriter, r *http.Request) {
+		serialNumber := strings.TrimPrefix(r.URL.Path, "/metrics/robots/")
+		if serialNumber == "" || strings.Contains(serialNumber, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+
+		window := defaultRobotStatsWindow
+		if raw := r.URL.Query().Get("window"); raw != "" {
+			parsed, err := time.ParseDuration(raw)
+			if err != nil || parsed <= 0 || parsed > maxRobotStatsWindow {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(
+					apperr.Validation("window", "window must be a duration between 0 and %s (e.g. 24h), got %q", maxRobotStatsWindow, raw), ""))
+				return
+			}
+			window = parsed
+		}
+		format := r.URL.Query().Get("format")
+		if format != "" && format != "json" && format != "prometheus" {
+			writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("format", "unsupported format: %s (json, prometheus)", format), ""))
+			return
+		}
+
+		to := time.Now()
+		stats, err := repository.ComputeRobotStats(db, siteID, serialNumber, to.Add(-window), to)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, apperr.ToResponse(err, ""))
+			return
+		}
+		if format == "json" {
+			writeJSON(w, http.StatusOK, stats)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		stats.WritePrometheus(w, siteID)
+	})
+}
+
+// statsOverviewWindow 처리량/실패 비율/전송 경로를 집계하는 최근 기간
+const statsOverviewWindow = time.Hour
+
+// statsOverviewTTL 대시보드 여러 대가 폴링해도 DB를 반복 집계하지 않도록 결과를 재사용하는 기간
+const statsOverviewTTL = 5 * time.Second
+
+// StatsOverviewResponse /admin/stats/overview 응답 (DB 집계에 Redis 상태 캐시와 수집 큐를 더함)
+type StatsOverviewResponse struct {
+	*repository.StatsOverview
+	IngestQueues []messaging.QueueStats `json:"ingest_queues,omitempty"` // 프로세스 내 수집 큐 (INGEST_POOL)
+}
+
+// SetStatsOverview 현황판용 요약 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET /admin/stats/overview   로봇별 실행/대기 오더와 배터리, 대기열 깊이, 최근 1시간 처리량과 실패 비율, 전송 경로별 건수
+func (s *Server) SetStatsOverview(db *gorm.DB, redisClient *redis.Client, siteID string) {
+	var mu sync.Mutex
+	var cached *StatsOverviewResponse
+	s.mux.HandleFunc("/admin/stats/overview", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if cached == nil || time.Since(cached.GeneratedAt) >= statsOverviewTTL {
+			overview, err := repository.ComputeStatsOverview(db, siteID, statsOverviewWindow, time.Now())
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, apperr.ToResponse(err, ""))
+				return
+			}
+			if redisClient != nil {
+				fillCachedRobotState(r.Context(), redisClient, overview.Robots)
+			}
+			cached = &StatsOverviewResponse{StatsOverview: overview, IngestQueues: s.checker.QueueStats()}
+		}
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", int(statsOverviewTTL.Seconds())))
+		writeJSON(w, http.StatusOK, cached)
+	})
+}
+
+// fillCachedRobotState Redis 상태 캐시에서 로봇별 배터리 잔량과 운영 모드를 채움 (캐시가 없으면 비워 둠)
+func fillCachedRobotState(ctx context.Context, redisClient *redis.Client, robots []repository.RobotOverview) {
+	for i := range robots {
+		fields, err := robot.ReadCachedState(ctx, redisClient, robots[i].SerialNumber)
+		if err != nil {
+			utils.Logger.Warnf("Failed to read cached state for robot %s: %v", robots[i].SerialNumber, err)
+			return
+		}
+		if charge, err := strconv.ParseFloat(fields["batteryState.batteryCharge"], 64); err == nil {
+			robots[i].BatteryCharge = &charge
+		}
+		robots[i].OperatingMode = fields["operatingMode"]
+	}
+}
@@ -0,0 +1,428 @@
+// internal/health/orders.go
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mqtt-bridge/internal/artifacts"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/workflow"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 오더 상태 롱 폴링 대기 시간
+const (
+	defaultOrderWait = 30 * time.Second
+	maxOrderWait     = 5 * time.Minute
+)
+
+// OrderWaiter 오더 상태 변경 대기 인터페이스
+type OrderWaiter interface {
+	Wait(ctx context.Context, orderID, knownStatus string) (*workflow.OrderWaitResult, error)
+}
+
+// SetOrderWait 오더 상태 롱 폴링 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET /admin/orders/<orderId>/wait?timeout=30s[&status=RUNNING]
+//
+// status를 주면 그 상태와 달라질 때까지, 없으면 현재 상태에서 바뀔 때까지 기다립니다 (최대 5분).
+// 이미 다르거나 종료 상태면 바로 반환하며, 시간이 지나면 200과 timed_out=true로 현재 상태를 반환합니다.
+// 서버가 종료되면 대기 중인 요청도 바로 반환합니다.
+func (s *Server) SetOrderWait(waiter OrderWaiter) {
+	shutdown, cancelWaits := context.WithCancel(context.Background())
+	s.server.RegisterOnShutdown(cancelWaits)
+
+	s.handleOrder("wait", func(w http.ResponseWriter, r *http.Request, orderID string) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+
+		timeout := defaultOrderWait
+		if raw := r.URL.Query().Get("timeout"); raw != "" {
+			parsed, err := time.ParseDuration(raw)
+			if err != nil || parsed <= 0 || parsed > maxOrderWait {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(
+					apperr.Validation("timeout", "timeout must be a duration between 0 and %s (e.g. 30s), got %q", maxOrderWait, raw), ""))
+				return
+			}
+			timeout = parsed
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		stop := context.AfterFunc(shutdown, cancel)
+		defer stop()
+
+		result, err := waiter.Wait(ctx, orderID, strings.ToUpper(r.URL.Query().Get("status")))
+		if err != nil {
+			status := http.StatusInternalServerError
+			if apperr.CodeOf(err) == apperr.CodeNotFound {
+				status = http.StatusNotFound
+			}
+			writeJSON(w, status, apperr.ToResponse(err, ""))
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	})
+}
+
+// OrderPauser 오더 일시정지/재개 인터페이스
+type OrderPauser interface {
+	PauseOrder(orderID, reason string) (*models.OrderExecution, error)
+	ResumePausedOrder(orderID string) (*models.OrderExecution, error)
+}
+
+// SetOrderPause 오더 일시정지/재개 엔드포인트 등록 (Start 전에 호출)
+//
+//	POST /admin/orders/<orderId>/pause    {"reason": "..."} (본문 생략 가능) RUNNING 오더에 startPause 전송 후 PAUSED
+//	POST /admin/orders/<orderId>/resume   PAUSED 오더에 stopPause 전송 후 RUNNING
+//
+// 상태가 맞지 않으면 422, 로봇이 pause 기능을 지원하지 않으면 409, 전송 실패는 502를 반환합니다.
+func (s *Server) SetOrderPause(pauser OrderPauser) {
+	handle := func(apply func(r *http.Request, orderID string) (*models.OrderExecution, error)) keyRoute {
+		return func(w http.ResponseWriter, r *http.Request, orderID string) {
+			if r.Method != http.MethodPost {
+				w.Header().Set("Allow", http.MethodPost)
+				writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+				return
+			}
+			execution, err := apply(r, orderID)
+			if err != nil {
+				status := http.StatusInternalServerError
+				switch apperr.CodeOf(err) {
+				case apperr.CodeNotFound:
+					status = http.StatusNotFound
+				case apperr.CodeValidationFailed:
+					status = http.StatusUnprocessableEntity
+				case apperr.CodeUnsupportedFeature:
+					status = http.StatusConflict
+				case apperr.CodeTransportUnavailable:
+					status = http.StatusBadGateway
+				}
+				writeJSON(w, status, apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusOK, execution)
+		}
+	}
+	s.handleOrder("pause", handle(func(r *http.Request, orderID string) (*models.OrderExecution, error) {
+		var body struct {
+			Reason string `json:"reason"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				return nil, apperr.Validation("body", "invalid request body: %v", err)
+			}
+		}
+		return pauser.PauseOrder(orderID, body.Reason)
+	}))
+	s.handleOrder("resume", handle(func(r *http.Request, orderID string) (*models.OrderExecution, error) {
+		return pauser.ResumePausedOrder(orderID)
+	}))
+}
+
+// OrderCanceller 오더 하나 취소 인터페이스
+type OrderCanceller interface {
+	CancelOrder(orderID, reason string) error
+}
+
+// SetOrderCancel 오더 취소 엔드포인트 등록 (Start 전에 호출)
+//
+//	POST /admin/orders/<orderId>/cancel   {"reason": "..."} (본문 생략 가능)
+//
+// PLC OC:<orderId> 명령과 같은 경로로 오더를 CANCELLED 처리하고 로봇에 cancelOrder를 보냅니다.
+// 이미 종료된 오더는 422, 로봇이 cancelOrder를 지원하지 않으면 409, 전송 실패는 502를 반환합니다.
+func (s *Server) SetOrderCancel(canceller OrderCanceller) {
+	s.handleOrder("cancel", func(w http.ResponseWriter, r *http.Request, orderID string) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+			return
+		}
+		var body struct {
+			Reason string `json:"reason"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+				return
+			}
+		}
+		reason := strings.TrimSpace(body.Reason)
+		if reason == "" {
+			reason = "Order " + orderID + " cancelled via admin API"
+		}
+
+		if err := canceller.CancelOrder(orderID, reason); err != nil {
+			status := http.StatusInternalServerError
+			switch apperr.CodeOf(err) {
+			case apperr.CodeNotFound:
+				status = http.StatusNotFound
+			case apperr.CodeValidationFailed:
+				status = http.StatusUnprocessableEntity
+			case apperr.CodeUnsupportedFeature:
+				status = http.StatusConflict
+			case apperr.CodeTransportUnavailable:
+				status = http.StatusBadGateway
+			}
+			writeJSON(w, status, apperr.ToResponse(err, ""))
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"order_id": orderID, "reason": reason})
+	})
+}
+
+// SetOrderTimeline 오더 실행 타임라인 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET /admin/orders/<orderId>/timeline   오더 생성, 전송, 단계 시작/종료, 액션 상태 전이 (시간순)
+func (s *Server) SetOrderTimeline(db *gorm.DB, siteID string) {
+	s.handleOrder("timeline", func(w http.ResponseWriter, r *http.Request, orderID string) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+		timeline, err := repository.BuildOrderTimeline(db, siteID, orderID)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if apperr.CodeOf(err) == apperr.CodeNotFound {
+				status = http.StatusNotFound
+			}
+			writeJSON(w, status, apperr.ToResponse(err, ""))
+			return
+		}
+		writeJSON(w, http.StatusOK, timeline)
+	})
+}
+
+// SetArtifacts 오더 결과물 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET    /admin/orders/<orderId>/artifacts                 결과물 목록
+//	GET    /admin/orders/<orderId>/artifacts/<name>          본문 내려받기 (?url=true면 pre-signed URL {"url"})
+//	PUT    /admin/orders/<orderId>/artifacts/<name>?kind=    요청 본문을 결과물로 첨부 (같은 이름이면 교체, Content-Type 유지)
+//	DELETE /admin/orders/<orderId>/artifacts/<name>          결과물 삭제
+func (s *Server) SetArtifacts(service *artifacts.Service) {
+	writeError := func(w http.ResponseWriter, err error) {
+		status := http.StatusInternalServerError
+		switch apperr.CodeOf(err) {
+		case apperr.CodeNotFound:
+			status = http.StatusNotFound
+		case apperr.CodeValidationFailed:
+			status = http.StatusUnprocessableEntity
+		}
+		writeJSON(w, status, apperr.ToResponse(err, ""))
+	}
+
+	s.handleOrderTree("artifacts", func(w http.ResponseWriter, r *http.Request, orderID, name string) {
+		if name == "" {
+			if r.Method != http.MethodGet {
+				w.Header().Set("Allow", http.MethodGet)
+				writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+				return
+			}
+			list, err := service.List(orderID)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, list)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			if urlOnly, _ := strconv.ParseBool(r.URL.Query().Get("url")); urlOnly {
+				url, err := service.URL(orderID, name)
+				if err != nil {
+					writeError(w, err)
+					return
+				}
+				if url == "" {
+					writeJSON(w, http.StatusNotImplemented, map[string]string{"error": "artifact store does not support pre-signed URLs"})
+					return
+				}
+				writeJSON(w, http.StatusOK, map[string]string{"url": url})
+				return
+			}
+			artifact, body, err := service.Open(r.Context(), orderID, name)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			defer body.Close()
+			w.Header().Set("Content-Type", artifact.ContentType)
+			w.Header().Set("Content-Length", strconv.FormatInt(artifact.Size, 10))
+			w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": artifact.Name}))
+			w.Header().Set("Digest", "sha-256="+artifact.SHA256)
+			w.WriteHeader(http.StatusOK)
+			io.Copy(w, body)
+		case http.MethodPut:
+			data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, artifacts.MaxArtifactSize))
+			if err != nil {
+				writeJSON(w, http.StatusRequestEntityTooLarge, apperr.ToResponse(
+					apperr.Validation("body", "artifact body is larger than %d bytes: %v", artifacts.MaxArtifactSize, err), ""))
+				return
+			}
+			kind := strings.ToUpper(r.URL.Query().Get("kind"))
+			if kind == "" {
+				kind = constants.ArtifactKindOther
+			}
+			artifact, err := service.Attach(r.Context(), orderID, name, kind, r.Header.Get("Content-Type"), data)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, artifact)
+		case http.MethodDelete:
+			if err := service.Delete(r.Context(), orderID, name); err != nil {
+				writeError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Allow", "GET, PUT, DELETE")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET, PUT or DELETE"})
+		}
+	})
+}
+
+// SetAnnotations 오더/명령 주석 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET  /admin/orders/<orderId>/annotations        오더와 그 오더를 만든 명령의 주석 (오래된 순)
+//	POST /admin/orders/<orderId>/annotations        {"labels": ["review"], "note": "...", "author": "..."}
+//	GET  /admin/commands/<correlationId>/annotations
+//	POST /admin/commands/<correlationId>/annotations
+func (s *Server) SetAnnotations(db *gorm.DB, siteID string) {
+	handle := func(list func(*gorm.DB, string, string) ([]models.ExecutionAnnotation, error),
+		annotate func(*gorm.DB, string, string, repository.AnnotationInput) (*models.ExecutionAnnotation, error)) keyRoute {
+		return func(w http.ResponseWriter, r *http.Request, key string) {
+			switch r.Method {
+			case http.MethodGet:
+				annotations, err := list(db, siteID, key)
+				if err != nil {
+					writeJSON(w, rolloutErrorStatus(err), apperr.ToResponse(err, ""))
+					return
+				}
+				writeJSON(w, http.StatusOK, annotations)
+			case http.MethodPost:
+				var input repository.AnnotationInput
+				if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+					writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+					return
+				}
+				annotation, err := annotate(db, siteID, key, input)
+				if err != nil {
+					code := validationErrorStatus(err)
+					if apperr.CodeOf(err) == apperr.CodeNotFound {
+						code = http.StatusNotFound
+					}
+					writeJSON(w, code, apperr.ToResponse(err, ""))
+					return
+				}
+				writeJSON(w, http.StatusCreated, annotation)
+			default:
+				w.Header().Set("Allow", "GET, POST")
+				writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET or POST"})
+			}
+		}
+	}
+	s.handleOrder("annotations", handle(repository.ListOrderAnnotations, repository.AnnotateOrder))
+	s.handleCommand("annotations", handle(repository.ListCommandAnnotations, repository.AnnotateCommand))
+}
+
+// SetJobs 작업 묶음 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET  /admin/jobs?status=RUNNING&robot=<serial>&limit=50   작업 묶음과 작업 항목 (최근 순)
+//	POST /admin/jobs                                          {"name", "tasks": [{"serial_number", "command_type", "parameters"}]}
+//	GET  /admin/jobs/<jobId>                                  작업 묶음 하나와 모아진 상태
+//	POST /admin/jobs/<jobId>/cancel                           {"reason"} 작업 묶음 전체 취소
+//
+// 생성은 모든 작업 항목을 승인하거나 모두 거부하며, 거부하면 422와 작업 항목별 문제를 반환합니다.
+func (s *Server) SetJobs(db *gorm.DB, siteID string) {
+	s.mux.HandleFunc("/admin/jobs", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			query := r.URL.Query()
+			filter := repository.JobFilter{Status: query.Get("status"), Robot: query.Get("robot")}
+			filter.Limit, _ = strconv.Atoi(query.Get("limit"))
+			jobs, err := repository.ListJobs(db, siteID, filter)
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusOK, jobs)
+		case http.MethodPost:
+			var request repository.JobRequest
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+				return
+			}
+			job, err := repository.CreateJob(db, siteID, request)
+			if err != nil {
+				status := http.StatusUnprocessableEntity
+				if apperr.CodeOf(err) == apperr.CodeInternal {
+					status = http.StatusInternalServerError
+				}
+				writeJSON(w, status, apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusCreated, job)
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET or POST"})
+		}
+	})
+	s.mux.HandleFunc("/admin/jobs/", func(w http.ResponseWriter, r *http.Request) {
+		jobID, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/admin/jobs/"), "/")
+		if jobID == "" {
+			http.NotFound(w, r)
+			return
+		}
+		switch {
+		case action == "" && r.Method == http.MethodGet:
+			job, err := repository.GetJob(db, siteID, jobID)
+			if err != nil {
+				writeJSON(w, rolloutErrorStatus(err), apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusOK, job)
+		case action == "cancel" && r.Method == http.MethodPost:
+			var body struct {
+				Reason string `json:"reason"`
+			}
+			if r.ContentLength != 0 {
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+					return
+				}
+			}
+			job, err := repository.CancelJob(db, siteID, jobID, strings.TrimSpace(body.Reason))
+			if err != nil {
+				writeJSON(w, rolloutErrorStatus(err), apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusOK, job)
+		case action == "" || action == "cancel":
+			allow := http.MethodGet
+			if action == "cancel" {
+				allow = http.MethodPost
+			}
+			w.Header().Set("Allow", allow)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use " + allow})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
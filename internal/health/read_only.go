@@ -0,0 +1,77 @@
+// internal/health/read_only.go
+package health
+
+import (
+	"encoding/json"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/readonly"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// readOnlyExemptPaths 읽기 전용이어도 허용하는 변경 요청 경로
+// (상태를 바꾸지 않거나 읽기 전용 해제/장애 점검에 필요, 로봇 HTTP 수신은 MQTT 수신처럼 계속 처리)
+var readOnlyExemptPaths = []string{"/admin/read-only", "/graphql", "/admin/faults", "/api/v1/ingest"}
+
+// readOnlyExempt 읽기 전용 중에도 처리할 요청인지 (조회, 시뮬레이션, 검사, dry_run)
+func readOnlyExempt(r *http.Request) bool {
+	if !isMutation(r.Method) {
+		return true
+	}
+	for _, path := range readOnlyExemptPaths {
+		if r.URL.Path == path || strings.HasPrefix(r.URL.Path, path+"/") {
+			return true
+		}
+	}
+	// 비상 정지는 읽기 전용이어도 막지 않음 (PLC ES 명령도 읽기 전용 검사를 거치지 않음)
+	if strings.HasSuffix(r.URL.Path, "/dry-run") || strings.HasSuffix(r.URL.Path, "/lint") || strings.HasSuffix(r.URL.Path, "/estop") {
+		return true
+	}
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+	return dryRun
+}
+
+// guardReadOnly 읽기 전용이면 변경 요청을 503으로 거부하고 나머지는 mux로 전달
+func (s *Server) guardReadOnly(w http.ResponseWriter, r *http.Request) {
+	if s.readOnly.Enabled() && !readOnlyExempt(r) {
+		err := s.readOnly.Check(r.Method + " " + r.URL.Path)
+		w.Header().Set("Retry-After", "60")
+		writeJSON(w, http.StatusServiceUnavailable, apperr.ToResponse(err, ""))
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// SetReadOnly 읽기 전용 스위치 엔드포인트 등록 (Start 전에 호출)
+// 켜져 있는 동안 조회, 시뮬레이션(dry-run, lint), 장애 주입, 이 엔드포인트를 제외한 변경 요청은 503 READ_ONLY로 거부합니다.
+//
+//	GET /admin/read-only   현재 상태
+//	PUT /admin/read-only   {"enabled": true, "reason": "db failover"} 켜기/끄기
+func (s *Server) SetReadOnly(sw *readonly.Switch) {
+	s.readOnly = sw
+	s.mux.HandleFunc("/admin/read-only", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var req struct {
+				Enabled bool   `json:"enabled"`
+				Reason  string `json:"reason"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+				return
+			}
+			if req.Enabled && strings.TrimSpace(req.Reason) == "" {
+				writeJSON(w, http.StatusUnprocessableEntity, apperr.ToResponse(apperr.Validation("reason", "reason is required when enabling read-only mode"), ""))
+				return
+			}
+			sw.Set(req.Enabled, strings.TrimSpace(req.Reason))
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET or PUT"})
+			return
+		}
+		writeJSON(w, http.StatusOK, sw.Status())
+	})
+}
@@ -0,0 +1,749 @@
+// internal/health/robots.go
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/messaging"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/robot"
+	"mqtt-bridge/internal/workflow"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// InitPositioner 로봇 위치 초기화 요청/조회 인터페이스
+type InitPositioner interface {
+	InitPosition(serialNumber string, pose models.PoseValue) (*robot.InitPositionRequest, error)
+	InitPositionFromHome(serialNumber string) (*robot.InitPositionRequest, error)
+	InitPositionRequests(serialNumber string) []robot.InitPositionRequest
+}
+
+// SetInitPosition 로봇 위치 초기화 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET  /admin/robots/<serial>/init-position   최근 요청과 로봇이 보고한 진행 상태 (최신순)
+//	POST /admin/robots/<serial>/init-position   {"mapId", "x", "y", "theta", "lastNodeId"} 또는 {"useHome": true}
+//
+// POST는 요청을 보낸 뒤 바로 202를 반환하며, 결과는 이후 state 메시지의 actionStates로 갱신됩니다.
+func (s *Server) SetInitPosition(positioner InitPositioner) {
+	s.handleRobot("init-position", func(w http.ResponseWriter, r *http.Request, serialNumber, rest string) {
+		if rest != "" {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, positioner.InitPositionRequests(serialNumber))
+		case http.MethodPost:
+			var body struct {
+				models.PoseValue
+				UseHome bool `json:"useHome"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+				return
+			}
+
+			var request *robot.InitPositionRequest
+			var err error
+			if body.UseHome {
+				request, err = positioner.InitPositionFromHome(serialNumber)
+			} else {
+				request, err = positioner.InitPosition(serialNumber, body.PoseValue)
+			}
+			if err != nil {
+				status := http.StatusInternalServerError
+				switch apperr.CodeOf(err) {
+				case apperr.CodeNotFound:
+					status = http.StatusNotFound
+				case apperr.CodeValidationFailed:
+					status = http.StatusUnprocessableEntity
+				case apperr.CodeUnsupportedFeature:
+					status = http.StatusConflict
+				case apperr.CodeTransportUnavailable:
+					status = http.StatusBadGateway
+				}
+				writeJSON(w, status, apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusAccepted, request)
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET or POST"})
+		}
+	})
+}
+
+// maxInstantActionsSize 원시 instantActions 요청 본문 최대 크기
+const maxInstantActionsSize = 1 << 20
+
+// InstantActionSender 원시 instantActions 전송 인터페이스
+type InstantActionSender interface {
+	SendInstantActions(serialNumber string, payload []byte) (*robot.InstantActionsMessage, error)
+}
+
+// SetInstantActions 원시 instantActions 전송 엔드포인트 등록 (Start 전에 호출)
+//
+//	POST /admin/robots/<serial>/instant-actions   VDA 5050 instantActions 메시지 그대로
+//
+// 스키마 검사와 INSTANT_ACTION_ALLOWLIST 검사를 통과해야 전송하며(아니면 문제 목록과 함께 422),
+// headerId는 브릿지 순번으로 바꿔 보낸 뒤 202와 전송한 메시지를 반환합니다.
+func (s *Server) SetInstantActions(sender InstantActionSender) {
+	s.handleRobot("instant-actions", func(w http.ResponseWriter, r *http.Request, serialNumber, rest string) {
+		if rest != "" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+			return
+		}
+		payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxInstantActionsSize))
+		if err != nil {
+			writeJSON(w, http.StatusRequestEntityTooLarge, apperr.ToResponse(apperr.Validation("body", "request body is larger than %d bytes: %v", maxInstantActionsSize, err), ""))
+			return
+		}
+		message, err := sender.SendInstantActions(serialNumber, payload)
+		if err != nil {
+			status := http.StatusInternalServerError
+			switch apperr.CodeOf(err) {
+			case apperr.CodeValidationFailed:
+				status = http.StatusUnprocessableEntity
+			case apperr.CodeUnsupportedFeature:
+				status = http.StatusConflict
+			case apperr.CodeTransportUnavailable:
+				status = http.StatusBadGateway
+			}
+			writeJSON(w, status, apperr.ToResponse(err, ""))
+			return
+		}
+		writeJSON(w, http.StatusAccepted, message)
+	})
+}
+
+// DualArmSender 양팔 궤적 오더 전송 인터페이스
+type DualArmSender interface {
+	SendDualArmTrajectory(serialNumber string, req workflow.DualArmTrajectory) (*workflow.DualArmTrajectoryResult, error)
+}
+
+// SetDualArmTrajectory 양팔 궤적 오더 엔드포인트 등록 (Start 전에 호출)
+//
+//	POST /admin/robots/<serial>/dual-arm-trajectory   {"left": "RG", "right": "FI", "barrier": true}
+//
+// 두 궤적 이름은 로봇 팩트시트의 trajectory_name 허용 값이어야 하며(아니면 422), 오더를 보낸 뒤 202를 반환합니다.
+func (s *Server) SetDualArmTrajectory(sender DualArmSender) {
+	s.handleRobot("dual-arm-trajectory", func(w http.ResponseWriter, r *http.Request, serialNumber, rest string) {
+		if rest != "" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+			return
+		}
+		var body workflow.DualArmTrajectory
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+			return
+		}
+		result, err := sender.SendDualArmTrajectory(serialNumber, body)
+		if err != nil {
+			status := http.StatusInternalServerError
+			switch apperr.CodeOf(err) {
+			case apperr.CodeNotFound:
+				status = http.StatusNotFound
+			case apperr.CodeValidationFailed:
+				status = http.StatusUnprocessableEntity
+			case apperr.CodeUnsupportedFeature:
+				status = http.StatusConflict
+			case apperr.CodePayloadTooLarge:
+				status = http.StatusRequestEntityTooLarge
+			case apperr.CodeTransportUnavailable:
+				status = http.StatusBadGateway
+			}
+			writeJSON(w, status, apperr.ToResponse(err, ""))
+			return
+		}
+		writeJSON(w, http.StatusAccepted, result)
+	})
+}
+
+// EmergencyStopper 로봇 비상 정지 인터페이스
+type EmergencyStopper interface {
+	EmergencyStop(serialNumber, reason string) (int, error)
+}
+
+// EmergencyStopResult 비상 정지 결과
+type EmergencyStopResult struct {
+	SerialNumber  string `json:"serialNumber"`
+	StoppedOrders int    `json:"stoppedOrders"`
+	PublishError  string `json:"publishError,omitempty"`
+}
+
+// SetEmergencyStop 로봇 비상 정지 엔드포인트 등록 (Start 전에 호출)
+//
+//	POST /admin/robots/<serial>/estop   {"reason": "operator"} (본문 생략 가능)
+//
+// PLC ES 명령과 같은 경로로 안전 액션을 보내고 실행 중인 오더를 E_STOPPED로 표시합니다.
+// 안전 액션 전송에 실패해도 오더 정리는 끝까지 진행하며 이때는 502와 함께 중지한 오더 수를 반환합니다.
+// 읽기 전용 모드에서도 거부하지 않습니다.
+func (s *Server) SetEmergencyStop(stopper EmergencyStopper) {
+	s.handleRobot("estop", func(w http.ResponseWriter, r *http.Request, serialNumber, rest string) {
+		if rest != "" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+			return
+		}
+		var body struct {
+			Reason string `json:"reason"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err != io.EOF {
+			writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+			return
+		}
+		reason := strings.TrimSpace(body.Reason)
+		if reason == "" {
+			reason = "Emergency stop requested via admin API"
+		}
+
+		stopped, err := stopper.EmergencyStop(serialNumber, reason)
+		result := EmergencyStopResult{SerialNumber: serialNumber, StoppedOrders: stopped}
+		switch {
+		case err == nil:
+			writeJSON(w, http.StatusOK, result)
+		case apperr.CodeOf(err) == apperr.CodeNotFound:
+			writeJSON(w, http.StatusNotFound, apperr.ToResponse(err, ""))
+		case apperr.CodeOf(err) == apperr.CodeTransportUnavailable:
+			result.PublishError = err.Error()
+			writeJSON(w, http.StatusBadGateway, result)
+		default:
+			writeJSON(w, http.StatusInternalServerError, apperr.ToResponse(err, ""))
+		}
+	})
+}
+
+// ActionSchemaSource 로봇 팩트시트 기반 액션 파라미터 스키마 조회 인터페이스
+type ActionSchemaSource interface {
+	ActionSchemas(serialNumber string) ([]robot.ActionSchema, error)
+	ActionSchema(serialNumber, actionType string) (*robot.ActionSchema, error)
+}
+
+// SetActionSchemas 액션 파라미터 스키마 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET /admin/robots/<serial>/actions                       팩트시트의 모든 액션과 파라미터 스키마
+//	GET /admin/robots/<serial>/actions/<actionType>/schema   액션 하나의 파라미터 이름, 타입, 필수 여부, 허용 값, 사용 중인 값
+func (s *Server) SetActionSchemas(source ActionSchemaSource) {
+	s.handleRobot("actions", func(w http.ResponseWriter, r *http.Request, serialNumber, rest string) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+
+		var body interface{}
+		var err error
+		if rest == "" {
+			body, err = source.ActionSchemas(serialNumber)
+		} else {
+			actionType, ok := strings.CutSuffix(rest, "/schema")
+			if !ok || actionType == "" || strings.Contains(actionType, "/") {
+				http.NotFound(w, r)
+				return
+			}
+			body, err = source.ActionSchema(serialNumber, actionType)
+		}
+		if err != nil {
+			status := http.StatusInternalServerError
+			if apperr.CodeOf(err) == apperr.CodeNotFound {
+				status = http.StatusNotFound
+			}
+			writeJSON(w, status, apperr.ToResponse(err, ""))
+			return
+		}
+		writeJSON(w, http.StatusOK, body)
+	})
+}
+
+// SetRobotMetadata 로봇 메타데이터 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET   /admin/robots/<serial>/metadata   로봇 메타데이터 {"key": "value"}
+//	PATCH /admin/robots/<serial>/metadata   {"location": "line-2", "retired": null} 추가/갱신, null이면 삭제
+func (s *Server) SetRobotMetadata(db *gorm.DB, siteID string) {
+	s.handleRobot("metadata", func(w http.ResponseWriter, r *http.Request, serialNumber, rest string) {
+		if rest != "" {
+			http.NotFound(w, r)
+			return
+		}
+		var metadata map[string]string
+		var err error
+		switch r.Method {
+		case http.MethodGet:
+			metadata, err = repository.GetRobotMetadata(db, siteID, serialNumber)
+		case http.MethodPatch:
+			var patch map[string]*string
+			if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+				return
+			}
+			metadata, err = repository.PatchRobotMetadata(db, siteID, serialNumber, patch)
+		default:
+			w.Header().Set("Allow", "GET, PATCH")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET or PATCH"})
+			return
+		}
+		if err != nil {
+			code := http.StatusInternalServerError
+			switch apperr.CodeOf(err) {
+			case apperr.CodeNotFound:
+				code = http.StatusNotFound
+			case apperr.CodeValidationFailed:
+				code = http.StatusUnprocessableEntity
+			}
+			writeJSON(w, code, apperr.ToResponse(err, ""))
+			return
+		}
+		writeJSON(w, http.StatusOK, metadata)
+	})
+}
+
+// RobotMaintenanceStatus 로봇 유지보수 모드 상태
+type RobotMaintenanceStatus struct {
+	SerialNumber string     `json:"serialNumber"`
+	Maintenance  bool       `json:"maintenance"`
+	Reason       string     `json:"reason,omitempty"`
+	Until        *time.Time `json:"until,omitempty"` // 자동 해제 시각 (없으면 수동 해제까지 유지)
+}
+
+// SetRobotMaintenance 로봇 유지보수 모드 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET /admin/robots/<serial>/maintenance   현재 유지보수 상태 (자동 해제 시각이 지났으면 해제 후 반환)
+//	PUT /admin/robots/<serial>/maintenance   {"enabled": true, "reason": "wheel swap", "for": "2h"} 켜기/끄기
+//
+// 켤 때는 reason이 필요하며, 자동 해제는 for(기간) 또는 until(RFC3339 시각) 중 하나로 지정합니다.
+// 유지보수 중인 로봇에는 새 명령과 오더를 배차하지 않고, 상태 조회는 그대로 동작합니다.
+func (s *Server) SetRobotMaintenance(db *gorm.DB, siteID string) {
+	s.handleRobot("maintenance", func(w http.ResponseWriter, r *http.Request, serialNumber, rest string) {
+		if rest != "" {
+			http.NotFound(w, r)
+			return
+		}
+		var err error
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var body struct {
+				Enabled bool       `json:"enabled"`
+				Reason  string     `json:"reason"`
+				For     string     `json:"for"`
+				Until   *time.Time `json:"until"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+				return
+			}
+			if body.Enabled {
+				err = setRobotMaintenance(db, siteID, serialNumber, strings.TrimSpace(body.Reason), body.For, body.Until)
+			} else {
+				err = repository.ClearRobotMaintenance(db, siteID, serialNumber)
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET or PUT"})
+			return
+		}
+
+		var maintenance *repository.RobotMaintenance
+		if err == nil {
+			maintenance, err = repository.ActiveMaintenance(db, siteID, serialNumber)
+		}
+		if err != nil {
+			code := http.StatusInternalServerError
+			switch apperr.CodeOf(err) {
+			case apperr.CodeNotFound:
+				code = http.StatusNotFound
+			case apperr.CodeValidationFailed:
+				code = http.StatusUnprocessableEntity
+			}
+			writeJSON(w, code, apperr.ToResponse(err, ""))
+			return
+		}
+		status := RobotMaintenanceStatus{SerialNumber: serialNumber}
+		if maintenance != nil {
+			status.Maintenance = true
+			status.Reason = maintenance.Reason
+			status.Until = maintenance.Until
+		}
+		writeJSON(w, http.StatusOK, status)
+	})
+}
+
+// setRobotMaintenance 요청 본문을 검증하고 유지보수 모드 설정 (for와 until은 함께 쓸 수 없음)
+func setRobotMaintenance(db *gorm.DB, siteID, serialNumber, reason, duration string, until *time.Time) error {
+	if reason == "" {
+		return apperr.Validation("reason", "reason is required when enabling maintenance mode")
+	}
+	if duration != "" {
+		if until != nil {
+			return apperr.Validation("for", "use either for or until, not both")
+		}
+		d, err := time.ParseDuration(duration)
+		if err != nil || d <= 0 {
+			return apperr.Validation("for", "invalid maintenance duration %q", duration)
+		}
+		t := time.Now().Add(d)
+		until = &t
+	}
+	return repository.SetRobotMaintenance(db, siteID, serialNumber, reason, until)
+}
+
+// SetRobotDefaults 로봇 기본 위치 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET    /admin/robots/<serial>/defaults   홈 위치, 기본 맵, 허용 편차
+//	PUT    /admin/robots/<serial>/defaults   {"x", "y", "theta", "map_id", "allowed_deviation_xy", "allowed_deviation_theta"} 저장 (전체 교체)
+//	DELETE /admin/robots/<serial>/defaults   삭제 (이후 노드 위치는 0)
+func (s *Server) SetRobotDefaults(db *gorm.DB, siteID string) {
+	s.handleRobot("defaults", func(w http.ResponseWriter, r *http.Request, serialNumber, rest string) {
+		if rest != "" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			defaults, err := repository.FindRobotDefaults(db, siteID, serialNumber)
+			if err == nil && defaults == nil {
+				err = apperr.New(apperr.CodeNotFound, "no defaults for robot %s", serialNumber).WithField("serialNumber")
+			}
+			if err != nil {
+				writeJSON(w, settingsErrorStatus(err), apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusOK, defaults)
+		case http.MethodPut:
+			var defaults models.RobotDefaults
+			if err := json.NewDecoder(r.Body).Decode(&defaults); err != nil {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+				return
+			}
+			defaults.ID = 0
+			defaults.SerialNumber = serialNumber
+			if err := repository.SaveRobotDefaults(db, siteID, &defaults); err != nil {
+				err = apperr.Wrap(apperr.CodeValidationFailed, err, "failed to save robot defaults")
+				writeJSON(w, settingsErrorStatus(err), apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusOK, defaults)
+		case http.MethodDelete:
+			existing, err := repository.FindRobotDefaults(db, siteID, serialNumber)
+			if err == nil && existing == nil {
+				err = apperr.New(apperr.CodeNotFound, "no defaults for robot %s", serialNumber).WithField("serialNumber")
+			}
+			if err == nil {
+				err = repository.DeleteRobotDefaults(db, siteID, serialNumber)
+			}
+			if err != nil {
+				writeJSON(w, settingsErrorStatus(err), apperr.ToResponse(err, ""))
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Allow", "GET, PUT, DELETE")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET, PUT or DELETE"})
+		}
+	})
+}
+
+// SetPreflight 로봇 사전 점검 엔드포인트 등록 (Start 전에 호출, preflight가 nil이면 로봇별 설정만 가능)
+//
+//	GET  /admin/robots/<serial>/preflight   켜진 점검, 로봇에서 끈 점검, 마지막 점검 결과
+//	POST /admin/robots/<serial>/preflight   지금 점검 수행 (모두 통과하면 200, 실패하면 409)
+//	PUT  /admin/robots/<serial>/preflight   {"disabled": ["automatic_mode"], "reason": "..."} 로봇에서 끌 점검 교체 (빈 목록이면 모두 켬)
+func (s *Server) SetPreflight(db *gorm.DB, siteID string, preflight *workflow.Preflight) {
+	s.handleRobot("preflight", func(w http.ResponseWriter, r *http.Request, serialNumber, rest string) {
+		if rest != "" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			override, err := repository.GetPreflightOverride(db, siteID, serialNumber)
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, apperr.ToResponse(err, ""))
+				return
+			}
+			response := map[string]interface{}{"serial_number": serialNumber, "checks": []string{}, "override": override}
+			if preflight != nil {
+				response["checks"] = preflight.Checks()
+				response["last"] = preflight.Last(serialNumber)
+			}
+			writeJSON(w, http.StatusOK, response)
+		case http.MethodPost:
+			if preflight == nil {
+				writeJSON(w, http.StatusConflict, map[string]string{"error": "no preflight checks configured (PREFLIGHT_CHECKS)"})
+				return
+			}
+			report := preflight.Run(serialNumber, "")
+			code := http.StatusOK
+			if !report.Passed {
+				code = http.StatusConflict
+			}
+			writeJSON(w, code, report)
+		case http.MethodPut:
+			var request struct {
+				Disabled []string `json:"disabled"`
+				Reason   string   `json:"reason"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+				return
+			}
+			override, err := repository.SetDisabledPreflightChecks(db, siteID, serialNumber, request.Disabled, request.Reason)
+			if err != nil {
+				code := http.StatusInternalServerError
+				if apperr.CodeOf(err) == apperr.CodeValidationFailed {
+					code = http.StatusUnprocessableEntity
+				}
+				writeJSON(w, code, apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{"serial_number": serialNumber, "override": override})
+		default:
+			w.Header().Set("Allow", "GET, POST, PUT")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET, POST or PUT"})
+		}
+	})
+}
+
+// ChargingSource 로봇의 자동 충전 상태 조회 인터페이스
+type ChargingSource interface {
+	Status(serialNumber string) (*workflow.ChargingStatus, error)
+}
+
+// SetCharging 자동 충전 상태 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET /admin/robots/<serial>/charging   적용 중인 정책, 마지막 배터리 잔량, 충전 배차 상태
+func (s *Server) SetCharging(source ChargingSource) {
+	s.handleRobot("charging", func(w http.ResponseWriter, r *http.Request, serialNumber, rest string) {
+		if rest != "" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+		status, err := source.Status(serialNumber)
+		if err != nil {
+			code := http.StatusInternalServerError
+			if apperr.CodeOf(err) == apperr.CodeNotFound {
+				code = http.StatusNotFound
+			}
+			writeJSON(w, code, apperr.ToResponse(err, ""))
+			return
+		}
+		writeJSON(w, http.StatusOK, status)
+	})
+}
+
+// RobotLatencyHealth 로봇 state 메시지 지연 통계와 기준 초과 여부
+type RobotLatencyHealth struct {
+	messaging.RobotLatency
+	Status   string   `json:"status"`             // ok, degraded (기준 초과), insufficient_samples
+	Warnings []string `json:"warnings,omitempty"` // 초과한 기준
+}
+
+// SetRobotLatency 로봇별 state 메시지 지연 엔드포인트 등록 (Start 전에 호출)
+// stateLatency, clockDrift는 알림 규칙과 같은 기준이며 0이면 해당 항목을 판정하지 않습니다.
+//
+//	GET /admin/robots/<serial>/health   최근 state 메시지의 지연 통계, 추정 시계 오차/네트워크 지연, 상태
+func (s *Server) SetRobotLatency(tracker *messaging.LatencyTracker, stateLatency, clockDrift time.Duration) {
+	s.latency = tracker
+	s.handleRobot("health", func(w http.ResponseWriter, r *http.Request, serialNumber, rest string) {
+		if rest != "" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+		latency, ok := tracker.Latency(serialNumber)
+		if !ok {
+			writeJSON(w, http.StatusNotFound, apperr.ToResponse(apperr.New(apperr.CodeNotFound, "no state messages received from robot %s", serialNumber), ""))
+			return
+		}
+		writeJSON(w, http.StatusOK, evaluateRobotHealth(latency, stateLatency, clockDrift))
+	})
+}
+
+// SetRobotTokens 로봇 HTTP 콜백 세션 토큰 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET    /admin/robots/<serial>/tokens[?all=true]   토큰 목록 (기본은 활성 토큰만, 원문은 포함하지 않음)
+//	POST   /admin/robots/<serial>/tokens              {"label", "ttl"} 토큰 발급 (201, 원문은 이 응답에서만 반환)
+//	POST   /admin/robots/<serial>/tokens/rotate       {"label", "ttl"} 새 토큰 발급, 기존 토큰은 grace 뒤 만료
+//	DELETE /admin/robots/<serial>/tokens/<tokenId>    토큰 하나 폐기
+//	DELETE /admin/robots/<serial>/tokens              로봇의 모든 토큰 폐기
+//
+// ttl은 "24h" 같은 기간이며 비어 있으면 ttl(ROBOT_TOKEN_TTL_HOURS)을 씁니다.
+func (s *Server) SetRobotTokens(db *gorm.DB, siteID string, ttl, grace time.Duration) {
+	s.handleRobot("tokens", func(w http.ResponseWriter, r *http.Request, serialNumber, rest string) {
+		switch {
+		case rest == "" && r.Method == http.MethodGet:
+			includeInactive, _ := strconv.ParseBool(r.URL.Query().Get("all"))
+			tokens, err := repository.ListRobotTokens(db, siteID, serialNumber, includeInactive)
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{"serial_number": serialNumber, "tokens": tokens})
+		case (rest == "" || rest == "rotate") && r.Method == http.MethodPost:
+			var request struct {
+				Label string `json:"label"`
+				TTL   string `json:"ttl"`
+			}
+			if r.ContentLength != 0 {
+				if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+					writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+					return
+				}
+			}
+			tokenTTL := ttl
+			if request.TTL != "" {
+				parsed, err := time.ParseDuration(request.TTL)
+				if err != nil || parsed < 0 {
+					writeJSON(w, http.StatusUnprocessableEntity, apperr.ToResponse(apperr.Validation("ttl", "invalid ttl %q", request.TTL), ""))
+					return
+				}
+				tokenTTL = parsed
+			}
+			var issued *repository.IssuedRobotToken
+			var err error
+			if rest == "rotate" {
+				issued, err = repository.RotateRobotToken(db, siteID, serialNumber, tokenTTL, grace, request.Label)
+			} else {
+				issued, err = repository.IssueRobotToken(db, siteID, serialNumber, tokenTTL, request.Label)
+			}
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusCreated, issued)
+		case rest == "" && r.Method == http.MethodDelete:
+			revoked, err := repository.RevokeRobotTokens(db, siteID, serialNumber)
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{"serial_number": serialNumber, "revoked": revoked})
+		case rest != "" && rest != "rotate" && r.Method == http.MethodDelete:
+			token, err := repository.RevokeRobotToken(db, siteID, serialNumber, rest)
+			if err != nil {
+				writeJSON(w, rolloutErrorStatus(err), apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusOK, token)
+		default:
+			allow := http.MethodDelete
+			switch rest {
+			case "":
+				allow = "GET, POST, DELETE"
+			case "rotate":
+				allow = http.MethodPost
+			}
+			w.Header().Set("Allow", allow)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use " + allow})
+		}
+	})
+}
+
+// evaluateRobotHealth 지연 통계를 기준과 비교 (표본이 최소 개수보다 적으면 판정하지 않음)
+func evaluateRobotHealth(latency messaging.RobotLatency, stateLatency, clockDrift time.Duration) RobotLatencyHealth {
+	health := RobotLatencyHealth{RobotLatency: latency, Status: "ok"}
+	if latency.Samples < messaging.MinLatencySamples {
+		health.Status = "insufficient_samples"
+		return health
+	}
+	if limit := float64(stateLatency) / float64(time.Millisecond); stateLatency > 0 && latency.NetworkP95Ms > limit {
+		health.Warnings = append(health.Warnings, fmt.Sprintf("network latency p95 %.0fms exceeds %s", latency.NetworkP95Ms, stateLatency))
+	}
+	if limit := float64(clockDrift) / float64(time.Millisecond); clockDrift > 0 && math.Abs(latency.ClockOffsetMs) > limit {
+		health.Warnings = append(health.Warnings, fmt.Sprintf("clock offset %.0fms exceeds %s", latency.ClockOffsetMs, clockDrift))
+	}
+	if len(health.Warnings) > 0 {
+		health.Status = "degraded"
+	}
+	return health
+}
+
+// SetRobotDiscovery 로봇 탐색 등록/승인 엔드포인트 등록 (Start 전에 호출)
+// 등록 목록은 사이트 DB 기준이므로 이 브릿지에서 탐색이 꺼져 있어도(enabled == false) 관리할 수 있습니다.
+//
+//	GET  /admin/discovery?status=PENDING     등록된 로봇 목록 (status가 없으면 전체)
+//	POST /admin/discovery/<serial>/approve   {"note"} 승인하여 오더 배차 허용
+//	POST /admin/discovery/<serial>/reject    {"note"} 거부
+func (s *Server) SetRobotDiscovery(db *gorm.DB, siteID string, enabled bool) {
+	s.mux.HandleFunc("/admin/discovery", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+		registrations, err := repository.ListRobotRegistrations(db, siteID, r.URL.Query().Get("status"))
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, apperr.ToResponse(err, ""))
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"enabled": enabled, "robots": registrations})
+	})
+	s.mux.HandleFunc("/admin/discovery/", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/admin/discovery/"), "/")
+		if len(parts) != 2 || parts[0] == "" {
+			http.NotFound(w, r)
+			return
+		}
+		decide := map[string]func(*gorm.DB, string, string, string) (*models.RobotRegistration, error){
+			"approve": repository.ApproveRobot,
+			"reject":  repository.RejectRobot,
+		}[parts[1]]
+		if decide == nil {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+			return
+		}
+		var body struct {
+			Note string `json:"note"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+				return
+			}
+		}
+		registration, err := decide(db, siteID, parts[0], body.Note)
+		if err != nil {
+			writeJSON(w, rolloutErrorStatus(err), apperr.ToResponse(err, ""))
+			return
+		}
+		writeJSON(w, http.StatusOK, registration)
+	})
+}
@@ -0,0 +1,369 @@
+// internal/health/server.go
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/readonly"
+	"mqtt-bridge/internal/messaging"
+	"mqtt-bridge/internal/telemetry"
+	"mqtt-bridge/internal/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Server Kubernetes 프로브용 헬스 체크 HTTP 서버
+// 변경 요청(POST, PUT, PATCH, DELETE)에 Idempotency-Key 헤더가 있으면 재시도해도 한 번만 처리합니다.
+// /healthz: 프로세스 생존 여부 (항상 200)
+// /readyz: 중요 의존성(MQTT, Postgres, Redis) 중 하나라도 DOWN이면 503
+// /metrics: 수집 큐, MQTT 발행 버퍼, 페이로드 검증, Redis 키 정리 지표 (Prometheus 텍스트 형식)
+// /metrics/robots/<serial>: 로봇별 오더 성공률, 평균 수행 시간, MTBF, 가동률, 단계 실패 (?window=&format=json, SetRobotStats로 등록)
+// /admin/selftest: 브로커 루프백, DB 스키마, Redis 지연, 전송 경로 자체 점검 (실패하면 503, SetSelfTest로 등록)
+// /admin/schema: 페이로드 스키마 검증 모드와 종류별 위반 지표 (JSON)
+// /admin/freshness: 수신 메시지 timestamp 오차와 버린 오래된 state 메시지 지표 (JSON)
+// /admin/diagnostics/clock: 로봇, PLC, DB와 브릿지 사이 시계 오차 추정과 기준 초과 장치 (SetClockAudit로 등록)
+// /admin/retention: 실행 이력 보존 정책과 최근 정리 실행 (POST /admin/retention/purge로 실행)
+// /graphql: 대시보드용 GraphQL 조회 (SetGraphQL로 등록)
+// /admin/transports/<name>/debug: 전송 경로의 요청/응답 디버그 기록 (TRANSPORT_DEBUG로 켠 경로만)
+// /admin/commands/<type>/dry-run: 명령의 오더 체인 시뮬레이션 (POST, SetCommandDryRun으로 등록)
+// /admin/templates/rollouts: 템플릿 카나리 롤아웃 시작/조회/승격/롤백 (SetTemplateRollouts로 등록)
+// /admin/templates[?include_deleted=true], /admin/templates/<id>/restore, /purge: 템플릿 목록과 보관된 템플릿 복구/영구 삭제 (POST, SetTemplateArchive로 등록)
+// /admin/templates/<id>/clone: 템플릿을 새 이름으로 깊은 복사 (POST, draft면 DRAFT 상태, SetTemplateClone으로 등록)
+// /admin/templates/<id>/status, /limit, /deadline, /notifications: 템플릿 상태, 동시 실행 제한, 실행 제한 시간, 종료 알림 설정 (PUT, SetTemplateSettings로 등록)
+// /admin/templates/import: 내보내기 형식의 템플릿을 한 트랜잭션으로 가져오기 (POST, SetTemplateImport로 등록)
+// /admin/templates/<id>/estimate: 템플릿 오더 예상 소요 시간 (?robot=, SetTemplateEstimates로 등록)
+// /admin/templates/<id>/lint: 템플릿 모범 사례 규칙 검사 (POST, SetTemplateLint로 등록)
+// /admin/templates/<id>/shadow, shadow-compare/<otherId>: 템플릿 섀도 실행과 메시지 비교 (SetTemplateShadows로 등록)
+// /admin/access: CORS 출처와 변경 요청 IP 허용 목록 (SetAccessControl로 등록, 정책 파일은 바뀌면 다시 읽음)
+// /admin/subscriptions: MQTT 구독별 수신 지표와 일시 중지/재개(POST /pause, /resume) (SetSubscriptions로 등록)
+// /admin/faults[/<target>]: 전송 경로/DB/Redis 장애 주입 규칙 (FAULT_INJECTION=true일 때 SetFaults로 등록)
+// /admin/plc/status-map: PLC 응답 상태 매핑 조회/교체 (PUT, SetPLCStatusMap으로 등록)
+// /admin/alerts: 알림 이력, 억제 시간대(/suppressions), 시험 전송(POST /test) (SetAlerts로 등록)
+// /admin/robots/<serial>/init-position: initPosition 전송(POST)과 진행 상태 조회 (SetInitPosition으로 등록)
+// /admin/robots/<serial>/estop: 안전 액션 전송 후 실행 중인 오더를 E_STOPPED 처리 (POST, 읽기 전용에서도 허용, SetEmergencyStop으로 등록)
+// /admin/robots/<serial>/instant-actions: 스키마와 허용 목록 검사를 통과한 원시 instantActions 전송 (POST, SetInstantActions로 등록)
+// /admin/robots/<serial>/dual-arm-trajectory: 양팔 궤적 오더 전송 (POST, SetDualArmTrajectory로 등록)
+// /admin/robots/<serial>/actions[/<type>/schema]: 팩트시트 기반 액션 파라미터 스키마 (SetActionSchemas로 등록)
+// /admin/robots/<serial>/metadata: 로봇 메타데이터 조회와 merge patch 수정 (PATCH, SetRobotMetadata로 등록)
+// /admin/robots/<serial>/maintenance: 유지보수 모드 조회와 켜기/끄기 (PUT, 사유와 자동 해제 시각, SetRobotMaintenance로 등록)
+// /admin/robots/<serial>/defaults: 로봇 기본 위치 조회, 저장(PUT), 삭제 (SetRobotDefaults로 등록)
+// /admin/robots/<serial>/charging: 적용 중인 충전 정책과 자동 충전 상태 (SetCharging으로 등록)
+// /admin/robots/<serial>/health: state 메시지 지연(시계 오차, 네트워크 지연) 최근 통계와 기준 초과 여부 (SetRobotLatency로 등록)
+// /admin/robots/<serial>/preflight: 오더 전송 전 사전 점검 결과 조회, 즉시 점검(POST), 로봇별 점검 끄기(PUT) (SetPreflight로 등록)
+// /admin/robots/<serial>/tokens[/rotate|/<tokenId>]: 로봇 HTTP 콜백 세션 토큰 발급, 교체, 폐기 (SetRobotTokens로 등록)
+// /admin/discovery[/<serial>/approve|reject]: 탐색으로 등록된 로봇과 승인 대기 목록, 승인/거부 (SetRobotDiscovery로 등록)
+// /admin/stats/overview: 로봇별 실행 현황, 대기열 깊이, 최근 1시간 처리량/실패 비율/전송 경로 (SetStatsOverview로 등록)
+// /admin/orders/<orderId>/annotations, /admin/commands/<cid>/annotations: 운영자 라벨/메모 조회와 추가 (SetAnnotations로 등록)
+// /admin/direct-actions[/<suffix>]: PLC 직접 액션(<명령>:<접미사>) 레지스트리 조회, 저장(PUT), 삭제 (SetDirectActions로 등록)
+// /admin/commands/history[/<id>]: PLC 명령과 실행, 오더 체인, PLC 응답 기록 (SetCommandHistory로 등록)
+// /admin/jobs[/<jobId>[/cancel]]: 여러 로봇/명령에 걸친 작업 묶음 생성, 조회, 묶음 단위 취소 (SetJobs로 등록)
+// /api/v1/ingest/state, /connection: 로봇이 HTTP로 보낸 VDA 5050 메시지를 MQTT와 같은 경로로 처리 (로봇 토큰 인증, SetHTTPIngest로 등록)
+// /admin/sync/snapshot, /diff, /apply: 다른 브릿지와 템플릿/PLC 매핑 비교와 고른 변경 적용 (?dry_run=, SetTemplateSync로 등록)
+// /admin/orders/<orderId>/timeline: 오더 생성, 전송, 단계, 액션 상태 전이 타임라인 (SetOrderTimeline으로 등록)
+// /admin/orders/<orderId>/artifacts[/<name>]: 오더 결과물 목록, 내려받기, 첨부(PUT), 삭제 (SetArtifacts로 등록)
+// /admin/orders/<orderId>/wait: 오더 상태가 바뀔 때까지 롱 폴링 (?timeout=30s, SetOrderWait로 등록)
+// /admin/orders/<orderId>/cancel: 오더 하나 취소 (POST, PLC OC:<orderId>와 같은 경로, SetOrderCancel로 등록)
+// /admin/orders/<orderId>/pause, /resume: startPause/stopPause로 오더 일시정지와 재개 (POST, SetOrderPause로 등록)
+// /admin/maps[/<mapId>]: 맵 경계와 지오펜스 구역 조회, 저장(PUT), 삭제 (SetMaps로 등록)
+// /admin/windows[/<name>[/override]]: 오더 실행 제한 시간대 조회, 저장(PUT), 삭제와 관리자 일시 해제 (SetExecutionWindows로 등록)
+// /admin/charging/policies[/<name>]: 자동 충전 정책 조회, 저장(PUT), 삭제 (SetChargingPolicies로 등록)
+// /admin/zones[/<zone>]: 구역 점유 예약, 대기 로봇, 교착 순환 조회와 예약 강제 해제 (DELETE, SetZones로 등록)
+// /admin/import: 로봇/노드 템플릿/액션 템플릿 CSV 일괄 등록 (POST ?kind=&dry_run=, SetImport로 등록)
+// /admin/read-only: 읽기 전용 상태 조회와 켜기/끄기 (PUT, 켜져 있으면 변경 요청을 503 READ_ONLY로 거부, SetReadOnly로 등록)
+// /admin/logs[/stream]: 메모리에 보관한 최근 로그와 실시간 로그 스트림(SSE) (?level=&module=, SetLogStream으로 등록)
+type Server struct {
+	checker        *Checker
+	server         *http.Server
+	mux            *http.ServeMux
+	handler        http.Handler              // mux에 Idempotency-Key 처리를 더한 핸들러
+	robotRoutes    map[string]robotRoute     // /admin/robots/<serial>/<route> 하위 경로
+	templateRoutes map[string]templateRoute  // /admin/templates/<id>/<route> 하위 경로
+	orderRoutes    map[string]keyTreeRoute   // /admin/orders/<orderId>/<route> 하위 경로
+	commandRoutes  map[string]keyTreeRoute   // /admin/commands/<type|correlationId>/<route> 하위 경로
+	access         *AccessControl            // CORS와 변경 요청 IP 제한 (없으면 제한 없음)
+	readOnly       *readonly.Switch          // 켜져 있으면 변경 요청 거부 (SetReadOnly, 없으면 제한 없음)
+	latency        *messaging.LatencyTracker // 로봇별 state 메시지 지연 (SetRobotLatency, 없으면 지표 생략)
+}
+
+// robotRoute /admin/robots/<serial>/<route>[/<rest>] 처리 함수
+type robotRoute func(w http.ResponseWriter, r *http.Request, serialNumber, rest string)
+
+// templateRoute /admin/templates/<id>/<route>[/<rest>] 처리 함수
+type templateRoute func(w http.ResponseWriter, r *http.Request, templateID uint, rest string)
+
+// keyRoute /admin/orders/<orderId>/<route>, /admin/commands/<key>/<route> 처리 함수
+type keyRoute func(w http.ResponseWriter, r *http.Request, key string)
+
+// keyTreeRoute /admin/orders/<orderId>/<route>[/<rest>] 처리 함수 (하위 경로가 있는 라우트)
+type keyTreeRoute func(w http.ResponseWriter, r *http.Request, key, rest string)
+
+// NewServer 새 헬스 체크 서버 생성
+func NewServer(addr string, checker *Checker) *Server {
+	mux := http.NewServeMux()
+	s := &Server{checker: checker, mux: mux}
+	s.handler = newIdempotencyCache().Wrap(http.HandlerFunc(s.guardReadOnly))
+
+	mux.HandleFunc("/healthz", s.handleLiveness)
+	mux.HandleFunc("/readyz", s.handleReadiness)
+	mux.HandleFunc("/metrics", s.handleMetrics)
+	mux.HandleFunc("/admin/schema", s.handleSchema)
+	mux.HandleFunc("/admin/freshness", s.handleFreshness)
+
+	s.server = &http.Server{
+		Addr:              addr,
+		Handler:           s.handler,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return s
+}
+
+// SetAccessControl 모든 요청에 CORS와 변경 요청 IP 제한을 적용하고 현재 정책 조회 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET /admin/access   적용 중인 정책 (정책 파일이 바뀌면 다시 읽은 값)
+func (s *Server) SetAccessControl(access *AccessControl) {
+	s.access = access
+	s.server.Handler = access.Wrap(s.handler)
+	s.mux.HandleFunc("/admin/access", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+		writeJSON(w, http.StatusOK, access.Policy())
+	})
+}
+
+// SetGraphQL 대시보드 GraphQL 엔드포인트 등록 (Start 전에 호출)
+func (s *Server) SetGraphQL(handler http.Handler) {
+	s.mux.Handle("/graphql", handler)
+}
+
+// Start 백그라운드에서 서버 시작
+func (s *Server) Start() {
+	if s.access != nil {
+		s.access.Start()
+	}
+	go func() {
+		utils.Logger.Infof("🩺 Health server listening on %s", s.server.Addr)
+		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			utils.Logger.Errorf("❌ Health server failed: %v", err)
+		}
+	}()
+}
+
+// Stop 서버 종료
+func (s *Server) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		utils.Logger.Errorf("Failed to stop health server: %v", err)
+	}
+	if s.access != nil {
+		s.access.Stop()
+	}
+}
+
+// handleLiveness 생존 프로브
+func (s *Server) handleLiveness(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": StatusUp})
+}
+
+// handleReadiness 준비 프로브 (의존성 상태 보고서 포함)
+func (s *Server) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.StartSpan(r.Context(), "GET /readyz")
+	defer span.End()
+
+	report := s.checker.Check(ctx)
+	span.SetAttributes(attribute.String("health.status", report.Status))
+
+	code := http.StatusOK
+	if !report.Ready() {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, report)
+}
+
+// handleRobot /admin/robots/<serial>/<route> 하위 경로 등록 (처음 등록할 때 /admin/robots/를 mux에 등록)
+func (s *Server) handleRobot(route string, handler robotRoute) {
+	if s.robotRoutes == nil {
+		s.robotRoutes = make(map[string]robotRoute)
+		s.mux.HandleFunc("/admin/robots/", s.dispatchRobot)
+	}
+	s.robotRoutes[route] = handler
+}
+
+// handleOrder /admin/orders/<orderId>/<route> 하위 경로 등록 (하위 경로가 더 있으면 404)
+func (s *Server) handleOrder(route string, handler keyRoute) {
+	s.handleOrderTree(route, exactKeyRoute(handler))
+}
+
+// handleOrderTree /admin/orders/<orderId>/<route>[/<rest>] 하위 경로 등록 (처음 등록할 때 /admin/orders/를 mux에 등록)
+func (s *Server) handleOrderTree(route string, handler keyTreeRoute) {
+	if s.orderRoutes == nil {
+		s.orderRoutes = make(map[string]keyTreeRoute)
+		s.mux.HandleFunc("/admin/orders/", func(w http.ResponseWriter, r *http.Request) {
+			dispatchKeyRoute(w, r, "/admin/orders/", s.orderRoutes)
+		})
+	}
+	s.orderRoutes[route] = handler
+}
+
+// handleCommand /admin/commands/<key>/<route> 하위 경로 등록 (처음 등록할 때 /admin/commands/를 mux에 등록)
+func (s *Server) handleCommand(route string, handler keyRoute) {
+	if s.commandRoutes == nil {
+		s.commandRoutes = make(map[string]keyTreeRoute)
+		s.mux.HandleFunc("/admin/commands/", func(w http.ResponseWriter, r *http.Request) {
+			dispatchKeyRoute(w, r, "/admin/commands/", s.commandRoutes)
+		})
+	}
+	s.commandRoutes[route] = exactKeyRoute(handler)
+}
+
+// exactKeyRoute 하위 경로가 더 있으면 404를 반환하는 keyTreeRoute로 변환
+func exactKeyRoute(handler keyRoute) keyTreeRoute {
+	return func(w http.ResponseWriter, r *http.Request, key, rest string) {
+		if rest != "" {
+			http.NotFound(w, r)
+			return
+		}
+		handler(w, r, key)
+	}
+}
+
+// dispatchKeyRoute <prefix><key>/<route>[/<rest>]를 등록된 하위 경로로 전달
+func dispatchKeyRoute(w http.ResponseWriter, r *http.Request, prefix string, routes map[string]keyTreeRoute) {
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, prefix), "/", 3)
+	if len(parts) < 2 || parts[0] == "" {
+		http.NotFound(w, r)
+		return
+	}
+	handler, ok := routes[parts[1]]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	rest := ""
+	if len(parts) == 3 {
+		rest = parts[2]
+	}
+	handler(w, r, parts[0], rest)
+}
+
+// handleTemplate /admin/templates/<id>/<route> 하위 경로 등록 (처음 등록할 때 /admin/templates/를 mux에 등록)
+// /admin/templates/rollouts 경로는 mux에 따로 등록되어 있어 더 구체적인 패턴으로 우선합니다.
+func (s *Server) handleTemplate(route string, handler templateRoute) {
+	if s.templateRoutes == nil {
+		s.templateRoutes = make(map[string]templateRoute)
+		s.mux.HandleFunc("/admin/templates/", s.dispatchTemplate)
+	}
+	s.templateRoutes[route] = handler
+}
+
+// dispatchTemplate /admin/templates/<id>/<route>[/<rest>]를 등록된 하위 경로로 전달
+func (s *Server) dispatchTemplate(w http.ResponseWriter, r *http.Request) {
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/admin/templates/"), "/", 3)
+	if len(parts) < 2 {
+		http.NotFound(w, r)
+		return
+	}
+	id, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	handler, ok := s.templateRoutes[parts[1]]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	rest := ""
+	if len(parts) == 3 {
+		rest = parts[2]
+	}
+	handler(w, r, uint(id), rest)
+}
+
+// dispatchRobot /admin/robots/<serial>/<route>[/<rest>]를 등록된 하위 경로로 전달
+func (s *Server) dispatchRobot(w http.ResponseWriter, r *http.Request) {
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/admin/robots/"), "/", 3)
+	if len(parts) < 2 || parts[0] == "" {
+		http.NotFound(w, r)
+		return
+	}
+	handler, ok := s.robotRoutes[parts[1]]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	rest := ""
+	if len(parts) == 3 {
+		rest = parts[2]
+	}
+	handler(w, r, parts[0], rest)
+}
+
+// handleSchema 페이로드 스키마 검증 지표 (검증기가 없으면 404)
+func (s *Server) handleSchema(w http.ResponseWriter, r *http.Request) {
+	report := s.checker.SchemaReport()
+	if report == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "payload schema validation disabled"})
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// handleFreshness 수신 메시지 타임스탬프 검사 지표 (검사기가 없으면 404)
+func (s *Server) handleFreshness(w http.ResponseWriter, r *http.Request) {
+	report := s.checker.FreshnessReport()
+	if report == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "message timestamp checks disabled"})
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// rolloutErrorStatus 롤아웃 오류의 HTTP 상태 코드
+func rolloutErrorStatus(err error) int {
+	switch apperr.CodeOf(err) {
+	case apperr.CodeNotFound, apperr.CodeTemplateNotFound:
+		return http.StatusNotFound
+	case apperr.CodeValidationFailed:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// validationErrorStatus 검증 실패는 422, 그 외는 500
+func validationErrorStatus(err error) int {
+	if apperr.CodeOf(err) == apperr.CodeValidationFailed {
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
+
+// settingsErrorStatus 대상(로봇, 템플릿)이 없으면 404, 검증 실패와 없는 명령을 가리키는 설정은 422, 그 외는 500
+func settingsErrorStatus(err error) int {
+	switch apperr.CodeOf(err) {
+	case apperr.CodeNotFound, apperr.CodeTemplateNotFound:
+		return http.StatusNotFound
+	case apperr.CodeValidationFailed, apperr.CodeCommandNotFound:
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeJSON JSON 응답 작성
+func writeJSON(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		utils.Logger.Errorf("Failed to write health response: %v", err)
+	}
+}
@@ -0,0 +1,583 @@
+// internal/health/templates.go
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SetTemplateRollouts 템플릿 카나리 롤아웃 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET  /admin/templates/rollouts                 롤아웃 목록
+//	POST /admin/templates/rollouts                 {"base_template_id", "candidate_template_id", "canary_robots": [...]}로 시작
+//	GET  /admin/templates/rollouts/<id>            기존/후보 버전 성공률 비교
+//	POST /admin/templates/rollouts/<id>/promote    후보 버전 승격 (?force=true면 성공률 조건 무시)
+//	POST /admin/templates/rollouts/<id>/rollback   카나리 중단
+func (s *Server) SetTemplateRollouts(db *gorm.DB, siteID string) {
+	s.mux.HandleFunc("/admin/templates/rollouts", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			rollouts, err := repository.ListTemplateRollouts(db, siteID)
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusOK, rollouts)
+		case http.MethodPost:
+			var body struct {
+				BaseTemplateID      uint     `json:"base_template_id"`
+				CandidateTemplateID uint     `json:"candidate_template_id"`
+				CanaryRobots        []string `json:"canary_robots"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+				return
+			}
+			rollout, err := repository.StartTemplateCanary(db, siteID, body.BaseTemplateID, body.CandidateTemplateID, body.CanaryRobots)
+			if err != nil {
+				writeJSON(w, rolloutErrorStatus(err), apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusCreated, rollout)
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET or POST"})
+		}
+	})
+	s.mux.HandleFunc("/admin/templates/rollouts/", func(w http.ResponseWriter, r *http.Request) {
+		idPart, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/admin/templates/rollouts/"), "/")
+		id, err := strconv.ParseUint(idPart, 10, 64)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		rolloutID := uint(id)
+
+		var report *repository.TemplateRolloutReport
+		switch action {
+		case "":
+			if r.Method != http.MethodGet {
+				w.Header().Set("Allow", http.MethodGet)
+				writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+				return
+			}
+			var rollout *models.TemplateRollout
+			if rollout, err = repository.FindTemplateRollout(db, siteID, rolloutID); err == nil {
+				report, err = repository.ComputeTemplateRolloutReport(db, siteID, rollout)
+			}
+		case "promote", "rollback":
+			if r.Method != http.MethodPost {
+				w.Header().Set("Allow", http.MethodPost)
+				writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+				return
+			}
+			if action == "promote" {
+				force, _ := strconv.ParseBool(r.URL.Query().Get("force"))
+				report, err = repository.PromoteTemplateRollout(db, siteID, rolloutID, force)
+			} else {
+				report, err = repository.RollbackTemplateRollout(db, siteID, rolloutID)
+			}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			writeJSON(w, rolloutErrorStatus(err), map[string]interface{}{"error": apperr.ToResponse(err, ""), "report": report})
+			return
+		}
+		writeJSON(w, http.StatusOK, report)
+	})
+}
+
+// SetTemplateArchive 오더 템플릿 목록과 보관된 템플릿 복구/영구 삭제 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET  /admin/templates[?include_deleted=true]   사이트의 오더 템플릿 목록 (보관된 템플릿 포함 여부)
+//	POST /admin/templates/<id>/restore              보관된 템플릿과 보관 시 함께 삭제된 스텝, 엣지 복구
+//	POST /admin/templates/<id>/purge                보관된 템플릿을 스텝, 엣지, 명령 매핑까지 영구 삭제
+//
+// 보관되지 않은 템플릿을 복구하거나 영구 삭제하면 409를 반환합니다.
+func (s *Server) SetTemplateArchive(db *gorm.DB, siteID string) {
+	s.mux.HandleFunc("/admin/templates", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+		includeDeleted, _ := strconv.ParseBool(r.URL.Query().Get("include_deleted"))
+		templates, err := repository.ListOrderTemplates(db, siteID, includeDeleted)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, apperr.ToResponse(err, ""))
+			return
+		}
+		writeJSON(w, http.StatusOK, templates)
+	})
+
+	handle := func(apply func(*gorm.DB, string, uint) error) templateRoute {
+		return func(w http.ResponseWriter, r *http.Request, templateID uint, rest string) {
+			if rest != "" {
+				http.NotFound(w, r)
+				return
+			}
+			if r.Method != http.MethodPost {
+				w.Header().Set("Allow", http.MethodPost)
+				writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+				return
+			}
+			if err := apply(db, siteID, templateID); err != nil {
+				writeJSON(w, rolloutErrorStatus(err), apperr.ToResponse(err, ""))
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+	s.handleTemplate("restore", handle(repository.RestoreOrderTemplate))
+	s.handleTemplate("purge", handle(repository.PurgeOrderTemplate))
+}
+
+// SetTemplateClone 템플릿 복제 엔드포인트 등록 (Start 전에 호출)
+//
+//	POST /admin/templates/<id>/clone   {"name": "dock-and-charge-v2", "draft": true}
+//
+// 단계, 노드, 액션, 파라미터, 엣지까지 새 이름으로 복제하고 201과 복제본을 반환합니다.
+// draft가 true면 카나리 롤아웃의 새 버전으로 쓸 수 있도록 DRAFT 상태로 만듭니다.
+func (s *Server) SetTemplateClone(db *gorm.DB, siteID string) {
+	s.handleTemplate("clone", func(w http.ResponseWriter, r *http.Request, templateID uint, rest string) {
+		if rest != "" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+			return
+		}
+		var body struct {
+			Name  string `json:"name"`
+			Draft bool   `json:"draft"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+			return
+		}
+
+		clone, err := repository.CloneOrderTemplate(db, siteID, templateID, body.Name)
+		if err == nil && body.Draft {
+			if err = repository.SetOrderTemplateStatus(db, siteID, clone.ID, constants.TemplateStatusDraft); err == nil {
+				clone.Status = constants.TemplateStatusDraft
+			}
+		}
+		if err != nil {
+			status := http.StatusInternalServerError
+			switch apperr.CodeOf(err) {
+			case apperr.CodeTemplateNotFound:
+				status = http.StatusNotFound
+			case apperr.CodeValidationFailed:
+				status = http.StatusUnprocessableEntity
+			}
+			writeJSON(w, status, apperr.ToResponse(err, ""))
+			return
+		}
+		writeJSON(w, http.StatusCreated, clone)
+	})
+}
+
+// SetTemplateSettings 템플릿 운영 설정 엔드포인트 등록 (Start 전에 호출)
+//
+//	PUT /admin/templates/<id>/status          {"status": "DRAFT|ACTIVE|DEPRECATED"} (카나리 롤아웃 중인 템플릿은 422)
+//	PUT /admin/templates/<id>/limit           {"max_concurrent_executions": 2} 사이트 전체 동시 실행 로봇 수 (0이면 제한 없음)
+//	PUT /admin/templates/<id>/deadline        {"deadline": "15m"} 오더 실행 제한 시간 (0이면 ORDER_DEADLINE_SECONDS)
+//	PUT /admin/templates/<id>/notifications   {"notify_on", "notify_channel", "notify_escalate_after"} 종료 알림 설정
+//
+// 성공하면 변경된 템플릿을 반환합니다.
+func (s *Server) SetTemplateSettings(db *gorm.DB, siteID string) {
+	handle := func(apply func(templateID uint, body json.RawMessage) error) templateRoute {
+		return func(w http.ResponseWriter, r *http.Request, templateID uint, rest string) {
+			if rest != "" {
+				http.NotFound(w, r)
+				return
+			}
+			if r.Method != http.MethodPut {
+				w.Header().Set("Allow", http.MethodPut)
+				writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use PUT"})
+				return
+			}
+			var body json.RawMessage
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+				return
+			}
+			if err := apply(templateID, body); err != nil {
+				writeJSON(w, settingsErrorStatus(err), apperr.ToResponse(err, ""))
+				return
+			}
+			var template models.OrderTemplate
+			if err := db.Scopes(repository.SiteScope(siteID)).First(&template, templateID).Error; err != nil {
+				writeJSON(w, http.StatusInternalServerError, apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusOK, template)
+		}
+	}
+	decode := func(body json.RawMessage, v interface{}) error {
+		if err := json.Unmarshal(body, v); err != nil {
+			return apperr.Validation("body", "invalid request body: %v", err)
+		}
+		return nil
+	}
+
+	s.handleTemplate("status", handle(func(templateID uint, body json.RawMessage) error {
+		var req struct {
+			Status string `json:"status"`
+		}
+		if err := decode(body, &req); err != nil {
+			return err
+		}
+		return repository.SetOrderTemplateStatus(db, siteID, templateID, strings.ToUpper(req.Status))
+	}))
+	s.handleTemplate("limit", handle(func(templateID uint, body json.RawMessage) error {
+		var req struct {
+			MaxConcurrent int `json:"max_concurrent_executions"`
+		}
+		if err := decode(body, &req); err != nil {
+			return err
+		}
+		return repository.SetTemplateConcurrencyLimit(db, siteID, templateID, req.MaxConcurrent)
+	}))
+	s.handleTemplate("deadline", handle(func(templateID uint, body json.RawMessage) error {
+		var req struct {
+			Deadline string `json:"deadline"`
+		}
+		if err := decode(body, &req); err != nil {
+			return err
+		}
+		deadline, err := time.ParseDuration(req.Deadline)
+		if err != nil {
+			return apperr.Validation("deadline", "invalid deadline %q", req.Deadline)
+		}
+		return repository.SetTemplateDeadline(db, siteID, templateID, deadline)
+	}))
+	s.handleTemplate("notifications", handle(func(templateID uint, body json.RawMessage) error {
+		var req repository.TemplateNotifications
+		if err := decode(body, &req); err != nil {
+			return err
+		}
+		_, err := repository.SetTemplateNotifications(db, siteID, templateID, req)
+		return err
+	}))
+}
+
+// SetTemplateImport 템플릿 가져오기 엔드포인트 등록 (Start 전에 호출)
+//
+//	POST /admin/templates/import   내보내기 형식([]TemplateExport) 그대로
+//
+// 한 트랜잭션에서 가져오므로 하나라도 실패하면(같은 이름이 이미 있는 경우 포함) 아무것도 만들지 않고 422를 반환합니다.
+// 성공하면 201과 만든 템플릿 목록을 반환합니다.
+func (s *Server) SetTemplateImport(db *gorm.DB, siteID string) {
+	s.mux.HandleFunc("/admin/templates/import", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+		var exports []repository.TemplateExport
+		if err := json.NewDecoder(r.Body).Decode(&exports); err != nil {
+			writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+			return
+		}
+
+		var imported []*models.OrderTemplate
+		err := db.Transaction(func(tx *gorm.DB) error {
+			for _, export := range exports {
+				template, err := repository.ImportOrderTemplate(tx, siteID, export)
+				if err != nil {
+					return err
+				}
+				imported = append(imported, template)
+			}
+			return nil
+		})
+		if err != nil {
+			writeJSON(w, settingsErrorStatus(err), apperr.ToResponse(err, ""))
+			return
+		}
+		writeJSON(w, http.StatusCreated, imported)
+	})
+}
+
+// SetTemplateEstimates 템플릿 오더 소요 시간 추정 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET /admin/templates/<id>/estimate?robot=<serial>   예상 소요 시간과 90% 신뢰 구간, 단계별 평균
+func (s *Server) SetTemplateEstimates(db *gorm.DB, siteID string) {
+	s.handleTemplate("estimate", func(w http.ResponseWriter, r *http.Request, templateID uint, rest string) {
+		if rest != "" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+		estimate, err := repository.EstimateOrderDuration(db, siteID, templateID, r.URL.Query().Get("robot"))
+		if err != nil {
+			status := http.StatusInternalServerError
+			if apperr.CodeOf(err) == apperr.CodeTemplateNotFound {
+				status = http.StatusNotFound
+			}
+			writeJSON(w, status, apperr.ToResponse(err, ""))
+			return
+		}
+		writeJSON(w, http.StatusOK, estimate)
+	})
+}
+
+// SetTemplateLint 템플릿 린트 엔드포인트 등록 (Start 전에 호출)
+//
+//	POST /admin/templates/<id>/lint   규칙 ID와 심각도(ERROR, WARNING)가 붙은 검사 결과
+//
+// 템플릿을 바꾸지 않으며, 문제가 있어도 200과 보고서를 반환합니다 (errors/warnings 수로 판단).
+func (s *Server) SetTemplateLint(db *gorm.DB, siteID string) {
+	s.handleTemplate("lint", func(w http.ResponseWriter, r *http.Request, templateID uint, rest string) {
+		if rest != "" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+			return
+		}
+		report, err := repository.LintOrderTemplate(db, siteID, templateID)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if apperr.CodeOf(err) == apperr.CodeTemplateNotFound {
+				status = http.StatusNotFound
+			}
+			writeJSON(w, status, apperr.ToResponse(err, ""))
+			return
+		}
+		writeJSON(w, http.StatusOK, report)
+	})
+}
+
+// SetTemplateShadows 템플릿 섀도 실행 설정과 비교 결과 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET    /admin/templates/<id>/shadow                       섀도 설정 조회
+//	PUT    /admin/templates/<id>/shadow                       {"shadow_template_id"}로 섀도 실행 시작 (기존 설정 교체)
+//	DELETE /admin/templates/<id>/shadow                       섀도 실행 중지
+//	GET    /admin/templates/<id>/shadow-compare/<otherId>     실행별 오더 메시지 비교 (?limit=)
+func (s *Server) SetTemplateShadows(db *gorm.DB, siteID string) {
+	s.handleTemplate("shadow", func(w http.ResponseWriter, r *http.Request, templateID uint, rest string) {
+		if rest != "" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			shadow, err := repository.FindTemplateShadow(db, siteID, templateID)
+			if err == nil && shadow == nil {
+				err = apperr.New(apperr.CodeNotFound, "order template %d has no shadow template", templateID).WithField("templateID")
+			}
+			if err != nil {
+				writeJSON(w, rolloutErrorStatus(err), apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusOK, shadow)
+		case http.MethodPut:
+			var body struct {
+				ShadowTemplateID uint `json:"shadow_template_id"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+				return
+			}
+			shadow, err := repository.SetTemplateShadow(db, siteID, templateID, body.ShadowTemplateID)
+			if err != nil {
+				writeJSON(w, rolloutErrorStatus(err), apperr.ToResponse(err, ""))
+				return
+			}
+			writeJSON(w, http.StatusOK, shadow)
+		case http.MethodDelete:
+			if err := repository.RemoveTemplateShadow(db, siteID, templateID); err != nil {
+				writeJSON(w, rolloutErrorStatus(err), apperr.ToResponse(err, ""))
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Allow", "GET, PUT, DELETE")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET, PUT or DELETE"})
+		}
+	})
+	s.handleTemplate("shadow-compare", func(w http.ResponseWriter, r *http.Request, templateID uint, rest string) {
+		otherID, err := strconv.ParseUint(rest, 10, 64)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		report, err := repository.CompareTemplateShadow(db, siteID, templateID, uint(otherID), limit)
+		if err != nil {
+			writeJSON(w, rolloutErrorStatus(err), apperr.ToResponse(err, ""))
+			return
+		}
+		writeJSON(w, http.StatusOK, report)
+	})
+}
+
+// syncFetchTimeout 다른 브릿지에서 동기화 스냅샷을 받아 오는 제한 시간
+const syncFetchTimeout = 15 * time.Second
+
+// SetTemplateSync 브릿지 사이 템플릿/PLC 매핑 동기화 엔드포인트 등록 (Start 전에 호출)
+//
+//	GET  /admin/sync/snapshot                    이 브릿지의 템플릿과 PLC 매핑 (다른 브릿지가 비교에 사용)
+//	GET  /admin/sync/diff?source=<url>           원본 브릿지와 비교해 적용될 변경 미리보기
+//	POST /admin/sync/apply[?dry_run=true]        {"source": "<url>", "changes": ["template:pick", ...]} 고른 변경 적용
+//
+// source는 sources(TEMPLATE_SYNC_SOURCES)에 등록된 브릿지 주소만 허용하며, 하나만 등록되어 있으면 생략할 수 있습니다.
+// sources가 비어 있으면 스냅샷만 제공합니다 (이 브릿지는 원본으로만 쓰임).
+func (s *Server) SetTemplateSync(db *gorm.DB, siteID string, sources []string) {
+	s.mux.HandleFunc("/admin/sync/snapshot", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+		snapshot, err := repository.ExportSyncSnapshot(db, siteID)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, apperr.ToResponse(err, ""))
+			return
+		}
+		writeJSON(w, http.StatusOK, snapshot)
+	})
+	if len(sources) == 0 {
+		return
+	}
+
+	resolveSource := func(source string) (string, error) {
+		source = strings.TrimRight(strings.TrimSpace(source), "/")
+		if source == "" && len(sources) == 1 {
+			return strings.TrimRight(sources[0], "/"), nil
+		}
+		for _, allowed := range sources {
+			if strings.TrimRight(allowed, "/") == source {
+				return source, nil
+			}
+		}
+		return "", apperr.Validation("source", "sync source %q is not in TEMPLATE_SYNC_SOURCES", source)
+	}
+	syncErrorStatus := func(err error) int {
+		switch apperr.CodeOf(err) {
+		case apperr.CodeValidationFailed:
+			return http.StatusUnprocessableEntity
+		case apperr.CodeTransportUnavailable:
+			return http.StatusBadGateway
+		}
+		return http.StatusInternalServerError
+	}
+
+	s.mux.HandleFunc("/admin/sync/diff", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+			return
+		}
+		source, err := resolveSource(r.URL.Query().Get("source"))
+		if err != nil {
+			writeJSON(w, syncErrorStatus(err), apperr.ToResponse(err, ""))
+			return
+		}
+		remote, err := FetchSyncSnapshot(r.Context(), source)
+		if err != nil {
+			writeJSON(w, syncErrorStatus(err), apperr.ToResponse(err, ""))
+			return
+		}
+		local, err := repository.ExportSyncSnapshot(db, siteID)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, apperr.ToResponse(err, ""))
+			return
+		}
+		diff, err := repository.DiffSyncSnapshots(local, remote)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, apperr.ToResponse(err, ""))
+			return
+		}
+		diff.Source = source
+		writeJSON(w, http.StatusOK, diff)
+	})
+
+	s.mux.HandleFunc("/admin/sync/apply", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+			return
+		}
+		var request struct {
+			Source  string   `json:"source"`
+			Changes []string `json:"changes"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeJSON(w, http.StatusBadRequest, apperr.ToResponse(apperr.Validation("body", "invalid request body: %v", err), ""))
+			return
+		}
+		source, err := resolveSource(request.Source)
+		if err != nil {
+			writeJSON(w, syncErrorStatus(err), apperr.ToResponse(err, ""))
+			return
+		}
+		remote, err := FetchSyncSnapshot(r.Context(), source)
+		if err != nil {
+			writeJSON(w, syncErrorStatus(err), apperr.ToResponse(err, ""))
+			return
+		}
+		dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+		result, err := repository.ApplySyncChanges(db, siteID, remote, request.Changes, dryRun)
+		if err != nil {
+			writeJSON(w, syncErrorStatus(err), apperr.ToResponse(err, ""))
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	})
+}
+
+// FetchSyncSnapshot 다른 브릿지의 /admin/sync/snapshot 조회 (연결 실패나 200이 아닌 응답은 TRANSPORT_UNAVAILABLE)
+func FetchSyncSnapshot(ctx context.Context, baseURL string) (*repository.SyncSnapshot, error) {
+	ctx, cancel := context.WithTimeout(ctx, syncFetchTimeout)
+	defer cancel()
+	url := strings.TrimRight(baseURL, "/") + "/admin/sync/snapshot"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, apperr.Validation("source", "invalid sync source %q: %v", baseURL, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, apperr.Wrap(apperr.CodeTransportUnavailable, err, "failed to fetch sync snapshot from %s", baseURL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, apperr.New(apperr.CodeTransportUnavailable, "sync snapshot from %s returned %d: %s",
+			baseURL, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var snapshot repository.SyncSnapshot
+	if err := json.NewDecoder(resp.Body).Decode(&snapshot); err != nil {
+		return nil, apperr.Wrap(apperr.CodeTransportUnavailable, err, "invalid sync snapshot from %s", baseURL)
+	}
+	return &snapshot, nil
+}
@@ -0,0 +1,314 @@
+// internal/janitor/janitor.go
+// Package janitor 흐름이 비정상 종료되어 남은 브릿지 소유 Redis 키를 DB 실행 상태와 대조해 정리합니다.
+package janitor
+
+import (
+	"context"
+	"fmt"
+	"mqtt-bridge/internal/common/constants"
+	redisKeys "mqtt-bridge/internal/common/redis"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/utils"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+// scanBatch SCAN 한 번에 요청할 키 수 (DB 대조도 이 단위로 함)
+const scanBatch = 500
+
+// KeyStats 키 패턴별 누적 정리 지표
+type KeyStats struct {
+	Pattern string `json:"pattern"`
+	Scanned uint64 `json:"scanned"`
+	Deleted uint64 `json:"deleted"` // DB에 진행 중인 실행이 없어 삭제한 고아 키
+	TTLSet  uint64 `json:"ttl_set"` // 만료 없이 남아 있어 만료를 설정한 키
+}
+
+// Stats 정리 작업 누적 지표
+type Stats struct {
+	Runs      uint64     `json:"runs"`
+	Failures  uint64     `json:"failures"`
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+	Keys      []KeyStats `json:"keys"`
+}
+
+// keyPattern 정리 대상 키 패턴과 고아 판정 방법
+type keyPattern struct {
+	pattern string
+	prefix  string
+	// active 키 접미사(ID) 중 DB에 진행 중인 실행이 있는 것
+	active func(db *gorm.DB, ids []string) (map[string]bool, error)
+	// ttl 살아 있는 키에 적용할 만료 (0이면 만료를 강제하지 않음)
+	ttl time.Duration
+}
+
+// Janitor 브릿지 소유 Redis 키 정리기
+// step_actions:<단계 실행 ID>는 단계가 RUNNING이 아니면, pending_direct_command:<orderId>는
+// 오더가 PENDING/RUNNING/WAITING/PAUSED가 아니면 고아로 보고 삭제합니다.
+// 로봇별 브릿지가 같은 Redis를 함께 쓰므로 사이트/로봇 구분 없이 DB 전체 기준으로 판정합니다.
+type Janitor struct {
+	db       *gorm.DB
+	client   *redis.Client
+	interval time.Duration
+	patterns []keyPattern
+
+	running sync.Mutex
+	mu      sync.Mutex
+	stats   Stats
+	keys    map[string]*KeyStats
+
+	cancel context.CancelFunc
+	doneCh chan struct{}
+}
+
+// NewJanitor 정리기 생성 (stepActionsTTL은 만료 없이 남은 진행 중 단계 키에 설정할 만료)
+func NewJanitor(db *gorm.DB, client *redis.Client, interval, stepActionsTTL time.Duration) *Janitor {
+	j := &Janitor{
+		db:       db,
+		client:   client,
+		interval: interval,
+		keys:     make(map[string]*KeyStats),
+		patterns: []keyPattern{
+			{pattern: redisKeys.AllStepActions(), prefix: "step_actions:", active: activeSteps, ttl: stepActionsTTL},
+			{pattern: redisKeys.AllPendingDirectCommands(), prefix: "pending_direct_command:", active: activeOrders},
+		},
+	}
+	for _, p := range j.patterns {
+		stats := &KeyStats{Pattern: p.pattern}
+		j.keys[p.pattern] = stats
+		j.stats.Keys = append(j.stats.Keys, *stats)
+	}
+	return j
+}
+
+// Start 주기적 정리 시작 (interval이 0이면 시작하지 않음)
+func (j *Janitor) Start(ctx context.Context) {
+	if j.interval <= 0 {
+		return
+	}
+	ctx, j.cancel = context.WithCancel(ctx)
+	j.doneCh = make(chan struct{})
+	go j.run(ctx)
+	utils.Logger.Infof("✅ Redis key janitor started (interval %v)", j.interval)
+}
+
+// Stop 주기적 정리를 멈춤
+func (j *Janitor) Stop() {
+	if j.cancel == nil {
+		return
+	}
+	j.cancel()
+	<-j.doneCh
+	j.cancel = nil
+}
+
+func (j *Janitor) run(ctx context.Context) {
+	defer close(j.doneCh)
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := j.Run(ctx); err != nil {
+				utils.Logger.Errorf("❌ Redis key janitor failed: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Run 정리를 한 번 실행하고 이번 실행의 패턴별 결과를 반환 (동시에 하나만 실행)
+func (j *Janitor) Run(ctx context.Context) ([]KeyStats, error) {
+	j.running.Lock()
+	defer j.running.Unlock()
+
+	var results []KeyStats
+	var runErr error
+	for _, p := range j.patterns {
+		result, err := j.sweep(ctx, p)
+		results = append(results, result)
+		if err != nil {
+			runErr = fmt.Errorf("%s: %w", p.pattern, err)
+			break
+		}
+	}
+	j.record(results, runErr)
+
+	for _, r := range results {
+		if r.Deleted > 0 || r.TTLSet > 0 {
+			utils.Logger.Infof("🧹 Redis janitor %s: scanned %d, deleted %d orphan(s), set TTL on %d", r.Pattern, r.Scanned, r.Deleted, r.TTLSet)
+		}
+	}
+	return results, runErr
+}
+
+// sweep 패턴의 키를 SCAN으로 나눠 읽으며 고아 키 삭제와 만료 설정
+func (j *Janitor) sweep(ctx context.Context, p keyPattern) (KeyStats, error) {
+	result := KeyStats{Pattern: p.pattern}
+	var cursor uint64
+	for {
+		keys, next, err := j.client.Scan(ctx, cursor, p.pattern, scanBatch).Result()
+		if err != nil {
+			return result, err
+		}
+		if len(keys) > 0 {
+			result.Scanned += uint64(len(keys))
+			deleted, ttlSet, err := j.check(ctx, p, keys)
+			result.Deleted += deleted
+			result.TTLSet += ttlSet
+			if err != nil {
+				return result, err
+			}
+		}
+		if next == 0 {
+			return result, nil
+		}
+		cursor = next
+	}
+}
+
+// check 키 한 묶음을 DB와 대조 (SCAN은 같은 키를 두 번 돌려줄 수 있어 중복 제거)
+func (j *Janitor) check(ctx context.Context, p keyPattern, keys []string) (uint64, uint64, error) {
+	keyByID := make(map[string]string, len(keys))
+	ids := make([]string, 0, len(keys))
+	for _, key := range keys {
+		id := strings.TrimPrefix(key, p.prefix)
+		if _, seen := keyByID[id]; !seen {
+			keyByID[id] = key
+			ids = append(ids, id)
+		}
+	}
+	active, err := p.active(j.db, ids)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var orphans, live []string
+	for _, id := range ids {
+		if active[id] {
+			live = append(live, keyByID[id])
+		} else {
+			orphans = append(orphans, keyByID[id])
+		}
+	}
+
+	var deleted uint64
+	if len(orphans) > 0 {
+		n, err := j.client.Del(ctx, orphans...).Result()
+		if err != nil {
+			return 0, 0, err
+		}
+		deleted = uint64(n)
+	}
+	if p.ttl <= 0 || len(live) == 0 {
+		return deleted, 0, nil
+	}
+
+	pipe := j.client.Pipeline()
+	ttls := make([]*redis.DurationCmd, len(live))
+	for i, key := range live {
+		ttls[i] = pipe.TTL(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return deleted, 0, err
+	}
+	var ttlSet uint64
+	for i, cmd := range ttls {
+		// 만료가 없는 키는 -1, 그 사이 삭제된 키는 -2로 반환됨
+		if cmd.Val() != -1 {
+			continue
+		}
+		if ok, err := j.client.Expire(ctx, live[i], p.ttl).Result(); err != nil {
+			return deleted, ttlSet, err
+		} else if ok {
+			ttlSet++
+		}
+	}
+	return deleted, ttlSet, nil
+}
+
+// record 누적 지표 반영
+func (j *Janitor) record(results []KeyStats, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	now := time.Now()
+	j.stats.Runs++
+	j.stats.LastRunAt = &now
+	j.stats.LastError = ""
+	if err != nil {
+		j.stats.Failures++
+		j.stats.LastError = err.Error()
+	}
+	for _, r := range results {
+		stats := j.keys[r.Pattern]
+		stats.Scanned += r.Scanned
+		stats.Deleted += r.Deleted
+		stats.TTLSet += r.TTLSet
+	}
+	for i := range j.stats.Keys {
+		j.stats.Keys[i] = *j.keys[j.stats.Keys[i].Pattern]
+	}
+}
+
+// Stats 누적 정리 지표 (복사본)
+func (j *Janitor) Stats() Stats {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	stats := j.stats
+	stats.Keys = append([]KeyStats(nil), j.stats.Keys...)
+	return stats
+}
+
+// activeSteps 단계 실행 ID 중 RUNNING인 것 (숫자가 아닌 ID는 어떤 단계에도 속하지 않음)
+func activeSteps(db *gorm.DB, ids []string) (map[string]bool, error) {
+	var stepIDs []uint64
+	for _, id := range ids {
+		if stepID, err := strconv.ParseUint(id, 10, 64); err == nil {
+			stepIDs = append(stepIDs, stepID)
+		}
+	}
+	active := make(map[string]bool)
+	if len(stepIDs) == 0 {
+		return active, nil
+	}
+	var running []uint64
+	err := db.Model(&models.StepExecution{}).
+		Where("id IN ? AND status = ?", stepIDs, constants.StepExecutionStatusRunning).
+		Pluck("id", &running).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range running {
+		active[strconv.FormatUint(id, 10)] = true
+	}
+	return active, nil
+}
+
+// activeOrders orderId 중 아직 끝나지 않은 오더 실행이 있는 것
+func activeOrders(db *gorm.DB, ids []string) (map[string]bool, error) {
+	var orderIDs []string
+	err := db.Model(&models.OrderExecution{}).
+		Where("order_id IN ? AND status IN ?", ids, []string{
+			constants.OrderExecutionStatusPending,
+			constants.OrderExecutionStatusRunning,
+			constants.OrderExecutionStatusWaiting,
+			constants.OrderExecutionStatusPaused,
+		}).
+		Pluck("order_id", &orderIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	active := make(map[string]bool, len(orderIDs))
+	for _, id := range orderIDs {
+		active[id] = true
+	}
+	return active, nil
+}
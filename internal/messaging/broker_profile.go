@@ -0,0 +1,291 @@
+// internal/messaging/broker_profile.go
+package messaging
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"mqtt-bridge/internal/config"
+	"mqtt-bridge/internal/utils"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// 브로커 프로파일 이름
+const (
+	BrokerProfileGeneric = "generic"
+	BrokerProfileAWSIoT  = "aws-iot"
+	BrokerProfileAzure   = "azure-iot-hub"
+)
+
+// azureAPIVersion Azure IoT Hub MQTT 사용자명에 포함되는 API 버전
+const azureAPIVersion = "2021-04-12"
+
+// TopicMapper 브릿지 내부 토픽과 브로커 토픽 간 변환 인터페이스
+type TopicMapper interface {
+	// OutboundTopic 발행 토픽 변환
+	OutboundTopic(local string) string
+	// InboundFilter 구독 필터 변환
+	InboundFilter(localFilter string) string
+	// LocalTopic 수신 토픽을 내부 토픽으로 복원 (복원 불가 시 false)
+	LocalTopic(remote string) (string, bool)
+}
+
+// BrokerProfile 관리형 MQTT 서비스별 연결 제약
+type BrokerProfile struct {
+	Name         string
+	Mapper       TopicMapper // nil이면 토픽 변환 없음
+	MaxQoS       byte
+	AllowRetain  bool
+	SharedSubs   bool // $share/<그룹>/ 공유 구독 지원
+	RequireTLS   bool
+	KeepAlive    time.Duration
+	MinKeepAlive time.Duration
+	MaxKeepAlive time.Duration
+	ClientID     string
+	Username     string
+	Password     string
+}
+
+// NewBrokerProfile 설정으로 브로커 프로파일 생성
+func NewBrokerProfile(cfg *config.Config) (*BrokerProfile, error) {
+	usesCert := cfg.MQTTCertFile != ""
+
+	var profile *BrokerProfile
+	switch strings.ToLower(cfg.MQTTBrokerProfile) {
+	case "", BrokerProfileGeneric:
+		profile = &BrokerProfile{
+			Name:        BrokerProfileGeneric,
+			MaxQoS:      2,
+			AllowRetain: true,
+			SharedSubs:  true,
+			KeepAlive:   60 * time.Second,
+			ClientID:    cfg.MQTTClientID,
+			Username:    cfg.MQTTUsername,
+			Password:    cfg.MQTTPassword,
+		}
+
+	case BrokerProfileAWSIoT:
+		// AWS IoT Core: QoS 2 미지원, 인증서 기반 상호 TLS, keepalive 30~1200초, 공유 구독 지원
+		profile = &BrokerProfile{
+			Name:         BrokerProfileAWSIoT,
+			MaxQoS:       1,
+			AllowRetain:  true,
+			SharedSubs:   true,
+			RequireTLS:   true,
+			KeepAlive:    300 * time.Second,
+			MinKeepAlive: 30 * time.Second,
+			MaxKeepAlive: 1200 * time.Second,
+			ClientID:     cfg.MQTTClientID,
+		}
+		if cfg.AWSIoTIngestRule != "" {
+			profile.Mapper = awsIngestMapper{prefix: "$aws/rules/" + cfg.AWSIoTIngestRule + "/"}
+		}
+		if !usesCert {
+			// 커스텀 인증자 사용 시에만 사용자명/비밀번호 전송
+			profile.Username = cfg.MQTTUsername
+			profile.Password = cfg.MQTTPassword
+		}
+
+	case BrokerProfileAzure:
+		// Azure IoT Hub: 디바이스 단위 토픽, QoS 0/1만 지원, retain/공유 구독 미지원, 클라이언트 ID = 디바이스 ID
+		deviceID := cfg.AzureIoTDeviceID
+		if deviceID == "" {
+			deviceID = cfg.MQTTClientID
+		}
+		hubHost := cfg.AzureIoTHubHost
+		if hubHost == "" {
+			hubHost = brokerHost(cfg.MQTTBroker)
+		}
+		profile = &BrokerProfile{
+			Name:         BrokerProfileAzure,
+			Mapper:       azureDeviceMapper{deviceID: deviceID},
+			MaxQoS:       1,
+			RequireTLS:   true,
+			KeepAlive:    230 * time.Second,
+			MaxKeepAlive: 1177 * time.Second,
+			ClientID:     deviceID,
+			Username:     fmt.Sprintf("%s/%s/?api-version=%s", hubHost, deviceID, azureAPIVersion),
+		}
+		if !usesCert {
+			// SAS 토큰 인증
+			profile.Password = cfg.MQTTPassword
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported MQTT broker profile: %s", cfg.MQTTBrokerProfile)
+	}
+
+	if profile.RequireTLS && strings.HasPrefix(strings.ToLower(cfg.MQTTBroker), "tcp://") {
+		return nil, fmt.Errorf("broker profile %s requires a TLS broker URL (ssl://host:8883), got %s", profile.Name, cfg.MQTTBroker)
+	}
+
+	if cfg.MQTTSharedGroup != "" && !profile.SharedSubs {
+		return nil, fmt.Errorf("broker profile %s does not support shared subscriptions, unset MQTT_SHARED_GROUP", profile.Name)
+	}
+	for kind, qos := range cfg.MQTTSubscriptionQoS {
+		if qos > profile.MaxQoS {
+			utils.Logger.Warnf("⚠️ %s subscription QoS %d above %s maximum, using %d", kind, qos, profile.Name, profile.MaxQoS)
+		}
+	}
+
+	if cfg.MQTTKeepAlive > 0 {
+		profile.KeepAlive = cfg.MQTTKeepAlive
+	}
+	if profile.MinKeepAlive > 0 && profile.KeepAlive < profile.MinKeepAlive {
+		utils.Logger.Warnf("⚠️ Keepalive %v below %s minimum, using %v", profile.KeepAlive, profile.Name, profile.MinKeepAlive)
+		profile.KeepAlive = profile.MinKeepAlive
+	}
+	if profile.MaxKeepAlive > 0 && profile.KeepAlive > profile.MaxKeepAlive {
+		utils.Logger.Warnf("⚠️ Keepalive %v above %s maximum, using %v", profile.KeepAlive, profile.Name, profile.MaxKeepAlive)
+		profile.KeepAlive = profile.MaxKeepAlive
+	}
+
+	return profile, nil
+}
+
+// ApplyOptions 프로파일 제약을 클라이언트 옵션에 적용
+func (p *BrokerProfile) ApplyOptions(opts *mqtt.ClientOptions, cfg *config.Config) error {
+	opts.SetClientID(p.ClientID)
+	opts.SetUsername(p.Username)
+	opts.SetPassword(p.Password)
+	opts.SetKeepAlive(p.KeepAlive)
+
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return err
+	}
+	if tlsConfig != nil {
+		opts.SetTLSConfig(tlsConfig)
+	}
+	return nil
+}
+
+// Wrap 토픽 변환 및 QoS/retain 제약을 적용한 클라이언트 반환 (generic은 그대로)
+func (p *BrokerProfile) Wrap(client mqtt.Client) mqtt.Client {
+	if p.Mapper == nil && p.MaxQoS >= 2 && p.AllowRetain {
+		return client
+	}
+	mapper := p.Mapper
+	if mapper == nil {
+		mapper = identityMapper{}
+	}
+	return &profileClient{
+		Client:   client,
+		profile:  p,
+		mapper:   mapper,
+		handlers: make(map[string]mqtt.MessageHandler),
+	}
+}
+
+// clampQoS 프로파일 최대 QoS로 제한
+func (p *BrokerProfile) clampQoS(qos byte) byte {
+	if qos > p.MaxQoS {
+		return p.MaxQoS
+	}
+	return qos
+}
+
+// newTLSConfig CA/클라이언트 인증서 파일로 TLS 설정 생성 (설정이 없으면 nil)
+func newTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	if cfg.MQTTCAFile == "" && cfg.MQTTCertFile == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if cfg.MQTTCAFile != "" {
+		caPEM, err := os.ReadFile(cfg.MQTTCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read MQTT CA file: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in MQTT CA file %s", cfg.MQTTCAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.MQTTCertFile != "" {
+		if cfg.MQTTKeyFile == "" {
+			return nil, fmt.Errorf("MQTT_KEY_FILE is required when MQTT_CERT_FILE is set")
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.MQTTCertFile, cfg.MQTTKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load MQTT client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// brokerHost 브로커 URL에서 호스트명 추출
+func brokerHost(broker string) string {
+	u, err := url.Parse(broker)
+	if err != nil || u.Hostname() == "" {
+		return broker
+	}
+	return u.Hostname()
+}
+
+// identityMapper 토픽 변환 없음
+type identityMapper struct{}
+
+func (identityMapper) OutboundTopic(local string) string       { return local }
+func (identityMapper) InboundFilter(localFilter string) string { return localFilter }
+func (identityMapper) LocalTopic(remote string) (string, bool) { return remote, true }
+
+// awsIngestMapper AWS IoT Basic Ingest: 발행만 $aws/rules/{rule}/ 접두사로 규칙 엔진에 직접 전달
+type awsIngestMapper struct {
+	prefix string
+}
+
+// OutboundTopic 예약 토픽($aws/...)을 제외하고 Basic Ingest 접두사 추가
+func (m awsIngestMapper) OutboundTopic(local string) string {
+	if strings.HasPrefix(local, "$aws/") {
+		return local
+	}
+	return m.prefix + local
+}
+
+// InboundFilter 구독은 변환 없음 (Basic Ingest 토픽은 구독 불가)
+func (m awsIngestMapper) InboundFilter(localFilter string) string { return localFilter }
+
+// LocalTopic 수신 토픽은 변환 없음
+func (m awsIngestMapper) LocalTopic(remote string) (string, bool) { return remote, true }
+
+// azureDeviceMapper Azure IoT Hub 디바이스 토픽 변환
+// 발행: devices/{id}/messages/events/topic={내부 토픽}
+// 수신: devices/{id}/messages/devicebound/...&topic={내부 토픽} (C2D 메시지 속성)
+type azureDeviceMapper struct {
+	deviceID string
+}
+
+// OutboundTopic 내부 토픽을 D2C 이벤트 속성으로 전달
+func (m azureDeviceMapper) OutboundTopic(local string) string {
+	return fmt.Sprintf("devices/%s/messages/events/topic=%s", m.deviceID, url.QueryEscape(local))
+}
+
+// InboundFilter 모든 구독은 디바이스의 C2D 토픽 하나로 수렴
+func (m azureDeviceMapper) InboundFilter(localFilter string) string {
+	return fmt.Sprintf("devices/%s/messages/devicebound/#", m.deviceID)
+}
+
+// LocalTopic C2D 메시지 속성의 topic 값으로 내부 토픽 복원
+func (m azureDeviceMapper) LocalTopic(remote string) (string, bool) {
+	prefix := fmt.Sprintf("devices/%s/messages/devicebound/", m.deviceID)
+	if !strings.HasPrefix(remote, prefix) {
+		return "", false
+	}
+	properties, err := url.ParseQuery(strings.TrimPrefix(remote, prefix))
+	if err != nil {
+		return "", false
+	}
+	topic := properties.Get("topic")
+	return topic, topic != ""
+}
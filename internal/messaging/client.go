@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"mqtt-bridge/internal/config"
 	"mqtt-bridge/internal/utils"
+	"sync"
 	"time"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
@@ -25,50 +26,97 @@ type MessageHandler func(client mqtt.Client, msg mqtt.Message)
 type MQTTClient struct {
 	client mqtt.Client
 	config *config.Config
+	buffer *OutboundBuffer // MQTT_BUFFER_DIR가 설정된 경우에만
+	v5     *v5Client       // MQTT_CLIENT_VERSION=5인 경우에만
+
+	subsMu       sync.RWMutex
+	subs         map[string]*subscription // 토픽 필터별 구독 (재연결 시 다시 구독)
+	pausedTopics map[string]bool          // 일시 중지 상태 (상태 파일과 동기화)
 }
 
 // NewMQTTClient 새 MQTT 클라이언트 생성
 func NewMQTTClient(cfg *config.Config) (*MQTTClient, error) {
 	utils.Logger.Infof("🏗️ CREATING MQTT Client")
 
+	profile, err := NewBrokerProfile(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	opts := mqtt.NewClientOptions()
 	opts.AddBroker(cfg.MQTTBroker)
-	opts.SetClientID(cfg.MQTTClientID)
-	opts.SetUsername(cfg.MQTTUsername)
-	opts.SetPassword(cfg.MQTTPassword)
-	opts.SetKeepAlive(60 * time.Second)
+	if err := profile.ApplyOptions(opts, cfg); err != nil {
+		return nil, err
+	}
 	opts.SetPingTimeout(10 * time.Second)
 	opts.SetAutoReconnect(true)
 	opts.SetMaxReconnectInterval(10 * time.Second)
 
-	// 연결 상태 콜백
-	opts.SetOnConnectHandler(func(c mqtt.Client) {
+	mqttClient := &MQTTClient{
+		config:       cfg,
+		subs:         make(map[string]*subscription),
+		pausedTopics: make(map[string]bool),
+	}
+	if err := mqttClient.loadSubscriptionState(); err != nil {
+		return nil, err
+	}
+
+	// 연결 상태 콜백 (재연결이면 일시 중지하지 않은 구독을 다시 구독)
+	onConnect := func(c mqtt.Client) {
 		utils.Logger.Info("MQTT client connected")
-	})
+		mqttClient.resubscribe(c)
+	}
+	opts.SetOnConnectHandler(onConnect)
 
 	opts.SetConnectionLostHandler(func(c mqtt.Client, err error) {
 		utils.Logger.Errorf("MQTT connection lost: %v", err)
 	})
 
-	client := mqtt.NewClient(opts)
+	// MQTT 5 클라이언트는 같은 mqtt.Client 인터페이스로 감싸서 프로파일/버퍼를 그대로 적용
+	var native mqtt.Client
+	if cfg.MQTTClientVersion == config.MQTTVersion5 {
+		mqttClient.v5, err = newV5Client(profile, cfg, onConnect)
+		if err != nil {
+			return nil, err
+		}
+		native = mqttClient.v5
+	} else {
+		native = mqtt.NewClient(opts)
+	}
+
+	// 관리형 브로커의 토픽 변환 및 QoS/retain 제약 적용
+	client := profile.Wrap(native)
+
+	// 연결이 끊긴 동안의 발행을 디스크에 보관 (재연결 시 순서대로 전송)
+	var buffer *OutboundBuffer
+	if cfg.MQTTBufferDir != "" {
+		buffer, err = NewOutboundBuffer(cfg.MQTTBufferDir, cfg.MQTTBufferMaxAge, cfg.MQTTBufferMaxBytes)
+		if err != nil {
+			return nil, err
+		}
+		client = buffer.Wrap(client)
+		utils.Logger.Infof("📦 MQTT outbound buffer enabled (%s, max age %v, max %d bytes)",
+			cfg.MQTTBufferDir, cfg.MQTTBufferMaxAge, cfg.MQTTBufferMaxBytes)
+	}
 
-	// 연결 시도
+	// 연결 시도 (연결 콜백이 mqttClient.client를 쓰지 않도록 클라이언트는 먼저 설정)
+	mqttClient.client = client
 	if token := client.Connect(); token.Wait() && token.Error() != nil {
+		if buffer != nil {
+			buffer.Close()
+		}
 		return nil, fmt.Errorf("failed to connect to MQTT broker: %v", token.Error())
 	}
 
-	mqttClient := &MQTTClient{
-		client: client,
-		config: cfg,
-	}
+	mqttClient.buffer = buffer
 
-	utils.Logger.Infof("✅ MQTT Client CREATED")
+	utils.Logger.Infof("✅ MQTT Client CREATED (profile: %s, protocol: %s, keepalive: %v)", profile.Name, cfg.MQTTClientVersion, profile.KeepAlive)
 	return mqttClient, nil
 }
 
 // Publish 메시지 발행
 func (c *MQTTClient) Publish(topic string, qos byte, retained bool, payload interface{}) error {
-	if !c.client.IsConnected() {
+	if !c.client.IsConnected() && c.buffer == nil {
 		return fmt.Errorf("MQTT client is not connected")
 	}
 
@@ -90,22 +138,38 @@ func (c *MQTTClient) Publish(topic string, qos byte, retained bool, payload inte
 	token := c.client.Publish(topic, qos, retained, payload)
 	if token.Wait() && token.Error() != nil {
 		utils.Logger.Errorf("❌ MQTT SEND FAILED: %s - %v", topic, token.Error())
-		return fmt.Errorf("failed to publish message: %v", token.Error())
+		return fmt.Errorf("failed to publish message: %w", token.Error())
 	}
 
 	utils.Logger.Infof("✅ MQTT SEND SUCCESS: %s", topic)
 	return nil
 }
 
-// Subscribe 토픽 구독
+// Subscribe 토픽 구독 (상태 파일에서 일시 중지된 토픽은 기록만 하고 브로커에는 구독하지 않음)
 func (c *MQTTClient) Subscribe(topic string, qos byte, callback MessageHandler) error {
 	if !c.client.IsConnected() {
 		return fmt.Errorf("MQTT client is not connected")
 	}
 
-	token := c.client.Subscribe(topic, qos, mqtt.MessageHandler(callback))
+	c.subsMu.Lock()
+	sub := &subscription{topic: topic, qos: qos, callback: callback, paused: c.pausedTopics[topic]}
+	if sub.paused {
+		now := time.Now()
+		sub.pausedAt = &now
+	}
+	c.subs[topic] = sub
+	c.subsMu.Unlock()
+	if sub.paused {
+		utils.Logger.Warnf("⏸️ Subscription to %s is paused, not subscribing until resumed", topic)
+		return nil
+	}
+
+	token := c.client.Subscribe(topic, qos, sub.handle(c))
 	if token.Wait() && token.Error() != nil {
-		return fmt.Errorf("failed to subscribe to topic %s: %v", topic, token.Error())
+		c.subsMu.Lock()
+		delete(c.subs, topic)
+		c.subsMu.Unlock()
+		return fmt.Errorf("failed to subscribe to topic %s: %w", topic, token.Error())
 	}
 
 	utils.Logger.Infof("✅ Subscribed to topic: %s", topic)
@@ -114,12 +178,29 @@ func (c *MQTTClient) Subscribe(topic string, qos byte, callback MessageHandler)
 
 // Disconnect 연결 해제
 func (c *MQTTClient) Disconnect(quiesce uint) {
+	if c.buffer != nil {
+		c.buffer.Close()
+	}
 	if c.client.IsConnected() {
 		c.client.Disconnect(quiesce)
 		utils.Logger.Info("MQTT client disconnected")
 	}
 }
 
+// OutboundBuffer 발행 버퍼 반환 (비활성화되어 있으면 nil)
+func (c *MQTTClient) OutboundBuffer() *OutboundBuffer {
+	return c.buffer
+}
+
+// ProtocolStats MQTT 5 발행 사유 코드/팩트시트 요청 지표 (MQTT 3.1.1 클라이언트면 nil)
+func (c *MQTTClient) ProtocolStats() *ProtocolStats {
+	if c.v5 == nil {
+		return nil
+	}
+	stats := c.v5.Stats()
+	return &stats
+}
+
 // IsConnected 연결 상태 확인
 func (c *MQTTClient) IsConnected() bool {
 	return c.client.IsConnected()
@@ -0,0 +1,468 @@
+// internal/messaging/client_v5.go
+package messaging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/config"
+	"mqtt-bridge/internal/utils"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/packets"
+	"github.com/eclipse/paho.golang/paho"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// factsheetRequestTTL 응답이 오지 않은 팩트시트 요청을 짝 맞춤 대기 목록에서 지우기까지의 시간
+const factsheetRequestTTL = 10 * time.Minute
+
+// ReasonCodeError 브로커가 MQTT 5 사유 코드로 발행이나 구독을 거부한 경우
+type ReasonCodeError struct {
+	Operation  string // publish, subscribe
+	Topic      string
+	ReasonCode byte
+	Reason     string // 브로커가 보낸 reason string (없으면 사유 코드 설명)
+}
+
+func (e *ReasonCodeError) Error() string {
+	return fmt.Sprintf("broker rejected %s to %s: reason code 0x%02X (%s)", e.Operation, e.Topic, e.ReasonCode, e.Reason)
+}
+
+// ProtocolStats MQTT 5 클라이언트 지표 (사유 코드별 발행 거부, 팩트시트 요청/응답 짝 맞춤)
+type ProtocolStats struct {
+	Version            string            `json:"version"`
+	PublishFailures    map[string]uint64 `json:"publish_failures"`    // 사유 코드(0x87 등)별 거부 수, 사유 코드 없는 실패는 "none"
+	FactsheetRequests  uint64            `json:"factsheet_requests"`  // response topic/correlation data를 붙여 보낸 요청 수
+	FactsheetResponses uint64            `json:"factsheet_responses"` // correlation data로 요청과 짝지어진 응답 수
+}
+
+// v5Client MQTT 5 연결을 paho v1 mqtt.Client 인터페이스로 제공 (MQTT_CLIENT_VERSION=5)
+// 나머지 코드는 기존 클라이언트처럼 사용하고, MQTT 5 속성은 발행 토픽/페이로드를 보고 여기서 붙입니다.
+//   - 오더: ORDER_MESSAGE_EXPIRY_SECONDS로 message expiry 설정
+//   - factsheetRequest 즉시 액션: 로봇의 factsheet 토픽을 response topic으로, actionId를 correlation data로 설정
+type v5Client struct {
+	config       autopaho.ClientConfig
+	onConnect    mqtt.OnConnectHandler
+	timeout      time.Duration // 연결/발행/구독 한 번의 제한 시간
+	orderExpiry  time.Duration
+	connected    atomic.Bool
+	factsheetReq atomic.Uint64
+	factsheetRes atomic.Uint64
+
+	mu       sync.RWMutex
+	cm       *autopaho.ConnectionManager
+	cancel   context.CancelFunc
+	handlers map[string]mqtt.MessageHandler // 구독 필터 → 핸들러
+
+	statsMu  sync.Mutex
+	failures map[string]uint64
+	pending  map[string]time.Time // 응답을 기다리는 팩트시트 요청 actionId → 전송 시각
+}
+
+// newV5Client 브로커 프로파일의 인증/TLS/keepalive로 MQTT 5 클라이언트 생성 (Connect 전까지 연결하지 않음)
+func newV5Client(profile *BrokerProfile, cfg *config.Config, onConnect mqtt.OnConnectHandler) (*v5Client, error) {
+	serverURL, err := url.Parse(cfg.MQTTBroker)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MQTT broker URL %s: %v", cfg.MQTTBroker, err)
+	}
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	c := &v5Client{
+		onConnect:   onConnect,
+		timeout:     timeout,
+		orderExpiry: cfg.OrderMessageExpiry,
+		handlers:    make(map[string]mqtt.MessageHandler),
+		failures:    make(map[string]uint64),
+		pending:     make(map[string]time.Time),
+	}
+	c.config = autopaho.ClientConfig{
+		ServerUrls:                    []*url.URL{serverURL},
+		TlsCfg:                        tlsConfig,
+		KeepAlive:                     uint16(profile.KeepAlive / time.Second),
+		CleanStartOnInitialConnection: true,
+		ConnectRetryDelay:             10 * time.Second,
+		ConnectTimeout:                10 * time.Second,
+		ConnectUsername:               profile.Username,
+		ConnectPassword:               []byte(profile.Password),
+		OnConnectionUp: func(_ *autopaho.ConnectionManager, _ *paho.Connack) {
+			c.connected.Store(true)
+			utils.Logger.Info("MQTT 5 client connected")
+			if c.onConnect != nil {
+				go c.onConnect(c)
+			}
+		},
+		OnConnectError: func(err error) {
+			utils.Logger.Errorf("MQTT 5 connection attempt failed: %v", err)
+		},
+		ClientConfig: paho.ClientConfig{
+			ClientID:          profile.ClientID,
+			OnPublishReceived: []func(paho.PublishReceived) (bool, error){c.dispatch},
+			OnClientError: func(err error) {
+				c.connected.Store(false)
+				utils.Logger.Errorf("MQTT connection lost: %v", err)
+			},
+			OnServerDisconnect: func(d *paho.Disconnect) {
+				c.connected.Store(false)
+				utils.Logger.Errorf("MQTT broker disconnected the bridge: reason code 0x%02X (%s)", d.ReasonCode, disconnectReason(d))
+			},
+		},
+	}
+	return c, nil
+}
+
+// Connect 브로커 연결 (제한 시간 안에 연결되지 않으면 재시도를 멈추고 실패, 연결 후에는 자동 재연결)
+func (c *v5Client) Connect() mqtt.Token {
+	ctx, cancel := context.WithCancel(context.Background())
+	cm, err := autopaho.NewConnection(ctx, c.config)
+	if err != nil {
+		cancel()
+		return &doneToken{err: err}
+	}
+	waitCtx, waitCancel := context.WithTimeout(ctx, c.timeout)
+	defer waitCancel()
+	if err := cm.AwaitConnection(waitCtx); err != nil {
+		cancel()
+		return &doneToken{err: fmt.Errorf("no MQTT 5 connection within %v: %v", c.timeout, err)}
+	}
+
+	c.mu.Lock()
+	c.cm = cm
+	c.cancel = cancel
+	c.mu.Unlock()
+	return &doneToken{}
+}
+
+// Disconnect 연결 종료 (quiesce 밀리초까지 진행 중인 작업 대기)
+func (c *v5Client) Disconnect(quiesce uint) {
+	cm := c.manager()
+	if cm == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(quiesce)*time.Millisecond)
+	defer cancel()
+	_ = cm.Disconnect(ctx)
+	c.cancel()
+	c.connected.Store(false)
+}
+
+func (c *v5Client) IsConnected() bool      { return c.connected.Load() }
+func (c *v5Client) IsConnectionOpen() bool { return c.connected.Load() }
+
+// OptionsReader paho v1 옵션은 사용하지 않음
+func (c *v5Client) OptionsReader() mqtt.ClientOptionsReader { return mqtt.ClientOptionsReader{} }
+
+// Publish MQTT 5 속성을 붙여 발행 (QoS 1/2는 브로커의 사유 코드를 ReasonCodeError로 반환)
+func (c *v5Client) Publish(topic string, qos byte, retained bool, payload interface{}) mqtt.Token {
+	var data []byte
+	switch p := payload.(type) {
+	case []byte:
+		data = p
+	case string:
+		data = []byte(p)
+	case bytes.Buffer:
+		data = p.Bytes()
+	case *bytes.Buffer:
+		data = p.Bytes()
+	default:
+		return &doneToken{err: fmt.Errorf("unsupported payload type %T", payload)}
+	}
+	cm := c.manager()
+	if cm == nil {
+		return &doneToken{err: autopaho.ConnectionDownError}
+	}
+
+	publish := &paho.Publish{Topic: topic, QoS: qos, Retain: retained, Payload: data, Properties: c.publishProperties(topic, data)}
+	token := newAsyncToken()
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+		defer cancel()
+		resp, err := cm.Publish(ctx, publish)
+		if err != nil && resp != nil && resp.ReasonCode >= 0x80 {
+			reason := (&packets.Puback{ReasonCode: resp.ReasonCode}).Reason()
+			if resp.Properties != nil && resp.Properties.ReasonString != "" {
+				reason = resp.Properties.ReasonString
+			}
+			err = &ReasonCodeError{Operation: "publish", Topic: topic, ReasonCode: resp.ReasonCode, Reason: reason}
+		}
+		if err != nil {
+			c.recordFailure(err)
+		}
+		token.complete(err)
+	}()
+	return token
+}
+
+// publishProperties 토픽의 메시지 종류에 맞는 MQTT 5 속성 (붙일 속성이 없으면 nil)
+func (c *v5Client) publishProperties(topic string, payload []byte) *paho.PublishProperties {
+	switch topic[strings.LastIndex(topic, "/")+1:] {
+	case "order":
+		var properties *paho.PublishProperties
+		if c.orderExpiry > 0 {
+			expiry := uint32(c.orderExpiry / time.Second)
+			properties = &paho.PublishProperties{MessageExpiry: &expiry}
+		}
+		// 압축된 오더 (ORDER_COMPRESSION=gzip)
+		if bytes.HasPrefix(payload, []byte{0x1f, 0x8b}) {
+			if properties == nil {
+				properties = &paho.PublishProperties{}
+			}
+			properties.ContentType = "application/json"
+			properties.User.Add("content-encoding", "gzip")
+		}
+		return properties
+	case "instantActions":
+		actionID := factsheetRequestID(payload)
+		if actionID == "" {
+			return nil
+		}
+		c.statsMu.Lock()
+		now := time.Now()
+		for id, sentAt := range c.pending {
+			if now.Sub(sentAt) > factsheetRequestTTL {
+				delete(c.pending, id)
+			}
+		}
+		c.pending[actionID] = now
+		c.statsMu.Unlock()
+		c.factsheetReq.Add(1)
+		return &paho.PublishProperties{
+			ResponseTopic:   strings.TrimSuffix(topic, "instantActions") + "factsheet",
+			CorrelationData: []byte(actionID),
+		}
+	}
+	return nil
+}
+
+// factsheetRequestID instantActions 페이로드의 factsheetRequest actionId (없으면 빈 값)
+func factsheetRequestID(payload []byte) string {
+	if !bytes.Contains(payload, []byte(constants.ActionTypeFactsheetRequest)) {
+		return ""
+	}
+	var message struct {
+		Actions []struct {
+			ActionType string `json:"actionType"`
+			ActionID   string `json:"actionId"`
+		} `json:"actions"`
+	}
+	if err := json.Unmarshal(payload, &message); err != nil {
+		return ""
+	}
+	for _, action := range message.Actions {
+		if action.ActionType == constants.ActionTypeFactsheetRequest {
+			return action.ActionID
+		}
+	}
+	return ""
+}
+
+// Subscribe 필터 구독과 핸들러 등록
+func (c *v5Client) Subscribe(topic string, qos byte, callback mqtt.MessageHandler) mqtt.Token {
+	return c.SubscribeMultiple(map[string]byte{topic: qos}, callback)
+}
+
+// SubscribeMultiple 여러 필터 구독 (브로커가 거부한 필터는 사유 코드와 함께 실패)
+func (c *v5Client) SubscribeMultiple(filters map[string]byte, callback mqtt.MessageHandler) mqtt.Token {
+	cm := c.manager()
+	if cm == nil {
+		return &doneToken{err: autopaho.ConnectionDownError}
+	}
+	topics := make([]string, 0, len(filters))
+	for topic := range filters {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+
+	subscribe := &paho.Subscribe{}
+	c.mu.Lock()
+	for _, topic := range topics {
+		c.handlers[topic] = callback
+		subscribe.Subscriptions = append(subscribe.Subscriptions, paho.SubscribeOptions{Topic: topic, QoS: filters[topic]})
+	}
+	c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	suback, err := cm.Subscribe(ctx, subscribe)
+	if suback != nil {
+		for i, code := range suback.Reasons {
+			if code >= 0x80 && i < len(topics) {
+				return &doneToken{err: &ReasonCodeError{Operation: "subscribe", Topic: topics[i], ReasonCode: code,
+					Reason: (&packets.Suback{Reasons: []byte{code}}).Reason(0)}}
+			}
+		}
+	}
+	return &doneToken{err: err}
+}
+
+// Unsubscribe 필터 구독 해제
+func (c *v5Client) Unsubscribe(topics ...string) mqtt.Token {
+	c.mu.Lock()
+	for _, topic := range topics {
+		delete(c.handlers, topic)
+	}
+	c.mu.Unlock()
+
+	cm := c.manager()
+	if cm == nil {
+		return &doneToken{err: autopaho.ConnectionDownError}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	_, err := cm.Unsubscribe(ctx, &paho.Unsubscribe{Topics: topics})
+	return &doneToken{err: err}
+}
+
+// AddRoute 구독 없이 핸들러만 등록
+func (c *v5Client) AddRoute(topic string, callback mqtt.MessageHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers[topic] = callback
+}
+
+// dispatch 수신 메시지를 일치하는 필터의 핸들러에 전달 (팩트시트 응답은 correlation data로 요청과 짝 맞춤)
+func (c *v5Client) dispatch(received paho.PublishReceived) (bool, error) {
+	publish := received.Packet
+	if strings.HasSuffix(publish.Topic, "/factsheet") && publish.Properties != nil && len(publish.Properties.CorrelationData) > 0 {
+		c.matchFactsheetResponse(string(publish.Properties.CorrelationData))
+	}
+
+	c.mu.RLock()
+	var matched []mqtt.MessageHandler
+	for filter, handler := range c.handlers {
+		if handler != nil && topicMatches(filter, publish.Topic) {
+			matched = append(matched, handler)
+		}
+	}
+	c.mu.RUnlock()
+
+	msg := &v5Message{publish: publish}
+	for _, handler := range matched {
+		handler(c, msg)
+	}
+	return len(matched) > 0, nil
+}
+
+// matchFactsheetResponse 응답의 correlation data로 대기 중인 팩트시트 요청 확인
+func (c *v5Client) matchFactsheetResponse(actionID string) {
+	c.statsMu.Lock()
+	sentAt, ok := c.pending[actionID]
+	delete(c.pending, actionID)
+	c.statsMu.Unlock()
+	if !ok {
+		return
+	}
+	c.factsheetRes.Add(1)
+	utils.Logger.Infof("📄 Factsheet response for request %s received after %v", actionID, time.Since(sentAt).Round(time.Millisecond))
+}
+
+// recordFailure 발행 실패를 사유 코드별로 집계
+func (c *v5Client) recordFailure(err error) {
+	key := "none"
+	if reasonErr, ok := err.(*ReasonCodeError); ok {
+		key = fmt.Sprintf("0x%02X", reasonErr.ReasonCode)
+	}
+	c.statsMu.Lock()
+	c.failures[key]++
+	c.statsMu.Unlock()
+}
+
+// Stats MQTT 5 지표
+func (c *v5Client) Stats() ProtocolStats {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	failures := make(map[string]uint64, len(c.failures))
+	for code, count := range c.failures {
+		failures[code] = count
+	}
+	return ProtocolStats{
+		Version:            config.MQTTVersion5,
+		PublishFailures:    failures,
+		FactsheetRequests:  c.factsheetReq.Load(),
+		FactsheetResponses: c.factsheetRes.Load(),
+	}
+}
+
+func (c *v5Client) manager() *autopaho.ConnectionManager {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cm
+}
+
+// disconnectReason 브로커 DISCONNECT의 사유 설명
+func disconnectReason(d *paho.Disconnect) string {
+	if d.Properties != nil && d.Properties.ReasonString != "" {
+		return d.Properties.ReasonString
+	}
+	return d.Packet().Reason()
+}
+
+// v5Message MQTT 5 PUBLISH를 paho v1 mqtt.Message로 제공 (수신 확인은 autopaho가 자동 처리)
+type v5Message struct {
+	publish *paho.Publish
+}
+
+func (m *v5Message) Duplicate() bool   { return m.publish.Duplicate() }
+func (m *v5Message) Qos() byte         { return m.publish.QoS }
+func (m *v5Message) Retained() bool    { return m.publish.Retain }
+func (m *v5Message) Topic() string     { return m.publish.Topic }
+func (m *v5Message) MessageID() uint16 { return m.publish.PacketID }
+func (m *v5Message) Payload() []byte   { return m.publish.Payload }
+func (m *v5Message) Ack()              {}
+
+// asyncToken 발행이 끝나면 완료되는 mqtt.Token
+type asyncToken struct {
+	done chan struct{}
+	err  error
+}
+
+func newAsyncToken() *asyncToken {
+	return &asyncToken{done: make(chan struct{})}
+}
+
+func (t *asyncToken) complete(err error) {
+	t.err = err
+	close(t.done)
+}
+
+func (t *asyncToken) Wait() bool {
+	<-t.done
+	return true
+}
+
+func (t *asyncToken) WaitTimeout(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-t.done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+func (t *asyncToken) Done() <-chan struct{} { return t.done }
+
+func (t *asyncToken) Error() error {
+	select {
+	case <-t.done:
+		return t.err
+	default:
+		return nil
+	}
+}
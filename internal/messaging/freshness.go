@@ -0,0 +1,139 @@
+// internal/messaging/freshness.go
+package messaging
+
+import (
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/utils"
+	"sync"
+	"time"
+)
+
+// FreshnessStats 페이로드 종류별 타임스탬프 검사 지표
+type FreshnessStats struct {
+	Kind         string     `json:"kind"`
+	Checked      uint64     `json:"checked"`
+	Unparsable   uint64     `json:"unparsable"`    // timestamp가 없거나 RFC3339가 아님 (그대로 처리)
+	Skewed       uint64     `json:"skewed"`        // 브릿지 시각과 최대 허용 오차 이상 차이남 (그대로 처리)
+	StaleDropped uint64     `json:"stale_dropped"` // 마지막으로 처리한 메시지보다 오래되어 버림
+	LastDrop     string     `json:"last_drop,omitempty"`
+	LastTopic    string     `json:"last_topic,omitempty"`
+	LastDropAt   *time.Time `json:"last_drop_at,omitempty"`
+}
+
+// FreshnessReport 최대 허용 시각 오차와 종류별 지표
+type FreshnessReport struct {
+	MaxSkewSeconds float64          `json:"max_skew_seconds"` // 0이면 오차 검사 안 함
+	DropStale      bool             `json:"drop_stale"`
+	Kinds          []FreshnessStats `json:"kinds"`
+}
+
+// lastProcessed 로봇별 마지막으로 처리한 state 메시지의 헤더
+type lastProcessed struct {
+	timestamp time.Time
+	headerID  int64
+}
+
+// FreshnessGuard 수신 메시지의 VDA 5050 헤더 timestamp를 검사합니다.
+// 브릿지 시각과 maxSkew 이상 차이나는 메시지는 기록만 하고, state 메시지가 그 로봇에서 마지막으로 처리한
+// 메시지보다 오래되었으면(재연결 후 브로커가 보존/대기 메시지를 다시 보낸 경우) 상태가 뒤로 돌아가지 않도록 버립니다.
+// 로봇 시각 기준으로 비교하므로 로봇과 브릿지의 시계가 어긋나 있어도 순서 판단에는 영향이 없습니다.
+type FreshnessGuard struct {
+	maxSkew   time.Duration
+	dropStale bool
+
+	mu    sync.Mutex
+	last  map[string]lastProcessed // 시리얼 번호별
+	stats map[string]*FreshnessStats
+}
+
+// NewFreshnessGuard 타임스탬프 검사기 생성 (maxSkew가 0이면 오차 검사 안 함, dropStale이면 오래된 state 메시지를 버림)
+func NewFreshnessGuard(maxSkew time.Duration, dropStale bool) *FreshnessGuard {
+	g := &FreshnessGuard{
+		maxSkew:   maxSkew,
+		dropStale: dropStale,
+		last:      make(map[string]lastProcessed),
+		stats:     make(map[string]*FreshnessStats),
+	}
+	for _, kind := range PayloadKinds() {
+		g.stats[kind] = &FreshnessStats{Kind: kind}
+	}
+	utils.Logger.Infof("✅ Message freshness guard CREATED (max skew %v, drop stale state=%t)", maxSkew, dropStale)
+	return g
+}
+
+// Accept 헤더 timestamp를 검사하고 라우팅 여부를 반환 (오래된 state 메시지만 false)
+func (g *FreshnessGuard) Accept(kind, topic string, payload []byte) bool {
+	var header struct {
+		HeaderID     int64  `json:"headerId"`
+		Timestamp    string `json:"timestamp"`
+		SerialNumber string `json:"serialNumber"`
+	}
+	if err := json.Unmarshal(payload, &header); err != nil {
+		return true
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	stats := g.stats[kind]
+	if stats == nil {
+		return true
+	}
+	stats.Checked++
+
+	timestamp, err := time.Parse(time.RFC3339Nano, header.Timestamp)
+	if err != nil {
+		stats.Unparsable++
+		utils.Logger.Debugf("⏱️ %s message from %s has an unparsable timestamp %q", kind, topic, header.Timestamp)
+		return true
+	}
+
+	now := time.Now()
+	skew := now.Sub(timestamp)
+	if g.maxSkew > 0 && g.outsideSkew(skew) {
+		stats.Skewed++
+		utils.Logger.Warnf("⏱️ %s message from %s is %v off the bridge clock (max skew %v)",
+			kind, topic, skew.Round(time.Millisecond), g.maxSkew)
+	}
+
+	if kind != PayloadKindState || !g.dropStale || header.SerialNumber == "" {
+		return true
+	}
+	previous, seen := g.last[header.SerialNumber]
+	stale := seen && (timestamp.Before(previous.timestamp) ||
+		(timestamp.Equal(previous.timestamp) && header.HeaderID <= previous.headerID))
+	// 마지막 기준이 브릿지 시각보다 오차 이상 앞서 있었다면 로봇 시계가 되돌려진 것이므로 새 기준으로 받아들임
+	if stale && g.maxSkew > 0 && previous.timestamp.Sub(now) > g.maxSkew && !g.outsideSkew(skew) {
+		utils.Logger.Warnf("⏱️ Robot %s clock moved back from %s, accepting %s as the new baseline",
+			header.SerialNumber, previous.timestamp.Format(time.RFC3339Nano), header.Timestamp)
+		stale = false
+	}
+	if stale {
+		dropAt := now
+		stats.StaleDropped++
+		stats.LastDrop = fmt.Sprintf("%s headerId %d at %s", header.SerialNumber, header.HeaderID, header.Timestamp)
+		stats.LastTopic = topic
+		stats.LastDropAt = &dropAt
+		utils.Logger.Warnf("🗑️ Dropped stale state from %s (headerId %d, %s is older than last processed headerId %d, %s)",
+			topic, header.HeaderID, header.Timestamp, previous.headerID, previous.timestamp.Format(time.RFC3339Nano))
+		return false
+	}
+	g.last[header.SerialNumber] = lastProcessed{timestamp: timestamp, headerID: header.HeaderID}
+	return true
+}
+
+// outsideSkew 오차가 최대 허용 오차를 넘는지
+func (g *FreshnessGuard) outsideSkew(skew time.Duration) bool {
+	return skew > g.maxSkew || skew < -g.maxSkew
+}
+
+// Report 최대 허용 오차와 종류별 지표 반환
+func (g *FreshnessGuard) Report() FreshnessReport {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	report := FreshnessReport{MaxSkewSeconds: g.maxSkew.Seconds(), DropStale: g.dropStale}
+	for _, kind := range PayloadKinds() {
+		report.Kinds = append(report.Kinds, *g.stats[kind])
+	}
+	return report
+}
@@ -0,0 +1,92 @@
+// internal/messaging/http_ingest.go
+package messaging
+
+import (
+	"encoding/json"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// HTTP로 받을 수 있는 메시지 종류
+const (
+	HTTPIngestState      = "state"
+	HTTPIngestConnection = "connection"
+)
+
+// IngestedMessage HTTP로 받아 라우터에 넘긴 메시지
+type IngestedMessage struct {
+	Kind         string `json:"kind"`
+	SerialNumber string `json:"serial_number"`
+	HeaderID     int64  `json:"header_id"`
+	Topic        string `json:"topic"`  // MQTT로 받았다면 들어왔을 토픽
+	Queued       bool   `json:"queued"` // 수집 큐에 넣었으면 true, 바로 처리했으면 false
+}
+
+// HTTPIngest MQTT를 쓸 수 없는 로봇이 HTTP로 보낸 VDA 5050 state/connection 메시지를
+// 같은 토픽의 MQTT 메시지처럼 라우터(수집 큐가 있으면 큐)로 넘깁니다.
+type HTTPIngest struct {
+	router *Router
+	pool   *IngestPool
+	client mqtt.Client
+}
+
+// NewHTTPIngest HTTP 수신 메시지 전달기 생성 (pool이 nil이면 요청을 처리하는 고루틴에서 바로 라우팅)
+func NewHTTPIngest(router *Router, pool *IngestPool, client mqtt.Client) *HTTPIngest {
+	return &HTTPIngest{router: router, pool: pool, client: client}
+}
+
+// Submit VDA 5050 메시지 하나 전달 (헤더의 serialNumber, manufacturer가 없으면 검증 오류)
+func (i *HTTPIngest) Submit(kind string, payload []byte) (*IngestedMessage, error) {
+	if kind != HTTPIngestState && kind != HTTPIngestConnection {
+		return nil, apperr.Validation("kind", "unsupported ingest kind %q", kind)
+	}
+	var header struct {
+		HeaderID     int64  `json:"headerId"`
+		Manufacturer string `json:"manufacturer"`
+		SerialNumber string `json:"serialNumber"`
+	}
+	if err := json.Unmarshal(payload, &header); err != nil {
+		return nil, apperr.Validation("body", "invalid %s payload: %v", kind, err)
+	}
+	if strings.TrimSpace(header.SerialNumber) == "" {
+		return nil, apperr.Validation("serialNumber", "serialNumber is required")
+	}
+	if strings.TrimSpace(header.Manufacturer) == "" {
+		return nil, apperr.Validation("manufacturer", "manufacturer is required")
+	}
+	if strings.ContainsAny(header.SerialNumber+header.Manufacturer, "/+#") {
+		return nil, apperr.Validation("serialNumber", "serialNumber and manufacturer must not contain MQTT topic characters")
+	}
+
+	topic := constants.GetMeiliStateTopic(header.Manufacturer, header.SerialNumber)
+	if kind == HTTPIngestConnection {
+		topic = constants.GetMeiliConnectionTopic(header.Manufacturer, header.SerialNumber)
+	}
+	msg := &httpMessage{topic: topic, payload: payload}
+	ingested := &IngestedMessage{Kind: kind, SerialNumber: header.SerialNumber, HeaderID: header.HeaderID, Topic: topic}
+	if i.pool != nil {
+		i.pool.Submit(i.client, msg)
+		ingested.Queued = true
+	} else {
+		i.router.routeMessage(i.client, msg, time.Now())
+	}
+	return ingested, nil
+}
+
+// httpMessage HTTP로 받은 메시지의 mqtt.Message 구현
+type httpMessage struct {
+	topic   string
+	payload []byte
+}
+
+func (m *httpMessage) Duplicate() bool   { return false }
+func (m *httpMessage) Qos() byte         { return 0 }
+func (m *httpMessage) Retained() bool    { return false }
+func (m *httpMessage) Topic() string     { return m.topic }
+func (m *httpMessage) MessageID() uint16 { return 0 }
+func (m *httpMessage) Payload() []byte   { return m.payload }
+func (m *httpMessage) Ack()              {}
@@ -0,0 +1,241 @@
+// internal/messaging/ingest.go
+package messaging
+
+import (
+	"fmt"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/utils"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// 수집 큐 종류
+const (
+	IngestQueueCommand    = "command"
+	IngestQueueConnection = "connection"
+	IngestQueueState      = "state"
+	IngestQueueOther      = "other" // factsheet, order 등
+)
+
+// IngestOptions 수집 워커 풀 설정
+type IngestOptions struct {
+	CommandQueueSize    int
+	ConnectionQueueSize int
+	StateQueueSize      int // 상태 워커별 큐 크기
+	OtherQueueSize      int
+	StateWorkers        int // 상태 메시지 워커 수 (시리얼 번호 기준으로 분배하여 로봇별 순서 유지)
+}
+
+// QueueStats 수집 큐 지표
+type QueueStats struct {
+	Name       string `json:"name"`
+	Depth      int    `json:"depth"`
+	Capacity   int    `json:"capacity"`
+	DropOldest bool   `json:"drop_oldest"`
+	Enqueued   uint64 `json:"enqueued"`
+	Processed  uint64 `json:"processed"`
+	Dropped    uint64 `json:"dropped"`
+}
+
+// ingestItem 큐에 보관하는 수신 메시지
+type ingestItem struct {
+	client     mqtt.Client
+	msg        mqtt.Message
+	receivedAt time.Time
+}
+
+// ingestQueue 단일 워커가 처리하는 제한된 큐
+type ingestQueue struct {
+	name       string
+	ch         chan ingestItem
+	dropOldest bool
+
+	enqueued  uint64
+	processed uint64
+	dropped   uint64
+}
+
+// push 큐에 메시지 추가
+// dropOldest 큐는 가득 차면 가장 오래된 메시지를 버리고, 그 외 큐는 빈 자리가 날 때까지 대기합니다.
+func (q *ingestQueue) push(item ingestItem) {
+	atomic.AddUint64(&q.enqueued, 1)
+	if !q.dropOldest {
+		q.ch <- item
+		return
+	}
+	for {
+		select {
+		case q.ch <- item:
+			return
+		default:
+		}
+		select {
+		case old := <-q.ch:
+			atomic.AddUint64(&q.dropped, 1)
+			utils.Logger.Warnf("⚠️ Ingest queue %s full, dropped oldest message from %s", q.name, old.msg.Topic())
+		default:
+		}
+	}
+}
+
+func (q *ingestQueue) stats() QueueStats {
+	return QueueStats{
+		Name:       q.name,
+		Depth:      len(q.ch),
+		Capacity:   cap(q.ch),
+		DropOldest: q.dropOldest,
+		Enqueued:   atomic.LoadUint64(&q.enqueued),
+		Processed:  atomic.LoadUint64(&q.processed),
+		Dropped:    atomic.LoadUint64(&q.dropped),
+	}
+}
+
+// IngestPool MQTT 수신 메시지를 토픽 종류별 제한된 큐에 넣고 워커에서 라우팅합니다.
+// paho 콜백은 큐에 넣기만 하므로 DB가 느려져도 MQTT 클라이언트(keepalive 포함)가 멈추지 않습니다.
+//   - 명령/연결/기타: 단일 워커, 가득 차면 대기 (메시지를 버리지 않음)
+//   - 상태: 시리얼 번호별로 StateWorkers개 워커에 분배(utils.ShardIndex), 가득 차면 가장 오래된 메시지를 버림
+type IngestPool struct {
+	router      *Router
+	command     *ingestQueue
+	connection  *ingestQueue
+	other       *ingestQueue
+	stateShards []*ingestQueue
+
+	mu      sync.RWMutex
+	started bool
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// NewIngestPool 수집 워커 풀 생성 (0 이하 값은 기본값 사용)
+func NewIngestPool(router *Router, opts IngestOptions) *IngestPool {
+	if opts.CommandQueueSize <= 0 {
+		opts.CommandQueueSize = 100
+	}
+	if opts.ConnectionQueueSize <= 0 {
+		opts.ConnectionQueueSize = 100
+	}
+	if opts.StateQueueSize <= 0 {
+		opts.StateQueueSize = 100
+	}
+	if opts.OtherQueueSize <= 0 {
+		opts.OtherQueueSize = 100
+	}
+	if opts.StateWorkers <= 0 {
+		opts.StateWorkers = 1
+	}
+
+	pool := &IngestPool{
+		router:     router,
+		command:    newIngestQueue(IngestQueueCommand, opts.CommandQueueSize, false),
+		connection: newIngestQueue(IngestQueueConnection, opts.ConnectionQueueSize, false),
+		other:      newIngestQueue(IngestQueueOther, opts.OtherQueueSize, false),
+	}
+	for i := 0; i < opts.StateWorkers; i++ {
+		name := fmt.Sprintf("%s-%d", IngestQueueState, i)
+		pool.stateShards = append(pool.stateShards, newIngestQueue(name, opts.StateQueueSize, true))
+	}
+
+	utils.Logger.Infof("✅ Ingest pool CREATED (state workers=%d, state queue=%d, command queue=%d)",
+		opts.StateWorkers, opts.StateQueueSize, opts.CommandQueueSize)
+	return pool
+}
+
+func newIngestQueue(name string, size int, dropOldest bool) *ingestQueue {
+	return &ingestQueue{name: name, ch: make(chan ingestItem, size), dropOldest: dropOldest}
+}
+
+// queues 모든 큐 목록
+func (p *IngestPool) queues() []*ingestQueue {
+	queues := []*ingestQueue{p.command, p.connection, p.other}
+	return append(queues, p.stateShards...)
+}
+
+// Start 큐별 워커 시작
+func (p *IngestPool) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.started {
+		return
+	}
+	p.started = true
+	for _, q := range p.queues() {
+		p.wg.Add(1)
+		go p.work(q)
+	}
+	utils.Logger.Infof("✅ Ingest pool STARTED")
+}
+
+// work 큐의 메시지를 순서대로 라우팅
+func (p *IngestPool) work(q *ingestQueue) {
+	defer p.wg.Done()
+	for item := range q.ch {
+		p.router.routeMessage(item.client, item.msg, item.receivedAt)
+		atomic.AddUint64(&q.processed, 1)
+	}
+}
+
+// Submit 수신 메시지를 토픽 종류에 맞는 큐에 추가 (풀이 중지되었으면 버림)
+func (p *IngestPool) Submit(client mqtt.Client, msg mqtt.Message) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		utils.Logger.Warnf("⚠️ Ingest pool stopped, discarding message from %s", msg.Topic())
+		return
+	}
+	p.queueFor(msg.Topic()).push(ingestItem{client: client, msg: msg, receivedAt: time.Now()})
+}
+
+// queueFor 라우터와 같은 기준으로 토픽의 큐 선택
+func (p *IngestPool) queueFor(topic string) *ingestQueue {
+	switch {
+	case topic == constants.TopicBridgeCommand:
+		return p.command
+	case strings.Contains(topic, "/connection"):
+		return p.connection
+	case strings.Contains(topic, "/state"):
+		return p.stateShards[utils.ShardIndex(stateShardKey(topic), len(p.stateShards))]
+	default:
+		return p.other
+	}
+}
+
+// stateShardKey 상태 토픽의 시리얼 번호 (…/<serialNumber>/state, 형식이 다르면 토픽 전체)
+func stateShardKey(topic string) string {
+	parts := strings.Split(topic, "/")
+	if len(parts) >= 2 && parts[len(parts)-1] == "state" {
+		return parts[len(parts)-2]
+	}
+	return topic
+}
+
+// Stop 새 메시지 수신을 멈추고 큐에 남은 메시지를 처리한 뒤 종료
+func (p *IngestPool) Stop() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	for _, q := range p.queues() {
+		close(q.ch)
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+	utils.Logger.Infof("✅ Ingest pool STOPPED")
+}
+
+// QueueStats 큐별 깊이/처리/버림 지표
+func (p *IngestPool) QueueStats() []QueueStats {
+	queues := p.queues()
+	stats := make([]QueueStats, 0, len(queues))
+	for _, q := range queues {
+		stats = append(stats, q.stats())
+	}
+	return stats
+}
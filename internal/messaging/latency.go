@@ -0,0 +1,144 @@
+// internal/messaging/latency.go
+package messaging
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RobotLatency 로봇 state 메시지의 헤더 timestamp와 브릿지 수신 시각 차이 (최근 window개 기준, 밀리초)
+// 차이에는 네트워크 지연과 로봇/브릿지 시계 오차가 함께 들어 있습니다. 네트워크 지연은 0 이상이므로
+// 가장 작은 차이를 시계 오차 추정값(ClockOffsetMs, 음수면 로봇 시계가 앞섬)으로, 나머지를 네트워크 지연으로 봅니다.
+type RobotLatency struct {
+	SerialNumber   string    `json:"serial_number"`
+	Samples        int       `json:"samples"`
+	LastMs         float64   `json:"last_ms"`
+	MinMs          float64   `json:"min_ms"`
+	MeanMs         float64   `json:"mean_ms"`
+	P50Ms          float64   `json:"p50_ms"`
+	P95Ms          float64   `json:"p95_ms"`
+	MaxMs          float64   `json:"max_ms"`
+	ClockOffsetMs  float64   `json:"clock_offset_ms"`  // 추정 시계 오차 (MinMs)
+	NetworkP95Ms   float64   `json:"network_p95_ms"`   // 추정 네트워크 지연 p95 (P95Ms - MinMs)
+	LastReceivedAt time.Time `json:"last_received_at"` // 마지막 state 메시지 수신 시각
+}
+
+// MinLatencySamples 지연/시계 오차를 판정하기 위한 최소 표본 수 (재연결 직후 몇 개로 판단하지 않도록)
+const MinLatencySamples = 10
+
+// latencyWindow 로봇 하나의 최근 차이 (링 버퍼)
+type latencyWindow struct {
+	samples        []time.Duration
+	next           int
+	full           bool
+	lastReceivedAt time.Time
+}
+
+// LatencyTracker 로봇별 state 메시지 지연을 최근 window개씩 보관합니다.
+// 수신 시각은 수집 큐에 넣기 전에 기록하므로 큐 대기 시간은 포함되지 않습니다.
+type LatencyTracker struct {
+	window int
+
+	mu     sync.Mutex
+	robots map[string]*latencyWindow
+}
+
+// NewLatencyTracker 로봇별 최근 window개의 지연을 보관하는 추적기 생성
+func NewLatencyTracker(window int) *LatencyTracker {
+	if window <= 0 {
+		window = 100
+	}
+	return &LatencyTracker{window: window, robots: make(map[string]*latencyWindow)}
+}
+
+// Observe state 메시지 하나의 헤더 timestamp(RFC3339)와 수신 시각 기록 (timestamp를 해석할 수 없으면 무시)
+func (t *LatencyTracker) Observe(serialNumber, timestamp string, receivedAt time.Time) {
+	if serialNumber == "" {
+		return
+	}
+	sentAt, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	w, ok := t.robots[serialNumber]
+	if !ok {
+		w = &latencyWindow{samples: make([]time.Duration, t.window)}
+		t.robots[serialNumber] = w
+	}
+	w.samples[w.next] = receivedAt.Sub(sentAt)
+	w.next = (w.next + 1) % t.window
+	if w.next == 0 {
+		w.full = true
+	}
+	w.lastReceivedAt = receivedAt
+}
+
+// Latency 로봇의 지연 요약 (기록이 없으면 false)
+func (t *LatencyTracker) Latency(serialNumber string) (RobotLatency, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	w, ok := t.robots[serialNumber]
+	if !ok {
+		return RobotLatency{}, false
+	}
+	return w.summary(serialNumber), true
+}
+
+// Latencies 모든 로봇의 지연 요약 (시리얼 번호 순)
+func (t *LatencyTracker) Latencies() []RobotLatency {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	latencies := make([]RobotLatency, 0, len(t.robots))
+	for serial, w := range t.robots {
+		latencies = append(latencies, w.summary(serial))
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i].SerialNumber < latencies[j].SerialNumber })
+	return latencies
+}
+
+// summary 링 버퍼의 지연 요약
+func (w *latencyWindow) summary(serialNumber string) RobotLatency {
+	count := w.next
+	if w.full {
+		count = len(w.samples)
+	}
+	last := w.samples[(w.next+len(w.samples)-1)%len(w.samples)]
+	sorted := append([]time.Duration(nil), w.samples[:count]...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	latency := RobotLatency{
+		SerialNumber:   serialNumber,
+		Samples:        count,
+		LastMs:         milliseconds(last),
+		MinMs:          milliseconds(sorted[0]),
+		MeanMs:         milliseconds(total / time.Duration(count)),
+		P50Ms:          milliseconds(percentile(sorted, 0.50)),
+		P95Ms:          milliseconds(percentile(sorted, 0.95)),
+		MaxMs:          milliseconds(sorted[count-1]),
+		LastReceivedAt: w.lastReceivedAt,
+	}
+	latency.ClockOffsetMs = latency.MinMs
+	latency.NetworkP95Ms = latency.P95Ms - latency.MinMs
+	return latency
+}
+
+// percentile 정렬된 값의 백분위수 (nearest-rank)
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+func milliseconds(d time.Duration) float64 {
+	return math.Round(float64(d)/float64(time.Microsecond)) / 1000
+}
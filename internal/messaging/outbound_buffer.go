@@ -0,0 +1,343 @@
+// internal/messaging/outbound_buffer.go
+package messaging
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/utils"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+const (
+	outboundBufferFile          = "outbound.jsonl"
+	outboundBufferFlushInterval = time.Second      // 재연결 확인 간격
+	outboundBufferPublishWait   = 10 * time.Second // 전송 중 메시지 하나의 완료 대기 시간
+)
+
+// BufferStats 발행 버퍼 지표
+type BufferStats struct {
+	Messages int    `json:"messages"` // 보관 중인 메시지 수
+	Bytes    int64  `json:"bytes"`    // 보관 중인 페이로드 크기
+	Buffered uint64 `json:"buffered"` // 보관한 메시지 수 (누적)
+	Flushed  uint64 `json:"flushed"`  // 재연결 후 전송한 메시지 수
+	Expired  uint64 `json:"expired"`  // 최대 보관 시간이 지나 버린 메시지 수
+	Rejected uint64 `json:"rejected"` // 버퍼가 가득 차 거부한 메시지 수
+}
+
+// bufferedMessage 디스크에 보관하는 발행 메시지 (JSON Lines 한 줄)
+type bufferedMessage struct {
+	Topic    string    `json:"topic"`
+	QoS      byte      `json:"qos"`
+	Retained bool      `json:"retained"`
+	Payload  []byte    `json:"payload"`
+	QueuedAt time.Time `json:"queued_at"`
+}
+
+// OutboundBuffer 브로커 연결이 끊긴 동안 발행 메시지를 디스크에 보관하는 store-and-forward 버퍼
+// 보관 중인 메시지가 있으면 새 메시지도 뒤에 쌓아 순서를 유지하고, 재연결되면 보관 순서대로 전송합니다.
+// 전송 도중 중단되면 남은 메시지는 재시작 후 다시 전송되므로 일부 메시지가 두 번 전달될 수 있습니다 (at-least-once).
+type OutboundBuffer struct {
+	path     string
+	maxAge   time.Duration
+	maxBytes int64
+
+	mu       sync.Mutex
+	file     *os.File
+	messages []bufferedMessage
+	bytes    int64
+	stats    BufferStats
+
+	kick chan struct{}
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewOutboundBuffer 새 발행 버퍼 생성 (dir에 남아 있는 메시지를 불러옴)
+func NewOutboundBuffer(dir string, maxAge time.Duration, maxBytes int64) (*OutboundBuffer, error) {
+	if maxBytes <= 0 {
+		return nil, fmt.Errorf("MQTT buffer max bytes must be positive")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create MQTT buffer directory: %w", err)
+	}
+
+	b := &OutboundBuffer{
+		path:     filepath.Join(dir, outboundBufferFile),
+		maxAge:   maxAge,
+		maxBytes: maxBytes,
+		kick:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+	}
+	if err := b.load(); err != nil {
+		return nil, err
+	}
+	b.dropExpired(time.Now())
+	if err := b.rewrite(); err != nil {
+		return nil, err
+	}
+	if len(b.messages) > 0 {
+		utils.Logger.Warnf("📦 MQTT buffer restored %d message(s) (%d bytes) from %s", len(b.messages), b.bytes, b.path)
+	}
+	return b, nil
+}
+
+// Wrap 연결이 끊긴 동안의 발행을 버퍼에 보관하는 클라이언트로 감싸고 재연결 시 전송 루프 시작
+func (b *OutboundBuffer) Wrap(client mqtt.Client) mqtt.Client {
+	go b.run(client)
+	return &bufferedClient{Client: client, buffer: b}
+}
+
+// Stats 버퍼 지표
+func (b *OutboundBuffer) Stats() BufferStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	stats := b.stats
+	stats.Messages = len(b.messages)
+	stats.Bytes = b.bytes
+	return stats
+}
+
+// Close 전송 루프를 멈추고 파일을 닫음 (보관 중인 메시지는 다음 시작 때 전송)
+func (b *OutboundBuffer) Close() {
+	b.once.Do(func() {
+		close(b.stop)
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if b.file != nil {
+			b.file.Close()
+			b.file = nil
+		}
+	})
+}
+
+// load 파일에 남은 메시지 읽기 (마지막 줄이 잘려 있으면 무시)
+func (b *OutboundBuffer) load() error {
+	f, err := os.Open(b.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open MQTT buffer: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), int(b.maxBytes)*2+1024)
+	for scanner.Scan() {
+		var msg bufferedMessage
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			utils.Logger.Warnf("📦 Skipping unreadable MQTT buffer entry: %v", err)
+			continue
+		}
+		b.messages = append(b.messages, msg)
+		b.bytes += int64(len(msg.Payload))
+	}
+	return scanner.Err()
+}
+
+// rewrite 보관 중인 메시지로 파일을 다시 쓰고 추가 기록용으로 엶 (잠금 상태에서 호출)
+func (b *OutboundBuffer) rewrite() error {
+	if b.file != nil {
+		b.file.Close()
+		b.file = nil
+	}
+
+	tmp := b.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to write MQTT buffer: %w", err)
+	}
+	writer := bufio.NewWriter(f)
+	encoder := json.NewEncoder(writer)
+	for i := range b.messages {
+		if err := encoder.Encode(&b.messages[i]); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to write MQTT buffer: %w", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write MQTT buffer: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write MQTT buffer: %w", err)
+	}
+	f.Close()
+	if err := os.Rename(tmp, b.path); err != nil {
+		return fmt.Errorf("failed to replace MQTT buffer: %w", err)
+	}
+
+	b.file, err = os.OpenFile(b.path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open MQTT buffer: %w", err)
+	}
+	return nil
+}
+
+// enqueue 메시지를 파일 끝에 기록하고 보관 목록에 추가
+func (b *OutboundBuffer) enqueue(msg bufferedMessage) error {
+	size := int64(len(msg.Payload))
+	b.dropExpired(msg.QueuedAt)
+	if b.bytes+size > b.maxBytes {
+		b.stats.Rejected++
+		return fmt.Errorf("MQTT client is not connected and the outbound buffer is full (%d/%d bytes)", b.bytes, b.maxBytes)
+	}
+	if b.file == nil {
+		return fmt.Errorf("MQTT client is not connected and the outbound buffer is closed")
+	}
+
+	line, err := json.Marshal(&msg)
+	if err != nil {
+		return err
+	}
+	if _, err := b.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to buffer MQTT message: %w", err)
+	}
+	if err := b.file.Sync(); err != nil {
+		return fmt.Errorf("failed to buffer MQTT message: %w", err)
+	}
+
+	if len(b.messages) == 0 {
+		utils.Logger.Warnf("📦 MQTT broker unreachable, buffering outbound messages in %s", b.path)
+	}
+	b.messages = append(b.messages, msg)
+	b.bytes += size
+	b.stats.Buffered++
+	return nil
+}
+
+// dropExpired 최대 보관 시간이 지난 메시지를 앞에서부터 버림 (잠금 상태에서 호출, 파일은 다음 다시 쓰기에 반영)
+func (b *OutboundBuffer) dropExpired(now time.Time) {
+	if b.maxAge <= 0 {
+		return
+	}
+	expired := 0
+	for expired < len(b.messages) && now.Sub(b.messages[expired].QueuedAt) > b.maxAge {
+		b.bytes -= int64(len(b.messages[expired].Payload))
+		utils.Logger.Warnf("📦 Dropping buffered MQTT message to %s queued at %s (older than %s)",
+			b.messages[expired].Topic, b.messages[expired].QueuedAt.Format(time.RFC3339), b.maxAge)
+		expired++
+	}
+	if expired > 0 {
+		b.messages = b.messages[expired:]
+		b.stats.Expired += uint64(expired)
+	}
+}
+
+// run 연결되어 있고 보관 중인 메시지가 있으면 전송
+func (b *OutboundBuffer) run(client mqtt.Client) {
+	ticker := time.NewTicker(outboundBufferFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+		case <-b.kick:
+		}
+		if client.IsConnected() {
+			b.flush(client)
+		}
+	}
+}
+
+// flush 보관 순서대로 전송하고 전송한 메시지는 파일에서 제거 (실패하면 남은 메시지는 다음 주기에 재시도)
+// 전송 중에는 잠금을 유지하여 새 발행이 보관 메시지보다 먼저 나가지 않도록 합니다.
+func (b *OutboundBuffer) flush(client mqtt.Client) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.messages) == 0 || b.file == nil {
+		return
+	}
+
+	b.dropExpired(time.Now())
+	sent := 0
+	var failure error
+	for _, msg := range b.messages {
+		token := client.Publish(msg.Topic, msg.QoS, msg.Retained, msg.Payload)
+		if !token.WaitTimeout(outboundBufferPublishWait) {
+			failure = fmt.Errorf("timed out after %s", outboundBufferPublishWait)
+			break
+		}
+		if err := token.Error(); err != nil {
+			failure = err
+			break
+		}
+		b.bytes -= int64(len(msg.Payload))
+		sent++
+	}
+	b.messages = b.messages[sent:]
+	b.stats.Flushed += uint64(sent)
+
+	if err := b.rewrite(); err != nil {
+		utils.Logger.Errorf("❌ %v", err)
+	}
+	if failure != nil {
+		utils.Logger.Errorf("❌ MQTT buffer flush stopped after %d message(s), %d left: %v", sent, len(b.messages), failure)
+		return
+	}
+	utils.Logger.Infof("📦 MQTT buffer flushed %d message(s) after reconnect", sent)
+}
+
+// bufferedClient 연결이 끊겼거나 보관 중인 메시지가 있으면 발행을 버퍼에 보관하는 mqtt.Client
+type bufferedClient struct {
+	mqtt.Client
+	buffer *OutboundBuffer
+}
+
+// Publish 연결되어 있고 보관 중인 메시지가 없으면 바로 발행, 아니면 버퍼에 보관 (보관되면 완료된 토큰 반환)
+func (c *bufferedClient) Publish(topic string, qos byte, retained bool, payload interface{}) mqtt.Token {
+	b := c.buffer
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.messages) == 0 && c.Client.IsConnected() {
+		return c.Client.Publish(topic, qos, retained, payload)
+	}
+
+	var data []byte
+	switch p := payload.(type) {
+	case []byte:
+		data = p
+	case string:
+		data = []byte(p)
+	default:
+		return &doneToken{err: fmt.Errorf("unsupported payload type %T", payload)}
+	}
+	msg := bufferedMessage{Topic: topic, QoS: qos, Retained: retained, Payload: data, QueuedAt: time.Now()}
+	if err := b.enqueue(msg); err != nil {
+		return &doneToken{err: err}
+	}
+	select {
+	case b.kick <- struct{}{}:
+	default:
+	}
+	return &doneToken{}
+}
+
+// Buffering 연결이 끊겨도 발행을 받아 둘 수 있는지 (버퍼에 여유가 있으면 true)
+func (c *bufferedClient) Buffering() bool {
+	c.buffer.mu.Lock()
+	defer c.buffer.mu.Unlock()
+	return c.buffer.file != nil && c.buffer.bytes < c.buffer.maxBytes
+}
+
+// doneToken 즉시 완료되는 mqtt.Token
+type doneToken struct {
+	err error
+}
+
+func (t *doneToken) Wait() bool                     { return true }
+func (t *doneToken) WaitTimeout(time.Duration) bool { return true }
+func (t *doneToken) Done() <-chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}
+func (t *doneToken) Error() error { return t.err }
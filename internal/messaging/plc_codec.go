@@ -0,0 +1,229 @@
+// internal/messaging/plc_codec.go
+package messaging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// PLC 코덱 이름
+const (
+	PLCCodecText   = "text"
+	PLCCodecJSON   = "json"
+	PLCCodecBinary = "binary"
+)
+
+// PLCRequest PLC 명령 내용
+type PLCRequest struct {
+	Command       string            `json:"cmd"`
+	CorrelationID string            `json:"cid,omitempty"`
+	Params        map[string]string `json:"params,omitempty"` // 템플릿 자리표시자({{name}}) 치환 값
+}
+
+// PLCResponse PLC 응답 내용
+type PLCResponse struct {
+	Command       string `json:"cmd"`
+	Status        string `json:"status"`
+	Error         string `json:"error,omitempty"`
+	Code          string `json:"code,omitempty"`  // 실패 시 오류 코드 (apperr.Code)
+	Field         string `json:"field,omitempty"` // 검증 실패 시 문제가 된 필드
+	CorrelationID string `json:"cid,omitempty"`
+}
+
+// PLCCodec PLC 명령/응답 페이로드 인코딩 인터페이스
+type PLCCodec interface {
+	Name() string
+	DecodeCommand(payload []byte) (PLCRequest, error)
+	EncodeCommand(request PLCRequest) ([]byte, error)
+	EncodeResponse(response PLCResponse) ([]byte, error)
+	DecodeResponse(payload []byte) (PLCResponse, error)
+}
+
+// NewPLCCodec 이름으로 PLC 코덱 생성 (빈 값은 text)
+func NewPLCCodec(name string) (PLCCodec, error) {
+	switch strings.ToLower(name) {
+	case "", PLCCodecText:
+		return TextCodec{}, nil
+	case PLCCodecJSON:
+		return JSONCodec{}, nil
+	case PLCCodecBinary:
+		return BinaryCodec{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported PLC codec: %s", name)
+	}
+}
+
+// TextCodec 기존 "COMMAND:STATUS" 문자열 형식 (에러 메시지와 추적 ID는 전송하지 않음)
+// 명령에는 "CR?pallet_id=P1&count=2"처럼 쿼리 문자열로 템플릿 파라미터를 붙일 수 있음
+type TextCodec struct{}
+
+// Name 코덱 이름
+func (TextCodec) Name() string { return PLCCodecText }
+
+// DecodeCommand 명령 문자열과 선택적 쿼리 문자열 파라미터 파싱
+func (TextCodec) DecodeCommand(payload []byte) (PLCRequest, error) {
+	command, query, hasParams := strings.Cut(strings.TrimSpace(string(payload)), "?")
+	request := PLCRequest{Command: command}
+	if !hasParams {
+		return request, nil
+	}
+
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return PLCRequest{}, fmt.Errorf("invalid command parameters: %v", err)
+	}
+	request.Params = make(map[string]string, len(values))
+	for key := range values {
+		request.Params[key] = values.Get(key)
+	}
+	return request, nil
+}
+
+// EncodeCommand 명령 문자열 (파라미터가 있으면 쿼리 문자열로 추가)
+func (TextCodec) EncodeCommand(request PLCRequest) ([]byte, error) {
+	if len(request.Params) == 0 {
+		return []byte(request.Command), nil
+	}
+	values := url.Values{}
+	for key, value := range request.Params {
+		values.Set(key, value)
+	}
+	return []byte(request.Command + "?" + values.Encode()), nil
+}
+
+// EncodeResponse "COMMAND:STATUS" 형식
+func (TextCodec) EncodeResponse(response PLCResponse) ([]byte, error) {
+	return []byte(response.Command + ":" + response.Status), nil
+}
+
+// DecodeResponse 첫 번째 ':' 기준으로 명령과 상태 분리
+func (TextCodec) DecodeResponse(payload []byte) (PLCResponse, error) {
+	parts := strings.SplitN(strings.TrimSpace(string(payload)), ":", 2)
+	if len(parts) != 2 {
+		return PLCResponse{}, fmt.Errorf("invalid text response: %q", string(payload))
+	}
+	return PLCResponse{Command: parts[0], Status: parts[1]}, nil
+}
+
+// JSONCodec {"cmd":"CR","status":"S","error":"...","code":"...","field":"...","cid":"..."} 형식
+type JSONCodec struct{}
+
+// Name 코덱 이름
+func (JSONCodec) Name() string { return PLCCodecJSON }
+
+// DecodeCommand {"cmd":"CR","cid":"..."} 형식의 명령 파싱
+func (JSONCodec) DecodeCommand(payload []byte) (PLCRequest, error) {
+	var request PLCRequest
+	if err := json.Unmarshal(payload, &request); err != nil {
+		return PLCRequest{}, fmt.Errorf("invalid JSON command: %v", err)
+	}
+	request.Command = strings.TrimSpace(request.Command)
+	return request, nil
+}
+
+// EncodeCommand {"cmd":"CR","cid":"..."} 형식
+func (JSONCodec) EncodeCommand(request PLCRequest) ([]byte, error) {
+	return json.Marshal(request)
+}
+
+// EncodeResponse JSON 응답 생성
+func (JSONCodec) EncodeResponse(response PLCResponse) ([]byte, error) {
+	return json.Marshal(response)
+}
+
+// DecodeResponse JSON 응답 파싱
+func (JSONCodec) DecodeResponse(payload []byte) (PLCResponse, error) {
+	var response PLCResponse
+	if err := json.Unmarshal(payload, &response); err != nil {
+		return PLCResponse{}, fmt.Errorf("invalid JSON response: %v", err)
+	}
+	return response, nil
+}
+
+// 바이너리 프레임 필드 길이 (ASCII, 남는 바이트는 0으로 채움)
+const (
+	binaryCommandLen = 16
+	binaryStatusLen  = 8
+	binaryErrorLen   = 64
+
+	// BinaryCommandFrameLen 명령 프레임: command(16)
+	BinaryCommandFrameLen = binaryCommandLen
+	// BinaryResponseFrameLen 응답 프레임: command(16) + status(8) + error(64)
+	BinaryResponseFrameLen = binaryCommandLen + binaryStatusLen + binaryErrorLen
+)
+
+// BinaryCodec 고정 길이 바이너리 프레임 형식 (추적 ID와 파라미터는 전송하지 않음)
+type BinaryCodec struct{}
+
+// Name 코덱 이름
+func (BinaryCodec) Name() string { return PLCCodecBinary }
+
+// DecodeCommand 16바이트 명령 프레임 파싱
+func (BinaryCodec) DecodeCommand(payload []byte) (PLCRequest, error) {
+	if len(payload) != BinaryCommandFrameLen {
+		return PLCRequest{}, fmt.Errorf("invalid command frame length: %d (expected %d)", len(payload), BinaryCommandFrameLen)
+	}
+	return PLCRequest{Command: readField(payload)}, nil
+}
+
+// EncodeCommand 16바이트 명령 프레임 생성
+func (BinaryCodec) EncodeCommand(request PLCRequest) ([]byte, error) {
+	if len(request.Params) > 0 {
+		return nil, fmt.Errorf("binary codec does not support command parameters")
+	}
+	frame := make([]byte, BinaryCommandFrameLen)
+	if err := writeField(frame, request.Command, "command"); err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
+
+// EncodeResponse 88바이트 응답 프레임 생성
+// 오류 코드가 있으면 에러 필드를 "CODE: 메시지"로 채우며, 길이 초과 시 잘라냄
+func (BinaryCodec) EncodeResponse(response PLCResponse) ([]byte, error) {
+	frame := make([]byte, BinaryResponseFrameLen)
+	if err := writeField(frame[:binaryCommandLen], response.Command, "command"); err != nil {
+		return nil, err
+	}
+	if err := writeField(frame[binaryCommandLen:binaryCommandLen+binaryStatusLen], response.Status, "status"); err != nil {
+		return nil, err
+	}
+	errMsg := response.Error
+	if response.Code != "" {
+		errMsg = response.Code + ": " + errMsg
+	}
+	if len(errMsg) > binaryErrorLen {
+		errMsg = errMsg[:binaryErrorLen]
+	}
+	copy(frame[binaryCommandLen+binaryStatusLen:], errMsg)
+	return frame, nil
+}
+
+// DecodeResponse 88바이트 응답 프레임 파싱
+func (BinaryCodec) DecodeResponse(payload []byte) (PLCResponse, error) {
+	if len(payload) != BinaryResponseFrameLen {
+		return PLCResponse{}, fmt.Errorf("invalid response frame length: %d (expected %d)", len(payload), BinaryResponseFrameLen)
+	}
+	return PLCResponse{
+		Command: readField(payload[:binaryCommandLen]),
+		Status:  readField(payload[binaryCommandLen : binaryCommandLen+binaryStatusLen]),
+		Error:   readField(payload[binaryCommandLen+binaryStatusLen:]),
+	}, nil
+}
+
+// writeField 고정 길이 필드에 값 기록
+func writeField(field []byte, value, name string) error {
+	if len(value) > len(field) {
+		return fmt.Errorf("%s %q exceeds %d bytes", name, value, len(field))
+	}
+	copy(field, value)
+	return nil
+}
+
+// readField 고정 길이 필드에서 0 패딩 제거
+func readField(field []byte) string {
+	return strings.TrimSpace(string(bytes.TrimRight(field, "\x00")))
+}
@@ -0,0 +1,213 @@
+// internal/messaging/plc_codec_test.go
+package messaging
+
+import (
+	"bytes"
+	"mqtt-bridge/internal/common/apperr"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func allCodecs(t *testing.T) []PLCCodec {
+	t.Helper()
+	var codecs []PLCCodec
+	for _, name := range []string{PLCCodecText, PLCCodecJSON, PLCCodecBinary} {
+		codec, err := NewPLCCodec(name)
+		if err != nil {
+			t.Fatalf("NewPLCCodec(%s): %v", name, err)
+		}
+		if codec.Name() != name {
+			t.Fatalf("NewPLCCodec(%s).Name() = %s", name, codec.Name())
+		}
+		codecs = append(codecs, codec)
+	}
+	return codecs
+}
+
+func TestNewPLCCodec(t *testing.T) {
+	codec, err := NewPLCCodec("")
+	if err != nil || codec.Name() != PLCCodecText {
+		t.Fatalf("NewPLCCodec(\"\") = %v, %v, want text codec", codec, err)
+	}
+	if codec, err := NewPLCCodec("JSON"); err != nil || codec.Name() != PLCCodecJSON {
+		t.Fatalf("NewPLCCodec(JSON) = %v, %v, want json codec", codec, err)
+	}
+	if _, err := NewPLCCodec("modbus"); err == nil {
+		t.Fatal("expected an error for an unsupported codec")
+	}
+}
+
+func TestPLCCodecRoundTrip(t *testing.T) {
+	for _, codec := range allCodecs(t) {
+		t.Run(codec.Name(), func(t *testing.T) {
+			request := PLCRequest{Command: "CR"}
+			payload, err := codec.EncodeCommand(request)
+			if err != nil {
+				t.Fatalf("EncodeCommand: %v", err)
+			}
+			decoded, err := codec.DecodeCommand(payload)
+			if err != nil {
+				t.Fatalf("DecodeCommand(%q): %v", payload, err)
+			}
+			if !reflect.DeepEqual(decoded, request) {
+				t.Fatalf("command round trip = %+v, want %+v", decoded, request)
+			}
+
+			response := PLCResponse{Command: "CR", Status: "F", Error: "robot busy"}
+			payload, err = codec.EncodeResponse(response)
+			if err != nil {
+				t.Fatalf("EncodeResponse: %v", err)
+			}
+			got, err := codec.DecodeResponse(payload)
+			if err != nil {
+				t.Fatalf("DecodeResponse(%q): %v", payload, err)
+			}
+			// text 형식은 에러 메시지를 전송하지 않음
+			want := response
+			if codec.Name() == PLCCodecText {
+				want.Error = ""
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("response round trip = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestPLCCodecRoundTripParams(t *testing.T) {
+	request := PLCRequest{Command: "CR", CorrelationID: "cid-1", Params: map[string]string{"pallet_id": "P 1&2", "count": "2"}}
+
+	payload, err := TextCodec{}.EncodeCommand(request)
+	if err != nil {
+		t.Fatalf("text EncodeCommand: %v", err)
+	}
+	decoded, err := TextCodec{}.DecodeCommand(payload)
+	if err != nil {
+		t.Fatalf("text DecodeCommand: %v", err)
+	}
+	// text 형식은 추적 ID를 전송하지 않음
+	if want := (PLCRequest{Command: "CR", Params: request.Params}); !reflect.DeepEqual(decoded, want) {
+		t.Fatalf("text round trip = %+v, want %+v", decoded, want)
+	}
+
+	payload, err = JSONCodec{}.EncodeCommand(request)
+	if err != nil {
+		t.Fatalf("json EncodeCommand: %v", err)
+	}
+	if decoded, err = (JSONCodec{}).DecodeCommand(payload); err != nil || !reflect.DeepEqual(decoded, request) {
+		t.Fatalf("json round trip = %+v, %v, want %+v", decoded, err, request)
+	}
+
+	if _, err := (BinaryCodec{}).EncodeCommand(request); err == nil {
+		t.Fatal("binary codec should reject command parameters")
+	}
+}
+
+func TestPLCCodecTruncated(t *testing.T) {
+	tests := []struct {
+		name   string
+		decode func() error
+	}{
+		{"binary command", func() error {
+			_, err := BinaryCodec{}.DecodeCommand(make([]byte, BinaryCommandFrameLen-1))
+			return err
+		}},
+		{"binary response", func() error {
+			frame, _ := BinaryCodec{}.EncodeResponse(PLCResponse{Command: "CR", Status: "S"})
+			_, err := BinaryCodec{}.DecodeResponse(frame[:BinaryResponseFrameLen-1])
+			return err
+		}},
+		{"binary empty response", func() error {
+			_, err := BinaryCodec{}.DecodeResponse(nil)
+			return err
+		}},
+		{"json command", func() error {
+			_, err := JSONCodec{}.DecodeCommand([]byte(`{"cmd":"CR"`))
+			return err
+		}},
+		{"json response", func() error {
+			_, err := JSONCodec{}.DecodeResponse([]byte(`{"cmd":"CR","status":`))
+			return err
+		}},
+		{"text response without status", func() error {
+			_, err := TextCodec{}.DecodeResponse([]byte("CR"))
+			return err
+		}},
+		{"text command with bad parameters", func() error {
+			_, err := TextCodec{}.DecodeCommand([]byte("CR?count=%zz"))
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.decode(); err == nil {
+				t.Fatal("expected an error for a truncated payload")
+			}
+		})
+	}
+}
+
+func TestPLCCodecOversized(t *testing.T) {
+	long := strings.Repeat("X", binaryCommandLen+1)
+
+	if _, err := (BinaryCodec{}).DecodeCommand(make([]byte, BinaryCommandFrameLen+1)); err == nil {
+		t.Error("expected an error for an oversized command frame")
+	}
+	if _, err := (BinaryCodec{}).DecodeResponse(make([]byte, BinaryResponseFrameLen+1)); err == nil {
+		t.Error("expected an error for an oversized response frame")
+	}
+	if _, err := (BinaryCodec{}).EncodeCommand(PLCRequest{Command: long}); err == nil {
+		t.Error("expected an error for a command longer than the command field")
+	}
+	if _, err := (BinaryCodec{}).EncodeResponse(PLCResponse{Command: "CR", Status: strings.Repeat("S", binaryStatusLen+1)}); err == nil {
+		t.Error("expected an error for a status longer than the status field")
+	}
+
+	// 에러 필드는 실패 응답을 잃지 않도록 잘라서 전송
+	frame, err := BinaryCodec{}.EncodeResponse(PLCResponse{Command: "CR", Status: "F", Error: strings.Repeat("e", 2*binaryErrorLen)})
+	if err != nil {
+		t.Fatalf("EncodeResponse with a long error: %v", err)
+	}
+	if len(frame) != BinaryResponseFrameLen {
+		t.Fatalf("frame length = %d, want %d", len(frame), BinaryResponseFrameLen)
+	}
+	decoded, err := BinaryCodec{}.DecodeResponse(frame)
+	if err != nil {
+		t.Fatalf("DecodeResponse: %v", err)
+	}
+	if decoded.Error != strings.Repeat("e", binaryErrorLen) {
+		t.Fatalf("error field = %q, want %d bytes", decoded.Error, binaryErrorLen)
+	}
+}
+
+func TestPLCCodecUnknownCommand(t *testing.T) {
+	// 코덱은 명령을 해석하지 않고 그대로 전달 (정의되지 않은 명령은 핸들러가 COMMAND_NOT_FOUND로 응답)
+	frame := make([]byte, BinaryCommandFrameLen)
+	copy(frame, "ZZ9")
+	request, err := BinaryCodec{}.DecodeCommand(frame)
+	if err != nil || request.Command != "ZZ9" {
+		t.Fatalf("DecodeCommand(unknown) = %+v, %v, want command ZZ9", request, err)
+	}
+
+	response := PLCResponse{Command: "ZZ9", Status: "F", Error: "Command not defined or inactive", Code: string(apperr.CodeCommandNotFound)}
+	payload, err := BinaryCodec{}.EncodeResponse(response)
+	if err != nil {
+		t.Fatalf("EncodeResponse: %v", err)
+	}
+	decoded, err := BinaryCodec{}.DecodeResponse(payload)
+	if err != nil {
+		t.Fatalf("DecodeResponse: %v", err)
+	}
+	if want := string(apperr.CodeCommandNotFound) + ": Command not defined or inactive"; decoded.Error != want {
+		t.Fatalf("error field = %q, want %q", decoded.Error, want)
+	}
+
+	payload, err = JSONCodec{}.EncodeResponse(response)
+	if err != nil {
+		t.Fatalf("json EncodeResponse: %v", err)
+	}
+	if !bytes.Contains(payload, []byte(`"code":"`+string(apperr.CodeCommandNotFound)+`"`)) {
+		t.Fatalf("json response %s does not carry the error code", payload)
+	}
+}
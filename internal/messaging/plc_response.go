@@ -2,67 +2,151 @@
 package messaging
 
 import (
+	"context"
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
 	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/telemetry"
 	"mqtt-bridge/internal/utils"
 	"strings"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // PLCResponseSender PLC 응답 전용 전송기
 type PLCResponseSender struct {
-	client mqtt.Client
-	topic  string
+	client    mqtt.Client
+	topic     string
+	codec     PLCCodec
+	statusMap *PLCStatusMap // nil이면 기본 상태 문자 그대로 전송
+	recorder  PLCResponseRecorder
 }
 
-// NewPLCResponseSender PLC 응답 전송기 생성
+// PLCResponseRecorder 보낸 PLC 응답을 기록하는 인터페이스 (status는 상태 매핑 전 내부 상태)
+type PLCResponseRecorder interface {
+	RecordPLCResponse(status string, response PLCResponse, publishErr error)
+}
+
+// NewPLCResponseSender PLC 응답 전송기 생성 (기본 코덱: text)
 func NewPLCResponseSender(client mqtt.Client, topic string) *PLCResponseSender {
 	return &PLCResponseSender{
 		client: client,
 		topic:  topic,
+		codec:  TextCodec{},
 	}
 }
 
-// 직접 액션 응답 표준화
-func (p *PLCResponseSender) standardizeResponse(command, status string) string {
+// SetCodec 응답 페이로드 코덱 설정
+func (p *PLCResponseSender) SetCodec(codec PLCCodec) {
+	p.codec = codec
+	utils.Logger.Infof("✅ PLC Response Sender: %s codec set", codec.Name())
+}
+
+// SetStatusMap 내부 상태를 PLC가 기대하는 값으로 바꾸는 매핑 표 설정
+func (p *PLCResponseSender) SetStatusMap(statusMap *PLCStatusMap) {
+	p.statusMap = statusMap
+	utils.Logger.Infof("✅ PLC Response Sender: status map set %v", statusMap.Table())
+}
+
+// SetRecorder 보낸 응답을 명령 이력에 남길 기록기 설정
+func (p *PLCResponseSender) SetRecorder(recorder PLCResponseRecorder) {
+	p.recorder = recorder
+}
+
+// 직접 액션 명령을 기본 명령으로 표준화
+func (p *PLCResponseSender) standardizeCommand(command string) string {
 	// 직접 액션인지 확인
 	if strings.Contains(command, ":") {
 		// 직접 액션을 기본 명령으로 단순화
-		parts := strings.Split(command, ":")
-		if len(parts) > 0 {
-			baseCommand := parts[0]
-			standardized := baseCommand + ":" + status
-			utils.Logger.Infof("🔄 Response standardized: %s:%s → %s", command, status, standardized)
-			return standardized
-		}
+		baseCommand := strings.Split(command, ":")[0]
+		utils.Logger.Infof("🔄 Response standardized: %s → %s", command, baseCommand)
+		return baseCommand
 	}
 
 	// 표준 명령은 그대로
-	return command + ":" + status
+	return command
 }
 
 // SendResponse PLC에 응답 전송
 func (p *PLCResponseSender) SendResponse(command, status, errMsg string) error {
-	response := p.standardizeResponse(command, status)
+	return p.SendCorrelatedResponse("", command, status, errMsg)
+}
+
+// SendCorrelatedResponse 요청 추적 ID를 포함하여 PLC에 응답 전송
+func (p *PLCResponseSender) SendCorrelatedResponse(correlationID, command, status, errMsg string) error {
+	return p.send(PLCResponse{
+		Command:       p.standardizeCommand(command),
+		Status:        status,
+		Error:         errMsg,
+		CorrelationID: correlationID,
+	})
+}
+
+// SendError 구조화된 오류(코드, 필드 포함)로 PLC에 응답 전송
+// apperr.Error가 아닌 오류는 apperr.From 규칙에 따라 코드가 정해집니다.
+func (p *PLCResponseSender) SendError(correlationID, command, status string, cause error) error {
+	errResp := apperr.ToResponse(cause, correlationID)
+	return p.send(PLCResponse{
+		Command:       p.standardizeCommand(command),
+		Status:        status,
+		Error:         errResp.Message,
+		Code:          string(errResp.Code),
+		Field:         errResp.Field,
+		CorrelationID: errResp.CorrelationID,
+	})
+}
+
+// send 응답을 코덱으로 인코딩하여 발행
+func (p *PLCResponseSender) send(response PLCResponse) (err error) {
+	command, status, correlationID := response.Command, response.Status, response.CorrelationID
+	_, span := telemetry.StartPublishSpan(context.Background(), p.topic, "plc_response")
+	span.SetAttributes(
+		attribute.String("correlation.id", correlationID),
+		attribute.String("plc.command", command),
+		attribute.String("plc.status", status),
+	)
+	if response.Code != "" {
+		span.SetAttributes(attribute.String("error.code", response.Code))
+	}
+	defer func() { telemetry.EndSpan(span, err) }()
 
 	// 실패 시 에러 로그
-	if status == constants.StatusFailure && errMsg != "" {
-		utils.Logger.Errorf("Command %s failed: %s", command, errMsg)
+	if status == constants.StatusFailure && response.Error != "" {
+		utils.Logger.Errorf("Command %s failed (code=%s, cid=%s): %s", command, response.Code, correlationID, response.Error)
+	}
+
+	if p.statusMap != nil {
+		response.Status = p.statusMap.Encode(status)
+	}
+	payload, err := p.codec.EncodeResponse(response)
+	if err != nil {
+		utils.Logger.Errorf("Failed to encode PLC response: %v", err)
+		return err
 	}
 
-	utils.Logger.Infof("Sending response to PLC: %s", response)
+	utils.Logger.Infof("Sending response to PLC: %s:%s (%s, cid=%s)", response.Command, response.Status, p.codec.Name(), correlationID)
 
 	// MQTT 발행
-	token := p.client.Publish(p.topic, 0, false, response)
+	token := p.client.Publish(p.topic, 0, false, payload)
 	if token.Wait() && token.Error() != nil {
 		utils.Logger.Errorf("Failed to send response to PLC: %v", token.Error())
+		p.record(status, response, token.Error())
 		return token.Error()
 	}
+	p.record(status, response, nil)
 
-	utils.Logger.Infof("Response sent successfully to PLC: %s", response)
+	utils.Logger.Infof("Response sent successfully to PLC: %s:%s", response.Command, response.Status)
 	return nil
 }
 
+// record 기록기가 설정되어 있으면 보낸 응답 기록
+func (p *PLCResponseSender) record(status string, response PLCResponse, publishErr error) {
+	if p.recorder != nil {
+		p.recorder.RecordPLCResponse(status, response, publishErr)
+	}
+}
+
 // SendSuccess 성공 응답 전송
 func (p *PLCResponseSender) SendSuccess(command, message string) error {
 	return p.SendResponse(command, constants.StatusSuccess, message)
@@ -73,6 +157,11 @@ func (p *PLCResponseSender) SendFailure(command, errMsg string) error {
 	return p.SendResponse(command, constants.StatusFailure, errMsg)
 }
 
+// SendProgress 진행률 응답 전송 (예: "CR:R:2/5")
+func (p *PLCResponseSender) SendProgress(correlationID, command string, completed, total int) error {
+	return p.SendCorrelatedResponse(correlationID, command, fmt.Sprintf("%s:%d/%d", constants.StatusRunning, completed, total), "")
+}
+
 // SendRejected 거부 응답 전송
 func (p *PLCResponseSender) SendRejected(command, reason string) error {
 	return p.SendResponse(command, constants.StatusRejected, reason)
@@ -0,0 +1,230 @@
+// internal/messaging/plc_status.go
+package messaging
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mqtt-bridge/internal/common/constants"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// plcStatusLetters 내부 응답 상태 이름과 기본 전송 값
+var plcStatusLetters = map[string]string{
+	"SUCCESS":      constants.StatusSuccess,
+	"FAILURE":      constants.StatusFailure,
+	"REJECTED":     constants.StatusRejected,
+	"RUNNING":      constants.StatusRunning,
+	"ABNORMAL":     constants.StatusAbnormal,
+	"NORMAL":       constants.StatusNormal,
+	"ACKNOWLEDGED": constants.StatusAcknowledged,
+	"PAUSED":       constants.StatusPaused,
+}
+
+// plcStatusDefaultSection 상태 매핑 파일에서 모든 코덱에 적용되는 섹션 이름
+const plcStatusDefaultSection = "default"
+
+// PLCStatusMap 내부 응답 상태(SUCCESS, FAILURE 등)를 대상 PLC가 기대하는 값으로 바꾸는 매핑 표
+// 설정하지 않은 상태는 기본 문자(S, F, X, R, A, N, K, P)를 그대로 사용합니다.
+type PLCStatusMap struct {
+	codec string
+	mu    sync.RWMutex
+	wire  map[string]string // 기본 문자 → 전송 값
+	names map[string]string // 전송 값 → 기본 문자
+}
+
+// NewPLCStatusMap 상태 이름 → 전송 값 매핑으로 표 생성 (codec은 값 길이 검증에 사용)
+func NewPLCStatusMap(codec string, overrides map[string]string) (*PLCStatusMap, error) {
+	m := &PLCStatusMap{codec: strings.ToLower(codec)}
+	if err := m.Update(overrides); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// PLCStatusMapFromConfig 매핑 파일(default 섹션 + 코덱 섹션)과 PLC_STATUS_MAP 값을 차례로 적용한 표 생성
+func PLCStatusMapFromConfig(codec, spec, file string) (*PLCStatusMap, error) {
+	overrides := make(map[string]string)
+	if file != "" {
+		sections, err := readPLCStatusMapFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, section := range []string{plcStatusDefaultSection, strings.ToLower(codec)} {
+			for name, value := range sections[section] {
+				overrides[name] = value
+			}
+		}
+	}
+	specOverrides, err := ParsePLCStatusMap(spec)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range specOverrides {
+		overrides[name] = value
+	}
+	return NewPLCStatusMap(codec, overrides)
+}
+
+// ParsePLCStatusMap "SUCCESS=OK,FAILURE=NG" 형식 파싱
+func ParsePLCStatusMap(spec string) (map[string]string, error) {
+	overrides := make(map[string]string)
+	for _, entry := range strings.Split(spec, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid PLC status mapping %q: use NAME=VALUE", entry)
+		}
+		overrides[strings.ToUpper(strings.TrimSpace(name))] = strings.TrimSpace(value)
+	}
+	return overrides, nil
+}
+
+// Update 매핑 표 전체를 교체 (overrides에 없는 상태는 기본 문자로 돌아감)
+func (m *PLCStatusMap) Update(overrides map[string]string) error {
+	wire := make(map[string]string, len(plcStatusLetters))
+	names := make(map[string]string, len(plcStatusLetters))
+	for _, letter := range plcStatusLetters {
+		wire[letter] = letter
+	}
+	for name, value := range overrides {
+		letter, ok := plcStatusLetters[strings.ToUpper(name)]
+		if !ok {
+			return fmt.Errorf("unknown PLC status %q (%s)", name, strings.Join(PLCStatusNames(), ", "))
+		}
+		if err := m.validateValue(name, value); err != nil {
+			return err
+		}
+		wire[letter] = value
+	}
+	for letter, value := range wire {
+		if other, exists := names[value]; exists {
+			return fmt.Errorf("PLC status value %q is used by both %s and %s", value, statusName(other), statusName(letter))
+		}
+		names[value] = letter
+	}
+
+	m.mu.Lock()
+	m.wire, m.names = wire, names
+	m.mu.Unlock()
+	return nil
+}
+
+// validateValue 전송 값이 코덱 형식에 들어갈 수 있는지 확인
+func (m *PLCStatusMap) validateValue(name, value string) error {
+	if value == "" {
+		return fmt.Errorf("PLC status %s must not be empty", name)
+	}
+	if m.codec == "" || m.codec == PLCCodecText {
+		// text 코덱은 "COMMAND:STATUS" 형식이므로 구분자를 포함할 수 없음
+		if strings.Contains(value, ":") {
+			return fmt.Errorf("PLC status %s value %q must not contain ':' for the text codec", name, value)
+		}
+	}
+	if m.codec == PLCCodecBinary && len(value) > binaryStatusLen {
+		return fmt.Errorf("PLC status %s value %q exceeds %d bytes for the binary codec", name, value, binaryStatusLen)
+	}
+	return nil
+}
+
+// Encode 내부 상태 문자를 전송 값으로 변환 (진행률 "R:2/5"처럼 뒤에 붙은 값은 유지)
+func (m *PLCStatusMap) Encode(status string) string {
+	letter, suffix, hasSuffix := strings.Cut(status, ":")
+	m.mu.RLock()
+	value, ok := m.wire[letter]
+	m.mu.RUnlock()
+	if !ok {
+		return status
+	}
+	if hasSuffix {
+		return value + ":" + suffix
+	}
+	return value
+}
+
+// Decode 전송 값을 내부 상태 문자로 변환 (모르는 값은 그대로 반환)
+func (m *PLCStatusMap) Decode(status string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if letter, ok := m.names[status]; ok {
+		return letter
+	}
+	// 진행률처럼 뒤에 값이 붙은 응답 (text 코덱에서는 전송 값에 ':'가 없음)
+	if value, suffix, ok := strings.Cut(status, ":"); ok {
+		if letter, ok := m.names[value]; ok {
+			return letter + ":" + suffix
+		}
+	}
+	return status
+}
+
+// Table 상태 이름 → 현재 전송 값
+func (m *PLCStatusMap) Table() map[string]string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	table := make(map[string]string, len(plcStatusLetters))
+	for name, letter := range plcStatusLetters {
+		table[name] = m.wire[letter]
+	}
+	return table
+}
+
+// Codec 값 검증에 사용하는 코덱 이름
+func (m *PLCStatusMap) Codec() string {
+	return m.codec
+}
+
+// SavePLCStatusMapFile 매핑 파일의 코덱 섹션을 table로 교체 (다른 섹션은 유지)
+func SavePLCStatusMapFile(file, codec string, table map[string]string) error {
+	sections, err := readPLCStatusMapFile(file)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if sections == nil {
+		sections = make(map[string]map[string]string)
+	}
+	sections[strings.ToLower(codec)] = table
+
+	data, err := json.MarshalIndent(sections, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(data, '\n'), 0644)
+}
+
+// PLCStatusNames 매핑할 수 있는 상태 이름 목록
+func PLCStatusNames() []string {
+	names := make([]string, 0, len(plcStatusLetters))
+	for name := range plcStatusLetters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// readPLCStatusMapFile {"default": {...}, "<codec>": {...}} 형식의 매핑 파일 읽기
+func readPLCStatusMapFile(file string) (map[string]map[string]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PLC status map file: %w", err)
+	}
+	var sections map[string]map[string]string
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return nil, fmt.Errorf("invalid PLC status map file %s: %w", file, err)
+	}
+	return sections, nil
+}
+
+func statusName(letter string) string {
+	for name, l := range plcStatusLetters {
+		if l == letter {
+			return name
+		}
+	}
+	return letter
+}
@@ -0,0 +1,164 @@
+// internal/messaging/profile_client.go
+package messaging
+
+import (
+	"mqtt-bridge/internal/utils"
+	"strings"
+	"sync"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// profileClient 브로커 프로파일 제약을 적용하는 MQTT 클라이언트 래퍼
+// 내부 코드는 기존 토픽을 그대로 사용하고, 브로커 토픽 변환과 QoS/retain 제한은 여기서만 처리
+type profileClient struct {
+	mqtt.Client
+	profile  *BrokerProfile
+	mapper   TopicMapper
+	handlers map[string]mqtt.MessageHandler // 내부 토픽 필터 → 핸들러
+	mu       sync.RWMutex
+}
+
+// Publish 토픽 변환 후 발행
+func (c *profileClient) Publish(topic string, qos byte, retained bool, payload interface{}) mqtt.Token {
+	return c.Client.Publish(c.mapper.OutboundTopic(topic), c.profile.clampQoS(qos), retained && c.profile.AllowRetain, payload)
+}
+
+// Subscribe 내부 필터를 등록하고 변환된 필터로 구독
+func (c *profileClient) Subscribe(topic string, qos byte, callback mqtt.MessageHandler) mqtt.Token {
+	c.addHandler(topic, callback)
+	return c.Client.Subscribe(c.remoteFilter(topic), c.profile.clampQoS(qos), c.dispatch)
+}
+
+// remoteFilter 내부 필터를 브로커 필터로 변환 (공유 구독은 $share/<그룹>/ 접두사를 유지하고 토픽만 변환)
+func (c *profileClient) remoteFilter(filter string) string {
+	if group, topic, ok := splitSharedFilter(filter); ok {
+		return "$share/" + group + "/" + c.mapper.InboundFilter(topic)
+	}
+	return c.mapper.InboundFilter(filter)
+}
+
+// SubscribeMultiple 여러 필터 구독
+func (c *profileClient) SubscribeMultiple(filters map[string]byte, callback mqtt.MessageHandler) mqtt.Token {
+	remoteFilters := make(map[string]byte, len(filters))
+	for topic, qos := range filters {
+		c.addHandler(topic, callback)
+		remote := c.remoteFilter(topic)
+		q := c.profile.clampQoS(qos)
+		if existing, ok := remoteFilters[remote]; !ok || q > existing {
+			remoteFilters[remote] = q
+		}
+	}
+	return c.Client.SubscribeMultiple(remoteFilters, c.dispatch)
+}
+
+// Unsubscribe 내부 필터 해제 (다른 필터가 공유하는 브로커 필터는 유지)
+func (c *profileClient) Unsubscribe(topics ...string) mqtt.Token {
+	c.mu.Lock()
+	for _, topic := range topics {
+		delete(c.handlers, topic)
+	}
+	inUse := make(map[string]bool)
+	for filter := range c.handlers {
+		inUse[c.remoteFilter(filter)] = true
+	}
+	c.mu.Unlock()
+
+	var remotes []string
+	seen := make(map[string]bool)
+	for _, topic := range topics {
+		remote := c.remoteFilter(topic)
+		if !inUse[remote] && !seen[remote] {
+			remotes = append(remotes, remote)
+			seen[remote] = true
+		}
+	}
+	return c.Client.Unsubscribe(remotes...)
+}
+
+// AddRoute 구독 없이 핸들러만 등록
+func (c *profileClient) AddRoute(topic string, callback mqtt.MessageHandler) {
+	c.addHandler(topic, callback)
+	c.Client.AddRoute(c.remoteFilter(topic), c.dispatch)
+}
+
+// addHandler 내부 필터 핸들러 등록
+func (c *profileClient) addHandler(topic string, callback mqtt.MessageHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers[topic] = callback
+}
+
+// dispatch 수신 토픽을 내부 토픽으로 복원하여 일치하는 핸들러에 전달
+func (c *profileClient) dispatch(_ mqtt.Client, msg mqtt.Message) {
+	local, ok := c.mapper.LocalTopic(msg.Topic())
+	if !ok {
+		utils.Logger.Warnf("⚠️ Dropping message on unmapped %s topic: %s", c.profile.Name, msg.Topic())
+		return
+	}
+
+	c.mu.RLock()
+	var matched []mqtt.MessageHandler
+	for filter, handler := range c.handlers {
+		if handler != nil && topicMatches(filter, local) {
+			matched = append(matched, handler)
+		}
+	}
+	c.mu.RUnlock()
+
+	if len(matched) == 0 {
+		utils.Logger.Warnf("⚠️ No handler for %s message: %s", c.profile.Name, local)
+		return
+	}
+
+	localMsg := &mappedMessage{Message: msg, topic: local}
+	for _, handler := range matched {
+		handler(c, localMsg)
+	}
+}
+
+// mappedMessage 내부 토픽으로 복원된 메시지
+type mappedMessage struct {
+	mqtt.Message
+	topic string
+}
+
+// Topic 내부 토픽 반환
+func (m *mappedMessage) Topic() string {
+	return m.topic
+}
+
+// topicMatches MQTT 와일드카드(+, #) 필터 일치 여부 (공유 구독 필터는 $share/<그룹>/ 뒤의 토픽으로 비교)
+func topicMatches(filter, topic string) bool {
+	if _, shared, ok := splitSharedFilter(filter); ok {
+		filter = shared
+	}
+	filterLevels := strings.Split(filter, "/")
+	topicLevels := strings.Split(topic, "/")
+
+	for i, level := range filterLevels {
+		if level == "#" {
+			return true
+		}
+		if i >= len(topicLevels) {
+			return false
+		}
+		if level != "+" && level != topicLevels[i] {
+			return false
+		}
+	}
+	return len(filterLevels) == len(topicLevels)
+}
+
+// splitSharedFilter 공유 구독 필터($share/<그룹>/<토픽>)의 그룹과 토픽 (공유 구독이 아니면 false)
+func splitSharedFilter(filter string) (group, topic string, ok bool) {
+	rest, ok := strings.CutPrefix(filter, "$share/")
+	if !ok {
+		return "", filter, false
+	}
+	group, topic, ok = strings.Cut(rest, "/")
+	if !ok {
+		return "", filter, false
+	}
+	return group, topic, true
+}
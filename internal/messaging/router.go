@@ -3,9 +3,14 @@ package messaging
 
 import (
 	"encoding/json"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/diagnostics"
 	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/sink"
 	"mqtt-bridge/internal/utils"
 	"strings"
+	"sync"
+	"time"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 )
@@ -29,11 +34,25 @@ type WorkflowHandler interface {
 	HandleOrderStateUpdate(stateMsg *models.RobotStateMessage)
 }
 
+// TrafficRecorder 라우팅되는 MQTT 메시지 기록기 (리플레이용)
+type TrafficRecorder interface {
+	Record(topic string, payload []byte)
+}
+
 // Router 메시지 라우터
 type Router struct {
 	commandHandler  CommandHandler
 	robotHandler    RobotHandler
 	workflowHandler WorkflowHandler
+	stateSink       sink.StateSink
+	recorder        TrafficRecorder
+	validator       *SchemaValidator
+	freshness       *FreshnessGuard
+	latency         *LatencyTracker
+	clock           *diagnostics.ClockAudit
+
+	lastStateAt map[string]time.Time // 로봇별 마지막 상태 메시지 수신 시각
+	stateMu     sync.RWMutex
 }
 
 // NewRouter 새 메시지 라우터 생성
@@ -44,32 +63,128 @@ func NewRouter(commandHandler CommandHandler, robotHandler RobotHandler, workflo
 		commandHandler:  commandHandler,
 		robotHandler:    robotHandler,
 		workflowHandler: workflowHandler,
+		lastStateAt:     make(map[string]time.Time),
 	}
 
 	utils.Logger.Infof("✅ Message Router CREATED")
 	return router
 }
 
+// SetStateSink 로봇 상태/연결 메시지를 전달할 외부 싱크 설정
+func (r *Router) SetStateSink(stateSink sink.StateSink) {
+	r.stateSink = stateSink
+	utils.Logger.Infof("✅ Message Router: State sink set")
+}
+
+// SetRecorder 라우팅되는 메시지를 기록할 기록기 설정
+func (r *Router) SetRecorder(recorder TrafficRecorder) {
+	r.recorder = recorder
+	utils.Logger.Infof("✅ Message Router: Traffic recorder set")
+}
+
+// SetSchemaValidator 상태/연결/팩트시트 페이로드 스키마 검증기 설정
+func (r *Router) SetSchemaValidator(validator *SchemaValidator) {
+	r.validator = validator
+	utils.Logger.Infof("✅ Message Router: Payload schema validator set (mode=%s)", validator.Mode())
+}
+
+// SchemaReport 페이로드 스키마 검증 지표 (검증기가 없으면 nil)
+func (r *Router) SchemaReport() *SchemaReport {
+	if r.validator == nil {
+		return nil
+	}
+	report := r.validator.Report()
+	return &report
+}
+
+// SetFreshnessGuard 헤더 timestamp 오차 검사와 오래된 state 메시지 필터 설정
+func (r *Router) SetFreshnessGuard(guard *FreshnessGuard) {
+	r.freshness = guard
+	utils.Logger.Infof("✅ Message Router: Freshness guard set")
+}
+
+// SetLatencyTracker 로봇별 state 메시지 지연(헤더 timestamp와 수신 시각 차이) 추적기 설정
+func (r *Router) SetLatencyTracker(tracker *LatencyTracker) {
+	r.latency = tracker
+	utils.Logger.Infof("✅ Message Router: State latency tracker set")
+}
+
+// SetClockAudit 로봇 메시지와 PLC 하트비트 timestamp를 브릿지 시계와 비교할 감사기 설정 (설정하면 하트비트도 구독)
+func (r *Router) SetClockAudit(audit *diagnostics.ClockAudit) {
+	r.clock = audit
+	utils.Logger.Infof("✅ Message Router: Clock audit set")
+}
+
+// FreshnessReport 타임스탬프 검사 지표 (검사기가 없으면 nil)
+func (r *Router) FreshnessReport() *FreshnessReport {
+	if r.freshness == nil {
+		return nil
+	}
+	report := r.freshness.Report()
+	return &report
+}
+
+// acceptPayload 스키마 검증과 타임스탬프 검사 결과에 따라 메시지를 라우팅할지 결정
+func (r *Router) acceptPayload(kind string, msg mqtt.Message) bool {
+	if r.validator != nil && !r.validator.Accept(kind, msg.Topic(), msg.Payload()) {
+		return false
+	}
+	if r.freshness != nil && !r.freshness.Accept(kind, msg.Topic(), msg.Payload()) {
+		return false
+	}
+	return true
+}
+
 // RouteMessage 토픽에 따라 메시지 라우팅
 func (r *Router) RouteMessage(client mqtt.Client, msg mqtt.Message) {
+	r.routeMessage(client, msg, time.Now())
+}
+
+// routeMessage 토픽에 따라 메시지 라우팅 (receivedAt: 브로커에서 받은 시각, 수집 큐 대기 전)
+func (r *Router) routeMessage(client mqtt.Client, msg mqtt.Message, receivedAt time.Time) {
 	topic := msg.Topic()
 	utils.Logger.Debugf("Routing message from topic: %s", topic)
 
+	if r.recorder != nil {
+		r.recorder.Record(topic, msg.Payload())
+	}
+
 	// 토픽 패턴에 따라 라우팅
 	switch {
-	case topic == "bridge/command":
+	case topic == constants.TopicBridgeCommand:
 		utils.Logger.Infof("🎯 ROUTING to Command Handler")
 		r.commandHandler.HandlePLCCommand(client, msg)
 
+	case topic == constants.TopicBridgeHeartbeat:
+		if r.clock == nil {
+			return
+		}
+		if err := r.clock.ObserveHeartbeat(msg.Payload(), receivedAt); err != nil {
+			utils.Logger.Warnf("⚠️ Ignoring PLC heartbeat: %v", err)
+		}
+
 	case strings.Contains(topic, "/connection"):
+		if !r.acceptPayload(PayloadKindConnection, msg) {
+			return
+		}
 		utils.Logger.Infof("🔗 ROUTING to Robot Connection Handler")
 		r.robotHandler.HandleConnectionState(client, msg)
+		r.observeConnectionClock(msg, receivedAt)
+		r.forwardToSink(sink.KindConnection, msg)
 
 	case strings.Contains(topic, "/state"):
+		if !r.acceptPayload(PayloadKindState, msg) {
+			return
+		}
 		utils.Logger.Infof("📊 ROUTING to Robot State Handler")
-		r.handleRobotState(client, msg)
+		r.recordStateReceived(topic)
+		r.handleRobotState(client, msg, receivedAt)
+		r.forwardToSink(sink.KindState, msg)
 
 	case strings.Contains(topic, "/factsheet"):
+		if !r.acceptPayload(PayloadKindFactsheet, msg) {
+			return
+		}
 		utils.Logger.Infof("📋 ROUTING to Robot Factsheet Handler")
 		r.robotHandler.HandleFactsheet(client, msg)
 
@@ -83,7 +198,7 @@ func (r *Router) RouteMessage(client mqtt.Client, msg mqtt.Message) {
 }
 
 // handleRobotState 로봇 상태 메시지를 여러 핸들러에 분배
-func (r *Router) handleRobotState(client mqtt.Client, msg mqtt.Message) {
+func (r *Router) handleRobotState(client mqtt.Client, msg mqtt.Message, receivedAt time.Time) {
 	// 로봇 핸들러에서 기본 처리
 	r.robotHandler.HandleRobotState(client, msg)
 
@@ -93,6 +208,12 @@ func (r *Router) handleRobotState(client mqtt.Client, msg mqtt.Message) {
 		utils.Logger.Errorf("Failed to parse robot state message: %v", err)
 		return
 	}
+	if r.latency != nil {
+		r.latency.Observe(stateMsg.SerialNumber, stateMsg.Timestamp, receivedAt)
+	}
+	if r.clock != nil {
+		r.clock.ObserveTimestamp(diagnostics.DeviceRobot, stateMsg.SerialNumber, stateMsg.Timestamp, receivedAt)
+	}
 
 	// 각 핸들러에 상태 업데이트 전달
 	if r.commandHandler != nil {
@@ -107,3 +228,57 @@ func (r *Router) handleRobotState(client mqtt.Client, msg mqtt.Message) {
 		r.robotHandler.CheckAndRequestInitPosition(&stateMsg)
 	}
 }
+
+// observeConnectionClock connection 메시지 헤더 timestamp를 시계 감사에 기록
+func (r *Router) observeConnectionClock(msg mqtt.Message, receivedAt time.Time) {
+	if r.clock == nil {
+		return
+	}
+	var header struct {
+		SerialNumber string `json:"serialNumber"`
+		Timestamp    string `json:"timestamp"`
+	}
+	if err := json.Unmarshal(msg.Payload(), &header); err != nil {
+		return
+	}
+	r.clock.ObserveTimestamp(diagnostics.DeviceRobot, header.SerialNumber, header.Timestamp, receivedAt)
+}
+
+// recordStateReceived 토픽의 시리얼 번호 기준으로 상태 메시지 수신 시각 기록
+func (r *Router) recordStateReceived(topic string) {
+	parts := strings.Split(topic, "/")
+	if len(parts) < 5 {
+		return
+	}
+	r.stateMu.Lock()
+	r.lastStateAt[parts[3]] = time.Now()
+	r.stateMu.Unlock()
+}
+
+// LastStateTimes 로봇별 마지막 상태 메시지 수신 시각 반환
+func (r *Router) LastStateTimes() map[string]time.Time {
+	r.stateMu.RLock()
+	defer r.stateMu.RUnlock()
+	times := make(map[string]time.Time, len(r.lastStateAt))
+	for serial, t := range r.lastStateAt {
+		times[serial] = t
+	}
+	return times
+}
+
+// forwardToSink 설정된 외부 싱크로 메시지 전달
+func (r *Router) forwardToSink(kind string, msg mqtt.Message) {
+	if r.stateSink == nil {
+		return
+	}
+
+	record, err := sink.NewRecord(kind, msg.Topic(), msg.Payload())
+	if err != nil {
+		utils.Logger.Warnf("Skipping state sink forward: %v", err)
+		return
+	}
+
+	if err := r.stateSink.Publish(record); err != nil {
+		utils.Logger.Errorf("❌ Failed to forward %s message to state sink: %v", kind, err)
+	}
+}
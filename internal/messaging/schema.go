@@ -0,0 +1,407 @@
+// internal/messaging/schema.go
+package messaging
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"mqtt-bridge/internal/utils"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 페이로드 검증 모드
+const (
+	SchemaModeOff     = "off"     // 검증하지 않음
+	SchemaModeLenient = "lenient" // 위반을 기록하고 메시지는 그대로 처리
+	SchemaModeStrict  = "strict"  // 위반 메시지는 라우팅하지 않고 버림
+)
+
+// 스키마로 검증하는 수신 페이로드 종류
+const (
+	PayloadKindState      = "state"
+	PayloadKindConnection = "connection"
+	PayloadKindFactsheet  = "factsheet"
+)
+
+// Schema VDA 5050 JSON 스키마의 검증용 부분 집합 (type, required, properties, items, enum)
+type Schema struct {
+	Type       string             // object, array, string, integer, number, boolean
+	Required   []string           // object의 필수 필드
+	Properties map[string]*Schema // object 필드별 스키마 (정의되지 않은 필드는 허용)
+	Items      *Schema            // array 요소 스키마
+	Enum       []string           // string 허용 값
+}
+
+// SchemaViolation 스키마 위반 항목
+type SchemaViolation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (v SchemaViolation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// Validate 값이 스키마를 만족하는지 검사하여 위반 목록 반환
+func (s *Schema) Validate(value interface{}) []SchemaViolation {
+	var violations []SchemaViolation
+	s.validate("$", value, &violations)
+	return violations
+}
+
+func (s *Schema) validate(path string, value interface{}, violations *[]SchemaViolation) {
+	add := func(format string, args ...interface{}) {
+		*violations = append(*violations, SchemaViolation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch s.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			add("expected object, got %s", jsonTypeOf(value))
+			return
+		}
+		for _, field := range s.Required {
+			if _, exists := object[field]; !exists {
+				*violations = append(*violations, SchemaViolation{Path: path + "." + field, Message: "required field missing"})
+			}
+		}
+		fields := make([]string, 0, len(s.Properties))
+		for field := range s.Properties {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			if child, exists := object[field]; exists {
+				s.Properties[field].validate(path+"."+field, child, violations)
+			}
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			add("expected array, got %s", jsonTypeOf(value))
+			return
+		}
+		if s.Items != nil {
+			for i, item := range array {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, violations)
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			add("expected string, got %s", jsonTypeOf(value))
+			return
+		}
+		if len(s.Enum) > 0 && !containsString(s.Enum, str) {
+			add("value %q is not one of %s", str, strings.Join(s.Enum, ", "))
+		}
+	case "integer":
+		number, ok := value.(float64)
+		if !ok || number != math.Trunc(number) {
+			add("expected integer, got %s", jsonTypeOf(value))
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			add("expected number, got %s", jsonTypeOf(value))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			add("expected boolean, got %s", jsonTypeOf(value))
+		}
+	}
+}
+
+// jsonTypeOf 디코딩된 JSON 값의 타입 이름
+func jsonTypeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// 스키마 구성 도우미
+func object(required []string, properties map[string]*Schema) *Schema {
+	return &Schema{Type: "object", Required: required, Properties: properties}
+}
+
+func arrayOf(items *Schema) *Schema { return &Schema{Type: "array", Items: items} }
+
+func enum(values ...string) *Schema { return &Schema{Type: "string", Enum: values} }
+
+var (
+	schemaString  = &Schema{Type: "string"}
+	schemaInteger = &Schema{Type: "integer"}
+	schemaNumber  = &Schema{Type: "number"}
+	schemaBoolean = &Schema{Type: "boolean"}
+)
+
+// headerRequired VDA 5050 공통 헤더 필드
+var headerRequired = []string{"headerId", "timestamp", "version", "manufacturer", "serialNumber"}
+
+// headerProperties VDA 5050 공통 헤더 필드 스키마
+func headerProperties(extra map[string]*Schema) map[string]*Schema {
+	properties := map[string]*Schema{
+		"headerId":     schemaInteger,
+		"timestamp":    schemaString,
+		"version":      schemaString,
+		"manufacturer": schemaString,
+		"serialNumber": schemaString,
+	}
+	for field, schema := range extra {
+		properties[field] = schema
+	}
+	return properties
+}
+
+// payloadSchemas 토픽 종류별 VDA 5050 (2.0) 스키마
+var payloadSchemas = map[string]*Schema{
+	PayloadKindConnection: object(append(append([]string(nil), headerRequired...), "connectionState"), headerProperties(map[string]*Schema{
+		"connectionState": enum("ONLINE", "OFFLINE", "CONNECTIONBROKEN"),
+	})),
+
+	PayloadKindState: object(
+		append(append([]string(nil), headerRequired...),
+			"orderId", "orderUpdateId", "lastNodeId", "lastNodeSequenceId", "driving",
+			"nodeStates", "edgeStates", "actionStates", "batteryState", "operatingMode", "errors", "safetyState"),
+		headerProperties(map[string]*Schema{
+			"orderId":               schemaString,
+			"orderUpdateId":         schemaInteger,
+			"zoneSetId":             schemaString,
+			"lastNodeId":            schemaString,
+			"lastNodeSequenceId":    schemaInteger,
+			"driving":               schemaBoolean,
+			"paused":                schemaBoolean,
+			"newBaseRequest":        schemaBoolean,
+			"distanceSinceLastNode": schemaNumber,
+			"operatingMode":         enum("AUTOMATIC", "SEMIAUTOMATIC", "MANUAL", "SERVICE", "TEACHIN"),
+			"nodeStates": arrayOf(object([]string{"nodeId", "sequenceId", "released"}, map[string]*Schema{
+				"nodeId":     schemaString,
+				"sequenceId": schemaInteger,
+				"released":   schemaBoolean,
+				"nodePosition": object([]string{"x", "y", "mapId"}, map[string]*Schema{
+					"x":     schemaNumber,
+					"y":     schemaNumber,
+					"theta": schemaNumber,
+					"mapId": schemaString,
+				}),
+			})),
+			"edgeStates": arrayOf(object([]string{"edgeId", "sequenceId", "released"}, map[string]*Schema{
+				"edgeId":     schemaString,
+				"sequenceId": schemaInteger,
+				"released":   schemaBoolean,
+			})),
+			"agvPosition": object([]string{"x", "y", "theta", "mapId", "positionInitialized"}, map[string]*Schema{
+				"x":                   schemaNumber,
+				"y":                   schemaNumber,
+				"theta":               schemaNumber,
+				"mapId":               schemaString,
+				"positionInitialized": schemaBoolean,
+				"localizationScore":   schemaNumber,
+				"deviationRange":      schemaNumber,
+			}),
+			"velocity": object(nil, map[string]*Schema{
+				"vx":    schemaNumber,
+				"vy":    schemaNumber,
+				"omega": schemaNumber,
+			}),
+			"actionStates": arrayOf(object([]string{"actionId", "actionStatus"}, map[string]*Schema{
+				"actionId":          schemaString,
+				"actionType":        schemaString,
+				"actionStatus":      enum("WAITING", "INITIALIZING", "RUNNING", "PAUSED", "FINISHED", "FAILED"),
+				"resultDescription": schemaString,
+			})),
+			"batteryState": object([]string{"batteryCharge", "charging"}, map[string]*Schema{
+				"batteryCharge":  schemaNumber,
+				"batteryVoltage": schemaNumber,
+				"batteryHealth":  schemaNumber,
+				"charging":       schemaBoolean,
+				"reach":          schemaNumber,
+			}),
+			"errors": arrayOf(object([]string{"errorType", "errorLevel"}, map[string]*Schema{
+				"errorType":        schemaString,
+				"errorDescription": schemaString,
+				"errorLevel":       enum("WARNING", "FATAL"),
+			})),
+			"information": arrayOf(object([]string{"infoType", "infoLevel"}, map[string]*Schema{
+				"infoType":        schemaString,
+				"infoDescription": schemaString,
+				"infoLevel":       enum("INFO", "DEBUG"),
+			})),
+			"safetyState": object([]string{"eStop", "fieldViolation"}, map[string]*Schema{
+				"eStop":          enum("AUTOACK", "MANUAL", "REMOTE", "NONE"),
+				"fieldViolation": schemaBoolean,
+			}),
+		}),
+	),
+
+	PayloadKindFactsheet: object(
+		append(append([]string(nil), headerRequired...),
+			"typeSpecification", "physicalParameters", "protocolLimits", "protocolFeatures", "agvGeometry", "loadSpecification"),
+		headerProperties(map[string]*Schema{
+			"typeSpecification": object(
+				[]string{"seriesName", "agvKinematic", "agvClass", "maxLoadMass", "localizationTypes", "navigationTypes"},
+				map[string]*Schema{
+					"seriesName":        schemaString,
+					"agvKinematic":      schemaString,
+					"agvClass":          schemaString,
+					"maxLoadMass":       schemaNumber,
+					"localizationTypes": arrayOf(schemaString),
+					"navigationTypes":   arrayOf(schemaString),
+				}),
+			"physicalParameters": object(nil, map[string]*Schema{
+				"speedMin": schemaNumber,
+				"speedMax": schemaNumber,
+				"length":   schemaNumber,
+				"width":    schemaNumber,
+			}),
+			"protocolLimits":    &Schema{Type: "object"},
+			"protocolFeatures":  &Schema{Type: "object"},
+			"agvGeometry":       &Schema{Type: "object"},
+			"loadSpecification": &Schema{Type: "object"},
+		}),
+	),
+}
+
+// PayloadKinds 스키마가 정의된 페이로드 종류
+func PayloadKinds() []string {
+	return []string{PayloadKindState, PayloadKindConnection, PayloadKindFactsheet}
+}
+
+// ValidatePayload 페이로드를 종류별 스키마로 검증 (JSON이 아니면 "$" 위반 하나로 보고)
+func ValidatePayload(kind string, payload []byte) ([]SchemaViolation, error) {
+	schema, ok := payloadSchemas[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown payload kind: %s (%s)", kind, strings.Join(PayloadKinds(), ", "))
+	}
+	var value interface{}
+	if err := json.Unmarshal(payload, &value); err != nil {
+		return []SchemaViolation{{Path: "$", Message: "invalid JSON: " + err.Error()}}, nil
+	}
+	return schema.Validate(value), nil
+}
+
+// SchemaStats 페이로드 종류별 검증 지표
+type SchemaStats struct {
+	Kind          string     `json:"kind"`
+	Validated     uint64     `json:"validated"`
+	Invalid       uint64     `json:"invalid"`
+	Rejected      uint64     `json:"rejected"` // strict 모드에서 버린 메시지 수
+	LastViolation string     `json:"last_violation,omitempty"`
+	LastTopic     string     `json:"last_topic,omitempty"`
+	LastInvalidAt *time.Time `json:"last_invalid_at,omitempty"`
+}
+
+// SchemaReport 검증 모드와 종류별 지표
+type SchemaReport struct {
+	Mode  string        `json:"mode"`
+	Kinds []SchemaStats `json:"kinds"`
+}
+
+// SchemaValidator 수신 페이로드를 토픽 종류별 스키마로 검증하고 위반 지표를 집계합니다.
+type SchemaValidator struct {
+	mode  string
+	mu    sync.Mutex
+	stats map[string]*SchemaStats
+}
+
+// NewSchemaValidator 검증기 생성 (mode: off, lenient, strict)
+func NewSchemaValidator(mode string) (*SchemaValidator, error) {
+	switch mode {
+	case SchemaModeOff, SchemaModeLenient, SchemaModeStrict:
+	default:
+		return nil, fmt.Errorf("unsupported payload schema mode: %s (off, lenient, strict)", mode)
+	}
+
+	v := &SchemaValidator{mode: mode, stats: make(map[string]*SchemaStats)}
+	for _, kind := range PayloadKinds() {
+		v.stats[kind] = &SchemaStats{Kind: kind}
+	}
+	utils.Logger.Infof("✅ Payload schema validator CREATED (mode=%s)", mode)
+	return v, nil
+}
+
+// Mode 검증 모드
+func (v *SchemaValidator) Mode() string {
+	return v.mode
+}
+
+// Accept 페이로드를 검증하고 라우팅 여부를 반환 (strict 모드에서 위반이 있으면 false)
+func (v *SchemaValidator) Accept(kind, topic string, payload []byte) bool {
+	if v.mode == SchemaModeOff {
+		return true
+	}
+	violations, err := ValidatePayload(kind, payload)
+	if err != nil {
+		return true
+	}
+
+	v.mu.Lock()
+	stats := v.stats[kind]
+	stats.Validated++
+	if len(violations) == 0 {
+		v.mu.Unlock()
+		return true
+	}
+	now := time.Now()
+	stats.Invalid++
+	stats.LastViolation = violations[0].String()
+	stats.LastTopic = topic
+	stats.LastInvalidAt = &now
+	rejected := v.mode == SchemaModeStrict
+	if rejected {
+		stats.Rejected++
+	}
+	v.mu.Unlock()
+
+	summary := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		summary = append(summary, violation.String())
+	}
+	if rejected {
+		utils.Logger.Errorf("🚫 Rejected %s message from %s (%d schema violation(s)): %s",
+			kind, topic, len(violations), strings.Join(summary, "; "))
+	} else {
+		utils.Logger.Warnf("⚠️ %s message from %s has %d schema violation(s): %s",
+			kind, topic, len(violations), strings.Join(summary, "; "))
+	}
+	return !rejected
+}
+
+// Report 검증 모드와 종류별 지표 반환
+func (v *SchemaValidator) Report() SchemaReport {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	report := SchemaReport{Mode: v.mode}
+	for _, kind := range PayloadKinds() {
+		report.Kinds = append(report.Kinds, *v.stats[kind])
+	}
+	return report
+}
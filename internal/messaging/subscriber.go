@@ -3,17 +3,27 @@ package messaging
 
 import (
 	"fmt"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/config"
 	"mqtt-bridge/internal/utils"
+	"sync"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 )
 
 // Subscriber MQTT 구독 관리자
 type Subscriber struct {
-	client Client
-	router *Router
+	client     Client
+	router     *Router
+	ingest     *IngestPool
+	filter     SubscriptionFilterFunc // 구독 종류별 브로커 필터와 QoS (미설정 시 토픽 그대로 QoS 0)
+	subscribed []string
+	mu         sync.RWMutex
 }
 
+// SubscriptionFilterFunc 구독 종류와 토픽으로 브로커 구독 필터($share/<그룹>/ 포함)와 QoS 결정
+type SubscriptionFilterFunc func(kind, topic string) (string, byte)
+
 // NewSubscriber 새 구독자 생성
 func NewSubscriber(client Client, router *Router) *Subscriber {
 	utils.Logger.Infof("🏗️ CREATING MQTT Subscriber")
@@ -27,48 +37,84 @@ func NewSubscriber(client Client, router *Router) *Subscriber {
 	return subscriber
 }
 
+// SetIngestPool 수신 메시지를 워커 풀을 거쳐 라우팅하도록 설정 (미설정 시 콜백에서 직접 라우팅)
+func (s *Subscriber) SetIngestPool(pool *IngestPool) {
+	s.ingest = pool
+	utils.Logger.Infof("✅ MQTT Subscriber: Ingest pool set")
+}
+
+// SetSubscriptionFilter 구독 종류별 QoS와 공유 구독 필터 설정 (SubscribeAll 전에 호출)
+func (s *Subscriber) SetSubscriptionFilter(filter SubscriptionFilterFunc) {
+	s.filter = filter
+	utils.Logger.Infof("✅ MQTT Subscriber: Subscription filter set")
+}
+
 // SubscribeAll 모든 필요한 토픽 구독
 func (s *Subscriber) SubscribeAll() error {
 	utils.Logger.Infof("🔔 STARTING All Subscriptions")
 
 	// 구독할 토픽들 정의
 	subscriptions := []struct {
+		kind        string
 		topic       string
 		description string
 	}{
 		{
-			topic:       "bridge/command",
+			kind:        config.SubscriptionCommand,
+			topic:       constants.TopicBridgeCommand,
 			description: "PLC Commands",
 		},
 		{
-			topic:       "meili/v2/+/+/connection",
+			kind:        config.SubscriptionConnection,
+			topic:       constants.TopicMeiliConnection,
 			description: "Robot Connection States",
 		},
 		{
-			topic:       "meili/v2/+/+/state",
+			kind:        config.SubscriptionState,
+			topic:       constants.TopicMeiliState,
 			description: "Robot States",
 		},
 		{
-			topic:       "meili/v2/+/+/factsheet",
+			kind:        config.SubscriptionFactsheet,
+			topic:       constants.TopicMeiliFactsheet,
 			description: "Robot Factsheets",
 		},
 		{
+			kind:        config.SubscriptionOrder,
 			topic:       "meili/v2/+/+/order",
 			description: "Robot Order Responses",
 		},
 	}
 
+	// 시계 감사를 켜면 PLC 하트비트도 구독
+	if s.router != nil && s.router.clock != nil {
+		subscriptions = append(subscriptions, struct {
+			kind        string
+			topic       string
+			description string
+		}{
+			kind:        config.SubscriptionHeartbeat,
+			topic:       constants.TopicBridgeHeartbeat,
+			description: "PLC Heartbeats",
+		})
+	}
+
 	// 각 토픽 구독
 	for _, sub := range subscriptions {
-		utils.Logger.Infof("🔔 SUBSCRIBING TO: %s (%s)", sub.topic, sub.description)
+		filter, qos := sub.topic, byte(0)
+		if s.filter != nil {
+			filter, qos = s.filter(sub.kind, sub.topic)
+		}
+		utils.Logger.Infof("🔔 SUBSCRIBING TO: %s (%s, QoS %d)", filter, sub.description, qos)
 
-		err := s.client.Subscribe(sub.topic, 0, s.handleMessage)
+		err := s.client.Subscribe(filter, qos, s.handleMessage)
 		if err != nil {
-			utils.Logger.Errorf("❌ SUBSCRIPTION FAILED: %s - %v", sub.topic, err)
-			return fmt.Errorf("failed to subscribe to %s: %v", sub.topic, err)
+			utils.Logger.Errorf("❌ SUBSCRIPTION FAILED: %s - %v", filter, err)
+			return fmt.Errorf("failed to subscribe to %s: %v", filter, err)
 		}
 
-		utils.Logger.Infof("✅ SUBSCRIPTION SUCCESS: %s", sub.topic)
+		s.recordSubscription(filter)
+		utils.Logger.Infof("✅ SUBSCRIPTION SUCCESS: %s", filter)
 	}
 
 	utils.Logger.Infof("🎉 ALL SUBSCRIPTIONS COMPLETED")
@@ -82,6 +128,12 @@ func (s *Subscriber) handleMessage(client mqtt.Client, msg mqtt.Message) {
 	utils.Logger.Infof("📨 MQTT RECEIVED Content: %s", string(msg.Payload()))
 	utils.Logger.Infof("📨 MQTT RECEIVED QoS    : %d, MessageID: %d", msg.Qos(), msg.MessageID())
 
+	// 워커 풀이 있으면 큐에 넣고 콜백을 바로 반환
+	if s.ingest != nil {
+		s.ingest.Submit(client, msg)
+		return
+	}
+
 	// 라우터에 메시지 전달
 	s.router.RouteMessage(client, msg)
 }
@@ -96,6 +148,28 @@ func (s *Subscriber) Subscribe(topic string, qos byte, handler MessageHandler) e
 		return err
 	}
 
+	s.recordSubscription(topic)
 	utils.Logger.Infof("✅ SUBSCRIPTION SUCCESS: %s", topic)
 	return nil
 }
+
+// recordSubscription 구독 성공한 토픽 기록 (중복 제외)
+func (s *Subscriber) recordSubscription(topic string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range s.subscribed {
+		if t == topic {
+			return
+		}
+	}
+	s.subscribed = append(s.subscribed, topic)
+}
+
+// Subscriptions 현재 구독 중인 토픽 목록 반환
+func (s *Subscriber) Subscriptions() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	topics := make([]string, len(s.subscribed))
+	copy(topics, s.subscribed)
+	return topics
+}
@@ -0,0 +1,251 @@
+// internal/messaging/subscriptions.go
+package messaging
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/utils"
+	"os"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// SubscriptionStats 구독 하나의 상태와 수신 지표
+type SubscriptionStats struct {
+	Topic         string     `json:"topic"`                  // 브로커 구독 필터 (공유 구독이면 $share/<그룹>/<토픽>)
+	SharedGroup   string     `json:"shared_group,omitempty"` // 공유 구독 그룹
+	QoS           byte       `json:"qos"`
+	Paused        bool       `json:"paused"`
+	Received      uint64     `json:"received"` // 라우팅한 메시지 수
+	Dropped       uint64     `json:"dropped"`  // 일시 중지 직후 도착하여 버린 메시지 수
+	LastMessageAt *time.Time `json:"last_message_at,omitempty"`
+	PausedAt      *time.Time `json:"paused_at,omitempty"`
+}
+
+// subscription 클라이언트가 관리하는 구독 (재연결 시 다시 구독)
+type subscription struct {
+	topic    string
+	qos      byte
+	callback MessageHandler
+	paused   bool
+	pausedAt *time.Time
+
+	received      atomic.Uint64
+	dropped       atomic.Uint64
+	lastMessageAt atomic.Int64 // UnixNano, 0이면 없음
+}
+
+// subscriptionState 구독 일시 중지 상태 파일 형식
+type subscriptionState struct {
+	Paused []string `json:"paused"`
+}
+
+// handle 일시 중지 중이 아니면 수신 지표를 올리고 콜백에 전달
+func (s *subscription) handle(c *MQTTClient) mqtt.MessageHandler {
+	return func(client mqtt.Client, msg mqtt.Message) {
+		c.subsMu.RLock()
+		paused := s.paused
+		c.subsMu.RUnlock()
+		if paused {
+			s.dropped.Add(1)
+			return
+		}
+		s.received.Add(1)
+		s.lastMessageAt.Store(time.Now().UnixNano())
+		s.callback(client, msg)
+	}
+}
+
+// Subscriptions 관리 중인 구독의 상태와 수신 지표 (토픽 순)
+func (c *MQTTClient) Subscriptions() []SubscriptionStats {
+	c.subsMu.RLock()
+	defer c.subsMu.RUnlock()
+	result := make([]SubscriptionStats, 0, len(c.subs))
+	for _, sub := range c.subs {
+		group, _, _ := splitSharedFilter(sub.topic)
+		stats := SubscriptionStats{
+			Topic:       sub.topic,
+			SharedGroup: group,
+			QoS:         sub.qos,
+			Paused:      sub.paused,
+			Received:    sub.received.Load(),
+			Dropped:     sub.dropped.Load(),
+			PausedAt:    sub.pausedAt,
+		}
+		if last := sub.lastMessageAt.Load(); last != 0 {
+			t := time.Unix(0, last)
+			stats.LastMessageAt = &t
+		}
+		result = append(result, stats)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Topic < result[j].Topic })
+	return result
+}
+
+// lookupSubscription 구독 필터로 구독 조회 (공유 구독은 $share/<그룹>/ 없이 토픽만으로도 조회, subsMu 보유 상태에서 호출)
+func (c *MQTTClient) lookupSubscription(topic string) (*subscription, bool) {
+	if sub, ok := c.subs[topic]; ok {
+		return sub, true
+	}
+	for filter, sub := range c.subs {
+		if _, shared, ok := splitSharedFilter(filter); ok && shared == topic {
+			return sub, true
+		}
+	}
+	return nil, false
+}
+
+// PauseSubscription 구독을 브로커에서 해제하고 일시 중지 상태로 기록 (재연결/재시작 후에도 유지)
+func (c *MQTTClient) PauseSubscription(topic string) (*SubscriptionStats, error) {
+	c.subsMu.Lock()
+	sub, ok := c.lookupSubscription(topic)
+	if !ok {
+		c.subsMu.Unlock()
+		return nil, apperr.New(apperr.CodeNotFound, "no subscription for topic %s", topic).WithField("topic")
+	}
+	topic = sub.topic
+	if sub.paused {
+		c.subsMu.Unlock()
+		return c.subscriptionStats(topic), nil
+	}
+	now := time.Now()
+	sub.paused = true
+	sub.pausedAt = &now
+	c.subsMu.Unlock()
+
+	if c.client.IsConnected() {
+		if token := c.client.Unsubscribe(topic); token.Wait() && token.Error() != nil {
+			utils.Logger.Warnf("⏸️ Failed to unsubscribe %s on the broker (messages are dropped until resumed): %v", topic, token.Error())
+		}
+	}
+	utils.Logger.Infof("⏸️ Subscription paused: %s", topic)
+	if err := c.saveSubscriptionState(); err != nil {
+		return c.subscriptionStats(topic), apperr.Wrap(apperr.CodeInternal, err,
+			"subscription paused but not saved to %s", c.config.MQTTSubscriptionStateFile)
+	}
+	return c.subscriptionStats(topic), nil
+}
+
+// ResumeSubscription 일시 중지한 구독을 브로커에 다시 구독
+func (c *MQTTClient) ResumeSubscription(topic string) (*SubscriptionStats, error) {
+	c.subsMu.RLock()
+	sub, ok := c.lookupSubscription(topic)
+	c.subsMu.RUnlock()
+	if !ok {
+		return nil, apperr.New(apperr.CodeNotFound, "no subscription for topic %s", topic).WithField("topic")
+	}
+	topic = sub.topic
+
+	c.subsMu.Lock()
+	wasPaused := sub.paused
+	sub.paused = false
+	sub.pausedAt = nil
+	c.subsMu.Unlock()
+	if !wasPaused {
+		return c.subscriptionStats(topic), nil
+	}
+
+	if c.client.IsConnected() {
+		if token := c.client.Subscribe(topic, sub.qos, sub.handle(c)); token.Wait() && token.Error() != nil {
+			c.subsMu.Lock()
+			sub.paused = true
+			now := time.Now()
+			sub.pausedAt = &now
+			c.subsMu.Unlock()
+			return nil, apperr.Wrap(apperr.CodeTransportUnavailable, token.Error(), "failed to resubscribe %s", topic)
+		}
+	}
+	utils.Logger.Infof("▶️ Subscription resumed: %s", topic)
+	if err := c.saveSubscriptionState(); err != nil {
+		return c.subscriptionStats(topic), apperr.Wrap(apperr.CodeInternal, err,
+			"subscription resumed but not saved to %s", c.config.MQTTSubscriptionStateFile)
+	}
+	return c.subscriptionStats(topic), nil
+}
+
+// subscriptionStats 토픽 하나의 지표
+func (c *MQTTClient) subscriptionStats(topic string) *SubscriptionStats {
+	for _, stats := range c.Subscriptions() {
+		if stats.Topic == topic {
+			return &stats
+		}
+	}
+	return nil
+}
+
+// resubscribe 재연결 후 일시 중지하지 않은 구독을 다시 구독 (클린 세션에서는 브로커가 구독을 잊음)
+func (c *MQTTClient) resubscribe(client mqtt.Client) {
+	c.subsMu.RLock()
+	var active []*subscription
+	for _, sub := range c.subs {
+		if !sub.paused {
+			active = append(active, sub)
+		}
+	}
+	c.subsMu.RUnlock()
+
+	for _, sub := range active {
+		if token := client.Subscribe(sub.topic, sub.qos, sub.handle(c)); token.Wait() && token.Error() != nil {
+			utils.Logger.Errorf("❌ Failed to resubscribe %s after reconnect: %v", sub.topic, token.Error())
+			continue
+		}
+		utils.Logger.Infof("🔁 Resubscribed to %s", sub.topic)
+	}
+}
+
+// loadSubscriptionState 상태 파일의 일시 중지 토픽 (파일이 없으면 빈 목록)
+func (c *MQTTClient) loadSubscriptionState() error {
+	file := c.config.MQTTSubscriptionStateFile
+	if file == "" {
+		return nil
+	}
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var state subscriptionState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("invalid subscription state file %s: %w", file, err)
+	}
+	for _, topic := range state.Paused {
+		c.pausedTopics[topic] = true
+	}
+	if len(state.Paused) > 0 {
+		utils.Logger.Infof("⏸️ Subscriptions paused by %s: %v", file, state.Paused)
+	}
+	return nil
+}
+
+// saveSubscriptionState 일시 중지 토픽을 상태 파일에 저장 (파일이 설정되지 않으면 메모리에만 유지)
+func (c *MQTTClient) saveSubscriptionState() error {
+	c.subsMu.Lock()
+	state := subscriptionState{Paused: make([]string, 0)}
+	for topic, sub := range c.subs {
+		c.pausedTopics[topic] = sub.paused
+	}
+	for topic, paused := range c.pausedTopics {
+		if paused {
+			state.Paused = append(state.Paused, topic)
+		}
+	}
+	c.subsMu.Unlock()
+
+	file := c.config.MQTTSubscriptionStateFile
+	if file == "" {
+		return nil
+	}
+	sort.Strings(state.Paused)
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(data, '\n'), 0644)
+}
@@ -0,0 +1,31 @@
+// internal/models/alert.go
+package models
+
+import (
+	"time"
+)
+
+// AlertEvent 알림 규칙이 발생시킨 알림 이력
+type AlertEvent struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	SiteID    string    `gorm:"size:50;not null;default:default;index" json:"site_id"`
+	Rule      string    `gorm:"size:50;not null;index" json:"rule"` // robot_offline, command_failed, battery_low, order_stuck, test
+	Subject   string    `gorm:"size:100;not null" json:"subject"`   // 알림 대상 (로봇 시리얼, 오더 ID 등)
+	Severity  string    `gorm:"size:20;not null" json:"severity"`   // WARNING, CRITICAL
+	Message   string    `gorm:"size:1000;not null" json:"message"`
+	Channels  string    `gorm:"size:100" json:"channels"`             // 전송한 채널 (쉼표 구분)
+	Status    string    `gorm:"size:20;not null;index" json:"status"` // SENT, FAILED, SUPPRESSED
+	Error     string    `gorm:"size:500" json:"error,omitempty"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// AlertSuppression 알림 억제 시간대 (점검 작업 등으로 예상되는 알림을 보내지 않음)
+type AlertSuppression struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	SiteID    string    `gorm:"size:50;not null;default:default;index" json:"site_id"`
+	Rule      string    `gorm:"size:50" json:"rule,omitempty"` // 비어 있으면 모든 규칙
+	Reason    string    `gorm:"size:255" json:"reason"`
+	StartsAt  time.Time `gorm:"not null" json:"starts_at"`
+	EndsAt    time.Time `gorm:"not null;index" json:"ends_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,21 @@
+// internal/models/annotation.go
+package models
+
+import (
+	"time"
+)
+
+// ExecutionAnnotation 오더 실행이나 명령에 운영자가 남긴 라벨과 메모
+// 오더 주석은 OrderID로, 명령 주석은 명령의 CorrelationID로 대상을 가리킵니다.
+// 명령 주석은 그 명령이 만든 모든 오더의 검색 결과에도 포함됩니다.
+type ExecutionAnnotation struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	SiteID        string    `gorm:"size:50;not null;default:default;index" json:"site_id"`
+	TargetType    string    `gorm:"size:10;not null" json:"target_type"`           // ORDER, COMMAND
+	OrderID       string    `gorm:"size:100;index" json:"order_id,omitempty"`      // ORDER 대상
+	CorrelationID string    `gorm:"size:64;index" json:"correlation_id,omitempty"` // COMMAND 대상 (ORDER 대상이면 오더의 상관 ID)
+	Labels        string    `gorm:"size:500" json:"labels,omitempty"`              // 쉼표로 구분된 소문자 라벨 (예: "review,operator-cancel")
+	Note          string    `gorm:"type:text" json:"note,omitempty"`
+	Author        string    `gorm:"size:100" json:"author,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
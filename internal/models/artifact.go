@@ -0,0 +1,25 @@
+// internal/models/artifact.go
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OrderArtifact 오더 실행 결과물 메타데이터 (본문은 결과물 저장소에 StorageKey로 저장)
+type OrderArtifact struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	SiteID      string         `gorm:"size:50;not null;default:default;uniqueIndex:idx_order_artifacts_site_order_name" json:"site_id"`
+	OrderID     string         `gorm:"size:100;not null;uniqueIndex:idx_order_artifacts_site_order_name" json:"order_id"`
+	Name        string         `gorm:"size:255;not null;uniqueIndex:idx_order_artifacts_site_order_name" json:"name"`
+	Kind        string         `gorm:"size:30;not null" json:"kind"` // INFERENCE_OUTPUT, IMAGE, LOG_REPORT, OTHER
+	ContentType string         `gorm:"size:100" json:"content_type"`
+	Size        int64          `json:"size"`
+	SHA256      string         `gorm:"size:64" json:"sha256"`
+	StorageKey  string         `gorm:"size:500;not null" json:"storage_key"`
+	ActionID    string         `gorm:"size:100" json:"action_id,omitempty"` // 자동 수집된 결과물의 액션
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
@@ -0,0 +1,25 @@
+// internal/models/charging_policy.go
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ChargingPolicy 로봇 그룹별 자동 충전 정책
+// 배터리가 LowBattery(%) 미만이고 실행 중인 오더가 없으면 CommandType 명령(충전소 이동 템플릿에 매핑)을 배차하고,
+// 로봇을 충전 사유의 유지보수 모드로 표시했다가 ResumeBattery(%) 이상 충전되면 다시 배차 가능 상태로 되돌립니다.
+type ChargingPolicy struct {
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	SiteID        string         `gorm:"size:50;not null;default:default;uniqueIndex:idx_charging_policies_site_name" json:"site_id"`
+	Name          string         `gorm:"size:100;not null;uniqueIndex:idx_charging_policies_site_name" json:"name"`
+	Robots        string         `gorm:"size:1000" json:"robots,omitempty"` // 쉼표로 구분된 로봇 그룹 (비어 있으면 다른 정책에 없는 모든 로봇)
+	LowBattery    float64        `gorm:"not null" json:"low_battery"`       // 이 잔량(%) 미만이면 충전 배차
+	ResumeBattery float64        `gorm:"not null" json:"resume_battery"`    // 이 잔량(%) 이상이면 배차 가능으로 복귀
+	CommandType   string         `gorm:"size:10;not null" json:"command_type"`
+	IsActive      bool           `gorm:"default:true" json:"is_active"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
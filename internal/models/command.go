@@ -9,13 +9,14 @@ import (
 
 // CommandDefinition PLC에서 사용 가능한 모든 명령어를 정의하는 테이블
 type CommandDefinition struct {
-	ID          uint           `gorm:"primaryKey" json:"id"`
-	CommandType string         `gorm:"size:10;not null;uniqueIndex" json:"command_type"` // "CR", "GR" 등 PLC에서 사용하는 고유 코드
-	Description string         `gorm:"size:255" json:"description"`                      // "백내장 적출", "그리퍼 세정" 등
-	IsActive    bool           `gorm:"default:true" json:"is_active"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	CommandType    string         `gorm:"size:10;not null;uniqueIndex" json:"command_type"` // "CR", "GR" 등 PLC에서 사용하는 고유 코드
+	Description    string         `gorm:"size:255" json:"description"`                      // "백내장 적출", "그리퍼 세정" 등
+	IsActive       bool           `gorm:"default:true" json:"is_active"`
+	ReportProgress bool           `gorm:"default:false" json:"report_progress"` // 다중 오더 명령의 오더 완료마다 진행률 응답 전송 (예: "CR:R:2/5")
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 }
 
 // Command PLC에서 요청된 명령의 실행 이력을 기록하는 로그 테이블
@@ -26,6 +27,8 @@ type Command struct {
 	RequestTime         time.Time      `gorm:"not null" json:"request_time"`
 	ResponseTime        *time.Time     `json:"response_time"`
 	ErrorMessage        string         `gorm:"size:500" json:"error_message"`
+	CorrelationID       string         `gorm:"size:64;index" json:"correlation_id"`  // PLC 요청 추적 ID (요청에 없으면 생성)
+	ParameterOverrides  string         `gorm:"type:text" json:"parameter_overrides"` // 템플릿 자리표시자 치환 값 (JSON)
 	CreatedAt           time.Time      `json:"created_at"`
 	UpdatedAt           time.Time      `json:"updated_at"`
 	DeletedAt           gorm.DeletedAt `gorm:"index" json:"deleted_at"`
@@ -36,16 +39,34 @@ type Command struct {
 
 // RobotStatus 로봇 상태 정보
 type RobotStatus struct {
-	ID              uint           `gorm:"primaryKey" json:"id"`
-	Manufacturer    string         `gorm:"size:50;not null" json:"manufacturer"`
-	SerialNumber    string         `gorm:"size:50;not null;uniqueIndex" json:"serial_number"`
-	ConnectionState string         `gorm:"size:20;not null" json:"connection_state"`
-	LastHeaderID    int64          `gorm:"not null" json:"last_header_id"`
-	LastTimestamp   time.Time      `gorm:"not null" json:"last_timestamp"`
-	Version         string         `gorm:"size:10" json:"version"`
-	CreatedAt       time.Time      `json:"created_at"`
-	UpdatedAt       time.Time      `json:"updated_at"`
-	DeletedAt       gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	SiteID          string    `gorm:"size:50;not null;default:default;index" json:"site_id"`
+	Manufacturer    string    `gorm:"size:50;not null" json:"manufacturer"`
+	SerialNumber    string    `gorm:"size:50;not null;uniqueIndex" json:"serial_number"`
+	ConnectionState string    `gorm:"size:20;not null" json:"connection_state"`
+	LastHeaderID    int64     `gorm:"not null" json:"last_header_id"`
+	LastTimestamp   time.Time `gorm:"not null" json:"last_timestamp"`
+	Version         string    `gorm:"size:10" json:"version"`
+	CurrentMapID    string    `gorm:"size:100" json:"current_map_id"` // 마지막 상태 메시지의 agvPosition.mapId
+
+	// 유지보수 모드: 설정되어 있으면 새 명령/오더를 배차하지 않음 (상태/헬스 조회는 유지)
+	Maintenance       bool       `gorm:"default:false" json:"maintenance"`
+	MaintenanceReason string     `gorm:"size:255" json:"maintenance_reason"`
+	MaintenanceUntil  *time.Time `json:"maintenance_until"` // 자동 해제 시각 (nil이면 수동 해제까지 유지)
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// ConnectionStateTransition 로봇 연결 상태 변경 이력 (가동률 계산용)
+type ConnectionStateTransition struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	SiteID       string    `gorm:"size:50;not null;default:default;index" json:"site_id"`
+	SerialNumber string    `gorm:"size:50;not null;index:idx_conn_transition_serial_time" json:"serial_number"`
+	FromState    string    `gorm:"size:20" json:"from_state"`
+	ToState      string    `gorm:"size:20;not null" json:"to_state"`
+	ObservedAt   time.Time `gorm:"not null;index:idx_conn_transition_serial_time" json:"observed_at"`
 }
 
 // ConnectionStateMessage 로봇 연결 상태 메시지
@@ -130,10 +151,11 @@ type AgvAction struct {
 
 // AgvActionParameter AGV 액션 파라미터
 type AgvActionParameter struct {
-	Description   string `json:"Description"`
-	IsOptional    bool   `json:"IsOptional"`
-	Key           string `json:"Key"`
-	ValueDataType string `json:"ValueDataType"`
+	Description   string   `json:"Description"`
+	IsOptional    bool     `json:"IsOptional"`
+	Key           string   `json:"Key"`
+	ValueDataType string   `json:"ValueDataType"`
+	Enum          []string `json:"Enum,omitempty"` // 허용 값 목록 (VDA 표준 외 제조사 확장, 없으면 생략)
 }
 
 // ProtocolLimits 프로토콜 제한사항
@@ -173,6 +195,7 @@ type RobotFactsheet struct {
 	HeightMin         float64        `json:"height_min"`
 	LocalizationTypes string         `gorm:"size:200" json:"localization_types"` // JSON 배열을 문자열로 저장
 	NavigationTypes   string         `gorm:"size:200" json:"navigation_types"`   // JSON 배열을 문자열로 저장
+	AgvActions        string         `gorm:"type:text" json:"agv_actions"`       // protocolFeatures.agvActions JSON 배열 (액션 파라미터 스키마용)
 	LastUpdated       time.Time      `gorm:"not null" json:"last_updated"`
 	CreatedAt         time.Time      `json:"created_at"`
 	UpdatedAt         time.Time      `json:"updated_at"`
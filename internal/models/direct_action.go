@@ -0,0 +1,41 @@
+// internal/models/direct_action.go
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DirectActionDefinition PLC 직접 액션 명령 레지스트리
+// "<기준명령>:<접미사>[:<인자>...]" 형식의 명령을 접미사로 찾아 단일 액션 오더로 변환합니다.
+// 기준 명령은 ParameterKey 파라미터로, 추가 인자는 Arguments 정의 순서대로 전달됩니다.
+type DirectActionDefinition struct {
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	Suffix       string         `gorm:"size:10;not null;uniqueIndex" json:"suffix"`         // "I", "T" 등 PLC 명령 접미사
+	ActionType   string         `gorm:"size:100;not null" json:"action_type"`               // 로봇에 전송할 VDA 5050 actionType
+	ParameterKey string         `gorm:"size:100;not null" json:"parameter_key"`             // 기준 명령을 전달할 액션 파라미터 키
+	BlockingType string         `gorm:"size:20;not null;default:NONE" json:"blocking_type"` // NONE, SOFT, HARD
+	Description  string         `gorm:"size:255" json:"description"`
+	IsActive     bool           `gorm:"default:true" json:"is_active"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+
+	// Relationships
+	Arguments []DirectActionArgument `gorm:"foreignKey:DirectActionDefinitionID" json:"arguments"`
+}
+
+// DirectActionArgument 직접 액션 명령의 추가 인자 (접미사 뒤 ":"로 구분된 위치 인자)
+type DirectActionArgument struct {
+	ID                       uint           `gorm:"primaryKey" json:"id"`
+	DirectActionDefinitionID uint           `gorm:"not null;index" json:"direct_action_definition_id"`
+	Position                 int            `gorm:"not null" json:"position"` // 접미사 뒤 인자 순번 (1부터)
+	Key                      string         `gorm:"size:100;not null" json:"key"`
+	Required                 bool           `gorm:"default:false" json:"required"`
+	DefaultValue             string         `gorm:"size:255" json:"default_value"` // 인자가 없을 때 사용할 값 (비어 있으면 파라미터 생략)
+	ValueMap                 string         `gorm:"size:500" json:"value_map"`     // PLC 값 → 파라미터 값 매핑 (예: "R=right,L=left", 비어 있으면 그대로 전달)
+	CreatedAt                time.Time      `json:"created_at"`
+	UpdatedAt                time.Time      `json:"updated_at"`
+	DeletedAt                gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
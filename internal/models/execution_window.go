@@ -0,0 +1,28 @@
+// internal/models/execution_window.go
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ExecutionWindow 오더 실행 제한 시간대 (예: 22:00~06:00 주행 오더 금지)
+// 템플릿과 로봇 그룹으로 적용 범위를 좁힐 수 있으며, 제한 시간대의 오더는 Policy에 따라
+// 시간대가 끝날 때까지 대기(QUEUE)하거나 거부(REJECT)됩니다.
+type ExecutionWindow struct {
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	SiteID        string         `gorm:"size:50;not null;default:default;uniqueIndex:idx_execution_windows_site_name" json:"site_id"`
+	Name          string         `gorm:"size:100;not null;uniqueIndex:idx_execution_windows_site_name" json:"name"`
+	TemplateID    *uint          `gorm:"index" json:"template_id,omitempty"`           // null이면 모든 템플릿
+	Robots        string         `gorm:"size:1000" json:"robots,omitempty"`            // 쉼표로 구분된 로봇 그룹 (비어 있으면 모든 로봇)
+	StartTime     string         `gorm:"size:5;not null" json:"start_time"`            // HH:MM
+	EndTime       string         `gorm:"size:5;not null" json:"end_time"`              // HH:MM (시작보다 이르면 자정을 넘김)
+	Timezone      string         `gorm:"size:50" json:"timezone,omitempty"`            // IANA 이름 (비어 있으면 브릿지 로컬 시간)
+	Policy        string         `gorm:"size:10;not null;default:QUEUE" json:"policy"` // QUEUE, REJECT
+	IsActive      bool           `gorm:"default:true" json:"is_active"`
+	OverrideUntil *time.Time     `json:"override_until,omitempty"` // 관리자가 이 시각까지 제한을 해제
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
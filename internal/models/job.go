@@ -0,0 +1,43 @@
+// internal/models/job.go
+package models
+
+import "time"
+
+// Job 여러 로봇과 명령(템플릿)에 걸친 작업 묶음
+// 모든 작업 항목을 한 번에 승인하거나 모두 거부하며, 상태는 작업 항목 상태를 모은 것이고 묶음 단위로 취소합니다.
+// 작업 항목은 로봇을 관리하는 브릿지가 가져가 실행하므로 여러 브릿지가 같은 DB를 쓰면 로봇마다 다른 브릿지가 실행합니다.
+type Job struct {
+	ID            uint       `gorm:"primaryKey" json:"-"`
+	JobID         string     `gorm:"size:64;not null;uniqueIndex" json:"job_id"`
+	SiteID        string     `gorm:"size:50;not null;default:default;index" json:"site_id"`
+	Name          string     `gorm:"size:100" json:"name"`
+	Status        string     `gorm:"size:20;not null;index" json:"status"` // PENDING, RUNNING, COMPLETED, FAILED, CANCELLED
+	CorrelationID string     `gorm:"size:64;index" json:"correlation_id"`  // 작업 항목 명령이 공유하는 요청 추적 ID
+	CancelReason  string     `gorm:"size:255" json:"cancel_reason,omitempty"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+
+	// 관계
+	Tasks []JobTask `gorm:"foreignKey:JobRef" json:"tasks"`
+}
+
+// JobTask 작업 묶음의 작업 항목 (로봇 하나에서 실행할 명령 하나)
+// 같은 로봇의 작업 항목은 Sequence 순서대로 하나씩 실행합니다.
+type JobTask struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	JobRef       uint       `gorm:"not null;index" json:"-"`
+	SiteID       string     `gorm:"size:50;not null;default:default;index" json:"site_id"`
+	Sequence     int        `gorm:"not null" json:"sequence"` // 작업 묶음 안의 순번 (1부터)
+	SerialNumber string     `gorm:"size:50;not null;index" json:"serial_number"`
+	CommandType  string     `gorm:"size:50;not null" json:"command_type"`
+	Parameters   string     `gorm:"type:text" json:"parameters,omitempty"` // 템플릿 자리표시자 치환 값 (JSON)
+	Status       string     `gorm:"size:20;not null;index" json:"status"`  // PENDING, RUNNING, COMPLETED, FAILED, CANCELLED
+	CommandID    uint       `gorm:"default:0" json:"command_id,omitempty"` // 실행을 시작하면 만든 명령
+	ErrorMessage string     `gorm:"size:500" json:"error_message,omitempty"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
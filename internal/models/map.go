@@ -0,0 +1,68 @@
+// internal/models/map.go
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RobotMap 로봇 맵 메타데이터 (맵 경계 좌표)
+type RobotMap struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	SiteID      string         `gorm:"size:50;not null;default:default;uniqueIndex:idx_robot_maps_site_map" json:"site_id"`
+	MapID       string         `gorm:"size:100;not null;uniqueIndex:idx_robot_maps_site_map" json:"map_id"`
+	Description string         `gorm:"size:500" json:"description"`
+	MinX        float64        `gorm:"default:0.0" json:"min_x"`
+	MinY        float64        `gorm:"default:0.0" json:"min_y"`
+	MaxX        float64        `gorm:"default:0.0" json:"max_x"`
+	MaxY        float64        `gorm:"default:0.0" json:"max_y"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+
+	// Relationships
+	Zones []MapZone `gorm:"foreignKey:RobotMapID" json:"zones"`
+}
+
+// HasBounds 맵 경계가 설정되어 있는지 여부
+func (m *RobotMap) HasBounds() bool {
+	return m.MaxX > m.MinX && m.MaxY > m.MinY
+}
+
+// MapZone 맵 안의 이름 있는 사각형 구역 (ALLOWED: 허용 구역, RESTRICTED: 진입 금지 구역)
+type MapZone struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	RobotMapID uint           `gorm:"not null;index" json:"robot_map_id"`
+	Name       string         `gorm:"size:100;not null" json:"name"`
+	ZoneType   string         `gorm:"size:20;not null" json:"zone_type"` // ALLOWED, RESTRICTED
+	MinX       float64        `gorm:"default:0.0" json:"min_x"`
+	MinY       float64        `gorm:"default:0.0" json:"min_y"`
+	MaxX       float64        `gorm:"default:0.0" json:"max_x"`
+	MaxY       float64        `gorm:"default:0.0" json:"max_y"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// Contains 좌표가 구역 안에 있는지 여부 (경계 포함)
+func (z *MapZone) Contains(x, y float64) bool {
+	return x >= z.MinX && x <= z.MaxX && y >= z.MinY && y <= z.MaxY
+}
+
+// RobotDefaults 로봇별 기본 노드 위치 (홈 위치, 기본 맵, 허용 편차)
+// 노드 템플릿이 없는 단계와 직접 액션 오더의 노드 위치, 템플릿에 비어 있는 맵/편차 값에 사용됩니다.
+type RobotDefaults struct {
+	ID                    uint           `gorm:"primaryKey" json:"id"`
+	SiteID                string         `gorm:"size:50;not null;default:default;uniqueIndex:idx_robot_defaults_site_serial" json:"site_id"`
+	SerialNumber          string         `gorm:"size:50;not null;uniqueIndex:idx_robot_defaults_site_serial" json:"serial_number"`
+	X                     float64        `gorm:"default:0.0" json:"x"`
+	Y                     float64        `gorm:"default:0.0" json:"y"`
+	Theta                 float64        `gorm:"default:0.0" json:"theta"`
+	MapID                 string         `gorm:"size:100" json:"map_id"`
+	AllowedDeviationXY    float64        `gorm:"default:0.0" json:"allowed_deviation_xy"`
+	AllowedDeviationTheta float64        `gorm:"default:0.0" json:"allowed_deviation_theta"`
+	CreatedAt             time.Time      `json:"created_at"`
+	UpdatedAt             time.Time      `json:"updated_at"`
+	DeletedAt             gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
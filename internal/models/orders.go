@@ -28,19 +28,80 @@ type CommandOrderMapping struct {
 
 // OrderTemplate 오더 템플릿
 type OrderTemplate struct {
-	ID          uint           `gorm:"primaryKey" json:"id"`
-	Name        string         `gorm:"size:100;not null;uniqueIndex" json:"name"`
-	Description string         `gorm:"size:500" json:"description"`
-	IsActive    bool           `gorm:"default:true" json:"is_active"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	SiteID      string `gorm:"size:50;not null;default:default;uniqueIndex:idx_order_templates_site_name" json:"site_id"`
+	Name        string `gorm:"size:100;not null;uniqueIndex:idx_order_templates_site_name" json:"name"`
+	Description string `gorm:"size:500" json:"description"`
+	IsActive    bool   `gorm:"default:true" json:"is_active"`
+	Status      string `gorm:"size:20;not null;default:ACTIVE;index" json:"status"` // DRAFT, ACTIVE, DEPRECATED
+
+	// 사이트 전체에서 이 템플릿 오더를 동시에 실행할 수 있는 최대 로봇 수 (0이면 제한 없음, 초과분은 WAITING으로 대기)
+	MaxConcurrentExecutions int `gorm:"default:0" json:"max_concurrent_executions"`
+
+	// 오더 실행 제한 시간 (초, 0이면 ORDER_DEADLINE_SECONDS 기본값, 둘 다 0이면 제한 없음)
+	// 시작 후 이 시간이 지나도 RUNNING이면 로봇에서 취소하고 EXPIRED로 끝내 FailureOrder로 분기
+	DeadlineSeconds int `gorm:"default:0" json:"deadline_seconds"`
+
+	// 오더 종료 알림 설정 (order_finished 규칙)
+	NotifyOn            string `gorm:"size:20;not null;default:FAILURE" json:"notify_on"` // FAILURE, ALL, NONE
+	NotifyChannel       string `gorm:"size:20" json:"notify_channel"`                     // 알림 채널 (slack, email, 비어 있으면 모든 채널)
+	NotifyEscalateAfter int    `gorm:"default:0" json:"notify_escalate_after"`            // 연속 N번째 실패부터 CRITICAL로 모든 채널에 알림 (0이면 끔)
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 
 	// 관계
 	OrderSteps      []OrderStep           `gorm:"foreignKey:TemplateID" json:"order_steps"`
 	CommandMappings []CommandOrderMapping `gorm:"foreignKey:TemplateID" json:"command_mappings"`
 }
 
+// TemplateRollout 템플릿 새 버전의 카나리 롤아웃
+// 진행 중(CANARY)인 동안 CanaryRobots의 로봇은 BaseTemplateID 대신 CandidateTemplateID로 오더를 실행하고,
+// 나머지 로봇은 기존 버전을 계속 사용합니다.
+type TemplateRollout struct {
+	ID                  uint       `gorm:"primaryKey" json:"id"`
+	SiteID              string     `gorm:"size:50;not null;default:default;index" json:"site_id"`
+	BaseTemplateID      uint       `gorm:"not null;index" json:"base_template_id"`
+	CandidateTemplateID uint       `gorm:"not null;index" json:"candidate_template_id"`
+	CanaryRobots        string     `gorm:"size:1000;not null" json:"canary_robots"` // 쉼표로 구분된 로봇 시리얼 번호
+	Status              string     `gorm:"size:20;not null;index" json:"status"`    // CANARY, PROMOTED, ROLLED_BACK
+	StartedAt           time.Time  `json:"started_at"`
+	FinishedAt          *time.Time `json:"finished_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// TemplateShadow 템플릿 섀도 실행 설정
+// TemplateID 오더가 끝날 때마다 같은 실행 조건으로 ShadowTemplateID를 드라이런하여
+// 실제로 보낸 오더 메시지와 비교합니다 (섀도 템플릿은 로봇에 전송하지 않음).
+type TemplateShadow struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	SiteID           string    `gorm:"size:50;not null;default:default;uniqueIndex:idx_template_shadow" json:"site_id"`
+	TemplateID       uint      `gorm:"not null;uniqueIndex:idx_template_shadow" json:"template_id"`
+	ShadowTemplateID uint      `gorm:"not null;index" json:"shadow_template_id"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// ShadowComparison 오더 실행 하나에 대한 실제 템플릿과 섀도 템플릿의 오더 메시지 비교
+type ShadowComparison struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	SiteID           string    `gorm:"size:50;not null;default:default;index" json:"site_id"`
+	TemplateID       uint      `gorm:"not null;index:idx_shadow_comparison_pair" json:"template_id"`
+	ShadowTemplateID uint      `gorm:"not null;index:idx_shadow_comparison_pair" json:"shadow_template_id"`
+	ExecutionID      uint      `gorm:"not null;index" json:"execution_id"`
+	OrderID          string    `gorm:"size:100;not null" json:"order_id"`
+	SerialNumber     string    `gorm:"size:50" json:"serial_number"`
+	ExecutionStatus  string    `gorm:"size:20" json:"execution_status"`
+	PrimaryPayloads  string    `gorm:"type:text" json:"primary_payloads"` // 실제 전송한 오더 메시지 (JSON 배열, 전송 순)
+	ShadowPayloads   string    `gorm:"type:text" json:"shadow_payloads"`  // 섀도 템플릿이 만들었을 오더 메시지 (JSON 배열, 단계 순)
+	Differences      string    `gorm:"type:text" json:"differences"`      // 메시지별 차이 (JSON 배열)
+	DiffCount        int       `json:"diff_count"`
+	ShadowError      string    `gorm:"size:500" json:"shadow_error,omitempty"` // 섀도 템플릿을 실행할 수 없었던 사유
+	CreatedAt        time.Time `json:"created_at"`
+}
+
 // NodeTemplate 노드 템플릿
 type NodeTemplate struct {
 	ID                    uint           `gorm:"primaryKey" json:"id"`
@@ -52,6 +113,7 @@ type NodeTemplate struct {
 	AllowedDeviationXY    float64        `gorm:"default:0.0" json:"allowed_deviation_xy"`
 	AllowedDeviationTheta float64        `gorm:"default:0.0" json:"allowed_deviation_theta"`
 	MapID                 string         `gorm:"size:100" json:"map_id"`
+	Zones                 string         `gorm:"size:255" json:"zones"` // 로봇이 이 노드에 있는 동안 점유하는 구역 ID (쉼표 구분)
 	CreatedAt             time.Time      `json:"created_at"`
 	UpdatedAt             time.Time      `json:"updated_at"`
 	DeletedAt             gorm.DeletedAt `gorm:"index" json:"deleted_at"`
@@ -99,6 +161,7 @@ type EdgeTemplate struct {
 	Orientation     float64        `gorm:"default:0.0" json:"orientation"`
 	Direction       string         `gorm:"size:20" json:"direction"` // STRAIGHT, LEFT, RIGHT
 	RotationAllowed bool           `gorm:"default:true" json:"rotation_allowed"`
+	Zones           string         `gorm:"size:255" json:"zones"` // 로봇이 이 엣지를 지나는 동안 점유하는 구역 ID (쉼표 구분)
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
 	DeletedAt       gorm.DeletedAt `gorm:"index" json:"deleted_at"`
@@ -126,13 +189,18 @@ type OrderStep struct {
 
 	// 실행 조건
 	PreviousStepResult string `gorm:"size:20" json:"previous_step_result"` // SUCCESS, FAILURE, ALWAYS 등
+	Condition          string `gorm:"size:255" json:"condition"`           // 로봇 상태 조건식 (예: battery > 30 && operatingMode == AUTOMATIC), 거짓이면 건너뜀
 
 	// 노드 정보
 	NodeTemplateID *uint `json:"node_template_id"` // null이면 기본값 사용
 
+	// 합성 템플릿: 다른 템플릿을 하위 시퀀스로 참조 (실행 시점에 해당 템플릿의 단계로 펼쳐짐)
+	SubTemplateID *uint `gorm:"index" json:"sub_template_id"`
+
 	// 액션 순차 실행을 위한 설정
-	WaitForCompletion bool `gorm:"default:true" json:"wait_for_completion"` // 이 단계 완료를 기다릴지 여부
-	TimeoutSeconds    int  `gorm:"default:300" json:"timeout_seconds"`      // 타임아웃 (초)
+	WaitForCompletion bool   `gorm:"default:true" json:"wait_for_completion"` // 이 단계 완료를 기다릴지 여부
+	ParallelGroup     string `gorm:"size:50" json:"parallel_group"`           // 같은 값을 가진 연속 단계는 하나의 오더로 함께 전송되고 모두 끝나야 다음 단계로 진행
+	TimeoutSeconds    int    `gorm:"default:300" json:"timeout_seconds"`      // 타임아웃 (초)
 
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
@@ -141,6 +209,7 @@ type OrderStep struct {
 	// 관계
 	Template           OrderTemplate       `gorm:"foreignKey:TemplateID"`
 	NodeTemplate       *NodeTemplate       `gorm:"foreignKey:NodeTemplateID"`
+	SubTemplate        *OrderTemplate      `gorm:"foreignKey:SubTemplateID" json:"sub_template,omitempty"`
 	StepActionMappings []StepActionMapping `gorm:"foreignKey:OrderStepID"`
 	Edges              []EdgeTemplate      `gorm:"foreignKey:OrderStepID" json:"edges"`
 }
@@ -149,8 +218,11 @@ type OrderStep struct {
 type CommandExecution struct {
 	ID                uint           `gorm:"primaryKey" json:"id"`
 	CommandID         uint           `gorm:"not null;index" json:"command_id"`
+	SiteID            string         `gorm:"size:50;not null;default:default;index" json:"site_id"`
+	SerialNumber      string         `gorm:"size:50;index" json:"serial_number"` // 명령을 수행하는 로봇 (재시작 후 재개 대상)
 	Status            string         `gorm:"size:20;not null" json:"status"`
 	CurrentOrderIndex int            `gorm:"default:0" json:"current_order_index"`
+	LastOrderID       uint           `gorm:"default:0" json:"last_order_id"` // 다음 순번 결정까지 끝난 마지막 오더 실행 ID (재시작 후 중복 분기 방지)
 	StartedAt         time.Time      `json:"started_at"`
 	CompletedAt       *time.Time     `json:"completed_at"`
 	CreatedAt         time.Time      `json:"created_at"`
@@ -164,18 +236,31 @@ type CommandExecution struct {
 
 // OrderExecution 개별 오더 실행
 type OrderExecution struct {
-	ID                 uint           `gorm:"primaryKey" json:"id"`
-	CommandExecutionID uint           `gorm:"not null;index" json:"command_execution_id"`
-	TemplateID         uint           `gorm:"not null;index" json:"template_id"`
-	OrderID            string         `gorm:"size:100;not null;uniqueIndex" json:"order_id"`
-	ExecutionOrder     int            `gorm:"not null" json:"execution_order"`
-	CurrentStep        int            `gorm:"default:0" json:"current_step"`
-	Status             string         `gorm:"size:20;not null" json:"status"`
-	StartedAt          time.Time      `json:"started_at"`
-	CompletedAt        *time.Time     `json:"completed_at"`
-	CreatedAt          time.Time      `json:"created_at"`
-	UpdatedAt          time.Time      `json:"updated_at"`
-	DeletedAt          gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+	ID                    uint           `gorm:"primaryKey" json:"id"`
+	CommandExecutionID    uint           `gorm:"not null;index" json:"command_execution_id"`
+	SiteID                string         `gorm:"size:50;not null;default:default;index" json:"site_id"`
+	SerialNumber          string         `gorm:"size:50;index" json:"serial_number"` // 오더를 수행한 로봇
+	TemplateID            uint           `gorm:"not null;index" json:"template_id"`
+	OrderID               string         `gorm:"size:100;not null;uniqueIndex" json:"order_id"`
+	CorrelationID         string         `gorm:"size:64;index" json:"correlation_id"`
+	ParameterOverrides    string         `gorm:"type:text" json:"parameter_overrides"` // 명령에서 복사한 자리표시자 치환 값 (JSON)
+	Transport             string         `gorm:"size:20" json:"transport"`             // 마지막 오더 메시지를 전달한 전송 경로 (mqtt, http)
+	ExecutionOrder        int            `gorm:"not null" json:"execution_order"`
+	CurrentStep           int            `gorm:"default:0" json:"current_step"`
+	QueuePosition         int            `gorm:"default:0" json:"queue_position"`        // 템플릿 동시 실행 제한으로 WAITING일 때의 대기 순번 (1부터)
+	LastNodeID            string         `gorm:"size:100" json:"last_node_id"`           // 로봇이 마지막으로 통과한 노드 (state.lastNodeId)
+	LastNodeSequenceID    int            `gorm:"default:0" json:"last_node_sequence_id"` // state.lastNodeSequenceId
+	RemainingNodes        string         `gorm:"type:text" json:"remaining_nodes"`       // 아직 통과하지 않은 노드 ID (JSON 배열, sequenceId 순)
+	Status                string         `gorm:"size:20;not null" json:"status"`
+	StartedAt             time.Time      `json:"started_at"`
+	CompletedAt           *time.Time     `json:"completed_at"`
+	EstimatedCompletionAt *time.Time     `json:"estimated_completion_at"`       // 과거 실행 이력으로 추정한 완료 예상 시각 (단계 시작마다 갱신)
+	DeadlineAt            *time.Time     `gorm:"index" json:"deadline_at"`      // 이 시각이 지나도 RUNNING이면 EXPIRED로 종료 (nil이면 제한 없음)
+	PausedAt              *time.Time     `json:"paused_at"`                     // PAUSED가 된 시각 (재개하면 그만큼 DeadlineAt을 늦춤)
+	ErrorMessage          string         `gorm:"size:500" json:"error_message"` // 로봇이 거부한 경우 로봇의 오류 내용
+	CreatedAt             time.Time      `json:"created_at"`
+	UpdatedAt             time.Time      `json:"updated_at"`
+	DeletedAt             gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 
 	// 관계
 	CommandExecution CommandExecution `gorm:"foreignKey:CommandExecutionID"`
@@ -192,9 +277,11 @@ type StepExecution struct {
 	Result              string         `gorm:"size:20" json:"result"`
 	SentToRobot         bool           `gorm:"default:false" json:"sent_to_robot"`
 	ActionCompleted     bool           `gorm:"default:false" json:"action_completed"`
-	ExpectedActionCount int            `json:"expected_action_count"` // (추가) 이 단계에서 기대하는 총 액션 개수
+	ExpectedActionCount int            `json:"expected_action_count"`          // (추가) 이 단계에서 기대하는 총 액션 개수
+	ParallelGroup       string         `gorm:"size:100" json:"parallel_group"` // 함께 전송된 병렬 그룹 (하위 템플릿 그룹은 템플릿 이름이 앞에 붙음)
 	LastActionCheck     time.Time      `json:"last_action_check"`
 	StartedAt           time.Time      `json:"started_at"`
+	TimeoutAt           *time.Time     `gorm:"index" json:"timeout_at"` // 이 시각이 지나도 RUNNING이면 TIMEOUT으로 실패 (OrderStep.TimeoutSeconds, 일시정지한 시간만큼 늦춤)
 	CompletedAt         *time.Time     `json:"completed_at"`
 	ErrorMessage        string         `gorm:"size:500" json:"error_message"`
 	CreatedAt           time.Time      `json:"created_at"`
@@ -267,3 +354,15 @@ type OrderEdge struct {
 
 // 호환성을 위한 Float64 타입 별칭 (기존 코드와의 호환성)
 type Float64 = types.Float64
+
+// ActionStatusTransition 단계 실행 중 관찰된 액션 상태 전이 기록 (타임라인 재구성용)
+type ActionStatusTransition struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	StepExecutionID   uint      `gorm:"not null;index" json:"step_execution_id"`
+	ActionID          string    `gorm:"size:100;not null" json:"action_id"`
+	ActionType        string    `gorm:"size:100" json:"action_type"`
+	FromStatus        string    `gorm:"size:20" json:"from_status"`
+	ToStatus          string    `gorm:"size:20;not null" json:"to_status"`
+	ResultDescription string    `gorm:"size:500" json:"result_description"`
+	ObservedAt        time.Time `gorm:"not null" json:"observed_at"`
+}
@@ -0,0 +1,26 @@
+// internal/models/outbox.go
+package models
+
+import (
+	"time"
+)
+
+// OutboxMessage 트랜잭션 아웃박스 메시지 (DB 커밋 후 디스패처가 MQTT 또는 대체 전송 경로로 발행)
+type OutboxMessage struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	Topic           string     `gorm:"size:255;not null" json:"topic"`
+	MessageType     string     `gorm:"size:50;not null" json:"message_type"`
+	OrderID         string     `gorm:"size:100;index" json:"order_id"`
+	CorrelationID   string     `gorm:"size:64;index" json:"correlation_id"`
+	HeaderID        int64      `json:"header_id"`                      // 발행 메시지의 VDA5050 headerId
+	StepExecutionID *uint      `gorm:"index" json:"step_execution_id"` // 발행 성공 시 SentToRobot 갱신 대상
+	Payload         string     `gorm:"type:text;not null" json:"payload"`
+	Status          string     `gorm:"size:20;not null;index" json:"status"` // PENDING, SENT, FAILED
+	Attempts        int        `gorm:"default:0" json:"attempts"`
+	ChunksSent      int        `gorm:"default:0" json:"chunks_sent"` // 나눠 보내는 오더에서 발행한 조각 수 (재시도 시 다음 조각부터)
+	LastError       string     `gorm:"size:500" json:"last_error"`
+	Transport       string     `gorm:"size:20" json:"transport"` // 발행에 성공한 전송 경로 (mqtt, http)
+	SentAt          *time.Time `json:"sent_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,21 @@
+// internal/models/plc_response.go
+package models
+
+import (
+	"time"
+)
+
+// PLCResponse PLC로 보낸 응답 기록 (명령 이력의 응답 기록)
+// 명령 하나에 접수 거부, 진행률, 최종 결과 등 여러 응답이 상관 ID로 연결됩니다.
+type PLCResponse struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	SiteID        string    `gorm:"size:50;not null;default:default;index" json:"site_id"`
+	CorrelationID string    `gorm:"size:64;index" json:"correlation_id"`
+	Command       string    `gorm:"size:100" json:"command"`
+	Status        string    `gorm:"size:20" json:"status"`                   // 내부 상태 (S, F, X, 진행률 R:2/5 등)
+	SentStatus    string    `gorm:"size:20" json:"sent_status"`              // 상태 매핑을 적용해 실제로 보낸 값
+	Code          string    `gorm:"size:50" json:"code,omitempty"`           // 실패 시 오류 코드
+	ErrorMessage  string    `gorm:"size:500" json:"error_message,omitempty"` // 실패 시 PLC에 보낸 오류 내용
+	PublishError  string    `gorm:"size:500" json:"publish_error,omitempty"` // 발행 자체가 실패한 경우
+	SentAt        time.Time `gorm:"not null;index" json:"sent_at"`
+}
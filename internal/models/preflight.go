@@ -0,0 +1,15 @@
+// internal/models/preflight.go
+package models
+
+import "time"
+
+// PreflightOverride 로봇별로 끈 오더 전송 전 사전 점검
+type PreflightOverride struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	SiteID         string    `gorm:"size:50;not null;default:default;uniqueIndex:idx_preflight_override_site_serial" json:"site_id"`
+	SerialNumber   string    `gorm:"size:50;not null;uniqueIndex:idx_preflight_override_site_serial" json:"serial_number"`
+	DisabledChecks string    `gorm:"size:255;not null" json:"disabled_checks"` // 쉼표 구분 점검 이름
+	Reason         string    `gorm:"size:255" json:"reason"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
@@ -0,0 +1,31 @@
+// internal/models/retention.go
+package models
+
+import "time"
+
+// PurgeRun 실행 이력 보존 정책에 따른 정리 실행 기록
+type PurgeRun struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	SiteID      string     `gorm:"size:50;not null;default:default;index" json:"site_id"`
+	Trigger     string     `gorm:"size:20;not null" json:"trigger"` // SCHEDULED, MANUAL
+	DryRun      bool       `gorm:"default:false" json:"dry_run"`
+	ArchiveMode string     `gorm:"size:20" json:"archive_mode"` // none, file, table
+	ArchivePath string     `gorm:"size:500" json:"archive_path"`
+	Status      string     `gorm:"size:20;not null;index" json:"status"` // RUNNING, COMPLETED, FAILED
+	Counts      string     `gorm:"type:text" json:"counts"`              // 테이블별 정리(또는 대상) 건수 (JSON)
+	Error       string     `gorm:"size:500" json:"error"`
+	StartedAt   time.Time  `gorm:"not null;index" json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// ArchivedRecord 정리 시 보관 테이블에 옮겨 둔 실행 이력 행 (RETENTION_ARCHIVE=table)
+type ArchivedRecord struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	PurgeRunID  uint      `gorm:"not null;index" json:"purge_run_id"`
+	SourceTable string    `gorm:"size:100;not null;index:idx_archived_records_table_record" json:"source_table"`
+	RecordID    uint      `gorm:"not null;index:idx_archived_records_table_record" json:"record_id"`
+	Payload     string    `gorm:"type:text;not null" json:"payload"` // 원본 행 (컬럼 → 값 JSON)
+	ArchivedAt  time.Time `gorm:"not null" json:"archived_at"`
+}
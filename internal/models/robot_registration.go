@@ -0,0 +1,36 @@
+// internal/models/robot_registration.go
+package models
+
+import (
+	"time"
+)
+
+// RobotRegistration 제조사 와일드카드 구독으로 발견한 로봇의 등록/승인 상태
+// 처음 보는 시리얼 번호는 PENDING으로 등록되고, 운영자가 승인(APPROVED)해야 오더를 받을 수 있습니다.
+type RobotRegistration struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	SiteID       string     `gorm:"size:50;not null;default:default;uniqueIndex:idx_robot_registrations_site_serial" json:"site_id"`
+	SerialNumber string     `gorm:"size:50;not null;uniqueIndex:idx_robot_registrations_site_serial" json:"serial_number"`
+	Manufacturer string     `gorm:"size:50;not null" json:"manufacturer"`
+	RobotGroup   string     `gorm:"size:50;index" json:"group,omitempty"` // 운영 그룹 (일괄 등록 시 지정)
+	Status       string     `gorm:"size:20;not null;index" json:"status"` // PENDING, APPROVED, REJECTED
+	Note         string     `gorm:"size:255" json:"note,omitempty"`       // 승인/거부 사유
+	LastTopic    string     `gorm:"size:255" json:"last_topic"`           // 마지막으로 메시지를 받은 토픽
+	FirstSeenAt  time.Time  `gorm:"not null" json:"first_seen_at"`
+	LastSeenAt   time.Time  `gorm:"not null" json:"last_seen_at"`
+	DecidedAt    *time.Time `json:"decided_at,omitempty"` // 운영자가 승인/거부한 시각
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// RobotMetadata 로봇별 운영 메타데이터 키/값 (위치, 담당 팀, 하드웨어 리비전 등)
+// 로봇 목록에 함께 표시되고, 일괄 작업과 알림 대상을 고르는 셀렉터(key=value)에 사용됩니다.
+type RobotMetadata struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	SiteID       string    `gorm:"size:50;not null;default:default;uniqueIndex:idx_robot_metadata_site_serial_key" json:"site_id"`
+	SerialNumber string    `gorm:"size:50;not null;uniqueIndex:idx_robot_metadata_site_serial_key" json:"serial_number"`
+	Key          string    `gorm:"size:63;not null;uniqueIndex:idx_robot_metadata_site_serial_key" json:"key"`
+	Value        string    `gorm:"size:255;not null" json:"value"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
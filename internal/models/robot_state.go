@@ -135,9 +135,9 @@ type InitPositionRequest struct {
 
 // PoseValue 위치 정보
 type PoseValue struct {
-	LastNodeID string  `json:"lastNodeId"`
-	MapID      string  `json:"mapId"`
-	Theta      float64 `json:"theta"`
+	LastNodeID string  `json:"lastNodeId" validate:"max=100"`
+	MapID      string  `json:"mapId" validate:"required,max=100"`
+	Theta      float64 `json:"theta" validate:"min=-pi,max=pi"`
 	X          float64 `json:"x"`
 	Y          float64 `json:"y"`
 }
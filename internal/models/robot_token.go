@@ -0,0 +1,24 @@
+// internal/models/robot_token.go
+package models
+
+import "time"
+
+// RobotToken 로봇이 HTTP로 결과/state를 보낼 때 쓰는 세션 토큰
+// 토큰 원문은 발급할 때 한 번만 보여주고 비밀 부분의 SHA-256만 저장합니다.
+type RobotToken struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	SiteID       string     `gorm:"size:50;not null;default:default;index:idx_robot_token_site_serial" json:"site_id"`
+	SerialNumber string     `gorm:"size:50;not null;index:idx_robot_token_site_serial" json:"serial_number"`
+	TokenID      string     `gorm:"size:40;not null;uniqueIndex" json:"token_id"` // 토큰 앞부분 (공개 식별자)
+	TokenHash    string     `gorm:"size:64;not null" json:"-"`
+	Label        string     `gorm:"size:100" json:"label"`
+	ExpiresAt    *time.Time `json:"expires_at"` // nil이면 만료 없음
+	RevokedAt    *time.Time `json:"revoked_at"`
+	LastUsedAt   *time.Time `json:"last_used_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// Active 폐기되지 않았고 만료되지 않은 토큰인지 확인
+func (t *RobotToken) Active(now time.Time) bool {
+	return t.RevokedAt == nil && (t.ExpiresAt == nil || now.Before(*t.ExpiresAt))
+}
@@ -0,0 +1,139 @@
+// internal/notifier/channels.go
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mqtt-bridge/internal/common/constants"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// Alert 채널로 전송할 알림
+type Alert struct {
+	Rule     string    `json:"rule"`
+	Subject  string    `json:"subject"`
+	Severity string    `json:"severity"`
+	SiteID   string    `json:"site_id"`
+	Message  string    `json:"message"`
+	Time     time.Time `json:"time"`
+	Channel  string    `json:"channel,omitempty"` // 이 채널로만 전송 (비어 있으면 모든 채널)
+}
+
+// Title 채널 공통 제목 (예: "[CRITICAL] robot_offline: AGV-01")
+func (a Alert) Title() string {
+	return fmt.Sprintf("[%s] %s: %s", a.Severity, a.Rule, a.Subject)
+}
+
+// Channel 알림 전송 채널
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, alert Alert) error
+}
+
+// SlackChannel Slack Incoming Webhook 채널
+type SlackChannel struct {
+	url    string
+	client *http.Client
+}
+
+// NewSlackChannel 새 Slack 웹훅 채널 생성
+func NewSlackChannel(webhookURL string) *SlackChannel {
+	return &SlackChannel{url: webhookURL, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Name 채널 이름
+func (c *SlackChannel) Name() string {
+	return constants.AlertChannelSlack
+}
+
+// Send 웹훅으로 알림 메시지 전송
+func (c *SlackChannel) Send(ctx context.Context, alert Alert) error {
+	icon := ":warning:"
+	switch alert.Severity {
+	case constants.AlertSeverityCritical:
+		icon = ":rotating_light:"
+	case constants.AlertSeverityInfo:
+		icon = ":information_source:"
+	}
+	body, err := json.Marshal(map[string]string{
+		"text": fmt.Sprintf("%s *%s*\n%s\n_site %s, %s_", icon, alert.Title(), alert.Message,
+			alert.SiteID, alert.Time.Format(time.RFC3339)),
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("slack webhook returned %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// SMTPChannel 이메일 채널
+type SMTPChannel struct {
+	addr     string
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+// NewSMTPChannel 새 SMTP 채널 생성 (username이 비어 있으면 인증 없이 전송)
+func NewSMTPChannel(addr, username, password, from string, to []string) (*SMTPChannel, error) {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return nil, fmt.Errorf("invalid SMTP address %q: use host:port", addr)
+	}
+	if from == "" || len(to) == 0 {
+		return nil, fmt.Errorf("SMTP alerts need a sender and at least one recipient")
+	}
+	return &SMTPChannel{addr: addr, username: username, password: password, from: from, to: to}, nil
+}
+
+// Name 채널 이름
+func (c *SMTPChannel) Name() string {
+	return constants.AlertChannelEmail
+}
+
+// Send 알림을 평문 메일로 전송
+func (c *SMTPChannel) Send(ctx context.Context, alert Alert) error {
+	var auth smtp.Auth
+	if c.username != "" {
+		host, _, _ := net.SplitHostPort(c.addr)
+		auth = smtp.PlainAuth("", c.username, c.password, host)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", c.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(c.to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", alert.Title())
+	fmt.Fprintf(&msg, "Date: %s\r\n", alert.Time.Format(time.RFC1123Z))
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	fmt.Fprintf(&msg, "%s\r\n\r\nRule: %s\r\nSubject: %s\r\nSite: %s\r\nTime: %s\r\n",
+		alert.Message, alert.Rule, alert.Subject, alert.SiteID, alert.Time.Format(time.RFC3339))
+
+	done := make(chan error, 1)
+	go func() { done <- smtp.SendMail(c.addr, auth, c.from, c.to, msg.Bytes()) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
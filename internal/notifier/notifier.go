@@ -0,0 +1,512 @@
+// internal/notifier/notifier.go
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/config"
+	"mqtt-bridge/internal/messaging"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/utils"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// sendTimeout 알림 하나를 모든 채널로 보내는 최대 시간
+const sendTimeout = 15 * time.Second
+
+// Notifier 이 브릿지가 관리하는 로봇의 상태를 주기적으로 평가하여 규칙에 맞으면 알림을 보냅니다.
+// 같은 대상의 같은 규칙은 cooldown 동안 다시 보내지 않으며, 억제 시간대의 알림은 이력에만 남깁니다.
+// 로봇 셀렉터가 있으면 로봇 메타데이터가 일치할 때만 규칙을 평가합니다.
+type Notifier struct {
+	db           *gorm.DB
+	siteID       string
+	serialNumber string
+	rules        Rules
+	interval     time.Duration
+	cooldown     time.Duration
+	channels     []Channel
+	selector     *repository.MetadataSelector
+	latency      LatencySource // state_latency, clock_drift 규칙용 (없으면 두 규칙을 평가하지 않음)
+
+	mu         sync.Mutex
+	lastSent   map[string]time.Time // 규칙|대상 → 마지막 처리 시각
+	battery    float64
+	hasBattery bool
+	lastScan   time.Time // 이 시각 이후 끝난 명령 실패만 알림
+	orderScan  time.Time // 이 시각 이후 끝난 오더만 알림
+
+	cancel context.CancelFunc
+	doneCh chan struct{}
+}
+
+// LatencySource 로봇별 state 메시지 지연 조회 인터페이스
+type LatencySource interface {
+	Latency(serialNumber string) (messaging.RobotLatency, bool)
+}
+
+// NewFromConfig 설정에 따라 알림기 생성 (채널이 하나도 없으면 nil 반환)
+func NewFromConfig(db *gorm.DB, cfg *config.Config) (*Notifier, error) {
+	var channels []Channel
+	if cfg.AlertSlackWebhookURL != "" {
+		channels = append(channels, NewSlackChannel(cfg.AlertSlackWebhookURL))
+	}
+	if cfg.AlertSMTPAddr != "" {
+		var to []string
+		for _, addr := range strings.Split(cfg.AlertSMTPTo, ",") {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				to = append(to, addr)
+			}
+		}
+		channel, err := NewSMTPChannel(cfg.AlertSMTPAddr, cfg.AlertSMTPUsername, cfg.AlertSMTPPassword, cfg.AlertSMTPFrom, to)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, channel)
+	}
+	if len(channels) == 0 {
+		return nil, nil
+	}
+
+	rules, err := ParseRules(cfg.AlertRules)
+	if err != nil {
+		return nil, err
+	}
+	selector, err := repository.ParseMetadataSelector(cfg.AlertRobotSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ALERT_ROBOT_SELECTOR: %w", err)
+	}
+	n := New(db, cfg.SiteID, cfg.RobotSerialNumber, rules, cfg.AlertInterval, cfg.AlertCooldown, channels)
+	n.SetRobotSelector(selector)
+	return n, nil
+}
+
+// New 새 알림기 생성
+func New(db *gorm.DB, siteID, serialNumber string, rules Rules, interval, cooldown time.Duration, channels []Channel) *Notifier {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	return &Notifier{
+		db:           db,
+		siteID:       siteID,
+		serialNumber: serialNumber,
+		rules:        rules,
+		interval:     interval,
+		cooldown:     cooldown,
+		channels:     channels,
+		lastSent:     make(map[string]time.Time),
+		lastScan:     time.Now(),
+		orderScan:    time.Now(),
+	}
+}
+
+// SetRobotSelector 로봇 메타데이터가 셀렉터와 일치할 때만 규칙을 평가하도록 설정 (nil이면 항상 평가)
+func (n *Notifier) SetRobotSelector(selector *repository.MetadataSelector) {
+	n.selector = selector
+}
+
+// SetLatencySource state_latency, clock_drift 규칙이 쓸 로봇별 지연 조회 대상 설정
+func (n *Notifier) SetLatencySource(source LatencySource) {
+	n.latency = source
+}
+
+// Rules 켜진 알림 규칙
+func (n *Notifier) Rules() Rules {
+	return n.rules
+}
+
+// ChannelNames 설정된 채널 이름 목록
+func (n *Notifier) ChannelNames() []string {
+	names := make([]string, 0, len(n.channels))
+	for _, channel := range n.channels {
+		names = append(names, channel.Name())
+	}
+	return names
+}
+
+// ObserveState 배터리 규칙 평가를 위해 로봇의 최신 잔량 기록
+func (n *Notifier) ObserveState(state *models.RobotStateMessage) {
+	if state.SerialNumber != n.serialNumber {
+		return
+	}
+	n.mu.Lock()
+	n.battery = state.BatteryState.BatteryCharge
+	n.hasBattery = true
+	n.mu.Unlock()
+}
+
+// Start 주기적 규칙 평가 시작
+func (n *Notifier) Start(ctx context.Context) {
+	ctx, n.cancel = context.WithCancel(ctx)
+	n.doneCh = make(chan struct{})
+	go n.run(ctx)
+	if n.selector.Empty() {
+		utils.Logger.Infof("✅ Alert notifier started (rules: %s, channels: %s, interval %v)",
+			n.rules, strings.Join(n.ChannelNames(), ","), n.interval)
+	} else {
+		utils.Logger.Infof("✅ Alert notifier started (rules: %s, channels: %s, interval %v, robot selector %q)",
+			n.rules, strings.Join(n.ChannelNames(), ","), n.interval, n.selector)
+	}
+}
+
+// Stop 규칙 평가 중지
+func (n *Notifier) Stop() {
+	if n.cancel == nil {
+		return
+	}
+	n.cancel()
+	<-n.doneCh
+	n.cancel = nil
+}
+
+func (n *Notifier) run(ctx context.Context) {
+	defer close(n.doneCh)
+	ticker := time.NewTicker(n.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			n.Evaluate(ctx, time.Now())
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Evaluate 켜진 규칙을 한 번 평가하여 조건에 맞는 알림 전송
+func (n *Notifier) Evaluate(ctx context.Context, now time.Time) {
+	if !n.robotSelected() {
+		n.mu.Lock()
+		n.lastScan = now // 선택되지 않은 동안의 명령 실패와 오더 종료는 나중에 알리지 않음
+		n.orderScan = now
+		n.mu.Unlock()
+		return
+	}
+	var alerts []Alert
+	if n.rules.RobotOffline > 0 {
+		alerts = append(alerts, n.checkRobotOffline(now)...)
+	}
+	if n.rules.CommandFailed {
+		alerts = append(alerts, n.checkCommandFailures(now)...)
+	}
+	if n.rules.BatteryLow > 0 {
+		alerts = append(alerts, n.checkBattery(now)...)
+	}
+	if n.rules.OrderStuck > 0 {
+		alerts = append(alerts, n.checkStuckOrders(now)...)
+	}
+	if (n.rules.StateLatency > 0 || n.rules.ClockDrift > 0) && n.latency != nil {
+		alerts = append(alerts, n.checkLatency(now)...)
+	}
+	if n.rules.OrderFinished {
+		alerts = append(alerts, n.checkFinishedOrders(now)...)
+	}
+	for _, alert := range alerts {
+		n.Notify(ctx, alert)
+	}
+}
+
+// robotSelected 로봇 메타데이터가 셀렉터와 일치하는지 (메타데이터는 실행 중에 바뀔 수 있어 매번 조회)
+func (n *Notifier) robotSelected() bool {
+	if n.selector.Empty() {
+		return true
+	}
+	metadata, err := repository.GetRobotMetadata(n.db, n.siteID, n.serialNumber)
+	if err != nil {
+		utils.Logger.Errorf("❌ Failed to read metadata of robot %s for alert selector: %v", n.serialNumber, err)
+		return false
+	}
+	return n.selector.Matches(metadata)
+}
+
+// checkRobotOffline 로봇이 기준 시간 이상 ONLINE이 아니고 메시지도 없으면 알림
+func (n *Notifier) checkRobotOffline(now time.Time) []Alert {
+	var status models.RobotStatus
+	err := n.db.Scopes(repository.SiteScope(n.siteID)).Where("serial_number = ?", n.serialNumber).First(&status).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			utils.Logger.Errorf("❌ Alert rule %s failed: %v", constants.AlertRuleRobotOffline, err)
+		}
+		return nil
+	}
+	silent := now.Sub(status.LastTimestamp)
+	if status.ConnectionState == constants.ConnectionStateOnline || silent < n.rules.RobotOffline {
+		return nil
+	}
+	return []Alert{n.alert(constants.AlertRuleRobotOffline, n.serialNumber, constants.AlertSeverityCritical, now,
+		"Robot %s is %s and has not reported for %s (last seen %s)",
+		n.serialNumber, status.ConnectionState, silent.Round(time.Second), status.LastTimestamp.Format(time.RFC3339))}
+}
+
+// checkCommandFailures 마지막 평가 이후 실패로 끝난 이 로봇의 명령마다 알림
+func (n *Notifier) checkCommandFailures(now time.Time) []Alert {
+	n.mu.Lock()
+	since := n.lastScan
+	n.lastScan = now
+	n.mu.Unlock()
+
+	var executions []models.CommandExecution
+	err := n.db.Where("site_id = ? AND serial_number = ? AND status = ? AND completed_at > ? AND completed_at <= ?",
+		n.siteID, n.serialNumber, constants.CommandExecutionStatusFailed, since, now).
+		Preload("Command.CommandDefinition").
+		Order("completed_at ASC").
+		Find(&executions).Error
+	if err != nil {
+		utils.Logger.Errorf("❌ Alert rule %s failed: %v", constants.AlertRuleCommandFailed, err)
+		return nil
+	}
+
+	alerts := make([]Alert, 0, len(executions))
+	for _, execution := range executions {
+		reason := execution.Command.ErrorMessage
+		if reason == "" {
+			reason = "no error message"
+		}
+		alerts = append(alerts, n.alert(constants.AlertRuleCommandFailed,
+			fmt.Sprintf("%s#%d", execution.Command.CommandDefinition.CommandType, execution.ID),
+			constants.AlertSeverityWarning, now,
+			"Command %s on robot %s failed (cid=%s): %s",
+			execution.Command.CommandDefinition.CommandType, n.serialNumber, execution.Command.CorrelationID, reason))
+	}
+	return alerts
+}
+
+// checkBattery 마지막으로 받은 배터리 잔량이 기준 미만이면 알림
+func (n *Notifier) checkBattery(now time.Time) []Alert {
+	n.mu.Lock()
+	charge, ok := n.battery, n.hasBattery
+	n.mu.Unlock()
+	if !ok || charge >= n.rules.BatteryLow {
+		return nil
+	}
+	return []Alert{n.alert(constants.AlertRuleBatteryLow, n.serialNumber, constants.AlertSeverityWarning, now,
+		"Robot %s battery at %.1f%% (below %g%%)", n.serialNumber, charge, n.rules.BatteryLow)}
+}
+
+// checkStuckOrders 기준 시간 이상 RUNNING인 이 로봇의 오더마다 알림
+func (n *Notifier) checkStuckOrders(now time.Time) []Alert {
+	var executions []models.OrderExecution
+	err := n.db.Scopes(repository.SiteScope(n.siteID)).
+		Where("serial_number = ? AND status = ? AND started_at < ?",
+			n.serialNumber, constants.OrderExecutionStatusRunning, now.Add(-n.rules.OrderStuck)).
+		Order("started_at ASC").
+		Find(&executions).Error
+	if err != nil {
+		utils.Logger.Errorf("❌ Alert rule %s failed: %v", constants.AlertRuleOrderStuck, err)
+		return nil
+	}
+
+	alerts := make([]Alert, 0, len(executions))
+	for _, execution := range executions {
+		alerts = append(alerts, n.alert(constants.AlertRuleOrderStuck, execution.OrderID, constants.AlertSeverityWarning, now,
+			"Order %s on robot %s has been RUNNING for %s (step %d, cid=%s)",
+			execution.OrderID, n.serialNumber, now.Sub(execution.StartedAt).Round(time.Second),
+			execution.CurrentStep, execution.CorrelationID))
+	}
+	return alerts
+}
+
+// checkLatency 최근 state 메시지의 네트워크 지연 p95나 시계 오차 추정값이 기준을 넘으면 알림
+// 지연이 오더 수신 확인/재조정 제한 시간에 가까워지거나 시계 오차가 메시지 timestamp 검사를 깨뜨리기 전에 알립니다.
+func (n *Notifier) checkLatency(now time.Time) []Alert {
+	latency, ok := n.latency.Latency(n.serialNumber)
+	if !ok || latency.Samples < messaging.MinLatencySamples {
+		return nil
+	}
+	var alerts []Alert
+	network := time.Duration(latency.NetworkP95Ms * float64(time.Millisecond))
+	if n.rules.StateLatency > 0 && network > n.rules.StateLatency {
+		alerts = append(alerts, n.alert(constants.AlertRuleStateLatency, n.serialNumber, constants.AlertSeverityWarning, now,
+			"Robot %s state messages are delayed by %s (p95 over the last %d messages, threshold %s)",
+			n.serialNumber, network.Round(time.Millisecond), latency.Samples, n.rules.StateLatency))
+	}
+	offset := time.Duration(latency.ClockOffsetMs * float64(time.Millisecond))
+	if n.rules.ClockDrift > 0 && (offset > n.rules.ClockDrift || offset < -n.rules.ClockDrift) {
+		direction := "behind"
+		if offset < 0 {
+			direction = "ahead of"
+			offset = -offset
+		}
+		alerts = append(alerts, n.alert(constants.AlertRuleClockDrift, n.serialNumber, constants.AlertSeverityWarning, now,
+			"Robot %s clock is about %s %s the bridge clock (threshold %s)",
+			n.serialNumber, offset.Round(time.Millisecond), direction, n.rules.ClockDrift))
+	}
+	return alerts
+}
+
+// checkFinishedOrders 마지막 평가 이후 끝난 이 로봇의 오더를 템플릿 알림 설정에 따라 알림
+// 실패는 템플릿이 정한 채널로 WARNING을 보내고, 같은 템플릿의 연속 실패가 에스컬레이션 기준에 닿으면
+// 채널 제한 없이 CRITICAL로 보냅니다. notify_on=ALL이면 완료된 오더도 INFO로 알립니다.
+func (n *Notifier) checkFinishedOrders(now time.Time) []Alert {
+	n.mu.Lock()
+	since := n.orderScan
+	n.orderScan = now
+	n.mu.Unlock()
+
+	var executions []models.OrderExecution
+	err := n.db.Scopes(repository.SiteScope(n.siteID)).
+		Where("serial_number = ? AND status IN ? AND completed_at > ? AND completed_at <= ?",
+			n.serialNumber, append([]string{constants.OrderExecutionStatusCompleted}, repository.OrderFailureStatuses...), since, now).
+		Preload("Template").
+		Order("completed_at ASC").
+		Find(&executions).Error
+	if err != nil {
+		utils.Logger.Errorf("❌ Alert rule %s failed: %v", constants.AlertRuleOrderFinished, err)
+		return nil
+	}
+
+	var alerts []Alert
+	for _, execution := range executions {
+		template := execution.Template
+		notifyOn := template.NotifyOn
+		if notifyOn == "" {
+			notifyOn = constants.TemplateNotifyFailure
+		}
+		if notifyOn == constants.TemplateNotifyNone {
+			continue
+		}
+
+		if execution.Status == constants.OrderExecutionStatusCompleted {
+			if notifyOn != constants.TemplateNotifyAll {
+				continue
+			}
+			alert := n.alert(constants.AlertRuleOrderFinished, execution.OrderID, constants.AlertSeverityInfo, now,
+				"Order %s (template %s) on robot %s completed (cid=%s)",
+				execution.OrderID, template.Name, n.serialNumber, execution.CorrelationID)
+			alert.Channel = template.NotifyChannel
+			alerts = append(alerts, alert)
+			continue
+		}
+
+		reason := execution.ErrorMessage
+		if reason == "" {
+			reason = "no error message"
+		}
+		severity, channel := constants.AlertSeverityWarning, template.NotifyChannel
+		escalation := ""
+		if template.NotifyEscalateAfter > 0 {
+			failures, err := repository.ConsecutiveTemplateFailures(n.db, n.siteID, template.ID, *execution.CompletedAt, template.NotifyEscalateAfter)
+			if err != nil {
+				utils.Logger.Warnf("⚠️ Failed to count consecutive failures of template %d: %v", template.ID, err)
+			} else if failures >= template.NotifyEscalateAfter {
+				severity, channel = constants.AlertSeverityCritical, ""
+				escalation = fmt.Sprintf(", %d consecutive failures", failures)
+			}
+		}
+		alert := n.alert(constants.AlertRuleOrderFinished, execution.OrderID, severity, now,
+			"Order %s (template %s) on robot %s ended %s%s (cid=%s): %s",
+			execution.OrderID, template.Name, n.serialNumber, execution.Status, escalation, execution.CorrelationID, reason)
+		alert.Channel = channel
+		alerts = append(alerts, alert)
+	}
+	return alerts
+}
+
+func (n *Notifier) alert(rule, subject, severity string, now time.Time, format string, args ...interface{}) Alert {
+	return Alert{
+		Rule:     rule,
+		Subject:  subject,
+		Severity: severity,
+		SiteID:   n.siteID,
+		Message:  fmt.Sprintf(format, args...),
+		Time:     now,
+	}
+}
+
+// Notify 알림을 모든 채널로 전송하고 이력에 기록
+// cooldown 안에 같은 규칙/대상으로 이미 처리했으면 무시하고, 억제 시간대면 전송하지 않고 SUPPRESSED로 기록합니다.
+func (n *Notifier) Notify(ctx context.Context, alert Alert) *models.AlertEvent {
+	key := alert.Rule + "|" + alert.Subject
+	n.mu.Lock()
+	if last, ok := n.lastSent[key]; ok && alert.Time.Sub(last) < n.cooldown {
+		n.mu.Unlock()
+		return nil
+	}
+	n.lastSent[key] = alert.Time
+	n.mu.Unlock()
+
+	event := &models.AlertEvent{
+		SiteID:   n.siteID,
+		Rule:     alert.Rule,
+		Subject:  alert.Subject,
+		Severity: alert.Severity,
+		Message:  alert.Message,
+	}
+
+	suppression, err := repository.ActiveAlertSuppression(n.db, n.siteID, alert.Rule, alert.Time)
+	if err != nil {
+		utils.Logger.Warnf("⚠️ Failed to check alert suppressions: %v", err)
+	}
+	if suppression != nil {
+		event.Status = constants.AlertStatusSuppressed
+		event.Error = fmt.Sprintf("suppressed until %s: %s", suppression.EndsAt.Format(time.RFC3339), suppression.Reason)
+		utils.Logger.Infof("🔕 Alert %s suppressed until %s", alert.Title(), suppression.EndsAt.Format(time.RFC3339))
+	} else {
+		n.send(ctx, alert, event)
+	}
+
+	if err := n.db.Create(event).Error; err != nil {
+		utils.Logger.Errorf("❌ Failed to record alert %s: %v", alert.Title(), err)
+	}
+	return event
+}
+
+// SendTest 채널 설정 확인용 시험 알림 전송 (cooldown과 억제 시간대를 무시)
+func (n *Notifier) SendTest(ctx context.Context, message string) *models.AlertEvent {
+	if message == "" {
+		message = "Test alert from mqtt-bridge"
+	}
+	alert := n.alert(constants.AlertRuleTest, n.serialNumber, constants.AlertSeverityWarning, time.Now(), "%s", message)
+	event := &models.AlertEvent{
+		SiteID:   n.siteID,
+		Rule:     alert.Rule,
+		Subject:  alert.Subject,
+		Severity: alert.Severity,
+		Message:  alert.Message,
+	}
+	n.send(ctx, alert, event)
+	if err := n.db.Create(event).Error; err != nil {
+		utils.Logger.Errorf("❌ Failed to record alert %s: %v", alert.Title(), err)
+	}
+	return event
+}
+
+// send 채널마다 전송하고 결과를 이력에 반영 (하나라도 성공하면 SENT, alert.Channel이 있으면 그 채널만)
+func (n *Notifier) send(ctx context.Context, alert Alert, event *models.AlertEvent) {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	var sent, failures []string
+	for _, channel := range n.channels {
+		if alert.Channel != "" && channel.Name() != alert.Channel {
+			continue
+		}
+		if err := channel.Send(ctx, alert); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", channel.Name(), err))
+			continue
+		}
+		sent = append(sent, channel.Name())
+	}
+
+	if len(sent) == 0 && len(failures) == 0 {
+		failures = append(failures, fmt.Sprintf("channel %s is not configured", alert.Channel))
+	}
+	event.Channels = strings.Join(sent, ",")
+	event.Error = strings.Join(failures, "; ")
+	if len(event.Error) > 500 {
+		event.Error = event.Error[:500]
+	}
+	if len(sent) > 0 {
+		event.Status = constants.AlertStatusSent
+		utils.Logger.Infof("🔔 Alert sent via %s: %s", event.Channels, alert.Title())
+	} else {
+		event.Status = constants.AlertStatusFailed
+		utils.Logger.Errorf("❌ Alert %s not delivered: %s", alert.Title(), event.Error)
+	}
+}
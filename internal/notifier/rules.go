@@ -0,0 +1,124 @@
+// internal/notifier/rules.go
+package notifier
+
+import (
+	"fmt"
+	"mqtt-bridge/internal/common/constants"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rules 켜진 알림 규칙과 기준값
+type Rules struct {
+	RobotOffline  time.Duration // 0이면 끔
+	CommandFailed bool
+	BatteryLow    float64 // 잔량(%) 기준, 0이면 끔
+	OrderStuck    time.Duration
+	StateLatency  time.Duration // state 메시지 네트워크 지연 p95 기준, 0이면 끔
+	ClockDrift    time.Duration // 로봇 시계 오차(절댓값) 기준, 0이면 끔
+	OrderFinished bool          // 템플릿별 알림 설정(notify_on, 채널, 에스컬레이션)에 따라 끝난 오더 알림
+}
+
+// ParseRules "규칙=기준" 쉼표 목록 파싱 (예: "robot_offline=5m,command_failed,battery_low=15,order_stuck=30m,state_latency=2s")
+// 기준을 생략하면 기본값(오프라인 5분, 배터리 15%, 오더 정체 30분, state 지연 2초, 시계 오차 5초)을 사용합니다.
+func ParseRules(spec string) (Rules, error) {
+	var rules Rules
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, hasValue := strings.Cut(entry, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.TrimSpace(value)
+
+		switch name {
+		case constants.AlertRuleRobotOffline, constants.AlertRuleOrderStuck:
+			threshold := 5 * time.Minute
+			if name == constants.AlertRuleOrderStuck {
+				threshold = 30 * time.Minute
+			}
+			if hasValue {
+				d, err := time.ParseDuration(value)
+				if err != nil || d <= 0 {
+					return rules, fmt.Errorf("alert rule %s: invalid duration %q", name, value)
+				}
+				threshold = d
+			}
+			if name == constants.AlertRuleRobotOffline {
+				rules.RobotOffline = threshold
+			} else {
+				rules.OrderStuck = threshold
+			}
+		case constants.AlertRuleStateLatency, constants.AlertRuleClockDrift:
+			threshold := 2 * time.Second
+			if name == constants.AlertRuleClockDrift {
+				threshold = 5 * time.Second
+			}
+			if hasValue {
+				d, err := time.ParseDuration(value)
+				if err != nil || d <= 0 {
+					return rules, fmt.Errorf("alert rule %s: invalid duration %q", name, value)
+				}
+				threshold = d
+			}
+			if name == constants.AlertRuleStateLatency {
+				rules.StateLatency = threshold
+			} else {
+				rules.ClockDrift = threshold
+			}
+		case constants.AlertRuleCommandFailed, constants.AlertRuleOrderFinished:
+			if hasValue {
+				return rules, fmt.Errorf("alert rule %s takes no threshold", name)
+			}
+			if name == constants.AlertRuleCommandFailed {
+				rules.CommandFailed = true
+			} else {
+				rules.OrderFinished = true
+			}
+		case constants.AlertRuleBatteryLow:
+			rules.BatteryLow = 15
+			if hasValue {
+				percent, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+				if err != nil || percent <= 0 || percent > 100 {
+					return rules, fmt.Errorf("alert rule %s: invalid percentage %q", name, value)
+				}
+				rules.BatteryLow = percent
+			}
+		default:
+			return rules, fmt.Errorf("unknown alert rule %q", name)
+		}
+	}
+	return rules, nil
+}
+
+// String 켜진 규칙 요약 (로그용)
+func (r Rules) String() string {
+	var parts []string
+	if r.RobotOffline > 0 {
+		parts = append(parts, fmt.Sprintf("%s>%s", constants.AlertRuleRobotOffline, r.RobotOffline))
+	}
+	if r.CommandFailed {
+		parts = append(parts, constants.AlertRuleCommandFailed)
+	}
+	if r.BatteryLow > 0 {
+		parts = append(parts, fmt.Sprintf("%s<%g%%", constants.AlertRuleBatteryLow, r.BatteryLow))
+	}
+	if r.OrderStuck > 0 {
+		parts = append(parts, fmt.Sprintf("%s>%s", constants.AlertRuleOrderStuck, r.OrderStuck))
+	}
+	if r.StateLatency > 0 {
+		parts = append(parts, fmt.Sprintf("%s>%s", constants.AlertRuleStateLatency, r.StateLatency))
+	}
+	if r.ClockDrift > 0 {
+		parts = append(parts, fmt.Sprintf("%s>%s", constants.AlertRuleClockDrift, r.ClockDrift))
+	}
+	if r.OrderFinished {
+		parts = append(parts, constants.AlertRuleOrderFinished)
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
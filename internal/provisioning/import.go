@@ -0,0 +1,525 @@
+// internal/provisioning/import.go
+package provisioning
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 일괄 등록 종류
+const (
+	KindRobots  = "robots"  // serial_number, manufacturer, group, 홈 위치(map_id, x, y, theta, 허용 편차)
+	KindNodes   = "nodes"   // 노드 템플릿 (name 기준으로 갱신)
+	KindActions = "actions" // 액션 템플릿 라이브러리 (action_type + description 기준으로 갱신)
+)
+
+// importColumns 종류별 허용 컬럼 (앞의 required개는 필수)
+var importColumns = map[string]struct {
+	columns  []string
+	required int
+}{
+	KindRobots:  {[]string{"serial_number", "manufacturer", "group", "map_id", "x", "y", "theta", "allowed_deviation_xy", "allowed_deviation_theta"}, 2},
+	KindNodes:   {[]string{"name", "description", "map_id", "x", "y", "theta", "allowed_deviation_xy", "allowed_deviation_theta"}, 1},
+	KindActions: {[]string{"action_type", "description", "blocking_type", "parameters"}, 1},
+}
+
+// homePoseColumns 하나라도 값이 있으면 로봇 기본 위치(RobotDefaults)를 저장하는 컬럼
+var homePoseColumns = []string{"map_id", "x", "y", "theta", "allowed_deviation_xy", "allowed_deviation_theta"}
+
+// RowError 행 단위 검증 오류 (Row는 헤더를 1행으로 센 스프레드시트 행 번호)
+type RowError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// Report 일괄 등록 결과
+// 오류가 하나라도 있거나 검증만 요청하면 아무것도 저장하지 않습니다 (Committed=false).
+type Report struct {
+	Kind      string     `json:"kind"`
+	DryRun    bool       `json:"dry_run"`
+	Rows      int        `json:"rows"`
+	Created   int        `json:"created"`
+	Updated   int        `json:"updated"`
+	Committed bool       `json:"committed"`
+	Errors    []RowError `json:"errors"`
+}
+
+// Valid 행 오류가 없는지
+func (r *Report) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+// csvRow 헤더 이름으로 값을 꺼내는 데이터 행
+type csvRow struct {
+	number int
+	values map[string]string
+}
+
+func (r csvRow) get(column string) string {
+	return strings.TrimSpace(r.values[column])
+}
+
+// rowParser 행 하나의 값 변환 중 생긴 오류를 모음
+type rowParser struct {
+	row    csvRow
+	errors []RowError
+}
+
+func (p *rowParser) fail(column, format string, args ...interface{}) {
+	p.errors = append(p.errors, RowError{Row: p.row.number, Column: column, Message: fmt.Sprintf(format, args...)})
+}
+
+func (p *rowParser) required(column string) string {
+	value := p.row.get(column)
+	if value == "" {
+		p.fail(column, "%s is required", column)
+	}
+	return value
+}
+
+func (p *rowParser) float(column string) float64 {
+	raw := p.row.get(column)
+	if raw == "" {
+		return 0
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		p.fail(column, "%s must be a number, got %q", column, raw)
+	}
+	return value
+}
+
+func (p *rowParser) deviation(column string) float64 {
+	value := p.float(column)
+	if value < 0 {
+		p.fail(column, "%s must not be negative", column)
+	}
+	return value
+}
+
+// Import CSV 데이터를 검증하고 (dryRun이 아니고 오류가 없으면) 한 트랜잭션으로 저장합니다.
+// 구분자는 헤더에서 쉼표, 세미콜론, 탭 중 하나로 판별하므로 스프레드시트에서 내보낸 CSV를 그대로 받습니다.
+// 읽을 수 없는 입력만 error로 반환하고, 행 단위 문제는 Report.Errors에 담습니다.
+func Import(db *gorm.DB, siteID, kind string, data io.Reader, dryRun bool) (*Report, error) {
+	spec, ok := importColumns[kind]
+	if !ok {
+		return nil, apperr.Validation("kind", "unsupported import kind %q (robots, nodes, actions)", kind)
+	}
+	rows, headerErrors, err := readCSV(data, spec.columns, spec.required)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{Kind: kind, DryRun: dryRun, Rows: len(rows), Errors: headerErrors}
+	if !report.Valid() {
+		return report, nil
+	}
+
+	var apply func(tx *gorm.DB) error
+	switch kind {
+	case KindRobots:
+		apply, err = planRobots(db, siteID, rows, report)
+	case KindNodes:
+		apply, err = planNodes(db, siteID, rows, report)
+	case KindActions:
+		apply, err = planActions(db, rows, report)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if report.Errors == nil {
+		report.Errors = []RowError{}
+	}
+	if dryRun || !report.Valid() {
+		return report, nil
+	}
+
+	if err := db.Transaction(apply); err != nil {
+		return nil, fmt.Errorf("failed to import %s: %w", kind, err)
+	}
+	report.Committed = true
+	utils.Logger.Infof("📥 Imported %d %s (%d created, %d updated)", report.Rows, kind, report.Created, report.Updated)
+	return report, nil
+}
+
+// readCSV 헤더를 확인하고 데이터 행을 읽습니다 (빈 행은 건너뜀).
+func readCSV(data io.Reader, columns []string, required int) ([]csvRow, []RowError, error) {
+	content, err := io.ReadAll(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf")) // 스프레드시트가 붙이는 UTF-8 BOM
+	if len(bytes.TrimSpace(content)) == 0 {
+		return nil, nil, apperr.Validation("file", "CSV is empty")
+	}
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.Comma = detectDelimiter(content)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, apperr.Validation("file", "invalid CSV: %v", err)
+	}
+
+	header := make([]string, len(records[0]))
+	var headerErrors []RowError
+	known := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		known[column] = true
+	}
+	seen := make(map[string]bool)
+	for i, name := range records[0] {
+		name = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "_")
+		header[i] = name
+		switch {
+		case name == "":
+		case !known[name]:
+			headerErrors = append(headerErrors, RowError{Row: 1, Column: name, Message: fmt.Sprintf("unknown column (expected %s)", strings.Join(columns, ", "))})
+		case seen[name]:
+			headerErrors = append(headerErrors, RowError{Row: 1, Column: name, Message: "duplicate column"})
+		}
+		seen[name] = true
+	}
+	for _, column := range columns[:required] {
+		if !seen[column] {
+			headerErrors = append(headerErrors, RowError{Row: 1, Column: column, Message: "required column is missing"})
+		}
+	}
+
+	var rows []csvRow
+	for i, record := range records[1:] {
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		row := csvRow{number: i + 2, values: make(map[string]string, len(header))}
+		for j, value := range record {
+			if j < len(header) && header[j] != "" {
+				row.values[header[j]] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, headerErrors, nil
+}
+
+// detectDelimiter 헤더 행에 가장 많이 나온 구분자 (쉼표 기본)
+func detectDelimiter(content []byte) rune {
+	header := content
+	if i := bytes.IndexByte(content, '\n'); i >= 0 {
+		header = content[:i]
+	}
+	best, count := ',', bytes.Count(header, []byte(","))
+	for _, candidate := range []rune{';', '\t'} {
+		if n := bytes.Count(header, []byte(string(candidate))); n > count {
+			best, count = candidate, n
+		}
+	}
+	return best
+}
+
+// planRobots 로봇 행 검증 (로봇 등록을 승인 상태로 만들고, 홈 위치 컬럼이 있으면 기본 위치 저장)
+func planRobots(db *gorm.DB, siteID string, rows []csvRow, report *Report) (func(tx *gorm.DB) error, error) {
+	type plannedRobot struct {
+		registration models.RobotRegistration
+		exists       bool
+		defaults     *models.RobotDefaults
+	}
+	var planned []plannedRobot
+	seen := make(map[string]int)
+	for _, row := range rows {
+		p := &rowParser{row: row}
+		serial := p.required("serial_number")
+		manufacturer := p.required("manufacturer")
+		if first, dup := seen[serial]; dup && serial != "" {
+			p.fail("serial_number", "duplicate serial number (first on row %d)", first)
+		}
+		seen[serial] = row.number
+
+		var defaults *models.RobotDefaults
+		for _, column := range homePoseColumns {
+			if row.get(column) != "" {
+				defaults = &models.RobotDefaults{
+					SerialNumber:          serial,
+					MapID:                 row.get("map_id"),
+					X:                     p.float("x"),
+					Y:                     p.float("y"),
+					Theta:                 p.float("theta"),
+					AllowedDeviationXY:    p.deviation("allowed_deviation_xy"),
+					AllowedDeviationTheta: p.deviation("allowed_deviation_theta"),
+				}
+				break
+			}
+		}
+		if defaults != nil && len(p.errors) == 0 {
+			if err := checkMapBounds(db, siteID, p, defaults.MapID, defaults.X, defaults.Y); err != nil {
+				return nil, err
+			}
+		}
+		if len(p.errors) > 0 {
+			report.Errors = append(report.Errors, p.errors...)
+			continue
+		}
+
+		robot := plannedRobot{
+			registration: models.RobotRegistration{SerialNumber: serial, Manufacturer: manufacturer, RobotGroup: row.get("group")},
+			defaults:     defaults,
+		}
+		var existing models.RobotRegistration
+		err := db.Scopes(repository.SiteScope(siteID)).Where("serial_number = ?", serial).First(&existing).Error
+		switch {
+		case err == nil:
+			robot.exists = true
+			report.Updated++
+		case err == gorm.ErrRecordNotFound:
+			report.Created++
+		default:
+			return nil, err
+		}
+		planned = append(planned, robot)
+	}
+
+	return func(tx *gorm.DB) error {
+		now := time.Now()
+		for _, robot := range planned {
+			if robot.exists {
+				if err := tx.Model(&models.RobotRegistration{}).Scopes(repository.SiteScope(siteID)).
+					Where("serial_number = ?", robot.registration.SerialNumber).
+					Updates(map[string]interface{}{
+						"manufacturer": robot.registration.Manufacturer,
+						"robot_group":  robot.registration.RobotGroup,
+					}).Error; err != nil {
+					return err
+				}
+			} else {
+				registration := robot.registration
+				registration.SiteID = siteID
+				registration.Status = constants.RobotRegistrationApproved
+				registration.Note = "bulk import"
+				registration.FirstSeenAt = now
+				registration.LastSeenAt = now
+				registration.DecidedAt = &now
+				if err := tx.Create(&registration).Error; err != nil {
+					return fmt.Errorf("robot %s: %w", registration.SerialNumber, err)
+				}
+			}
+			if robot.defaults != nil {
+				if err := repository.SaveRobotDefaults(tx, siteID, robot.defaults); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}, nil
+}
+
+// planNodes 노드 템플릿 행 검증 (같은 이름의 노드 템플릿이 있으면 갱신)
+func planNodes(db *gorm.DB, siteID string, rows []csvRow, report *Report) (func(tx *gorm.DB) error, error) {
+	var planned []models.NodeTemplate
+	seen := make(map[string]int)
+	for _, row := range rows {
+		p := &rowParser{row: row}
+		node := models.NodeTemplate{
+			Name:                  p.required("name"),
+			Description:           row.get("description"),
+			MapID:                 row.get("map_id"),
+			X:                     p.float("x"),
+			Y:                     p.float("y"),
+			Theta:                 p.float("theta"),
+			AllowedDeviationXY:    p.deviation("allowed_deviation_xy"),
+			AllowedDeviationTheta: p.deviation("allowed_deviation_theta"),
+		}
+		if first, dup := seen[node.Name]; dup && node.Name != "" {
+			p.fail("name", "duplicate node name (first on row %d)", first)
+		}
+		seen[node.Name] = row.number
+		if len(p.errors) == 0 {
+			if err := checkMapBounds(db, siteID, p, node.MapID, node.X, node.Y); err != nil {
+				return nil, err
+			}
+		}
+		if len(p.errors) > 0 {
+			report.Errors = append(report.Errors, p.errors...)
+			continue
+		}
+
+		var existing models.NodeTemplate
+		err := db.Where("name = ?", node.Name).First(&existing).Error
+		switch {
+		case err == nil:
+			node.ID = existing.ID
+			node.CreatedAt = existing.CreatedAt
+			report.Updated++
+		case err == gorm.ErrRecordNotFound:
+			report.Created++
+		default:
+			return nil, err
+		}
+		planned = append(planned, node)
+	}
+
+	return func(tx *gorm.DB) error {
+		for i := range planned {
+			if err := tx.Save(&planned[i]).Error; err != nil {
+				return fmt.Errorf("node %s: %w", planned[i].Name, err)
+			}
+		}
+		return nil
+	}, nil
+}
+
+// planActions 액션 템플릿 행 검증
+// 단계에 연결되지 않은 라이브러리 액션 중 action_type과 description이 같은 것이 있으면 파라미터를 교체합니다.
+// parameters는 "key=value|key:NUMBER=value" 형식이며 타입을 생략하면 STRING입니다.
+func planActions(db *gorm.DB, rows []csvRow, report *Report) (func(tx *gorm.DB) error, error) {
+	type plannedAction struct {
+		action models.ActionTemplate
+		exists bool
+	}
+	var planned []plannedAction
+	seen := make(map[string]int)
+	for _, row := range rows {
+		p := &rowParser{row: row}
+		action := models.ActionTemplate{
+			ActionType:        p.required("action_type"),
+			ActionDescription: row.get("description"),
+			BlockingType:      strings.ToUpper(row.get("blocking_type")),
+		}
+		switch action.BlockingType {
+		case "":
+			action.BlockingType = constants.BlockingTypeNone
+		case constants.BlockingTypeNone, constants.BlockingTypeSoft, constants.BlockingTypeHard:
+		default:
+			p.fail("blocking_type", "blocking_type must be NONE, SOFT or HARD, got %q", action.BlockingType)
+		}
+		action.Parameters = parseActionParameters(p, row.get("parameters"))
+		key := action.ActionType + "\x00" + action.ActionDescription
+		if first, dup := seen[key]; dup && action.ActionType != "" {
+			p.fail("action_type", "duplicate action type and description (first on row %d)", first)
+		}
+		seen[key] = row.number
+		if len(p.errors) > 0 {
+			report.Errors = append(report.Errors, p.errors...)
+			continue
+		}
+
+		var existing models.ActionTemplate
+		err := db.Where("action_type = ? AND action_description = ?", action.ActionType, action.ActionDescription).
+			Where("NOT EXISTS (SELECT 1 FROM step_action_mappings sam WHERE sam.action_template_id = action_templates.id)").
+			First(&existing).Error
+		switch {
+		case err == nil:
+			action.ID = existing.ID
+			action.CreatedAt = existing.CreatedAt
+			planned = append(planned, plannedAction{action: action, exists: true})
+			report.Updated++
+		case err == gorm.ErrRecordNotFound:
+			planned = append(planned, plannedAction{action: action})
+			report.Created++
+		default:
+			return nil, err
+		}
+	}
+
+	return func(tx *gorm.DB) error {
+		for _, item := range planned {
+			action := item.action
+			parameters := action.Parameters
+			action.Parameters = nil
+			if item.exists {
+				if err := tx.Where("action_template_id = ?", action.ID).Delete(&models.ActionParameter{}).Error; err != nil {
+					return err
+				}
+			}
+			if err := tx.Save(&action).Error; err != nil {
+				return fmt.Errorf("action %s: %w", action.ActionType, err)
+			}
+			for _, param := range parameters {
+				param.ActionTemplateID = action.ID
+				if err := tx.Create(&param).Error; err != nil {
+					return fmt.Errorf("action %s parameter %s: %w", action.ActionType, param.Key, err)
+				}
+			}
+		}
+		return nil
+	}, nil
+}
+
+// parseActionParameters "key=value|key:TYPE=value" 형식의 파라미터 목록 해석
+func parseActionParameters(p *rowParser, raw string) []models.ActionParameter {
+	var parameters []models.ActionParameter
+	if raw == "" {
+		return parameters
+	}
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(raw, "|") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(item, "=")
+		if !ok {
+			p.fail("parameters", "parameter %q must be key=value", item)
+			continue
+		}
+		key, valueType, typed := strings.Cut(strings.TrimSpace(name), ":")
+		valueType = strings.ToUpper(strings.TrimSpace(valueType))
+		if !typed {
+			valueType = "STRING"
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		switch {
+		case key == "":
+			p.fail("parameters", "parameter %q has no key", item)
+			continue
+		case seen[key]:
+			p.fail("parameters", "duplicate parameter %s", key)
+			continue
+		}
+		seen[key] = true
+		switch valueType {
+		case "STRING":
+		case "NUMBER":
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				p.fail("parameters", "parameter %s must be a number, got %q", key, value)
+			}
+		case "BOOLEAN":
+			if _, err := strconv.ParseBool(value); err != nil {
+				p.fail("parameters", "parameter %s must be true or false, got %q", key, value)
+			}
+		default:
+			p.fail("parameters", "parameter %s has unsupported type %s (STRING, NUMBER, BOOLEAN)", key, valueType)
+		}
+		parameters = append(parameters, models.ActionParameter{Key: key, Value: value, ValueType: valueType})
+	}
+	return parameters
+}
+
+// checkMapBounds 등록된 맵에 경계가 있으면 위치가 경계 안에 있는지 확인 (DB 오류만 반환)
+func checkMapBounds(db *gorm.DB, siteID string, p *rowParser, mapID string, x, y float64) error {
+	if mapID == "" {
+		return nil
+	}
+	robotMap, err := repository.FindRobotMap(db, siteID, mapID)
+	if err != nil {
+		return err
+	}
+	if robotMap != nil && robotMap.HasBounds() &&
+		(x < robotMap.MinX || x > robotMap.MaxX || y < robotMap.MinY || y > robotMap.MaxY) {
+		p.fail("x", "position (%.2f, %.2f) is outside map %s", x, y, mapID)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"mqtt-bridge/internal/config"
+	"mqtt-bridge/internal/telemetry"
 
 	"github.com/go-redis/redis/v8"
 )
@@ -15,6 +16,9 @@ func NewRedisClient(cfg *config.Config) (*redis.Client, error) {
 		DB:       cfg.RedisDB,
 	})
 
+	// 부모 스팬이 있는 명령을 트레이싱
+	client.AddHook(telemetry.RedisHook{})
+
 	// 연결 테스트
 	ctx := context.Background()
 	if err := client.Ping(ctx).Err(); err != nil {
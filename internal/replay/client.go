@@ -0,0 +1,86 @@
+// internal/replay/client.go
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// CaptureClient 브로커 없이 동작하는 mqtt.Client
+// 재생 중 브릿지가 발행하는 오더/instantActions/PLC 응답을 브로커 대신 기록기(선택)로 보냅니다.
+type CaptureClient struct {
+	mu        sync.Mutex
+	encoder   *json.Encoder
+	published int
+}
+
+// NewCaptureClient 발행 메시지를 out에 JSON Lines로 쓰는 클라이언트 생성 (out이 nil이면 버림)
+func NewCaptureClient(out io.Writer) *CaptureClient {
+	client := &CaptureClient{}
+	if out != nil {
+		client.encoder = json.NewEncoder(out)
+	}
+	return client
+}
+
+// Published 지금까지 발행된 메시지 수
+func (c *CaptureClient) Published() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.published
+}
+
+func (c *CaptureClient) Publish(topic string, qos byte, retained bool, payload interface{}) mqtt.Token {
+	var data []byte
+	switch p := payload.(type) {
+	case []byte:
+		data = p
+	case string:
+		data = []byte(p)
+	default:
+		return &doneToken{err: fmt.Errorf("unsupported payload type %T", payload)}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.published++
+	if c.encoder != nil {
+		if err := c.encoder.Encode(&Entry{ReceivedAt: time.Now(), Topic: topic, Payload: string(data)}); err != nil {
+			return &doneToken{err: err}
+		}
+	}
+	return &doneToken{}
+}
+
+func (c *CaptureClient) IsConnected() bool       { return true }
+func (c *CaptureClient) IsConnectionOpen() bool  { return true }
+func (c *CaptureClient) Connect() mqtt.Token     { return &doneToken{} }
+func (c *CaptureClient) Disconnect(quiesce uint) {}
+func (c *CaptureClient) Subscribe(topic string, qos byte, callback mqtt.MessageHandler) mqtt.Token {
+	return &doneToken{}
+}
+func (c *CaptureClient) SubscribeMultiple(filters map[string]byte, callback mqtt.MessageHandler) mqtt.Token {
+	return &doneToken{}
+}
+func (c *CaptureClient) Unsubscribe(topics ...string) mqtt.Token             { return &doneToken{} }
+func (c *CaptureClient) AddRoute(topic string, callback mqtt.MessageHandler) {}
+func (c *CaptureClient) OptionsReader() mqtt.ClientOptionsReader             { return mqtt.ClientOptionsReader{} }
+
+// doneToken 즉시 완료되는 mqtt.Token
+type doneToken struct {
+	err error
+}
+
+func (t *doneToken) Wait() bool                     { return true }
+func (t *doneToken) WaitTimeout(time.Duration) bool { return true }
+func (t *doneToken) Done() <-chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}
+func (t *doneToken) Error() error { return t.err }
@@ -0,0 +1,106 @@
+// internal/replay/player.go
+package replay
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// MessageRouter 기록된 메시지를 전달받는 라우터 (messaging.Router)
+type MessageRouter interface {
+	RouteMessage(client mqtt.Client, msg mqtt.Message)
+}
+
+// Options 재생 옵션
+type Options struct {
+	Speed        float64  // 기록 간격 배속 (2는 두 배 빠르게, 0 이하는 간격 무시)
+	MaxRate      float64  // 초당 최대 메시지 수 (0 이하는 제한 없음)
+	TopicFilters []string // 재생할 토픽 부분 문자열 (비어 있으면 전체, 예: "/state", "bridge/command")
+}
+
+// Stats 재생 결과
+type Stats struct {
+	Routed   int
+	Skipped  int
+	Duration time.Duration
+}
+
+// Play 기록을 원래 간격(배속 적용)과 속도 제한에 맞춰 라우터로 전달합니다.
+// 컨텍스트가 취소되면 그때까지의 결과를 반환합니다.
+func Play(ctx context.Context, entries []Entry, router MessageRouter, client mqtt.Client, opts Options) (Stats, error) {
+	var stats Stats
+	start := time.Now()
+
+	var minInterval time.Duration
+	if opts.MaxRate > 0 {
+		minInterval = time.Duration(float64(time.Second) / opts.MaxRate)
+	}
+
+	var previous time.Time
+	var lastSent time.Time
+	for _, entry := range entries {
+		if !matchesFilters(entry.Topic, opts.TopicFilters) {
+			stats.Skipped++
+			continue
+		}
+
+		var wait time.Duration
+		if opts.Speed > 0 && !previous.IsZero() && entry.ReceivedAt.After(previous) {
+			wait = time.Duration(float64(entry.ReceivedAt.Sub(previous)) / opts.Speed)
+		}
+		if !lastSent.IsZero() {
+			if elapsed := time.Since(lastSent); wait < minInterval-elapsed {
+				wait = minInterval - elapsed
+			}
+		}
+		previous = entry.ReceivedAt
+
+		if wait > 0 {
+			select {
+			case <-ctx.Done():
+				stats.Duration = time.Since(start)
+				return stats, ctx.Err()
+			case <-time.After(wait):
+			}
+		} else if ctx.Err() != nil {
+			stats.Duration = time.Since(start)
+			return stats, ctx.Err()
+		}
+
+		router.RouteMessage(client, &message{topic: entry.Topic, payload: []byte(entry.Payload)})
+		lastSent = time.Now()
+		stats.Routed++
+	}
+
+	stats.Duration = time.Since(start)
+	return stats, nil
+}
+
+func matchesFilters(topic string, filters []string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	for _, filter := range filters {
+		if filter != "" && strings.Contains(topic, filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// message 재생용 mqtt.Message 구현
+type message struct {
+	topic   string
+	payload []byte
+}
+
+func (m *message) Duplicate() bool   { return false }
+func (m *message) Qos() byte         { return 0 }
+func (m *message) Retained() bool    { return false }
+func (m *message) Topic() string     { return m.topic }
+func (m *message) MessageID() uint16 { return 0 }
+func (m *message) Payload() []byte   { return m.payload }
+func (m *message) Ack()              {}
@@ -0,0 +1,77 @@
+// internal/replay/recorder.go
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mqtt-bridge/internal/utils"
+	"os"
+	"sync"
+	"time"
+)
+
+// Entry 기록된 MQTT 메시지 한 건 (JSON Lines 파일의 한 줄)
+type Entry struct {
+	ReceivedAt time.Time `json:"receivedAt"`
+	Topic      string    `json:"topic"`
+	Payload    string    `json:"payload"`
+}
+
+// Recorder 라우터로 들어온 MQTT 메시지를 JSON Lines 파일에 기록
+type Recorder struct {
+	mu      sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// NewRecorder 파일에 이어 쓰는 기록기 생성
+func NewRecorder(path string) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open record file %s: %w", path, err)
+	}
+	utils.Logger.Infof("✅ Traffic recorder created (file: %s)", path)
+	return &Recorder{file: file, encoder: json.NewEncoder(file)}, nil
+}
+
+// Record 메시지 한 건 기록 (실패는 로그만 남김)
+func (r *Recorder) Record(topic string, payload []byte) {
+	entry := Entry{ReceivedAt: time.Now(), Topic: topic, Payload: string(payload)}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.encoder.Encode(&entry); err != nil {
+		utils.Logger.Errorf("❌ Failed to record message on %s: %v", topic, err)
+	}
+}
+
+// Close 파일 닫기
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// ReadEntries JSON Lines 기록을 순서대로 읽기
+func ReadEntries(reader io.Reader) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("invalid record at line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
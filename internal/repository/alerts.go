@@ -0,0 +1,110 @@
+// internal/repository/alerts.go
+package repository
+
+import (
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AlertRules 알림 규칙 이름 목록
+var AlertRules = []string{
+	constants.AlertRuleRobotOffline,
+	constants.AlertRuleCommandFailed,
+	constants.AlertRuleBatteryLow,
+	constants.AlertRuleOrderStuck,
+	constants.AlertRuleStateLatency,
+	constants.AlertRuleClockDrift,
+	constants.AlertRuleOrderFinished,
+}
+
+// IsAlertRule 알려진 알림 규칙인지 확인
+func IsAlertRule(rule string) bool {
+	for _, known := range AlertRules {
+		if rule == known {
+			return true
+		}
+	}
+	return false
+}
+
+// AlertEventFilter 알림 이력 조회 조건
+type AlertEventFilter struct {
+	Rule   string
+	Status string
+	Since  time.Time
+	Limit  int
+}
+
+// ListAlertEvents 사이트의 알림 이력을 최신순으로 조회
+func ListAlertEvents(db *gorm.DB, siteID string, filter AlertEventFilter) ([]models.AlertEvent, error) {
+	query := db.Scopes(SiteScope(siteID)).Order("created_at DESC, id DESC")
+	if filter.Rule != "" {
+		query = query.Where("rule = ?", filter.Rule)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where("created_at >= ?", filter.Since)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 100
+	}
+	var events []models.AlertEvent
+	err := query.Limit(filter.Limit).Find(&events).Error
+	return events, err
+}
+
+// CreateAlertSuppression 알림 억제 시간대 생성 (rule이 비어 있으면 모든 규칙)
+func CreateAlertSuppression(db *gorm.DB, siteID string, suppression *models.AlertSuppression) error {
+	if suppression.Rule != "" && !IsAlertRule(suppression.Rule) {
+		return apperr.Validation("rule", "unknown alert rule %q", suppression.Rule)
+	}
+	if suppression.StartsAt.IsZero() {
+		suppression.StartsAt = time.Now()
+	}
+	if !suppression.EndsAt.After(suppression.StartsAt) {
+		return apperr.Validation("endsAt", "suppression must end after it starts")
+	}
+	suppression.SiteID = siteID
+	return db.Create(suppression).Error
+}
+
+// ListAlertSuppressions 아직 끝나지 않은 알림 억제 시간대 목록
+func ListAlertSuppressions(db *gorm.DB, siteID string, now time.Time) ([]models.AlertSuppression, error) {
+	var suppressions []models.AlertSuppression
+	err := db.Scopes(SiteScope(siteID)).Where("ends_at > ?", now).Order("starts_at ASC").Find(&suppressions).Error
+	return suppressions, err
+}
+
+// DeleteAlertSuppression 알림 억제 시간대 삭제
+func DeleteAlertSuppression(db *gorm.DB, siteID string, id uint) error {
+	result := db.Scopes(SiteScope(siteID)).Delete(&models.AlertSuppression{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperr.New(apperr.CodeNotFound, "alert suppression %d not found", id)
+	}
+	return nil
+}
+
+// ActiveAlertSuppression now에 규칙을 억제하는 시간대 (없으면 nil)
+func ActiveAlertSuppression(db *gorm.DB, siteID, rule string, now time.Time) (*models.AlertSuppression, error) {
+	var suppression models.AlertSuppression
+	err := db.Scopes(SiteScope(siteID)).
+		Where("(rule = '' OR rule = ?) AND starts_at <= ? AND ends_at > ?", rule, now, now).
+		Order("ends_at DESC").
+		First(&suppression).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &suppression, nil
+}
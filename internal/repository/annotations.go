@@ -0,0 +1,138 @@
+// internal/repository/annotations.go
+package repository
+
+import (
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// AnnotationInput 주석 추가 요청 (라벨과 메모 중 하나는 있어야 함)
+type AnnotationInput struct {
+	Labels []string `json:"labels"`
+	Note   string   `json:"note"`
+	Author string   `json:"author"`
+}
+
+// AnnotateOrder 오더 실행에 라벨/메모 추가
+func AnnotateOrder(db *gorm.DB, siteID, orderID string, input AnnotationInput) (*models.ExecutionAnnotation, error) {
+	var execution models.OrderExecution
+	err := db.Scopes(SiteScope(siteID)).Select("order_id", "correlation_id").Where("order_id = ?", orderID).First(&execution).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, apperr.New(apperr.CodeNotFound, "order %s not found", orderID).WithField("orderId")
+	}
+	if err != nil {
+		return nil, err
+	}
+	annotation := &models.ExecutionAnnotation{
+		TargetType:    constants.AnnotationTargetOrder,
+		OrderID:       execution.OrderID,
+		CorrelationID: execution.CorrelationID,
+	}
+	return createAnnotation(db, siteID, annotation, input)
+}
+
+// AnnotateCommand 명령(상관 ID)에 라벨/메모 추가
+func AnnotateCommand(db *gorm.DB, siteID, correlationID string, input AnnotationInput) (*models.ExecutionAnnotation, error) {
+	var count int64
+	if err := db.Model(&models.Command{}).Where("correlation_id = ?", correlationID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, apperr.New(apperr.CodeNotFound, "command with correlation id %s not found", correlationID).WithField("correlationId")
+	}
+	annotation := &models.ExecutionAnnotation{
+		TargetType:    constants.AnnotationTargetCommand,
+		CorrelationID: correlationID,
+	}
+	return createAnnotation(db, siteID, annotation, input)
+}
+
+func createAnnotation(db *gorm.DB, siteID string, annotation *models.ExecutionAnnotation, input AnnotationInput) (*models.ExecutionAnnotation, error) {
+	labels := NormalizeLabels(input.Labels)
+	note := strings.TrimSpace(input.Note)
+	if len(labels) == 0 && note == "" {
+		return nil, apperr.Validation("labels", "at least one label or a note is required")
+	}
+	for _, label := range labels {
+		if strings.ContainsAny(label, ", ") {
+			return nil, apperr.Validation("labels", "label %q must not contain spaces or commas", label)
+		}
+	}
+	annotation.SiteID = siteID
+	annotation.Labels = strings.Join(labels, ",")
+	annotation.Note = note
+	annotation.Author = strings.TrimSpace(input.Author)
+	if err := db.Create(annotation).Error; err != nil {
+		return nil, fmt.Errorf("failed to save annotation: %w", err)
+	}
+	return annotation, nil
+}
+
+// ListOrderAnnotations 오더와 그 오더를 만든 명령에 남긴 주석 (오래된 순)
+func ListOrderAnnotations(db *gorm.DB, siteID, orderID string) ([]models.ExecutionAnnotation, error) {
+	var execution models.OrderExecution
+	err := db.Scopes(SiteScope(siteID)).Select("order_id", "correlation_id").Where("order_id = ?", orderID).First(&execution).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, apperr.New(apperr.CodeNotFound, "order %s not found", orderID).WithField("orderId")
+	}
+	if err != nil {
+		return nil, err
+	}
+	var annotations []models.ExecutionAnnotation
+	err = db.Scopes(SiteScope(siteID)).Scopes(annotationTarget(execution.OrderID, execution.CorrelationID)).
+		Order("created_at ASC").Find(&annotations).Error
+	return annotations, err
+}
+
+// ListCommandAnnotations 명령(상관 ID)에 남긴 주석 (오래된 순)
+func ListCommandAnnotations(db *gorm.DB, siteID, correlationID string) ([]models.ExecutionAnnotation, error) {
+	var annotations []models.ExecutionAnnotation
+	err := db.Scopes(SiteScope(siteID)).
+		Where("target_type = ? AND correlation_id = ?", constants.AnnotationTargetCommand, correlationID).
+		Order("created_at ASC").Find(&annotations).Error
+	return annotations, err
+}
+
+// NormalizeLabels 라벨을 소문자로 바꾸고 빈 값과 중복을 제거
+func NormalizeLabels(labels []string) []string {
+	lowered := make([]string, 0, len(labels))
+	for _, label := range labels {
+		lowered = append(lowered, strings.ToLower(label))
+	}
+	return normalizeRobotList(lowered)
+}
+
+// annotationTarget 오더 주석과 그 오더를 만든 명령의 주석 조건
+func annotationTarget(orderID, correlationID string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if correlationID == "" {
+			return db.Where("target_type = ? AND order_id = ?", constants.AnnotationTargetOrder, orderID)
+		}
+		return db.Where("(target_type = ? AND order_id = ?) OR (target_type = ? AND correlation_id = ?)",
+			constants.AnnotationTargetOrder, orderID, constants.AnnotationTargetCommand, correlationID)
+	}
+}
+
+// annotatedOrders 라벨/메모 조건에 맞는 주석이 있는 오더만 남기는 조건 (명령 주석 포함)
+func annotatedOrders(siteID, label, noteQuery string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		sub := "SELECT 1 FROM execution_annotations a WHERE a.site_id = ? AND " +
+			"((a.target_type = ? AND a.order_id = order_executions.order_id) OR " +
+			"(a.target_type = ? AND a.correlation_id <> '' AND a.correlation_id = order_executions.correlation_id))"
+		args := []interface{}{siteID, constants.AnnotationTargetOrder, constants.AnnotationTargetCommand}
+		if label != "" {
+			sub += " AND ',' || a.labels || ',' LIKE ?"
+			args = append(args, "%,"+strings.ToLower(strings.TrimSpace(label))+",%")
+		}
+		if noteQuery != "" {
+			sub += " AND a.note ILIKE ?"
+			args = append(args, "%"+noteQuery+"%")
+		}
+		return db.Where("EXISTS ("+sub+")", args...)
+	}
+}
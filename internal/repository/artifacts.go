@@ -0,0 +1,42 @@
+// internal/repository/artifacts.go
+package repository
+
+import (
+	"mqtt-bridge/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ListOrderArtifacts 오더의 결과물 목록 조회
+func ListOrderArtifacts(db *gorm.DB, siteID, orderID string) ([]models.OrderArtifact, error) {
+	var artifacts []models.OrderArtifact
+	err := db.Scopes(SiteScope(siteID)).Where("order_id = ?", orderID).Order("created_at ASC, id ASC").Find(&artifacts).Error
+	return artifacts, err
+}
+
+// FindOrderArtifact 오더의 결과물을 이름으로 조회 (없으면 nil 반환)
+func FindOrderArtifact(db *gorm.DB, siteID, orderID, name string) (*models.OrderArtifact, error) {
+	var artifact models.OrderArtifact
+	err := db.Scopes(SiteScope(siteID)).Where("order_id = ? AND name = ?", orderID, name).First(&artifact).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &artifact, nil
+}
+
+// SaveOrderArtifact 결과물 메타데이터 저장 (같은 오더/이름이면 갱신)
+func SaveOrderArtifact(db *gorm.DB, artifact *models.OrderArtifact) error {
+	existing, err := FindOrderArtifact(db, artifact.SiteID, artifact.OrderID, artifact.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		artifact.ID = existing.ID
+		artifact.CreatedAt = existing.CreatedAt
+		return db.Save(artifact).Error
+	}
+	return db.Create(artifact).Error
+}
@@ -0,0 +1,141 @@
+// internal/repository/charging_policy.go
+package repository
+
+import (
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/utils"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// 자동 충전이 설정한 유지보수 사유와 충전 명령의 상관 ID 접두사
+// 충전 정책은 이 접두사로 자신이 설정한 유지보수만 해제하고, Executor는 충전 명령만 충전 중에도 배차합니다.
+const (
+	ChargingMaintenancePrefix = "charging: "
+	ChargingCorrelationPrefix = "charging-"
+)
+
+// IsChargingMaintenance 자동 충전이 설정한 유지보수인지
+func IsChargingMaintenance(m *RobotMaintenance) bool {
+	return m != nil && strings.HasPrefix(m.Reason, ChargingMaintenancePrefix)
+}
+
+// IsChargingCommand 자동 충전이 배차한 명령의 상관 ID인지
+func IsChargingCommand(correlationID string) bool {
+	return strings.HasPrefix(correlationID, ChargingCorrelationPrefix)
+}
+
+// ListChargingPolicies 사이트의 충전 정책 목록
+func ListChargingPolicies(db *gorm.DB, siteID string) ([]models.ChargingPolicy, error) {
+	var policies []models.ChargingPolicy
+	err := db.Scopes(SiteScope(siteID)).Order("name ASC").Find(&policies).Error
+	return policies, err
+}
+
+// SaveChargingPolicy 충전 정책을 저장합니다 (같은 이름이 있으면 갱신).
+func SaveChargingPolicy(db *gorm.DB, siteID string, policy *models.ChargingPolicy) error {
+	if strings.TrimSpace(policy.Name) == "" {
+		return apperr.Validation("name", "policy name is required")
+	}
+	if policy.LowBattery <= 0 || policy.LowBattery >= 100 {
+		return apperr.Validation("lowBattery", "low battery threshold must be between 0 and 100, got %g", policy.LowBattery)
+	}
+	if policy.ResumeBattery <= policy.LowBattery || policy.ResumeBattery > 100 {
+		return apperr.Validation("resumeBattery", "resume threshold must be above the low threshold %g and at most 100, got %g",
+			policy.LowBattery, policy.ResumeBattery)
+	}
+	var count int64
+	db.Model(&models.CommandDefinition{}).Where("command_type = ? AND is_active = ?", policy.CommandType, true).Count(&count)
+	if count == 0 {
+		return apperr.New(apperr.CodeCommandNotFound, "command %q is not defined or inactive", policy.CommandType).WithField("commandType")
+	}
+	policy.Robots = strings.Join(normalizeRobotList(strings.Split(policy.Robots, ",")), ",")
+	policy.SiteID = siteID
+
+	var existing models.ChargingPolicy
+	result := db.Scopes(SiteScope(siteID)).Where("name = ?", policy.Name).First(&existing)
+	switch {
+	case result.Error == nil:
+		policy.ID = existing.ID
+		policy.CreatedAt = existing.CreatedAt
+		if err := db.Save(policy).Error; err != nil {
+			return fmt.Errorf("failed to save charging policy %s: %w", policy.Name, err)
+		}
+	case result.Error == gorm.ErrRecordNotFound:
+		active := policy.IsActive
+		if err := db.Create(policy).Error; err != nil {
+			return fmt.Errorf("failed to create charging policy %s: %w", policy.Name, err)
+		}
+		// gorm default:true 필드는 false 값이 생략되므로 명시적으로 갱신
+		if !active {
+			if err := db.Model(policy).Update("is_active", false).Error; err != nil {
+				return err
+			}
+		}
+	default:
+		return result.Error
+	}
+	utils.Logger.Infof("🔋 Charging policy %s saved: below %.1f%% send %s, available again at %.1f%%",
+		policy.Name, policy.LowBattery, policy.CommandType, policy.ResumeBattery)
+	return nil
+}
+
+// DeleteChargingPolicy 충전 정책 삭제
+func DeleteChargingPolicy(db *gorm.DB, siteID, name string) error {
+	result := db.Unscoped().Scopes(SiteScope(siteID)).Where("name = ?", name).Delete(&models.ChargingPolicy{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperr.New(apperr.CodeNotFound, "charging policy %s not found", name)
+	}
+	utils.Logger.Infof("🔋 Charging policy %s deleted", name)
+	return nil
+}
+
+// FindChargingPolicyForRobot 로봇에 적용되는 활성 충전 정책 (없으면 nil)
+// 로봇을 그룹에 명시한 정책이 로봇 그룹이 비어 있는 기본 정책보다 우선하며, 같은 조건이면 이름 순으로 먼저인 정책을 사용합니다.
+func FindChargingPolicyForRobot(db *gorm.DB, siteID, serialNumber string) (*models.ChargingPolicy, error) {
+	var policies []models.ChargingPolicy
+	if err := db.Scopes(SiteScope(siteID)).Where("is_active = ?", true).Order("name ASC").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	var fallback *models.ChargingPolicy
+	for i := range policies {
+		robots := normalizeRobotList(strings.Split(policies[i].Robots, ","))
+		if len(robots) == 0 {
+			if fallback == nil {
+				fallback = &policies[i]
+			}
+			continue
+		}
+		for _, robot := range robots {
+			if robot == serialNumber {
+				return &policies[i], nil
+			}
+		}
+	}
+	return fallback, nil
+}
+
+// HasActiveOrders 로봇에 대기/실행 중인 오더나 실행 중인 명령이 있는지
+func HasActiveOrders(db *gorm.DB, siteID, serialNumber string) (bool, error) {
+	var orders int64
+	err := db.Model(&models.OrderExecution{}).Scopes(SiteScope(siteID)).
+		Where("serial_number = ? AND status IN ?", serialNumber, []string{
+			constants.OrderExecutionStatusPending, constants.OrderExecutionStatusRunning, constants.OrderExecutionStatusWaiting,
+			constants.OrderExecutionStatusPaused,
+		}).Count(&orders).Error
+	if err != nil || orders > 0 {
+		return orders > 0, err
+	}
+	var commands int64
+	err = db.Model(&models.CommandExecution{}).Scopes(SiteScope(siteID)).
+		Where("serial_number = ? AND status = ?", serialNumber, constants.CommandExecutionStatusRunning).
+		Count(&commands).Error
+	return commands > 0, err
+}
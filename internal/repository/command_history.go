@@ -0,0 +1,277 @@
+// internal/repository/command_history.go
+package repository
+
+import (
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/messaging"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/utils"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// defaultCommandHistoryLimit 명령 이력 조회 기본 건수
+const defaultCommandHistoryLimit = 50
+
+// PLCResponseLog 보낸 PLC 응답을 plc_responses 테이블에 기록 (messaging.PLCResponseRecorder 구현)
+type PLCResponseLog struct {
+	db     *gorm.DB
+	siteID string
+}
+
+// NewPLCResponseLog PLC 응답 기록기 생성
+func NewPLCResponseLog(db *gorm.DB, siteID string) *PLCResponseLog {
+	return &PLCResponseLog{db: db, siteID: siteID}
+}
+
+// RecordPLCResponse 응답 기록 (실패해도 응답 전송에는 영향 없음)
+func (l *PLCResponseLog) RecordPLCResponse(status string, response messaging.PLCResponse, publishErr error) {
+	record := models.PLCResponse{
+		SiteID:        l.siteID,
+		CorrelationID: response.CorrelationID,
+		Command:       response.Command,
+		Status:        status,
+		SentStatus:    response.Status,
+		Code:          response.Code,
+		ErrorMessage:  truncateText(response.Error, 500),
+		SentAt:        time.Now(),
+	}
+	if publishErr != nil {
+		record.PublishError = truncateText(publishErr.Error(), 500)
+	}
+	if err := l.db.Create(&record).Error; err != nil {
+		utils.Logger.Errorf("❌ Failed to record PLC response %s:%s (cid=%s): %v", response.Command, status, response.CorrelationID, err)
+	}
+}
+
+// CommandHistoryFilter 명령 이력 조회 조건 (빈 값은 조건 없음)
+type CommandHistoryFilter struct {
+	Type   string    // 명령 종류 (CommandDefinition.CommandType)
+	Status string    // 명령 상태 (PENDING, SUCCESS, FAILURE 등)
+	From   time.Time // 요청 시각 하한
+	To     time.Time // 요청 시각 상한
+	Limit  int
+}
+
+// CommandHistory 명령과 그 실행, 오더 체인, PLC 응답 기록
+type CommandHistory struct {
+	ID            uint                      `json:"id"`
+	CommandType   string                    `json:"command_type"`
+	CorrelationID string                    `json:"correlation_id"`
+	Status        string                    `json:"status"`
+	RequestTime   time.Time                 `json:"request_time"`
+	ResponseTime  *time.Time                `json:"response_time,omitempty"`
+	ErrorMessage  string                    `json:"error_message,omitempty"`
+	Parameters    map[string]string         `json:"parameters,omitempty"`
+	Executions    []CommandExecutionHistory `json:"executions"`
+	Responses     []models.PLCResponse      `json:"responses"` // 보낸 순서
+}
+
+// CommandExecutionHistory 명령 실행 하나와 실행 순서대로의 오더
+type CommandExecutionHistory struct {
+	ID           uint           `json:"id"`
+	SerialNumber string         `json:"serial_number"`
+	Status       string         `json:"status"`
+	StartedAt    time.Time      `json:"started_at"`
+	CompletedAt  *time.Time     `json:"completed_at,omitempty"`
+	Orders       []OrderHistory `json:"orders"`
+}
+
+// OrderHistory 명령 실행이 만든 오더 (단계는 상세 조회에서만 채움)
+type OrderHistory struct {
+	OrderID        string        `json:"order_id"`
+	TemplateID     uint          `json:"template_id"`
+	ExecutionOrder int           `json:"execution_order"`
+	Status         string        `json:"status"`
+	Transport      string        `json:"transport,omitempty"`
+	StartedAt      time.Time     `json:"started_at"`
+	CompletedAt    *time.Time    `json:"completed_at,omitempty"`
+	ErrorMessage   string        `json:"error_message,omitempty"`
+	Steps          []StepHistory `json:"steps,omitempty"`
+}
+
+// StepHistory 오더의 단계 실행
+type StepHistory struct {
+	StepOrder     int        `json:"step_order"`
+	Status        string     `json:"status"`
+	Result        string     `json:"result,omitempty"`
+	ParallelGroup string     `json:"parallel_group,omitempty"`
+	StartedAt     time.Time  `json:"started_at"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+	ErrorMessage  string     `json:"error_message,omitempty"`
+}
+
+// ListCommandHistory 조건에 맞는 명령을 최근 순으로 실행/오더/응답 기록과 함께 조회 (단계 제외)
+func ListCommandHistory(db *gorm.DB, siteID string, filter CommandHistoryFilter) ([]CommandHistory, error) {
+	query := db.Scopes(siteCommands(siteID)).Preload("CommandDefinition")
+	if filter.Type != "" {
+		query = query.Where("command_definition_id IN (?)",
+			db.Model(&models.CommandDefinition{}).Select("id").Where("command_type = ?", strings.ToUpper(filter.Type)))
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", strings.ToUpper(filter.Status))
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("request_time >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("request_time < ?", filter.To)
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultCommandHistoryLimit
+	}
+
+	var commands []models.Command
+	if err := query.Order("request_time DESC, id DESC").Limit(limit).Find(&commands).Error; err != nil {
+		return nil, err
+	}
+	return buildCommandHistory(db, siteID, commands, false)
+}
+
+// GetCommandHistory 명령 하나의 전체 실행 트리 (실행 → 오더 → 단계, PLC 응답 기록)
+func GetCommandHistory(db *gorm.DB, siteID string, commandID uint) (*CommandHistory, error) {
+	var command models.Command
+	err := db.Scopes(siteCommands(siteID)).Preload("CommandDefinition").First(&command, commandID).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, apperr.New(apperr.CodeNotFound, "command %d not found", commandID).WithField("id")
+	}
+	if err != nil {
+		return nil, err
+	}
+	history, err := buildCommandHistory(db, siteID, []models.Command{command}, true)
+	if err != nil {
+		return nil, err
+	}
+	return &history[0], nil
+}
+
+// siteCommands 사이트의 명령만 남기는 조건
+// 명령 테이블에는 사이트 구분이 없으므로 다른 사이트의 실행이 연결된 명령을 제외합니다.
+func siteCommands(siteID string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("NOT EXISTS (SELECT 1 FROM command_executions ce WHERE ce.command_id = commands.id AND ce.site_id <> ?)", siteID)
+	}
+}
+
+// buildCommandHistory 명령 목록에 실행, 오더(withSteps면 단계 포함), 응답 기록을 붙입니다.
+func buildCommandHistory(db *gorm.DB, siteID string, commands []models.Command, withSteps bool) ([]CommandHistory, error) {
+	histories := make([]CommandHistory, 0, len(commands))
+	if len(commands) == 0 {
+		return histories, nil
+	}
+	commandIDs := make([]uint, 0, len(commands))
+	correlationIDs := make([]string, 0, len(commands))
+	for _, c := range commands {
+		commandIDs = append(commandIDs, c.ID)
+		if c.CorrelationID != "" {
+			correlationIDs = append(correlationIDs, c.CorrelationID)
+		}
+	}
+
+	var executions []models.CommandExecution
+	if err := db.Where("command_id IN ?", commandIDs).
+		Preload("OrderExecutions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("order_executions.started_at ASC, order_executions.id ASC")
+		}).
+		Order("id ASC").Find(&executions).Error; err != nil {
+		return nil, err
+	}
+	steps := make(map[uint][]StepHistory)
+	if withSteps {
+		var orderExecutionIDs []uint
+		for _, execution := range executions {
+			for _, order := range execution.OrderExecutions {
+				orderExecutionIDs = append(orderExecutionIDs, order.ID)
+			}
+		}
+		if len(orderExecutionIDs) > 0 {
+			var stepExecutions []models.StepExecution
+			if err := db.Where("execution_id IN ?", orderExecutionIDs).Order("id ASC").Find(&stepExecutions).Error; err != nil {
+				return nil, err
+			}
+			for _, s := range stepExecutions {
+				steps[s.ExecutionID] = append(steps[s.ExecutionID], StepHistory{
+					StepOrder:     s.StepOrder,
+					Status:        s.Status,
+					Result:        s.Result,
+					ParallelGroup: s.ParallelGroup,
+					StartedAt:     s.StartedAt,
+					CompletedAt:   s.CompletedAt,
+					ErrorMessage:  s.ErrorMessage,
+				})
+			}
+		}
+	}
+
+	var responses []models.PLCResponse
+	if len(correlationIDs) > 0 {
+		if err := db.Scopes(SiteScope(siteID)).Where("correlation_id IN ?", correlationIDs).
+			Order("sent_at ASC, id ASC").Find(&responses).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	executionsByCommand := make(map[uint][]CommandExecutionHistory)
+	for _, execution := range executions {
+		entry := CommandExecutionHistory{
+			ID:           execution.ID,
+			SerialNumber: execution.SerialNumber,
+			Status:       execution.Status,
+			StartedAt:    execution.StartedAt,
+			CompletedAt:  execution.CompletedAt,
+			Orders:       make([]OrderHistory, 0, len(execution.OrderExecutions)),
+		}
+		for _, order := range execution.OrderExecutions {
+			entry.Orders = append(entry.Orders, OrderHistory{
+				OrderID:        order.OrderID,
+				TemplateID:     order.TemplateID,
+				ExecutionOrder: order.ExecutionOrder,
+				Status:         order.Status,
+				Transport:      order.Transport,
+				StartedAt:      order.StartedAt,
+				CompletedAt:    order.CompletedAt,
+				ErrorMessage:   order.ErrorMessage,
+				Steps:          steps[order.ID],
+			})
+		}
+		executionsByCommand[execution.CommandID] = append(executionsByCommand[execution.CommandID], entry)
+	}
+	responsesByCorrelation := make(map[string][]models.PLCResponse)
+	for _, response := range responses {
+		responsesByCorrelation[response.CorrelationID] = append(responsesByCorrelation[response.CorrelationID], response)
+	}
+
+	for _, c := range commands {
+		history := CommandHistory{
+			ID:            c.ID,
+			CommandType:   c.CommandDefinition.CommandType,
+			CorrelationID: c.CorrelationID,
+			Status:        c.Status,
+			RequestTime:   c.RequestTime,
+			ResponseTime:  c.ResponseTime,
+			ErrorMessage:  c.ErrorMessage,
+			Parameters:    DecodeParameters(c.ParameterOverrides),
+			Executions:    executionsByCommand[c.ID],
+			Responses:     responsesByCorrelation[c.CorrelationID],
+		}
+		if history.Executions == nil {
+			history.Executions = []CommandExecutionHistory{}
+		}
+		if history.Responses == nil || c.CorrelationID == "" {
+			history.Responses = []models.PLCResponse{}
+		}
+		histories = append(histories, history)
+	}
+	return histories, nil
+}
+
+// truncateText 컬럼 길이에 맞게 자르기 (UTF-8 문자 중간에서 자르지 않음)
+func truncateText(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	return strings.ToValidUTF8(text[:limit], "")
+}
@@ -0,0 +1,258 @@
+// internal/repository/direct_actions.go
+package repository
+
+import (
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/utils"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// DirectActionParameter 직접 액션 오더의 액션 파라미터
+type DirectActionParameter struct {
+	Key   string
+	Value string
+}
+
+// DirectActionCall 레지스트리로 해석된 직접 액션 명령
+type DirectActionCall struct {
+	BaseCommand  string
+	Suffix       string
+	ActionType   string
+	BlockingType string
+	Parameters   []DirectActionParameter
+}
+
+// ListDirectActions 직접 액션 정의 목록을 인자와 함께 조회
+func ListDirectActions(db *gorm.DB) ([]models.DirectActionDefinition, error) {
+	var defs []models.DirectActionDefinition
+	err := db.Preload("Arguments", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("position ASC")
+	}).Order("suffix ASC").Find(&defs).Error
+	return defs, err
+}
+
+// FindDirectAction 접미사로 직접 액션 정의 조회 (없으면 nil 반환)
+func FindDirectAction(db *gorm.DB, suffix string) (*models.DirectActionDefinition, error) {
+	var def models.DirectActionDefinition
+	err := db.Preload("Arguments", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("position ASC")
+	}).Where("suffix = ?", suffix).First(&def).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &def, nil
+}
+
+// SaveDirectAction 직접 액션 정의를 저장합니다.
+// 같은 접미사가 이미 있으면 정의를 갱신하고 인자를 전달된 목록으로 교체합니다.
+func SaveDirectAction(db *gorm.DB, def *models.DirectActionDefinition) error {
+	if err := validateDirectAction(def); err != nil {
+		return err
+	}
+
+	arguments := def.Arguments
+	def.Arguments = nil
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var existing models.DirectActionDefinition
+		result := tx.Where("suffix = ?", def.Suffix).First(&existing)
+		if result.Error == nil {
+			def.ID = existing.ID
+			def.CreatedAt = existing.CreatedAt
+			if err := tx.Save(def).Error; err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Where("direct_action_definition_id = ?", def.ID).Delete(&models.DirectActionArgument{}).Error; err != nil {
+				return err
+			}
+		} else if result.Error == gorm.ErrRecordNotFound {
+			if err := tx.Create(def).Error; err != nil {
+				return err
+			}
+		} else {
+			return result.Error
+		}
+
+		for i := range arguments {
+			arguments[i].ID = 0
+			arguments[i].DirectActionDefinitionID = def.ID
+			if err := tx.Create(&arguments[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	def.Arguments = arguments
+	if err != nil {
+		return fmt.Errorf("failed to save direct action %s: %w", def.Suffix, err)
+	}
+
+	utils.Logger.Infof("Direct action :%s saved (actionType=%s, arguments=%d)", def.Suffix, def.ActionType, len(arguments))
+	return nil
+}
+
+// DeleteDirectAction 직접 액션 정의와 인자를 삭제합니다.
+func DeleteDirectAction(db *gorm.DB, suffix string) error {
+	def, err := FindDirectAction(db, suffix)
+	if err != nil {
+		return err
+	}
+	if def == nil {
+		return apperr.New(apperr.CodeNotFound, "direct action %s not found", suffix).WithField("suffix")
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("direct_action_definition_id = ?", def.ID).Delete(&models.DirectActionArgument{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(def).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete direct action %s: %w", suffix, err)
+	}
+
+	utils.Logger.Infof("Direct action :%s deleted", suffix)
+	return nil
+}
+
+// ResolveDirectActionCommand PLC 명령이 등록된 직접 액션이면 해석 결과를 반환합니다.
+// 접미사가 없거나 활성 정의가 없으면 (nil, nil)을 반환하여 표준 명령으로 처리하게 합니다.
+// 정의는 있지만 인자가 맞지 않으면 오류를 반환합니다.
+func ResolveDirectActionCommand(db *gorm.DB, commandStr string) (*DirectActionCall, error) {
+	parts := strings.Split(commandStr, ":")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return nil, nil
+	}
+
+	def, err := FindDirectAction(db, parts[1])
+	if err != nil {
+		return nil, err
+	}
+	if def == nil || !def.IsActive {
+		return nil, nil
+	}
+	return ResolveDirectAction(def, parts[0], parts[2:])
+}
+
+// ResolveDirectAction 기준 명령과 위치 인자를 정의에 맞춰 액션 파라미터로 변환
+func ResolveDirectAction(def *models.DirectActionDefinition, baseCommand string, args []string) (*DirectActionCall, error) {
+	if len(args) > len(def.Arguments) {
+		return nil, apperr.New(apperr.CodeValidationFailed, "direct action :%s accepts at most %d argument(s), got %d", def.Suffix, len(def.Arguments), len(args))
+	}
+
+	call := &DirectActionCall{
+		BaseCommand:  baseCommand,
+		Suffix:       def.Suffix,
+		ActionType:   def.ActionType,
+		BlockingType: def.BlockingType,
+		Parameters:   []DirectActionParameter{{Key: def.ParameterKey, Value: baseCommand}},
+	}
+
+	arguments := append([]models.DirectActionArgument(nil), def.Arguments...)
+	sort.Slice(arguments, func(i, j int) bool { return arguments[i].Position < arguments[j].Position })
+
+	for i, arg := range arguments {
+		value := ""
+		if i < len(args) {
+			value = args[i]
+		}
+		if value == "" {
+			if arg.Required {
+				return nil, apperr.Validation(arg.Key, "direct action :%s requires argument %s", def.Suffix, arg.Key)
+			}
+			if arg.DefaultValue == "" {
+				continue
+			}
+			call.Parameters = append(call.Parameters, DirectActionParameter{Key: arg.Key, Value: arg.DefaultValue})
+			continue
+		}
+
+		mapped, err := mapArgumentValue(arg, value)
+		if err != nil {
+			return nil, apperr.Validation(arg.Key, "direct action :%s: %v", def.Suffix, err)
+		}
+		call.Parameters = append(call.Parameters, DirectActionParameter{Key: arg.Key, Value: mapped})
+	}
+	return call, nil
+}
+
+// validateDirectAction 저장 전 정의 검증
+func validateDirectAction(def *models.DirectActionDefinition) error {
+	if def.Suffix == "" || strings.Contains(def.Suffix, ":") {
+		return apperr.Validation("suffix", "direct action suffix is required and must not contain ':'")
+	}
+	if def.ActionType == "" {
+		return apperr.Validation("action_type", "direct action :%s: action type is required", def.Suffix)
+	}
+	if def.ParameterKey == "" {
+		return apperr.Validation("parameter_key", "direct action :%s: parameter key is required", def.Suffix)
+	}
+	switch def.BlockingType {
+	case "":
+		def.BlockingType = constants.BlockingTypeNone
+	case constants.BlockingTypeNone, constants.BlockingTypeSoft, constants.BlockingTypeHard:
+	default:
+		return apperr.Validation("blocking_type", "direct action :%s: invalid blocking type %q", def.Suffix, def.BlockingType)
+	}
+
+	positions := make(map[int]bool)
+	for _, arg := range def.Arguments {
+		if arg.Key == "" {
+			return apperr.Validation("arguments", "direct action :%s: argument key is required", def.Suffix)
+		}
+		if arg.Position < 1 || positions[arg.Position] {
+			return apperr.Validation("arguments", "direct action :%s: argument %s has invalid or duplicate position %d", def.Suffix, arg.Key, arg.Position)
+		}
+		positions[arg.Position] = true
+		if _, err := parseValueMap(arg.ValueMap); err != nil {
+			return apperr.Validation("arguments", "direct action :%s: argument %s: %v", def.Suffix, arg.Key, err)
+		}
+	}
+	for i := 1; i <= len(def.Arguments); i++ {
+		if !positions[i] {
+			return apperr.Validation("arguments", "direct action :%s: argument positions must be contiguous from 1", def.Suffix)
+		}
+	}
+	return nil
+}
+
+// mapArgumentValue 인자의 값 매핑 적용 (매핑이 있으면 정의된 값만 허용)
+func mapArgumentValue(arg models.DirectActionArgument, value string) (string, error) {
+	valueMap, err := parseValueMap(arg.ValueMap)
+	if err != nil {
+		return "", err
+	}
+	if len(valueMap) == 0 {
+		return value, nil
+	}
+	mapped, ok := valueMap[value]
+	if !ok {
+		return "", fmt.Errorf("argument %s does not accept %q", arg.Key, value)
+	}
+	return mapped, nil
+}
+
+// parseValueMap "R=right,L=left" 형식의 값 매핑 파싱
+func parseValueMap(s string) (map[string]string, error) {
+	valueMap := make(map[string]string)
+	if strings.TrimSpace(s) == "" {
+		return valueMap, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		from, to, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("invalid value map entry %q", pair)
+		}
+		valueMap[from] = to
+	}
+	return valueMap, nil
+}
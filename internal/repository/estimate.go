@@ -0,0 +1,197 @@
+// internal/repository/estimate.go
+package repository
+
+import (
+	"math"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 소요 시간 추정 근거
+const (
+	EstimateBasisRobot    = "robot"    // 같은 템플릿, 같은 로봇의 이력
+	EstimateBasisTemplate = "template" // 로봇 이력이 부족해 같은 템플릿의 모든 로봇 이력 사용
+	EstimateBasisNone     = "none"     // 완료된 이력 없음
+)
+
+const (
+	estimateSampleLimit    = 50    // 추정에 쓰는 최근 완료 오더 수
+	estimateMinRobotSample = 3     // 로봇 이력만으로 추정하기 위한 최소 완료 오더 수
+	estimateConfidence     = 0.9   // 신뢰 구간 수준
+	estimateZScore         = 1.645 // 90% 양측 구간의 표준 정규 분위수
+)
+
+// DurationEstimate 템플릿 오더의 예상 소요 시간과 신뢰 구간
+type DurationEstimate struct {
+	TemplateID   uint                   `json:"template_id"`
+	SerialNumber string                 `json:"serial_number,omitempty"`
+	Basis        string                 `json:"basis"`       // robot, template, none
+	SampleSize   int                    `json:"sample_size"` // 추정에 쓴 완료 오더 수
+	ExpectedMs   int64                  `json:"expected_ms"`
+	LowerMs      int64                  `json:"lower_ms"`
+	UpperMs      int64                  `json:"upper_ms"`
+	Confidence   float64                `json:"confidence"` // 신뢰 구간 수준 (0~1)
+	Steps        []StepDurationEstimate `json:"steps"`
+}
+
+// StepDurationEstimate 단계별 평균 소요 시간 (실행 중 오더의 ETA 계산용)
+type StepDurationEstimate struct {
+	StepOrder  int   `json:"step_order"`
+	SampleSize int   `json:"sample_size"`
+	ExpectedMs int64 `json:"expected_ms"`
+}
+
+// EstimateOrderDuration 같은 템플릿/로봇의 완료된 오더와 단계 실행 이력으로 소요 시간 추정
+// 로봇의 완료 이력이 estimateMinRobotSample개보다 적으면 같은 템플릿의 모든 로봇 이력을 사용하며,
+// serialNumber가 비어 있으면 처음부터 템플릿 전체 이력을 사용합니다.
+func EstimateOrderDuration(db *gorm.DB, siteID string, templateID uint, serialNumber string) (*DurationEstimate, error) {
+	var template models.OrderTemplate
+	if err := db.Scopes(SiteScope(siteID)).Select("id").First(&template, templateID).Error; err != nil {
+		return nil, apperr.Wrap(apperr.CodeTemplateNotFound, err, "order template %d not found", templateID)
+	}
+
+	estimate := &DurationEstimate{
+		TemplateID:   templateID,
+		SerialNumber: serialNumber,
+		Basis:        EstimateBasisNone,
+		Confidence:   estimateConfidence,
+		Steps:        make([]StepDurationEstimate, 0),
+	}
+
+	var executions []models.OrderExecution
+	if serialNumber != "" {
+		var err error
+		if executions, err = completedExecutions(db, siteID, templateID, serialNumber); err != nil {
+			return nil, err
+		}
+		if len(executions) >= estimateMinRobotSample {
+			estimate.Basis = EstimateBasisRobot
+		}
+	}
+	if estimate.Basis == EstimateBasisNone {
+		var err error
+		if executions, err = completedExecutions(db, siteID, templateID, ""); err != nil {
+			return nil, err
+		}
+		if len(executions) > 0 {
+			estimate.Basis = EstimateBasisTemplate
+		}
+	}
+	if len(executions) == 0 {
+		return estimate, nil
+	}
+
+	durations := make([]float64, 0, len(executions))
+	ids := make([]uint, 0, len(executions))
+	for _, execution := range executions {
+		durations = append(durations, float64(execution.CompletedAt.Sub(execution.StartedAt).Milliseconds()))
+		ids = append(ids, execution.ID)
+	}
+	mean, stddev := meanStddev(durations)
+	margin := estimateZScore * stddev
+	estimate.SampleSize = len(durations)
+	estimate.ExpectedMs = int64(math.Round(mean))
+	estimate.LowerMs = int64(math.Round(math.Max(0, mean-margin)))
+	estimate.UpperMs = int64(math.Round(mean + margin))
+
+	steps, err := stepDurations(db, ids)
+	if err != nil {
+		return nil, err
+	}
+	estimate.Steps = steps
+	return estimate, nil
+}
+
+// EstimateRemaining 실행 중인 오더가 fromStep 단계부터 끝날 때까지 남은 예상 시간 (추정할 이력이 없으면 false)
+// 단계 이력이 없으면 오더 전체 예상 시간에서 경과 시간을 뺍니다.
+func (e *DurationEstimate) EstimateRemaining(fromStep int, elapsed time.Duration) (time.Duration, bool) {
+	if e.SampleSize == 0 {
+		return 0, false
+	}
+	var remainingMs int64
+	found := false
+	for _, step := range e.Steps {
+		if step.StepOrder >= fromStep {
+			remainingMs += step.ExpectedMs
+			found = true
+		}
+	}
+	if !found {
+		remainingMs = e.ExpectedMs - elapsed.Milliseconds()
+	}
+	if remainingMs < 0 {
+		remainingMs = 0
+	}
+	return time.Duration(remainingMs) * time.Millisecond, true
+}
+
+// UpdateOrderExecutionETA 실행 중인 오더의 예상 완료 시각 저장
+func UpdateOrderExecutionETA(db *gorm.DB, execution *models.OrderExecution, eta time.Time) error {
+	execution.EstimatedCompletionAt = &eta
+	return db.Model(execution).Update("estimated_completion_at", eta).Error
+}
+
+// completedExecutions 템플릿의 최근 완료 오더 (serialNumber가 비어 있으면 모든 로봇)
+func completedExecutions(db *gorm.DB, siteID string, templateID uint, serialNumber string) ([]models.OrderExecution, error) {
+	query := db.Scopes(SiteScope(siteID)).
+		Select("id", "started_at", "completed_at").
+		Where("template_id = ? AND status = ? AND completed_at IS NOT NULL",
+			templateID, constants.OrderExecutionStatusCompleted)
+	if serialNumber != "" {
+		query = query.Where("serial_number = ?", serialNumber)
+	}
+	var executions []models.OrderExecution
+	err := query.Order("completed_at DESC").Limit(estimateSampleLimit).Find(&executions).Error
+	return executions, err
+}
+
+// stepDurations 오더 실행들의 완료된 단계별 평균 소요 시간 (step_order 순)
+func stepDurations(db *gorm.DB, executionIDs []uint) ([]StepDurationEstimate, error) {
+	var steps []models.StepExecution
+	if err := db.Select("step_order", "started_at", "completed_at").
+		Where("execution_id IN ? AND status = ? AND completed_at IS NOT NULL",
+			executionIDs, constants.StepExecutionStatusFinished).
+		Find(&steps).Error; err != nil {
+		return nil, err
+	}
+
+	byStep := make(map[int][]float64)
+	for _, step := range steps {
+		byStep[step.StepOrder] = append(byStep[step.StepOrder], float64(step.CompletedAt.Sub(step.StartedAt).Milliseconds()))
+	}
+	result := make([]StepDurationEstimate, 0, len(byStep))
+	for stepOrder, durations := range byStep {
+		mean, _ := meanStddev(durations)
+		result = append(result, StepDurationEstimate{
+			StepOrder:  stepOrder,
+			SampleSize: len(durations),
+			ExpectedMs: int64(math.Round(mean)),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].StepOrder < result[j].StepOrder })
+	return result, nil
+}
+
+// meanStddev 평균과 표본 표준편차 (표본이 하나면 표준편차 0)
+func meanStddev(values []float64) (mean, stddev float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	if len(values) < 2 {
+		return mean, 0
+	}
+	var sumSq float64
+	for _, v := range values {
+		sumSq += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(sumSq / float64(len(values)-1))
+}
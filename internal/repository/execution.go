@@ -2,13 +2,39 @@
 package repository
 
 import (
+	"encoding/json"
+	"mqtt-bridge/internal/common/constants"
 	"mqtt-bridge/internal/models"
 	"mqtt-bridge/internal/utils"
+	"sort"
+	"sync"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+var (
+	orderStatusListenerMu sync.RWMutex
+	orderStatusListener   func(execution models.OrderExecution)
+)
+
+// SetOrderStatusListener 오더 실행 상태가 바뀔 때마다 호출할 함수 설정 (nil이면 해제)
+func SetOrderStatusListener(listener func(execution models.OrderExecution)) {
+	orderStatusListenerMu.Lock()
+	orderStatusListener = listener
+	orderStatusListenerMu.Unlock()
+}
+
+// notifyOrderStatus 설정된 리스너에 바뀐 오더 실행 전달
+func notifyOrderStatus(execution *models.OrderExecution) {
+	orderStatusListenerMu.RLock()
+	listener := orderStatusListener
+	orderStatusListenerMu.RUnlock()
+	if listener != nil {
+		listener(*execution)
+	}
+}
+
 // UpdateCommandStatus Command의 최종 상태를 업데이트합니다.
 func UpdateCommandStatus(db *gorm.DB, command *models.Command, status, errMsg string) {
 	command.Status = status
@@ -39,6 +65,7 @@ func UpdateOrderExecutionStatus(db *gorm.DB, exec *models.OrderExecution, status
 	}
 	db.Save(exec)
 	utils.Logger.Infof("OrderExecution for order %s status updated to %s", exec.OrderID, status)
+	notifyOrderStatus(exec)
 }
 
 // UpdateStepExecutionStatus StepExecution의 상태를 업데이트합니다.
@@ -54,3 +81,106 @@ func UpdateStepExecutionStatus(db *gorm.DB, exec *models.StepExecution, status,
 	db.Save(exec)
 	utils.Logger.Infof("StepExecution %d for order %d status updated to %s", exec.ID, exec.ExecutionID, status)
 }
+
+// NodeProgress state 메시지에서 읽은 오더의 노드 진행 상황
+type NodeProgress struct {
+	LastNodeID         string
+	LastNodeSequenceID int
+	RemainingNodes     []string // sequenceId 순
+}
+
+// NodeProgressFromState state 메시지의 lastNodeId와 nodeStates로 진행 상황 구성
+func NodeProgressFromState(stateMsg *models.RobotStateMessage) NodeProgress {
+	nodes := append([]models.NodeState(nil), stateMsg.NodeStates...)
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].SequenceID < nodes[j].SequenceID })
+
+	remaining := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		remaining = append(remaining, node.NodeID)
+	}
+	return NodeProgress{
+		LastNodeID:         stateMsg.LastNodeID,
+		LastNodeSequenceID: stateMsg.LastNodeSequenceID,
+		RemainingNodes:     remaining,
+	}
+}
+
+// UpdateNodeProgress 실행 중인 오더의 노드 진행 상황 저장
+// 값이 바뀌었을 때만 기록하며, 순서가 뒤바뀐 state로 lastNodeSequenceId가 되돌아가지 않습니다.
+// 갱신되었으면 true를 반환합니다.
+func UpdateNodeProgress(db *gorm.DB, siteID, orderID string, progress NodeProgress) (bool, error) {
+	remaining, err := json.Marshal(progress.RemainingNodes)
+	if err != nil {
+		return false, err
+	}
+	result := db.Model(&models.OrderExecution{}).
+		Scopes(SiteScope(siteID)).
+		Where("order_id = ? AND status = ? AND last_node_sequence_id <= ?",
+			orderID, constants.OrderExecutionStatusRunning, progress.LastNodeSequenceID).
+		Where("COALESCE(last_node_id, '') <> ? OR last_node_sequence_id <> ? OR COALESCE(remaining_nodes, '') <> ?",
+			progress.LastNodeID, progress.LastNodeSequenceID, string(remaining)).
+		Updates(map[string]interface{}{
+			"last_node_id":          progress.LastNodeID,
+			"last_node_sequence_id": progress.LastNodeSequenceID,
+			"remaining_nodes":       string(remaining),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// RemainingNodeIDs 저장된 남은 노드 목록 (기록이 없으면 nil)
+func RemainingNodeIDs(execution *models.OrderExecution) []string {
+	if execution.RemainingNodes == "" {
+		return nil
+	}
+	var nodes []string
+	if err := json.Unmarshal([]byte(execution.RemainingNodes), &nodes); err != nil {
+		return nil
+	}
+	return nodes
+}
+
+// OrderExecutionFilter 오더 실행 이력 조회 조건
+type OrderExecutionFilter struct {
+	SerialNumber string
+	Status       string
+	Since        time.Time
+	Limit        int
+	Label        string // 이 라벨의 주석이 있는 오더 (명령 주석 포함)
+	Note         string // 메모에 이 문자열이 들어 있는 주석이 있는 오더 (대소문자 무시)
+}
+
+// ListOrderExecutions 사이트의 오더 실행 이력을 최신순으로 조회
+func ListOrderExecutions(db *gorm.DB, siteID string, filter OrderExecutionFilter) ([]models.OrderExecution, error) {
+	query := db.Scopes(SiteScope(siteID))
+	if filter.SerialNumber != "" {
+		query = query.Where("serial_number = ?", filter.SerialNumber)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where("started_at >= ?", filter.Since)
+	}
+	if filter.Label != "" || filter.Note != "" {
+		query = query.Scopes(annotatedOrders(siteID, filter.Label, filter.Note))
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var executions []models.OrderExecution
+	err := query.Order("started_at DESC").Find(&executions).Error
+	return executions, err
+}
+
+// ListConnectionHistory 로봇의 연결 상태 변경 이력을 최신순으로 조회
+func ListConnectionHistory(db *gorm.DB, siteID, serialNumber string, since time.Time, limit int) ([]models.ConnectionStateTransition, error) {
+	query := db.Scopes(SiteScope(siteID)).Where("serial_number = ? AND observed_at >= ?", serialNumber, since)
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var transitions []models.ConnectionStateTransition
+	err := query.Order("observed_at DESC").Find(&transitions).Error
+	return transitions, err
+}
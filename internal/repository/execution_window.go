@@ -0,0 +1,223 @@
+// internal/repository/execution_window.go
+package repository
+
+import (
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/utils"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WindowBlock 오더를 막는 실행 제한 시간대와 제한이 끝나는 시각
+type WindowBlock struct {
+	Window  models.ExecutionWindow
+	OpensAt time.Time
+}
+
+// ListExecutionWindows 사이트의 실행 제한 시간대 목록
+func ListExecutionWindows(db *gorm.DB, siteID string) ([]models.ExecutionWindow, error) {
+	var windows []models.ExecutionWindow
+	err := db.Scopes(SiteScope(siteID)).Order("name ASC").Find(&windows).Error
+	return windows, err
+}
+
+// SaveExecutionWindow 실행 제한 시간대를 저장합니다 (같은 이름이 있으면 갱신, 관리자 해제 시각은 유지).
+func SaveExecutionWindow(db *gorm.DB, siteID string, window *models.ExecutionWindow) error {
+	if strings.TrimSpace(window.Name) == "" {
+		return apperr.Validation("name", "window name is required")
+	}
+	start, err := parseClock(window.StartTime)
+	if err != nil {
+		return apperr.Validation("startTime", "invalid start time %q: use HH:MM", window.StartTime)
+	}
+	end, err := parseClock(window.EndTime)
+	if err != nil {
+		return apperr.Validation("endTime", "invalid end time %q: use HH:MM", window.EndTime)
+	}
+	if start == end {
+		return apperr.Validation("endTime", "window start and end must differ")
+	}
+	if _, err := time.LoadLocation(window.Timezone); err != nil {
+		return apperr.Validation("timezone", "unknown timezone %q", window.Timezone)
+	}
+	window.Policy = strings.ToUpper(window.Policy)
+	switch window.Policy {
+	case "":
+		window.Policy = constants.ExecutionWindowPolicyQueue
+	case constants.ExecutionWindowPolicyQueue, constants.ExecutionWindowPolicyReject:
+	default:
+		return apperr.Validation("policy", "invalid policy %q (QUEUE, REJECT)", window.Policy)
+	}
+	if window.TemplateID != nil {
+		var count int64
+		db.Model(&models.OrderTemplate{}).Scopes(SiteScope(siteID)).Where("id = ?", *window.TemplateID).Count(&count)
+		if count == 0 {
+			return apperr.New(apperr.CodeTemplateNotFound, "order template %d not found", *window.TemplateID)
+		}
+	}
+	window.Robots = strings.Join(normalizeRobotList(strings.Split(window.Robots, ",")), ",")
+	window.SiteID = siteID
+
+	var existing models.ExecutionWindow
+	result := db.Scopes(SiteScope(siteID)).Where("name = ?", window.Name).First(&existing)
+	switch {
+	case result.Error == nil:
+		window.ID = existing.ID
+		window.CreatedAt = existing.CreatedAt
+		window.OverrideUntil = existing.OverrideUntil
+		// gorm Save는 false 값도 저장하므로 IsActive를 그대로 반영
+		if err := db.Save(window).Error; err != nil {
+			return fmt.Errorf("failed to save execution window %s: %w", window.Name, err)
+		}
+	case result.Error == gorm.ErrRecordNotFound:
+		active := window.IsActive
+		if err := db.Create(window).Error; err != nil {
+			return fmt.Errorf("failed to create execution window %s: %w", window.Name, err)
+		}
+		// gorm default:true 필드는 false 값이 생략되므로 명시적으로 갱신
+		if !active {
+			if err := db.Model(window).Update("is_active", false).Error; err != nil {
+				return err
+			}
+		}
+	default:
+		return result.Error
+	}
+	utils.Logger.Infof("🌙 Execution window %s saved: %s-%s %s", window.Name, window.StartTime, window.EndTime, window.Policy)
+	return nil
+}
+
+// DeleteExecutionWindow 실행 제한 시간대 삭제
+func DeleteExecutionWindow(db *gorm.DB, siteID, name string) error {
+	result := db.Unscoped().Scopes(SiteScope(siteID)).Where("name = ?", name).Delete(&models.ExecutionWindow{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperr.New(apperr.CodeNotFound, "execution window %s not found", name)
+	}
+	utils.Logger.Infof("🌙 Execution window %s deleted", name)
+	return nil
+}
+
+// SetExecutionWindowOverride 관리자가 until까지 시간대 제한을 해제합니다 (nil이면 해제 취소).
+func SetExecutionWindowOverride(db *gorm.DB, siteID, name string, until *time.Time) error {
+	result := db.Model(&models.ExecutionWindow{}).Scopes(SiteScope(siteID)).Where("name = ?", name).
+		Update("override_until", until)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperr.New(apperr.CodeNotFound, "execution window %s not found", name)
+	}
+	if until != nil {
+		utils.Logger.Warnf("🌙 Execution window %s overridden until %s", name, until.Format(time.RFC3339))
+	} else {
+		utils.Logger.Infof("🌙 Execution window %s override cleared", name)
+	}
+	return nil
+}
+
+// CheckExecutionWindows 지금 템플릿 오더를 로봇에 보낼 수 없게 하는 시간대를 찾습니다 (없으면 nil).
+// REJECT 시간대가 QUEUE보다 우선하며, QUEUE 시간대가 여럿이면 가장 늦게 끝나는 시간대를 반환합니다.
+func CheckExecutionWindows(db *gorm.DB, siteID string, templateID uint, serialNumber string, now time.Time) (*WindowBlock, error) {
+	var windows []models.ExecutionWindow
+	err := db.Scopes(SiteScope(siteID)).
+		Where("is_active = ? AND (template_id IS NULL OR template_id = ?)", true, templateID).
+		Find(&windows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var block *WindowBlock
+	for _, window := range windows {
+		if window.OverrideUntil != nil && now.Before(*window.OverrideUntil) {
+			continue
+		}
+		if !windowAppliesToRobot(&window, serialNumber) {
+			continue
+		}
+		opensAt, closed := windowOpensAt(&window, now)
+		if !closed {
+			continue
+		}
+		candidate := &WindowBlock{Window: window, OpensAt: opensAt}
+		switch {
+		case block == nil:
+			block = candidate
+		case window.Policy == constants.ExecutionWindowPolicyReject && block.Window.Policy != constants.ExecutionWindowPolicyReject:
+			block = candidate
+		case window.Policy == block.Window.Policy && opensAt.After(block.OpensAt):
+			block = candidate
+		}
+	}
+	return block, nil
+}
+
+// ExecutionWindowError 실행 제한 시간대에 대한 배차 거부 오류
+func ExecutionWindowError(block *WindowBlock) *apperr.Error {
+	return apperr.New(apperr.CodeExecutionWindow, "Orders are not allowed during execution window %s (%s-%s) until %s",
+		block.Window.Name, block.Window.StartTime, block.Window.EndTime, block.OpensAt.Format(time.RFC3339))
+}
+
+func windowAppliesToRobot(window *models.ExecutionWindow, serialNumber string) bool {
+	robots := normalizeRobotList(strings.Split(window.Robots, ","))
+	if len(robots) == 0 {
+		return true
+	}
+	for _, robot := range robots {
+		if robot == serialNumber {
+			return true
+		}
+	}
+	return false
+}
+
+// windowOpensAt now가 시간대 안이면 시간대가 끝나는 시각과 true를 반환
+func windowOpensAt(window *models.ExecutionWindow, now time.Time) (time.Time, bool) {
+	start, err := parseClock(window.StartTime)
+	if err != nil {
+		return time.Time{}, false
+	}
+	end, err := parseClock(window.EndTime)
+	if err != nil {
+		return time.Time{}, false
+	}
+	loc, err := time.LoadLocation(window.Timezone)
+	if err != nil {
+		loc = time.Local
+	}
+
+	local := now.In(loc)
+	current := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute + time.Duration(local.Second())*time.Second
+	var inside bool
+	if start < end {
+		inside = current >= start && current < end
+	} else {
+		inside = current >= start || current < end
+	}
+	if !inside {
+		return time.Time{}, false
+	}
+
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	opensAt := midnight.Add(end)
+	if !opensAt.After(local) {
+		opensAt = time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, loc).Add(end)
+	}
+	return opensAt, true
+}
+
+// parseClock "HH:MM"을 자정 기준 경과 시간으로 변환
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,302 @@
+// internal/repository/jobs.go
+package repository
+
+import (
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/common/idgen"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/utils"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// defaultJobListLimit 작업 묶음 목록 기본 건수
+const defaultJobListLimit = 50
+
+// JobRequest 작업 묶음 생성 요청
+type JobRequest struct {
+	Name  string           `json:"name"`
+	Tasks []JobTaskRequest `json:"tasks"`
+}
+
+// JobTaskRequest 작업 항목 생성 요청 (로봇 하나에서 실행할 명령 하나)
+type JobTaskRequest struct {
+	SerialNumber string            `json:"serial_number"`
+	CommandType  string            `json:"command_type"`
+	Parameters   map[string]string `json:"parameters,omitempty"`
+}
+
+// JobFilter 작업 묶음 조회 조건 (빈 값은 조건 없음)
+type JobFilter struct {
+	Status string
+	Robot  string
+	Limit  int
+}
+
+// CreateJob 작업 묶음을 모두 승인하거나 모두 거부합니다.
+// 모든 작업 항목의 명령이 정의되어 있고 파라미터가 맞으며, 로봇이 등록되어 ONLINE이고 유지보수 중이 아니어야 하며,
+// 하나라도 맞지 않으면 아무것도 만들지 않고 모든 문제를 모아 오류로 반환합니다.
+// 승인된 작업 항목은 PENDING으로 만들어지고 각 로봇을 관리하는 브릿지가 가져가 실행합니다.
+func CreateJob(db *gorm.DB, siteID string, request JobRequest) (*models.Job, error) {
+	if len(request.Tasks) == 0 {
+		return nil, apperr.Validation("tasks", "job needs at least one task")
+	}
+	if len(request.Name) > 100 {
+		return nil, apperr.Validation("name", "job name must be at most 100 characters")
+	}
+
+	var problems []string
+	code, field := apperr.CodeValidationFailed, ""
+	problem := func(i int, c apperr.Code, format string, args ...interface{}) {
+		if len(problems) == 0 {
+			code, field = c, fmt.Sprintf("tasks[%d]", i)
+		}
+		problems = append(problems, fmt.Sprintf("task %d: %s", i+1, fmt.Sprintf(format, args...)))
+	}
+
+	checkedRobots := make(map[string]bool)
+	for i, task := range request.Tasks {
+		if task.SerialNumber == "" || task.CommandType == "" {
+			problem(i, apperr.CodeValidationFailed, "serial_number and command_type are required")
+			continue
+		}
+		var definition models.CommandDefinition
+		if err := db.Where("command_type = ? AND is_active = ?", task.CommandType, true).First(&definition).Error; err != nil {
+			problem(i, apperr.CodeCommandNotFound, "command %s not defined or inactive", task.CommandType)
+		} else if err := ValidateCommandParameters(db, definition.ID, task.Parameters); err != nil {
+			problem(i, apperr.CodeValidationFailed, "%s", apperr.From(err).Message)
+		}
+
+		if checkedRobots[task.SerialNumber] {
+			continue
+		}
+		checkedRobots[task.SerialNumber] = true
+		var status models.RobotStatus
+		err := db.Scopes(SiteScope(siteID)).Where("serial_number = ?", task.SerialNumber).First(&status).Error
+		switch {
+		case err == gorm.ErrRecordNotFound:
+			problem(i, apperr.CodeNotFound, "robot %s is not known on site %s", task.SerialNumber, siteID)
+			continue
+		case err != nil:
+			return nil, err
+		case status.ConnectionState != constants.ConnectionStateOnline:
+			problem(i, apperr.CodeRobotOffline, "robot %s is %s", task.SerialNumber, status.ConnectionState)
+		}
+		maintenance, err := ActiveMaintenance(db, siteID, task.SerialNumber)
+		if err != nil {
+			return nil, err
+		}
+		if maintenance != nil {
+			problem(i, apperr.CodeRobotMaintenance, "robot %s is in maintenance (%s)", task.SerialNumber, maintenance.Reason)
+		}
+	}
+	if len(problems) > 0 {
+		return nil, apperr.New(code, "job rejected: %s", strings.Join(problems, "; ")).WithField(field)
+	}
+
+	job := &models.Job{
+		JobID:         idgen.JobID(),
+		SiteID:        siteID,
+		Name:          request.Name,
+		Status:        constants.JobStatusPending,
+		CorrelationID: idgen.CorrelationID(),
+	}
+	for i, task := range request.Tasks {
+		job.Tasks = append(job.Tasks, models.JobTask{
+			SiteID:       siteID,
+			Sequence:     i + 1,
+			SerialNumber: task.SerialNumber,
+			CommandType:  task.CommandType,
+			Parameters:   EncodeParameters(task.Parameters),
+			Status:       constants.JobStatusPending,
+		})
+	}
+	if err := db.Transaction(func(tx *gorm.DB) error { return tx.Create(job).Error }); err != nil {
+		return nil, err
+	}
+	utils.Logger.Infof("🧩 Job %s created with %d task(s) across %d robot(s) (cid=%s)",
+		job.JobID, len(job.Tasks), len(checkedRobots), job.CorrelationID)
+	return job, nil
+}
+
+// GetJob 작업 묶음과 작업 항목 조회
+func GetJob(db *gorm.DB, siteID, jobID string) (*models.Job, error) {
+	var job models.Job
+	err := db.Scopes(SiteScope(siteID)).Where("job_id = ?", jobID).
+		Preload("Tasks", func(db *gorm.DB) *gorm.DB { return db.Order("sequence ASC") }).
+		First(&job).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, apperr.New(apperr.CodeNotFound, "job %s not found", jobID).WithField("jobId")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ListJobs 작업 묶음 목록 (최근 순, 작업 항목 포함)
+func ListJobs(db *gorm.DB, siteID string, filter JobFilter) ([]models.Job, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultJobListLimit
+	}
+	query := db.Scopes(SiteScope(siteID))
+	if filter.Status != "" {
+		query = query.Where("status = ?", strings.ToUpper(filter.Status))
+	}
+	if filter.Robot != "" {
+		query = query.Where("id IN (?)", db.Model(&models.JobTask{}).Select("job_ref").Where("serial_number = ?", filter.Robot))
+	}
+	var jobs []models.Job
+	err := query.Preload("Tasks", func(db *gorm.DB) *gorm.DB { return db.Order("sequence ASC") }).
+		Order("id DESC").
+		Limit(filter.Limit).
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// CancelJob 작업 묶음 전체 취소
+// 아직 시작하지 않은 작업 항목은 바로 CANCELLED가 되고, 실행 중인 작업 항목은 그 로봇을 관리하는 브릿지가
+// 다음 확인 때 오더를 취소합니다.
+func CancelJob(db *gorm.DB, siteID, jobID, reason string) (*models.Job, error) {
+	if reason == "" {
+		reason = "cancelled by operator"
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var job models.Job
+		if err := tx.Scopes(SiteScope(siteID)).Where("job_id = ?", jobID).First(&job).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return apperr.New(apperr.CodeNotFound, "job %s not found", jobID).WithField("jobId")
+			}
+			return err
+		}
+		if job.Status != constants.JobStatusPending && job.Status != constants.JobStatusRunning {
+			return apperr.New(apperr.CodeValidationFailed, "job %s is already %s", jobID, job.Status).WithField("jobId")
+		}
+		now := time.Now()
+		result := tx.Model(&job).Where("status IN ?", []string{constants.JobStatusPending, constants.JobStatusRunning}).
+			Updates(map[string]interface{}{"status": constants.JobStatusCancelled, "cancel_reason": truncateText(reason, 255), "finished_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperr.New(apperr.CodeValidationFailed, "job %s finished while cancelling", jobID).WithField("jobId")
+		}
+		return tx.Model(&models.JobTask{}).Where("job_ref = ? AND status = ?", job.ID, constants.JobStatusPending).
+			Updates(map[string]interface{}{"status": constants.JobStatusCancelled, "error_message": "job cancelled", "finished_at": now}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	utils.Logger.Warnf("🧩 Job %s cancelled: %s", jobID, reason)
+	return GetJob(db, siteID, jobID)
+}
+
+// NextJobTask 로봇이 다음에 실행할 작업 항목 (취소되지 않은 작업 묶음의 PENDING 항목 중 가장 먼저 만든 것, 없으면 nil)
+func NextJobTask(db *gorm.DB, siteID, serialNumber string) (*models.JobTask, *models.Job, error) {
+	var task models.JobTask
+	err := db.Scopes(SiteScope(siteID)).
+		Where("serial_number = ? AND status = ?", serialNumber, constants.JobStatusPending).
+		Where("job_ref IN (?)", db.Model(&models.Job{}).Select("id").
+			Where("status IN ?", []string{constants.JobStatusPending, constants.JobStatusRunning})).
+		Order("job_ref ASC, sequence ASC").
+		First(&task).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	var job models.Job
+	if err := db.First(&job, task.JobRef).Error; err != nil {
+		return nil, nil, err
+	}
+	return &task, &job, nil
+}
+
+// ClaimJobTask PENDING 작업 항목을 RUNNING으로 전환 (다른 브릿지가 먼저 가져갔으면 false)
+// 작업 묶음이 PENDING이면 함께 RUNNING으로 바꿉니다.
+func ClaimJobTask(db *gorm.DB, task *models.JobTask) (bool, error) {
+	claimed := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(task).Where("status = ?", constants.JobStatusPending).
+			Updates(map[string]interface{}{"status": constants.JobStatusRunning, "started_at": now})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		claimed = true
+		return tx.Model(&models.Job{}).Where("id = ? AND status = ?", task.JobRef, constants.JobStatusPending).
+			Updates(map[string]interface{}{"status": constants.JobStatusRunning, "started_at": now}).Error
+	})
+	return claimed, err
+}
+
+// ReleaseJobTask 시작하지 못한 작업 항목을 다시 PENDING으로 되돌림 (로봇이 바빠진 경우)
+func ReleaseJobTask(db *gorm.DB, task *models.JobTask) error {
+	return db.Model(task).Where("status = ?", constants.JobStatusRunning).
+		Updates(map[string]interface{}{"status": constants.JobStatusPending, "started_at": nil}).Error
+}
+
+// RunningJobTasks 로봇에서 실행 중인 작업 항목과 작업 묶음 상태 (취소 여부 확인용)
+func RunningJobTasks(db *gorm.DB, siteID, serialNumber string) ([]models.JobTask, map[uint]string, error) {
+	var tasks []models.JobTask
+	err := db.Scopes(SiteScope(siteID)).
+		Where("serial_number = ? AND status = ?", serialNumber, constants.JobStatusRunning).
+		Order("id ASC").
+		Find(&tasks).Error
+	if err != nil || len(tasks) == 0 {
+		return nil, nil, err
+	}
+	refs := make([]uint, 0, len(tasks))
+	for _, task := range tasks {
+		refs = append(refs, task.JobRef)
+	}
+	var jobs []models.Job
+	if err := db.Select("id", "status").Where("id IN ?", refs).Find(&jobs).Error; err != nil {
+		return nil, nil, err
+	}
+	statuses := make(map[uint]string, len(jobs))
+	for _, job := range jobs {
+		statuses[job.ID] = job.Status
+	}
+	return tasks, statuses, nil
+}
+
+// FinishJobTask 작업 항목을 끝난 상태로 기록하고 작업 묶음의 모아진 상태를 갱신
+// 모든 작업 항목이 끝나면 작업 묶음은 전부 성공이면 COMPLETED, 아니면 FAILED가 됩니다 (취소된 묶음은 그대로).
+func FinishJobTask(db *gorm.DB, task *models.JobTask, status, errMsg string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Model(task).Updates(map[string]interface{}{
+			"status": status, "error_message": truncateText(errMsg, 500), "finished_at": now,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		var statuses []string
+		if err := tx.Model(&models.JobTask{}).Where("job_ref = ?", task.JobRef).Pluck("status", &statuses).Error; err != nil {
+			return err
+		}
+		final := constants.JobStatusCompleted
+		for _, s := range statuses {
+			switch s {
+			case constants.JobStatusPending, constants.JobStatusRunning:
+				return nil
+			case constants.JobStatusFailed, constants.JobStatusCancelled:
+				final = constants.JobStatusFailed
+			}
+		}
+		result := tx.Model(&models.Job{}).Where("id = ? AND status IN ?", task.JobRef,
+			[]string{constants.JobStatusPending, constants.JobStatusRunning}).
+			Updates(map[string]interface{}{"status": final, "finished_at": now})
+		if result.Error == nil && result.RowsAffected > 0 {
+			utils.Logger.Infof("🧩 Job #%d finished: %s", task.JobRef, final)
+		}
+		return result.Error
+	})
+}
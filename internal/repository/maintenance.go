@@ -0,0 +1,97 @@
+// internal/repository/maintenance.go
+package repository
+
+import (
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RobotMaintenance 로봇 유지보수 모드 상태
+type RobotMaintenance struct {
+	SerialNumber string
+	Reason       string
+	Until        *time.Time
+}
+
+// SetRobotMaintenance 로봇을 유지보수 모드로 설정 (until이 nil이면 수동 해제까지 유지)
+func SetRobotMaintenance(db *gorm.DB, siteID, serialNumber, reason string, until *time.Time) error {
+	if until != nil && !until.After(time.Now()) {
+		return apperr.Validation("until", "maintenance expiry %s is in the past", until.Format(time.RFC3339))
+	}
+	result := db.Model(&models.RobotStatus{}).
+		Scopes(SiteScope(siteID)).
+		Where("serial_number = ?", serialNumber).
+		Updates(map[string]interface{}{
+			"maintenance":        true,
+			"maintenance_reason": reason,
+			"maintenance_until":  until,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperr.New(apperr.CodeNotFound, "robot %s not found", serialNumber).WithField("serialNumber")
+	}
+	utils.Logger.Warnf("🔧 Robot %s in maintenance: %s", serialNumber, reason)
+	return nil
+}
+
+// ClearRobotMaintenance 로봇의 유지보수 모드 해제
+func ClearRobotMaintenance(db *gorm.DB, siteID, serialNumber string) error {
+	result := db.Model(&models.RobotStatus{}).
+		Scopes(SiteScope(siteID)).
+		Where("serial_number = ?", serialNumber).
+		Updates(map[string]interface{}{
+			"maintenance":        false,
+			"maintenance_reason": "",
+			"maintenance_until":  nil,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperr.New(apperr.CodeNotFound, "robot %s not found", serialNumber).WithField("serialNumber")
+	}
+	utils.Logger.Infof("🔧 Robot %s maintenance cleared", serialNumber)
+	return nil
+}
+
+// ActiveMaintenance 로봇이 유지보수 모드이면 상태를 반환 (아니면 nil)
+// 자동 해제 시각이 지난 유지보수는 해제 처리하고 nil을 반환합니다.
+func ActiveMaintenance(db *gorm.DB, siteID, serialNumber string) (*RobotMaintenance, error) {
+	var status models.RobotStatus
+	err := db.Scopes(SiteScope(siteID)).Where("serial_number = ?", serialNumber).First(&status).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !status.Maintenance {
+		return nil, nil
+	}
+	if status.MaintenanceUntil != nil && time.Now().After(*status.MaintenanceUntil) {
+		if err := ClearRobotMaintenance(db, siteID, serialNumber); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+	return &RobotMaintenance{
+		SerialNumber: status.SerialNumber,
+		Reason:       status.MaintenanceReason,
+		Until:        status.MaintenanceUntil,
+	}, nil
+}
+
+// MaintenanceError 유지보수 중인 로봇에 대한 배차 거부 오류
+func MaintenanceError(m *RobotMaintenance) *apperr.Error {
+	if m.Until != nil {
+		return apperr.New(apperr.CodeRobotMaintenance, "Robot %s is in maintenance until %s: %s",
+			m.SerialNumber, m.Until.Format(time.RFC3339), m.Reason)
+	}
+	return apperr.New(apperr.CodeRobotMaintenance, "Robot %s is in maintenance: %s", m.SerialNumber, m.Reason)
+}
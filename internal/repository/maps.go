@@ -0,0 +1,113 @@
+// internal/repository/maps.go
+package repository
+
+import (
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// ListRobotMaps 사이트의 맵 목록을 구역과 함께 조회
+func ListRobotMaps(db *gorm.DB, siteID string) ([]models.RobotMap, error) {
+	var maps []models.RobotMap
+	err := db.Scopes(SiteScope(siteID)).Preload("Zones").Order("map_id ASC").Find(&maps).Error
+	return maps, err
+}
+
+// FindRobotMap 맵 ID로 맵을 구역과 함께 조회 (없으면 nil 반환)
+func FindRobotMap(db *gorm.DB, siteID, mapID string) (*models.RobotMap, error) {
+	var robotMap models.RobotMap
+	err := db.Scopes(SiteScope(siteID)).Preload("Zones").Where("map_id = ?", mapID).First(&robotMap).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &robotMap, nil
+}
+
+// SaveRobotMap 맵 메타데이터와 구역을 저장합니다.
+// 같은 맵 ID가 이미 있으면 경계와 설명을 갱신하고 구역을 전달된 목록으로 교체합니다.
+func SaveRobotMap(db *gorm.DB, siteID string, robotMap *models.RobotMap) error {
+	if robotMap.MapID == "" {
+		return apperr.Validation("map_id", "map id is required")
+	}
+	for _, zone := range robotMap.Zones {
+		if zone.ZoneType != constants.ZoneTypeAllowed && zone.ZoneType != constants.ZoneTypeRestricted {
+			return apperr.Validation("zones", "zone %q has invalid type %q", zone.Name, zone.ZoneType)
+		}
+		if zone.MaxX < zone.MinX || zone.MaxY < zone.MinY {
+			return apperr.Validation("zones", "zone %q has invalid bounds", zone.Name)
+		}
+	}
+
+	zones := robotMap.Zones
+	robotMap.SiteID = siteID
+	robotMap.Zones = nil
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var existing models.RobotMap
+		result := tx.Scopes(SiteScope(siteID)).Where("map_id = ?", robotMap.MapID).First(&existing)
+		if result.Error == nil {
+			robotMap.ID = existing.ID
+			robotMap.CreatedAt = existing.CreatedAt
+			if err := tx.Save(robotMap).Error; err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Where("robot_map_id = ?", robotMap.ID).Delete(&models.MapZone{}).Error; err != nil {
+				return err
+			}
+		} else if result.Error == gorm.ErrRecordNotFound {
+			if err := tx.Create(robotMap).Error; err != nil {
+				return err
+			}
+		} else {
+			return result.Error
+		}
+
+		for i := range zones {
+			zones[i].ID = 0
+			zones[i].RobotMapID = robotMap.ID
+			if err := tx.Create(&zones[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	robotMap.Zones = zones
+	if err != nil {
+		return fmt.Errorf("failed to save map %s: %w", robotMap.MapID, err)
+	}
+
+	utils.Logger.Infof("Map %s saved with %d zones", robotMap.MapID, len(zones))
+	return nil
+}
+
+// DeleteRobotMap 맵과 구역을 삭제합니다.
+func DeleteRobotMap(db *gorm.DB, siteID, mapID string) error {
+	robotMap, err := FindRobotMap(db, siteID, mapID)
+	if err != nil {
+		return err
+	}
+	if robotMap == nil {
+		return apperr.New(apperr.CodeNotFound, "map %s not found", mapID).WithField("mapId")
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("robot_map_id = ?", robotMap.ID).Delete(&models.MapZone{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(robotMap).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete map %s: %w", mapID, err)
+	}
+
+	utils.Logger.Infof("Map %s deleted", mapID)
+	return nil
+}
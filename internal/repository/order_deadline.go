@@ -0,0 +1,75 @@
+// internal/repository/order_deadline.go
+package repository
+
+import (
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SetTemplateDeadline 템플릿 오더 실행 제한 시간 설정 (0이면 ORDER_DEADLINE_SECONDS 기본값 사용)
+// 이미 실행 중인 오더의 제한 시각은 바뀌지 않습니다.
+func SetTemplateDeadline(db *gorm.DB, siteID string, templateID uint, deadline time.Duration) error {
+	if deadline < 0 {
+		return apperr.Validation("deadlineSeconds", "deadline must be 0 (site default) or positive, got %s", deadline)
+	}
+	var template models.OrderTemplate
+	if err := db.Scopes(SiteScope(siteID)).First(&template, templateID).Error; err != nil {
+		return apperr.Wrap(apperr.CodeTemplateNotFound, err, "order template %d not found", templateID)
+	}
+	if err := db.Model(&template).Update("deadline_seconds", int(deadline/time.Second)).Error; err != nil {
+		return err
+	}
+	utils.Logger.Infof("Order template %d deadline set to %s", templateID, deadline)
+	return nil
+}
+
+// OverdueOrderExecutions 제한 시각이 지났는데 아직 RUNNING인 로봇의 오더 실행 (제한 시각 순)
+// PAUSED 오더는 제외합니다 (재개할 때 제한 시각을 일시정지한 시간만큼 늦춤).
+func OverdueOrderExecutions(db *gorm.DB, siteID, serialNumber string, now time.Time) ([]models.OrderExecution, error) {
+	var executions []models.OrderExecution
+	err := db.Scopes(SiteScope(siteID)).
+		Where("serial_number = ? AND status = ? AND deadline_at IS NOT NULL AND deadline_at <= ?",
+			serialNumber, constants.OrderExecutionStatusRunning, now).
+		Order("deadline_at").
+		Find(&executions).Error
+	return executions, err
+}
+
+// TimedOutStepExecutions 제한 시각(TimeoutAt)이 지났는데 아직 RUNNING인 로봇 오더의 RUNNING 단계 (제한 시각 순, Execution 포함)
+// PAUSED 오더의 단계는 제외합니다 (재개할 때 단계 제한 시각도 일시정지한 시간만큼 늦춤).
+func TimedOutStepExecutions(db *gorm.DB, siteID, serialNumber string, now time.Time) ([]models.StepExecution, error) {
+	runningOrders := db.Model(&models.OrderExecution{}).Scopes(SiteScope(siteID)).Select("id").
+		Where("serial_number = ? AND status = ?", serialNumber, constants.OrderExecutionStatusRunning)
+	var steps []models.StepExecution
+	err := db.Preload("Execution").
+		Where("status = ? AND timeout_at IS NOT NULL AND timeout_at <= ? AND execution_id IN (?)",
+			constants.StepExecutionStatusRunning, now, runningOrders).
+		Order("timeout_at").
+		Find(&steps).Error
+	return steps, err
+}
+
+// ExpireOrderExecution 아직 RUNNING인 오더 실행을 EXPIRED로 종료 (그 사이 다른 상태가 되었으면 false)
+func ExpireOrderExecution(db *gorm.DB, execution *models.OrderExecution, detail string, now time.Time) (bool, error) {
+	result := db.Model(&models.OrderExecution{}).
+		Where("id = ? AND status = ?", execution.ID, constants.OrderExecutionStatusRunning).
+		Updates(map[string]interface{}{
+			"status":        constants.OrderExecutionStatusExpired,
+			"completed_at":  now,
+			"error_message": detail,
+		})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	execution.Status = constants.OrderExecutionStatusExpired
+	execution.CompletedAt = &now
+	execution.ErrorMessage = detail
+	utils.Logger.Infof("OrderExecution for order %s status updated to %s", execution.OrderID, execution.Status)
+	notifyOrderStatus(execution)
+	return true, nil
+}
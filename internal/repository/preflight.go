@@ -0,0 +1,115 @@
+// internal/repository/preflight.go
+package repository
+
+import (
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/utils"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PreflightChecks 오더 전송 전 사전 점검 이름 목록
+var PreflightChecks = []string{
+	constants.PreflightCheckNoErrors,
+	constants.PreflightCheckPositionInitialized,
+	constants.PreflightCheckAutomaticMode,
+	constants.PreflightCheckEStopReleased,
+}
+
+// IsPreflightCheck 알려진 사전 점검인지 확인
+func IsPreflightCheck(check string) bool {
+	for _, known := range PreflightChecks {
+		if check == known {
+			return true
+		}
+	}
+	return false
+}
+
+// ParsePreflightChecks 쉼표 구분 점검 이름 목록 파싱 (알 수 없는 이름이면 오류, 중복 제거)
+func ParsePreflightChecks(spec string) ([]string, error) {
+	var checks []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		if !IsPreflightCheck(name) {
+			return nil, apperr.Validation("checks", "unknown preflight check %q (%s)", name, strings.Join(PreflightChecks, ", "))
+		}
+		seen[name] = true
+		checks = append(checks, name)
+	}
+	return checks, nil
+}
+
+// GetPreflightOverride 로봇별로 끈 사전 점검 (설정이 없으면 nil)
+func GetPreflightOverride(db *gorm.DB, siteID, serialNumber string) (*models.PreflightOverride, error) {
+	var override models.PreflightOverride
+	err := db.Scopes(SiteScope(siteID)).Where("serial_number = ?", serialNumber).First(&override).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &override, nil
+}
+
+// DisabledPreflightChecks 로봇에서 끈 사전 점검 이름 (이름 → true)
+func DisabledPreflightChecks(db *gorm.DB, siteID, serialNumber string) (map[string]bool, error) {
+	override, err := GetPreflightOverride(db, siteID, serialNumber)
+	if err != nil || override == nil {
+		return nil, err
+	}
+	disabled := make(map[string]bool)
+	for _, check := range strings.Split(override.DisabledChecks, ",") {
+		if check != "" {
+			disabled[check] = true
+		}
+	}
+	return disabled, nil
+}
+
+// SetDisabledPreflightChecks 로봇에서 끌 사전 점검 설정 (기존 설정 교체, 비어 있으면 모든 점검 다시 켬)
+func SetDisabledPreflightChecks(db *gorm.DB, siteID, serialNumber string, checks []string, reason string) (*models.PreflightOverride, error) {
+	if serialNumber == "" {
+		return nil, apperr.Validation("serialNumber", "serial number is required")
+	}
+	parsed, err := ParsePreflightChecks(strings.Join(checks, ","))
+	if err != nil {
+		return nil, err
+	}
+	if len(parsed) == 0 {
+		err := db.Scopes(SiteScope(siteID)).Where("serial_number = ?", serialNumber).Delete(&models.PreflightOverride{}).Error
+		if err != nil {
+			return nil, err
+		}
+		utils.Logger.Infof("✈️ All preflight checks enabled for robot %s", serialNumber)
+		return nil, nil
+	}
+	if len(reason) > 255 {
+		return nil, apperr.Validation("reason", "reason must be at most 255 characters")
+	}
+
+	override := &models.PreflightOverride{
+		SiteID:         siteID,
+		SerialNumber:   serialNumber,
+		DisabledChecks: strings.Join(parsed, ","),
+		Reason:         reason,
+	}
+	err = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "site_id"}, {Name: "serial_number"}},
+		DoUpdates: clause.AssignmentColumns([]string{"disabled_checks", "reason", "updated_at"}),
+	}).Create(override).Error
+	if err != nil {
+		return nil, err
+	}
+	utils.Logger.Warnf("✈️ Preflight checks disabled for robot %s: %s (%s)", serialNumber, override.DisabledChecks, reason)
+	return GetPreflightOverride(db, siteID, serialNumber)
+}
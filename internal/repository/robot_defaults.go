@@ -0,0 +1,85 @@
+// internal/repository/robot_defaults.go
+package repository
+
+import (
+	"fmt"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// ListRobotDefaults 사이트의 로봇별 기본 위치 목록
+func ListRobotDefaults(db *gorm.DB, siteID string) ([]models.RobotDefaults, error) {
+	var defaults []models.RobotDefaults
+	err := db.Scopes(SiteScope(siteID)).Order("serial_number ASC").Find(&defaults).Error
+	return defaults, err
+}
+
+// FindRobotDefaults 로봇의 기본 위치 조회 (없으면 nil 반환)
+func FindRobotDefaults(db *gorm.DB, siteID, serialNumber string) (*models.RobotDefaults, error) {
+	var defaults models.RobotDefaults
+	err := db.Scopes(SiteScope(siteID)).Where("serial_number = ?", serialNumber).First(&defaults).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &defaults, nil
+}
+
+// SaveRobotDefaults 로봇의 기본 위치를 저장합니다 (이미 있으면 갱신).
+// 기본 맵이 등록된 맵이고 경계가 있으면 홈 위치가 경계 안에 있어야 합니다.
+func SaveRobotDefaults(db *gorm.DB, siteID string, defaults *models.RobotDefaults) error {
+	if defaults.SerialNumber == "" {
+		return fmt.Errorf("serial number is required")
+	}
+	if defaults.AllowedDeviationXY < 0 || defaults.AllowedDeviationTheta < 0 {
+		return fmt.Errorf("allowed deviations must not be negative")
+	}
+	if defaults.MapID != "" {
+		robotMap, err := FindRobotMap(db, siteID, defaults.MapID)
+		if err != nil {
+			return err
+		}
+		if robotMap != nil && robotMap.HasBounds() &&
+			(defaults.X < robotMap.MinX || defaults.X > robotMap.MaxX || defaults.Y < robotMap.MinY || defaults.Y > robotMap.MaxY) {
+			return fmt.Errorf("home position (%.2f, %.2f) is outside map %s", defaults.X, defaults.Y, defaults.MapID)
+		}
+	}
+
+	defaults.SiteID = siteID
+	var existing models.RobotDefaults
+	result := db.Scopes(SiteScope(siteID)).Where("serial_number = ?", defaults.SerialNumber).First(&existing)
+	switch {
+	case result.Error == nil:
+		defaults.ID = existing.ID
+		defaults.CreatedAt = existing.CreatedAt
+		if err := db.Save(defaults).Error; err != nil {
+			return fmt.Errorf("failed to save defaults for robot %s: %w", defaults.SerialNumber, err)
+		}
+	case result.Error == gorm.ErrRecordNotFound:
+		if err := db.Create(defaults).Error; err != nil {
+			return fmt.Errorf("failed to save defaults for robot %s: %w", defaults.SerialNumber, err)
+		}
+	default:
+		return result.Error
+	}
+
+	utils.Logger.Infof("Defaults for robot %s saved (map %q)", defaults.SerialNumber, defaults.MapID)
+	return nil
+}
+
+// DeleteRobotDefaults 로봇의 기본 위치 삭제 (다시 저장할 수 있도록 행을 완전히 삭제)
+func DeleteRobotDefaults(db *gorm.DB, siteID, serialNumber string) error {
+	result := db.Unscoped().Scopes(SiteScope(siteID)).Where("serial_number = ?", serialNumber).Delete(&models.RobotDefaults{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete defaults for robot %s: %w", serialNumber, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no defaults for robot %s", serialNumber)
+	}
+	utils.Logger.Infof("Defaults for robot %s deleted", serialNumber)
+	return nil
+}
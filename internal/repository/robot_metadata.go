@@ -0,0 +1,232 @@
+// internal/repository/robot_metadata.go
+package repository
+
+import (
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/utils"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 메타데이터 제한 (models.RobotMetadata 컬럼 크기와 같음)
+const (
+	maxMetadataKeyLength   = 63
+	maxMetadataValueLength = 255
+)
+
+// metadataKeyPattern 메타데이터 키 형식 (소문자, 숫자, '.', '_', '-'로 이루어지고 영숫자로 시작/끝)
+var metadataKeyPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9._-]*[a-z0-9])?$`)
+
+// GetRobotMetadata 로봇의 메타데이터 (없으면 빈 맵)
+func GetRobotMetadata(db *gorm.DB, siteID, serialNumber string) (map[string]string, error) {
+	var rows []models.RobotMetadata
+	if err := db.Scopes(SiteScope(siteID)).Where("serial_number = ?", serialNumber).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	metadata := make(map[string]string, len(rows))
+	for _, row := range rows {
+		metadata[row.Key] = row.Value
+	}
+	return metadata, nil
+}
+
+// ListRobotMetadata 사이트 로봇들의 메타데이터 (시리얼 번호별, 메타데이터가 없는 로봇은 빠짐)
+func ListRobotMetadata(db *gorm.DB, siteID string) (map[string]map[string]string, error) {
+	var rows []models.RobotMetadata
+	if err := db.Scopes(SiteScope(siteID)).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	bySerial := make(map[string]map[string]string)
+	for _, row := range rows {
+		if bySerial[row.SerialNumber] == nil {
+			bySerial[row.SerialNumber] = make(map[string]string)
+		}
+		bySerial[row.SerialNumber][row.Key] = row.Value
+	}
+	return bySerial, nil
+}
+
+// PatchRobotMetadata 로봇 메타데이터에 JSON merge patch를 적용하고 적용 후 전체 메타데이터 반환
+// 값이 nil인 키는 삭제하고 나머지는 추가/갱신합니다. 상태 기록이나 탐색 등록이 있는 로봇만 대상입니다.
+func PatchRobotMetadata(db *gorm.DB, siteID, serialNumber string, patch map[string]*string) (map[string]string, error) {
+	keys := make([]string, 0, len(patch))
+	for key, value := range patch {
+		if err := validateMetadataKey(key); err != nil {
+			return nil, err
+		}
+		if value != nil && utf8.RuneCountInString(*value) > maxMetadataValueLength {
+			return nil, apperr.Validation(key, "metadata value must be at most %d characters", maxMetadataValueLength)
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if err := requireKnownRobot(db, siteID, serialNumber); err != nil {
+		return nil, err
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, key := range keys {
+			value := patch[key]
+			if value == nil {
+				if err := tx.Scopes(SiteScope(siteID)).Where("serial_number = ? AND key = ?", serialNumber, key).
+					Delete(&models.RobotMetadata{}).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			row := models.RobotMetadata{SiteID: siteID, SerialNumber: serialNumber, Key: key, Value: *value}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "site_id"}, {Name: "serial_number"}, {Name: "key"}},
+				DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+			}).Create(&row).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, apperr.Wrap(apperr.CodeInternal, err, "failed to update metadata of robot %s", serialNumber)
+	}
+	if len(keys) > 0 {
+		utils.Logger.Infof("🏷️ Robot %s metadata updated (%s)", serialNumber, strings.Join(keys, ", "))
+	}
+	return GetRobotMetadata(db, siteID, serialNumber)
+}
+
+// requireKnownRobot 상태 기록이나 탐색 등록이 있는 로봇인지 확인 (오타로 메타데이터가 쌓이지 않도록)
+func requireKnownRobot(db *gorm.DB, siteID, serialNumber string) error {
+	for _, model := range []interface{}{&models.RobotStatus{}, &models.RobotRegistration{}} {
+		var count int64
+		if err := db.Model(model).Scopes(SiteScope(siteID)).Where("serial_number = ?", serialNumber).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+	}
+	return apperr.New(apperr.CodeNotFound, "robot %s not found", serialNumber).WithField("serialNumber")
+}
+
+func validateMetadataKey(key string) error {
+	if len(key) > maxMetadataKeyLength || !metadataKeyPattern.MatchString(key) {
+		return apperr.Validation(key, "invalid metadata key %q (lowercase letters, digits, '.', '_', '-', at most %d characters)",
+			key, maxMetadataKeyLength)
+	}
+	return nil
+}
+
+// metadataRequirement 셀렉터 조건 하나
+type metadataRequirement struct {
+	key   string
+	value string
+	op    string // "=", "!=", "exists", "!exists"
+}
+
+// MetadataSelector 로봇 메타데이터 셀렉터
+// 쉼표로 구분한 조건(key=value, key!=value, key, !key)을 모두 만족하는 로봇이 일치합니다.
+// 빈 셀렉터는 모든 로봇과 일치합니다.
+type MetadataSelector struct {
+	raw          string
+	requirements []metadataRequirement
+}
+
+// ParseMetadataSelector "location=line-1,team!=qa,!retired" 형식의 셀렉터 해석
+func ParseMetadataSelector(selector string) (*MetadataSelector, error) {
+	parsed := &MetadataSelector{raw: strings.TrimSpace(selector)}
+	if parsed.raw == "" {
+		return parsed, nil
+	}
+	for _, term := range strings.Split(parsed.raw, ",") {
+		term = strings.TrimSpace(term)
+		var req metadataRequirement
+		switch {
+		case strings.Contains(term, "!="):
+			parts := strings.SplitN(term, "!=", 2)
+			req = metadataRequirement{key: strings.TrimSpace(parts[0]), value: strings.TrimSpace(parts[1]), op: "!="}
+		case strings.Contains(term, "="):
+			parts := strings.SplitN(term, "=", 2)
+			req = metadataRequirement{key: strings.TrimSpace(parts[0]), value: strings.TrimSpace(parts[1]), op: "="}
+		case strings.HasPrefix(term, "!"):
+			req = metadataRequirement{key: strings.TrimSpace(term[1:]), op: "!exists"}
+		default:
+			req = metadataRequirement{key: term, op: "exists"}
+		}
+		if err := validateMetadataKey(req.key); err != nil {
+			return nil, apperr.Validation("selector", "invalid selector term %q: key must be lowercase letters, digits, '.', '_', '-'", term)
+		}
+		parsed.requirements = append(parsed.requirements, req)
+	}
+	return parsed, nil
+}
+
+// Empty 조건이 없는 셀렉터인지 (모든 로봇과 일치)
+func (s *MetadataSelector) Empty() bool {
+	return s == nil || len(s.requirements) == 0
+}
+
+// String 해석한 원래 셀렉터 문자열
+func (s *MetadataSelector) String() string {
+	if s == nil {
+		return ""
+	}
+	return s.raw
+}
+
+// Matches 메타데이터가 셀렉터의 모든 조건을 만족하는지
+// key!=value는 키가 없어도 만족합니다.
+func (s *MetadataSelector) Matches(metadata map[string]string) bool {
+	if s == nil {
+		return true
+	}
+	for _, req := range s.requirements {
+		value, ok := metadata[req.key]
+		switch req.op {
+		case "=":
+			if !ok || value != req.value {
+				return false
+			}
+		case "!=":
+			if ok && value == req.value {
+				return false
+			}
+		case "exists":
+			if !ok {
+				return false
+			}
+		case "!exists":
+			if ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// SelectRobots 사이트에서 상태 기록이 있는 로봇 중 셀렉터와 일치하는 로봇의 시리얼 번호 (시리얼 번호 순)
+func SelectRobots(db *gorm.DB, siteID string, selector *MetadataSelector) ([]string, error) {
+	var serials []string
+	if err := db.Model(&models.RobotStatus{}).Scopes(SiteScope(siteID)).
+		Order("serial_number ASC").Pluck("serial_number", &serials).Error; err != nil {
+		return nil, err
+	}
+	if selector.Empty() {
+		return serials, nil
+	}
+	metadata, err := ListRobotMetadata(db, siteID)
+	if err != nil {
+		return nil, err
+	}
+	selected := make([]string, 0, len(serials))
+	for _, serial := range serials {
+		if selector.Matches(metadata[serial]) {
+			selected = append(selected, serial)
+		}
+	}
+	return selected, nil
+}
@@ -0,0 +1,116 @@
+// internal/repository/robot_registration.go
+package repository
+
+import (
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/utils"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RegisterDiscoveredRobot 탐색 구독에서 받은 로봇을 등록하거나 마지막 수신 시각을 갱신합니다.
+// 처음 보는 로봇은 승인 대기로 등록하되, 탐색을 켜기 전부터 상태가 기록되어 있던 로봇은 바로 승인합니다.
+// 새로 등록했으면 created가 true입니다.
+func RegisterDiscoveredRobot(db *gorm.DB, siteID, manufacturer, serialNumber, topic string, seenAt time.Time) (*models.RobotRegistration, bool, error) {
+	var registration models.RobotRegistration
+	err := db.Scopes(SiteScope(siteID)).Where("serial_number = ?", serialNumber).First(&registration).Error
+	if err == nil {
+		registration.LastSeenAt = seenAt
+		registration.LastTopic = topic
+		err = db.Model(&registration).Select("last_seen_at", "last_topic").Updates(&registration).Error
+		return &registration, false, err
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, false, err
+	}
+
+	registration = models.RobotRegistration{
+		SiteID:       siteID,
+		SerialNumber: serialNumber,
+		Manufacturer: manufacturer,
+		Status:       constants.RobotRegistrationPending,
+		LastTopic:    topic,
+		FirstSeenAt:  seenAt,
+		LastSeenAt:   seenAt,
+	}
+	var known int64
+	if err := db.Model(&models.RobotStatus{}).Scopes(SiteScope(siteID)).Where("serial_number = ?", serialNumber).Count(&known).Error; err != nil {
+		return nil, false, err
+	}
+	if known > 0 {
+		registration.Status = constants.RobotRegistrationApproved
+		registration.Note = "known before discovery"
+		registration.DecidedAt = &seenAt
+	}
+	if err := db.Create(&registration).Error; err != nil {
+		return nil, false, fmt.Errorf("failed to register robot %s: %w", serialNumber, err)
+	}
+	if registration.Status == constants.RobotRegistrationPending {
+		utils.Logger.Warnf("🆕 New robot %s/%s discovered on %s, waiting for approval", manufacturer, serialNumber, topic)
+	} else {
+		utils.Logger.Infof("🆕 Robot %s/%s registered (already known, approved)", manufacturer, serialNumber)
+	}
+	return &registration, true, nil
+}
+
+// ListRobotRegistrations 사이트의 등록 로봇 목록 (status가 비어 있으면 전체, 최근 발견 순)
+func ListRobotRegistrations(db *gorm.DB, siteID, status string) ([]models.RobotRegistration, error) {
+	query := db.Scopes(SiteScope(siteID))
+	if status != "" {
+		query = query.Where("status = ?", strings.ToUpper(status))
+	}
+	var registrations []models.RobotRegistration
+	err := query.Order("first_seen_at DESC").Find(&registrations).Error
+	return registrations, err
+}
+
+// ApproveRobot 등록된 로봇을 승인하여 오더를 받을 수 있게 함
+func ApproveRobot(db *gorm.DB, siteID, serialNumber, note string) (*models.RobotRegistration, error) {
+	return decideRobotRegistration(db, siteID, serialNumber, constants.RobotRegistrationApproved, note)
+}
+
+// RejectRobot 등록된 로봇을 거부 (승인한 로봇을 다시 막을 때도 사용)
+func RejectRobot(db *gorm.DB, siteID, serialNumber, note string) (*models.RobotRegistration, error) {
+	return decideRobotRegistration(db, siteID, serialNumber, constants.RobotRegistrationRejected, note)
+}
+
+func decideRobotRegistration(db *gorm.DB, siteID, serialNumber, status, note string) (*models.RobotRegistration, error) {
+	var registration models.RobotRegistration
+	err := db.Scopes(SiteScope(siteID)).Where("serial_number = ?", serialNumber).First(&registration).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, apperr.New(apperr.CodeNotFound, "robot %s has not been discovered", serialNumber).WithField("serialNumber")
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	registration.Status = status
+	registration.Note = note
+	registration.DecidedAt = &now
+	if err := db.Model(&registration).Select("status", "note", "decided_at").Updates(&registration).Error; err != nil {
+		return nil, err
+	}
+	utils.Logger.Infof("🆕 Robot %s registration %s", serialNumber, status)
+	return &registration, nil
+}
+
+// CheckRobotApproved 로봇 탐색을 켠 경우 오더 배차 전에 로봇이 승인되었는지 확인
+func CheckRobotApproved(db *gorm.DB, siteID, serialNumber string) error {
+	var registration models.RobotRegistration
+	err := db.Scopes(SiteScope(siteID)).Where("serial_number = ?", serialNumber).First(&registration).Error
+	if err == gorm.ErrRecordNotFound {
+		return apperr.New(apperr.CodeRobotNotApproved, "Robot %s has not been discovered or approved", serialNumber)
+	}
+	if err != nil {
+		return err
+	}
+	if registration.Status != constants.RobotRegistrationApproved {
+		return apperr.New(apperr.CodeRobotNotApproved, "Robot %s registration is %s", serialNumber, registration.Status)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+// internal/repository/robot_tokens.go
+package repository
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/idgen"
+	"mqtt-bridge/internal/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// robotTokenUseInterval last_used_at을 갱신하는 최소 간격 (state를 자주 보내도 매번 쓰지 않도록)
+const robotTokenUseInterval = time.Minute
+
+// IssuedRobotToken 새로 발급한 토큰 (Token 원문은 이때만 알 수 있음)
+type IssuedRobotToken struct {
+	models.RobotToken
+	Token string `json:"token"` // "<token_id>.<secret>" 형식, Authorization: Bearer 헤더로 보냄
+}
+
+// IssueRobotToken 로봇 세션 토큰 발급 (ttl이 0이면 만료 없음)
+func IssueRobotToken(db *gorm.DB, siteID, serialNumber string, ttl time.Duration, label string) (*IssuedRobotToken, error) {
+	serialNumber = strings.TrimSpace(serialNumber)
+	if serialNumber == "" {
+		return nil, apperr.Validation("serial_number", "serial number is required")
+	}
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return nil, apperr.Wrap(apperr.CodeInternal, err, "failed to generate robot token")
+	}
+	secret := hex.EncodeToString(secretBytes)
+
+	token := models.RobotToken{
+		SiteID:       siteID,
+		SerialNumber: serialNumber,
+		TokenID:      idgen.RobotTokenID(),
+		TokenHash:    hashRobotTokenSecret(secret),
+		Label:        strings.TrimSpace(label),
+	}
+	if ttl > 0 {
+		expiresAt := time.Now().Add(ttl)
+		token.ExpiresAt = &expiresAt
+	}
+	if err := db.Create(&token).Error; err != nil {
+		return nil, err
+	}
+	return &IssuedRobotToken{RobotToken: token, Token: token.TokenID + "." + secret}, nil
+}
+
+// RotateRobotToken 새 토큰을 발급하고 기존 활성 토큰은 grace 뒤에 만료되도록 줄임 (grace가 0이면 즉시 폐기)
+// 로봇이 새 토큰으로 바꾸는 동안 기존 토큰도 잠시 통과시키기 위한 것입니다.
+func RotateRobotToken(db *gorm.DB, siteID, serialNumber string, ttl, grace time.Duration, label string) (*IssuedRobotToken, error) {
+	var issued *IssuedRobotToken
+	err := db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		active := tx.Model(&models.RobotToken{}).Scopes(SiteScope(siteID)).
+			Where("serial_number = ? AND revoked_at IS NULL", serialNumber)
+		var err error
+		if grace > 0 {
+			cutoff := now.Add(grace)
+			err = active.Where("(expires_at IS NULL OR expires_at > ?)", cutoff).Update("expires_at", cutoff).Error
+		} else {
+			err = active.Update("revoked_at", now).Error
+		}
+		if err != nil {
+			return err
+		}
+		issued, err = IssueRobotToken(tx, siteID, serialNumber, ttl, label)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return issued, nil
+}
+
+// RevokeRobotToken 토큰 하나 폐기 (다른 로봇의 토큰이거나 없으면 NotFound, 이미 폐기됐으면 그대로 반환)
+func RevokeRobotToken(db *gorm.DB, siteID, serialNumber, tokenID string) (*models.RobotToken, error) {
+	var token models.RobotToken
+	err := db.Scopes(SiteScope(siteID)).Where("serial_number = ? AND token_id = ?", serialNumber, tokenID).First(&token).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, apperr.New(apperr.CodeNotFound, "robot token %s not found for %s", tokenID, serialNumber)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if token.RevokedAt != nil {
+		return &token, nil
+	}
+	now := time.Now()
+	if err := db.Model(&token).Update("revoked_at", now).Error; err != nil {
+		return nil, err
+	}
+	token.RevokedAt = &now
+	return &token, nil
+}
+
+// RevokeRobotTokens 로봇의 폐기되지 않은 토큰 모두 폐기 (폐기한 개수 반환)
+func RevokeRobotTokens(db *gorm.DB, siteID, serialNumber string) (int64, error) {
+	result := db.Model(&models.RobotToken{}).Scopes(SiteScope(siteID)).
+		Where("serial_number = ? AND revoked_at IS NULL", serialNumber).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+// ListRobotTokens 로봇 토큰 목록 (최근 발급 순, includeInactive가 false면 활성 토큰만)
+func ListRobotTokens(db *gorm.DB, siteID, serialNumber string, includeInactive bool) ([]models.RobotToken, error) {
+	query := db.Scopes(SiteScope(siteID)).Where("serial_number = ?", serialNumber)
+	if !includeInactive {
+		query = query.Where("revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", time.Now())
+	}
+	var tokens []models.RobotToken
+	if err := query.Order("created_at DESC, id DESC").Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// VerifyRobotToken 토큰 원문 확인 (형식이 틀리거나 없거나 폐기/만료됐으면 Unauthorized)
+// 성공하면 토큰 주인 로봇이 담긴 토큰을 반환하며, 요청의 로봇과 같은지는 호출하는 쪽에서 확인합니다.
+func VerifyRobotToken(db *gorm.DB, siteID, raw string) (*models.RobotToken, error) {
+	tokenID, secret, ok := strings.Cut(strings.TrimSpace(raw), ".")
+	if !ok || tokenID == "" || secret == "" {
+		return nil, apperr.New(apperr.CodeUnauthorized, "malformed robot token")
+	}
+	var token models.RobotToken
+	err := db.Scopes(SiteScope(siteID)).Where("token_id = ?", tokenID).First(&token).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, apperr.New(apperr.CodeUnauthorized, "unknown robot token")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hashRobotTokenSecret(secret)), []byte(token.TokenHash)) != 1 {
+		return nil, apperr.New(apperr.CodeUnauthorized, "invalid robot token")
+	}
+	now := time.Now()
+	if token.RevokedAt != nil {
+		return nil, apperr.New(apperr.CodeUnauthorized, "robot token %s was revoked", token.TokenID)
+	}
+	if !token.Active(now) {
+		return nil, apperr.New(apperr.CodeUnauthorized, "robot token %s expired", token.TokenID)
+	}
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= robotTokenUseInterval {
+		if err := db.Model(&token).UpdateColumn("last_used_at", now).Error; err == nil {
+			token.LastUsedAt = &now
+		}
+	}
+	return &token, nil
+}
+
+func hashRobotTokenSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,11 @@
+// internal/repository/scope.go
+package repository
+
+import "gorm.io/gorm"
+
+// SiteScope site_id 컬럼으로 조회 범위를 제한하는 GORM 스코프
+func SiteScope(siteID string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("site_id = ?", siteID)
+	}
+}
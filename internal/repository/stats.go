@@ -0,0 +1,230 @@
+// internal/repository/stats.go
+package repository
+
+import (
+	"fmt"
+	"io"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UnattributedFailure 실패한 액션이 기록되지 않은 단계 실패 (타임아웃 등)
+const UnattributedFailure = "unattributed"
+
+// StepFailureCount 액션 타입별 단계 실패 수
+type StepFailureCount struct {
+	ActionType string `json:"action_type"`
+	Count      int    `json:"count"`
+}
+
+// RobotStats 기간 내 로봇 SLA 지표
+type RobotStats struct {
+	SerialNumber       string             `json:"serial_number"`
+	From               time.Time          `json:"from"`
+	To                 time.Time          `json:"to"`
+	OrdersTotal        int                `json:"orders_total"`
+	OrdersSucceeded    int                `json:"orders_succeeded"`
+	OrdersFailed       int                `json:"orders_failed"`
+	OrderSuccessRate   float64            `json:"order_success_rate"` // 종료된 오더 중 성공 비율 (0~1)
+	AvgOrderDurationMs int64              `json:"avg_order_duration_ms"`
+	OnlineSeconds      float64            `json:"online_seconds"`
+	UptimePercent      float64            `json:"uptime_percent"`
+	MTBFSeconds        *float64           `json:"mtbf_seconds,omitempty"` // 온라인 시간 / 실패 오더 수 (실패가 없으면 생략)
+	StepFailures       []StepFailureCount `json:"step_failures"`
+}
+
+// ComputeRobotStats 로봇의 [from, to) 기간 SLA 지표 계산
+// 가동률은 연결 상태 이력(ConnectionStateTransition)에서, 오더 지표는 해당 로봇의 OrderExecution에서 계산합니다.
+func ComputeRobotStats(db *gorm.DB, siteID, serialNumber string, from, to time.Time) (*RobotStats, error) {
+	if now := time.Now(); to.After(now) {
+		to = now
+	}
+	stats := &RobotStats{
+		SerialNumber: serialNumber,
+		From:         from,
+		To:           to,
+		StepFailures: make([]StepFailureCount, 0),
+	}
+
+	if err := computeOrderStats(db, siteID, stats); err != nil {
+		return nil, err
+	}
+	if err := computeUptime(db, siteID, stats); err != nil {
+		return nil, err
+	}
+	if err := computeStepFailures(db, siteID, stats); err != nil {
+		return nil, err
+	}
+
+	if stats.OrdersFailed > 0 {
+		mtbf := stats.OnlineSeconds / float64(stats.OrdersFailed)
+		stats.MTBFSeconds = &mtbf
+	}
+	return stats, nil
+}
+
+// computeOrderStats 오더 성공률과 평균 수행 시간
+func computeOrderStats(db *gorm.DB, siteID string, stats *RobotStats) error {
+	var orders []models.OrderExecution
+	if err := db.Scopes(SiteScope(siteID)).
+		Select("status", "started_at", "completed_at").
+		Where("serial_number = ? AND started_at >= ? AND started_at < ?", stats.SerialNumber, stats.From, stats.To).
+		Find(&orders).Error; err != nil {
+		return err
+	}
+
+	var totalDuration time.Duration
+	var finishedWithTime int
+	for _, order := range orders {
+		stats.OrdersTotal++
+		switch order.Status {
+		case constants.OrderExecutionStatusCompleted:
+			stats.OrdersSucceeded++
+		case constants.OrderExecutionStatusFailed:
+			stats.OrdersFailed++
+		default:
+			continue
+		}
+		if order.CompletedAt != nil {
+			totalDuration += order.CompletedAt.Sub(order.StartedAt)
+			finishedWithTime++
+		}
+	}
+
+	if finished := stats.OrdersSucceeded + stats.OrdersFailed; finished > 0 {
+		stats.OrderSuccessRate = float64(stats.OrdersSucceeded) / float64(finished)
+	}
+	if finishedWithTime > 0 {
+		stats.AvgOrderDurationMs = (totalDuration / time.Duration(finishedWithTime)).Milliseconds()
+	}
+	return nil
+}
+
+// computeUptime 연결 상태 이력으로 ONLINE 시간과 가동률 계산
+// 기간 시작 전 마지막 상태를 초기 상태로 사용하며, 이력이 없으면 오프라인으로 간주합니다.
+func computeUptime(db *gorm.DB, siteID string, stats *RobotStats) error {
+	var initial models.ConnectionStateTransition
+	state := ""
+	err := db.Scopes(SiteScope(siteID)).
+		Where("serial_number = ? AND observed_at < ?", stats.SerialNumber, stats.From).
+		Order("observed_at DESC").
+		Limit(1).
+		Find(&initial).Error
+	if err != nil {
+		return err
+	}
+	if initial.ID != 0 {
+		state = initial.ToState
+	}
+
+	var transitions []models.ConnectionStateTransition
+	if err := db.Scopes(SiteScope(siteID)).
+		Where("serial_number = ? AND observed_at >= ? AND observed_at < ?", stats.SerialNumber, stats.From, stats.To).
+		Order("observed_at ASC").
+		Find(&transitions).Error; err != nil {
+		return err
+	}
+
+	var online time.Duration
+	cursor := stats.From
+	for _, t := range transitions {
+		if state == constants.ConnectionStateOnline {
+			online += t.ObservedAt.Sub(cursor)
+		}
+		state = t.ToState
+		cursor = t.ObservedAt
+	}
+	if state == constants.ConnectionStateOnline {
+		online += stats.To.Sub(cursor)
+	}
+
+	stats.OnlineSeconds = online.Seconds()
+	if window := stats.To.Sub(stats.From); window > 0 {
+		stats.UptimePercent = 100 * online.Seconds() / window.Seconds()
+	}
+	return nil
+}
+
+// computeStepFailures 실패/타임아웃 단계를 실패한 액션 타입별로 집계
+func computeStepFailures(db *gorm.DB, siteID string, stats *RobotStats) error {
+	var stepIDs []uint
+	if err := db.Model(&models.StepExecution{}).
+		Joins("JOIN order_executions ON order_executions.id = step_executions.execution_id").
+		Where("order_executions.site_id = ? AND order_executions.serial_number = ?", siteID, stats.SerialNumber).
+		Where("step_executions.started_at >= ? AND step_executions.started_at < ?", stats.From, stats.To).
+		Where("step_executions.status IN ?", []string{
+			constants.StepExecutionStatusFailed, constants.StepExecutionStatusTimeout, constants.StepExecutionStatusNotAcknowledged,
+		}).
+		Pluck("step_executions.id", &stepIDs).Error; err != nil {
+		return err
+	}
+	if len(stepIDs) == 0 {
+		return nil
+	}
+
+	var transitions []models.ActionStatusTransition
+	if err := db.Select("step_execution_id", "action_type").
+		Where("step_execution_id IN ? AND to_status = ?", stepIDs, constants.ActionStatusFailed).
+		Find(&transitions).Error; err != nil {
+		return err
+	}
+
+	// 단계별로 실패한 액션 타입을 한 번씩만 집계
+	stepActionTypes := make(map[uint]map[string]bool)
+	for _, t := range transitions {
+		if stepActionTypes[t.StepExecutionID] == nil {
+			stepActionTypes[t.StepExecutionID] = make(map[string]bool)
+		}
+		stepActionTypes[t.StepExecutionID][t.ActionType] = true
+	}
+
+	counts := make(map[string]int)
+	for _, stepID := range stepIDs {
+		actionTypes := stepActionTypes[stepID]
+		if len(actionTypes) == 0 {
+			counts[UnattributedFailure]++
+			continue
+		}
+		for actionType := range actionTypes {
+			counts[actionType]++
+		}
+	}
+
+	for actionType, count := range counts {
+		stats.StepFailures = append(stats.StepFailures, StepFailureCount{ActionType: actionType, Count: count})
+	}
+	sort.Slice(stats.StepFailures, func(i, j int) bool {
+		if stats.StepFailures[i].Count != stats.StepFailures[j].Count {
+			return stats.StepFailures[i].Count > stats.StepFailures[j].Count
+		}
+		return stats.StepFailures[i].ActionType < stats.StepFailures[j].ActionType
+	})
+	return nil
+}
+
+// WritePrometheus SLA 지표를 Prometheus 텍스트 형식으로 출력
+func (s *RobotStats) WritePrometheus(w io.Writer, siteID string) {
+	labels := fmt.Sprintf(`site=%q,serial_number=%q`, siteID, s.SerialNumber)
+	gauge := func(name, help string, value float64) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s{%s} %g\n", name, help, name, name, labels, value)
+	}
+
+	gauge("bridge_robot_orders_total", "Orders started in the window", float64(s.OrdersTotal))
+	gauge("bridge_robot_orders_succeeded", "Orders completed successfully in the window", float64(s.OrdersSucceeded))
+	gauge("bridge_robot_orders_failed", "Orders failed in the window", float64(s.OrdersFailed))
+	gauge("bridge_robot_order_success_ratio", "Share of finished orders that succeeded", s.OrderSuccessRate)
+	gauge("bridge_robot_order_duration_avg_seconds", "Average duration of finished orders", float64(s.AvgOrderDurationMs)/1000)
+	gauge("bridge_robot_uptime_percent", "Share of the window the robot was ONLINE", s.UptimePercent)
+	if s.MTBFSeconds != nil {
+		gauge("bridge_robot_mtbf_seconds", "Online time divided by failed orders", *s.MTBFSeconds)
+	}
+
+	fmt.Fprintf(w, "# HELP bridge_robot_step_failures Failed steps by failing action type\n# TYPE bridge_robot_step_failures gauge\n")
+	for _, failure := range s.StepFailures {
+		fmt.Fprintf(w, "bridge_robot_step_failures{%s,action_type=%q} %d\n", labels, failure.ActionType, failure.Count)
+	}
+}
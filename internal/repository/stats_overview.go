@@ -0,0 +1,264 @@
+// internal/repository/stats_overview.go
+package repository
+
+import (
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// unknownTransport 전송 경로가 기록되지 않은 오더 (아직 메시지를 보내지 않았거나 기록 이전 오더)
+const unknownTransport = "unknown"
+
+// RobotOverview 로봇별 현재 실행 현황
+type RobotOverview struct {
+	SerialNumber    string            `json:"serial_number"`
+	ConnectionState string            `json:"connection_state"`
+	Maintenance     bool              `json:"maintenance"`
+	RunningOrders   int               `json:"running_orders"`
+	WaitingOrders   int               `json:"waiting_orders"` // 템플릿 동시 실행 제한으로 대기 중
+	BatteryCharge   *float64          `json:"battery_charge,omitempty"`
+	OperatingMode   string            `json:"operating_mode,omitempty"`
+	Metadata        map[string]string `json:"metadata,omitempty"` // 로봇 메타데이터 (위치, 담당 팀 등)
+}
+
+// QueueDepths DB에 쌓여 있는 대기 작업 수
+type QueueDepths struct {
+	PendingCommands int64 `json:"pending_commands"` // 시작 전 명령 실행
+	WaitingOrders   int64 `json:"waiting_orders"`   // 동시 실행 제한으로 대기 중인 오더
+	PendingOutbox   int64 `json:"pending_outbox"`   // 아직 발행하지 못한 아웃박스 메시지
+	FailedOutbox    int64 `json:"failed_outbox"`    // 재시도를 포기한 아웃박스 메시지
+}
+
+// Throughput 기간 내 명령/오더 처리량과 실패 비율
+type Throughput struct {
+	CommandsStarted   int     `json:"commands_started"`
+	CommandsCompleted int     `json:"commands_completed"`
+	CommandsFailed    int     `json:"commands_failed"` // FAILED, CANCELLED, E_STOPPED
+	CommandsPerMinute float64 `json:"commands_per_minute"`
+	OrdersStarted     int     `json:"orders_started"`
+	OrdersCompleted   int     `json:"orders_completed"`
+	OrdersFailed      int     `json:"orders_failed"`      // FAILED, CANCELLED, E_STOPPED, REJECTED, EXPIRED
+	CommandFailRatio  float64 `json:"command_fail_ratio"` // 종료된 명령 중 실패 비율 (0~1)
+	OrderFailRatio    float64 `json:"order_fail_ratio"`   // 종료된 오더 중 실패 비율 (0~1)
+}
+
+// TransportUsage 전송 경로별 사용 건수
+type TransportUsage struct {
+	Transport string `json:"transport"`
+	Orders    int64  `json:"orders"` // 기간 내 시작한 오더의 마지막 전송 경로
+	Outbox    int64  `json:"outbox"` // 기간 내 발행된 아웃박스 메시지
+}
+
+// StatsOverview 현황판(벽면 대시보드)용 사이트 현황 요약
+type StatsOverview struct {
+	GeneratedAt time.Time        `json:"generated_at"`
+	Window      string           `json:"window"` // 처리량/전송 경로 집계 기간
+	Robots      []RobotOverview  `json:"robots"`
+	Queues      QueueDepths      `json:"queues"`
+	Throughput  Throughput       `json:"throughput"`
+	Transports  []TransportUsage `json:"transports"`
+}
+
+// statusCount 상태별 건수 집계 행
+type statusCount struct {
+	SerialNumber string
+	Status       string
+	Transport    string
+	Count        int64
+}
+
+// ComputeStatsOverview 사이트의 현재 실행 현황과 최근 window 동안의 처리량을 DB에서 집계합니다.
+// 로봇의 배터리/운영 모드 같은 실시간 값은 호출자가 Redis 상태 캐시에서 채웁니다.
+func ComputeStatsOverview(db *gorm.DB, siteID string, window time.Duration, now time.Time) (*StatsOverview, error) {
+	overview := &StatsOverview{
+		GeneratedAt: now,
+		Window:      window.String(),
+		Robots:      make([]RobotOverview, 0),
+		Transports:  make([]TransportUsage, 0),
+	}
+	since := now.Add(-window)
+
+	if err := computeRobotOverview(db, siteID, overview); err != nil {
+		return nil, err
+	}
+	if err := computeQueueDepths(db, siteID, &overview.Queues); err != nil {
+		return nil, err
+	}
+	if err := computeThroughput(db, siteID, since, window, &overview.Throughput); err != nil {
+		return nil, err
+	}
+	if err := computeTransportUsage(db, siteID, since, overview); err != nil {
+		return nil, err
+	}
+	return overview, nil
+}
+
+// computeRobotOverview 로봇별 실행 중/대기 중 오더 수 (상태 기록이 없어도 오더가 있는 로봇 포함)
+func computeRobotOverview(db *gorm.DB, siteID string, overview *StatsOverview) error {
+	var robots []models.RobotStatus
+	if err := db.Scopes(SiteScope(siteID)).Order("serial_number ASC").Find(&robots).Error; err != nil {
+		return err
+	}
+	var counts []statusCount
+	if err := db.Model(&models.OrderExecution{}).Scopes(SiteScope(siteID)).
+		Select("serial_number, status, COUNT(*) AS count").
+		Where("status IN ?", []string{constants.OrderExecutionStatusRunning, constants.OrderExecutionStatusWaiting}).
+		Group("serial_number, status").
+		Scan(&counts).Error; err != nil {
+		return err
+	}
+
+	bySerial := make(map[string]*RobotOverview)
+	for _, r := range robots {
+		bySerial[r.SerialNumber] = &RobotOverview{
+			SerialNumber:    r.SerialNumber,
+			ConnectionState: r.ConnectionState,
+			Maintenance:     r.Maintenance,
+		}
+	}
+	for _, c := range counts {
+		robot := bySerial[c.SerialNumber]
+		if robot == nil {
+			robot = &RobotOverview{SerialNumber: c.SerialNumber}
+			bySerial[c.SerialNumber] = robot
+		}
+		if c.Status == constants.OrderExecutionStatusWaiting {
+			robot.WaitingOrders += int(c.Count)
+		} else {
+			robot.RunningOrders += int(c.Count)
+		}
+	}
+	metadata, err := ListRobotMetadata(db, siteID)
+	if err != nil {
+		return err
+	}
+	for serial, robot := range bySerial {
+		robot.Metadata = metadata[serial]
+		overview.Robots = append(overview.Robots, *robot)
+	}
+	sort.Slice(overview.Robots, func(i, j int) bool {
+		return overview.Robots[i].SerialNumber < overview.Robots[j].SerialNumber
+	})
+	return nil
+}
+
+// computeQueueDepths 대기 중인 명령/오더/아웃박스 메시지 수 (아웃박스는 사이트 구분이 없어 브릿지 전체 기준)
+func computeQueueDepths(db *gorm.DB, siteID string, queues *QueueDepths) error {
+	if err := db.Model(&models.CommandExecution{}).Scopes(SiteScope(siteID)).
+		Where("status = ?", constants.CommandExecutionStatusPending).
+		Count(&queues.PendingCommands).Error; err != nil {
+		return err
+	}
+	if err := db.Model(&models.OrderExecution{}).Scopes(SiteScope(siteID)).
+		Where("status = ?", constants.OrderExecutionStatusWaiting).
+		Count(&queues.WaitingOrders).Error; err != nil {
+		return err
+	}
+	if err := db.Model(&models.OutboxMessage{}).
+		Where("status = ?", constants.OutboxStatusPending).
+		Count(&queues.PendingOutbox).Error; err != nil {
+		return err
+	}
+	return db.Model(&models.OutboxMessage{}).
+		Where("status = ?", constants.OutboxStatusFailed).
+		Count(&queues.FailedOutbox).Error
+}
+
+// computeThroughput since 이후 시작한 명령/오더의 처리량과 실패 비율
+func computeThroughput(db *gorm.DB, siteID string, since time.Time, window time.Duration, throughput *Throughput) error {
+	var commands []statusCount
+	if err := db.Model(&models.CommandExecution{}).Scopes(SiteScope(siteID)).
+		Select("status, COUNT(*) AS count").
+		Where("started_at >= ?", since).
+		Group("status").
+		Scan(&commands).Error; err != nil {
+		return err
+	}
+	for _, c := range commands {
+		throughput.CommandsStarted += int(c.Count)
+		switch c.Status {
+		case constants.CommandExecutionStatusCompleted:
+			throughput.CommandsCompleted += int(c.Count)
+		case constants.CommandExecutionStatusFailed, constants.CommandExecutionStatusCancelled, constants.CommandExecutionStatusEStopped:
+			throughput.CommandsFailed += int(c.Count)
+		}
+	}
+
+	var orders []statusCount
+	if err := db.Model(&models.OrderExecution{}).Scopes(SiteScope(siteID)).
+		Select("status, COUNT(*) AS count").
+		Where("started_at >= ?", since).
+		Group("status").
+		Scan(&orders).Error; err != nil {
+		return err
+	}
+	for _, o := range orders {
+		throughput.OrdersStarted += int(o.Count)
+		switch o.Status {
+		case constants.OrderExecutionStatusCompleted:
+			throughput.OrdersCompleted += int(o.Count)
+		case constants.OrderExecutionStatusFailed, constants.OrderExecutionStatusCancelled, constants.OrderExecutionStatusEStopped,
+			constants.OrderExecutionStatusRejected, constants.OrderExecutionStatusExpired:
+			throughput.OrdersFailed += int(o.Count)
+		}
+	}
+
+	if minutes := window.Minutes(); minutes > 0 {
+		throughput.CommandsPerMinute = float64(throughput.CommandsStarted) / minutes
+	}
+	if finished := throughput.CommandsCompleted + throughput.CommandsFailed; finished > 0 {
+		throughput.CommandFailRatio = float64(throughput.CommandsFailed) / float64(finished)
+	}
+	if finished := throughput.OrdersCompleted + throughput.OrdersFailed; finished > 0 {
+		throughput.OrderFailRatio = float64(throughput.OrdersFailed) / float64(finished)
+	}
+	return nil
+}
+
+// computeTransportUsage since 이후 오더와 아웃박스 발행의 전송 경로별 건수
+func computeTransportUsage(db *gorm.DB, siteID string, since time.Time, overview *StatsOverview) error {
+	var orders []statusCount
+	if err := db.Model(&models.OrderExecution{}).Scopes(SiteScope(siteID)).
+		Select("transport, COUNT(*) AS count").
+		Where("started_at >= ?", since).
+		Group("transport").
+		Scan(&orders).Error; err != nil {
+		return err
+	}
+	var outbox []statusCount
+	if err := db.Model(&models.OutboxMessage{}).
+		Select("transport, COUNT(*) AS count").
+		Where("status = ? AND sent_at >= ?", constants.OutboxStatusSent, since).
+		Group("transport").
+		Scan(&outbox).Error; err != nil {
+		return err
+	}
+
+	byTransport := make(map[string]*TransportUsage)
+	usage := func(transport string) *TransportUsage {
+		if transport == "" {
+			transport = unknownTransport
+		}
+		if byTransport[transport] == nil {
+			byTransport[transport] = &TransportUsage{Transport: transport}
+		}
+		return byTransport[transport]
+	}
+	for _, o := range orders {
+		usage(o.Transport).Orders += o.Count
+	}
+	for _, o := range outbox {
+		usage(o.Transport).Outbox += o.Count
+	}
+	for _, u := range byTransport {
+		overview.Transports = append(overview.Transports, *u)
+	}
+	sort.Slice(overview.Transports, func(i, j int) bool {
+		return overview.Transports[i].Transport < overview.Transports[j].Transport
+	})
+	return nil
+}
@@ -0,0 +1,158 @@
+// internal/repository/template.go
+package repository
+
+import (
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ListOrderTemplates 사이트의 오더 템플릿 목록 조회 (includeDeleted가 true면 soft delete된 템플릿 포함)
+func ListOrderTemplates(db *gorm.DB, siteID string, includeDeleted bool) ([]models.OrderTemplate, error) {
+	query := db.Scopes(SiteScope(siteID))
+	if includeDeleted {
+		query = query.Unscoped()
+	}
+
+	var templates []models.OrderTemplate
+	err := query.Order("id ASC").Find(&templates).Error
+	return templates, err
+}
+
+// ArchiveOrderTemplate 오더 템플릿과 그 스텝, 엣지를 같은 시각으로 soft delete하여 보관 처리합니다.
+// 이미 삭제되어 있던 스텝과 엣지는 건드리지 않으므로 RestoreOrderTemplate은 보관으로 삭제된 행만 복구합니다.
+func ArchiveOrderTemplate(db *gorm.DB, siteID string, templateID uint) error {
+	// Postgres timestamp 정밀도(마이크로초)에 맞춰 복구 시 같은 값으로 비교되도록 함
+	now := time.Now().Truncate(time.Microsecond)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.OrderTemplate{}).Scopes(SiteScope(siteID)).
+			Where("id = ?", templateID).
+			Update("deleted_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperr.New(apperr.CodeTemplateNotFound, "order template %d not found", templateID).WithField("templateId")
+		}
+
+		var stepIDs []uint
+		if err := tx.Model(&models.OrderStep{}).Where("template_id = ?", templateID).Pluck("id", &stepIDs).Error; err != nil {
+			return err
+		}
+		if len(stepIDs) == 0 {
+			return nil
+		}
+		if err := tx.Model(&models.EdgeTemplate{}).Where("order_step_id IN ?", stepIDs).Update("deleted_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.OrderStep{}).Where("id IN ?", stepIDs).Update("deleted_at", now).Error
+	})
+	if err != nil {
+		return err
+	}
+	utils.Logger.Infof("Order template %d archived", templateID)
+	return nil
+}
+
+// RestoreOrderTemplate 보관(soft delete)된 오더 템플릿과 보관 시 함께 삭제된 스텝, 엣지를 복구합니다.
+// 보관 전에 따로 삭제된 스텝과 엣지는 deleted_at이 달라 복구하지 않습니다.
+func RestoreOrderTemplate(db *gorm.DB, siteID string, templateID uint) error {
+	var template models.OrderTemplate
+	err := db.Unscoped().Scopes(SiteScope(siteID)).First(&template, templateID).Error
+	if err == gorm.ErrRecordNotFound {
+		return apperr.New(apperr.CodeTemplateNotFound, "order template %d not found", templateID).WithField("templateId")
+	}
+	if err != nil {
+		return err
+	}
+	if !template.DeletedAt.Valid {
+		return apperr.Validation("templateId", "order template %d is not archived", templateID)
+	}
+	archivedAt := template.DeletedAt.Time
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.OrderTemplate{}).
+			Where("id = ?", templateID).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+
+		var stepIDs []uint
+		if err := tx.Unscoped().Model(&models.OrderStep{}).
+			Where("template_id = ? AND deleted_at = ?", templateID, archivedAt).
+			Pluck("id", &stepIDs).Error; err != nil {
+			return err
+		}
+		if len(stepIDs) == 0 {
+			return nil
+		}
+
+		if err := tx.Unscoped().Model(&models.EdgeTemplate{}).
+			Where("order_step_id IN ? AND deleted_at = ?", stepIDs, archivedAt).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&models.OrderStep{}).
+			Where("id IN ?", stepIDs).
+			Update("deleted_at", nil).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to restore order template %d: %w", templateID, err)
+	}
+
+	utils.Logger.Infof("Order template %d restored", templateID)
+	return nil
+}
+
+// PurgeOrderTemplate 보관(soft delete)된 오더 템플릿을 영구 삭제합니다.
+// 스텝, 엣지, 스텝-액션 매핑, 명령 매핑까지 함께 삭제하며 재사용 가능한 ActionTemplate은 유지합니다.
+func PurgeOrderTemplate(db *gorm.DB, siteID string, templateID uint) error {
+	var template models.OrderTemplate
+	err := db.Unscoped().Scopes(SiteScope(siteID)).First(&template, templateID).Error
+	if err == gorm.ErrRecordNotFound {
+		return apperr.New(apperr.CodeTemplateNotFound, "order template %d not found", templateID).WithField("templateId")
+	}
+	if err != nil {
+		return err
+	}
+	if !template.DeletedAt.Valid {
+		return apperr.Validation("templateId", "order template %d must be archived before purge", templateID)
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var stepIDs []uint
+		if err := tx.Unscoped().Model(&models.OrderStep{}).
+			Where("template_id = ?", templateID).
+			Pluck("id", &stepIDs).Error; err != nil {
+			return err
+		}
+
+		if len(stepIDs) > 0 {
+			if err := tx.Where("order_step_id IN ?", stepIDs).Delete(&models.StepActionMapping{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Where("order_step_id IN ?", stepIDs).Delete(&models.EdgeTemplate{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Where("id IN ?", stepIDs).Delete(&models.OrderStep{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Unscoped().Where("template_id = ?", templateID).Delete(&models.CommandOrderMapping{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Delete(&models.OrderTemplate{}, templateID).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to purge order template %d: %w", templateID, err)
+	}
+
+	utils.Logger.Warnf("Order template %d purged permanently", templateID)
+	return nil
+}
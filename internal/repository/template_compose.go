@@ -0,0 +1,163 @@
+// internal/repository/template_compose.go
+package repository
+
+import (
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/models"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// MaxTemplateCompositionDepth 합성 템플릿의 최대 중첩 깊이
+const MaxTemplateCompositionDepth = 5
+
+// CloneOrderTemplate 템플릿을 단계, 노드, 액션, 파라미터, 엣지까지 새 이름으로 복제합니다.
+// 액션 템플릿은 새로 생성되고, 동일한 노드 템플릿과 하위 템플릿 참조는 그대로 공유합니다.
+func CloneOrderTemplate(db *gorm.DB, siteID string, templateID uint, newName string) (*models.OrderTemplate, error) {
+	if strings.TrimSpace(newName) == "" {
+		return nil, apperr.Validation("name", "clone name is required")
+	}
+	detail, err := LoadTemplateDetail(db, siteID, templateID)
+	if err != nil {
+		return nil, apperr.Wrap(apperr.CodeTemplateNotFound, err, "order template %d not found", templateID)
+	}
+
+	export := ToTemplateExport(detail)
+	export.Name = newName
+	clone, err := ImportOrderTemplate(db, siteID, export)
+	if err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
+
+// LoadExpandedTemplate 템플릿을 로드하고 하위 템플릿 참조 단계를 펼쳐 반환
+func LoadExpandedTemplate(db *gorm.DB, siteID string, templateID uint) (*models.OrderTemplate, error) {
+	detail, err := LoadTemplateDetail(db, siteID, templateID)
+	if err != nil {
+		return nil, apperr.Wrap(apperr.CodeTemplateNotFound, err, "order template %d not found", templateID)
+	}
+	return ExpandTemplate(db, siteID, detail)
+}
+
+// ExpandTemplate 합성 템플릿의 하위 템플릿 참조 단계를 실행 시점의 하위 템플릿 단계로 펼칩니다.
+// 펼친 단계는 1부터 다시 번호를 매기며, 참조 단계의 실행 조건은 펼친 첫 단계에 적용됩니다.
+// 하위 템플릿의 병렬 그룹 이름에는 "하위 템플릿 이름/"이 앞에 붙습니다.
+// 하위 템플릿은 각각 그래프 검증을 거치며, 순환 참조나 최대 깊이 초과는 오류입니다.
+// 하위 템플릿 참조가 없으면 전달된 템플릿을 그대로 반환합니다.
+func ExpandTemplate(db *gorm.DB, siteID string, template *models.OrderTemplate) (*models.OrderTemplate, error) {
+	if !isComposite(template) {
+		return template, nil
+	}
+
+	steps, err := expandSteps(db, siteID, template, []uint{template.ID})
+	if err != nil {
+		return nil, err
+	}
+	for i := range steps {
+		steps[i].StepOrder = i + 1
+	}
+
+	expanded := *template
+	expanded.OrderSteps = steps
+	return &expanded, nil
+}
+
+// isComposite 하위 템플릿을 참조하는 단계가 있는지 여부
+func isComposite(template *models.OrderTemplate) bool {
+	for _, step := range template.OrderSteps {
+		if step.SubTemplateID != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// expandSteps 단계 순서대로 하위 템플릿을 재귀적으로 펼침 (path는 순환 검출용 템플릿 ID 경로)
+func expandSteps(db *gorm.DB, siteID string, template *models.OrderTemplate, path []uint) ([]models.OrderStep, error) {
+	if len(path) > MaxTemplateCompositionDepth {
+		return nil, apperr.New(apperr.CodeValidationFailed,
+			"order template %q exceeds the maximum composition depth of %d", template.Name, MaxTemplateCompositionDepth)
+	}
+
+	ordered := append([]models.OrderStep(nil), template.OrderSteps...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].StepOrder < ordered[j].StepOrder })
+
+	var steps []models.OrderStep
+	for _, step := range ordered {
+		if step.SubTemplateID == nil {
+			steps = append(steps, step)
+			continue
+		}
+
+		subID := *step.SubTemplateID
+		for _, id := range path {
+			if id == subID {
+				return nil, apperr.New(apperr.CodeValidationFailed,
+					"order template %q step %d references template %d in a cycle", template.Name, step.StepOrder, subID).
+					WithField("sub_template")
+			}
+		}
+
+		sub, err := LoadTemplateDetail(db, siteID, subID)
+		if err != nil {
+			return nil, apperr.Wrap(apperr.CodeTemplateNotFound, err,
+				"order template %q step %d references missing template %d", template.Name, step.StepOrder, subID)
+		}
+		if err := ValidateTemplateGraph(sub); err != nil {
+			return nil, err
+		}
+		if err := ValidateStepConditions(sub); err != nil {
+			return nil, err
+		}
+
+		subSteps, err := expandSteps(db, siteID, sub, append(path, subID))
+		if err != nil {
+			return nil, err
+		}
+		if len(subSteps) > 0 && step.PreviousStepResult != "" {
+			subSteps[0].PreviousStepResult = step.PreviousStepResult
+		}
+		// 인접한 다른 템플릿의 같은 이름 그룹과 합쳐지지 않도록 하위 템플릿 이름을 앞에 붙임
+		for i := range subSteps {
+			if subSteps[i].ParallelGroup != "" {
+				subSteps[i].ParallelGroup = sub.Name + "/" + subSteps[i].ParallelGroup
+			}
+		}
+		steps = append(steps, subSteps...)
+	}
+	return steps, nil
+}
+
+// validateCompositeStep 하위 템플릿 참조 단계는 자체 노드/액션/엣지를 가질 수 없음
+func validateCompositeStep(stepExport StepExport) error {
+	if stepExport.SubTemplate == "" {
+		return nil
+	}
+	if stepExport.Node != nil || len(stepExport.Actions) > 0 || len(stepExport.Edges) > 0 {
+		return apperr.Validation("sub_template", "step referencing sub template %q must not define a node, actions or edges", stepExport.SubTemplate)
+	}
+	// 조건식은 펼친 단계 전체를 건너뛸 수 없으므로 하위 템플릿의 단계에 직접 지정
+	if stepExport.Condition != "" {
+		return apperr.Validation("condition", "step referencing sub template %q must not define a condition", stepExport.SubTemplate)
+	}
+	// 병렬 그룹은 하위 템플릿 안에서 정의
+	if stepExport.ParallelGroup != "" {
+		return apperr.Validation("parallel_group", "step referencing sub template %q must not define a parallel group", stepExport.SubTemplate)
+	}
+	return nil
+}
+
+// resolveSubTemplate 하위 템플릿 이름을 사이트의 템플릿 ID로 변환
+func resolveSubTemplate(tx *gorm.DB, siteID, name string) (uint, error) {
+	var sub models.OrderTemplate
+	if err := tx.Scopes(SiteScope(siteID)).Where("name = ?", name).First(&sub).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return 0, apperr.New(apperr.CodeTemplateNotFound, "sub template %q not found", name).WithField("sub_template")
+		}
+		return 0, err
+	}
+	return sub.ID, nil
+}
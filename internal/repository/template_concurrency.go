@@ -0,0 +1,88 @@
+// internal/repository/template_concurrency.go
+package repository
+
+import (
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SetTemplateConcurrencyLimit 사이트 전체에서 템플릿 오더를 동시에 실행할 수 있는 최대 로봇 수 설정 (0이면 제한 없음)
+func SetTemplateConcurrencyLimit(db *gorm.DB, siteID string, templateID uint, limit int) error {
+	if limit < 0 {
+		return apperr.Validation("maxConcurrentExecutions", "concurrency limit must be 0 (unlimited) or positive, got %d", limit)
+	}
+	var template models.OrderTemplate
+	if err := db.Scopes(SiteScope(siteID)).First(&template, templateID).Error; err != nil {
+		return apperr.Wrap(apperr.CodeTemplateNotFound, err, "order template %d not found", templateID)
+	}
+	if err := db.Model(&template).Update("max_concurrent_executions", limit).Error; err != nil {
+		return err
+	}
+	utils.Logger.Infof("Order template %d concurrency limit set to %d", templateID, limit)
+	return nil
+}
+
+// AdmitOrderExecution 템플릿 동시 실행 제한 안에서 WAITING 오더 실행을 RUNNING으로 전환합니다.
+// 사이트 전체(같은 DB를 쓰는 모든 브릿지)에서 같은 템플릿의 RUNNING 오더와 먼저 대기한 오더를 세어
+// 자리가 있으면 전환하고, 없으면 대기 순번(1부터)만 갱신하고 false를 반환합니다.
+// 템플릿 행을 잠가 여러 브릿지가 같은 자리를 동시에 차지하지 않게 하며,
+// 그 사이 오더가 취소되어 WAITING이 아니면 execution.Status만 갱신하고 false를 반환합니다.
+func AdmitOrderExecution(db *gorm.DB, execution *models.OrderExecution) (bool, error) {
+	admitted := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var template models.OrderTemplate
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Scopes(SiteScope(execution.SiteID)).First(&template, execution.TemplateID).Error; err != nil {
+			return apperr.Wrap(apperr.CodeTemplateNotFound, err, "order template %d not found", execution.TemplateID)
+		}
+		var current models.OrderExecution
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, execution.ID).Error; err != nil {
+			return err
+		}
+		if current.Status != constants.OrderExecutionStatusWaiting {
+			execution.Status = current.Status
+			return nil
+		}
+
+		position := 0
+		if template.MaxConcurrentExecutions > 0 {
+			var running, ahead int64
+			if err := tx.Model(&models.OrderExecution{}).Scopes(SiteScope(execution.SiteID)).
+				Where("template_id = ? AND status = ?", template.ID, constants.OrderExecutionStatusRunning).
+				Count(&running).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.OrderExecution{}).Scopes(SiteScope(execution.SiteID)).
+				Where("template_id = ? AND status = ? AND id < ?", template.ID, constants.OrderExecutionStatusWaiting, execution.ID).
+				Count(&ahead).Error; err != nil {
+				return err
+			}
+			if running+ahead >= int64(template.MaxConcurrentExecutions) {
+				position = int(ahead) + 1
+			}
+		}
+
+		updates := map[string]interface{}{"queue_position": position}
+		if position == 0 {
+			updates["status"] = constants.OrderExecutionStatusRunning
+		}
+		if err := tx.Model(execution).Updates(updates).Error; err != nil {
+			return err
+		}
+		execution.QueuePosition = position
+		if position == 0 {
+			execution.Status = constants.OrderExecutionStatusRunning
+			admitted = true
+		}
+		return nil
+	})
+	if admitted {
+		notifyOrderStatus(execution)
+	}
+	return admitted, err
+}
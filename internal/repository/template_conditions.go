@@ -0,0 +1,61 @@
+// internal/repository/template_conditions.go
+package repository
+
+import (
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/condition"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"sort"
+)
+
+// ValidateStepConditions 단계 실행 조건(이전 단계 결과, 조건식, 병렬 그룹)을 검증합니다.
+//   - PreviousStepResult는 빈 값, ALWAYS, SUCCESS, FAILURE 중 하나
+//   - Condition은 condition 패키지 문법과 변수/타입 규칙을 만족해야 함
+//   - 같은 ParallelGroup의 단계는 순서상 연속해야 하며 하위 템플릿을 참조할 수 없음
+func ValidateStepConditions(template *models.OrderTemplate) error {
+	for _, step := range template.OrderSteps {
+		switch step.PreviousStepResult {
+		case "", constants.PreviousResultAny, constants.PreviousResultSuccess, constants.PreviousResultFailure:
+		default:
+			return apperr.Validation("previous_step_result",
+				"order template %q step %d: unsupported previous step result %q (ALWAYS, SUCCESS, FAILURE)",
+				template.Name, step.StepOrder, step.PreviousStepResult)
+		}
+		if err := condition.Validate(step.Condition); err != nil {
+			return apperr.Wrap(apperr.CodeValidationFailed, err,
+				"order template %q step %d has an invalid condition", template.Name, step.StepOrder).WithField("condition")
+		}
+	}
+	return validateParallelGroups(template)
+}
+
+// validateParallelGroups 병렬 그룹이 연속된 단계로만 이루어졌는지 확인
+func validateParallelGroups(template *models.OrderTemplate) error {
+	ordered := append([]models.OrderStep(nil), template.OrderSteps...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].StepOrder < ordered[j].StepOrder })
+
+	closed := make(map[string]bool)
+	previous := ""
+	for _, step := range ordered {
+		group := step.ParallelGroup
+		if group != previous && previous != "" {
+			closed[previous] = true
+		}
+		previous = group
+		if group == "" {
+			continue
+		}
+		if closed[group] {
+			return apperr.Validation("parallel_group",
+				"order template %q step %d: parallel group %q must consist of consecutive steps",
+				template.Name, step.StepOrder, group)
+		}
+		if step.SubTemplateID != nil {
+			return apperr.Validation("parallel_group",
+				"order template %q step %d: step referencing a sub template cannot join parallel group %q",
+				template.Name, step.StepOrder, group)
+		}
+	}
+	return nil
+}
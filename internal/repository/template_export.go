@@ -0,0 +1,474 @@
+// internal/repository/template_export.go
+package repository
+
+import (
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/common/validate"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/zones"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// TemplateExport 오더 템플릿 내보내기/가져오기 형식 (DB ID 없이 이식 가능)
+// validate 태그는 가져오기 전에 validate.Struct로 검사합니다.
+type TemplateExport struct {
+	Name          string       `json:"name" validate:"required,max=100"`
+	Description   string       `json:"description" validate:"max=500"`
+	IsActive      bool         `json:"is_active"`
+	Status        string       `json:"status,omitempty" validate:"oneof=DRAFT ACTIVE DEPRECATED"` // 비어 있으면 ACTIVE
+	MaxConcurrent int          `json:"max_concurrent_executions,omitempty" validate:"min=0"`
+	Deadline      int          `json:"deadline_seconds,omitempty" validate:"min=0"`
+	NotifyOn      string       `json:"notify_on,omitempty" validate:"omitempty,oneof=FAILURE ALL NONE"` // 비어 있으면 FAILURE
+	NotifyChannel string       `json:"notify_channel,omitempty" validate:"omitempty,oneof=slack email"`
+	EscalateAfter int          `json:"notify_escalate_after,omitempty" validate:"min=0"`
+	Steps         []StepExport `json:"steps"`
+}
+
+// StepExport 오더 단계 내보내기 형식
+type StepExport struct {
+	StepOrder          int            `json:"step_order" validate:"min=1"`
+	PreviousStepResult string         `json:"previous_step_result" validate:"oneof=ALWAYS SUCCESS FAILURE ABNORMAL NORMAL"`
+	Condition          string         `json:"condition,omitempty" validate:"max=255"`
+	WaitForCompletion  bool           `json:"wait_for_completion"`
+	TimeoutSeconds     int            `json:"timeout_seconds" validate:"min=0"`
+	ParallelGroup      string         `json:"parallel_group,omitempty" validate:"max=50"`
+	SubTemplate        string         `json:"sub_template,omitempty" validate:"max=100"` // 하위 시퀀스로 펼칠 템플릿 이름 (노드/액션/엣지 없음)
+	Node               *NodeExport    `json:"node,omitempty"`
+	Actions            []ActionExport `json:"actions"`
+	Edges              []EdgeExport   `json:"edges"`
+}
+
+// NodeExport 노드 템플릿 내보내기 형식
+type NodeExport struct {
+	Name                  string   `json:"name" validate:"required,max=100"`
+	Description           string   `json:"description" validate:"max=500"`
+	X                     float64  `json:"x"`
+	Y                     float64  `json:"y"`
+	Theta                 float64  `json:"theta" validate:"min=-pi,max=pi"`
+	AllowedDeviationXY    float64  `json:"allowed_deviation_xy" validate:"min=0"`
+	AllowedDeviationTheta float64  `json:"allowed_deviation_theta" validate:"min=0,max=pi"`
+	MapID                 string   `json:"map_id" validate:"max=100"`
+	Zones                 []string `json:"zones,omitempty"` // 점유 구역 ID
+}
+
+// ActionExport 단계 액션 내보내기 형식
+type ActionExport struct {
+	ExecutionOrder    int               `json:"execution_order" validate:"min=0"`
+	ActionType        string            `json:"action_type" validate:"required,max=100"`
+	ActionDescription string            `json:"action_description" validate:"max=500"`
+	BlockingType      string            `json:"blocking_type" validate:"oneof=NONE SOFT HARD"`
+	Parameters        []ParameterExport `json:"parameters"`
+}
+
+// ParameterExport 액션 파라미터 내보내기 형식
+type ParameterExport struct {
+	Key       string `json:"key" validate:"required,max=100"`
+	Value     string `json:"value" validate:"max=500"`
+	ValueType string `json:"value_type" validate:"max=20"`
+}
+
+// EdgeExport 엣지 템플릿 내보내기 형식
+type EdgeExport struct {
+	EdgeID          string   `json:"edge_id" validate:"required,max=100"`
+	StartNodeID     string   `json:"start_node_id" validate:"required,max=100"`
+	EndNodeID       string   `json:"end_node_id" validate:"required,max=100"`
+	MaxSpeed        float64  `json:"max_speed" validate:"min=0"`
+	MaxHeight       float64  `json:"max_height" validate:"min=0"`
+	MinHeight       float64  `json:"min_height" validate:"min=0"`
+	Orientation     float64  `json:"orientation" validate:"min=-pi,max=pi"`
+	Direction       string   `json:"direction" validate:"max=20"`
+	RotationAllowed bool     `json:"rotation_allowed"`
+	Zones           []string `json:"zones,omitempty"` // 점유 구역 ID
+}
+
+// LoadTemplateDetail 템플릿을 스텝, 노드, 액션, 파라미터, 엣지까지 함께 로드
+func LoadTemplateDetail(db *gorm.DB, siteID string, templateID uint) (*models.OrderTemplate, error) {
+	var template models.OrderTemplate
+	err := db.Scopes(SiteScope(siteID)).
+		Preload("OrderSteps", func(db *gorm.DB) *gorm.DB {
+			return db.Order("order_steps.step_order ASC")
+		}).
+		Preload("OrderSteps.NodeTemplate").
+		Preload("OrderSteps.SubTemplate").
+		Preload("OrderSteps.StepActionMappings.ActionTemplate.Parameters").
+		Preload("OrderSteps.Edges").
+		First(&template, templateID).Error
+	if err != nil {
+		return nil, fmt.Errorf("order template %d not found: %w", templateID, err)
+	}
+	return &template, nil
+}
+
+// ExportOrderTemplates 사이트의 모든 활성 템플릿을 내보내기 형식으로 변환
+func ExportOrderTemplates(db *gorm.DB, siteID string) ([]TemplateExport, error) {
+	templates, err := ListOrderTemplates(db, siteID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	exports := make([]TemplateExport, 0, len(templates))
+	for _, t := range templates {
+		detail, err := LoadTemplateDetail(db, siteID, t.ID)
+		if err != nil {
+			return nil, err
+		}
+		exports = append(exports, ToTemplateExport(detail))
+	}
+	return exports, nil
+}
+
+// ToTemplateExport 로드된 템플릿을 내보내기 형식으로 변환
+func ToTemplateExport(template *models.OrderTemplate) TemplateExport {
+	export := TemplateExport{
+		Name:          template.Name,
+		Description:   template.Description,
+		IsActive:      template.IsActive,
+		Status:        template.Status,
+		MaxConcurrent: template.MaxConcurrentExecutions,
+		Deadline:      template.DeadlineSeconds,
+		NotifyOn:      template.NotifyOn,
+		NotifyChannel: template.NotifyChannel,
+		EscalateAfter: template.NotifyEscalateAfter,
+		Steps:         make([]StepExport, 0, len(template.OrderSteps)),
+	}
+
+	for _, step := range template.OrderSteps {
+		stepExport := StepExport{
+			StepOrder:          step.StepOrder,
+			PreviousStepResult: step.PreviousStepResult,
+			Condition:          step.Condition,
+			WaitForCompletion:  step.WaitForCompletion,
+			TimeoutSeconds:     step.TimeoutSeconds,
+			ParallelGroup:      step.ParallelGroup,
+			Actions:            make([]ActionExport, 0, len(step.StepActionMappings)),
+			Edges:              make([]EdgeExport, 0, len(step.Edges)),
+		}
+		if step.SubTemplate != nil {
+			stepExport.SubTemplate = step.SubTemplate.Name
+		}
+
+		if step.NodeTemplate != nil {
+			stepExport.Node = &NodeExport{
+				Name:                  step.NodeTemplate.Name,
+				Description:           step.NodeTemplate.Description,
+				X:                     step.NodeTemplate.X,
+				Y:                     step.NodeTemplate.Y,
+				Theta:                 step.NodeTemplate.Theta,
+				AllowedDeviationXY:    step.NodeTemplate.AllowedDeviationXY,
+				AllowedDeviationTheta: step.NodeTemplate.AllowedDeviationTheta,
+				MapID:                 step.NodeTemplate.MapID,
+				Zones:                 zones.ParseZones(step.NodeTemplate.Zones),
+			}
+		}
+
+		mappings := step.StepActionMappings
+		sort.Slice(mappings, func(i, j int) bool {
+			return mappings[i].ExecutionOrder < mappings[j].ExecutionOrder
+		})
+		for _, mapping := range mappings {
+			action := ActionExport{
+				ExecutionOrder:    mapping.ExecutionOrder,
+				ActionType:        mapping.ActionTemplate.ActionType,
+				ActionDescription: mapping.ActionTemplate.ActionDescription,
+				BlockingType:      mapping.ActionTemplate.BlockingType,
+				Parameters:        make([]ParameterExport, 0, len(mapping.ActionTemplate.Parameters)),
+			}
+			for _, param := range mapping.ActionTemplate.Parameters {
+				action.Parameters = append(action.Parameters, ParameterExport{
+					Key:       param.Key,
+					Value:     param.Value,
+					ValueType: param.ValueType,
+				})
+			}
+			stepExport.Actions = append(stepExport.Actions, action)
+		}
+
+		for _, edge := range step.Edges {
+			stepExport.Edges = append(stepExport.Edges, EdgeExport{
+				EdgeID:          edge.EdgeID,
+				StartNodeID:     edge.StartNodeID,
+				EndNodeID:       edge.EndNodeID,
+				MaxSpeed:        edge.MaxSpeed,
+				MaxHeight:       edge.MaxHeight,
+				MinHeight:       edge.MinHeight,
+				Orientation:     edge.Orientation,
+				Direction:       edge.Direction,
+				RotationAllowed: edge.RotationAllowed,
+				Zones:           zones.ParseZones(edge.Zones),
+			})
+		}
+
+		export.Steps = append(export.Steps, stepExport)
+	}
+
+	return export
+}
+
+// TemplateFromExport 내보내기 형식을 DB 없이 메모리의 템플릿으로 변환합니다 (골든 픽스처 렌더링용).
+// 하위 템플릿은 해석할 수 없으므로 펼친 템플릿을 내보낸 형식이어야 하며, 그래프와 단계 조건을 검증합니다.
+func TemplateFromExport(export TemplateExport) (*models.OrderTemplate, error) {
+	if err := validate.Struct(export); err != nil {
+		return nil, err
+	}
+	template := &models.OrderTemplate{
+		Name:                    export.Name,
+		Description:             export.Description,
+		IsActive:                export.IsActive,
+		Status:                  export.Status,
+		MaxConcurrentExecutions: export.MaxConcurrent,
+		DeadlineSeconds:         export.Deadline,
+		NotifyOn:                export.NotifyOn,
+		NotifyChannel:           export.NotifyChannel,
+		NotifyEscalateAfter:     export.EscalateAfter,
+		OrderSteps:              make([]models.OrderStep, 0, len(export.Steps)),
+	}
+	for _, stepExport := range export.Steps {
+		if stepExport.SubTemplate != "" {
+			return nil, fmt.Errorf("step %d: sub template %q must be expanded before rendering", stepExport.StepOrder, stepExport.SubTemplate)
+		}
+		step := models.OrderStep{
+			StepOrder:          stepExport.StepOrder,
+			PreviousStepResult: stepExport.PreviousStepResult,
+			Condition:          stepExport.Condition,
+			WaitForCompletion:  stepExport.WaitForCompletion,
+			TimeoutSeconds:     stepExport.TimeoutSeconds,
+			ParallelGroup:      stepExport.ParallelGroup,
+		}
+		if stepExport.Node != nil {
+			step.NodeTemplate = &models.NodeTemplate{
+				Name:                  stepExport.Node.Name,
+				Description:           stepExport.Node.Description,
+				X:                     stepExport.Node.X,
+				Y:                     stepExport.Node.Y,
+				Theta:                 stepExport.Node.Theta,
+				AllowedDeviationXY:    stepExport.Node.AllowedDeviationXY,
+				AllowedDeviationTheta: stepExport.Node.AllowedDeviationTheta,
+				MapID:                 stepExport.Node.MapID,
+				Zones:                 zones.FormatZones(stepExport.Node.Zones),
+			}
+		}
+		for _, actionExport := range stepExport.Actions {
+			action := models.ActionTemplate{
+				ActionType:        actionExport.ActionType,
+				ActionDescription: actionExport.ActionDescription,
+				BlockingType:      actionExport.BlockingType,
+			}
+			for _, paramExport := range actionExport.Parameters {
+				action.Parameters = append(action.Parameters, models.ActionParameter{
+					Key:       paramExport.Key,
+					Value:     paramExport.Value,
+					ValueType: paramExport.ValueType,
+				})
+			}
+			step.StepActionMappings = append(step.StepActionMappings, models.StepActionMapping{
+				ExecutionOrder: actionExport.ExecutionOrder,
+				ActionTemplate: action,
+			})
+		}
+		for _, edgeExport := range stepExport.Edges {
+			step.Edges = append(step.Edges, models.EdgeTemplate{
+				EdgeID:          edgeExport.EdgeID,
+				StartNodeID:     edgeExport.StartNodeID,
+				EndNodeID:       edgeExport.EndNodeID,
+				MaxSpeed:        edgeExport.MaxSpeed,
+				MaxHeight:       edgeExport.MaxHeight,
+				MinHeight:       edgeExport.MinHeight,
+				Orientation:     edgeExport.Orientation,
+				Direction:       edgeExport.Direction,
+				RotationAllowed: edgeExport.RotationAllowed,
+				Zones:           zones.FormatZones(edgeExport.Zones),
+			})
+		}
+		template.OrderSteps = append(template.OrderSteps, step)
+	}
+	sort.SliceStable(template.OrderSteps, func(i, j int) bool {
+		return template.OrderSteps[i].StepOrder < template.OrderSteps[j].StepOrder
+	})
+
+	if err := ValidateTemplateGraph(template); err != nil {
+		return nil, err
+	}
+	if err := ValidateStepConditions(template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// ImportOrderTemplate 내보내기 형식의 템플릿을 사이트에 새로 생성합니다.
+// 같은 이름의 템플릿이 이미 있으면 에러를 반환합니다.
+func ImportOrderTemplate(db *gorm.DB, siteID string, export TemplateExport) (*models.OrderTemplate, error) {
+	if err := validate.Struct(export); err != nil {
+		return nil, apperr.Wrap(apperr.CodeValidationFailed, err, "invalid order template %q", export.Name)
+	}
+
+	var count int64
+	db.Model(&models.OrderTemplate{}).Scopes(SiteScope(siteID)).Where("name = ?", export.Name).Count(&count)
+	if count > 0 {
+		return nil, apperr.Validation("name", "order template %q already exists", export.Name)
+	}
+
+	if export.Status == "" {
+		export.Status = constants.TemplateStatusActive
+	}
+
+	template := &models.OrderTemplate{
+		SiteID:                  siteID,
+		Name:                    export.Name,
+		Description:             export.Description,
+		IsActive:                export.IsActive,
+		Status:                  export.Status,
+		MaxConcurrentExecutions: export.MaxConcurrent,
+		DeadlineSeconds:         export.Deadline,
+		NotifyOn:                export.NotifyOn,
+		NotifyChannel:           export.NotifyChannel,
+		NotifyEscalateAfter:     export.EscalateAfter,
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(template).Error; err != nil {
+			return err
+		}
+		// gorm default:true 필드는 false 값이 생략되므로 명시적으로 갱신
+		if !export.IsActive {
+			if err := tx.Model(template).Update("is_active", false).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, stepExport := range export.Steps {
+			if err := importStep(tx, siteID, template.ID, stepExport); err != nil {
+				return fmt.Errorf("step %d: %w", stepExport.StepOrder, err)
+			}
+		}
+
+		// 노드/엣지 그래프나 단계 실행 조건이 올바르지 않거나 하위 템플릿을 펼칠 수 없으면 롤백
+		detail, err := LoadTemplateDetail(tx, siteID, template.ID)
+		if err != nil {
+			return err
+		}
+		if err := ValidateTemplateGraph(detail); err != nil {
+			return err
+		}
+		if err := ValidateStepConditions(detail); err != nil {
+			return err
+		}
+		_, err = ExpandTemplate(tx, siteID, detail)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to import order template %q: %w", export.Name, err)
+	}
+
+	return template, nil
+}
+
+// importStep 단계와 노드, 액션, 엣지 생성 (하위 템플릿 참조는 사이트의 템플릿 이름으로 해석)
+func importStep(tx *gorm.DB, siteID string, templateID uint, stepExport StepExport) error {
+	if err := validateCompositeStep(stepExport); err != nil {
+		return err
+	}
+
+	step := models.OrderStep{
+		TemplateID:         templateID,
+		StepOrder:          stepExport.StepOrder,
+		PreviousStepResult: stepExport.PreviousStepResult,
+		Condition:          stepExport.Condition,
+		WaitForCompletion:  stepExport.WaitForCompletion,
+		TimeoutSeconds:     stepExport.TimeoutSeconds,
+		ParallelGroup:      stepExport.ParallelGroup,
+	}
+
+	if stepExport.SubTemplate != "" {
+		subID, err := resolveSubTemplate(tx, siteID, stepExport.SubTemplate)
+		if err != nil {
+			return err
+		}
+		step.SubTemplateID = &subID
+	}
+
+	if stepExport.Node != nil {
+		node := models.NodeTemplate{
+			Name:                  stepExport.Node.Name,
+			Description:           stepExport.Node.Description,
+			X:                     stepExport.Node.X,
+			Y:                     stepExport.Node.Y,
+			Theta:                 stepExport.Node.Theta,
+			AllowedDeviationXY:    stepExport.Node.AllowedDeviationXY,
+			AllowedDeviationTheta: stepExport.Node.AllowedDeviationTheta,
+			MapID:                 stepExport.Node.MapID,
+			Zones:                 zones.FormatZones(stepExport.Node.Zones),
+		}
+		if err := tx.Where(&node).FirstOrCreate(&node).Error; err != nil {
+			return err
+		}
+		step.NodeTemplateID = &node.ID
+	}
+
+	if err := tx.Create(&step).Error; err != nil {
+		return err
+	}
+	if !stepExport.WaitForCompletion {
+		if err := tx.Model(&step).Update("wait_for_completion", false).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, actionExport := range stepExport.Actions {
+		action := models.ActionTemplate{
+			ActionType:        actionExport.ActionType,
+			ActionDescription: actionExport.ActionDescription,
+			BlockingType:      actionExport.BlockingType,
+		}
+		if err := tx.Create(&action).Error; err != nil {
+			return err
+		}
+		for _, paramExport := range actionExport.Parameters {
+			param := models.ActionParameter{
+				ActionTemplateID: action.ID,
+				Key:              paramExport.Key,
+				Value:            paramExport.Value,
+				ValueType:        paramExport.ValueType,
+			}
+			if err := tx.Create(&param).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(&models.StepActionMapping{
+			OrderStepID:      step.ID,
+			ActionTemplateID: action.ID,
+			ExecutionOrder:   actionExport.ExecutionOrder,
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, edgeExport := range stepExport.Edges {
+		edge := models.EdgeTemplate{
+			OrderStepID:     step.ID,
+			EdgeID:          edgeExport.EdgeID,
+			StartNodeID:     edgeExport.StartNodeID,
+			EndNodeID:       edgeExport.EndNodeID,
+			MaxSpeed:        edgeExport.MaxSpeed,
+			MaxHeight:       edgeExport.MaxHeight,
+			MinHeight:       edgeExport.MinHeight,
+			Orientation:     edgeExport.Orientation,
+			Direction:       edgeExport.Direction,
+			RotationAllowed: edgeExport.RotationAllowed,
+			Zones:           zones.FormatZones(edgeExport.Zones),
+		}
+		if err := tx.Create(&edge).Error; err != nil {
+			return err
+		}
+		if !edgeExport.RotationAllowed {
+			if err := tx.Model(&edge).Update("rotation_allowed", false).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,217 @@
+// internal/repository/template_graph.go
+package repository
+
+import (
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/models"
+	"sort"
+	"strings"
+)
+
+// 그래프 검증 문제 유형
+const (
+	GraphProblemDuplicateSequence = "DUPLICATE_SEQUENCE_ID"
+	GraphProblemInvalidSequence   = "INVALID_SEQUENCE_ID"
+	GraphProblemDuplicateNode     = "DUPLICATE_NODE"
+	GraphProblemDuplicateEdge     = "DUPLICATE_EDGE"
+	GraphProblemUnknownNode       = "UNKNOWN_NODE"
+	GraphProblemOrphanEdge        = "ORPHAN_EDGE"
+	GraphProblemDisconnected      = "DISCONNECTED_GRAPH"
+)
+
+// GraphProblem 그래프 검증 문제 (문제가 된 ID 목록 포함)
+type GraphProblem struct {
+	Code    string   `json:"code"`
+	Message string   `json:"message"`
+	IDs     []string `json:"ids"`
+}
+
+// GraphValidationError 템플릿 노드/엣지 그래프 검증 실패
+type GraphValidationError struct {
+	TemplateID   uint
+	TemplateName string
+	Problems     []GraphProblem
+}
+
+// Error 모든 문제와 해당 ID를 나열
+func (e *GraphValidationError) Error() string {
+	parts := make([]string, 0, len(e.Problems))
+	for _, p := range e.Problems {
+		parts = append(parts, fmt.Sprintf("%s: %s [%s]", p.Code, p.Message, strings.Join(p.IDs, ", ")))
+	}
+	return fmt.Sprintf("order template %q (id=%d) has an invalid node/edge graph: %s",
+		e.TemplateName, e.TemplateID, strings.Join(parts, "; "))
+}
+
+// ErrorCode 오류 코드 (apperr.Coder)
+func (e *GraphValidationError) ErrorCode() apperr.Code {
+	return apperr.CodeValidationFailed
+}
+
+// ValidateTemplateGraph 오더 템플릿의 노드/엣지 그래프를 검증합니다.
+// 노드는 각 단계의 NodeTemplate 이름으로 식별되며, 엣지의 시작/끝 노드는 이 이름을 참조해야 합니다.
+//   - 단계 순번(sequenceId)은 1 이상이고 중복되지 않아야 함
+//   - 노드 이름과 엣지 ID는 템플릿 내에서 중복되지 않아야 함
+//   - 엣지는 템플릿에 존재하는 노드만 참조해야 함
+//   - 엣지는 자신이 속한 단계의 노드에 연결되어야 함 (고아 엣지 금지)
+//   - 엣지가 있으면 모든 노드가 하나의 연결 그래프를 이루어야 함
+//
+// OrderSteps, OrderSteps.NodeTemplate, OrderSteps.Edges가 preload되어 있어야 합니다.
+func ValidateTemplateGraph(template *models.OrderTemplate) error {
+	var problems []GraphProblem
+	addProblem := func(code, message string, ids map[string]bool) {
+		if len(ids) == 0 {
+			return
+		}
+		problems = append(problems, GraphProblem{Code: code, Message: message, IDs: sortedKeys(ids)})
+	}
+
+	// 단계 순번
+	seenSteps := make(map[int]bool)
+	duplicateSteps := make(map[string]bool)
+	invalidSteps := make(map[string]bool)
+	for _, step := range template.OrderSteps {
+		id := fmt.Sprintf("step %d", step.StepOrder)
+		if step.StepOrder < 1 {
+			invalidSteps[id] = true
+		}
+		if seenSteps[step.StepOrder] {
+			duplicateSteps[id] = true
+		}
+		seenSteps[step.StepOrder] = true
+	}
+	addProblem(GraphProblemInvalidSequence, "step sequence must start at 1", invalidSteps)
+	addProblem(GraphProblemDuplicateSequence, "step sequence is used by more than one step", duplicateSteps)
+
+	// 노드 (단계별 NodeTemplate 이름)
+	nodes := make(map[string]bool)
+	duplicateNodes := make(map[string]bool)
+	stepNodes := make(map[uint]string)
+	for _, step := range template.OrderSteps {
+		if step.NodeTemplate == nil {
+			continue
+		}
+		name := step.NodeTemplate.Name
+		stepNodes[step.ID] = name
+		// 같은 NodeTemplate을 여러 단계가 재사용하는 것은 허용
+		if nodes[name] && !sameNodeTemplate(template, step, name) {
+			duplicateNodes[name] = true
+		}
+		nodes[name] = true
+	}
+	addProblem(GraphProblemDuplicateNode, "node name is used by different node templates", duplicateNodes)
+
+	// 엣지
+	seenEdges := make(map[string]bool)
+	duplicateEdges := make(map[string]bool)
+	unknownNodes := make(map[string]bool)
+	orphanEdges := make(map[string]bool)
+	adjacency := make(map[string][]string)
+	edgeCount := 0
+	for _, step := range template.OrderSteps {
+		for _, edge := range step.Edges {
+			edgeCount++
+			if seenEdges[edge.EdgeID] {
+				duplicateEdges[edge.EdgeID] = true
+			}
+			seenEdges[edge.EdgeID] = true
+
+			known := true
+			for _, nodeID := range []string{edge.StartNodeID, edge.EndNodeID} {
+				if !nodes[nodeID] {
+					unknownNodes[fmt.Sprintf("%s→%s", edge.EdgeID, nodeID)] = true
+					known = false
+				}
+			}
+
+			stepNode, hasNode := stepNodes[step.ID]
+			if !hasNode || (edge.StartNodeID != stepNode && edge.EndNodeID != stepNode) {
+				orphanEdges[fmt.Sprintf("%s (step %d)", edge.EdgeID, step.StepOrder)] = true
+			}
+
+			if known {
+				adjacency[edge.StartNodeID] = append(adjacency[edge.StartNodeID], edge.EndNodeID)
+				adjacency[edge.EndNodeID] = append(adjacency[edge.EndNodeID], edge.StartNodeID)
+			}
+		}
+	}
+	addProblem(GraphProblemDuplicateEdge, "edge ID is used more than once", duplicateEdges)
+	addProblem(GraphProblemUnknownNode, "edge references a node that is not in the template", unknownNodes)
+	addProblem(GraphProblemOrphanEdge, "edge is not connected to its step's node", orphanEdges)
+
+	// 연결성 (엣지가 정의된 템플릿만)
+	if edgeCount > 0 && len(nodes) > 1 {
+		addProblem(GraphProblemDisconnected, "node is not reachable from the rest of the graph",
+			unreachableNodes(nodes, adjacency))
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return &GraphValidationError{
+		TemplateID:   template.ID,
+		TemplateName: template.Name,
+		Problems:     problems,
+	}
+}
+
+// sameNodeTemplate 같은 이름의 노드가 모두 동일한 NodeTemplate인지 확인
+func sameNodeTemplate(template *models.OrderTemplate, step models.OrderStep, name string) bool {
+	for _, other := range template.OrderSteps {
+		if other.ID == step.ID || other.NodeTemplate == nil || other.NodeTemplate.Name != name {
+			continue
+		}
+		if other.NodeTemplate.ID != step.NodeTemplate.ID {
+			return false
+		}
+	}
+	return true
+}
+
+// unreachableNodes 가장 큰 연결 요소에 속하지 않는 노드 목록
+func unreachableNodes(nodes map[string]bool, adjacency map[string][]string) map[string]bool {
+	visited := make(map[string]bool)
+	var largest map[string]bool
+
+	for _, start := range sortedKeys(nodes) {
+		if visited[start] {
+			continue
+		}
+		component := map[string]bool{start: true}
+		queue := []string{start}
+		visited[start] = true
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, next := range adjacency[current] {
+				if !visited[next] {
+					visited[next] = true
+					component[next] = true
+					queue = append(queue, next)
+				}
+			}
+		}
+		if len(component) > len(largest) {
+			largest = component
+		}
+	}
+
+	unreachable := make(map[string]bool)
+	for node := range nodes {
+		if !largest[node] {
+			unreachable[node] = true
+		}
+	}
+	return unreachable
+}
+
+// sortedKeys 정렬된 키 목록
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
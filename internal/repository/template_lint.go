@@ -0,0 +1,303 @@
+// internal/repository/template_lint.go
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Lint Severity 템플릿 린트 결과 심각도 상수
+const (
+	LintSeverityError   = "ERROR"   // 실행하면 실패하거나 의도대로 동작하지 않음
+	LintSeverityWarning = "WARNING" // 동작은 하지만 운영에서 문제가 되기 쉬움
+)
+
+// Lint Rule 템플릿 린트 규칙 ID
+const (
+	LintRuleNoSteps                 = "NO_STEPS"                  // 단계가 없는 템플릿
+	LintRuleInvalidGraph            = "INVALID_GRAPH"             // 노드/엣지 그래프 검증 실패
+	LintRuleInvalidCondition        = "INVALID_CONDITION"         // 실행 조건/병렬 그룹 검증 실패
+	LintRuleZeroTimeout             = "ZERO_TIMEOUT"              // 제한 시간이 0 이하인 단계
+	LintRuleHardBeforeNone          = "HARD_BEFORE_NONE"          // HARD 블로킹 액션 뒤의 NONE 액션
+	LintRuleUnreachableFailureOrder = "UNREACHABLE_FAILURE_ORDER" // 명령 매핑의 FailureOrder가 없는 순번을 가리킴
+	LintRuleUnreachableNextOrder    = "UNREACHABLE_NEXT_ORDER"    // 명령 매핑의 NextExecutionOrder가 없는 순번을 가리킴
+	LintRuleFinalStepNoWait         = "FINAL_STEP_NO_WAIT"        // 마지막 단계가 완료를 기다리지 않음
+	LintRuleInvalidParameterType    = "INVALID_PARAMETER_TYPE"    // 값이 선언한 타입으로 변환되지 않거나 모르는 타입
+	LintRuleSuspiciousParameterType = "SUSPICIOUS_PARAMETER_TYPE" // STRING으로 선언했지만 숫자/불리언처럼 보이는 값
+)
+
+// LintFinding 템플릿 린트 결과 하나
+type LintFinding struct {
+	Rule      string `json:"rule"`
+	Severity  string `json:"severity"`
+	Message   string `json:"message"`
+	StepOrder int    `json:"step_order,omitempty"` // 문제가 된 단계 (템플릿 전체 문제면 0)
+	Field     string `json:"field,omitempty"`
+}
+
+// LintReport 템플릿 린트 보고서
+type LintReport struct {
+	TemplateID   uint          `json:"template_id"`
+	TemplateName string        `json:"template_name"`
+	Errors       int           `json:"errors"`
+	Warnings     int           `json:"warnings"`
+	Findings     []LintFinding `json:"findings"`
+}
+
+// Clean 오류와 경고가 하나도 없는지
+func (r *LintReport) Clean() bool {
+	return r.Errors == 0 && r.Warnings == 0
+}
+
+// lintTarget 린트 규칙이 검사하는 템플릿과 이 템플릿을 쓰는 명령 매핑
+type lintTarget struct {
+	template *models.OrderTemplate
+	mappings []models.CommandOrderMapping
+	// orders 명령 정의별 활성 매핑 순번 (FailureOrder/NextExecutionOrder 확인용)
+	orders map[uint]map[int]bool
+}
+
+// lintRule 템플릿 린트 규칙
+type lintRule struct {
+	id       string
+	severity string
+	check    func(t *lintTarget) []LintFinding
+}
+
+// lintRules 적용할 린트 규칙 (보고서는 이 순서로 나열)
+var lintRules = []lintRule{
+	{LintRuleNoSteps, LintSeverityError, lintNoSteps},
+	{LintRuleInvalidGraph, LintSeverityError, lintGraph},
+	{LintRuleInvalidCondition, LintSeverityError, lintConditions},
+	{LintRuleZeroTimeout, LintSeverityWarning, lintZeroTimeout},
+	{LintRuleHardBeforeNone, LintSeverityWarning, lintHardBeforeNone},
+	{LintRuleUnreachableFailureOrder, LintSeverityError, lintMappingOrders(func(m models.CommandOrderMapping) (string, int) {
+		return "FailureOrder", m.FailureOrder
+	})},
+	{LintRuleUnreachableNextOrder, LintSeverityError, lintMappingOrders(func(m models.CommandOrderMapping) (string, int) {
+		return "NextExecutionOrder", m.NextExecutionOrder
+	})},
+	{LintRuleFinalStepNoWait, LintSeverityWarning, lintFinalStepNoWait},
+	{LintRuleInvalidParameterType, LintSeverityError, lintInvalidParameterTypes},
+	{LintRuleSuspiciousParameterType, LintSeverityWarning, lintSuspiciousParameterTypes},
+}
+
+// LintOrderTemplate 템플릿에 모범 사례 규칙을 적용한 보고서
+// 작성한 그대로의 템플릿을 검사하며, 하위 템플릿 참조 단계의 내용은 하위 템플릿을 따로 검사하세요.
+func LintOrderTemplate(db *gorm.DB, siteID string, templateID uint) (*LintReport, error) {
+	template, err := LoadTemplateDetail(db, siteID, templateID)
+	if err != nil {
+		return nil, apperr.Wrap(apperr.CodeTemplateNotFound, err, "order template %d not found", templateID)
+	}
+	target := &lintTarget{template: template, orders: make(map[uint]map[int]bool)}
+	if err := db.Where("template_id = ? AND is_active = ?", templateID, true).
+		Order("command_definition_id ASC, execution_order ASC").
+		Find(&target.mappings).Error; err != nil {
+		return nil, err
+	}
+	for _, mapping := range target.mappings {
+		if _, ok := target.orders[mapping.CommandDefinitionID]; ok {
+			continue
+		}
+		var executionOrders []int
+		if err := db.Model(&models.CommandOrderMapping{}).
+			Where("command_definition_id = ? AND is_active = ?", mapping.CommandDefinitionID, true).
+			Pluck("execution_order", &executionOrders).Error; err != nil {
+			return nil, err
+		}
+		orders := make(map[int]bool, len(executionOrders))
+		for _, order := range executionOrders {
+			orders[order] = true
+		}
+		target.orders[mapping.CommandDefinitionID] = orders
+	}
+
+	report := &LintReport{TemplateID: template.ID, TemplateName: template.Name, Findings: make([]LintFinding, 0)}
+	for _, rule := range lintRules {
+		for _, finding := range rule.check(target) {
+			finding.Rule, finding.Severity = rule.id, rule.severity
+			if finding.Severity == LintSeverityError {
+				report.Errors++
+			} else {
+				report.Warnings++
+			}
+			report.Findings = append(report.Findings, finding)
+		}
+	}
+	return report, nil
+}
+
+func lintNoSteps(t *lintTarget) []LintFinding {
+	if len(t.template.OrderSteps) > 0 {
+		return nil
+	}
+	return []LintFinding{{Message: "template has no steps, its orders complete without sending anything to the robot"}}
+}
+
+func lintGraph(t *lintTarget) []LintFinding {
+	var graphErr *GraphValidationError
+	if err := ValidateTemplateGraph(t.template); !errors.As(err, &graphErr) {
+		return nil
+	}
+	findings := make([]LintFinding, 0, len(graphErr.Problems))
+	for _, problem := range graphErr.Problems {
+		findings = append(findings, LintFinding{
+			Message: fmt.Sprintf("%s: %s [%s]", problem.Code, problem.Message, strings.Join(problem.IDs, ", ")),
+		})
+	}
+	return findings
+}
+
+func lintConditions(t *lintTarget) []LintFinding {
+	err := apperr.From(ValidateStepConditions(t.template))
+	if err == nil {
+		return nil
+	}
+	return []LintFinding{{Message: err.Message, Field: err.Field}}
+}
+
+func lintZeroTimeout(t *lintTarget) []LintFinding {
+	var findings []LintFinding
+	for _, step := range t.template.OrderSteps {
+		if step.SubTemplateID == nil && step.TimeoutSeconds <= 0 {
+			findings = append(findings, LintFinding{
+				Message:   fmt.Sprintf("step %d has timeout %ds, a robot that never reports completion blocks the order forever", step.StepOrder, step.TimeoutSeconds),
+				StepOrder: step.StepOrder,
+				Field:     "timeout_seconds",
+			})
+		}
+	}
+	return findings
+}
+
+// lintHardBeforeNone HARD 액션은 앞뒤 액션과 동시에 실행되지 않으므로 뒤의 NONE 블로킹은 의도대로 동작하지 않음
+func lintHardBeforeNone(t *lintTarget) []LintFinding {
+	var findings []LintFinding
+	for _, step := range t.template.OrderSteps {
+		mappings := append([]models.StepActionMapping(nil), step.StepActionMappings...)
+		sort.SliceStable(mappings, func(i, j int) bool { return mappings[i].ExecutionOrder < mappings[j].ExecutionOrder })
+		hard := ""
+		for _, mapping := range mappings {
+			action := mapping.ActionTemplate
+			switch {
+			case action.BlockingType == constants.BlockingTypeHard:
+				hard = action.ActionType
+			case hard != "" && (action.BlockingType == constants.BlockingTypeNone || action.BlockingType == ""):
+				findings = append(findings, LintFinding{
+					Message: fmt.Sprintf("step %d: action %q (NONE) follows HARD-blocking action %q and cannot run in parallel with it",
+						step.StepOrder, action.ActionType, hard),
+					StepOrder: step.StepOrder,
+					Field:     "blocking_type",
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// lintMappingOrders 이 템플릿을 쓰는 명령 매핑의 다음 순번이 같은 명령의 활성 매핑을 가리키는지 확인
+func lintMappingOrders(target func(models.CommandOrderMapping) (string, int)) func(t *lintTarget) []LintFinding {
+	return func(t *lintTarget) []LintFinding {
+		var findings []LintFinding
+		for _, mapping := range t.mappings {
+			field, order := target(mapping)
+			if order == 0 || t.orders[mapping.CommandDefinitionID][order] {
+				continue
+			}
+			findings = append(findings, LintFinding{
+				Message: fmt.Sprintf("command definition %d, execution order %d: %s %d does not match any active mapping of the command",
+					mapping.CommandDefinitionID, mapping.ExecutionOrder, field, order),
+				Field: field,
+			})
+		}
+		return findings
+	}
+}
+
+// lintFinalStepNoWait 마지막 단계(병렬 그룹이면 그룹 전체)가 완료를 기다리지 않으면 로봇이 끝내기 전에 오더가 완료됨
+func lintFinalStepNoWait(t *lintTarget) []LintFinding {
+	steps := t.template.OrderSteps
+	if len(steps) == 0 {
+		return nil
+	}
+	last := steps[0]
+	for _, step := range steps {
+		if step.StepOrder > last.StepOrder {
+			last = step
+		}
+	}
+	if last.SubTemplateID != nil {
+		return nil
+	}
+	for _, step := range steps {
+		if step.WaitForCompletion && (step.ID == last.ID || (last.ParallelGroup != "" && step.ParallelGroup == last.ParallelGroup)) {
+			return nil
+		}
+	}
+	return []LintFinding{{
+		Message:   fmt.Sprintf("final step %d does not wait for completion, the order completes before the robot finishes it", last.StepOrder),
+		StepOrder: last.StepOrder,
+		Field:     "wait_for_completion",
+	}}
+}
+
+// lintInvalidParameterTypes 고정 값이 선언한 타입으로 변환되지 않거나 모르는 타입 (자리표시자 값은 실행 시 검사)
+func lintInvalidParameterTypes(t *lintTarget) []LintFinding {
+	return lintParameters(t, func(param models.ActionParameter) string {
+		switch param.ValueType {
+		case "", "STRING", "NUMBER", "BOOLEAN":
+		default:
+			return fmt.Sprintf("unknown value type %q (STRING, NUMBER, BOOLEAN)", param.ValueType)
+		}
+		if len(Placeholders(param.Value)) > 0 {
+			return ""
+		}
+		if err := coerceParameter(param.ValueType, param.Value); err != nil {
+			return fmt.Sprintf("%v but declared %s", err, param.ValueType)
+		}
+		return ""
+	})
+}
+
+// lintSuspiciousParameterTypes STRING으로 선언되어 로봇에 문자열로 전달되지만 숫자/불리언처럼 보이는 고정 값
+func lintSuspiciousParameterTypes(t *lintTarget) []LintFinding {
+	return lintParameters(t, func(param models.ActionParameter) string {
+		if (param.ValueType != "" && param.ValueType != "STRING") || len(Placeholders(param.Value)) > 0 {
+			return ""
+		}
+		if _, err := strconv.ParseFloat(param.Value, 64); err == nil {
+			return fmt.Sprintf("value %q looks like a NUMBER but is declared STRING", param.Value)
+		}
+		if _, err := strconv.ParseBool(param.Value); err == nil {
+			return fmt.Sprintf("value %q looks like a BOOLEAN but is declared STRING", param.Value)
+		}
+		return ""
+	})
+}
+
+// lintParameters 모든 액션 파라미터에 check를 적용하고 문제 설명이 있으면 결과로 변환
+func lintParameters(t *lintTarget, check func(models.ActionParameter) string) []LintFinding {
+	var findings []LintFinding
+	for _, step := range t.template.OrderSteps {
+		for _, mapping := range step.StepActionMappings {
+			for _, param := range mapping.ActionTemplate.Parameters {
+				if problem := check(param); problem != "" {
+					findings = append(findings, LintFinding{
+						Message:   fmt.Sprintf("step %d: %s.%s: %s", step.StepOrder, mapping.ActionTemplate.ActionType, param.Key, problem),
+						StepOrder: step.StepOrder,
+						Field:     param.Key,
+					})
+				}
+			}
+		}
+	}
+	return findings
+}
@@ -0,0 +1,92 @@
+// internal/repository/template_notifications.go
+package repository
+
+import (
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/utils"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OrderFailureStatuses order_finished 알림과 에스컬레이션에서 실패로 보는 오더 실행 상태 (운영자 취소는 제외)
+var OrderFailureStatuses = []string{
+	constants.OrderExecutionStatusFailed,
+	constants.OrderExecutionStatusEStopped,
+	constants.OrderExecutionStatusRejected,
+	constants.OrderExecutionStatusExpired,
+}
+
+// TemplateNotifications 템플릿 오더 종료 알림 설정
+type TemplateNotifications struct {
+	NotifyOn      string `json:"notify_on"`      // FAILURE, ALL, NONE
+	Channel       string `json:"notify_channel"` // slack, email (비어 있으면 모든 채널)
+	EscalateAfter int    `json:"notify_escalate_after"`
+}
+
+// SetTemplateNotifications 템플릿 오더 종료 알림 설정 변경
+// EscalateAfter가 N이면 같은 템플릿의 N번째 연속 실패부터 채널 제한 없이 CRITICAL로 알립니다 (0이면 끔).
+func SetTemplateNotifications(db *gorm.DB, siteID string, templateID uint, settings TemplateNotifications) (*models.OrderTemplate, error) {
+	settings.NotifyOn = strings.ToUpper(strings.TrimSpace(settings.NotifyOn))
+	if settings.NotifyOn == "" {
+		settings.NotifyOn = constants.TemplateNotifyFailure
+	}
+	switch settings.NotifyOn {
+	case constants.TemplateNotifyFailure, constants.TemplateNotifyAll, constants.TemplateNotifyNone:
+	default:
+		return nil, apperr.Validation("notifyOn", "notify_on must be %s, %s or %s, got %q",
+			constants.TemplateNotifyFailure, constants.TemplateNotifyAll, constants.TemplateNotifyNone, settings.NotifyOn)
+	}
+	settings.Channel = strings.ToLower(strings.TrimSpace(settings.Channel))
+	switch settings.Channel {
+	case "", constants.AlertChannelSlack, constants.AlertChannelEmail:
+	default:
+		return nil, apperr.Validation("notifyChannel", "notify channel must be %s or %s (empty for all channels), got %q",
+			constants.AlertChannelSlack, constants.AlertChannelEmail, settings.Channel)
+	}
+	if settings.EscalateAfter < 0 {
+		return nil, apperr.Validation("notifyEscalateAfter", "escalation threshold must be 0 (off) or positive, got %d", settings.EscalateAfter)
+	}
+
+	var template models.OrderTemplate
+	if err := db.Scopes(SiteScope(siteID)).First(&template, templateID).Error; err != nil {
+		return nil, apperr.Wrap(apperr.CodeTemplateNotFound, err, "order template %d not found", templateID)
+	}
+	err := db.Model(&template).Updates(map[string]interface{}{
+		"notify_on":             settings.NotifyOn,
+		"notify_channel":        settings.Channel,
+		"notify_escalate_after": settings.EscalateAfter,
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+	utils.Logger.Infof("Order template %d notifications set to %s (channel %q, escalate after %d)",
+		templateID, settings.NotifyOn, settings.Channel, settings.EscalateAfter)
+	return &template, nil
+}
+
+// ConsecutiveTemplateFailures until 시각까지 끝난 템플릿 오더 중 가장 최근부터 연속으로 실패한 수 (최대 limit)
+// 로봇과 관계없이 사이트 전체 실행을 봅니다.
+func ConsecutiveTemplateFailures(db *gorm.DB, siteID string, templateID uint, until time.Time, limit int) (int, error) {
+	var statuses []string
+	err := db.Model(&models.OrderExecution{}).Scopes(SiteScope(siteID)).
+		Where("template_id = ? AND status IN ? AND completed_at <= ?", templateID,
+			append([]string{constants.OrderExecutionStatusCompleted}, OrderFailureStatuses...), until).
+		Order("completed_at DESC").
+		Limit(limit).
+		Pluck("status", &statuses).Error
+	if err != nil {
+		return 0, err
+	}
+	failures := 0
+	for _, status := range statuses {
+		if status == constants.OrderExecutionStatusCompleted {
+			break
+		}
+		failures++
+	}
+	return failures, nil
+}
@@ -0,0 +1,193 @@
+// internal/repository/template_params.go
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/models"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// placeholderPattern {{name}} 형식의 템플릿 파라미터 자리표시자
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// ParameterValidationError 런타임 파라미터 검증 실패
+type ParameterValidationError struct {
+	Missing []string // 템플릿에서 사용하지만 전달되지 않은 파라미터
+	Unused  []string // 전달되었지만 어느 템플릿에서도 사용하지 않는 파라미터
+	Invalid []string // 타입 변환 실패 (액션 파라미터의 ValueType 기준)
+}
+
+// Error 누락/미사용/타입 오류 파라미터 나열
+func (e *ParameterValidationError) Error() string {
+	var parts []string
+	if len(e.Missing) > 0 {
+		parts = append(parts, "missing: "+strings.Join(e.Missing, ", "))
+	}
+	if len(e.Unused) > 0 {
+		parts = append(parts, "unused: "+strings.Join(e.Unused, ", "))
+	}
+	if len(e.Invalid) > 0 {
+		parts = append(parts, "invalid: "+strings.Join(e.Invalid, ", "))
+	}
+	return "invalid template parameters (" + strings.Join(parts, "; ") + ")"
+}
+
+// ErrorCode 오류 코드 (apperr.Coder)
+func (e *ParameterValidationError) ErrorCode() apperr.Code {
+	return apperr.CodeValidationFailed
+}
+
+// ErrorField 처음 문제가 된 파라미터 이름 (누락 > 타입 오류 > 미사용 순)
+func (e *ParameterValidationError) ErrorField() string {
+	for _, names := range [][]string{e.Missing, e.Invalid, e.Unused} {
+		if len(names) > 0 {
+			return names[0]
+		}
+	}
+	return ""
+}
+
+// Placeholders 문자열에 포함된 자리표시자 이름 목록
+func Placeholders(s string) []string {
+	var names []string
+	for _, match := range placeholderPattern.FindAllStringSubmatch(s, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
+
+// SubstitutePlaceholders 자리표시자를 파라미터 값으로 치환 (값이 없는 자리표시자는 그대로 유지)
+func SubstitutePlaceholders(s string, params map[string]string) string {
+	if len(params) == 0 || !strings.Contains(s, "{{") {
+		return s
+	}
+	return placeholderPattern.ReplaceAllStringFunc(s, func(match string) string {
+		name := placeholderPattern.FindStringSubmatch(match)[1]
+		if value, ok := params[name]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// EncodeParameters 파라미터를 DB 저장용 JSON으로 변환 (비어 있으면 빈 문자열)
+func EncodeParameters(params map[string]string) string {
+	if len(params) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(params)
+	return string(data)
+}
+
+// DecodeParameters DB에 저장된 JSON 파라미터 복원
+func DecodeParameters(data string) map[string]string {
+	if data == "" {
+		return nil
+	}
+	var params map[string]string
+	if err := json.Unmarshal([]byte(data), &params); err != nil {
+		return nil
+	}
+	return params
+}
+
+// ValidateTemplateParameters 템플릿들의 자리표시자와 전달된 파라미터를 비교 검증합니다.
+// 액션 파라미터 값과 노드 설명의 자리표시자를 대상으로 하며, NUMBER/BOOLEAN 타입 액션 파라미터는
+// 치환 후 값이 해당 타입으로 변환되는지 확인합니다.
+// OrderSteps.NodeTemplate, OrderSteps.StepActionMappings.ActionTemplate.Parameters가 preload되어 있어야 합니다.
+func ValidateTemplateParameters(templates []models.OrderTemplate, params map[string]string) error {
+	used := make(map[string]bool)
+	missing := make(map[string]bool)
+	invalid := make(map[string]bool)
+
+	check := func(value string) {
+		for _, name := range Placeholders(value) {
+			used[name] = true
+			if _, ok := params[name]; !ok {
+				missing[name] = true
+			}
+		}
+	}
+
+	for _, template := range templates {
+		for _, step := range template.OrderSteps {
+			if step.NodeTemplate != nil {
+				check(step.NodeTemplate.Description)
+			}
+			for _, mapping := range step.StepActionMappings {
+				for _, param := range mapping.ActionTemplate.Parameters {
+					check(param.Value)
+					if len(Placeholders(param.Value)) == 0 {
+						continue
+					}
+					if err := coerceParameter(param.ValueType, SubstitutePlaceholders(param.Value, params)); err != nil {
+						invalid[fmt.Sprintf("%s.%s (%v)", mapping.ActionTemplate.ActionType, param.Key, err)] = true
+					}
+				}
+			}
+		}
+	}
+
+	unused := make(map[string]bool)
+	for name := range params {
+		if !used[name] {
+			unused[name] = true
+		}
+	}
+
+	// 누락된 파라미터는 타입 오류로 중복 보고하지 않음
+	if len(missing) > 0 {
+		invalid = nil
+	}
+	if len(missing) == 0 && len(unused) == 0 && len(invalid) == 0 {
+		return nil
+	}
+	return &ParameterValidationError{
+		Missing: sortedKeys(missing),
+		Unused:  sortedKeys(unused),
+		Invalid: sortedKeys(invalid),
+	}
+}
+
+// ValidateCommandParameters 명령에 매핑된 모든 활성 템플릿 기준으로 파라미터 검증
+func ValidateCommandParameters(db *gorm.DB, commandDefinitionID uint, params map[string]string) error {
+	var mappings []models.CommandOrderMapping
+	if err := db.Where("command_definition_id = ? AND is_active = ?", commandDefinitionID, true).
+		Preload("Template.OrderSteps.NodeTemplate").
+		Preload("Template.OrderSteps.StepActionMappings.ActionTemplate.Parameters").
+		Find(&mappings).Error; err != nil {
+		return err
+	}
+
+	templates := make([]models.OrderTemplate, 0, len(mappings))
+	for _, mapping := range mappings {
+		// 합성 템플릿은 하위 템플릿의 자리표시자까지 포함하여 검증
+		expanded, err := ExpandTemplate(db, mapping.Template.SiteID, &mapping.Template)
+		if err != nil {
+			return err
+		}
+		templates = append(templates, *expanded)
+	}
+	return ValidateTemplateParameters(templates, params)
+}
+
+// coerceParameter 값이 액션 파라미터 타입으로 변환 가능한지 확인
+func coerceParameter(valueType, value string) error {
+	switch valueType {
+	case "NUMBER":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+	case "BOOLEAN":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%q is not a boolean", value)
+		}
+	}
+	return nil
+}
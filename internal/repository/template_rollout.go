@@ -0,0 +1,337 @@
+// internal/repository/template_rollout.go
+package repository
+
+import (
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/utils"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TemplateRunStats 롤아웃 시작 이후 템플릿의 오더 실행 결과
+type TemplateRunStats struct {
+	TemplateID  uint    `json:"template_id"`
+	Name        string  `json:"name"`
+	Status      string  `json:"status"`
+	Total       int     `json:"total"`
+	Succeeded   int     `json:"succeeded"`
+	Failed      int     `json:"failed"`
+	SuccessRate float64 `json:"success_rate"` // 종료된 오더 중 성공 비율 (0~1)
+}
+
+// TemplateRolloutReport 롤아웃과 기존/후보 버전의 실행 결과 비교
+type TemplateRolloutReport struct {
+	Rollout   models.TemplateRollout `json:"rollout"`
+	Base      TemplateRunStats       `json:"base"`
+	Candidate TemplateRunStats       `json:"candidate"`
+}
+
+// SetOrderTemplateStatus 템플릿 활성화 상태를 직접 변경합니다.
+// 진행 중인 롤아웃에 포함된 템플릿은 승격/롤백으로만 상태가 바뀝니다.
+func SetOrderTemplateStatus(db *gorm.DB, siteID string, templateID uint, status string) error {
+	switch status {
+	case constants.TemplateStatusDraft, constants.TemplateStatusActive, constants.TemplateStatusDeprecated:
+	default:
+		return apperr.Validation("status", "invalid template status %q (DRAFT, ACTIVE, DEPRECATED)", status)
+	}
+
+	var template models.OrderTemplate
+	if err := db.Scopes(SiteScope(siteID)).First(&template, templateID).Error; err != nil {
+		return apperr.Wrap(apperr.CodeTemplateNotFound, err, "order template %d not found", templateID)
+	}
+	if rollout, err := activeRolloutFor(db, siteID, templateID); err != nil {
+		return err
+	} else if rollout != nil {
+		return apperr.New(apperr.CodeValidationFailed,
+			"order template %d is part of canary rollout %d; promote or roll it back instead", templateID, rollout.ID)
+	}
+
+	if err := db.Model(&template).Update("status", status).Error; err != nil {
+		return err
+	}
+	utils.Logger.Infof("Order template %d status set to %s", templateID, status)
+	return nil
+}
+
+// StartTemplateCanary ACTIVE 템플릿의 새 버전(DRAFT)을 지정한 로봇에만 먼저 적용하는 롤아웃을 시작합니다.
+func StartTemplateCanary(db *gorm.DB, siteID string, baseID, candidateID uint, robots []string) (*models.TemplateRollout, error) {
+	robots = normalizeRobotList(robots)
+	if len(robots) == 0 {
+		return nil, apperr.Validation("canaryRobots", "at least one canary robot is required")
+	}
+	if baseID == candidateID {
+		return nil, apperr.Validation("candidateTemplateID", "candidate template must differ from the base template")
+	}
+
+	base, err := LoadTemplateDetail(db, siteID, baseID)
+	if err != nil {
+		return nil, apperr.Wrap(apperr.CodeTemplateNotFound, err, "order template %d not found", baseID)
+	}
+	if base.Status != constants.TemplateStatusActive {
+		return nil, apperr.Validation("baseTemplateID", "base template %d is %s, not ACTIVE", baseID, base.Status)
+	}
+	candidate, err := LoadTemplateDetail(db, siteID, candidateID)
+	if err != nil {
+		return nil, apperr.Wrap(apperr.CodeTemplateNotFound, err, "order template %d not found", candidateID)
+	}
+	if candidate.Status != constants.TemplateStatusDraft {
+		return nil, apperr.Validation("candidateTemplateID", "candidate template %d is %s, not DRAFT", candidateID, candidate.Status)
+	}
+	if err := ValidateTemplateGraph(candidate); err != nil {
+		return nil, err
+	}
+	if err := ValidateStepConditions(candidate); err != nil {
+		return nil, err
+	}
+	if _, err := ExpandTemplate(db, siteID, candidate); err != nil {
+		return nil, err
+	}
+
+	for _, id := range []uint{baseID, candidateID} {
+		rollout, err := activeRolloutFor(db, siteID, id)
+		if err != nil {
+			return nil, err
+		}
+		if rollout != nil {
+			return nil, apperr.New(apperr.CodeValidationFailed, "order template %d is already part of canary rollout %d", id, rollout.ID)
+		}
+	}
+
+	rollout := &models.TemplateRollout{
+		SiteID:              siteID,
+		BaseTemplateID:      baseID,
+		CandidateTemplateID: candidateID,
+		CanaryRobots:        strings.Join(robots, ","),
+		Status:              constants.TemplateRolloutStatusCanary,
+		StartedAt:           time.Now(),
+	}
+	if err := db.Create(rollout).Error; err != nil {
+		return nil, err
+	}
+	utils.Logger.Infof("🐤 Canary rollout %d started: template %d -> %d on robots %s",
+		rollout.ID, baseID, candidateID, rollout.CanaryRobots)
+	return rollout, nil
+}
+
+// ListTemplateRollouts 사이트의 롤아웃 목록 (최근 순)
+func ListTemplateRollouts(db *gorm.DB, siteID string) ([]models.TemplateRollout, error) {
+	var rollouts []models.TemplateRollout
+	err := db.Scopes(SiteScope(siteID)).Order("id DESC").Find(&rollouts).Error
+	return rollouts, err
+}
+
+// FindTemplateRollout 롤아웃 조회
+func FindTemplateRollout(db *gorm.DB, siteID string, rolloutID uint) (*models.TemplateRollout, error) {
+	var rollout models.TemplateRollout
+	if err := db.Scopes(SiteScope(siteID)).First(&rollout, rolloutID).Error; err != nil {
+		return nil, apperr.Wrap(apperr.CodeNotFound, err, "template rollout %d not found", rolloutID)
+	}
+	return &rollout, nil
+}
+
+// ComputeTemplateRolloutReport 롤아웃 시작(종료된 롤아웃은 종료 시점까지) 이후 기존/후보 버전의 오더 성공률
+func ComputeTemplateRolloutReport(db *gorm.DB, siteID string, rollout *models.TemplateRollout) (*TemplateRolloutReport, error) {
+	until := time.Now()
+	if rollout.FinishedAt != nil {
+		until = *rollout.FinishedAt
+	}
+	report := &TemplateRolloutReport{Rollout: *rollout}
+	var err error
+	if report.Base, err = templateRunStats(db, siteID, rollout.BaseTemplateID, rollout.StartedAt, until); err != nil {
+		return nil, err
+	}
+	if report.Candidate, err = templateRunStats(db, siteID, rollout.CandidateTemplateID, rollout.StartedAt, until); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// PromoteTemplateRollout 후보 버전을 ACTIVE로, 기존 버전을 DEPRECATED로 바꾸고
+// 기존 버전을 가리키던 명령 매핑과 하위 템플릿 참조를 후보 버전으로 옮깁니다.
+// force가 아니면 종료된 카나리 오더가 있고 성공률이 기존 버전 이상일 때만 승격합니다.
+func PromoteTemplateRollout(db *gorm.DB, siteID string, rolloutID uint, force bool) (*TemplateRolloutReport, error) {
+	rollout, err := findCanaryRollout(db, siteID, rolloutID)
+	if err != nil {
+		return nil, err
+	}
+	report, err := ComputeTemplateRolloutReport(db, siteID, rollout)
+	if err != nil {
+		return nil, err
+	}
+	if !force {
+		candidateFinished := report.Candidate.Succeeded + report.Candidate.Failed
+		baseFinished := report.Base.Succeeded + report.Base.Failed
+		if candidateFinished == 0 {
+			return report, apperr.New(apperr.CodeValidationFailed,
+				"rollout %d has no finished canary orders yet; use force to promote anyway", rolloutID)
+		}
+		if baseFinished > 0 && report.Candidate.SuccessRate < report.Base.SuccessRate {
+			return report, apperr.New(apperr.CodeValidationFailed,
+				"canary success rate %.1f%% is below base %.1f%%; use force to promote anyway",
+				report.Candidate.SuccessRate*100, report.Base.SuccessRate*100)
+		}
+	}
+
+	now := time.Now()
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.OrderTemplate{}).Where("id = ?", rollout.CandidateTemplateID).
+			Update("status", constants.TemplateStatusActive).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.OrderTemplate{}).Where("id = ?", rollout.BaseTemplateID).
+			Update("status", constants.TemplateStatusDeprecated).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.CommandOrderMapping{}).Where("template_id = ?", rollout.BaseTemplateID).
+			Update("template_id", rollout.CandidateTemplateID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.OrderStep{}).
+			Where("sub_template_id = ? AND template_id <> ?", rollout.BaseTemplateID, rollout.CandidateTemplateID).
+			Update("sub_template_id", rollout.CandidateTemplateID).Error; err != nil {
+			return err
+		}
+		return tx.Model(rollout).Updates(map[string]interface{}{
+			"status":      constants.TemplateRolloutStatusPromoted,
+			"finished_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rollout.Status = constants.TemplateRolloutStatusPromoted
+	rollout.FinishedAt = &now
+	report.Rollout = *rollout
+	report.Base.Status = constants.TemplateStatusDeprecated
+	report.Candidate.Status = constants.TemplateStatusActive
+	utils.Logger.Infof("🐤 Canary rollout %d promoted: template %d replaces %d", rolloutID, rollout.CandidateTemplateID, rollout.BaseTemplateID)
+	return report, nil
+}
+
+// RollbackTemplateRollout 롤아웃을 중단하여 모든 로봇이 기존 버전을 사용하게 합니다 (후보는 DRAFT로 남음).
+func RollbackTemplateRollout(db *gorm.DB, siteID string, rolloutID uint) (*TemplateRolloutReport, error) {
+	rollout, err := findCanaryRollout(db, siteID, rolloutID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := db.Model(rollout).Updates(map[string]interface{}{
+		"status":      constants.TemplateRolloutStatusRolledBack,
+		"finished_at": now,
+	}).Error; err != nil {
+		return nil, err
+	}
+	rollout.Status = constants.TemplateRolloutStatusRolledBack
+	rollout.FinishedAt = &now
+	utils.Logger.Infof("🐤 Canary rollout %d rolled back: robots %s return to template %d",
+		rolloutID, rollout.CanaryRobots, rollout.BaseTemplateID)
+	return ComputeTemplateRolloutReport(db, siteID, rollout)
+}
+
+// ResolveTemplateForRobot 진행 중인 롤아웃의 카나리 로봇이면 후보 템플릿 ID와 롤아웃을 반환합니다.
+// 대상이 아니면 templateID와 nil을 그대로 반환합니다.
+func ResolveTemplateForRobot(db *gorm.DB, siteID string, templateID uint, serialNumber string) (uint, *models.TemplateRollout, error) {
+	var rollout models.TemplateRollout
+	err := db.Scopes(SiteScope(siteID)).
+		Where("base_template_id = ? AND status = ?", templateID, constants.TemplateRolloutStatusCanary).
+		First(&rollout).Error
+	if err == gorm.ErrRecordNotFound {
+		return templateID, nil, nil
+	}
+	if err != nil {
+		return templateID, nil, err
+	}
+	for _, robot := range CanaryRobotList(&rollout) {
+		if robot == serialNumber {
+			return rollout.CandidateTemplateID, &rollout, nil
+		}
+	}
+	return templateID, nil, nil
+}
+
+// CanaryRobotList 롤아웃의 카나리 로봇 목록
+func CanaryRobotList(rollout *models.TemplateRollout) []string {
+	return normalizeRobotList(strings.Split(rollout.CanaryRobots, ","))
+}
+
+func normalizeRobotList(robots []string) []string {
+	seen := make(map[string]bool, len(robots))
+	result := make([]string, 0, len(robots))
+	for _, robot := range robots {
+		if robot = strings.TrimSpace(robot); robot != "" && !seen[robot] {
+			seen[robot] = true
+			result = append(result, robot)
+		}
+	}
+	return result
+}
+
+// findCanaryRollout 진행 중인 롤아웃 조회 (이미 종료되었으면 오류)
+func findCanaryRollout(db *gorm.DB, siteID string, rolloutID uint) (*models.TemplateRollout, error) {
+	rollout, err := FindTemplateRollout(db, siteID, rolloutID)
+	if err != nil {
+		return nil, err
+	}
+	if rollout.Status != constants.TemplateRolloutStatusCanary {
+		return nil, apperr.New(apperr.CodeValidationFailed, "template rollout %d is already %s", rolloutID, rollout.Status)
+	}
+	return rollout, nil
+}
+
+// activeRolloutFor 템플릿이 기존 또는 후보 버전으로 포함된 진행 중인 롤아웃 (없으면 nil)
+func activeRolloutFor(db *gorm.DB, siteID string, templateID uint) (*models.TemplateRollout, error) {
+	var rollout models.TemplateRollout
+	err := db.Scopes(SiteScope(siteID)).
+		Where("status = ? AND (base_template_id = ? OR candidate_template_id = ?)",
+			constants.TemplateRolloutStatusCanary, templateID, templateID).
+		First(&rollout).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rollout, nil
+}
+
+// templateRunStats [from, until) 기간에 시작된 템플릿 오더의 결과 집계
+func templateRunStats(db *gorm.DB, siteID string, templateID uint, from, until time.Time) (TemplateRunStats, error) {
+	stats := TemplateRunStats{TemplateID: templateID}
+	var template models.OrderTemplate
+	if err := db.Unscoped().Scopes(SiteScope(siteID)).First(&template, templateID).Error; err == nil {
+		stats.Name = template.Name
+		stats.Status = template.Status
+	}
+
+	var rows []struct {
+		Status string
+		Count  int
+	}
+	err := db.Model(&models.OrderExecution{}).Scopes(SiteScope(siteID)).
+		Select("status, COUNT(*) AS count").
+		Where("template_id = ? AND started_at >= ? AND started_at < ?", templateID, from, until).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return stats, err
+	}
+	for _, row := range rows {
+		stats.Total += row.Count
+		switch row.Status {
+		case constants.OrderExecutionStatusCompleted:
+			stats.Succeeded += row.Count
+		case constants.OrderExecutionStatusFailed:
+			stats.Failed += row.Count
+		}
+	}
+	if finished := stats.Succeeded + stats.Failed; finished > 0 {
+		stats.SuccessRate = float64(stats.Succeeded) / float64(finished)
+	}
+	return stats, nil
+}
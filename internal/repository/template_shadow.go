@@ -0,0 +1,117 @@
+// internal/repository/template_shadow.go
+package repository
+
+import (
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// ShadowCompareReport 템플릿과 섀도 템플릿의 실행별 오더 메시지 비교 요약
+type ShadowCompareReport struct {
+	TemplateID       uint                      `json:"template_id"`
+	ShadowTemplateID uint                      `json:"shadow_template_id"`
+	Active           bool                      `json:"active"`      // 현재 섀도 실행 중인지
+	Executions       int                       `json:"executions"`  // 비교한 실행 수 (조회 범위 안)
+	Identical        int                       `json:"identical"`   // 차이가 없는 실행 수
+	Divergent        int                       `json:"divergent"`   // 차이가 있거나 섀도 실행이 실패한 실행 수
+	Comparisons      []models.ShadowComparison `json:"comparisons"` // 최신순
+}
+
+// SetTemplateShadow 템플릿 오더가 끝날 때마다 섀도 템플릿을 드라이런하여 비교하도록 설정 (기존 설정은 교체)
+func SetTemplateShadow(db *gorm.DB, siteID string, templateID, shadowTemplateID uint) (*models.TemplateShadow, error) {
+	if templateID == shadowTemplateID {
+		return nil, apperr.Validation("shadowTemplateID", "a template cannot shadow itself")
+	}
+	for _, id := range []uint{templateID, shadowTemplateID} {
+		var template models.OrderTemplate
+		if err := db.Scopes(SiteScope(siteID)).Select("id").First(&template, id).Error; err != nil {
+			return nil, apperr.Wrap(apperr.CodeTemplateNotFound, err, "order template %d not found", id)
+		}
+	}
+
+	var shadow models.TemplateShadow
+	err := db.Scopes(SiteScope(siteID)).Where("template_id = ?", templateID).
+		Attrs(models.TemplateShadow{SiteID: siteID, TemplateID: templateID}).
+		FirstOrInit(&shadow).Error
+	if err != nil {
+		return nil, err
+	}
+	shadow.ShadowTemplateID = shadowTemplateID
+	if err := db.Save(&shadow).Error; err != nil {
+		return nil, err
+	}
+	utils.Logger.Infof("👥 Order template %d is shadowed by template %d", templateID, shadowTemplateID)
+	return &shadow, nil
+}
+
+// RemoveTemplateShadow 템플릿의 섀도 실행 중지 (비교 기록은 유지)
+func RemoveTemplateShadow(db *gorm.DB, siteID string, templateID uint) error {
+	result := db.Scopes(SiteScope(siteID)).Where("template_id = ?", templateID).Delete(&models.TemplateShadow{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperr.New(apperr.CodeNotFound, "order template %d has no shadow template", templateID).WithField("templateID")
+	}
+	utils.Logger.Infof("👥 Shadow execution of order template %d stopped", templateID)
+	return nil
+}
+
+// FindTemplateShadow 템플릿의 섀도 설정 (없으면 nil)
+func FindTemplateShadow(db *gorm.DB, siteID string, templateID uint) (*models.TemplateShadow, error) {
+	var shadows []models.TemplateShadow
+	if err := db.Scopes(SiteScope(siteID)).Where("template_id = ?", templateID).Limit(1).Find(&shadows).Error; err != nil {
+		return nil, err
+	}
+	if len(shadows) == 0 {
+		return nil, nil
+	}
+	return &shadows[0], nil
+}
+
+// ListTemplateShadows 사이트의 섀도 설정 목록
+func ListTemplateShadows(db *gorm.DB, siteID string) ([]models.TemplateShadow, error) {
+	var shadows []models.TemplateShadow
+	err := db.Scopes(SiteScope(siteID)).Order("template_id ASC").Find(&shadows).Error
+	return shadows, err
+}
+
+// CompareTemplateShadow 템플릿 실행과 섀도 템플릿 드라이런의 최근 비교 결과 (최대 limit건, 최신순)
+func CompareTemplateShadow(db *gorm.DB, siteID string, templateID, shadowTemplateID uint, limit int) (*ShadowCompareReport, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	report := &ShadowCompareReport{
+		TemplateID:       templateID,
+		ShadowTemplateID: shadowTemplateID,
+		Comparisons:      make([]models.ShadowComparison, 0),
+	}
+	shadow, err := FindTemplateShadow(db, siteID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	report.Active = shadow != nil && shadow.ShadowTemplateID == shadowTemplateID
+
+	if err := db.Scopes(SiteScope(siteID)).
+		Where("template_id = ? AND shadow_template_id = ?", templateID, shadowTemplateID).
+		Order("id DESC").Limit(limit).
+		Find(&report.Comparisons).Error; err != nil {
+		return nil, err
+	}
+	if !report.Active && len(report.Comparisons) == 0 {
+		return nil, apperr.New(apperr.CodeNotFound, "order template %d has never been shadowed by template %d",
+			templateID, shadowTemplateID).WithField("otherID")
+	}
+	for _, comparison := range report.Comparisons {
+		report.Executions++
+		if comparison.DiffCount == 0 && comparison.ShadowError == "" {
+			report.Identical++
+		} else {
+			report.Divergent++
+		}
+	}
+	return report, nil
+}
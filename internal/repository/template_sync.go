@@ -0,0 +1,623 @@
+// internal/repository/template_sync.go
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/common/validate"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/utils"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 동기화 변경 대상 종류
+const (
+	SyncKindTemplate = "template" // 오더 템플릿 (단계의 노드/엣지/액션 템플릿 포함)
+	SyncKindCommand  = "command"  // 이 사이트 템플릿으로 가는 PLC 명령 오더 매핑
+)
+
+// 동기화 변경 종류
+const (
+	SyncActionAdd    = "add"    // 원본에만 있음 (생성)
+	SyncActionUpdate = "update" // 양쪽에 있고 내용이 다름 (원본 내용으로 교체)
+	SyncActionRemove = "remove" // 이쪽에만 있음 (템플릿은 보관, 명령은 이 사이트 매핑 삭제)
+)
+
+// SyncSnapshot 다른 브릿지와 비교하는 템플릿과 PLC 매핑 (DB ID 없이 템플릿 이름과 명령 유형 기준)
+type SyncSnapshot struct {
+	SiteID      string                 `json:"site_id"`
+	GeneratedAt time.Time              `json:"generated_at"`
+	Templates   []TemplateExport       `json:"templates"`
+	Commands    []CommandMappingExport `json:"commands"` // 이 사이트 템플릿에 매핑된 활성 명령만
+}
+
+// CommandMappingExport PLC 명령과 이 사이트 템플릿으로 가는 오더 매핑 내보내기 형식
+// 명령 정의는 모든 사이트가 함께 쓰므로 Description/ReportProgress는 이쪽에 정의가 없을 때 만드는 데에만 쓰고 비교하지 않습니다.
+type CommandMappingExport struct {
+	CommandType    string               `json:"command_type"`
+	Description    string               `json:"description"`
+	ReportProgress bool                 `json:"report_progress"`
+	Orders         []MappingOrderExport `json:"orders"`
+}
+
+// MappingOrderExport 명령의 오더 매핑 하나 (템플릿은 이름으로 참조)
+type MappingOrderExport struct {
+	ExecutionOrder     int    `json:"execution_order"`
+	Template           string `json:"template"`
+	NextExecutionOrder int    `json:"next_execution_order"`
+	FailureOrder       int    `json:"failure_order"`
+	IsActive           bool   `json:"is_active"`
+}
+
+// SyncFieldChange 바뀌는 항목 하나 (JSON 경로, 이쪽 값, 원본 값)
+type SyncFieldChange struct {
+	Path string      `json:"path"`
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// SyncChange 원본과 다른 템플릿 또는 명령 하나
+type SyncChange struct {
+	Key    string            `json:"key"` // template:<이름>, command:<명령 유형> (적용할 변경 선택에 사용)
+	Kind   string            `json:"kind"`
+	Name   string            `json:"name"`
+	Action string            `json:"action"`
+	Fields []SyncFieldChange `json:"fields,omitempty"` // update일 때 바뀌는 항목
+}
+
+// SyncDiff 원본 브릿지와의 차이
+type SyncDiff struct {
+	Source    string       `json:"source,omitempty"`
+	Changes   []SyncChange `json:"changes"`
+	Unchanged int          `json:"unchanged"`
+}
+
+// SyncApplyResult 적용한 변경 (DryRun이면 검증만 하고 되돌림)
+type SyncApplyResult struct {
+	Applied []SyncChange `json:"applied"`
+	DryRun  bool         `json:"dry_run"`
+}
+
+// errSyncDryRun dry run 트랜잭션을 되돌리기 위한 오류
+var errSyncDryRun = errors.New("template sync dry run")
+
+// ExportSyncSnapshot 사이트의 보관되지 않은 템플릿과 그 템플릿에 매핑된 활성 명령 (이름 순)
+func ExportSyncSnapshot(db *gorm.DB, siteID string) (*SyncSnapshot, error) {
+	templates, err := ExportOrderTemplates(db, siteID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+
+	// 명령 정의는 사이트 구분이 없으므로 이 사이트 템플릿으로 가는 매핑이 있는 정의만 비교
+	siteMappings := db.Model(&models.CommandOrderMapping{}).
+		Joins("JOIN order_templates ON order_templates.id = command_order_mappings.template_id AND order_templates.deleted_at IS NULL").
+		Where("order_templates.site_id = ?", siteID).
+		Select("command_order_mappings.command_definition_id")
+	var definitions []models.CommandDefinition
+	if err := db.Where("is_active = ? AND id IN (?)", true, siteMappings).Order("command_type").Find(&definitions).Error; err != nil {
+		return nil, err
+	}
+	commands := make([]CommandMappingExport, 0, len(definitions))
+	for _, definition := range definitions {
+		var mappings []models.CommandOrderMapping
+		if err := db.Preload("Template").
+			Where("command_definition_id = ?", definition.ID).
+			Order("execution_order").
+			Find(&mappings).Error; err != nil {
+			return nil, err
+		}
+		command := CommandMappingExport{
+			CommandType:    definition.CommandType,
+			Description:    definition.Description,
+			ReportProgress: definition.ReportProgress,
+			Orders:         make([]MappingOrderExport, 0, len(mappings)),
+		}
+		for _, mapping := range mappings {
+			// 다른 사이트나 보관된 템플릿을 가리키는 매핑은 비교하지 않음
+			if mapping.Template.ID == 0 || mapping.Template.SiteID != siteID {
+				continue
+			}
+			command.Orders = append(command.Orders, MappingOrderExport{
+				ExecutionOrder:     mapping.ExecutionOrder,
+				Template:           mapping.Template.Name,
+				NextExecutionOrder: mapping.NextExecutionOrder,
+				FailureOrder:       mapping.FailureOrder,
+				IsActive:           mapping.IsActive,
+			})
+		}
+		commands = append(commands, command)
+	}
+
+	return &SyncSnapshot{SiteID: siteID, GeneratedAt: time.Now(), Templates: templates, Commands: commands}, nil
+}
+
+// DiffSyncSnapshots 이쪽(local)을 원본(remote)과 같게 만들기 위한 변경 목록 (템플릿 먼저, 종류 안에서는 이름 순)
+func DiffSyncSnapshots(local, remote *SyncSnapshot) (*SyncDiff, error) {
+	diff := &SyncDiff{Changes: []SyncChange{}}
+
+	localTemplates := make(map[string]TemplateExport, len(local.Templates))
+	for _, template := range local.Templates {
+		localTemplates[template.Name] = template
+	}
+	remoteTemplates := make(map[string]TemplateExport, len(remote.Templates))
+	for _, template := range remote.Templates {
+		remoteTemplates[template.Name] = template
+	}
+	localCommands := make(map[string]CommandMappingExport, len(local.Commands))
+	for _, command := range local.Commands {
+		localCommands[command.CommandType] = command
+	}
+	remoteCommands := make(map[string]CommandMappingExport, len(remote.Commands))
+	for _, command := range remote.Commands {
+		remoteCommands[command.CommandType] = command
+	}
+
+	for _, name := range unionKeys(localTemplates, remoteTemplates) {
+		localTemplate, inLocal := localTemplates[name]
+		remoteTemplate, inRemote := remoteTemplates[name]
+		change, err := diffSyncItem(SyncKindTemplate, name, localTemplate, remoteTemplate, inLocal, inRemote)
+		if err != nil {
+			return nil, err
+		}
+		if change == nil {
+			diff.Unchanged++
+			continue
+		}
+		diff.Changes = append(diff.Changes, *change)
+	}
+	for _, commandType := range unionKeys(localCommands, remoteCommands) {
+		localCommand, inLocal := localCommands[commandType]
+		remoteCommand, inRemote := remoteCommands[commandType]
+		change, err := diffSyncItem(SyncKindCommand, commandType, localCommand.mappings(), remoteCommand.mappings(), inLocal, inRemote)
+		if err != nil {
+			return nil, err
+		}
+		if change == nil {
+			diff.Unchanged++
+			continue
+		}
+		diff.Changes = append(diff.Changes, *change)
+	}
+	return diff, nil
+}
+
+// mappings 명령에서 비교하는 부분 (사이트별 오더 매핑만, 공용 정의 설명은 제외)
+func (c CommandMappingExport) mappings() interface{} {
+	return struct {
+		Orders []MappingOrderExport `json:"orders"`
+	}{c.Orders}
+}
+
+// diffSyncItem 항목 하나 비교 (같으면 nil)
+func diffSyncItem(kind, name string, local, remote interface{}, inLocal, inRemote bool) (*SyncChange, error) {
+	change := &SyncChange{Key: kind + ":" + name, Kind: kind, Name: name}
+	switch {
+	case !inLocal:
+		change.Action = SyncActionAdd
+	case !inRemote:
+		change.Action = SyncActionRemove
+	default:
+		fields, err := diffJSONFields(local, remote)
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) == 0 {
+			return nil, nil
+		}
+		change.Action = SyncActionUpdate
+		change.Fields = fields
+	}
+	return change, nil
+}
+
+// diffJSONFields 두 값을 JSON 경로별로 펼쳐 다른 항목만 반환 (경로 순)
+func diffJSONFields(local, remote interface{}) ([]SyncFieldChange, error) {
+	localFields, err := flattenJSON(local)
+	if err != nil {
+		return nil, err
+	}
+	remoteFields, err := flattenJSON(remote)
+	if err != nil {
+		return nil, err
+	}
+	var changes []SyncFieldChange
+	for _, path := range unionKeys(localFields, remoteFields) {
+		from, to := localFields[path], remoteFields[path]
+		if !reflect.DeepEqual(from, to) {
+			changes = append(changes, SyncFieldChange{Path: path, From: from, To: to})
+		}
+	}
+	return changes, nil
+}
+
+// flattenJSON 값을 JSON으로 바꿔 "steps[0].node.x" 같은 경로별 값으로 펼침 (null은 없는 값으로 봄)
+func flattenJSON(value interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	fields := make(map[string]interface{})
+	var walk func(path string, v interface{})
+	walk = func(path string, v interface{}) {
+		switch typed := v.(type) {
+		case map[string]interface{}:
+			for key, child := range typed {
+				if path == "" {
+					walk(key, child)
+				} else {
+					walk(path+"."+key, child)
+				}
+			}
+		case []interface{}:
+			for i, child := range typed {
+				walk(fmt.Sprintf("%s[%d]", path, i), child)
+			}
+		case nil:
+		default:
+			fields[path] = typed
+		}
+	}
+	walk("", decoded)
+	return fields, nil
+}
+
+// unionKeys 두 맵의 키 합집합 (정렬)
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ApplySyncChanges 원본 스냅샷과의 차이 중 keys로 고른 변경을 한 트랜잭션으로 적용합니다.
+// 템플릿 생성(하위 템플릿 먼저), 템플릿 교체, 명령 매핑, 템플릿 보관 순으로 적용하며 하나라도 실패하면 모두 되돌립니다.
+// 실행 중인 오더나 진행 중인 롤아웃이 있는 템플릿은 교체/보관하지 않습니다. dryRun이면 적용해 본 뒤 되돌립니다.
+func ApplySyncChanges(db *gorm.DB, siteID string, remote *SyncSnapshot, keys []string, dryRun bool) (*SyncApplyResult, error) {
+	if len(keys) == 0 {
+		return nil, apperr.Validation("changes", "select at least one change to apply")
+	}
+	local, err := ExportSyncSnapshot(db, siteID)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := DiffSyncSnapshots(local, remote)
+	if err != nil {
+		return nil, err
+	}
+	pending := make(map[string]SyncChange, len(diff.Changes))
+	for _, change := range diff.Changes {
+		pending[change.Key] = change
+	}
+	selected := make(map[string]SyncChange, len(keys))
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		change, ok := pending[key]
+		if !ok {
+			return nil, apperr.Validation("changes", "no pending change %q (already in sync or unknown)", key)
+		}
+		selected[key] = change
+	}
+
+	remoteTemplates := make(map[string]TemplateExport, len(remote.Templates))
+	for _, template := range remote.Templates {
+		remoteTemplates[template.Name] = template
+	}
+	remoteCommands := make(map[string]CommandMappingExport, len(remote.Commands))
+	for _, command := range remote.Commands {
+		remoteCommands[command.CommandType] = command
+	}
+
+	result := &SyncApplyResult{Applied: []SyncChange{}, DryRun: dryRun}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var adds []TemplateExport
+		for _, change := range diff.Changes {
+			if _, ok := selected[change.Key]; ok && change.Kind == SyncKindTemplate && change.Action == SyncActionAdd {
+				adds = append(adds, remoteTemplates[change.Name])
+			}
+		}
+		for _, export := range orderTemplateAdds(adds) {
+			if err := addSyncedTemplate(tx, siteID, export); err != nil {
+				return fmt.Errorf("%s:%s: %w", SyncKindTemplate, export.Name, err)
+			}
+			result.Applied = append(result.Applied, selected[SyncKindTemplate+":"+export.Name])
+		}
+
+		for _, step := range []struct {
+			kind, action string
+			apply        func(name string) error
+		}{
+			{SyncKindTemplate, SyncActionUpdate, func(name string) error { return replaceOrderTemplate(tx, siteID, remoteTemplates[name]) }},
+			{SyncKindCommand, SyncActionAdd, func(name string) error { return applyCommandMapping(tx, siteID, remoteCommands[name]) }},
+			{SyncKindCommand, SyncActionUpdate, func(name string) error { return applyCommandMapping(tx, siteID, remoteCommands[name]) }},
+			{SyncKindCommand, SyncActionRemove, func(name string) error { return removeCommandMappings(tx, siteID, name) }},
+			{SyncKindTemplate, SyncActionRemove, func(name string) error { return archiveSyncedTemplate(tx, siteID, name) }},
+		} {
+			for _, change := range diff.Changes {
+				if _, ok := selected[change.Key]; !ok || change.Kind != step.kind || change.Action != step.action {
+					continue
+				}
+				if err := step.apply(change.Name); err != nil {
+					return fmt.Errorf("%s: %w", change.Key, err)
+				}
+				result.Applied = append(result.Applied, change)
+			}
+		}
+		if dryRun {
+			return errSyncDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errSyncDryRun) {
+		return nil, err
+	}
+	if !dryRun {
+		utils.Logger.Infof("🔄 Applied %d template sync change(s) from site %s", len(result.Applied), remote.SiteID)
+	}
+	return result, nil
+}
+
+// orderTemplateAdds 새로 만들 템플릿을 하위 템플릿이 먼저 오도록 정렬 (순환이면 남은 순서대로)
+func orderTemplateAdds(adds []TemplateExport) []TemplateExport {
+	pending := make(map[string]bool, len(adds))
+	for _, export := range adds {
+		pending[export.Name] = true
+	}
+	ordered := make([]TemplateExport, 0, len(adds))
+	for len(ordered) < len(adds) {
+		progressed := false
+		for _, export := range adds {
+			if !pending[export.Name] {
+				continue
+			}
+			ready := true
+			for _, step := range export.Steps {
+				if step.SubTemplate != "" && step.SubTemplate != export.Name && pending[step.SubTemplate] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				ordered = append(ordered, export)
+				pending[export.Name] = false
+				progressed = true
+			}
+		}
+		if !progressed {
+			for _, export := range adds {
+				if pending[export.Name] {
+					ordered = append(ordered, export)
+					pending[export.Name] = false
+				}
+			}
+		}
+	}
+	return ordered
+}
+
+// addSyncedTemplate 원본에만 있는 템플릿 생성 (같은 이름이 보관되어 있으면 복구해서 원본 내용으로 교체, 이름은 보관된 템플릿과도 겹칠 수 없음)
+func addSyncedTemplate(tx *gorm.DB, siteID string, export TemplateExport) error {
+	var archived models.OrderTemplate
+	err := tx.Unscoped().Scopes(SiteScope(siteID)).Where("name = ? AND deleted_at IS NOT NULL", export.Name).First(&archived).Error
+	if err == gorm.ErrRecordNotFound {
+		_, err = ImportOrderTemplate(tx, siteID, export)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Model(&archived).Update("deleted_at", nil).Error; err != nil {
+		return err
+	}
+	return replaceOrderTemplate(tx, siteID, export)
+}
+
+// checkTemplateIdle 템플릿에 끝나지 않은 오더나 진행 중인 롤아웃이 없는지 확인
+func checkTemplateIdle(tx *gorm.DB, siteID string, template *models.OrderTemplate) error {
+	var unfinished int64
+	if err := tx.Model(&models.OrderExecution{}).Scopes(SiteScope(siteID)).
+		Where("template_id = ? AND status IN ?", template.ID, []string{
+			constants.OrderExecutionStatusPending, constants.OrderExecutionStatusRunning,
+			constants.OrderExecutionStatusWaiting, constants.OrderExecutionStatusPaused,
+		}).
+		Count(&unfinished).Error; err != nil {
+		return err
+	}
+	if unfinished > 0 {
+		return apperr.Validation("changes", "order template %q has %d unfinished order(s); apply after they finish", template.Name, unfinished)
+	}
+	rollout, err := activeRolloutFor(tx, siteID, template.ID)
+	if err != nil {
+		return err
+	}
+	if rollout != nil {
+		return apperr.Validation("changes", "order template %q is part of canary rollout %d", template.Name, rollout.ID)
+	}
+	return nil
+}
+
+// replaceOrderTemplate 같은 이름의 템플릿 설정과 단계를 원본 내용으로 교체 (템플릿 ID는 유지되어 명령 매핑/하위 템플릿 참조가 그대로 유효)
+func replaceOrderTemplate(tx *gorm.DB, siteID string, export TemplateExport) error {
+	if err := validate.Struct(export); err != nil {
+		return err
+	}
+	var template models.OrderTemplate
+	if err := tx.Scopes(SiteScope(siteID)).Where("name = ?", export.Name).First(&template).Error; err != nil {
+		return apperr.Wrap(apperr.CodeTemplateNotFound, err, "order template %q not found", export.Name)
+	}
+	if err := checkTemplateIdle(tx, siteID, &template); err != nil {
+		return err
+	}
+	if export.Status == "" {
+		export.Status = constants.TemplateStatusActive
+	}
+	if err := tx.Model(&template).Updates(map[string]interface{}{
+		"description":               export.Description,
+		"is_active":                 export.IsActive,
+		"status":                    export.Status,
+		"max_concurrent_executions": export.MaxConcurrent,
+		"deadline_seconds":          export.Deadline,
+		"notify_on":                 export.NotifyOn,
+		"notify_channel":            export.NotifyChannel,
+		"notify_escalate_after":     export.EscalateAfter,
+	}).Error; err != nil {
+		return err
+	}
+
+	// 기존 단계와 엣지, 단계-액션 매핑 삭제 (재사용 가능한 ActionTemplate은 유지)
+	var stepIDs []uint
+	if err := tx.Model(&models.OrderStep{}).Where("template_id = ?", template.ID).Pluck("id", &stepIDs).Error; err != nil {
+		return err
+	}
+	if len(stepIDs) > 0 {
+		if err := tx.Where("order_step_id IN ?", stepIDs).Delete(&models.StepActionMapping{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("order_step_id IN ?", stepIDs).Delete(&models.EdgeTemplate{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id IN ?", stepIDs).Delete(&models.OrderStep{}).Error; err != nil {
+			return err
+		}
+	}
+	for _, stepExport := range export.Steps {
+		if err := importStep(tx, siteID, template.ID, stepExport); err != nil {
+			return fmt.Errorf("step %d: %w", stepExport.StepOrder, err)
+		}
+	}
+
+	detail, err := LoadTemplateDetail(tx, siteID, template.ID)
+	if err != nil {
+		return err
+	}
+	if err := ValidateTemplateGraph(detail); err != nil {
+		return err
+	}
+	if err := ValidateStepConditions(detail); err != nil {
+		return err
+	}
+	_, err = ExpandTemplate(tx, siteID, detail)
+	return err
+}
+
+// archiveSyncedTemplate 원본에 없는 템플릿 보관 (아직 명령 매핑이 가리키면 거부)
+func archiveSyncedTemplate(tx *gorm.DB, siteID, name string) error {
+	var template models.OrderTemplate
+	if err := tx.Scopes(SiteScope(siteID)).Where("name = ?", name).First(&template).Error; err != nil {
+		return apperr.Wrap(apperr.CodeTemplateNotFound, err, "order template %q not found", name)
+	}
+	if err := checkTemplateIdle(tx, siteID, &template); err != nil {
+		return err
+	}
+	var mapped int64
+	if err := tx.Model(&models.CommandOrderMapping{}).
+		Joins("JOIN command_definitions ON command_definitions.id = command_order_mappings.command_definition_id").
+		Where("command_order_mappings.template_id = ? AND command_definitions.is_active = ? AND command_definitions.deleted_at IS NULL", template.ID, true).
+		Count(&mapped).Error; err != nil {
+		return err
+	}
+	if mapped > 0 {
+		return apperr.Validation("changes", "order template %q is still used by %d active command mapping(s)", name, mapped)
+	}
+	return ArchiveOrderTemplate(tx, siteID, template.ID)
+}
+
+// applyCommandMapping 이 사이트 템플릿으로 가는 명령의 오더 매핑을 원본 내용으로 교체
+// 명령 정의는 모든 사이트가 함께 쓰므로 없을 때만 만들고, 있는 정의의 설명/진행 보고 설정은 바꾸지 않습니다.
+// 정의가 비활성화되었거나 삭제되었으면 다른 사이트에도 영향을 주므로 되살리지 않고 거부합니다.
+func applyCommandMapping(tx *gorm.DB, siteID string, command CommandMappingExport) error {
+	orders := make(map[int]bool, len(command.Orders))
+	for _, order := range command.Orders {
+		if order.ExecutionOrder < 1 || orders[order.ExecutionOrder] {
+			return apperr.Validation("orders", "command %s has an invalid or duplicate execution order %d", command.CommandType, order.ExecutionOrder)
+		}
+		orders[order.ExecutionOrder] = true
+	}
+	for _, order := range command.Orders {
+		for _, next := range []int{order.NextExecutionOrder, order.FailureOrder} {
+			if next != 0 && !orders[next] {
+				return apperr.Validation("orders", "command %s order %d points to missing order %d", command.CommandType, order.ExecutionOrder, next)
+			}
+		}
+	}
+
+	var definition models.CommandDefinition
+	err := tx.Unscoped().Where("command_type = ?", command.CommandType).First(&definition).Error
+	switch {
+	case err == gorm.ErrRecordNotFound:
+		definition = models.CommandDefinition{
+			CommandType:    command.CommandType,
+			Description:    command.Description,
+			IsActive:       true,
+			ReportProgress: command.ReportProgress,
+		}
+		if err := tx.Create(&definition).Error; err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	case definition.DeletedAt.Valid || !definition.IsActive:
+		return apperr.Validation("changes", "command %s is disabled for all sites; re-enable it before syncing its mappings", command.CommandType)
+	}
+
+	siteTemplates := tx.Unscoped().Model(&models.OrderTemplate{}).Scopes(SiteScope(siteID)).Select("id")
+	if err := tx.Where("command_definition_id = ? AND template_id IN (?)", definition.ID, siteTemplates).
+		Delete(&models.CommandOrderMapping{}).Error; err != nil {
+		return err
+	}
+	for _, order := range command.Orders {
+		var template models.OrderTemplate
+		if err := tx.Scopes(SiteScope(siteID)).Where("name = ?", order.Template).First(&template).Error; err != nil {
+			return apperr.Wrap(apperr.CodeTemplateNotFound, err,
+				"order template %q used by command %s not found (sync the template first)", order.Template, command.CommandType)
+		}
+		mapping := models.CommandOrderMapping{
+			CommandDefinitionID: definition.ID,
+			TemplateID:          template.ID,
+			ExecutionOrder:      order.ExecutionOrder,
+			NextExecutionOrder:  order.NextExecutionOrder,
+			FailureOrder:        order.FailureOrder,
+			IsActive:            order.IsActive,
+		}
+		if err := tx.Create(&mapping).Error; err != nil {
+			return err
+		}
+		// gorm default:true 필드는 false 값이 생략되므로 명시적으로 갱신
+		if !order.IsActive {
+			if err := tx.Model(&mapping).Update("is_active", false).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// removeCommandMappings 원본에 없는 명령의 이 사이트 템플릿 매핑 삭제 (공용 명령 정의와 다른 사이트 매핑은 유지)
+func removeCommandMappings(tx *gorm.DB, siteID, commandType string) error {
+	var definition models.CommandDefinition
+	if err := tx.Where("command_type = ?", commandType).First(&definition).Error; err != nil {
+		return apperr.Wrap(apperr.CodeCommandNotFound, err, "command %s not found", commandType)
+	}
+	siteTemplates := tx.Unscoped().Model(&models.OrderTemplate{}).Scopes(SiteScope(siteID)).Select("id")
+	return tx.Where("command_definition_id = ? AND template_id IN (?)", definition.ID, siteTemplates).
+		Delete(&models.CommandOrderMapping{}).Error
+}
@@ -0,0 +1,177 @@
+// internal/repository/timeline.go
+package repository
+
+import (
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Timeline Event Type 타임라인 이벤트 타입 상수
+const (
+	TimelineOrderCreated   = "ORDER_CREATED"
+	TimelineOrderSent      = "ORDER_SENT"
+	TimelineStepStarted    = "STEP_STARTED"
+	TimelineStepCompleted  = "STEP_COMPLETED"
+	TimelineStepFailed     = "STEP_FAILED"
+	TimelineActionStatus   = "ACTION_STATUS"
+	TimelineOrderCompleted = "ORDER_COMPLETED"
+	TimelineOrderFailed    = "ORDER_FAILED"
+)
+
+// TimelineEvent 오더 타임라인 이벤트
+type TimelineEvent struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Type       string    `json:"type"`
+	StepOrder  int       `json:"step_order,omitempty"`
+	ActionID   string    `json:"action_id,omitempty"`
+	ActionType string    `json:"action_type,omitempty"`
+	Status     string    `json:"status,omitempty"`
+	Detail     string    `json:"detail,omitempty"`
+	DurationMs int64     `json:"duration_ms,omitempty"`
+}
+
+// OrderTimeline 오더 실행 타임라인
+type OrderTimeline struct {
+	OrderID       string          `json:"order_id"`
+	CorrelationID string          `json:"correlation_id"`
+	TemplateID    uint            `json:"template_id"`
+	Status        string          `json:"status"`
+	StartedAt     time.Time       `json:"started_at"`
+	CompletedAt   *time.Time      `json:"completed_at"`
+	DurationMs    int64           `json:"duration_ms"`
+	Events        []TimelineEvent `json:"events"`
+}
+
+// BuildOrderTimeline 오더 실행, 단계 실행, 아웃박스, 액션 상태 전이 기록으로 타임라인을 재구성합니다.
+func BuildOrderTimeline(db *gorm.DB, siteID, orderID string) (*OrderTimeline, error) {
+	var execution models.OrderExecution
+	err := db.Scopes(SiteScope(siteID)).Where("order_id = ?", orderID).
+		Preload("Steps", func(db *gorm.DB) *gorm.DB {
+			return db.Order("step_executions.id ASC")
+		}).
+		First(&execution).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, apperr.New(apperr.CodeNotFound, "order %s not found", orderID).WithField("orderId")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	timeline := &OrderTimeline{
+		OrderID:       execution.OrderID,
+		CorrelationID: execution.CorrelationID,
+		TemplateID:    execution.TemplateID,
+		Status:        execution.Status,
+		StartedAt:     execution.StartedAt,
+		CompletedAt:   execution.CompletedAt,
+		DurationMs:    durationMs(execution.StartedAt, execution.CompletedAt),
+	}
+
+	events := []TimelineEvent{{
+		Timestamp: execution.StartedAt,
+		Type:      TimelineOrderCreated,
+		Status:    constants.OrderExecutionStatusRunning,
+	}}
+
+	// 오더 전송 (아웃박스 기록)
+	var outboxMessages []models.OutboxMessage
+	db.Where("order_id = ?", orderID).Order("id ASC").Find(&outboxMessages)
+	for _, msg := range outboxMessages {
+		event := TimelineEvent{Type: TimelineOrderSent, Status: msg.Status, Detail: msg.LastError}
+		if msg.SentAt != nil {
+			event.Timestamp = *msg.SentAt
+		} else {
+			event.Timestamp = msg.UpdatedAt
+		}
+		events = append(events, event)
+	}
+
+	stepIDs := make([]uint, 0, len(execution.Steps))
+	stepOrders := make(map[uint]int, len(execution.Steps))
+	for _, step := range execution.Steps {
+		stepIDs = append(stepIDs, step.ID)
+		stepOrders[step.ID] = step.StepOrder
+
+		events = append(events, TimelineEvent{
+			Timestamp: step.StartedAt,
+			Type:      TimelineStepStarted,
+			StepOrder: step.StepOrder,
+			Status:    constants.StepExecutionStatusRunning,
+		})
+
+		if step.CompletedAt != nil {
+			eventType := TimelineStepCompleted
+			if step.Status != constants.StepExecutionStatusFinished {
+				eventType = TimelineStepFailed
+			}
+			events = append(events, TimelineEvent{
+				Timestamp:  *step.CompletedAt,
+				Type:       eventType,
+				StepOrder:  step.StepOrder,
+				Status:     step.Status,
+				Detail:     step.ErrorMessage,
+				DurationMs: durationMs(step.StartedAt, step.CompletedAt),
+			})
+		}
+	}
+
+	// 액션 상태 전이
+	if len(stepIDs) > 0 {
+		var transitions []models.ActionStatusTransition
+		db.Where("step_execution_id IN ?", stepIDs).Order("id ASC").Find(&transitions)
+		for _, t := range transitions {
+			detail := t.ResultDescription
+			if t.FromStatus != "" {
+				detail = fmt.Sprintf("%s -> %s", t.FromStatus, t.ToStatus)
+				if t.ResultDescription != "" {
+					detail += ": " + t.ResultDescription
+				}
+			}
+			events = append(events, TimelineEvent{
+				Timestamp:  t.ObservedAt,
+				Type:       TimelineActionStatus,
+				StepOrder:  stepOrders[t.StepExecutionID],
+				ActionID:   t.ActionID,
+				ActionType: t.ActionType,
+				Status:     t.ToStatus,
+				Detail:     detail,
+			})
+		}
+	}
+
+	if execution.CompletedAt != nil {
+		eventType := TimelineOrderCompleted
+		if execution.Status != constants.OrderExecutionStatusCompleted {
+			eventType = TimelineOrderFailed
+		}
+		events = append(events, TimelineEvent{
+			Timestamp:  *execution.CompletedAt,
+			Type:       eventType,
+			Status:     execution.Status,
+			DurationMs: timeline.DurationMs,
+		})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+	timeline.Events = events
+	return timeline, nil
+}
+
+// durationMs 시작~종료 시간(ms), 종료되지 않았으면 현재까지의 경과 시간
+func durationMs(start time.Time, end *time.Time) int64 {
+	if start.IsZero() {
+		return 0
+	}
+	if end == nil {
+		return time.Since(start).Milliseconds()
+	}
+	return end.Sub(start).Milliseconds()
+}
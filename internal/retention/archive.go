@@ -0,0 +1,153 @@
+// internal/retention/archive.go
+package retention
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/models"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Archive Mode 정리 전 보관 방식 상수
+const (
+	ArchiveNone  = "none"  // 보관하지 않고 삭제
+	ArchiveFile  = "file"  // 실행마다 gzip 압축 JSON Lines 파일로 보관
+	ArchiveTable = "table" // archived_records 테이블에 보관 (삭제와 같은 트랜잭션)
+)
+
+// archiver 삭제 전에 행을 보관하는 대상
+type archiver interface {
+	archive(tx *gorm.DB, table string, rows []map[string]interface{}) error
+	location() string
+	close() error
+}
+
+// newArchiver 정리 실행용 보관 대상 생성
+func newArchiver(mode, dir string, run *models.PurgeRun) (archiver, error) {
+	switch mode {
+	case ArchiveNone:
+		return noneArchiver{}, nil
+	case ArchiveFile:
+		name := fmt.Sprintf("purge-%s-%d-%s.jsonl.gz", run.SiteID, run.ID, run.StartedAt.UTC().Format("20060102T150405Z"))
+		return &fileArchiver{path: filepath.Join(dir, name)}, nil
+	case ArchiveTable:
+		return &tableArchiver{runID: run.ID}, nil
+	default:
+		return nil, fmt.Errorf("unsupported retention archive mode: %s (none, file, table)", mode)
+	}
+}
+
+// noneArchiver 보관하지 않음
+type noneArchiver struct{}
+
+func (noneArchiver) archive(*gorm.DB, string, []map[string]interface{}) error { return nil }
+func (noneArchiver) location() string                                         { return "" }
+func (noneArchiver) close() error                                             { return nil }
+
+// archiveLine 보관 파일의 한 줄
+type archiveLine struct {
+	Table  string                 `json:"table"`
+	Record map[string]interface{} `json:"record"`
+}
+
+// fileArchiver 첫 행을 보관할 때 파일을 열고 실행이 끝나면 닫음
+// 파일 기록 후 삭제 트랜잭션이 실패하면 다음 실행에서 같은 행이 다시 보관될 수 있습니다.
+type fileArchiver struct {
+	path string
+	file *os.File
+	gz   *gzip.Writer
+	enc  *json.Encoder
+}
+
+func (a *fileArchiver) archive(_ *gorm.DB, table string, rows []map[string]interface{}) error {
+	if a.file == nil {
+		if err := os.MkdirAll(filepath.Dir(a.path), 0o755); err != nil {
+			return fmt.Errorf("failed to create archive directory: %v", err)
+		}
+		file, err := os.Create(a.path)
+		if err != nil {
+			return fmt.Errorf("failed to create archive file: %v", err)
+		}
+		a.file = file
+		a.gz = gzip.NewWriter(file)
+		a.enc = json.NewEncoder(a.gz)
+	}
+	for _, row := range rows {
+		if err := a.enc.Encode(archiveLine{Table: table, Record: row}); err != nil {
+			return fmt.Errorf("failed to write archive file: %v", err)
+		}
+	}
+	// 삭제 전에 디스크에 반영
+	return a.gz.Flush()
+}
+
+func (a *fileArchiver) location() string {
+	if a.file == nil {
+		return ""
+	}
+	return a.path
+}
+
+func (a *fileArchiver) close() error {
+	if a.file == nil {
+		return nil
+	}
+	if err := a.gz.Close(); err != nil {
+		a.file.Close()
+		return err
+	}
+	return a.file.Close()
+}
+
+// tableArchiver 삭제와 같은 트랜잭션에서 archived_records에 기록
+type tableArchiver struct {
+	runID uint
+}
+
+func (a *tableArchiver) archive(tx *gorm.DB, table string, rows []map[string]interface{}) error {
+	now := time.Now()
+	records := make([]models.ArchivedRecord, 0, len(rows))
+	for _, row := range rows {
+		payload, err := json.Marshal(row)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s row: %v", table, err)
+		}
+		records = append(records, models.ArchivedRecord{
+			PurgeRunID:  a.runID,
+			SourceTable: table,
+			RecordID:    rowID(row),
+			Payload:     string(payload),
+			ArchivedAt:  now,
+		})
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	return tx.CreateInBatches(records, 100).Error
+}
+
+func (a *tableArchiver) location() string { return "archived_records" }
+func (a *tableArchiver) close() error     { return nil }
+
+// rowID 컬럼 맵의 id 값
+func rowID(row map[string]interface{}) uint {
+	switch v := row["id"].(type) {
+	case int64:
+		return uint(v)
+	case int32:
+		return uint(v)
+	case int:
+		return uint(v)
+	case uint:
+		return v
+	case uint64:
+		return uint(v)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,489 @@
+// internal/retention/purger.go
+package retention
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/config"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/utils"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 정리 대상 테이블 이름
+const (
+	TableCommands                = "commands"
+	TableCommandExecutions       = "command_executions"
+	TableOrderExecutions         = "order_executions"
+	TableStepExecutions          = "step_executions"
+	TableActionStatusTransitions = "action_status_transitions"
+	TablePLCResponses            = "plc_responses"
+)
+
+// Policy 실행 이력 보존 정책 (보존 기간 0은 무기한 보존)
+// 부모 행을 정리하면 자식 행(명령 → 명령 실행 → 오더 실행 → 단계 실행 → 액션 상태 전이)도 함께 정리합니다.
+// 명령을 정리할 때는 그 명령의 PLC 응답 기록도 함께 정리합니다.
+type Policy struct {
+	CommandTTL        time.Duration
+	OrderExecutionTTL time.Duration
+	StepExecutionTTL  time.Duration
+	Interval          time.Duration // 백그라운드 정리 주기 (0이면 수동 실행만)
+	BatchSize         int           // 트랜잭션 하나에서 정리할 최상위 행 수
+	ArchiveMode       string
+	ArchiveDir        string
+}
+
+// PolicyFromConfig 설정에서 보존 정책 구성
+func PolicyFromConfig(cfg *config.Config) Policy {
+	return Policy{
+		CommandTTL:        cfg.RetentionCommandTTL,
+		OrderExecutionTTL: cfg.RetentionOrderExecutionTTL,
+		StepExecutionTTL:  cfg.RetentionStepExecutionTTL,
+		Interval:          cfg.RetentionInterval,
+		BatchSize:         cfg.RetentionBatchSize,
+		ArchiveMode:       cfg.RetentionArchive,
+		ArchiveDir:        cfg.RetentionArchiveDir,
+	}
+}
+
+// Enabled 보존 기간이 하나라도 설정되었는지 여부
+func (p Policy) Enabled() bool {
+	return p.CommandTTL > 0 || p.OrderExecutionTTL > 0 || p.StepExecutionTTL > 0
+}
+
+// MarshalJSON 기간을 "720h0m0s" 형식 문자열로 출력
+func (p Policy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"command_ttl":         p.CommandTTL.String(),
+		"order_execution_ttl": p.OrderExecutionTTL.String(),
+		"step_execution_ttl":  p.StepExecutionTTL.String(),
+		"interval":            p.Interval.String(),
+		"batch_size":          p.BatchSize,
+		"archive_mode":        p.ArchiveMode,
+		"archive_dir":         p.ArchiveDir,
+	})
+}
+
+// tableIDs 한 배치에서 정리할 테이블별 행 ID (자식 → 부모 순서로 삭제)
+type tableIDs struct {
+	table string
+	model interface{}
+	ids   []uint
+}
+
+// Purger 보존 기간이 지난 실행 이력을 보관 후 삭제합니다.
+// 한 번에 하나의 실행만 진행되며, 실행 결과는 purge_runs에 기록됩니다.
+type Purger struct {
+	db     *gorm.DB
+	siteID string
+	policy Policy
+
+	running sync.Mutex
+	cancel  context.CancelFunc
+	doneCh  chan struct{}
+}
+
+// NewPurger 정리기 생성
+func NewPurger(db *gorm.DB, siteID string, policy Policy) (*Purger, error) {
+	switch policy.ArchiveMode {
+	case ArchiveNone, ArchiveFile, ArchiveTable:
+	default:
+		return nil, fmt.Errorf("unsupported retention archive mode: %s (none, file, table)", policy.ArchiveMode)
+	}
+	if policy.BatchSize <= 0 {
+		policy.BatchSize = 500
+	}
+	return &Purger{db: db, siteID: siteID, policy: policy}, nil
+}
+
+// Policy 보존 정책 반환
+func (p *Purger) Policy() Policy {
+	return p.policy
+}
+
+// Start 주기적 정리 시작 (Interval이 0이면 시작하지 않음)
+func (p *Purger) Start(ctx context.Context) {
+	if p.policy.Interval <= 0 || !p.policy.Enabled() {
+		return
+	}
+	ctx, p.cancel = context.WithCancel(ctx)
+	p.doneCh = make(chan struct{})
+	go p.run(ctx)
+	utils.Logger.Infof("✅ Retention purger started (interval %v, archive=%s)", p.policy.Interval, p.policy.ArchiveMode)
+}
+
+// Stop 주기적 정리를 멈춤 (진행 중인 실행은 현재 배치 후 중단)
+func (p *Purger) Stop() {
+	if p.cancel == nil {
+		return
+	}
+	p.cancel()
+	<-p.doneCh
+	p.cancel = nil
+}
+
+func (p *Purger) run(ctx context.Context) {
+	defer close(p.doneCh)
+	ticker := time.NewTicker(p.policy.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := p.Run(ctx, constants.PurgeTriggerScheduled, false); err != nil {
+				utils.Logger.Errorf("❌ Scheduled retention purge failed: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Run 정리를 실행하고 완료된 실행 기록을 반환 (dryRun이면 대상 건수만 집계)
+func (p *Purger) Run(ctx context.Context, trigger string, dryRun bool) (*models.PurgeRun, error) {
+	run, err := p.begin(trigger, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	err = p.execute(ctx, run)
+	return run, err
+}
+
+// Trigger 백그라운드에서 정리를 시작하고 진행 중인 실행 기록을 바로 반환
+func (p *Purger) Trigger(dryRun bool) (*models.PurgeRun, error) {
+	run, err := p.begin(constants.PurgeTriggerManual, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	started := *run
+	go func() {
+		if err := p.execute(context.Background(), run); err != nil {
+			utils.Logger.Errorf("❌ Retention purge run %d failed: %v", run.ID, err)
+		}
+	}()
+	return &started, nil
+}
+
+// begin 실행 잠금을 잡고 실행 기록 생성 (잠금은 execute에서 해제)
+func (p *Purger) begin(trigger string, dryRun bool) (*models.PurgeRun, error) {
+	if !p.policy.Enabled() {
+		return nil, apperr.Validation("policy", "no retention TTL configured")
+	}
+	if !p.running.TryLock() {
+		return nil, apperr.New(apperr.CodeValidationFailed, "a retention purge run is already in progress")
+	}
+
+	run := &models.PurgeRun{
+		SiteID:      p.siteID,
+		Trigger:     trigger,
+		DryRun:      dryRun,
+		ArchiveMode: p.policy.ArchiveMode,
+		Status:      constants.PurgeRunStatusRunning,
+		StartedAt:   time.Now(),
+	}
+	if err := p.db.Create(run).Error; err != nil {
+		p.running.Unlock()
+		return nil, fmt.Errorf("failed to record purge run: %v", err)
+	}
+	return run, nil
+}
+
+// execute 정책별로 정리하고 실행 기록 마무리
+func (p *Purger) execute(ctx context.Context, run *models.PurgeRun) error {
+	defer p.running.Unlock()
+	utils.Logger.Infof("🧹 Retention purge run %d started (trigger=%s, dryRun=%t)", run.ID, run.Trigger, run.DryRun)
+
+	counts := make(map[string]int64)
+	var err error
+	if run.DryRun {
+		err = p.countCandidates(counts)
+	} else {
+		err = p.purge(ctx, run, counts)
+	}
+
+	now := time.Now()
+	run.CompletedAt = &now
+	run.Status = constants.PurgeRunStatusCompleted
+	if err != nil {
+		run.Status = constants.PurgeRunStatusFailed
+		run.Error = err.Error()
+		if len(run.Error) > 500 {
+			run.Error = run.Error[:500]
+		}
+	}
+	countsJSON, _ := json.Marshal(counts)
+	run.Counts = string(countsJSON)
+	if saveErr := p.db.Save(run).Error; saveErr != nil {
+		utils.Logger.Errorf("❌ Failed to record purge run %d result: %v", run.ID, saveErr)
+	}
+
+	if err != nil {
+		return err
+	}
+	utils.Logger.Infof("🧹 Retention purge run %d completed: %s", run.ID, run.Counts)
+	return nil
+}
+
+// purge 단계 실행 → 오더 실행 → 명령 순으로 보존 기간이 지난 행을 배치 단위로 정리
+func (p *Purger) purge(ctx context.Context, run *models.PurgeRun, counts map[string]int64) error {
+	arch, err := newArchiver(p.policy.ArchiveMode, p.policy.ArchiveDir, run)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		run.ArchivePath = arch.location()
+		if err := arch.close(); err != nil {
+			utils.Logger.Errorf("❌ Failed to close retention archive: %v", err)
+		}
+	}()
+
+	now := time.Now()
+	phases := []struct {
+		ttl      time.Duration
+		selectFn func(tx *gorm.DB, cutoff time.Time, limit int) ([]tableIDs, error)
+	}{
+		{p.policy.StepExecutionTTL, p.selectStepExecutions},
+		{p.policy.OrderExecutionTTL, p.selectOrderExecutions},
+		{p.policy.CommandTTL, p.selectCommands},
+	}
+	for _, phase := range phases {
+		if phase.ttl <= 0 {
+			continue
+		}
+		cutoff := now.Add(-phase.ttl)
+		for {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			roots, err := p.purgeBatch(arch, cutoff, phase.selectFn, counts)
+			if err != nil {
+				return err
+			}
+			if roots < p.policy.BatchSize {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// purgeBatch 한 배치를 하나의 트랜잭션에서 보관 후 삭제하고 최상위 행 수 반환
+func (p *Purger) purgeBatch(arch archiver, cutoff time.Time,
+	selectFn func(tx *gorm.DB, cutoff time.Time, limit int) ([]tableIDs, error), counts map[string]int64) (int, error) {
+	roots := 0
+	batchCounts := make(map[string]int64)
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		batch, err := selectFn(tx, cutoff, p.policy.BatchSize)
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		roots = len(batch[len(batch)-1].ids)
+
+		for _, target := range batch {
+			if len(target.ids) == 0 {
+				continue
+			}
+			var rows []map[string]interface{}
+			if err := tx.Table(target.table).Where("id IN ?", target.ids).Find(&rows).Error; err != nil {
+				return fmt.Errorf("failed to read %s for archive: %v", target.table, err)
+			}
+			if err := arch.archive(tx, target.table, rows); err != nil {
+				return err
+			}
+			result := tx.Unscoped().Where("id IN ?", target.ids).Delete(target.model)
+			if result.Error != nil {
+				return fmt.Errorf("failed to delete %s: %v", target.table, result.Error)
+			}
+			batchCounts[target.table] += result.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	for table, count := range batchCounts {
+		counts[table] += count
+	}
+	return roots, nil
+}
+
+// selectCommands 완료된 지 오래된 명령과 하위 실행 이력
+// 명령 테이블에는 사이트 구분이 없으므로 다른 사이트의 오더 실행이 연결된 명령은 제외합니다.
+func (p *Purger) selectCommands(tx *gorm.DB, cutoff time.Time, limit int) ([]tableIDs, error) {
+	var commandIDs []uint
+	if err := p.commandCandidates(tx, cutoff).Order("id ASC").Limit(limit).Pluck("id", &commandIDs).Error; err != nil {
+		return nil, err
+	}
+	if len(commandIDs) == 0 {
+		return nil, nil
+	}
+
+	var responseIDs []uint
+	if err := tx.Model(&models.PLCResponse{}).
+		Where("site_id = ? AND correlation_id <> '' AND correlation_id IN (?)", p.siteID,
+			tx.Unscoped().Model(&models.Command{}).Select("correlation_id").Where("id IN ?", commandIDs)).
+		Pluck("id", &responseIDs).Error; err != nil {
+		return nil, err
+	}
+
+	var commandExecutionIDs []uint
+	if err := tx.Unscoped().Model(&models.CommandExecution{}).
+		Where("command_id IN ?", commandIDs).Pluck("id", &commandExecutionIDs).Error; err != nil {
+		return nil, err
+	}
+	var orderExecutionIDs []uint
+	if len(commandExecutionIDs) > 0 {
+		if err := tx.Unscoped().Model(&models.OrderExecution{}).
+			Where("command_execution_id IN ?", commandExecutionIDs).Pluck("id", &orderExecutionIDs).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	batch, err := p.orderExecutionChildren(tx, orderExecutionIDs)
+	if err != nil {
+		return nil, err
+	}
+	return append(batch,
+		tableIDs{TableOrderExecutions, &models.OrderExecution{}, orderExecutionIDs},
+		tableIDs{TableCommandExecutions, &models.CommandExecution{}, commandExecutionIDs},
+		tableIDs{TablePLCResponses, &models.PLCResponse{}, responseIDs},
+		tableIDs{TableCommands, &models.Command{}, commandIDs},
+	), nil
+}
+
+// selectOrderExecutions 완료된 지 오래된 오더 실행과 하위 단계 실행
+func (p *Purger) selectOrderExecutions(tx *gorm.DB, cutoff time.Time, limit int) ([]tableIDs, error) {
+	var orderExecutionIDs []uint
+	if err := p.orderExecutionCandidates(tx, cutoff).Order("id ASC").Limit(limit).Pluck("id", &orderExecutionIDs).Error; err != nil {
+		return nil, err
+	}
+	if len(orderExecutionIDs) == 0 {
+		return nil, nil
+	}
+
+	batch, err := p.orderExecutionChildren(tx, orderExecutionIDs)
+	if err != nil {
+		return nil, err
+	}
+	return append(batch, tableIDs{TableOrderExecutions, &models.OrderExecution{}, orderExecutionIDs}), nil
+}
+
+// selectStepExecutions 완료된 지 오래된 단계 실행과 액션 상태 전이 (오더 실행이 끝난 경우만)
+func (p *Purger) selectStepExecutions(tx *gorm.DB, cutoff time.Time, limit int) ([]tableIDs, error) {
+	var stepExecutionIDs []uint
+	if err := p.stepExecutionCandidates(tx, cutoff).Order("id ASC").Limit(limit).Pluck("id", &stepExecutionIDs).Error; err != nil {
+		return nil, err
+	}
+	if len(stepExecutionIDs) == 0 {
+		return nil, nil
+	}
+
+	var transitionIDs []uint
+	if err := tx.Model(&models.ActionStatusTransition{}).
+		Where("step_execution_id IN ?", stepExecutionIDs).Pluck("id", &transitionIDs).Error; err != nil {
+		return nil, err
+	}
+	return []tableIDs{
+		{TableActionStatusTransitions, &models.ActionStatusTransition{}, transitionIDs},
+		{TableStepExecutions, &models.StepExecution{}, stepExecutionIDs},
+	}, nil
+}
+
+// orderExecutionChildren 오더 실행에 속한 단계 실행과 액션 상태 전이
+func (p *Purger) orderExecutionChildren(tx *gorm.DB, orderExecutionIDs []uint) ([]tableIDs, error) {
+	if len(orderExecutionIDs) == 0 {
+		return nil, nil
+	}
+	var stepExecutionIDs []uint
+	if err := tx.Unscoped().Model(&models.StepExecution{}).
+		Where("execution_id IN ?", orderExecutionIDs).Pluck("id", &stepExecutionIDs).Error; err != nil {
+		return nil, err
+	}
+	var transitionIDs []uint
+	if len(stepExecutionIDs) > 0 {
+		if err := tx.Model(&models.ActionStatusTransition{}).
+			Where("step_execution_id IN ?", stepExecutionIDs).Pluck("id", &transitionIDs).Error; err != nil {
+			return nil, err
+		}
+	}
+	return []tableIDs{
+		{TableActionStatusTransitions, &models.ActionStatusTransition{}, transitionIDs},
+		{TableStepExecutions, &models.StepExecution{}, stepExecutionIDs},
+	}, nil
+}
+
+// commandCandidates 정리 대상 명령 조회 (진행 중인 명령 제외)
+func (p *Purger) commandCandidates(tx *gorm.DB, cutoff time.Time) *gorm.DB {
+	return tx.Unscoped().Model(&models.Command{}).
+		Where("status NOT IN ?", []string{constants.CommandStatusPending, constants.CommandStatusRunning}).
+		Where("created_at < ?", cutoff).
+		Where(`NOT EXISTS (SELECT 1 FROM command_executions ce
+			JOIN order_executions oe ON oe.command_execution_id = ce.id
+			WHERE ce.command_id = commands.id AND oe.site_id <> ?)`, p.siteID)
+}
+
+// orderExecutionCandidates 정리 대상 오더 실행 조회 (완료된 실행만)
+func (p *Purger) orderExecutionCandidates(tx *gorm.DB, cutoff time.Time) *gorm.DB {
+	return tx.Unscoped().Model(&models.OrderExecution{}).
+		Where("site_id = ? AND completed_at IS NOT NULL AND completed_at < ?", p.siteID, cutoff)
+}
+
+// stepExecutionCandidates 정리 대상 단계 실행 조회 (단계와 오더 실행이 모두 완료된 경우만)
+func (p *Purger) stepExecutionCandidates(tx *gorm.DB, cutoff time.Time) *gorm.DB {
+	return tx.Unscoped().Model(&models.StepExecution{}).
+		Where("completed_at IS NOT NULL AND completed_at < ?", cutoff).
+		Where("execution_id IN (SELECT id FROM order_executions WHERE site_id = ? AND completed_at IS NOT NULL)", p.siteID)
+}
+
+// countCandidates 정책별 정리 대상 최상위 행 수 집계 (하위 행 제외)
+func (p *Purger) countCandidates(counts map[string]int64) error {
+	now := time.Now()
+	targets := []struct {
+		table string
+		ttl   time.Duration
+		query func(tx *gorm.DB, cutoff time.Time) *gorm.DB
+	}{
+		{TableStepExecutions, p.policy.StepExecutionTTL, p.stepExecutionCandidates},
+		{TableOrderExecutions, p.policy.OrderExecutionTTL, p.orderExecutionCandidates},
+		{TableCommands, p.policy.CommandTTL, p.commandCandidates},
+	}
+	for _, target := range targets {
+		if target.ttl <= 0 {
+			continue
+		}
+		var count int64
+		if err := target.query(p.db, now.Add(-target.ttl)).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to count %s: %v", target.table, err)
+		}
+		counts[target.table] = count
+	}
+	return nil
+}
+
+// ListRuns 사이트의 최근 정리 실행 기록 (최신순)
+func ListRuns(db *gorm.DB, siteID string, limit int) ([]models.PurgeRun, error) {
+	var runs []models.PurgeRun
+	query := db.Scopes(repository.SiteScope(siteID)).Order("started_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Find(&runs).Error; err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// ListRuns 최근 정리 실행 기록 (관리 엔드포인트용)
+func (p *Purger) ListRuns(limit int) ([]models.PurgeRun, error) {
+	return ListRuns(p.db, p.siteID, limit)
+}
@@ -0,0 +1,191 @@
+// internal/robot/action_schema.go
+package robot
+
+import (
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// maxParameterExamples 파라미터별로 돌려주는 액션 템플릿 사용 값 수
+const maxParameterExamples = 20
+
+// ActionSchema 팩트시트가 보고한 액션의 파라미터 스키마 (UI 입력 폼 생성용)
+type ActionSchema struct {
+	ActionType        string            `json:"action_type"`
+	Description       string            `json:"description,omitempty"`
+	Scopes            []string          `json:"scopes"` // INSTANT, NODE, EDGE
+	ResultDescription string            `json:"result_description,omitempty"`
+	Parameters        []ParameterSchema `json:"parameters"`
+}
+
+// ParameterSchema 액션 파라미터 하나의 입력 형식
+type ParameterSchema struct {
+	Key         string   `json:"key"`
+	Type        string   `json:"type"`       // string, number, integer, boolean, object, array
+	DataType    string   `json:"data_type"`  // 팩트시트의 valueDataType 원문
+	ValueType   string   `json:"value_type"` // 액션 템플릿 파라미터 타입 (STRING, NUMBER, BOOLEAN)
+	Required    bool     `json:"required"`
+	Description string   `json:"description,omitempty"`
+	Enum        []string `json:"enum,omitempty"`     // 팩트시트가 알려준 허용 값
+	Examples    []string `json:"examples,omitempty"` // 액션 템플릿에서 이미 쓰고 있는 값 (자동 완성 후보)
+}
+
+// ActionSchemas 로봇 팩트시트의 모든 액션 스키마 (actionType 순)
+func (f *FactsheetManager) ActionSchemas(serialNumber string) ([]ActionSchema, error) {
+	actions, err := f.factsheetActions(serialNumber)
+	if err != nil {
+		return nil, err
+	}
+	schemas := make([]ActionSchema, 0, len(actions))
+	for _, action := range actions {
+		schema, err := f.buildActionSchema(action)
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, *schema)
+	}
+	sort.Slice(schemas, func(i, j int) bool { return schemas[i].ActionType < schemas[j].ActionType })
+	return schemas, nil
+}
+
+// ActionSchema 로봇 팩트시트에서 actionType의 파라미터 스키마 조회
+func (f *FactsheetManager) ActionSchema(serialNumber, actionType string) (*ActionSchema, error) {
+	actions, err := f.factsheetActions(serialNumber)
+	if err != nil {
+		return nil, err
+	}
+	for _, action := range actions {
+		if action.ActionType == actionType {
+			return f.buildActionSchema(action)
+		}
+	}
+	return nil, apperr.New(apperr.CodeNotFound, "robot %s factsheet does not list action %s", serialNumber, actionType).
+		WithField("actionType")
+}
+
+// TrajectoryNames 로봇 팩트시트가 궤적 액션의 trajectory_name 허용 값으로 알려준 궤적 이름
+// 궤적 액션이 없으면 UNSUPPORTED_FEATURE, 허용 값을 알려주지 않으면 검증할 수 없으므로 VALIDATION_FAILED 오류입니다.
+func (f *FactsheetManager) TrajectoryNames(serialNumber string) ([]string, error) {
+	actions, err := f.factsheetActions(serialNumber)
+	if err != nil {
+		return nil, err
+	}
+	for _, action := range actions {
+		if action.ActionType != constants.ActionTypeTrajectory {
+			continue
+		}
+		for _, param := range action.ActionParameters {
+			if param.Key == "trajectory_name" && len(param.Enum) > 0 {
+				return param.Enum, nil
+			}
+		}
+		return nil, apperr.Validation("trajectory_name",
+			"robot %s factsheet does not declare trajectory names for %s", serialNumber, constants.ActionTypeTrajectory)
+	}
+	return nil, apperr.New(apperr.CodeUnsupportedFeature, "robot %s factsheet does not list action %s",
+		serialNumber, constants.ActionTypeTrajectory)
+}
+
+// factsheetActions 저장된 팩트시트의 agvActions 목록
+func (f *FactsheetManager) factsheetActions(serialNumber string) ([]models.AgvAction, error) {
+	factsheet, err := f.GetFactsheet(serialNumber)
+	if err == gorm.ErrRecordNotFound {
+		return nil, apperr.New(apperr.CodeNotFound, "robot %s has not reported a factsheet yet", serialNumber).
+			WithField("serialNumber")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var actions []models.AgvAction
+	if factsheet.AgvActions != "" {
+		if err := json.Unmarshal([]byte(factsheet.AgvActions), &actions); err != nil {
+			return nil, fmt.Errorf("stored factsheet actions for robot %s are invalid: %w", serialNumber, err)
+		}
+	}
+	return actions, nil
+}
+
+// buildActionSchema 팩트시트 액션을 스키마로 변환하고 액션 템플릿의 사용 값을 후보로 덧붙임
+func (f *FactsheetManager) buildActionSchema(action models.AgvAction) (*ActionSchema, error) {
+	examples, err := f.parameterExamples(action.ActionType)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &ActionSchema{
+		ActionType:        action.ActionType,
+		Description:       action.ActionDescription,
+		Scopes:            action.ActionScopes,
+		ResultDescription: action.ResultDescription,
+		Parameters:        make([]ParameterSchema, 0, len(action.ActionParameters)),
+	}
+	if schema.Scopes == nil {
+		schema.Scopes = []string{}
+	}
+	for _, param := range action.ActionParameters {
+		jsonType, valueType := parameterTypes(param.ValueDataType)
+		schema.Parameters = append(schema.Parameters, ParameterSchema{
+			Key:         param.Key,
+			Type:        jsonType,
+			DataType:    param.ValueDataType,
+			ValueType:   valueType,
+			Required:    !param.IsOptional,
+			Description: param.Description,
+			Enum:        param.Enum,
+			Examples:    examples[param.Key],
+		})
+	}
+	return schema, nil
+}
+
+// parameterExamples 액션 템플릿에서 actionType 파라미터 키별로 쓰고 있는 값 (자리표시자 제외)
+func (f *FactsheetManager) parameterExamples(actionType string) (map[string][]string, error) {
+	var rows []struct {
+		Key   string
+		Value string
+	}
+	err := f.db.Model(&models.ActionParameter{}).
+		Select("DISTINCT action_parameters.key, action_parameters.value").
+		Joins("JOIN action_templates ON action_templates.id = action_parameters.action_template_id AND action_templates.deleted_at IS NULL").
+		Where("action_templates.action_type = ?", actionType).
+		Order("action_parameters.key, action_parameters.value").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	examples := make(map[string][]string)
+	for _, row := range rows {
+		if strings.Contains(row.Value, "{{") || len(examples[row.Key]) >= maxParameterExamples {
+			continue
+		}
+		examples[row.Key] = append(examples[row.Key], row.Value)
+	}
+	return examples, nil
+}
+
+// parameterTypes 팩트시트 valueDataType을 입력 타입과 액션 템플릿 파라미터 타입으로 변환 (모르는 타입은 문자열)
+func parameterTypes(dataType string) (jsonType, valueType string) {
+	switch strings.ToUpper(dataType) {
+	case "BOOL", "BOOLEAN":
+		return "boolean", "BOOLEAN"
+	case "INTEGER", "INT":
+		return "integer", "NUMBER"
+	case "NUMBER", "FLOAT", "DOUBLE":
+		return "number", "NUMBER"
+	case "OBJECT":
+		return "object", "STRING"
+	case "ARRAY":
+		return "array", "STRING"
+	default:
+		return "string", "STRING"
+	}
+}
@@ -0,0 +1,234 @@
+// internal/robot/compatibility.go
+package robot
+
+import (
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/utils"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Compatibility Mode 로봇 버전 호환성 검사 방식 상수
+const (
+	CompatibilityModeOff     = "off"     // 검사하지 않음
+	CompatibilityModeLenient = "lenient" // 지원하지 않는 기능도 경고만 남기고 전송
+	CompatibilityModeStrict  = "strict"  // 지원하지 않는 기능은 전송하지 않음
+)
+
+// Feature 로봇에 보내는 기능 이름 상수 (VDA_FEATURE_VERSIONS 설정 키)
+const (
+	FeatureOrder            = "order"             // 단일 노드 오더
+	FeatureMultiNodeOrder   = "multi_node_order"  // 병렬 그룹처럼 여러 노드를 가진 오더
+	FeatureCancelOrder      = "cancel_order"      // cancelOrder instantAction
+	FeatureFactsheetRequest = "factsheet_request" // factsheetRequest instantAction
+	FeatureStateRequest     = "state_request"     // stateRequest instantAction
+	FeatureInitPosition     = "init_position"     // initPosition instantAction
+	FeaturePause            = "pause"             // startPause/stopPause instantAction
+)
+
+// VersionRange 기능을 지원하는 VDA 버전 범위 (빈 값은 제한 없음)
+type VersionRange struct {
+	Min string `json:"min,omitempty"`
+	Max string `json:"max,omitempty"`
+}
+
+// Contains 버전이 범위 안에 있는지 확인
+func (r VersionRange) Contains(version string) bool {
+	if r.Min != "" && CompareVersions(version, r.Min) < 0 {
+		return false
+	}
+	if r.Max != "" && CompareVersions(version, r.Max) > 0 {
+		return false
+	}
+	return true
+}
+
+// String 사람이 읽는 범위 표기 (예: ">= 2.0.0", "1.1.0..2.0.0", "any")
+func (r VersionRange) String() string {
+	switch {
+	case r.Min == "" && r.Max == "":
+		return "any"
+	case r.Max == "":
+		return ">= " + r.Min
+	case r.Min == "":
+		return "<= " + r.Max
+	default:
+		return r.Min + ".." + r.Max
+	}
+}
+
+// CompatibilityMatrix 기능별 지원 버전 범위
+type CompatibilityMatrix map[string]VersionRange
+
+// DefaultCompatibilityMatrix 기본 호환성 표 (factsheetRequest는 VDA 5050 2.0.0부터 정의됨)
+func DefaultCompatibilityMatrix() CompatibilityMatrix {
+	return CompatibilityMatrix{
+		FeatureOrder:            {},
+		FeatureMultiNodeOrder:   {},
+		FeatureCancelOrder:      {},
+		FeatureFactsheetRequest: {Min: "2.0.0"},
+		FeatureStateRequest:     {},
+		FeatureInitPosition:     {},
+		FeaturePause:            {},
+	}
+}
+
+// Features 정렬된 기능 이름 목록
+func (m CompatibilityMatrix) Features() []string {
+	features := make([]string, 0, len(m))
+	for feature := range m {
+		features = append(features, feature)
+	}
+	sort.Strings(features)
+	return features
+}
+
+// ParseCompatibilityMatrix 기본 표에 "feature=min..max" 목록(쉼표 구분)을 덮어씀
+// 예: "multi_node_order=2.0.0..,factsheet_request=2.0.0..2.1.0"
+func ParseCompatibilityMatrix(spec string) (CompatibilityMatrix, error) {
+	matrix := DefaultCompatibilityMatrix()
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		feature, bounds, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid feature version entry %q (feature=min..max)", entry)
+		}
+		feature = strings.TrimSpace(feature)
+		if _, known := matrix[feature]; !known {
+			return nil, fmt.Errorf("unknown feature %q in feature versions (%s)", feature, strings.Join(matrix.Features(), ", "))
+		}
+		min, max, ok := strings.Cut(strings.TrimSpace(bounds), "..")
+		if !ok {
+			min = bounds // 범위가 없으면 최소 버전만 지정
+		}
+		versionRange := VersionRange{Min: strings.TrimSpace(min), Max: strings.TrimSpace(max)}
+		for _, version := range []string{versionRange.Min, versionRange.Max} {
+			if version != "" && !validVersion(version) {
+				return nil, fmt.Errorf("invalid version %q for feature %s", version, feature)
+			}
+		}
+		matrix[feature] = versionRange
+	}
+	return matrix, nil
+}
+
+// CompareVersions 점으로 구분된 숫자 버전 비교 (-1, 0, 1, 빠진 자리는 0으로 간주)
+func CompareVersions(a, b string) int {
+	as, bs := versionParts(a), versionParts(b)
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// versionParts 버전의 숫자 자리 ("2.0.0-rc1"처럼 접미사가 있으면 숫자 부분만 사용)
+func versionParts(version string) []int {
+	var parts []int
+	for _, part := range strings.Split(strings.TrimPrefix(strings.TrimSpace(version), "v"), ".") {
+		digits := part
+		if i := strings.IndexFunc(part, func(r rune) bool { return r < '0' || r > '9' }); i >= 0 {
+			digits = part[:i]
+		}
+		n, _ := strconv.Atoi(digits)
+		parts = append(parts, n)
+	}
+	return parts
+}
+
+// validVersion 숫자로 시작하는 점 구분 버전인지 확인
+func validVersion(version string) bool {
+	for _, part := range strings.Split(strings.TrimPrefix(version, "v"), ".") {
+		if part == "" || part[0] < '0' || part[0] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// CompatibilityGate 로봇이 보고한 버전으로 기능 전송 가능 여부를 판단
+type CompatibilityGate struct {
+	db     *gorm.DB
+	siteID string
+	mode   string
+	matrix CompatibilityMatrix
+}
+
+// NewCompatibilityGate 호환성 검사기 생성 (featureVersions는 ParseCompatibilityMatrix 형식)
+func NewCompatibilityGate(db *gorm.DB, siteID, mode, featureVersions string) (*CompatibilityGate, error) {
+	switch mode {
+	case CompatibilityModeOff, CompatibilityModeLenient, CompatibilityModeStrict:
+	default:
+		return nil, fmt.Errorf("unsupported VDA compatibility mode: %s (off, lenient, strict)", mode)
+	}
+	matrix, err := ParseCompatibilityMatrix(featureVersions)
+	if err != nil {
+		return nil, err
+	}
+	utils.Logger.Infof("✅ Compatibility gate CREATED (mode=%s)", mode)
+	return &CompatibilityGate{db: db, siteID: siteID, mode: mode, matrix: matrix}, nil
+}
+
+// Mode 검사 모드
+func (g *CompatibilityGate) Mode() string {
+	return g.mode
+}
+
+// Matrix 기능별 지원 버전 범위
+func (g *CompatibilityGate) Matrix() CompatibilityMatrix {
+	return g.matrix
+}
+
+// CheckFeature 로봇이 기능을 지원하는지 확인합니다.
+// 버전을 아직 보고하지 않은 로봇은 판단할 수 없으므로 허용하고,
+// lenient 모드에서는 경고만 남기며 strict 모드에서만 UNSUPPORTED_FEATURE 오류를 반환합니다.
+func (g *CompatibilityGate) CheckFeature(serialNumber, feature string) error {
+	if g.mode == CompatibilityModeOff {
+		return nil
+	}
+	versionRange, ok := g.matrix[feature]
+	if !ok || (versionRange.Min == "" && versionRange.Max == "") {
+		return nil
+	}
+
+	var status models.RobotStatus
+	err := g.db.Scopes(repository.SiteScope(g.siteID)).
+		Select("version").
+		Where("serial_number = ?", serialNumber).
+		First(&status).Error
+	if err != nil || status.Version == "" {
+		utils.Logger.Debugf("🧬 Version of robot %s unknown, allowing %s", serialNumber, feature)
+		return nil
+	}
+	if versionRange.Contains(status.Version) {
+		return nil
+	}
+
+	unsupported := apperr.New(apperr.CodeUnsupportedFeature,
+		"robot %s reports VDA version %s, but %s requires %s", serialNumber, status.Version, feature, versionRange)
+	if g.mode == CompatibilityModeStrict {
+		return unsupported
+	}
+	utils.Logger.Warnf("🧬 %v (sending anyway)", unsupported)
+	return nil
+}
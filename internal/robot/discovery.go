@@ -0,0 +1,70 @@
+// internal/robot/discovery.go
+package robot
+
+import (
+	"fmt"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/utils"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"gorm.io/gorm"
+)
+
+// discoveryTouchInterval 이미 등록된 로봇의 마지막 수신 시각을 DB에 갱신하는 최소 간격
+const discoveryTouchInterval = time.Minute
+
+// Discovery 제조사 와일드카드 토픽(meili/v2/<manufacturer>/+/+)을 구독하여 처음 보는 로봇을 승인 대기로 등록합니다.
+// 등록만 담당하며, 승인 여부는 Executor가 오더를 배차하기 전에 확인합니다.
+type Discovery struct {
+	db           *gorm.DB
+	siteID       string
+	manufacturer string
+
+	mu   sync.Mutex
+	seen map[string]time.Time // 시리얼 번호별 마지막으로 DB에 기록한 시각
+}
+
+// NewDiscovery 로봇 탐색 서비스 생성
+func NewDiscovery(db *gorm.DB, siteID, manufacturer string) *Discovery {
+	return &Discovery{
+		db:           db,
+		siteID:       siteID,
+		manufacturer: manufacturer,
+		seen:         make(map[string]time.Time),
+	}
+}
+
+// Topic 탐색용 구독 토픽 (제조사의 모든 로봇, 모든 메시지 종류)
+func (d *Discovery) Topic() string {
+	return fmt.Sprintf("meili/v2/%s/+/+", d.manufacturer)
+}
+
+// HandleMessage 토픽의 시리얼 번호로 로봇을 등록 (페이로드는 보지 않음)
+func (d *Discovery) HandleMessage(client mqtt.Client, msg mqtt.Message) {
+	parts := strings.Split(msg.Topic(), "/")
+	if len(parts) != 5 || parts[3] == "" {
+		return
+	}
+	manufacturer, serialNumber := parts[2], parts[3]
+
+	now := time.Now()
+	d.mu.Lock()
+	last, ok := d.seen[serialNumber]
+	if ok && now.Sub(last) < discoveryTouchInterval {
+		d.mu.Unlock()
+		return
+	}
+	d.seen[serialNumber] = now
+	d.mu.Unlock()
+
+	if _, _, err := repository.RegisterDiscoveredRobot(d.db, d.siteID, manufacturer, serialNumber, msg.Topic(), now); err != nil {
+		utils.Logger.Errorf("❌ Failed to register discovered robot %s: %v", serialNumber, err)
+		// 다음 메시지에서 다시 시도
+		d.mu.Lock()
+		delete(d.seen, serialNumber)
+		d.mu.Unlock()
+	}
+}
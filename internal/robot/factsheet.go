@@ -59,7 +59,7 @@ func (f *FactsheetManager) SaveFactsheet(resp *models.FactsheetResponse) error {
 	// TypeSpecification 안전 접근
 	var seriesName, seriesDescription, agvClass, agvKinematics string
 	var maxLoadMass int
-	var localizationTypesJSON, navigationTypesJSON []byte
+	var localizationTypesJSON, navigationTypesJSON, agvActionsJSON []byte
 
 	if resp.TypeSpecification.SeriesName != "" {
 		seriesName = resp.TypeSpecification.SeriesName
@@ -90,6 +90,12 @@ func (f *FactsheetManager) SaveFactsheet(resp *models.FactsheetResponse) error {
 		navigationTypesJSON = []byte("[]")
 	}
 
+	if len(resp.ProtocolFeatures.AgvActions) > 0 {
+		agvActionsJSON, _ = json.Marshal(resp.ProtocolFeatures.AgvActions)
+	} else {
+		agvActionsJSON = []byte("[]")
+	}
+
 	// PhysicalParameters 안전 접근
 	speedMax := getFloatField(resp.PhysicalParameters.SpeedMax)
 	speedMin := getFloatField(resp.PhysicalParameters.SpeedMin)
@@ -119,6 +125,7 @@ func (f *FactsheetManager) SaveFactsheet(resp *models.FactsheetResponse) error {
 		HeightMin:         heightMin,
 		LocalizationTypes: string(localizationTypesJSON),
 		NavigationTypes:   string(navigationTypesJSON),
+		AgvActions:        string(agvActionsJSON),
 		LastUpdated:       timestamp,
 	}
 
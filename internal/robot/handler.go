@@ -18,12 +18,34 @@ type CommandFailureHandler interface {
 	FailAllProcessingCommands(reason string)
 }
 
+// StateObserver state 메시지를 함께 받아 보는 구성 요소 (알림 규칙 평가 등)
+type StateObserver interface {
+	ObserveState(stateMsg *models.RobotStateMessage)
+}
+
 // Handler 로봇 메시지 처리 핸들러 (Position 기능 통합)
 type Handler struct {
-	statusManager         *StatusManager
-	factsheetManager      *FactsheetManager
-	commandFailureHandler CommandFailureHandler
-	mqttClient            mqtt.Client
+	statusManager          *StatusManager
+	factsheetManager       *FactsheetManager
+	commandFailureHandler  CommandFailureHandler
+	mqttClient             mqtt.Client
+	stateCache             *StateCache
+	stateWriter            *StateWriter // 설정되면 상태 컬럼을 모아서 기록 (없으면 state마다 바로 기록)
+	compatibility          *CompatibilityGate
+	stateObservers         []StateObserver
+	initPositions          *initPositionTracker
+	initPositionFromHome   bool
+	instantActionsQoS      byte // factsheetRequest/initPosition 발행 옵션 (SetInstantActionsPublish)
+	instantActionsRetain   bool
+	factsheetPolicy        sendPolicy              // factsheetRequest 발행 제한 시간과 재시도 (SetFactsheetSendPolicy)
+	instantActionValidator *InstantActionValidator // 원시 instantActions 검증기 (없으면 SendInstantActions 거부)
+}
+
+// sendPolicy 직접 발행하는 메시지의 제한 시간과 재시도 (0이면 응답을 무한히 기다리고 재시도하지 않음)
+type sendPolicy struct {
+	timeout time.Duration
+	retries int
+	backoff time.Duration
 }
 
 // NewHandler 새 로봇 핸들러 생성
@@ -37,12 +59,47 @@ func NewHandler(statusManager *StatusManager, factsheetManager *FactsheetManager
 		factsheetManager:      factsheetManager,
 		commandFailureHandler: commandFailureHandler,
 		mqttClient:            mqttClient,
+		initPositions:         newInitPositionTracker(),
 	}
 
 	utils.Logger.Infof("✅ Robot Handler CREATED")
 	return handler
 }
 
+// SetInstantActionsPublish 로봇에 보내는 instantActions의 QoS와 retained 플래그 설정 (기본: QoS 0, retained 없음)
+func (h *Handler) SetInstantActionsPublish(qos byte, retained bool) {
+	h.instantActionsQoS = qos
+	h.instantActionsRetain = retained
+}
+
+// SetFactsheetSendPolicy factsheetRequest 발행 제한 시간, 재시도 횟수, 재시도 기본 대기 설정 (TRANSPORT_POLICIES의 mqtt.factsheetRequest)
+func (h *Handler) SetFactsheetSendPolicy(timeout time.Duration, retries int, backoff time.Duration) {
+	h.factsheetPolicy = sendPolicy{timeout: timeout, retries: retries, backoff: backoff}
+}
+
+// SetStateCache state 메시지를 Redis에 캐시할 상태 캐시 설정
+func (h *Handler) SetStateCache(stateCache *StateCache) {
+	h.stateCache = stateCache
+	utils.Logger.Infof("✅ Robot Handler: State cache set")
+}
+
+// SetStateWriter state마다 바로 기록하는 대신 상태 컬럼을 샤드별로 모아 주기적으로 기록할 기록기 설정
+func (h *Handler) SetStateWriter(writer *StateWriter) {
+	h.stateWriter = writer
+	utils.Logger.Infof("✅ Robot Handler: State writer set")
+}
+
+// SetCompatibilityGate 로봇 VDA 버전에 따라 지원하지 않는 요청을 막는 호환성 검사기 설정
+func (h *Handler) SetCompatibilityGate(gate *CompatibilityGate) {
+	h.compatibility = gate
+	utils.Logger.Infof("✅ Robot Handler: Compatibility gate set")
+}
+
+// AddStateObserver state 메시지를 받을 구성 요소 추가 (구독 전에 호출)
+func (h *Handler) AddStateObserver(observer StateObserver) {
+	h.stateObservers = append(h.stateObservers, observer)
+}
+
 // HandleConnectionState 로봇 연결 상태 메시지 처리
 func (h *Handler) HandleConnectionState(client mqtt.Client, msg mqtt.Message) {
 	var connMsg models.ConnectionStateMessage
@@ -85,12 +142,46 @@ func (h *Handler) HandleRobotState(client mqtt.Client, msg mqtt.Message) {
 		return
 	}
 
+	if h.stateWriter != nil {
+		// 마지막 접속 시간, VDA 버전, 현재 맵을 다음 기록 주기에 UPDATE 한 번으로 기록
+		h.stateWriter.Observe(&stateMsg)
+	} else {
+		h.writeState(&stateMsg)
+	}
+
+	if h.stateCache != nil {
+		if err := h.stateCache.Update(&stateMsg); err != nil {
+			utils.Logger.Errorf("Failed to cache robot state: %v", err)
+		}
+	}
+	h.initPositions.observe(&stateMsg)
+	for _, observer := range h.stateObservers {
+		observer.ObserveState(&stateMsg)
+	}
+
+	utils.Logger.Debugf("Robot state updated for %s", stateMsg.SerialNumber)
+}
+
+// writeState state 메시지의 상태 컬럼을 바로 기록
+func (h *Handler) writeState(stateMsg *models.RobotStateMessage) {
 	// 마지막 접속 시간 업데이트
 	if err := h.statusManager.UpdateLastSeen(stateMsg.SerialNumber); err != nil {
 		utils.Logger.Errorf("Failed to update last seen time: %v", err)
 	}
 
-	utils.Logger.Debugf("Robot state updated for %s", stateMsg.SerialNumber)
+	// 호환성 검사를 위한 VDA 버전 기록
+	if stateMsg.Version != "" {
+		if err := h.statusManager.UpdateVersion(stateMsg.SerialNumber, stateMsg.Version); err != nil {
+			utils.Logger.Errorf("Failed to update robot version: %v", err)
+		}
+	}
+
+	// 지오펜스 검증을 위한 현재 맵 기록
+	if stateMsg.AgvPosition.MapID != "" {
+		if err := h.statusManager.UpdateCurrentMap(stateMsg.SerialNumber, stateMsg.AgvPosition.MapID); err != nil {
+			utils.Logger.Errorf("Failed to update current map: %v", err)
+		}
+	}
 }
 
 // HandleFactsheet 팩트시트 응답 처리
@@ -112,10 +203,17 @@ func (h *Handler) HandleFactsheet(client mqtt.Client, msg mqtt.Message) {
 
 // RequestFactsheet 팩트시트 요청 전송 (통합됨)
 func (h *Handler) RequestFactsheet(manufacturer, serialNumber string) error {
-	actionID := idgen.UniqueID()
+	if h.compatibility != nil {
+		if err := h.compatibility.CheckFeature(serialNumber, FeatureFactsheetRequest); err != nil {
+			return err
+		}
+	}
+
+	actionID := idgen.ActionID()
+	topic := constants.GetMeiliInstantActionsTopic(manufacturer, serialNumber)
 
 	request := map[string]interface{}{
-		"headerId":     utils.GetNextHeaderID(),
+		"headerId":     utils.NextHeaderID(topic),
 		"timestamp":    time.Now().Format(time.RFC3339Nano),
 		"version":      "2.0.0",
 		"manufacturer": manufacturer,
@@ -135,18 +233,38 @@ func (h *Handler) RequestFactsheet(manufacturer, serialNumber string) error {
 		return fmt.Errorf("failed to marshal factsheet request: %v", err)
 	}
 
-	topic := constants.GetMeiliInstantActionsTopic(manufacturer, serialNumber)
 	utils.Logger.Infof("📤 SENDING factsheet request to %s (ActionID: %s)", topic, actionID)
 
-	token := h.mqttClient.Publish(topic, 0, false, reqData)
-	if token.Wait() && token.Error() != nil {
-		return fmt.Errorf("failed to send factsheet request: %v", token.Error())
+	if err := h.publishWithPolicy(topic, reqData, h.factsheetPolicy); err != nil {
+		return fmt.Errorf("failed to send factsheet request: %v", err)
 	}
 
 	utils.Logger.Infof("✅ Factsheet request sent successfully to robot: %s", serialNumber)
 	return nil
 }
 
+// publishWithPolicy instantActions 발행 (제한 시간 안에 완료되지 않거나 실패하면 지터를 더한 백오프 후 재시도)
+func (h *Handler) publishWithPolicy(topic string, payload []byte, policy sendPolicy) error {
+	for attempt := 0; ; attempt++ {
+		token := h.mqttClient.Publish(topic, h.instantActionsQoS, h.instantActionsRetain, payload)
+		var err error
+		if policy.timeout <= 0 {
+			token.Wait()
+			err = token.Error()
+		} else if !token.WaitTimeout(policy.timeout) {
+			err = fmt.Errorf("publish timed out after %v", policy.timeout)
+		} else {
+			err = token.Error()
+		}
+		if err == nil || attempt >= policy.retries {
+			return err
+		}
+		backoff := utils.JitteredBackoff(policy.backoff, attempt, 10*time.Second)
+		utils.Logger.Warnf("🔁 Publish to %s failed (attempt %d/%d), retrying in %v: %v", topic, attempt+1, policy.retries+1, backoff, err)
+		time.Sleep(backoff)
+	}
+}
+
 // CheckAndRequestInitPosition 위치 초기화 확인 및 요청 (Position에서 통합됨)
 func (h *Handler) CheckAndRequestInitPosition(stateMsg *models.RobotStateMessage) {
 	if stateMsg == nil {
@@ -180,19 +298,24 @@ func (h *Handler) CheckAndRequestInitPosition(stateMsg *models.RobotStateMessage
 }
 
 // sendInitPositionRequest initPosition 요청 전송 (Position에서 통합됨)
+// 홈 위치 사용이 켜져 있고 저장된 홈 위치가 있으면 그 위치를, 아니면 보고된 현재 위치를 사용합니다.
 func (h *Handler) sendInitPositionRequest(stateMsg *models.RobotStateMessage) error {
 	if stateMsg == nil {
 		return fmt.Errorf("state message is nil")
 	}
+	if h.initPositions.inFlight(stateMsg.SerialNumber, time.Now()) {
+		return nil // 이전 요청의 응답 대기 중
+	}
 
-	actionID := idgen.UniqueID()
-
-	// 안전한 필드 접근
-	safeString := func(val string) string {
-		if val == "" {
-			return ""
+	if h.initPositionFromHome {
+		home, err := h.homePose(stateMsg.SerialNumber)
+		if err != nil {
+			utils.Logger.Errorf("Failed to load home pose for robot %s: %v", stateMsg.SerialNumber, err)
+		}
+		if home != nil {
+			_, err := h.requestInitPosition(stateMsg.SerialNumber, stateMsg.Manufacturer, *home, InitPositionSourceHome)
+			return err
 		}
-		return val
 	}
 
 	safeFloat := func(val float64) float64 {
@@ -202,75 +325,19 @@ func (h *Handler) sendInitPositionRequest(stateMsg *models.RobotStateMessage) er
 		return val
 	}
 
-	// 현재 위치를 기준으로 초기 위치 설정
-	pose := map[string]interface{}{
-		"lastNodeId": "",
-		"mapId":      safeString(stateMsg.AgvPosition.MapID),
-		"theta":      safeFloat(stateMsg.AgvPosition.Theta),
-		"x":          safeFloat(stateMsg.AgvPosition.X),
-		"y":          safeFloat(stateMsg.AgvPosition.Y),
+	// 현재 위치를 기준으로 초기 위치 설정 (NaN이면 원점)
+	pose := models.PoseValue{
+		MapID: stateMsg.AgvPosition.MapID,
+		X:     safeFloat(stateMsg.AgvPosition.X),
+		Y:     safeFloat(stateMsg.AgvPosition.Y),
+		Theta: safeFloat(stateMsg.AgvPosition.Theta),
 	}
-
-	// 위치가 모두 0이면 원점 사용
-	x := safeFloat(stateMsg.AgvPosition.X)
-	y := safeFloat(stateMsg.AgvPosition.Y)
-	theta := safeFloat(stateMsg.AgvPosition.Theta)
-
-	if x == 0 && y == 0 && theta == 0 {
-		pose["x"] = 0.0
-		pose["y"] = 0.0
-		pose["theta"] = 0.0
+	if pose.X == 0 && pose.Y == 0 && pose.Theta == 0 {
 		utils.Logger.Infof("Using origin position for robot %s", stateMsg.SerialNumber)
 	}
 
-	// 요청 메시지 생성
-	request := map[string]interface{}{
-		"headerId":     utils.GetNextHeaderID(),
-		"timestamp":    time.Now().Format(time.RFC3339Nano),
-		"version":      "2.0.0",
-		"manufacturer": safeString(stateMsg.Manufacturer),
-		"serialNumber": safeString(stateMsg.SerialNumber),
-		"actions": []map[string]interface{}{
-			{
-				"actionType":   constants.ActionTypeInitPosition,
-				"actionId":     actionID,
-				"blockingType": constants.BlockingTypeNone,
-				"actionParameters": []map[string]interface{}{
-					{
-						"key":   "pose",
-						"value": pose,
-					},
-				},
-			},
-		},
-	}
-
-	// JSON 직렬화
-	reqData, err := json.Marshal(request)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request: %v", err)
-	}
-
-	// MQTT 토픽 생성 및 전송
-	manufacturer := safeString(stateMsg.Manufacturer)
-	serialNumber := safeString(stateMsg.SerialNumber)
-
-	if manufacturer == "" || serialNumber == "" {
-		return fmt.Errorf("invalid manufacturer or serial number")
-	}
-
-	topic := constants.GetMeiliInstantActionsTopic(manufacturer, serialNumber)
-
-	utils.Logger.Infof("📤 SENDING initPosition request to %s (ActionID: %s)", topic, actionID)
-	utils.Logger.Debugf("Request payload: %s", string(reqData))
-
-	token := h.mqttClient.Publish(topic, 0, false, reqData)
-	if token.Wait() && token.Error() != nil {
-		return fmt.Errorf("MQTT publish failed: %v", token.Error())
-	}
-
-	utils.Logger.Infof("✅ InitPosition request sent successfully to robot: %s", serialNumber)
-	return nil
+	_, err := h.requestInitPosition(stateMsg.SerialNumber, stateMsg.Manufacturer, pose, InitPositionSourceCurrent)
+	return err
 }
 
 // GetStatusManager 상태 관리자 반환 (필수 Getter - 실제 사용됨)
@@ -0,0 +1,255 @@
+// internal/robot/init_position.go
+package robot
+
+import (
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/common/idgen"
+	"mqtt-bridge/internal/common/validate"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/utils"
+	"sync"
+	"time"
+)
+
+// initPosition 요청 출처
+const (
+	InitPositionSourceManual  = "manual"  // 관리 요청에서 지정한 위치
+	InitPositionSourceHome    = "home"    // 저장된 홈 위치 (robot defaults)
+	InitPositionSourceCurrent = "current" // 미초기화 state 메시지의 현재 위치 (자동)
+)
+
+// InitPositionStatusSent 로봇이 아직 actionStates로 보고하지 않은 요청 상태
+const InitPositionStatusSent = "SENT"
+
+const (
+	initPositionHistorySize = 20               // 로봇별로 보관하는 최근 요청 수
+	initPositionRetryAfter  = 10 * time.Second // 자동 요청이 응답 없이 이 시간이 지나면 다시 보냄
+)
+
+// InitPositionRequest 보낸 initPosition 요청과 로봇이 보고한 진행 상태
+type InitPositionRequest struct {
+	ActionID          string           `json:"action_id"`
+	SerialNumber      string           `json:"serial_number"`
+	Source            string           `json:"source"`
+	Pose              models.PoseValue `json:"pose"`
+	Status            string           `json:"status"` // SENT 또는 VDA actionStatus
+	ResultDescription string           `json:"result_description,omitempty"`
+	PositionInit      bool             `json:"position_initialized"` // 마지막 state의 agvPosition.positionInitialized
+	RequestedAt       time.Time        `json:"requested_at"`
+	UpdatedAt         time.Time        `json:"updated_at"`
+}
+
+// Done 로봇이 요청을 끝냈는지 (FINISHED 또는 FAILED)
+func (r *InitPositionRequest) Done() bool {
+	return r.Status == constants.ActionStatusFinished || r.Status == constants.ActionStatusFailed
+}
+
+// initPositionTracker 로봇별 최근 initPosition 요청 (최신순)
+type initPositionTracker struct {
+	mu       sync.Mutex
+	requests map[string][]*InitPositionRequest
+}
+
+func newInitPositionTracker() *initPositionTracker {
+	return &initPositionTracker{requests: make(map[string][]*InitPositionRequest)}
+}
+
+// add 새 요청 기록 (오래된 요청은 버림)
+func (t *initPositionTracker) add(request *InitPositionRequest) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	list := append([]*InitPositionRequest{request}, t.requests[request.SerialNumber]...)
+	if len(list) > initPositionHistorySize {
+		list = list[:initPositionHistorySize]
+	}
+	t.requests[request.SerialNumber] = list
+}
+
+// list 로봇의 최근 요청 사본 (최신순)
+func (t *initPositionTracker) list(serialNumber string) []InitPositionRequest {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	result := make([]InitPositionRequest, 0, len(t.requests[serialNumber]))
+	for _, request := range t.requests[serialNumber] {
+		result = append(result, *request)
+	}
+	return result
+}
+
+// inFlight 응답을 기다리는 최근 요청이 있는지 (자동 요청 중복 방지)
+func (t *initPositionTracker) inFlight(serialNumber string, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, request := range t.requests[serialNumber] {
+		if !request.Done() && now.Sub(request.UpdatedAt) < initPositionRetryAfter {
+			return true
+		}
+	}
+	return false
+}
+
+// observe state 메시지의 actionStates로 끝나지 않은 요청의 상태를 갱신
+func (t *initPositionTracker) observe(stateMsg *models.RobotStateMessage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	requests := t.requests[stateMsg.SerialNumber]
+	if len(requests) == 0 {
+		return
+	}
+	now := time.Now()
+	for _, request := range requests {
+		if request.Done() {
+			continue
+		}
+		for _, actionState := range stateMsg.ActionStates {
+			if actionState.ActionID != request.ActionID {
+				continue
+			}
+			request.PositionInit = stateMsg.AgvPosition.PositionInitialized
+			if actionState.ActionStatus != request.Status {
+				request.Status = actionState.ActionStatus
+				request.ResultDescription = actionState.ResultDescription
+				request.UpdatedAt = now
+				switch request.Status {
+				case constants.ActionStatusFinished:
+					utils.Logger.Infof("📍 initPosition %s finished on robot %s (initialized: %t)",
+						request.ActionID, request.SerialNumber, request.PositionInit)
+				case constants.ActionStatusFailed:
+					utils.Logger.Warnf("📍 initPosition %s failed on robot %s: %s",
+						request.ActionID, request.SerialNumber, request.ResultDescription)
+				}
+			}
+			break
+		}
+	}
+}
+
+// SetInitPositionFromHome 미초기화 로봇 자동 요청에 현재 위치 대신 저장된 홈 위치 사용 여부 설정
+func (h *Handler) SetInitPositionFromHome(enabled bool) {
+	h.initPositionFromHome = enabled
+	utils.Logger.Infof("✅ Robot Handler: initPosition from home pose: %t", enabled)
+}
+
+// InitPosition 지정한 위치로 initPosition instantAction 전송
+func (h *Handler) InitPosition(serialNumber string, pose models.PoseValue) (*InitPositionRequest, error) {
+	if err := validate.Struct(pose); err != nil {
+		return nil, err
+	}
+	return h.requestInitPosition(serialNumber, "", pose, InitPositionSourceManual)
+}
+
+// InitPositionFromHome 로봇의 저장된 홈 위치(robot defaults)로 initPosition instantAction 전송
+func (h *Handler) InitPositionFromHome(serialNumber string) (*InitPositionRequest, error) {
+	pose, err := h.homePose(serialNumber)
+	if err != nil {
+		return nil, err
+	}
+	if pose == nil {
+		return nil, apperr.New(apperr.CodeNotFound, "robot %s has no home pose with a map (bridgectl robots defaults set)", serialNumber).
+			WithField("serialNumber")
+	}
+	return h.requestInitPosition(serialNumber, "", *pose, InitPositionSourceHome)
+}
+
+// InitPositionRequests 로봇의 최근 initPosition 요청과 진행 상태 (최신순)
+func (h *Handler) InitPositionRequests(serialNumber string) []InitPositionRequest {
+	return h.initPositions.list(serialNumber)
+}
+
+// homePose 저장된 홈 위치 (맵이 없으면 nil)
+func (h *Handler) homePose(serialNumber string) (*models.PoseValue, error) {
+	defaults, err := repository.FindRobotDefaults(h.statusManager.db, h.statusManager.siteID, serialNumber)
+	if err != nil {
+		return nil, err
+	}
+	if defaults == nil || defaults.MapID == "" {
+		return nil, nil
+	}
+	return &models.PoseValue{MapID: defaults.MapID, X: defaults.X, Y: defaults.Y, Theta: defaults.Theta}, nil
+}
+
+// requestInitPosition 요청을 보내고 진행 상태 추적을 시작합니다 (manufacturer가 비어 있으면 로봇 상태에서 조회).
+func (h *Handler) requestInitPosition(serialNumber, manufacturer string, pose models.PoseValue, source string) (*InitPositionRequest, error) {
+	if manufacturer == "" {
+		status, err := h.statusManager.GetRobotStatus(serialNumber)
+		if err != nil {
+			return nil, apperr.New(apperr.CodeNotFound, "robot %s not found", serialNumber).WithField("serialNumber")
+		}
+		manufacturer = status.Manufacturer
+	}
+	if h.compatibility != nil {
+		if err := h.compatibility.CheckFeature(serialNumber, FeatureInitPosition); err != nil {
+			return nil, err
+		}
+	}
+
+	actionID, err := h.publishInitPosition(manufacturer, serialNumber, pose)
+	if err != nil {
+		return nil, apperr.Wrap(apperr.CodeTransportUnavailable, err, "failed to send initPosition to robot %s", serialNumber)
+	}
+
+	now := time.Now()
+	request := &InitPositionRequest{
+		ActionID:     actionID,
+		SerialNumber: serialNumber,
+		Source:       source,
+		Pose:         pose,
+		Status:       InitPositionStatusSent,
+		RequestedAt:  now,
+		UpdatedAt:    now,
+	}
+	h.initPositions.add(request)
+	result := *request
+	return &result, nil
+}
+
+// publishInitPosition initPosition instantAction 발행 후 actionId 반환
+func (h *Handler) publishInitPosition(manufacturer, serialNumber string, pose models.PoseValue) (string, error) {
+	if manufacturer == "" || serialNumber == "" {
+		return "", fmt.Errorf("invalid manufacturer or serial number")
+	}
+
+	actionID := idgen.ActionID()
+	topic := constants.GetMeiliInstantActionsTopic(manufacturer, serialNumber)
+	request := map[string]interface{}{
+		"headerId":     utils.NextHeaderID(topic),
+		"timestamp":    time.Now().Format(time.RFC3339Nano),
+		"version":      "2.0.0",
+		"manufacturer": manufacturer,
+		"serialNumber": serialNumber,
+		"actions": []map[string]interface{}{
+			{
+				"actionType":   constants.ActionTypeInitPosition,
+				"actionId":     actionID,
+				"blockingType": constants.BlockingTypeNone,
+				"actionParameters": []map[string]interface{}{
+					{
+						"key":   "pose",
+						"value": pose,
+					},
+				},
+			},
+		},
+	}
+
+	reqData, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	utils.Logger.Infof("📤 SENDING initPosition request to %s (ActionID: %s, map %s, %.3f/%.3f/%.3f)",
+		topic, actionID, pose.MapID, pose.X, pose.Y, pose.Theta)
+	utils.Logger.Debugf("Request payload: %s", string(reqData))
+
+	token := h.mqttClient.Publish(topic, h.instantActionsQoS, h.instantActionsRetain, reqData)
+	if token.Wait() && token.Error() != nil {
+		return "", fmt.Errorf("MQTT publish failed: %v", token.Error())
+	}
+
+	utils.Logger.Infof("✅ InitPosition request sent successfully to robot: %s", serialNumber)
+	return actionID, nil
+}
@@ -0,0 +1,213 @@
+// internal/robot/instant_actions.go
+package robot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/utils"
+	"strings"
+	"time"
+)
+
+// AllowAllInstantActions 허용 목록에서 모든 actionType을 허용하는 값
+const AllowAllInstantActions = "*"
+
+// InstantActionsMessage VDA 5050 instantActions 메시지
+type InstantActionsMessage struct {
+	HeaderID     int64           `json:"headerId"`
+	Timestamp    string          `json:"timestamp"`
+	Version      string          `json:"version"`
+	Manufacturer string          `json:"manufacturer"`
+	SerialNumber string          `json:"serialNumber"`
+	Actions      []models.Action `json:"actions"`
+}
+
+// InstantActionValidationError 스키마/허용 목록 검증 실패 (필드 경로별 문제 목록)
+type InstantActionValidationError struct {
+	Problems []string
+}
+
+// Error 모든 문제 나열
+func (e *InstantActionValidationError) Error() string {
+	return "invalid instantActions payload: " + strings.Join(e.Problems, "; ")
+}
+
+// ErrorCode 오류 코드 (apperr.Coder)
+func (e *InstantActionValidationError) ErrorCode() apperr.Code {
+	return apperr.CodeValidationFailed
+}
+
+// InstantActionValidator 원시 instantActions 페이로드 검증기
+// VDA 5050 instantActions 스키마의 필수 필드, 타입, 열거값을 검사하고
+// 환경별 허용 목록에 없는 actionType은 거부합니다.
+type InstantActionValidator struct {
+	allowed  map[string]bool
+	allowAll bool
+}
+
+// NewInstantActionValidator 허용 actionType 목록으로 검증기 생성 ("*"는 전체 허용)
+func NewInstantActionValidator(allowlist []string) *InstantActionValidator {
+	v := &InstantActionValidator{allowed: make(map[string]bool)}
+	for _, actionType := range allowlist {
+		actionType = strings.TrimSpace(actionType)
+		switch actionType {
+		case "":
+		case AllowAllInstantActions:
+			v.allowAll = true
+		default:
+			v.allowed[actionType] = true
+		}
+	}
+	return v
+}
+
+// Validate 페이로드를 검증하고 대상 로봇과 일치하는지 확인한 뒤 파싱된 메시지 반환
+func (v *InstantActionValidator) Validate(serialNumber string, payload []byte) (*InstantActionsMessage, error) {
+	var raw map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, &InstantActionValidationError{Problems: []string{fmt.Sprintf("payload is not a JSON object: %v", err)}}
+	}
+
+	var problems []string
+	addProblem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	// 헤더 필드
+	if n, ok := raw["headerId"].(json.Number); !ok {
+		addProblem("headerId: required integer")
+	} else if id, err := n.Int64(); err != nil || id < 0 {
+		addProblem("headerId: must be a non-negative integer")
+	}
+	if ts, ok := raw["timestamp"].(string); !ok {
+		addProblem("timestamp: required string")
+	} else if _, err := time.Parse(time.RFC3339Nano, ts); err != nil {
+		addProblem("timestamp: must be ISO 8601 (RFC 3339)")
+	}
+	for _, field := range []string{"version", "manufacturer", "serialNumber"} {
+		if _, ok := raw[field].(string); !ok {
+			addProblem("%s: required string", field)
+		}
+	}
+	if serial, ok := raw["serialNumber"].(string); ok && serial != serialNumber {
+		addProblem("serialNumber: %q does not match target robot %q", serial, serialNumber)
+	}
+
+	// 액션 목록
+	actions, ok := raw["actions"].([]interface{})
+	if !ok {
+		addProblem("actions: required array")
+	}
+	seenActionIDs := make(map[string]bool)
+	for i, item := range actions {
+		path := fmt.Sprintf("actions[%d]", i)
+		action, ok := item.(map[string]interface{})
+		if !ok {
+			addProblem("%s: must be an object", path)
+			continue
+		}
+
+		actionType, ok := action["actionType"].(string)
+		if !ok || actionType == "" {
+			addProblem("%s.actionType: required string", path)
+		} else if !v.allowAll && !v.allowed[actionType] {
+			addProblem("%s.actionType: %q is not in the allowlist", path, actionType)
+		}
+
+		if actionID, ok := action["actionId"].(string); !ok || actionID == "" {
+			addProblem("%s.actionId: required string", path)
+		} else if seenActionIDs[actionID] {
+			addProblem("%s.actionId: %q is duplicated", path, actionID)
+		} else {
+			seenActionIDs[actionID] = true
+		}
+
+		switch action["blockingType"] {
+		case constants.BlockingTypeNone, constants.BlockingTypeSoft, constants.BlockingTypeHard:
+		default:
+			addProblem("%s.blockingType: must be one of NONE, SOFT, HARD", path)
+		}
+
+		if description, exists := action["actionDescription"]; exists {
+			if _, ok := description.(string); !ok {
+				addProblem("%s.actionDescription: must be a string", path)
+			}
+		}
+
+		if params, exists := action["actionParameters"]; exists {
+			list, ok := params.([]interface{})
+			if !ok {
+				addProblem("%s.actionParameters: must be an array", path)
+				continue
+			}
+			for j, p := range list {
+				paramPath := fmt.Sprintf("%s.actionParameters[%d]", path, j)
+				param, ok := p.(map[string]interface{})
+				if !ok {
+					addProblem("%s: must be an object", paramPath)
+					continue
+				}
+				if _, ok := param["key"].(string); !ok {
+					addProblem("%s.key: required string", paramPath)
+				}
+				if _, exists := param["value"]; !exists {
+					addProblem("%s.value: required", paramPath)
+				}
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, &InstantActionValidationError{Problems: problems}
+	}
+
+	var message InstantActionsMessage
+	if err := json.Unmarshal(payload, &message); err != nil {
+		return nil, &InstantActionValidationError{Problems: []string{err.Error()}}
+	}
+	return &message, nil
+}
+
+// SetInstantActionValidator 관리 API로 받은 원시 instantActions를 검증할 검증기 설정
+func (h *Handler) SetInstantActionValidator(validator *InstantActionValidator) {
+	h.instantActionValidator = validator
+}
+
+// SendInstantActions 원시 instantActions 페이로드를 검증한 뒤 로봇에 전송
+// headerId는 브릿지가 보내는 다른 메시지와 같은 토픽별 순번으로 바꿔서 보냅니다.
+func (h *Handler) SendInstantActions(serialNumber string, payload []byte) (*InstantActionsMessage, error) {
+	if h.instantActionValidator == nil {
+		return nil, apperr.New(apperr.CodeUnsupportedFeature, "raw instantActions are not enabled on this bridge")
+	}
+	message, err := h.instantActionValidator.Validate(serialNumber, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+	topic := constants.GetMeiliInstantActionsTopic(message.Manufacturer, serialNumber)
+	message.HeaderID = utils.NextHeaderID(topic)
+	raw["headerId"] = message.HeaderID
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal instantActions: %v", err)
+	}
+
+	utils.Logger.Infof("📤 SENDING %d raw instant action(s) to %s", len(message.Actions), topic)
+	token := h.mqttClient.Publish(topic, h.instantActionsQoS, h.instantActionsRetain, data)
+	if token.Wait() && token.Error() != nil {
+		return nil, apperr.Wrap(apperr.CodeTransportUnavailable, token.Error(), "failed to publish instantActions to %s", serialNumber)
+	}
+	return message, nil
+}
@@ -0,0 +1,287 @@
+// internal/robot/state_cache.go
+package robot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	redisKeys "mqtt-bridge/internal/common/redis"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/utils"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// 상태 해시에 항상 기록하지만 변경 비교에서는 제외하는 필드 (매 메시지마다 바뀜)
+const (
+	StateFieldHeaderID  = "headerId"
+	StateFieldTimestamp = "timestamp"
+	StateFieldHash      = "_hash"
+)
+
+// StateCacheStats 상태 캐시 누적 통계
+type StateCacheStats struct {
+	Received      uint64 `json:"received"`       // 수신한 state 메시지 수
+	Skipped       uint64 `json:"skipped"`        // 내용이 같아 기록을 생략한 메시지 수
+	FieldsWritten uint64 `json:"fields_written"` // Redis에 기록한 필드 수
+	FieldsDeleted uint64 `json:"fields_deleted"` // Redis에서 삭제한 필드 수
+	Flushes       uint64 `json:"flushes"`        // 실행한 파이프라인 수
+	FlushErrors   uint64 `json:"flush_errors"`
+}
+
+// stateSnapshot 로봇별 마지막으로 반영(대기 포함)한 필드와 내용 해시
+type stateSnapshot struct {
+	hash   uint64
+	fields map[string]string
+}
+
+// pendingWrite 다음 플러시에 반영할 로봇별 변경분
+type pendingWrite struct {
+	reset bool // 기존 해시를 지우고 전체 기록 (첫 기록 또는 이전 플러시 실패 후)
+	set   map[string]interface{}
+	del   map[string]bool
+}
+
+// StateCache 로봇 state 메시지를 Redis 해시(robot_status:<serial>)에 필드 단위로 캐시합니다.
+// 중첩 객체는 "agvPosition.x"처럼 평탄화하고 배열은 JSON 문자열로 저장합니다.
+// 내용 해시가 이전과 같으면 기록을 생략하고, 바뀐 필드만 모아 주기적으로 하나의 파이프라인으로 기록합니다.
+type StateCache struct {
+	client   *redis.Client
+	interval time.Duration
+
+	mu        sync.Mutex
+	snapshots map[string]*stateSnapshot
+	pending   map[string]*pendingWrite
+	stats     StateCacheStats
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewStateCache 상태 캐시 생성 (interval마다 변경분을 일괄 기록)
+func NewStateCache(client *redis.Client, interval time.Duration) *StateCache {
+	return &StateCache{
+		client:    client,
+		interval:  interval,
+		snapshots: make(map[string]*stateSnapshot),
+		pending:   make(map[string]*pendingWrite),
+	}
+}
+
+// Start 주기적 플러시 시작
+func (c *StateCache) Start(ctx context.Context) {
+	c.stopCh = make(chan struct{})
+	c.doneCh = make(chan struct{})
+	go c.run(ctx)
+	utils.Logger.Infof("✅ Robot state cache started (flush interval %v)", c.interval)
+}
+
+// Stop 주기적 플러시를 멈추고 남은 변경분을 기록
+func (c *StateCache) Stop() {
+	if c.stopCh == nil {
+		return
+	}
+	close(c.stopCh)
+	<-c.doneCh
+	c.stopCh = nil
+}
+
+func (c *StateCache) run(ctx context.Context) {
+	defer close(c.doneCh)
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.Flush(ctx)
+		case <-c.stopCh:
+			c.Flush(context.Background())
+			return
+		case <-ctx.Done():
+			c.Flush(context.Background())
+			return
+		}
+	}
+}
+
+// Update state 메시지를 이전 스냅샷과 비교하여 바뀐 필드만 대기열에 추가
+func (c *StateCache) Update(state *models.RobotStateMessage) error {
+	if state.SerialNumber == "" {
+		return fmt.Errorf("state message has no serial number")
+	}
+	fields, err := FlattenState(state)
+	if err != nil {
+		return err
+	}
+	hash := hashFields(fields)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.Received++
+
+	snapshot := c.snapshots[state.SerialNumber]
+	if snapshot != nil && snapshot.hash == hash {
+		c.stats.Skipped++
+		return nil
+	}
+
+	write := c.pending[state.SerialNumber]
+	if write == nil {
+		write = &pendingWrite{set: make(map[string]interface{}), del: make(map[string]bool)}
+		c.pending[state.SerialNumber] = write
+	}
+
+	if snapshot == nil {
+		write.reset = true
+		for key, value := range fields {
+			write.set[key] = value
+		}
+	} else {
+		for key, value := range fields {
+			if old, ok := snapshot.fields[key]; !ok || old != value {
+				write.set[key] = value
+				delete(write.del, key)
+			}
+		}
+		for key := range snapshot.fields {
+			if _, ok := fields[key]; !ok {
+				write.del[key] = true
+				delete(write.set, key)
+			}
+		}
+	}
+	write.set[StateFieldHeaderID] = state.HeaderID
+	write.set[StateFieldTimestamp] = state.Timestamp
+	write.set[StateFieldHash] = strconv.FormatUint(hash, 16)
+
+	c.snapshots[state.SerialNumber] = &stateSnapshot{hash: hash, fields: fields}
+	return nil
+}
+
+// Flush 대기 중인 변경분을 하나의 파이프라인으로 기록
+// 실패한 로봇은 스냅샷을 지워 다음 메시지에서 전체를 다시 기록합니다.
+func (c *StateCache) Flush(ctx context.Context) {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = make(map[string]*pendingWrite)
+	c.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	var written, deleted uint64
+	pipe := c.client.Pipeline()
+	for serialNumber, write := range pending {
+		key := redisKeys.RobotStatus(serialNumber)
+		if write.reset {
+			pipe.Del(ctx, key)
+		}
+		if len(write.del) > 0 {
+			fields := make([]string, 0, len(write.del))
+			for field := range write.del {
+				fields = append(fields, field)
+			}
+			pipe.HDel(ctx, key, fields...)
+			deleted += uint64(len(fields))
+		}
+		if len(write.set) > 0 {
+			pipe.HSet(ctx, key, write.set)
+			written += uint64(len(write.set))
+		}
+	}
+	_, err := pipe.Exec(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.Flushes++
+	if err != nil {
+		c.stats.FlushErrors++
+		for serialNumber := range pending {
+			delete(c.snapshots, serialNumber)
+		}
+		utils.Logger.Errorf("❌ Failed to flush robot state cache (%d robot(s)): %v", len(pending), err)
+		return
+	}
+	c.stats.FieldsWritten += written
+	c.stats.FieldsDeleted += deleted
+	utils.Logger.Debugf("Robot state cache flushed: %d robot(s), %d field(s) written, %d deleted", len(pending), written, deleted)
+}
+
+// Stats 누적 통계 반환
+func (c *StateCache) Stats() StateCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// FlattenState state 메시지를 필드 경로 → 문자열 값으로 평탄화 (headerId/timestamp 제외)
+func FlattenState(state *models.RobotStateMessage) (map[string]string, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal state message: %v", err)
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode state message: %v", err)
+	}
+	delete(raw, StateFieldHeaderID)
+	delete(raw, StateFieldTimestamp)
+
+	fields := make(map[string]string)
+	flattenInto(fields, "", raw)
+	return fields, nil
+}
+
+// flattenInto 객체는 "."로 이어 재귀 평탄화하고, 배열과 스칼라는 문자열로 저장
+func flattenInto(fields map[string]string, prefix string, value interface{}) {
+	if object, ok := value.(map[string]interface{}); ok {
+		for key, child := range object {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			flattenInto(fields, path, child)
+		}
+		return
+	}
+
+	switch v := value.(type) {
+	case string:
+		fields[prefix] = v
+	case nil:
+		fields[prefix] = ""
+	default:
+		data, _ := json.Marshal(v)
+		fields[prefix] = string(data)
+	}
+}
+
+// hashFields 필드 이름 순으로 정렬한 내용의 FNV-64a 해시
+func hashFields(fields map[string]string) uint64 {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	h := fnv.New64a()
+	for _, key := range keys {
+		h.Write([]byte(key))
+		h.Write([]byte{0})
+		h.Write([]byte(fields[key]))
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
+
+// ReadCachedState Redis에 캐시된 로봇 state 필드 조회
+func ReadCachedState(ctx context.Context, client *redis.Client, serialNumber string) (map[string]string, error) {
+	return client.HGetAll(ctx, redisKeys.RobotStatus(serialNumber)).Result()
+}
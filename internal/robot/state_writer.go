@@ -0,0 +1,172 @@
+// internal/robot/state_writer.go
+package robot
+
+import (
+	"context"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/utils"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StateWriterStats 상태 기록기 지표
+type StateWriterStats struct {
+	Shards   int    `json:"shards"`
+	Pending  int    `json:"pending"`  // 다음 기록을 기다리는 로봇 수
+	Observed uint64 `json:"observed"` // 받은 state 메시지
+	Updates  uint64 `json:"updates"`  // 실행한 UPDATE (로봇당 주기마다 최대 한 번)
+	Failures uint64 `json:"failures"` // 실패한 UPDATE (다음 주기에 다시 기록)
+}
+
+// pendingState 로봇의 아직 기록하지 않은 상태 컬럼
+type pendingState struct {
+	lastSeen time.Time
+	version  string // 비어 있으면 갱신하지 않음
+	mapID    string // 비어 있으면 갱신하지 않음
+}
+
+// stateShard 한 샤드에 배정된 로봇들의 대기 중인 상태
+type stateShard struct {
+	mu      sync.Mutex
+	pending map[string]*pendingState
+}
+
+// StateWriter state 메시지가 갱신하는 로봇 상태 컬럼(last_timestamp, version, current_map_id)을 모아 기록
+// 로봇을 시리얼 번호로 샤드에 배정하고(수집 워커와 같은 utils.ShardIndex), 샤드마다 주기적으로
+// 로봇당 UPDATE 한 번으로 기록합니다. 한 주기 안의 state 메시지는 마지막 값만 남으므로
+// 로봇별 순서는 수집 워커의 처리 순서를 따릅니다.
+type StateWriter struct {
+	db       *gorm.DB
+	siteID   string
+	interval time.Duration
+	shards   []*stateShard
+
+	observed uint64
+	updates  uint64
+	failures uint64
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewStateWriter 상태 기록기 생성 (shards가 1 미만이면 1)
+func NewStateWriter(db *gorm.DB, siteID string, shards int, interval time.Duration) *StateWriter {
+	if shards < 1 {
+		shards = 1
+	}
+	w := &StateWriter{db: db, siteID: siteID, interval: interval}
+	for i := 0; i < shards; i++ {
+		w.shards = append(w.shards, &stateShard{pending: make(map[string]*pendingState)})
+	}
+	return w
+}
+
+// Observe state 메시지의 상태 컬럼을 로봇의 샤드에 모음 (DB에는 다음 주기에 기록)
+func (w *StateWriter) Observe(stateMsg *models.RobotStateMessage) {
+	atomic.AddUint64(&w.observed, 1)
+	shard := w.shards[utils.ShardIndex(stateMsg.SerialNumber, len(w.shards))]
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	state, ok := shard.pending[stateMsg.SerialNumber]
+	if !ok {
+		state = &pendingState{}
+		shard.pending[stateMsg.SerialNumber] = state
+	}
+	state.lastSeen = time.Now()
+	if stateMsg.Version != "" {
+		state.version = stateMsg.Version
+	}
+	if stateMsg.AgvPosition.MapID != "" {
+		state.mapID = stateMsg.AgvPosition.MapID
+	}
+}
+
+// Start 샤드별 주기적 기록 시작
+func (w *StateWriter) Start(ctx context.Context) {
+	ctx, w.cancel = context.WithCancel(ctx)
+	for _, shard := range w.shards {
+		w.wg.Add(1)
+		go w.run(ctx, shard)
+	}
+	utils.Logger.Infof("✅ Robot state writer started (shards=%d, interval %v)", len(w.shards), w.interval)
+}
+
+// Stop 주기적 기록을 멈추고 남은 상태를 기록
+func (w *StateWriter) Stop() {
+	if w.cancel == nil {
+		return
+	}
+	w.cancel()
+	w.wg.Wait()
+	w.cancel = nil
+}
+
+func (w *StateWriter) run(ctx context.Context, shard *stateShard) {
+	defer w.wg.Done()
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.flush(shard)
+		case <-ctx.Done():
+			w.flush(shard)
+			return
+		}
+	}
+}
+
+// flush 샤드의 대기 중인 상태를 로봇당 UPDATE 한 번으로 기록
+// 실패한 로봇은 그 사이 새 state가 오지 않았으면 다음 주기에 다시 기록합니다.
+func (w *StateWriter) flush(shard *stateShard) {
+	shard.mu.Lock()
+	batch := shard.pending
+	shard.pending = make(map[string]*pendingState, len(batch))
+	shard.mu.Unlock()
+
+	for serialNumber, state := range batch {
+		columns := map[string]interface{}{"last_timestamp": state.lastSeen}
+		if state.version != "" {
+			columns["version"] = state.version
+		}
+		if state.mapID != "" {
+			columns["current_map_id"] = state.mapID
+		}
+		err := w.db.Model(&models.RobotStatus{}).
+			Scopes(repository.SiteScope(w.siteID)).
+			Where("serial_number = ?", serialNumber).
+			Updates(columns).Error
+		atomic.AddUint64(&w.updates, 1)
+		if err == nil {
+			continue
+		}
+		atomic.AddUint64(&w.failures, 1)
+		utils.Logger.Errorf("Failed to write robot state for %s: %v", serialNumber, err)
+		shard.mu.Lock()
+		if _, newer := shard.pending[serialNumber]; !newer {
+			shard.pending[serialNumber] = state
+		}
+		shard.mu.Unlock()
+	}
+}
+
+// Stats 상태 기록기 지표
+func (w *StateWriter) Stats() StateWriterStats {
+	stats := StateWriterStats{
+		Shards:   len(w.shards),
+		Observed: atomic.LoadUint64(&w.observed),
+		Updates:  atomic.LoadUint64(&w.updates),
+		Failures: atomic.LoadUint64(&w.failures),
+	}
+	for _, shard := range w.shards {
+		shard.mu.Lock()
+		stats.Pending += len(shard.pending)
+		shard.mu.Unlock()
+	}
+	return stats
+}
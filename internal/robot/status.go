@@ -3,6 +3,8 @@ package robot
 import (
 	"mqtt-bridge/internal/common/constants"
 	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/utils"
 	"time"
 
 	"gorm.io/gorm"
@@ -10,34 +12,44 @@ import (
 
 // StatusManager 로봇 상태 관리
 type StatusManager struct {
-	db *gorm.DB
+	db     *gorm.DB
+	siteID string
 }
 
-// NewStatusManager 새 상태 관리자 생성
-func NewStatusManager(db *gorm.DB) *StatusManager {
+// NewStatusManager 새 상태 관리자 생성 (siteID 범위의 로봇만 조회/갱신)
+func NewStatusManager(db *gorm.DB, siteID string) *StatusManager {
 	return &StatusManager{
-		db: db,
+		db:     db,
+		siteID: siteID,
 	}
 }
 
 // IsOnline 로봇이 온라인 상태인지 확인
 func (s *StatusManager) IsOnline(serialNumber string) bool {
 	var robotStatus models.RobotStatus
-	err := s.db.Where("serial_number = ?", serialNumber).First(&robotStatus).Error
+	err := s.db.Scopes(repository.SiteScope(s.siteID)).Where("serial_number = ?", serialNumber).First(&robotStatus).Error
 	if err != nil {
 		return false
 	}
 	return robotStatus.ConnectionState == constants.ConnectionStateOnline
 }
 
+// Maintenance 로봇이 유지보수 모드이면 상태를 반환 (만료된 유지보수는 해제)
+func (s *StatusManager) Maintenance(serialNumber string) (*repository.RobotMaintenance, error) {
+	return repository.ActiveMaintenance(s.db, s.siteID, serialNumber)
+}
+
 // UpdateConnectionState 연결 상태 업데이트
 func (s *StatusManager) UpdateConnectionState(connMsg *models.ConnectionStateMessage, timestamp time.Time) error {
 	var existingStatus models.RobotStatus
-	result := s.db.Where("serial_number = ?", connMsg.SerialNumber).First(&existingStatus)
+	result := s.db.Scopes(repository.SiteScope(s.siteID)).Where("serial_number = ?", connMsg.SerialNumber).First(&existingStatus)
 
 	if result.Error == gorm.ErrRecordNotFound {
+		s.recordConnectionTransition(connMsg.SerialNumber, "", connMsg.ConnectionState, timestamp)
+
 		// 새로 생성
 		robotStatus := &models.RobotStatus{
+			SiteID:          s.siteID,
 			Manufacturer:    connMsg.Manufacturer,
 			SerialNumber:    connMsg.SerialNumber,
 			ConnectionState: connMsg.ConnectionState,
@@ -47,21 +59,42 @@ func (s *StatusManager) UpdateConnectionState(connMsg *models.ConnectionStateMes
 		}
 		return s.db.Create(robotStatus).Error
 	} else if result.Error == nil {
+		if existingStatus.ConnectionState != connMsg.ConnectionState {
+			s.recordConnectionTransition(connMsg.SerialNumber, existingStatus.ConnectionState, connMsg.ConnectionState, timestamp)
+		}
+
 		// 기존 업데이트
 		existingStatus.ConnectionState = connMsg.ConnectionState
 		existingStatus.LastHeaderID = connMsg.HeaderID
 		existingStatus.LastTimestamp = timestamp
 		existingStatus.Version = connMsg.Version
-		return s.db.Save(&existingStatus).Error
+		// 유지보수 필드는 별도로 관리하므로 연결 관련 컬럼만 갱신
+		return s.db.Model(&existingStatus).
+			Select("connection_state", "last_header_id", "last_timestamp", "version").
+			Updates(&existingStatus).Error
 	}
 
 	return result.Error
 }
 
+// recordConnectionTransition 연결 상태 변경 이력 기록
+func (s *StatusManager) recordConnectionTransition(serialNumber, fromState, toState string, timestamp time.Time) {
+	transition := &models.ConnectionStateTransition{
+		SiteID:       s.siteID,
+		SerialNumber: serialNumber,
+		FromState:    fromState,
+		ToState:      toState,
+		ObservedAt:   timestamp,
+	}
+	if err := s.db.Create(transition).Error; err != nil {
+		utils.Logger.Errorf("Failed to record connection transition for %s: %v", serialNumber, err)
+	}
+}
+
 // GetRobotStatus 로봇 상태 조회
 func (s *StatusManager) GetRobotStatus(serialNumber string) (*models.RobotStatus, error) {
 	var status models.RobotStatus
-	err := s.db.Where("serial_number = ?", serialNumber).First(&status).Error
+	err := s.db.Scopes(repository.SiteScope(s.siteID)).Where("serial_number = ?", serialNumber).First(&status).Error
 	if err != nil {
 		return nil, err
 	}
@@ -71,6 +104,23 @@ func (s *StatusManager) GetRobotStatus(serialNumber string) (*models.RobotStatus
 // UpdateLastSeen 마지막 접속 시간 업데이트
 func (s *StatusManager) UpdateLastSeen(serialNumber string) error {
 	return s.db.Model(&models.RobotStatus{}).
+		Scopes(repository.SiteScope(s.siteID)).
 		Where("serial_number = ?", serialNumber).
 		Update("last_timestamp", time.Now()).Error
 }
+
+// UpdateVersion state 메시지가 보고한 VDA 버전 기록 (변경된 경우에만 기록)
+func (s *StatusManager) UpdateVersion(serialNumber, version string) error {
+	return s.db.Model(&models.RobotStatus{}).
+		Scopes(repository.SiteScope(s.siteID)).
+		Where("serial_number = ? AND version IS DISTINCT FROM ?", serialNumber, version).
+		Update("version", version).Error
+}
+
+// UpdateCurrentMap 로봇의 현재 맵 ID 업데이트 (변경된 경우에만 기록)
+func (s *StatusManager) UpdateCurrentMap(serialNumber, mapID string) error {
+	return s.db.Model(&models.RobotStatus{}).
+		Scopes(repository.SiteScope(s.siteID)).
+		Where("serial_number = ? AND current_map_id IS DISTINCT FROM ?", serialNumber, mapID).
+		Update("current_map_id", mapID).Error
+}
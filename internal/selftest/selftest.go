@@ -0,0 +1,295 @@
+// internal/selftest/selftest.go
+// Package selftest 배포 파이프라인용 자체 점검
+// 브로커 연결, 루프백 토픽 메시지 왕복, DB 스키마, Redis 지연, 전송 경로 도달 여부를 확인하고
+// 기계가 읽을 수 있는 보고서(JSON)를 만듭니다.
+package selftest
+
+import (
+	"context"
+	"fmt"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/common/idgen"
+	"mqtt-bridge/internal/config"
+	"mqtt-bridge/internal/database"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+// Status 점검 결과 값
+const (
+	StatusPass = "PASS"
+	StatusFail = "FAIL"
+	StatusSkip = "SKIP" // 점검 대상이 설정되지 않음
+)
+
+// Check Name 점검 이름 (전송 경로 점검은 "transport:<name>")
+const (
+	CheckMQTTBroker     = "mqtt_broker"
+	CheckMQTTLoopback   = "mqtt_loopback"
+	CheckPostgresSchema = "postgres_schema"
+	CheckRedisLatency   = "redis_latency"
+)
+
+// 점검 제한
+const (
+	checkTimeout    = 5 * time.Second        // 점검 하나의 최대 대기 시간
+	redisPings      = 3                      // Redis 지연 측정 횟수 (가장 느린 값으로 판정)
+	maxRedisLatency = 250 * time.Millisecond // 이보다 느리면 FAIL
+)
+
+// Check 점검 하나의 결과
+type Check struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Detail    string `json:"detail,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report 자체 점검 보고서 (점검이 하나라도 FAIL이면 FAIL)
+type Report struct {
+	Status     string    `json:"status"`
+	StartedAt  time.Time `json:"started_at"`
+	DurationMs int64     `json:"duration_ms"`
+	Checks     []Check   `json:"checks"`
+}
+
+// Passed 모든 점검이 PASS 또는 SKIP인지
+func (r *Report) Passed() bool {
+	return r.Status == StatusPass
+}
+
+// Runner 자체 점검 실행기
+// 연결에 실패한 의존성은 그 오류를 점검 결과로 보고하므로, 연결 오류도 함께 설정합니다.
+type Runner struct {
+	cfg        *config.Config
+	httpClient *http.Client
+
+	db       *gorm.DB
+	dbErr    error
+	redis    *redis.Client
+	redisErr error
+	mqtt     mqtt.Client
+	mqttErr  error
+
+	running sync.Mutex // 루프백 구독이 겹치지 않도록 한 번에 하나만 실행
+}
+
+// NewRunner 새 자체 점검 실행기 생성
+func NewRunner(cfg *config.Config) *Runner {
+	return &Runner{cfg: cfg, httpClient: &http.Client{Timeout: checkTimeout}}
+}
+
+// SetDB 점검할 DB 연결 (err는 연결 실패 오류)
+func (r *Runner) SetDB(db *gorm.DB, err error) {
+	r.db, r.dbErr = db, err
+}
+
+// SetRedis 점검할 Redis 클라이언트 (err는 연결 실패 오류)
+func (r *Runner) SetRedis(client *redis.Client, err error) {
+	r.redis, r.redisErr = client, err
+}
+
+// SetMQTT 점검할 MQTT 클라이언트 (err는 연결 실패 오류)
+func (r *Runner) SetMQTT(client mqtt.Client, err error) {
+	r.mqtt, r.mqttErr = client, err
+}
+
+// Run 모든 점검을 차례로 실행
+func (r *Runner) Run(ctx context.Context) *Report {
+	r.running.Lock()
+	defer r.running.Unlock()
+
+	report := &Report{Status: StatusPass, StartedAt: time.Now()}
+	checks := []func(context.Context) Check{r.checkBroker, r.checkLoopback, r.checkSchema, r.checkRedis}
+	for _, check := range checks {
+		report.Checks = append(report.Checks, check(ctx))
+	}
+	report.Checks = append(report.Checks, r.checkTransports(ctx)...)
+
+	for _, check := range report.Checks {
+		if check.Status == StatusFail {
+			report.Status = StatusFail
+		}
+	}
+	report.DurationMs = time.Since(report.StartedAt).Milliseconds()
+	return report
+}
+
+// timed 점검 함수를 실행하고 걸린 시간을 기록
+func timed(name string, run func(check *Check) error) Check {
+	check := Check{Name: name, Status: StatusPass}
+	started := time.Now()
+	err := run(&check)
+	if check.LatencyMs == 0 {
+		check.LatencyMs = time.Since(started).Milliseconds()
+	}
+	if err != nil {
+		check.Status = StatusFail
+		check.Error = err.Error()
+	}
+	return check
+}
+
+func (r *Runner) checkBroker(ctx context.Context) Check {
+	return timed(CheckMQTTBroker, func(check *Check) error {
+		check.Detail = r.cfg.MQTTBroker
+		if r.mqttErr != nil {
+			return r.mqttErr
+		}
+		if r.mqtt == nil || !r.mqtt.IsConnected() {
+			return fmt.Errorf("MQTT client is not connected")
+		}
+		return nil
+	})
+}
+
+// checkLoopback 고유 토픽을 구독하고 발행한 메시지가 브로커를 거쳐 돌아오는지 확인
+func (r *Runner) checkLoopback(ctx context.Context) Check {
+	return timed(CheckMQTTLoopback, func(check *Check) error {
+		if r.mqtt == nil || !r.mqtt.IsConnected() {
+			return fmt.Errorf("MQTT client is not connected")
+		}
+		nonce := idgen.NewGenerator().UniqueID()
+		topic := constants.TopicBridgeSelfTest + "/" + nonce
+		check.Detail = topic
+
+		received := make(chan struct{}, 1)
+		token := r.mqtt.Subscribe(topic, 1, func(_ mqtt.Client, msg mqtt.Message) {
+			if string(msg.Payload()) == nonce {
+				select {
+				case received <- struct{}{}:
+				default:
+				}
+			}
+		})
+		if err := waitToken(token, "subscribe"); err != nil {
+			return err
+		}
+		defer waitToken(r.mqtt.Unsubscribe(topic), "unsubscribe")
+
+		started := time.Now()
+		if err := waitToken(r.mqtt.Publish(topic, 1, false, []byte(nonce)), "publish"); err != nil {
+			return err
+		}
+		select {
+		case <-received:
+			check.LatencyMs = time.Since(started).Milliseconds()
+			return nil
+		case <-time.After(checkTimeout):
+			return fmt.Errorf("loopback message not received within %v", checkTimeout)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// waitToken MQTT 요청을 제한 시간까지 기다림
+func waitToken(token mqtt.Token, operation string) error {
+	if !token.WaitTimeout(checkTimeout) {
+		return fmt.Errorf("%s timed out after %v", operation, checkTimeout)
+	}
+	if err := token.Error(); err != nil {
+		return fmt.Errorf("%s failed: %v", operation, err)
+	}
+	return nil
+}
+
+// checkSchema 이 바이너리의 모델이 DB에 모두 마이그레이션되어 있는지 확인
+func (r *Runner) checkSchema(ctx context.Context) Check {
+	return timed(CheckPostgresSchema, func(check *Check) error {
+		if r.dbErr != nil {
+			return r.dbErr
+		}
+		if r.db == nil {
+			return fmt.Errorf("database is not connected")
+		}
+		schema, err := database.CheckSchema(r.db.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		if !schema.Current() {
+			missing := append(append([]string(nil), schema.MissingTables...), schema.MissingColumns...)
+			return fmt.Errorf("schema is behind this version, missing: %s", strings.Join(missing, ", "))
+		}
+		check.Detail = fmt.Sprintf("%d tables up to date", schema.Tables)
+		return nil
+	})
+}
+
+// checkRedis PING 지연을 여러 번 재서 가장 느린 값으로 판정
+func (r *Runner) checkRedis(ctx context.Context) Check {
+	return timed(CheckRedisLatency, func(check *Check) error {
+		if r.redisErr != nil {
+			return r.redisErr
+		}
+		if r.redis == nil {
+			return fmt.Errorf("redis is not connected")
+		}
+		ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+		defer cancel()
+		var slowest time.Duration
+		for i := 0; i < redisPings; i++ {
+			started := time.Now()
+			if err := r.redis.Ping(ctx).Err(); err != nil {
+				return err
+			}
+			if elapsed := time.Since(started); elapsed > slowest {
+				slowest = elapsed
+			}
+		}
+		check.LatencyMs = slowest.Milliseconds()
+		check.Detail = fmt.Sprintf("slowest of %d pings: %v", redisPings, slowest.Round(time.Microsecond))
+		if slowest > maxRedisLatency {
+			return fmt.Errorf("redis latency %v exceeds %v", slowest.Round(time.Millisecond), maxRedisLatency)
+		}
+		return nil
+	})
+}
+
+// checkTransports TRANSPORT_FAILOVER의 전송 경로마다 도달 여부 확인
+// HTTP 경로는 응답이 오기만 하면(상태 코드와 무관하게) 도달한 것으로 봅니다.
+func (r *Runner) checkTransports(ctx context.Context) []Check {
+	var checks []Check
+	for _, name := range strings.Split(r.cfg.TransportFailover, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		checks = append(checks, timed("transport:"+name, func(check *Check) error {
+			switch name {
+			case "mqtt":
+				check.Detail = r.cfg.MQTTBroker
+				if r.mqtt == nil || !r.mqtt.IsConnected() {
+					return fmt.Errorf("MQTT client is not connected")
+				}
+				return nil
+			case "http":
+				check.Detail = r.cfg.RobotHTTPURL
+				if r.cfg.RobotHTTPURL == "" {
+					return fmt.Errorf("ROBOT_HTTP_URL is not set")
+				}
+				req, err := http.NewRequestWithContext(ctx, http.MethodHead, r.cfg.RobotHTTPURL, nil)
+				if err != nil {
+					return err
+				}
+				resp, err := r.httpClient.Do(req)
+				if err != nil {
+					return err
+				}
+				resp.Body.Close()
+				check.Detail = fmt.Sprintf("%s (%s)", r.cfg.RobotHTTPURL, resp.Status)
+				return nil
+			default:
+				return fmt.Errorf("unsupported transport: %s (mqtt, http)", name)
+			}
+		}))
+	}
+	return checks
+}
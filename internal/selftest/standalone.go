@@ -0,0 +1,46 @@
+// internal/selftest/standalone.go
+package selftest
+
+import (
+	"context"
+	"mqtt-bridge/internal/common/idgen"
+	"mqtt-bridge/internal/config"
+	"mqtt-bridge/internal/database"
+	"mqtt-bridge/internal/messaging"
+	"mqtt-bridge/internal/redis"
+)
+
+// RunStandalone 브릿지를 시작하지 않고 설정대로 의존성에 연결해 자체 점검 (--selftest)
+// 실행 중인 브릿지와 세션이 충돌하지 않도록 별도 클라이언트 ID로 연결하고, 발행 버퍼와
+// 구독 상태 파일은 쓰지 않습니다. DB 마이그레이션도 하지 않습니다.
+func RunStandalone(ctx context.Context, cfg *config.Config) *Report {
+	runner := NewRunner(cfg)
+
+	db, err := database.Open(cfg)
+	runner.SetDB(db, err)
+	if err == nil {
+		if sqlDB, err := db.DB(); err == nil {
+			defer sqlDB.Close()
+		}
+	}
+
+	redisClient, err := redis.NewRedisClient(cfg)
+	runner.SetRedis(redisClient, err)
+	if err == nil {
+		defer redisClient.Close()
+	}
+
+	mqttCfg := *cfg
+	mqttCfg.MQTTClientID = cfg.MQTTClientID + "_selftest_" + idgen.NewGenerator().ShortID()
+	mqttCfg.MQTTBufferDir = ""
+	mqttCfg.MQTTSubscriptionStateFile = ""
+	mqttClient, err := messaging.NewMQTTClient(&mqttCfg)
+	if err != nil {
+		runner.SetMQTT(nil, err)
+	} else {
+		runner.SetMQTT(mqttClient.GetNativeClient(), nil)
+		defer mqttClient.Disconnect(250)
+	}
+
+	return runner.Run(ctx)
+}
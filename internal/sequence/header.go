@@ -0,0 +1,65 @@
+// internal/sequence/header.go
+package sequence
+
+import (
+	"context"
+	redisKeys "mqtt-bridge/internal/common/redis"
+	"mqtt-bridge/internal/utils"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// headerSequenceTimeout Redis 순번 발급 대기 시간 (넘으면 메모리 순번으로 대체)
+const headerSequenceTimeout = 500 * time.Millisecond
+
+// nextHeaderScript 순번을 올리되 이 프로세스가 이미 발급한 값(ARGV[1]) 이하로는 내려가지 않게 함
+// Redis 장애 중 메모리로 발급한 순번이 복구 후 다시 쓰이지 않도록 합니다.
+var nextHeaderScript = redis.NewScript(`
+local v = redis.call('INCR', KEYS[1])
+local floor = tonumber(ARGV[1])
+if v <= floor then
+	v = floor + 1
+	redis.call('SET', KEYS[1], v)
+end
+return v
+`)
+
+// HeaderSequence Redis에 저장하는 로봇/토픽별 headerId 순번 (utils.HeaderSequencer)
+// 재시작하거나 여러 브릿지가 같은 로봇에 보내도 토픽마다 순번이 계속 증가합니다.
+// Redis에 닿지 않으면 이 프로세스가 마지막으로 발급한 값에서 이어서 발급합니다.
+type HeaderSequence struct {
+	client *redis.Client
+
+	mu       sync.Mutex
+	issued   map[string]int64 // 토픽별 마지막 발급 값
+	degraded bool             // Redis 장애로 메모리 순번을 쓰는 중
+}
+
+// NewHeaderSequence 새 headerId 순번 생성
+func NewHeaderSequence(client *redis.Client) *HeaderSequence {
+	return &HeaderSequence{client: client, issued: make(map[string]int64)}
+}
+
+// Next 토픽의 다음 headerId
+func (s *HeaderSequence) Next(topic string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), headerSequenceTimeout)
+	defer cancel()
+	value, err := nextHeaderScript.Run(ctx, s.client, []string{redisKeys.HeaderSequence(topic)}, s.issued[topic]).Int64()
+	if err != nil {
+		value = s.issued[topic] + 1
+		if !s.degraded {
+			utils.Logger.Warnf("⚠️ headerId sequence unavailable in Redis, continuing in memory: %v", err)
+			s.degraded = true
+		}
+	} else if s.degraded {
+		utils.Logger.Infof("✅ headerId sequence restored in Redis")
+		s.degraded = false
+	}
+	s.issued[topic] = value
+	return value
+}
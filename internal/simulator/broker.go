@@ -0,0 +1,490 @@
+// internal/simulator/broker.go
+package simulator
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+)
+
+// MQTT 3.1.1 패킷 종류
+const (
+	packetConnect     = 1
+	packetConnack     = 2
+	packetPublish     = 3
+	packetPuback      = 4
+	packetPubrec      = 5
+	packetPubrel      = 6
+	packetPubcomp     = 7
+	packetSubscribe   = 8
+	packetSuback      = 9
+	packetUnsubscribe = 10
+	packetUnsuback    = 11
+	packetPingreq     = 12
+	packetPingresp    = 13
+	packetDisconnect  = 14
+)
+
+// brokerMessage 브로커가 전달하는 메시지
+type brokerMessage struct {
+	topic   string
+	payload []byte
+	qos     byte
+	retain  bool
+}
+
+// brokerSession 연결된 클라이언트 하나
+type brokerSession struct {
+	clientID string
+	conn     net.Conn
+
+	writeMu  sync.Mutex
+	nextID   uint16
+	subs     map[string]byte // 구독 필터 → QoS
+	will     *brokerMessage
+	closedOK bool // DISCONNECT로 정상 종료 (will 발행 안 함)
+}
+
+// Broker 테스트와 로컬 실행용 인프로세스 MQTT 3.1.1 브로커
+// QoS 0/1 전달(QoS 2 발행은 받되 QoS 1로 전달), retained 메시지, will, +/# 와일드카드를 지원합니다.
+// 세션은 저장하지 않으며($share/<그룹>/ 구독은 그룹 없이 일반 구독으로 처리) 인증도 하지 않습니다.
+type Broker struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	sessions map[*brokerSession]struct{}
+	retained map[string]brokerMessage
+	closed   bool
+
+	wg sync.WaitGroup
+}
+
+// NewBroker 주소(예: "127.0.0.1:0")에서 연결을 받는 브로커 시작
+func NewBroker(addr string) (*Broker, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	b := &Broker{
+		listener: listener,
+		sessions: make(map[*brokerSession]struct{}),
+		retained: make(map[string]brokerMessage),
+	}
+	b.wg.Add(1)
+	go b.accept()
+	return b, nil
+}
+
+// URL 클라이언트가 연결할 브로커 주소 (tcp://host:port)
+func (b *Broker) URL() string {
+	return "tcp://" + b.listener.Addr().String()
+}
+
+// Close 리스너와 모든 연결 종료
+func (b *Broker) Close() {
+	b.mu.Lock()
+	b.closed = true
+	for session := range b.sessions {
+		session.conn.Close()
+	}
+	b.mu.Unlock()
+	b.listener.Close()
+	b.wg.Wait()
+}
+
+func (b *Broker) accept() {
+	defer b.wg.Done()
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			b.serve(conn)
+		}()
+	}
+}
+
+// serve 연결 하나의 패킷 처리 (연결이 끊기면 will 발행)
+func (b *Broker) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	header, body, err := readPacket(reader)
+	if err != nil || header>>4 != packetConnect {
+		return
+	}
+	session, err := parseConnect(body)
+	if err != nil {
+		return
+	}
+	session.conn = conn
+	session.subs = make(map[string]byte)
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	// 같은 클라이언트 ID로 다시 연결하면 이전 연결을 끊음
+	for other := range b.sessions {
+		if other.clientID == session.clientID && session.clientID != "" {
+			other.conn.Close()
+		}
+	}
+	b.sessions[session] = struct{}{}
+	b.mu.Unlock()
+
+	defer func() {
+		b.mu.Lock()
+		delete(b.sessions, session)
+		b.mu.Unlock()
+		if !session.closedOK && session.will != nil {
+			b.publish(*session.will)
+		}
+	}()
+
+	if err := session.write(packetConnack<<4, []byte{0, 0}); err != nil {
+		return
+	}
+	for {
+		header, body, err := readPacket(reader)
+		if err != nil {
+			return
+		}
+		if err := b.handle(session, header, body); err != nil {
+			return
+		}
+		if session.closedOK {
+			return
+		}
+	}
+}
+
+// handle CONNECT 이후 패킷 하나 처리
+func (b *Broker) handle(session *brokerSession, header byte, body []byte) error {
+	switch header >> 4 {
+	case packetPublish:
+		message, packetID, err := parsePublish(header, body)
+		if err != nil {
+			return err
+		}
+		switch message.qos {
+		case 1:
+			if err := session.write(packetPuback<<4, packetID); err != nil {
+				return err
+			}
+		case 2:
+			if err := session.write(packetPubrec<<4, packetID); err != nil {
+				return err
+			}
+		}
+		b.publish(message)
+	case packetPubrel:
+		if len(body) < 2 {
+			return errors.New("malformed PUBREL")
+		}
+		return session.write(packetPubcomp<<4, body[:2])
+	case packetPuback, packetPubrec, packetPubcomp:
+		// 브로커는 QoS 1까지만 전달하므로 클라이언트 확인은 기록하지 않음
+	case packetSubscribe:
+		return b.subscribe(session, body)
+	case packetUnsubscribe:
+		if len(body) < 2 {
+			return errors.New("malformed UNSUBSCRIBE")
+		}
+		rest := body[2:]
+		b.mu.Lock()
+		for len(rest) > 0 {
+			var filter string
+			var err error
+			if filter, rest, err = readString(rest); err != nil {
+				b.mu.Unlock()
+				return err
+			}
+			delete(session.subs, filter)
+		}
+		b.mu.Unlock()
+		return session.write(packetUnsuback<<4, body[:2])
+	case packetPingreq:
+		return session.write(packetPingresp<<4, nil)
+	case packetDisconnect:
+		session.closedOK = true
+	default:
+		return fmt.Errorf("unsupported MQTT packet type %d", header>>4)
+	}
+	return nil
+}
+
+// subscribe 구독 등록 후 SUBACK, 일치하는 retained 메시지 전달
+func (b *Broker) subscribe(session *brokerSession, body []byte) error {
+	if len(body) < 2 {
+		return errors.New("malformed SUBSCRIBE")
+	}
+	packetID, rest := body[:2], body[2:]
+	var filters []string
+	granted := []byte{}
+
+	b.mu.Lock()
+	for len(rest) > 0 {
+		filter, next, err := readString(rest)
+		if err != nil || len(next) < 1 {
+			b.mu.Unlock()
+			return errors.New("malformed SUBSCRIBE filter")
+		}
+		qos := next[0] & 0x03
+		if qos > 1 {
+			qos = 1
+		}
+		rest = next[1:]
+		filter = sharedTopic(filter)
+		session.subs[filter] = qos
+		filters = append(filters, filter)
+		granted = append(granted, qos)
+	}
+	var retained []brokerMessage
+	for _, message := range b.retained {
+		for i, filter := range filters {
+			if topicMatches(filter, message.topic) {
+				if message.qos > granted[i] {
+					message.qos = granted[i]
+				}
+				retained = append(retained, message)
+				break
+			}
+		}
+	}
+	b.mu.Unlock()
+
+	if err := session.write(packetSuback<<4, append(append([]byte{}, packetID...), granted...)); err != nil {
+		return err
+	}
+	for _, message := range retained {
+		if err := session.deliver(message, message.qos, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// publish retained 메시지를 갱신하고 일치하는 구독자에게 전달
+func (b *Broker) publish(message brokerMessage) {
+	type target struct {
+		session *brokerSession
+		qos     byte
+	}
+	var targets []target
+
+	b.mu.Lock()
+	if message.retain {
+		if len(message.payload) == 0 {
+			delete(b.retained, message.topic)
+		} else {
+			b.retained[message.topic] = message
+		}
+	}
+	for session := range b.sessions {
+		matched, qos := false, byte(0)
+		for filter, subQoS := range session.subs {
+			if topicMatches(filter, message.topic) {
+				matched = true
+				if subQoS > qos {
+					qos = subQoS
+				}
+			}
+		}
+		if matched {
+			if message.qos < qos {
+				qos = message.qos
+			}
+			targets = append(targets, target{session, qos})
+		}
+	}
+	b.mu.Unlock()
+
+	for _, t := range targets {
+		// 전달 실패는 해당 연결의 읽기 루프가 끊김으로 처리
+		_ = t.session.deliver(message, t.qos, false)
+	}
+}
+
+// deliver 클라이언트에 PUBLISH 전송
+func (s *brokerSession) deliver(message brokerMessage, qos byte, retain bool) error {
+	header := byte(packetPublish<<4) | qos<<1
+	if retain {
+		header |= 0x01
+	}
+	body := appendString(nil, message.topic)
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if qos > 0 {
+		s.nextID++
+		if s.nextID == 0 {
+			s.nextID = 1
+		}
+		body = binary.BigEndian.AppendUint16(body, s.nextID)
+	}
+	body = append(body, message.payload...)
+	return writePacket(s.conn, header, body)
+}
+
+// write 제어 패킷 전송
+func (s *brokerSession) write(header byte, body []byte) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return writePacket(s.conn, header, body)
+}
+
+// parseConnect CONNECT 가변 헤더와 페이로드 해석 (프로토콜 레벨 3, 4)
+func parseConnect(body []byte) (*brokerSession, error) {
+	_, rest, err := readString(body) // 프로토콜 이름 (MQTT, MQIsdp)
+	if err != nil || len(rest) < 4 {
+		return nil, errors.New("malformed CONNECT")
+	}
+	level, flags := rest[0], rest[1]
+	if level != 3 && level != 4 {
+		return nil, fmt.Errorf("unsupported MQTT protocol level %d", level)
+	}
+	rest = rest[4:] // 레벨, 플래그, keep alive
+
+	session := &brokerSession{}
+	if session.clientID, rest, err = readString(rest); err != nil {
+		return nil, err
+	}
+	if flags&0x04 != 0 {
+		will := &brokerMessage{qos: (flags >> 3) & 0x03, retain: flags&0x20 != 0}
+		if will.topic, rest, err = readString(rest); err != nil {
+			return nil, err
+		}
+		var payload string
+		if payload, rest, err = readString(rest); err != nil {
+			return nil, err
+		}
+		will.payload = []byte(payload)
+		session.will = will
+	}
+	// 사용자 이름/비밀번호는 확인하지 않음
+	return session, nil
+}
+
+// parsePublish PUBLISH 패킷 해석 (QoS > 0이면 패킷 ID도 반환)
+func parsePublish(header byte, body []byte) (brokerMessage, []byte, error) {
+	message := brokerMessage{qos: (header >> 1) & 0x03, retain: header&0x01 != 0}
+	topic, rest, err := readString(body)
+	if err != nil {
+		return message, nil, err
+	}
+	message.topic = topic
+	var packetID []byte
+	if message.qos > 0 {
+		if len(rest) < 2 {
+			return message, nil, errors.New("malformed PUBLISH")
+		}
+		packetID, rest = rest[:2], rest[2:]
+	}
+	message.payload = append([]byte(nil), rest...)
+	return message, packetID, nil
+}
+
+// sharedTopic $share/<그룹>/<토픽> 구독 필터의 토픽 부분
+func sharedTopic(filter string) string {
+	if rest, ok := strings.CutPrefix(filter, "$share/"); ok {
+		if _, topic, ok := strings.Cut(rest, "/"); ok {
+			return topic
+		}
+	}
+	return filter
+}
+
+// topicMatches MQTT 와일드카드(+, #) 필터 일치 여부 ($로 시작하는 토픽은 와일드카드로 시작하는 필터와 일치하지 않음)
+func topicMatches(filter, topic string) bool {
+	if strings.HasPrefix(topic, "$") && (strings.HasPrefix(filter, "+") || strings.HasPrefix(filter, "#")) {
+		return false
+	}
+	filterLevels := strings.Split(filter, "/")
+	topicLevels := strings.Split(topic, "/")
+	for i, level := range filterLevels {
+		if level == "#" {
+			return true
+		}
+		if i >= len(topicLevels) {
+			return false
+		}
+		if level != "+" && level != topicLevels[i] {
+			return false
+		}
+	}
+	return len(filterLevels) == len(topicLevels)
+}
+
+// readPacket 고정 헤더와 나머지 길이만큼의 본문 읽기
+func readPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		if i == 4 {
+			return 0, nil, errors.New("malformed remaining length")
+		}
+		digit, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(digit&0x7f) * multiplier
+		if digit&0x80 == 0 {
+			break
+		}
+		multiplier *= 128
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header, body, nil
+}
+
+// writePacket 고정 헤더(나머지 길이 포함)와 본문 쓰기
+func writePacket(w io.Writer, header byte, body []byte) error {
+	packet := []byte{header}
+	length := len(body)
+	for {
+		digit := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			digit |= 0x80
+		}
+		packet = append(packet, digit)
+		if length == 0 {
+			break
+		}
+	}
+	_, err := w.Write(append(packet, body...))
+	return err
+}
+
+// readString 2바이트 길이 접두사 문자열 읽기
+func readString(data []byte) (string, []byte, error) {
+	if len(data) < 2 {
+		return "", nil, errors.New("malformed MQTT string")
+	}
+	length := int(binary.BigEndian.Uint16(data))
+	if len(data) < 2+length {
+		return "", nil, errors.New("malformed MQTT string")
+	}
+	return string(data[2 : 2+length]), data[2+length:], nil
+}
+
+// appendString 2바이트 길이 접두사 문자열 추가
+func appendString(data []byte, value string) []byte {
+	data = binary.BigEndian.AppendUint16(data, uint16(len(value)))
+	return append(data, value...)
+}
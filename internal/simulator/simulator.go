@@ -0,0 +1,423 @@
+// internal/simulator/simulator.go
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/utils"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// Options 시뮬레이터 설정
+type Options struct {
+	Broker       string
+	ClientID     string
+	Username     string
+	Password     string
+	Manufacturer string
+	SerialNumber string
+
+	ActionDuration  time.Duration   // 액션 하나가 RUNNING 상태로 머무는 시간
+	StateInterval   time.Duration   // 주기적 state 발행 간격 (0이면 변화가 있을 때만 발행)
+	FailActionTypes map[string]bool // 실패(FAILED)로 끝낼 actionType
+	InferenceResult string          // 추론 액션 완료 시 resultDescription
+}
+
+// Simulator VDA 5050 가상 로봇
+// order/instantActions 토픽을 구독하고, 오더의 액션을 순서대로 RUNNING→FINISHED로 진행시키며
+// state/connection(LWT 포함)/factsheet 메시지를 발행합니다.
+type Simulator struct {
+	opts   Options
+	client mqtt.Client
+
+	mu          sync.Mutex
+	state       models.RobotStateMessage
+	cancelOrder context.CancelFunc
+	resume      chan struct{} // 일시정지 해제 알림 (일시정지 중에만 존재)
+
+	orders chan *models.OrderMessage
+	wg     sync.WaitGroup
+}
+
+// New 시뮬레이터 생성
+func New(opts Options) *Simulator {
+	if opts.ActionDuration <= 0 {
+		opts.ActionDuration = time.Second
+	}
+	s := &Simulator{
+		opts:   opts,
+		orders: make(chan *models.OrderMessage, 16),
+	}
+	s.state = models.RobotStateMessage{
+		Version:       "2.0.0",
+		Manufacturer:  opts.Manufacturer,
+		SerialNumber:  opts.SerialNumber,
+		OperatingMode: constants.OperatingModeAutomatic,
+		ActionStates:  []models.ActionState{},
+		NodeStates:    []models.NodeState{},
+		EdgeStates:    []models.EdgeState{},
+		Errors:        []models.ErrorInfo{},
+		Information:   []models.InfoMessage{},
+		BatteryState:  models.BatteryState{BatteryCharge: 100, BatteryHealth: 100, BatteryVoltage: 48},
+		SafetyState:   models.SafetyState{EStop: constants.EStopNone},
+	}
+	return s
+}
+
+// Start 브로커에 연결하고 오더 처리를 시작합니다. ctx가 취소되면 처리를 멈추며, 종료는 Stop으로 합니다.
+func (s *Simulator) Start(ctx context.Context) error {
+	connectionTopic := constants.GetMeiliConnectionTopic(s.opts.Manufacturer, s.opts.SerialNumber)
+	will, err := json.Marshal(s.connectionMessage(constants.ConnectionStateConnectionBroken))
+	if err != nil {
+		return err
+	}
+
+	mqttOpts := mqtt.NewClientOptions().
+		AddBroker(s.opts.Broker).
+		SetClientID(s.opts.ClientID).
+		SetUsername(s.opts.Username).
+		SetPassword(s.opts.Password).
+		SetAutoReconnect(true).
+		SetBinaryWill(connectionTopic, will, 1, true).
+		SetOnConnectHandler(s.onConnect)
+
+	s.client = mqtt.NewClient(mqttOpts)
+	if token := s.client.Connect(); token.Wait() && token.Error() != nil {
+		return fmt.Errorf("failed to connect simulator to %s: %w", s.opts.Broker, token.Error())
+	}
+
+	s.wg.Add(1)
+	go s.processOrders(ctx)
+	if s.opts.StateInterval > 0 {
+		s.wg.Add(1)
+		go s.publishPeriodically(ctx)
+	}
+	return nil
+}
+
+// Stop 처리 고루틴 종료를 기다린 뒤 OFFLINE을 발행하고 연결 종료 (Start의 ctx를 먼저 취소해야 함)
+func (s *Simulator) Stop() {
+	s.wg.Wait()
+	s.publish(constants.GetMeiliConnectionTopic(s.opts.Manufacturer, s.opts.SerialNumber),
+		s.connectionMessage(constants.ConnectionStateOffline), true)
+	s.client.Disconnect(250)
+	utils.Logger.Infof("🤖 Simulator %s stopped", s.opts.SerialNumber)
+}
+
+// onConnect 연결(재연결 포함) 시 구독, ONLINE/factsheet/state 발행
+func (s *Simulator) onConnect(client mqtt.Client) {
+	orderTopic := constants.GetMeiliOrderTopic(s.opts.Manufacturer, s.opts.SerialNumber)
+	instantTopic := constants.GetMeiliInstantActionsTopic(s.opts.Manufacturer, s.opts.SerialNumber)
+	client.Subscribe(orderTopic, 1, s.handleOrder)
+	client.Subscribe(instantTopic, 1, s.handleInstantActions)
+
+	s.publish(constants.GetMeiliConnectionTopic(s.opts.Manufacturer, s.opts.SerialNumber),
+		s.connectionMessage(constants.ConnectionStateOnline), true)
+	s.publishFactsheet()
+	s.publishState()
+	utils.Logger.Infof("🤖 Simulator %s/%s online (broker: %s)", s.opts.Manufacturer, s.opts.SerialNumber, s.opts.Broker)
+}
+
+// handleOrder 오더 수신 (처리 큐에 추가)
+func (s *Simulator) handleOrder(client mqtt.Client, msg mqtt.Message) {
+	var order models.OrderMessage
+	if err := json.Unmarshal(msg.Payload(), &order); err != nil {
+		utils.Logger.Errorf("❌ Simulator: invalid order payload: %v", err)
+		return
+	}
+	utils.Logger.Infof("🤖 Simulator: order %s received (%d node(s))", order.OrderID, len(order.Nodes))
+	s.orders <- &order
+}
+
+// handleInstantActions instantActions 처리 (즉시 FINISHED로 보고)
+func (s *Simulator) handleInstantActions(client mqtt.Client, msg mqtt.Message) {
+	var request models.FactsheetRequest
+	if err := json.Unmarshal(msg.Payload(), &request); err != nil {
+		utils.Logger.Errorf("❌ Simulator: invalid instantActions payload: %v", err)
+		return
+	}
+
+	publishFactsheet := false
+	s.mu.Lock()
+	for _, action := range request.Actions {
+		utils.Logger.Infof("🤖 Simulator: instant action %s (%s)", action.ActionType, action.ActionID)
+		switch action.ActionType {
+		case constants.ActionTypeCancelOrder:
+			if s.cancelOrder != nil {
+				s.cancelOrder()
+			}
+		case constants.ActionTypeStartPause:
+			s.state.Paused = true
+			if s.resume == nil {
+				s.resume = make(chan struct{})
+			}
+		case constants.ActionTypeStopPause:
+			s.state.Paused = false
+			if s.resume != nil {
+				close(s.resume)
+				s.resume = nil
+			}
+		case constants.ActionTypeInitPosition:
+			s.state.AgvPosition.PositionInitialized = true
+			s.state.AgvPosition.LocalizationScore = 1
+		case constants.ActionTypeFactsheetRequest:
+			publishFactsheet = true
+		}
+		s.state.ActionStates = append(s.state.ActionStates, models.ActionState{
+			ActionID:     action.ActionID,
+			ActionType:   action.ActionType,
+			ActionStatus: constants.ActionStatusFinished,
+		})
+	}
+	s.mu.Unlock()
+
+	if publishFactsheet {
+		s.publishFactsheet()
+	}
+	s.publishState()
+}
+
+// processOrders 큐의 오더를 순서대로 실행
+func (s *Simulator) processOrders(ctx context.Context) {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case order := <-s.orders:
+			s.runOrder(ctx, order)
+		}
+	}
+}
+
+// runOrder 오더의 노드별 액션을 순서대로 RUNNING→FINISHED(또는 FAILED)로 진행
+func (s *Simulator) runOrder(ctx context.Context, order *models.OrderMessage) {
+	orderCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s.mu.Lock()
+	s.cancelOrder = cancel
+	s.state.OrderID = order.OrderID
+	s.state.OrderUpdateID = order.OrderUpdateID
+	s.state.ActionStates = []models.ActionState{}
+	s.state.NodeStates = []models.NodeState{}
+	s.state.Errors = []models.ErrorInfo{}
+	for _, node := range order.Nodes {
+		nodeState := models.NodeState{NodeID: node.NodeID, Released: node.Released, SequenceID: node.SequenceID}
+		nodeState.NodePosition.X = float64(node.NodePosition.X)
+		nodeState.NodePosition.Y = float64(node.NodePosition.Y)
+		nodeState.NodePosition.Theta = float64(node.NodePosition.Theta)
+		s.state.NodeStates = append(s.state.NodeStates, nodeState)
+		for _, action := range node.Actions {
+			s.state.ActionStates = append(s.state.ActionStates, models.ActionState{
+				ActionID:          action.ActionID,
+				ActionType:        action.ActionType,
+				ActionDescription: action.ActionDescription,
+				ActionStatus:      constants.ActionStatusWaiting,
+			})
+		}
+	}
+	s.mu.Unlock()
+	s.publishState()
+
+	for _, node := range order.Nodes {
+		s.mu.Lock()
+		s.state.LastNodeID = node.NodeID
+		s.state.LastNodeSequenceID = node.SequenceID
+		s.state.AgvPosition.X = float64(node.NodePosition.X)
+		s.state.AgvPosition.Y = float64(node.NodePosition.Y)
+		s.state.AgvPosition.Theta = float64(node.NodePosition.Theta)
+		s.state.AgvPosition.MapID = node.NodePosition.MapID
+		s.state.NodeStates = removeNode(s.state.NodeStates, node.NodeID)
+		s.mu.Unlock()
+
+		for _, action := range node.Actions {
+			if !s.waitWhilePaused(orderCtx) {
+				s.failRemainingActions("order cancelled")
+				return
+			}
+
+			s.setActionStatus(action.ActionID, constants.ActionStatusRunning, "")
+			s.publishState()
+
+			select {
+			case <-orderCtx.Done():
+				s.failRemainingActions("order cancelled")
+				return
+			case <-time.After(s.opts.ActionDuration):
+			}
+
+			if s.opts.FailActionTypes[action.ActionType] {
+				s.setActionStatus(action.ActionID, constants.ActionStatusFailed, "simulated failure")
+				s.publishState()
+				utils.Logger.Warnf("🤖 Simulator: action %s (%s) failed", action.ActionID, action.ActionType)
+				return
+			}
+
+			result := ""
+			if action.ActionType == constants.ActionTypeInference {
+				result = s.opts.InferenceResult
+			}
+			s.setActionStatus(action.ActionID, constants.ActionStatusFinished, result)
+			s.publishState()
+		}
+	}
+
+	s.mu.Lock()
+	s.cancelOrder = nil
+	s.mu.Unlock()
+	utils.Logger.Infof("🤖 Simulator: order %s finished", order.OrderID)
+}
+
+// waitWhilePaused 일시정지 중이면 해제될 때까지 대기 (취소되면 false)
+func (s *Simulator) waitWhilePaused(ctx context.Context) bool {
+	for {
+		s.mu.Lock()
+		resume := s.resume
+		s.mu.Unlock()
+		if resume == nil {
+			return ctx.Err() == nil
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-resume:
+		}
+	}
+}
+
+// setActionStatus 액션 상태 변경
+func (s *Simulator) setActionStatus(actionID, status, result string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.state.ActionStates {
+		if s.state.ActionStates[i].ActionID == actionID {
+			s.state.ActionStates[i].ActionStatus = status
+			s.state.ActionStates[i].ResultDescription = result
+		}
+	}
+}
+
+// failRemainingActions 끝나지 않은 오더 액션을 FAILED로 보고
+func (s *Simulator) failRemainingActions(reason string) {
+	s.mu.Lock()
+	for i := range s.state.ActionStates {
+		switch s.state.ActionStates[i].ActionStatus {
+		case constants.ActionStatusWaiting, constants.ActionStatusInitializing, constants.ActionStatusRunning, constants.ActionStatusPaused:
+			s.state.ActionStates[i].ActionStatus = constants.ActionStatusFailed
+			s.state.ActionStates[i].ResultDescription = reason
+		}
+	}
+	s.cancelOrder = nil
+	s.mu.Unlock()
+	s.publishState()
+}
+
+// publishPeriodically 주기적으로 state 발행
+func (s *Simulator) publishPeriodically(ctx context.Context) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.opts.StateInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.publishState()
+		}
+	}
+}
+
+// publishState 현재 state 발행
+func (s *Simulator) publishState() {
+	s.mu.Lock()
+	s.state.HeaderID = utils.NextHeaderID(constants.GetMeiliStateTopic(s.opts.Manufacturer, s.opts.SerialNumber))
+	s.state.Timestamp = time.Now().Format(time.RFC3339Nano)
+	s.state.Driving = false
+	payload, err := json.Marshal(s.state)
+	s.mu.Unlock()
+	if err != nil {
+		utils.Logger.Errorf("❌ Simulator: failed to marshal state: %v", err)
+		return
+	}
+	s.publishRaw(constants.GetMeiliStateTopic(s.opts.Manufacturer, s.opts.SerialNumber), payload, false)
+}
+
+// publishFactsheet factsheet 발행
+func (s *Simulator) publishFactsheet() {
+	factsheet := models.FactsheetResponse{
+		HeaderID:     utils.NextHeaderID(constants.GetMeiliFactsheetTopic(s.opts.Manufacturer, s.opts.SerialNumber)),
+		Timestamp:    time.Now().Format(time.RFC3339Nano),
+		Version:      "2.0.0",
+		Manufacturer: s.opts.Manufacturer,
+		SerialNumber: s.opts.SerialNumber,
+		PhysicalParameters: models.PhysicalParameters{
+			SpeedMax: 1.0, AccelerationMax: 0.5, DecelerationMax: 0.5, Length: 1.0, Width: 0.6, HeightMax: 1.5,
+		},
+		ProtocolFeatures: models.ProtocolFeatures{
+			AgvActions: []models.AgvAction{
+				{ActionType: constants.ActionTypeInference, ActionScopes: []string{"NODE"}},
+				{ActionType: constants.ActionTypeTrajectory, ActionScopes: []string{"NODE"}, ActionParameters: []models.AgvActionParameter{
+					{Key: "trajectory_name", ValueDataType: "STRING", Enum: []string{"RG", "FI"}},
+					{Key: "arm", ValueDataType: "STRING", Enum: []string{constants.ArmLeft, constants.ArmRight}},
+				}},
+				{ActionType: constants.ActionTypeInitPosition, ActionScopes: []string{"INSTANT"}},
+				{ActionType: constants.ActionTypeCancelOrder, ActionScopes: []string{"INSTANT"}},
+			},
+		},
+		TypeSpecification: models.TypeSpecification{
+			SeriesName:        "simulator",
+			SeriesDescription: "mqtt-bridge VDA 5050 robot simulator",
+			AgvClass:          "FORKLIFT",
+			AgvKinematics:     "DIFF",
+			LocalizationTypes: []string{"NATURAL"},
+			NavigationTypes:   []string{"VIRTUAL_LINE_GUIDED"},
+		},
+	}
+	s.publish(constants.GetMeiliFactsheetTopic(s.opts.Manufacturer, s.opts.SerialNumber), factsheet, true)
+}
+
+func (s *Simulator) connectionMessage(state string) models.ConnectionStateMessage {
+	return models.ConnectionStateMessage{
+		HeaderID:        utils.NextHeaderID(constants.GetMeiliConnectionTopic(s.opts.Manufacturer, s.opts.SerialNumber)),
+		Timestamp:       time.Now().Format(time.RFC3339Nano),
+		Version:         "2.0.0",
+		Manufacturer:    s.opts.Manufacturer,
+		SerialNumber:    s.opts.SerialNumber,
+		ConnectionState: state,
+	}
+}
+
+func (s *Simulator) publish(topic string, message interface{}, retained bool) {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		utils.Logger.Errorf("❌ Simulator: failed to marshal message for %s: %v", topic, err)
+		return
+	}
+	s.publishRaw(topic, payload, retained)
+}
+
+func (s *Simulator) publishRaw(topic string, payload []byte, retained bool) {
+	token := s.client.Publish(topic, 1, retained, payload)
+	token.Wait()
+	if err := token.Error(); err != nil {
+		utils.Logger.Errorf("❌ Simulator: failed to publish to %s: %v", topic, err)
+	}
+}
+
+// removeNode 도달한 노드를 nodeStates에서 제거
+func removeNode(nodes []models.NodeState, nodeID string) []models.NodeState {
+	remaining := nodes[:0]
+	for _, node := range nodes {
+		if node.NodeID != nodeID {
+			remaining = append(remaining, node)
+		}
+	}
+	return remaining
+}
@@ -0,0 +1,224 @@
+// internal/simulator/simulator_test.go
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+const (
+	testManufacturer = "Roboligent"
+	testSerialNumber = "SIM-TEST-001"
+)
+
+// startSimulator 프로세스 내 브로커와 시뮬레이터를 띄우고 테스트 종료 시 정리
+func startSimulator(t *testing.T, opts Options) *Broker {
+	t.Helper()
+	broker, err := NewBroker("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("NewBroker: %v", err)
+	}
+	t.Cleanup(broker.Close)
+
+	opts.Broker = broker.URL()
+	opts.ClientID = "simulator-" + t.Name()
+	opts.Manufacturer = testManufacturer
+	opts.SerialNumber = testSerialNumber
+	sim := New(opts)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := sim.Start(ctx); err != nil {
+		cancel()
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() {
+		cancel()
+		sim.Stop()
+	})
+	return broker
+}
+
+// connectObserver 테스트용 MQTT 클라이언트 연결
+func connectObserver(t *testing.T, broker *Broker) mqtt.Client {
+	t.Helper()
+	client := mqtt.NewClient(mqtt.NewClientOptions().AddBroker(broker.URL()).SetClientID("observer-" + t.Name()))
+	if token := client.Connect(); token.Wait() && token.Error() != nil {
+		t.Fatalf("observer connect: %v", token.Error())
+	}
+	t.Cleanup(func() { client.Disconnect(100) })
+	return client
+}
+
+// subscribe 토픽 메시지를 채널로 전달
+func subscribe(t *testing.T, client mqtt.Client, topic string) <-chan []byte {
+	t.Helper()
+	messages := make(chan []byte, 256)
+	token := client.Subscribe(topic, 1, func(_ mqtt.Client, msg mqtt.Message) {
+		select {
+		case messages <- msg.Payload():
+		default:
+		}
+	})
+	if token.Wait() && token.Error() != nil {
+		t.Fatalf("subscribe %s: %v", topic, token.Error())
+	}
+	return messages
+}
+
+// waitForState 조건을 만족하는 state 메시지를 기다림
+func waitForState(t *testing.T, states <-chan []byte, match func(models.RobotStateMessage) bool) models.RobotStateMessage {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case payload := <-states:
+			var state models.RobotStateMessage
+			if err := json.Unmarshal(payload, &state); err != nil {
+				t.Fatalf("invalid state payload: %v", err)
+			}
+			if match(state) {
+				return state
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for robot state")
+		}
+	}
+}
+
+func publishOrder(t *testing.T, client mqtt.Client, order models.OrderMessage) {
+	t.Helper()
+	payload, err := json.Marshal(order)
+	if err != nil {
+		t.Fatalf("marshal order: %v", err)
+	}
+	token := client.Publish(constants.GetMeiliOrderTopic(testManufacturer, testSerialNumber), 1, false, payload)
+	if token.Wait() && token.Error() != nil {
+		t.Fatalf("publish order: %v", token.Error())
+	}
+}
+
+func testOrder(orderID string, actionTypes ...string) models.OrderMessage {
+	order := models.OrderMessage{
+		Version:      "2.0.0",
+		Manufacturer: testManufacturer,
+		SerialNumber: testSerialNumber,
+		OrderID:      orderID,
+	}
+	for i, actionType := range actionTypes {
+		order.Nodes = append(order.Nodes, models.OrderNode{
+			NodeID:     "node-" + actionType,
+			SequenceID: i * 2,
+			Released:   true,
+			Actions: []models.OrderAction{{
+				ActionType:   actionType,
+				ActionID:     orderID + "-" + actionType,
+				BlockingType: "HARD",
+			}},
+		})
+	}
+	return order
+}
+
+func actionStatus(state models.RobotStateMessage, actionID string) string {
+	for _, action := range state.ActionStates {
+		if action.ActionID == actionID {
+			return action.ActionStatus
+		}
+	}
+	return ""
+}
+
+func TestSimulatorReportsOnlineAndFactsheet(t *testing.T) {
+	broker := startSimulator(t, Options{ActionDuration: 10 * time.Millisecond})
+	observer := connectObserver(t, broker)
+
+	// ONLINE과 factsheet는 retained로 발행되므로 나중에 구독해도 받아야 함
+	connections := subscribe(t, observer, constants.GetMeiliConnectionTopic(testManufacturer, testSerialNumber))
+	factsheets := subscribe(t, observer, constants.GetMeiliFactsheetTopic(testManufacturer, testSerialNumber))
+
+	select {
+	case payload := <-connections:
+		var connection models.ConnectionStateMessage
+		if err := json.Unmarshal(payload, &connection); err != nil {
+			t.Fatalf("invalid connection payload: %v", err)
+		}
+		if connection.ConnectionState != constants.ConnectionStateOnline {
+			t.Fatalf("connectionState = %q, want %q", connection.ConnectionState, constants.ConnectionStateOnline)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for retained connection message")
+	}
+
+	select {
+	case payload := <-factsheets:
+		var factsheet models.FactsheetResponse
+		if err := json.Unmarshal(payload, &factsheet); err != nil {
+			t.Fatalf("invalid factsheet payload: %v", err)
+		}
+		if factsheet.SerialNumber != testSerialNumber {
+			t.Fatalf("factsheet serialNumber = %q, want %q", factsheet.SerialNumber, testSerialNumber)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for retained factsheet")
+	}
+}
+
+func TestSimulatorRunsOrderToFinished(t *testing.T) {
+	broker := startSimulator(t, Options{ActionDuration: 10 * time.Millisecond, InferenceResult: "DONE"})
+	observer := connectObserver(t, broker)
+	states := subscribe(t, observer, constants.GetMeiliStateTopic(testManufacturer, testSerialNumber))
+
+	order := testOrder("order-finished", constants.ActionTypeTrajectory, constants.ActionTypeInference)
+	publishOrder(t, observer, order)
+
+	trajectoryID := order.Nodes[0].Actions[0].ActionID
+	inferenceID := order.Nodes[1].Actions[0].ActionID
+
+	waitForState(t, states, func(state models.RobotStateMessage) bool {
+		return state.OrderID == order.OrderID && actionStatus(state, trajectoryID) == constants.ActionStatusRunning
+	})
+	final := waitForState(t, states, func(state models.RobotStateMessage) bool {
+		return state.OrderID == order.OrderID && actionStatus(state, inferenceID) == constants.ActionStatusFinished
+	})
+
+	if status := actionStatus(final, trajectoryID); status != constants.ActionStatusFinished {
+		t.Errorf("trajectory action status = %q, want %q", status, constants.ActionStatusFinished)
+	}
+	if len(final.NodeStates) != 0 {
+		t.Errorf("nodeStates = %d, want 0 after the last node", len(final.NodeStates))
+	}
+	if final.LastNodeID != order.Nodes[1].NodeID {
+		t.Errorf("lastNodeId = %q, want %q", final.LastNodeID, order.Nodes[1].NodeID)
+	}
+	for _, action := range final.ActionStates {
+		if action.ActionID == inferenceID && action.ResultDescription != "DONE" {
+			t.Errorf("inference resultDescription = %q, want %q", action.ResultDescription, "DONE")
+		}
+	}
+}
+
+func TestSimulatorFailsConfiguredActionType(t *testing.T) {
+	broker := startSimulator(t, Options{
+		ActionDuration:  10 * time.Millisecond,
+		FailActionTypes: map[string]bool{constants.ActionTypeInference: true},
+	})
+	observer := connectObserver(t, broker)
+	states := subscribe(t, observer, constants.GetMeiliStateTopic(testManufacturer, testSerialNumber))
+
+	order := testOrder("order-failed", constants.ActionTypeInference, constants.ActionTypeTrajectory)
+	publishOrder(t, observer, order)
+
+	failed := waitForState(t, states, func(state models.RobotStateMessage) bool {
+		return state.OrderID == order.OrderID && actionStatus(state, order.Nodes[0].Actions[0].ActionID) == constants.ActionStatusFailed
+	})
+	// 실패 이후의 액션은 시작되지 않아야 함
+	if status := actionStatus(failed, order.Nodes[1].Actions[0].ActionID); status != constants.ActionStatusWaiting {
+		t.Errorf("action after failure status = %q, want %q", status, constants.ActionStatusWaiting)
+	}
+}
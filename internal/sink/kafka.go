@@ -0,0 +1,62 @@
+// internal/sink/kafka.go
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/utils"
+	"strconv"
+	"strings"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaSink Kafka 토픽으로 레코드를 전달하는 싱크
+type KafkaSink struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaSink 새 Kafka 싱크 생성 (brokers는 콤마로 구분)
+func NewKafkaSink(brokers, topic string) (*KafkaSink, error) {
+	if brokers == "" || topic == "" {
+		return nil, fmt.Errorf("kafka sink requires brokers and topic")
+	}
+
+	writer := &kafka.Writer{
+		Addr:     kafka.TCP(strings.Split(brokers, ",")...),
+		Topic:    topic,
+		Balancer: &kafka.Hash{}, // 로봇별 순서 보장을 위해 시리얼 번호 키로 파티셔닝
+		Async:    true,          // MQTT 콜백을 블로킹하지 않음
+		Completion: func(messages []kafka.Message, err error) {
+			if err != nil {
+				utils.Logger.Errorf("❌ Kafka sink delivery failed (%d messages): %v", len(messages), err)
+			}
+		},
+	}
+
+	utils.Logger.Infof("✅ Kafka state sink created (brokers: %s, topic: %s)", brokers, topic)
+	return &KafkaSink{writer: writer}, nil
+}
+
+// Publish 레코드 전달
+func (k *KafkaSink) Publish(record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sink record: %v", err)
+	}
+
+	return k.writer.WriteMessages(context.Background(), kafka.Message{
+		Key:   []byte(record.SerialNumber),
+		Value: data,
+		Headers: []kafka.Header{
+			{Key: "schema-version", Value: []byte(strconv.Itoa(record.SchemaVersion))},
+			{Key: "kind", Value: []byte(record.Kind)},
+		},
+	})
+}
+
+// Close 대기 중인 메시지 전송 후 종료
+func (k *KafkaSink) Close() error {
+	return k.writer.Close()
+}
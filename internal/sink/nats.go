@@ -0,0 +1,62 @@
+// internal/sink/nats.go
+package sink
+
+import (
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/utils"
+	"strconv"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATSSink NATS 서브젝트로 레코드를 전달하는 싱크
+type NATSSink struct {
+	conn          *nats.Conn
+	subjectPrefix string
+}
+
+// NewNATSSink 새 NATS 싱크 생성
+// 서브젝트는 {prefix}.{kind}.{manufacturer}.{serialNumber} 형식입니다.
+func NewNATSSink(url, subjectPrefix string) (*NATSSink, error) {
+	if url == "" || subjectPrefix == "" {
+		return nil, fmt.Errorf("nats sink requires url and subject prefix")
+	}
+
+	conn, err := nats.Connect(url,
+		nats.Name("mqtt-bridge"),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			utils.Logger.Warnf("NATS sink disconnected: %v", err)
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %v", err)
+	}
+
+	utils.Logger.Infof("✅ NATS state sink created (url: %s, subject prefix: %s)", url, subjectPrefix)
+	return &NATSSink{conn: conn, subjectPrefix: subjectPrefix}, nil
+}
+
+// Publish 레코드 전달
+func (n *NATSSink) Publish(record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sink record: %v", err)
+	}
+
+	msg := &nats.Msg{
+		Subject: strings.Join([]string{n.subjectPrefix, record.Kind, record.Manufacturer, record.SerialNumber}, "."),
+		Data:    data,
+		Header:  nats.Header{},
+	}
+	msg.Header.Set("Schema-Version", strconv.Itoa(record.SchemaVersion))
+
+	return n.conn.PublishMsg(msg)
+}
+
+// Close 버퍼를 비우고 연결 종료
+func (n *NATSSink) Close() error {
+	return n.conn.Drain()
+}
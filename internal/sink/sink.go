@@ -0,0 +1,82 @@
+// internal/sink/sink.go
+package sink
+
+import (
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/config"
+	"strings"
+	"time"
+)
+
+// SchemaVersion 싱크로 전달되는 레코드 스키마 버전 (필드 변경 시 증가)
+const SchemaVersion = 1
+
+// Record Kind 레코드 종류 상수
+const (
+	KindState      = "state"
+	KindConnection = "connection"
+)
+
+// Sink Type 싱크 타입 상수
+const (
+	TypeNone  = ""
+	TypeKafka = "kafka"
+	TypeNATS  = "nats"
+)
+
+// Record 싱크로 전달되는 로봇 메시지 봉투
+type Record struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	Kind          string          `json:"kind"`
+	Manufacturer  string          `json:"manufacturer"`
+	SerialNumber  string          `json:"serialNumber"`
+	MQTTTopic     string          `json:"mqttTopic"`
+	ReceivedAt    time.Time       `json:"receivedAt"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// StateSink 로봇 상태/연결 메시지를 외부 스트림으로 전달하는 인터페이스
+type StateSink interface {
+	Publish(record *Record) error
+	Close() error
+}
+
+// NewRecord MQTT 토픽과 페이로드로 레코드 생성 (meili/v2/{manufacturer}/{serialNumber}/{kind})
+func NewRecord(kind, topic string, payload []byte) (*Record, error) {
+	if !json.Valid(payload) {
+		return nil, fmt.Errorf("payload on topic %s is not valid JSON", topic)
+	}
+
+	parts := strings.Split(topic, "/")
+	if len(parts) < 5 {
+		return nil, fmt.Errorf("unexpected robot topic format: %s", topic)
+	}
+
+	data := make([]byte, len(payload))
+	copy(data, payload)
+
+	return &Record{
+		SchemaVersion: SchemaVersion,
+		Kind:          kind,
+		Manufacturer:  parts[2],
+		SerialNumber:  parts[3],
+		MQTTTopic:     topic,
+		ReceivedAt:    time.Now(),
+		Payload:       data,
+	}, nil
+}
+
+// NewFromConfig 설정에 따라 싱크 생성 (미설정 시 nil 반환)
+func NewFromConfig(cfg *config.Config) (StateSink, error) {
+	switch strings.ToLower(cfg.StateSinkType) {
+	case TypeNone:
+		return nil, nil
+	case TypeKafka:
+		return NewKafkaSink(cfg.StateSinkURL, cfg.StateSinkTopic)
+	case TypeNATS:
+		return NewNATSSink(cfg.StateSinkURL, cfg.StateSinkTopic)
+	default:
+		return nil, fmt.Errorf("unsupported state sink type: %s", cfg.StateSinkType)
+	}
+}
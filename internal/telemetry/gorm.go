@@ -0,0 +1,98 @@
+// internal/telemetry/gorm.go
+package telemetry
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const gormSpanKey = "telemetry:span"
+
+// GormPlugin DB 쿼리마다 스팬을 생성하는 gorm 플러그인
+// 부모 컨텍스트는 db.WithContext(ctx)로 전달된 값을 사용합니다.
+type GormPlugin struct{}
+
+// Name 플러그인 이름
+func (GormPlugin) Name() string {
+	return "telemetry"
+}
+
+// Initialize 쿼리 콜백 등록
+func (p GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	registrations := []struct {
+		operation string
+		before    error
+		after     error
+	}{
+		{"create",
+			cb.Create().Before("gorm:create").Register("telemetry:before_create", startGormSpan("create")),
+			cb.Create().After("gorm:create").Register("telemetry:after_create", endGormSpan)},
+		{"query",
+			cb.Query().Before("gorm:query").Register("telemetry:before_query", startGormSpan("query")),
+			cb.Query().After("gorm:query").Register("telemetry:after_query", endGormSpan)},
+		{"update",
+			cb.Update().Before("gorm:update").Register("telemetry:before_update", startGormSpan("update")),
+			cb.Update().After("gorm:update").Register("telemetry:after_update", endGormSpan)},
+		{"delete",
+			cb.Delete().Before("gorm:delete").Register("telemetry:before_delete", startGormSpan("delete")),
+			cb.Delete().After("gorm:delete").Register("telemetry:after_delete", endGormSpan)},
+		{"row",
+			cb.Row().Before("gorm:row").Register("telemetry:before_row", startGormSpan("row")),
+			cb.Row().After("gorm:row").Register("telemetry:after_row", endGormSpan)},
+		{"raw",
+			cb.Raw().Before("gorm:raw").Register("telemetry:before_raw", startGormSpan("raw")),
+			cb.Raw().After("gorm:raw").Register("telemetry:after_raw", endGormSpan)},
+	}
+
+	for _, r := range registrations {
+		if r.before != nil {
+			return r.before
+		}
+		if r.after != nil {
+			return r.after
+		}
+	}
+	return nil
+}
+
+// startGormSpan 쿼리 시작 시 스팬 생성
+func startGormSpan(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx := db.Statement.Context
+		// 부모 스팬이 없는 쿼리(백그라운드 조회 등)는 추적하지 않음
+		if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+			return
+		}
+		_, span := Tracer().Start(ctx, "db."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("db.system", "postgresql"),
+				attribute.String("db.operation", operation),
+				attribute.String("db.sql.table", db.Statement.Table),
+			))
+		db.InstanceSet(gormSpanKey, span)
+	}
+}
+
+// endGormSpan 쿼리 종료 시 스팬 종료
+func endGormSpan(db *gorm.DB) {
+	value, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	span.SetAttributes(
+		attribute.String("db.statement", db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	err := db.Error
+	if err == gorm.ErrRecordNotFound {
+		err = nil
+	}
+	EndSpan(span, err)
+}
@@ -0,0 +1,73 @@
+// internal/telemetry/orders.go
+package telemetry
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OrderTracer 오더 전송부터 상태 업데이트, 완료까지 이어지는 오더 단위 스팬 관리
+type OrderTracer struct {
+	spans map[string]trace.Span
+	mu    sync.Mutex
+}
+
+// NewOrderTracer 새 오더 트레이서 생성
+func NewOrderTracer() *OrderTracer {
+	return &OrderTracer{
+		spans: make(map[string]trace.Span),
+	}
+}
+
+// Start 오더 스팬 시작 후 하위 작업에 사용할 컨텍스트 반환
+func (t *OrderTracer) Start(ctx context.Context, orderID string, attrs ...attribute.KeyValue) context.Context {
+	attrs = append(attrs, attribute.String("order.id", orderID))
+	ctx, span := Tracer().Start(ctx, "order", trace.WithAttributes(attrs...))
+
+	t.mu.Lock()
+	t.spans[orderID] = span
+	t.mu.Unlock()
+	return ctx
+}
+
+// Context 진행 중인 오더 스팬의 컨텍스트 반환 (없으면 Background)
+func (t *OrderTracer) Context(orderID string) context.Context {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if span, ok := t.spans[orderID]; ok {
+		return trace.ContextWithSpan(context.Background(), span)
+	}
+	return context.Background()
+}
+
+// AddEvent 오더 스팬에 이벤트 기록 (상태 업데이트 등)
+func (t *OrderTracer) AddEvent(orderID, name string, attrs ...attribute.KeyValue) {
+	t.mu.Lock()
+	span, ok := t.spans[orderID]
+	t.mu.Unlock()
+	if ok {
+		span.AddEvent(name, trace.WithAttributes(attrs...))
+	}
+}
+
+// End 오더 스팬 종료
+func (t *OrderTracer) End(orderID string, success bool, reason string) {
+	t.mu.Lock()
+	span, ok := t.spans[orderID]
+	delete(t.spans, orderID)
+	t.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	if success {
+		span.SetStatus(codes.Ok, "")
+	} else {
+		span.SetStatus(codes.Error, reason)
+	}
+	span.End()
+}
@@ -0,0 +1,73 @@
+// internal/telemetry/redis.go
+package telemetry
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type redisSpanKey struct{}
+
+// RedisHook Redis 명령마다 스팬을 생성하는 go-redis 훅
+type RedisHook struct{}
+
+var _ redis.Hook = RedisHook{}
+
+// BeforeProcess 명령 시작 시 스팬 생성 (부모 스팬이 있을 때만)
+func (RedisHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+		return ctx, nil
+	}
+	ctx, span := Tracer().Start(ctx, "redis."+cmd.Name(),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("db.system", "redis")))
+	return context.WithValue(ctx, redisSpanKey{}, span), nil
+}
+
+// AfterProcess 명령 종료 시 스팬 종료
+func (RedisHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	endRedisSpan(ctx, cmd.Err())
+	return nil
+}
+
+// BeforeProcessPipeline 파이프라인 시작 시 스팬 생성
+func (RedisHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+		return ctx, nil
+	}
+	ctx, span := Tracer().Start(ctx, "redis.pipeline",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "redis"),
+			attribute.Int("redis.pipeline_length", len(cmds)),
+		))
+	return context.WithValue(ctx, redisSpanKey{}, span), nil
+}
+
+// AfterProcessPipeline 파이프라인 종료 시 스팬 종료
+func (RedisHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	var err error
+	for _, cmd := range cmds {
+		if cmd.Err() != nil && cmd.Err() != redis.Nil {
+			err = cmd.Err()
+			break
+		}
+	}
+	endRedisSpan(ctx, err)
+	return nil
+}
+
+// endRedisSpan 컨텍스트에 저장된 스팬 종료
+func endRedisSpan(ctx context.Context, err error) {
+	span, ok := ctx.Value(redisSpanKey{}).(trace.Span)
+	if !ok {
+		return
+	}
+	if err == redis.Nil {
+		err = nil
+	}
+	EndSpan(span, err)
+}
@@ -0,0 +1,102 @@
+// internal/telemetry/telemetry.go
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"mqtt-bridge/internal/config"
+	"mqtt-bridge/internal/utils"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName 브릿지 트레이서 이름
+const instrumentationName = "mqtt-bridge"
+
+// ShutdownFunc 트레이스 익스포터 종료 함수
+type ShutdownFunc func(ctx context.Context) error
+
+// Setup OTLP(HTTP) 트레이스 익스포터를 설정합니다.
+// 엔드포인트가 비어 있으면 기본 no-op 프로바이더를 유지하여 계측 비용이 없습니다.
+func Setup(ctx context.Context, cfg *config.Config) (ShutdownFunc, error) {
+	if cfg.OTLPEndpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	// 표준 환경변수처럼 URL(http://host:4318)로 지정하거나 host:port로 지정
+	var opts []otlptracehttp.Option
+	if strings.Contains(cfg.OTLPEndpoint, "://") {
+		opts = append(opts, otlptracehttp.WithEndpointURL(cfg.OTLPEndpoint))
+	} else {
+		opts = append(opts, otlptracehttp.WithEndpoint(cfg.OTLPEndpoint))
+	}
+	if cfg.OTLPInsecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.OTelServiceName),
+		attribute.String("bridge.site_id", cfg.SiteID),
+		attribute.String("robot.serial_number", cfg.RobotSerialNumber),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.TraceSampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	utils.Logger.Infof("🔭 OpenTelemetry tracing enabled (endpoint: %s, sample ratio: %.2f)", cfg.OTLPEndpoint, cfg.TraceSampleRatio)
+	return provider.Shutdown, nil
+}
+
+// Tracer 브릿지 트레이서 반환
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// StartSpan 새 스팬 시작
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan 에러가 있으면 기록한 후 스팬 종료
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// StartPublishSpan MQTT 발행 스팬 시작
+func StartPublishSpan(ctx context.Context, topic, messageType string) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, "mqtt.publish "+messageType,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String("mqtt"),
+			semconv.MessagingDestinationName(topic),
+		))
+}
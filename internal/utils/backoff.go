@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"math/rand"
+	"time"
+)
+
+// JitteredBackoff attempt번째(0부터) 실패 후 기다릴 시간
+// base·2^attempt를 max로 자른 값의 절반에서 전체 사이의 무작위 시간이라 여러 발행이 동시에 재시도하지 않습니다.
+func JitteredBackoff(base time.Duration, attempt int, max time.Duration) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	backoff := base << uint(attempt)
+	if backoff <= 0 || backoff > max {
+		backoff = max
+	}
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
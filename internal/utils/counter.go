@@ -1,10 +1,37 @@
 package utils
 
-import "sync/atomic"
+import "sync"
 
-var headerIDCounter int64
+// HeaderSequencer 토픽별로 단조 증가하는 headerId 발급기
+type HeaderSequencer interface {
+	Next(topic string) int64
+}
+
+var (
+	headerSequencerMu sync.RWMutex
+	headerSequencer   HeaderSequencer
+	topicCounterMu    sync.Mutex
+	topicCounters     = make(map[string]int64)
+)
+
+// SetHeaderSequencer NextHeaderID가 사용할 발급기 설정 (nil이면 프로세스 메모리의 토픽별 카운터)
+func SetHeaderSequencer(sequencer HeaderSequencer) {
+	headerSequencerMu.Lock()
+	headerSequencer = sequencer
+	headerSequencerMu.Unlock()
+}
+
+// NextHeaderID 토픽의 다음 headerId (VDA 5050: 로봇의 토픽마다 1씩 증가)
+func NextHeaderID(topic string) int64 {
+	headerSequencerMu.RLock()
+	sequencer := headerSequencer
+	headerSequencerMu.RUnlock()
+	if sequencer != nil {
+		return sequencer.Next(topic)
+	}
 
-// GetNextHeaderID 는 원자적으로 1씩 증가하는 안전한 헤더 ID를 반환합니다.
-func GetNextHeaderID() int64 {
-	return atomic.AddInt64(&headerIDCounter, 1)
+	topicCounterMu.Lock()
+	defer topicCounterMu.Unlock()
+	topicCounters[topic]++
+	return topicCounters[topic]
 }
@@ -0,0 +1,226 @@
+// internal/utils/log_buffer.go
+package utils
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// logSubscriberBuffer 구독자별 대기 가능한 로그 수 (가득 차면 그 구독자는 끊김)
+const logSubscriberBuffer = 256
+
+// LogEntry 버퍼에 남긴 로그 한 줄
+type LogEntry struct {
+	Seq     uint64                 `json:"seq"`
+	Time    time.Time              `json:"time"`
+	Level   string                 `json:"level"`
+	Module  string                 `json:"module"` // 로그를 남긴 패키지 (workflow, messaging 등)
+	Message string                 `json:"message"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+}
+
+// LogFilter 로그 조회 조건 (빈 값은 조건 없음)
+type LogFilter struct {
+	Level   string   // 이 수준 이상만 (debug, info, warn, error)
+	Modules []string // 이 패키지들만
+}
+
+// Matches 로그가 조건에 맞는지
+func (f LogFilter) Matches(entry LogEntry) bool {
+	if f.Level != "" {
+		minimum, err := logrus.ParseLevel(f.Level)
+		if err == nil {
+			level, err := logrus.ParseLevel(entry.Level)
+			if err == nil && level > minimum {
+				return false
+			}
+		}
+	}
+	if len(f.Modules) > 0 {
+		for _, module := range f.Modules {
+			if strings.EqualFold(strings.TrimSpace(module), entry.Module) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// LogBuffer 최근 로그를 메모리에 보관하고 구독자에게 전달하는 logrus 훅 (SSH 없이 로그를 보기 위한 용도)
+type LogBuffer struct {
+	mu          sync.Mutex
+	entries     []LogEntry // 링 버퍼
+	next        int        // 다음에 쓸 위치
+	full        bool
+	seq         uint64
+	subscribers map[chan LogEntry]LogFilter
+}
+
+// NewLogBuffer size개까지 보관하는 로그 버퍼 생성
+func NewLogBuffer(size int) *LogBuffer {
+	if size <= 0 {
+		size = 1
+	}
+	return &LogBuffer{
+		entries:     make([]LogEntry, size),
+		subscribers: make(map[chan LogEntry]LogFilter),
+	}
+}
+
+// EnableLogBuffer Logger에 로그 버퍼 훅을 붙입니다 (size가 0 이하면 nil).
+func EnableLogBuffer(size int) *LogBuffer {
+	if size <= 0 {
+		return nil
+	}
+	buffer := NewLogBuffer(size)
+	Logger.AddHook(buffer)
+	return buffer
+}
+
+// Levels 모든 수준의 로그를 받음 (출력 수준은 Logger 설정을 따름)
+func (b *LogBuffer) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire 로그를 버퍼에 추가하고 조건이 맞는 구독자에게 전달
+func (b *LogBuffer) Fire(e *logrus.Entry) error {
+	entry := LogEntry{
+		Time:    e.Time,
+		Level:   e.Level.String(),
+		Module:  callerModule(),
+		Message: e.Message,
+	}
+	if len(e.Data) > 0 {
+		entry.Fields = make(map[string]interface{}, len(e.Data))
+		for key, value := range e.Data {
+			if err, ok := value.(error); ok {
+				value = err.Error()
+			}
+			entry.Fields[key] = value
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.seq++
+	entry.Seq = b.seq
+	b.entries[b.next] = entry
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+	for ch, filter := range b.subscribers {
+		if !filter.Matches(entry) {
+			continue
+		}
+		select {
+		case ch <- entry:
+		default:
+			// 따라오지 못하는 구독자 때문에 로깅이 막히지 않도록 끊습니다.
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+	return nil
+}
+
+// Recent 조건에 맞는 최근 로그를 오래된 순으로 최대 limit개 (limit이 0 이하면 전부)
+func (b *LogBuffer) Recent(filter LogFilter, limit int) []LogEntry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.recentLocked(filter, limit)
+}
+
+func (b *LogBuffer) recentLocked(filter LogFilter, limit int) []LogEntry {
+	ordered := b.entries[:b.next]
+	if b.full {
+		ordered = append(append([]LogEntry{}, b.entries[b.next:]...), b.entries[:b.next]...)
+	}
+	matched := make([]LogEntry, 0)
+	for _, entry := range ordered {
+		if filter.Matches(entry) {
+			matched = append(matched, entry)
+		}
+	}
+	if limit > 0 && len(matched) > limit {
+		matched = matched[len(matched)-limit:]
+	}
+	return matched
+}
+
+// Subscribe 조건에 맞는 최근 로그 backlog개와 이후 로그를 받을 채널을 반환합니다.
+// 채널이 닫히면 구독이 끊긴 것이며(느린 구독자), 다 쓰면 cancel을 호출해야 합니다.
+func (b *LogBuffer) Subscribe(filter LogFilter, backlog int) ([]LogEntry, <-chan LogEntry, func()) {
+	ch := make(chan LogEntry, logSubscriberBuffer)
+	b.mu.Lock()
+	var recent []LogEntry
+	if backlog > 0 {
+		recent = b.recentLocked(filter, backlog)
+	}
+	b.subscribers[ch] = filter
+	b.mu.Unlock()
+
+	cancel := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+	return recent, ch, cancel
+}
+
+// Size 보관 가능한 로그 수
+func (b *LogBuffer) Size() int {
+	return len(b.entries)
+}
+
+// callerModule 로그를 남긴 패키지 이름 (logrus와 utils 프레임은 건너뜀)
+func callerModule() string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" &&
+			!strings.Contains(frame.Function, "github.com/sirupsen/logrus") &&
+			!strings.HasPrefix(frame.Function, "mqtt-bridge/internal/utils.") {
+			return packageName(frame.Function)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// packageName "mqtt-bridge/internal/workflow.(*Executor).run" → "workflow"
+func packageName(function string) string {
+	slash := strings.LastIndex(function, "/")
+	name := function[slash+1:]
+	if dot := strings.Index(name, "."); dot >= 0 {
+		name = name[:dot]
+	}
+	return name
+}
+
+// String 사람이 읽는 한 줄 형식 (bridgectl logs 출력용)
+func (e LogEntry) String() string {
+	line := fmt.Sprintf("%s %-5s [%s] %s", e.Time.Format("15:04:05.000"), strings.ToUpper(e.Level), e.Module, e.Message)
+	keys := make([]string, 0, len(e.Fields))
+	for key := range e.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		line += fmt.Sprintf(" %s=%v", key, e.Fields[key])
+	}
+	return line
+}
@@ -0,0 +1,14 @@
+package utils
+
+import "hash/fnv"
+
+// ShardIndex 키를 n개 샤드 중 하나에 고정 배정 (FNV-1a, 같은 키는 항상 같은 샤드)
+// 수집 워커와 상태 기록기가 같은 함수로 로봇 시리얼 번호를 배정하여 로봇별 처리가 한 샤드에 모입니다.
+func ShardIndex(key string, n int) int {
+	if n <= 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(n))
+}
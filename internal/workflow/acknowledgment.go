@@ -0,0 +1,194 @@
+// internal/workflow/acknowledgment.go
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/utils"
+	"time"
+)
+
+// ackWatch 발행한 오더의 수신 확인 대기 (오더 ID마다 마지막으로 보낸 단계 하나)
+type ackWatch struct {
+	stepExecutionID uint
+	actionIDs       map[string]bool // 이 오더 메시지의 액션 ID (같은 orderId의 이전 단계 상태와 구분)
+	resends         int
+	timer           *time.Timer
+}
+
+// acknowledgedBy 상태 메시지가 이 오더 메시지를 받았음을 보여주는지 여부
+// 단계마다 같은 orderId로 보내므로 이 메시지의 액션을 하나라도 보고해야 받은 것으로 봅니다 (액션이 없으면 orderId만 확인).
+func (w *ackWatch) acknowledgedBy(stateMsg *models.RobotStateMessage) bool {
+	if len(w.actionIDs) == 0 {
+		return true
+	}
+	for _, actionState := range stateMsg.ActionStates {
+		if w.actionIDs[actionState.ActionID] {
+			return true
+		}
+	}
+	return false
+}
+
+// watchAcknowledgment 오더 메시지가 발행되면 수신 확인 제한 시간 시작 (아웃박스 발행 성공 콜백)
+// 같은 단계를 다시 보낸 경우에는 재전송 횟수를 이어서 셉니다.
+func (e *Executor) watchAcknowledgment(msg *models.OutboxMessage) {
+	timeout := e.config.OrderAckTimeout
+	if timeout <= 0 || msg.MessageType != "order" || msg.StepExecutionID == nil || msg.OrderID == "" {
+		return
+	}
+	var orderMsg models.OrderMessage
+	if err := json.Unmarshal([]byte(msg.Payload), &orderMsg); err != nil {
+		utils.Logger.Warnf("⚠️ Cannot watch acknowledgment of order %s: %v", msg.OrderID, err)
+		return
+	}
+	actionIDs := make(map[string]bool)
+	for _, node := range orderMsg.Nodes {
+		for _, action := range node.Actions {
+			actionIDs[action.ActionID] = true
+		}
+	}
+
+	e.ackMu.Lock()
+	defer e.ackMu.Unlock()
+	watch := &ackWatch{stepExecutionID: *msg.StepExecutionID, actionIDs: actionIDs}
+	if previous, ok := e.ackWatches[msg.OrderID]; ok {
+		if previous.timer != nil {
+			previous.timer.Stop()
+		}
+		if previous.stepExecutionID == watch.stepExecutionID {
+			watch.resends = previous.resends
+		}
+	}
+	orderID := msg.OrderID
+	watch.timer = time.AfterFunc(timeout, func() { e.handleAckTimeout(orderID, watch) })
+	e.ackWatches[orderID] = watch
+}
+
+// acknowledgeOrder 상태 메시지가 수신 확인을 기다리는 오더 메시지를 참조하면 대기 종료
+func (e *Executor) acknowledgeOrder(stateMsg *models.RobotStateMessage) {
+	e.ackMu.Lock()
+	defer e.ackMu.Unlock()
+	watch, ok := e.ackWatches[stateMsg.OrderID]
+	if !ok || !watch.acknowledgedBy(stateMsg) {
+		return
+	}
+	if watch.timer != nil {
+		watch.timer.Stop()
+	}
+	delete(e.ackWatches, stateMsg.OrderID)
+	utils.Logger.Debugf("📬 Order %s acknowledged by robot %s (step execution %d)",
+		stateMsg.OrderID, stateMsg.SerialNumber, watch.stepExecutionID)
+}
+
+// handleAckTimeout 제한 시간 안에 수신 확인이 없으면 재전송하고, 재전송 한도를 넘으면 단계를 NOT_ACKNOWLEDGED로 실패 처리
+func (e *Executor) handleAckTimeout(orderID string, watch *ackWatch) {
+	e.ackMu.Lock()
+	if e.ackWatches[orderID] != watch {
+		e.ackMu.Unlock()
+		return // 이미 확인되었거나 다음 단계 오더로 교체됨
+	}
+	watch.timer = nil
+	resend := watch.resends < e.config.OrderAckRetries
+	if resend {
+		watch.resends++
+	} else {
+		delete(e.ackWatches, orderID)
+	}
+	e.ackMu.Unlock()
+
+	var step models.StepExecution
+	if err := e.db.Preload("Execution").First(&step, watch.stepExecutionID).Error; err != nil {
+		utils.Logger.Errorf("❌ Failed to load step execution %d for acknowledgment timeout: %v", watch.stepExecutionID, err)
+		e.forgetAcknowledgment(orderID, watch)
+		return
+	}
+	if step.Status != constants.StepExecutionStatusRunning {
+		e.forgetAcknowledgment(orderID, watch)
+		return
+	}
+
+	timeout := e.config.OrderAckTimeout
+	if resend {
+		utils.Logger.Warnf("📭 Robot %s did not acknowledge order %s (step %d) within %v, re-sending (%d/%d)",
+			step.Execution.SerialNumber, orderID, step.StepOrder, timeout, watch.resends, e.config.OrderAckRetries)
+		if err := e.resendOrder(&step); err != nil {
+			utils.Logger.Errorf("❌ Failed to re-send order %s: %v", orderID, err)
+			e.forgetAcknowledgment(orderID, watch)
+			e.stepManager.failStep(&step, &step.Execution, constants.StepExecutionStatusNotAcknowledged,
+				fmt.Sprintf("robot did not acknowledge order within %v and re-send failed: %v", timeout, err))
+		}
+		return
+	}
+
+	utils.Logger.Errorf("📭 Robot %s never acknowledged order %s (step %d) after %d re-send(s)",
+		step.Execution.SerialNumber, orderID, step.StepOrder, watch.resends)
+	e.stepManager.failStep(&step, &step.Execution, constants.StepExecutionStatusNotAcknowledged,
+		fmt.Sprintf("robot did not acknowledge order within %v (%d re-send(s))", timeout, watch.resends))
+}
+
+// forgetAcknowledgment 더 기다릴 필요가 없는 수신 확인 대기 제거 (그 사이 교체된 대기는 유지)
+func (e *Executor) forgetAcknowledgment(orderID string, watch *ackWatch) {
+	e.ackMu.Lock()
+	if e.ackWatches[orderID] == watch {
+		delete(e.ackWatches, orderID)
+	}
+	e.ackMu.Unlock()
+}
+
+// suspendAcknowledgment 오더가 일시정지되는 동안 수신 확인 제한 시간을 멈춤 (대기는 유지)
+func (e *Executor) suspendAcknowledgment(orderID string) {
+	e.ackMu.Lock()
+	defer e.ackMu.Unlock()
+	if watch, ok := e.ackWatches[orderID]; ok && watch.timer != nil {
+		watch.timer.Stop()
+		watch.timer = nil
+	}
+}
+
+// resumeAcknowledgment 일시정지가 풀리면 멈춰 둔 수신 확인 제한 시간을 처음부터 다시 시작
+func (e *Executor) resumeAcknowledgment(orderID string) {
+	timeout := e.config.OrderAckTimeout
+	e.ackMu.Lock()
+	defer e.ackMu.Unlock()
+	watch, ok := e.ackWatches[orderID]
+	if !ok || watch.timer != nil || timeout <= 0 {
+		return
+	}
+	watch.timer = time.AfterFunc(timeout, func() { e.handleAckTimeout(orderID, watch) })
+}
+
+// resendOrder 단계의 마지막 오더 메시지를 새 headerId/timestamp로 아웃박스에 다시 넣고 발행
+// 발행이 미뤄지면 아웃박스 재시도가 보내며, 발행되면 수신 확인 대기가 다시 시작됩니다.
+func (e *Executor) resendOrder(step *models.StepExecution) error {
+	var last models.OutboxMessage
+	if err := e.db.Where("step_execution_id = ? AND message_type = ?", step.ID, "order").
+		Order("id DESC").First(&last).Error; err != nil {
+		return fmt.Errorf("order message not found: %v", err)
+	}
+	var orderMsg models.OrderMessage
+	if err := json.Unmarshal([]byte(last.Payload), &orderMsg); err != nil {
+		return fmt.Errorf("invalid order message %d: %v", last.ID, err)
+	}
+	orderMsg.HeaderID = utils.NextHeaderID(last.Topic)
+	orderMsg.Timestamp = time.Now().Format(time.RFC3339Nano)
+
+	msg, err := e.outbox.Enqueue(e.db, &models.OutboxMessage{
+		Topic:           last.Topic,
+		MessageType:     last.MessageType,
+		OrderID:         last.OrderID,
+		CorrelationID:   last.CorrelationID,
+		HeaderID:        orderMsg.HeaderID,
+		StepExecutionID: last.StepExecutionID,
+	}, &orderMsg)
+	if err != nil {
+		return err
+	}
+	if err := e.outbox.Dispatch(context.Background(), msg); err != nil && msg.Status != constants.OutboxStatusFailed {
+		utils.Logger.Warnf("⚠️ Re-sent order %s deferred to outbox retry: %v", last.OrderID, err)
+	}
+	return nil
+}
@@ -0,0 +1,269 @@
+// internal/workflow/charging.go
+package workflow
+
+import (
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/common/idgen"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/utils"
+	"sync"
+	"time"
+)
+
+const (
+	// chargingCheckInterval state 메시지마다가 아니라 이 간격으로만 충전 정책을 평가
+	chargingCheckInterval = 10 * time.Second
+	// chargingRedispatchInterval 충전 명령을 보냈는데도 충전을 시작하지 않으면 다시 보내기까지의 간격
+	chargingRedispatchInterval = 5 * time.Minute
+)
+
+// 자동 충전 상태
+const (
+	ChargingStateNoPolicy    = "NO_POLICY"   // 적용되는 정책 없음
+	ChargingStateAvailable   = "AVAILABLE"   // 잔량이 충분하여 배차 가능
+	ChargingStateBusy        = "BUSY"        // 잔량이 부족하지만 실행 중인 오더가 끝나기를 기다림
+	ChargingStateCharging    = "CHARGING"    // 충전 명령을 보냈거나 충전 중 (배차 불가)
+	ChargingStateMaintenance = "MAINTENANCE" // 운영자가 설정한 유지보수 중 (자동 충전하지 않음)
+)
+
+// ChargingStatus 로봇의 자동 충전 상태
+type ChargingStatus struct {
+	SerialNumber   string     `json:"serial_number"`
+	State          string     `json:"state"`
+	Policy         string     `json:"policy,omitempty"`
+	LowBattery     float64    `json:"low_battery,omitempty"`
+	ResumeBattery  float64    `json:"resume_battery,omitempty"`
+	CommandType    string     `json:"command_type,omitempty"`
+	BatteryCharge  float64    `json:"battery_charge"`
+	Charging       bool       `json:"charging"` // 로봇이 보고한 batteryState.charging
+	LastDispatch   string     `json:"last_dispatch,omitempty"`
+	LastDispatchAt *time.Time `json:"last_dispatch_at,omitempty"`
+	LastError      string     `json:"last_error,omitempty"` // 마지막 평가/배차 오류 (충전 명령을 보내면 지움)
+	EvaluatedAt    *time.Time `json:"evaluated_at,omitempty"`
+}
+
+// ChargingMonitor 로봇 그룹별 충전 정책에 따라 배터리가 부족한 로봇을 충전소로 보냅니다.
+// 잔량이 정책의 하한 미만이고 실행 중인 오더가 없으면 충전 사유의 유지보수 모드로 표시한 뒤 충전 명령을 배차하고,
+// 재개 잔량 이상 충전되면 유지보수를 해제하여 다시 배차 가능하게 합니다.
+// 상태를 유지보수 사유로 기록하므로 재시작 후에도 이어지며, 운영자가 설정한 유지보수는 건드리지 않습니다.
+type ChargingMonitor struct {
+	executor *Executor
+
+	mu         sync.Mutex
+	status     ChargingStatus
+	evaluating bool
+	lastCheck  time.Time
+}
+
+// NewChargingMonitor 브릿지가 관리하는 로봇의 자동 충전 모니터 생성
+func NewChargingMonitor(executor *Executor) *ChargingMonitor {
+	return &ChargingMonitor{
+		executor: executor,
+		status: ChargingStatus{
+			SerialNumber: executor.config.RobotSerialNumber,
+			State:        ChargingStateNoPolicy,
+		},
+	}
+}
+
+// ObserveState 배터리 잔량을 기록하고 평가 간격이 지났으면 백그라운드에서 정책 평가
+func (m *ChargingMonitor) ObserveState(state *models.RobotStateMessage) {
+	if state.SerialNumber != m.status.SerialNumber {
+		return
+	}
+	m.mu.Lock()
+	m.status.BatteryCharge = state.BatteryState.BatteryCharge
+	m.status.Charging = state.BatteryState.Charging
+	if m.evaluating || time.Since(m.lastCheck) < chargingCheckInterval {
+		m.mu.Unlock()
+		return
+	}
+	m.evaluating = true
+	battery, charging := m.status.BatteryCharge, m.status.Charging
+	m.mu.Unlock()
+
+	go m.evaluate(battery, charging)
+}
+
+// Status 로봇의 자동 충전 상태 (브릿지가 관리하지 않는 로봇이면 오류)
+func (m *ChargingMonitor) Status(serialNumber string) (*ChargingStatus, error) {
+	if serialNumber != m.status.SerialNumber {
+		return nil, apperr.New(apperr.CodeNotFound, "robot %s is not managed by this bridge", serialNumber).WithField("serial_number")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	status := m.status
+	return &status, nil
+}
+
+// evaluate 정책을 평가하고 결과를 상태에 기록
+func (m *ChargingMonitor) evaluate(battery float64, charging bool) {
+	policy, state, err := m.check(battery, charging)
+
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.evaluating = false
+	m.lastCheck = now
+	m.status.EvaluatedAt = &now
+	m.status.State = state
+	m.status.Policy, m.status.LowBattery, m.status.ResumeBattery, m.status.CommandType = "", 0, 0, ""
+	if policy != nil {
+		m.status.Policy = policy.Name
+		m.status.LowBattery = policy.LowBattery
+		m.status.ResumeBattery = policy.ResumeBattery
+		m.status.CommandType = policy.CommandType
+	}
+	if err != nil {
+		m.status.LastError = err.Error()
+		utils.Logger.Errorf("❌ Charging policy check failed for robot %s: %v", m.status.SerialNumber, err)
+	}
+}
+
+// check 충전 배차/복귀를 수행하고 적용된 정책과 상태를 반환
+func (m *ChargingMonitor) check(battery float64, charging bool) (*models.ChargingPolicy, string, error) {
+	db, siteID, serialNumber := m.executor.db, m.executor.config.SiteID, m.status.SerialNumber
+
+	policy, err := repository.FindChargingPolicyForRobot(db, siteID, serialNumber)
+	if err != nil {
+		return nil, m.currentState(), err
+	}
+	maintenance, err := repository.ActiveMaintenance(db, siteID, serialNumber)
+	if err != nil {
+		return policy, m.currentState(), err
+	}
+
+	if repository.IsChargingMaintenance(maintenance) {
+		// 정책이 삭제/비활성화되었으면 로봇이 묶여 있지 않도록 바로 복귀
+		if policy == nil || battery >= policy.ResumeBattery {
+			if err := repository.ClearRobotMaintenance(db, siteID, serialNumber); err != nil {
+				return policy, ChargingStateCharging, err
+			}
+			utils.Logger.Infof("🔋 Robot %s charged to %.1f%%, available for orders again", serialNumber, battery)
+			if policy == nil {
+				return nil, ChargingStateNoPolicy, nil
+			}
+			return policy, ChargingStateAvailable, nil
+		}
+		// 충전 명령이 실패했거나 충전소에 도착하지 못했으면 일정 시간 뒤 다시 보냄
+		if !charging && m.redispatchDue() {
+			active, err := repository.HasActiveOrders(db, siteID, serialNumber)
+			if err != nil {
+				return policy, ChargingStateCharging, err
+			}
+			if !active {
+				utils.Logger.Warnf("🔋 Robot %s is still not charging at %.1f%%, sending %s again",
+					serialNumber, battery, policy.CommandType)
+				if err := m.dispatch(policy); err != nil {
+					return policy, ChargingStateCharging, err
+				}
+			}
+		}
+		return policy, ChargingStateCharging, nil
+	}
+
+	if policy == nil {
+		return nil, ChargingStateNoPolicy, nil
+	}
+	if maintenance != nil {
+		return policy, ChargingStateMaintenance, nil
+	}
+	if battery >= policy.LowBattery {
+		return policy, ChargingStateAvailable, nil
+	}
+
+	active, err := repository.HasActiveOrders(db, siteID, serialNumber)
+	if err != nil {
+		return policy, ChargingStateAvailable, err
+	}
+	if active {
+		utils.Logger.Debugf("🔋 Robot %s battery %.1f%% is below %.1f%%, waiting for running orders to finish",
+			serialNumber, battery, policy.LowBattery)
+		return policy, ChargingStateBusy, nil
+	}
+	// 직전 충전 명령이 실패했으면 재전송 간격 동안 다시 보내지 않음
+	if !charging && m.lastDispatchFailed() && !m.redispatchDue() {
+		return policy, ChargingStateAvailable, nil
+	}
+
+	// 유지보수를 먼저 설정하여 충전 명령 배차 중에 PLC 명령이 끼어들지 않도록 함
+	reason := fmt.Sprintf("%sbattery %.1f%% below %.1f%% (policy %s)",
+		repository.ChargingMaintenancePrefix, battery, policy.LowBattery, policy.Name)
+	if err := repository.SetRobotMaintenance(db, siteID, serialNumber, reason, nil); err != nil {
+		return policy, ChargingStateAvailable, err
+	}
+	if charging {
+		utils.Logger.Infof("🔋 Robot %s is already charging at %.1f%%, holding new orders until %.1f%%",
+			serialNumber, battery, policy.ResumeBattery)
+		return policy, ChargingStateCharging, nil
+	}
+	if err := m.dispatch(policy); err != nil {
+		if clearErr := repository.ClearRobotMaintenance(db, siteID, serialNumber); clearErr != nil {
+			utils.Logger.Errorf("❌ Failed to clear charging maintenance for robot %s: %v", serialNumber, clearErr)
+			return policy, ChargingStateCharging, err
+		}
+		return policy, ChargingStateAvailable, err
+	}
+	return policy, ChargingStateCharging, nil
+}
+
+// dispatch 정책의 충전 명령을 PLC 명령과 같은 경로로 실행 (상관 ID는 charging- 접두사)
+func (m *ChargingMonitor) dispatch(policy *models.ChargingPolicy) error {
+	db := m.executor.db
+	var definition models.CommandDefinition
+	if err := db.Where("command_type = ? AND is_active = ?", policy.CommandType, true).First(&definition).Error; err != nil {
+		return apperr.Wrap(apperr.CodeCommandNotFound, err, "charging command %q of policy %s not defined or inactive",
+			policy.CommandType, policy.Name)
+	}
+
+	now := time.Now()
+	command := &models.Command{
+		CommandDefinitionID: definition.ID,
+		Status:              constants.CommandStatusPending,
+		RequestTime:         now,
+		CorrelationID:       repository.ChargingCorrelationPrefix + idgen.CorrelationID(),
+	}
+	if err := db.Create(command).Error; err != nil {
+		return fmt.Errorf("failed to record charging command: %w", err)
+	}
+	command.CommandDefinition = definition
+
+	m.mu.Lock()
+	m.status.LastDispatch = fmt.Sprintf("%s (cid=%s)", policy.CommandType, command.CorrelationID)
+	m.status.LastDispatchAt = &now
+	m.mu.Unlock()
+
+	utils.Logger.Warnf("🔋 Sending robot %s to charge: %s (policy %s, cid=%s)",
+		m.status.SerialNumber, policy.CommandType, policy.Name, command.CorrelationID)
+	if err := m.executor.ExecuteCommandOrder(command); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	m.status.LastError = ""
+	m.mu.Unlock()
+	return nil
+}
+
+// redispatchDue 마지막 충전 명령 후 재전송 간격이 지났는지 (재시작 후 첫 평가는 바로 재전송)
+func (m *ChargingMonitor) redispatchDue() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status.LastDispatchAt == nil || time.Since(*m.status.LastDispatchAt) >= chargingRedispatchInterval
+}
+
+// lastDispatchFailed 마지막 충전 명령 배차가 실패했는지
+func (m *ChargingMonitor) lastDispatchFailed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status.LastDispatchAt != nil && m.status.LastError != ""
+}
+
+// currentState 평가에 실패했을 때 유지할 직전 상태
+func (m *ChargingMonitor) currentState() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status.State
+}
@@ -0,0 +1,116 @@
+// internal/workflow/concurrency.go
+package workflow
+
+import (
+	"fmt"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/utils"
+	"time"
+)
+
+// concurrencyRecheckInterval 템플릿 동시 실행 제한으로 대기 중인 오더를 다시 확인하는 간격
+// 다른 브릿지의 오더가 끝나 자리가 나는 것은 알림이 없으므로 주기적으로 확인합니다.
+const concurrencyRecheckInterval = 5 * time.Second
+
+// waitForConcurrencySlot 동시 실행 제한에 걸린 오더를 대기 목록에 넣고 PLC에 대기 순번을 알립니다.
+func (e *Executor) waitForConcurrencySlot(commandExecution *models.CommandExecution, execution *models.OrderExecution) {
+	utils.Logger.Infof("🚦 Order %s waiting for template %d concurrency slot (position %d, cid=%s)",
+		execution.OrderID, execution.TemplateID, execution.QueuePosition, execution.CorrelationID)
+	e.scheduleWaitingOrder(execution.ID, concurrencyRecheckInterval)
+	e.sendResponseToPLC(commandExecution.Command.CorrelationID, commandExecution.Command.CommandDefinition.CommandType,
+		constants.CommandStatusRunning, fmt.Sprintf("Queued for template %d (position %d)", execution.TemplateID, execution.QueuePosition))
+}
+
+// scheduleWaitingOrder delay 후에 대기 중인 오더의 자리를 다시 확인하도록 예약
+func (e *Executor) scheduleWaitingOrder(id uint, delay time.Duration) {
+	e.queuedMu.Lock()
+	defer e.queuedMu.Unlock()
+	if previous, ok := e.waitingOrders[id]; ok {
+		previous.Stop()
+	}
+	e.waitingOrders[id] = time.AfterFunc(delay, func() { e.retryWaitingOrder(id) })
+}
+
+// retryWaitingOrder 대기 중인 오더에 자리가 났으면 첫 단계를 실행하고, 아니면 대기 순번을 갱신하고 다시 예약
+func (e *Executor) retryWaitingOrder(id uint) {
+	e.queuedMu.Lock()
+	timer, ok := e.waitingOrders[id]
+	e.queuedMu.Unlock()
+	if !ok {
+		return
+	}
+	done := func() {
+		e.queuedMu.Lock()
+		if e.waitingOrders[id] == timer {
+			delete(e.waitingOrders, id)
+		}
+		e.queuedMu.Unlock()
+	}
+
+	var execution models.OrderExecution
+	if err := e.db.First(&execution, id).Error; err != nil {
+		utils.Logger.Errorf("❌ Waiting order execution %d not found: %v", id, err)
+		done()
+		return
+	}
+	if execution.Status != constants.OrderExecutionStatusWaiting {
+		done() // 취소/비상 정지로 이미 종료됨
+		return
+	}
+
+	previousPosition := execution.QueuePosition
+	admitted, err := repository.AdmitOrderExecution(e.db, &execution)
+	if err != nil {
+		utils.Logger.Errorf("❌ Failed to admit waiting order %s: %v", execution.OrderID, err)
+		e.scheduleWaitingOrder(id, concurrencyRecheckInterval)
+		return
+	}
+	if !admitted && execution.Status != constants.OrderExecutionStatusWaiting {
+		done() // 확인 중에 종료됨
+		return
+	}
+	if !admitted {
+		if execution.QueuePosition != previousPosition {
+			utils.Logger.Infof("🚦 Order %s is now at position %d for template %d",
+				execution.OrderID, execution.QueuePosition, execution.TemplateID)
+		}
+		e.scheduleWaitingOrder(id, concurrencyRecheckInterval)
+		return
+	}
+	done()
+
+	template, err := repository.LoadExpandedTemplate(e.db, execution.SiteID, execution.TemplateID)
+	if err != nil {
+		utils.Logger.Errorf("🧩 Failed to load order template %d for %s: %v", execution.TemplateID, execution.OrderID, err)
+		now := time.Now()
+		repository.UpdateOrderExecutionStatus(e.db, &execution, constants.OrderExecutionStatusFailed, &now)
+		e.OnOrderCompleted(&execution, false)
+		return
+	}
+	utils.Logger.Infof("🚦 Order %s admitted for template %d", execution.OrderID, execution.TemplateID)
+	execution.StartedAt = time.Now()
+	execution.DeadlineAt = e.orderDeadline(template, execution.StartedAt)
+	e.db.Model(&execution).Updates(map[string]interface{}{"started_at": execution.StartedAt, "deadline_at": execution.DeadlineAt})
+	e.stepManager.ExecuteNextStep(&execution, template)
+}
+
+// wakeWaitingOrders 이 브릿지의 오더가 끝나 자리가 났을 수 있으므로 대기 중인 오더를 바로 다시 확인
+func (e *Executor) wakeWaitingOrders() {
+	e.queuedMu.Lock()
+	defer e.queuedMu.Unlock()
+	for _, timer := range e.waitingOrders {
+		timer.Reset(0)
+	}
+}
+
+// dropWaitingOrders 대기 중인 오더를 모두 대기 목록에서 제거 (취소/비상 정지용, 오더 상태는 호출자가 종료)
+func (e *Executor) dropWaitingOrders() {
+	e.queuedMu.Lock()
+	defer e.queuedMu.Unlock()
+	for id, timer := range e.waitingOrders {
+		timer.Stop()
+		delete(e.waitingOrders, id)
+	}
+}
@@ -0,0 +1,216 @@
+// internal/workflow/deadline.go
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/utils"
+	"strings"
+	"time"
+)
+
+// deadlinePollInterval 제한 시각이 지난 RUNNING 오더와 단계를 확인하는 간격
+const deadlinePollInterval = 5 * time.Second
+
+// orderDeadline 템플릿(없으면 ORDER_DEADLINE_SECONDS) 제한 시간으로 계산한 제한 시각 (제한이 없으면 nil)
+func (e *Executor) orderDeadline(template *models.OrderTemplate, startedAt time.Time) *time.Time {
+	deadline := e.config.OrderDeadline
+	if template != nil && template.DeadlineSeconds > 0 {
+		deadline = time.Duration(template.DeadlineSeconds) * time.Second
+	}
+	if deadline <= 0 {
+		return nil
+	}
+	deadlineAt := startedAt.Add(deadline)
+	return &deadlineAt
+}
+
+// extendDeadlineForPause 재개하는 오더의 제한 시각을 일시정지한 시간만큼 늦추고 일시정지 시각을 지움 (저장은 호출자가 함)
+func extendDeadlineForPause(orderExec *models.OrderExecution, now time.Time) {
+	if orderExec.PausedAt == nil {
+		return
+	}
+	if orderExec.DeadlineAt != nil {
+		if paused := now.Sub(*orderExec.PausedAt); paused > 0 {
+			deadlineAt := orderExec.DeadlineAt.Add(paused)
+			orderExec.DeadlineAt = &deadlineAt
+			utils.Logger.Infof("⏰ Order %s deadline moved to %s after %s paused",
+				orderExec.OrderID, deadlineAt.Format(time.RFC3339), paused.Round(time.Second))
+		}
+	}
+	orderExec.PausedAt = nil
+}
+
+// extendStepTimeoutsForPause 재개하는 오더의 실행 중인 단계 제한 시각을 pausedAt부터 now까지 일시정지한 시간만큼 늦춤 (저장은 호출자가 함)
+func extendStepTimeoutsForPause(steps []models.StepExecution, pausedAt, now time.Time) {
+	paused := now.Sub(pausedAt)
+	if paused <= 0 {
+		return
+	}
+	for i := range steps {
+		if steps[i].TimeoutAt != nil {
+			timeoutAt := steps[i].TimeoutAt.Add(paused)
+			steps[i].TimeoutAt = &timeoutAt
+		}
+	}
+}
+
+// resumeStepTimeouts 재개하는 오더의 실행 중인 단계 제한 시각을 늦춰 저장 (extendDeadlineForPause가 PausedAt을 지우기 전에 호출)
+func (e *Executor) resumeStepTimeouts(orderExec *models.OrderExecution, now time.Time) {
+	if orderExec.PausedAt == nil {
+		return
+	}
+	var steps []models.StepExecution
+	if err := e.db.Where("execution_id = ? AND status = ? AND timeout_at IS NOT NULL",
+		orderExec.ID, constants.StepExecutionStatusRunning).Find(&steps).Error; err != nil {
+		utils.Logger.Errorf("❌ Failed to load running steps of resumed order %s: %v", orderExec.OrderID, err)
+		return
+	}
+	extendStepTimeoutsForPause(steps, *orderExec.PausedAt, now)
+	for _, step := range steps {
+		if err := e.db.Model(&step).Update("timeout_at", step.TimeoutAt).Error; err != nil {
+			utils.Logger.Errorf("❌ Failed to move timeout of step %d for order %s: %v", step.StepOrder, orderExec.OrderID, err)
+		}
+	}
+}
+
+// DeadlineMonitor 제한 시각(DeadlineAt)이 지났는데 아직 RUNNING인 이 로봇의 오더를 만료시킵니다.
+// 로봇에 cancelOrder를 보내고 EXPIRED로 끝낸 뒤 명령 매핑의 FailureOrder로 분기하며,
+// 제한 시각은 DB에 있으므로 브릿지를 다시 시작해도 이어서 확인합니다.
+// 단계 제한 시각(StepExecution.TimeoutAt)이 지난 RUNNING 단계도 TIMEOUT으로 실패시켜 실패 분기로 진행합니다.
+// 일시정지한 시간은 제한 시간에 넣지 않습니다. PAUSED 오더와 그 단계는 만료시키지 않고,
+// 재개할 때 오더와 단계의 제한 시각을 일시정지했던 시간만큼 늦추므로 재개 직후 바로 만료되지 않습니다.
+type DeadlineMonitor struct {
+	executor *Executor
+	interval time.Duration
+
+	cancel context.CancelFunc
+	doneCh chan struct{}
+}
+
+// NewDeadlineMonitor 새 오더/단계 제한 시간 감시기 생성
+func NewDeadlineMonitor(executor *Executor) *DeadlineMonitor {
+	return &DeadlineMonitor{executor: executor, interval: deadlinePollInterval}
+}
+
+// Start 주기적 제한 시간 확인 시작
+func (m *DeadlineMonitor) Start(ctx context.Context) {
+	ctx, m.cancel = context.WithCancel(ctx)
+	m.doneCh = make(chan struct{})
+	go m.run(ctx)
+}
+
+// Stop 제한 시간 확인 중지
+func (m *DeadlineMonitor) Stop() {
+	if m.cancel == nil {
+		return
+	}
+	m.cancel()
+	<-m.doneCh
+	m.cancel = nil
+}
+
+func (m *DeadlineMonitor) run(ctx context.Context) {
+	defer close(m.doneCh)
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.Poll()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Poll 제한 시각이 지난 오더 만료와 단계 실패 처리 (읽기 전용이면 로봇에 아무것도 보내지 않고 다음 확인으로 미룸)
+func (m *DeadlineMonitor) Poll() {
+	e := m.executor
+	if e.readOnly.Enabled() {
+		return
+	}
+	now := time.Now()
+	executions, err := repository.OverdueOrderExecutions(e.db, e.config.SiteID, e.config.RobotSerialNumber, now)
+	if err != nil {
+		utils.Logger.Errorf("❌ Failed to load overdue orders of robot %s: %v", e.config.RobotSerialNumber, err)
+		return
+	}
+	for i := range executions {
+		e.expireOrder(&executions[i], now)
+	}
+
+	steps, err := repository.TimedOutStepExecutions(e.db, e.config.SiteID, e.config.RobotSerialNumber, now)
+	if err != nil {
+		utils.Logger.Errorf("❌ Failed to load timed out steps of robot %s: %v", e.config.RobotSerialNumber, err)
+		return
+	}
+	for i := range steps {
+		e.timeOutStep(&steps[i])
+	}
+}
+
+// timeOutStep 제한 시간 안에 끝나지 않은 단계를 TIMEOUT으로 실패 처리 (실패 분기가 없으면 오더 실패)
+func (e *Executor) timeOutStep(step *models.StepExecution) {
+	// 같은 확인에서 병렬 그룹의 다른 단계가 먼저 실패하면 함께 끝나므로 다시 확인
+	var current models.StepExecution
+	if err := e.db.Preload("Execution").First(&current, step.ID).Error; err != nil {
+		utils.Logger.Errorf("❌ Failed to load step execution %d for step timeout: %v", step.ID, err)
+		return
+	}
+	if current.Status != constants.StepExecutionStatusRunning || current.Execution.Status != constants.OrderExecutionStatusRunning {
+		return
+	}
+	elapsed := current.TimeoutAt.Sub(current.StartedAt).Round(time.Second)
+	utils.Logger.Warnf("⏰ Step %d of order %s did not finish within %s (cid=%s)",
+		current.StepOrder, current.Execution.OrderID, elapsed, current.Execution.CorrelationID)
+	e.stepManager.failStep(&current, &current.Execution, constants.StepExecutionStatusTimeout,
+		fmt.Sprintf("step did not finish within %s", elapsed))
+}
+
+// expireOrder 오더를 진행 상황과 함께 EXPIRED로 표시하고 로봇에서 취소한 뒤 실패로 완료 처리
+func (e *Executor) expireOrder(orderExec *models.OrderExecution, now time.Time) {
+	detail := fmt.Sprintf("deadline exceeded after %s: %s",
+		now.Sub(orderExec.StartedAt).Round(time.Second), e.describeProgress(orderExec))
+	if len(detail) > orderErrorMessageLimit {
+		detail = strings.ToValidUTF8(detail[:orderErrorMessageLimit], "")
+	}
+	expired, err := repository.ExpireOrderExecution(e.db, orderExec, detail, now)
+	if err != nil {
+		utils.Logger.Errorf("❌ Failed to expire order %s: %v", orderExec.OrderID, err)
+		return
+	}
+	if !expired {
+		return // 확인하는 사이 완료/취소됨
+	}
+	utils.Logger.Warnf("⏰ Order %s on %s expired: %s (cid=%s)",
+		orderExec.OrderID, orderExec.SerialNumber, detail, orderExec.CorrelationID)
+
+	e.stepManager.CancelRunningSteps(orderExec.ID, "order deadline exceeded")
+	if err := e.SendCancelOrder(orderExec.OrderID); err != nil {
+		utils.Logger.Errorf("❌ Failed to send cancelOrder for expired order %s: %v", orderExec.OrderID, err)
+	}
+	e.OnOrderCompleted(orderExec, false)
+}
+
+// describeProgress 만료 시점의 오더 진행 상황 (현재 단계, 끝난 단계 수, 마지막 노드, 남은 노드 수)
+func (e *Executor) describeProgress(orderExec *models.OrderExecution) string {
+	var finished int64
+	e.db.Model(&models.StepExecution{}).
+		Where("execution_id = ? AND status = ?", orderExec.ID, constants.StepExecutionStatusFinished).
+		Count(&finished)
+	progress := fmt.Sprintf("at step %d (%d step(s) finished)", orderExec.CurrentStep, finished)
+	if orderExec.LastNodeID != "" {
+		progress += fmt.Sprintf(", last node %s (sequence %d)", orderExec.LastNodeID, orderExec.LastNodeSequenceID)
+	}
+	var remaining []string
+	if orderExec.RemainingNodes != "" && json.Unmarshal([]byte(orderExec.RemainingNodes), &remaining) == nil {
+		progress += fmt.Sprintf(", %d node(s) remaining", len(remaining))
+	}
+	return progress
+}
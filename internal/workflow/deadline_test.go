@@ -0,0 +1,69 @@
+// internal/workflow/deadline_test.go
+package workflow
+
+import (
+	"mqtt-bridge/internal/models"
+	"testing"
+	"time"
+)
+
+func TestExtendDeadlineForPause(t *testing.T) {
+	started := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	deadline := started.Add(10 * time.Minute)
+	pausedAt := started.Add(8 * time.Minute)
+	resumedAt := pausedAt.Add(30 * time.Minute) // 원래 제한 시각을 넘겨서 재개
+
+	orderExec := &models.OrderExecution{OrderID: "order-paused", StartedAt: started, DeadlineAt: &deadline, PausedAt: &pausedAt}
+	extendDeadlineForPause(orderExec, resumedAt)
+
+	if orderExec.PausedAt != nil {
+		t.Errorf("PausedAt = %v, want nil after resume", orderExec.PausedAt)
+	}
+	// 일시정지 전까지 8분을 썼으므로 재개 후 2분이 남아야 함
+	if want := resumedAt.Add(2 * time.Minute); orderExec.DeadlineAt == nil || !orderExec.DeadlineAt.Equal(want) {
+		t.Fatalf("DeadlineAt = %v, want %v", orderExec.DeadlineAt, want)
+	}
+}
+
+func TestExtendDeadlineForPauseWithoutDeadline(t *testing.T) {
+	pausedAt := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	orderExec := &models.OrderExecution{OrderID: "order-unbounded", PausedAt: &pausedAt}
+	extendDeadlineForPause(orderExec, pausedAt.Add(time.Hour))
+
+	if orderExec.DeadlineAt != nil {
+		t.Errorf("DeadlineAt = %v, want nil for an order without a deadline", orderExec.DeadlineAt)
+	}
+	if orderExec.PausedAt != nil {
+		t.Errorf("PausedAt = %v, want nil after resume", orderExec.PausedAt)
+	}
+}
+
+func TestExtendStepTimeoutsForPause(t *testing.T) {
+	started := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	timeoutAt := started.Add(5 * time.Minute)
+	pausedAt := started.Add(3 * time.Minute)
+	resumedAt := pausedAt.Add(time.Hour) // 단계 제한 시각을 넘겨서 재개
+
+	steps := []models.StepExecution{
+		{StepOrder: 1, StartedAt: started, TimeoutAt: &timeoutAt},
+		{StepOrder: 2, StartedAt: started}, // 제한 시간 없는 병렬 단계
+	}
+	extendStepTimeoutsForPause(steps, pausedAt, resumedAt)
+
+	// 일시정지 전까지 3분을 썼으므로 재개 후 2분이 남아야 함
+	if want := resumedAt.Add(2 * time.Minute); steps[0].TimeoutAt == nil || !steps[0].TimeoutAt.Equal(want) {
+		t.Fatalf("step 1 TimeoutAt = %v, want %v", steps[0].TimeoutAt, want)
+	}
+	if !timeoutAt.Equal(started.Add(5 * time.Minute)) {
+		t.Errorf("original TimeoutAt = %v, want it left unchanged", timeoutAt)
+	}
+	if steps[1].TimeoutAt != nil {
+		t.Errorf("step 2 TimeoutAt = %v, want nil for a step without a timeout", steps[1].TimeoutAt)
+	}
+
+	// 일시정지 시각보다 이른 재개 시각(시계 보정)은 제한 시각을 당기지 않음
+	extendStepTimeoutsForPause(steps, resumedAt, pausedAt)
+	if want := resumedAt.Add(2 * time.Minute); !steps[0].TimeoutAt.Equal(want) {
+		t.Fatalf("step 1 TimeoutAt after negative pause = %v, want %v", steps[0].TimeoutAt, want)
+	}
+}
@@ -0,0 +1,255 @@
+// internal/workflow/dry_run.go
+package workflow
+
+import (
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/config"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// dryRunMaxPaths 분기 시뮬레이션에서 나열하는 최대 경로 수
+const dryRunMaxPaths = 64
+
+// 시뮬레이션 경로 결과
+const (
+	DryRunResultSuccess = "SUCCESS" // 명령 성공 종료
+	DryRunResultFailure = "FAILURE" // 명령 실패 종료
+	DryRunResultLoop    = "LOOP"    // 이미 거친 오더로 되돌아감 (재시도 루프)
+)
+
+// DryRunStep 단계(병렬 그룹이면 그룹 전체)가 전송할 오더 메시지
+type DryRunStep struct {
+	StepOrders    []int                `json:"step_orders"`
+	Conditional   bool                 `json:"conditional,omitempty"`    // 조건식이 있어 실행 시 건너뛸 수 있음
+	FailureBranch bool                 `json:"failure_branch,omitempty"` // 이전 단계가 실패했을 때만 실행
+	GeofenceError string               `json:"geofence_error,omitempty"` // 현재 맵/구역 기준으로 거부될 사유
+	Message       *models.OrderMessage `json:"message"`
+}
+
+// DryRunOrder 매핑 하나(오더 하나)의 시뮬레이션 결과
+type DryRunOrder struct {
+	ExecutionOrder int          `json:"execution_order"`
+	TemplateID     uint         `json:"template_id"`
+	TemplateName   string       `json:"template_name"`
+	NextOnSuccess  int          `json:"next_on_success"`
+	NextOnFailure  int          `json:"next_on_failure"`
+	Steps          []DryRunStep `json:"steps"`
+	Error          string       `json:"error,omitempty"` // 실제 실행이었다면 오더를 시작하지 못하고 명령이 실패했을 사유
+}
+
+// DryRunPath 오더별 성공/실패를 가정하고 따라간 경로
+type DryRunPath struct {
+	Outcomes []string `json:"outcomes"` // "<순번>:SUCCESS" 또는 "<순번>:FAILURE"
+	Result   string   `json:"result"`
+}
+
+// DryRunReport 명령 드라이런 결과
+type DryRunReport struct {
+	CommandType     string            `json:"command_type"`
+	Parameters      map[string]string `json:"parameters,omitempty"`
+	ParameterError  string            `json:"parameter_error,omitempty"` // 실제 실행이었다면 명령이 거부되었을 사유
+	Orders          []DryRunOrder     `json:"orders"`
+	Paths           []DryRunPath      `json:"paths"`
+	PathsTruncated  bool              `json:"paths_truncated,omitempty"`
+	MissingMappings []int             `json:"missing_mappings,omitempty"` // 분기가 가리키지만 매핑이 없는 순번
+}
+
+// DryRunCommand 명령의 CommandOrderMapping 체인을 따라 생성될 오더 메시지를 시뮬레이션합니다.
+// 템플릿 검증/합성 펼침/파라미터 치환은 실제 실행과 같게 수행하지만 로봇 전송이나
+// 실행 테이블 기록은 하지 않습니다. 각 오더의 메시지는 모든 단계가 성공한다고 가정하며,
+// 실패 분기 단계와 조건부 단계는 표시만 합니다.
+func DryRunCommand(db *gorm.DB, cfg *config.Config, commandType string, params map[string]string) (*DryRunReport, error) {
+	var cmdDef models.CommandDefinition
+	err := db.Where("command_type = ? AND is_active = true", commandType).First(&cmdDef).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, apperr.New(apperr.CodeCommandNotFound, "command %s is not defined or inactive", commandType).WithField("commandType")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DryRunReport{CommandType: commandType, Parameters: params}
+	if err := repository.ValidateCommandParameters(db, cmdDef.ID, params); err != nil {
+		report.ParameterError = err.Error()
+	}
+
+	var mappings []models.CommandOrderMapping
+	err = db.Where("command_definition_id = ?", cmdDef.ID).
+		Preload("Template.OrderSteps", func(db *gorm.DB) *gorm.DB {
+			return db.Order("order_steps.step_order ASC")
+		}).
+		Preload("Template.OrderSteps.NodeTemplate").
+		Preload("Template.OrderSteps.StepActionMappings.ActionTemplate.Parameters").
+		Preload("Template.OrderSteps.Edges").
+		Order("execution_order ASC").
+		Find(&mappings).Error
+	if err != nil {
+		return nil, err
+	}
+
+	builder := NewOrderBuilder(db, cfg)
+	geofence := NewGeofenceValidator(db, cfg.SiteID)
+	byOrder := make(map[int]*DryRunOrder, len(mappings))
+	for i := range mappings {
+		order := dryRunOrder(db, cfg, builder, geofence, &mappings[i], params, commandType)
+		report.Orders = append(report.Orders, order)
+	}
+	for i := range report.Orders {
+		byOrder[report.Orders[i].ExecutionOrder] = &report.Orders[i]
+	}
+
+	missing := make(map[int]bool)
+	report.Paths, report.PathsTruncated = dryRunPaths(byOrder, missing)
+	for executionOrder := range missing {
+		report.MissingMappings = append(report.MissingMappings, executionOrder)
+	}
+	sort.Ints(report.MissingMappings)
+	return report, nil
+}
+
+// dryRunOrder 매핑의 템플릿을 검증/펼친 뒤 단계별 오더 메시지 생성
+func dryRunOrder(db *gorm.DB, cfg *config.Config, builder *OrderBuilder, geofence *GeofenceValidator,
+	mapping *models.CommandOrderMapping, params map[string]string, commandType string) DryRunOrder {
+
+	order := DryRunOrder{
+		ExecutionOrder: mapping.ExecutionOrder,
+		TemplateID:     mapping.TemplateID,
+		TemplateName:   mapping.Template.Name,
+		NextOnSuccess:  mapping.NextExecutionOrder,
+		NextOnFailure:  mapping.FailureOrder,
+		Steps:          []DryRunStep{},
+	}
+
+	if err := resolveMappingTemplate(db, cfg, mapping); err != nil {
+		order.Error = err.Error()
+		return order
+	}
+	order.TemplateID = mapping.TemplateID
+	order.TemplateName = mapping.Template.Name
+
+	if mapping.Template.SiteID != cfg.SiteID {
+		order.Error = fmt.Sprintf("order template %d belongs to site %s, not %s",
+			mapping.TemplateID, mapping.Template.SiteID, cfg.SiteID)
+		return order
+	}
+	if err := repository.ValidateTemplateGraph(&mapping.Template); err != nil {
+		order.Error = err.Error()
+		return order
+	}
+	if err := repository.ValidateStepConditions(&mapping.Template); err != nil {
+		order.Error = err.Error()
+		return order
+	}
+	template, err := repository.ExpandTemplate(db, cfg.SiteID, &mapping.Template)
+	if err != nil {
+		order.Error = err.Error()
+		return order
+	}
+
+	execution := &models.OrderExecution{
+		SiteID:             cfg.SiteID,
+		SerialNumber:       cfg.RobotSerialNumber,
+		TemplateID:         mapping.TemplateID,
+		OrderID:            fmt.Sprintf("dry-run-%s-%d", commandType, mapping.ExecutionOrder),
+		ParameterOverrides: repository.EncodeParameters(params),
+		ExecutionOrder:     mapping.ExecutionOrder,
+	}
+	order.Steps = dryRunSteps(builder, geofence, template, execution)
+	return order
+}
+
+// dryRunSteps 펼친 템플릿의 단계(병렬 그룹은 그룹 전체)별 오더 메시지 생성
+func dryRunSteps(builder *OrderBuilder, geofence *GeofenceValidator, template *models.OrderTemplate,
+	execution *models.OrderExecution) []DryRunStep {
+
+	steps := []DryRunStep{}
+	for i := 0; i < len(template.OrderSteps); {
+		step := &template.OrderSteps[i]
+		members := []*models.OrderStep{step}
+		if step.ParallelGroup != "" {
+			members = parallelGroupMembers(template, step)
+		}
+
+		entry := DryRunStep{}
+		for _, member := range members {
+			entry.StepOrders = append(entry.StepOrders, member.StepOrder)
+			if member.Condition != "" {
+				entry.Conditional = true
+			}
+			if member.PreviousStepResult == constants.PreviousResultFailure {
+				entry.FailureBranch = true
+			}
+		}
+		// 미리보기 메시지가 로봇의 headerId 순번을 소비하지 않도록 headerId는 0으로 둠
+		entry.Message = builder.buildGroupOrderBody(execution, members)
+		entry.Message.Timestamp = time.Now().Format(time.RFC3339Nano)
+		if err := geofence.ValidateOrder(entry.Message); err != nil {
+			entry.GeofenceError = err.Error()
+		}
+		steps = append(steps, entry)
+		i += len(members)
+	}
+	return steps
+}
+
+// dryRunPaths 순번 1부터 오더마다 성공/실패를 가정하여 명령 종료까지의 경로를 나열
+func dryRunPaths(orders map[int]*DryRunOrder, missing map[int]bool) ([]DryRunPath, bool) {
+	var paths []DryRunPath
+	truncated := false
+
+	var walk func(executionOrder int, outcomes []string, visited map[int]bool)
+	walk = func(executionOrder int, outcomes []string, visited map[int]bool) {
+		if len(paths) >= dryRunMaxPaths {
+			truncated = true
+			return
+		}
+		finish := func(result string) {
+			paths = append(paths, DryRunPath{Outcomes: append([]string(nil), outcomes...), Result: result})
+		}
+
+		order, ok := orders[executionOrder]
+		if !ok {
+			// 실행기와 같게 매핑이 없으면 명령 실패
+			missing[executionOrder] = true
+			finish(DryRunResultFailure)
+			return
+		}
+		if visited[executionOrder] {
+			finish(DryRunResultLoop)
+			return
+		}
+		if order.Error != "" {
+			// 오더를 시작하지 못하면 실패 분기 없이 명령 실패
+			finish(DryRunResultFailure)
+			return
+		}
+
+		visited[executionOrder] = true
+		defer delete(visited, executionOrder)
+
+		for _, branch := range []struct {
+			outcome string
+			next    int
+		}{
+			{DryRunResultSuccess, order.NextOnSuccess},
+			{DryRunResultFailure, order.NextOnFailure},
+		} {
+			next := append(outcomes, fmt.Sprintf("%d:%s", executionOrder, branch.outcome))
+			if branch.next == 0 {
+				paths = append(paths, DryRunPath{Outcomes: append([]string(nil), next...), Result: branch.outcome})
+				continue
+			}
+			walk(branch.next, next, visited)
+		}
+	}
+
+	walk(1, nil, make(map[int]bool))
+	return paths, truncated
+}
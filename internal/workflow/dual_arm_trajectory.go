@@ -0,0 +1,166 @@
+// internal/workflow/dual_arm_trajectory.go
+package workflow
+
+import (
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/common/idgen"
+	"mqtt-bridge/internal/robot"
+	"mqtt-bridge/internal/utils"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 양팔 동기화 액션 파라미터 키 (barrier가 켜지면 두 궤적 액션에 같은 값으로 붙음)
+const (
+	syncGroupParameterKey = "sync_group" // 함께 시작하고 함께 끝나야 하는 액션 묶음 ID
+	barrierParameterKey   = "barrier"    // "true"면 두 팔 모두 준비되어야 시작하고 둘 다 끝나야 완료
+)
+
+// DualArmTrajectory 양팔 궤적 오더 요청 (오더 하나의 노드 하나에 왼팔/오른팔 궤적 액션을 함께 넣음)
+type DualArmTrajectory struct {
+	Left    string `json:"left"`    // 왼팔 trajectory_name
+	Right   string `json:"right"`   // 오른팔 trajectory_name
+	Barrier bool   `json:"barrier"` // 두 팔을 동시에 시작하고 둘 다 끝나야 완료로 보고하도록 요청
+}
+
+// DualArmTrajectoryResult 보낸 양팔 궤적 오더
+type DualArmTrajectoryResult struct {
+	OrderID       string    `json:"order_id"`
+	SerialNumber  string    `json:"serial_number"`
+	LeftActionID  string    `json:"left_action_id"`
+	RightActionID string    `json:"right_action_id"`
+	Barrier       bool      `json:"barrier"`
+	SyncGroup     string    `json:"sync_group,omitempty"`
+	SentAt        time.Time `json:"sent_at"`
+}
+
+// SetFactsheetManager 양팔 궤적 오더의 궤적 이름을 팩트시트로 검증하도록 설정
+func (e *Executor) SetFactsheetManager(factsheets *robot.FactsheetManager) {
+	e.factsheets = factsheets
+}
+
+// SendDualArmTrajectory 양팔 궤적을 오더 하나로 보냄 (두 궤적 이름은 로봇 팩트시트의 trajectory_name 허용 값이어야 함)
+// 두 액션은 blockingType NONE으로 함께 실행되며, Barrier면 같은 sync_group과 barrier=true 파라미터를 붙입니다.
+func (e *Executor) SendDualArmTrajectory(serialNumber string, req DualArmTrajectory) (*DualArmTrajectoryResult, error) {
+	req.Left = strings.TrimSpace(req.Left)
+	req.Right = strings.TrimSpace(req.Right)
+	if req.Left == "" {
+		return nil, apperr.Validation("left", "left arm trajectory name is required")
+	}
+	if req.Right == "" {
+		return nil, apperr.Validation("right", "right arm trajectory name is required")
+	}
+	if serialNumber != e.config.RobotSerialNumber {
+		return nil, apperr.New(apperr.CodeNotFound, "this bridge does not control robot %s", serialNumber).
+			WithField("serialNumber")
+	}
+	if err := e.checkFeature(robot.FeatureOrder); err != nil {
+		return nil, err
+	}
+	if err := e.validateTrajectories(serialNumber, req); err != nil {
+		return nil, err
+	}
+
+	directOrder, result := e.orderBuilder.BuildDualArmTrajectoryOrder(req)
+	if err := e.checkPreflight(result.OrderID); err != nil {
+		return nil, err
+	}
+	if err := e.sendOrder(directOrder); err != nil {
+		return nil, err
+	}
+	result.SentAt = time.Now()
+	utils.Logger.Infof("🦾 Dual-arm trajectory order %s sent to %s (left=%s, right=%s, barrier=%t)",
+		result.OrderID, serialNumber, req.Left, req.Right, req.Barrier)
+	return result, nil
+}
+
+// validateTrajectories 두 궤적 이름이 팩트시트의 허용 값인지 확인
+func (e *Executor) validateTrajectories(serialNumber string, req DualArmTrajectory) error {
+	if e.factsheets == nil {
+		return apperr.New(apperr.CodeUnsupportedFeature, "factsheet validation is not available on this bridge")
+	}
+	names, err := e.factsheets.TrajectoryNames(serialNumber)
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool, len(names))
+	for _, name := range names {
+		known[name] = true
+	}
+	for _, arm := range []struct{ field, name string }{{"left", req.Left}, {"right", req.Right}} {
+		if !known[arm.name] {
+			return apperr.Validation(arm.field, "trajectory %q is not in robot %s factsheet (%s)",
+				arm.name, serialNumber, strings.Join(names, ", "))
+		}
+	}
+	return nil
+}
+
+// BuildDualArmTrajectoryOrder 왼팔/오른팔 궤적 액션을 한 노드에 담은 오더 메시지 생성
+func (b *OrderBuilder) BuildDualArmTrajectoryOrder(req DualArmTrajectory) (*DirectOrderMessage, *DualArmTrajectoryResult) {
+	orderID := idgen.OrderID()
+	result := &DualArmTrajectoryResult{
+		OrderID:       orderID,
+		SerialNumber:  b.config.RobotSerialNumber,
+		LeftActionID:  idgen.ActionID(),
+		RightActionID: idgen.ActionID(),
+		Barrier:       req.Barrier,
+	}
+	if req.Barrier {
+		result.SyncGroup = orderID
+	}
+
+	armAction := func(actionID, arm, trajectory string) DirectOrderAction {
+		params := []DirectOrderActionParameter{
+			{Key: "trajectory_name", Value: trajectory},
+			{Key: "arm", Value: arm},
+		}
+		if req.Barrier {
+			params = append(params,
+				DirectOrderActionParameter{Key: syncGroupParameterKey, Value: result.SyncGroup},
+				DirectOrderActionParameter{Key: barrierParameterKey, Value: strconv.FormatBool(true)})
+		}
+		return DirectOrderAction{
+			ActionType:        constants.ActionTypeTrajectory,
+			ActionID:          actionID,
+			ActionDescription: fmt.Sprintf("Follow trajectory %s with %s arm", trajectory, arm),
+			BlockingType:      constants.BlockingTypeNone, // 두 팔이 함께 실행되도록
+			ActionParameters:  params,
+		}
+	}
+
+	position := defaultNodePosition(b.robotDefaults(""))
+	return &DirectOrderMessage{
+		HeaderID:      utils.NextHeaderID(constants.GetMeiliOrderTopic(b.config.RobotManufacturer, b.config.RobotSerialNumber)),
+		Timestamp:     time.Now().Format(time.RFC3339Nano),
+		Version:       "2.0.0",
+		Manufacturer:  b.config.RobotManufacturer,
+		SerialNumber:  b.config.RobotSerialNumber,
+		OrderID:       orderID,
+		OrderUpdateID: 0,
+		Nodes: []DirectOrderNode{
+			{
+				NodeID:      idgen.NodeID(),
+				Description: fmt.Sprintf("Dual-arm trajectory %s / %s", req.Left, req.Right),
+				SequenceID:  1,
+				Released:    true,
+				NodePosition: DirectNodePosition{
+					X:                     position.X,
+					Y:                     position.Y,
+					Theta:                 position.Theta,
+					AllowedDeviationXY:    position.AllowedDeviationXY,
+					AllowedDeviationTheta: position.AllowedDeviationTheta,
+					MapID:                 position.MapID,
+				},
+				Actions: []DirectOrderAction{
+					armAction(result.LeftActionID, constants.ArmLeft, req.Left),
+					armAction(result.RightActionID, constants.ArmRight, req.Right),
+				},
+			},
+		},
+		Edges: []DirectOrderEdge{},
+	}, result
+}
@@ -0,0 +1,124 @@
+// internal/workflow/estop.go
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/common/idgen"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/utils"
+	"time"
+)
+
+// EmergencyStop 로봇 비상 정지
+// 안전 instantAction(또는 설정된 비상 오더)을 먼저 전송한 뒤, 로봇의 실행 중인 오더와
+// 해당 명령 실행을 E_STOPPED로 표시하고 PLC에 실패 응답을 보냅니다.
+// 전송에 실패해도 DB 정리는 계속 진행하며, 중지한 오더 수와 전송 오류를 반환합니다.
+func (e *Executor) EmergencyStop(serialNumber, reason string) (int, error) {
+	if serialNumber != e.config.RobotSerialNumber {
+		return 0, apperr.New(apperr.CodeNotFound, "robot %s is not managed by this bridge", serialNumber).WithField("serial_number")
+	}
+	utils.Logger.Warnf("🛑 EMERGENCY STOP for robot %s: %s", serialNumber, reason)
+
+	publishErr := e.sendEmergencyStop()
+	if publishErr != nil {
+		utils.Logger.Errorf("❌ Failed to publish emergency stop to robot %s: %v", serialNumber, publishErr)
+	}
+
+	var orderExecutions []models.OrderExecution
+	if err := e.db.Where("site_id = ? AND serial_number = ? AND status IN ?", e.config.SiteID, serialNumber,
+		[]string{constants.OrderExecutionStatusPending, constants.OrderExecutionStatusRunning, constants.OrderExecutionStatusWaiting,
+			constants.OrderExecutionStatusPaused}).
+		Find(&orderExecutions).Error; err != nil {
+		return 0, fmt.Errorf("failed to load running orders for robot %s: %w", serialNumber, err)
+	}
+
+	commandExecutionIDs := make(map[uint]bool)
+	// 실행 제한 시간대로 대기 중인 명령은 아직 오더가 없으므로 따로 종료
+	for _, id := range e.dropQueuedCommands() {
+		commandExecutionIDs[id] = true
+	}
+	// 구역 예약은 로봇이 구역 안에 멈춰 있을 수 있으므로 해제하지 않음 (운영자가 zones release로 해제)
+	e.dropWaitingOrders()
+	for i := range orderExecutions {
+		orderExec := &orderExecutions[i]
+		now := time.Now()
+		repository.UpdateOrderExecutionStatus(e.db, orderExec, constants.OrderExecutionStatusEStopped, &now)
+		e.stepManager.CancelRunningSteps(orderExec.ID, reason)
+		e.orderTracer.End(orderExec.OrderID, false, "emergency stop")
+		commandExecutionIDs[orderExec.CommandExecutionID] = true
+		utils.Logger.Warnf("🛑 Order %s marked %s (cid=%s)", orderExec.OrderID, constants.OrderExecutionStatusEStopped, orderExec.CorrelationID)
+	}
+
+	for commandExecutionID := range commandExecutionIDs {
+		var cmdExec models.CommandExecution
+		if err := e.db.Preload("Command.CommandDefinition").First(&cmdExec, commandExecutionID).Error; err != nil {
+			utils.Logger.Errorf("❌ Command execution %d not found during emergency stop: %v", commandExecutionID, err)
+			continue
+		}
+		if cmdExec.Status != constants.CommandExecutionStatusRunning {
+			continue
+		}
+
+		now := time.Now()
+		repository.UpdateCommandExecutionStatus(e.db, &cmdExec, constants.CommandExecutionStatusEStopped, &now)
+		repository.UpdateCommandStatus(e.db, &cmdExec.Command, constants.CommandStatusFailure, reason)
+		e.sendResponseToPLC(cmdExec.Command.CorrelationID, cmdExec.Command.CommandDefinition.CommandType, constants.StatusFailure, reason)
+		if e.commandHandler != nil {
+			e.commandHandler.FinishCommand(cmdExec.CommandID, false)
+		}
+	}
+
+	return len(orderExecutions), publishErr
+}
+
+// sendEmergencyStop 비상 오더 템플릿이 설정되어 있으면 비상 오더를, 아니면 안전 instantAction을 전송
+func (e *Executor) sendEmergencyStop() error {
+	if e.config.EStopOrderTemplate != "" {
+		return e.sendEmergencyOrder(e.config.EStopOrderTemplate)
+	}
+
+	message, err := e.orderBuilder.BuildEmergencyStopMessage(e.config.EStopActions)
+	if err != nil {
+		return err
+	}
+	reqData, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal emergency stop request: %v", err)
+	}
+	topic := constants.GetMeiliInstantActionsTopic(e.config.RobotManufacturer, e.config.RobotSerialNumber)
+	// 안전 명령은 전달 보장을 위해 최소 QoS 1로 전송
+	options := e.config.RobotPublishOptions(topic)
+	if options.QoS < 1 {
+		options.QoS = 1
+	}
+	token := e.mqttClient.Publish(topic, options.QoS, options.Retained, reqData)
+	token.Wait()
+	if token.Error() != nil {
+		return apperr.Wrap(apperr.CodeTransportUnavailable, token.Error(), "Failed to publish emergency stop")
+	}
+	return nil
+}
+
+// sendEmergencyOrder 설정된 비상 오더 템플릿(단일 단계)을 즉시 전송
+func (e *Executor) sendEmergencyOrder(templateName string) error {
+	var template models.OrderTemplate
+	if err := e.db.Scopes(repository.SiteScope(e.config.SiteID)).Where("name = ?", templateName).First(&template).Error; err != nil {
+		return apperr.Wrap(apperr.CodeTemplateNotFound, err, "emergency order template %q not found", templateName)
+	}
+	detail, err := repository.LoadExpandedTemplate(e.db, e.config.SiteID, template.ID)
+	if err != nil {
+		return err
+	}
+	if len(detail.OrderSteps) != 1 {
+		return apperr.New(apperr.CodeValidationFailed, "emergency order template %q must have exactly one step, has %d", templateName, len(detail.OrderSteps))
+	}
+
+	execution := &models.OrderExecution{OrderID: idgen.OrderID()}
+	orderMsg := e.orderBuilder.BuildOrderMessage(execution, &detail.OrderSteps[0])
+	utils.Logger.Warnf("🛑 Sending emergency order %s from template %q", execution.OrderID, templateName)
+	return e.sendOrder(orderMsg)
+}
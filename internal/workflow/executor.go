@@ -2,23 +2,35 @@
 package workflow
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"mqtt-bridge/internal/artifacts"
 	"mqtt-bridge/internal/command"
+	"mqtt-bridge/internal/common/apperr"
 	"mqtt-bridge/internal/common/constants"
 	"mqtt-bridge/internal/common/idgen"
+	"mqtt-bridge/internal/common/readonly"
 	"mqtt-bridge/internal/config"
 	"mqtt-bridge/internal/messaging"
 	"mqtt-bridge/internal/models"
 	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/robot"
+	"mqtt-bridge/internal/telemetry"
 	"mqtt-bridge/internal/utils"
+	"mqtt-bridge/internal/zones"
+	"sync"
 	"time"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/attribute"
 	"gorm.io/gorm"
 )
 
+// windowRecheckInterval 실행 제한 시간대로 대기 중인 명령을 다시 확인하는 최대 간격
+const windowRecheckInterval = time.Minute
+
 // Executor 워크플로우 실행 엔진
 type Executor struct {
 	db             *gorm.DB
@@ -27,8 +39,27 @@ type Executor struct {
 	config         *config.Config
 	orderBuilder   *OrderBuilder
 	stepManager    *StepManager
+	outbox         *OutboxDispatcher
+	orderTracer    *telemetry.OrderTracer
 	plcSender      *messaging.PLCResponseSender
 	commandHandler command.CommandHandler
+	artifacts      *artifacts.Service
+	compatibility  *robot.CompatibilityGate
+	factsheets     *robot.FactsheetManager // 양팔 궤적 이름 검증
+	zones          *zones.Coordinator      // ZONE_RESERVATIONS가 꺼져 있으면 nil
+	readOnly       *readonly.Switch        // 켜져 있으면 오더 전송 보류 (nil이면 항상 꺼짐)
+	preflight      *Preflight              // 오더 전송 전 사전 점검 (PREFLIGHT_CHECKS가 비어 있으면 nil)
+	payloadPolicy  *OrderPayloadPolicy     // 오더 압축과 최대 크기 (둘 다 설정하지 않으면 nil)
+
+	// 실행 제한 시간대(QUEUE)로 대기 중인 명령 실행 (CommandExecution ID → 재확인 타이머)
+	queued map[uint]*time.Timer
+	// 템플릿 동시 실행 제한, 구역 예약 또는 읽기 전용으로 대기 중인 오더 실행 (OrderExecution ID → 재확인 타이머)
+	waitingOrders map[uint]*time.Timer
+	queuedMu      sync.Mutex
+
+	// 로봇의 수신 확인을 기다리는 발행된 오더 (orderId → 대기, ORDER_ACK_TIMEOUT_SECONDS가 0이면 비어 있음)
+	ackWatches map[string]*ackWatch
+	ackMu      sync.Mutex
 }
 
 // NewExecutor 새 워크플로우 실행기 생성
@@ -37,11 +68,9 @@ func NewExecutor(db *gorm.DB, redisClient *redis.Client, mqttClient mqtt.Client,
 
 	utils.Logger.Infof("🏗️ CREATING Workflow Executor")
 
-	orderBuilder := NewOrderBuilder(cfg)
-	messageSender := &MQTTMessageSender{
-		mqttClient: mqttClient,
-		config:     cfg,
-	}
+	orderBuilder := NewOrderBuilder(db, cfg)
+	outbox := NewOutboxDispatcher(db, mqttClient, cfg.OutboxPollInterval, cfg.OutboxMaxAttempts)
+	orderTracer := telemetry.NewOrderTracer()
 
 	executor := &Executor{
 		db:             db,
@@ -49,12 +78,21 @@ func NewExecutor(db *gorm.DB, redisClient *redis.Client, mqttClient mqtt.Client,
 		mqttClient:     mqttClient,
 		config:         cfg,
 		orderBuilder:   orderBuilder,
+		outbox:         outbox,
+		orderTracer:    orderTracer,
 		plcSender:      plcSender,
 		commandHandler: nil,
+		queued:         make(map[uint]*time.Timer),
+		waitingOrders:  make(map[uint]*time.Timer),
+		ackWatches:     make(map[string]*ackWatch),
 	}
+	outbox.SetSentHandler(executor.watchAcknowledgment)
 
-	stepManager := NewStepManager(db, redisClient, orderBuilder, messageSender)
+	stepManager := NewStepManager(db, redisClient, orderBuilder, outbox, orderTracer)
 	stepManager.SetExecutor(executor)
+	stepManager.SetGeofenceValidator(NewGeofenceValidator(db, cfg.SiteID))
+	stepManager.shadow = NewShadowRunner(db, orderBuilder)
+	stepManager.actionMapTTL = cfg.RedisStepActionsTTL
 	executor.stepManager = stepManager
 
 	utils.Logger.Infof("✅ Workflow Executor CREATED")
@@ -67,11 +105,49 @@ func (e *Executor) SetCommandHandler(handler command.CommandHandler) {
 	utils.Logger.Infof("✅ Workflow Executor: Command Handler reference set")
 }
 
+// SetArtifactService 완료된 추론 액션 결과를 오더 결과물로 수집하도록 설정
+func (e *Executor) SetArtifactService(service *artifacts.Service) {
+	e.artifacts = service
+	utils.Logger.Infof("✅ Workflow Executor: Artifact service set")
+}
+
+// SetCompatibilityGate 로봇 VDA 버전이 지원하지 않는 오더/취소 요청을 막도록 설정
+func (e *Executor) SetCompatibilityGate(gate *robot.CompatibilityGate) {
+	e.compatibility = gate
+	e.stepManager.compatibility = gate
+	utils.Logger.Infof("✅ Workflow Executor: Compatibility gate set")
+}
+
+// SetTransports 오더 전송 경로와 실패 시 대체 순서 설정
+func (e *Executor) SetTransports(transports []Transport) {
+	e.outbox.SetTransports(transports)
+}
+
+// checkFeature 호환성 검사기가 설정되어 있으면 로봇의 기능 지원 여부 확인
+func (e *Executor) checkFeature(feature string) error {
+	if e.compatibility == nil {
+		return nil
+	}
+	return e.compatibility.CheckFeature(e.config.RobotSerialNumber, feature)
+}
+
+// Start 백그라운드 작업(아웃박스 디스패처, 재시작 복구) 시작
+func (e *Executor) Start(ctx context.Context) {
+	e.outbox.Start(ctx)
+	go func() {
+		e.reconcileRunningSteps(ctx)
+		e.ResumeCommandExecutions()
+	}()
+}
+
 // ExecuteCommandOrder는 전달받은 Command를 기반으로 워크플로우를 시작합니다. (수정됨)
 func (e *Executor) ExecuteCommandOrder(command *models.Command) error {
 	if command == nil {
 		return fmt.Errorf("command cannot be nil")
 	}
+	if err := e.readOnly.Check("starting a workflow"); err != nil {
+		return err
+	}
 
 	if command.CommandDefinition.CommandType == "" {
 		e.db.Preload("CommandDefinition").First(&command, command.ID)
@@ -83,6 +159,8 @@ func (e *Executor) ExecuteCommandOrder(command *models.Command) error {
 	// Executor가 다시 CommandExecution을 생성합니다.
 	commandExecution := &models.CommandExecution{
 		CommandID:         command.ID,
+		SiteID:            e.config.SiteID,
+		SerialNumber:      e.config.RobotSerialNumber,
 		Status:            constants.CommandExecutionStatusRunning,
 		CurrentOrderIndex: 1,
 		StartedAt:         time.Now(),
@@ -95,11 +173,20 @@ func (e *Executor) ExecuteCommandOrder(command *models.Command) error {
 }
 
 // SendDirectActionOrder 직접 액션 오더 전송
-func (e *Executor) SendDirectActionOrder(baseCommand string, commandType rune, armParam string) (string, error) {
-	directOrder, orderID, err := e.orderBuilder.BuildDirectActionOrder(baseCommand, commandType, armParam)
+func (e *Executor) SendDirectActionOrder(call *repository.DirectActionCall) (string, error) {
+	if err := e.checkFeature(robot.FeatureOrder); err != nil {
+		return "", err
+	}
+	if err := e.readOnly.Check("sending an order"); err != nil {
+		return "", err
+	}
+	directOrder, orderID, err := e.orderBuilder.BuildDirectActionOrder(call)
 	if err != nil {
 		return "", err
 	}
+	if err := e.checkPreflight(orderID); err != nil {
+		return "", err
+	}
 	if err := e.sendOrder(directOrder); err != nil {
 		return "", err
 	}
@@ -109,6 +196,24 @@ func (e *Executor) SendDirectActionOrder(baseCommand string, commandType rune, a
 // HandleOrderStateUpdate 로봇 상태 업데이트 처리
 func (e *Executor) HandleOrderStateUpdate(stateMsg *models.RobotStateMessage) {
 	utils.Logger.Debugf("🔍 HandleOrderStateUpdate called for OrderID: %s", stateMsg.OrderID)
+	e.stepManager.ObserveState(stateMsg)
+	if stateMsg.OrderID != "" {
+		e.acknowledgeOrder(stateMsg)
+	}
+	if len(stateMsg.Errors) > 0 {
+		e.handleOrderRejections(stateMsg)
+	}
+	if stateMsg.OrderID != "" {
+		e.orderTracer.AddEvent(stateMsg.OrderID, "robot.state",
+			attribute.Int64("vda5050.header_id", stateMsg.HeaderID),
+			attribute.String("robot.last_node_id", stateMsg.LastNodeID),
+			attribute.Int("robot.action_states", len(stateMsg.ActionStates)),
+			attribute.Int("robot.errors", len(stateMsg.Errors)))
+		e.recordNodeProgress(stateMsg)
+		if e.artifacts != nil {
+			e.artifacts.CaptureActionResults(context.Background(), stateMsg.OrderID, stateMsg.ActionStates)
+		}
+	}
 	if e.stepManager.HandleStepCompletion(stateMsg) {
 		utils.Logger.Infof("✅ Step completion handled for OrderID: %s", stateMsg.OrderID)
 		return
@@ -116,10 +221,27 @@ func (e *Executor) HandleOrderStateUpdate(stateMsg *models.RobotStateMessage) {
 	utils.Logger.Debugf("🔍 No step completion detected for OrderID: %s", stateMsg.OrderID)
 }
 
+// recordNodeProgress state의 lastNodeId/nodeStates로 오더의 노드 진행 상황 갱신
+func (e *Executor) recordNodeProgress(stateMsg *models.RobotStateMessage) {
+	progress := repository.NodeProgressFromState(stateMsg)
+	updated, err := repository.UpdateNodeProgress(e.db, e.config.SiteID, stateMsg.OrderID, progress)
+	if err != nil {
+		utils.Logger.Errorf("❌ Failed to record node progress for order %s: %v", stateMsg.OrderID, err)
+		return
+	}
+	if updated {
+		utils.Logger.Infof("📍 Order %s at node %s (seq %d, %d remaining)",
+			stateMsg.OrderID, progress.LastNodeID, progress.LastNodeSequenceID, len(progress.RemainingNodes))
+	}
+}
+
 // OnOrderCompleted 오더 완료 콜백 (StepManager에서 호출)
 func (e *Executor) OnOrderCompleted(orderExecution *models.OrderExecution, success bool) {
 	utils.Logger.Infof("📢 OnOrderCompleted called: OrderID=%s, Success=%t",
 		orderExecution.OrderID, success)
+	e.orderTracer.End(orderExecution.OrderID, success, "order failed")
+	e.releaseZones(orderExecution.SerialNumber)
+	e.wakeWaitingOrders()
 
 	var cmdExec models.CommandExecution
 	if err := e.db.Preload("Command.CommandDefinition").First(&cmdExec, orderExecution.CommandExecutionID).Error; err != nil {
@@ -142,7 +264,12 @@ func (e *Executor) OnOrderCompleted(orderExecution *models.OrderExecution, succe
 		nextOrderIndex = currentMapping.FailureOrder
 	}
 
+	if success && nextOrderIndex != 0 && cmdExec.Command.CommandDefinition.ReportProgress {
+		e.sendProgressToPLC(&cmdExec)
+	}
+
 	cmdExec.CurrentOrderIndex = nextOrderIndex
+	cmdExec.LastOrderID = orderExecution.ID
 	if err := e.db.Save(&cmdExec).Error; err != nil {
 		utils.Logger.Errorf("❌ Failed to update command execution: %v", err)
 		e.completeCommandExecution(&cmdExec, false)
@@ -154,39 +281,130 @@ func (e *Executor) OnOrderCompleted(orderExecution *models.OrderExecution, succe
 	}
 }
 
-// CancelAllRunningOrders 모든 실행 중인 오더 취소
+// CancelAllRunningOrders 모든 실행 중인 오더 취소 (실행 제한 시간대로 대기 중인 명령 포함)
 func (e *Executor) CancelAllRunningOrders() error {
+	e.dropQueuedCommands()
+	e.dropWaitingOrders()
+	paused := false
+
 	var commandExecutions []models.CommandExecution
 	e.db.Where("status = ?", constants.CommandExecutionStatusRunning).
 		Preload("Command").
 		Find(&commandExecutions)
 
-	for _, cmdExec := range commandExecutions {
-		now := time.Now()
-		repository.UpdateCommandExecutionStatus(e.db, &cmdExec, constants.CommandExecutionStatusCancelled, &now)
-		repository.UpdateCommandStatus(e.db, &cmdExec.Command, constants.CommandStatusFailure, "Cancelled by user")
+	for i := range commandExecutions {
+		if e.cancelCommandExecution(&commandExecutions[i], "Cancelled by user", "Cancelled by order cancel command") {
+			paused = true
+		}
+	}
 
-		var orderExecutions []models.OrderExecution
-		e.db.Where("command_execution_id = ? AND status IN ?",
-			cmdExec.ID, []string{constants.OrderExecutionStatusRunning, constants.OrderExecutionStatusPending}).
-			Find(&orderExecutions)
+	e.releaseZones(e.config.RobotSerialNumber)
 
-		for _, orderExec := range orderExecutions {
-			nowOrderExec := time.Now()
-			repository.UpdateOrderExecutionStatus(e.db, &orderExec, constants.OrderExecutionStatusFailed, &nowOrderExec)
-			e.stepManager.CancelRunningSteps(orderExec.ID, "Cancelled by order cancel command")
-		}
-		if e.commandHandler != nil {
-			e.commandHandler.FinishCommand(cmdExec.CommandID, false)
+	if err := e.SendCancelOrder(""); err != nil {
+		return err
+	}
+	if paused {
+		e.releasePause("")
+	}
+	return nil
+}
+
+// CancelCommand 실행 중인 명령 하나를 취소합니다 (작업 묶음 취소용).
+// 명령의 남은 오더를 모두 실패 처리하고 로봇에 cancelOrder를 보내며, 이미 끝난 명령이면 아무것도 하지 않습니다.
+func (e *Executor) CancelCommand(commandID uint, reason string) error {
+	var cmdExec models.CommandExecution
+	err := e.db.Where("command_id = ? AND status = ?", commandID, constants.CommandExecutionStatusRunning).
+		Preload("Command").
+		First(&cmdExec).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	paused := e.cancelCommandExecution(&cmdExec, reason, reason)
+	e.releaseZones(e.config.RobotSerialNumber)
+	if err := e.SendCancelOrder(""); err != nil {
+		return err
+	}
+	if paused {
+		e.releasePause("")
+	}
+	return nil
+}
+
+// cancelCommandExecution 명령 실행과 남은 오더를 취소 상태로 기록 (일시정지된 오더가 있었으면 true)
+func (e *Executor) cancelCommandExecution(cmdExec *models.CommandExecution, commandReason, stepReason string) bool {
+	paused := false
+	now := time.Now()
+	repository.UpdateCommandExecutionStatus(e.db, cmdExec, constants.CommandExecutionStatusCancelled, &now)
+	repository.UpdateCommandStatus(e.db, &cmdExec.Command, constants.CommandStatusFailure, commandReason)
+
+	var orderExecutions []models.OrderExecution
+	e.db.Where("command_execution_id = ? AND status IN ?",
+		cmdExec.ID, []string{constants.OrderExecutionStatusRunning, constants.OrderExecutionStatusPending,
+			constants.OrderExecutionStatusWaiting, constants.OrderExecutionStatusPaused}).
+		Find(&orderExecutions)
+
+	for _, orderExec := range orderExecutions {
+		if orderExec.Status == constants.OrderExecutionStatusPaused {
+			paused = true
 		}
+		nowOrderExec := time.Now()
+		repository.UpdateOrderExecutionStatus(e.db, &orderExec, constants.OrderExecutionStatusFailed, &nowOrderExec)
+		e.orderTracer.End(orderExec.OrderID, false, "cancelled")
+		e.stepManager.CancelRunningSteps(orderExec.ID, stepReason)
+	}
+	if e.commandHandler != nil {
+		e.commandHandler.FinishCommand(cmdExec.CommandID, false)
+	}
+	return paused
+}
+
+// CancelOrder 지정한 오더만 취소합니다.
+// 오더의 실행 중인 단계를 실패 처리하고 해당 오더를 참조하는 cancelOrder를 로봇에 전송한 뒤,
+// 오더 실패로 처리하여 매핑의 FailureOrder로 분기합니다 (0이면 명령 실패 종료).
+// 일시정지된 오더면 취소 후 stopPause를 보내 로봇이 다음 오더를 실행할 수 있게 합니다.
+func (e *Executor) CancelOrder(orderID, reason string) error {
+	var orderExec models.OrderExecution
+	err := e.db.Scopes(repository.SiteScope(e.config.SiteID)).Where("order_id = ?", orderID).First(&orderExec).Error
+	if err == gorm.ErrRecordNotFound {
+		return apperr.New(apperr.CodeNotFound, "order %s not found", orderID).WithField("orderId")
+	}
+	if err != nil {
+		return err
+	}
+	switch orderExec.Status {
+	case constants.OrderExecutionStatusPending, constants.OrderExecutionStatusRunning, constants.OrderExecutionStatusWaiting,
+		constants.OrderExecutionStatusPaused:
+	default:
+		return apperr.New(apperr.CodeValidationFailed, "order %s is already %s", orderID, orderExec.Status).WithField("orderId")
+	}
+
+	utils.Logger.Warnf("🚫 Cancelling order %s: %s (cid=%s)", orderID, reason, orderExec.CorrelationID)
+	wasPaused := orderExec.Status == constants.OrderExecutionStatusPaused
+	now := time.Now()
+	repository.UpdateOrderExecutionStatus(e.db, &orderExec, constants.OrderExecutionStatusCancelled, &now)
+	e.stepManager.CancelRunningSteps(orderExec.ID, reason)
+
+	sendErr := e.SendCancelOrder(orderID)
+	if sendErr != nil {
+		utils.Logger.Errorf("❌ Failed to send cancelOrder for %s: %v", orderID, sendErr)
+	} else if wasPaused {
+		e.releasePause(orderID)
 	}
 
-	return e.SendCancelOrder()
+	e.OnOrderCompleted(&orderExec, false)
+	return sendErr
 }
 
-// SendCancelOrder 로봇에 cancelOrder 요청 전송
-func (e *Executor) SendCancelOrder() error {
-	cancelMessage, err := e.orderBuilder.BuildCancelOrderMessage()
+// SendCancelOrder 로봇에 cancelOrder 요청 전송 (orderID가 있으면 액션 파라미터로 참조)
+func (e *Executor) SendCancelOrder(orderID string) error {
+	if err := e.checkFeature(robot.FeatureCancelOrder); err != nil {
+		return err
+	}
+	cancelMessage, err := e.orderBuilder.BuildCancelOrderMessage(orderID)
 	if err != nil {
 		return fmt.Errorf("failed to build cancel order message: %v", err)
 	}
@@ -195,9 +413,13 @@ func (e *Executor) SendCancelOrder() error {
 		return fmt.Errorf("failed to marshal cancelOrder request: %v", err)
 	}
 	topic := constants.GetMeiliInstantActionsTopic(e.config.RobotManufacturer, e.config.RobotSerialNumber)
-	token := e.mqttClient.Publish(topic, 0, false, reqData)
+	options := e.config.RobotPublishOptions(topic)
+	token := e.mqttClient.Publish(topic, options.QoS, options.Retained, reqData)
 	token.Wait()
-	return token.Error()
+	if token.Error() != nil {
+		return apperr.Wrap(apperr.CodeTransportUnavailable, token.Error(), "Failed to send cancelOrder")
+	}
+	return nil
 }
 
 // executeNextOrder 조건에 맞는 다음 오더를 찾아 실행
@@ -225,24 +447,228 @@ func (e *Executor) executeNextOrder(commandExecution *models.CommandExecution) e
 		return fmt.Errorf(errMsg)
 	}
 
+	if err := resolveMappingTemplate(e.db, e.config, &mapping); err != nil {
+		utils.Logger.Errorf("🐤 %v", err)
+		e.completeCommandExecution(commandExecution, false)
+		return err
+	}
+
+	if mapping.Template.SiteID != e.config.SiteID {
+		errMsg := fmt.Sprintf("order template %d belongs to site %s, not %s",
+			mapping.TemplateID, mapping.Template.SiteID, e.config.SiteID)
+		utils.Logger.Errorf(errMsg)
+		e.completeCommandExecution(commandExecution, false)
+		return fmt.Errorf(errMsg)
+	}
+
+	// 로봇이 거부하기 전에 노드/엣지 그래프 검증 (등록 이후 변경된 템플릿 대비)
+	if err := repository.ValidateTemplateGraph(&mapping.Template); err != nil {
+		utils.Logger.Errorf("🕸️ %v", err)
+		e.completeCommandExecution(commandExecution, false)
+		return err
+	}
+	if err := repository.ValidateStepConditions(&mapping.Template); err != nil {
+		utils.Logger.Errorf("🔀 %v", err)
+		e.completeCommandExecution(commandExecution, false)
+		return err
+	}
+
+	// 합성 템플릿은 실행 시점의 하위 템플릿 단계로 펼침
+	template, err := repository.ExpandTemplate(e.db, e.config.SiteID, &mapping.Template)
+	if err != nil {
+		utils.Logger.Errorf("🧩 Failed to expand order template %d: %v", mapping.TemplateID, err)
+		e.completeCommandExecution(commandExecution, false)
+		return err
+	}
+
+	// 실행 제한 시간대에는 정책에 따라 시간대가 끝날 때까지 대기하거나 명령 실패
+	block, err := repository.CheckExecutionWindows(e.db, e.config.SiteID, mapping.TemplateID, e.config.RobotSerialNumber, time.Now())
+	if err != nil {
+		utils.Logger.Errorf("❌ Failed to check execution windows: %v", err)
+		e.completeCommandExecution(commandExecution, false)
+		return err
+	}
+	if block != nil {
+		if block.Window.Policy == constants.ExecutionWindowPolicyQueue {
+			e.queueUntilWindowOpens(commandExecution, block)
+			return nil
+		}
+		windowErr := repository.ExecutionWindowError(block)
+		utils.Logger.Warnf("🌙 %v", windowErr)
+		e.completeCommandExecution(commandExecution, false)
+		return windowErr
+	}
+
+	// 유지보수 중인 로봇에는 새 오더를 배차하지 않음 (명령 실행 중에 설정된 경우 포함)
+	// 자동 충전 중에는 충전 정책이 보낸 충전 명령만 계속 배차
+	maintenance, err := repository.ActiveMaintenance(e.db, e.config.SiteID, e.config.RobotSerialNumber)
+	if err != nil {
+		utils.Logger.Errorf("❌ Failed to check maintenance mode for robot %s: %v", e.config.RobotSerialNumber, err)
+		e.completeCommandExecution(commandExecution, false)
+		return err
+	}
+	chargingCommand := repository.IsChargingMaintenance(maintenance) &&
+		repository.IsChargingCommand(commandExecution.Command.CorrelationID)
+	if maintenance != nil && !chargingCommand {
+		maintenanceErr := repository.MaintenanceError(maintenance)
+		utils.Logger.Warnf("🔧 %v", maintenanceErr)
+		e.completeCommandExecution(commandExecution, false)
+		return maintenanceErr
+	}
+
+	// 로봇 탐색을 켠 경우 운영자가 승인한 로봇에만 배차
+	if e.config.RobotDiscovery {
+		if err := repository.CheckRobotApproved(e.db, e.config.SiteID, e.config.RobotSerialNumber); err != nil {
+			utils.Logger.Warnf("🆕 %v", err)
+			e.completeCommandExecution(commandExecution, false)
+			return err
+		}
+	}
+
 	orderExecution := &models.OrderExecution{
 		CommandExecutionID: commandExecution.ID,
+		SiteID:             e.config.SiteID,
+		SerialNumber:       e.config.RobotSerialNumber,
 		TemplateID:         mapping.TemplateID,
 		OrderID:            idgen.OrderID(),
+		CorrelationID:      commandExecution.Command.CorrelationID,
+		ParameterOverrides: commandExecution.Command.ParameterOverrides,
 		ExecutionOrder:     mapping.ExecutionOrder,
 		CurrentStep:        1,
 		Status:             constants.OrderExecutionStatusRunning,
 		StartedAt:          time.Now(),
 	}
+	// 템플릿 동시 실행 제한이 있으면 대기 상태로 만든 뒤 자리가 있을 때만 시작 (제한 시간은 시작할 때부터)
+	if mapping.Template.MaxConcurrentExecutions > 0 {
+		orderExecution.Status = constants.OrderExecutionStatusWaiting
+	} else {
+		orderExecution.DeadlineAt = e.orderDeadline(template, orderExecution.StartedAt)
+	}
 	if err := e.db.Create(orderExecution).Error; err != nil {
 		e.completeCommandExecution(commandExecution, false)
 		return fmt.Errorf("failed to create order execution: %v", err)
 	}
 
-	e.stepManager.ExecuteNextStep(orderExecution, &mapping.Template)
+	utils.Logger.Infof("🧾 Order %s created for command %s (cid=%s)",
+		orderExecution.OrderID, commandExecution.Command.CommandDefinition.CommandType, orderExecution.CorrelationID)
+	e.orderTracer.Start(context.Background(), orderExecution.OrderID,
+		attribute.String("correlation.id", orderExecution.CorrelationID),
+		attribute.String("command.type", commandExecution.Command.CommandDefinition.CommandType),
+		attribute.Int("order.template_id", int(orderExecution.TemplateID)),
+		attribute.Int("order.execution_order", orderExecution.ExecutionOrder))
+
+	if orderExecution.Status == constants.OrderExecutionStatusWaiting {
+		admitted, err := repository.AdmitOrderExecution(e.db, orderExecution)
+		if err != nil {
+			now := time.Now()
+			repository.UpdateOrderExecutionStatus(e.db, orderExecution, constants.OrderExecutionStatusFailed, &now)
+			e.completeCommandExecution(commandExecution, false)
+			return fmt.Errorf("failed to check template concurrency limit: %v", err)
+		}
+		if !admitted {
+			e.waitForConcurrencySlot(commandExecution, orderExecution)
+			return nil
+		}
+		orderExecution.DeadlineAt = e.orderDeadline(template, orderExecution.StartedAt)
+		e.db.Model(orderExecution).Update("deadline_at", orderExecution.DeadlineAt)
+	}
+	e.stepManager.ExecuteNextStep(orderExecution, template)
 	return nil
 }
 
+// resolveMappingTemplate 이 로봇이 카나리 롤아웃 대상이면 매핑 템플릿을 후보 버전으로 교체합니다.
+// 대상이 아닌데 매핑 템플릿이 DRAFT면 실행을 거부합니다.
+func resolveMappingTemplate(db *gorm.DB, cfg *config.Config, mapping *models.CommandOrderMapping) error {
+	templateID, rollout, err := repository.ResolveTemplateForRobot(db, cfg.SiteID, mapping.TemplateID, cfg.RobotSerialNumber)
+	if err != nil {
+		return err
+	}
+	if rollout != nil {
+		candidate, err := repository.LoadTemplateDetail(db, cfg.SiteID, templateID)
+		if err != nil {
+			return apperr.Wrap(apperr.CodeTemplateNotFound, err, "canary template %d of rollout %d not found", templateID, rollout.ID)
+		}
+		utils.Logger.Infof("🐤 Robot %s is in canary rollout %d: using template %d instead of %d",
+			cfg.RobotSerialNumber, rollout.ID, templateID, mapping.TemplateID)
+		mapping.TemplateID = templateID
+		mapping.Template = *candidate
+		return nil
+	}
+	if mapping.Template.Status == constants.TemplateStatusDraft {
+		return apperr.New(apperr.CodeValidationFailed,
+			"order template %d is a DRAFT; activate it or start a canary rollout", mapping.TemplateID)
+	}
+	return nil
+}
+
+// queueUntilWindowOpens 실행 제한 시간대가 끝날 때까지 명령의 다음 오더 배차를 미룹니다.
+// 관리자 해제를 반영하도록 최대 windowRecheckInterval마다 시간대를 다시 확인합니다.
+func (e *Executor) queueUntilWindowOpens(commandExecution *models.CommandExecution, block *repository.WindowBlock) {
+	id := commandExecution.ID
+	delay := time.Until(block.OpensAt)
+	if delay > windowRecheckInterval {
+		delay = windowRecheckInterval
+	}
+
+	e.queuedMu.Lock()
+	previous, alreadyQueued := e.queued[id]
+	if alreadyQueued {
+		previous.Stop()
+	}
+	e.queued[id] = time.AfterFunc(delay, func() { e.resumeQueuedCommand(id) })
+	e.queuedMu.Unlock()
+
+	if alreadyQueued {
+		return
+	}
+	utils.Logger.Infof("🌙 Command %s queued until %s by execution window %s (cid=%s)",
+		commandExecution.Command.CommandDefinition.CommandType, block.OpensAt.Format(time.RFC3339),
+		block.Window.Name, commandExecution.Command.CorrelationID)
+	e.sendResponseToPLC(commandExecution.Command.CorrelationID, commandExecution.Command.CommandDefinition.CommandType,
+		constants.CommandStatusRunning, fmt.Sprintf("Queued until %s (execution window %s)",
+			block.OpensAt.Format(time.RFC3339), block.Window.Name))
+}
+
+// resumeQueuedCommand 대기 중인 명령의 시간대를 다시 확인하고, 열렸으면 다음 오더를 배차
+func (e *Executor) resumeQueuedCommand(id uint) {
+	e.queuedMu.Lock()
+	timer, ok := e.queued[id]
+	e.queuedMu.Unlock()
+	if !ok {
+		return
+	}
+
+	var cmdExec models.CommandExecution
+	err := e.db.First(&cmdExec, id).Error
+	if err == nil && cmdExec.Status == constants.CommandExecutionStatusRunning {
+		if err := e.executeNextOrder(&cmdExec); err != nil {
+			utils.Logger.Errorf("❌ Failed to execute queued command %d: %v", id, err)
+		}
+	} else if err != nil {
+		utils.Logger.Errorf("❌ Queued command execution %d not found: %v", id, err)
+	}
+
+	// executeNextOrder가 다시 대기시키지 않았으면 대기 목록에서 제거
+	e.queuedMu.Lock()
+	if e.queued[id] == timer {
+		delete(e.queued, id)
+	}
+	e.queuedMu.Unlock()
+}
+
+// dropQueuedCommands 대기 중인 명령을 모두 대기 목록에서 빼고 ID를 반환 (취소/비상 정지용)
+func (e *Executor) dropQueuedCommands() []uint {
+	e.queuedMu.Lock()
+	defer e.queuedMu.Unlock()
+	ids := make([]uint, 0, len(e.queued))
+	for id, timer := range e.queued {
+		timer.Stop()
+		ids = append(ids, id)
+		delete(e.queued, id)
+	}
+	return ids
+}
+
 // completeCommandExecution 명령 실행 완료 처리
 func (e *Executor) completeCommandExecution(commandExecution *models.CommandExecution, success bool) error {
 	var finalStatus string
@@ -263,7 +689,7 @@ func (e *Executor) completeCommandExecution(commandExecution *models.CommandExec
 	repository.UpdateCommandExecutionStatus(e.db, commandExecution, finalStatus, &now)
 	repository.UpdateCommandStatus(e.db, &commandExecution.Command, finalCommandStatus, message)
 
-	e.sendResponseToPLC(commandExecution.Command.CommandDefinition.CommandType, finalCommandStatus, message)
+	e.sendResponseToPLC(commandExecution.Command.CorrelationID, commandExecution.Command.CommandDefinition.CommandType, finalCommandStatus, message)
 
 	if e.commandHandler != nil {
 		e.commandHandler.FinishCommand(commandExecution.CommandID, success)
@@ -273,7 +699,7 @@ func (e *Executor) completeCommandExecution(commandExecution *models.CommandExec
 }
 
 // sendResponseToPLC PLC에 응답 전송
-func (e *Executor) sendResponseToPLC(command, status, errMsg string) {
+func (e *Executor) sendResponseToPLC(correlationID, command, status, errMsg string) {
 	var finalStatus string
 	switch status {
 	case constants.CommandStatusSuccess:
@@ -289,36 +715,59 @@ func (e *Executor) sendResponseToPLC(command, status, errMsg string) {
 	default:
 		finalStatus = status
 	}
-	if err := e.plcSender.SendResponse(command, finalStatus, errMsg); err != nil {
+	if err := e.plcSender.SendCorrelatedResponse(correlationID, command, finalStatus, errMsg); err != nil {
 		utils.Logger.Errorf("❌ Failed to send PLC response: %v", err)
 	}
 }
 
-// sendOrder 오더 메시지 전송
-func (e *Executor) sendOrder(orderPayload interface{}) error {
-	topic := constants.GetMeiliOrderTopic(e.config.RobotManufacturer, e.config.RobotSerialNumber)
-	msgData, err := json.Marshal(orderPayload)
-	if err != nil {
-		return fmt.Errorf("failed to marshal order message: %v", err)
+// sendProgressToPLC 완료된 오더 수와 전체 오더 수로 진행률 응답 전송
+// 전체 수는 명령에 매핑된 활성 오더 수이며, 분기 경로에 따라 실제 실행 수와 다를 수 있습니다.
+func (e *Executor) sendProgressToPLC(cmdExec *models.CommandExecution) {
+	var completed int64
+	e.db.Model(&models.OrderExecution{}).
+		Where("command_execution_id = ? AND status = ?", cmdExec.ID, constants.OrderExecutionStatusCompleted).
+		Count(&completed)
+
+	var total int64
+	e.db.Model(&models.CommandOrderMapping{}).
+		Where("command_definition_id = ? AND is_active = ?", cmdExec.Command.CommandDefinitionID, true).
+		Count(&total)
+	if total < completed {
+		total = completed
 	}
-	token := e.mqttClient.Publish(topic, 0, false, msgData)
-	token.Wait()
-	return token.Error()
-}
 
-// MQTTMessageSender 구현
-type MQTTMessageSender struct {
-	mqttClient mqtt.Client
-	config     *config.Config
+	command := cmdExec.Command.CommandDefinition.CommandType
+	if err := e.plcSender.SendProgress(cmdExec.Command.CorrelationID, command, int(completed), int(total)); err != nil {
+		utils.Logger.Errorf("❌ Failed to send PLC progress: %v", err)
+	}
 }
 
-func (m *MQTTMessageSender) SendOrderMessage(orderMsg *models.OrderMessage) error {
-	topic := constants.GetMeiliOrderTopic(m.config.RobotManufacturer, m.config.RobotSerialNumber)
-	msgData, err := json.Marshal(orderMsg)
+// sendOrder 오더 메시지 전송
+func (e *Executor) sendOrder(orderPayload interface{}) (err error) {
+	topic := constants.GetMeiliOrderTopic(e.config.RobotManufacturer, e.config.RobotSerialNumber)
+	_, span := telemetry.StartPublishSpan(context.Background(), topic, "direct_order")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	msgData, err := json.Marshal(orderPayload)
 	if err != nil {
 		return fmt.Errorf("failed to marshal order message: %v", err)
 	}
-	token := m.mqttClient.Publish(topic, 0, false, msgData)
-	token.Wait()
-	return token.Error()
+	if !e.mqttClient.IsConnected() {
+		return apperr.New(apperr.CodeTransportUnavailable, "MQTT broker is not connected")
+	}
+	payloads := [][]byte{msgData}
+	if e.payloadPolicy != nil {
+		if payloads, err = e.payloadPolicy.Prepare(topic, msgData); err != nil {
+			return err
+		}
+	}
+	options := e.config.RobotPublishOptions(topic)
+	for _, payload := range payloads {
+		token := e.mqttClient.Publish(topic, options.QoS, options.Retained, payload)
+		token.Wait()
+		if token.Error() != nil {
+			return apperr.Wrap(apperr.CodeTransportUnavailable, token.Error(), "Failed to send order to robot")
+		}
+	}
+	return nil
 }
@@ -0,0 +1,108 @@
+// internal/workflow/geofence.go
+package workflow
+
+import (
+	"fmt"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// GeofenceValidator 오더 노드 위치를 로봇의 현재 맵과 구역 제한에 대해 검증
+type GeofenceValidator struct {
+	db     *gorm.DB
+	siteID string
+}
+
+// NewGeofenceValidator 새 지오펜스 검증기 생성
+func NewGeofenceValidator(db *gorm.DB, siteID string) *GeofenceValidator {
+	return &GeofenceValidator{
+		db:     db,
+		siteID: siteID,
+	}
+}
+
+// ValidateOrder 오더의 모든 노드가 허용된 영역 안에 있는지 검증합니다.
+// mapId가 비어 있는 노드는 현재 위치에서의 액션 실행으로 간주하여 위치 검증을 건너뜁니다.
+// 맵 메타데이터가 등록되지 않은 맵은 제한 없이 허용합니다.
+func (g *GeofenceValidator) ValidateOrder(orderMsg *models.OrderMessage) error {
+	currentMapID := g.currentMapID(orderMsg.SerialNumber)
+	maps := make(map[string]*models.RobotMap)
+
+	for _, node := range orderMsg.Nodes {
+		mapID := node.NodePosition.MapID
+		if mapID == "" {
+			continue
+		}
+
+		if currentMapID != "" && mapID != currentMapID {
+			return fmt.Errorf("node %s targets map %s but robot %s is on map %s",
+				node.NodeID, mapID, orderMsg.SerialNumber, currentMapID)
+		}
+
+		robotMap, ok := maps[mapID]
+		if !ok {
+			var err error
+			robotMap, err = repository.FindRobotMap(g.db, g.siteID, mapID)
+			if err != nil {
+				return fmt.Errorf("failed to load map %s: %v", mapID, err)
+			}
+			maps[mapID] = robotMap
+		}
+		if robotMap == nil {
+			continue
+		}
+
+		x := node.NodePosition.X.Float64Value()
+		y := node.NodePosition.Y.Float64Value()
+		if err := checkPosition(robotMap, x, y); err != nil {
+			return fmt.Errorf("node %s (%.2f, %.2f) rejected: %v", node.NodeID, x, y, err)
+		}
+	}
+
+	return nil
+}
+
+// currentMapID 로봇의 마지막 보고 맵 ID 조회 (알 수 없으면 빈 문자열)
+func (g *GeofenceValidator) currentMapID(serialNumber string) string {
+	var status models.RobotStatus
+	err := g.db.Scopes(repository.SiteScope(g.siteID)).
+		Where("serial_number = ?", serialNumber).
+		First(&status).Error
+	if err != nil {
+		return ""
+	}
+	return status.CurrentMapID
+}
+
+// checkPosition 맵 경계, 진입 금지 구역, 허용 구역 순으로 좌표 검사
+func checkPosition(robotMap *models.RobotMap, x, y float64) error {
+	if robotMap.HasBounds() &&
+		(x < robotMap.MinX || x > robotMap.MaxX || y < robotMap.MinY || y > robotMap.MaxY) {
+		return fmt.Errorf("outside bounds of map %s", robotMap.MapID)
+	}
+
+	hasAllowedZones := false
+	inAllowedZone := false
+	for i := range robotMap.Zones {
+		zone := &robotMap.Zones[i]
+		switch zone.ZoneType {
+		case constants.ZoneTypeRestricted:
+			if zone.Contains(x, y) {
+				return fmt.Errorf("inside restricted zone %s", zone.Name)
+			}
+		case constants.ZoneTypeAllowed:
+			hasAllowedZones = true
+			if zone.Contains(x, y) {
+				inAllowedZone = true
+			}
+		}
+	}
+
+	if hasAllowedZones && !inAllowedZone {
+		return fmt.Errorf("outside allowed zones of map %s", robotMap.MapID)
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+// internal/workflow/golden.go
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/config"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// goldenGeneratedValue 생성할 때마다 달라지는 ID 대신 골든 메시지에 기록하는 값
+const goldenGeneratedValue = "<generated>"
+
+// goldenGeneratedKeys 값 대신 존재 여부만 비교하는 필드 (idgen으로 새로 만드는 ID)
+var goldenGeneratedKeys = map[string]bool{
+	"nodeId":   true,
+	"edgeId":   true,
+	"actionId": true,
+}
+
+// goldenIgnoredKeys 전송 시점에 채워지는 필드 (골든 메시지에서 제외)
+var goldenIgnoredKeys = map[string]bool{
+	"headerId":  true,
+	"timestamp": true,
+}
+
+// GoldenContext 골든 메시지를 만들 합성 실행 조건
+type GoldenContext struct {
+	Manufacturer string            `json:"manufacturer"`
+	SerialNumber string            `json:"serial_number"`
+	OrderID      string            `json:"order_id"`
+	Parameters   map[string]string `json:"parameters,omitempty"` // 템플릿 자리표시자 치환 값
+}
+
+// GoldenMessage 단계(병렬 그룹이면 그룹 전체)가 만드는 정규화된 오더 메시지
+type GoldenMessage struct {
+	StepOrders []int       `json:"step_orders"`
+	Message    interface{} `json:"message"`
+}
+
+// GoldenFixture 템플릿과 실행 조건, 그리고 OrderBuilder가 만들어야 하는 메시지
+// 템플릿은 하위 템플릿을 펼친 내보내기 형식이라 DB 없이 렌더링할 수 있습니다.
+type GoldenFixture struct {
+	Name     string                    `json:"name"`
+	Template repository.TemplateExport `json:"template"`
+	Context  GoldenContext             `json:"context"`
+	Messages []GoldenMessage           `json:"messages"`
+}
+
+// GoldenDiff 골든 메시지와 렌더링한 메시지의 차이 하나
+type GoldenDiff struct {
+	StepOrder int         `json:"step_order"` // 메시지의 첫 단계
+	Path      string      `json:"path"`       // JSON 경로 (예: nodes[0].actions[1].actionType), 메시지가 한쪽에만 있으면 빈 값
+	Expected  interface{} `json:"expected"`
+	Actual    interface{} `json:"actual"`
+}
+
+// GoldenResult 픽스처 하나의 비교 결과
+type GoldenResult struct {
+	File    string       `json:"file"`
+	Name    string       `json:"name"`
+	Passed  bool         `json:"passed"`
+	Updated bool         `json:"updated,omitempty"` // 차이를 골든 메시지에 반영함 (update 실행)
+	Diffs   []GoldenDiff `json:"diffs,omitempty"`
+	Error   string       `json:"error,omitempty"` // 픽스처를 읽거나 렌더링하지 못한 사유
+}
+
+// NewGoldenFixture 템플릿(하위 템플릿을 펼친 것)과 실행 조건으로 현재 OrderBuilder 출력을 골든 메시지로 기록
+func NewGoldenFixture(name string, template *models.OrderTemplate, ctx GoldenContext) (*GoldenFixture, error) {
+	fixture := &GoldenFixture{Name: name, Template: repository.ToTemplateExport(template), Context: ctx}
+	messages, err := RenderGoldenMessages(fixture)
+	if err != nil {
+		return nil, err
+	}
+	fixture.Messages = messages
+	return fixture, nil
+}
+
+// RenderGoldenMessages 픽스처의 템플릿과 실행 조건으로 단계별 오더 메시지를 렌더링합니다 (DB 없이 실행).
+func RenderGoldenMessages(fixture *GoldenFixture) ([]GoldenMessage, error) {
+	template, err := repository.TemplateFromExport(fixture.Template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fixture template %q: %w", fixture.Template.Name, err)
+	}
+	builder := NewOrderBuilder(nil, &config.Config{
+		RobotManufacturer: fixture.Context.Manufacturer,
+		RobotSerialNumber: fixture.Context.SerialNumber,
+	})
+	execution := &models.OrderExecution{
+		SerialNumber:       fixture.Context.SerialNumber,
+		OrderID:            fixture.Context.OrderID,
+		ParameterOverrides: repository.EncodeParameters(fixture.Context.Parameters),
+	}
+
+	messages := []GoldenMessage{}
+	for i := 0; i < len(template.OrderSteps); {
+		step := &template.OrderSteps[i]
+		members := []*models.OrderStep{step}
+		if step.ParallelGroup != "" {
+			members = parallelGroupMembers(template, step)
+		}
+		entry := GoldenMessage{}
+		for _, member := range members {
+			entry.StepOrders = append(entry.StepOrders, member.StepOrder)
+		}
+		data, err := json.Marshal(builder.buildGroupOrderBody(execution, members))
+		if err != nil {
+			return nil, err
+		}
+		var message interface{}
+		if err := json.Unmarshal(data, &message); err != nil {
+			return nil, err
+		}
+		entry.Message = normalizeGoldenValue(message)
+		messages = append(messages, entry)
+		i += len(members)
+	}
+	return messages, nil
+}
+
+// CompareGoldenFixture 픽스처를 렌더링하여 기록된 골든 메시지와 비교
+func CompareGoldenFixture(fixture *GoldenFixture) ([]GoldenDiff, error) {
+	actual, err := RenderGoldenMessages(fixture)
+	if err != nil {
+		return nil, err
+	}
+
+	expected := make(map[int]interface{}, len(fixture.Messages))
+	for _, message := range fixture.Messages {
+		if len(message.StepOrders) > 0 {
+			expected[message.StepOrders[0]] = message.Message
+		}
+	}
+	rendered := make([]shadowStep, 0, len(actual))
+	for _, message := range actual {
+		rendered = append(rendered, shadowStep{stepOrder: message.StepOrders[0], message: message.Message})
+	}
+
+	diffs := make([]GoldenDiff, 0)
+	for _, diff := range diffShadowMessages(expected, rendered, true) {
+		diffs = append(diffs, GoldenDiff{StepOrder: diff.StepOrder, Path: diff.Path, Expected: diff.Primary, Actual: diff.Shadow})
+	}
+	return diffs, nil
+}
+
+// LoadGoldenFixture 픽스처 파일 읽기
+func LoadGoldenFixture(path string) (*GoldenFixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fixture GoldenFixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("invalid golden fixture %s: %w", path, err)
+	}
+	return &fixture, nil
+}
+
+// SaveGoldenFixture 픽스처 파일 쓰기 (리뷰에서 차이가 잘 보이도록 들여쓰기)
+func SaveGoldenFixture(path string, fixture *GoldenFixture) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false) // <generated>가 \u003c로 바뀌지 않도록
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(fixture); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// RunGoldenFixtures 파일 또는 디렉터리(*.json, *.golden)의 픽스처를 모두 비교합니다.
+// update면 차이가 있는 픽스처의 골든 메시지를 현재 렌더링 결과로 다시 씁니다.
+func RunGoldenFixtures(paths []string, update bool) ([]GoldenResult, error) {
+	files, err := goldenFixtureFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]GoldenResult, 0, len(files))
+	for _, file := range files {
+		result := GoldenResult{File: file}
+		fixture, err := LoadGoldenFixture(file)
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+		result.Name = fixture.Name
+		diffs, err := CompareGoldenFixture(fixture)
+		switch {
+		case err != nil:
+			result.Error = err.Error()
+		case len(diffs) > 0 && update:
+			if fixture.Messages, err = RenderGoldenMessages(fixture); err == nil {
+				err = SaveGoldenFixture(file, fixture)
+			}
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Passed = true
+				result.Updated = true
+				result.Diffs = diffs
+			}
+		default:
+			result.Passed = len(diffs) == 0
+			result.Diffs = diffs
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// goldenFixtureFiles 경로 목록을 픽스처 파일 목록으로 (디렉터리는 바로 아래 *.json과 *.golden, 이름 순)
+func goldenFixtureFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		var matches []string
+		for _, pattern := range []string{"*.json", "*.golden"} {
+			found, err := filepath.Glob(filepath.Join(path, pattern))
+			if err != nil {
+				return nil, err
+			}
+			matches = append(matches, found...)
+		}
+		sort.Strings(matches)
+		files = append(files, matches...)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no golden fixtures found in %s", strings.Join(paths, ", "))
+	}
+	return files, nil
+}
+
+// normalizeGoldenValue 전송 시점 필드를 지우고 생성 ID를 고정 값으로 바꾼 사본
+func normalizeGoldenValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			if goldenIgnoredKeys[key] {
+				continue
+			}
+			if id, ok := child.(string); ok && id != "" && goldenGeneratedKeys[key] {
+				result[key] = goldenGeneratedValue
+				continue
+			}
+			result[key] = normalizeGoldenValue(child)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, child := range v {
+			result[i] = normalizeGoldenValue(child)
+		}
+		return result
+	default:
+		return value
+	}
+}
@@ -0,0 +1,44 @@
+// internal/workflow/golden_test.go
+package workflow
+
+import (
+	"flag"
+	"path/filepath"
+	"testing"
+)
+
+// updateGolden go test ./internal/workflow -run TestGoldenOrderMessages -update 로 골든 메시지 갱신
+var updateGolden = flag.Bool("update", false, "rewrite testdata/golden/*.golden with the current OrderBuilder output")
+
+// TestGoldenOrderMessages testdata/golden의 템플릿을 OrderBuilder로 렌더링하여 기록된 오더 메시지와 비교
+func TestGoldenOrderMessages(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "golden", "*.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no golden fixtures in testdata/golden")
+	}
+
+	results, err := RunGoldenFixtures(files, *updateGolden)
+	if err != nil {
+		t.Fatalf("RunGoldenFixtures: %v", err)
+	}
+	for _, result := range results {
+		t.Run(filepath.Base(result.File), func(t *testing.T) {
+			if result.Error != "" {
+				t.Fatalf("%s: %s", result.File, result.Error)
+			}
+			if result.Updated {
+				t.Logf("%s: rewrote golden messages (%d difference(s))", result.File, len(result.Diffs))
+				return
+			}
+			for _, diff := range result.Diffs {
+				t.Errorf("step %d %s: expected %v, got %v", diff.StepOrder, diff.Path, diff.Expected, diff.Actual)
+			}
+			if !result.Passed && len(result.Diffs) > 0 {
+				t.Logf("rerun with -update if the OrderBuilder change is intended")
+			}
+		})
+	}
+}
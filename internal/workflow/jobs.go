@@ -0,0 +1,183 @@
+// internal/workflow/jobs.go
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/utils"
+	"time"
+)
+
+// jobPollInterval 작업 묶음의 새 작업 항목과 실행 중인 작업 항목을 확인하는 간격
+const jobPollInterval = 2 * time.Second
+
+// JobRunner 브릿지가 관리하는 로봇의 작업 항목을 실행합니다.
+// 로봇에 실행 중인 오더가 없으면 가장 먼저 만든 PENDING 작업 항목을 가져가 PLC 명령과 같은 경로로 실행하고,
+// 명령이 끝나면 작업 항목과 작업 묶음 상태를 갱신하며, 작업 묶음이 취소되면 실행 중인 명령을 취소합니다.
+type JobRunner struct {
+	executor *Executor
+	interval time.Duration
+
+	cancel context.CancelFunc
+	doneCh chan struct{}
+}
+
+// NewJobRunner 새 작업 항목 실행기 생성
+func NewJobRunner(executor *Executor) *JobRunner {
+	return &JobRunner{executor: executor, interval: jobPollInterval}
+}
+
+// Start 주기적 작업 항목 확인 시작
+func (r *JobRunner) Start(ctx context.Context) {
+	ctx, r.cancel = context.WithCancel(ctx)
+	r.doneCh = make(chan struct{})
+	go r.run(ctx)
+}
+
+// Stop 작업 항목 확인 중지 (실행 중인 명령은 계속 진행)
+func (r *JobRunner) Stop() {
+	if r.cancel == nil {
+		return
+	}
+	r.cancel()
+	<-r.doneCh
+	r.cancel = nil
+}
+
+func (r *JobRunner) run(ctx context.Context) {
+	defer close(r.doneCh)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.Poll()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Poll 실행 중인 작업 항목을 갱신하고 로봇이 비어 있으면 다음 작업 항목 시작
+func (r *JobRunner) Poll() {
+	e := r.executor
+	siteID, serialNumber := e.config.SiteID, e.config.RobotSerialNumber
+
+	tasks, jobStatuses, err := repository.RunningJobTasks(e.db, siteID, serialNumber)
+	if err != nil {
+		utils.Logger.Errorf("❌ Failed to load running job tasks of robot %s: %v", serialNumber, err)
+		return
+	}
+	for i := range tasks {
+		r.track(&tasks[i], jobStatuses[tasks[i].JobRef])
+	}
+
+	if e.readOnly.Enabled() || !r.robotReady() {
+		return
+	}
+	active, err := repository.HasActiveOrders(e.db, siteID, serialNumber)
+	if err != nil || active {
+		return
+	}
+	task, job, err := repository.NextJobTask(e.db, siteID, serialNumber)
+	if err != nil {
+		utils.Logger.Errorf("❌ Failed to load next job task of robot %s: %v", serialNumber, err)
+		return
+	}
+	if task != nil {
+		r.dispatch(task, job)
+	}
+}
+
+// track 실행 중인 작업 항목의 명령 상태를 반영하고, 작업 묶음이 취소되었으면 명령 취소
+func (r *JobRunner) track(task *models.JobTask, jobStatus string) {
+	e := r.executor
+	if jobStatus == constants.JobStatusCancelled {
+		if err := e.CancelCommand(task.CommandID, "job cancelled"); err != nil {
+			utils.Logger.Errorf("❌ Failed to cancel command of job task %d: %v", task.ID, err)
+		}
+		r.finish(task, constants.JobStatusCancelled, "job cancelled")
+		return
+	}
+
+	var cmdExec models.CommandExecution
+	if err := e.db.Where("command_id = ?", task.CommandID).Order("id DESC").First(&cmdExec).Error; err != nil {
+		return // 아직 명령 실행을 만들기 전
+	}
+	switch cmdExec.Status {
+	case constants.CommandExecutionStatusCompleted:
+		r.finish(task, constants.JobStatusCompleted, "")
+	case constants.CommandExecutionStatusFailed, constants.CommandExecutionStatusEStopped:
+		r.finish(task, constants.JobStatusFailed, "command "+cmdExec.Status)
+	case constants.CommandExecutionStatusCancelled:
+		r.finish(task, constants.JobStatusCancelled, "command cancelled")
+	}
+}
+
+// dispatch 작업 항목을 가져가 명령으로 실행
+func (r *JobRunner) dispatch(task *models.JobTask, job *models.Job) {
+	e := r.executor
+	claimed, err := repository.ClaimJobTask(e.db, task)
+	if err != nil || !claimed {
+		if err != nil {
+			utils.Logger.Errorf("❌ Failed to claim job task %d: %v", task.ID, err)
+		}
+		return
+	}
+
+	var definition models.CommandDefinition
+	if err := e.db.Where("command_type = ? AND is_active = ?", task.CommandType, true).First(&definition).Error; err != nil {
+		r.finish(task, constants.JobStatusFailed, fmt.Sprintf("command %s not defined or inactive", task.CommandType))
+		return
+	}
+	command := &models.Command{
+		CommandDefinitionID: definition.ID,
+		Status:              constants.CommandStatusPending,
+		RequestTime:         time.Now(),
+		CorrelationID:       job.CorrelationID,
+		ParameterOverrides:  task.Parameters,
+	}
+	if err := e.db.Create(command).Error; err != nil {
+		utils.Logger.Errorf("❌ Failed to record command of job task %d: %v", task.ID, err)
+		if err := repository.ReleaseJobTask(e.db, task); err != nil {
+			utils.Logger.Errorf("❌ Failed to release job task %d: %v", task.ID, err)
+		}
+		return
+	}
+	command.CommandDefinition = definition
+	if err := e.db.Model(task).Update("command_id", command.ID).Error; err != nil {
+		utils.Logger.Errorf("❌ Failed to link command %d to job task %d: %v", command.ID, task.ID, err)
+	}
+
+	utils.Logger.Infof("🧩 Job %s task %d: running %s on robot %s (cid=%s)",
+		job.JobID, task.Sequence, task.CommandType, task.SerialNumber, job.CorrelationID)
+	if err := e.ExecuteCommandOrder(command); err != nil {
+		utils.Logger.Errorf("❌ Job %s task %d failed to start: %v", job.JobID, task.Sequence, err)
+		repository.UpdateCommandStatus(e.db, command, constants.CommandStatusFailure, err.Error())
+		r.finish(task, constants.JobStatusFailed, err.Error())
+	}
+}
+
+// finish 작업 항목 종료 기록
+func (r *JobRunner) finish(task *models.JobTask, status, errMsg string) {
+	if err := repository.FinishJobTask(r.executor.db, task, status, errMsg); err != nil {
+		utils.Logger.Errorf("❌ Failed to finish job task %d: %v", task.ID, err)
+	}
+}
+
+// robotReady 로봇이 ONLINE이고 유지보수 중이 아닌지
+func (r *JobRunner) robotReady() bool {
+	e := r.executor
+	var status models.RobotStatus
+	err := e.db.Scopes(repository.SiteScope(e.config.SiteID)).
+		Where("serial_number = ?", e.config.RobotSerialNumber).First(&status).Error
+	if err != nil || status.ConnectionState != constants.ConnectionStateOnline {
+		return false
+	}
+	maintenance, err := repository.ActiveMaintenance(e.db, e.config.SiteID, e.config.RobotSerialNumber)
+	return err == nil && maintenance == nil
+}
@@ -8,10 +8,14 @@ import (
 	"mqtt-bridge/internal/common/types"
 	"mqtt-bridge/internal/config"
 	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
 	"mqtt-bridge/internal/utils"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // DirectOrderMessage Direct Action Order 전용 구조체 (공통 타입 사용)
@@ -74,74 +78,118 @@ type DirectOrderEdge struct {
 
 // OrderBuilder 오더 메시지 생성기
 type OrderBuilder struct {
+	db     *gorm.DB
 	config *config.Config
 	idGen  *idgen.Generator
 }
 
 // NewOrderBuilder 새 오더 빌더 생성
-func NewOrderBuilder(cfg *config.Config) *OrderBuilder {
+func NewOrderBuilder(db *gorm.DB, cfg *config.Config) *OrderBuilder {
 	return &OrderBuilder{
+		db:     db,
 		config: cfg,
 		idGen:  idgen.NewGenerator("order"),
 	}
 }
 
+// robotDefaults 로봇의 기본 위치 조회 (없거나 조회 실패면 nil, 위치는 0으로 채워짐)
+func (b *OrderBuilder) robotDefaults(serialNumber string) *models.RobotDefaults {
+	if b.db == nil {
+		return nil
+	}
+	if serialNumber == "" {
+		serialNumber = b.config.RobotSerialNumber
+	}
+	defaults, err := repository.FindRobotDefaults(b.db, b.config.SiteID, serialNumber)
+	if err != nil {
+		utils.Logger.Warnf("⚠️ Failed to load defaults for robot %s, using zero position: %v", serialNumber, err)
+		return nil
+	}
+	return defaults
+}
+
+// defaultNodePosition 로봇 기본 위치로 노드 위치 생성 (기본값이 없으면 0)
+func defaultNodePosition(defaults *models.RobotDefaults) models.NodePosition {
+	if defaults == nil {
+		return models.NodePosition{
+			X:                     models.Float64(0.0),
+			Y:                     models.Float64(0.0),
+			Theta:                 models.Float64(0.0),
+			AllowedDeviationXY:    models.Float64(0.0),
+			AllowedDeviationTheta: models.Float64(0.0),
+			MapID:                 "",
+		}
+	}
+	return models.NodePosition{
+		X:                     models.Float64(defaults.X),
+		Y:                     models.Float64(defaults.Y),
+		Theta:                 models.Float64(defaults.Theta),
+		AllowedDeviationXY:    models.Float64(defaults.AllowedDeviationXY),
+		AllowedDeviationTheta: models.Float64(defaults.AllowedDeviationTheta),
+		MapID:                 defaults.MapID,
+	}
+}
+
 // BuildOrderMessage 표준 오더 메시지 생성
 func (b *OrderBuilder) BuildOrderMessage(execution *models.OrderExecution, step *models.OrderStep) *models.OrderMessage {
-	node := b.buildOrderNode(step)
-	edges := b.buildOrderEdges(step)
+	return b.BuildGroupOrderMessage(execution, []*models.OrderStep{step})
+}
+
+// BuildGroupOrderMessage 병렬 그룹의 단계들을 하나의 오더로 생성 (단계 순서대로 노드 하나씩, 엣지는 이어 붙임)
+func (b *OrderBuilder) BuildGroupOrderMessage(execution *models.OrderExecution, steps []*models.OrderStep) *models.OrderMessage {
+	msg := b.buildGroupOrderBody(execution, steps)
+	msg.HeaderID = utils.NextHeaderID(constants.GetMeiliOrderTopic(msg.Manufacturer, msg.SerialNumber))
+	msg.Timestamp = time.Now().Format(time.RFC3339Nano)
+	return msg
+}
+
+// buildGroupOrderBody headerId/timestamp 없이 오더 메시지 본문 생성 (섀도 비교처럼 전송하지 않는 메시지용)
+func (b *OrderBuilder) buildGroupOrderBody(execution *models.OrderExecution, steps []*models.OrderStep) *models.OrderMessage {
+	params := repository.DecodeParameters(execution.ParameterOverrides)
+	defaults := b.robotDefaults(execution.SerialNumber)
+	nodes := make([]models.OrderNode, 0, len(steps))
+	edges := make([]models.OrderEdge, 0)
+	for _, step := range steps {
+		nodes = append(nodes, b.buildOrderNode(step, params, defaults))
+		edges = append(edges, b.buildOrderEdges(step)...)
+	}
 
 	return &models.OrderMessage{
-		HeaderID:      utils.GetNextHeaderID(),
-		Timestamp:     time.Now().Format(time.RFC3339Nano),
 		Version:       "2.0.0",
 		Manufacturer:  b.config.RobotManufacturer,
 		SerialNumber:  b.config.RobotSerialNumber,
 		OrderID:       execution.OrderID,
 		OrderUpdateID: 0,
-		Nodes:         []models.OrderNode{node},
+		Nodes:         nodes,
 		Edges:         edges,
 	}
 }
 
-// BuildDirectActionOrder 직접 액션 오더 메시지 생성 (공통 상수 사용)
-func (b *OrderBuilder) BuildDirectActionOrder(baseCommand string, commandType rune, armParam string) (*DirectOrderMessage, string, error) {
-	var actionType string
-	var actionParameters []DirectOrderActionParameter
-
-	switch commandType {
-	case constants.CommandTypeInference:
-		actionType = constants.ActionTypeInference
-		actionParameters = []DirectOrderActionParameter{
-			{
-				Key:   "inference_name",
-				Value: baseCommand,
-			},
-		}
-	case constants.CommandTypeTrajectory:
-		actionType = constants.ActionTypeTrajectory
-		actionParameters = []DirectOrderActionParameter{
-			{
-				Key:   "trajectory_name",
-				Value: baseCommand,
-			},
-		}
+// BuildDirectActionOrder 직접 액션 오더 메시지 생성 (레지스트리로 해석된 액션 사용)
+func (b *OrderBuilder) BuildDirectActionOrder(call *repository.DirectActionCall) (*DirectOrderMessage, string, error) {
+	if call == nil || call.ActionType == "" {
+		return nil, "", fmt.Errorf("invalid direct action: action type is required")
+	}
+	baseCommand := call.BaseCommand
+	actionType := call.ActionType
+	blockingType := call.BlockingType
+	if blockingType == "" {
+		blockingType = constants.BlockingTypeNone
+	}
 
-		// arm 파라미터 처리 (공통 함수 사용)
-		arm := constants.ParseArmParam(armParam)
+	actionParameters := make([]DirectOrderActionParameter, 0, len(call.Parameters))
+	for _, param := range call.Parameters {
 		actionParameters = append(actionParameters, DirectOrderActionParameter{
-			Key:   "arm",
-			Value: arm,
+			Key:   param.Key,
+			Value: param.Value,
 		})
-
-	default:
-		return nil, "", fmt.Errorf("invalid direct action command type: %c", commandType)
 	}
 
 	orderID := idgen.OrderID() // 공통 ID 생성기 사용
+	position := defaultNodePosition(b.robotDefaults(""))
 
 	directOrder := &DirectOrderMessage{
-		HeaderID:      utils.GetNextHeaderID(),
+		HeaderID:      utils.NextHeaderID(constants.GetMeiliOrderTopic(b.config.RobotManufacturer, b.config.RobotSerialNumber)),
 		Timestamp:     time.Now().Format(time.RFC3339Nano),
 		Version:       "2.0.0",
 		Manufacturer:  b.config.RobotManufacturer,
@@ -155,19 +203,19 @@ func (b *OrderBuilder) BuildDirectActionOrder(baseCommand string, commandType ru
 				SequenceID:  1,
 				Released:    true,
 				NodePosition: DirectNodePosition{
-					X:                     types.ZeroFloat64(),
-					Y:                     types.ZeroFloat64(),
-					Theta:                 types.ZeroFloat64(),
-					AllowedDeviationXY:    types.ZeroFloat64(),
-					AllowedDeviationTheta: types.ZeroFloat64(),
-					MapID:                 "",
+					X:                     position.X,
+					Y:                     position.Y,
+					Theta:                 position.Theta,
+					AllowedDeviationXY:    position.AllowedDeviationXY,
+					AllowedDeviationTheta: position.AllowedDeviationTheta,
+					MapID:                 position.MapID,
 				},
 				Actions: []DirectOrderAction{
 					{
 						ActionType:        actionType,
 						ActionID:          idgen.ActionID(), // 공통 ID 생성기 사용
 						ActionDescription: fmt.Sprintf("Execute %s for %s", actionType, baseCommand),
-						BlockingType:      constants.BlockingTypeNone,
+						BlockingType:      blockingType,
 						ActionParameters:  actionParameters,
 					},
 				},
@@ -179,12 +227,22 @@ func (b *OrderBuilder) BuildDirectActionOrder(baseCommand string, commandType ru
 	return directOrder, orderID, nil
 }
 
-// BuildCancelOrderMessage 취소 오더 메시지 생성 (공통 상수 사용)
-func (b *OrderBuilder) BuildCancelOrderMessage() (map[string]interface{}, error) {
-	actionID := idgen.UniqueID() // 공통 ID 생성기 사용
+// instantActionsTopic 설정된 로봇의 instantActions 토픽 (headerId 순번용)
+func (b *OrderBuilder) instantActionsTopic() string {
+	return constants.GetMeiliInstantActionsTopic(b.config.RobotManufacturer, b.config.RobotSerialNumber)
+}
+
+// BuildCancelOrderMessage 취소 오더 메시지 생성 (orderID가 있으면 취소할 오더를 파라미터로 지정)
+func (b *OrderBuilder) BuildCancelOrderMessage(orderID string) (map[string]interface{}, error) {
+	actionID := idgen.ActionID() // 공통 ID 생성기 사용
+
+	actionParameters := []map[string]interface{}{}
+	if orderID != "" {
+		actionParameters = append(actionParameters, map[string]interface{}{"key": "orderId", "value": orderID})
+	}
 
 	request := map[string]interface{}{
-		"headerId":     utils.GetNextHeaderID(),
+		"headerId":     utils.NextHeaderID(b.instantActionsTopic()),
 		"timestamp":    time.Now().Format(time.RFC3339Nano),
 		"version":      "2.0.0",
 		"manufacturer": b.config.RobotManufacturer,
@@ -194,34 +252,105 @@ func (b *OrderBuilder) BuildCancelOrderMessage() (map[string]interface{}, error)
 				"actionType":       constants.ActionTypeCancelOrder,
 				"actionId":         actionID,
 				"blockingType":     constants.BlockingTypeHard,
+				"actionParameters": actionParameters,
+			},
+		},
+	}
+
+	return request, nil
+}
+
+// BuildStateRequestMessage 로봇에 즉시 상태 메시지 발행을 요청하는 stateRequest 메시지 생성
+func (b *OrderBuilder) BuildStateRequestMessage() map[string]interface{} {
+	return map[string]interface{}{
+		"headerId":     utils.NextHeaderID(b.instantActionsTopic()),
+		"timestamp":    time.Now().Format(time.RFC3339Nano),
+		"version":      "2.0.0",
+		"manufacturer": b.config.RobotManufacturer,
+		"serialNumber": b.config.RobotSerialNumber,
+		"actions": []map[string]interface{}{
+			{
+				"actionType":       constants.ActionTypeStateRequest,
+				"actionId":         idgen.ActionID(),
+				"blockingType":     constants.BlockingTypeNone,
 				"actionParameters": []map[string]interface{}{},
 			},
 		},
 	}
+}
+
+// BuildPauseMessage 로봇 일시정지/재개 instantActions 메시지 생성 (actionType은 startPause 또는 stopPause)
+func (b *OrderBuilder) BuildPauseMessage(actionType string) map[string]interface{} {
+	return map[string]interface{}{
+		"headerId":     utils.NextHeaderID(b.instantActionsTopic()),
+		"timestamp":    time.Now().Format(time.RFC3339Nano),
+		"version":      "2.0.0",
+		"manufacturer": b.config.RobotManufacturer,
+		"serialNumber": b.config.RobotSerialNumber,
+		"actions": []map[string]interface{}{
+			{
+				"actionType":       actionType,
+				"actionId":         idgen.ActionID(),
+				"blockingType":     constants.BlockingTypeHard,
+				"actionParameters": []map[string]interface{}{},
+			},
+		},
+	}
+}
+
+// BuildEmergencyStopMessage 비상 정지용 instantActions 메시지 생성 (모든 액션 HARD 블로킹)
+func (b *OrderBuilder) BuildEmergencyStopMessage(actionTypes []string) (map[string]interface{}, error) {
+	actions := make([]map[string]interface{}, 0, len(actionTypes))
+	for _, actionType := range actionTypes {
+		actionType = strings.TrimSpace(actionType)
+		if actionType == "" {
+			continue
+		}
+		actions = append(actions, map[string]interface{}{
+			"actionType":       actionType,
+			"actionId":         idgen.ActionID(),
+			"blockingType":     constants.BlockingTypeHard,
+			"actionParameters": []map[string]interface{}{},
+		})
+	}
+	if len(actions) == 0 {
+		return nil, fmt.Errorf("no emergency stop actions configured")
+	}
+
+	request := map[string]interface{}{
+		"headerId":     utils.NextHeaderID(b.instantActionsTopic()),
+		"timestamp":    time.Now().Format(time.RFC3339Nano),
+		"version":      "2.0.0",
+		"manufacturer": b.config.RobotManufacturer,
+		"serialNumber": b.config.RobotSerialNumber,
+		"actions":      actions,
+	}
 
 	return request, nil
 }
 
-// buildOrderNode 오더 노드 생성 (공통 타입 사용)
-func (b *OrderBuilder) buildOrderNode(step *models.OrderStep) models.OrderNode {
+// buildOrderNode 오더 노드 생성 (노드 설명과 액션 파라미터의 자리표시자 치환)
+// 노드 템플릿이 없으면 로봇 기본 위치를 쓰고, 템플릿의 빈 맵 ID와 0인 허용 편차는 기본값으로 채웁니다.
+func (b *OrderBuilder) buildOrderNode(step *models.OrderStep, params map[string]string, defaults *models.RobotDefaults) models.OrderNode {
 	nodeID := idgen.NodeID() // 공통 ID 생성기 사용
 
-	nodePos := models.NodePosition{
-		X:                     models.Float64(0.0),
-		Y:                     models.Float64(0.0),
-		Theta:                 models.Float64(0.0),
-		AllowedDeviationXY:    models.Float64(0.0),
-		AllowedDeviationTheta: models.Float64(0.0),
-		MapID:                 "",
-	}
+	nodePos := defaultNodePosition(defaults)
 
+	description := ""
 	if step.NodeTemplate != nil {
+		description = repository.SubstitutePlaceholders(step.NodeTemplate.Description, params)
 		nodePos.X = models.Float64(step.NodeTemplate.X)
 		nodePos.Y = models.Float64(step.NodeTemplate.Y)
 		nodePos.Theta = models.Float64(step.NodeTemplate.Theta)
-		nodePos.AllowedDeviationXY = models.Float64(step.NodeTemplate.AllowedDeviationXY)
-		nodePos.AllowedDeviationTheta = models.Float64(step.NodeTemplate.AllowedDeviationTheta)
-		nodePos.MapID = step.NodeTemplate.MapID
+		if step.NodeTemplate.AllowedDeviationXY != 0 {
+			nodePos.AllowedDeviationXY = models.Float64(step.NodeTemplate.AllowedDeviationXY)
+		}
+		if step.NodeTemplate.AllowedDeviationTheta != 0 {
+			nodePos.AllowedDeviationTheta = models.Float64(step.NodeTemplate.AllowedDeviationTheta)
+		}
+		if step.NodeTemplate.MapID != "" {
+			nodePos.MapID = step.NodeTemplate.MapID
+		}
 	}
 
 	sort.Slice(step.StepActionMappings, func(i, j int) bool {
@@ -236,14 +365,14 @@ func (b *OrderBuilder) buildOrderNode(step *models.OrderStep) models.OrderNode {
 			ActionID:          idgen.ActionID(), // 공통 ID 생성기 사용
 			ActionDescription: actionTemplate.ActionDescription,
 			BlockingType:      actionTemplate.BlockingType,
-			ActionParameters:  b.buildActionParameters(actionTemplate.Parameters),
+			ActionParameters:  b.buildActionParameters(actionTemplate.Parameters, params),
 		}
 		actions = append(actions, action)
 	}
 
 	return models.OrderNode{
 		NodeID:       nodeID,
-		Description:  "",
+		Description:  description,
 		SequenceID:   step.StepOrder,
 		Released:     true,
 		NodePosition: nodePos,
@@ -276,27 +405,28 @@ func (b *OrderBuilder) buildOrderEdges(step *models.OrderStep) []models.OrderEdg
 }
 
 // buildActionParameters 액션 파라미터 생성
-func (b *OrderBuilder) buildActionParameters(params []models.ActionParameter) []models.OrderActionParameter {
+func (b *OrderBuilder) buildActionParameters(params []models.ActionParameter, overrides map[string]string) []models.OrderActionParameter {
 	actionParams := make([]models.OrderActionParameter, 0, len(params))
 
 	for _, param := range params {
 		var value interface{}
+		rawValue := repository.SubstitutePlaceholders(param.Value, overrides)
 
 		switch param.ValueType {
 		case "NUMBER":
-			if floatVal, err := strconv.ParseFloat(param.Value, 64); err == nil {
+			if floatVal, err := strconv.ParseFloat(rawValue, 64); err == nil {
 				value = floatVal
 			} else {
-				value = param.Value
+				value = rawValue
 			}
 		case "BOOLEAN":
-			if boolVal, err := strconv.ParseBool(param.Value); err == nil {
+			if boolVal, err := strconv.ParseBool(rawValue); err == nil {
 				value = boolVal
 			} else {
-				value = param.Value
+				value = rawValue
 			}
 		default: // STRING
-			value = param.Value
+			value = rawValue
 		}
 
 		actionParam := models.OrderActionParameter{
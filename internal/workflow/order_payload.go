@@ -0,0 +1,365 @@
+// internal/workflow/order_payload.go
+package workflow
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/config"
+	"mqtt-bridge/internal/utils"
+	"strings"
+)
+
+// 오더 페이로드 압축 방식 (ORDER_COMPRESSION)
+const (
+	OrderCompressionOff  = "off"
+	OrderCompressionGzip = "gzip"
+)
+
+// 최대 크기를 넘는 오더 처리 (ORDER_OVERSIZE_POLICY)
+const (
+	OrderOversizeReject = "reject" // 보내지 않고 크기를 담은 오류 반환
+	OrderOversizeSplit  = "split"  // 노드를 나눠 기본 오더와 이어지는 오더 업데이트(orderUpdateId 증가)로 전송
+)
+
+// gzipMagic gzip 스트림 첫 두 바이트 (JSON 페이로드는 이 값으로 시작할 수 없음)
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// IsGzipPayload 페이로드가 gzip으로 압축되었는지 확인
+// MQTT 3.1.1에는 헤더가 없으므로 로봇은 이 두 바이트로 압축 여부를 판단합니다.
+// HTTP 전송은 Content-Encoding: gzip, MQTT 5는 content-encoding 사용자 속성을 함께 보냅니다.
+func IsGzipPayload(payload []byte) bool {
+	return bytes.HasPrefix(payload, gzipMagic)
+}
+
+// OrderPayloadPolicy 오더 페이로드 압축과 최대 크기 정책
+type OrderPayloadPolicy struct {
+	compression string
+	minBytes    int // 이 크기 이상일 때만 압축
+	maxBytes    int // 전송 크기(압축 후) 상한, 0이면 제한 없음
+	oversize    string
+}
+
+// NewOrderPayloadPolicy 설정으로 오더 페이로드 정책 생성 (압축도 크기 제한도 없으면 nil)
+func NewOrderPayloadPolicy(cfg *config.Config) (*OrderPayloadPolicy, error) {
+	compression := strings.ToLower(strings.TrimSpace(cfg.OrderCompression))
+	switch compression {
+	case "", OrderCompressionOff:
+		compression = OrderCompressionOff
+	case OrderCompressionGzip:
+	default:
+		return nil, fmt.Errorf("unsupported ORDER_COMPRESSION: %s (%s, %s)", cfg.OrderCompression, OrderCompressionOff, OrderCompressionGzip)
+	}
+	oversize := strings.ToLower(strings.TrimSpace(cfg.OrderOversizePolicy))
+	switch oversize {
+	case "":
+		oversize = OrderOversizeReject
+	case OrderOversizeReject, OrderOversizeSplit:
+	default:
+		return nil, fmt.Errorf("unsupported ORDER_OVERSIZE_POLICY: %s (%s, %s)", cfg.OrderOversizePolicy, OrderOversizeReject, OrderOversizeSplit)
+	}
+	if cfg.OrderMaxPayloadBytes < 0 {
+		return nil, fmt.Errorf("ORDER_MAX_PAYLOAD_BYTES must be 0 (no limit) or positive, got %d", cfg.OrderMaxPayloadBytes)
+	}
+	if compression == OrderCompressionOff && cfg.OrderMaxPayloadBytes == 0 {
+		return nil, nil
+	}
+	return &OrderPayloadPolicy{
+		compression: compression,
+		minBytes:    cfg.OrderCompressMinBytes,
+		maxBytes:    cfg.OrderMaxPayloadBytes,
+		oversize:    oversize,
+	}, nil
+}
+
+// String 로그용 정책 요약
+func (p *OrderPayloadPolicy) String() string {
+	limit := "no limit"
+	if p.maxBytes > 0 {
+		limit = fmt.Sprintf("max %d bytes, %s", p.maxBytes, p.oversize)
+	}
+	return fmt.Sprintf("compression %s, %s", p.compression, limit)
+}
+
+// encode 압축 설정에 따라 전송할 페이로드 생성
+func (p *OrderPayloadPolicy) encode(data []byte) ([]byte, error) {
+	if p.compression != OrderCompressionGzip || len(data) < p.minBytes {
+		return data, nil
+	}
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// tooLarge 전송 크기 초과 오류 (원래 크기와 압축 후 크기 포함)
+func (p *OrderPayloadPolicy) tooLarge(raw, encoded int) error {
+	size := fmt.Sprintf("%d bytes", raw)
+	if encoded != raw {
+		size += fmt.Sprintf(" (%d bytes compressed)", encoded)
+	}
+	return apperr.New(apperr.CodePayloadTooLarge, "order payload is %s, exceeds ORDER_MAX_PAYLOAD_BYTES=%d", size, p.maxBytes)
+}
+
+// Check 크기 초과 시 거부하는 정책이면 오더를 보내기 전에 전송 크기 검사 (나누는 정책은 전송할 때 나눔)
+func (p *OrderPayloadPolicy) Check(orderPayload interface{}) error {
+	if p == nil || p.maxBytes == 0 || p.oversize != OrderOversizeReject {
+		return nil
+	}
+	data, err := json.Marshal(orderPayload)
+	if err != nil {
+		return err
+	}
+	encoded, err := p.encode(data)
+	if err != nil {
+		return err
+	}
+	if len(encoded) > p.maxBytes {
+		return p.tooLarge(len(data), len(encoded))
+	}
+	return nil
+}
+
+// Prepare 오더 JSON을 전송할 페이로드로 변환 (압축, 크기 초과 시 거부 또는 여러 오더 업데이트로 나눔)
+func (p *OrderPayloadPolicy) Prepare(topic string, data []byte) ([][]byte, error) {
+	encoded, err := p.encode(data)
+	if err != nil {
+		return nil, err
+	}
+	if p.maxBytes == 0 || len(encoded) <= p.maxBytes {
+		return [][]byte{encoded}, nil
+	}
+	if p.oversize != OrderOversizeSplit {
+		return nil, p.tooLarge(len(data), len(encoded))
+	}
+	chunks, err := p.split(topic, data)
+	if err != nil {
+		return nil, err
+	}
+	utils.Logger.Warnf("✂️ Order payload of %d bytes split into %d order updates (ORDER_MAX_PAYLOAD_BYTES=%d)",
+		len(data), len(chunks), p.maxBytes)
+	return chunks, nil
+}
+
+// orderSequence 노드/엣지의 sequenceId
+type orderSequence struct {
+	SequenceID int `json:"sequenceId"`
+}
+
+// split 오더를 최대 크기 안에 들어가는 기본 오더와 이어지는 오더 업데이트로 나눔
+// 업데이트는 직전 조각의 마지막 노드(액션 제외)로 시작해 이어 붙이며 orderUpdateId와 headerId를 하나씩 늘립니다.
+// 엣지는 양 끝 노드의 sequenceId 사이에 있는 조각에 들어갑니다.
+func (p *OrderPayloadPolicy) split(topic string, data []byte) ([][]byte, error) {
+	var order map[string]json.RawMessage
+	if err := json.Unmarshal(data, &order); err != nil {
+		return nil, fmt.Errorf("cannot split order payload: %v", err)
+	}
+	var nodes, edges []json.RawMessage
+	var updateID int
+	if err := json.Unmarshal(order["nodes"], &nodes); err != nil || len(nodes) < 2 {
+		return nil, p.tooLarge(len(data), len(data))
+	}
+	if raw, ok := order["edges"]; ok {
+		if err := json.Unmarshal(raw, &edges); err != nil {
+			return nil, fmt.Errorf("cannot split order payload: %v", err)
+		}
+	}
+	if raw, ok := order["orderUpdateId"]; ok {
+		if err := json.Unmarshal(raw, &updateID); err != nil {
+			return nil, fmt.Errorf("cannot split order payload: invalid orderUpdateId: %v", err)
+		}
+	}
+	nodeSeq := make([]int, len(nodes))
+	for i, node := range nodes {
+		var seq orderSequence
+		if err := json.Unmarshal(node, &seq); err != nil {
+			return nil, fmt.Errorf("cannot split order payload: invalid sequenceId of node %d: %v", i, err)
+		}
+		nodeSeq[i] = seq.SequenceID
+	}
+	edgeSeq := make([]int, len(edges))
+	for i, edge := range edges {
+		var seq orderSequence
+		if err := json.Unmarshal(edge, &seq); err != nil {
+			return nil, fmt.Errorf("cannot split order payload: invalid sequenceId of edge %d: %v", i, err)
+		}
+		edgeSeq[i] = seq.SequenceID
+	}
+
+	// build 노드 [start, end]와 그 사이 엣지로 조각 하나 생성 (start가 이어 붙이는 노드면 액션 제거)
+	// 크기를 재는 동안에는 headerId 순번을 쓰지 않도록 가장 긴 값으로 자리를 잡고, 확정한 조각에만 순번을 부여합니다.
+	build := func(start, end, index int, headerID int64) ([]byte, error) {
+		chunkNodes := append([]json.RawMessage(nil), nodes[start:end+1]...)
+		if index > 0 {
+			stitch, err := withoutActions(chunkNodes[0])
+			if err != nil {
+				return nil, err
+			}
+			chunkNodes[0] = stitch
+		}
+		chunkEdges := []json.RawMessage{}
+		for i, edge := range edges {
+			if edgeSeq[i] > nodeSeq[start] && edgeSeq[i] < nodeSeq[end] {
+				chunkEdges = append(chunkEdges, edge)
+			}
+		}
+		chunk := make(map[string]interface{}, len(order))
+		for key, value := range order {
+			chunk[key] = value
+		}
+		chunk["nodes"] = chunkNodes
+		chunk["edges"] = chunkEdges
+		chunk["orderUpdateId"] = updateID + index
+		if index > 0 {
+			chunk["headerId"] = headerID
+		}
+		encoded, err := json.Marshal(chunk)
+		if err != nil {
+			return nil, err
+		}
+		return p.encode(encoded)
+	}
+
+	var chunks [][]byte
+	for start := 0; start < len(nodes)-1 || len(chunks) == 0; {
+		// 최대 크기 안에 들어가는 가장 긴 조각 (이어 붙이는 노드 외에 노드가 하나 이상 있어야 함)
+		// 조각을 노드 하나씩 늘려 다시 인코딩하면 오더 크기의 제곱에 비례하므로, 두 배씩 늘려 넘치는 끝을 찾고 그 사이를 이분 탐색합니다.
+		fits := func(end int) (bool, error) {
+			encoded, err := build(start, end, len(chunks), math.MaxInt64)
+			if err != nil {
+				return false, err
+			}
+			return len(encoded) <= p.maxBytes, nil
+		}
+		bestEnd, over := start, -1
+		for step := 1; bestEnd < len(nodes)-1; step *= 2 {
+			end := min(start+step, len(nodes)-1)
+			ok, err := fits(end)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				over = end
+				break
+			}
+			bestEnd = end
+		}
+		for over >= 0 && over-bestEnd > 1 {
+			mid := (bestEnd + over) / 2
+			ok, err := fits(mid)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				bestEnd = mid
+			} else {
+				over = mid
+			}
+		}
+		if bestEnd == start {
+			single, _ := build(start, start+1, len(chunks), math.MaxInt64)
+			return nil, apperr.New(apperr.CodePayloadTooLarge,
+				"order payload is %d bytes and cannot be split under ORDER_MAX_PAYLOAD_BYTES=%d (nodes %d-%d alone need %d bytes)",
+				len(data), p.maxBytes, start, start+1, len(single))
+		}
+		var headerID int64
+		if len(chunks) > 0 {
+			headerID = utils.NextHeaderID(topic)
+		}
+		best, err := build(start, bestEnd, len(chunks), headerID)
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, best)
+		start = bestEnd
+	}
+	return chunks, nil
+}
+
+// withoutActions 노드 JSON의 액션을 비움 (오더 업데이트의 이어 붙이는 노드)
+func withoutActions(node json.RawMessage) (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(node, &fields); err != nil {
+		return nil, err
+	}
+	fields["actions"] = json.RawMessage("[]")
+	return json.Marshal(fields)
+}
+
+// orderPayloadTransport 오더 메시지에 압축과 최대 크기 정책을 적용하는 전송 경로 (변환 규칙 적용 후 가장 바깥)
+type orderPayloadTransport struct {
+	Transport
+	policy *OrderPayloadPolicy
+}
+
+// Debug 감싼 경로의 디버그 기록 (없으면 nil)
+func (t *orderPayloadTransport) Debug() *DebugCapture {
+	if d, ok := t.Transport.(interface{ Debug() *DebugCapture }); ok {
+		return d.Debug()
+	}
+	return nil
+}
+
+// Publish 오더를 조각으로 나눠 순서대로 발행
+// context에 보낸 조각 수(withChunkProgress)가 있으면 그 조각부터 이어서 보내고, 조각을 보낼 때마다 갱신합니다.
+// 같은 페이로드는 항상 같은 경계로 나뉘므로 일부 조각만 보낸 뒤 재시도해도 기본 오더를 다시 보내지 않습니다.
+func (t *orderPayloadTransport) Publish(ctx context.Context, topic string, payload []byte) error {
+	if !strings.HasSuffix(topic, "/order") {
+		return t.Transport.Publish(ctx, topic, payload)
+	}
+	chunks, err := t.policy.Prepare(topic, payload)
+	if err != nil {
+		return fmt.Errorf("%s transport: %w", t.Name(), err)
+	}
+	sent, _ := ctx.Value(chunkProgressKey{}).(*int)
+	start := 0
+	if sent != nil && *sent > 0 {
+		start = min(*sent, len(chunks))
+		utils.Logger.Infof("✂️ Resuming split order on %s via %s from chunk %d/%d", topic, t.Name(), start+1, len(chunks))
+	}
+	for i := start; i < len(chunks); i++ {
+		if err := t.Transport.Publish(ctx, topic, chunks[i]); err != nil {
+			return err
+		}
+		if sent != nil {
+			*sent = i + 1
+		}
+	}
+	return nil
+}
+
+// chunkProgressKey 나눠 보내는 오더에서 이미 발행한 조각 수를 전달하는 context 키
+type chunkProgressKey struct{}
+
+// withChunkProgress 이미 발행한 조각 수를 전송 경로에 전달 (전송 경로가 발행한 만큼 sent를 갱신)
+func withChunkProgress(ctx context.Context, sent *int) context.Context {
+	return context.WithValue(ctx, chunkProgressKey{}, sent)
+}
+
+// WithOrderPayloadPolicy 전송 경로마다 오더 페이로드 정책을 적용 (policy가 nil이면 그대로 반환)
+func WithOrderPayloadPolicy(transports []Transport, policy *OrderPayloadPolicy) []Transport {
+	if policy == nil {
+		return transports
+	}
+	wrapped := make([]Transport, 0, len(transports))
+	for _, t := range transports {
+		wrapped = append(wrapped, &orderPayloadTransport{Transport: t, policy: policy})
+	}
+	return wrapped
+}
+
+// SetOrderPayloadPolicy 오더 페이로드 압축과 최대 크기 정책 설정 (템플릿 단계 오더는 보내기 전에 크기를 검사하고, 직접 오더에도 적용)
+func (e *Executor) SetOrderPayloadPolicy(policy *OrderPayloadPolicy) {
+	e.payloadPolicy = policy
+	e.stepManager.payloadPolicy = policy
+	utils.Logger.Infof("✅ Workflow Executor: Order payload policy set (%s)", policy)
+}
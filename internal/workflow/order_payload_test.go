@@ -0,0 +1,199 @@
+// internal/workflow/order_payload_test.go
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mqtt-bridge/internal/config"
+	"strings"
+	"testing"
+)
+
+// recordingTransport 발행한 페이로드를 기록하고 failAt번째 발행(1부터)에서 실패하는 전송 경로
+type recordingTransport struct {
+	published [][]byte
+	calls     int
+	failAt    int
+}
+
+func (t *recordingTransport) Name() string    { return "recording" }
+func (t *recordingTransport) Available() bool { return true }
+
+func (t *recordingTransport) Publish(ctx context.Context, topic string, payload []byte) error {
+	t.calls++
+	if t.calls == t.failAt {
+		return errors.New("broker went away")
+	}
+	t.published = append(t.published, payload)
+	return nil
+}
+
+// largeOrder 노드마다 액션을 가진 오더 JSON
+func largeOrder(t *testing.T, nodes int) []byte {
+	t.Helper()
+	order := map[string]interface{}{"headerId": 1, "orderId": "order-split", "orderUpdateId": 0}
+	var orderNodes, orderEdges []map[string]interface{}
+	for i := 0; i < nodes; i++ {
+		orderNodes = append(orderNodes, map[string]interface{}{
+			"nodeId":     fmt.Sprintf("node-%d", i),
+			"sequenceId": i * 2,
+			"released":   true,
+			"actions":    []map[string]interface{}{{"actionId": fmt.Sprintf("action-%d", i), "actionType": "pick", "blockingType": "HARD"}},
+		})
+		if i > 0 {
+			orderEdges = append(orderEdges, map[string]interface{}{"edgeId": fmt.Sprintf("edge-%d", i), "sequenceId": i*2 - 1})
+		}
+	}
+	order["nodes"], order["edges"] = orderNodes, orderEdges
+	data, err := json.Marshal(order)
+	if err != nil {
+		t.Fatalf("marshal order: %v", err)
+	}
+	return data
+}
+
+func orderUpdateIDs(t *testing.T, payloads [][]byte) []int {
+	t.Helper()
+	ids := make([]int, 0, len(payloads))
+	for _, payload := range payloads {
+		var chunk struct {
+			OrderUpdateID int `json:"orderUpdateId"`
+		}
+		if err := json.Unmarshal(payload, &chunk); err != nil {
+			t.Fatalf("invalid chunk: %v", err)
+		}
+		ids = append(ids, chunk.OrderUpdateID)
+	}
+	return ids
+}
+
+func TestSplitOrderResumesAfterPartialFailure(t *testing.T) {
+	policy, err := NewOrderPayloadPolicy(&config.Config{OrderMaxPayloadBytes: 400, OrderOversizePolicy: OrderOversizeSplit})
+	if err != nil {
+		t.Fatalf("NewOrderPayloadPolicy: %v", err)
+	}
+	topic := "meili/v2/Roboligent/SIM-001/order"
+	payload := largeOrder(t, 8)
+	chunks, err := policy.Prepare(topic, payload)
+	if err != nil {
+		t.Fatalf("Prepare: %v", err)
+	}
+	if len(chunks) < 3 {
+		t.Fatalf("order split into %d chunk(s), want at least 3 for this test", len(chunks))
+	}
+
+	// 세 번째 조각에서 실패하면 앞의 두 조각만 보낸 것으로 기록되어야 함
+	inner := &recordingTransport{failAt: 3}
+	transport := &orderPayloadTransport{Transport: inner, policy: policy}
+	sent := 0
+	if err := transport.Publish(withChunkProgress(context.Background(), &sent), topic, payload); err == nil {
+		t.Fatal("first attempt should fail on the third chunk")
+	}
+	if sent != 2 {
+		t.Fatalf("chunks sent after failure = %d, want 2", sent)
+	}
+
+	// 재시도는 기본 오더를 다시 보내지 않고 세 번째 조각부터 이어서 보내야 함
+	if err := transport.Publish(withChunkProgress(context.Background(), &sent), topic, payload); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if sent != len(chunks) {
+		t.Fatalf("chunks sent after retry = %d, want %d", sent, len(chunks))
+	}
+	ids := orderUpdateIDs(t, inner.published)
+	want := make([]int, len(chunks))
+	for i := range want {
+		want[i] = i
+	}
+	if fmt.Sprint(ids) != fmt.Sprint(want) {
+		t.Fatalf("published orderUpdateIds = %v, want each chunk exactly once %v", ids, want)
+	}
+}
+
+func TestSplitOrderWithoutProgressSendsEveryChunk(t *testing.T) {
+	policy, err := NewOrderPayloadPolicy(&config.Config{OrderMaxPayloadBytes: 400, OrderOversizePolicy: OrderOversizeSplit})
+	if err != nil {
+		t.Fatalf("NewOrderPayloadPolicy: %v", err)
+	}
+	topic := "meili/v2/Roboligent/SIM-001/order"
+	inner := &recordingTransport{}
+	transport := &orderPayloadTransport{Transport: inner, policy: policy}
+	if err := transport.Publish(context.Background(), topic, largeOrder(t, 8)); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if len(inner.published) < 2 {
+		t.Fatalf("published %d chunk(s), want the order split", len(inner.published))
+	}
+	for i, chunk := range inner.published {
+		if len(chunk) > 400 {
+			t.Errorf("chunk %d is %d bytes, over the 400 byte limit", i, len(chunk))
+		}
+	}
+}
+
+func TestSplitLargeOrderCoversEveryNode(t *testing.T) {
+	policy, err := NewOrderPayloadPolicy(&config.Config{OrderMaxPayloadBytes: 2000, OrderOversizePolicy: OrderOversizeSplit})
+	if err != nil {
+		t.Fatalf("NewOrderPayloadPolicy: %v", err)
+	}
+	const nodes = 500
+	chunks, err := policy.Prepare("meili/v2/Roboligent/SIM-001/order", largeOrder(t, nodes))
+	if err != nil {
+		t.Fatalf("Prepare: %v", err)
+	}
+
+	// 조각마다 크기 안에 들어가고, 다음 조각은 직전 조각의 마지막 노드로 시작해야 함
+	next := 0
+	for i, payload := range chunks {
+		if len(payload) > 2000 {
+			t.Fatalf("chunk %d is %d bytes, over the 2000 byte limit", i, len(payload))
+		}
+		var chunk struct {
+			Nodes []struct {
+				SequenceID int `json:"sequenceId"`
+			} `json:"nodes"`
+			Edges []struct {
+				SequenceID int `json:"sequenceId"`
+			} `json:"edges"`
+		}
+		if err := json.Unmarshal(payload, &chunk); err != nil {
+			t.Fatalf("invalid chunk %d: %v", i, err)
+		}
+		if got := chunk.Nodes[0].SequenceID; got != next {
+			t.Fatalf("chunk %d starts at sequenceId %d, want %d", i, got, next)
+		}
+		if len(chunk.Edges) != len(chunk.Nodes)-1 {
+			t.Fatalf("chunk %d has %d nodes and %d edges", i, len(chunk.Nodes), len(chunk.Edges))
+		}
+		next = chunk.Nodes[len(chunk.Nodes)-1].SequenceID
+	}
+	if want := (nodes - 1) * 2; next != want {
+		t.Fatalf("last node sequenceId = %d, want %d", next, want)
+	}
+}
+
+func TestSplitRejectsMalformedOrder(t *testing.T) {
+	policy, err := NewOrderPayloadPolicy(&config.Config{OrderMaxPayloadBytes: 60, OrderOversizePolicy: OrderOversizeSplit})
+	if err != nil {
+		t.Fatalf("NewOrderPayloadPolicy: %v", err)
+	}
+	nodes := `[{"nodeId":"a","sequenceId":0,"actions":[]},{"nodeId":"b","sequenceId":2,"actions":[]}]`
+	tests := []struct {
+		name  string
+		order string
+	}{
+		{"orderUpdateId", `{"orderId":"o","orderUpdateId":"1","nodes":` + nodes + `,"edges":[]}`},
+		{"node sequenceId", `{"orderId":"o","nodes":[{"nodeId":"a","sequenceId":"0"},{"nodeId":"b","sequenceId":2}],"edges":[]}`},
+		{"edge sequenceId", `{"orderId":"o","nodes":` + nodes + `,"edges":[{"edgeId":"e","sequenceId":1.5}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := policy.Prepare("meili/v2/Roboligent/SIM-001/order", []byte(tt.order))
+			if err == nil || !strings.Contains(err.Error(), "cannot split order payload") {
+				t.Fatalf("Prepare = %v, want a split error for an invalid %s", err, tt.name)
+			}
+		})
+	}
+}
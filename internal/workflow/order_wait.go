@@ -0,0 +1,138 @@
+// internal/workflow/order_wait.go
+package workflow
+
+import (
+	"context"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OrderWaitResult 오더 상태 대기 결과
+type OrderWaitResult struct {
+	OrderID        string     `json:"order_id"`
+	Status         string     `json:"status"`
+	PreviousStatus string     `json:"previous_status"` // 대기를 시작할 때 기준으로 삼은 상태
+	Changed        bool       `json:"changed"`
+	TimedOut       bool       `json:"timed_out"`
+	Final          bool       `json:"final"` // 더 이상 바뀌지 않는 종료 상태
+	CurrentStep    int        `json:"current_step"`
+	LastNodeID     string     `json:"last_node_id,omitempty"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	WaitedMs       int64      `json:"waited_ms"`
+}
+
+// OrderStatusHub 오더 ID별로 실행 상태 변경을 기다리는 프로세스 내 발행/구독
+// repository.SetOrderStatusListener로 Publish를 등록하면 state 처리 중 바뀐 상태가 대기 중인 요청에 바로 전달되어,
+// SSE/WebSocket을 쓰지 못하는 클라이언트도 롱 폴링으로 오더 완료를 기다릴 수 있습니다.
+type OrderStatusHub struct {
+	db     *gorm.DB
+	siteID string
+
+	mu      sync.Mutex
+	waiters map[string]map[chan models.OrderExecution]struct{} // 오더 ID별 대기 채널
+}
+
+// NewOrderStatusHub 오더 상태 대기 허브 생성
+func NewOrderStatusHub(db *gorm.DB, siteID string) *OrderStatusHub {
+	return &OrderStatusHub{
+		db:      db,
+		siteID:  siteID,
+		waiters: make(map[string]map[chan models.OrderExecution]struct{}),
+	}
+}
+
+// Publish 바뀐 오더 실행을 그 오더를 기다리는 요청에 전달 (대기자가 없으면 무시)
+func (h *OrderStatusHub) Publish(execution models.OrderExecution) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.waiters[execution.OrderID] {
+		// 대기자는 첫 변경만 보므로 버퍼가 차 있으면 버림
+		select {
+		case ch <- execution:
+		default:
+		}
+	}
+}
+
+// Wait 오더 상태가 knownStatus와 달라지거나 ctx가 끝날 때까지 대기합니다.
+// knownStatus가 비어 있으면 현재 상태를 기준으로 삼고, 이미 다르거나 종료 상태면 바로 반환합니다.
+func (h *OrderStatusHub) Wait(ctx context.Context, orderID, knownStatus string) (*OrderWaitResult, error) {
+	started := time.Now()
+	// 현재 상태를 읽기 전에 구독하여 그 사이의 변경을 놓치지 않음
+	ch := make(chan models.OrderExecution, 1)
+	h.subscribe(orderID, ch)
+	defer h.unsubscribe(orderID, ch)
+
+	var execution models.OrderExecution
+	err := h.db.Scopes(repository.SiteScope(h.siteID)).Where("order_id = ?", orderID).First(&execution).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, apperr.New(apperr.CodeNotFound, "order %s not found", orderID).WithField("orderId")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if knownStatus == "" {
+		knownStatus = execution.Status
+	}
+	for execution.Status == knownStatus && !isFinalOrderStatus(execution.Status) {
+		select {
+		case changed := <-ch:
+			execution = changed
+		case <-ctx.Done():
+			result := newOrderWaitResult(&execution, knownStatus, started)
+			result.TimedOut = true
+			return result, nil
+		}
+	}
+	return newOrderWaitResult(&execution, knownStatus, started), nil
+}
+
+func (h *OrderStatusHub) subscribe(orderID string, ch chan models.OrderExecution) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.waiters[orderID] == nil {
+		h.waiters[orderID] = make(map[chan models.OrderExecution]struct{})
+	}
+	h.waiters[orderID][ch] = struct{}{}
+}
+
+func (h *OrderStatusHub) unsubscribe(orderID string, ch chan models.OrderExecution) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.waiters[orderID], ch)
+	if len(h.waiters[orderID]) == 0 {
+		delete(h.waiters, orderID)
+	}
+}
+
+func newOrderWaitResult(execution *models.OrderExecution, knownStatus string, started time.Time) *OrderWaitResult {
+	return &OrderWaitResult{
+		OrderID:        execution.OrderID,
+		Status:         execution.Status,
+		PreviousStatus: knownStatus,
+		Changed:        execution.Status != knownStatus,
+		Final:          isFinalOrderStatus(execution.Status),
+		CurrentStep:    execution.CurrentStep,
+		LastNodeID:     execution.LastNodeID,
+		CompletedAt:    execution.CompletedAt,
+		WaitedMs:       time.Since(started).Milliseconds(),
+	}
+}
+
+// isFinalOrderStatus 오더 실행이 끝난 상태인지
+func isFinalOrderStatus(status string) bool {
+	switch status {
+	case constants.OrderExecutionStatusCompleted, constants.OrderExecutionStatusFailed,
+		constants.OrderExecutionStatusEStopped, constants.OrderExecutionStatusCancelled,
+		constants.OrderExecutionStatusRejected, constants.OrderExecutionStatusExpired:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,261 @@
+// internal/workflow/outbox.go
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/common/readonly"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/telemetry"
+	"mqtt-bridge/internal/utils"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm"
+)
+
+// OutboxFailureHandler 최대 재시도 초과로 발행에 실패한 아웃박스 메시지 처리 콜백
+type OutboxFailureHandler func(msg *models.OutboxMessage)
+
+// OutboxSentHandler 발행에 성공한 아웃박스 메시지 처리 콜백 (즉시 발행과 재시도 모두)
+type OutboxSentHandler func(msg *models.OutboxMessage)
+
+// OutboxDispatcher 트랜잭션 아웃박스 디스패처
+// 메시지는 실행 기록과 같은 트랜잭션에서 저장되고, 커밋 후 디스패처가 발행하여 SENT로 표시합니다.
+// 발행 실패 시 PENDING 상태로 남아 백그라운드 루프에서 재시도됩니다 (at-least-once).
+type OutboxDispatcher struct {
+	db           *gorm.DB
+	transports   []Transport // 우선순위 순서 (앞의 경로가 실패하면 다음 경로로 재시도)
+	pollInterval time.Duration
+	maxAttempts  int
+	onFailed     OutboxFailureHandler
+	onSent       OutboxSentHandler
+	mu           sync.Mutex       // 즉시 발행과 백그라운드 재시도의 중복 발행 방지
+	readOnly     *readonly.Switch // 켜져 있으면 대기 중인 메시지를 발행하지 않음 (Executor.SetReadOnly)
+}
+
+// NewOutboxDispatcher 새 아웃박스 디스패처 생성
+func NewOutboxDispatcher(db *gorm.DB, mqttClient mqtt.Client, pollInterval time.Duration, maxAttempts int) *OutboxDispatcher {
+	if pollInterval <= 0 {
+		pollInterval = time.Second
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	return &OutboxDispatcher{
+		db:           db,
+		transports:   []Transport{&mqttTransport{client: mqttClient}},
+		pollInterval: pollInterval,
+		maxAttempts:  maxAttempts,
+	}
+}
+
+// SetTransports 전송 경로와 대체 순서 설정 (기본은 MQTT만 사용)
+func (d *OutboxDispatcher) SetTransports(transports []Transport) {
+	if len(transports) == 0 {
+		return
+	}
+	d.mu.Lock()
+	d.transports = transports
+	d.mu.Unlock()
+
+	names := make([]string, 0, len(transports))
+	for _, transport := range transports {
+		names = append(names, transport.Name())
+	}
+	utils.Logger.Infof("✅ Outbox transports set: %s", strings.Join(names, " → "))
+}
+
+// SetFailureHandler 발행 최종 실패 콜백 설정
+func (d *OutboxDispatcher) SetFailureHandler(handler OutboxFailureHandler) {
+	d.onFailed = handler
+}
+
+// SetSentHandler 발행 성공 콜백 설정
+func (d *OutboxDispatcher) SetSentHandler(handler OutboxSentHandler) {
+	d.onSent = handler
+}
+
+// Enqueue 주어진 트랜잭션 안에서 아웃박스 메시지를 저장합니다.
+// msg에는 토픽, 메시지 타입, 오더/추적 정보를 채워서 전달하며 페이로드와 상태는 여기서 설정합니다.
+func (d *OutboxDispatcher) Enqueue(tx *gorm.DB, msg *models.OutboxMessage, payload interface{}) (*models.OutboxMessage, error) {
+	msgData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s message: %v", msg.MessageType, err)
+	}
+
+	msg.Payload = string(msgData)
+	msg.Status = constants.OutboxStatusPending
+	if err := tx.Create(msg).Error; err != nil {
+		return nil, fmt.Errorf("failed to persist outbox message: %v", err)
+	}
+	return msg, nil
+}
+
+// Dispatch 아웃박스 메시지를 발행하고 결과를 기록합니다.
+func (d *OutboxDispatcher) Dispatch(ctx context.Context, msg *models.OutboxMessage) error {
+	sent, err := d.dispatchLocked(ctx, msg)
+
+	// 콜백은 후속 오더 발행으로 이어질 수 있으므로 잠금 해제 후 호출
+	if err != nil && msg.Status == constants.OutboxStatusFailed && d.onFailed != nil {
+		d.onFailed(msg)
+	}
+	if sent && d.onSent != nil {
+		d.onSent(msg)
+	}
+	return err
+}
+
+// dispatchLocked 잠금 상태에서 발행 및 상태 기록 (이번 호출에서 발행했으면 sent가 true)
+func (d *OutboxDispatcher) dispatchLocked(ctx context.Context, msg *models.OutboxMessage) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// 다른 경로에서 이미 처리되었는지 확인
+	var current models.OutboxMessage
+	if err := d.db.First(&current, msg.ID).Error; err != nil {
+		return false, fmt.Errorf("failed to load outbox message %d: %v", msg.ID, err)
+	}
+	if current.Status != constants.OutboxStatusPending {
+		*msg = current
+		return false, nil
+	}
+
+	current.Attempts++
+	transport, err := d.publish(ctx, &current)
+	if err == nil {
+		now := time.Now()
+		current.Status = constants.OutboxStatusSent
+		current.SentAt = &now
+		current.LastError = ""
+		current.Transport = transport
+		if err := d.db.Save(&current).Error; err != nil {
+			// 발행은 이미 끝났으므로 PENDING으로 남은 메시지는 재발행될 수 있음 (at-least-once)
+			utils.Logger.Errorf("❌ Outbox message %d sent but status not recorded (may be re-sent): %v", current.ID, err)
+		}
+
+		if current.StepExecutionID != nil {
+			d.db.Model(&models.StepExecution{}).
+				Where("id = ?", *current.StepExecutionID).
+				Update("sent_to_robot", true)
+		}
+		if current.OrderID != "" {
+			d.db.Model(&models.OrderExecution{}).
+				Where("order_id = ?", current.OrderID).
+				Update("transport", transport)
+		}
+		utils.Logger.Infof("📤 Outbox message %d (%s) sent for order %s via %s (headerId=%d, cid=%s)",
+			current.ID, current.MessageType, current.OrderID, transport, current.HeaderID, current.CorrelationID)
+		*msg = current
+		return true, nil
+	}
+
+	current.LastError = err.Error()
+	if len(current.LastError) > 500 {
+		current.LastError = current.LastError[:500] // 여러 전송 경로의 오류가 합쳐지면 컬럼 크기를 넘을 수 있음
+	}
+	if current.Attempts >= d.maxAttempts {
+		current.Status = constants.OutboxStatusFailed
+	}
+	if saveErr := d.db.Save(&current).Error; saveErr != nil {
+		utils.Logger.Errorf("❌ Failed to record outbox message %d dispatch failure: %v", current.ID, saveErr)
+	}
+	*msg = current
+
+	utils.Logger.Errorf("❌ Outbox message %d (%s) dispatch failed (attempt %d/%d): %v",
+		current.ID, current.MessageType, current.Attempts, d.maxAttempts, err)
+	return false, err
+}
+
+// Start 대기 중인 아웃박스 메시지를 주기적으로 재발행하는 루프 시작
+func (d *OutboxDispatcher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(d.pollInterval)
+		defer ticker.Stop()
+
+		utils.Logger.Infof("📮 Outbox dispatcher started (interval: %v, max attempts: %d)", d.pollInterval, d.maxAttempts)
+		for {
+			select {
+			case <-ctx.Done():
+				utils.Logger.Info("Outbox dispatcher stopped")
+				return
+			case <-ticker.C:
+				d.dispatchPending(ctx)
+			}
+		}
+	}()
+}
+
+// dispatchPending 대기 중인 메시지를 생성 순서대로 발행
+func (d *OutboxDispatcher) dispatchPending(ctx context.Context) {
+	if d.readOnly.Enabled() || !d.anyTransportAvailable() {
+		return
+	}
+
+	var pending []models.OutboxMessage
+	if err := d.db.Where("status = ?", constants.OutboxStatusPending).
+		Order("id ASC").
+		Limit(100).
+		Find(&pending).Error; err != nil {
+		utils.Logger.Errorf("❌ Failed to load pending outbox messages: %v", err)
+		return
+	}
+
+	for i := range pending {
+		if err := d.Dispatch(ctx, &pending[i]); err != nil {
+			// 순서 보장을 위해 실패 시 이번 주기는 중단
+			return
+		}
+	}
+}
+
+// anyTransportAvailable 발행을 시도할 수 있는 전송 경로가 있는지 확인
+func (d *OutboxDispatcher) anyTransportAvailable() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, transport := range d.transports {
+		if transport.Available() {
+			return true
+		}
+	}
+	return false
+}
+
+// publish 전송 경로를 우선순위대로 시도하고 성공한 경로 이름을 반환 (모두 실패하면 각 경로의 오류를 합쳐 반환)
+func (d *OutboxDispatcher) publish(ctx context.Context, msg *models.OutboxMessage) (transport string, err error) {
+	ctx, span := telemetry.StartPublishSpan(ctx, msg.Topic, msg.MessageType)
+	span.SetAttributes(
+		attribute.String("order.id", msg.OrderID),
+		attribute.String("correlation.id", msg.CorrelationID),
+		attribute.Int64("vda5050.header_id", msg.HeaderID),
+		attribute.Int("outbox.attempt", msg.Attempts),
+	)
+	defer func() {
+		span.SetAttributes(attribute.String("outbox.transport", transport))
+		telemetry.EndSpan(span, err)
+	}()
+
+	// 나눠 보내는 오더는 앞선 시도(다른 전송 경로 포함)에서 보낸 조각 다음부터 발행
+	chunksSent := msg.ChunksSent
+	ctx = withChunkProgress(ctx, &chunksSent)
+	defer func() { msg.ChunksSent = chunksSent }()
+
+	var failures []string
+	for i, t := range d.transports {
+		publishErr := t.Publish(ctx, msg.Topic, []byte(msg.Payload))
+		if publishErr == nil {
+			if i > 0 {
+				utils.Logger.Warnf("🔁 Outbox message %d delivered via fallback transport %s after: %s",
+					msg.ID, t.Name(), strings.Join(failures, "; "))
+			}
+			return t.Name(), nil
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", t.Name(), publishErr))
+	}
+	return "", fmt.Errorf("%s", strings.Join(failures, "; "))
+}
@@ -0,0 +1,138 @@
+// internal/workflow/pause.go
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/robot"
+	"mqtt-bridge/internal/utils"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm"
+)
+
+// PauseOrder 실행 중인 오더 일시정지
+// 로봇에 startPause instantAction을 보낸 뒤 오더를 PAUSED로 바꾸고 수신 확인 제한 시간을 멈춥니다.
+// 오더와 단계 제한 시각은 PAUSED 동안 확인하지 않습니다 (DeadlineMonitor).
+// 일시정지 중에는 단계 완료를 처리하지 않으며(재개 후 상태 메시지로 처리), PLC 명령의 오더면 PLC에 P 상태를 보냅니다.
+func (e *Executor) PauseOrder(orderID, reason string) (*models.OrderExecution, error) {
+	orderExec, err := e.findRobotOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if orderExec.Status != constants.OrderExecutionStatusRunning {
+		return nil, apperr.New(apperr.CodeValidationFailed, "order %s is %s, only %s orders can be paused",
+			orderID, orderExec.Status, constants.OrderExecutionStatusRunning).WithField("orderId")
+	}
+	if err := e.sendPauseAction(constants.ActionTypeStartPause); err != nil {
+		return nil, err
+	}
+
+	if reason == "" {
+		reason = "paused by operator"
+	}
+	utils.Logger.Warnf("⏸️ Order %s paused: %s (cid=%s)", orderID, reason, orderExec.CorrelationID)
+	now := time.Now()
+	orderExec.PausedAt = &now
+	repository.UpdateOrderExecutionStatus(e.db, orderExec, constants.OrderExecutionStatusPaused, nil)
+	e.suspendAcknowledgment(orderID)
+	e.orderTracer.AddEvent(orderID, "order.paused", attribute.String("reason", reason))
+	e.notifyPLCOfPause(orderExec, true, reason)
+	return orderExec, nil
+}
+
+// ResumePausedOrder 일시정지한 오더 재개
+// 로봇에 stopPause instantAction을 보낸 뒤 오더를 RUNNING으로 되돌리고 수신 확인 제한 시간을 다시 시작합니다.
+// 오더와 실행 중인 단계의 제한 시각은 일시정지했던 시간만큼 늦춥니다.
+// PLC 명령의 오더면 PLC에 진행률(R) 응답을 보냅니다.
+func (e *Executor) ResumePausedOrder(orderID string) (*models.OrderExecution, error) {
+	orderExec, err := e.findRobotOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if orderExec.Status != constants.OrderExecutionStatusPaused {
+		return nil, apperr.New(apperr.CodeValidationFailed, "order %s is %s, not %s",
+			orderID, orderExec.Status, constants.OrderExecutionStatusPaused).WithField("orderId")
+	}
+	if err := e.sendPauseAction(constants.ActionTypeStopPause); err != nil {
+		return nil, err
+	}
+
+	utils.Logger.Infof("▶️ Order %s resumed (cid=%s)", orderID, orderExec.CorrelationID)
+	now := time.Now()
+	e.resumeStepTimeouts(orderExec, now)
+	extendDeadlineForPause(orderExec, now)
+	repository.UpdateOrderExecutionStatus(e.db, orderExec, constants.OrderExecutionStatusRunning, nil)
+	e.resumeAcknowledgment(orderID)
+	e.orderTracer.AddEvent(orderID, "order.resumed")
+	e.notifyPLCOfPause(orderExec, false, "")
+	return orderExec, nil
+}
+
+// findRobotOrder 이 브릿지 로봇의 오더 실행 조회
+func (e *Executor) findRobotOrder(orderID string) (*models.OrderExecution, error) {
+	var orderExec models.OrderExecution
+	err := e.db.Scopes(repository.SiteScope(e.config.SiteID)).Where("order_id = ?", orderID).First(&orderExec).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, apperr.New(apperr.CodeNotFound, "order %s not found", orderID).WithField("orderId")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if orderExec.SerialNumber != "" && orderExec.SerialNumber != e.config.RobotSerialNumber {
+		return nil, apperr.New(apperr.CodeNotFound, "order %s runs on robot %s, which is not managed by this bridge",
+			orderID, orderExec.SerialNumber).WithField("orderId")
+	}
+	return &orderExec, nil
+}
+
+// sendPauseAction 로봇에 startPause/stopPause instantAction 전송 (로봇 전체에 적용됨)
+func (e *Executor) sendPauseAction(actionType string) error {
+	if err := e.checkFeature(robot.FeaturePause); err != nil {
+		return err
+	}
+	reqData, err := json.Marshal(e.orderBuilder.BuildPauseMessage(actionType))
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s request: %v", actionType, err)
+	}
+	if !e.mqttClient.IsConnected() {
+		return apperr.New(apperr.CodeTransportUnavailable, "MQTT broker is not connected")
+	}
+	topic := constants.GetMeiliInstantActionsTopic(e.config.RobotManufacturer, e.config.RobotSerialNumber)
+	options := e.config.RobotPublishOptions(topic)
+	token := e.mqttClient.Publish(topic, options.QoS, options.Retained, reqData)
+	token.Wait()
+	if token.Error() != nil {
+		return apperr.Wrap(apperr.CodeTransportUnavailable, token.Error(), "Failed to send %s", actionType)
+	}
+	return nil
+}
+
+// releasePause 일시정지된 오더를 취소한 뒤 로봇이 다음 오더를 실행할 수 있도록 stopPause 전송
+func (e *Executor) releasePause(orderID string) {
+	if err := e.sendPauseAction(constants.ActionTypeStopPause); err != nil {
+		utils.Logger.Errorf("❌ Failed to send stopPause after cancelling paused order %s: %v", orderID, err)
+	}
+}
+
+// notifyPLCOfPause 오더를 실행 중인 PLC 명령에 일시정지(P) 또는 재개(진행률 R) 응답 전송
+func (e *Executor) notifyPLCOfPause(orderExec *models.OrderExecution, paused bool, reason string) {
+	var cmdExec models.CommandExecution
+	if err := e.db.Preload("Command.CommandDefinition").First(&cmdExec, orderExec.CommandExecutionID).Error; err != nil {
+		utils.Logger.Errorf("❌ Command execution %d of order %s not found: %v", orderExec.CommandExecutionID, orderExec.OrderID, err)
+		return
+	}
+	if cmdExec.Status != constants.CommandExecutionStatusRunning {
+		return
+	}
+	if paused {
+		e.sendResponseToPLC(cmdExec.Command.CorrelationID, cmdExec.Command.CommandDefinition.CommandType, constants.StatusPaused, reason)
+		return
+	}
+	e.sendProgressToPLC(&cmdExec)
+}
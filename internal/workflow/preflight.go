@@ -0,0 +1,170 @@
+// internal/workflow/preflight.go
+package workflow
+
+import (
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/utils"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PreflightResult 사전 점검 하나의 결과
+type PreflightResult struct {
+	Check    string `json:"check"`
+	Passed   bool   `json:"passed"`
+	Disabled bool   `json:"disabled,omitempty"` // 로봇별 설정으로 끈 점검 (통과로 봄)
+	Detail   string `json:"detail,omitempty"`
+}
+
+// PreflightReport 오더 전송 전 사전 점검 결과
+type PreflightReport struct {
+	SerialNumber string            `json:"serial_number"`
+	OrderID      string            `json:"order_id,omitempty"`
+	Passed       bool              `json:"passed"`
+	CheckedAt    time.Time         `json:"checked_at"`
+	Results      []PreflightResult `json:"results"`
+}
+
+// Err 실패한 점검을 모은 오류 (모두 통과했으면 nil)
+func (r *PreflightReport) Err() error {
+	if r.Passed {
+		return nil
+	}
+	var failed []string
+	for _, result := range r.Results {
+		if !result.Passed {
+			failed = append(failed, result.Check+": "+result.Detail)
+		}
+	}
+	return apperr.New(apperr.CodePreflightFailed, "preflight checks failed for robot %s: %s",
+		r.SerialNumber, strings.Join(failed, "; "))
+}
+
+// Preflight 오더를 보내기 전에 로봇의 최신 state로 사전 점검을 수행합니다.
+// 로봇별로 끈 점검(repository.SetDisabledPreflightChecks)은 건너뛰며, 로봇별 마지막 결과를 보관합니다.
+type Preflight struct {
+	db     *gorm.DB
+	siteID string
+	checks []string
+	state  func(serialNumber string) *models.RobotStateMessage
+
+	mu   sync.Mutex
+	last map[string]*PreflightReport
+}
+
+// NewPreflight PREFLIGHT_CHECKS로 사전 점검기 생성 (점검이 없으면 nil)
+func NewPreflight(db *gorm.DB, siteID, spec string) (*Preflight, error) {
+	checks, err := repository.ParsePreflightChecks(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PREFLIGHT_CHECKS: %w", err)
+	}
+	if len(checks) == 0 {
+		return nil, nil
+	}
+	return &Preflight{db: db, siteID: siteID, checks: checks, last: make(map[string]*PreflightReport)}, nil
+}
+
+// Checks 켜진 점검 이름
+func (p *Preflight) Checks() []string {
+	return p.checks
+}
+
+// Run 로봇의 최신 state로 점검을 수행하고 결과를 보관
+func (p *Preflight) Run(serialNumber, orderID string) *PreflightReport {
+	report := &PreflightReport{SerialNumber: serialNumber, OrderID: orderID, Passed: true, CheckedAt: time.Now()}
+
+	disabled, err := repository.DisabledPreflightChecks(p.db, p.siteID, serialNumber)
+	if err != nil {
+		utils.Logger.Warnf("⚠️ Failed to load preflight overrides of robot %s: %v", serialNumber, err)
+	}
+	var state *models.RobotStateMessage
+	if p.state != nil {
+		state = p.state(serialNumber)
+	}
+	for _, check := range p.checks {
+		result := PreflightResult{Check: check, Passed: true}
+		switch {
+		case disabled[check]:
+			result.Disabled = true
+		case state == nil:
+			result.Passed, result.Detail = false, "no state message received from robot"
+		default:
+			result.Passed, result.Detail = evaluatePreflight(check, state)
+		}
+		if !result.Passed {
+			report.Passed = false
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	p.mu.Lock()
+	p.last[serialNumber] = report
+	p.mu.Unlock()
+	return report
+}
+
+// Last 로봇의 마지막 점검 결과 (점검한 적이 없으면 nil)
+func (p *Preflight) Last(serialNumber string) *PreflightReport {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.last[serialNumber]
+}
+
+// SetPreflight 오더 전송 전 사전 점검 설정 (템플릿 오더 단계, 직접 액션, 양팔 궤적 오더에 적용)
+func (e *Executor) SetPreflight(preflight *Preflight) {
+	preflight.state = e.stepManager.latestState
+	e.preflight = preflight
+	e.stepManager.preflight = preflight
+	utils.Logger.Infof("✅ Workflow Executor: Preflight checks enabled (%s)", strings.Join(preflight.Checks(), ", "))
+}
+
+// checkPreflight 사전 점검을 수행하고 실패하면 보고서를 담은 오류 반환 (점검기가 없으면 nil)
+func (e *Executor) checkPreflight(orderID string) error {
+	if e.preflight == nil {
+		return nil
+	}
+	report := e.preflight.Run(e.config.RobotSerialNumber, orderID)
+	if err := report.Err(); err != nil {
+		utils.Logger.Warnf("✈️ Order %s not sent: %v", orderID, err)
+		return err
+	}
+	return nil
+}
+
+// evaluatePreflight 점검 하나 평가 (통과 여부와 실패 사유)
+func evaluatePreflight(check string, state *models.RobotStateMessage) (bool, string) {
+	switch check {
+	case constants.PreflightCheckNoErrors:
+		if len(state.Errors) == 0 {
+			return true, ""
+		}
+		errors := make([]string, 0, len(state.Errors))
+		for _, e := range state.Errors {
+			errors = append(errors, fmt.Sprintf("%s (%s)", e.ErrorType, e.ErrorLevel))
+		}
+		return false, "robot reports errors: " + strings.Join(errors, ", ")
+	case constants.PreflightCheckPositionInitialized:
+		if state.AgvPosition.PositionInitialized {
+			return true, ""
+		}
+		return false, "position is not initialized"
+	case constants.PreflightCheckAutomaticMode:
+		if state.OperatingMode == constants.OperatingModeAutomatic {
+			return true, ""
+		}
+		return false, fmt.Sprintf("operating mode is %q, not %s", state.OperatingMode, constants.OperatingModeAutomatic)
+	case constants.PreflightCheckEStopReleased:
+		if state.SafetyState.EStop == constants.EStopNone {
+			return true, ""
+		}
+		return false, fmt.Sprintf("e-stop is %q", state.SafetyState.EStop)
+	}
+	return true, ""
+}
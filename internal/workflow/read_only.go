@@ -0,0 +1,28 @@
+// internal/workflow/read_only.go
+package workflow
+
+import (
+	"mqtt-bridge/internal/common/readonly"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/utils"
+)
+
+// SetReadOnly 읽기 전용 스위치 설정
+// 켜져 있는 동안 새 워크플로우와 직접 오더를 거부하고, 실행 중인 오더의 다음 단계와 대기 중인 아웃박스 메시지는
+// 보내지 않고 보류했다가 해제되면 이어서 보냅니다. 비상 정지와 취소는 영향을 받지 않습니다.
+func (e *Executor) SetReadOnly(sw *readonly.Switch) {
+	e.readOnly = sw
+	e.outbox.readOnly = sw
+}
+
+// holdForReadOnly 읽기 전용이면 단계를 보내지 않고 해제될 때까지 보류 (보류했으면 true)
+func (s *StepManager) holdForReadOnly(execution *models.OrderExecution) bool {
+	if !s.executor.readOnly.Enabled() {
+		return false
+	}
+	if s.executor.scheduleStepRecheck(execution.ID) {
+		utils.Logger.Warnf("🔒 Order %s step %d held while the bridge is read-only (cid=%s)",
+			execution.OrderID, execution.CurrentStep, execution.CorrelationID)
+	}
+	return true
+}
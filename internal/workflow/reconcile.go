@@ -0,0 +1,356 @@
+// internal/workflow/reconcile.go
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/common/redis"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/robot"
+	"mqtt-bridge/internal/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// reconcilePollInterval 재시작 복구 중 새 상태 메시지 수신 여부를 확인하는 간격
+const reconcilePollInterval = 500 * time.Millisecond
+
+// runningStepsByOrder 이 로봇의 실행 중인 단계를 오더 실행별로 조회 (오더 실행 ID 순, 단계 순서 순)
+func (e *Executor) runningStepsByOrder() ([]uint, map[uint][]models.StepExecution, error) {
+	var steps []models.StepExecution
+	err := e.db.Joins("JOIN order_executions ON step_executions.execution_id = order_executions.id").
+		Where("order_executions.site_id = ? AND order_executions.serial_number = ? AND order_executions.status = ? AND step_executions.status = ?",
+			e.config.SiteID, e.config.RobotSerialNumber,
+			constants.OrderExecutionStatusRunning, constants.StepExecutionStatusRunning).
+		Preload("Execution").
+		Order("step_executions.execution_id ASC, step_executions.step_order ASC").
+		Find(&steps).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var orderIDs []uint
+	byOrder := make(map[uint][]models.StepExecution)
+	for _, step := range steps {
+		if _, ok := byOrder[step.ExecutionID]; !ok {
+			orderIDs = append(orderIDs, step.ExecutionID)
+		}
+		byOrder[step.ExecutionID] = append(byOrder[step.ExecutionID], step)
+	}
+	return orderIDs, byOrder, nil
+}
+
+// RebuildStepActions 재시작 전에 실행 중이던 단계의 Redis 액션 상태 맵을 아웃박스에 저장된 오더 메시지로 복구합니다.
+// 상태 메시지가 빈 맵으로 처리되지 않도록 토픽 구독 전에 호출해야 하며, 이미 있는 맵은 그대로 둡니다.
+// 오더 메시지를 찾을 수 없어 액션을 알 수 없는 단계는 영원히 끝나지 않으므로 실패 처리합니다.
+func (e *Executor) RebuildStepActions(ctx context.Context) {
+	orderIDs, byOrder, err := e.runningStepsByOrder()
+	if err != nil {
+		utils.Logger.Errorf("❌ Failed to load running steps for reconciliation: %v", err)
+		return
+	}
+	if len(orderIDs) == 0 {
+		return
+	}
+
+	rebuilt := 0
+	for _, orderID := range orderIDs {
+		steps := byOrder[orderID]
+		for i := range steps {
+			step := &steps[i]
+			exists, err := e.redisClient.Exists(ctx, redis.StepActions(int(step.ID))).Result()
+			if err != nil {
+				utils.Logger.Errorf("❌ Failed to check action map of step %d: %v", step.ID, err)
+				continue
+			}
+			if exists > 0 {
+				continue
+			}
+
+			nodes, err := e.sentStepNodes(step)
+			if err != nil {
+				utils.Logger.Errorf("♻️ Cannot rebuild action map of step %d (order %s): %v",
+					step.StepOrder, step.Execution.OrderID, err)
+				e.stepManager.handleStepFailure(step, &step.Execution, fmt.Sprintf("reconcile: action map lost after restart: %v", err))
+				break // 같은 오더의 다른 단계는 handleStepFailure에서 함께 정리됨
+			}
+			if err := e.restoreActionMap(ctx, step, nodes); err != nil {
+				utils.Logger.Errorf("❌ Failed to rebuild action map of step %d: %v", step.ID, err)
+				continue
+			}
+			rebuilt++
+		}
+	}
+	utils.Logger.Infof("♻️ Reconciliation found %d running order(s), rebuilt %d action map(s)", len(orderIDs), rebuilt)
+}
+
+// sentStepNodes 단계가 속한 오더 메시지에서 그 단계의 노드 찾기
+// 병렬 그룹은 오더 메시지 하나에 단계마다 노드 하나가 순서대로 들어 있고, 아웃박스 메시지는 그룹의 첫 단계에 연결됩니다.
+func (e *Executor) sentStepNodes(step *models.StepExecution) ([]models.OrderNode, error) {
+	var msg models.OutboxMessage
+	err := e.db.Where("step_execution_id IN (?)",
+		e.db.Model(&models.StepExecution{}).Select("id").
+			Where("execution_id = ? AND id <= ?", step.ExecutionID, step.ID)).
+		Where("message_type = ?", "order").
+		Order("step_execution_id DESC").
+		First(&msg).Error
+	if err != nil {
+		return nil, fmt.Errorf("order message not found: %v", err)
+	}
+
+	var orderMsg models.OrderMessage
+	if err := json.Unmarshal([]byte(msg.Payload), &orderMsg); err != nil {
+		return nil, fmt.Errorf("invalid order message %d: %v", msg.ID, err)
+	}
+
+	// 같은 트랜잭션에서 생성된 그룹 단계 중 이 단계의 위치
+	var groupIDs []uint
+	e.db.Model(&models.StepExecution{}).
+		Where("execution_id = ? AND id >= ?", step.ExecutionID, *msg.StepExecutionID).
+		Order("id ASC").
+		Limit(len(orderMsg.Nodes)).
+		Pluck("id", &groupIDs)
+	for i, id := range groupIDs {
+		if id == step.ID {
+			return orderMsg.Nodes[i : i+1], nil
+		}
+	}
+	return nil, fmt.Errorf("step is not part of order message %d", msg.ID)
+}
+
+// restoreActionMap 노드의 액션을 WAITING 상태로 Redis에 다시 기록
+func (e *Executor) restoreActionMap(ctx context.Context, step *models.StepExecution, nodes []models.OrderNode) error {
+	redisKey := redis.StepActions(int(step.ID))
+	pipe := e.redisClient.Pipeline()
+	count := 0
+	for _, node := range nodes {
+		for _, action := range node.Actions {
+			pipe.HSet(ctx, redisKey, action.ActionID, constants.ActionStatusWaiting)
+			count++
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	if e.stepManager.actionMapTTL > 0 {
+		pipe.Expire(ctx, redisKey, e.stepManager.actionMapTTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+	utils.Logger.Infof("♻️ Rebuilt %d action(s) for step %d of order %s", count, step.StepOrder, step.Execution.OrderID)
+	return nil
+}
+
+// reconcileRunningSteps 로봇에 stateRequest를 보내고 받은 상태로 실행 중인 단계를 확정합니다.
+// 로봇이 같은 오더와 단계의 액션을 보고하면 이후 상태 메시지로 평소처럼 진행하고,
+// 다른 오더를 보고하거나 단계의 액션이 없거나 제한 시간 안에 상태가 오지 않으면 단계를 실패 처리합니다.
+// 아직 로봇에 전송되지 않은 단계는 아웃박스 재시도가 처리하므로 건드리지 않습니다.
+func (e *Executor) reconcileRunningSteps(ctx context.Context) {
+	timeout := e.config.ReconcileStateTimeout
+	if timeout <= 0 {
+		return
+	}
+	orderIDs, byOrder, err := e.runningStepsByOrder()
+	if err != nil {
+		utils.Logger.Errorf("❌ Failed to load running steps for reconciliation: %v", err)
+		return
+	}
+	if !anySentToRobot(byOrder) {
+		return
+	}
+
+	baseline := e.stepManager.latestState(e.config.RobotSerialNumber)
+	if err := e.sendStateRequest(); err != nil {
+		utils.Logger.Warnf("⚠️ stateRequest not sent, waiting for the next periodic state: %v", err)
+	}
+
+	state := baseline
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(reconcilePollInterval)
+	defer ticker.Stop()
+wait:
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline.C:
+			break wait
+		case <-ticker.C:
+			if state = e.stepManager.latestState(e.config.RobotSerialNumber); state != baseline {
+				break wait
+			}
+		}
+	}
+	if state == baseline {
+		state = nil
+	}
+
+	for _, orderID := range orderIDs {
+		if reason := e.reconcileOrderSteps(ctx, byOrder[orderID], state, timeout); reason != "" {
+			e.failReconciledOrder(orderID, reason)
+		}
+	}
+}
+
+// reconcileOrderSteps 오더의 실행 중인 단계가 로봇 상태와 맞지 않으면 실패 사유 반환 (맞으면 빈 값)
+func (e *Executor) reconcileOrderSteps(ctx context.Context, steps []models.StepExecution, state *models.RobotStateMessage, timeout time.Duration) string {
+	orderID := steps[0].Execution.OrderID
+	sent := false
+	for _, step := range steps {
+		sent = sent || step.SentToRobot
+	}
+	if !sent {
+		return ""
+	}
+	if state == nil {
+		return fmt.Sprintf("reconcile: no state from robot within %s after restart", timeout)
+	}
+	if state.OrderID != orderID {
+		return fmt.Sprintf("reconcile: robot reports order %q instead of %s after restart", state.OrderID, orderID)
+	}
+
+	reported := make(map[string]bool, len(state.ActionStates))
+	for _, actionState := range state.ActionStates {
+		reported[actionState.ActionID] = true
+	}
+	for _, step := range steps {
+		if !step.SentToRobot {
+			continue
+		}
+		actions, err := e.redisClient.HKeys(ctx, redis.StepActions(int(step.ID))).Result()
+		if err != nil || len(actions) == 0 {
+			continue // 이미 완료되어 정리되었거나 확인할 액션이 없음
+		}
+		known := false
+		for _, actionID := range actions {
+			known = known || reported[actionID]
+		}
+		if !known {
+			return fmt.Sprintf("reconcile: robot has no actions of step %d of order %s after restart", step.StepOrder, orderID)
+		}
+	}
+	utils.Logger.Infof("♻️ Order %s confirmed by robot state, resuming", orderID)
+	return ""
+}
+
+// failReconciledOrder 확인 중에 다른 경로로 끝나지 않았으면 오더의 실행 중인 단계를 실패 처리
+func (e *Executor) failReconciledOrder(orderExecutionID uint, reason string) {
+	var step models.StepExecution
+	err := e.db.Where("execution_id = ? AND status = ?", orderExecutionID, constants.StepExecutionStatusRunning).
+		Preload("Execution").
+		Order("step_order ASC").
+		First(&step).Error
+	if err != nil || step.Execution.Status != constants.OrderExecutionStatusRunning {
+		return
+	}
+	utils.Logger.Warnf("♻️ Failing step %d of order %s: %s", step.StepOrder, step.Execution.OrderID, reason)
+	e.stepManager.handleStepFailure(&step, &step.Execution, reason)
+}
+
+// sendStateRequest 로봇에 stateRequest instantAction 전송
+func (e *Executor) sendStateRequest() error {
+	if err := e.checkFeature(robot.FeatureStateRequest); err != nil {
+		return err
+	}
+	reqData, err := json.Marshal(e.orderBuilder.BuildStateRequestMessage())
+	if err != nil {
+		return fmt.Errorf("failed to marshal stateRequest: %v", err)
+	}
+	topic := constants.GetMeiliInstantActionsTopic(e.config.RobotManufacturer, e.config.RobotSerialNumber)
+	options := e.config.RobotPublishOptions(topic)
+	token := e.mqttClient.Publish(topic, options.QoS, options.Retained, reqData)
+	token.Wait()
+	if token.Error() != nil {
+		return fmt.Errorf("failed to send stateRequest: %v", token.Error())
+	}
+	utils.Logger.Infof("📤 stateRequest sent to robot %s for reconciliation", e.config.RobotSerialNumber)
+	return nil
+}
+
+// anySentToRobot 로봇에 전송된 실행 중인 단계가 있는지 확인
+func anySentToRobot(byOrder map[uint][]models.StepExecution) bool {
+	for _, steps := range byOrder {
+		for _, step := range steps {
+			if step.SentToRobot {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ResumeCommandExecutions 재시작 전에 실행 중이던 이 로봇의 명령을 저장된 진행 위치에서 이어서 실행합니다.
+// 실행 중인 단계가 있는 오더는 상태 메시지로 진행되므로 그대로 두고, 단계 사이에서 멈춘 오더는 다음 단계를,
+// 오더 사이에서 멈춘 명령은 마지막 오더 결과에 따른 다음 오더를 배차합니다.
+func (e *Executor) ResumeCommandExecutions() int {
+	var cmdExecs []models.CommandExecution
+	err := e.db.Where("status = ?", constants.CommandExecutionStatusRunning).
+		Where("(site_id = ? AND serial_number = ?) OR (serial_number = '' AND id IN (?))",
+			e.config.SiteID, e.config.RobotSerialNumber,
+			e.db.Model(&models.OrderExecution{}).Select("command_execution_id").
+				Where("site_id = ? AND serial_number = ?", e.config.SiteID, e.config.RobotSerialNumber)).
+		Preload("Command.CommandDefinition").
+		Order("id ASC").
+		Find(&cmdExecs).Error
+	if err != nil {
+		utils.Logger.Errorf("❌ Failed to load running command executions to resume: %v", err)
+		return 0
+	}
+
+	resumed := 0
+	for i := range cmdExecs {
+		if e.resumeCommandExecution(&cmdExecs[i]) {
+			resumed++
+		}
+	}
+	if resumed > 0 {
+		utils.Logger.Infof("♻️ Resumed %d of %d running command execution(s) after restart", resumed, len(cmdExecs))
+	}
+	return resumed
+}
+
+// resumeCommandExecution 명령의 마지막 오더 실행 상태에 따라 멈춘 지점부터 재개 (재개할 것이 없으면 false)
+func (e *Executor) resumeCommandExecution(cmdExec *models.CommandExecution) bool {
+	var latest models.OrderExecution
+	err := e.db.Where("command_execution_id = ?", cmdExec.ID).Order("id DESC").First(&latest).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		utils.Logger.Errorf("❌ Failed to load orders of command execution %d: %v", cmdExec.ID, err)
+		return false
+	}
+
+	commandType := cmdExec.Command.CommandDefinition.CommandType
+	switch {
+	case err == gorm.ErrRecordNotFound || latest.ID == cmdExec.LastOrderID:
+		// 첫 오더 전이거나 다음 순번까지 정한 뒤 멈춤 (실행 제한 시간대 대기 포함)
+		utils.Logger.Infof("♻️ Resuming command %s (execution %d) at order index %d",
+			commandType, cmdExec.ID, cmdExec.CurrentOrderIndex)
+		if err := e.executeNextOrder(cmdExec); err != nil {
+			utils.Logger.Errorf("❌ Failed to resume command execution %d: %v", cmdExec.ID, err)
+		}
+		return true
+	case latest.Status == constants.OrderExecutionStatusWaiting:
+		// 템플릿 동시 실행 제한으로 대기 중이던 오더는 대기를 이어감
+		utils.Logger.Infof("🚦 Order %s keeps waiting for template %d (position %d)",
+			latest.OrderID, latest.TemplateID, latest.QueuePosition)
+		e.scheduleWaitingOrder(latest.ID, 0)
+		return true
+	case latest.Status == constants.OrderExecutionStatusPaused:
+		// 일시정지한 오더는 운영자가 재개할 때까지 그대로 둠 (단계 완료는 재개 후 상태 메시지로 처리)
+		utils.Logger.Infof("⏸️ Order %s stays paused until it is resumed", latest.OrderID)
+		return true
+	case latest.Status == constants.OrderExecutionStatusRunning ||
+		latest.Status == constants.OrderExecutionStatusPending:
+		return e.stepManager.ResumeOrder(&latest)
+	default:
+		// 오더는 끝났지만 결과에 따른 분기 전에 멈춤
+		utils.Logger.Infof("♻️ Resuming command %s (execution %d) after order %s ended %s",
+			commandType, cmdExec.ID, latest.OrderID, latest.Status)
+		e.OnOrderCompleted(&latest, latest.Status == constants.OrderExecutionStatusCompleted)
+		return true
+	}
+}
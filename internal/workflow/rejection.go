@@ -0,0 +1,84 @@
+// internal/workflow/rejection.go
+package workflow
+
+import (
+	"fmt"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/utils"
+	"strings"
+	"time"
+)
+
+// orderReferenceKey 로봇 오류가 오더를 가리킬 때 쓰는 errorReferences 키 (VDA5050)
+const orderReferenceKey = "orderId"
+
+// orderErrorMessageLimit 저장하는 로봇 오류 내용 최대 길이 (ErrorMessage 컬럼 500자, 단계 오류의 접두어 여유)
+const orderErrorMessageLimit = 450
+
+// orderRejections state.errors 중 orderId를 참조하는 오류를 오더별 오류 내용으로 모읍니다.
+// 로봇은 받아들이지 않은 오더(검증 실패, 경로 없음, orderUpdate 오류 등)를 이렇게 보고합니다.
+func orderRejections(stateMsg *models.RobotStateMessage) map[string]string {
+	rejections := make(map[string]string)
+	for _, robotErr := range stateMsg.Errors {
+		for _, ref := range robotErr.ErrorReferences {
+			if ref.ReferenceKey != orderReferenceKey || ref.ReferenceValue == "" {
+				continue
+			}
+			detail := robotErr.ErrorType
+			if robotErr.ErrorDescription != "" {
+				detail = fmt.Sprintf("%s: %s", robotErr.ErrorType, robotErr.ErrorDescription)
+			}
+			if previous, exists := rejections[ref.ReferenceValue]; exists {
+				detail = previous + "; " + detail
+			}
+			rejections[ref.ReferenceValue] = detail
+		}
+	}
+	return rejections
+}
+
+// handleOrderRejections 로봇이 거부한 실행 중 오더를 REJECTED로 끝내고 명령 매핑의 FailureOrder로 분기합니다.
+// 이미 끝난 오더를 참조하는 오류는 무시하므로, 로봇이 오류를 계속 보고해도 한 번만 처리됩니다.
+func (e *Executor) handleOrderRejections(stateMsg *models.RobotStateMessage) {
+	rejections := orderRejections(stateMsg)
+	if len(rejections) == 0 {
+		return
+	}
+	orderIDs := make([]string, 0, len(rejections))
+	for orderID := range rejections {
+		orderIDs = append(orderIDs, orderID)
+	}
+
+	var orderExecutions []models.OrderExecution
+	if err := e.db.Scopes(repository.SiteScope(e.config.SiteID)).
+		Where("order_id IN ? AND status IN ?", orderIDs,
+			[]string{constants.OrderExecutionStatusPending, constants.OrderExecutionStatusRunning}).
+		Find(&orderExecutions).Error; err != nil {
+		utils.Logger.Errorf("❌ Failed to load orders referenced by robot errors: %v", err)
+		return
+	}
+	for i := range orderExecutions {
+		orderExec := &orderExecutions[i]
+		if orderExec.SerialNumber != "" && orderExec.SerialNumber != stateMsg.SerialNumber {
+			continue
+		}
+		e.rejectOrder(orderExec, rejections[orderExec.OrderID])
+	}
+}
+
+// rejectOrder 오더를 로봇 오류 내용과 함께 REJECTED로 표시하고 실패로 완료 처리
+func (e *Executor) rejectOrder(orderExec *models.OrderExecution, detail string) {
+	utils.Logger.Errorf("⛔ Order %s rejected by robot %s: %s (cid=%s)",
+		orderExec.OrderID, orderExec.SerialNumber, detail, orderExec.CorrelationID)
+
+	if len(detail) > orderErrorMessageLimit {
+		detail = strings.ToValidUTF8(detail[:orderErrorMessageLimit], "")
+	}
+	orderExec.ErrorMessage = detail
+	now := time.Now()
+	repository.UpdateOrderExecutionStatus(e.db, orderExec, constants.OrderExecutionStatusRejected, &now)
+	e.stepManager.CancelRunningSteps(orderExec.ID, "rejected by robot: "+detail)
+	e.OnOrderCompleted(orderExec, false)
+}
@@ -0,0 +1,296 @@
+// internal/workflow/shadow.go
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/utils"
+	"reflect"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// shadowMaxDiffs 실행 하나에 기록하는 최대 차이 수
+const shadowMaxDiffs = 100
+
+// shadowIgnoredKeys 메시지마다 달라지는 필드 (비교에서 제외)
+var shadowIgnoredKeys = map[string]bool{
+	"headerId":  true,
+	"timestamp": true,
+	"actionId":  true,
+}
+
+// ShadowDiff 실제 오더 메시지와 섀도 메시지의 차이 하나
+type ShadowDiff struct {
+	StepOrder int         `json:"step_order"` // 메시지의 첫 단계
+	Path      string      `json:"path"`       // JSON 경로 (예: nodes[0].actions[1].actionType), 메시지가 한쪽에만 있으면 빈 값
+	Primary   interface{} `json:"primary"`
+	Shadow    interface{} `json:"shadow"`
+}
+
+// ShadowRunner 끝난 오더를 섀도 템플릿으로 드라이런하여 실제 전송한 메시지와 비교합니다.
+type ShadowRunner struct {
+	db      *gorm.DB
+	builder *OrderBuilder
+}
+
+// NewShadowRunner 새 섀도 실행기 생성
+func NewShadowRunner(db *gorm.DB, builder *OrderBuilder) *ShadowRunner {
+	return &ShadowRunner{db: db, builder: builder}
+}
+
+// Compare 오더 템플릿에 섀도 설정이 있으면 같은 실행 조건으로 섀도 템플릿 메시지를 만들어 비교 결과 저장
+func (r *ShadowRunner) Compare(execution *models.OrderExecution) {
+	shadow, err := repository.FindTemplateShadow(r.db, execution.SiteID, execution.TemplateID)
+	if err != nil {
+		utils.Logger.Warnf("👥 Failed to load shadow template of order %s: %v", execution.OrderID, err)
+		return
+	}
+	if shadow == nil {
+		return
+	}
+
+	comparison, err := r.compare(execution, shadow.ShadowTemplateID)
+	if err != nil {
+		utils.Logger.Warnf("👥 Failed to compare order %s with shadow template %d: %v",
+			execution.OrderID, shadow.ShadowTemplateID, err)
+		return
+	}
+	if err := r.db.Create(comparison).Error; err != nil {
+		utils.Logger.Warnf("👥 Failed to save shadow comparison of order %s: %v", execution.OrderID, err)
+		return
+	}
+	if comparison.ShadowError != "" {
+		utils.Logger.Warnf("👥 Shadow template %d could not run for order %s: %s",
+			shadow.ShadowTemplateID, execution.OrderID, comparison.ShadowError)
+	} else {
+		utils.Logger.Infof("👥 Order %s compared with shadow template %d: %d difference(s)",
+			execution.OrderID, shadow.ShadowTemplateID, comparison.DiffCount)
+	}
+}
+
+// compare 실제 전송한 메시지(아웃박스)와 섀도 템플릿 메시지를 단계별로 비교
+func (r *ShadowRunner) compare(execution *models.OrderExecution, shadowTemplateID uint) (*models.ShadowComparison, error) {
+	comparison := &models.ShadowComparison{
+		SiteID:           execution.SiteID,
+		TemplateID:       execution.TemplateID,
+		ShadowTemplateID: shadowTemplateID,
+		ExecutionID:      execution.ID,
+		OrderID:          execution.OrderID,
+		SerialNumber:     execution.SerialNumber,
+		ExecutionStatus:  execution.Status,
+	}
+
+	primary, primaryPayloads, err := r.primaryMessages(execution)
+	if err != nil {
+		return nil, err
+	}
+	comparison.PrimaryPayloads = encodeShadowPayloads(primaryPayloads)
+
+	template, err := repository.LoadExpandedTemplate(r.db, execution.SiteID, shadowTemplateID)
+	if err != nil {
+		comparison.ShadowError = err.Error()
+		if len(comparison.ShadowError) > 500 {
+			comparison.ShadowError = comparison.ShadowError[:500]
+		}
+		return comparison, nil
+	}
+	shadowExecution := *execution
+	shadowExecution.TemplateID = shadowTemplateID
+	shadowSteps := r.shadowMessages(template, &shadowExecution)
+	shadowPayloads := make([]interface{}, 0, len(shadowSteps))
+	for _, step := range shadowSteps {
+		shadowPayloads = append(shadowPayloads, step.message)
+	}
+	comparison.ShadowPayloads = encodeShadowPayloads(shadowPayloads)
+
+	diffs := diffShadowMessages(primary, shadowSteps, execution.Status == constants.OrderExecutionStatusCompleted)
+	comparison.DiffCount = len(diffs)
+	if len(diffs) > shadowMaxDiffs {
+		diffs = diffs[:shadowMaxDiffs]
+	}
+	data, err := json.Marshal(diffs)
+	if err != nil {
+		return nil, err
+	}
+	comparison.Differences = string(data)
+	return comparison, nil
+}
+
+// primaryMessages 실행이 전송한 오더 메시지를 첫 단계 순번별로 정규화 (전송 순 원문도 함께 반환)
+func (r *ShadowRunner) primaryMessages(execution *models.OrderExecution) (map[int]interface{}, []interface{}, error) {
+	var rows []struct {
+		Payload   string
+		StepOrder *int
+	}
+	err := r.db.Model(&models.OutboxMessage{}).
+		Select("outbox_messages.payload, step_executions.step_order").
+		Joins("LEFT JOIN step_executions ON step_executions.id = outbox_messages.step_execution_id").
+		Where("outbox_messages.order_id = ? AND outbox_messages.message_type = ?", execution.OrderID, "order").
+		Order("outbox_messages.id ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byStep := make(map[int]interface{}, len(rows))
+	payloads := make([]interface{}, 0, len(rows))
+	for _, row := range rows {
+		var payload interface{}
+		if err := json.Unmarshal([]byte(row.Payload), &payload); err != nil {
+			return nil, nil, fmt.Errorf("stored order payload is invalid: %w", err)
+		}
+		payloads = append(payloads, payload)
+		if row.StepOrder != nil {
+			byStep[*row.StepOrder] = normalizeShadowValue(payload)
+		}
+	}
+	return byStep, payloads, nil
+}
+
+// shadowStep 섀도 템플릿 단계(병렬 그룹은 그룹 전체)의 메시지
+type shadowStep struct {
+	stepOrder int
+	optional  bool // 조건부 또는 실패 분기 단계라 실제 실행에서 빠질 수 있음
+	message   interface{}
+}
+
+// shadowMessages 섀도 템플릿의 단계별 메시지 (headerId 없이 생성하므로 실제 발행 순번을 소비하지 않음)
+func (r *ShadowRunner) shadowMessages(template *models.OrderTemplate, execution *models.OrderExecution) []shadowStep {
+	var steps []shadowStep
+	for i := 0; i < len(template.OrderSteps); {
+		step := &template.OrderSteps[i]
+		members := []*models.OrderStep{step}
+		if step.ParallelGroup != "" {
+			members = parallelGroupMembers(template, step)
+		}
+
+		entry := shadowStep{stepOrder: step.StepOrder}
+		for _, member := range members {
+			if member.Condition != "" || member.PreviousStepResult == constants.PreviousResultFailure {
+				entry.optional = true
+			}
+		}
+		data, _ := json.Marshal(r.builder.buildGroupOrderBody(execution, members))
+		var message interface{}
+		json.Unmarshal(data, &message)
+		entry.message = normalizeShadowValue(message)
+		steps = append(steps, entry)
+		i += len(members)
+	}
+	return steps
+}
+
+// diffShadowMessages 첫 단계 순번이 같은 메시지끼리 비교
+// 실제로 보내지 않은 섀도 단계는 오더가 성공했고 조건부/실패 분기 단계가 아닐 때만 차이로 봅니다.
+func diffShadowMessages(primary map[int]interface{}, shadow []shadowStep, completed bool) []ShadowDiff {
+	diffs := make([]ShadowDiff, 0)
+	seen := make(map[int]bool, len(shadow))
+	for _, step := range shadow {
+		seen[step.stepOrder] = true
+		sent, ok := primary[step.stepOrder]
+		if !ok {
+			if completed && !step.optional {
+				diffs = append(diffs, ShadowDiff{StepOrder: step.stepOrder, Shadow: step.message})
+			}
+			continue
+		}
+		diffShadowValue(step.stepOrder, "", sent, step.message, &diffs)
+	}
+
+	var missing []int
+	for stepOrder := range primary {
+		if !seen[stepOrder] {
+			missing = append(missing, stepOrder)
+		}
+	}
+	sort.Ints(missing)
+	for _, stepOrder := range missing {
+		diffs = append(diffs, ShadowDiff{StepOrder: stepOrder, Primary: primary[stepOrder]})
+	}
+	return diffs
+}
+
+// diffShadowValue 두 JSON 값을 재귀적으로 비교하여 다른 경로를 기록
+func diffShadowValue(stepOrder int, path string, primary, shadow interface{}, diffs *[]ShadowDiff) {
+	primaryMap, primaryIsMap := primary.(map[string]interface{})
+	shadowMap, shadowIsMap := shadow.(map[string]interface{})
+	if primaryIsMap && shadowIsMap {
+		keys := make(map[string]bool, len(primaryMap)+len(shadowMap))
+		for key := range primaryMap {
+			keys[key] = true
+		}
+		for key := range shadowMap {
+			keys[key] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for key := range keys {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+		for _, key := range sorted {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			diffShadowValue(stepOrder, childPath, primaryMap[key], shadowMap[key], diffs)
+		}
+		return
+	}
+
+	primaryList, primaryIsList := primary.([]interface{})
+	shadowList, shadowIsList := shadow.([]interface{})
+	if primaryIsList && shadowIsList {
+		for i := 0; i < len(primaryList) || i < len(shadowList); i++ {
+			var p, s interface{}
+			if i < len(primaryList) {
+				p = primaryList[i]
+			}
+			if i < len(shadowList) {
+				s = shadowList[i]
+			}
+			diffShadowValue(stepOrder, fmt.Sprintf("%s[%d]", path, i), p, s, diffs)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(primary, shadow) {
+		*diffs = append(*diffs, ShadowDiff{StepOrder: stepOrder, Path: path, Primary: primary, Shadow: shadow})
+	}
+}
+
+// normalizeShadowValue 비교에서 제외할 필드를 지운 사본
+func normalizeShadowValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			if shadowIgnoredKeys[key] {
+				continue
+			}
+			result[key] = normalizeShadowValue(child)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, child := range v {
+			result[i] = normalizeShadowValue(child)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// encodeShadowPayloads 메시지 목록을 JSON 배열 문자열로 저장
+func encodeShadowPayloads(payloads []interface{}) string {
+	data, err := json.Marshal(payloads)
+	if err != nil {
+		return "[]"
+	}
+	return string(data)
+}
@@ -4,40 +4,56 @@ package workflow
 import (
 	"context"
 	"fmt"
+	"mqtt-bridge/internal/common/condition"
 	"mqtt-bridge/internal/common/constants"
 	"mqtt-bridge/internal/common/redis"
 	"mqtt-bridge/internal/models"
 	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/robot"
+	"mqtt-bridge/internal/telemetry"
 	"mqtt-bridge/internal/utils"
+	"mqtt-bridge/internal/zones"
+	"sync"
 	"time"
 
 	redisClient "github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/attribute"
 	"gorm.io/gorm"
 )
 
-// MessageSender 메시지 전송 인터페이스
-type MessageSender interface {
-	SendOrderMessage(orderMsg *models.OrderMessage) error
-}
-
 // StepManager 워크플로우 단계 관리
 type StepManager struct {
 	db            *gorm.DB
 	redisClient   *redisClient.Client
 	orderBuilder  *OrderBuilder
-	messageSender MessageSender
+	outbox        *OutboxDispatcher
+	geofence      *GeofenceValidator
+	tracer        *telemetry.OrderTracer
 	executor      *Executor // 🔥 Executor 참조 추가
+	compatibility *robot.CompatibilityGate
+	shadow        *ShadowRunner       // 템플릿 섀도 비교 (끝난 오더마다)
+	zones         *zones.Coordinator  // 구역 점유 예약 (ZONE_RESERVATIONS가 꺼져 있으면 nil)
+	preflight     *Preflight          // 오더 전송 전 사전 점검 (PREFLIGHT_CHECKS가 비어 있으면 nil)
+	payloadPolicy *OrderPayloadPolicy // 오더 압축과 최대 크기 (둘 다 설정하지 않으면 nil)
+	actionMapTTL  time.Duration       // Redis 액션 상태 키 만료 (0이면 만료 없음)
+
+	stateMu      sync.RWMutex
+	latestStates map[string]*models.RobotStateMessage // 로봇별 최신 상태 (단계 조건 평가용)
 }
 
 // NewStepManager 새 단계 관리자 생성
-func NewStepManager(db *gorm.DB, redisClient *redisClient.Client, orderBuilder *OrderBuilder, messageSender MessageSender) *StepManager {
-	return &StepManager{
-		db:            db,
-		redisClient:   redisClient,
-		orderBuilder:  orderBuilder,
-		messageSender: messageSender,
-		executor:      nil, // 기본값은 nil
+func NewStepManager(db *gorm.DB, redisClient *redisClient.Client, orderBuilder *OrderBuilder, outbox *OutboxDispatcher, tracer *telemetry.OrderTracer) *StepManager {
+	stepManager := &StepManager{
+		db:           db,
+		redisClient:  redisClient,
+		orderBuilder: orderBuilder,
+		outbox:       outbox,
+		tracer:       tracer,
+		executor:     nil, // 기본값은 nil
+		latestStates: make(map[string]*models.RobotStateMessage),
 	}
+	outbox.SetFailureHandler(stepManager.handleOutboxFailure)
+	return stepManager
 }
 
 // SetExecutor Executor 참조 설정 (순환 의존성 해결용)
@@ -46,6 +62,29 @@ func (s *StepManager) SetExecutor(executor *Executor) {
 	utils.Logger.Infof("✅ StepManager: Executor reference set")
 }
 
+// SetGeofenceValidator 오더 발행 전 지오펜스 검증기 설정
+func (s *StepManager) SetGeofenceValidator(geofence *GeofenceValidator) {
+	s.geofence = geofence
+	utils.Logger.Infof("✅ StepManager: Geofence validator set")
+}
+
+// ObserveState 단계 조건 평가에 사용할 로봇의 최신 상태 기록
+func (s *StepManager) ObserveState(stateMsg *models.RobotStateMessage) {
+	if stateMsg.SerialNumber == "" {
+		return
+	}
+	s.stateMu.Lock()
+	s.latestStates[stateMsg.SerialNumber] = stateMsg
+	s.stateMu.Unlock()
+}
+
+// latestState 로봇의 최신 상태 (수신한 적이 없으면 nil)
+func (s *StepManager) latestState(serialNumber string) *models.RobotStateMessage {
+	s.stateMu.RLock()
+	defer s.stateMu.RUnlock()
+	return s.latestStates[serialNumber]
+}
+
 // ExecuteNextStep 다음 단계 실행
 func (s *StepManager) ExecuteNextStep(execution *models.OrderExecution, template *models.OrderTemplate) {
 	utils.Logger.Infof("🚀 ExecuteNextStep called: OrderID=%s, CurrentStep=%d",
@@ -61,65 +100,213 @@ func (s *StepManager) ExecuteNextStep(execution *models.OrderExecution, template
 	}
 
 	if currentOrderStep == nil {
-		// 모든 단계 완료
+		// 모든 단계 완료 (마지막으로 실행된 단계가 실패했으면 오더 실패)
+		success := s.previousStepResult(execution) != constants.PreviousResultFailure
+		status := constants.OrderExecutionStatusCompleted
+		if !success {
+			status = constants.OrderExecutionStatusFailed
+		}
 		now := time.Now()
-		repository.UpdateOrderExecutionStatus(s.db, execution, constants.OrderExecutionStatusCompleted, &now)
-		utils.Logger.Infof("🏁 Order execution completed: %s (no more steps)", execution.OrderID)
+		repository.UpdateOrderExecutionStatus(s.db, execution, status, &now)
+		utils.Logger.Infof("🏁 Order execution finished: %s (no more steps, success=%t)", execution.OrderID, success)
 
 		// 🔥 워크플로우 실행기에 완료 알림
-		s.notifyWorkflowExecutor(execution, true)
+		s.notifyWorkflowExecutor(execution, success)
+		return
+	}
+	if s.holdForReadOnly(execution) {
 		return
 	}
 
+	// 실행 조건(이전 단계 결과, 조건식)을 만족하지 않으면 건너뛰고 다음 단계로 진행
+	run, skipReason, err := s.evaluateStepCondition(execution, currentOrderStep)
+	if err != nil {
+		utils.Logger.Errorf("🔀 Failed to evaluate condition of step %d for order %s: %v",
+			currentOrderStep.StepOrder, execution.OrderID, err)
+		s.handleStepFailure(&models.StepExecution{
+			ExecutionID: execution.ID,
+			StepOrder:   currentOrderStep.StepOrder,
+			StartedAt:   time.Now(),
+		}, execution, fmt.Sprintf("condition: %v", err))
+		return
+	}
+	if !run {
+		s.skipStep(execution, currentOrderStep, skipReason)
+		execution.CurrentStep++
+		s.db.Save(execution)
+		s.ExecuteNextStep(execution, template)
+		return
+	}
+
+	// 병렬 그룹이면 이어지는 그룹 단계를 함께 전송 (실행 조건을 만족하지 않는 단계는 건너뜀)
+	members := []*models.OrderStep{currentOrderStep}
+	groupEnd := currentOrderStep.StepOrder
+	if currentOrderStep.ParallelGroup != "" {
+		group := parallelGroupMembers(template, currentOrderStep)
+		groupEnd = group[len(group)-1].StepOrder
+		for _, member := range group[1:] {
+			run, skipReason, err := s.evaluateStepCondition(execution, member)
+			if err != nil {
+				utils.Logger.Errorf("🔀 Failed to evaluate condition of step %d for order %s: %v",
+					member.StepOrder, execution.OrderID, err)
+				s.handleStepFailure(&models.StepExecution{
+					ExecutionID: execution.ID,
+					StepOrder:   member.StepOrder,
+					StartedAt:   time.Now(),
+				}, execution, fmt.Sprintf("condition: %v", err))
+				return
+			}
+			if !run {
+				s.skipStep(execution, member, skipReason)
+				continue
+			}
+			members = append(members, member)
+		}
+		utils.Logger.Infof("🔀 Dispatching parallel group %q for order %s: %d step(s)",
+			currentOrderStep.ParallelGroup, execution.OrderID, len(members))
+	}
+
+	s.refreshETA(execution, currentOrderStep.StepOrder)
+
 	utils.Logger.Infof("🔧 Executing step %d for order %s: %s",
 		currentOrderStep.StepOrder, execution.OrderID,
 		fmt.Sprintf("StepID=%d, WaitForCompletion=%t", currentOrderStep.ID, currentOrderStep.WaitForCompletion))
 
-	// ExpectedActionCount 정확히 계산
-	expectedCount := len(currentOrderStep.StepActionMappings)
-	if expectedCount == 0 {
-		expectedCount = 1 // 최소 1개의 액션은 있어야 함
+	ctx, span := telemetry.StartSpan(s.tracer.Context(execution.OrderID), "step.execute",
+		attribute.String("order.id", execution.OrderID),
+		attribute.Int("step.order", currentOrderStep.StepOrder),
+		attribute.Int("step.group_size", len(members)))
+	defer span.End()
+
+	stepExecutions := make([]*models.StepExecution, 0, len(members))
+	for _, member := range members {
+		// ExpectedActionCount 정확히 계산
+		expectedCount := len(member.StepActionMappings)
+		if expectedCount == 0 {
+			expectedCount = 1 // 최소 1개의 액션은 있어야 함
+		}
+
+		stepExec := &models.StepExecution{
+			ExecutionID:         execution.ID,
+			StepOrder:           member.StepOrder,
+			Status:              constants.StepExecutionStatusRunning,
+			ExpectedActionCount: expectedCount,
+			ParallelGroup:       member.ParallelGroup,
+			StartedAt:           time.Now(),
+		}
+		if member.WaitForCompletion && member.TimeoutSeconds > 0 {
+			timeoutAt := stepExec.StartedAt.Add(time.Duration(member.TimeoutSeconds) * time.Second)
+			stepExec.TimeoutAt = &timeoutAt
+		}
+		stepExecutions = append(stepExecutions, stepExec)
 	}
+	stepExecution := stepExecutions[0]
 
-	stepExecution := &models.StepExecution{
-		ExecutionID:         execution.ID,
-		StepOrder:           currentOrderStep.StepOrder,
-		Status:              constants.StepExecutionStatusRunning,
-		ExpectedActionCount: expectedCount,
-		StartedAt:           time.Now(),
+	// 오더 메시지 생성 (그룹 단계마다 노드 하나)
+	orderMsg := s.orderBuilder.BuildGroupOrderMessage(execution, members)
+	topic := constants.GetMeiliOrderTopic(orderMsg.Manufacturer, orderMsg.SerialNumber)
+
+	// 로봇 VDA 버전이 지원하지 않는 오더는 발행하지 않고 단계 실패 처리 (strict 모드)
+	if s.compatibility != nil {
+		feature := robot.FeatureOrder
+		if len(orderMsg.Nodes) > 1 {
+			feature = robot.FeatureMultiNodeOrder
+		}
+		if err := s.compatibility.CheckFeature(execution.SerialNumber, feature); err != nil {
+			utils.Logger.Errorf("🧬 Order %s not sent: %v", execution.OrderID, err)
+			s.handleStepFailure(stepExecution, execution, fmt.Sprintf("compatibility: %v", err))
+			return
+		}
 	}
 
-	if err := s.db.Create(stepExecution).Error; err != nil {
-		utils.Logger.Errorf("❌ Failed to create step execution: %v", err)
+	// 최대 전송 크기를 넘는 오더는 발행하지 않고 단계 실패 처리 (ORDER_OVERSIZE_POLICY=reject)
+	if err := s.payloadPolicy.Check(orderMsg); err != nil {
+		utils.Logger.Errorf("📦 Order %s not sent: %v", execution.OrderID, err)
+		s.handleStepFailure(stepExecution, execution, err.Error())
 		return
 	}
 
-	utils.Logger.Infof("📝 Step execution created: ID=%d, ExpectedActionCount=%d",
-		stepExecution.ID, stepExecution.ExpectedActionCount)
+	// 허용 영역을 벗어나는 오더는 발행하지 않고 단계 실패 처리
+	if s.geofence != nil {
+		if err := s.geofence.ValidateOrder(orderMsg); err != nil {
+			utils.Logger.Errorf("🚧 Geofence rejected order %s: %v", execution.OrderID, err)
+			s.handleStepFailure(stepExecution, execution, fmt.Sprintf("geofence: %v", err))
+			return
+		}
+	}
 
-	// 오더 메시지 생성
-	orderMsg := s.orderBuilder.BuildOrderMessage(execution, currentOrderStep)
+	// 로봇 사전 점검에 실패하면 발행하지 않고 단계 실패 처리 (실패한 점검과 사유를 오류에 기록)
+	if s.preflight != nil {
+		if err := s.preflight.Run(execution.SerialNumber, execution.OrderID).Err(); err != nil {
+			utils.Logger.Warnf("✈️ Order %s not sent: %v", execution.OrderID, err)
+			s.handleStepFailure(stepExecution, execution, err.Error())
+			return
+		}
+	}
 
-	// Redis에 액션 상태 초기화
-	s.initializeActionStatusInRedis(stepExecution, orderMsg)
+	// 다른 로봇이 점유 중인 구역이 있으면 보내지 않고 구역이 해제될 때까지 대기
+	if s.zones != nil && !s.reserveZones(execution, members) {
+		return
+	}
 
-	// 로봇에 오더 전송
-	if err := s.messageSender.SendOrderMessage(orderMsg); err != nil {
-		s.handleStepFailure(stepExecution, execution, fmt.Sprintf("failed to send order: %v", err))
+	// 단계 실행 기록과 아웃박스 메시지를 같은 트랜잭션으로 저장 (아웃박스는 그룹의 첫 단계에 연결)
+	var outboxMsg *models.OutboxMessage
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, exec := range stepExecutions {
+			if err := tx.Create(exec).Error; err != nil {
+				return err
+			}
+		}
+		var err error
+		outboxMsg, err = s.outbox.Enqueue(tx, &models.OutboxMessage{
+			Topic:           topic,
+			MessageType:     "order",
+			OrderID:         execution.OrderID,
+			CorrelationID:   execution.CorrelationID,
+			HeaderID:        orderMsg.HeaderID,
+			StepExecutionID: &stepExecution.ID,
+		}, orderMsg)
+		return err
+	})
+	if err != nil {
+		utils.Logger.Errorf("❌ Failed to create step execution: %v", err)
 		return
 	}
 
-	stepExecution.SentToRobot = true
-	s.db.Save(stepExecution)
+	// Redis에 단계별 액션 상태 초기화
+	for i, exec := range stepExecutions {
+		utils.Logger.Infof("📝 Step execution created: ID=%d, ExpectedActionCount=%d",
+			exec.ID, exec.ExpectedActionCount)
+		s.initializeActionStatusInRedis(ctx, exec, orderMsg.Nodes[i:i+1])
+	}
 
-	utils.Logger.Infof("📤 Order sent to robot: OrderID=%s, StepOrder=%d", execution.OrderID, currentOrderStep.StepOrder)
+	// 로봇에 오더 전송 (실패 시 아웃박스 디스패처가 재시도)
+	if err := s.outbox.Dispatch(ctx, outboxMsg); err != nil {
+		if outboxMsg.Status == constants.OutboxStatusFailed {
+			return // 실패 처리는 handleOutboxFailure에서 완료됨
+		}
+		utils.Logger.Warnf("⚠️ Order %s dispatch deferred to outbox retry: %v", execution.OrderID, err)
+	} else {
+		for _, exec := range stepExecutions {
+			exec.SentToRobot = true
+		}
+		utils.Logger.Infof("📤 Order sent to robot: OrderID=%s, StepOrder=%d", execution.OrderID, currentOrderStep.StepOrder)
+	}
 
-	// WaitForCompletion 처리
-	if !currentOrderStep.WaitForCompletion {
-		utils.Logger.Infof("⚡ Step %d does not wait for completion, moving to next step immediately", currentOrderStep.StepOrder)
+	// WaitForCompletion 처리 (그룹은 완료를 기다리는 단계가 하나라도 있으면 대기)
+	waiting := false
+	for i, member := range members {
+		if member.WaitForCompletion {
+			waiting = true
+			continue
+		}
+		utils.Logger.Infof("⚡ Step %d does not wait for completion", member.StepOrder)
 		now := time.Now()
-		repository.UpdateStepExecutionStatus(s.db, stepExecution, constants.StepExecutionStatusFinished, constants.PreviousResultSuccess, "", &now)
-		execution.CurrentStep++
+		repository.UpdateStepExecutionStatus(s.db, stepExecutions[i], constants.StepExecutionStatusFinished, constants.PreviousResultSuccess, "", &now)
+	}
+	if !waiting {
+		utils.Logger.Infof("⚡ Moving to next step immediately after step %d", groupEnd)
+		execution.CurrentStep = groupEnd + 1
 		s.db.Save(execution)
 		s.ExecuteNextStep(execution, template)
 	} else {
@@ -127,6 +314,49 @@ func (s *StepManager) ExecuteNextStep(execution *models.OrderExecution, template
 	}
 }
 
+// ResumeOrder 재시작으로 단계 사이에서 멈춘 오더를 이어서 실행 (실행 중인 단계가 있으면 상태 메시지에 맡기고 false)
+// 마지막 단계가 끝났지만 CurrentStep이 갱신되기 전에 멈췄으면 완료 처리와 같은 규칙으로 다음 단계를 정합니다.
+func (s *StepManager) ResumeOrder(execution *models.OrderExecution) bool {
+	var running int64
+	s.db.Model(&models.StepExecution{}).
+		Where("execution_id = ? AND status = ?", execution.ID, constants.StepExecutionStatusRunning).
+		Count(&running)
+	if running > 0 {
+		return false
+	}
+
+	template, err := repository.LoadExpandedTemplate(s.db, execution.SiteID, execution.TemplateID)
+	if err != nil {
+		utils.Logger.Errorf("❌ Failed to load order template to resume %s: %v", execution.OrderID, err)
+		now := time.Now()
+		repository.UpdateOrderExecutionStatus(s.db, execution, constants.OrderExecutionStatusFailed, &now)
+		s.notifyWorkflowExecutor(execution, false)
+		return true
+	}
+
+	var last models.StepExecution
+	err = s.db.Where("execution_id = ?", execution.ID).Order("step_order DESC, id DESC").First(&last).Error
+	if err == nil && last.StepOrder >= execution.CurrentStep {
+		switch last.Status {
+		case constants.StepExecutionStatusFailed, constants.StepExecutionStatusTimeout, constants.StepExecutionStatusNotAcknowledged:
+			if !hasFailureBranch(template, last.StepOrder) {
+				now := time.Now()
+				repository.UpdateOrderExecutionStatus(s.db, execution, constants.OrderExecutionStatusFailed, &now)
+				s.notifyWorkflowExecutor(execution, false)
+				return true
+			}
+			execution.CurrentStep = last.StepOrder + 1
+		default:
+			execution.CurrentStep = parallelGroupEnd(template, last.StepOrder) + 1
+		}
+		s.db.Save(execution)
+	}
+
+	utils.Logger.Infof("♻️ Resuming order %s at step %d", execution.OrderID, execution.CurrentStep)
+	s.ExecuteNextStep(execution, template)
+	return true
+}
+
 // HandleStepCompletion 단계 완료 처리
 func (s *StepManager) HandleStepCompletion(stateMsg *models.RobotStateMessage) bool {
 	if stateMsg.OrderID == "" {
@@ -136,21 +366,24 @@ func (s *StepManager) HandleStepCompletion(stateMsg *models.RobotStateMessage) b
 
 	utils.Logger.Infof("🔍 Checking step completion for OrderID: %s", stateMsg.OrderID)
 
-	// 실행 중인 단계 조회
-	var stepExecution models.StepExecution
+	// 실행 중인 단계 조회 (병렬 그룹이면 여러 단계)
+	var runningSteps []models.StepExecution
 	err := s.db.Joins("JOIN order_executions ON step_executions.execution_id = order_executions.id").
 		Where("order_executions.order_id = ? AND step_executions.status = ?",
 			stateMsg.OrderID, constants.StepExecutionStatusRunning).
 		Preload("Execution.Template").
-		First(&stepExecution).Error
+		Order("step_executions.step_order ASC").
+		Find(&runningSteps).Error
 
-	if err != nil {
+	if err != nil || len(runningSteps) == 0 {
 		utils.Logger.Debugf("🔍 No running step found for OrderID: %s (%v)", stateMsg.OrderID, err)
 		return false
 	}
-
-	utils.Logger.Infof("🔍 Found running step: ID=%d, StepOrder=%d, ExecutionID=%d",
-		stepExecution.ID, stepExecution.StepOrder, stepExecution.ExecutionID)
+	if runningSteps[0].Execution.Status == constants.OrderExecutionStatusPaused {
+		// 재개하면 로봇이 바뀐 상태를 다시 보내므로 그때 처리
+		utils.Logger.Debugf("⏸️ Order %s is paused, deferring step completion", stateMsg.OrderID)
+		return false
+	}
 
 	// 액션 상태 디버그 로깅
 	utils.Logger.Infof("🔍 Analyzing %d action states:", len(stateMsg.ActionStates))
@@ -159,57 +392,70 @@ func (s *StepManager) HandleStepCompletion(stateMsg *models.RobotStateMessage) b
 			i, action.ActionID, action.ActionType, action.ActionStatus)
 	}
 
-	ctx := context.Background()
-	redisKey := redis.StepActions(int(stepExecution.ID))
-
-	// 액션 상태 업데이트
-	for _, actionState := range stateMsg.ActionStates {
-		utils.Logger.Debugf("🔍 Updating Redis: %s -> %s", actionState.ActionID, actionState.ActionStatus)
-		s.redisClient.HSet(ctx, redisKey, actionState.ActionID, actionState.ActionStatus)
-	}
+	ctx := s.tracer.Context(stateMsg.OrderID)
 
-	// 모든 액션 상태 확인
-	allStatuses, err := s.redisClient.HGetAll(ctx, redisKey).Result()
-	if err != nil {
-		utils.Logger.Errorf("❌ Failed to get action statuses from Redis for step %d: %v", stepExecution.ID, err)
-		return false
-	}
-
-	utils.Logger.Infof("🔍 Redis action statuses: %+v", allStatuses)
-
-	// 단계 결과 결정
-	stepResult := s.determineStepResultFromActions(stateMsg.ActionStates, &stepExecution)
+	var stepExecution models.StepExecution
+	completed := false
+	for i := range runningSteps {
+		running := &runningSteps[i]
+		utils.Logger.Infof("🔍 Found running step: ID=%d, StepOrder=%d, ExecutionID=%d",
+			running.ID, running.StepOrder, running.ExecutionID)
+
+		stepResult := s.updateStepActions(ctx, running, stateMsg.ActionStates)
+		if stepResult == "" {
+			utils.Logger.Infof("🔍 Step %d still in progress", running.StepOrder)
+			continue
+		}
 
-	utils.Logger.Infof("🔍 Step result determined: '%s' for step %d", stepResult, stepExecution.StepOrder)
+		if stepResult == constants.PreviousResultFailure {
+			utils.Logger.Errorf("❌ Step %d failed", running.StepOrder)
+			s.handleStepFailure(running, &running.Execution, "Action failed or robot reported a critical error.")
+			return true
+		}
 
-	if stepResult == "" {
-		utils.Logger.Infof("🔍 Step %d still in progress", stepExecution.StepOrder)
+		// 단계 완료 처리
+		utils.Logger.Infof("✅ Step %d completed successfully", running.StepOrder)
+		now := time.Now()
+		repository.UpdateStepExecutionStatus(s.db, running, constants.StepExecutionStatusFinished, constants.PreviousResultSuccess, "", &now)
+		stepExecution = *running
+		completed = true
+	}
+	if !completed {
 		return false // 아직 진행 중
 	}
 
-	// Redis 정리
-	s.redisClient.Del(ctx, redisKey)
-
-	if stepResult == constants.PreviousResultFailure {
-		utils.Logger.Errorf("❌ Step %d failed", stepExecution.StepOrder)
-		s.handleStepFailure(&stepExecution, &stepExecution.Execution, "Action failed or robot reported a critical error.")
+	// 병렬 그룹은 모든 단계가 끝나야 다음 단계로 진행
+	var remaining int64
+	s.db.Model(&models.StepExecution{}).
+		Where("execution_id = ? AND status = ?", stepExecution.ExecutionID, constants.StepExecutionStatusRunning).
+		Count(&remaining)
+	if remaining > 0 {
+		utils.Logger.Infof("⏳ Parallel group %q of order %s waiting for %d more step(s)",
+			stepExecution.ParallelGroup, stateMsg.OrderID, remaining)
 		return true
 	}
 
-	// 단계 완료 처리
-	utils.Logger.Infof("✅ Step %d completed successfully", stepExecution.StepOrder)
-	now := time.Now()
-	repository.UpdateStepExecutionStatus(s.db, &stepExecution, constants.StepExecutionStatusFinished, constants.PreviousResultSuccess, "", &now)
-
 	execution := stepExecution.Execution
-	execution.CurrentStep++
+
+	// 단계 목록은 합성 템플릿을 펼친 기준으로 판단
+	template, err := repository.LoadExpandedTemplate(s.db, execution.SiteID, execution.TemplateID)
+	if err != nil {
+		utils.Logger.Errorf("❌ Failed to load order template for %s: %v", execution.OrderID, err)
+		now := time.Now()
+		repository.UpdateOrderExecutionStatus(s.db, &execution, constants.OrderExecutionStatusFailed, &now)
+		s.notifyWorkflowExecutor(&execution, false)
+		return true
+	}
+
+	// 병렬 그룹이면 그룹의 마지막 단계 다음으로 이동
+	execution.CurrentStep = parallelGroupEnd(template, stepExecution.StepOrder) + 1
 	s.db.Save(&execution)
 
 	utils.Logger.Infof("📈 Moving to next step: OrderID=%s, CurrentStep=%d -> %d",
 		execution.OrderID, stepExecution.StepOrder, execution.CurrentStep)
 
 	// 다음 단계가 있는지 확인
-	if execution.CurrentStep > len(execution.Template.OrderSteps) {
+	if execution.CurrentStep > len(template.OrderSteps) {
 		// 모든 단계 완료
 		now := time.Now()
 		repository.UpdateOrderExecutionStatus(s.db, &execution, constants.OrderExecutionStatusCompleted, &now)
@@ -221,10 +467,63 @@ func (s *StepManager) HandleStepCompletion(stateMsg *models.RobotStateMessage) b
 	}
 
 	// 다음 단계 실행
-	s.ExecuteNextStep(&execution, &execution.Template)
+	s.ExecuteNextStep(&execution, template)
 	return true
 }
 
+// updateStepActions 단계의 액션 상태를 Redis에 반영하고 단계 결과 결정 (진행 중이면 빈 값)
+// 병렬 그룹 단계는 같은 오더의 다른 단계 액션이 섞이지 않도록 자기 액션만 반영합니다.
+func (s *StepManager) updateStepActions(ctx context.Context, stepExecution *models.StepExecution, actionStates []models.ActionState) string {
+	redisKey := redis.StepActions(int(stepExecution.ID))
+
+	if stepExecution.ParallelGroup != "" {
+		own, err := s.redisClient.HGetAll(ctx, redisKey).Result()
+		if err != nil {
+			utils.Logger.Errorf("❌ Failed to get action statuses from Redis for step %d: %v", stepExecution.ID, err)
+			return ""
+		}
+		filtered := make([]models.ActionState, 0, len(own))
+		for _, actionState := range actionStates {
+			if _, ok := own[actionState.ActionID]; ok {
+				filtered = append(filtered, actionState)
+			}
+		}
+		actionStates = filtered
+	}
+
+	// 상태 전이 기록 (타임라인용)
+	s.recordActionTransitions(ctx, redisKey, stepExecution.ID, actionStates)
+
+	// 액션 상태 업데이트
+	for _, actionState := range actionStates {
+		utils.Logger.Debugf("🔍 Updating Redis: %s -> %s", actionState.ActionID, actionState.ActionStatus)
+		s.redisClient.HSet(ctx, redisKey, actionState.ActionID, actionState.ActionStatus)
+	}
+	if s.actionMapTTL > 0 && len(actionStates) > 0 {
+		s.redisClient.Expire(ctx, redisKey, s.actionMapTTL)
+	}
+
+	// 모든 액션 상태 확인
+	allStatuses, err := s.redisClient.HGetAll(ctx, redisKey).Result()
+	if err != nil {
+		utils.Logger.Errorf("❌ Failed to get action statuses from Redis for step %d: %v", stepExecution.ID, err)
+		return ""
+	}
+
+	utils.Logger.Infof("🔍 Redis action statuses: %+v", allStatuses)
+
+	// 단계 결과 결정
+	stepResult := s.determineStepResultFromActions(actionStates, stepExecution)
+
+	utils.Logger.Infof("🔍 Step result determined: '%s' for step %d", stepResult, stepExecution.StepOrder)
+
+	if stepResult != "" {
+		// Redis 정리
+		s.redisClient.Del(ctx, redisKey)
+	}
+	return stepResult
+}
+
 // CancelRunningSteps 실행 중인 단계들 취소
 func (s *StepManager) CancelRunningSteps(orderExecutionID uint, reason string) {
 	var stepExecutions []models.StepExecution
@@ -300,6 +599,10 @@ func (s *StepManager) determineStepResultFromActions(actionStates []models.Actio
 
 // notifyWorkflowExecutor 워크플로우 실행기에 알림
 func (s *StepManager) notifyWorkflowExecutor(execution *models.OrderExecution, success bool) {
+	if s.shadow != nil {
+		finished := *execution
+		go s.shadow.Compare(&finished)
+	}
 	if s.executor != nil {
 		utils.Logger.Infof("📢 Calling executor OnOrderCompleted: OrderID=%s, Success=%t",
 			execution.OrderID, success)
@@ -310,10 +613,15 @@ func (s *StepManager) notifyWorkflowExecutor(execution *models.OrderExecution, s
 }
 
 // handleStepFailure 단계 실패 처리
+// 이후에 이전 단계 결과가 FAILURE일 때 실행하는 단계가 있으면 오더를 실패시키지 않고 그 분기로 진행합니다.
 func (s *StepManager) handleStepFailure(step *models.StepExecution, order *models.OrderExecution, reason string) {
+	s.failStep(step, order, constants.StepExecutionStatusFailed, reason)
+}
+
+// failStep 단계를 주어진 실패 상태(FAILED, NOT_ACKNOWLEDGED 등)로 끝내고 실패 분기 또는 오더 실패로 진행
+func (s *StepManager) failStep(step *models.StepExecution, order *models.OrderExecution, status, reason string) {
 	now := time.Now()
-	repository.UpdateStepExecutionStatus(s.db, step, constants.StepExecutionStatusFailed, "", reason, &now)
-	repository.UpdateOrderExecutionStatus(s.db, order, constants.OrderExecutionStatusFailed, &now)
+	repository.UpdateStepExecutionStatus(s.db, step, status, constants.PreviousResultFailure, reason, &now)
 
 	// Redis 정리
 	ctx := context.Background()
@@ -322,26 +630,96 @@ func (s *StepManager) handleStepFailure(step *models.StepExecution, order *model
 
 	utils.Logger.Errorf("❌ Step %d failed for order %s: %s", step.StepOrder, order.OrderID, reason)
 
+	// 병렬 그룹의 다른 단계도 함께 실패 처리
+	s.CancelRunningSteps(order.ID, fmt.Sprintf("parallel step %d failed: %s", step.StepOrder, reason))
+
+	if order.Status == constants.OrderExecutionStatusRunning {
+		template, err := repository.LoadExpandedTemplate(s.db, order.SiteID, order.TemplateID)
+		if err == nil && hasFailureBranch(template, step.StepOrder) {
+			utils.Logger.Warnf("🔀 Order %s continues with failure branch after step %d", order.OrderID, step.StepOrder)
+			order.CurrentStep = step.StepOrder + 1
+			s.db.Save(order)
+			s.ExecuteNextStep(order, template)
+			return
+		}
+	}
+
+	repository.UpdateOrderExecutionStatus(s.db, order, constants.OrderExecutionStatusFailed, &now)
+
 	// 워크플로우 실행기에 실패 알림
 	s.notifyWorkflowExecutor(order, false)
 }
 
+// recordActionTransitions Redis의 이전 액션 상태와 비교하여 변경된 상태 전이를 저장
+func (s *StepManager) recordActionTransitions(ctx context.Context, redisKey string, stepExecutionID uint, actionStates []models.ActionState) {
+	previous, err := s.redisClient.HGetAll(ctx, redisKey).Result()
+	if err != nil {
+		utils.Logger.Warnf("⚠️ Failed to load previous action statuses for step %d: %v", stepExecutionID, err)
+		return
+	}
+
+	now := time.Now()
+	transitions := make([]models.ActionStatusTransition, 0, len(actionStates))
+	for _, actionState := range actionStates {
+		prevStatus := previous[actionState.ActionID]
+		if prevStatus == actionState.ActionStatus {
+			continue
+		}
+		transitions = append(transitions, models.ActionStatusTransition{
+			StepExecutionID:   stepExecutionID,
+			ActionID:          actionState.ActionID,
+			ActionType:        actionState.ActionType,
+			FromStatus:        prevStatus,
+			ToStatus:          actionState.ActionStatus,
+			ResultDescription: actionState.ResultDescription,
+			ObservedAt:        now,
+		})
+	}
+
+	if len(transitions) == 0 {
+		return
+	}
+	if err := s.db.Create(&transitions).Error; err != nil {
+		utils.Logger.Warnf("⚠️ Failed to record action transitions for step %d: %v", stepExecutionID, err)
+	}
+}
+
+// handleOutboxFailure 재시도 한도를 초과한 오더 발행 실패를 단계 실패로 처리
+func (s *StepManager) handleOutboxFailure(msg *models.OutboxMessage) {
+	if msg.StepExecutionID == nil {
+		return
+	}
+
+	var stepExecution models.StepExecution
+	if err := s.db.Preload("Execution").First(&stepExecution, *msg.StepExecutionID).Error; err != nil {
+		utils.Logger.Errorf("❌ Failed to load step execution %d for outbox failure: %v", *msg.StepExecutionID, err)
+		return
+	}
+	if stepExecution.Status != constants.StepExecutionStatusRunning {
+		return
+	}
+
+	s.handleStepFailure(&stepExecution, &stepExecution.Execution, fmt.Sprintf("failed to send order: %s", msg.LastError))
+}
+
 // initializeActionStatusInRedis Redis에 액션 상태 초기화
-func (s *StepManager) initializeActionStatusInRedis(stepExec *models.StepExecution, orderMsg *models.OrderMessage) {
-	ctx := context.Background()
+func (s *StepManager) initializeActionStatusInRedis(ctx context.Context, stepExec *models.StepExecution, nodes []models.OrderNode) {
 	redisKey := redis.StepActions(int(stepExec.ID))
 	s.redisClient.Del(ctx, redisKey)
 
 	pipe := s.redisClient.Pipeline()
 	actionCount := 0
 
-	for _, node := range orderMsg.Nodes {
+	for _, node := range nodes {
 		for _, action := range node.Actions {
 			pipe.HSet(ctx, redisKey, action.ActionID, constants.ActionStatusWaiting)
 			actionCount++
 			utils.Logger.Debugf("🔧 Initialized Redis action: %s -> %s", action.ActionID, constants.ActionStatusWaiting)
 		}
 	}
+	if s.actionMapTTL > 0 {
+		pipe.Expire(ctx, redisKey, s.actionMapTTL)
+	}
 
 	_, err := pipe.Exec(ctx)
 	if err != nil {
@@ -358,3 +736,138 @@ func (s *StepManager) initializeActionStatusInRedis(stepExec *models.StepExecuti
 		s.db.Save(stepExec)
 	}
 }
+
+// refreshETA 과거 실행 이력으로 오더의 완료 예상 시각을 다시 계산해 저장 (이력이 없으면 그대로 둠)
+func (s *StepManager) refreshETA(execution *models.OrderExecution, stepOrder int) {
+	estimate, err := repository.EstimateOrderDuration(s.db, execution.SiteID, execution.TemplateID, execution.SerialNumber)
+	if err != nil {
+		utils.Logger.Warnf("⏱️ Failed to estimate duration of order %s: %v", execution.OrderID, err)
+		return
+	}
+	now := time.Now()
+	remaining, ok := estimate.EstimateRemaining(stepOrder, now.Sub(execution.StartedAt))
+	if !ok {
+		return
+	}
+	if err := repository.UpdateOrderExecutionETA(s.db, execution, now.Add(remaining)); err != nil {
+		utils.Logger.Warnf("⏱️ Failed to save ETA of order %s: %v", execution.OrderID, err)
+		return
+	}
+	utils.Logger.Debugf("⏱️ Order %s ETA %s (step %d, %s remaining, basis %s/%d)",
+		execution.OrderID, execution.EstimatedCompletionAt.Format(time.RFC3339), stepOrder,
+		remaining.Round(time.Second), estimate.Basis, estimate.SampleSize)
+}
+
+// previousStepResult 마지막으로 실행된 단계의 결과 (SUCCESS/FAILURE, 실행된 단계가 없으면 빈 값)
+// 건너뛴 단계는 결과에 영향을 주지 않습니다.
+func (s *StepManager) previousStepResult(execution *models.OrderExecution) string {
+	var last models.StepExecution
+	err := s.db.Where("execution_id = ? AND status IN ?", execution.ID, []string{
+		constants.StepExecutionStatusFinished,
+		constants.StepExecutionStatusFailed,
+		constants.StepExecutionStatusTimeout,
+		constants.StepExecutionStatusNotAcknowledged,
+	}).Order("step_order DESC, id DESC").First(&last).Error
+	if err != nil {
+		return ""
+	}
+	if last.Status == constants.StepExecutionStatusFinished {
+		return constants.PreviousResultSuccess
+	}
+	return constants.PreviousResultFailure
+}
+
+// evaluateStepCondition 단계 실행 여부 판단 (실행하지 않으면 건너뛰는 이유 반환)
+//   - PreviousStepResult가 SUCCESS/FAILURE이면 직전 실행 단계 결과와 일치해야 함 (첫 단계는 SUCCESS로 간주)
+//   - Condition이 있으면 로봇의 최신 상태로 평가해 참이어야 함
+func (s *StepManager) evaluateStepCondition(execution *models.OrderExecution, step *models.OrderStep) (bool, string, error) {
+	previous := s.previousStepResult(execution)
+	if previous == "" {
+		previous = constants.PreviousResultSuccess
+	}
+
+	switch step.PreviousStepResult {
+	case constants.PreviousResultSuccess, constants.PreviousResultFailure:
+		if step.PreviousStepResult != previous {
+			return false, fmt.Sprintf("previous step result %s does not match %s", previous, step.PreviousStepResult), nil
+		}
+	}
+
+	if step.Condition == "" {
+		return true, "", nil
+	}
+	expr, err := condition.Parse(step.Condition)
+	if err != nil {
+		return false, "", err
+	}
+	ok, err := expr.Eval(condition.Env{
+		State:          s.latestState(execution.SerialNumber),
+		PreviousResult: previous,
+	})
+	if err != nil {
+		return false, "", err
+	}
+	if !ok {
+		return false, fmt.Sprintf("condition not met: %s", step.Condition), nil
+	}
+	return true, "", nil
+}
+
+// skipStep 실행 조건을 만족하지 않은 단계를 SKIPPED로 기록
+func (s *StepManager) skipStep(execution *models.OrderExecution, step *models.OrderStep, reason string) {
+	now := time.Now()
+	stepExecution := &models.StepExecution{
+		ExecutionID:  execution.ID,
+		StepOrder:    step.StepOrder,
+		Status:       constants.StepExecutionStatusSkipped,
+		StartedAt:    now,
+		CompletedAt:  &now,
+		ErrorMessage: reason,
+	}
+	if err := s.db.Create(stepExecution).Error; err != nil {
+		utils.Logger.Errorf("Failed to record skipped step %d for order %s: %v", step.StepOrder, execution.OrderID, err)
+	}
+	utils.Logger.Infof("⏭️ Step %d skipped for order %s: %s", step.StepOrder, execution.OrderID, reason)
+}
+
+// hasFailureBranch 실패한 단계 이후에 이전 단계 결과가 FAILURE일 때 실행하는 단계가 있는지 확인
+func hasFailureBranch(template *models.OrderTemplate, failedStepOrder int) bool {
+	for _, step := range template.OrderSteps {
+		if step.StepOrder > failedStepOrder && step.PreviousStepResult == constants.PreviousResultFailure {
+			return true
+		}
+	}
+	return false
+}
+
+// parallelGroupMembers 단계부터 이어지는 같은 병렬 그룹의 단계들 (그룹이 없으면 단계 자신만)
+func parallelGroupMembers(template *models.OrderTemplate, step *models.OrderStep) []*models.OrderStep {
+	members := []*models.OrderStep{step}
+	if step.ParallelGroup == "" {
+		return members
+	}
+	for next := step.StepOrder + 1; ; next++ {
+		var member *models.OrderStep
+		for i := range template.OrderSteps {
+			if template.OrderSteps[i].StepOrder == next {
+				member = &template.OrderSteps[i]
+				break
+			}
+		}
+		if member == nil || member.ParallelGroup != step.ParallelGroup {
+			return members
+		}
+		members = append(members, member)
+	}
+}
+
+// parallelGroupEnd 단계가 속한 병렬 그룹의 마지막 단계 순서 (그룹이 없으면 단계 자신)
+func parallelGroupEnd(template *models.OrderTemplate, stepOrder int) int {
+	for i := range template.OrderSteps {
+		if template.OrderSteps[i].StepOrder == stepOrder {
+			members := parallelGroupMembers(template, &template.OrderSteps[i])
+			return members[len(members)-1].StepOrder
+		}
+	}
+	return stepOrder
+}
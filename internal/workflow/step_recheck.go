@@ -0,0 +1,77 @@
+// internal/workflow/step_recheck.go
+package workflow
+
+import (
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"mqtt-bridge/internal/utils"
+	"time"
+)
+
+// stepRecheckInterval 보내지 못하고 보류한 단계(구역 예약 대기, 읽기 전용)를 다시 확인하는 간격
+// 다른 브릿지의 로봇이 구역을 해제하는 것은 알림이 없으므로 주기적으로 확인합니다.
+const stepRecheckInterval = 2 * time.Second
+
+// scheduleStepRecheck 오더의 현재 단계를 잠시 후 다시 실행하도록 예약 (처음 보류하면 true)
+// 동시 실행 제한 대기와 같은 목록을 쓰므로 오더가 끝날 때 바로 다시 확인되고 취소/비상 정지 시 함께 제거됩니다.
+func (e *Executor) scheduleStepRecheck(id uint) bool {
+	e.queuedMu.Lock()
+	defer e.queuedMu.Unlock()
+	previous, waiting := e.waitingOrders[id]
+	if waiting {
+		previous.Stop()
+	}
+	e.waitingOrders[id] = time.AfterFunc(stepRecheckInterval, func() { e.recheckStep(id) })
+	return !waiting
+}
+
+// recheckStep 보류한 오더의 현재 단계를 다시 실행 (아직 보낼 수 없으면 ExecuteNextStep이 다시 보류함)
+func (e *Executor) recheckStep(id uint) {
+	e.queuedMu.Lock()
+	timer, ok := e.waitingOrders[id]
+	e.queuedMu.Unlock()
+	if !ok {
+		return
+	}
+	done := func() {
+		e.queuedMu.Lock()
+		if e.waitingOrders[id] == timer {
+			delete(e.waitingOrders, id)
+		}
+		e.queuedMu.Unlock()
+	}
+
+	var execution models.OrderExecution
+	if err := e.db.First(&execution, id).Error; err != nil {
+		utils.Logger.Errorf("❌ Held order execution %d not found: %v", id, err)
+		if e.readOnly.Enabled() {
+			e.scheduleStepRecheck(id) // DB 페일오버 중일 수 있으므로 계속 보류
+			return
+		}
+		done()
+		return
+	}
+	switch execution.Status {
+	case constants.OrderExecutionStatusRunning:
+	case constants.OrderExecutionStatusPaused:
+		e.scheduleStepRecheck(id) // 재개될 때까지 보내지 않음
+		return
+	default:
+		done() // 취소/비상 정지로 이미 종료됨
+		return
+	}
+	if e.readOnly.Enabled() {
+		e.scheduleStepRecheck(id)
+		return
+	}
+
+	template, err := repository.LoadExpandedTemplate(e.db, execution.SiteID, execution.TemplateID)
+	if err != nil {
+		utils.Logger.Errorf("🧩 Failed to load order template %d for %s: %v", execution.TemplateID, execution.OrderID, err)
+		e.scheduleStepRecheck(id)
+		return
+	}
+	done()
+	e.stepManager.ExecuteNextStep(&execution, template)
+}
@@ -0,0 +1,313 @@
+// internal/workflow/transform.go
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/robot"
+	"os"
+	"strings"
+)
+
+// Transform 연산 상수 (PAYLOAD_TRANSFORM_FILE의 op 값)
+const (
+	TransformRename = "rename" // 필드 이름을 to로 변경 (제조사 방언)
+	TransformScale  = "scale"  // 숫자 값을 value * factor + offset으로 변경 (단위 변환)
+	TransformSet    = "set"    // 값 설정 (문자열의 {{siteId}}, {{manufacturer}}, {{serialNumber}}, {{transport}} 치환)
+	TransformRemove = "remove" // 필드 제거 (로봇 버전이 지원하지 않는 필드)
+)
+
+// TransformStep 규칙의 변환 단계 하나
+type TransformStep struct {
+	Op     string      `json:"op"`
+	Path   string      `json:"path"`             // 점으로 구분한 경로, 배열의 모든 요소는 [] (예: nodes[].actions[].actionType)
+	To     string      `json:"to,omitempty"`     // rename: 새 필드 이름
+	Factor float64     `json:"factor,omitempty"` // scale: 곱할 값 (오프셋만 더하려면 1)
+	Offset float64     `json:"offset,omitempty"` // scale: 곱한 뒤 더할 값
+	Value  interface{} `json:"value,omitempty"`  // set: 설정할 값
+}
+
+// TransformRule 대상 조건과 변환 단계 (조건이 비어 있으면 모든 대상에 적용)
+type TransformRule struct {
+	Name         string             `json:"name"`
+	Manufacturer string             `json:"manufacturer,omitempty"`
+	SerialNumber string             `json:"serial_number,omitempty"`
+	Transports   []string           `json:"transports,omitempty"`    // mqtt, http
+	MessageTypes []string           `json:"message_types,omitempty"` // 토픽의 마지막 세그먼트 (order, instantActions)
+	Versions     robot.VersionRange `json:"versions,omitempty"`      // 로봇이 보고한 VDA 버전 범위 (버전을 모르는 로봇에는 적용하지 않음)
+	Steps        []TransformStep    `json:"steps"`
+}
+
+// TransformTarget 변환할 메시지의 전송 대상
+type TransformTarget struct {
+	Transport    string
+	Manufacturer string
+	SerialNumber string
+	MessageType  string
+	Version      string // 로봇이 보고한 VDA 버전 (모르면 빈 값)
+}
+
+// PayloadTransforms 전송 전 페이로드 변환 규칙 묶음 (파일 순서대로 적용)
+type PayloadTransforms struct {
+	siteID string
+	rules  []compiledRule
+}
+
+// compiledRule 경로를 미리 해석한 규칙
+type compiledRule struct {
+	TransformRule
+	paths [][]pathSegment // Steps와 같은 순서
+}
+
+// pathSegment 경로의 한 부분 (each면 배열의 모든 요소로 내려감)
+type pathSegment struct {
+	key  string
+	each bool
+}
+
+// LoadPayloadTransforms 변환 규칙 파일 읽기 ({"rules": [...]})
+func LoadPayloadTransforms(path, siteID string) (*PayloadTransforms, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Rules []TransformRule `json:"rules"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid payload transform file %s: %w", path, err)
+	}
+	return NewPayloadTransforms(file.Rules, siteID)
+}
+
+// NewPayloadTransforms 규칙을 검증하여 변환기 생성
+func NewPayloadTransforms(rules []TransformRule, siteID string) (*PayloadTransforms, error) {
+	transforms := &PayloadTransforms{siteID: siteID}
+	for i, rule := range rules {
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule-%d", i+1)
+		}
+		compiled := compiledRule{TransformRule: rule}
+		for j, step := range rule.Steps {
+			segments, err := validateTransformStep(step)
+			if err != nil {
+				return nil, fmt.Errorf("payload transform %s step %d: %w", rule.Name, j+1, err)
+			}
+			compiled.paths = append(compiled.paths, segments)
+		}
+		transforms.rules = append(transforms.rules, compiled)
+	}
+	return transforms, nil
+}
+
+func validateTransformStep(step TransformStep) ([]pathSegment, error) {
+	segments, err := parseTransformPath(step.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch step.Op {
+	case TransformRename:
+		if step.To == "" || strings.ContainsAny(step.To, ".[]") {
+			return nil, fmt.Errorf("rename requires a plain field name in to")
+		}
+	case TransformScale:
+		if step.Factor == 0 {
+			return nil, fmt.Errorf("scale requires a non-zero factor (use 1 to only add an offset)")
+		}
+	case TransformSet, TransformRemove:
+	default:
+		return nil, fmt.Errorf("unsupported op %q (rename, scale, set, remove)", step.Op)
+	}
+	return segments, nil
+}
+
+// parseTransformPath "nodes[].actions[].actionType" 형식 경로 해석 (마지막은 필드 이름이어야 함)
+func parseTransformPath(path string) ([]pathSegment, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path is required")
+	}
+	var segments []pathSegment
+	for _, part := range strings.Split(path, ".") {
+		key, each := strings.CutSuffix(part, "[]")
+		if key == "" || strings.ContainsAny(key, "[]") {
+			return nil, fmt.Errorf("invalid path %q", path)
+		}
+		segments = append(segments, pathSegment{key: key, each: each})
+	}
+	if segments[len(segments)-1].each {
+		return nil, fmt.Errorf("path %q must end with a field name", path)
+	}
+	return segments, nil
+}
+
+// Rules 적용 순서대로의 규칙 목록
+func (p *PayloadTransforms) Rules() []TransformRule {
+	rules := make([]TransformRule, 0, len(p.rules))
+	for _, rule := range p.rules {
+		rules = append(rules, rule.TransformRule)
+	}
+	return rules
+}
+
+// NeedsVersion 로봇 버전 조건이 있는 규칙이 있는지 (없으면 버전을 조회하지 않아도 됨)
+func (p *PayloadTransforms) NeedsVersion() bool {
+	for _, rule := range p.rules {
+		if rule.Versions.Min != "" || rule.Versions.Max != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply 대상에 맞는 규칙을 순서대로 적용한 페이로드와 적용한 규칙 이름을 반환합니다.
+// 맞는 규칙이 없으면 페이로드를 그대로 반환하며, 경로가 없는 필드는 건너뜁니다.
+func (p *PayloadTransforms) Apply(target TransformTarget, payload []byte) ([]byte, []string, error) {
+	var matched []*compiledRule
+	for i := range p.rules {
+		if p.rules[i].matches(target) {
+			matched = append(matched, &p.rules[i])
+		}
+	}
+	if len(matched) == 0 {
+		return payload, nil, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber() // headerId 같은 정수를 float로 바꾸지 않도록
+	var message interface{}
+	if err := decoder.Decode(&message); err != nil {
+		return nil, nil, fmt.Errorf("payload is not JSON: %w", err)
+	}
+
+	applied := make([]string, 0, len(matched))
+	for _, rule := range matched {
+		for i, step := range rule.Steps {
+			if err := p.applyStep(message, rule.paths[i], step, target); err != nil {
+				return nil, nil, fmt.Errorf("payload transform %s: %w", rule.Name, err)
+			}
+		}
+		applied = append(applied, rule.Name)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(message); err != nil {
+		return nil, nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), applied, nil
+}
+
+// matches 규칙 조건이 대상과 맞는지
+func (r *compiledRule) matches(target TransformTarget) bool {
+	if r.Manufacturer != "" && !strings.EqualFold(r.Manufacturer, target.Manufacturer) {
+		return false
+	}
+	if r.SerialNumber != "" && r.SerialNumber != target.SerialNumber {
+		return false
+	}
+	if len(r.Transports) > 0 && !containsFold(r.Transports, target.Transport) {
+		return false
+	}
+	if len(r.MessageTypes) > 0 && !containsFold(r.MessageTypes, target.MessageType) {
+		return false
+	}
+	if r.Versions.Min != "" || r.Versions.Max != "" {
+		if target.Version == "" || !r.Versions.Contains(target.Version) {
+			return false
+		}
+	}
+	return true
+}
+
+// applyStep 경로의 모든 대상 필드에 변환 단계 적용
+func (p *PayloadTransforms) applyStep(message interface{}, segments []pathSegment, step TransformStep, target TransformTarget) error {
+	return walkTransformPath(message, segments, step.Op == TransformSet, func(parent map[string]interface{}, key string) error {
+		value, exists := parent[key]
+		switch step.Op {
+		case TransformRename:
+			if exists {
+				delete(parent, key)
+				parent[step.To] = value
+			}
+		case TransformScale:
+			if !exists || value == nil {
+				return nil
+			}
+			number, err := transformNumber(value)
+			if err != nil {
+				return fmt.Errorf("%s: %w", step.Path, err)
+			}
+			parent[key] = number*step.Factor + step.Offset
+		case TransformSet:
+			parent[key] = p.expandValue(step.Value, target)
+		case TransformRemove:
+			delete(parent, key)
+		}
+		return nil
+	})
+}
+
+// walkTransformPath 경로를 따라 내려가 마지막 필드의 부모 객체마다 fn 호출
+// create면 중간 객체가 없을 때 만들고(set), 아니면 그 경로는 건너뜁니다.
+func walkTransformPath(node interface{}, segments []pathSegment, create bool, fn func(parent map[string]interface{}, key string) error) error {
+	object, ok := node.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	segment := segments[0]
+	if len(segments) == 1 {
+		return fn(object, segment.key)
+	}
+	child, exists := object[segment.key]
+	if segment.each {
+		items, _ := child.([]interface{})
+		for _, item := range items {
+			if err := walkTransformPath(item, segments[1:], create, fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if !exists && create {
+		child = make(map[string]interface{})
+		object[segment.key] = child
+	}
+	return walkTransformPath(child, segments[1:], create, fn)
+}
+
+// expandValue set 값의 문자열 자리표시자를 사이트/대상 값으로 치환
+func (p *PayloadTransforms) expandValue(value interface{}, target TransformTarget) interface{} {
+	text, ok := value.(string)
+	if !ok {
+		return value
+	}
+	return strings.NewReplacer(
+		"{{siteId}}", p.siteID,
+		"{{manufacturer}}", target.Manufacturer,
+		"{{serialNumber}}", target.SerialNumber,
+		"{{transport}}", target.Transport,
+	).Replace(text)
+}
+
+// transformNumber JSON 숫자 값을 float64로
+func transformNumber(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case json.Number:
+		return v.Float64()
+	case float64:
+		return v, nil
+	}
+	return 0, fmt.Errorf("value %v is not a number", value)
+}
+
+// containsFold 대소문자 구분 없이 목록에 값이 있는지
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+// internal/workflow/transport.go
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mqtt-bridge/internal/config"
+	"net/http"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// Transport 이름 상수 (TRANSPORT_FAILOVER 설정 값)
+const (
+	TransportMQTT = "mqtt" // MQTT 브로커로 발행
+	TransportHTTP = "http" // 로봇 HTTP 엔드포인트로 직접 전송
+)
+
+// Transport 아웃박스 메시지를 로봇에 전달하는 경로
+type Transport interface {
+	Name() string
+	Available() bool
+	Publish(ctx context.Context, topic string, payload []byte) error
+}
+
+// bufferingClient 연결이 끊긴 동안 발행을 보관하는 MQTT 클라이언트 (MQTT_BUFFER_DIR)
+type bufferingClient interface {
+	Buffering() bool
+}
+
+// mqttTransport MQTT 브로커 발행
+type mqttTransport struct {
+	client mqtt.Client
+	config *config.Config // 메시지 종류별 QoS/retained
+	debug  *DebugCapture
+}
+
+func (t *mqttTransport) Name() string         { return TransportMQTT }
+func (t *mqttTransport) Available() bool      { return t.client.IsConnected() || t.buffering() }
+func (t *mqttTransport) Debug() *DebugCapture { return t.debug }
+
+// buffering 연결이 끊겨도 발행 버퍼가 메시지를 받아 둘 수 있는지
+func (t *mqttTransport) buffering() bool {
+	buffered, ok := t.client.(bufferingClient)
+	return ok && buffered.Buffering()
+}
+
+func (t *mqttTransport) Publish(ctx context.Context, topic string, payload []byte) (err error) {
+	started := time.Now()
+	defer func() { t.debug.record(TransportMQTT, topic, payload, "", err, started) }()
+
+	if !t.client.IsConnected() && !t.buffering() {
+		return fmt.Errorf("MQTT client is not connected")
+	}
+	options := t.config.RobotPublishOptions(topic)
+	token := t.client.Publish(topic, options.QoS, options.Retained, payload)
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-ctx.Done():
+		return fmt.Errorf("MQTT publish not acknowledged: %v", ctx.Err())
+	}
+}
+
+// httpTransport 로봇 HTTP 엔드포인트로 직접 전송
+// 토픽의 마지막 세그먼트(order, instantActions)를 경로로 사용해 <baseURL>/<segment>에 JSON을 POST합니다.
+// 요청 제한 시간은 발행 정책(TRANSPORT_POLICIES)이 컨텍스트로 정합니다.
+type httpTransport struct {
+	baseURL string
+	client  *http.Client
+	debug   *DebugCapture
+}
+
+func (t *httpTransport) Name() string         { return TransportHTTP }
+func (t *httpTransport) Available() bool      { return true }
+func (t *httpTransport) Debug() *DebugCapture { return t.debug }
+
+func (t *httpTransport) Publish(ctx context.Context, topic string, payload []byte) (err error) {
+	started := time.Now()
+	var response string
+	defer func() { t.debug.record(TransportHTTP, topic, payload, response, err, started) }()
+
+	endpoint := t.baseURL + "/" + topic[strings.LastIndex(topic, "/")+1:]
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if IsGzipPayload(payload) {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	req.Header.Set("X-VDA5050-Topic", topic)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP transport: %v", err)
+	}
+	defer resp.Body.Close()
+	if t.debug != nil {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxCaptureResponse+1))
+		response = resp.Status + " " + strings.TrimSpace(string(body))
+		if resp.StatusCode/100 != 2 {
+			return fmt.Errorf("HTTP transport: %s returned %s: %.256s", endpoint, resp.Status, strings.TrimSpace(string(body)))
+		}
+		return nil
+	}
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return fmt.Errorf("HTTP transport: %s returned %s: %s", endpoint, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// NewTransports TRANSPORT_FAILOVER 순서대로 전송 경로 생성 (첫 경로가 기본, 이후는 실패 시 순서대로 시도)
+// 경로마다 메시지 종류별 제한 시간과 재시도(TRANSPORT_POLICIES)가 적용됩니다.
+// TRANSPORT_DEBUG에 나열된 경로는 요청/응답을 디버그 버퍼에 기록합니다.
+func NewTransports(cfg *config.Config, mqttClient mqtt.Client) ([]Transport, error) {
+	debug := make(map[string]*DebugCapture)
+	for _, name := range cfg.TransportDebug {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if name != TransportMQTT && name != TransportHTTP {
+			return nil, fmt.Errorf("unsupported transport in TRANSPORT_DEBUG: %s (mqtt, http)", name)
+		}
+		debug[name] = NewDebugCapture(cfg.TransportDebugBuffer, cfg.TransportDebugRedact)
+	}
+
+	var transports []Transport
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(cfg.TransportFailover, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("transport %s is listed more than once in TRANSPORT_FAILOVER", name)
+		}
+		seen[name] = true
+
+		switch name {
+		case TransportMQTT:
+			transports = append(transports, &mqttTransport{client: mqttClient, config: cfg, debug: debug[name]})
+		case TransportHTTP:
+			if cfg.RobotHTTPURL == "" {
+				return nil, fmt.Errorf("ROBOT_HTTP_URL is required for the http transport")
+			}
+			transports = append(transports, &httpTransport{
+				baseURL: strings.TrimRight(cfg.RobotHTTPURL, "/"),
+				client:  &http.Client{},
+				debug:   debug[name],
+			})
+		default:
+			return nil, fmt.Errorf("unsupported transport: %s (mqtt, http)", name)
+		}
+	}
+	if len(transports) == 0 {
+		return nil, fmt.Errorf("TRANSPORT_FAILOVER must list at least one transport")
+	}
+	return withSendPolicies(transports, cfg), nil
+}
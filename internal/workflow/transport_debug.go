@@ -0,0 +1,150 @@
+// internal/workflow/transport_debug.go
+package workflow
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redactedValue 가려진 필드 값
+const redactedValue = "***"
+
+// maxCaptureResponse 응답 본문 최대 기록 크기
+const maxCaptureResponse = 4096
+
+// TransportCapture 전송 한 번의 요청/응답 기록
+type TransportCapture struct {
+	Time       time.Time       `json:"time"`
+	Transport  string          `json:"transport"`
+	Topic      string          `json:"topic"`
+	Request    json.RawMessage `json:"request"`            // 가림 규칙이 적용된 페이로드
+	Response   string          `json:"response,omitempty"` // HTTP 상태와 본문 (MQTT는 비어 있음)
+	Error      string          `json:"error,omitempty"`
+	DurationMs int64           `json:"duration_ms"`
+}
+
+// DebugCapture 전송 경로별 디버그 기록 링 버퍼 (가득 차면 가장 오래된 기록부터 덮어씀)
+type DebugCapture struct {
+	mu      sync.Mutex
+	size    int
+	redact  map[string]bool
+	entries []TransportCapture
+	next    int
+	total   int
+}
+
+// NewDebugCapture 새 디버그 기록 버퍼 생성 (redactKeys의 JSON 필드 값은 대소문자 무시하고 가림)
+func NewDebugCapture(size int, redactKeys []string) *DebugCapture {
+	if size <= 0 {
+		size = 100
+	}
+	redact := make(map[string]bool, len(redactKeys))
+	for _, key := range redactKeys {
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			redact[key] = true
+		}
+	}
+	return &DebugCapture{size: size, redact: redact, entries: make([]TransportCapture, 0, size)}
+}
+
+// record 전송 결과 기록
+func (c *DebugCapture) record(transport, topic string, payload []byte, response string, err error, started time.Time) {
+	if c == nil {
+		return
+	}
+	capture := TransportCapture{
+		Time:       started,
+		Transport:  transport,
+		Topic:      topic,
+		Request:    c.redactPayload(payload),
+		DurationMs: time.Since(started).Milliseconds(),
+	}
+	if len(response) > maxCaptureResponse {
+		response = response[:maxCaptureResponse] + "...(truncated)"
+	}
+	capture.Response = response
+	if err != nil {
+		capture.Error = err.Error()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) < c.size {
+		c.entries = append(c.entries, capture)
+	} else {
+		c.entries[c.next] = capture
+	}
+	c.next = (c.next + 1) % c.size
+	c.total++
+}
+
+// Entries 기록을 오래된 순서로 반환
+func (c *DebugCapture) Entries() []TransportCapture {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) < c.size {
+		return append([]TransportCapture(nil), c.entries...)
+	}
+	return append(append([]TransportCapture(nil), c.entries[c.next:]...), c.entries[:c.next]...)
+}
+
+// Total 버퍼 시작 이후 기록된 전체 건수 (덮어쓴 기록 포함)
+func (c *DebugCapture) Total() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.total
+}
+
+// redactPayload JSON이면 가림 대상 필드 값을 치환 (JSON이 아니면 문자열로 기록)
+func (c *DebugCapture) redactPayload(payload []byte) json.RawMessage {
+	var value interface{}
+	if err := json.Unmarshal(payload, &value); err != nil {
+		quoted, _ := json.Marshal(string(payload))
+		return quoted
+	}
+	data, err := json.Marshal(c.redactValue(value))
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func (c *DebugCapture) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		// VDA5050 actionParameters의 {"key": "...", "value": ...} 형태도 키 이름으로 가림
+		if key, ok := v["key"].(string); ok && c.redact[strings.ToLower(key)] {
+			if _, has := v["value"]; has {
+				v["value"] = redactedValue
+			}
+		}
+		for key, child := range v {
+			if c.redact[strings.ToLower(key)] {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = c.redactValue(child)
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = c.redactValue(child)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// DebugCaptures 디버그 기록이 켜진 전송 경로의 버퍼 (이름 → 버퍼)
+func DebugCaptures(transports []Transport) map[string]*DebugCapture {
+	captures := make(map[string]*DebugCapture)
+	for _, t := range transports {
+		if d, ok := t.(interface{ Debug() *DebugCapture }); ok && d.Debug() != nil {
+			captures[t.Name()] = d.Debug()
+		}
+	}
+	return captures
+}
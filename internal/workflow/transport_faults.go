@@ -0,0 +1,40 @@
+// internal/workflow/transport_faults.go
+package workflow
+
+import (
+	"context"
+	"mqtt-bridge/internal/faults"
+)
+
+// faultTransport 발행 전에 장애 주입 규칙(transport:<name>)을 적용하는 전송 경로
+type faultTransport struct {
+	Transport
+	injector *faults.Injector
+}
+
+// Debug 감싼 경로의 디버그 기록 (없으면 nil)
+func (t *faultTransport) Debug() *DebugCapture {
+	if d, ok := t.Transport.(interface{ Debug() *DebugCapture }); ok {
+		return d.Debug()
+	}
+	return nil
+}
+
+func (t *faultTransport) Publish(ctx context.Context, topic string, payload []byte) error {
+	if err := t.injector.Inject(ctx, "transport:"+t.Name()); err != nil {
+		return err
+	}
+	return t.Transport.Publish(ctx, topic, payload)
+}
+
+// WithFaults 전송 경로마다 장애 주입을 적용 (injector가 nil이면 그대로 반환)
+func WithFaults(transports []Transport, injector *faults.Injector) []Transport {
+	if injector == nil {
+		return transports
+	}
+	wrapped := make([]Transport, 0, len(transports))
+	for _, t := range transports {
+		wrapped = append(wrapped, &faultTransport{Transport: t, injector: injector})
+	}
+	return wrapped
+}
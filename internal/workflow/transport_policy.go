@@ -0,0 +1,103 @@
+// internal/workflow/transport_policy.go
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mqtt-bridge/internal/common/constants"
+	"mqtt-bridge/internal/config"
+	"mqtt-bridge/internal/utils"
+	"strings"
+	"time"
+)
+
+// maxRetryBackoff 재시도 대기 상한
+const maxRetryBackoff = 10 * time.Second
+
+// policyTransport 메시지 종류별 제한 시간과 재시도(TRANSPORT_POLICIES)를 적용하는 전송 경로
+// 시도마다 제한 시간이 있는 컨텍스트로 감싼 경로를 호출하고, 실패하면 지터를 더한 지수 백오프 후 다시 시도합니다.
+// 재시도를 모두 실패해야 다음 대체 경로로 넘어갑니다.
+type policyTransport struct {
+	Transport
+	config *config.Config
+}
+
+// Debug 감싼 경로의 디버그 기록 (없으면 nil)
+func (t *policyTransport) Debug() *DebugCapture {
+	if d, ok := t.Transport.(interface{ Debug() *DebugCapture }); ok {
+		return d.Debug()
+	}
+	return nil
+}
+
+func (t *policyTransport) Publish(ctx context.Context, topic string, payload []byte) error {
+	messageType := sendMessageType(topic, payload)
+	policy := t.config.SendPolicy(t.Name(), messageType)
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = t.publishOnce(ctx, policy.Timeout, topic, payload)
+		if err == nil || attempt >= policy.Retries {
+			break
+		}
+		backoff := utils.JitteredBackoff(t.config.TransportRetryBackoff, attempt, maxRetryBackoff)
+		utils.Logger.Warnf("🔁 %s %s publish to %s failed (attempt %d/%d), retrying in %v: %v",
+			t.Name(), messageType, topic, attempt+1, policy.Retries+1, backoff, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+	}
+	if policy.Retries > 0 && err != nil {
+		return fmt.Errorf("%v (after %d attempts)", err, policy.Retries+1)
+	}
+	return err
+}
+
+// publishOnce 제한 시간 안에 한 번 발행
+func (t *policyTransport) publishOnce(ctx context.Context, timeout time.Duration, topic string, payload []byte) error {
+	if timeout <= 0 {
+		return t.Transport.Publish(ctx, topic, payload)
+	}
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := t.Transport.Publish(attemptCtx, topic, payload)
+	if err != nil && attemptCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		return fmt.Errorf("%s publish timed out after %v: %v", t.Name(), timeout, err)
+	}
+	return err
+}
+
+// sendMessageType 발행 정책을 고르는 메시지 종류 (토픽의 마지막 세그먼트)
+// factsheetRequest 액션만 담은 instantActions는 factsheetRequest로 구분합니다.
+func sendMessageType(topic string, payload []byte) string {
+	messageType := topic[strings.LastIndex(topic, "/")+1:]
+	if messageType != "instantActions" || !strings.Contains(string(payload), constants.ActionTypeFactsheetRequest) {
+		return messageType
+	}
+	var message struct {
+		Actions []struct {
+			ActionType string `json:"actionType"`
+		} `json:"actions"`
+	}
+	if err := json.Unmarshal(payload, &message); err != nil || len(message.Actions) == 0 {
+		return messageType
+	}
+	for _, action := range message.Actions {
+		if action.ActionType != constants.ActionTypeFactsheetRequest {
+			return messageType
+		}
+	}
+	return constants.ActionTypeFactsheetRequest
+}
+
+// withSendPolicies 전송 경로마다 메시지 종류별 발행 정책을 적용
+func withSendPolicies(transports []Transport, cfg *config.Config) []Transport {
+	wrapped := make([]Transport, 0, len(transports))
+	for _, t := range transports {
+		wrapped = append(wrapped, &policyTransport{Transport: t, config: cfg})
+	}
+	return wrapped
+}
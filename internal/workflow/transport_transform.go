@@ -0,0 +1,70 @@
+// internal/workflow/transport_transform.go
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/repository"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// transformTransport 발행 전에 페이로드 변환 규칙을 적용하는 전송 경로
+// 디버그 기록은 감싼 경로가 남기므로 변환된 페이로드가 기록됩니다.
+type transformTransport struct {
+	Transport
+	transforms *PayloadTransforms
+	db         *gorm.DB
+	siteID     string
+}
+
+// Debug 감싼 경로의 디버그 기록 (없으면 nil)
+func (t *transformTransport) Debug() *DebugCapture {
+	if d, ok := t.Transport.(interface{ Debug() *DebugCapture }); ok {
+		return d.Debug()
+	}
+	return nil
+}
+
+func (t *transformTransport) Publish(ctx context.Context, topic string, payload []byte) error {
+	target := TransformTargetFromTopic(t.Name(), topic)
+	if t.transforms.NeedsVersion() && target.SerialNumber != "" {
+		var status models.RobotStatus
+		if err := t.db.Scopes(repository.SiteScope(t.siteID)).Select("version").
+			Where("serial_number = ?", target.SerialNumber).First(&status).Error; err == nil {
+			target.Version = status.Version
+		}
+	}
+	transformed, _, err := t.transforms.Apply(target, payload)
+	if err != nil {
+		return fmt.Errorf("%s transport: %w", t.Name(), err)
+	}
+	return t.Transport.Publish(ctx, topic, transformed)
+}
+
+// TransformTargetFromTopic VDA5050 토픽(<interface>/<version>/<manufacturer>/<serial>/<type>)에서 변환 대상 추출
+func TransformTargetFromTopic(transport, topic string) TransformTarget {
+	target := TransformTarget{Transport: transport}
+	parts := strings.Split(topic, "/")
+	target.MessageType = parts[len(parts)-1]
+	if len(parts) >= 5 {
+		target.Manufacturer = parts[len(parts)-3]
+		target.SerialNumber = parts[len(parts)-2]
+	}
+	return target
+}
+
+// WithPayloadTransforms 전송 경로마다 페이로드 변환을 적용 (transforms가 nil이면 그대로 반환)
+// 아웃박스를 거치는 오더 메시지에 적용되며, 직접 발행하는 instantActions(비상 정지 등)는 변환하지 않습니다.
+func WithPayloadTransforms(transports []Transport, transforms *PayloadTransforms, db *gorm.DB, siteID string) []Transport {
+	if transforms == nil {
+		return transports
+	}
+	wrapped := make([]Transport, 0, len(transports))
+	for _, t := range transports {
+		wrapped = append(wrapped, &transformTransport{Transport: t, transforms: transforms, db: db, siteID: siteID})
+	}
+	return wrapped
+}
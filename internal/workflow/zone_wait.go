@@ -0,0 +1,85 @@
+// internal/workflow/zone_wait.go
+package workflow
+
+import (
+	"context"
+	"mqtt-bridge/internal/models"
+	"mqtt-bridge/internal/utils"
+	"mqtt-bridge/internal/zones"
+	"sort"
+	"strings"
+)
+
+// SetZoneCoordinator 단계를 보내기 전에 노드/엣지의 구역을 예약하도록 설정
+func (e *Executor) SetZoneCoordinator(coordinator *zones.Coordinator) {
+	e.zones = coordinator
+	e.stepManager.zones = coordinator
+	utils.Logger.Infof("✅ Workflow Executor: Zone reservations enabled")
+}
+
+// stepZones 단계 그룹의 노드와 엣지가 선언한 구역
+func stepZones(members []*models.OrderStep) []string {
+	var ids []string
+	for _, member := range members {
+		if member.NodeTemplate != nil {
+			ids = append(ids, zones.ParseZones(member.NodeTemplate.Zones)...)
+		}
+		for _, edge := range member.Edges {
+			ids = append(ids, zones.ParseZones(edge.Zones)...)
+		}
+	}
+	return zones.NormalizeZones(ids)
+}
+
+// reserveZones 단계 그룹의 구역을 예약 (예약하지 못하면 단계를 보내지 않고 다시 확인하도록 예약한 뒤 false)
+// 로봇이 점유 중이던 구역 중 이 단계에 필요 없는 구역은 예약과 함께 해제됩니다.
+func (s *StepManager) reserveZones(execution *models.OrderExecution, members []*models.OrderStep) bool {
+	request := zones.Request{
+		SerialNumber: execution.SerialNumber,
+		OrderID:      execution.OrderID,
+		StepOrder:    members[0].StepOrder,
+		Zones:        stepZones(members),
+	}
+	decision, err := s.zones.Acquire(context.Background(), request)
+	if err != nil {
+		// 예약을 확인할 수 없으면 충돌 위험이 있으므로 보내지 않고 다시 시도
+		utils.Logger.Errorf("❌ Failed to reserve zones for order %s step %d: %v", execution.OrderID, request.StepOrder, err)
+		s.executor.scheduleStepRecheck(execution.ID)
+		return false
+	}
+	if decision.Acquired {
+		if len(request.Zones) > 0 {
+			utils.Logger.Infof("🟩 Order %s step %d reserved zone(s): %s", execution.OrderID, request.StepOrder, strings.Join(request.Zones, ", "))
+		}
+		if len(decision.Released) > 0 {
+			s.executor.wakeWaitingOrders()
+		}
+		return true
+	}
+
+	if s.executor.scheduleStepRecheck(execution.ID) {
+		utils.Logger.Infof("🟥 Order %s step %d waiting for zone(s): %s (cid=%s)",
+			execution.OrderID, request.StepOrder, formatBlockers(decision.BlockedBy), execution.CorrelationID)
+	}
+	return false
+}
+
+// formatBlockers "구역 held by 로봇" 목록 (구역 순)
+func formatBlockers(blockedBy map[string]string) string {
+	parts := make([]string, 0, len(blockedBy))
+	for zone, serial := range blockedBy {
+		parts = append(parts, zone+" by "+serial)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+// releaseZones 오더가 끝난 로봇의 구역 예약과 대기를 해제
+func (e *Executor) releaseZones(serialNumber string) {
+	if e.zones == nil || serialNumber == "" {
+		return
+	}
+	if _, err := e.zones.Release(context.Background(), serialNumber); err != nil {
+		utils.Logger.Errorf("❌ Failed to release zones of robot %s: %v", serialNumber, err)
+	}
+}
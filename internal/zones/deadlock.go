@@ -0,0 +1,87 @@
+// internal/zones/deadlock.go
+package zones
+
+import "sort"
+
+// waitGraph 대기 그래프 (기다리는 로봇 → 그 로봇을 막는 로봇들, 시리얼 번호 순)
+func waitGraph(waiters map[string]Waiter) map[string][]string {
+	graph := make(map[string][]string, len(waiters))
+	for serial, w := range waiters {
+		seen := make(map[string]bool)
+		for _, blocker := range w.BlockedBy {
+			if blocker != serial && !seen[blocker] {
+				seen[blocker] = true
+				graph[serial] = append(graph[serial], blocker)
+			}
+		}
+		sort.Strings(graph[serial])
+	}
+	return graph
+}
+
+// cycleFrom serial에서 시작해 다시 serial로 돌아오는 대기 순환 (없으면 nil)
+func cycleFrom(serial string, waiters map[string]Waiter) []string {
+	graph := waitGraph(waiters)
+	visited := make(map[string]bool)
+	var path []string
+	var walk func(node string) []string
+	walk = func(node string) []string {
+		path = append(path, node)
+		defer func() { path = path[:len(path)-1] }()
+		for _, next := range graph[node] {
+			if next == serial {
+				return append([]string(nil), path...)
+			}
+			if visited[next] {
+				continue
+			}
+			visited[next] = true
+			if cycle := walk(next); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+	visited[serial] = true
+	return walk(serial)
+}
+
+// DetectDeadlocks 대기 그래프의 모든 순환 (순환마다 시리얼 번호가 가장 작은 로봇부터, 중복 제거)
+func DetectDeadlocks(waiters map[string]Waiter) [][]string {
+	serials := make([]string, 0, len(waiters))
+	for serial := range waiters {
+		serials = append(serials, serial)
+	}
+	sort.Strings(serials)
+
+	seen := make(map[string]bool)
+	var cycles [][]string
+	for _, serial := range serials {
+		cycle := cycleFrom(serial, waiters)
+		if cycle == nil {
+			continue
+		}
+		cycle = rotateToMin(cycle)
+		key := ""
+		for _, s := range cycle {
+			key += s + ">"
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		cycles = append(cycles, cycle)
+	}
+	return cycles
+}
+
+// rotateToMin 같은 순환이 같은 모양이 되도록 시리얼 번호가 가장 작은 로봇부터 시작
+func rotateToMin(cycle []string) []string {
+	start := 0
+	for i, serial := range cycle {
+		if serial < cycle[start] {
+			start = i
+		}
+	}
+	return append(append([]string(nil), cycle[start:]...), cycle[:start]...)
+}
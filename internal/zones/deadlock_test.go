@@ -0,0 +1,142 @@
+// internal/zones/deadlock_test.go
+package zones
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// waiting 구역 → 막는 로봇 쌍으로 대기 생성
+func waiting(serial string, blockedBy ...string) Waiter {
+	w := Waiter{SerialNumber: serial, BlockedBy: map[string]string{}}
+	for i := 0; i+1 < len(blockedBy); i += 2 {
+		w.BlockedBy[blockedBy[i]] = blockedBy[i+1]
+		w.Zones = append(w.Zones, blockedBy[i])
+	}
+	return w
+}
+
+func waiterMap(waiters ...Waiter) map[string]Waiter {
+	m := make(map[string]Waiter, len(waiters))
+	for _, w := range waiters {
+		m[w.SerialNumber] = w
+	}
+	return m
+}
+
+func TestWaitGraph(t *testing.T) {
+	graph := waitGraph(waiterMap(
+		waiting("AGV-1", "Z1", "AGV-3", "Z2", "AGV-2", "Z3", "AGV-2"),
+		waiting("AGV-2", "Z4", "AGV-2"), // 자기 자신은 간선이 아님
+	))
+	want := map[string][]string{"AGV-1": {"AGV-2", "AGV-3"}}
+	if len(graph["AGV-2"]) != 0 || !reflect.DeepEqual(graph["AGV-1"], want["AGV-1"]) {
+		t.Fatalf("waitGraph = %v, want %v", graph, want)
+	}
+}
+
+func TestCycleFrom(t *testing.T) {
+	tests := []struct {
+		name    string
+		waiters map[string]Waiter
+		serial  string
+		want    []string
+	}{
+		{
+			name:    "no waiters",
+			waiters: waiterMap(),
+			serial:  "AGV-1",
+		},
+		{
+			name:    "chain without cycle",
+			waiters: waiterMap(waiting("AGV-1", "Z1", "AGV-2"), waiting("AGV-2", "Z2", "AGV-3")),
+			serial:  "AGV-1",
+		},
+		{
+			name:    "two robots",
+			waiters: waiterMap(waiting("AGV-1", "Z1", "AGV-2"), waiting("AGV-2", "Z2", "AGV-1")),
+			serial:  "AGV-2",
+			want:    []string{"AGV-2", "AGV-1"},
+		},
+		{
+			name: "three robots",
+			waiters: waiterMap(
+				waiting("AGV-1", "Z1", "AGV-2"),
+				waiting("AGV-2", "Z2", "AGV-3"),
+				waiting("AGV-3", "Z3", "AGV-1"),
+			),
+			serial: "AGV-1",
+			want:   []string{"AGV-1", "AGV-2", "AGV-3"},
+		},
+		{
+			name: "cycle not through serial",
+			waiters: waiterMap(
+				waiting("AGV-1", "Z1", "AGV-2"),
+				waiting("AGV-2", "Z2", "AGV-3"),
+				waiting("AGV-3", "Z3", "AGV-2"),
+			),
+			serial: "AGV-1",
+		},
+		{
+			name: "dead end branch before cycle",
+			waiters: waiterMap(
+				waiting("AGV-1", "Z1", "AGV-2", "Z2", "AGV-3"),
+				waiting("AGV-3", "Z3", "AGV-1"),
+			),
+			serial: "AGV-1",
+			want:   []string{"AGV-1", "AGV-3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cycleFrom(tt.serial, tt.waiters); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("cycleFrom(%s) = %v, want %v", tt.serial, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectDeadlocks(t *testing.T) {
+	waiters := waiterMap(
+		waiting("AGV-3", "Z3", "AGV-1"),
+		waiting("AGV-1", "Z1", "AGV-2"),
+		waiting("AGV-2", "Z2", "AGV-3"),
+		waiting("AGV-5", "Z5", "AGV-4"),
+		waiting("AGV-4", "Z4", "AGV-5"),
+		waiting("AGV-6", "Z6", "AGV-1"), // 순환을 기다리지만 순환에 속하지는 않음
+	)
+	want := [][]string{{"AGV-1", "AGV-2", "AGV-3"}, {"AGV-4", "AGV-5"}}
+	if got := DetectDeadlocks(waiters); !reflect.DeepEqual(got, want) {
+		t.Fatalf("DetectDeadlocks = %v, want %v", got, want)
+	}
+	if got := DetectDeadlocks(waiterMap(waiting("AGV-1", "Z1", "AGV-2"))); got != nil {
+		t.Fatalf("DetectDeadlocks without cycle = %v, want nil", got)
+	}
+}
+
+func TestBlockers(t *testing.T) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	expired := now.Add(-time.Second)
+	reservations := map[string]Reservation{
+		"Z1": {Zone: "Z1", SerialNumber: "AGV-2"},
+		"Z2": {Zone: "Z2", SerialNumber: "AGV-1"},
+		"Z3": {Zone: "Z3", SerialNumber: "AGV-2", ExpiresAt: &expired},
+	}
+	waiters := map[string]Waiter{
+		"AGV-3": {SerialNumber: "AGV-3", Zones: []string{"Z3", "Z2"}, Since: now.Add(-time.Minute), UpdatedAt: now},
+		"AGV-4": {SerialNumber: "AGV-4", Zones: []string{"Z3"}, Since: now.Add(-2 * time.Minute), UpdatedAt: now.Add(-time.Hour)},
+	}
+
+	// Z1은 AGV-2가 점유, Z2는 이미 점유 중이라 대기자가 있어도 유지, 만료된 Z3는 먼저 기다린 AGV-3 차례 (멈춘 AGV-4는 무시)
+	got := blockers("AGV-1", []string{"Z1", "Z2", "Z3"}, now, reservations, waiters, now)
+	want := map[string]string{"Z1": "AGV-2", "Z3": "AGV-3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("blockers = %v, want %v", got, want)
+	}
+
+	// 나중에 기다리기 시작한 대기자는 막지 않음
+	if got := blockers("AGV-1", []string{"Z3"}, now.Add(-2*time.Minute), reservations, waiters, now); got != nil {
+		t.Fatalf("blockers for the earliest waiter = %v, want nil", got)
+	}
+}
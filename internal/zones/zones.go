@@ -0,0 +1,458 @@
+// internal/zones/zones.go
+// Package zones 구역 점유 예약 (여러 로봇이 같은 구역에 동시에 들어가지 않도록 조정)
+// 실행기는 노드/엣지 템플릿이 선언한 구역을 노드를 로봇에 보내기 전에 예약하고, 다른 로봇이 점유 중이거나
+// 먼저 기다리는 로봇이 있으면 단계를 보내지 않고 기다립니다. 예약과 대기는 사이트별 Redis 해시에 저장하므로
+// 같은 사이트의 모든 브릿지가 공유합니다. 로봇은 다음 단계의 구역을 예약할 때까지 현재 구역을 계속 점유하므로
+// 서로의 구역을 기다리는 순환(교착)이 생길 수 있으며, 이는 감지해 보고하고 운영자가 예약을 해제해 풉니다.
+package zones
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mqtt-bridge/internal/common/apperr"
+	redisKeys "mqtt-bridge/internal/common/redis"
+	"mqtt-bridge/internal/utils"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// 예약 제한
+const (
+	maxTxRetries     = 5                // 다른 브릿지와 동시에 예약해 트랜잭션이 실패했을 때 재시도 횟수
+	waiterStaleAfter = 30 * time.Second // 이 시간 동안 다시 확인하지 않은 대기는 브릿지가 멈춘 것으로 보고 무시
+)
+
+// Reservation 구역 하나의 점유 예약
+type Reservation struct {
+	Zone         string     `json:"zone"`
+	SerialNumber string     `json:"serial_number"`
+	OrderID      string     `json:"order_id"`
+	StepOrder    int        `json:"step_order"`
+	AcquiredAt   time.Time  `json:"acquired_at"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"` // ZONE_RESERVATION_TTL_SECONDS가 0이면 만료 없음
+}
+
+func (r *Reservation) expired(now time.Time) bool {
+	return r.ExpiresAt != nil && now.After(*r.ExpiresAt)
+}
+
+// Waiter 구역 예약을 기다리는 로봇
+type Waiter struct {
+	SerialNumber string            `json:"serial_number"`
+	OrderID      string            `json:"order_id"`
+	StepOrder    int               `json:"step_order"`
+	Zones        []string          `json:"zones"`
+	BlockedBy    map[string]string `json:"blocked_by"` // 구역 → 점유 중이거나 먼저 기다리는 로봇
+	Since        time.Time         `json:"since"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+func (w *Waiter) stale(now time.Time) bool {
+	return now.Sub(w.UpdatedAt) > waiterStaleAfter
+}
+
+func (w *Waiter) wants(zone string) bool {
+	for _, z := range w.Zones {
+		if z == zone {
+			return true
+		}
+	}
+	return false
+}
+
+// Request 단계 하나가 필요로 하는 구역 예약 요청
+type Request struct {
+	SerialNumber string
+	OrderID      string
+	StepOrder    int
+	Zones        []string // 비어 있으면 로봇이 점유 중인 구역만 해제
+}
+
+// Decision 예약 결과
+type Decision struct {
+	Acquired  bool              `json:"acquired"`
+	Released  []string          `json:"released,omitempty"`   // 다음 단계에 필요 없어 해제한 구역
+	BlockedBy map[string]string `json:"blocked_by,omitempty"` // 구역 → 점유 중이거나 먼저 기다리는 로봇
+	Deadlock  []string          `json:"deadlock,omitempty"`   // 대기가 순환하면 순환에 포함된 로봇
+}
+
+// Snapshot 사이트의 구역 예약 현황
+type Snapshot struct {
+	Reservations []Reservation `json:"reservations"`
+	Waiters      []Waiter      `json:"waiters"`
+	Deadlocks    [][]string    `json:"deadlocks"` // 서로의 구역을 기다리는 로봇 순환
+}
+
+// Coordinator Redis 기반 구역 예약 조정기
+type Coordinator struct {
+	client *redis.Client
+	siteID string
+	ttl    time.Duration
+
+	mu       sync.Mutex
+	reported map[string]bool // 이미 경고한 교착 순환
+}
+
+// NewCoordinator 구역 예약 조정기 생성 (ttl이 0이면 예약이 만료되지 않음)
+func NewCoordinator(client *redis.Client, siteID string, ttl time.Duration) *Coordinator {
+	return &Coordinator{client: client, siteID: siteID, ttl: ttl, reported: make(map[string]bool)}
+}
+
+// Acquire 요청한 구역을 모두 예약하고, 로봇이 점유 중이던 나머지 구역은 해제합니다.
+// 하나라도 다른 로봇이 점유 중이거나 먼저 기다리는 로봇이 있으면 아무것도 예약하지 않고
+// 대기 목록에 올린 뒤 Acquired=false를 반환합니다 (기존 점유는 유지).
+func (c *Coordinator) Acquire(ctx context.Context, req Request) (*Decision, error) {
+	zones := NormalizeZones(req.Zones)
+	reservationsKey := redisKeys.ZoneReservations(c.siteID)
+	waitersKey := redisKeys.ZoneWaiters(c.siteID)
+
+	var decision *Decision
+	var waiters map[string]Waiter
+	txf := func(tx *redis.Tx) error {
+		reservations, err := loadReservations(ctx, tx, reservationsKey)
+		if err != nil {
+			return err
+		}
+		waiters, err = loadWaiters(ctx, tx, waitersKey)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		since := now
+		if w, ok := waiters[req.SerialNumber]; ok {
+			since = w.Since
+		}
+		decision = &Decision{BlockedBy: blockers(req.SerialNumber, zones, since, reservations, waiters, now)}
+		decision.Acquired = len(decision.BlockedBy) == 0
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			for zone, r := range reservations {
+				if r.expired(now) {
+					pipe.HDel(ctx, reservationsKey, zone)
+				}
+			}
+			for serial, w := range waiters {
+				if w.stale(now) {
+					pipe.HDel(ctx, waitersKey, serial)
+					delete(waiters, serial)
+				}
+			}
+
+			if !decision.Acquired {
+				waiter := Waiter{
+					SerialNumber: req.SerialNumber,
+					OrderID:      req.OrderID,
+					StepOrder:    req.StepOrder,
+					Zones:        zones,
+					BlockedBy:    decision.BlockedBy,
+					Since:        since,
+					UpdatedAt:    now,
+				}
+				data, err := json.Marshal(waiter)
+				if err != nil {
+					return err
+				}
+				pipe.HSet(ctx, waitersKey, req.SerialNumber, data)
+				waiters[req.SerialNumber] = waiter
+				return nil
+			}
+
+			wanted := make(map[string]bool, len(zones))
+			for _, zone := range zones {
+				wanted[zone] = true
+			}
+			decision.Released = nil
+			for zone, r := range reservations {
+				if r.SerialNumber == req.SerialNumber && !wanted[zone] && !r.expired(now) {
+					pipe.HDel(ctx, reservationsKey, zone)
+					decision.Released = append(decision.Released, zone)
+				}
+			}
+			sort.Strings(decision.Released)
+			for _, zone := range zones {
+				reservation := Reservation{
+					Zone:         zone,
+					SerialNumber: req.SerialNumber,
+					OrderID:      req.OrderID,
+					StepOrder:    req.StepOrder,
+					AcquiredAt:   now,
+				}
+				if r, ok := reservations[zone]; ok && r.SerialNumber == req.SerialNumber && !r.expired(now) {
+					reservation.AcquiredAt = r.AcquiredAt
+				}
+				if c.ttl > 0 {
+					expiresAt := now.Add(c.ttl)
+					reservation.ExpiresAt = &expiresAt
+				}
+				data, err := json.Marshal(reservation)
+				if err != nil {
+					return err
+				}
+				pipe.HSet(ctx, reservationsKey, zone, data)
+			}
+			pipe.HDel(ctx, waitersKey, req.SerialNumber)
+			return nil
+		})
+		return err
+	}
+	if err := c.watch(ctx, txf, reservationsKey, waitersKey); err != nil {
+		return nil, err
+	}
+
+	if decision.Acquired {
+		c.forget(req.SerialNumber)
+		return decision, nil
+	}
+	decision.Deadlock = cycleFrom(req.SerialNumber, waiters)
+	if decision.Deadlock != nil {
+		c.reportDeadlock(decision.Deadlock)
+	}
+	return decision, nil
+}
+
+// blockers 요청한 구역마다 예약을 막는 로봇 (점유 중인 로봇, 없으면 먼저 기다리기 시작한 로봇)
+// 이미 점유 중인 구역은 먼저 기다리는 로봇이 있어도 계속 점유합니다.
+func blockers(serial string, zones []string, since time.Time, reservations map[string]Reservation,
+	waiters map[string]Waiter, now time.Time) map[string]string {
+
+	blocked := make(map[string]string)
+	for _, zone := range zones {
+		if r, ok := reservations[zone]; ok && !r.expired(now) {
+			if r.SerialNumber != serial {
+				blocked[zone] = r.SerialNumber
+			}
+			continue
+		}
+		var first *Waiter
+		for _, w := range waiters {
+			w := w
+			if w.SerialNumber == serial || w.stale(now) || !w.Since.Before(since) || !w.wants(zone) {
+				continue
+			}
+			if first == nil || w.Since.Before(first.Since) {
+				first = &w
+			}
+		}
+		if first != nil {
+			blocked[zone] = first.SerialNumber
+		}
+	}
+	if len(blocked) == 0 {
+		return nil
+	}
+	return blocked
+}
+
+// Release 로봇의 모든 구역 예약과 대기를 해제 (오더가 끝났을 때) 해제한 구역 반환
+func (c *Coordinator) Release(ctx context.Context, serialNumber string) ([]string, error) {
+	reservationsKey := redisKeys.ZoneReservations(c.siteID)
+	waitersKey := redisKeys.ZoneWaiters(c.siteID)
+
+	var released []string
+	txf := func(tx *redis.Tx) error {
+		reservations, err := loadReservations(ctx, tx, reservationsKey)
+		if err != nil {
+			return err
+		}
+		released = nil
+		for zone, r := range reservations {
+			if r.SerialNumber == serialNumber {
+				released = append(released, zone)
+			}
+		}
+		sort.Strings(released)
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			if len(released) > 0 {
+				pipe.HDel(ctx, reservationsKey, released...)
+			}
+			pipe.HDel(ctx, waitersKey, serialNumber)
+			return nil
+		})
+		return err
+	}
+	if err := c.watch(ctx, txf, reservationsKey); err != nil {
+		return nil, err
+	}
+	c.forget(serialNumber)
+	if len(released) > 0 {
+		utils.Logger.Infof("🟩 Robot %s released zone(s): %s", serialNumber, strings.Join(released, ", "))
+	}
+	return released, nil
+}
+
+// ReleaseZone 구역 예약을 강제로 해제 (교착을 풀거나 멈춘 브릿지가 남긴 예약 정리용) 해제한 예약 반환
+// 읽은 예약을 그대로 지우도록 키를 감시하는 트랜잭션으로 처리합니다 (그 사이 바뀌면 다시 읽음).
+func (c *Coordinator) ReleaseZone(ctx context.Context, zone string) (*Reservation, error) {
+	reservationsKey := redisKeys.ZoneReservations(c.siteID)
+
+	var reservation Reservation
+	txf := func(tx *redis.Tx) error {
+		data, err := tx.HGet(ctx, reservationsKey, zone).Result()
+		if err == redis.Nil {
+			return apperr.New(apperr.CodeNotFound, "zone %s is not reserved", zone).WithField("zone")
+		}
+		if err != nil {
+			return err
+		}
+		reservation = Reservation{}
+		if err := json.Unmarshal([]byte(data), &reservation); err != nil {
+			return fmt.Errorf("invalid reservation of zone %s: %w", zone, err)
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HDel(ctx, reservationsKey, zone)
+			return nil
+		})
+		return err
+	}
+	if err := c.watch(ctx, txf, reservationsKey); err != nil {
+		return nil, err
+	}
+	utils.Logger.Warnf("🟩 Zone %s reservation of robot %s (order %s) released by operator",
+		zone, reservation.SerialNumber, reservation.OrderID)
+	return &reservation, nil
+}
+
+// Snapshot 사이트의 예약, 대기, 교착 순환 조회 (만료된 예약과 멈춘 대기는 제외)
+func (c *Coordinator) Snapshot(ctx context.Context) (*Snapshot, error) {
+	reservations, err := loadReservations(ctx, c.client, redisKeys.ZoneReservations(c.siteID))
+	if err != nil {
+		return nil, err
+	}
+	waiters, err := loadWaiters(ctx, c.client, redisKeys.ZoneWaiters(c.siteID))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	snapshot := &Snapshot{Reservations: []Reservation{}, Waiters: []Waiter{}, Deadlocks: [][]string{}}
+	for _, r := range reservations {
+		if !r.expired(now) {
+			snapshot.Reservations = append(snapshot.Reservations, r)
+		}
+	}
+	sort.Slice(snapshot.Reservations, func(i, j int) bool {
+		return snapshot.Reservations[i].Zone < snapshot.Reservations[j].Zone
+	})
+	for serial, w := range waiters {
+		if w.stale(now) {
+			delete(waiters, serial)
+			continue
+		}
+		snapshot.Waiters = append(snapshot.Waiters, w)
+	}
+	sort.Slice(snapshot.Waiters, func(i, j int) bool {
+		return snapshot.Waiters[i].Since.Before(snapshot.Waiters[j].Since)
+	})
+	snapshot.Deadlocks = append(snapshot.Deadlocks, DetectDeadlocks(waiters)...)
+	return snapshot, nil
+}
+
+// watch 키를 감시하는 트랜잭션 실행 (다른 브릿지가 동시에 바꿔 실패하면 재시도)
+func (c *Coordinator) watch(ctx context.Context, txf func(*redis.Tx) error, keys ...string) error {
+	for i := 0; i < maxTxRetries; i++ {
+		err := c.client.Watch(ctx, txf, keys...)
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+	}
+	return fmt.Errorf("zone reservation contended, gave up after %d attempts", maxTxRetries)
+}
+
+// reportDeadlock 새로 감지한 교착 순환을 한 번만 경고
+func (c *Coordinator) reportDeadlock(cycle []string) {
+	key := strings.Join(cycle, ">")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.reported[key] {
+		return
+	}
+	c.reported[key] = true
+	utils.Logger.Warnf("🔒 Zone deadlock detected: %s -> %s (release a zone with bridgectl zones release)",
+		strings.Join(cycle, " -> "), cycle[0])
+}
+
+// forget 로봇이 예약에 성공하거나 해제했으므로 그 로봇이 포함된 교착 경고를 지움
+func (c *Coordinator) forget(serialNumber string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.reported {
+		for _, serial := range strings.Split(key, ">") {
+			if serial == serialNumber {
+				delete(c.reported, key)
+				break
+			}
+		}
+	}
+}
+
+// hashReader HGETALL을 지원하는 클라이언트 (redis.Client, redis.Tx)
+type hashReader interface {
+	HGetAll(ctx context.Context, key string) *redis.StringStringMapCmd
+}
+
+func loadReservations(ctx context.Context, r hashReader, key string) (map[string]Reservation, error) {
+	fields, err := r.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	reservations := make(map[string]Reservation, len(fields))
+	for zone, data := range fields {
+		var reservation Reservation
+		if err := json.Unmarshal([]byte(data), &reservation); err != nil {
+			utils.Logger.Warnf("⚠️ Ignoring invalid reservation of zone %s: %v", zone, err)
+			continue
+		}
+		reservations[zone] = reservation
+	}
+	return reservations, nil
+}
+
+func loadWaiters(ctx context.Context, r hashReader, key string) (map[string]Waiter, error) {
+	fields, err := r.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	waiters := make(map[string]Waiter, len(fields))
+	for serial, data := range fields {
+		var waiter Waiter
+		if err := json.Unmarshal([]byte(data), &waiter); err != nil {
+			utils.Logger.Warnf("⚠️ Ignoring invalid zone waiter %s: %v", serial, err)
+			continue
+		}
+		waiters[serial] = waiter
+	}
+	return waiters, nil
+}
+
+// NormalizeZones 구역 ID 목록 정리 (공백 제거, 중복 제거, 정렬)
+func NormalizeZones(zones []string) []string {
+	seen := make(map[string]bool, len(zones))
+	normalized := make([]string, 0, len(zones))
+	for _, zone := range zones {
+		zone = strings.TrimSpace(zone)
+		if zone == "" || seen[zone] {
+			continue
+		}
+		seen[zone] = true
+		normalized = append(normalized, zone)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// ParseZones 템플릿의 쉼표 구분 구역 ID 해석
+func ParseZones(zones string) []string {
+	return NormalizeZones(strings.Split(zones, ","))
+}
+
+// FormatZones 구역 ID 목록을 템플릿 컬럼 값(쉼표 구분)으로 변환
+func FormatZones(zones []string) string {
+	return strings.Join(NormalizeZones(zones), ",")
+}